@@ -0,0 +1,101 @@
+package bundle
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"net/http/httputil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/andybalholm/brotli"
+)
+
+// DecodeBody removes the chunked transfer coding and gzip/deflate/br content codings
+// named in headers (a raw HTTP header block) from body. For an encoding it cannot
+// decode, it returns the body decoded so far along with an error.
+func DecodeBody(headers string, body []byte) ([]byte, error) {
+	transfer, content := encodingHeaders(headers)
+
+	if strings.Contains(strings.ToLower(transfer), "chunked") {
+		dechunked, err := io.ReadAll(httputil.NewChunkedReader(bytes.NewReader(body)))
+		if err != nil {
+			return body, fmt.Errorf("decode chunked body: %w", err)
+		}
+		body = dechunked
+	}
+
+	// Codings are listed in the order applied, so undo them in reverse.
+	codings := strings.Split(content, ",")
+	for i := len(codings) - 1; i >= 0; i-- {
+		coding := strings.ToLower(strings.TrimSpace(codings[i]))
+		var r io.Reader
+		var err error
+		switch coding {
+		case "", "identity":
+			continue
+		case "gzip", "x-gzip":
+			r, err = gzip.NewReader(bytes.NewReader(body))
+		case "deflate":
+			// Servers send either zlib-wrapped (per RFC 9110) or raw deflate data.
+			if r, err = zlib.NewReader(bytes.NewReader(body)); err != nil {
+				r, err = flate.NewReader(bytes.NewReader(body)), nil
+			}
+		case "br":
+			r = brotli.NewReader(bytes.NewReader(body))
+		default:
+			return body, fmt.Errorf("unsupported Content-Encoding %q, body left encoded", coding)
+		}
+		if err == nil {
+			var decoded []byte
+			if decoded, err = io.ReadAll(r); err == nil {
+				body = decoded
+				continue
+			}
+		}
+		return body, fmt.Errorf("decode %s body: %w", coding, err)
+	}
+	return body, nil
+}
+
+func encodingHeaders(headers string) (transfer, content string) {
+	for _, line := range strings.Split(headers, "\n") {
+		name, value, ok := strings.Cut(strings.TrimRight(line, "\r"), ":")
+		if !ok {
+			continue
+		}
+		switch strings.ToLower(strings.TrimSpace(name)) {
+		case "transfer-encoding":
+			transfer = joinHeader(transfer, value)
+		case "content-encoding":
+			content = joinHeader(content, value)
+		}
+	}
+	return transfer, content
+}
+
+func joinHeader(existing, value string) string {
+	if existing == "" {
+		return strings.TrimSpace(value)
+	}
+	return existing + "," + strings.TrimSpace(value)
+}
+
+// WriteBody writes data to path, or to stdout when path is "-", creating parent
+// directories as needed.
+func WriteBody(path string, data []byte) error {
+	if path == "-" {
+		_, err := os.Stdout.Write(data)
+		return err
+	}
+	if dir := filepath.Dir(path); dir != "." {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("create output directory: %w", err)
+		}
+	}
+	return os.WriteFile(path, data, 0644)
+}
@@ -0,0 +1,75 @@
+package bundle
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/andybalholm/brotli"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeBody(t *testing.T) {
+	t.Parallel()
+
+	payload := []byte("%PDF-1.7\x00\xff\xfe binary")
+	var gz bytes.Buffer
+	gw := gzip.NewWriter(&gz)
+	_, _ = gw.Write(payload)
+	require.NoError(t, gw.Close())
+	var raw bytes.Buffer
+	fw, _ := flate.NewWriter(&raw, flate.BestSpeed)
+	_, _ = fw.Write(payload)
+	require.NoError(t, fw.Close())
+	var br bytes.Buffer
+	bw := brotli.NewWriter(&br)
+	_, _ = bw.Write(payload)
+	require.NoError(t, bw.Close())
+
+	tests := []struct {
+		name    string
+		headers string
+		body    []byte
+		want    []byte
+		wantErr string
+	}{
+		{name: "identity", headers: "HTTP/1.1 200 OK\r\nContent-Type: application/pdf\r\n\r\n", body: payload, want: payload},
+		{name: "gzip", headers: "HTTP/1.1 200 OK\r\ncontent-encoding: gzip\r\n\r\n", body: gz.Bytes(), want: payload},
+		{name: "raw_deflate", headers: "HTTP/1.1 200 OK\r\nContent-Encoding: deflate\r\n\r\n", body: raw.Bytes(), want: payload},
+		{
+			name:    "chunked_gzip",
+			headers: "HTTP/1.1 200 OK\r\nTransfer-Encoding: chunked\r\nContent-Encoding: gzip\r\n\r\n",
+			body:    append([]byte("5\r\n"+string(gz.Bytes()[:5])+"\r\n"+strconv.FormatInt(int64(gz.Len()-5), 16)+"\r\n"), append(gz.Bytes()[5:], []byte("\r\n0\r\n\r\n")...)...),
+			want:    payload,
+		},
+		{name: "brotli", headers: "HTTP/1.1 200 OK\r\nContent-Encoding: br\r\n\r\n", body: br.Bytes(), want: payload},
+		{name: "unsupported", headers: "HTTP/1.1 200 OK\r\nContent-Encoding: zstd\r\n\r\n", body: []byte("zstd"), want: []byte("zstd"), wantErr: `unsupported Content-Encoding "zstd"`},
+		{name: "corrupt_gzip", headers: "HTTP/1.1 200 OK\r\nContent-Encoding: gzip\r\n\r\n", body: []byte("nope"), want: []byte("nope"), wantErr: "decode gzip body"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := DecodeBody(tc.headers, tc.body)
+			if tc.wantErr != "" {
+				assert.ErrorContains(t, err, tc.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestWriteBody(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "nested", "out.bin")
+	require.NoError(t, WriteBody(path, []byte{0, 1, 2, 0xff}))
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, []byte{0, 1, 2, 0xff}, data)
+}
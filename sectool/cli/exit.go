@@ -0,0 +1,85 @@
+package cli
+
+import (
+	"errors"
+	"strings"
+)
+
+// Exit codes are stable so scripts and CI jobs can branch on them.
+const (
+	ExitOK          = 0
+	ExitError       = 1 // usage errors and unclassified failures
+	ExitNotFound    = 2 // flow, replay, session, rule, etc. does not exist
+	ExitUnavailable = 3 // MCP server or its backend cannot be reached
+	ExitOutOfScope  = 4 // target rejected by scope configuration
+	ExitValidation  = 5 // request or parameter validation failed
+)
+
+var (
+	ErrNotFound    = errors.New("not found")
+	ErrUnavailable = errors.New("backend unavailable")
+	ErrOutOfScope  = errors.New("out of scope")
+	ErrValidation  = errors.New("validation failed")
+)
+
+// ExitCode maps an error to the process exit code.
+func ExitCode(err error) int {
+	switch {
+	case err == nil:
+		return ExitOK
+	case errors.Is(err, ErrNotFound):
+		return ExitNotFound
+	case errors.Is(err, ErrUnavailable):
+		return ExitUnavailable
+	case errors.Is(err, ErrOutOfScope):
+		return ExitOutOfScope
+	case errors.Is(err, ErrValidation):
+		return ExitValidation
+	default:
+		return ExitError
+	}
+}
+
+// classifiedError keeps the original message while matching a sentinel via errors.Is.
+type classifiedError struct {
+	msg   string
+	kind  error
+	cause error
+}
+
+func (e *classifiedError) Error() string { return e.msg }
+
+func (e *classifiedError) Unwrap() []error {
+	if e.cause == nil {
+		return []error{e.kind}
+	}
+	return []error{e.kind, e.cause}
+}
+
+// Classify wraps msg as an error matching ErrNotFound, ErrUnavailable, ErrOutOfScope,
+// or ErrValidation based on its wording. Unrecognized messages return a plain error.
+func Classify(msg string) error {
+	lower := strings.ToLower(msg)
+	var kind error
+	switch {
+	case strings.Contains(lower, "not found"):
+		kind = ErrNotFound
+	case strings.Contains(lower, "out of scope"):
+		kind = ErrOutOfScope
+	case strings.HasPrefix(lower, "validation failed"), strings.HasPrefix(lower, "invalid "),
+		strings.Contains(lower, " is required"), strings.Contains(lower, " requires "),
+		strings.Contains(lower, "modification failed"):
+		kind = ErrValidation
+	case strings.Contains(lower, "connection refused"), strings.Contains(lower, "unavailable"),
+		strings.Contains(lower, "not connected"), strings.Contains(lower, "no such host"):
+		kind = ErrUnavailable
+	default:
+		return errors.New(msg)
+	}
+	return &classifiedError{msg: msg, kind: kind}
+}
+
+// Unavailable wraps err so that ExitCode reports ExitUnavailable.
+func Unavailable(err error) error {
+	return &classifiedError{msg: err.Error(), kind: ErrUnavailable, cause: err}
+}
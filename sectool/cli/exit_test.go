@@ -0,0 +1,50 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClassify(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		msg  string
+		want int
+	}{
+		{name: "flow_not_found", msg: "flow_id not found: run proxy_poll to see available flows", want: ExitNotFound},
+		{name: "replay_not_found", msg: "replay not found: replay results are ephemeral", want: ExitNotFound},
+		{name: "validation", msg: "validation failed:\n- missing Host header", want: ExitValidation},
+		{name: "required_param", msg: "flow_id is required", want: ExitValidation},
+		{name: "invalid_param", msg: "invalid timeout duration: bad", want: ExitValidation},
+		{name: "out_of_scope", msg: "target example.com is out of scope", want: ExitOutOfScope},
+		{name: "backend_down", msg: "failed to fetch proxy history: dial tcp: connection refused", want: ExitUnavailable},
+		{name: "other", msg: "request failed: EOF", want: ExitError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Classify(tt.msg)
+			assert.Equal(t, tt.msg, err.Error())
+			assert.Equal(t, tt.want, ExitCode(err))
+			// Wrapping keeps the classification
+			assert.Equal(t, tt.want, ExitCode(fmt.Errorf("proxy get: %w", err)))
+		})
+	}
+}
+
+func TestExitCode(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, ExitOK, ExitCode(nil))
+	assert.Equal(t, ExitError, ExitCode(errors.New("boom")))
+
+	cause := errors.New("dial failed")
+	err := Unavailable(cause)
+	assert.Equal(t, ExitUnavailable, ExitCode(err))
+	assert.ErrorIs(t, err, cause)
+}
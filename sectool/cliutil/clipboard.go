@@ -0,0 +1,76 @@
+package cliutil
+
+import (
+	"encoding/base64"
+	"errors"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// osc52MaxBytes is a conservative payload limit; many terminals drop larger OSC52 writes.
+const osc52MaxBytes = 100_000
+
+// CopyToClipboard places text on the system clipboard and returns the method used.
+// Over SSH, or when no clipboard tool is installed, it falls back to an OSC52
+// escape sequence which the local terminal emulator applies.
+func CopyToClipboard(text string) (string, error) {
+	if !isSSH(os.Getenv) {
+		for _, args := range clipboardCommands(runtime.GOOS, os.Getenv) {
+			if _, err := exec.LookPath(args[0]); err != nil {
+				continue
+			}
+			cmd := exec.Command(args[0], args[1:]...)
+			cmd.Stdin = strings.NewReader(text)
+			if err := cmd.Run(); err == nil {
+				return args[0], nil
+			}
+		}
+	}
+
+	if len(text) > osc52MaxBytes {
+		return "", errors.New("no clipboard tool available and output too large for OSC52")
+	}
+	tty, err := os.OpenFile("/dev/tty", os.O_WRONLY, 0)
+	if err != nil {
+		tty = os.Stderr
+	} else {
+		defer func() { _ = tty.Close() }()
+	}
+	if _, err := tty.WriteString(osc52(text, os.Getenv("TMUX") != "")); err != nil {
+		return "", err
+	}
+	return "osc52", nil
+}
+
+// clipboardCommands returns candidate clipboard commands for the platform in preference order.
+func clipboardCommands(goos string, getenv func(string) string) [][]string {
+	switch goos {
+	case "darwin":
+		return [][]string{{"pbcopy"}}
+	case "windows":
+		return [][]string{{"clip.exe"}}
+	}
+	var cmds [][]string
+	if getenv("WAYLAND_DISPLAY") != "" {
+		cmds = append(cmds, []string{"wl-copy"})
+	}
+	if getenv("DISPLAY") != "" {
+		cmds = append(cmds, []string{"xclip", "-selection", "clipboard"}, []string{"xsel", "--clipboard", "--input"})
+	}
+	return cmds
+}
+
+func isSSH(getenv func(string) string) bool {
+	return getenv("SSH_TTY") != "" || getenv("SSH_CONNECTION") != ""
+}
+
+// osc52 builds the clipboard escape sequence, wrapped for tmux passthrough when needed.
+func osc52(text string, tmux bool) string {
+	seq := "\x1b]52;c;" + base64.StdEncoding.EncodeToString([]byte(text)) + "\x07"
+	if tmux {
+		return "\x1bPtmux;\x1b" + seq + "\x1b\\"
+	}
+	return seq
+}
@@ -0,0 +1,47 @@
+package cliutil
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClipboardCommands(t *testing.T) {
+	t.Parallel()
+
+	env := func(vars map[string]string) func(string) string {
+		return func(k string) string { return vars[k] }
+	}
+
+	tests := []struct {
+		name  string
+		goos  string
+		vars  map[string]string
+		first string
+		count int
+	}{
+		{name: "darwin", goos: "darwin", first: "pbcopy", count: 1},
+		{name: "windows", goos: "windows", first: "clip.exe", count: 1},
+		{name: "linux_wayland", goos: "linux", vars: map[string]string{"WAYLAND_DISPLAY": "wayland-0", "DISPLAY": ":0"}, first: "wl-copy", count: 3},
+		{name: "linux_x11", goos: "linux", vars: map[string]string{"DISPLAY": ":0"}, first: "xclip", count: 2},
+		{name: "linux_headless", goos: "linux", count: 0},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			cmds := clipboardCommands(tc.goos, env(tc.vars))
+			assert.Len(t, cmds, tc.count)
+			if tc.count > 0 {
+				assert.Equal(t, tc.first, cmds[0][0])
+			}
+		})
+	}
+}
+
+func TestOSC52(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "\x1b]52;c;aGk=\x07", osc52("hi", false))
+	assert.Equal(t, "\x1bPtmux;\x1b\x1b]52;c;aGk=\x07\x1b\\", osc52("hi", true))
+}
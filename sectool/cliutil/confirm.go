@@ -0,0 +1,26 @@
+package cliutil
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Confirm prints prompt to stderr and waits for the user to type answer, returning
+// an error unless they do. It refuses when stdin or stderr is not a terminal, so a
+// script or agent running the CLI cannot answer for the user.
+func Confirm(prompt, answer string) error {
+	if !isTerminal(os.Stdin) || !isTerminal(os.Stderr) {
+		return errors.New("confirmation needs an interactive terminal")
+	}
+	_, _ = fmt.Fprintf(os.Stderr, "%s\nType %q to continue: ", prompt, answer)
+	line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && line == "" {
+		return fmt.Errorf("read confirmation: %w", err)
+	} else if strings.TrimSpace(line) != answer {
+		return errors.New("not confirmed")
+	}
+	return nil
+}
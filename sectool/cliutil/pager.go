@@ -0,0 +1,74 @@
+package cliutil
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// defaultPager is used when $PAGER is unset; exits immediately if output fits one screen.
+const defaultPager = "less -FRX"
+
+// NoPager disables paging for the process (set by the --no-pager global flag).
+var NoPager bool
+
+// StartPager redirects os.Stdout through $PAGER when stdout is a terminal.
+// The returned function must be called to flush output and wait for the pager.
+func StartPager() func() {
+	if NoPager || !isTerminal(os.Stdout) {
+		return func() {}
+	}
+
+	pager, ok := os.LookupEnv("PAGER")
+	if !ok {
+		pager = defaultPager
+	}
+	args := strings.Fields(pager)
+	if len(args) == 0 || args[0] == "cat" {
+		return func() {}
+	}
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		return func() {}
+	}
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Stdin = r
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Start(); err != nil {
+		_ = r.Close()
+		_ = w.Close()
+		return func() {}
+	}
+	_ = r.Close()
+
+	orig := os.Stdout
+	os.Stdout = w
+	return func() {
+		os.Stdout = orig
+		_ = w.Close()
+		_ = cmd.Wait()
+	}
+}
+
+// PageOffset converts a 1-based --page into an offset for the given limit.
+// Returns offset unchanged when page is not set.
+func PageOffset(page, limit, offset int) (int, error) {
+	if page == 0 {
+		return offset, nil
+	} else if page < 0 {
+		return 0, errors.New("--page must be 1 or greater")
+	} else if limit <= 0 {
+		return 0, errors.New("--page requires --limit")
+	} else if offset != 0 {
+		return 0, errors.New("--page and --offset are mutually exclusive")
+	}
+	return (page - 1) * limit, nil
+}
+
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,42 @@
+package cliutil
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPageOffset(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		page    int
+		limit   int
+		offset  int
+		want    int
+		wantErr string
+	}{
+		{name: "no_page_keeps_offset", limit: 10, offset: 7, want: 7},
+		{name: "first_page", page: 1, limit: 25, want: 0},
+		{name: "third_page", page: 3, limit: 25, want: 50},
+		{name: "negative_page", page: -1, limit: 10, wantErr: "--page must be 1 or greater"},
+		{name: "missing_limit", page: 2, wantErr: "--page requires --limit"},
+		{name: "offset_conflict", page: 2, limit: 10, offset: 5, wantErr: "mutually exclusive"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := PageOffset(tc.page, tc.limit, tc.offset)
+			if tc.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
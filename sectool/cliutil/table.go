@@ -0,0 +1,148 @@
+package cliutil
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"text/tabwriter"
+)
+
+// Format is a table output format for list commands.
+type Format string
+
+const (
+	FormatMarkdown Format = "markdown"
+	FormatPlain    Format = "plain"
+	FormatCSV      Format = "csv"
+	FormatTSV      Format = "tsv"
+)
+
+var formats = []Format{FormatMarkdown, FormatPlain, FormatCSV, FormatTSV}
+
+// ParseFormat validates a --format value (empty defaults to markdown).
+func ParseFormat(s string) (Format, error) {
+	if s == "" {
+		return FormatMarkdown, nil
+	}
+	f := Format(strings.ToLower(strings.TrimSpace(s)))
+	if !slices.Contains(formats, f) {
+		return "", fmt.Errorf("invalid --format %q: expected markdown, plain, csv, or tsv", s)
+	}
+	return f, nil
+}
+
+// SelectColumns parses a comma-separated --columns value against the available columns.
+// Empty spec selects all available columns in their default order.
+func SelectColumns(spec string, available []string) ([]string, error) {
+	if strings.TrimSpace(spec) == "" {
+		return available, nil
+	}
+	var selected []string
+	for _, c := range strings.Split(spec, ",") {
+		c = strings.ToLower(strings.TrimSpace(c))
+		if c == "" {
+			continue
+		} else if !slices.Contains(available, c) {
+			return nil, fmt.Errorf("unknown column %q (available: %s)", c, strings.Join(available, ", "))
+		}
+		selected = append(selected, c)
+	}
+	if len(selected) == 0 {
+		return available, nil
+	}
+	return selected, nil
+}
+
+// Table writes rows in a selected format, projecting to the selected columns.
+type Table struct {
+	format  Format
+	columns []string
+	idx     []int
+	w       io.Writer
+	tw      *tabwriter.Writer
+	cw      *csv.Writer
+}
+
+// NewTable creates a table over all columns; rows are passed in the order of all.
+func NewTable(w io.Writer, format Format, all, selected []string) *Table {
+	t := &Table{format: format, columns: selected, w: w}
+	for _, c := range selected {
+		t.idx = append(t.idx, slices.Index(all, c))
+	}
+	switch format {
+	case FormatPlain:
+		t.tw = tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		t.w = t.tw
+	case FormatCSV, FormatTSV:
+		t.cw = csv.NewWriter(w)
+		if format == FormatTSV {
+			t.cw.Comma = '\t'
+		}
+	}
+	return t
+}
+
+// Markdown reports whether the table renders as Markdown (footers and hints are only shown then).
+func (t *Table) Markdown() bool {
+	return t.format == FormatMarkdown || t.format == ""
+}
+
+// Header writes the column header row.
+func (t *Table) Header() {
+	t.write(t.columns)
+	if t.Markdown() {
+		var sb strings.Builder
+		sb.WriteString("|")
+		for _, c := range t.columns {
+			sb.WriteString(strings.Repeat("-", len(c)+2))
+			sb.WriteString("|")
+		}
+		_, _ = fmt.Fprintln(t.w, sb.String())
+	}
+}
+
+// Row writes one row; values must align with the table's full column list.
+func (t *Table) Row(values ...string) {
+	cells := make([]string, len(t.idx))
+	for i, idx := range t.idx {
+		if idx >= 0 && idx < len(values) {
+			cells[i] = values[idx]
+		}
+	}
+	t.write(cells)
+}
+
+// Flush writes any buffered output.
+func (t *Table) Flush() {
+	if t.tw != nil {
+		_ = t.tw.Flush()
+	}
+	if t.cw != nil {
+		t.cw.Flush()
+	}
+}
+
+func (t *Table) write(cells []string) {
+	switch t.format {
+	case FormatCSV, FormatTSV:
+		_ = t.cw.Write(cells)
+	case FormatPlain:
+		for i, c := range cells {
+			cells[i] = flattenCell(c)
+		}
+		_, _ = fmt.Fprintln(t.w, strings.Join(cells, "\t"))
+	default:
+		escaped := make([]string, len(cells))
+		for i, c := range cells {
+			escaped[i] = EscapeMarkdown(c)
+		}
+		_, _ = fmt.Fprintf(t.w, "| %s |\n", strings.Join(escaped, " | "))
+	}
+}
+
+// flattenCell removes characters that break tab-aligned columns.
+func flattenCell(s string) string {
+	return strings.NewReplacer("\t", " ", "\r", "", "\n", " ").Replace(s)
+}
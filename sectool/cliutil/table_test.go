@@ -0,0 +1,111 @@
+package cliutil
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseFormat(t *testing.T) {
+	t.Parallel()
+
+	f, err := ParseFormat("")
+	require.NoError(t, err)
+	assert.Equal(t, FormatMarkdown, f)
+
+	f, err = ParseFormat("CSV")
+	require.NoError(t, err)
+	assert.Equal(t, FormatCSV, f)
+
+	_, err = ParseFormat("json")
+	assert.Error(t, err)
+}
+
+func TestSelectColumns(t *testing.T) {
+	t.Parallel()
+
+	available := []string{"flow_id", "method", "host"}
+
+	tests := []struct {
+		name    string
+		spec    string
+		want    []string
+		wantErr bool
+	}{
+		{name: "empty_selects_all", spec: "", want: available},
+		{name: "subset_reordered", spec: "host, flow_id", want: []string{"host", "flow_id"}},
+		{name: "case_insensitive", spec: "METHOD", want: []string{"method"}},
+		{name: "unknown_column", spec: "flow_id,bogus", wantErr: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := SelectColumns(tc.spec, available)
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestTable(t *testing.T) {
+	t.Parallel()
+
+	all := []string{"id", "host", "path"}
+
+	tests := []struct {
+		name     string
+		format   Format
+		selected []string
+		expected string
+	}{
+		{
+			name:     "markdown",
+			format:   FormatMarkdown,
+			selected: all,
+			expected: "| id | host | path |\n|----|------|------|\n| a1 | example.com | /x\\|y |\n",
+		},
+		{
+			name:     "markdown_projection",
+			format:   FormatMarkdown,
+			selected: []string{"path", "id"},
+			expected: "| path | id |\n|------|----|\n| /x\\|y | a1 |\n",
+		},
+		{
+			name:     "csv",
+			format:   FormatCSV,
+			selected: all,
+			expected: "id,host,path\na1,example.com,/x|y\n",
+		},
+		{
+			name:     "tsv",
+			format:   FormatTSV,
+			selected: []string{"id", "host"},
+			expected: "id\thost\na1\texample.com\n",
+		},
+		{
+			name:     "plain",
+			format:   FormatPlain,
+			selected: []string{"id", "host"},
+			expected: "id  host\na1  example.com\n",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			var buf bytes.Buffer
+			tbl := NewTable(&buf, tc.format, all, tc.selected)
+			tbl.Header()
+			tbl.Row("a1", "example.com", "/x|y")
+			tbl.Flush()
+			assert.Equal(t, tc.expected, buf.String())
+		})
+	}
+}
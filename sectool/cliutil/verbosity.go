@@ -0,0 +1,39 @@
+package cliutil
+
+import (
+	"fmt"
+	"os"
+)
+
+// Verbosity controls how much detail CLI commands print.
+type Verbosity int
+
+const (
+	VerbosityQuiet   Verbosity = -1 // results only, no hints
+	VerbosityNormal  Verbosity = 0
+	VerbosityVerbose Verbosity = 1 // backend details and timing on stderr
+	VerbosityDebug   Verbosity = 2 // raw tool arguments and payloads on stderr
+)
+
+// Level is the process verbosity (set from -v, -vv, --quiet global flags).
+var Level = VerbosityNormal
+
+func Quiet() bool   { return Level <= VerbosityQuiet }
+func Verbose() bool { return Level >= VerbosityVerbose }
+func Debug() bool   { return Level >= VerbosityDebug }
+
+// Hintf prints a next-step hint to stdout unless --quiet is set.
+func Hintf(format string, args ...any) {
+	if Quiet() {
+		return
+	}
+	fmt.Printf(format, args...)
+}
+
+// Logf prints a diagnostic line to stderr when verbosity is at least level.
+func Logf(level Verbosity, format string, args ...any) {
+	if Level < level {
+		return
+	}
+	_, _ = fmt.Fprintf(os.Stderr, "[sectool] "+format+"\n", args...)
+}
@@ -0,0 +1,33 @@
+package cliutil
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// ReadWordlist reads non-empty lines from path, or stdin when path is "-".
+func ReadWordlist(path string) ([]string, error) {
+	var r io.Reader = os.Stdin
+	if path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, fmt.Errorf("open wordlist: %w", err)
+		}
+		defer func() { _ = f.Close() }()
+		r = f
+	}
+	var names []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); line != "" {
+			names = append(names, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read wordlist: %w", err)
+	}
+	return names, nil
+}
@@ -0,0 +1,97 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+)
+
+// Auth profile types.
+const (
+	AuthTypeNTLM      = "ntlm"
+	AuthTypeNegotiate = "negotiate" // SPNEGO carrying NTLM; Kerberos is not supported
+	AuthTypeDigest    = "digest"
+	AuthTypeSigV4     = "sigv4" // AWS Signature Version 4 request signing
+)
+
+// AuthProfile holds credentials for an authentication handshake or request signing
+// performed by the send path. For sigv4, Username is the access key ID and Password
+// the secret access key.
+type AuthProfile struct {
+	Type     string `json:"type"`
+	Username string `json:"username,omitempty"` // "user" or "DOMAIN\user"
+	Password string `json:"password,omitempty"`
+	// PasswordEnv names an environment variable holding the password, so it need not
+	// be stored in the config file. Takes precedence over Password when set.
+	PasswordEnv string `json:"password_env,omitempty"`
+	Domain      string `json:"domain,omitempty"`
+
+	// SigV4 signing scope and optional temporary-credential token.
+	Region       string `json:"region,omitempty"`
+	Service      string `json:"service,omitempty"`
+	SessionToken string `json:"session_token,omitempty"`
+}
+
+// AuthProfileNames returns configured auth profile names in sorted order.
+func (c *Config) AuthProfileNames() []string {
+	return slices.Sorted(maps.Keys(c.AuthProfiles))
+}
+
+// AuthProfile returns the named auth profile after validating it.
+func (c *Config) AuthProfile(name string) (*AuthProfile, error) {
+	p, ok := c.AuthProfiles[name]
+	if !ok || p == nil {
+		if len(c.AuthProfiles) == 0 {
+			return nil, fmt.Errorf("unknown auth profile %q (no auth_profiles configured)", name)
+		}
+		return nil, fmt.Errorf("unknown auth profile %q (available: %s)", name, strings.Join(c.AuthProfileNames(), ", "))
+	}
+	if err := p.Validate(); err != nil {
+		return nil, fmt.Errorf("auth profile %q: %w", name, err)
+	}
+	return p, nil
+}
+
+// Validate checks that the profile type is known and its credentials are present.
+func (p *AuthProfile) Validate() error {
+	switch strings.ToLower(p.Type) {
+	case AuthTypeNTLM, AuthTypeNegotiate, AuthTypeDigest:
+		if p.Username == "" {
+			return errors.New("username is required")
+		}
+	case AuthTypeSigV4:
+		if p.Username == "" || p.Region == "" || p.Service == "" {
+			return errors.New("username (access key ID), region and service are required")
+		}
+	case "":
+		return errors.New("type is required")
+	default:
+		return fmt.Errorf("unsupported type %q (supported: %s, %s, %s, %s)",
+			p.Type, AuthTypeNTLM, AuthTypeNegotiate, AuthTypeDigest, AuthTypeSigV4)
+	}
+	return nil
+}
+
+// Secret returns the password, read from PasswordEnv when set.
+func (p *AuthProfile) Secret() string {
+	if p.PasswordEnv != "" {
+		return os.Getenv(p.PasswordEnv)
+	}
+	return p.Password
+}
+
+// Credentials returns the user and domain, splitting a "DOMAIN\user" username when
+// Domain is not set explicitly. Only NTLM-based types use the domain.
+func (p *AuthProfile) Credentials() (user, domain string) {
+	user, domain = p.Username, p.Domain
+	if d, u, ok := strings.Cut(p.Username, `\`); ok {
+		user = u
+		if domain == "" {
+			domain = d
+		}
+	}
+	return user, domain
+}
@@ -0,0 +1,68 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuthProfile(t *testing.T) {
+	t.Parallel()
+
+	cfg := DefaultConfig()
+	cfg.AuthProfiles = map[string]*AuthProfile{
+		"corp":    {Type: "NTLM", Username: `CORP\alice`, Password: "pw"},
+		"broken":  {Type: "kerberos", Username: "bob"},
+		"nouser":  {Type: AuthTypeNegotiate},
+		"noscope": {Type: AuthTypeSigV4, Username: "AKID"},
+		"domain":  {Type: AuthTypeNTLM, Username: `CORP\alice`, Domain: "OTHER"},
+		"envpass": {Type: AuthTypeNTLM, Username: "carol", Password: "ignored", PasswordEnv: "SECTOOL_TEST_AUTH_PASSWORD"},
+	}
+
+	t.Run("credentials", func(t *testing.T) {
+		p, err := cfg.AuthProfile("corp")
+		require.NoError(t, err)
+		user, domain := p.Credentials()
+		assert.Equal(t, "alice", user)
+		assert.Equal(t, "CORP", domain)
+		assert.Equal(t, "pw", p.Secret())
+
+		user, domain = cfg.AuthProfiles["domain"].Credentials()
+		assert.Equal(t, "alice", user)
+		assert.Equal(t, "OTHER", domain)
+	})
+
+	t.Run("unknown", func(t *testing.T) {
+		_, err := cfg.AuthProfile("missing")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "available: broken, corp, domain, envpass, noscope, nouser")
+
+		_, err = DefaultConfig().AuthProfile("corp")
+		assert.ErrorContains(t, err, "no auth_profiles configured")
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		_, err := cfg.AuthProfile("broken")
+		assert.ErrorContains(t, err, "unsupported type")
+		_, err = cfg.AuthProfile("nouser")
+		assert.ErrorContains(t, err, "username is required")
+		_, err = cfg.AuthProfile("noscope")
+		assert.ErrorContains(t, err, "region and service are required")
+	})
+
+	t.Run("profile_overlay", func(t *testing.T) {
+		c := DefaultConfig()
+		c.AuthProfiles = map[string]*AuthProfile{"corp": {Type: AuthTypeNTLM, Username: "a"}}
+		c.Profiles = map[string]*Config{"lab": {AuthProfiles: map[string]*AuthProfile{"lab": {Type: AuthTypeNTLM, Username: "b"}}}}
+		require.NoError(t, c.ApplyProfile("lab"))
+		assert.Equal(t, []string{"corp", "lab"}, c.AuthProfileNames())
+	})
+}
+
+func TestAuthProfileSecretEnv(t *testing.T) {
+	t.Setenv("SECTOOL_TEST_AUTH_PASSWORD", "from-env")
+
+	p := &AuthProfile{Type: AuthTypeNTLM, Username: "carol", Password: "ignored", PasswordEnv: "SECTOOL_TEST_AUTH_PASSWORD"}
+	assert.Equal(t, "from-env", p.Secret())
+}
@@ -13,8 +13,14 @@ const (
 	DefaultBurpMCPURL = "http://127.0.0.1:9876/sse"
 	DefaultMCPPort    = 9119
 	DefaultProxyPort  = 8080
+
+	// DefaultMaxOutputBytes caps MCP tool results so a large body cannot flood the agent's context.
+	DefaultMaxOutputBytes = 100000
 )
 
+// DefaultBurpRESTURL is Burp Suite Professional's REST API, used to run scans.
+const DefaultBurpRESTURL = "http://127.0.0.1:1337"
+
 // RevNum is injected at build time via ldflags; defaults to "dev".
 var RevNum = "dev"
 
@@ -22,6 +28,15 @@ func UserAgent() string {
 	return "Mozilla/5.0 (compatible; go-harden/llm-security-toolbox sectool-v" + Version + "-" + RevNum + ")"
 }
 
+// ConfirmKeyFileName is the file beside the config holding the key the CLI sends
+// to confirm requests outside the target allowlist and project scope.
+const ConfirmKeyFileName = "confirm.key"
+
+// ConfirmKeyPath returns the confirm key file for the config at configPath.
+func ConfirmKeyPath(configPath string) string {
+	return filepath.Join(filepath.Dir(configPath), ConfirmKeyFileName)
+}
+
 // DefaultPath returns ~/.sectool/config.json.
 func DefaultPath() string {
 	home, err := os.UserHomeDir()
@@ -32,11 +47,41 @@ func DefaultPath() string {
 }
 
 type Config struct {
-	Version      string        `json:"version"`
-	MCPPort      int           `json:"mcp_port,omitempty"`
-	ProxyPort    int           `json:"proxy_port,omitempty"`
-	BurpRequired *bool         `json:"burp_required,omitempty"`
-	Crawler      CrawlerConfig `json:"crawler,omitempty"`
+	Version      string `json:"version,omitempty"`
+	MCPPort      int    `json:"mcp_port,omitempty"`
+	ProxyPort    int    `json:"proxy_port,omitempty"`
+	BurpMCPURL   string `json:"burp_mcp_url,omitempty"`
+	BurpRequired *bool  `json:"burp_required,omitempty"`
+	BurpRESTURL  string `json:"burp_rest_url,omitempty"` // with the API key as first path segment when set
+	UpdateCheck  *bool  `json:"update_check,omitempty"`  // look for a newer release at service start
+	// MaxOutputBytes caps every MCP tool result; a smaller per-call max_output_bytes wins.
+	MaxOutputBytes int             `json:"max_output_bytes,omitempty"`
+	Crawler        CrawlerConfig   `json:"crawler,omitempty"`
+	OAST           OastConfig      `json:"oast,omitempty"`
+	RateLimit      RateLimitConfig `json:"rate_limit,omitempty"`
+
+	// TargetAllowlist holds the "host" or "host/path" globs (as in Scope) the user has
+	// signed off on. When set, no request may go elsewhere, whatever the project scope,
+	// unless confirmed with the CLI's --confirm-out-of-scope.
+	TargetAllowlist []string `json:"target_allowlist,omitempty"`
+
+	// Scope, when set (usually in a profile), replaces the project's .sectool/scope.json
+	// as the project scope at service start.
+	Scope *Scope `json:"scope,omitempty"`
+
+	// Hosts maps hostnames to the IP address or hostname sectool connects to instead
+	// of resolving them, like /etc/hosts entries. Host headers, TLS server names and
+	// scope checks keep the original hostname.
+	Hosts map[string]string `json:"hosts,omitempty"`
+
+	// GuideVars are values for {{name}} placeholders in workflow guides.
+	GuideVars map[string]string `json:"guide_vars,omitempty"`
+
+	// AuthProfiles are named credentials for send-time authentication handshakes.
+	AuthProfiles map[string]*AuthProfile `json:"auth_profiles,omitempty"`
+
+	// Profiles are named overrides selected with --profile; see ApplyProfile.
+	Profiles map[string]*Config `json:"profiles,omitempty"`
 }
 
 type CrawlerConfig struct {
@@ -52,15 +97,51 @@ type CrawlerConfig struct {
 	Recon                *bool    `json:"recon,omitempty"`
 }
 
+// RateLimitConfig throttles outbound requests from replays, discovery and
+// brute-force tools and the crawler, so tests do not overload a target. Zero
+// values are unlimited.
+type RateLimitConfig struct {
+	RequestsPerSecond     float64 `json:"requests_per_second,omitempty"`      // across all hosts
+	HostRequestsPerSecond float64 `json:"host_requests_per_second,omitempty"` // to each host
+	MaxConcurrent         int     `json:"max_concurrent,omitempty"`           // requests in flight across all hosts
+	HostMaxConcurrent     int     `json:"host_max_concurrent,omitempty"`      // requests in flight to each host
+	JitterMS              int     `json:"jitter_ms,omitempty"`                // random extra delay before each request, up to this
+}
+
+// OastConfig selects the interactsh server used for OAST sessions. With no
+// server URLs the public interactsh servers are used.
+type OastConfig struct {
+	ServerURLs []string `json:"server_urls,omitempty"`
+	Token      string   `json:"token,omitempty"`
+	// TokenEnv names an environment variable holding the server token, so it need
+	// not be stored in the config file. Takes precedence over Token when set.
+	TokenEnv string `json:"token_env,omitempty"`
+	// NotifyURL receives a JSON POST for each interaction as it arrives.
+	NotifyURL string `json:"notify_url,omitempty"`
+	// NotifyFile has each interaction appended as a JSON line as it arrives. A relative
+	// path is resolved against the project directory.
+	NotifyFile string `json:"notify_file,omitempty"`
+}
+
+// ServerToken returns the server auth token, read from TokenEnv when set.
+func (o OastConfig) ServerToken() string {
+	if o.TokenEnv != "" {
+		return os.Getenv(o.TokenEnv)
+	}
+	return o.Token
+}
+
 // DefaultConfig returns a Config with default values.
 func DefaultConfig() *Config {
 	t := true
 	f := false
 	return &Config{
-		Version:      Version,
-		MCPPort:      DefaultMCPPort,
-		ProxyPort:    DefaultProxyPort,
-		BurpRequired: &f,
+		Version:        Version,
+		MCPPort:        DefaultMCPPort,
+		ProxyPort:      DefaultProxyPort,
+		BurpRequired:   &f,
+		UpdateCheck:    &t,
+		MaxOutputBytes: DefaultMaxOutputBytes,
 		Crawler: CrawlerConfig{
 			MaxResponseBodyBytes: 1048576, // 1MB
 			IncludeSubdomains:    &t,
@@ -104,12 +185,18 @@ func Load(path string) (*Config, error) {
 	if cfg.ProxyPort == 0 {
 		cfg.ProxyPort = DefaultProxyPort
 	}
+	if cfg.MaxOutputBytes == 0 {
+		cfg.MaxOutputBytes = DefaultMaxOutputBytes
+	}
 
 	// Apply CrawlerConfig defaults for zero values
 	defaults := DefaultConfig()
 	if cfg.BurpRequired == nil {
 		cfg.BurpRequired = defaults.BurpRequired
 	}
+	if cfg.UpdateCheck == nil {
+		cfg.UpdateCheck = defaults.UpdateCheck
+	}
 	if cfg.Crawler.MaxResponseBodyBytes == 0 {
 		cfg.Crawler.MaxResponseBodyBytes = defaults.Crawler.MaxResponseBodyBytes
 	}
@@ -54,6 +54,7 @@ func TestLoadAppliesDefaults(t *testing.T) {
 	cfg, err := Load(path)
 	require.NoError(t, err)
 	assert.Equal(t, DefaultMCPPort, cfg.MCPPort)
+	assert.True(t, *cfg.UpdateCheck)
 }
 
 func TestLoadInvalidJSON(t *testing.T) {
@@ -115,3 +116,10 @@ func TestDefaultPath(t *testing.T) {
 	assert.Contains(t, path, ".sectool")
 	assert.Contains(t, path, "config.json")
 }
+
+func TestOastConfigServerToken(t *testing.T) {
+	t.Setenv("SECTOOL_TEST_OAST_TOKEN", "from-env")
+
+	assert.Equal(t, "inline", OastConfig{Token: "inline"}.ServerToken())
+	assert.Equal(t, "from-env", OastConfig{Token: "inline", TokenEnv: "SECTOOL_TEST_OAST_TOKEN"}.ServerToken())
+}
@@ -0,0 +1,497 @@
+package config
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"maps"
+	"math"
+	"net"
+	"net/url"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/go-harden/interactsh-lite/oobclient"
+)
+
+// Key describes a config key settable with `sectool config set`.
+type Key struct {
+	Name        string
+	Description string
+	get         func(*Config) string
+	set         func(*Config, string) error
+}
+
+// Keys returns all settable config keys in display order.
+func Keys() []Key {
+	return []Key{
+		{
+			Name:        "mcp_port",
+			Description: "MCP server port",
+			get:         func(c *Config) string { return strconv.Itoa(c.MCPPort) },
+			set:         intSetter(func(c *Config) *int { return &c.MCPPort }, 1, 65535),
+		},
+		{
+			Name:        "proxy_port",
+			Description: "built-in proxy port",
+			get:         func(c *Config) string { return strconv.Itoa(c.ProxyPort) },
+			set:         intSetter(func(c *Config) *int { return &c.ProxyPort }, 1, 65535),
+		},
+		{
+			Name:        "burp_mcp_url",
+			Description: "Burp MCP SSE endpoint URL",
+			get: func(c *Config) string {
+				if c.BurpMCPURL == "" {
+					return DefaultBurpMCPURL
+				}
+				return c.BurpMCPURL
+			},
+			set: func(c *Config, v string) error {
+				u, err := url.Parse(v)
+				if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+					return fmt.Errorf("invalid URL %q: must be http(s)://host[:port]/path", v)
+				}
+				c.BurpMCPURL = v
+				return nil
+			},
+		},
+		{
+			Name:        "burp_rest_url",
+			Description: "Burp REST API URL for scans, http://host:port[/api_key]",
+			get: func(c *Config) string {
+				if c.BurpRESTURL == "" {
+					return DefaultBurpRESTURL
+				}
+				return c.BurpRESTURL
+			},
+			set: func(c *Config, v string) error {
+				u, err := url.Parse(v)
+				if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+					return fmt.Errorf("invalid URL %q: must be http(s)://host[:port][/api_key]", v)
+				}
+				c.BurpRESTURL = v
+				return nil
+			},
+		},
+		{
+			Name:        "burp_required",
+			Description: "fail startup if Burp MCP is unavailable",
+			get:         boolGetter(func(c *Config) **bool { return &c.BurpRequired }),
+			set:         boolSetter(func(c *Config) **bool { return &c.BurpRequired }),
+		},
+		{
+			Name:        "update_check",
+			Description: "check GitHub for a newer sectool release when the service starts",
+			get:         boolGetter(func(c *Config) **bool { return &c.UpdateCheck }),
+			set:         boolSetter(func(c *Config) **bool { return &c.UpdateCheck }),
+		},
+		{
+			Name:        "max_output_bytes",
+			Description: "cap on MCP tool result size; larger results are shaped and kept for output_get",
+			get:         func(c *Config) string { return strconv.Itoa(c.MaxOutputBytes) },
+			set:         intSetter(func(c *Config) *int { return &c.MaxOutputBytes }, 1024, 0),
+		},
+		{
+			Name:        "target_allowlist",
+			Description: "comma-separated host or host/path globs that requests may target, whatever the project scope (* = any)",
+			get: func(c *Config) string {
+				if len(c.TargetAllowlist) == 0 {
+					return "*"
+				}
+				return strings.Join(c.TargetAllowlist, ",")
+			},
+			set: func(c *Config, v string) error {
+				patterns, err := parseScopePatterns(v)
+				if err != nil {
+					return err
+				}
+				c.TargetAllowlist = patterns
+				return nil
+			},
+		},
+		{
+			Name:        "scope.include",
+			Description: "comma-separated host or host/path globs used as the project scope in place of .sectool/scope.json (usually per profile)",
+			get:         scopeGetter(func(s *Scope) []string { return s.Include }),
+			set:         scopeSetter(func(s *Scope) *[]string { return &s.Include }),
+		},
+		{
+			Name:        "scope.exclude",
+			Description: "comma-separated globs excluded from scope.include",
+			get:         scopeGetter(func(s *Scope) []string { return s.Exclude }),
+			set:         scopeSetter(func(s *Scope) *[]string { return &s.Exclude }),
+		},
+		{
+			Name:        "crawler.max_response_body_bytes",
+			Description: "maximum response body size stored per crawled flow",
+			get:         func(c *Config) string { return strconv.Itoa(c.Crawler.MaxResponseBodyBytes) },
+			set:         intSetter(func(c *Config) *int { return &c.Crawler.MaxResponseBodyBytes }, 1, 0),
+		},
+		{
+			Name:        "crawler.include_subdomains",
+			Description: "crawl subdomains of seed hosts",
+			get:         boolGetter(func(c *Config) **bool { return &c.Crawler.IncludeSubdomains }),
+			set:         boolSetter(func(c *Config) **bool { return &c.Crawler.IncludeSubdomains }),
+		},
+		{
+			Name:        "crawler.disallowed_paths",
+			Description: "comma-separated path globs the crawler never visits",
+			get:         func(c *Config) string { return strings.Join(c.Crawler.DisallowedPaths, ",") },
+			set: func(c *Config, v string) error {
+				var paths []string
+				for _, p := range strings.Split(v, ",") {
+					if p = strings.TrimSpace(p); p != "" {
+						paths = append(paths, p)
+					}
+				}
+				if len(paths) == 0 {
+					// empty lists are omitted from JSON and would load as defaults
+					return errors.New("must contain at least one path glob")
+				}
+				c.Crawler.DisallowedPaths = paths
+				return nil
+			},
+		},
+		{
+			Name:        "crawler.delay_ms",
+			Description: "delay between crawler requests in milliseconds",
+			get:         func(c *Config) string { return strconv.Itoa(c.Crawler.DelayMS) },
+			set:         intSetter(func(c *Config) *int { return &c.Crawler.DelayMS }, 1, 0),
+		},
+		{
+			Name:        "crawler.parallelism",
+			Description: "concurrent crawler requests",
+			get:         func(c *Config) string { return strconv.Itoa(c.Crawler.Parallelism) },
+			set:         intSetter(func(c *Config) *int { return &c.Crawler.Parallelism }, 1, 100),
+		},
+		{
+			Name:        "crawler.max_depth",
+			Description: "maximum crawl depth",
+			get:         func(c *Config) string { return strconv.Itoa(c.Crawler.MaxDepth) },
+			set:         intSetter(func(c *Config) *int { return &c.Crawler.MaxDepth }, 1, 0),
+		},
+		{
+			Name:        "crawler.max_requests",
+			Description: "maximum requests per crawl session",
+			get:         func(c *Config) string { return strconv.Itoa(c.Crawler.MaxRequests) },
+			set:         intSetter(func(c *Config) *int { return &c.Crawler.MaxRequests }, 1, 0),
+		},
+		{
+			Name:        "crawler.extract_forms",
+			Description: "extract forms from crawled pages",
+			get:         boolGetter(func(c *Config) **bool { return &c.Crawler.ExtractForms }),
+			set:         boolSetter(func(c *Config) **bool { return &c.Crawler.ExtractForms }),
+		},
+		{
+			Name:        "crawler.submit_forms",
+			Description: "submit discovered forms while crawling",
+			get:         boolGetter(func(c *Config) **bool { return &c.Crawler.SubmitForms }),
+			set:         boolSetter(func(c *Config) **bool { return &c.Crawler.SubmitForms }),
+		},
+		{
+			Name:        "crawler.recon",
+			Description: "run recon to discover additional URLs for seed domains",
+			get:         boolGetter(func(c *Config) **bool { return &c.Crawler.Recon }),
+			set:         boolSetter(func(c *Config) **bool { return &c.Crawler.Recon }),
+		},
+		{
+			Name:        "oast.server_urls",
+			Description: "comma-separated interactsh servers for OAST sessions (empty = public servers)",
+			get: func(c *Config) string {
+				if len(c.OAST.ServerURLs) == 0 {
+					return strings.Join(oobclient.DefaultOptions.ServerURLs, ",")
+				}
+				return strings.Join(c.OAST.ServerURLs, ",")
+			},
+			set: func(c *Config, v string) error {
+				var urls []string
+				for _, s := range strings.Split(v, ",") {
+					if s = strings.TrimSpace(s); s == "" {
+						continue
+					}
+					if !validOastServer(s) {
+						return fmt.Errorf("invalid server %q: must be host[:port] or http(s)://host[:port]", s)
+					}
+					urls = append(urls, s)
+				}
+				c.OAST.ServerURLs = urls
+				return nil
+			},
+		},
+		{
+			Name:        "oast.notify_url",
+			Description: "webhook receiving a JSON POST per OAST interaction as it arrives, or off",
+			get:         func(c *Config) string { return cmp.Or(c.OAST.NotifyURL, "off") },
+			set: func(c *Config, v string) error {
+				if v == "off" {
+					v = ""
+				} else if v != "" {
+					u, err := url.Parse(v)
+					if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+						return fmt.Errorf("invalid URL %q: must be http(s)://host[:port]/path", v)
+					}
+				}
+				c.OAST.NotifyURL = v
+				return nil
+			},
+		},
+		{
+			Name:        "oast.notify_file",
+			Description: "file to append each OAST interaction to as a JSON line (relative to the project directory), or off",
+			get:         func(c *Config) string { return cmp.Or(c.OAST.NotifyFile, "off") },
+			set: func(c *Config, v string) error {
+				if v = strings.TrimSpace(v); v == "off" {
+					v = ""
+				}
+				c.OAST.NotifyFile = v
+				return nil
+			},
+		},
+		{
+			Name:        "rate_limit.requests_per_second",
+			Description: "outbound requests per second across all hosts (0 = unlimited)",
+			get:         func(c *Config) string { return formatFloat(c.RateLimit.RequestsPerSecond) },
+			set:         floatSetter(func(c *Config) *float64 { return &c.RateLimit.RequestsPerSecond }),
+		},
+		{
+			Name:        "rate_limit.host_requests_per_second",
+			Description: "outbound requests per second to each host (0 = unlimited)",
+			get:         func(c *Config) string { return formatFloat(c.RateLimit.HostRequestsPerSecond) },
+			set:         floatSetter(func(c *Config) *float64 { return &c.RateLimit.HostRequestsPerSecond }),
+		},
+		{
+			Name:        "rate_limit.max_concurrent",
+			Description: "outbound requests in flight across all hosts (0 = unlimited)",
+			get:         func(c *Config) string { return strconv.Itoa(c.RateLimit.MaxConcurrent) },
+			set:         intSetter(func(c *Config) *int { return &c.RateLimit.MaxConcurrent }, 0, 0),
+		},
+		{
+			Name:        "rate_limit.host_max_concurrent",
+			Description: "outbound requests in flight to each host (0 = unlimited)",
+			get:         func(c *Config) string { return strconv.Itoa(c.RateLimit.HostMaxConcurrent) },
+			set:         intSetter(func(c *Config) *int { return &c.RateLimit.HostMaxConcurrent }, 0, 0),
+		},
+		{
+			Name:        "rate_limit.jitter_ms",
+			Description: "random extra delay before each outbound request, up to this many milliseconds",
+			get:         func(c *Config) string { return strconv.Itoa(c.RateLimit.JitterMS) },
+			set:         intSetter(func(c *Config) *int { return &c.RateLimit.JitterMS }, 0, 0),
+		},
+	}
+}
+
+// validOastServer reports whether s is an interactsh server as host[:port] or an
+// http(s) URL.
+func validOastServer(s string) bool {
+	if !strings.Contains(s, "://") {
+		s = "https://" + s
+	}
+	u, err := url.Parse(s)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != "" && strings.Trim(u.Path, "/") == ""
+}
+
+// Get returns the string form of a config key.
+func (c *Config) Get(name string) (string, error) {
+	k, err := lookupKey(name)
+	if err != nil {
+		return "", err
+	}
+	return k.get(c), nil
+}
+
+// Set validates and assigns a config key from its string form.
+func (c *Config) Set(name, value string) error {
+	k, err := lookupKey(name)
+	if err != nil {
+		return err
+	}
+	if err := k.set(c, strings.TrimSpace(value)); err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	return nil
+}
+
+// GuideVarPrefix prefixes config keys naming a guide variable, e.g. guide_vars.report_path.
+const GuideVarPrefix = "guide_vars."
+
+var guideVarNameRe = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+// guideVarKey is the dynamic key for one guide variable; an empty value removes it.
+func guideVarKey(name string) (Key, error) {
+	if !guideVarNameRe.MatchString(name) {
+		return Key{}, fmt.Errorf("invalid guide variable name %q: use lowercase letters, digits, and _", name)
+	}
+	return Key{
+		Name:        GuideVarPrefix + name,
+		Description: "guide placeholder {{" + name + "}}",
+		get:         func(c *Config) string { return c.GuideVars[name] },
+		set: func(c *Config, v string) error {
+			if v == "" {
+				delete(c.GuideVars, name)
+				return nil
+			}
+			if c.GuideVars == nil {
+				c.GuideVars = make(map[string]string)
+			}
+			c.GuideVars[name] = v
+			return nil
+		},
+	}, nil
+}
+
+// GuideVarNames returns the configured guide variable names in sorted order.
+func (c *Config) GuideVarNames() []string {
+	return slices.Sorted(maps.Keys(c.GuideVars))
+}
+
+// HostPrefix prefixes config keys naming a host override, e.g. hosts.app.example.com.
+const HostPrefix = "hosts."
+
+var hostNameRe = regexp.MustCompile(`^[a-z0-9]([a-z0-9.-]*[a-z0-9])?$`)
+
+// hostKey is the dynamic key for one host override; an empty value removes it.
+func hostKey(name string) (Key, error) {
+	name = strings.ToLower(name)
+	if !hostNameRe.MatchString(name) {
+		return Key{}, fmt.Errorf("invalid hostname %q", name)
+	}
+	return Key{
+		Name:        HostPrefix + name,
+		Description: "address connections to " + name + " go to",
+		get:         func(c *Config) string { return c.Hosts[name] },
+		set: func(c *Config, v string) error {
+			if v == "" {
+				delete(c.Hosts, name)
+				return nil
+			} else if net.ParseIP(v) == nil && !hostNameRe.MatchString(strings.ToLower(v)) {
+				return fmt.Errorf("invalid address %q: must be an IP address or hostname without a port", v)
+			}
+			if c.Hosts == nil {
+				c.Hosts = make(map[string]string)
+			}
+			c.Hosts[name] = v
+			return nil
+		},
+	}, nil
+}
+
+// HostNames returns the hostnames with an override in sorted order.
+func (c *Config) HostNames() []string {
+	return slices.Sorted(maps.Keys(c.Hosts))
+}
+
+func lookupKey(name string) (Key, error) {
+	if varName, ok := strings.CutPrefix(name, GuideVarPrefix); ok {
+		return guideVarKey(varName)
+	} else if host, ok := strings.CutPrefix(name, HostPrefix); ok {
+		return hostKey(host)
+	}
+	keys := Keys()
+	idx := slices.IndexFunc(keys, func(k Key) bool { return k.Name == name })
+	if idx < 0 {
+		names := make([]string, len(keys))
+		for i, k := range keys {
+			names[i] = k.Name
+		}
+		return Key{}, fmt.Errorf("unknown config key %q (valid keys: %s, %s<name>, %s<hostname>)", name, strings.Join(names, ", "), GuideVarPrefix, HostPrefix)
+	}
+	return keys[idx], nil
+}
+
+// intSetter parses an integer in [lo, hi]; hi of 0 means unbounded.
+func intSetter(field func(*Config) *int, lo, hi int) func(*Config, string) error {
+	return func(c *Config, v string) error {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return fmt.Errorf("invalid integer %q", v)
+		} else if n < lo || (hi > 0 && n > hi) {
+			if hi > 0 {
+				return fmt.Errorf("must be between %d and %d", lo, hi)
+			}
+			return fmt.Errorf("must be at least %d", lo)
+		}
+		*field(c) = n
+		return nil
+	}
+}
+
+// floatSetter parses a non-negative number.
+func floatSetter(field func(*Config) *float64) func(*Config, string) error {
+	return func(c *Config, v string) error {
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil || math.IsNaN(f) || math.IsInf(f, 0) {
+			return fmt.Errorf("invalid number %q", v)
+		} else if f < 0 {
+			return errors.New("must not be negative")
+		}
+		*field(c) = f
+		return nil
+	}
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
+
+func boolGetter(field func(*Config) **bool) func(*Config) string {
+	return func(c *Config) string {
+		b := *field(c)
+		return strconv.FormatBool(b != nil && *b)
+	}
+}
+
+func boolSetter(field func(*Config) **bool) func(*Config, string) error {
+	return func(c *Config, v string) error {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return errors.New("must be true or false")
+		}
+		*field(c) = &b
+		return nil
+	}
+}
+
+// parseScopePatterns splits a comma-separated list of scope globs, validating each.
+func parseScopePatterns(v string) ([]string, error) {
+	var patterns []string
+	for _, p := range strings.Split(v, ",") {
+		if p = strings.TrimSpace(p); p == "" {
+			continue
+		}
+		if err := ValidateScopePattern(p); err != nil {
+			return nil, err
+		}
+		patterns = append(patterns, p)
+	}
+	return patterns, nil
+}
+
+// scopeGetter reads a Scope list; without a config scope the project's scope file applies.
+func scopeGetter(field func(*Scope) []string) func(*Config) string {
+	return func(c *Config) string {
+		if c.Scope == nil {
+			return "(project scope)"
+		}
+		return strings.Join(field(c.Scope), ",")
+	}
+}
+
+// scopeSetter sets a Scope list, dropping the config scope once both lists are empty.
+func scopeSetter(field func(*Scope) *[]string) func(*Config, string) error {
+	return func(c *Config, v string) error {
+		patterns, err := parseScopePatterns(v)
+		if err != nil {
+			return err
+		}
+		if c.Scope == nil {
+			c.Scope = &Scope{}
+		}
+		*field(c.Scope) = patterns
+		if len(c.Scope.Include) == 0 && len(c.Scope.Exclude) == 0 {
+			c.Scope = nil
+		}
+		return nil
+	}
+}
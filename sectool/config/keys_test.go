@@ -0,0 +1,100 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigSetGet(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		key     string
+		value   string
+		want    string
+		wantErr string
+	}{
+		{name: "int", key: "crawler.max_depth", value: "3", want: "3"},
+		{name: "int_out_of_range", key: "mcp_port", value: "70000", wantErr: "between 1 and 65535"},
+		{name: "int_invalid", key: "proxy_port", value: "abc", wantErr: "invalid integer"},
+		{name: "int_below_min", key: "max_output_bytes", value: "100", wantErr: "at least 1024"},
+		{name: "bool", key: "burp_required", value: "true", want: "true"},
+		{name: "update_check_off", key: "update_check", value: "false", want: "false"},
+		{name: "bool_invalid", key: "crawler.recon", value: "maybe", wantErr: "true or false"},
+		{name: "url", key: "burp_mcp_url", value: "http://10.0.0.5:9876/sse", want: "http://10.0.0.5:9876/sse"},
+		{name: "url_invalid", key: "burp_mcp_url", value: "not a url", wantErr: "invalid URL"},
+		{name: "list_trimmed", key: "crawler.disallowed_paths", value: " *logout* , ,*admin*", want: "*logout*,*admin*"},
+		{name: "list_empty", key: "crawler.disallowed_paths", value: " , ", wantErr: "at least one"},
+		{name: "oast_servers", key: "oast.server_urls", value: "oast.example.com, https://oob.internal:8443", want: "oast.example.com,https://oob.internal:8443"},
+		{name: "oast_servers_invalid", key: "oast.server_urls", value: "oast.example.com/path", wantErr: "invalid server"},
+		{name: "oast_servers_default", key: "oast.server_urls", value: "", want: "oast.pro,oast.live,oast.site,oast.online,oast.fun,oast.me"},
+		{name: "oast_notify_url", key: "oast.notify_url", value: "http://127.0.0.1:9000/hook", want: "http://127.0.0.1:9000/hook"},
+		{name: "oast_notify_url_invalid", key: "oast.notify_url", value: "127.0.0.1:9000", wantErr: "invalid URL"},
+		{name: "oast_notify_url_off", key: "oast.notify_url", value: "off", want: "off"},
+		{name: "oast_notify_file", key: "oast.notify_file", value: " oast-events.jsonl ", want: "oast-events.jsonl"},
+		{name: "float", key: "rate_limit.host_requests_per_second", value: "2.5", want: "2.5"},
+		{name: "float_negative", key: "rate_limit.requests_per_second", value: "-1", wantErr: "must not be negative"},
+		{name: "float_invalid", key: "rate_limit.requests_per_second", value: "fast", wantErr: "invalid number"},
+		{name: "int_zero_allowed", key: "rate_limit.max_concurrent", value: "0", want: "0"},
+		{name: "allowlist", key: "target_allowlist", value: "*.example.com, api.test.com/v2/*", want: "*.example.com,api.test.com/v2/*"},
+		{name: "allowlist_url", key: "target_allowlist", value: "https://example.com", wantErr: "invalid scope pattern"},
+		{name: "allowlist_empty", key: "target_allowlist", value: "", want: "*"},
+		{name: "scope_include", key: "scope.include", value: "*.staging.example.com, api.staging.example.com/v2/*", want: "*.staging.example.com,api.staging.example.com/v2/*"},
+		{name: "scope_include_url", key: "scope.include", value: "https://staging.example.com", wantErr: "invalid scope pattern"},
+		{name: "scope_include_empty", key: "scope.include", value: "", want: "(project scope)"},
+		{name: "unknown_key", key: "bogus", value: "1", wantErr: "unknown config key"},
+		{name: "guide_var", key: "guide_vars.report_path", value: " reports/app.md ", want: "reports/app.md"},
+		{name: "guide_var_invalid_name", key: "guide_vars.Report-Path", value: "x", wantErr: "invalid guide variable name"},
+		{name: "host", key: "hosts.App.Example.com", value: "10.0.0.7", want: "10.0.0.7"},
+		{name: "host_ipv6", key: "hosts.app.example.com", value: "fd00::7", want: "fd00::7"},
+		{name: "host_with_port", key: "hosts.app.example.com", value: "10.0.0.7:8443", wantErr: "without a port"},
+		{name: "host_invalid_name", key: "hosts.app example.com", value: "10.0.0.7", wantErr: "invalid hostname"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			cfg := DefaultConfig()
+			err := cfg.Set(tc.key, tc.value)
+			if tc.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+
+			got, err := cfg.Get(tc.key)
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestConfigKeysGettable(t *testing.T) {
+	t.Parallel()
+
+	cfg := DefaultConfig()
+	for _, k := range Keys() {
+		v, err := cfg.Get(k.Name)
+		require.NoError(t, err, k.Name)
+		assert.NotEmpty(t, v, k.Name)
+	}
+}
+
+func TestConfigSetPersists(t *testing.T) {
+	t.Parallel()
+
+	path := t.TempDir() + "/config.json"
+	cfg := DefaultConfig()
+	require.NoError(t, cfg.Set("crawler.disallowed_paths", "*admin*"))
+	require.NoError(t, cfg.Set("burp_mcp_url", "http://127.0.0.1:1234/sse"))
+	require.NoError(t, cfg.Save(path))
+
+	loaded, err := Load(path)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"*admin*"}, loaded.Crawler.DisallowedPaths)
+	assert.Equal(t, "http://127.0.0.1:1234/sse", loaded.BurpMCPURL)
+}
@@ -0,0 +1,166 @@
+package config
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+)
+
+// ProfileEnv selects a profile when --profile is not given.
+const ProfileEnv = "SECTOOL_PROFILE"
+
+// ResolveProfile returns the profile flag value, falling back to $SECTOOL_PROFILE.
+func ResolveProfile(flag string) string {
+	if flag != "" {
+		return flag
+	}
+	return os.Getenv(ProfileEnv)
+}
+
+// ProfileNames returns configured profile names in sorted order.
+func (c *Config) ProfileNames() []string {
+	return slices.Sorted(maps.Keys(c.Profiles))
+}
+
+// Profile returns the named profile overrides.
+func (c *Config) Profile(name string) (*Config, error) {
+	p, ok := c.Profiles[name]
+	if !ok || p == nil {
+		if len(c.Profiles) == 0 {
+			return nil, fmt.Errorf("unknown profile %q (no profiles configured)", name)
+		}
+		return nil, fmt.Errorf("unknown profile %q (available: %s)", name, strings.Join(c.ProfileNames(), ", "))
+	}
+	return p, nil
+}
+
+// ApplyProfile overlays the non-zero fields of the named profile onto c.
+// An empty name is a no-op.
+func (c *Config) ApplyProfile(name string) error {
+	if name == "" {
+		return nil
+	}
+	p, err := c.Profile(name)
+	if err != nil {
+		return err
+	}
+
+	if p.MCPPort != 0 {
+		c.MCPPort = p.MCPPort
+	}
+	if p.ProxyPort != 0 {
+		c.ProxyPort = p.ProxyPort
+	}
+	if p.BurpMCPURL != "" {
+		c.BurpMCPURL = p.BurpMCPURL
+	}
+	if p.BurpRESTURL != "" {
+		c.BurpRESTURL = p.BurpRESTURL
+	}
+	if p.BurpRequired != nil {
+		c.BurpRequired = p.BurpRequired
+	}
+	if p.UpdateCheck != nil {
+		c.UpdateCheck = p.UpdateCheck
+	}
+	if p.MaxOutputBytes != 0 {
+		c.MaxOutputBytes = p.MaxOutputBytes
+	}
+	if p.TargetAllowlist != nil {
+		c.TargetAllowlist = p.TargetAllowlist
+	}
+	if p.Scope != nil {
+		c.Scope = p.Scope
+	}
+	if len(p.Hosts) > 0 { // merged per host
+		hosts := maps.Clone(c.Hosts)
+		if hosts == nil {
+			hosts = make(map[string]string, len(p.Hosts))
+		}
+		maps.Copy(hosts, p.Hosts)
+		c.Hosts = hosts
+	}
+	if len(p.GuideVars) > 0 { // merged per variable
+		vars := maps.Clone(c.GuideVars)
+		if vars == nil {
+			vars = make(map[string]string, len(p.GuideVars))
+		}
+		maps.Copy(vars, p.GuideVars)
+		c.GuideVars = vars
+	}
+	if len(p.AuthProfiles) > 0 { // merged per auth profile
+		auth := maps.Clone(c.AuthProfiles)
+		if auth == nil {
+			auth = make(map[string]*AuthProfile, len(p.AuthProfiles))
+		}
+		maps.Copy(auth, p.AuthProfiles)
+		c.AuthProfiles = auth
+	}
+	if p.OAST.ServerURLs != nil {
+		c.OAST.ServerURLs = p.OAST.ServerURLs
+	}
+	if p.OAST.Token != "" {
+		c.OAST.Token = p.OAST.Token
+	}
+	if p.OAST.TokenEnv != "" {
+		c.OAST.TokenEnv = p.OAST.TokenEnv
+	}
+	if p.OAST.NotifyURL != "" {
+		c.OAST.NotifyURL = p.OAST.NotifyURL
+	}
+	if p.OAST.NotifyFile != "" {
+		c.OAST.NotifyFile = p.OAST.NotifyFile
+	}
+
+	pr := p.RateLimit
+	if pr.RequestsPerSecond != 0 {
+		c.RateLimit.RequestsPerSecond = pr.RequestsPerSecond
+	}
+	if pr.HostRequestsPerSecond != 0 {
+		c.RateLimit.HostRequestsPerSecond = pr.HostRequestsPerSecond
+	}
+	if pr.MaxConcurrent != 0 {
+		c.RateLimit.MaxConcurrent = pr.MaxConcurrent
+	}
+	if pr.HostMaxConcurrent != 0 {
+		c.RateLimit.HostMaxConcurrent = pr.HostMaxConcurrent
+	}
+	if pr.JitterMS != 0 {
+		c.RateLimit.JitterMS = pr.JitterMS
+	}
+
+	pc := p.Crawler
+	if pc.MaxResponseBodyBytes != 0 {
+		c.Crawler.MaxResponseBodyBytes = pc.MaxResponseBodyBytes
+	}
+	if pc.IncludeSubdomains != nil {
+		c.Crawler.IncludeSubdomains = pc.IncludeSubdomains
+	}
+	if pc.DisallowedPaths != nil {
+		c.Crawler.DisallowedPaths = pc.DisallowedPaths
+	}
+	if pc.DelayMS != 0 {
+		c.Crawler.DelayMS = pc.DelayMS
+	}
+	if pc.Parallelism != 0 {
+		c.Crawler.Parallelism = pc.Parallelism
+	}
+	if pc.MaxDepth != 0 {
+		c.Crawler.MaxDepth = pc.MaxDepth
+	}
+	if pc.MaxRequests != 0 {
+		c.Crawler.MaxRequests = pc.MaxRequests
+	}
+	if pc.ExtractForms != nil {
+		c.Crawler.ExtractForms = pc.ExtractForms
+	}
+	if pc.SubmitForms != nil {
+		c.Crawler.SubmitForms = pc.SubmitForms
+	}
+	if pc.Recon != nil {
+		c.Crawler.Recon = pc.Recon
+	}
+	return nil
+}
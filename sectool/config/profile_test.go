@@ -0,0 +1,96 @@
+package config
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyProfile(t *testing.T) {
+	t.Parallel()
+
+	newConfig := func() *Config {
+		cfg := DefaultConfig()
+		required, noUpdateCheck := true, false
+		cfg.Profiles = map[string]*Config{
+			"staging": {
+				MCPPort:         9120,
+				BurpMCPURL:      "http://10.0.0.5:9876/sse",
+				BurpRequired:    &required,
+				UpdateCheck:     &noUpdateCheck,
+				Crawler:         CrawlerConfig{MaxDepth: 3},
+				RateLimit:       RateLimitConfig{HostRequestsPerSecond: 2},
+				TargetAllowlist: []string{"*.staging.example.com"},
+				Scope:           &Scope{Include: []string{"*.staging.example.com"}, Exclude: []string{"admin.staging.example.com"}},
+				Hosts:           map[string]string{"app.example.com": "10.0.0.7"},
+				OAST:            OastConfig{ServerURLs: []string{"oast.staging.example.com"}, NotifyURL: "http://127.0.0.1:9000/hook", NotifyFile: "staging-oast.jsonl"},
+				GuideVars:       map[string]string{"target": "https://staging.example.com"},
+			},
+			"prod": {ProxyPort: 8181},
+		}
+		return cfg
+	}
+
+	t.Run("empty_name_noop", func(t *testing.T) {
+		cfg := newConfig()
+		require.NoError(t, cfg.ApplyProfile(""))
+		assert.Equal(t, DefaultMCPPort, cfg.MCPPort)
+		assert.Nil(t, cfg.Scope)
+	})
+
+	t.Run("overlays_set_fields", func(t *testing.T) {
+		cfg := newConfig()
+		cfg.GuideVars = map[string]string{"target": "https://example.com", "report_path": "report.md"}
+		cfg.RateLimit = RateLimitConfig{HostRequestsPerSecond: 10, JitterMS: 50}
+		cfg.Hosts = map[string]string{"app.example.com": "192.0.2.1", "api.example.com": "192.0.2.2"}
+		require.NoError(t, cfg.ApplyProfile("staging"))
+		assert.Equal(t, &Scope{Include: []string{"*.staging.example.com"}, Exclude: []string{"admin.staging.example.com"}}, cfg.Scope)
+		assert.Equal(t, map[string]string{"app.example.com": "10.0.0.7", "api.example.com": "192.0.2.2"}, cfg.Hosts)
+		assert.Equal(t, RateLimitConfig{HostRequestsPerSecond: 2, JitterMS: 50}, cfg.RateLimit)
+		assert.Equal(t, []string{"*.staging.example.com"}, cfg.TargetAllowlist)
+		assert.Equal(t, map[string]string{"target": "https://staging.example.com", "report_path": "report.md"}, cfg.GuideVars)
+		assert.Equal(t, 9120, cfg.MCPPort)
+		assert.Equal(t, DefaultProxyPort, cfg.ProxyPort)
+		assert.Equal(t, "http://10.0.0.5:9876/sse", cfg.BurpMCPURL)
+		assert.True(t, *cfg.BurpRequired)
+		assert.False(t, *cfg.UpdateCheck)
+		assert.Equal(t, 3, cfg.Crawler.MaxDepth)
+		assert.Equal(t, 1000, cfg.Crawler.MaxRequests)
+		assert.Equal(t, []string{"oast.staging.example.com"}, cfg.OAST.ServerURLs)
+		assert.Equal(t, "http://127.0.0.1:9000/hook", cfg.OAST.NotifyURL)
+		assert.Equal(t, "staging-oast.jsonl", cfg.OAST.NotifyFile)
+	})
+
+	t.Run("unknown_profile", func(t *testing.T) {
+		err := newConfig().ApplyProfile("dev")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "available: prod, staging")
+	})
+
+	t.Run("no_profiles", func(t *testing.T) {
+		err := DefaultConfig().ApplyProfile("dev")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "no profiles configured")
+	})
+}
+
+func TestProfileRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "config.json")
+	cfg := DefaultConfig()
+	cfg.Profiles = map[string]*Config{"staging": {}}
+	require.NoError(t, cfg.Profiles["staging"].Set("mcp_port", "9120"))
+	require.NoError(t, cfg.Profiles["staging"].Set("scope.include", "*.staging.example.com"))
+	require.NoError(t, cfg.Profiles["staging"].Set("hosts.app.example.com", "10.0.0.7"))
+	require.NoError(t, cfg.Save(path))
+
+	loaded, err := Load(path)
+	require.NoError(t, err)
+	require.NoError(t, loaded.ApplyProfile("staging"))
+	assert.Equal(t, 9120, loaded.MCPPort)
+	assert.Equal(t, &Scope{Include: []string{"*.staging.example.com"}}, loaded.Scope)
+	assert.Equal(t, map[string]string{"app.example.com": "10.0.0.7"}, loaded.Hosts)
+}
@@ -0,0 +1,97 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+const (
+	// ProjectDirName is the per-project directory, relative to where sectool runs.
+	ProjectDirName = ".sectool"
+	// ScopeFileName is the project scope file within ProjectDirName.
+	ScopeFileName = "scope.json"
+)
+
+// ProjectScopePath returns the scope file path for the project in dir.
+func ProjectScopePath(dir string) string {
+	return filepath.Join(dir, ProjectDirName, ScopeFileName)
+}
+
+// Scope is an engagement's boundaries. Patterns are "host" or "host/path" globs
+// (e.g. "*.example.com", "api.example.com/v2/*"); a target is in scope when it matches
+// an Include pattern (or Include is empty) and no Exclude pattern.
+type Scope struct {
+	Targets []string `json:"targets,omitempty"` // engagement base URLs, for reference
+	Include []string `json:"include,omitempty"`
+	Exclude []string `json:"exclude,omitempty"`
+}
+
+// NewScope builds a Scope from target URLs and extra include/exclude patterns. Each
+// target contributes an include pattern for its host and path prefix.
+func NewScope(targets, include, exclude []string) (*Scope, error) {
+	s := &Scope{Targets: targets, Exclude: exclude}
+	for _, target := range targets {
+		u, err := url.Parse(target)
+		if err != nil || u.Hostname() == "" {
+			return nil, fmt.Errorf("invalid target %q: must be a URL like https://app.example.com", target)
+		}
+		pattern := u.Hostname()
+		if p := strings.TrimSuffix(u.Path, "/"); p != "" {
+			pattern += p + "*"
+		}
+		s.Include = append(s.Include, pattern)
+	}
+	for _, pattern := range slices.Concat(include, exclude) {
+		if err := ValidateScopePattern(pattern); err != nil {
+			return nil, err
+		}
+	}
+	s.Include = slices.Compact(append(s.Include, include...))
+	return s, nil
+}
+
+// ValidateScopePattern checks that pattern is a "host" or "host/path" glob.
+func ValidateScopePattern(pattern string) error {
+	if pattern == "" || strings.HasPrefix(pattern, "/") || strings.Contains(pattern, "://") {
+		return fmt.Errorf("invalid scope pattern %q: use host or host/path globs", pattern)
+	}
+	return nil
+}
+
+// LoadScope reads a scope file. Returns os.ErrNotExist if the file is missing.
+func LoadScope(path string) (*Scope, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var s Scope
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	return &s, nil
+}
+
+// Save writes the scope file, creating the parent directory if needed.
+func (s *Scope) Save(path string) error {
+	if s == nil {
+		return errors.New("scope is nil")
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("create scope directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, data, 0644)
+}
@@ -0,0 +1,54 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewScope(t *testing.T) {
+	t.Parallel()
+
+	t.Run("targets_become_includes", func(t *testing.T) {
+		s, err := NewScope(
+			[]string{"https://app.example.com", "https://api.example.com:8443/v2/"},
+			[]string{"*.cdn.example.com"},
+			[]string{"admin.example.com"},
+		)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"app.example.com", "api.example.com/v2*", "*.cdn.example.com"}, s.Include)
+		assert.Equal(t, []string{"admin.example.com"}, s.Exclude)
+	})
+
+	t.Run("invalid_target", func(t *testing.T) {
+		_, err := NewScope([]string{"not a url"}, nil, nil)
+		assert.Error(t, err)
+	})
+
+	t.Run("invalid_pattern", func(t *testing.T) {
+		_, err := NewScope(nil, []string{"https://example.com"}, nil)
+		assert.Error(t, err)
+		_, err = NewScope(nil, nil, []string{"/admin/*"})
+		assert.Error(t, err)
+	})
+}
+
+func TestScopeSaveLoad(t *testing.T) {
+	t.Parallel()
+
+	path := ProjectScopePath(t.TempDir())
+	_, err := LoadScope(path)
+	require.ErrorIs(t, err, os.ErrNotExist)
+
+	s, err := NewScope([]string{"https://app.example.com"}, nil, nil)
+	require.NoError(t, err)
+	require.NoError(t, s.Save(path))
+	assert.Equal(t, ScopeFileName, filepath.Base(path))
+
+	loaded, err := LoadScope(path)
+	require.NoError(t, err)
+	assert.Equal(t, s, loaded)
+}
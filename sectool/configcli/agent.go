@@ -0,0 +1,139 @@
+package configcli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-harden/llm-security-toolbox/sectool/cliutil"
+)
+
+// mcpServerName is the name sectool is registered under in agent client configs.
+const mcpServerName = "sectool"
+
+var agentClients = []string{"claude", "codex"}
+
+// agent registers the sectool MCP endpoint (from the configured mcp_port) with an
+// agent client, replacing an existing sectool entry and keeping everything else.
+func agent(configPath, profile, client string) error {
+	cfg, err := load(configPath, profile)
+	if err != nil {
+		return err
+	}
+	mcpURL := fmt.Sprintf("http://127.0.0.1:%d/mcp", cfg.MCPPort)
+
+	var path string
+	var patch func([]byte) ([]byte, error)
+	switch client {
+	case "claude":
+		wd, err := os.Getwd()
+		if err != nil {
+			return err
+		}
+		path = filepath.Join(wd, ".mcp.json")
+		patch = func(data []byte) ([]byte, error) { return patchClaudeMCPConfig(data, mcpURL) }
+	case "codex":
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return err
+		}
+		path = filepath.Join(home, ".codex", "config.toml")
+		patch = func(data []byte) ([]byte, error) { return patchCodexConfig(data, mcpURL), nil }
+	default:
+		return fmt.Errorf("unknown agent client %q (available: %s)", client, strings.Join(agentClients, ", "))
+	}
+	if err := patchFile(path, patch); err != nil {
+		return err
+	}
+
+	fmt.Printf("Registered `%s` at %s in %s\n", mcpServerName, mcpURL, path)
+	cliutil.Hintf("Start the server with `sectool mcp`%s, then restart the agent to pick up the change.\n", profileHint(profile))
+	return nil
+}
+
+func profileHint(profile string) string {
+	if profile == "" {
+		return ""
+	}
+	return " (with `--profile " + profile + "`)"
+}
+
+// patchFile rewrites path with patch applied to its current content (empty if missing).
+func patchFile(path string, patch func([]byte) ([]byte, error)) error {
+	data, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	updated, err := patch(data)
+	if err != nil {
+		return fmt.Errorf("update %s: %w", path, err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, updated, 0644)
+}
+
+// patchClaudeMCPConfig sets mcpServers.sectool in a Claude Code .mcp.json document.
+func patchClaudeMCPConfig(data []byte, mcpURL string) ([]byte, error) {
+	doc := map[string]json.RawMessage{}
+	if len(strings.TrimSpace(string(data))) > 0 {
+		if err := json.Unmarshal(data, &doc); err != nil {
+			return nil, err
+		}
+	}
+	servers := map[string]json.RawMessage{}
+	if raw, ok := doc["mcpServers"]; ok {
+		if err := json.Unmarshal(raw, &servers); err != nil {
+			return nil, fmt.Errorf("mcpServers: %w", err)
+		}
+	}
+
+	entry, _ := json.Marshal(map[string]string{"type": "http", "url": mcpURL})
+	servers[mcpServerName] = entry
+	doc["mcpServers"], _ = json.Marshal(servers)
+
+	out, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(out, '\n'), nil
+}
+
+// patchCodexConfig sets the [mcp_servers.sectool] table in a Codex config.toml,
+// replacing the existing table (up to the next table header) if present.
+func patchCodexConfig(data []byte, mcpURL string) []byte {
+	header := "[mcp_servers." + mcpServerName + "]"
+	table := header + "\nurl = \"" + mcpURL + "\"\n"
+
+	lines := strings.SplitAfter(string(data), "\n")
+	start, end := -1, len(lines)
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if start < 0 && trimmed == header {
+			start = i
+		} else if start >= 0 && strings.HasPrefix(trimmed, "[") {
+			end = i
+			break
+		}
+	}
+	if start < 0 {
+		content := string(data)
+		if content != "" && !strings.HasSuffix(content, "\n") {
+			content += "\n"
+		}
+		if content != "" {
+			content += "\n"
+		}
+		return []byte(content + table)
+	}
+
+	rest := strings.Join(lines[end:], "")
+	if rest != "" {
+		table += "\n"
+	}
+	return []byte(strings.Join(lines[:start], "") + table + rest)
+}
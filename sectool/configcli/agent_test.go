@@ -0,0 +1,59 @@
+package configcli
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPatchClaudeMCPConfig(t *testing.T) {
+	t.Parallel()
+
+	t.Run("empty", func(t *testing.T) {
+		out, err := patchClaudeMCPConfig(nil, "http://127.0.0.1:9119/mcp")
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"mcpServers":{"sectool":{"type":"http","url":"http://127.0.0.1:9119/mcp"}}}`, string(out))
+	})
+
+	t.Run("keeps_other_servers", func(t *testing.T) {
+		in := `{"mcpServers":{"other":{"command":"x"},"sectool":{"type":"http","url":"http://127.0.0.1:1/mcp"}},"extra":true}`
+		out, err := patchClaudeMCPConfig([]byte(in), "http://127.0.0.1:9120/mcp")
+		require.NoError(t, err)
+
+		var doc map[string]interface{}
+		require.NoError(t, json.Unmarshal(out, &doc))
+		assert.Equal(t, true, doc["extra"])
+		servers := doc["mcpServers"].(map[string]interface{})
+		assert.Contains(t, servers, "other")
+		assert.Equal(t, "http://127.0.0.1:9120/mcp", servers["sectool"].(map[string]interface{})["url"])
+	})
+
+	t.Run("invalid_json", func(t *testing.T) {
+		_, err := patchClaudeMCPConfig([]byte("{"), "http://127.0.0.1:9119/mcp")
+		assert.Error(t, err)
+	})
+}
+
+func TestPatchCodexConfig(t *testing.T) {
+	t.Parallel()
+
+	const url = "http://127.0.0.1:9119/mcp"
+
+	t.Run("empty", func(t *testing.T) {
+		assert.Equal(t, "[mcp_servers.sectool]\nurl = \""+url+"\"\n", string(patchCodexConfig(nil, url)))
+	})
+
+	t.Run("append", func(t *testing.T) {
+		in := "model = \"o3\"\n\n[mcp_servers.other]\ncommand = \"x\""
+		want := in + "\n\n[mcp_servers.sectool]\nurl = \"" + url + "\"\n"
+		assert.Equal(t, want, string(patchCodexConfig([]byte(in), url)))
+	})
+
+	t.Run("replace", func(t *testing.T) {
+		in := "model = \"o3\"\n[mcp_servers.sectool]\nurl = \"http://127.0.0.1:1/mcp\"\n\n[mcp_servers.other]\ncommand = \"x\"\n"
+		want := "model = \"o3\"\n[mcp_servers.sectool]\nurl = \"" + url + "\"\n\n[mcp_servers.other]\ncommand = \"x\"\n"
+		assert.Equal(t, want, string(patchCodexConfig([]byte(in), url)))
+	})
+}
@@ -0,0 +1,192 @@
+package configcli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/go-harden/llm-security-toolbox/sectool/cliutil"
+	"github.com/go-harden/llm-security-toolbox/sectool/config"
+)
+
+var keyColumns = []string{"key", "value", "description"}
+
+func resolvePath(configPath string) string {
+	if configPath == "" {
+		return config.DefaultPath()
+	}
+	return configPath
+}
+
+// load reads the config with the selected profile applied.
+func load(configPath, profile string) (*config.Config, error) {
+	cfg, err := config.LoadOrDefaultConfig(resolvePath(configPath))
+	if err != nil {
+		return nil, err
+	} else if err := cfg.ApplyProfile(profile); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+func list(configPath, profile string, format cliutil.Format) error {
+	cfg, err := load(configPath, profile)
+	if err != nil {
+		return err
+	}
+
+	if profile != "" && format == cliutil.FormatMarkdown {
+		fmt.Printf("Profile: `%s`\n\n", profile)
+	}
+	t := cliutil.NewTable(os.Stdout, format, keyColumns, keyColumns)
+	t.Header()
+	for _, k := range config.Keys() {
+		v, _ := cfg.Get(k.Name)
+		t.Row(k.Name, v, k.Description)
+	}
+	for _, name := range cfg.GuideVarNames() {
+		t.Row(config.GuideVarPrefix+name, cfg.GuideVars[name], "guide placeholder {{"+name+"}}")
+	}
+	for _, name := range cfg.HostNames() {
+		t.Row(config.HostPrefix+name, cfg.Hosts[name], "address connections to "+name+" go to")
+	}
+	t.Flush()
+
+	return nil
+}
+
+func get(configPath, profile, key string) error {
+	cfg, err := load(configPath, profile)
+	if err != nil {
+		return err
+	}
+
+	v, err := cfg.Get(key)
+	if err != nil {
+		return err
+	}
+	fmt.Println(v)
+
+	return nil
+}
+
+func set(configPath, profile, key, value string) error {
+	path := resolvePath(configPath)
+	cfg, err := config.LoadOrCreatePath(path)
+	if err != nil {
+		return err
+	}
+
+	target := cfg
+	if profile != "" {
+		if cfg.Profiles == nil {
+			cfg.Profiles = make(map[string]*config.Config)
+		}
+		if cfg.Profiles[profile] == nil {
+			cfg.Profiles[profile] = &config.Config{}
+		}
+		target = cfg.Profiles[profile]
+	}
+
+	if err := target.Set(key, value); err != nil {
+		return err
+	}
+	if err := cfg.Save(path); err != nil {
+		return fmt.Errorf("save config: %w", err)
+	}
+
+	v, _ := target.Get(key)
+	if profile != "" {
+		fmt.Printf("Set `%s` = `%s` in profile `%s` (%s)\n", key, v, profile, path)
+	} else {
+		fmt.Printf("Set `%s` = `%s` in %s\n", key, v, path)
+	}
+	cliutil.Hintf("Restart `sectool mcp` to apply.\n")
+
+	return nil
+}
+
+func profiles(configPath string) error {
+	cfg, err := config.LoadOrDefaultConfig(resolvePath(configPath))
+	if err != nil {
+		return err
+	}
+
+	names := cfg.ProfileNames()
+	if len(names) == 0 {
+		fmt.Println("No profiles configured.")
+		cliutil.Hintf("\nTo create one: `sectool --profile <name> config set <key> <value>`\n")
+		return nil
+	}
+
+	fmt.Println("| profile | overrides |")
+	fmt.Println("|---------|-----------|")
+	for _, name := range names {
+		overrides, _ := json.Marshal(cfg.Profiles[name])
+		fmt.Printf("| %s | `%s` |\n", cliutil.EscapeMarkdown(name), cliutil.EscapeMarkdown(string(overrides)))
+	}
+	fmt.Printf("\n*%d profile(s)*\n", len(names))
+
+	return nil
+}
+
+func scopePath() (string, error) {
+	wd, err := os.Getwd()
+	if err != nil {
+		return "", err
+	}
+	return config.ProjectScopePath(wd), nil
+}
+
+func showScope() error {
+	path, err := scopePath()
+	if err != nil {
+		return err
+	}
+	scope, err := config.LoadScope(path)
+	if errors.Is(err, os.ErrNotExist) {
+		fmt.Println("No project scope defined.")
+		cliutil.Hintf("\nTo create one: `sectool config scope --target <url>`\n")
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	printScope(scope, path)
+	return nil
+}
+
+func writeScope(targets, include, exclude []string) error {
+	path, err := scopePath()
+	if err != nil {
+		return err
+	}
+	scope, err := config.NewScope(targets, include, exclude)
+	if err != nil {
+		return err
+	} else if len(scope.Include) == 0 {
+		return errors.New("scope requires at least one --target or --scope")
+	}
+	if err := scope.Save(path); err != nil {
+		return fmt.Errorf("save scope: %w", err)
+	}
+
+	printScope(scope, path)
+	cliutil.Hintf("Restart `sectool mcp` from this directory to apply.\n")
+	return nil
+}
+
+func printScope(scope *config.Scope, path string) {
+	fmt.Printf("Scope (%s)\n\n", path)
+	for _, target := range scope.Targets {
+		fmt.Printf("- target: `%s`\n", cliutil.EscapeMarkdown(target))
+	}
+	for _, pattern := range scope.Include {
+		fmt.Printf("- include: `%s`\n", cliutil.EscapeMarkdown(pattern))
+	}
+	for _, pattern := range scope.Exclude {
+		fmt.Printf("- exclude: `%s`\n", cliutil.EscapeMarkdown(pattern))
+	}
+	fmt.Println()
+}
@@ -0,0 +1,264 @@
+package configcli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/spf13/pflag"
+
+	"github.com/go-harden/llm-security-toolbox/sectool/cli"
+	"github.com/go-harden/llm-security-toolbox/sectool/cliutil"
+)
+
+var configSubcommands = []string{"list", "get", "set", "profiles", "scope", "agent", "path", "help"}
+
+// Parse handles `sectool config`; configPath is empty for the default path and
+// profile is empty when no --profile is selected.
+func Parse(args []string, configPath, profile string) error {
+	if len(args) < 1 {
+		printUsage()
+		return errors.New("subcommand required")
+	}
+
+	switch args[0] {
+	case "list":
+		return parseList(args[1:], configPath, profile)
+	case "get":
+		return parseGet(args[1:], configPath, profile)
+	case "set":
+		return parseSet(args[1:], configPath, profile)
+	case "profiles":
+		return profiles(configPath)
+	case "scope":
+		return parseScope(args[1:])
+	case "agent":
+		return parseAgent(args[1:], configPath, profile)
+	case "path":
+		fmt.Println(resolvePath(configPath))
+		return nil
+	case "help", "--help", "-h":
+		printUsage()
+		return nil
+	default:
+		return cli.UnknownSubcommandError("config", args[0], configSubcommands)
+	}
+}
+
+func printUsage() {
+	_, _ = fmt.Fprint(os.Stderr, `Usage: sectool config <command> [options]
+
+View and edit sectool configuration (~/.sectool/config.json by default).
+Runs locally, no service required. Restart 'sectool mcp' to apply changes.
+
+With the global --profile <name> flag (or $SECTOOL_PROFILE), list and get
+show values with that profile applied, and set writes to the profile instead
+of the base config. Profiles override only the keys they set.
+
+---
+
+config list [options]
+
+  Show all settable keys with current values.
+
+  Options:
+    --format <fmt>     output format: markdown, plain, csv, tsv
+
+---
+
+config get <key>
+
+  Print the current value of a key.
+
+  Example:
+    sectool config get crawler.max_depth
+
+---
+
+config set <key> <value>
+
+  Validate and save a new value for a key.
+
+  Examples:
+    sectool config set burp_mcp_url http://127.0.0.1:9876/sse
+    sectool config set crawler.disallowed_paths "*logout*,*delete*"
+    sectool config set burp_required true
+    sectool --profile staging config set mcp_port 9120   # creates profile
+
+---
+
+config profiles
+
+  List configured profiles and the keys each overrides.
+
+---
+
+config scope [options]
+
+  Show or write the project scope (.sectool/scope.json in the current
+  directory). The scope establishes engagement boundaries for the project;
+  proxy_poll in_scope filters by it. Run 'sectool mcp' from the same directory.
+
+  Options:
+    --target <url>     engagement base URL; its host and path become in scope (repeatable)
+    --scope <glob>     additional in-scope host or host/path glob (repeatable)
+    --exclude <glob>   out-of-scope host or host/path glob (repeatable)
+
+  Examples:
+    sectool config scope --target https://app.example.com --scope '*.api.example.com'
+    sectool config scope --target https://example.com --exclude 'admin.example.com'
+    sectool config scope                 # show current scope
+
+---
+
+config agent <client>
+
+  Register the sectool MCP endpoint with an agent client, using the
+  configured mcp_port (and --profile). An existing sectool entry is
+  replaced; other settings are kept.
+
+  Clients:
+    claude             project .mcp.json in the current directory
+    codex              ~/.codex/config.toml
+
+  Examples:
+    sectool config agent claude
+    sectool --profile staging config agent codex
+
+---
+
+config path
+
+  Print the config file path in use.
+`)
+}
+
+func parseList(args []string, configPath, profile string) error {
+	fs := pflag.NewFlagSet("config list", pflag.ContinueOnError)
+	fs.SetInterspersed(true)
+	var format string
+
+	fs.StringVar(&format, "format", "markdown", "output format: markdown, plain, csv, tsv")
+
+	fs.Usage = func() {
+		_, _ = fmt.Fprint(os.Stderr, `Usage: sectool config list [options]
+
+Show all settable config keys with their current values.
+
+Options:
+`)
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	outFormat, err := cliutil.ParseFormat(format)
+	if err != nil {
+		return err
+	}
+
+	return list(configPath, profile, outFormat)
+}
+
+func parseGet(args []string, configPath, profile string) error {
+	fs := pflag.NewFlagSet("config get", pflag.ContinueOnError)
+	fs.SetInterspersed(true)
+
+	fs.Usage = func() {
+		_, _ = fmt.Fprint(os.Stderr, `Usage: sectool config get <key>
+
+Print the current value of a config key. Use 'sectool config list' for keys.
+`)
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if len(fs.Args()) < 1 {
+		fs.Usage()
+		return errors.New("key required")
+	}
+
+	return get(configPath, profile, fs.Args()[0])
+}
+
+func parseSet(args []string, configPath, profile string) error {
+	fs := pflag.NewFlagSet("config set", pflag.ContinueOnError)
+	fs.SetInterspersed(true)
+
+	fs.Usage = func() {
+		_, _ = fmt.Fprint(os.Stderr, `Usage: sectool config set <key> <value>
+
+Validate and save a config value. Use 'sectool config list' for keys.
+Restart 'sectool mcp' to apply changes.
+`)
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if len(fs.Args()) < 2 {
+		fs.Usage()
+		return errors.New("key and value required")
+	}
+
+	return set(configPath, profile, fs.Args()[0], fs.Args()[1])
+}
+
+func parseScope(args []string) error {
+	fs := pflag.NewFlagSet("config scope", pflag.ContinueOnError)
+	fs.SetInterspersed(true)
+	var targets, include, exclude []string
+
+	fs.StringArrayVar(&targets, "target", nil, "engagement base URL (repeatable)")
+	fs.StringArrayVar(&include, "scope", nil, "in-scope host or host/path glob (repeatable)")
+	fs.StringArrayVar(&exclude, "exclude", nil, "out-of-scope host or host/path glob (repeatable)")
+
+	fs.Usage = func() {
+		_, _ = fmt.Fprint(os.Stderr, `Usage: sectool config scope [options]
+
+Show the project scope, or replace it when any option is given.
+The scope is stored in .sectool/scope.json in the current directory.
+
+Options:
+`)
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if len(targets) == 0 && len(include) == 0 && len(exclude) == 0 {
+		return showScope()
+	}
+	return writeScope(targets, include, exclude)
+}
+
+func parseAgent(args []string, configPath, profile string) error {
+	fs := pflag.NewFlagSet("config agent", pflag.ContinueOnError)
+	fs.SetInterspersed(true)
+
+	fs.Usage = func() {
+		_, _ = fmt.Fprint(os.Stderr, `Usage: sectool config agent <client>
+
+Register the sectool MCP endpoint with an agent client.
+Clients: claude (./.mcp.json), codex (~/.codex/config.toml)
+`)
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if len(fs.Args()) < 1 {
+		fs.Usage()
+		return errors.New("client required")
+	}
+
+	return agent(configPath, profile, fs.Args()[0])
+}
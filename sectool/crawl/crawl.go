@@ -66,9 +66,9 @@ func create(mcpURL string, timeout time.Duration, urls, flows, domains []string,
 	if resp.Label != "" {
 		statusRef = resp.Label
 	}
-	fmt.Printf("To check status: `sectool crawl status %s`\n", statusRef)
-	fmt.Printf("To view results: `sectool crawl list %s`\n", statusRef)
-	fmt.Printf("To stop: `sectool crawl stop %s`\n", statusRef)
+	cliutil.Hintf("To check status: `sectool crawl status %s`\n", statusRef)
+	cliutil.Hintf("To view results: `sectool crawl list %s`\n", statusRef)
+	cliutil.Hintf("To stop: `sectool crawl stop %s`\n", statusRef)
 
 	return nil
 }
@@ -141,6 +141,8 @@ func summary(mcpURL string, timeout time.Duration, sessionID string) error {
 		return fmt.Errorf("crawl summary failed: %w", err)
 	}
 
+	defer cliutil.StartPager()()
+
 	fmt.Println("## Crawl Summary")
 	fmt.Println()
 	fmt.Printf("Session: `%s` | State: **%s** | Duration: %s\n", resp.SessionID, resp.State, resp.Duration)
@@ -199,6 +201,8 @@ func list(mcpURL string, timeout time.Duration, sessionID, listType, host, path,
 		return fmt.Errorf("crawl list failed: %w", err)
 	}
 
+	defer cliutil.StartPager()()
+
 	switch outputMode {
 	case "forms":
 		if len(resp.Forms) == 0 {
@@ -261,13 +265,13 @@ func list(mcpURL string, timeout time.Duration, sessionID, listType, host, path,
 		}
 		fmt.Printf("\n*%d flow(s)*\n", len(resp.Flows))
 		if len(resp.Flows) == limit && limit > 0 {
-			fmt.Printf("\nMore results may be available. Use `--offset %d` to paginate.\n", offset+limit)
+			cliutil.Hintf("\nMore results may be available. Use `--offset %d` to paginate.\n", offset+limit)
 		}
 		if len(resp.Flows) > 0 {
 			lastFlow := resp.Flows[len(resp.Flows)-1]
-			fmt.Printf("\nTo list flows after this: `sectool crawl list %s --since %s`\n", sessionID, lastFlow.FlowID)
+			cliutil.Hintf("\nTo list flows after this: `sectool crawl list %s --since %s`\n", sessionID, lastFlow.FlowID)
 		}
-		fmt.Printf("To export for editing/replay: `sectool crawl export <flow_id>`\n")
+		cliutil.Hintf("To export for editing/replay: `sectool crawl export <flow_id>`\n")
 	}
 
 	return nil
@@ -288,9 +292,11 @@ func sessions(mcpURL string, timeout time.Duration, limit int) error {
 		return fmt.Errorf("crawl sessions failed: %w", err)
 	}
 
+	defer cliutil.StartPager()()
+
 	if len(resp.Sessions) == 0 {
 		fmt.Println("No crawl sessions.")
-		fmt.Println("\nTo create one: `sectool crawl create --url <url>`")
+		cliutil.Hintf("\nTo create one: `sectool crawl create --url <url>`\n")
 		return nil
 	}
 
@@ -319,6 +325,282 @@ func sessions(mcpURL string, timeout time.Duration, limit int) error {
 	return nil
 }
 
+func results(mcpURL string, timeout time.Duration, opts mcpclient.CrawlResultsOpts) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	client, err := mcpclient.Connect(ctx, mcpURL)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = client.Close() }()
+
+	resp, err := client.CrawlResults(ctx, opts)
+	if err != nil {
+		return fmt.Errorf("crawl results failed: %w", err)
+	}
+
+	defer cliutil.StartPager()()
+
+	if len(resp.Endpoints) == 0 && len(resp.Services) == 0 {
+		fmt.Println("No endpoints discovered.")
+		cliutil.Hintf("\nTo start a crawl: `sectool crawl create --url <url>`\n")
+		return nil
+	}
+
+	if len(resp.Endpoints) > 0 {
+		fmt.Println("| method | host | path | params | status | sources | count |")
+		fmt.Println("|--------|------|------|--------|--------|---------|-------|")
+		for _, e := range resp.Endpoints {
+			statuses := make([]string, 0, len(e.Status))
+			for _, code := range e.Status {
+				statuses = append(statuses, strconv.Itoa(code))
+			}
+			sources := strings.Join(e.Sources, ",")
+			if e.HasCSRF {
+				sources += " (csrf)"
+			}
+			fmt.Printf("| %s | %s | %s | %s | %s | %s | %d |\n",
+				e.Method, e.Host, cliutil.EscapeMarkdown(e.Path), cliutil.EscapeMarkdown(strings.Join(e.Params, ", ")),
+				strings.Join(statuses, ","), sources, e.Count)
+		}
+		fmt.Printf("\n*%d endpoint(s) from %d session(s)*\n", len(resp.Endpoints), resp.Sessions)
+	}
+	if len(resp.Services) > 0 {
+		if len(resp.Endpoints) > 0 {
+			fmt.Println()
+		}
+		printOpenPorts(resp.Services)
+	}
+	if resp.NextCursor != "" {
+		cliutil.Hintf("\nMore endpoints: `sectool crawl results --cursor %s`\n", resp.NextCursor)
+	}
+
+	return nil
+}
+
+// printOpenPorts prints port_scan results as a Markdown table.
+func printOpenPorts(ports []protocol.OpenPort) {
+	fmt.Println("| host | port | service | banner |")
+	fmt.Println("|------|------|---------|--------|")
+	for _, p := range ports {
+		service := p.Service
+		if p.TLS {
+			service += " (tls)"
+		}
+		fmt.Printf("| %s | %d | %s | %s |\n", p.Host, p.Port, service, cliutil.EscapeMarkdown(p.Banner))
+	}
+}
+
+func dirbust(mcpURL string, timeout time.Duration, opts mcpclient.DirbustOpts) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	client, err := mcpclient.Connect(ctx, mcpURL)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = client.Close() }()
+
+	resp, err := client.Dirbust(ctx, opts)
+	if err != nil {
+		return fmt.Errorf("crawl dirbust failed: %w", err)
+	}
+
+	fmt.Printf("Tested %d names in %d director(ies) under %s with %d requests\n\n", resp.Tested, resp.Directories, resp.URL, resp.Requests)
+	if len(resp.Hits) == 0 {
+		fmt.Println("No paths found.")
+	} else {
+		fmt.Println("| path | status | size | location | replay_id |")
+		fmt.Println("|------|--------|------|----------|-----------|")
+		for _, h := range resp.Hits {
+			path := h.Path
+			if h.Directory {
+				path += "/"
+			}
+			fmt.Printf("| %s | %d | %d | %s | %s |\n",
+				cliutil.EscapeMarkdown(path), h.Status, h.Size, cliutil.EscapeMarkdown(h.Location), h.ReplayID)
+		}
+	}
+	if resp.Skipped > 0 {
+		fmt.Printf("\n%d path(s) outside the project scope were skipped.\n", resp.Skipped)
+	}
+	if resp.Errors > 0 {
+		fmt.Printf("\n%d request(s) failed, last: %s\n", resp.Errors, resp.LastError)
+	}
+	if resp.Stopped != "" {
+		cliutil.Hintf("\nStopped at the request budget; raise --max-requests\n")
+	} else if len(resp.Hits) > 0 {
+		cliutil.Hintf("\nInspect with `sectool replay get <replay_id>`; all hits: `sectool crawl results`\n")
+	}
+	return nil
+}
+
+func vhosts(mcpURL string, timeout time.Duration, opts mcpclient.VhostDiscoverOpts) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	client, err := mcpclient.Connect(ctx, mcpURL)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = client.Close() }()
+
+	resp, err := client.VhostDiscover(ctx, opts)
+	if err != nil {
+		return fmt.Errorf("crawl vhosts failed: %w", err)
+	}
+
+	fmt.Printf("Tested %d hosts against %s with %d requests; a random host got %s\n\n", resp.Tested, resp.URL, resp.Requests, resp.Baseline)
+	if len(resp.Hits) == 0 {
+		fmt.Println("No virtual hosts found.")
+	} else {
+		fmt.Println("| host | status | size | title | replay_id |")
+		fmt.Println("|------|--------|------|-------|-----------|")
+		for _, h := range resp.Hits {
+			host := h.Host
+			if h.OutOfScope {
+				host += " (out of scope)"
+			}
+			title := h.Title
+			if title == "" && h.Location != "" {
+				title = "-> " + h.Location
+			}
+			fmt.Printf("| %s | %d | %d | %s | %s |\n", host, h.Status, h.Size, cliutil.EscapeMarkdown(title), h.ReplayID)
+		}
+	}
+	if resp.Errors > 0 {
+		fmt.Printf("\n%d request(s) failed, last: %s\n", resp.Errors, resp.LastError)
+	}
+	if resp.Stopped != "" {
+		cliutil.Hintf("\nStopped at the request budget; raise --max-requests\n")
+	} else if len(resp.Hits) > 0 {
+		cliutil.Hintf("\nInspect with `sectool replay get <replay_id>`; send more with `sectool replay send --replay <replay_id>`\n")
+	}
+	return nil
+}
+
+func subdomains(mcpURL string, timeout time.Duration, opts mcpclient.SubdomainEnumOpts) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	client, err := mcpclient.Connect(ctx, mcpURL)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = client.Close() }()
+
+	resp, err := client.SubdomainEnum(ctx, opts)
+	if err != nil {
+		return fmt.Errorf("crawl subdomains failed: %w", err)
+	}
+
+	if len(resp.Wildcard) > 0 {
+		fmt.Printf("Wildcard DNS: random names resolve to %s\n\n", strings.Join(resp.Wildcard, ", "))
+	}
+	if len(resp.Subdomains) == 0 {
+		fmt.Printf("No subdomains of %s found (%d names resolved).\n", resp.Domain, resp.Tested)
+	} else {
+		fmt.Println("| host | addresses | sources |")
+		fmt.Println("|------|-----------|---------|")
+		for _, s := range resp.Subdomains {
+			host := s.Host
+			if s.OutOfScope {
+				host += " (out of scope)"
+			}
+			fmt.Printf("| %s | %s | %s |\n", host, strings.Join(s.Addresses, ", "), strings.Join(s.Sources, ","))
+		}
+		fmt.Printf("\n*%d subdomain(s) of %s*\n", len(resp.Subdomains), resp.Domain)
+	}
+	if resp.CTError != "" {
+		fmt.Printf("\nCertificate transparency lookup failed: %s\n", resp.CTError)
+	}
+	if len(resp.Subdomains) > 0 {
+		cliutil.Hintf("\nProbe virtual hosts: `sectool crawl vhosts <url> --host <host>`\n")
+	}
+	return nil
+}
+
+func dnsQuery(mcpURL string, timeout time.Duration, opts mcpclient.DNSQueryOpts) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	client, err := mcpclient.Connect(ctx, mcpURL)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = client.Close() }()
+
+	resp, err := client.DNSQuery(ctx, opts)
+	if err != nil {
+		return fmt.Errorf("crawl dns failed: %w", err)
+	}
+
+	fmt.Printf("%s via %s: %s", resp.Name, resp.Server, resp.Status)
+	if resp.OutOfScope {
+		fmt.Print(" (out of scope)")
+	}
+	fmt.Printf("\n\n")
+	if len(resp.CNAMEChain) > 0 {
+		fmt.Printf("CNAME chain: %s -> %s\n\n", resp.Name, strings.Join(resp.CNAMEChain, " -> "))
+	}
+	if len(resp.Records) == 0 {
+		fmt.Println("No records.")
+	} else {
+		fmt.Println("| type | name | value | ttl |")
+		fmt.Println("|------|------|-------|-----|")
+		for _, r := range resp.Records {
+			value := r.Value
+			if r.Type == "MX" {
+				value = strconv.Itoa(r.Priority) + " " + value
+			}
+			fmt.Printf("| %s | %s | %s | %d |\n", r.Type, r.Name, cliutil.EscapeMarkdown(value), r.TTL)
+		}
+	}
+	if len(resp.Errors) > 0 {
+		fmt.Printf("\nFailed queries: %s\n", strings.Join(resp.Errors, "; "))
+	}
+	if t := resp.Takeover; t != nil {
+		fmt.Printf("\n**Possible subdomain takeover** (%s): %s\n", t.Target, t.Detail)
+		cliutil.Hintf("\nConfirm by requesting the host: `sectool request new --url https://%s/`\n", resp.Name)
+	}
+	return nil
+}
+
+func ports(mcpURL string, timeout time.Duration, opts mcpclient.PortScanOpts) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	client, err := mcpclient.Connect(ctx, mcpURL)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = client.Close() }()
+
+	resp, err := client.PortScan(ctx, opts)
+	if err != nil {
+		return fmt.Errorf("crawl ports failed: %w", err)
+	}
+
+	fmt.Printf("Checked %d port(s) on %s with %d connections in %s: %d open, %d closed, %d filtered\n\n",
+		resp.Ports, strings.Join(resp.Hosts, ", "), resp.Connections, resp.Duration, len(resp.Open), resp.Closed, resp.Filtered)
+	if len(resp.Open) == 0 {
+		fmt.Println("No open ports found.")
+	} else {
+		printOpenPorts(resp.Open)
+	}
+	if resp.Skipped > 0 {
+		fmt.Printf("\n%d host:port pair(s) skipped as out of scope\n", resp.Skipped)
+	}
+	if resp.Stopped {
+		fmt.Println("\nStopped before all ports were checked.")
+	}
+	if len(resp.Open) > 0 {
+		cliutil.Hintf("\nTLS details: `sectool request tls <host:port>`; other protocols: `sectool request raw <host:port>`\n")
+	}
+	return nil
+}
+
 func stop(mcpURL string, timeout time.Duration, sessionID string) error {
 	ctx, cancel := context.WithTimeout(context.Background(), timeout)
 	defer cancel()
@@ -338,7 +620,7 @@ func stop(mcpURL string, timeout time.Duration, sessionID string) error {
 	return nil
 }
 
-func export(mcpURL string, timeout time.Duration, flowID string) error {
+func export(mcpURL string, timeout time.Duration, flowID string, copyRaw bool) error {
 	ctx, cancel := context.WithTimeout(context.Background(), timeout)
 	defer cancel()
 
@@ -380,8 +662,15 @@ func export(mcpURL string, timeout time.Duration, flowID string) error {
 		fmt.Println("- response.http - response headers")
 		fmt.Println("- response.body - response body")
 	}
+	if copyRaw {
+		method, err := cliutil.CopyToClipboard(resp.ReqHeaders + string(reqBody))
+		if err != nil {
+			return fmt.Errorf("copy to clipboard: %w", err)
+		}
+		fmt.Printf("\nRaw request copied to clipboard (%s)\n", method)
+	}
 	fmt.Println()
-	fmt.Printf("To replay: `sectool replay send --bundle %s`\n", flowID)
+	cliutil.Hintf("To replay: `sectool replay send --bundle %s`\n", flowID)
 
 	return nil
 }
@@ -4,11 +4,14 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/spf13/pflag"
 
 	"github.com/go-harden/llm-security-toolbox/sectool/cli"
+	"github.com/go-harden/llm-security-toolbox/sectool/cliutil"
+	"github.com/go-harden/llm-security-toolbox/sectool/mcpclient"
 )
 
 const (
@@ -16,7 +19,7 @@ const (
 	subcmdErrors = "errors"
 )
 
-var crawlSubcommands = []string{"create", "seed", "status", "summary", "list", subcmdForms, subcmdErrors, "sessions", "stop", "export", "help"}
+var crawlSubcommands = []string{"create", "seed", "status", "summary", "list", subcmdForms, subcmdErrors, "results", "dirbust", "vhosts", "subdomains", "dns", "ports", "sessions", "stop", "export", "help"}
 
 func Parse(args []string, mcpURL string) error {
 	if len(args) < 1 {
@@ -39,6 +42,18 @@ func Parse(args []string, mcpURL string) error {
 		return parseForms(args[1:], mcpURL)
 	case subcmdErrors:
 		return parseErrors(args[1:], mcpURL)
+	case "results":
+		return parseResults(args[1:], mcpURL)
+	case "dirbust":
+		return parseDirbust(args[1:], mcpURL)
+	case "vhosts":
+		return parseVhosts(args[1:], mcpURL)
+	case "subdomains":
+		return parseSubdomains(args[1:], mcpURL)
+	case "dns":
+		return parseDNS(args[1:], mcpURL)
+	case "ports":
+		return parsePorts(args[1:], mcpURL)
 	case "sessions":
 		return parseSessions(args[1:], mcpURL)
 	case "stop":
@@ -126,6 +141,7 @@ crawl list <session_id> [options]
     --since <val>          flows after: flow_id, timestamp, or 'last'
     --limit <n>            maximum results (default: 100)
     --offset <n>           skip first N results
+    --page <n>             page number (1-based, uses --limit as page size)
 
   Output: Markdown table with flow_id, method, host, path, status, size
 
@@ -153,6 +169,139 @@ crawl errors <session_id> [options]
 
 ---
 
+crawl results [session_id] [options]
+
+  List unique endpoints discovered by crawling (links and form actions)
+  and dirbust, merged across all sessions unless a session is given.
+  Without a session, open ports from 'crawl ports' are listed as services.
+
+  Options:
+    --host <pattern>       filter by host pattern (glob: *, ?)
+    --path <pattern>       filter by path pattern (glob: *, ?)
+    --method <list>        filter by HTTP method (comma-separated)
+    --limit <n>            maximum endpoints (default: 200)
+    --cursor <c>           continue from a previous page
+
+  Output: Markdown table with method, host, path, params, status, sources,
+  then open ports with service and banner
+
+---
+
+crawl dirbust <url> [options]
+
+  Brute-force directories and files under a base URL (ffuf style). Each
+  directory is first sent random names to fingerprint its not-found
+  response, so soft 404 pages and catch-all redirects are filtered out.
+  Paths outside the project scope are skipped. Hits are stored as replays
+  and listed by 'crawl results' with source dirbust.
+
+  Options:
+    -w, --wordlist <path>  names to try, one per line (default: built-in
+                           'dirs' list, see 'sectool payloads get dirs')
+    -x, --extensions <l>   extensions to also try, comma-separated (php,bak)
+    --filter-status <l>    status codes never reported (default: 404)
+    --max-depth <n>        recurse into found directories (default: 0)
+    --threads <n>          concurrent requests (default: 5, max 20)
+    --max-requests <n>     request budget (default: 1000)
+    --header <h>           header in 'Name: Value' format (repeatable)
+    --request-timeout <d>  per-request timeout
+    --rate <n>             requests per second to each host (default:
+                           rate_limit.host_requests_per_second config)
+
+  Examples:
+    sectool crawl dirbust https://example.com/
+    sectool crawl dirbust https://example.com/app/ -x php,bak --max-depth 1
+
+  Output: Markdown table with path, status, size, location, replay_id
+
+---
+
+crawl vhosts <url> [options]
+
+  Find virtual hosts served from one address by varying the Host header.
+  A random host is sent first to fingerprint the default response; Host
+  values answering differently are reported and stored as replays.
+
+  Options:
+    --domain <domain>      domain the labels are joined to (default: the
+                           url's host when it is a name)
+    -w, --wordlist <path>  labels to try, one per line (default: built-in
+                           'subdomains' list)
+    --host <host>          full Host value to try (repeatable)
+    --filter-status <l>    status codes never reported
+    --threads <n>          concurrent requests (default: 10, max 50)
+    --max-requests <n>     request budget (default: 500)
+    --header <h>           header in 'Name: Value' format (repeatable)
+    --request-timeout <d>  per-request timeout
+    --rate <n>             requests per second to each host (default:
+                           rate_limit.host_requests_per_second config)
+
+  Examples:
+    sectool crawl vhosts https://203.0.113.10/ --domain example.com
+    sectool crawl vhosts https://example.com/ --host intranet.corp.local
+
+  Output: Markdown table with host, status, size, title, replay_id
+
+---
+
+crawl subdomains <domain> [options]
+
+  Enumerate subdomains by DNS resolution of a wordlist and certificate
+  transparency logs (crt.sh). Sends nothing to the target's web servers.
+  With wildcard DNS, names resolving only to the wildcard are dropped.
+
+  Options:
+    -w, --wordlist <path>  labels to resolve, one per line (default:
+                           built-in 'subdomains' list)
+    --no-ct                skip the certificate transparency lookup
+    --threads <n>          concurrent DNS lookups (default: 10, max 50)
+
+  Output: Markdown table with host, addresses, sources
+
+---
+
+crawl dns <name|ip> [options]
+
+  Resolve DNS records directly against a nameserver, follow the CNAME chain
+  and flag subdomain takeover candidates (dangling CNAMEs, targets on hosting
+  services). An IP address is reverse-resolved (PTR).
+
+  Options:
+    -t, --type <type>      record type: A, AAAA, CNAME, MX, TXT, NS, PTR
+                           (repeatable; default: all but PTR, PTR for an IP)
+    --server <ip[:port]>   DNS server (default: first resolv.conf nameserver);
+                           compare servers to check split-horizon DNS
+
+  Examples:
+    sectool crawl dns assets.example.com
+    sectool crawl dns intranet.example.com --server 10.0.0.2 -t A
+
+  Output: Markdown table with type, name, value, ttl, plus takeover notes
+
+---
+
+crawl ports <host>... [options]
+
+  Check TCP ports on in-scope hosts and grab service banners. Requires a
+  project scope with include patterns; out-of-scope pairs are skipped.
+  Open ports are also listed by 'crawl results'.
+
+  Options:
+    -p, --ports <spec>     ports and ranges, e.g. 22,80,8000-8100
+                           (default: ~50 common service ports)
+    --rate <n>             connections per second (default: 20, max 200)
+    --threads <n>          concurrent connections (default: 10, max 50)
+    --connect-timeout <d>  connect timeout per port (default: 3s)
+    --banner-wait <d>      wait for a banner (default: 1s)
+
+  Examples:
+    sectool crawl ports app.example.com
+    sectool crawl ports 10.0.0.5 10.0.0.6 -p 1-1024 --rate 50
+
+  Output: Markdown table with host, port, service, banner
+
+---
+
 crawl sessions [options]
 
   List all crawl sessions (most recent first).
@@ -176,6 +325,9 @@ crawl export <flow_id>
 
   Export a crawled flow to an editable bundle on disk.
 
+  Options:
+    --copy                 also copy the raw request to the clipboard
+
   Output: Bundle path and list of created files
 `)
 }
@@ -324,7 +476,7 @@ func parseList(args []string, mcpURL string) error {
 	fs.SetInterspersed(true)
 	var timeout time.Duration
 	var host, path, method, status, contains, containsBody, excludeHost, excludePath, since string
-	var limit, offset int
+	var limit, offset, page int
 
 	fs.DurationVar(&timeout, "timeout", 30*time.Second, "client-side timeout")
 	fs.StringVar(&host, "host", "", "filter by host pattern (glob: *, ?)")
@@ -338,6 +490,7 @@ func parseList(args []string, mcpURL string) error {
 	fs.StringVar(&since, "since", "", "flows after flow_id or timestamp")
 	fs.IntVar(&limit, "limit", 100, "maximum results")
 	fs.IntVar(&offset, "offset", 0, "skip first N results")
+	fs.IntVar(&page, "page", 0, "page number (1-based, uses --limit as page size)")
 
 	fs.Usage = func() {
 		_, _ = fmt.Fprint(os.Stderr, `Usage: sectool crawl list <session_id> [options]
@@ -357,6 +510,10 @@ Options:
 		fs.Usage()
 		return errors.New("session_id required")
 	}
+	offset, err := cliutil.PageOffset(page, limit, offset)
+	if err != nil {
+		return err
+	}
 
 	return list(mcpURL, timeout, fs.Args()[0], "urls", host, path, method, status, contains, containsBody, excludeHost, excludePath, since, limit, offset)
 }
@@ -423,6 +580,289 @@ Options:
 	return list(mcpURL, timeout, fs.Args()[0], "errors", "", "", "", "", "", "", "", "", "", limit, 0)
 }
 
+func parseResults(args []string, mcpURL string) error {
+	fs := pflag.NewFlagSet("crawl results", pflag.ContinueOnError)
+	fs.SetInterspersed(true)
+	var timeout time.Duration
+	var opts mcpclient.CrawlResultsOpts
+
+	fs.DurationVar(&timeout, "timeout", 30*time.Second, "client-side timeout")
+	fs.StringVar(&opts.Host, "host", "", "filter by host pattern")
+	fs.StringVar(&opts.Path, "path", "", "filter by path pattern")
+	fs.StringVar(&opts.Method, "method", "", "filter by HTTP method (comma-separated)")
+	fs.IntVar(&opts.Limit, "limit", 0, "maximum endpoints to return")
+	fs.StringVar(&opts.Cursor, "cursor", "", "continue from a previous page")
+
+	fs.Usage = func() {
+		_, _ = fmt.Fprint(os.Stderr, `Usage: sectool crawl results [session_id] [options]
+
+List unique endpoints discovered by crawling.
+
+Options:
+`)
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() > 1 {
+		fs.Usage()
+		return errors.New("at most one session_id is allowed")
+	}
+	opts.SessionID = fs.Arg(0)
+
+	return results(mcpURL, timeout, opts)
+}
+
+func parseDirbust(args []string, mcpURL string) error {
+	fs := pflag.NewFlagSet("crawl dirbust", pflag.ContinueOnError)
+	fs.SetInterspersed(true)
+	var timeout, requestTimeout time.Duration
+	var rate float64
+	var wordlist string
+	var headers []string
+	var opts mcpclient.DirbustOpts
+
+	fs.DurationVar(&timeout, "timeout", 30*time.Minute, "client-side timeout")
+	fs.StringVarP(&wordlist, "wordlist", "w", "", "names to try, one per line (- for stdin)")
+	fs.StringVarP(&opts.Extensions, "extensions", "x", "", "extensions to also try (comma-separated)")
+	fs.StringVar(&opts.FilterStatus, "filter-status", "", "status codes never reported (default: 404)")
+	fs.IntVar(&opts.MaxDepth, "max-depth", 0, "recurse into found directories")
+	fs.IntVar(&opts.Threads, "threads", 0, "concurrent requests (default: 5)")
+	fs.IntVar(&opts.MaxRequests, "max-requests", 0, "request budget (default: 1000)")
+	fs.StringArrayVar(&headers, "header", nil, "header in 'Name: Value' format (repeatable)")
+	fs.DurationVar(&requestTimeout, "request-timeout", 0, "per-request timeout")
+	fs.Float64Var(&rate, "rate", 0, "requests per second to each host (default: from config)")
+
+	fs.Usage = func() {
+		_, _ = fmt.Fprint(os.Stderr, `Usage: sectool crawl dirbust <url> [options]
+
+Brute-force directories and files under a base URL.
+
+Options:
+`)
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return errors.New("exactly one url is required")
+	}
+	opts.URL = fs.Arg(0)
+	if wordlist != "" {
+		words, err := cliutil.ReadWordlist(wordlist)
+		if err != nil {
+			return err
+		}
+		opts.Words = words
+	}
+	var err error
+	if opts.Headers, err = parseHeaderFlags(headers); err != nil {
+		return err
+	}
+	if requestTimeout > 0 {
+		opts.Timeout = requestTimeout.String()
+	}
+	if fs.Changed("rate") {
+		opts.Rate = &rate
+	}
+
+	return dirbust(mcpURL, timeout, opts)
+}
+
+func parseVhosts(args []string, mcpURL string) error {
+	fs := pflag.NewFlagSet("crawl vhosts", pflag.ContinueOnError)
+	fs.SetInterspersed(true)
+	var timeout, requestTimeout time.Duration
+	var rate float64
+	var wordlist string
+	var headers []string
+	var opts mcpclient.VhostDiscoverOpts
+
+	fs.DurationVar(&timeout, "timeout", 30*time.Minute, "client-side timeout")
+	fs.StringVar(&opts.Domain, "domain", "", "domain the labels are joined to")
+	fs.StringVarP(&wordlist, "wordlist", "w", "", "labels to try, one per line (- for stdin)")
+	fs.StringArrayVar(&opts.Hosts, "host", nil, "full Host value to try (repeatable)")
+	fs.StringVar(&opts.FilterStatus, "filter-status", "", "status codes never reported (comma-separated)")
+	fs.IntVar(&opts.Threads, "threads", 0, "concurrent requests (default: 10)")
+	fs.IntVar(&opts.MaxRequests, "max-requests", 0, "request budget (default: 500)")
+	fs.StringArrayVar(&headers, "header", nil, "header in 'Name: Value' format (repeatable)")
+	fs.DurationVar(&requestTimeout, "request-timeout", 0, "per-request timeout")
+	fs.Float64Var(&rate, "rate", 0, "requests per second to each host (default: from config)")
+
+	fs.Usage = func() {
+		_, _ = fmt.Fprint(os.Stderr, `Usage: sectool crawl vhosts <url> [options]
+
+Find virtual hosts served from one address by varying the Host header.
+
+Options:
+`)
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return errors.New("exactly one url is required")
+	}
+	opts.URL = fs.Arg(0)
+	if wordlist != "" {
+		words, err := cliutil.ReadWordlist(wordlist)
+		if err != nil {
+			return err
+		}
+		opts.Words = words
+	}
+	var err error
+	if opts.Headers, err = parseHeaderFlags(headers); err != nil {
+		return err
+	}
+	if requestTimeout > 0 {
+		opts.Timeout = requestTimeout.String()
+	}
+	if fs.Changed("rate") {
+		opts.Rate = &rate
+	}
+
+	return vhosts(mcpURL, timeout, opts)
+}
+
+func parseSubdomains(args []string, mcpURL string) error {
+	fs := pflag.NewFlagSet("crawl subdomains", pflag.ContinueOnError)
+	fs.SetInterspersed(true)
+	var timeout time.Duration
+	var wordlist string
+	var opts mcpclient.SubdomainEnumOpts
+
+	fs.DurationVar(&timeout, "timeout", 10*time.Minute, "client-side timeout")
+	fs.StringVarP(&wordlist, "wordlist", "w", "", "labels to resolve, one per line (- for stdin)")
+	fs.BoolVar(&opts.NoCT, "no-ct", false, "skip the certificate transparency lookup")
+	fs.IntVar(&opts.Threads, "threads", 0, "concurrent DNS lookups (default: 10)")
+
+	fs.Usage = func() {
+		_, _ = fmt.Fprint(os.Stderr, `Usage: sectool crawl subdomains <domain> [options]
+
+Enumerate subdomains by DNS resolution and certificate transparency logs.
+
+Options:
+`)
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return errors.New("exactly one domain is required")
+	}
+	opts.Domain = fs.Arg(0)
+	if wordlist != "" {
+		words, err := cliutil.ReadWordlist(wordlist)
+		if err != nil {
+			return err
+		}
+		opts.Words = words
+	}
+
+	return subdomains(mcpURL, timeout, opts)
+}
+
+func parseDNS(args []string, mcpURL string) error {
+	fs := pflag.NewFlagSet("crawl dns", pflag.ContinueOnError)
+	fs.SetInterspersed(true)
+	var timeout time.Duration
+	var opts mcpclient.DNSQueryOpts
+
+	fs.DurationVar(&timeout, "timeout", time.Minute, "client-side timeout")
+	fs.StringArrayVarP(&opts.Types, "type", "t", nil, "record type (repeatable)")
+	fs.StringVar(&opts.Server, "server", "", "DNS server as ip or ip:port")
+
+	fs.Usage = func() {
+		_, _ = fmt.Fprint(os.Stderr, `Usage: sectool crawl dns <name|ip> [options]
+
+Resolve DNS records and check CNAMEs for subdomain takeover.
+
+Options:
+`)
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return errors.New("exactly one name or IP address is required")
+	}
+	opts.Name = fs.Arg(0)
+
+	return dnsQuery(mcpURL, timeout, opts)
+}
+
+func parsePorts(args []string, mcpURL string) error {
+	fs := pflag.NewFlagSet("crawl ports", pflag.ContinueOnError)
+	fs.SetInterspersed(true)
+	var timeout, connectTimeout, bannerWait time.Duration
+	var opts mcpclient.PortScanOpts
+
+	fs.DurationVar(&timeout, "timeout", 30*time.Minute, "client-side timeout")
+	fs.StringVarP(&opts.Ports, "ports", "p", "", "ports and ranges, e.g. 22,80,8000-8100")
+	fs.IntVar(&opts.Rate, "rate", 0, "connections per second (default: 20)")
+	fs.IntVar(&opts.Threads, "threads", 0, "concurrent connections (default: 10)")
+	fs.DurationVar(&connectTimeout, "connect-timeout", 0, "connect timeout per port (default: 3s)")
+	fs.DurationVar(&bannerWait, "banner-wait", 0, "wait for a banner (default: 1s)")
+
+	fs.Usage = func() {
+		_, _ = fmt.Fprint(os.Stderr, `Usage: sectool crawl ports <host>... [options]
+
+Check TCP ports on in-scope hosts and grab service banners.
+
+Options:
+`)
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return errors.New("at least one host is required")
+	}
+	opts.Hosts = fs.Args()
+	if connectTimeout > 0 {
+		opts.Timeout = connectTimeout.String()
+	}
+	if bannerWait > 0 {
+		opts.BannerWait = bannerWait.String()
+	}
+
+	return ports(mcpURL, timeout, opts)
+}
+
+// parseHeaderFlags parses repeated 'Name: Value' flags into a header map.
+func parseHeaderFlags(headers []string) (map[string]string, error) {
+	var result map[string]string
+	for _, h := range headers {
+		name, value, ok := strings.Cut(h, ":")
+		if !ok {
+			return nil, fmt.Errorf("invalid header %q: expected 'Name: Value'", h)
+		}
+		if result == nil {
+			result = make(map[string]string)
+		}
+		result[strings.TrimSpace(name)] = strings.TrimSpace(value)
+	}
+	return result, nil
+}
+
 func parseSessions(args []string, mcpURL string) error {
 	fs := pflag.NewFlagSet("crawl sessions", pflag.ContinueOnError)
 	fs.SetInterspersed(true)
@@ -482,8 +922,10 @@ func parseExport(args []string, mcpURL string) error {
 	fs := pflag.NewFlagSet("crawl export", pflag.ContinueOnError)
 	fs.SetInterspersed(true)
 	var timeout time.Duration
+	var copyRaw bool
 
 	fs.DurationVar(&timeout, "timeout", 30*time.Second, "client-side timeout")
+	fs.BoolVar(&copyRaw, "copy", false, "also copy the raw request to the clipboard (OSC52 over SSH)")
 
 	fs.Usage = func() {
 		_, _ = fmt.Fprint(os.Stderr, `Usage: sectool crawl export <flow_id> [options]
@@ -504,5 +946,5 @@ Options:
 		return errors.New("flow_id required (get from 'sectool crawl list')")
 	}
 
-	return export(mcpURL, timeout, fs.Args()[0])
+	return export(mcpURL, timeout, fs.Args()[0], copyRaw)
 }
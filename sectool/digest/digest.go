@@ -0,0 +1,118 @@
+// Package digest computes hashes and HMACs for crafting signed requests and comparing
+// leaked hashes.
+package digest
+
+import (
+	"crypto/hmac"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// DefaultAlgorithm is used when no algorithm is named.
+const DefaultAlgorithm = "sha256"
+
+var algorithms = map[string]func() hash.Hash{
+	"md5":    md5.New,
+	"sha1":   sha1.New,
+	"sha256": sha256.New,
+	"sha512": sha512.New,
+}
+
+// Algorithms returns the supported algorithm names, sorted.
+func Algorithms() []string {
+	return slices.Sorted(maps.Keys(algorithms))
+}
+
+// Sum returns the alg digest of data.
+func Sum(alg string, data []byte) ([]byte, error) {
+	newHash, err := lookup(alg)
+	if err != nil {
+		return nil, err
+	}
+	h := newHash()
+	h.Write(data)
+	return h.Sum(nil), nil
+}
+
+// HMAC returns the alg HMAC of data under key.
+func HMAC(alg string, key, data []byte) ([]byte, error) {
+	newHash, err := lookup(alg)
+	if err != nil {
+		return nil, err
+	}
+	mac := hmac.New(newHash, key)
+	mac.Write(data)
+	return mac.Sum(nil), nil
+}
+
+// Name normalizes an algorithm name: "SHA-256" becomes "sha256", and empty becomes
+// DefaultAlgorithm.
+func Name(alg string) string {
+	alg = strings.ReplaceAll(strings.ToLower(strings.TrimSpace(alg)), "-", "")
+	if alg == "" {
+		return DefaultAlgorithm
+	}
+	return alg
+}
+
+func lookup(alg string) (func() hash.Hash, error) {
+	alg = Name(alg)
+	newHash, ok := algorithms[alg]
+	if !ok {
+		return nil, fmt.Errorf("unknown algorithm %q (supported: %s)", alg, strings.Join(Algorithms(), ", "))
+	}
+	return newHash, nil
+}
+
+// Decode converts value to bytes per encoding: text (default, the UTF-8 bytes), hex, or
+// base64 (standard or URL alphabet, padding optional).
+func Decode(value, encoding string) ([]byte, error) {
+	switch strings.ToLower(encoding) {
+	case "", "text":
+		return []byte(value), nil
+	case "hex":
+		b, err := hex.DecodeString(strings.TrimSpace(value))
+		if err != nil {
+			return nil, fmt.Errorf("hex decode error: %w", err)
+		}
+		return b, nil
+	case "base64":
+		b, err := decodeBase64(strings.TrimSpace(value))
+		if err != nil {
+			return nil, fmt.Errorf("base64 decode error: %w", err)
+		}
+		return b, nil
+	default:
+		return nil, fmt.Errorf("unknown encoding %q (use text, hex or base64)", encoding)
+	}
+}
+
+func decodeBase64(value string) ([]byte, error) {
+	enc := base64.RawStdEncoding
+	if strings.ContainsAny(value, "-_") {
+		enc = base64.RawURLEncoding
+	}
+	return enc.DecodeString(strings.TrimRight(value, "="))
+}
+
+// Matches reports whether expected is sum written as hex (any case) or base64.
+func Matches(sum []byte, expected string) bool {
+	expected = strings.TrimSpace(expected)
+	if b, err := hex.DecodeString(expected); err == nil {
+		return subtle.ConstantTimeCompare(b, sum) == 1
+	}
+	if b, err := decodeBase64(expected); err == nil {
+		return subtle.ConstantTimeCompare(b, sum) == 1
+	}
+	return false
+}
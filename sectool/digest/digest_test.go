@@ -0,0 +1,81 @@
+package digest
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSum(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		alg  string
+		want string
+	}{
+		{"md5", "5d41402abc4b2a76b9719d911017c592"},
+		{"SHA-1", "aaf4c61ddcc5e8a2dabede0f3b482cd9aea9434d"},
+		{"", "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824"},
+		{"sha512", "9b71d224bd62f3785d96d46ad3ea3d73319bfbc2890caadae2dff72519673ca72323c3d99ba5c11d7c7acc6e14b8c5da0c4663475c2e5c3adef46f73bcdec043"},
+	}
+	for _, tc := range tests {
+		sum, err := Sum(tc.alg, []byte("hello"))
+		require.NoError(t, err, tc.alg)
+		assert.Equal(t, tc.want, hex.EncodeToString(sum), tc.alg)
+	}
+
+	_, err := Sum("sha3", nil)
+	assert.ErrorContains(t, err, "unknown algorithm")
+}
+
+func TestHMAC(t *testing.T) {
+	t.Parallel()
+
+	// RFC 4231 test case 2
+	mac, err := HMAC("sha256", []byte("Jefe"), []byte("what do ya want for nothing?"))
+	require.NoError(t, err)
+	assert.Equal(t, "5bdcc146bf60754e6a042426089575c75a003f089d2739839dec58b964ec3843", hex.EncodeToString(mac))
+}
+
+func TestDecode(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		value    string
+		encoding string
+		want     string
+		wantErr  bool
+	}{
+		{name: "text", value: "key", want: "key"},
+		{name: "hex", value: "6b6579", encoding: "hex", want: "key"},
+		{name: "base64_padded", value: "a2V5Pw==", encoding: "base64", want: "key?"},
+		{name: "base64_url_unpadded", value: "_-8", encoding: "base64", want: "\xff\xef"},
+		{name: "bad_hex", value: "zz", encoding: "hex", wantErr: true},
+		{name: "unknown", value: "x", encoding: "rot13", wantErr: true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := Decode(tc.value, tc.encoding)
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, string(got))
+		})
+	}
+}
+
+func TestMatches(t *testing.T) {
+	t.Parallel()
+
+	sum, err := Sum("md5", []byte("hello"))
+	require.NoError(t, err)
+	assert.True(t, Matches(sum, "5D41402ABC4B2A76B9719D911017C592"))
+	assert.True(t, Matches(sum, "XUFAKrxLKna5cZ2REBfFkg=="))
+	assert.False(t, Matches(sum, "5d41402abc4b2a76b9719d911017c593"))
+	assert.False(t, Matches(sum, "not a digest!"))
+}
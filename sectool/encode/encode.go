@@ -2,17 +2,32 @@ package encode
 
 import (
 	"encoding/base64"
+	"encoding/hex"
+	"errors"
 	"fmt"
 	"html"
 	"net/url"
+
+	"github.com/go-harden/llm-security-toolbox/sectool/cliutil"
+	"github.com/go-harden/llm-security-toolbox/sectool/digest"
+	"github.com/go-harden/llm-security-toolbox/sectool/payload"
+	"github.com/go-harden/llm-security-toolbox/sectool/saml"
 )
 
-func run(input string, decode, raw bool, fn func(string, bool) (string, error)) error {
+func run(input string, decode, raw, copyResult bool, fn func(string, bool) (string, error)) error {
 	result, err := fn(input, decode)
 	if err != nil {
 		return err
 	}
 
+	if copyResult {
+		method, err := cliutil.CopyToClipboard(result)
+		if err != nil {
+			return fmt.Errorf("copy to clipboard: %w", err)
+		}
+		cliutil.Logf(cliutil.VerbosityNormal, "copied to clipboard (%s)", method)
+	}
+
 	if raw {
 		fmt.Print(result)
 	} else {
@@ -39,9 +54,109 @@ func encodeBase64(input string, decode bool) (string, error) {
 	return base64.StdEncoding.EncodeToString([]byte(input)), nil
 }
 
+// encodeNamed returns the encode/decode function for a payload.Encoding.
+func encodeNamed(name string) func(string, bool) (string, error) {
+	return func(input string, decode bool) (string, error) {
+		enc, err := payload.GetEncoding(name)
+		if err != nil {
+			return "", err
+		}
+		if !decode {
+			return enc.Encode(input), nil
+		}
+		decoded, err := enc.Decode(input)
+		if err != nil {
+			return "", fmt.Errorf("%s decode error: %w", name, err)
+		}
+		return decoded, nil
+	}
+}
+
+// encodeSAML encodes XML for the SAML POST binding, or decodes a SAMLRequest or
+// SAMLResponse value from either binding back to XML.
+func encodeSAML(input string, decode bool) (string, error) {
+	if decode {
+		msg, err := saml.Decode(input)
+		if err != nil {
+			return "", fmt.Errorf("saml decode error: %w", err)
+		}
+		return msg.XML, nil
+	}
+	return saml.Encode(input, false)
+}
+
 func encodeHTML(input string, decode bool) (string, error) {
 	if decode {
 		return html.UnescapeString(input), nil
 	}
 	return html.EscapeString(input), nil
 }
+
+type digestOptions struct {
+	alg           string
+	key           string
+	keyEncoding   string
+	inputEncoding string
+	base64        bool
+	expected      string
+	raw           bool
+	copyResult    bool
+}
+
+// runDigest prints the hash, or with useKey the HMAC, of input. It fails when
+// opts.expected is set and differs, so scripts can test the exit code.
+func runDigest(useKey bool, input string, opts digestOptions) error {
+	data, err := digest.Decode(input, opts.inputEncoding)
+	if err != nil {
+		return fmt.Errorf("input: %w", err)
+	}
+
+	var sum []byte
+	if useKey {
+		key, err := digest.Decode(opts.key, opts.keyEncoding)
+		if err != nil {
+			return fmt.Errorf("key: %w", err)
+		}
+		sum, err = digest.HMAC(opts.alg, key, data)
+		if err != nil {
+			return err
+		}
+	} else if sum, err = digest.Sum(opts.alg, data); err != nil {
+		return err
+	}
+
+	err = run(input, false, opts.raw, opts.copyResult, func(string, bool) (string, error) {
+		if opts.base64 {
+			return base64.StdEncoding.EncodeToString(sum), nil
+		}
+		return hex.EncodeToString(sum), nil
+	})
+	if err != nil || opts.expected == "" {
+		return err
+	}
+	if !digest.Matches(sum, opts.expected) {
+		return errors.New("digest does not match expected")
+	}
+	cliutil.Logf(cliutil.VerbosityNormal, "digest matches expected")
+	return nil
+}
+
+// transform prints the variants of payloads, one per line, so they can feed a payload list.
+func transform(payloads, presets []string) error {
+	variants, err := payload.Transform(payloads, presets)
+	if err != nil {
+		return err
+	}
+	for _, v := range variants {
+		fmt.Println(v.Payload)
+	}
+	cliutil.Logf(cliutil.VerbosityNormal, "%d variant(s) from %d payload(s)", len(variants), len(payloads))
+	return nil
+}
+
+func listPresets() error {
+	for _, p := range payload.Presets() {
+		fmt.Printf("%-14s %s\n", p.Name, p.Description)
+	}
+	return nil
+}
@@ -58,7 +58,7 @@ func TestRun(t *testing.T) {
 				})
 			}
 
-			err := run("value", false, tt.raw, tt.fn)
+			err := run("value", false, tt.raw, false, tt.fn)
 			if tt.wantErr != nil {
 				require.Error(t, err)
 				assert.EqualError(t, err, tt.wantErr.Error())
@@ -148,3 +148,20 @@ func TestEncodeHTML(t *testing.T) {
 		})
 	}
 }
+
+func TestRunDigest(t *testing.T) {
+	t.Run("matches_expected", func(t *testing.T) {
+		err := runDigest(false, "hello", digestOptions{alg: "md5", raw: true, expected: "5d41402abc4b2a76b9719d911017c592"})
+		assert.NoError(t, err)
+	})
+
+	t.Run("mismatch", func(t *testing.T) {
+		err := runDigest(true, "hello", digestOptions{key: "k", raw: true, expected: "00"})
+		assert.EqualError(t, err, "digest does not match expected")
+	})
+
+	t.Run("bad_key_encoding", func(t *testing.T) {
+		err := runDigest(true, "hello", digestOptions{key: "zz", keyEncoding: "hex"})
+		assert.ErrorContains(t, err, "key: hex decode error")
+	})
+}
@@ -10,9 +10,10 @@ import (
 	"github.com/spf13/pflag"
 
 	"github.com/go-harden/llm-security-toolbox/sectool/cli"
+	"github.com/go-harden/llm-security-toolbox/sectool/digest"
 )
 
-var encodeSubcommands = []string{"url", "base64", "html", "help"}
+var encodeSubcommands = []string{"url", "base64", "html", "double-url", "base64url", "hex", "unicode", "html-decimal", "html-hex", "saml", "hash", "hmac", "transform", "help"}
 
 func Parse(args []string) error {
 	if len(args) < 1 {
@@ -27,6 +28,14 @@ func Parse(args []string) error {
 		return parseAndRun("base64", args[1:], encodeBase64)
 	case "html":
 		return parseAndRun("html", args[1:], encodeHTML)
+	case "double-url", "base64url", "hex", "unicode", "html-decimal", "html-hex":
+		return parseAndRun(args[0], args[1:], encodeNamed(args[0]))
+	case "saml":
+		return parseAndRun("saml", args[1:], encodeSAML)
+	case "hash", "hmac":
+		return parseDigest(args[0], args[1:])
+	case "transform":
+		return parseTransform(args[1:])
 	case "help", "--help", "-h":
 		printUsage()
 		return nil
@@ -74,10 +83,76 @@ encode html [options] <string>
 
 ---
 
-Common Options (all types):
+encode double-url | base64url | hex | unicode | html-decimal | html-hex [options] <string>
+
+  Further encodings for filter and WAF bypass: URL encoding applied twice,
+  unpadded URL-safe base64 (JWT style), hex bytes, \uXXXX escapes per
+  character, and decimal or hex HTML entities per character.
+
+  Examples:
+    sectool encode double-url "../etc/passwd"  # ..%252Fetc%252Fpasswd
+    sectool encode unicode "<svg>"             # \u003c\u0073\u0076\u0067\u003e
+    sectool encode html-hex "<svg>"            # &#x3c;&#x73;&#x76;&#x67;&#x3e;
+    sectool encode hex -d '\x41\x42'           # AB
+
+---
+
+encode saml [options] <string | -f PATH>
+
+  SAMLRequest/SAMLResponse values. Decoding accepts URL-encoded values and
+  both the POST (base64) and redirect (DEFLATE + base64) bindings and prints
+  the XML; encoding produces the POST binding.
+
+  Examples:
+    sectool encode saml -d "PHNhbWxwOlJlc3BvbnNl..."
+    sectool encode saml -f response.xml | sectool encode url
+
+---
+
+encode hash [options] <string | -f PATH>
+encode hmac --key KEY [options] <string | -f PATH>
+
+  Hex digest (or HMAC) of the input, for comparing leaked hashes or signing
+  webhook bodies and API requests. With --expected, exits non-zero when the
+  digest differs.
+
+  Options:
+    -a, --alg <name>          md5, sha1, sha256 (default), sha512
+    --key <key>               HMAC key (hmac only)
+    --key-encoding <enc>      how --key is written: text (default), hex, base64
+    --input-encoding <enc>    how the input is written: text (default), hex, base64
+    --base64                  print base64 instead of hex
+    --expected <digest>       compare against a hash or signature (hex or base64)
+
+  Examples:
+    sectool encode hash -a md5 "password123"
+    sectool encode hash -f dump.bin --expected 482c811da5d5b4bc6d497ffa98491e38
+    sectool encode hmac --key whsec_123 -f body.json --base64
+
+---
+
+encode transform [options] <string | -f PATH>
+
+  WAF-evasion variants of a payload, one per line. With -f, each line of
+  the file is a payload.
+
+  Options:
+    --preset <list>   comma-separated presets: case, sql-comment, whitespace,
+                      keyword-split, overlong-utf8, null-byte, all
+                      (default: all)
+    --list            list presets and exit
+
+  Examples:
+    sectool encode transform --preset sql-comment "1 UNION SELECT 1"
+    sectool encode transform -f sqli.txt > sqli-waf.txt
+
+---
+
+Common Options (all encodings; -f, --raw and --copy also apply to hash, hmac):
   -d, --decode      decode instead of encode
   -f, --file PATH   read input from file (- for stdin)
   --raw             output without trailing newline
+  --copy            also copy the result to the clipboard (OSC52 over SSH)
 
 Output: Encoded/decoded string to stdout
 `)
@@ -86,12 +161,13 @@ Output: Encoded/decoded string to stdout
 func parseAndRun(name string, args []string, fn func(string, bool) (string, error)) error {
 	fs := pflag.NewFlagSet("encode "+name, pflag.ContinueOnError)
 	fs.SetInterspersed(true)
-	var decode, raw bool
+	var decode, raw, copyResult bool
 	var file string
 
 	fs.BoolVarP(&decode, "decode", "d", false, "decode instead of encode")
 	fs.StringVarP(&file, "file", "f", "", "read input from file (- for stdin)")
 	fs.BoolVar(&raw, "raw", false, "output without trailing newline")
+	fs.BoolVar(&copyResult, "copy", false, "also copy the result to the clipboard (OSC52 over SSH)")
 
 	fs.Usage = func() {
 		_, _ = fmt.Fprintf(os.Stderr, "Usage: sectool encode %s [options] <string>\n\nOptions:\n", name)
@@ -104,22 +180,119 @@ func parseAndRun(name string, args []string, fn func(string, bool) (string, erro
 
 	var input string
 	if file != "" {
-		var data []byte
 		var err error
-		if file == "-" {
-			data, err = io.ReadAll(os.Stdin)
-		} else {
-			data, err = os.ReadFile(file)
+		if input, err = readInput(file); err != nil {
+			return err
 		}
-		if err != nil {
-			return fmt.Errorf("reading input: %w", err)
+	} else if remaining := fs.Args(); len(remaining) > 0 {
+		input = strings.Join(remaining, " ")
+	} else {
+		return errors.New("input required: provide string argument or use -f")
+	}
+
+	return run(input, decode, raw, copyResult, fn)
+}
+
+func parseDigest(name string, args []string) error {
+	fs := pflag.NewFlagSet("encode "+name, pflag.ContinueOnError)
+	fs.SetInterspersed(true)
+	var opts digestOptions
+	var file string
+
+	fs.StringVarP(&opts.alg, "alg", "a", digest.DefaultAlgorithm, "algorithm: "+strings.Join(digest.Algorithms(), ", "))
+	if name == "hmac" {
+		fs.StringVar(&opts.key, "key", "", "HMAC key (required)")
+		fs.StringVar(&opts.keyEncoding, "key-encoding", "text", "how --key is written: text, hex, base64")
+	}
+	fs.StringVar(&opts.inputEncoding, "input-encoding", "text", "how the input is written: text, hex, base64")
+	fs.BoolVar(&opts.base64, "base64", false, "print base64 instead of hex")
+	fs.StringVar(&opts.expected, "expected", "", "compare against a hash or signature (hex or base64)")
+	fs.StringVarP(&file, "file", "f", "", "read input from file (- for stdin)")
+	fs.BoolVar(&opts.raw, "raw", false, "output without trailing newline")
+	fs.BoolVar(&opts.copyResult, "copy", false, "also copy the result to the clipboard (OSC52 over SSH)")
+
+	fs.Usage = func() {
+		_, _ = fmt.Fprintf(os.Stderr, "Usage: sectool encode %s [options] <string | -f PATH>\n\nOptions:\n", name)
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if name == "hmac" && opts.key == "" {
+		fs.Usage()
+		return errors.New("--key is required")
+	}
+
+	var input string
+	if file != "" {
+		var err error
+		if input, err = readInput(file); err != nil {
+			return err
 		}
-		input = string(data)
 	} else if remaining := fs.Args(); len(remaining) > 0 {
 		input = strings.Join(remaining, " ")
 	} else {
 		return errors.New("input required: provide string argument or use -f")
 	}
 
-	return run(input, decode, raw, fn)
+	return runDigest(name == "hmac", input, opts)
+}
+
+func parseTransform(args []string) error {
+	fs := pflag.NewFlagSet("encode transform", pflag.ContinueOnError)
+	fs.SetInterspersed(true)
+	var file string
+	var presets []string
+	var list bool
+
+	fs.StringVarP(&file, "file", "f", "", "read payloads from file, one per line (- for stdin)")
+	fs.StringSliceVar(&presets, "preset", nil, "comma-separated presets (default: all)")
+	fs.BoolVar(&list, "list", false, "list presets and exit")
+
+	fs.Usage = func() {
+		_, _ = fmt.Fprint(os.Stderr, "Usage: sectool encode transform [options] <string | -f PATH>\n\nOptions:\n")
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if list {
+		return listPresets()
+	}
+
+	var payloads []string
+	if file != "" {
+		input, err := readInput(file)
+		if err != nil {
+			return err
+		}
+		for _, line := range strings.Split(strings.TrimRight(input, "\r\n"), "\n") {
+			if line = strings.TrimSuffix(line, "\r"); line != "" {
+				payloads = append(payloads, line)
+			}
+		}
+	} else if remaining := fs.Args(); len(remaining) > 0 {
+		payloads = []string{strings.Join(remaining, " ")}
+	} else {
+		return errors.New("input required: provide string argument or use -f")
+	}
+
+	return transform(payloads, presets)
+}
+
+// readInput reads file contents, or stdin when file is "-".
+func readInput(file string) (string, error) {
+	var data []byte
+	var err error
+	if file == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(file)
+	}
+	if err != nil {
+		return "", fmt.Errorf("reading input: %w", err)
+	}
+	return string(data), nil
 }
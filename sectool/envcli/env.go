@@ -0,0 +1,79 @@
+package envcli
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/go-harden/llm-security-toolbox/sectool/cliutil"
+	"github.com/go-harden/llm-security-toolbox/sectool/mcpclient"
+	"github.com/go-harden/llm-security-toolbox/sectool/protocol"
+)
+
+func list(mcpURL string, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	client, err := mcpclient.Connect(ctx, mcpURL)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = client.Close() }()
+
+	resp, err := client.EnvList(ctx)
+	if err != nil {
+		return fmt.Errorf("env list failed: %w", err)
+	}
+	printEnvironments(resp)
+	return nil
+}
+
+func set(mcpURL string, timeout time.Duration, opts mcpclient.EnvSetOpts) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	client, err := mcpclient.Connect(ctx, mcpURL)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = client.Close() }()
+
+	resp, err := client.EnvSet(ctx, opts)
+	if err != nil {
+		return fmt.Errorf("env set failed: %w", err)
+	}
+	printEnvironments(resp)
+	return nil
+}
+
+func printEnvironments(resp *protocol.EnvListResponse) {
+	if len(resp.Environments) == 0 {
+		fmt.Println("No environments defined.")
+		cliutil.Hintf("\nTo create one: `sectool env set staging base_url=https://staging.example.com --activate`\n")
+		return
+	}
+
+	for _, env := range resp.Environments {
+		marker := ""
+		if env.Name == resp.Active {
+			marker = " (active)"
+		}
+		fmt.Printf("## %s%s\n\n", env.Name, marker)
+		if len(env.Vars) == 0 {
+			fmt.Println("No variables.")
+			fmt.Println()
+			continue
+		}
+		fmt.Println("| name | value |")
+		fmt.Println("|------|-------|")
+		for _, name := range slices.Sorted(maps.Keys(env.Vars)) {
+			fmt.Printf("| %s | %s |\n", cliutil.EscapeMarkdown(name), cliutil.EscapeMarkdown(env.Vars[name]))
+		}
+		fmt.Println()
+	}
+	if resp.Active == "" {
+		cliutil.Hintf("No active environment: pass --activate to `sectool env set` to use one by default.\n")
+	}
+}
@@ -0,0 +1,130 @@
+package envcli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/pflag"
+
+	"github.com/go-harden/llm-security-toolbox/sectool/cli"
+	"github.com/go-harden/llm-security-toolbox/sectool/mcpclient"
+)
+
+var envSubcommands = []string{"list", "set", "help"}
+
+func Parse(args []string, mcpURL string) error {
+	if len(args) < 1 {
+		printUsage()
+		return errors.New("subcommand required")
+	}
+
+	switch args[0] {
+	case "list":
+		return parseList(args[1:], mcpURL)
+	case "set":
+		return parseSet(args[1:], mcpURL)
+	case "help", "--help", "-h":
+		printUsage()
+		return nil
+	default:
+		return cli.UnknownSubcommandError("env", args[0], envSubcommands)
+	}
+}
+
+func printUsage() {
+	_, _ = fmt.Fprint(os.Stderr, `Usage: sectool env <command> [options]
+
+Named environments of request variables (.sectool/environments.json).
+Sends replace {{name}} placeholders in URLs, targets, headers and bodies
+with values from the active environment.
+
+---
+
+env list
+
+  List environments and their variables.
+
+---
+
+env set <name> [KEY=VALUE ...] [options]
+
+  Create or update an environment; values merge into existing ones.
+
+  Options:
+    --unset <key>        remove a variable (repeatable)
+    --activate           make this the active environment
+    --delete             delete the environment
+
+  Examples:
+    sectool env set staging base_url=https://staging.example.com token=abc --activate
+    sectool request new --url '{{base_url}}/api/me' -H 'Authorization: Bearer {{token}}'
+`)
+}
+
+func parseList(args []string, mcpURL string) error {
+	fs := pflag.NewFlagSet("env list", pflag.ContinueOnError)
+	fs.SetInterspersed(true)
+	var timeout time.Duration
+
+	fs.DurationVar(&timeout, "timeout", 30*time.Second, "client-side timeout")
+
+	fs.Usage = func() {
+		_, _ = fmt.Fprint(os.Stderr, `Usage: sectool env list [options]
+
+List request variable environments.
+
+Options:
+`)
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	return list(mcpURL, timeout)
+}
+
+func parseSet(args []string, mcpURL string) error {
+	fs := pflag.NewFlagSet("env set", pflag.ContinueOnError)
+	fs.SetInterspersed(true)
+	var timeout time.Duration
+	var opts mcpclient.EnvSetOpts
+
+	fs.DurationVar(&timeout, "timeout", 30*time.Second, "client-side timeout")
+	fs.StringArrayVar(&opts.Unset, "unset", nil, "variable to remove (repeatable)")
+	fs.BoolVar(&opts.Activate, "activate", false, "make this the active environment")
+	fs.BoolVar(&opts.Delete, "delete", false, "delete the environment")
+
+	fs.Usage = func() {
+		_, _ = fmt.Fprint(os.Stderr, `Usage: sectool env set <name> [KEY=VALUE ...] [options]
+
+Create or update an environment.
+
+Options:
+`)
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() < 1 {
+		fs.Usage()
+		return errors.New("environment name required")
+	}
+	opts.Name = fs.Arg(0)
+	opts.Vars = make(map[string]string)
+	for _, pair := range fs.Args()[1:] {
+		key, value, ok := strings.Cut(pair, "=")
+		if !ok || key == "" {
+			return fmt.Errorf("invalid variable %q: expected KEY=VALUE", pair)
+		}
+		opts.Vars[key] = value
+	}
+
+	return set(mcpURL, timeout, opts)
+}
@@ -0,0 +1,133 @@
+// Package export serializes HTTP exchanges as HAR 1.2 or JSON Lines for other tools,
+// and reads HAR captures back in.
+package export
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"strings"
+	"time"
+)
+
+// Export formats.
+const (
+	FormatHAR   = "har"
+	FormatJSONL = "jsonl"
+	FormatSARIF = "sarif"
+)
+
+// Exchange is one HTTP request and its response.
+type Exchange struct {
+	Kind     string // "flow" or "replay"; empty when read from a HAR
+	ID       string // flow_id or replay_id
+	URL      string
+	Request  []byte // raw HTTP request
+	Response []byte // raw HTTP response, empty when none was received
+	Started  time.Time
+	Duration time.Duration
+}
+
+// WriteJSONL writes each item as one line of JSON.
+func WriteJSONL[T any](w io.Writer, items []T) error {
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	for _, item := range items {
+		if err := enc.Encode(item); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// jsonlExchange is the JSON Lines form of an Exchange. Messages that are not valid
+// UTF-8 are base64 encoded and flagged by the matching encoding field.
+type jsonlExchange struct {
+	Kind             string `json:"kind"`
+	ID               string `json:"id"`
+	Method           string `json:"method"`
+	URL              string `json:"url"`
+	Status           int    `json:"status,omitempty"`
+	Started          string `json:"started,omitempty"`
+	DurationMs       int64  `json:"duration_ms,omitempty"`
+	Request          string `json:"request"`
+	RequestEncoding  string `json:"request_encoding,omitempty"`
+	Response         string `json:"response,omitempty"`
+	ResponseEncoding string `json:"response_encoding,omitempty"`
+}
+
+// WriteExchangesJSONL writes exchanges as JSON Lines, one exchange per line.
+func WriteExchangesJSONL(w io.Writer, exchanges []Exchange) error {
+	lines := make([]jsonlExchange, 0, len(exchanges))
+	for _, ex := range exchanges {
+		reqLine, _, _ := parseMessage(ex.Request)
+		statusLine, _, _ := parseMessage(ex.Response)
+		line := jsonlExchange{
+			Kind:       ex.Kind,
+			ID:         ex.ID,
+			Method:     field(reqLine, 0),
+			URL:        ex.URL,
+			Status:     statusCode(statusLine),
+			DurationMs: ex.Duration.Milliseconds(),
+		}
+		if !ex.Started.IsZero() {
+			line.Started = ex.Started.UTC().Format(time.RFC3339)
+		}
+		line.Request, line.RequestEncoding = encodeText(ex.Request)
+		line.Response, line.ResponseEncoding = encodeText(ex.Response)
+		lines = append(lines, line)
+	}
+	return WriteJSONL(w, lines)
+}
+
+// parseMessage splits a raw HTTP message into its start line, header lines and body.
+func parseMessage(raw []byte) (startLine string, headers []header, body []byte) {
+	head, body, found := bytes.Cut(raw, []byte("\r\n\r\n"))
+	if !found {
+		head, body, _ = bytes.Cut(raw, []byte("\n\n"))
+	}
+	lines := strings.Split(strings.ReplaceAll(string(head), "\r\n", "\n"), "\n")
+	for _, line := range lines[1:] {
+		name, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		headers = append(headers, header{Name: strings.TrimSpace(name), Value: strings.TrimSpace(value)})
+	}
+	return lines[0], headers, body
+}
+
+type header struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// headerValue returns the first value of the named header.
+func headerValue(headers []header, name string) string {
+	for _, h := range headers {
+		if strings.EqualFold(h.Name, name) {
+			return h.Value
+		}
+	}
+	return ""
+}
+
+// field returns the i-th space-separated field of a start line.
+func field(line string, i int) string {
+	parts := strings.SplitN(line, " ", 3)
+	if i < len(parts) {
+		return parts[i]
+	}
+	return ""
+}
+
+func statusCode(statusLine string) int {
+	var code int
+	for _, c := range field(statusLine, 1) {
+		if c < '0' || c > '9' {
+			return 0
+		}
+		code = code*10 + int(c-'0')
+	}
+	return code
+}
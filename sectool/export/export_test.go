@@ -0,0 +1,203 @@
+package export
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func gzipped(t *testing.T, s string) string {
+	t.Helper()
+
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	_, err := zw.Write([]byte(s))
+	require.NoError(t, err)
+	require.NoError(t, zw.Close())
+	return buf.String()
+}
+
+func TestWriteHAR(t *testing.T) {
+	t.Parallel()
+
+	exchanges := []Exchange{
+		{
+			Kind:     "flow",
+			ID:       "f1",
+			URL:      "https://example.com/search?q=a%20b&page=2",
+			Request:  []byte("POST /search?q=a%20b&page=2 HTTP/1.1\r\nHost: example.com\r\nCookie: sid=abc; theme=dark\r\nContent-Type: application/x-www-form-urlencoded\r\n\r\nx=1"),
+			Response: []byte("HTTP/1.1 302 Found\r\nLocation: /login\r\nSet-Cookie: sid=new; Path=/; HttpOnly; Secure\r\nContent-Encoding: gzip\r\nContent-Type: text/plain\r\n\r\n" + gzipped(t, "moved")),
+			Started:  time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+			Duration: 1500 * time.Microsecond,
+		},
+		{
+			Kind:    "replay",
+			ID:      "r1",
+			URL:     "http://example.com/img",
+			Request: []byte("GET /img HTTP/1.1\r\nHost: example.com\r\n\r\n"),
+			Started: time.Date(2026, 1, 2, 3, 4, 6, 0, time.UTC),
+		},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteHAR(&buf, exchanges))
+	var har harLog
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &har))
+
+	assert.Equal(t, "1.2", har.Log.Version)
+	require.Len(t, har.Log.Entries, 2)
+
+	entry := har.Log.Entries[0]
+	assert.Equal(t, "2026-01-02T03:04:05Z", entry.StartedDateTime)
+	assert.InDelta(t, 1.5, entry.Time, 0.001)
+	assert.Equal(t, "flow f1", entry.Comment)
+
+	req := entry.Request
+	assert.Equal(t, "POST", req.Method)
+	assert.Equal(t, "HTTP/1.1", req.HTTPVersion)
+	assert.Equal(t, []header{{Name: "q", Value: "a b"}, {Name: "page", Value: "2"}}, req.QueryString)
+	assert.Equal(t, []harCookie{{Name: "sid", Value: "abc"}, {Name: "theme", Value: "dark"}}, req.Cookies)
+	require.NotNil(t, req.PostData)
+	assert.Equal(t, "x=1", req.PostData.Text)
+	assert.Equal(t, "application/x-www-form-urlencoded", req.PostData.MimeType)
+	assert.Equal(t, 3, req.BodySize)
+
+	resp := entry.Response
+	assert.Equal(t, 302, resp.Status)
+	assert.Equal(t, "Found", resp.StatusText)
+	assert.Equal(t, "/login", resp.RedirectURL)
+	assert.Equal(t, "moved", resp.Content.Text)
+	assert.Equal(t, 5, resp.Content.Size)
+	require.Len(t, resp.Cookies, 1)
+	assert.True(t, resp.Cookies[0].HTTPOnly)
+	assert.Equal(t, "/", resp.Cookies[0].Path)
+
+	// No response received
+	assert.Equal(t, 0, har.Log.Entries[1].Response.Status)
+	assert.Equal(t, -1, har.Log.Entries[1].Response.BodySize)
+	assert.Nil(t, har.Log.Entries[1].Request.PostData)
+}
+
+func TestWriteHAR_BinaryBody(t *testing.T) {
+	t.Parallel()
+
+	body := "\x89PNG\r\n\x1a\n\x00\xff"
+	var buf bytes.Buffer
+	require.NoError(t, WriteHAR(&buf, []Exchange{{
+		URL:      "https://example.com/logo.png",
+		Request:  []byte("GET /logo.png HTTP/1.1\r\nHost: example.com\r\n\r\n"),
+		Response: []byte("HTTP/1.1 200 OK\r\nContent-Type: image/png\r\n\r\n" + body),
+	}}))
+	var har harLog
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &har))
+
+	content := har.Log.Entries[0].Response.Content
+	assert.Equal(t, "base64", content.Encoding)
+	decoded, err := base64.StdEncoding.DecodeString(content.Text)
+	require.NoError(t, err)
+	assert.Equal(t, body, string(decoded))
+}
+
+func TestWriteExchangesJSONL(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteExchangesJSONL(&buf, []Exchange{
+		{
+			Kind:     "replay",
+			ID:       "r1",
+			URL:      "https://example.com/a",
+			Request:  []byte("GET /a HTTP/1.1\r\nHost: example.com\r\n\r\n"),
+			Response: []byte("HTTP/1.1 404 Not Found\r\n\r\n<p>missing</p>"),
+			Started:  time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+			Duration: 42 * time.Millisecond,
+		},
+		{
+			Kind:     "flow",
+			ID:       "f1",
+			URL:      "https://example.com/b",
+			Request:  []byte("GET /b HTTP/1.1\r\n\r\n"),
+			Response: []byte("HTTP/1.1 200 OK\r\n\r\n\xff\xfe"),
+		},
+	}))
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 2)
+	assert.Contains(t, lines[0], `<p>missing</p>`) // HTML is not escaped
+
+	var first jsonlExchange
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &first))
+	assert.Equal(t, "GET", first.Method)
+	assert.Equal(t, 404, first.Status)
+	assert.Equal(t, int64(42), first.DurationMs)
+	assert.Equal(t, "2026-01-02T03:04:05Z", first.Started)
+	assert.Empty(t, first.ResponseEncoding)
+
+	var second jsonlExchange
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), &second))
+	assert.Equal(t, "base64", second.ResponseEncoding)
+	assert.Empty(t, second.RequestEncoding)
+	assert.Empty(t, second.Started)
+}
+
+func TestReadHAR(t *testing.T) {
+	t.Parallel()
+
+	har := `{"log":{"version":"1.2","entries":[
+		{"startedDateTime":"2026-01-02T03:04:05.250+01:00","time":12.5,
+		 "request":{"method":"POST","url":"https://api.example.com/login?next=%2Fhome","httpVersion":"h2",
+		  "headers":[{"name":":authority","value":"api.example.com"},{"name":"content-type","value":"application/json"},{"name":"content-length","value":"99"}],
+		  "postData":{"mimeType":"application/json","text":"{\"u\":\"a\"}"}},
+		 "response":{"status":200,"statusText":"","httpVersion":"h2",
+		  "headers":[{"name":"content-encoding","value":"gzip"},{"name":"content-type","value":"application/json"}],
+		  "content":{"size":11,"mimeType":"application/json","text":"eyJvayI6MX0=","encoding":"base64"}}},
+		{"startedDateTime":"2026-01-02T03:04:06Z","time":0,
+		 "request":{"method":"POST","url":"http://example.com/form","httpVersion":"HTTP/1.0","headers":[{"name":"Host","value":"example.com"}],
+		  "postData":{"mimeType":"application/x-www-form-urlencoded","params":[{"name":"a b","value":"1&2"}]}},
+		 "response":{"status":0,"statusText":"","httpVersion":"","headers":[],"content":{"size":0,"mimeType":""}}},
+		{"startedDateTime":"2026-01-02T03:04:07Z","time":0,
+		 "request":{"method":"GET","url":"data:image/png;base64,AAAA","httpVersion":"","headers":[]},
+		 "response":{"status":200,"statusText":"OK","httpVersion":"","headers":[],"content":{"size":3,"mimeType":"image/png"}}}
+	]}}`
+
+	exchanges, skipped, err := ReadHAR(strings.NewReader(har))
+	require.NoError(t, err)
+	assert.Equal(t, 1, skipped)
+	require.Len(t, exchanges, 2)
+
+	first := exchanges[0]
+	assert.Equal(t, "https://api.example.com/login?next=%2Fhome", first.URL)
+	assert.Equal(t, time.Date(2026, 1, 2, 2, 4, 5, 250_000_000, time.UTC), first.Started.UTC())
+	assert.Equal(t, 12500*time.Microsecond, first.Duration)
+	assert.Equal(t, "POST /login?next=%2Fhome HTTP/1.1\r\nHost: api.example.com\r\ncontent-type: application/json\r\nContent-Length: 9\r\n\r\n{\"u\":\"a\"}", string(first.Request))
+	assert.Equal(t, "HTTP/1.1 200 OK\r\ncontent-type: application/json\r\nContent-Length: 8\r\n\r\n{\"ok\":1}", string(first.Response))
+
+	second := exchanges[1]
+	assert.Equal(t, "POST /form HTTP/1.0\r\nHost: example.com\r\nContent-Length: 9\r\n\r\na+b=1%262", string(second.Request))
+	assert.Empty(t, second.Response)
+
+	t.Run("round_trip", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, WriteHAR(&buf, exchanges))
+		again, skipped, err := ReadHAR(&buf)
+		require.NoError(t, err)
+		assert.Zero(t, skipped)
+		require.Len(t, again, 2)
+		assert.Equal(t, string(first.Request), string(again[0].Request))
+		assert.Equal(t, string(first.Response), string(again[0].Response))
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		_, _, err := ReadHAR(strings.NewReader(`{"entries":[]}`))
+		require.Error(t, err)
+		_, _, err = ReadHAR(strings.NewReader(`not json`))
+		require.Error(t, err)
+	})
+}
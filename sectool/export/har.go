@@ -0,0 +1,220 @@
+package export
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/go-harden/llm-security-toolbox/sectool/bundle"
+	"github.com/go-harden/llm-security-toolbox/sectool/config"
+)
+
+// HAR 1.2 (http://www.softwareishard.com/blog/har-12-spec/), limited to the fields
+// sectool can fill from raw messages.
+type harLog struct {
+	Log harBody `json:"log"`
+}
+
+type harBody struct {
+	Version string     `json:"version"`
+	Creator harCreator `json:"creator"`
+	Entries []harEntry `json:"entries"`
+}
+
+type harCreator struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+type harEntry struct {
+	StartedDateTime string      `json:"startedDateTime"`
+	Time            float64     `json:"time"`
+	Request         harRequest  `json:"request"`
+	Response        harResponse `json:"response"`
+	Cache           struct{}    `json:"cache"`
+	Timings         harTimings  `json:"timings"`
+	Comment         string      `json:"comment,omitempty"`
+}
+
+type harRequest struct {
+	Method      string       `json:"method"`
+	URL         string       `json:"url"`
+	HTTPVersion string       `json:"httpVersion"`
+	Cookies     []harCookie  `json:"cookies"`
+	Headers     []header     `json:"headers"`
+	QueryString []header     `json:"queryString"`
+	PostData    *harPostData `json:"postData,omitempty"`
+	HeadersSize int          `json:"headersSize"`
+	BodySize    int          `json:"bodySize"`
+}
+
+type harResponse struct {
+	Status      int         `json:"status"`
+	StatusText  string      `json:"statusText"`
+	HTTPVersion string      `json:"httpVersion"`
+	Cookies     []harCookie `json:"cookies"`
+	Headers     []header    `json:"headers"`
+	Content     harContent  `json:"content"`
+	RedirectURL string      `json:"redirectURL"`
+	HeadersSize int         `json:"headersSize"`
+	BodySize    int         `json:"bodySize"`
+}
+
+type harCookie struct {
+	Name     string `json:"name"`
+	Value    string `json:"value"`
+	Path     string `json:"path,omitempty"`
+	Domain   string `json:"domain,omitempty"`
+	Expires  string `json:"expires,omitempty"`
+	HTTPOnly bool   `json:"httpOnly,omitempty"`
+	Secure   bool   `json:"secure,omitempty"`
+}
+
+type harPostData struct {
+	MimeType string   `json:"mimeType"`
+	Text     string   `json:"text"`
+	Params   []header `json:"params,omitempty"` // read only; some browsers send forms as params
+	Comment  string   `json:"comment,omitempty"`
+}
+
+type harContent struct {
+	Size     int    `json:"size"`
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text,omitempty"`
+	Encoding string `json:"encoding,omitempty"`
+}
+
+type harTimings struct {
+	Send    float64 `json:"send"`
+	Wait    float64 `json:"wait"`
+	Receive float64 `json:"receive"`
+}
+
+// WriteHAR writes exchanges as a HAR 1.2 log. Response bodies are stored decoded
+// (chunked and gzip/deflate/br removed); binary bodies are base64 encoded.
+func WriteHAR(w io.Writer, exchanges []Exchange) error {
+	log := harLog{Log: harBody{
+		Version: "1.2",
+		Creator: harCreator{Name: "sectool", Version: config.Version},
+		Entries: make([]harEntry, 0, len(exchanges)),
+	}}
+	for _, ex := range exchanges {
+		log.Log.Entries = append(log.Log.Entries, harEntryFor(ex))
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.SetEscapeHTML(false)
+	return enc.Encode(log)
+}
+
+func harEntryFor(ex Exchange) harEntry {
+	ms := float64(ex.Duration.Microseconds()) / 1000
+	return harEntry{
+		StartedDateTime: ex.Started.UTC().Format(time.RFC3339Nano),
+		Time:            ms,
+		Request:         harRequestFor(ex),
+		Response:        harResponseFor(ex.Response),
+		Timings:         harTimings{Wait: ms},
+		Comment:         ex.Kind + " " + ex.ID,
+	}
+}
+
+func harRequestFor(ex Exchange) harRequest {
+	line, headers, body := parseMessage(ex.Request)
+	req := harRequest{
+		Method:      field(line, 0),
+		URL:         ex.URL,
+		HTTPVersion: field(line, 2),
+		Cookies:     []harCookie{},
+		Headers:     nonNil(headers),
+		QueryString: []header{},
+		HeadersSize: len(ex.Request) - len(body),
+		BodySize:    len(body),
+	}
+	if u, err := url.Parse(ex.URL); err == nil && u.RawQuery != "" {
+		for _, pair := range strings.Split(u.RawQuery, "&") {
+			name, value, _ := strings.Cut(pair, "=")
+			name, _ = url.QueryUnescape(name)
+			value, _ = url.QueryUnescape(value)
+			req.QueryString = append(req.QueryString, header{Name: name, Value: value})
+		}
+	}
+	if cookie := headerValue(headers, "Cookie"); cookie != "" {
+		if cookies, err := http.ParseCookie(cookie); err == nil {
+			for _, c := range cookies {
+				req.Cookies = append(req.Cookies, harCookie{Name: c.Name, Value: c.Value})
+			}
+		}
+	}
+	if len(body) > 0 {
+		req.PostData = &harPostData{MimeType: headerValue(headers, "Content-Type")}
+		if utf8.Valid(body) {
+			req.PostData.Text = string(body)
+		} else {
+			req.PostData.Text = base64.StdEncoding.EncodeToString(body)
+			req.PostData.Comment = "base64"
+		}
+	}
+	return req
+}
+
+func harResponseFor(raw []byte) harResponse {
+	resp := harResponse{
+		Cookies:  []harCookie{},
+		Headers:  []header{},
+		BodySize: -1,
+	}
+	if len(raw) == 0 {
+		resp.HeadersSize = -1
+		return resp
+	}
+
+	line, headers, body := parseMessage(raw)
+	resp.HTTPVersion = field(line, 0)
+	resp.Status = statusCode(line)
+	resp.StatusText = field(line, 2)
+	resp.Headers = nonNil(headers)
+	resp.RedirectURL = headerValue(headers, "Location")
+	resp.HeadersSize = len(raw) - len(body)
+	resp.BodySize = len(body)
+	for _, h := range headers {
+		if !strings.EqualFold(h.Name, "Set-Cookie") {
+			continue
+		}
+		if c, err := http.ParseSetCookie(h.Value); err == nil {
+			cookie := harCookie{Name: c.Name, Value: c.Value, Path: c.Path, Domain: c.Domain, HTTPOnly: c.HttpOnly, Secure: c.Secure}
+			if !c.Expires.IsZero() {
+				cookie.Expires = c.Expires.UTC().Format(time.RFC3339)
+			}
+			resp.Cookies = append(resp.Cookies, cookie)
+		}
+	}
+
+	head := raw[:len(raw)-len(body)]
+	decoded, _ := bundle.DecodeBody(string(head), body)
+	resp.Content = harContent{Size: len(decoded), MimeType: headerValue(headers, "Content-Type")}
+	resp.Content.Text, resp.Content.Encoding = encodeText(decoded)
+	return resp
+}
+
+// encodeText returns data as a string, base64 encoded with encoding "base64" when it
+// is not valid UTF-8.
+func encodeText(data []byte) (text, encoding string) {
+	if utf8.Valid(data) {
+		return string(data), ""
+	}
+	return base64.StdEncoding.EncodeToString(data), "base64"
+}
+
+func nonNil(headers []header) []header {
+	if headers == nil {
+		return []header{}
+	}
+	return headers
+}
@@ -0,0 +1,149 @@
+package export
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ReadHAR parses a HAR log, such as a browser DevTools export, into exchanges with
+// rebuilt HTTP/1.1 messages. Entries whose URL is not http(s) (data:, ws:, ...) are
+// skipped and counted.
+//
+// HAR stores response bodies decoded, so the rebuilt response drops Content-Encoding
+// and Transfer-Encoding and carries a Content-Length matching the stored body.
+func ReadHAR(r io.Reader) (exchanges []Exchange, skipped int, err error) {
+	var log harLog
+	if err := json.NewDecoder(r).Decode(&log); err != nil {
+		return nil, 0, fmt.Errorf("parse HAR: %w", err)
+	} else if log.Log.Entries == nil {
+		return nil, 0, errors.New("parse HAR: no log.entries")
+	}
+
+	for i, entry := range log.Log.Entries {
+		u, err := url.Parse(entry.Request.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			skipped++
+			continue
+		}
+		req, err := harRebuildRequest(entry.Request, u)
+		if err != nil {
+			return nil, 0, fmt.Errorf("entry %d: %w", i, err)
+		}
+		resp, err := harRebuildResponse(entry.Response)
+		if err != nil {
+			return nil, 0, fmt.Errorf("entry %d: %w", i, err)
+		}
+		started, _ := time.Parse(time.RFC3339Nano, entry.StartedDateTime)
+		exchanges = append(exchanges, Exchange{
+			URL:      u.String(),
+			Request:  req,
+			Response: resp,
+			Started:  started,
+			Duration: time.Duration(entry.Time * float64(time.Millisecond)),
+		})
+	}
+	return exchanges, skipped, nil
+}
+
+func harRebuildRequest(r harRequest, u *url.URL) ([]byte, error) {
+	var body []byte
+	if r.PostData != nil {
+		switch {
+		case r.PostData.Comment == "base64": // written by WriteHAR for binary bodies
+			data, err := base64.StdEncoding.DecodeString(r.PostData.Text)
+			if err != nil {
+				return nil, fmt.Errorf("decode request body: %w", err)
+			}
+			body = data
+		case r.PostData.Text != "":
+			body = []byte(r.PostData.Text)
+		case len(r.PostData.Params) > 0:
+			form := make([]string, 0, len(r.PostData.Params))
+			for _, p := range r.PostData.Params {
+				form = append(form, url.QueryEscape(p.Name)+"="+url.QueryEscape(p.Value))
+			}
+			body = []byte(strings.Join(form, "&"))
+		}
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(r.Method + " " + u.RequestURI() + " " + http1Version(r.HTTPVersion) + "\r\n")
+	if !hasHeader(r.Headers, "Host") {
+		buf.WriteString("Host: " + u.Host + "\r\n")
+	}
+	writeHARHeaders(&buf, r.Headers)
+	if len(body) > 0 {
+		buf.WriteString("Content-Length: " + strconv.Itoa(len(body)) + "\r\n")
+	}
+	buf.WriteString("\r\n")
+	buf.Write(body)
+	return buf.Bytes(), nil
+}
+
+// harRebuildResponse returns nil for entries without a response (status 0).
+func harRebuildResponse(r harResponse) ([]byte, error) {
+	if r.Status == 0 {
+		return nil, nil
+	}
+	body := []byte(r.Content.Text)
+	if r.Content.Encoding == "base64" {
+		data, err := base64.StdEncoding.DecodeString(r.Content.Text)
+		if err != nil {
+			return nil, fmt.Errorf("decode response body: %w", err)
+		}
+		body = data
+	}
+	statusText := r.StatusText
+	if statusText == "" {
+		statusText = http.StatusText(r.Status)
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(http1Version(r.HTTPVersion) + " " + strconv.Itoa(r.Status) + " " + statusText + "\r\n")
+	writeHARHeaders(&buf, r.Headers)
+	buf.WriteString("Content-Length: " + strconv.Itoa(len(body)) + "\r\n\r\n")
+	buf.Write(body)
+	return buf.Bytes(), nil
+}
+
+// writeHARHeaders writes headers as HTTP/1.1 header lines, leaving out HTTP/2
+// pseudo-headers and the framing headers the rebuilt message sets itself.
+func writeHARHeaders(buf *bytes.Buffer, headers []header) {
+	for _, h := range headers {
+		switch {
+		case strings.HasPrefix(h.Name, ":"),
+			strings.EqualFold(h.Name, "Content-Length"),
+			strings.EqualFold(h.Name, "Content-Encoding"),
+			strings.EqualFold(h.Name, "Transfer-Encoding"):
+			continue
+		}
+		buf.WriteString(h.Name + ": " + h.Value + "\r\n")
+	}
+}
+
+func hasHeader(headers []header, name string) bool {
+	for _, h := range headers {
+		if strings.EqualFold(h.Name, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// http1Version keeps HTTP/1.0 and maps everything else (h2, h3, HTTP/2.0, empty)
+// to HTTP/1.1, the only versions sectool replays.
+func http1Version(v string) string {
+	if strings.EqualFold(v, "HTTP/1.0") {
+		return "HTTP/1.0"
+	}
+	return "HTTP/1.1"
+}
@@ -0,0 +1,46 @@
+package exportcli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/go-harden/llm-security-toolbox/sectool/cliutil"
+	"github.com/go-harden/llm-security-toolbox/sectool/mcpclient"
+)
+
+func run(mcpURL string, timeout time.Duration, opts mcpclient.ExportOpts, output string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	client, err := mcpclient.Connect(ctx, mcpURL)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = client.Close() }()
+
+	resp, err := client.Export(ctx, opts)
+	if err != nil {
+		return fmt.Errorf("export %s failed: %w", opts.Source, err)
+	}
+
+	path := resp.Path
+	if output != "" {
+		// The service writes in its project directory; copy from there
+		data, err := os.ReadFile(resp.Path)
+		if err != nil {
+			return fmt.Errorf("read export: %w", err)
+		}
+		if err := os.WriteFile(output, data, 0644); err != nil {
+			return err
+		}
+		path = output
+	}
+
+	fmt.Printf("Exported %d %s entries as %s to `%s` (%d bytes)\n", resp.Count, resp.Source, resp.Format, path, resp.Bytes)
+	if resp.Count == 0 {
+		cliutil.Hintf("\nNothing matched; check the filters or `sectool proxy summary`.\n")
+	}
+	return nil
+}
@@ -0,0 +1,191 @@
+package exportcli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/pflag"
+
+	"github.com/go-harden/llm-security-toolbox/sectool/cli"
+	"github.com/go-harden/llm-security-toolbox/sectool/export"
+	"github.com/go-harden/llm-security-toolbox/sectool/mcpclient"
+)
+
+var exportSubcommands = []string{"proxy", "replay", "findings", "help"}
+
+func Parse(args []string, mcpURL string) error {
+	if len(args) < 1 {
+		printUsage()
+		return errors.New("subcommand required")
+	}
+
+	switch args[0] {
+	case "proxy":
+		return parseProxy(args[1:], mcpURL)
+	case "replay":
+		return parseReplay(args[1:], mcpURL)
+	case "findings":
+		return parseFindings(args[1:], mcpURL)
+	case "help", "--help", "-h":
+		printUsage()
+		return nil
+	default:
+		return cli.UnknownSubcommandError("export", args[0], exportSubcommands)
+	}
+}
+
+func printUsage() {
+	_, _ = fmt.Fprint(os.Stderr, `Usage: sectool export <command> [options]
+
+Serialize results for CI systems and other security tools. The service
+writes each export to a new file under .sectool/exports/; -o also copies it
+to another path.
+
+---
+
+export proxy [options]
+
+  Export proxy history as HAR 1.2 or JSON Lines. Takes the filters of
+  'sectool proxy list'; only in-scope flows when a project scope is defined.
+
+  Options:
+    --format <f>           har (default) or jsonl
+    --host, --path, --method, --status, --contains, --contains-body,
+    --since, --exclude-host, --exclude-path
+                           proxy history filters
+    --limit <n>            first n matching flows
+    -o, --output <path>    also copy the export to path
+
+  Examples:
+    sectool export proxy --host api.example.com -o api.har
+    sectool export proxy --status 5XX --format jsonl
+
+---
+
+export replay [options]
+
+  Export replay results, newest first, as HAR 1.2 or JSON Lines.
+
+  Options:
+    --format <f>           har (default) or jsonl
+    --limit <n>            newest n replays
+    -o, --output <path>    also copy the export to path
+
+---
+
+export findings [options]
+
+  Export recorded findings as SARIF 2.1.0 (for code scanning dashboards)
+  or JSON Lines.
+
+  Options:
+    --format <f>           sarif (default) or jsonl
+    --severity <level>     minimum severity: info, low, medium, high, critical
+    --status <s>           only draft or confirmed findings
+    --limit <n>            most severe n findings
+    -o, --output <path>    also copy the export to path
+
+  Examples:
+    sectool export findings --status confirmed -o sectool.sarif
+`)
+}
+
+func parseProxy(args []string, mcpURL string) error {
+	fs := pflag.NewFlagSet("export proxy", pflag.ContinueOnError)
+	fs.SetInterspersed(true)
+	var timeout time.Duration
+	var output string
+	opts := mcpclient.ExportOpts{Source: "proxy"}
+
+	fs.DurationVar(&timeout, "timeout", 2*time.Minute, "client-side timeout")
+	fs.StringVar(&opts.Format, "format", export.FormatHAR, "export format: har, jsonl")
+	fs.StringVar(&opts.Host, "host", "", "filter by host pattern (glob: *, ?)")
+	fs.StringVar(&opts.Path, "path", "", "filter by path pattern (glob: *, ?)")
+	fs.StringVar(&opts.Method, "method", "", "filter by HTTP method (comma-separated)")
+	fs.StringVar(&opts.Status, "status", "", "filter by status code (e.g., 200,4XX)")
+	fs.StringVar(&opts.Contains, "contains", "", "search in URL and headers")
+	fs.StringVar(&opts.ContainsBody, "contains-body", "", "search in request/response body")
+	fs.StringVar(&opts.Since, "since", "", "filter since flow_id")
+	fs.StringVar(&opts.ExcludeHost, "exclude-host", "", "exclude hosts matching pattern")
+	fs.StringVar(&opts.ExcludePath, "exclude-path", "", "exclude paths matching pattern")
+	fs.IntVar(&opts.Limit, "limit", 0, "maximum number of flows to export")
+	fs.StringVarP(&output, "output", "o", "", "also copy the export to this path")
+
+	fs.Usage = func() {
+		_, _ = fmt.Fprint(os.Stderr, `Usage: sectool export proxy [options]
+
+Export proxy history as HAR 1.2 or JSON Lines.
+
+Options:
+`)
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	return run(mcpURL, timeout, opts, output)
+}
+
+func parseReplay(args []string, mcpURL string) error {
+	fs := pflag.NewFlagSet("export replay", pflag.ContinueOnError)
+	fs.SetInterspersed(true)
+	var timeout time.Duration
+	var output string
+	opts := mcpclient.ExportOpts{Source: "replay"}
+
+	fs.DurationVar(&timeout, "timeout", time.Minute, "client-side timeout")
+	fs.StringVar(&opts.Format, "format", export.FormatHAR, "export format: har, jsonl")
+	fs.IntVar(&opts.Limit, "limit", 0, "newest n replays")
+	fs.StringVarP(&output, "output", "o", "", "also copy the export to this path")
+
+	fs.Usage = func() {
+		_, _ = fmt.Fprint(os.Stderr, `Usage: sectool export replay [options]
+
+Export replay results as HAR 1.2 or JSON Lines.
+
+Options:
+`)
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	return run(mcpURL, timeout, opts, output)
+}
+
+func parseFindings(args []string, mcpURL string) error {
+	fs := pflag.NewFlagSet("export findings", pflag.ContinueOnError)
+	fs.SetInterspersed(true)
+	var timeout time.Duration
+	var output string
+	opts := mcpclient.ExportOpts{Source: "findings"}
+
+	fs.DurationVar(&timeout, "timeout", time.Minute, "client-side timeout")
+	fs.StringVar(&opts.Format, "format", export.FormatSARIF, "export format: sarif, jsonl")
+	fs.StringVar(&opts.Severity, "severity", "", "minimum severity: info, low, medium, high, critical")
+	fs.StringVar(&opts.FindingStatus, "status", "", "only findings with this status: draft, confirmed")
+	fs.IntVar(&opts.Limit, "limit", 0, "most severe n findings")
+	fs.StringVarP(&output, "output", "o", "", "also copy the export to this path")
+
+	fs.Usage = func() {
+		_, _ = fmt.Fprint(os.Stderr, `Usage: sectool export findings [options]
+
+Export recorded findings as SARIF 2.1.0 or JSON Lines.
+
+Options:
+`)
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	return run(mcpURL, timeout, opts, output)
+}
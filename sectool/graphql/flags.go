@@ -0,0 +1,107 @@
+package graphql
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/pflag"
+
+	"github.com/go-harden/llm-security-toolbox/sectool/cli"
+)
+
+var graphqlSubcommands = []string{"introspect", "help"}
+
+func Parse(args []string, mcpURL string) error {
+	if len(args) < 1 {
+		printUsage()
+		return errors.New("subcommand required")
+	}
+
+	switch args[0] {
+	case "introspect":
+		return parseIntrospect(args[1:], mcpURL)
+	case "help", "--help", "-h":
+		printUsage()
+		return nil
+	default:
+		return cli.UnknownSubcommandError("graphql", args[0], graphqlSubcommands)
+	}
+}
+
+func printUsage() {
+	_, _ = fmt.Fprint(os.Stderr, `Usage: sectool graphql <command> [options]
+
+Enumerate GraphQL schemas and generate operation templates.
+
+---
+
+graphql introspect <url> [options]
+
+  Send an introspection query to the endpoint (or parse --sdl when
+  introspection is disabled), cache the schema, and create a request template
+  with example variables for each query and mutation. Send a template with
+  'sectool replay send --flow <flow_id>'. Later calls use the cached schema.
+
+  Options:
+    --flow <id>            take the endpoint and headers from a captured request
+    --sdl <file>           parse this schema file instead of introspecting
+    -H, --header <h>       header "Name: Value" (repeatable)
+    --auth-profile <name>  auth profile from config for introspection
+    --type <name>          show one type's fields, enum values or members
+    --refresh              re-run introspection instead of using the cache
+    --depth <n>            selection set depth of templates (default: 2)
+
+  Output: Markdown tables of types and operations, or one type's fields
+
+  Examples:
+    sectool graphql introspect https://example.com/graphql -H "Authorization: Bearer ..."
+    sectool graphql introspect --flow f7k2x
+    sectool graphql introspect https://example.com/graphql --sdl schema.graphql
+    sectool graphql introspect https://example.com/graphql --type User
+`)
+}
+
+func parseIntrospect(args []string, mcpURL string) error {
+	fs := pflag.NewFlagSet("graphql introspect", pflag.ContinueOnError)
+	fs.SetInterspersed(true)
+	var timeout time.Duration
+	var opts introspectOptions
+
+	fs.DurationVar(&timeout, "timeout", time.Minute, "client-side timeout")
+	fs.StringVar(&opts.flow, "flow", "", "captured request to take the endpoint and headers from")
+	fs.StringVar(&opts.sdlFile, "sdl", "", "schema file to parse instead of introspecting")
+	fs.StringArrayVarP(&opts.headers, "header", "H", nil, "header \"Name: Value\" (repeatable)")
+	fs.StringVar(&opts.authProfile, "auth-profile", "", "auth profile from config for introspection")
+	fs.StringVar(&opts.typeName, "type", "", "show one type's definition")
+	fs.BoolVar(&opts.refresh, "refresh", false, "re-run introspection instead of using the cache")
+	fs.IntVar(&opts.depth, "depth", 0, "selection set depth of templates (default: 2)")
+
+	fs.Usage = func() {
+		_, _ = fmt.Fprint(os.Stderr, `Usage: sectool graphql introspect <url> [options]
+
+Load a GraphQL schema and generate operation templates.
+
+Options:
+`)
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	switch {
+	case fs.NArg() > 1:
+		fs.Usage()
+		return errors.New("at most one endpoint URL is allowed")
+	case fs.NArg() == 1 && opts.flow != "":
+		return errors.New("set either an endpoint URL or --flow, not both")
+	case fs.NArg() == 0 && opts.flow == "":
+		fs.Usage()
+		return errors.New("endpoint URL or --flow is required")
+	}
+	opts.url = fs.Arg(0)
+
+	return introspect(mcpURL, timeout, opts)
+}
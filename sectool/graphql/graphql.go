@@ -0,0 +1,149 @@
+package graphql
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/go-harden/llm-security-toolbox/sectool/cliutil"
+	"github.com/go-harden/llm-security-toolbox/sectool/mcpclient"
+	"github.com/go-harden/llm-security-toolbox/sectool/protocol"
+)
+
+type introspectOptions struct {
+	url         string
+	flow        string
+	sdlFile     string
+	headers     []string
+	authProfile string
+	typeName    string
+	refresh     bool
+	depth       int
+}
+
+func introspect(mcpURL string, timeout time.Duration, opts introspectOptions) error {
+	clientOpts := mcpclient.GraphQLIntrospectOpts{
+		URL:         opts.url,
+		FlowID:      opts.flow,
+		AuthProfile: opts.authProfile,
+		Type:        opts.typeName,
+		Refresh:     opts.refresh,
+		Depth:       opts.depth,
+	}
+	if len(opts.headers) > 0 {
+		clientOpts.Headers = make(map[string]string, len(opts.headers))
+		for _, h := range opts.headers {
+			name, value, ok := strings.Cut(h, ":")
+			if !ok || strings.TrimSpace(name) == "" {
+				return fmt.Errorf("invalid header %q: expected \"Name: Value\"", h)
+			}
+			clientOpts.Headers[strings.TrimSpace(name)] = strings.TrimSpace(value)
+		}
+	}
+	if opts.sdlFile != "" {
+		// Read locally so relative paths work regardless of the service working directory
+		data, err := os.ReadFile(opts.sdlFile)
+		if err != nil {
+			return fmt.Errorf("read SDL: %w", err)
+		}
+		clientOpts.SDL = string(data)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	client, err := mcpclient.Connect(ctx, mcpURL)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = client.Close() }()
+
+	resp, err := client.GraphQLIntrospect(ctx, clientOpts)
+	if err != nil {
+		return fmt.Errorf("graphql introspect failed: %w", err)
+	}
+
+	defer cliutil.StartPager()()
+	if resp.Type != nil {
+		printType(resp.Type)
+		return nil
+	}
+
+	fmt.Printf("## GraphQL schema: `%s`\n\nSource: %s", resp.Endpoint, resp.Source)
+	if resp.Cached {
+		fmt.Print(" (cached)")
+	}
+	if resp.ReplayID != "" {
+		fmt.Printf(", replay `%s`", resp.ReplayID)
+	}
+	fmt.Println()
+	fmt.Printf("\nRoots: query=%s mutation=%s subscription=%s\n\n",
+		orDash(resp.QueryType), orDash(resp.MutationType), orDash(resp.SubscriptionType))
+
+	if len(resp.Types) > 0 {
+		fmt.Println("| type | kind | fields |")
+		fmt.Println("|------|------|--------|")
+		for _, t := range resp.Types {
+			fmt.Printf("| %s | %s | %d |\n", cliutil.EscapeMarkdown(t.Name), t.Kind, t.Fields)
+		}
+		fmt.Println()
+	}
+	if len(resp.Operations) == 0 {
+		fmt.Println("No operations found.")
+		return nil
+	}
+	fmt.Println("| flow_id | kind | name | args | returns |")
+	fmt.Println("|---------|------|------|------|---------|")
+	for _, op := range resp.Operations {
+		fmt.Printf("| %s | %s | %s | %s | %s |\n", op.FlowID, op.Kind, cliutil.EscapeMarkdown(op.Name),
+			cliutil.EscapeMarkdown(strings.Join(op.Args, ", ")), cliutil.EscapeMarkdown(op.ReturnType))
+	}
+	fmt.Printf("\n*%d type(s), %d operation(s)*\n", len(resp.Types), len(resp.Operations))
+	cliutil.Hintf("\nTo send one: `sectool replay send --flow %s`\n", resp.Operations[0].FlowID)
+	cliutil.Hintf("To inspect a type: `sectool graphql introspect %s --type <name>`\n", resp.Endpoint)
+	return nil
+}
+
+func printType(t *protocol.GraphQLType) {
+	fmt.Printf("## %s (%s)\n\n", cliutil.EscapeMarkdown(t.Name), t.Kind)
+	if t.Description != "" {
+		fmt.Printf("%s\n\n", cliutil.EscapeMarkdown(t.Description))
+	}
+	if len(t.Interfaces) > 0 {
+		fmt.Printf("Implements: %s\n\n", strings.Join(t.Interfaces, ", "))
+	}
+	if len(t.PossibleTypes) > 0 {
+		fmt.Printf("Members: %s\n\n", strings.Join(t.PossibleTypes, " | "))
+	}
+	if len(t.EnumValues) > 0 {
+		fmt.Printf("Values: %s\n\n", strings.Join(t.EnumValues, ", "))
+	}
+	if len(t.Fields) == 0 {
+		return
+	}
+	fmt.Println("| field | type | args | notes |")
+	fmt.Println("|-------|------|------|-------|")
+	for _, f := range t.Fields {
+		var notes []string
+		if f.DefaultValue != "" {
+			notes = append(notes, "default "+f.DefaultValue)
+		}
+		if f.Deprecated {
+			notes = append(notes, "deprecated")
+		}
+		if f.Description != "" {
+			notes = append(notes, f.Description)
+		}
+		fmt.Printf("| %s | %s | %s | %s |\n", cliutil.EscapeMarkdown(f.Name), cliutil.EscapeMarkdown(f.Type),
+			cliutil.EscapeMarkdown(strings.Join(f.Args, ", ")), cliutil.EscapeMarkdown(strings.Join(notes, "; ")))
+	}
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
@@ -0,0 +1,165 @@
+// Package grpc decodes and re-encodes gRPC and gRPC-Web messages: length-prefixed
+// framing, protobuf payloads described by .proto files or descriptor sets, and a
+// schema-less rendering of the wire format for calls without a schema.
+package grpc
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Frame flags; a gRPC-Web response carries its trailers in a final frame.
+const (
+	flagCompressed = 0x01
+	flagTrailer    = 0x80
+)
+
+// maxFrameSize bounds a single decoded message.
+const maxFrameSize = 64 << 20
+
+// Frame is one length-prefixed message.
+type Frame struct {
+	Compressed bool
+	Trailer    bool // gRPC-Web trailer frame, Data holds "name:value" lines
+	Data       []byte
+}
+
+// Protocol variants, from the Content-Type.
+const (
+	ProtocolGRPC    = "grpc"
+	ProtocolWeb     = "grpc-web"
+	ProtocolWebText = "grpc-web-text" // base64 encoded frames
+)
+
+// ContentProtocol returns the protocol variant of a gRPC Content-Type, or "" when
+// contentType is not gRPC.
+func ContentProtocol(contentType string) string {
+	ct, _, _ := strings.Cut(strings.ToLower(strings.TrimSpace(contentType)), ";")
+	ct = strings.TrimSpace(ct)
+	switch {
+	case strings.HasPrefix(ct, "application/grpc-web-text"):
+		return ProtocolWebText
+	case strings.HasPrefix(ct, "application/grpc-web"):
+		return ProtocolWeb
+	case ct == "application/grpc" || strings.HasPrefix(ct, "application/grpc+"):
+		return ProtocolGRPC
+	}
+	return ""
+}
+
+// ParseFrames splits a message body into frames. For grpc-web-text the body is
+// base64 decoded first; concatenated base64 chunks are accepted.
+func ParseFrames(body []byte, protocol string) ([]Frame, error) {
+	if protocol == ProtocolWebText {
+		decoded, err := decodeWebText(body)
+		if err != nil {
+			return nil, err
+		}
+		body = decoded
+	}
+
+	var frames []Frame
+	for len(body) > 0 {
+		if len(body) < 5 {
+			return frames, fmt.Errorf("truncated frame header (%d bytes)", len(body))
+		}
+		flags := body[0]
+		size := binary.BigEndian.Uint32(body[1:5])
+		if size > maxFrameSize {
+			return frames, fmt.Errorf("frame of %d bytes exceeds limit", size)
+		} else if int(size) > len(body)-5 {
+			return frames, fmt.Errorf("truncated frame: %d of %d bytes", len(body)-5, size)
+		}
+		frames = append(frames, Frame{
+			Compressed: flags&flagCompressed != 0,
+			Trailer:    flags&flagTrailer != 0,
+			Data:       body[5 : 5+size],
+		})
+		body = body[5+size:]
+	}
+	return frames, nil
+}
+
+// EncodeFrames serializes frames, base64 encoding the result for grpc-web-text.
+func EncodeFrames(frames []Frame, protocol string) []byte {
+	var buf bytes.Buffer
+	for _, f := range frames {
+		var flags byte
+		if f.Compressed {
+			flags |= flagCompressed
+		}
+		if f.Trailer {
+			flags |= flagTrailer
+		}
+		buf.WriteByte(flags)
+		_ = binary.Write(&buf, binary.BigEndian, uint32(len(f.Data)))
+		buf.Write(f.Data)
+	}
+	if protocol == ProtocolWebText {
+		return []byte(base64.StdEncoding.EncodeToString(buf.Bytes()))
+	}
+	return buf.Bytes()
+}
+
+// Decompress returns the payload of a compressed frame. Only gzip is supported,
+// the one encoding every gRPC implementation ships.
+func Decompress(data []byte, encoding string) ([]byte, error) {
+	switch strings.ToLower(encoding) {
+	case "gzip":
+		zr, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("gzip: %w", err)
+		}
+		defer func() { _ = zr.Close() }()
+		out, err := io.ReadAll(io.LimitReader(zr, maxFrameSize+1))
+		if err != nil {
+			return nil, fmt.Errorf("gzip: %w", err)
+		} else if len(out) > maxFrameSize {
+			return nil, errors.New("gzip: decompressed message exceeds limit")
+		}
+		return out, nil
+	case "", "identity":
+		return nil, errors.New("frame is flagged compressed but no grpc-encoding is set")
+	}
+	return nil, fmt.Errorf("unsupported grpc-encoding %q", encoding)
+}
+
+// ParseTrailer parses a gRPC-Web trailer frame into lowercased names and values.
+func ParseTrailer(data []byte) map[string]string {
+	out := make(map[string]string)
+	for _, line := range strings.Split(string(data), "\n") {
+		name, value, ok := strings.Cut(strings.TrimRight(line, "\r"), ":")
+		if ok {
+			out[strings.ToLower(strings.TrimSpace(name))] = strings.TrimSpace(value)
+		}
+	}
+	return out
+}
+
+func decodeWebText(body []byte) ([]byte, error) {
+	var out []byte
+	text := strings.Join(strings.Fields(string(body)), "")
+	// Each streamed chunk is padded separately, so decode up to every padding run
+	for text != "" {
+		end := len(text)
+		if i := strings.IndexByte(text, '='); i >= 0 {
+			end = i
+			for end < len(text) && text[end] == '=' {
+				end++
+			}
+		}
+		chunk, err := base64.StdEncoding.DecodeString(text[:end])
+		if err != nil {
+			return nil, fmt.Errorf("decode grpc-web-text: %w", err)
+		}
+		out = append(out, chunk...)
+		text = text[end:]
+	}
+	return out, nil
+}
@@ -0,0 +1,241 @@
+package grpc
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+const testProto = `
+syntax = "proto3";
+
+package shop.v1;
+
+import "google/protobuf/timestamp.proto";
+
+option go_package = "example.com/shop;shop";
+
+/* Orders */
+service OrderService {
+  rpc GetOrder (GetOrderRequest) returns (Order);
+  rpc Watch (GetOrderRequest) returns (stream Order) {
+    option deprecated = true;
+  }
+}
+
+message GetOrderRequest {
+  int64 id = 1;
+  optional string tenant = 2 [json_name = "tenantId"];
+}
+
+message Order {
+  enum Status {
+    STATUS_UNSPECIFIED = 0;
+    PAID = 1;
+  }
+  int64 id = 1;
+  string owner = 2;
+  Status status = 3;
+  repeated Item items = 4;
+  map<string, string> labels = 5;
+  oneof payment {
+    string card = 6;
+    string iban = 7;
+  }
+  google.protobuf.Timestamp created = 8;
+  reserved 9, 10;
+
+  message Item {
+    string sku = 1;
+    uint32 qty = 2 [deprecated = true];
+  }
+}
+`
+
+func TestFrames(t *testing.T) {
+	t.Parallel()
+
+	frames := []Frame{{Data: []byte("abc")}, {Trailer: true, Data: []byte("grpc-status:0\r\ngrpc-message:ok\r\n")}}
+
+	t.Run("round_trip", func(t *testing.T) {
+		body := EncodeFrames(frames, ProtocolWeb)
+		assert.Equal(t, []byte{0, 0, 0, 0, 3, 'a', 'b', 'c'}, body[:8])
+		parsed, err := ParseFrames(body, ProtocolWeb)
+		require.NoError(t, err)
+		assert.Equal(t, frames, parsed)
+		assert.Equal(t, map[string]string{"grpc-status": "0", "grpc-message": "ok"}, ParseTrailer(parsed[1].Data))
+	})
+
+	t.Run("web_text", func(t *testing.T) {
+		// Streamed chunks are base64 encoded (and padded) separately
+		first := base64.StdEncoding.EncodeToString(EncodeFrames(frames[:1], ProtocolWeb))
+		second := base64.StdEncoding.EncodeToString(EncodeFrames(frames[1:], ProtocolWeb))
+		parsed, err := ParseFrames([]byte(first+second), ProtocolWebText)
+		require.NoError(t, err)
+		assert.Equal(t, frames, parsed)
+	})
+
+	t.Run("truncated", func(t *testing.T) {
+		_, err := ParseFrames([]byte{0, 0, 0, 0, 9, 'a'}, ProtocolGRPC)
+		assert.Error(t, err)
+	})
+
+	t.Run("gzip", func(t *testing.T) {
+		var buf bytes.Buffer
+		zw := gzip.NewWriter(&buf)
+		_, _ = zw.Write([]byte("payload"))
+		require.NoError(t, zw.Close())
+		out, err := Decompress(buf.Bytes(), "gzip")
+		require.NoError(t, err)
+		assert.Equal(t, "payload", string(out))
+
+		_, err = Decompress(buf.Bytes(), "snappy")
+		assert.Error(t, err)
+	})
+}
+
+func TestContentProtocol(t *testing.T) {
+	t.Parallel()
+
+	for ct, want := range map[string]string{
+		"application/grpc":                 ProtocolGRPC,
+		"application/grpc+proto":           ProtocolGRPC,
+		"application/grpc-web+proto":       ProtocolWeb,
+		"application/grpc-web-text; q=1":   ProtocolWebText,
+		"application/json":                 "",
+		"application/grpcx":                "",
+		" Application/GRPC-Web ; charset=": ProtocolWeb,
+	} {
+		assert.Equal(t, want, ContentProtocol(ct), ct)
+	}
+}
+
+func TestRawRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	var nested []byte
+	nested = protowire.AppendTag(nested, 1, protowire.VarintType)
+	nested = protowire.AppendVarint(nested, 7)
+	var msg []byte
+	msg = protowire.AppendTag(msg, 1, protowire.VarintType)
+	msg = protowire.AppendVarint(msg, 150)
+	msg = protowire.AppendTag(msg, 2, protowire.BytesType)
+	msg = protowire.AppendString(msg, "alice")
+	msg = protowire.AppendTag(msg, 3, protowire.BytesType)
+	msg = protowire.AppendBytes(msg, nested)
+	msg = protowire.AppendTag(msg, 3, protowire.BytesType)
+	msg = protowire.AppendBytes(msg, nested)
+	msg = protowire.AppendTag(msg, 4, protowire.BytesType)
+	msg = protowire.AppendBytes(msg, []byte{0xff, 0x00})
+	msg = protowire.AppendTag(msg, 5, protowire.Fixed32Type)
+	msg = protowire.AppendFixed32(msg, 42)
+	msg = protowire.AppendTag(msg, 6, protowire.VarintType)
+	msg = protowire.AppendVarint(msg, uint64(1<<63))
+
+	decoded, err := DecodeRaw(msg)
+	require.NoError(t, err)
+	rendered, err := json.Marshal(decoded)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"1":150,"2":"alice","3":[{"1":7},{"1":7}],"4":{"bytes":"/wA="},"5":{"fixed32":42},"6":{"varint":"9223372036854775808"}}`, string(rendered))
+
+	// Values come back from JSON (e.g. after set_json edits) as float64
+	var fromJSON map[string]interface{}
+	require.NoError(t, json.Unmarshal(rendered, &fromJSON))
+	encoded, err := EncodeRaw(fromJSON)
+	require.NoError(t, err)
+	assert.Equal(t, msg, encoded)
+
+	t.Run("negative", func(t *testing.T) {
+		encoded, err := EncodeRaw(map[string]interface{}{"1": float64(-1)})
+		require.NoError(t, err)
+		v, n := protowire.ConsumeVarint(encoded[1:])
+		require.Positive(t, n)
+		assert.Equal(t, uint64(1<<64-1), v)
+	})
+
+	t.Run("invalid_key", func(t *testing.T) {
+		_, err := EncodeRaw(map[string]interface{}{"name": "x"})
+		assert.Error(t, err)
+	})
+}
+
+func TestSchemaProto(t *testing.T) {
+	t.Parallel()
+
+	s := NewSchema()
+	require.NoError(t, s.AddProto("shop.proto", testProto))
+	assert.Error(t, s.AddProto("shop.proto", testProto))
+
+	methods := s.Methods()
+	require.Len(t, methods, 2)
+	assert.Equal(t, Method{Path: "/shop.v1.OrderService/GetOrder", Input: "shop.v1.GetOrderRequest", Output: "shop.v1.Order"}, methods[0])
+	assert.True(t, methods[1].ServerStreaming)
+
+	md, ok := s.Method("/shop.v1.OrderService/GetOrder")
+	require.True(t, ok)
+	_, ok = s.Method("/shop.v1.OrderService/Missing")
+	assert.False(t, ok)
+
+	t.Run("encode_decode", func(t *testing.T) {
+		input := `{"id":"42","owner":"bob","status":"PAID","items":[{"sku":"A1","qty":2}],"labels":{"k":"v"},"card":"4111","created":"2024-01-02T03:04:05Z"}`
+		data, err := s.EncodeMessage(md.Output(), []byte(input))
+		require.NoError(t, err)
+		out, err := s.DecodeMessage(md.Output(), data)
+		require.NoError(t, err)
+		assert.JSONEq(t, input, string(out))
+
+		// JSON names are accepted on input; proto3 optional keeps presence
+		data, err = s.EncodeMessage(md.Input(), []byte(`{"id":"1","tenantId":""}`))
+		require.NoError(t, err)
+		out, err = s.DecodeMessage(md.Input(), data)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"id":"1","tenant":""}`, string(out))
+
+		_, err = s.EncodeMessage(md.Input(), []byte(`{"nope":1}`))
+		assert.Error(t, err)
+	})
+
+	t.Run("missing_import", func(t *testing.T) {
+		err := NewSchema().AddProto("a.proto", `syntax = "proto3"; import "b.proto"; message A { B b = 1; }`)
+		assert.Error(t, err)
+	})
+
+	t.Run("syntax_error", func(t *testing.T) {
+		err := NewSchema().AddProto("a.proto", `syntax = "proto3"; message A { string a 1; }`)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "line 1")
+	})
+}
+
+func TestSchemaDescriptorSet(t *testing.T) {
+	t.Parallel()
+
+	// Build a descriptor set the way protoc would, from a parsed file
+	source := NewSchema()
+	require.NoError(t, source.AddProto("shop.proto", testProto))
+	md, _ := source.Method("/shop.v1.OrderService/GetOrder")
+	set := &descriptorpb.FileDescriptorSet{File: []*descriptorpb.FileDescriptorProto{
+		protodesc.ToFileDescriptorProto(md.ParentFile()),
+	}}
+	data, err := proto.Marshal(set)
+	require.NoError(t, err)
+
+	s := NewSchema()
+	added, err := s.AddDescriptorSet(data)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"shop.proto"}, added)
+	_, ok := s.Method("/shop.v1.OrderService/Watch")
+	assert.True(t, ok)
+
+	_, err = s.AddDescriptorSet([]byte("not a descriptor set"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,594 @@
+package grpc
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+var protoScalarTypes = map[string]descriptorpb.FieldDescriptorProto_Type{
+	"double":   descriptorpb.FieldDescriptorProto_TYPE_DOUBLE,
+	"float":    descriptorpb.FieldDescriptorProto_TYPE_FLOAT,
+	"int32":    descriptorpb.FieldDescriptorProto_TYPE_INT32,
+	"int64":    descriptorpb.FieldDescriptorProto_TYPE_INT64,
+	"uint32":   descriptorpb.FieldDescriptorProto_TYPE_UINT32,
+	"uint64":   descriptorpb.FieldDescriptorProto_TYPE_UINT64,
+	"sint32":   descriptorpb.FieldDescriptorProto_TYPE_SINT32,
+	"sint64":   descriptorpb.FieldDescriptorProto_TYPE_SINT64,
+	"fixed32":  descriptorpb.FieldDescriptorProto_TYPE_FIXED32,
+	"fixed64":  descriptorpb.FieldDescriptorProto_TYPE_FIXED64,
+	"sfixed32": descriptorpb.FieldDescriptorProto_TYPE_SFIXED32,
+	"sfixed64": descriptorpb.FieldDescriptorProto_TYPE_SFIXED64,
+	"bool":     descriptorpb.FieldDescriptorProto_TYPE_BOOL,
+	"string":   descriptorpb.FieldDescriptorProto_TYPE_STRING,
+	"bytes":    descriptorpb.FieldDescriptorProto_TYPE_BYTES,
+}
+
+// parseProto parses proto2 or proto3 source into a file descriptor. Messages, enums,
+// oneofs, maps and services are read; options other than json_name and default,
+// reserved ranges, extensions and groups are read past. Message and enum references
+// are left relative for protodesc to resolve.
+func parseProto(name, source string) (*descriptorpb.FileDescriptorProto, error) {
+	tokens, err := tokenizeProto(source)
+	if err != nil {
+		return nil, err
+	}
+	p := &protoParser{tokens: tokens}
+	fd := &descriptorpb.FileDescriptorProto{Name: proto.String(name)}
+
+	for !p.done() {
+		switch tok := p.next(); tok {
+		case ";":
+		case "syntax":
+			if err := p.expect("="); err != nil {
+				return nil, err
+			}
+			syntax := p.stringLit()
+			switch syntax {
+			case "proto3":
+				fd.Syntax = proto.String(syntax)
+			case "proto2":
+			default:
+				return nil, fmt.Errorf("unsupported syntax %q", syntax)
+			}
+			if err := p.expect(";"); err != nil {
+				return nil, err
+			}
+		case "edition":
+			return nil, errors.New("protobuf editions are not supported; use a descriptor set instead")
+		case "package":
+			fd.Package = proto.String(p.next())
+			if err := p.expect(";"); err != nil {
+				return nil, err
+			}
+		case "import":
+			public := p.accept("public")
+			if !public {
+				p.accept("weak")
+			}
+			if public {
+				fd.PublicDependency = append(fd.PublicDependency, int32(len(fd.Dependency)))
+			}
+			fd.Dependency = append(fd.Dependency, p.stringLit())
+			if err := p.expect(";"); err != nil {
+				return nil, err
+			}
+		case "option":
+			p.skipStatement()
+		case "message":
+			msg, err := p.message(fd.GetSyntax() == "proto3")
+			if err != nil {
+				return nil, err
+			}
+			fd.MessageType = append(fd.MessageType, msg)
+		case "enum":
+			enum, err := p.enum()
+			if err != nil {
+				return nil, err
+			}
+			fd.EnumType = append(fd.EnumType, enum)
+		case "service":
+			svc, err := p.service()
+			if err != nil {
+				return nil, err
+			}
+			fd.Service = append(fd.Service, svc)
+		case "extend":
+			p.next()
+			p.skipBlock()
+		default:
+			return nil, p.errorf("unexpected %q", tok)
+		}
+	}
+	return fd, nil
+}
+
+type protoParser struct {
+	tokens []protoToken
+	pos    int
+}
+
+type protoToken struct {
+	text string
+	line int
+	str  bool // string literal, text is unquoted
+}
+
+func (p *protoParser) done() bool {
+	return p.pos >= len(p.tokens)
+}
+
+func (p *protoParser) peek() string {
+	if p.done() {
+		return ""
+	}
+	return p.tokens[p.pos].text
+}
+
+func (p *protoParser) next() string {
+	tok := p.peek()
+	if !p.done() {
+		p.pos++
+	}
+	return tok
+}
+
+func (p *protoParser) accept(tok string) bool {
+	if !p.done() && !p.tokens[p.pos].str && p.tokens[p.pos].text == tok {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *protoParser) expect(tok string) error {
+	if !p.accept(tok) {
+		return p.errorf("expected %q, got %q", tok, p.peek())
+	}
+	return nil
+}
+
+func (p *protoParser) errorf(format string, args ...interface{}) error {
+	line := 0
+	if p.pos < len(p.tokens) {
+		line = p.tokens[p.pos].line
+	} else if len(p.tokens) > 0 {
+		line = p.tokens[len(p.tokens)-1].line
+	}
+	return fmt.Errorf("line %d: %s", line, fmt.Sprintf(format, args...))
+}
+
+// stringLit consumes adjacent string literals, which proto concatenates.
+func (p *protoParser) stringLit() string {
+	var sb strings.Builder
+	for !p.done() && p.tokens[p.pos].str {
+		sb.WriteString(p.next())
+	}
+	return sb.String()
+}
+
+func (p *protoParser) number() (int32, error) {
+	tok := p.next()
+	n, err := strconv.ParseInt(tok, 0, 32)
+	if err != nil {
+		return 0, p.errorf("invalid number %q", tok)
+	}
+	return int32(n), nil
+}
+
+// skipStatement reads past tokens up to and including ";", skipping nested blocks.
+func (p *protoParser) skipStatement() {
+	for !p.done() {
+		switch p.next() {
+		case ";":
+			return
+		case "{":
+			p.pos--
+			p.skipBlock()
+		}
+	}
+}
+
+// skipBlock reads past a balanced {...} block.
+func (p *protoParser) skipBlock() {
+	depth := 0
+	for !p.done() {
+		switch p.next() {
+		case "{":
+			depth++
+		case "}":
+			if depth--; depth <= 0 {
+				return
+			}
+		}
+	}
+}
+
+func (p *protoParser) message(proto3 bool) (*descriptorpb.DescriptorProto, error) {
+	msg := &descriptorpb.DescriptorProto{Name: proto.String(p.next())}
+	if err := p.expect("{"); err != nil {
+		return nil, err
+	}
+	var synthetic []string // proto3 optional fields, whose oneofs follow the real ones
+	var syntheticFields []*descriptorpb.FieldDescriptorProto
+
+	for !p.accept("}") {
+		if p.done() {
+			return nil, p.errorf("unterminated message %s", msg.GetName())
+		}
+		switch p.peek() {
+		case ";":
+			p.next()
+		case "message":
+			p.next()
+			nested, err := p.message(proto3)
+			if err != nil {
+				return nil, err
+			}
+			msg.NestedType = append(msg.NestedType, nested)
+		case "enum":
+			p.next()
+			enum, err := p.enum()
+			if err != nil {
+				return nil, err
+			}
+			msg.EnumType = append(msg.EnumType, enum)
+		case "option", "reserved", "extensions":
+			p.skipStatement()
+		case "extend":
+			p.next()
+			p.next()
+			p.skipBlock()
+		case "oneof":
+			p.next()
+			index := int32(len(msg.OneofDecl))
+			msg.OneofDecl = append(msg.OneofDecl, &descriptorpb.OneofDescriptorProto{Name: proto.String(p.next())})
+			if err := p.expect("{"); err != nil {
+				return nil, err
+			}
+			for !p.accept("}") {
+				if p.done() {
+					return nil, p.errorf("unterminated oneof")
+				}
+				if p.peek() == "option" {
+					p.skipStatement()
+					continue
+				} else if p.accept(";") {
+					continue
+				}
+				field, err := p.field(msg, descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL)
+				if err != nil {
+					return nil, err
+				}
+				field.OneofIndex = proto.Int32(index)
+				msg.Field = append(msg.Field, field)
+			}
+		case "map":
+			p.next()
+			field, err := p.mapField(msg)
+			if err != nil {
+				return nil, err
+			}
+			msg.Field = append(msg.Field, field)
+		case "group":
+			return nil, p.errorf("groups are not supported")
+		default:
+			label := descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL
+			optional := false
+			switch {
+			case p.accept("repeated"):
+				label = descriptorpb.FieldDescriptorProto_LABEL_REPEATED
+			case p.accept("required"):
+				label = descriptorpb.FieldDescriptorProto_LABEL_REQUIRED
+			case p.accept("optional"):
+				optional = true
+			}
+			if p.peek() == "group" {
+				return nil, p.errorf("groups are not supported")
+			}
+			field, err := p.field(msg, label)
+			if err != nil {
+				return nil, err
+			}
+			if optional && proto3 {
+				field.Proto3Optional = proto.Bool(true)
+				synthetic = append(synthetic, "_"+field.GetName())
+				syntheticFields = append(syntheticFields, field)
+			}
+			msg.Field = append(msg.Field, field)
+		}
+	}
+
+	for i, name := range synthetic {
+		syntheticFields[i].OneofIndex = proto.Int32(int32(len(msg.OneofDecl)))
+		msg.OneofDecl = append(msg.OneofDecl, &descriptorpb.OneofDescriptorProto{Name: proto.String(name)})
+	}
+	return msg, nil
+}
+
+// field parses "type name = number [options];".
+func (p *protoParser) field(msg *descriptorpb.DescriptorProto, label descriptorpb.FieldDescriptorProto_Label) (*descriptorpb.FieldDescriptorProto, error) {
+	typeName := p.next()
+	field := &descriptorpb.FieldDescriptorProto{Name: proto.String(p.next()), Label: label.Enum()}
+	setFieldType(field, typeName)
+	if err := p.expect("="); err != nil {
+		return nil, fmt.Errorf("message %s: %w", msg.GetName(), err)
+	}
+	num, err := p.number()
+	if err != nil {
+		return nil, err
+	}
+	field.Number = proto.Int32(num)
+	if err := p.fieldOptions(field); err != nil {
+		return nil, err
+	}
+	return field, p.expect(";")
+}
+
+// mapField parses "map<K, V> name = number [options];" and adds the entry message
+// protoc synthesizes for it.
+func (p *protoParser) mapField(msg *descriptorpb.DescriptorProto) (*descriptorpb.FieldDescriptorProto, error) {
+	if err := p.expect("<"); err != nil {
+		return nil, err
+	}
+	keyType := p.next()
+	if err := p.expect(","); err != nil {
+		return nil, err
+	}
+	valueType := p.next()
+	if err := p.expect(">"); err != nil {
+		return nil, err
+	}
+	name := p.next()
+	entryName := protoCamelCase(name) + "Entry"
+
+	key := &descriptorpb.FieldDescriptorProto{Name: proto.String("key"), Number: proto.Int32(1), Label: descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum()}
+	setFieldType(key, keyType)
+	value := &descriptorpb.FieldDescriptorProto{Name: proto.String("value"), Number: proto.Int32(2), Label: descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum()}
+	setFieldType(value, valueType)
+	msg.NestedType = append(msg.NestedType, &descriptorpb.DescriptorProto{
+		Name:    proto.String(entryName),
+		Field:   []*descriptorpb.FieldDescriptorProto{key, value},
+		Options: &descriptorpb.MessageOptions{MapEntry: proto.Bool(true)},
+	})
+
+	field := &descriptorpb.FieldDescriptorProto{
+		Name:     proto.String(name),
+		Label:    descriptorpb.FieldDescriptorProto_LABEL_REPEATED.Enum(),
+		Type:     descriptorpb.FieldDescriptorProto_TYPE_MESSAGE.Enum(),
+		TypeName: proto.String(entryName),
+	}
+	if err := p.expect("="); err != nil {
+		return nil, err
+	}
+	num, err := p.number()
+	if err != nil {
+		return nil, err
+	}
+	field.Number = proto.Int32(num)
+	if err := p.fieldOptions(field); err != nil {
+		return nil, err
+	}
+	return field, p.expect(";")
+}
+
+// fieldOptions reads an optional [name = value, ...] list, keeping json_name and default.
+func (p *protoParser) fieldOptions(field *descriptorpb.FieldDescriptorProto) error {
+	if !p.accept("[") {
+		return nil
+	}
+	for !p.accept("]") {
+		if p.done() {
+			return p.errorf("unterminated field options")
+		}
+		name := p.next()
+		if name == "(" { // custom option: (full.name).sub
+			for !p.done() && p.next() != ")" {
+			}
+			for strings.HasPrefix(p.peek(), ".") {
+				p.next()
+			}
+		}
+		if err := p.expect("="); err != nil {
+			return err
+		}
+		var value string
+		switch {
+		case p.peek() == "{":
+			p.skipBlock()
+		case !p.done() && p.tokens[p.pos].str:
+			value = p.stringLit()
+		case p.accept("-"):
+			value = "-" + p.next()
+		default:
+			value = p.next()
+		}
+		switch name {
+		case "json_name":
+			field.JsonName = proto.String(value)
+		case "default":
+			field.DefaultValue = proto.String(value)
+		}
+		p.accept(",")
+	}
+	return nil
+}
+
+func setFieldType(field *descriptorpb.FieldDescriptorProto, typeName string) {
+	if t, ok := protoScalarTypes[typeName]; ok {
+		field.Type = t.Enum()
+		return
+	}
+	field.TypeName = proto.String(typeName) // message or enum, resolved by protodesc
+}
+
+func (p *protoParser) enum() (*descriptorpb.EnumDescriptorProto, error) {
+	enum := &descriptorpb.EnumDescriptorProto{Name: proto.String(p.next())}
+	if err := p.expect("{"); err != nil {
+		return nil, err
+	}
+	for !p.accept("}") {
+		if p.done() {
+			return nil, p.errorf("unterminated enum %s", enum.GetName())
+		}
+		switch p.peek() {
+		case ";":
+			p.next()
+		case "option", "reserved":
+			p.skipStatement()
+		default:
+			value := &descriptorpb.EnumValueDescriptorProto{Name: proto.String(p.next())}
+			if err := p.expect("="); err != nil {
+				return nil, fmt.Errorf("enum %s: %w", enum.GetName(), err)
+			}
+			negative := p.accept("-")
+			num, err := p.number()
+			if err != nil {
+				return nil, err
+			}
+			if negative {
+				num = -num
+			}
+			value.Number = proto.Int32(num)
+			if p.peek() == "[" {
+				for !p.done() && p.next() != "]" {
+				}
+			}
+			if err := p.expect(";"); err != nil {
+				return nil, err
+			}
+			enum.Value = append(enum.Value, value)
+		}
+	}
+	return enum, nil
+}
+
+func (p *protoParser) service() (*descriptorpb.ServiceDescriptorProto, error) {
+	svc := &descriptorpb.ServiceDescriptorProto{Name: proto.String(p.next())}
+	if err := p.expect("{"); err != nil {
+		return nil, err
+	}
+	for !p.accept("}") {
+		if p.done() {
+			return nil, p.errorf("unterminated service %s", svc.GetName())
+		}
+		switch p.next() {
+		case ";":
+		case "option":
+			p.skipStatement()
+		case "rpc":
+			method := &descriptorpb.MethodDescriptorProto{Name: proto.String(p.next())}
+			if err := p.expect("("); err != nil {
+				return nil, err
+			}
+			if p.accept("stream") {
+				method.ClientStreaming = proto.Bool(true)
+			}
+			method.InputType = proto.String(p.next())
+			if err := p.expect(")"); err != nil {
+				return nil, err
+			}
+			if err := p.expect("returns"); err != nil {
+				return nil, err
+			}
+			if err := p.expect("("); err != nil {
+				return nil, err
+			}
+			if p.accept("stream") {
+				method.ServerStreaming = proto.Bool(true)
+			}
+			method.OutputType = proto.String(p.next())
+			if err := p.expect(")"); err != nil {
+				return nil, err
+			}
+			if p.peek() == "{" {
+				p.skipBlock()
+			} else if err := p.expect(";"); err != nil {
+				return nil, err
+			}
+			svc.Method = append(svc.Method, method)
+		default:
+			return nil, p.errorf("unexpected %q in service %s", p.tokens[p.pos-1].text, svc.GetName())
+		}
+	}
+	return svc, nil
+}
+
+// protoCamelCase converts a field name to the CamelCase protoc uses for map entries.
+func protoCamelCase(name string) string {
+	var sb strings.Builder
+	upper := true
+	for _, r := range name {
+		if r == '_' {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
+
+func tokenizeProto(src string) ([]protoToken, error) {
+	var tokens []protoToken
+	line := 1
+	for i := 0; i < len(src); {
+		c := src[i]
+		switch {
+		case c == '\n':
+			line++
+			i++
+		case c == ' ' || c == '\t' || c == '\r':
+			i++
+		case strings.HasPrefix(src[i:], "//"):
+			for i < len(src) && src[i] != '\n' {
+				i++
+			}
+		case strings.HasPrefix(src[i:], "/*"):
+			end := strings.Index(src[i+2:], "*/")
+			if end < 0 {
+				return nil, fmt.Errorf("line %d: unterminated comment", line)
+			}
+			line += strings.Count(src[i:i+2+end], "\n")
+			i += end + 4
+		case c == '"' || c == '\'':
+			j := i + 1
+			for j < len(src) && src[j] != c {
+				if src[j] == '\\' {
+					j++
+				} else if src[j] == '\n' {
+					return nil, fmt.Errorf("line %d: unterminated string", line)
+				}
+				j++
+			}
+			if j >= len(src) {
+				return nil, fmt.Errorf("line %d: unterminated string", line)
+			}
+			value, err := strconv.Unquote(`"` + strings.ReplaceAll(src[i+1:j], `"`, `\"`) + `"`)
+			if err != nil {
+				value = src[i+1 : j]
+			}
+			tokens = append(tokens, protoToken{text: value, line: line, str: true})
+			i = j + 1
+		case c == '_' || c == '.' || unicode.IsLetter(rune(c)) || unicode.IsDigit(rune(c)):
+			j := i
+			for j < len(src) && (src[j] == '_' || src[j] == '.' || unicode.IsLetter(rune(src[j])) || unicode.IsDigit(rune(src[j]))) {
+				j++
+			}
+			tokens = append(tokens, protoToken{text: src[i:j], line: line})
+			i = j
+		default:
+			tokens = append(tokens, protoToken{text: string(c), line: line})
+			i++
+		}
+	}
+	return tokens, nil
+}
@@ -0,0 +1,233 @@
+package grpc
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+
+	// Well-known types, importable from .proto files without supplying them
+	_ "google.golang.org/protobuf/types/known/anypb"
+	_ "google.golang.org/protobuf/types/known/durationpb"
+	_ "google.golang.org/protobuf/types/known/emptypb"
+	_ "google.golang.org/protobuf/types/known/fieldmaskpb"
+	_ "google.golang.org/protobuf/types/known/structpb"
+	_ "google.golang.org/protobuf/types/known/timestamppb"
+	_ "google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+// Schema holds loaded protobuf files and resolves gRPC methods to their message
+// types. It is safe for concurrent use.
+type Schema struct {
+	mu    sync.RWMutex
+	files *protoregistry.Files
+}
+
+// Method describes an RPC.
+type Method struct {
+	Path            string // /package.Service/Method, the HTTP/2 :path
+	Input           string
+	Output          string
+	ClientStreaming bool
+	ServerStreaming bool
+}
+
+func NewSchema() *Schema {
+	return &Schema{files: new(protoregistry.Files)}
+}
+
+// AddDescriptorSet loads a serialized FileDescriptorSet, as written by
+// protoc --descriptor_set_out or buf build -o. Files already loaded are skipped.
+func (s *Schema) AddDescriptorSet(data []byte) ([]string, error) {
+	var set descriptorpb.FileDescriptorSet
+	if err := proto.Unmarshal(data, &set); err != nil {
+		return nil, fmt.Errorf("parse descriptor set: %w", err)
+	} else if len(set.GetFile()) == 0 {
+		return nil, errors.New("descriptor set contains no files")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	// Register in dependency order; each pass adds every file whose imports resolve
+	pending := set.GetFile()
+	var added []string
+	for len(pending) > 0 {
+		var next []*descriptorpb.FileDescriptorProto
+		var lastErr error
+		for _, fd := range pending {
+			if _, err := s.files.FindFileByPath(fd.GetName()); err == nil {
+				continue
+			}
+			if err := s.register(fd); err != nil {
+				next, lastErr = append(next, fd), err
+				continue
+			}
+			added = append(added, fd.GetName())
+		}
+		if len(next) == len(pending) {
+			return added, lastErr
+		}
+		pending = next
+	}
+	return added, nil
+}
+
+// AddProto parses .proto source and loads it under name, the path other files
+// import it by. Imports must already be loaded, except for the well-known types.
+func (s *Schema) AddProto(name, source string) error {
+	fd, err := parseProto(name, source)
+	if err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, err := s.files.FindFileByPath(name); err == nil {
+		return fmt.Errorf("%s: already loaded", name)
+	}
+	if err := s.register(fd); err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	return nil
+}
+
+func (s *Schema) register(fd *descriptorpb.FileDescriptorProto) error {
+	file, err := protodesc.NewFile(fd, schemaResolver{s.files})
+	if err != nil {
+		return err
+	}
+	return s.files.RegisterFile(file)
+}
+
+// Methods returns the RPCs of all loaded services, sorted by path.
+func (s *Schema) Methods() []Method {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var methods []Method
+	s.files.RangeFiles(func(f protoreflect.FileDescriptor) bool {
+		services := f.Services()
+		for i := range services.Len() {
+			ms := services.Get(i).Methods()
+			for j := range ms.Len() {
+				methods = append(methods, describeMethod(ms.Get(j)))
+			}
+		}
+		return true
+	})
+	slices.SortFunc(methods, func(a, b Method) int { return strings.Compare(a.Path, b.Path) })
+	return methods
+}
+
+// Method looks up an RPC by its path (/package.Service/Method).
+func (s *Schema) Method(path string) (protoreflect.MethodDescriptor, bool) {
+	service, method, ok := strings.Cut(strings.TrimPrefix(path, "/"), "/")
+	if !ok {
+		return nil, false
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	d, err := s.files.FindDescriptorByName(protoreflect.FullName(service))
+	if err != nil {
+		return nil, false
+	}
+	sd, ok := d.(protoreflect.ServiceDescriptor)
+	if !ok {
+		return nil, false
+	}
+	md := sd.Methods().ByName(protoreflect.Name(method))
+	return md, md != nil
+}
+
+func describeMethod(md protoreflect.MethodDescriptor) Method {
+	return Method{
+		Path:            "/" + string(md.Parent().FullName()) + "/" + string(md.Name()),
+		Input:           string(md.Input().FullName()),
+		Output:          string(md.Output().FullName()),
+		ClientStreaming: md.IsStreamingClient(),
+		ServerStreaming: md.IsStreamingServer(),
+	}
+}
+
+// DecodeMessage renders a serialized message of type md as protojson with proto
+// field names; unset fields are left out.
+func (s *Schema) DecodeMessage(md protoreflect.MessageDescriptor, data []byte) ([]byte, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	types := schemaTypes{s.files}
+	msg := dynamicpb.NewMessage(md)
+	if err := (proto.UnmarshalOptions{Resolver: types}).Unmarshal(data, msg); err != nil {
+		return nil, fmt.Errorf("decode %s: %w", md.FullName(), err)
+	}
+	return protojson.MarshalOptions{UseProtoNames: true, Resolver: types}.Marshal(msg)
+}
+
+// EncodeMessage serializes protojson (field names in either proto or JSON form) as a
+// message of type md.
+func (s *Schema) EncodeMessage(md protoreflect.MessageDescriptor, jsonData []byte) ([]byte, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	msg := dynamicpb.NewMessage(md)
+	if err := (protojson.UnmarshalOptions{Resolver: schemaTypes{s.files}}).Unmarshal(jsonData, msg); err != nil {
+		return nil, fmt.Errorf("encode %s: %w", md.FullName(), err)
+	}
+	return proto.MarshalOptions{Deterministic: true}.Marshal(msg)
+}
+
+// schemaResolver resolves imports against loaded files, then the well-known types.
+type schemaResolver struct {
+	files *protoregistry.Files
+}
+
+func (r schemaResolver) FindFileByPath(path string) (protoreflect.FileDescriptor, error) {
+	if fd, err := r.files.FindFileByPath(path); err == nil {
+		return fd, nil
+	}
+	return protoregistry.GlobalFiles.FindFileByPath(path)
+}
+
+func (r schemaResolver) FindDescriptorByName(name protoreflect.FullName) (protoreflect.Descriptor, error) {
+	if d, err := r.files.FindDescriptorByName(name); err == nil {
+		return d, nil
+	}
+	return protoregistry.GlobalFiles.FindDescriptorByName(name)
+}
+
+// schemaTypes resolves google.protobuf.Any payloads to loaded messages, then to the
+// well-known types. Extensions resolve to the global types only.
+type schemaTypes struct {
+	files *protoregistry.Files
+}
+
+func (t schemaTypes) FindMessageByName(name protoreflect.FullName) (protoreflect.MessageType, error) {
+	if d, err := t.files.FindDescriptorByName(name); err == nil {
+		if md, ok := d.(protoreflect.MessageDescriptor); ok {
+			return dynamicpb.NewMessageType(md), nil
+		}
+	}
+	return protoregistry.GlobalTypes.FindMessageByName(name)
+}
+
+func (t schemaTypes) FindMessageByURL(url string) (protoreflect.MessageType, error) {
+	name := url
+	if i := strings.LastIndexByte(url, '/'); i >= 0 {
+		name = url[i+1:]
+	}
+	return t.FindMessageByName(protoreflect.FullName(name))
+}
+
+func (schemaTypes) FindExtensionByName(name protoreflect.FullName) (protoreflect.ExtensionType, error) {
+	return protoregistry.GlobalTypes.FindExtensionByName(name)
+}
+
+func (schemaTypes) FindExtensionByNumber(message protoreflect.FullName, field protoreflect.FieldNumber) (protoreflect.ExtensionType, error) {
+	return protoregistry.GlobalTypes.FindExtensionByNumber(message, field)
+}
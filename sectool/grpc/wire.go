@@ -0,0 +1,261 @@
+package grpc
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"unicode/utf8"
+
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// Wrapper keys of the schema-less rendering for values a plain JSON value can't
+// round-trip. Message fields are keyed by field number, so these never collide.
+const (
+	rawBytes   = "bytes"   // base64 of a length-delimited field that isn't text or a message
+	rawFixed32 = "fixed32" // 32-bit field as an unsigned integer
+	rawFixed64 = "fixed64" // 64-bit field as a decimal string
+	rawVarint  = "varint"  // varint above 2^53 as a decimal string
+)
+
+// maxRawDepth bounds nested message detection in DecodeRaw.
+const maxRawDepth = 32
+
+// DecodeRaw renders a protobuf message without a schema, like protoc --decode_raw.
+// Fields are keyed by number; a repeated number becomes an array. Varints are numbers,
+// length-delimited fields are a nested message when they parse as one, a string when
+// they are printable UTF-8, and {"bytes": base64} otherwise. EncodeRaw reverses it.
+func DecodeRaw(data []byte) (map[string]interface{}, error) {
+	return decodeRaw(data, 0)
+}
+
+func decodeRaw(data []byte, depth int) (map[string]interface{}, error) {
+	out := make(map[string]interface{})
+	for len(data) > 0 {
+		num, typ, n := protowire.ConsumeTag(data)
+		if n < 0 {
+			return nil, protowire.ParseError(n)
+		}
+		data = data[n:]
+
+		var value interface{}
+		switch typ {
+		case protowire.VarintType:
+			v, n := protowire.ConsumeVarint(data)
+			if n < 0 {
+				return nil, protowire.ParseError(n)
+			}
+			data = data[n:]
+			if v > 1<<53 {
+				value = map[string]interface{}{rawVarint: strconv.FormatUint(v, 10)}
+			} else {
+				value = v
+			}
+		case protowire.Fixed32Type:
+			v, n := protowire.ConsumeFixed32(data)
+			if n < 0 {
+				return nil, protowire.ParseError(n)
+			}
+			data = data[n:]
+			value = map[string]interface{}{rawFixed32: v}
+		case protowire.Fixed64Type:
+			v, n := protowire.ConsumeFixed64(data)
+			if n < 0 {
+				return nil, protowire.ParseError(n)
+			}
+			data = data[n:]
+			value = map[string]interface{}{rawFixed64: strconv.FormatUint(v, 10)}
+		case protowire.BytesType:
+			v, n := protowire.ConsumeBytes(data)
+			if n < 0 {
+				return nil, protowire.ParseError(n)
+			}
+			data = data[n:]
+			value = decodeRawBytes(v, depth)
+		default:
+			return nil, fmt.Errorf("field %d: unsupported wire type %d", num, typ)
+		}
+
+		key := strconv.Itoa(int(num))
+		switch existing := out[key].(type) {
+		case nil:
+			out[key] = value
+		case []interface{}:
+			out[key] = append(existing, value)
+		default:
+			out[key] = []interface{}{existing, value}
+		}
+	}
+	return out, nil
+}
+
+func decodeRawBytes(v []byte, depth int) interface{} {
+	if len(v) > 0 && depth < maxRawDepth && !isPrintable(v) {
+		if nested, err := decodeRaw(v, depth+1); err == nil {
+			return nested
+		}
+	}
+	if isPrintable(v) {
+		return string(v)
+	}
+	return map[string]interface{}{rawBytes: base64.StdEncoding.EncodeToString(v)}
+}
+
+// isPrintable reports whether v is UTF-8 text without control characters other than
+// whitespace, the heuristic separating strings from nested messages.
+func isPrintable(v []byte) bool {
+	if !utf8.Valid(v) {
+		return false
+	}
+	for _, r := range string(v) {
+		if r < 0x20 && r != '\n' && r != '\r' && r != '\t' {
+			return false
+		}
+	}
+	return true
+}
+
+// EncodeRaw serializes the DecodeRaw rendering back to the wire format, writing
+// fields in ascending number order. Integers become varints (negative values as
+// two's complement), other numbers doubles, booleans varints, strings and nested
+// objects length-delimited fields.
+func EncodeRaw(msg map[string]interface{}) ([]byte, error) {
+	nums := make([]int, 0, len(msg))
+	for key := range msg {
+		num, err := strconv.Atoi(key)
+		if err != nil || num < 1 || num > int(protowire.MaxValidNumber) {
+			return nil, fmt.Errorf("invalid field number %q", key)
+		}
+		nums = append(nums, num)
+	}
+	slices.Sort(nums)
+
+	var out []byte
+	for _, num := range nums {
+		values, ok := msg[strconv.Itoa(num)].([]interface{})
+		if !ok {
+			values = []interface{}{msg[strconv.Itoa(num)]}
+		}
+		for _, v := range values {
+			var err error
+			if out, err = appendRawValue(out, protowire.Number(num), v); err != nil {
+				return nil, fmt.Errorf("field %d: %w", num, err)
+			}
+		}
+	}
+	return out, nil
+}
+
+func appendRawValue(out []byte, num protowire.Number, v interface{}) ([]byte, error) {
+	switch v := v.(type) {
+	case nil:
+		return out, nil // removed field
+	case bool:
+		out = protowire.AppendTag(out, num, protowire.VarintType)
+		return protowire.AppendVarint(out, protowire.EncodeBool(v)), nil
+	case string:
+		out = protowire.AppendTag(out, num, protowire.BytesType)
+		return protowire.AppendString(out, v), nil
+	case uint64:
+		out = protowire.AppendTag(out, num, protowire.VarintType)
+		return protowire.AppendVarint(out, v), nil
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			out = protowire.AppendTag(out, num, protowire.VarintType)
+			return protowire.AppendVarint(out, uint64(i)), nil
+		} else if u, err := strconv.ParseUint(v.String(), 10, 64); err == nil {
+			out = protowire.AppendTag(out, num, protowire.VarintType)
+			return protowire.AppendVarint(out, u), nil
+		}
+		f, err := v.Float64()
+		if err != nil {
+			return nil, err
+		}
+		return appendRawValue(out, num, f)
+	case float64:
+		if v == math.Trunc(v) && math.Abs(v) <= 1<<53 {
+			out = protowire.AppendTag(out, num, protowire.VarintType)
+			return protowire.AppendVarint(out, uint64(int64(v))), nil
+		}
+		out = protowire.AppendTag(out, num, protowire.Fixed64Type)
+		return protowire.AppendFixed64(out, math.Float64bits(v)), nil
+	case map[string]interface{}:
+		return appendRawObject(out, num, v)
+	}
+	return nil, fmt.Errorf("unsupported value %T", v)
+}
+
+// appendRawObject writes a wrapper value ({"bytes": ...}, {"fixed32": ...}, ...) or
+// a nested message.
+func appendRawObject(out []byte, num protowire.Number, v map[string]interface{}) ([]byte, error) {
+	if len(v) == 1 {
+		for key, inner := range v {
+			switch key {
+			case rawBytes:
+				s, ok := inner.(string)
+				if !ok {
+					return nil, errors.New("bytes must be a base64 string")
+				}
+				data, err := base64.StdEncoding.DecodeString(s)
+				if err != nil {
+					return nil, fmt.Errorf("bytes: %w", err)
+				}
+				out = protowire.AppendTag(out, num, protowire.BytesType)
+				return protowire.AppendBytes(out, data), nil
+			case rawFixed32:
+				u, err := rawUint(inner, 32)
+				if err != nil {
+					return nil, fmt.Errorf("fixed32: %w", err)
+				}
+				out = protowire.AppendTag(out, num, protowire.Fixed32Type)
+				return protowire.AppendFixed32(out, uint32(u)), nil
+			case rawFixed64:
+				u, err := rawUint(inner, 64)
+				if err != nil {
+					return nil, fmt.Errorf("fixed64: %w", err)
+				}
+				out = protowire.AppendTag(out, num, protowire.Fixed64Type)
+				return protowire.AppendFixed64(out, u), nil
+			case rawVarint:
+				u, err := rawUint(inner, 64)
+				if err != nil {
+					return nil, fmt.Errorf("varint: %w", err)
+				}
+				out = protowire.AppendTag(out, num, protowire.VarintType)
+				return protowire.AppendVarint(out, u), nil
+			}
+		}
+	}
+	nested, err := EncodeRaw(v)
+	if err != nil {
+		return nil, err
+	}
+	out = protowire.AppendTag(out, num, protowire.BytesType)
+	return protowire.AppendBytes(out, nested), nil
+}
+
+// rawUint reads an unsigned wrapper value given as a number or decimal string;
+// negative numbers wrap as two's complement.
+func rawUint(v interface{}, bits int) (uint64, error) {
+	switch v := v.(type) {
+	case string:
+		if u, err := strconv.ParseUint(v, 10, bits); err == nil {
+			return u, nil
+		}
+		i, err := strconv.ParseInt(v, 10, bits)
+		return uint64(i) & (math.MaxUint64 >> (64 - bits)), err
+	case json.Number:
+		return rawUint(v.String(), bits)
+	case float64:
+		return rawUint(strconv.FormatFloat(v, 'f', -1, 64), bits)
+	case uint32:
+		return uint64(v), nil
+	case uint64:
+		return v, nil
+	}
+	return 0, fmt.Errorf("unsupported value %T", v)
+}
@@ -0,0 +1,227 @@
+package grpccli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/pflag"
+
+	"github.com/go-harden/llm-security-toolbox/sectool/cli"
+)
+
+var grpcSubcommands = []string{"schema", "list", "get", "send", "help"}
+
+func Parse(args []string, mcpURL string) error {
+	if len(args) < 1 {
+		printUsage()
+		return errors.New("subcommand required")
+	}
+
+	switch args[0] {
+	case "schema":
+		return parseSchema(args[1:], mcpURL)
+	case "list":
+		return parseList(args[1:], mcpURL)
+	case "get":
+		return parseGet(args[1:], mcpURL)
+	case "send":
+		return parseSend(args[1:], mcpURL)
+	case "help", "--help", "-h":
+		printUsage()
+		return nil
+	default:
+		return cli.UnknownSubcommandError("grpc", args[0], grpcSubcommands)
+	}
+}
+
+func printUsage() {
+	_, _ = fmt.Fprint(os.Stderr, `Usage: sectool grpc <command> [options]
+
+Decode, edit and re-send gRPC and gRPC-Web calls from proxy history.
+
+Without a schema, messages are shown with fields keyed by number. Load .proto
+files or descriptor sets with 'grpc schema' to decode fields by name.
+
+---
+
+grpc schema [file...] [options]
+
+  Load .proto files or binary descriptor sets (protoc --descriptor_set_out,
+  buf build -o); without files, list the loaded methods. Files import each
+  other by path relative to --root (default: their base name).
+
+  Options:
+    --root <dir>           import root of .proto files (protoc -I)
+
+  Examples:
+    sectool grpc schema api.pb
+    sectool grpc schema --root proto proto/shop/v1/*.proto
+
+---
+
+grpc list [options]
+
+  List gRPC calls in proxy history, most recent first.
+
+  Options:
+    --host <glob>          host filter
+    --method <glob>        method path filter (e.g., '/shop.v1.*/Get*')
+    --limit <n>            maximum calls
+
+---
+
+grpc get <flow_id> [options]
+
+  Show the decoded request and response messages of a call.
+
+  Options:
+    --replay <id>          decode a replay instead of a proxy flow
+
+---
+
+grpc send --flow <id> [options]
+
+  Re-send a call with edits to its first request message. Native gRPC goes
+  over HTTP/2, gRPC-Web through the proxy.
+
+  Options:
+    --flow <id>            proxy flow to use as base
+    --replay <id>          replay to use as base
+    --message <json>       replacement message (- reads stdin)
+    --set-field <k=v>      set a message field (repeatable, dot path)
+    --remove-field <k>     remove a message field (repeatable)
+    -H, --metadata <h>     metadata "name: value" (repeatable)
+    --target <url>         override destination
+    --request-timeout <d>  request timeout
+    --label <name>         label for the replay
+
+  Examples:
+    sectool grpc send --flow f7k2x --set-field id=2
+    sectool grpc send --flow f7k2x --set-field 1=2 -H "authorization: Bearer ..."
+`)
+}
+
+func parseSchema(args []string, mcpURL string) error {
+	fs := pflag.NewFlagSet("grpc schema", pflag.ContinueOnError)
+	fs.SetInterspersed(true)
+	var timeout time.Duration
+	var root string
+
+	fs.DurationVar(&timeout, "timeout", 30*time.Second, "client-side timeout")
+	fs.StringVar(&root, "root", "", "import root of .proto files")
+
+	fs.Usage = func() {
+		_, _ = fmt.Fprint(os.Stderr, `Usage: sectool grpc schema [file...] [options]
+
+Load .proto files or descriptor sets, or list loaded methods.
+
+Options:
+`)
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	return schema(mcpURL, timeout, fs.Args(), root)
+}
+
+func parseList(args []string, mcpURL string) error {
+	fs := pflag.NewFlagSet("grpc list", pflag.ContinueOnError)
+	fs.SetInterspersed(true)
+	var timeout time.Duration
+	var host, method string
+	var limit int
+
+	fs.DurationVar(&timeout, "timeout", 30*time.Second, "client-side timeout")
+	fs.StringVar(&host, "host", "", "host glob filter")
+	fs.StringVar(&method, "method", "", "method path glob filter")
+	fs.IntVar(&limit, "limit", 0, "maximum calls")
+
+	fs.Usage = func() {
+		_, _ = fmt.Fprint(os.Stderr, `Usage: sectool grpc list [options]
+
+List gRPC calls in proxy history.
+
+Options:
+`)
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	return list(mcpURL, timeout, host, method, limit)
+}
+
+func parseGet(args []string, mcpURL string) error {
+	fs := pflag.NewFlagSet("grpc get", pflag.ContinueOnError)
+	fs.SetInterspersed(true)
+	var timeout time.Duration
+	var replayID string
+
+	fs.DurationVar(&timeout, "timeout", 30*time.Second, "client-side timeout")
+	fs.StringVar(&replayID, "replay", "", "replay ID to decode instead of a flow")
+
+	fs.Usage = func() {
+		_, _ = fmt.Fprint(os.Stderr, `Usage: sectool grpc get <flow_id> [options]
+
+Show decoded gRPC messages.
+
+Options:
+`)
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	var flowID string
+	if fs.NArg() > 0 {
+		flowID = fs.Arg(0)
+	}
+	if (flowID == "") == (replayID == "") {
+		fs.Usage()
+		return errors.New("set either a flow_id or --replay")
+	}
+	return get(mcpURL, timeout, flowID, replayID)
+}
+
+func parseSend(args []string, mcpURL string) error {
+	fs := pflag.NewFlagSet("grpc send", pflag.ContinueOnError)
+	fs.SetInterspersed(true)
+	var timeout time.Duration
+	var opts sendOptions
+
+	fs.DurationVar(&timeout, "timeout", time.Minute, "client-side timeout")
+	fs.StringVar(&opts.flow, "flow", "", "proxy flow to use as base")
+	fs.StringVar(&opts.replay, "replay", "", "replay to use as base")
+	fs.StringVar(&opts.message, "message", "", "replacement message JSON (- reads stdin)")
+	fs.StringArrayVar(&opts.setFields, "set-field", nil, "set message field (repeatable, e.g., user.id=2)")
+	fs.StringArrayVar(&opts.removeFields, "remove-field", nil, "remove message field (repeatable)")
+	fs.StringArrayVarP(&opts.metadata, "metadata", "H", nil, "metadata \"name: value\" (repeatable)")
+	fs.StringVar(&opts.target, "target", "", "override target URL (scheme://host:port)")
+	fs.DurationVar(&opts.requestTimeout, "request-timeout", 0, "request timeout (0 = no timeout)")
+	fs.StringVar(&opts.label, "label", "", "label for the replay")
+
+	fs.Usage = func() {
+		_, _ = fmt.Fprint(os.Stderr, `Usage: sectool grpc send --flow <id> [options]
+
+Re-send a gRPC call with message edits.
+
+Options:
+`)
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if (opts.flow == "") == (opts.replay == "") {
+		fs.Usage()
+		return errors.New("set either --flow or --replay")
+	}
+	return send(mcpURL, timeout, opts)
+}
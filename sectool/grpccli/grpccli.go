@@ -0,0 +1,249 @@
+package grpccli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/go-harden/llm-security-toolbox/sectool/cliutil"
+	"github.com/go-harden/llm-security-toolbox/sectool/mcpclient"
+	"github.com/go-harden/llm-security-toolbox/sectool/protocol"
+)
+
+type sendOptions struct {
+	flow           string
+	replay         string
+	message        string
+	setFields      []string
+	removeFields   []string
+	metadata       []string
+	target         string
+	requestTimeout time.Duration
+	label          string
+}
+
+func schema(mcpURL string, timeout time.Duration, files []string, root string) error {
+	opts := mcpclient.GRPCSchemaOpts{}
+	// The service reads the files, so paths must not depend on its working directory
+	for _, f := range files {
+		path, err := filepath.Abs(f)
+		if err != nil {
+			return err
+		}
+		opts.Paths = append(opts.Paths, path)
+	}
+	if root != "" {
+		path, err := filepath.Abs(root)
+		if err != nil {
+			return err
+		}
+		opts.Root = path
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	client, err := mcpclient.Connect(ctx, mcpURL)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = client.Close() }()
+
+	resp, err := client.GRPCSchema(ctx, opts)
+	if err != nil {
+		return fmt.Errorf("grpc schema failed: %w", err)
+	}
+
+	if len(resp.Loaded) > 0 {
+		fmt.Printf("Loaded: %s\n\n", strings.Join(resp.Loaded, ", "))
+	}
+	if len(resp.Methods) == 0 {
+		fmt.Println("No methods loaded.")
+		cliutil.Hintf("\nTo load a schema: `sectool grpc schema <file.proto|descriptor.pb>`\n")
+		return nil
+	}
+	fmt.Println("| method | input | output | streaming |")
+	fmt.Println("|--------|-------|--------|-----------|")
+	for _, m := range resp.Methods {
+		fmt.Printf("| %s | %s | %s | %s |\n", m.Path, m.Input, m.Output, orDash(m.Streaming))
+	}
+	fmt.Printf("\n*%d method(s)*\n", len(resp.Methods))
+	return nil
+}
+
+func list(mcpURL string, timeout time.Duration, host, method string, limit int) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	client, err := mcpclient.Connect(ctx, mcpURL)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = client.Close() }()
+
+	resp, err := client.GRPCList(ctx, mcpclient.GRPCListOpts{Host: host, Method: method, Limit: limit})
+	if err != nil {
+		return fmt.Errorf("grpc list failed: %w", err)
+	}
+	if len(resp.Calls) == 0 {
+		fmt.Println("No gRPC calls found.")
+		return nil
+	}
+
+	defer cliutil.StartPager()()
+	fmt.Println("| flow_id | host | method | protocol | http | grpc-status | messages | schema |")
+	fmt.Println("|---------|------|--------|----------|------|-------------|----------|--------|")
+	for _, c := range resp.Calls {
+		schemaMark := ""
+		if c.Schema {
+			schemaMark = "yes"
+		}
+		fmt.Printf("| %s | %s | %s | %s | %d | %s | %d | %s |\n", c.FlowID, cliutil.EscapeMarkdown(c.Host),
+			cliutil.EscapeMarkdown(c.Method), c.Protocol, c.HTTPStatus, orDash(c.GRPCStatus), c.Messages, schemaMark)
+	}
+	fmt.Printf("\n*%d call(s)*\n", len(resp.Calls))
+	cliutil.Hintf("\nTo decode one: `sectool grpc get %s`\n", resp.Calls[0].FlowID)
+	return nil
+}
+
+func get(mcpURL string, timeout time.Duration, flowID, replayID string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	client, err := mcpclient.Connect(ctx, mcpURL)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = client.Close() }()
+
+	resp, err := client.GRPCGet(ctx, flowID, replayID)
+	if err != nil {
+		return fmt.Errorf("grpc get failed: %w", err)
+	}
+
+	defer cliutil.StartPager()()
+	fmt.Printf("## `%s` (%s)\n\n", resp.Method, resp.Protocol)
+	if !resp.Schema {
+		fmt.Println("No schema loaded for this method; fields are keyed by number.")
+		fmt.Println()
+	}
+	printMessages("Request", resp.Request)
+	printMessages("Response", resp.Response)
+	printStatus(resp.HTTPStatus, resp.GRPCStatus, resp.GRPCMessage)
+	if resp.FrameError != "" {
+		fmt.Printf("Framing error: %s\n", resp.FrameError)
+	}
+	return nil
+}
+
+func send(mcpURL string, timeout time.Duration, opts sendOptions) error {
+	clientOpts := mcpclient.GRPCSendOpts{
+		FlowID:       opts.flow,
+		ReplayID:     opts.replay,
+		RemoveFields: opts.removeFields,
+		Target:       opts.target,
+		Label:        opts.label,
+	}
+	if opts.requestTimeout > 0 {
+		clientOpts.Timeout = opts.requestTimeout.String()
+	}
+	if opts.message != "" {
+		data := []byte(opts.message)
+		if opts.message == "-" {
+			var err error
+			if data, err = io.ReadAll(os.Stdin); err != nil {
+				return fmt.Errorf("read message: %w", err)
+			}
+		}
+		if err := json.Unmarshal(data, &clientOpts.Message); err != nil {
+			return fmt.Errorf("--message must be a JSON object: %w", err)
+		}
+	}
+	if len(opts.setFields) > 0 {
+		clientOpts.SetFields = make(map[string]interface{}, len(opts.setFields))
+		for _, kv := range opts.setFields {
+			if key, value, ok := strings.Cut(kv, "="); ok && key != "" {
+				clientOpts.SetFields[key] = value
+			} else {
+				clientOpts.SetFields[kv] = nil
+			}
+		}
+	}
+	if len(opts.metadata) > 0 {
+		clientOpts.Metadata = make(map[string]string, len(opts.metadata))
+		for _, h := range opts.metadata {
+			name, value, ok := strings.Cut(h, ":")
+			if !ok || strings.TrimSpace(name) == "" {
+				return fmt.Errorf("invalid metadata %q: expected \"name: value\"", h)
+			}
+			clientOpts.Metadata[strings.TrimSpace(name)] = strings.TrimSpace(value)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	client, err := mcpclient.Connect(ctx, mcpURL)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = client.Close() }()
+
+	resp, err := client.GRPCSend(ctx, clientOpts)
+	if err != nil {
+		return fmt.Errorf("grpc send failed: %w", err)
+	}
+
+	fmt.Printf("## `%s` replay `%s` (%s)\n\n", resp.Method, resp.ReplayID, resp.Duration)
+	printMessages("Response", resp.Response)
+	printStatus(resp.HTTPStatus, resp.GRPCStatus, resp.GRPCMessage)
+	if resp.FrameError != "" {
+		fmt.Printf("Framing error: %s\n", resp.FrameError)
+	}
+	cliutil.Hintf("\nTo decode the exchange: `sectool grpc get --replay %s`\n", resp.ReplayID)
+	return nil
+}
+
+func printMessages(title string, msgs []protocol.GRPCMessage) {
+	if len(msgs) == 0 {
+		return
+	}
+	fmt.Printf("### %s\n\n", title)
+	for i, m := range msgs {
+		fmt.Printf("Message %d (%d bytes)", i+1, m.Size)
+		if m.Error != "" {
+			fmt.Printf(": %s", m.Error)
+		}
+		fmt.Println()
+		if len(m.JSON) > 0 {
+			var pretty bytes.Buffer
+			if err := json.Indent(&pretty, m.JSON, "", "  "); err != nil {
+				pretty.Reset()
+				pretty.Write(m.JSON)
+			}
+			fmt.Printf("```json\n%s\n```\n", pretty.String())
+		}
+		fmt.Println()
+	}
+}
+
+func printStatus(httpStatus int, grpcStatus, grpcMessage string) {
+	fmt.Printf("HTTP status: %d, grpc-status: %s", httpStatus, orDash(grpcStatus))
+	if grpcMessage != "" {
+		fmt.Printf(" (%s)", grpcMessage)
+	}
+	fmt.Println()
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
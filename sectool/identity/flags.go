@@ -0,0 +1,192 @@
+package identity
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/pflag"
+
+	"github.com/go-harden/llm-security-toolbox/sectool/cli"
+	"github.com/go-harden/llm-security-toolbox/sectool/mcpclient"
+)
+
+var identitySubcommands = []string{"list", "set", "matrix", "help"}
+
+func Parse(args []string, mcpURL string) error {
+	if len(args) < 1 {
+		printUsage()
+		return errors.New("subcommand required")
+	}
+
+	switch args[0] {
+	case "list":
+		return parseList(args[1:], mcpURL)
+	case "set":
+		return parseSet(args[1:], mcpURL)
+	case "matrix":
+		return parseMatrix(args[1:], mcpURL)
+	case "help", "--help", "-h":
+		printUsage()
+		return nil
+	default:
+		return cli.UnknownSubcommandError("identity", args[0], identitySubcommands)
+	}
+}
+
+func printUsage() {
+	_, _ = fmt.Fprint(os.Stderr, `Usage: sectool identity <command> [options]
+
+Named identities for authorization testing (.sectool/identities.json). An
+identity is one user's headers and cookies; 'sectool replay send --as <name>'
+and 'identity matrix' swap them into a request in place of its captured
+credentials.
+
+---
+
+identity list
+
+  List identities.
+
+---
+
+identity set <name> [options]
+
+  Create or replace an identity.
+
+  Options:
+    -H, --header <h>       header to set, 'Name: Value' (repeatable)
+    --cookie <k=v>         cookie to set (repeatable)
+    --remove-header <n>    header to remove (repeatable)
+    --delete               delete the identity
+
+  Examples:
+    sectool identity set user_b -H "Authorization: Bearer eyJ..."
+    sectool identity set admin --cookie session=abc --cookie csrf=xyz
+    sectool identity set anonymous --remove-header Authorization --remove-header Cookie
+
+---
+
+identity matrix (--flow <id> | --replay <id>) [options]
+
+  Replay a request as the original sender and as every identity, and compare
+  each response to the baseline. 'same' verdicts for users who should not see
+  the resource point to IDOR/BOLA.
+
+  Options:
+    --identity <name>      identity to replay as (repeatable, default: all)
+    --baseline <name>      identity with the expected response
+                           (default: original, the unmodified request)
+    --request-timeout <d>  per-request timeout
+
+  Examples:
+    sectool identity matrix --flow f7k2x
+    sectool identity matrix --flow f7k2x --identity user_b --identity anonymous
+`)
+}
+
+func parseList(args []string, mcpURL string) error {
+	fs := pflag.NewFlagSet("identity list", pflag.ContinueOnError)
+	fs.SetInterspersed(true)
+	var timeout time.Duration
+
+	fs.DurationVar(&timeout, "timeout", 30*time.Second, "client-side timeout")
+
+	fs.Usage = func() {
+		_, _ = fmt.Fprint(os.Stderr, `Usage: sectool identity list [options]
+
+List identities.
+
+Options:
+`)
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	return list(mcpURL, timeout)
+}
+
+func parseSet(args []string, mcpURL string) error {
+	fs := pflag.NewFlagSet("identity set", pflag.ContinueOnError)
+	fs.SetInterspersed(true)
+	var timeout time.Duration
+	var cookies []string
+	var opts mcpclient.IdentitySetOpts
+
+	fs.DurationVar(&timeout, "timeout", 30*time.Second, "client-side timeout")
+	fs.StringArrayVarP(&opts.Headers, "header", "H", nil, "header to set, 'Name: Value' (repeatable)")
+	fs.StringArrayVar(&cookies, "cookie", nil, "cookie to set, name=value (repeatable)")
+	fs.StringArrayVar(&opts.RemoveHeaders, "remove-header", nil, "header to remove (repeatable)")
+	fs.BoolVar(&opts.Delete, "delete", false, "delete the identity")
+
+	fs.Usage = func() {
+		_, _ = fmt.Fprint(os.Stderr, `Usage: sectool identity set <name> [options]
+
+Create or replace an identity.
+
+Options:
+`)
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() < 1 {
+		fs.Usage()
+		return errors.New("identity name required")
+	}
+	opts.Name = fs.Arg(0)
+	opts.Cookies = make(map[string]string)
+	for _, pair := range cookies {
+		name, value, ok := strings.Cut(pair, "=")
+		if !ok || name == "" {
+			return fmt.Errorf("invalid cookie %q: expected name=value", pair)
+		}
+		opts.Cookies[name] = value
+	}
+
+	return set(mcpURL, timeout, opts)
+}
+
+func parseMatrix(args []string, mcpURL string) error {
+	fs := pflag.NewFlagSet("identity matrix", pflag.ContinueOnError)
+	fs.SetInterspersed(true)
+	var timeout, requestTimeout time.Duration
+	var opts mcpclient.AuthzMatrixOpts
+
+	fs.DurationVar(&timeout, "timeout", 5*time.Minute, "client-side timeout")
+	fs.StringVar(&opts.FlowID, "flow", "", "flow_id to replay")
+	fs.StringVar(&opts.ReplayID, "replay", "", "replay_id or label to replay")
+	fs.StringArrayVar(&opts.Identities, "identity", nil, "identity to replay as (repeatable, default: all)")
+	fs.StringVar(&opts.Baseline, "baseline", "", "identity with the expected response (default: original)")
+	fs.DurationVar(&requestTimeout, "request-timeout", 0, "per-request timeout")
+
+	fs.Usage = func() {
+		_, _ = fmt.Fprint(os.Stderr, `Usage: sectool identity matrix (--flow <id> | --replay <id>) [options]
+
+Replay a request as every identity and compare the responses.
+
+Options:
+`)
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if (opts.FlowID == "") == (opts.ReplayID == "") {
+		fs.Usage()
+		return errors.New("exactly one of --flow or --replay is required")
+	}
+	if requestTimeout > 0 {
+		opts.Timeout = requestTimeout.String()
+	}
+
+	return matrix(mcpURL, timeout, opts)
+}
@@ -0,0 +1,114 @@
+package identity
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-harden/llm-security-toolbox/sectool/cliutil"
+	"github.com/go-harden/llm-security-toolbox/sectool/mcpclient"
+	"github.com/go-harden/llm-security-toolbox/sectool/protocol"
+)
+
+var (
+	identityColumns = []string{"name", "headers", "cookies", "remove_headers"}
+	matrixColumns   = []string{"identity", "status", "size", "similarity", "verdict", "replay_id"}
+)
+
+func list(mcpURL string, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	client, err := mcpclient.Connect(ctx, mcpURL)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = client.Close() }()
+
+	resp, err := client.IdentityList(ctx)
+	if err != nil {
+		return fmt.Errorf("identity list failed: %w", err)
+	}
+	printIdentities(resp)
+	return nil
+}
+
+func set(mcpURL string, timeout time.Duration, opts mcpclient.IdentitySetOpts) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	client, err := mcpclient.Connect(ctx, mcpURL)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = client.Close() }()
+
+	resp, err := client.IdentitySet(ctx, opts)
+	if err != nil {
+		return fmt.Errorf("identity set failed: %w", err)
+	}
+	printIdentities(resp)
+	if !opts.Delete {
+		cliutil.Hintf("\nTo send as it: `sectool replay send --flow <flow_id> --as %s`\n", opts.Name)
+	}
+	return nil
+}
+
+func matrix(mcpURL string, timeout time.Duration, opts mcpclient.AuthzMatrixOpts) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	client, err := mcpclient.Connect(ctx, mcpURL)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = client.Close() }()
+
+	resp, err := client.AuthzMatrix(ctx, opts)
+	if err != nil {
+		return fmt.Errorf("identity matrix failed: %w", err)
+	}
+
+	fmt.Printf("Replayed %s, baseline %s\n\n", resp.Base, resp.Baseline)
+	t := cliutil.NewTable(os.Stdout, cliutil.FormatMarkdown, matrixColumns, matrixColumns)
+	t.Header()
+	for _, r := range resp.Results {
+		if r.Error != "" {
+			t.Row(r.Identity, "", "", "", r.Verdict+": "+r.Error, "")
+			continue
+		}
+		t.Row(r.Identity, strconv.Itoa(r.Status), strconv.Itoa(r.Size),
+			fmt.Sprintf("%.0f%%", r.Similarity*100), r.Verdict, r.ReplayID)
+	}
+	t.Flush()
+
+	if len(resp.Suspicious) > 0 {
+		fmt.Printf("\nSuspicious: %s received the baseline response\n", strings.Join(resp.Suspicious, ", "))
+		cliutil.Hintf("Compare with `sectool replay diff <baseline_replay_id> <replay_id>`\n")
+	}
+	return nil
+}
+
+func printIdentities(resp *protocol.IdentityListResponse) {
+	if len(resp.Identities) == 0 {
+		fmt.Println("No identities defined.")
+		cliutil.Hintf("\nTo create one: `sectool identity set user_b -H \"Authorization: Bearer <token>\"`\n")
+		return
+	}
+
+	t := cliutil.NewTable(os.Stdout, cliutil.FormatMarkdown, identityColumns, identityColumns)
+	t.Header()
+	for _, id := range resp.Identities {
+		cookies := make([]string, 0, len(id.Cookies))
+		for _, name := range slices.Sorted(maps.Keys(id.Cookies)) {
+			cookies = append(cookies, name+"="+id.Cookies[name])
+		}
+		t.Row(id.Name, strings.Join(id.Headers, "; "), strings.Join(cookies, "; "), strings.Join(id.RemoveHeaders, ", "))
+	}
+	t.Flush()
+}
@@ -0,0 +1,156 @@
+package jwt
+
+import (
+	"bufio"
+	"context"
+	"crypto/hmac"
+	"errors"
+	"iter"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Reasons a Crack run stopped.
+const (
+	StopFound     = "found"
+	StopExhausted = "exhausted"
+	StopTimeout   = "timeout"
+	StopLimit     = "limit"
+)
+
+const crackBatchSize = 256
+
+// CrackOptions caps the CPU and time a Crack run may use.
+type CrackOptions struct {
+	Timeout       time.Duration // 0 = no limit beyond ctx
+	MaxCandidates int           // 0 = no limit
+	Workers       int           // 0 = half the CPUs
+}
+
+// CrackResult reports the outcome of a Crack run.
+type CrackResult struct {
+	Found   bool
+	Secret  string
+	Tried   int
+	Elapsed time.Duration
+	Stopped string
+}
+
+// Crack tries each candidate as the HMAC secret of t until one reproduces its
+// signature, candidates run out, or a cap is reached. Only HS256, HS384 and HS512
+// tokens can be cracked.
+func Crack(ctx context.Context, t *Token, candidates iter.Seq[string], opts CrackOptions) (*CrackResult, error) {
+	newHash, err := hmacHash(t.Algorithm())
+	if err != nil {
+		return nil, err
+	}
+	if len(t.Signature) == 0 {
+		return nil, errors.New("token has no signature to crack")
+	}
+
+	workers := opts.Workers
+	if workers <= 0 {
+		workers = max(1, runtime.NumCPU()/2)
+	}
+	if opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.Timeout)
+		defer cancel()
+	}
+	ctx, stop := context.WithCancel(ctx)
+	defer stop()
+
+	start := time.Now()
+	input := []byte(t.signingInput)
+	batches := make(chan []string, workers)
+	var tried atomic.Int64
+	var found atomic.Pointer[string]
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for batch := range batches {
+				for _, candidate := range batch {
+					mac := hmac.New(newHash, []byte(candidate))
+					mac.Write(input)
+					if hmac.Equal(mac.Sum(nil), t.Signature) {
+						found.CompareAndSwap(nil, &candidate)
+						stop()
+					}
+				}
+				tried.Add(int64(len(batch)))
+			}
+		}()
+	}
+
+	stopped := StopExhausted
+	sent := 0
+	batch := make([]string, 0, crackBatchSize)
+	send := func() bool {
+		select {
+		case batches <- batch:
+			batch = make([]string, 0, crackBatchSize)
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}
+produce:
+	for candidate := range candidates {
+		if opts.MaxCandidates > 0 && sent >= opts.MaxCandidates {
+			stopped = StopLimit
+			break
+		}
+		batch = append(batch, candidate)
+		sent++
+		if len(batch) == crackBatchSize && !send() {
+			break produce
+		}
+	}
+	if len(batch) > 0 && ctx.Err() == nil {
+		send()
+	}
+	close(batches)
+	wg.Wait()
+
+	result := &CrackResult{Tried: int(tried.Load()), Elapsed: time.Since(start), Stopped: stopped}
+	if secret := found.Load(); secret != nil {
+		result.Found = true
+		result.Secret = *secret
+		result.Stopped = StopFound
+	} else if ctx.Err() != nil {
+		result.Stopped = StopTimeout
+	}
+	return result, nil
+}
+
+// Lines yields the non-empty lines of sc, without trailing carriage returns. Check
+// sc.Err after iterating.
+func Lines(sc *bufio.Scanner) iter.Seq[string] {
+	return func(yield func(string) bool) {
+		for sc.Scan() {
+			line := strings.TrimSuffix(sc.Text(), "\r")
+			if line != "" && !yield(line) {
+				return
+			}
+		}
+	}
+}
+
+// CommonSecrets are weak HMAC secrets seen in tutorials, framework defaults and
+// leaked configs, tried when no wordlist is given.
+var CommonSecrets = []string{
+	"secret", "Secret", "SECRET", "secretkey", "secret_key", "secret-key", "mysecret", "my_secret",
+	"jwt", "jwt_secret", "jwt-secret", "jwtsecret", "JWT_SECRET", "jwtkey", "jwt_key", "token", "tokensecret",
+	"key", "private", "privatekey", "private_key", "signing_key", "auth", "authsecret", "supersecret",
+	"password", "Password", "password1", "password123", "passw0rd", "123456", "12345678", "1234567890",
+	"qwerty", "admin", "administrator", "root", "test", "testing", "dev", "development", "changeme",
+	"change_me", "changethis", "default", "example", "demo", "hello", "letmein", "welcome", "s3cr3t",
+	"your-256-bit-secret", "your-384-bit-secret", "your-512-bit-secret", "your_jwt_secret", "your-secret-key",
+	"shhhhh", "shhhhhhared-secret", "keyboard cat", "gottacatchemall", "notasecret", "app_secret", "api_secret",
+	"django-insecure", "laravel", "rails", "node", "express", "spring", "flask", "nestjs",
+}
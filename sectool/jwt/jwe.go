@@ -0,0 +1,69 @@
+package jwt
+
+import (
+	"encoding/base64"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// JWE is a parsed compact-serialized JSON Web Encryption token. Only the protected
+// header is readable without the key; the other segments are kept as raw bytes.
+type JWE struct {
+	Header       map[string]interface{}
+	EncryptedKey []byte // empty for alg "dir"
+	IV           []byte
+	Ciphertext   []byte
+	Tag          []byte
+}
+
+// ParseJWE splits and decodes a compact JWE without decrypting it.
+func ParseJWE(s string) (*JWE, error) {
+	parts := strings.Split(strings.TrimSpace(s), ".")
+	if len(parts) != 5 {
+		return nil, fmt.Errorf("invalid JWE: expected 5 dot-separated parts, got %d", len(parts))
+	}
+
+	t := &JWE{}
+	if err := decodeSegment(parts[0], &t.Header); err != nil {
+		return nil, fmt.Errorf("invalid JWE header: %w", err)
+	}
+	for i, seg := range []*[]byte{&t.EncryptedKey, &t.IV, &t.Ciphertext, &t.Tag} {
+		b, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[i+1], "="))
+		if err != nil {
+			return nil, fmt.Errorf("invalid JWE %s: %w", jweSegments[i], err)
+		}
+		*seg = b
+	}
+	return t, nil
+}
+
+var jweSegments = []string{"encrypted key", "IV", "ciphertext", "tag"}
+
+// Algorithm returns the header alg value (the key management algorithm).
+func (t *JWE) Algorithm() string {
+	alg, _ := t.Header["alg"].(string)
+	return alg
+}
+
+// Encryption returns the header enc value (the content encryption algorithm).
+func (t *JWE) Encryption() string {
+	enc, _ := t.Header["enc"].(string)
+	return enc
+}
+
+// jweRe matches compact JWEs: a JSON header followed by four base64url segments, of
+// which the encrypted key is empty for direct encryption.
+var jweRe = regexp.MustCompile(`eyJ[A-Za-z0-9_-]*\.[A-Za-z0-9_-]*\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]*`)
+
+// FindJWE returns the distinct JWEs in text, in order of appearance.
+func FindJWE(text string) []string {
+	var found []string
+	for _, m := range jweRe.FindAllString(text, -1) {
+		if !slices.Contains(found, m) {
+			found = append(found, m)
+		}
+	}
+	return found
+}
@@ -0,0 +1,51 @@
+package jwt
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testJWE(header string, encryptedKey []byte) string {
+	enc := base64.RawURLEncoding
+	return strings.Join([]string{
+		enc.EncodeToString([]byte(header)),
+		enc.EncodeToString(encryptedKey),
+		enc.EncodeToString(make([]byte, 12)),
+		enc.EncodeToString([]byte("ciphertext")),
+		enc.EncodeToString(make([]byte, 16)),
+	}, ".")
+}
+
+func TestParseJWE(t *testing.T) {
+	t.Parallel()
+
+	token := testJWE(`{"alg":"RSA-OAEP","enc":"A256GCM","kid":"k1"}`, make([]byte, 256))
+	jwe, err := ParseJWE(token)
+	require.NoError(t, err)
+	assert.Equal(t, "RSA-OAEP", jwe.Algorithm())
+	assert.Equal(t, "A256GCM", jwe.Encryption())
+	assert.Equal(t, "k1", jwe.Header["kid"])
+	assert.Len(t, jwe.EncryptedKey, 256)
+	assert.Len(t, jwe.IV, 12)
+	assert.Equal(t, "ciphertext", string(jwe.Ciphertext))
+	assert.Len(t, jwe.Tag, 16)
+
+	_, err = ParseJWE(jwtIOToken)
+	assert.ErrorContains(t, err, "expected 5")
+	_, err = ParseJWE("eyJhbGciOiJkaXIifQ..!!.YQ.YQ")
+	assert.ErrorContains(t, err, "invalid JWE IV")
+}
+
+func TestFindJWE(t *testing.T) {
+	t.Parallel()
+
+	direct := testJWE(`{"alg":"dir","enc":"A128CBC-HS256"}`, nil)
+	wrapped := testJWE(`{"alg":"RSA1_5","enc":"A128GCM"}`, []byte("key"))
+	text := "Cookie: token=" + direct + "; other=" + wrapped + "\r\nAuthorization: Bearer " + jwtIOToken
+	assert.Equal(t, []string{direct, wrapped}, FindJWE(text))
+	assert.Empty(t, FindJWE(jwtIOToken))
+}
@@ -0,0 +1,96 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// signAsymmetric signs input with the PEM private key in keyPEM using an RS, PS, ES
+// or EdDSA algorithm.
+func signAsymmetric(alg string, keyPEM, input []byte) ([]byte, error) {
+	alg = strings.ToUpper(alg)
+	var h crypto.Hash
+	switch alg {
+	case "EDDSA":
+	case "RS256", "PS256", "ES256":
+		h = crypto.SHA256
+	case "RS384", "PS384", "ES384":
+		h = crypto.SHA384
+	case "RS512", "PS512", "ES512":
+		h = crypto.SHA512
+	default:
+		return nil, errors.New("unsupported algorithm " + alg)
+	}
+
+	key, err := parsePrivateKey(keyPEM)
+	if err != nil {
+		return nil, fmt.Errorf("%s needs a PEM private key: %w", alg, err)
+	}
+
+	if alg == "EDDSA" {
+		k, ok := key.(ed25519.PrivateKey)
+		if !ok {
+			return nil, fmt.Errorf("EdDSA needs an Ed25519 key, got %T", key)
+		}
+		return ed25519.Sign(k, input), nil
+	}
+
+	hasher := h.New()
+	hasher.Write(input)
+	digest := hasher.Sum(nil)
+
+	switch alg[:2] {
+	case "RS", "PS":
+		k, ok := key.(*rsa.PrivateKey)
+		if !ok {
+			return nil, fmt.Errorf("%s needs an RSA key, got %T", alg, key)
+		} else if alg[0] == 'P' {
+			return rsa.SignPSS(rand.Reader, k, h, digest, &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash})
+		}
+		return rsa.SignPKCS1v15(rand.Reader, k, h, digest)
+	default:
+		k, ok := key.(*ecdsa.PrivateKey)
+		if !ok {
+			return nil, fmt.Errorf("%s needs an ECDSA key, got %T", alg, key)
+		}
+		r, s, err := ecdsa.Sign(rand.Reader, k, digest)
+		if err != nil {
+			return nil, err
+		}
+		// JWS uses the fixed-width r || s encoding rather than ASN.1
+		size := (k.Curve.Params().BitSize + 7) / 8
+		sig := make([]byte, 2*size)
+		r.FillBytes(sig[:size])
+		s.FillBytes(sig[size:])
+		return sig, nil
+	}
+}
+
+// parsePrivateKey decodes a PKCS#8, PKCS#1 or SEC 1 PEM private key.
+func parsePrivateKey(data []byte) (crypto.Signer, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM block found")
+	}
+	if key, err := x509.ParsePKCS8PrivateKey(block.Bytes); err == nil {
+		if signer, ok := key.(crypto.Signer); ok {
+			return signer, nil
+		}
+		return nil, fmt.Errorf("unsupported key type %T", key)
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	if key, err := x509.ParseECPrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	return nil, fmt.Errorf("unsupported PEM block %q: expected a private key", block.Type)
+}
@@ -0,0 +1,182 @@
+// Package jwt parses, signs and attacks JSON Web Tokens locally.
+package jwt
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Token is a parsed compact-serialized JWT.
+type Token struct {
+	Header    map[string]interface{}
+	Claims    map[string]interface{}
+	Signature []byte
+
+	signingInput string // base64url header "." base64url payload, as received
+}
+
+// Parse splits and decodes a compact JWT without verifying it.
+func Parse(s string) (*Token, error) {
+	parts := strings.Split(strings.TrimSpace(s), ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("invalid JWT: expected 3 dot-separated parts, got %d", len(parts))
+	}
+
+	t := &Token{signingInput: parts[0] + "." + parts[1]}
+	if err := decodeSegment(parts[0], &t.Header); err != nil {
+		return nil, fmt.Errorf("invalid JWT header: %w", err)
+	}
+	if err := decodeSegment(parts[1], &t.Claims); err != nil {
+		return nil, fmt.Errorf("invalid JWT payload: %w", err)
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[2], "="))
+	if err != nil {
+		return nil, fmt.Errorf("invalid JWT signature: %w", err)
+	}
+	t.Signature = sig
+	return t, nil
+}
+
+// Algorithm returns the header alg value.
+func (t *Token) Algorithm() string {
+	alg, _ := t.Header["alg"].(string)
+	return alg
+}
+
+// Sign encodes header and claims and signs them with alg, which replaces the header
+// alg. key is the shared secret for HS256/HS384/HS512 and a PEM private key for the
+// RS, PS, ES and EdDSA algorithms. "none" in any casing produces an empty signature.
+func Sign(header, claims map[string]interface{}, alg string, key []byte) (string, error) {
+	h := make(map[string]interface{}, len(header)+1)
+	for k, v := range header {
+		h[k] = v
+	}
+	h["alg"] = alg
+
+	headerJSON, err := json.Marshal(h)
+	if err != nil {
+		return "", err
+	}
+	claimsJSON, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+	input := base64.RawURLEncoding.EncodeToString(headerJSON) + "." + base64.RawURLEncoding.EncodeToString(claimsJSON)
+
+	var sig []byte
+	if strings.EqualFold(alg, "none") {
+		return input + ".", nil
+	} else if newHash, err := hmacHash(alg); err == nil {
+		mac := hmac.New(newHash, key)
+		mac.Write([]byte(input))
+		sig = mac.Sum(nil)
+	} else if sig, err = signAsymmetric(alg, key, []byte(input)); err != nil {
+		return "", err
+	}
+	return input + "." + base64.RawURLEncoding.EncodeToString(sig), nil
+}
+
+// hmacHash returns the hash constructor for an HMAC JWT algorithm.
+func hmacHash(alg string) (func() hash.Hash, error) {
+	switch strings.ToUpper(alg) {
+	case "HS256":
+		return sha256.New, nil
+	case "HS384":
+		return sha512.New384, nil
+	case "HS512":
+		return sha512.New, nil
+	}
+	return nil, errors.New("unsupported algorithm " + alg + ": only HS256, HS384 and HS512 use a shared secret")
+}
+
+// tokenRe matches compact JWTs embedded in headers, cookies and bodies. Both the
+// header and payload are JSON objects, so they start with base64url "{".
+var tokenRe = regexp.MustCompile(`eyJ[A-Za-z0-9_-]*\.eyJ[A-Za-z0-9_-]*\.[A-Za-z0-9_-]*`)
+
+// Find returns the distinct JWTs in text, in order of appearance.
+func Find(text string) []string {
+	var found []string
+	for _, m := range tokenRe.FindAllString(text, -1) {
+		if !slices.Contains(found, m) {
+			found = append(found, m)
+		}
+	}
+	return found
+}
+
+// NumericDate returns claim as a time when it holds a JWT NumericDate (seconds).
+func (t *Token) NumericDate(claim string) (time.Time, bool) {
+	secs, ok := t.Claims[claim].(float64)
+	if !ok {
+		return time.Time{}, false
+	}
+	return time.Unix(int64(secs), 0).UTC(), true
+}
+
+func decodeSegment(seg string, v interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(seg, "="))
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// forgeExpiry is how far Forge pushes exp when no claims are set explicitly.
+const forgeExpiry = 24 * time.Hour
+
+// Forge re-signs t with secret after applying set to its claims. With no claims to
+// set, an existing exp is extended by a day so the token is immediately usable.
+// Returns the token and the names of the claims it changed.
+func Forge(t *Token, secret string, set map[string]interface{}) (string, []string, error) {
+	header, claims, changed := Tamper(t, nil, set, nil)
+	if len(set) == 0 {
+		if _, ok := claims["exp"]; ok {
+			claims["exp"] = time.Now().Add(forgeExpiry).Unix()
+			changed = append(changed, "exp")
+		}
+	}
+
+	token, err := Sign(header, claims, t.Algorithm(), []byte(secret))
+	return token, changed, err
+}
+
+// Tamper returns copies of t's header and claims with setHeader and setClaims applied
+// and removeClaims deleted, and the sorted names of the claims that changed.
+func Tamper(t *Token, setHeader, setClaims map[string]interface{}, removeClaims []string) (map[string]interface{}, map[string]interface{}, []string) {
+	header := make(map[string]interface{}, len(t.Header)+len(setHeader))
+	for k, v := range t.Header {
+		header[k] = v
+	}
+	for k, v := range setHeader {
+		header[k] = v
+	}
+
+	claims := make(map[string]interface{}, len(t.Claims)+len(setClaims))
+	for k, v := range t.Claims {
+		claims[k] = v
+	}
+	var changed []string
+	for k, v := range setClaims {
+		claims[k] = v
+		changed = append(changed, k)
+	}
+	for _, k := range removeClaims {
+		if _, ok := claims[k]; ok {
+			delete(claims, k)
+			changed = append(changed, k)
+		}
+	}
+	sort.Strings(changed)
+	return header, claims, changed
+}
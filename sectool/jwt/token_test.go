@@ -0,0 +1,165 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/pem"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	tok, err := Parse(jwtIOToken)
+	require.NoError(t, err)
+	assert.Equal(t, "HS256", tok.Algorithm())
+	assert.Equal(t, "John Doe", tok.Claims["name"])
+	assert.Len(t, tok.Signature, 32)
+
+	_, err = Parse("not.a.jwt")
+	assert.Error(t, err)
+	_, err = Parse("onlyonepart")
+	assert.ErrorContains(t, err, "expected 3")
+}
+
+func TestSignRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	tok, err := Parse(jwtIOToken)
+	require.NoError(t, err)
+	signed, err := Sign(tok.Header, tok.Claims, "HS256", []byte("your-256-bit-secret"))
+	require.NoError(t, err)
+
+	// Key order differs from the original, so verify by signature rather than string
+	resigned, err := Parse(signed)
+	require.NoError(t, err)
+	assert.Equal(t, tok.Claims, resigned.Claims)
+	res, err := Crack(t.Context(), resigned, func(yield func(string) bool) { yield("your-256-bit-secret") }, CrackOptions{})
+	require.NoError(t, err)
+	assert.True(t, res.Found)
+
+	_, err = Sign(tok.Header, tok.Claims, "RS256", []byte("x"))
+	assert.Error(t, err)
+}
+
+func TestForge(t *testing.T) {
+	t.Parallel()
+
+	tok, err := Parse(jwtIOToken)
+	require.NoError(t, err)
+
+	t.Run("set_claims", func(t *testing.T) {
+		forged, changed, err := Forge(tok, "your-256-bit-secret", map[string]interface{}{"role": "admin", "sub": "1"})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"role", "sub"}, changed)
+		parsed, err := Parse(forged)
+		require.NoError(t, err)
+		assert.Equal(t, "admin", parsed.Claims["role"])
+		assert.Equal(t, "John Doe", parsed.Claims["name"])
+	})
+
+	t.Run("extends_exp", func(t *testing.T) {
+		expiring := &Token{Header: tok.Header, Claims: map[string]interface{}{"exp": float64(1)}}
+		forged, changed, err := Forge(expiring, "k", nil)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"exp"}, changed)
+		parsed, err := Parse(forged)
+		require.NoError(t, err)
+		assert.Greater(t, parsed.Claims["exp"], float64(time.Now().Unix()))
+	})
+}
+
+func TestSignAlgorithms(t *testing.T) {
+	t.Parallel()
+
+	claims := map[string]interface{}{"sub": "42"}
+	digest := func(token string) []byte {
+		sum := sha256.Sum256([]byte(token[:strings.LastIndex(token, ".")]))
+		return sum[:]
+	}
+
+	t.Run("none", func(t *testing.T) {
+		signed, err := Sign(nil, claims, "nOnE", nil)
+		require.NoError(t, err)
+		assert.True(t, strings.HasSuffix(signed, "."))
+		tok, err := Parse(signed)
+		require.NoError(t, err)
+		assert.Equal(t, "nOnE", tok.Algorithm())
+		assert.Empty(t, tok.Signature)
+	})
+
+	t.Run("rs256", func(t *testing.T) {
+		key, err := rsa.GenerateKey(rand.Reader, 2048)
+		require.NoError(t, err)
+		keyPEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+
+		signed, err := Sign(map[string]interface{}{"typ": "JWT"}, claims, "RS256", keyPEM)
+		require.NoError(t, err)
+		tok, err := Parse(signed)
+		require.NoError(t, err)
+		assert.NoError(t, rsa.VerifyPKCS1v15(&key.PublicKey, crypto.SHA256, digest(signed), tok.Signature))
+
+		_, err = Sign(nil, claims, "ES256", keyPEM)
+		assert.ErrorContains(t, err, "needs an ECDSA key")
+	})
+
+	t.Run("es256", func(t *testing.T) {
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		require.NoError(t, err)
+		der, err := x509.MarshalPKCS8PrivateKey(key)
+		require.NoError(t, err)
+		keyPEM := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+
+		signed, err := Sign(nil, claims, "ES256", keyPEM)
+		require.NoError(t, err)
+		tok, err := Parse(signed)
+		require.NoError(t, err)
+		require.Len(t, tok.Signature, 64)
+		r, s := new(big.Int).SetBytes(tok.Signature[:32]), new(big.Int).SetBytes(tok.Signature[32:])
+		assert.True(t, ecdsa.Verify(&key.PublicKey, digest(signed), r, s))
+	})
+
+	t.Run("bad_key", func(t *testing.T) {
+		_, err := Sign(nil, claims, "RS256", []byte("secret"))
+		assert.ErrorContains(t, err, "PEM private key")
+	})
+}
+
+func TestFind(t *testing.T) {
+	t.Parallel()
+
+	text := "Authorization: Bearer " + jwtIOToken + "\r\nCookie: a=b; session=" + jwtIOToken + "\r\n\r\n{\"id_token\":\"eyJhbGciOiJub25lIn0.eyJzdWIiOiIxIn0.\"}"
+	assert.Equal(t, []string{jwtIOToken, "eyJhbGciOiJub25lIn0.eyJzdWIiOiIxIn0."}, Find(text))
+	assert.Empty(t, Find("eyJhbGciOiJub25lIn0 not a token"))
+}
+
+func TestTamper(t *testing.T) {
+	t.Parallel()
+
+	tok, err := Parse(jwtIOToken)
+	require.NoError(t, err)
+	header, claims, changed := Tamper(tok, map[string]interface{}{"kid": "../../dev/null"}, map[string]interface{}{"admin": true}, []string{"iat", "missing"})
+	assert.Equal(t, []string{"admin", "iat"}, changed)
+	assert.Equal(t, "../../dev/null", header["kid"])
+	assert.Equal(t, true, claims["admin"])
+	assert.NotContains(t, claims, "iat")
+	assert.Contains(t, tok.Claims, "iat")
+	assert.NotContains(t, tok.Header, "kid")
+
+	iat, ok := tok.NumericDate("iat")
+	require.True(t, ok)
+	assert.Equal(t, int64(1516239022), iat.Unix())
+	_, ok = tok.NumericDate("name")
+	assert.False(t, ok)
+}
@@ -0,0 +1,215 @@
+package jwtcli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/pflag"
+
+	"github.com/go-harden/llm-security-toolbox/sectool/cli"
+)
+
+var jwtSubcommands = []string{"decode", "forge", "crack", "help"}
+
+// Parse handles `sectool jwt`.
+func Parse(args []string) error {
+	if len(args) < 1 {
+		printUsage()
+		return errors.New("subcommand required")
+	}
+
+	switch args[0] {
+	case "decode":
+		return parseDecode(args[1:])
+	case "forge":
+		return parseForge(args[1:])
+	case "crack":
+		return parseCrack(args[1:])
+	case "help", "--help", "-h":
+		printUsage()
+		return nil
+	default:
+		return cli.UnknownSubcommandError("jwt", args[0], jwtSubcommands)
+	}
+}
+
+func printUsage() {
+	_, _ = fmt.Fprint(os.Stderr, `Usage: sectool jwt <command> [options]
+
+JSON Web Token attacks. Runs locally, no service required.
+
+---
+
+jwt decode [token|-]
+
+  Decode JWTs without verifying them. The argument may be a token, or any
+  header/body text containing tokens; - or no argument reads stdin. JWEs
+  (5 parts) show their header and segment sizes; the payload stays encrypted.
+
+  Examples:
+    sectool jwt decode eyJhbGciOi...
+    pbpaste | sectool jwt decode
+
+  Output: header, claims and iat/nbf/exp times of each token; header and
+  segment sizes of each JWE
+
+---
+
+jwt forge <token> [options]
+
+  Modify claims or header fields and re-sign the token.
+
+  Options:
+    --set <claim=value>     claim to set (repeatable)
+    --unset <claim>         claim to remove (repeatable)
+    --header <name=value>   header field to set (repeatable), e.g. kid, jku
+    --alg <alg>             signing algorithm (default: the token's);
+                            none/None/NONE produce an unsigned token
+    --secret <secret>       HMAC secret for HS256/HS384/HS512
+    --guess                 recover the HMAC secret from common weak secrets
+    --key <path>            PEM private key for RS*/PS*/ES*/EdDSA
+    --expires-in <dur>      set exp to now plus dur
+
+  Examples:
+    sectool jwt forge eyJhbGciOi... --alg none --set role=admin
+    sectool jwt forge eyJhbGciOi... --guess --expires-in 24h
+    sectool jwt forge eyJhbGciOi... --alg HS256 --secret "$(cat public.pem)"
+
+  Output: the forged token and an Authorization header line
+
+---
+
+jwt crack <token> [options]
+
+  Recover the secret of an HS256/HS384/HS512 token by dictionary attack.
+  Without -w, tries a built-in list of common weak secrets.
+
+  Options:
+    -w, --wordlist <path>   wordlist file, one candidate per line (- for stdin)
+    --timeout <dur>         time cap (default: 5m)
+    --max <n>               stop after n candidates
+    --workers <n>           parallel workers (default: half the CPUs)
+    --set <claim=value>     claim to set in the forged token (repeatable)
+
+  Examples:
+    sectool jwt crack eyJhbGciOi...
+    sectool jwt crack eyJhbGciOi... -w rockyou.txt --set role=admin
+
+  Output: the secret and a forged token (exp extended a day unless --set is used)
+`)
+}
+
+func parseCrack(args []string) error {
+	fs := pflag.NewFlagSet("jwt crack", pflag.ContinueOnError)
+	fs.SetInterspersed(true)
+	var wordlist string
+	var timeout time.Duration
+	var maxCandidates, workers int
+	var set []string
+
+	fs.StringVarP(&wordlist, "wordlist", "w", "", "wordlist file, one candidate per line (- for stdin)")
+	fs.DurationVar(&timeout, "timeout", 5*time.Minute, "time cap")
+	fs.IntVar(&maxCandidates, "max", 0, "stop after n candidates")
+	fs.IntVar(&workers, "workers", 0, "parallel workers (default: half the CPUs)")
+	fs.StringArrayVar(&set, "set", nil, "claim=value to set in the forged token (repeatable)")
+
+	fs.Usage = func() {
+		_, _ = fmt.Fprint(os.Stderr, `Usage: sectool jwt crack <token> [options]
+
+Recover the signing secret of an HMAC JWT and print a forged token.
+
+Options:
+`)
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	} else if len(fs.Args()) < 1 {
+		fs.Usage()
+		return errors.New("token required")
+	}
+
+	claims, err := parseClaims(set)
+	if err != nil {
+		return err
+	}
+	return crack(fs.Args()[0], wordlist, timeout, maxCandidates, workers, claims)
+}
+
+func parseDecode(args []string) error {
+	fs := pflag.NewFlagSet("jwt decode", pflag.ContinueOnError)
+	fs.SetInterspersed(true)
+
+	fs.Usage = func() {
+		_, _ = fmt.Fprint(os.Stderr, `Usage: sectool jwt decode [token|-]
+
+Decode JWTs and JWEs in the argument or stdin without verifying them.
+`)
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	input := "-"
+	if len(fs.Args()) > 0 {
+		input = fs.Args()[0]
+	}
+	return decode(input)
+}
+
+func parseForge(args []string) error {
+	fs := pflag.NewFlagSet("jwt forge", pflag.ContinueOnError)
+	fs.SetInterspersed(true)
+	var set, unset, header []string
+	var alg, secret, keyFile string
+	var guess bool
+	var expiresIn time.Duration
+
+	fs.StringArrayVar(&set, "set", nil, "claim=value to set (repeatable)")
+	fs.StringArrayVar(&unset, "unset", nil, "claim to remove (repeatable)")
+	fs.StringArrayVar(&header, "header", nil, "header name=value to set (repeatable)")
+	fs.StringVar(&alg, "alg", "", "signing algorithm (default: the token's)")
+	fs.StringVar(&secret, "secret", "", "HMAC secret")
+	fs.BoolVar(&guess, "guess", false, "recover the HMAC secret from common weak secrets")
+	fs.StringVar(&keyFile, "key", "", "PEM private key file for RS*/PS*/ES*/EdDSA")
+	fs.DurationVar(&expiresIn, "expires-in", 0, "set exp to now plus this duration")
+
+	fs.Usage = func() {
+		_, _ = fmt.Fprint(os.Stderr, `Usage: sectool jwt forge <token> [options]
+
+Modify a JWT's claims or header and re-sign it.
+
+Options:
+`)
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	} else if len(fs.Args()) < 1 {
+		fs.Usage()
+		return errors.New("token required")
+	}
+
+	claims, err := parseClaims(set)
+	if err != nil {
+		return err
+	}
+	headerFields, err := parseClaims(header)
+	if err != nil {
+		return err
+	}
+	return forge(fs.Args()[0], forgeOptions{
+		setClaims:    claims,
+		removeClaims: unset,
+		setHeader:    headerFields,
+		alg:          alg,
+		secret:       secret,
+		guess:        guess,
+		keyFile:      keyFile,
+		expiresIn:    expiresIn,
+	})
+}
@@ -0,0 +1,229 @@
+package jwtcli
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"iter"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/go-harden/llm-security-toolbox/sectool/cliutil"
+	"github.com/go-harden/llm-security-toolbox/sectool/jwt"
+)
+
+func crack(rawToken, wordlist string, timeout time.Duration, maxCandidates, workers int, claims map[string]interface{}) error {
+	token, err := jwt.Parse(rawToken)
+	if err != nil {
+		return err
+	}
+
+	var candidates iter.Seq[string] = slices.Values(jwt.CommonSecrets)
+	var scanner *bufio.Scanner
+	if wordlist != "" {
+		var r io.Reader = os.Stdin
+		if wordlist != "-" {
+			f, err := os.Open(wordlist)
+			if err != nil {
+				return fmt.Errorf("open wordlist: %w", err)
+			}
+			defer func() { _ = f.Close() }()
+			r = f
+		}
+		scanner = bufio.NewScanner(r)
+		candidates = jwt.Lines(scanner)
+	}
+
+	res, err := jwt.Crack(context.Background(), token, candidates, jwt.CrackOptions{
+		Timeout:       timeout,
+		MaxCandidates: maxCandidates,
+		Workers:       workers,
+	})
+	if err != nil {
+		return err
+	} else if scanner != nil && scanner.Err() != nil {
+		return fmt.Errorf("read wordlist: %w", scanner.Err())
+	}
+
+	elapsed := res.Elapsed.Round(time.Millisecond)
+	if !res.Found {
+		fmt.Printf("Secret not found (%s after %d candidates in %s)\n", res.Stopped, res.Tried, elapsed)
+		if wordlist == "" {
+			cliutil.Hintf("Only common secrets were tried; pass a larger list with `-w <path>`\n")
+		}
+		return nil
+	}
+
+	fmt.Printf("Secret: `%s` (%s, found after %d candidates in %s)\n", res.Secret, token.Algorithm(), res.Tried, elapsed)
+	forged, changed, err := jwt.Forge(token, res.Secret, claims)
+	if err != nil {
+		return err
+	}
+	if len(changed) > 0 {
+		fmt.Printf("Forged token (changed: %s):\n", strings.Join(changed, ", "))
+	} else {
+		fmt.Println("Re-signed token:")
+	}
+	fmt.Println(forged)
+	return nil
+}
+
+func decode(input string) error {
+	text := input
+	if input == "-" {
+		data, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return fmt.Errorf("read stdin: %w", err)
+		}
+		text = string(data)
+	}
+
+	tokens, encrypted := jwt.Find(text), jwt.FindJWE(text)
+	if len(tokens) == 0 && len(encrypted) == 0 {
+		// Not eyJ-prefixed, but may still be a token; Parse reports why it is not
+		if trimmed := strings.TrimSpace(text); strings.Count(trimmed, ".") == 4 {
+			encrypted = []string{trimmed}
+		} else {
+			tokens = []string{trimmed}
+		}
+	}
+	for i, raw := range tokens {
+		t, err := jwt.Parse(raw)
+		if err != nil {
+			return err
+		}
+		if i > 0 {
+			fmt.Println()
+		}
+		if len(tokens) > 1 {
+			fmt.Printf("## Token %d\n\n", i+1)
+		}
+		printJSON("Header", t.Header)
+		printJSON("Claims", t.Claims)
+		for _, claim := range []string{"iat", "nbf", "exp"} {
+			if ts, ok := t.NumericDate(claim); ok {
+				note := ""
+				if claim == "exp" && time.Now().After(ts) {
+					note = " (expired)"
+				}
+				fmt.Printf("%s: %s%s\n", claim, ts.Format(time.RFC3339), note)
+			}
+		}
+	}
+	for i, raw := range encrypted {
+		t, err := jwt.ParseJWE(raw)
+		if err != nil {
+			return err
+		}
+		if i > 0 || len(tokens) > 0 {
+			fmt.Println()
+		}
+		fmt.Printf("## JWE %d (encrypted)\n\n", i+1)
+		printJSON("Header", t.Header)
+		fmt.Printf("Encrypted key: %d bytes, IV: %d bytes, ciphertext: %d bytes, tag: %d bytes\n",
+			len(t.EncryptedKey), len(t.IV), len(t.Ciphertext), len(t.Tag))
+	}
+	if len(tokens) > 0 {
+		cliutil.Hintf("\nTo tamper: `sectool jwt forge <token> --set <claim=value> --alg none`\n")
+	}
+	return nil
+}
+
+func printJSON(title string, v interface{}) {
+	data, _ := json.MarshalIndent(v, "", "  ")
+	fmt.Printf("%s:\n```json\n%s\n```\n", title, data)
+}
+
+type forgeOptions struct {
+	setClaims    map[string]interface{}
+	removeClaims []string
+	setHeader    map[string]interface{}
+	alg          string
+	secret       string
+	guess        bool
+	keyFile      string
+	expiresIn    time.Duration
+}
+
+func forge(rawToken string, opts forgeOptions) error {
+	token, err := jwt.Parse(rawToken)
+	if err != nil {
+		return err
+	}
+	alg := opts.alg
+	if alg == "" {
+		alg = token.Algorithm()
+	}
+	if opts.expiresIn > 0 {
+		if opts.setClaims == nil {
+			opts.setClaims = make(map[string]interface{}, 1)
+		}
+		opts.setClaims["exp"] = time.Now().Add(opts.expiresIn).Unix()
+	}
+
+	var key []byte
+	switch upper := strings.ToUpper(alg); {
+	case upper == "NONE":
+	case strings.HasPrefix(upper, "HS"):
+		if opts.secret != "" {
+			key = []byte(opts.secret)
+		} else if opts.guess {
+			res, err := jwt.Crack(context.Background(), token, slices.Values(jwt.CommonSecrets), jwt.CrackOptions{})
+			if err != nil {
+				return err
+			} else if !res.Found {
+				return fmt.Errorf("secret not among %d common secrets: try `sectool jwt crack -w <wordlist>` or pass --secret", res.Tried)
+			}
+			fmt.Printf("Secret: `%s`\n", res.Secret)
+			key = []byte(res.Secret)
+		} else {
+			return fmt.Errorf("%s requires --secret or --guess", alg)
+		}
+	default:
+		if opts.keyFile == "" {
+			return fmt.Errorf("%s requires --key <pem file>", alg)
+		}
+		if key, err = os.ReadFile(opts.keyFile); err != nil {
+			return fmt.Errorf("read key: %w", err)
+		}
+	}
+
+	header, claims, changed := jwt.Tamper(token, opts.setHeader, opts.setClaims, opts.removeClaims)
+	forged, err := jwt.Sign(header, claims, alg, key)
+	if err != nil {
+		return err
+	}
+	if len(changed) > 0 {
+		fmt.Printf("Forged %s token (changed: %s):\n", alg, strings.Join(changed, ", "))
+	} else {
+		fmt.Printf("Re-signed %s token:\n", alg)
+	}
+	fmt.Println(forged)
+	cliutil.Hintf("\nTo send: `sectool replay send --flow <flow_id> --set-header \"Authorization: Bearer <token>\"`\n")
+	return nil
+}
+
+// parseClaims parses claim=value pairs; values that are valid JSON (numbers, booleans,
+// objects) keep their type, anything else is a string.
+func parseClaims(pairs []string) (map[string]interface{}, error) {
+	if len(pairs) == 0 {
+		return nil, nil
+	}
+	claims := make(map[string]interface{}, len(pairs))
+	for _, pair := range pairs {
+		name, value, ok := strings.Cut(pair, "=")
+		if !ok || name == "" {
+			return nil, fmt.Errorf("invalid pair %q: expected name=value", pair)
+		}
+		var v interface{}
+		if err := json.Unmarshal([]byte(value), &v); err != nil {
+			v = value
+		}
+		claims[name] = v
+	}
+	return claims, nil
+}
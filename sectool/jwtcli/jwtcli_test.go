@@ -0,0 +1,19 @@
+package jwtcli
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseClaims(t *testing.T) {
+	t.Parallel()
+
+	claims, err := parseClaims([]string{"role=admin", "uid=1", "admin=true", "note=a=b"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"role": "admin", "uid": float64(1), "admin": true, "note": "a=b"}, claims)
+
+	_, err = parseClaims([]string{"role"})
+	assert.Error(t, err)
+}
@@ -10,17 +10,42 @@ import (
 	"github.com/spf13/pflag"
 
 	"github.com/go-harden/llm-security-toolbox/sectool/cli"
+	"github.com/go-harden/llm-security-toolbox/sectool/cliutil"
 	"github.com/go-harden/llm-security-toolbox/sectool/config"
+	"github.com/go-harden/llm-security-toolbox/sectool/configcli"
 	"github.com/go-harden/llm-security-toolbox/sectool/crawl"
 	"github.com/go-harden/llm-security-toolbox/sectool/encode"
+	"github.com/go-harden/llm-security-toolbox/sectool/envcli"
+	"github.com/go-harden/llm-security-toolbox/sectool/exportcli"
+	"github.com/go-harden/llm-security-toolbox/sectool/graphql"
+	"github.com/go-harden/llm-security-toolbox/sectool/grpccli"
+	"github.com/go-harden/llm-security-toolbox/sectool/identity"
+	"github.com/go-harden/llm-security-toolbox/sectool/jwtcli"
+	"github.com/go-harden/llm-security-toolbox/sectool/mcpclient"
 	"github.com/go-harden/llm-security-toolbox/sectool/oast"
+	"github.com/go-harden/llm-security-toolbox/sectool/payloadcli"
 	"github.com/go-harden/llm-security-toolbox/sectool/proxy"
 	"github.com/go-harden/llm-security-toolbox/sectool/replay"
+	"github.com/go-harden/llm-security-toolbox/sectool/reportcli"
+	"github.com/go-harden/llm-security-toolbox/sectool/request"
+	"github.com/go-harden/llm-security-toolbox/sectool/scan"
+	"github.com/go-harden/llm-security-toolbox/sectool/sequence"
 	"github.com/go-harden/llm-security-toolbox/sectool/service"
+	"github.com/go-harden/llm-security-toolbox/sectool/session"
+	"github.com/go-harden/llm-security-toolbox/sectool/spec"
+	"github.com/go-harden/llm-security-toolbox/sectool/status"
+	"github.com/go-harden/llm-security-toolbox/sectool/ui"
+	"github.com/go-harden/llm-security-toolbox/sectool/update"
+	"github.com/go-harden/llm-security-toolbox/sectool/ws"
 )
 
 func main() {
 	globalFlags, args := parseGlobalFlags(os.Args[1:])
+	cliutil.NoPager = globalFlags.NoPager
+	cliutil.Level = globalFlags.Verbosity
+	if globalFlags.ConfigPath != "" {
+		mcpclient.ConfigPath = globalFlags.ConfigPath
+	}
 
 	if len(args) < 1 {
 		printRootUsage()
@@ -31,9 +56,17 @@ func main() {
 	switch args[0] {
 	// Commands that don't need MCP client
 	case "mcp":
-		os.Exit(runServiceMode(args[1:]))
+		os.Exit(runServiceMode(service.ParseMCPServerFlags, args[1:], globalFlags))
 	case "encode":
 		err = encode.Parse(args[1:])
+	case "jwt":
+		err = jwtcli.Parse(args[1:])
+	case "payloads":
+		err = payloadcli.Parse(args[1:])
+	case "config":
+		err = configcli.Parse(args[1:], globalFlags.ConfigPath, config.ResolveProfile(globalFlags.Profile))
+	case "update":
+		err = update.Parse(args[1:])
 	case "version", "--version", "-v":
 		_, _ = fmt.Printf("sectool version %s\n", config.Version)
 		return
@@ -42,7 +75,11 @@ func main() {
 		return
 
 	// Commands that need MCP client
-	case "proxy", "replay", "oast", "crawl":
+	case "proxy", "replay", "request", "env", "session", "sequence", "identity", "spec", "graphql", "grpc", "ws", "oast", "scan", "crawl", "report", "export", "ui", "status":
+		if args[0] == "proxy" && len(args) > 1 && args[1] == "start" {
+			// Runs the service with the built-in proxy rather than connecting to one
+			os.Exit(runServiceMode(service.ParseProxyStartFlags, args[2:], globalFlags))
+		}
 		var mcpURL string
 		mcpURL, err = getMCPURL(globalFlags)
 		if err != nil {
@@ -54,14 +91,42 @@ func main() {
 			err = proxy.Parse(args[1:], mcpURL)
 		case "replay":
 			err = replay.Parse(args[1:], mcpURL)
+		case "request":
+			err = request.Parse(args[1:], mcpURL)
+		case "env":
+			err = envcli.Parse(args[1:], mcpURL)
+		case "session":
+			err = session.Parse(args[1:], mcpURL)
+		case "sequence":
+			err = sequence.Parse(args[1:], mcpURL)
+		case "identity":
+			err = identity.Parse(args[1:], mcpURL)
+		case "spec":
+			err = spec.Parse(args[1:], mcpURL)
+		case "graphql":
+			err = graphql.Parse(args[1:], mcpURL)
+		case "grpc":
+			err = grpccli.Parse(args[1:], mcpURL)
+		case "ws":
+			err = ws.Parse(args[1:], mcpURL)
 		case "oast":
 			err = oast.Parse(args[1:], mcpURL)
+		case "scan":
+			err = scan.Parse(args[1:], mcpURL)
 		case "crawl":
 			err = crawl.Parse(args[1:], mcpURL)
+		case "report":
+			err = reportcli.Parse(args[1:], mcpURL)
+		case "export":
+			err = exportcli.Parse(args[1:], mcpURL)
+		case "ui":
+			err = ui.Parse(args[1:], mcpURL)
+		case "status":
+			err = status.Parse(args[1:], mcpURL)
 		}
 
 	default:
-		validCommands := []string{"mcp", "proxy", "replay", "oast", "crawl", "encode", "version", "help"}
+		validCommands := []string{"mcp", "proxy", "replay", "request", "env", "session", "sequence", "identity", "spec", "graphql", "grpc", "ws", "oast", "scan", "crawl", "report", "export", "ui", "status", "encode", "jwt", "payloads", "config", "update", "version", "help"}
 		err = cli.UnknownCommandError(args[0], validCommands)
 	}
 
@@ -70,16 +135,25 @@ func main() {
 			return
 		}
 		_, _ = fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-		os.Exit(1)
+		os.Exit(cli.ExitCode(err))
 	}
 }
 
-func runServiceMode(args []string) int {
-	flags, err := service.ParseMCPServerFlags(args)
-	if err != nil {
+func runServiceMode(parse func([]string) (service.MCPServerFlags, error), args []string, global globalFlags) int {
+	flags, err := parse(args)
+	if errors.Is(err, pflag.ErrHelp) {
+		return 0
+	} else if err != nil {
 		_, _ = fmt.Fprintf(os.Stderr, "Error parsing service flags: %v\n", err)
 		return 1
 	}
+	// Global flags are stripped before dispatch, so carry them over
+	if flags.ConfigPath == "" {
+		flags.ConfigPath = global.ConfigPath
+	}
+	if flags.Profile == "" {
+		flags.Profile = config.ResolveProfile(global.Profile)
+	}
 
 	if srv, err := service.NewServer(flags, nil, nil, nil); err != nil {
 		_, _ = fmt.Fprintf(os.Stderr, "Error creating service: %v\n", err)
@@ -96,15 +170,46 @@ func printRootUsage() {
 
 Commands:
   mcp        Start MCP server (required before other commands work)
-  proxy      Query and manage proxy history
+  proxy      Query and manage proxy history, or run the built-in proxy
   replay     Replay HTTP requests (with modifications)
+  request    Craft and send new HTTP requests without a proxy flow
+  env        Request variable environments ({{base_url}}, {{token}})
+  session    Login sessions that refresh replay auth tokens on 401/403
+  sequence   Multi-step request flows with values carried between steps
+  identity   Per-user credentials, replay as another user, IDOR matrix
+  spec       Import OpenAPI/Swagger specs as request templates
+  graphql    Introspect GraphQL schemas into operation templates
+  grpc       Decode, edit and re-send gRPC calls
+  ws         List and replay WebSocket messages
   oast       Manage OAST domains for out-of-band testing
+  scan       Burp Scanner crawl/audit and issues (Burp Suite Professional)
   crawl      Web crawler for URL and form discovery
-  encode     Encoding/decoding utilities (url, base64, html)
+  report     Render recorded findings as Markdown, HTML or SARIF
+  export     Export proxy history, replays (HAR, JSONL) or findings (SARIF, JSONL)
+  ui         Interactive terminal UI for live proxy history
+  status     Summarize the current project and service activity
+  encode     Encoding/decoding utilities (url, base64, hex, unicode, html, hash, ...)
+  jwt        JSON Web Token decode, forge and crack
+  payloads   Built-in payload lists (sqli, xss, path-traversal, ssti, crlf, cmdi)
+  config     View and edit config.json settings
+  update     Update sectool to the latest release (checksum verified)
 
 Global Options:
   --config <path>    Config file path (default: ~/.sectool/config.json)
   --mcp-url <url>    MCP server URL (default: http://127.0.0.1:<port from config>/mcp)
+  --profile <name>   Config profile to apply (default: $SECTOOL_PROFILE)
+  --no-pager         Do not pipe long output through $PAGER (default: less -FRX)
+  -v, --verbose      Print MCP connection and call timing to stderr
+  -vv                Also print raw tool arguments and responses to stderr
+  -q, --quiet        Print results only (no hints or response payload previews)
+
+Exit Codes:
+  0  success
+  1  usage error or other failure
+  2  not found (flow, replay, session, rule, ...)
+  3  MCP server or backend unavailable
+  4  target out of scope
+  5  validation failed
 
 Use "sectool <command> --help" for specific command usage.
 `)
@@ -114,6 +219,9 @@ Use "sectool <command> --help" for specific command usage.
 type globalFlags struct {
 	ConfigPath string
 	MCPURL     string
+	Profile    string
+	NoPager    bool
+	Verbosity  cliutil.Verbosity
 }
 
 // parseGlobalFlags extracts global flags from args, returning remaining args.
@@ -143,6 +251,34 @@ func parseGlobalFlags(args []string) (globalFlags, []string) {
 			continue
 		}
 
+		// --profile <name> or --profile=<name>
+		if arg == "--profile" && i+1 < len(args) {
+			flags.Profile = args[i+1]
+			i++
+			continue
+		} else if strings.HasPrefix(arg, "--profile=") {
+			flags.Profile = strings.TrimPrefix(arg, "--profile=")
+			continue
+		}
+
+		if arg == "--no-pager" {
+			flags.NoPager = true
+			continue
+		}
+
+		// -v/-vv/--verbose raise verbosity, -q/--quiet lowers it; a lone -v still prints the version
+		switch {
+		case (arg == "-v" && len(args) > 1) || arg == "--verbose":
+			flags.Verbosity = min(max(flags.Verbosity, 0)+1, cliutil.VerbosityDebug)
+			continue
+		case arg == "-vv":
+			flags.Verbosity = cliutil.VerbosityDebug
+			continue
+		case arg == "-q" || arg == "--quiet":
+			flags.Verbosity = cliutil.VerbosityQuiet
+			continue
+		}
+
 		remaining = append(remaining, arg)
 	}
 
@@ -163,6 +299,8 @@ func getMCPURL(flags globalFlags) (string, error) {
 	cfg, err := config.LoadOrDefaultConfig(configPath)
 	if err != nil {
 		return "", fmt.Errorf("load config: %w", err)
+	} else if err := cfg.ApplyProfile(config.ResolveProfile(flags.Profile)); err != nil {
+		return "", err
 	}
 
 	if cfg.MCPPort != 0 && cfg.MCPPort != config.DefaultMCPPort {
@@ -14,6 +14,8 @@ import (
 	"github.com/mark3labs/mcp-go/client/transport"
 	"github.com/mark3labs/mcp-go/mcp"
 
+	"github.com/go-harden/llm-security-toolbox/sectool/cli"
+	"github.com/go-harden/llm-security-toolbox/sectool/cliutil"
 	"github.com/go-harden/llm-security-toolbox/sectool/config"
 	"github.com/go-harden/llm-security-toolbox/sectool/protocol"
 )
@@ -43,13 +45,20 @@ func New(ctx context.Context, mcpURL string) (*Client, error) {
 	if mcpURL == "" {
 		mcpURL = DefaultMCPURL
 	}
+	cliutil.Logf(cliutil.VerbosityVerbose, "connecting to MCP server at %s", mcpURL)
 
 	httpClient := &http.Client{
 		Timeout: ClientTimeout,
 	}
 
+	// each invocation is a new session, the name keeps "since=last" markers across them
+	headers := map[string]string{protocol.ClientHeader: protocol.ClientNameCLI}
+	if confirmKey != "" {
+		headers[protocol.ConfirmOutOfScopeHeader] = confirmKey
+	}
 	mcpClient, err := client.NewStreamableHttpClient(mcpURL,
 		transport.WithHTTPBasicClient(httpClient),
+		transport.WithHTTPHeaders(headers),
 	)
 	if err != nil {
 		return nil, formatConnectionError(mcpURL, err)
@@ -110,16 +119,27 @@ func (c *Client) Close() error {
 
 // CallTool calls an MCP tool and returns the raw result.
 func (c *Client) CallTool(ctx context.Context, name string, args map[string]interface{}) (*mcp.CallToolResult, error) {
+	if cliutil.Debug() {
+		argsJSON, _ := json.Marshal(args)
+		cliutil.Logf(cliutil.VerbosityDebug, "%s args: %s", name, argsJSON)
+	}
+
+	start := time.Now()
 	result, err := c.mcpClient.CallTool(ctx, mcp.CallToolRequest{
 		Params: mcp.CallToolParams{
 			Name:      name,
 			Arguments: args,
 		},
 	})
+	cliutil.Logf(cliutil.VerbosityVerbose, "%s completed in %s", name, time.Since(start).Round(time.Millisecond))
 	if err != nil {
 		return nil, translateTimeoutError(err)
-	} else if result.IsError {
-		return nil, errors.New(extractTextContent(result.Content))
+	}
+	if cliutil.Debug() {
+		cliutil.Logf(cliutil.VerbosityDebug, "%s result: %s", name, extractTextContent(result.Content))
+	}
+	if result.IsError {
+		return nil, cli.Classify(extractTextContent(result.Content))
 	}
 	return result, nil
 }
@@ -171,17 +191,17 @@ func isContextStopError(err error) string {
 // formatConnectionError formats connection errors with actionable messages.
 func formatConnectionError(mcpURL string, err error) error {
 	if msg := isContextStopError(err); msg != "" {
-		return fmt.Errorf("connection to MCP server at %s %s", mcpURL, msg)
+		return cli.Unavailable(fmt.Errorf("connection to MCP server at %s %s", mcpURL, msg))
 	}
 
 	errStr := err.Error()
 
 	if strings.Contains(errStr, "connection refused") ||
 		strings.Contains(errStr, "no such host") || strings.Contains(errStr, "dial tcp") {
-		return fmt.Errorf("cannot connect to MCP server at %s\nStart the server with: sectool mcp", mcpURL)
+		return cli.Unavailable(fmt.Errorf("cannot connect to MCP server at %s\nStart the server with: sectool mcp", mcpURL))
 	}
 
-	return fmt.Errorf("MCP connection failed: %w", err)
+	return cli.Unavailable(fmt.Errorf("MCP connection failed: %w", err))
 }
 
 // translateTimeoutError translates MCP errors to user-friendly messages.
@@ -0,0 +1,33 @@
+package mcpclient
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/go-harden/llm-security-toolbox/sectool/cliutil"
+	"github.com/go-harden/llm-security-toolbox/sectool/config"
+)
+
+// ConfigPath is the service's config file, beside which the service keeps the
+// confirm key (set from the --config global flag).
+var ConfigPath = config.DefaultPath()
+
+// confirmKey is sent on every client created after ConfirmOutOfScope succeeds.
+var confirmKey string
+
+// ConfirmOutOfScope asks the user at the terminal to allow this command's requests
+// outside the target_allowlist config and project scope, then makes clients
+// created afterwards send the service's confirm key. MCP tools have no equivalent.
+func ConfirmOutOfScope() error {
+	keyPath := config.ConfirmKeyPath(ConfigPath)
+	data, err := os.ReadFile(keyPath)
+	if err != nil {
+		return fmt.Errorf("read confirm key %s (written when 'sectool mcp' starts with this config): %w", keyPath, err)
+	}
+	if err := cliutil.Confirm("Requests from this command may go to targets outside the target_allowlist config and the project scope.", "yes"); err != nil {
+		return fmt.Errorf("--confirm-out-of-scope: %w", err)
+	}
+	confirmKey = strings.TrimSpace(string(data))
+	return nil
+}
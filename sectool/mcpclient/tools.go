@@ -2,6 +2,7 @@ package mcpclient
 
 import (
 	"context"
+	"encoding/base64"
 
 	"github.com/go-harden/llm-security-toolbox/sectool/protocol"
 )
@@ -45,6 +46,9 @@ func (c *Client) ProxyPoll(ctx context.Context, opts ProxyPollOpts) (*protocol.P
 	if opts.Offset > 0 {
 		args["offset"] = opts.Offset
 	}
+	if opts.Cursor != "" {
+		args["cursor"] = opts.Cursor
+	}
 
 	var resp protocol.ProxyPollResponse
 	if err := c.CallToolJSON(ctx, "proxy_poll", args, &resp); err != nil {
@@ -63,168 +67,1266 @@ func (c *Client) ProxyGet(ctx context.Context, flowID string) (*protocol.ProxyGe
 	return &resp, nil
 }
 
+// ProxyImport calls proxy_import to load a HAR capture into proxy history.
+func (c *Client) ProxyImport(ctx context.Context, opts ProxyImportOpts) (*protocol.ProxyImportResponse, error) {
+	args := make(map[string]interface{})
+	if opts.Path != "" {
+		args["path"] = opts.Path
+	}
+	if opts.Content != "" {
+		args["content"] = opts.Content
+	}
+
+	var resp protocol.ProxyImportResponse
+	if err := c.CallToolJSON(ctx, "proxy_import", args, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// ProxyFindings calls proxy_findings to scan proxy history for secrets and PII.
+func (c *Client) ProxyFindings(ctx context.Context, opts ProxyFindingsOpts) (*protocol.ProxyFindingsResponse, error) {
+	args := make(map[string]interface{})
+	if len(opts.Detectors) > 0 {
+		args["detectors"] = opts.Detectors
+	}
+	if opts.Severity != "" {
+		args["severity"] = opts.Severity
+	}
+	if opts.Host != "" {
+		args["host"] = opts.Host
+	}
+	if opts.NoRecord {
+		args["record"] = false
+	}
+	if opts.Reveal {
+		args["reveal"] = true
+	}
+	if opts.Limit > 0 {
+		args["limit"] = opts.Limit
+	}
+
+	var resp protocol.ProxyFindingsResponse
+	if err := c.CallToolJSON(ctx, "proxy_findings", args, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// AnalyzeHeaders calls analyze_headers to summarize response header issues per host.
+func (c *Client) AnalyzeHeaders(ctx context.Context, opts AnalyzeHeadersOpts) (*protocol.AnalyzeHeadersResponse, error) {
+	args := make(map[string]interface{})
+	if opts.Host != "" {
+		args["host"] = opts.Host
+	}
+	if opts.Severity != "" {
+		args["severity"] = opts.Severity
+	}
+
+	var resp protocol.AnalyzeHeadersResponse
+	if err := c.CallToolJSON(ctx, "analyze_headers", args, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// CSRFAnalyze calls csrf_analyze to find anti-CSRF tokens and unprotected requests per host.
+func (c *Client) CSRFAnalyze(ctx context.Context, opts CSRFAnalyzeOpts) (*protocol.CSRFAnalyzeResponse, error) {
+	args := make(map[string]interface{})
+	if opts.Host != "" {
+		args["host"] = opts.Host
+	}
+
+	var resp protocol.CSRFAnalyzeResponse
+	if err := c.CallToolJSON(ctx, "csrf_analyze", args, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// FlowTag calls flow_tag to add or remove tags on a flow or replay.
+func (c *Client) FlowTag(ctx context.Context, opts FlowTagOpts) (*protocol.FlowTagResponse, error) {
+	args := make(map[string]interface{})
+	if opts.FlowID != "" {
+		args["flow_id"] = opts.FlowID
+	}
+	if opts.ReplayID != "" {
+		args["replay_id"] = opts.ReplayID
+	}
+	if len(opts.Tags) > 0 {
+		args["tags"] = opts.Tags
+	}
+	if len(opts.Remove) > 0 {
+		args["remove"] = opts.Remove
+	}
+
+	var resp protocol.FlowTagResponse
+	if err := c.CallToolJSON(ctx, "flow_tag", args, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// FlowList calls flow_list and returns tagged flows and replays.
+func (c *Client) FlowList(ctx context.Context, tag string) (*protocol.FlowListResponse, error) {
+	args := make(map[string]interface{})
+	if tag != "" {
+		args["tag"] = tag
+	}
+
+	var resp protocol.FlowListResponse
+	if err := c.CallToolJSON(ctx, "flow_list", args, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
 // ProxyRuleList calls proxy_rule_list and returns rules.
 func (c *Client) ProxyRuleList(ctx context.Context, typeFilter string, limit int) (*protocol.RuleListResponse, error) {
 	args := make(map[string]interface{})
 	if typeFilter != "" {
 		args["type_filter"] = typeFilter
 	}
-	if limit > 0 {
-		args["limit"] = limit
+	if limit > 0 {
+		args["limit"] = limit
+	}
+
+	var resp protocol.RuleListResponse
+	if err := c.CallToolJSON(ctx, "proxy_rule_list", args, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// ProxyRuleAdd calls proxy_rule_add and returns the created rule with its preview.
+func (c *Client) ProxyRuleAdd(ctx context.Context, opts RuleAddOpts) (*protocol.RuleAddResponse, error) {
+	args := map[string]interface{}{
+		"type": opts.Type,
+	}
+	if opts.Match != "" {
+		args["match"] = opts.Match
+	}
+	if opts.Replace != "" {
+		args["replace"] = opts.Replace
+	}
+	if opts.Label != "" {
+		args["label"] = opts.Label
+	}
+	if opts.IsRegex {
+		args["is_regex"] = opts.IsRegex
+	}
+
+	var resp protocol.RuleAddResponse
+	if err := c.CallToolJSON(ctx, "proxy_rule_add", args, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// ProxyRuleUpdate calls proxy_rule_update and returns the updated rule.
+func (c *Client) ProxyRuleUpdate(ctx context.Context, ruleID string, opts RuleUpdateOpts) (*protocol.RuleEntry, error) {
+	args := map[string]interface{}{
+		"rule_id": ruleID,
+		"type":    opts.Type,
+	}
+	if opts.Match != "" {
+		args["match"] = opts.Match
+	}
+	if opts.Replace != "" {
+		args["replace"] = opts.Replace
+	}
+	if opts.Label != "" {
+		args["label"] = opts.Label
+	}
+	if opts.IsRegex != nil {
+		args["is_regex"] = *opts.IsRegex
+	}
+
+	var resp protocol.RuleEntry
+	if err := c.CallToolJSON(ctx, "proxy_rule_update", args, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// ProxyRuleToggle calls proxy_rule_toggle and returns the updated rule.
+func (c *Client) ProxyRuleToggle(ctx context.Context, ruleID string, enabled bool) (*protocol.RuleEntry, error) {
+	args := map[string]interface{}{"rule_id": ruleID, "enabled": enabled}
+	var resp protocol.RuleEntry
+	if err := c.CallToolJSON(ctx, "proxy_rule_toggle", args, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// ProxyRuleMove calls proxy_rule_move and returns the moved rule.
+func (c *Client) ProxyRuleMove(ctx context.Context, ruleID string, position int) (*protocol.RuleEntry, error) {
+	args := map[string]interface{}{"rule_id": ruleID, "position": position}
+	var resp protocol.RuleEntry
+	if err := c.CallToolJSON(ctx, "proxy_rule_move", args, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// ProxyRuleTest calls proxy_rule_test and returns the dry-run result.
+func (c *Client) ProxyRuleTest(ctx context.Context, opts RuleTestOpts) (*protocol.RuleTestResponse, error) {
+	args := make(map[string]interface{})
+	if opts.RuleID != "" {
+		args["rule_id"] = opts.RuleID
+	}
+	if opts.Type != "" {
+		args["type"] = opts.Type
+	}
+	if opts.Match != "" {
+		args["match"] = opts.Match
+	}
+	if opts.Replace != "" {
+		args["replace"] = opts.Replace
+	}
+	if opts.IsRegex {
+		args["is_regex"] = opts.IsRegex
+	}
+	if opts.FlowID != "" {
+		args["flow_id"] = opts.FlowID
+	}
+	if opts.Sample != "" {
+		args["sample"] = opts.Sample
+	}
+
+	var resp protocol.RuleTestResponse
+	if err := c.CallToolJSON(ctx, "proxy_rule_test", args, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// ProxyRuleDelete calls proxy_rule_delete.
+func (c *Client) ProxyRuleDelete(ctx context.Context, ruleID string) error {
+	_, err := c.CallTool(ctx, "proxy_rule_delete", map[string]interface{}{"rule_id": ruleID})
+	return err
+}
+
+// ReplaySend calls replay_send and returns the result.
+func (c *Client) ReplaySend(ctx context.Context, opts ReplaySendOpts) (*protocol.ReplaySendResponse, error) {
+	args := make(map[string]interface{})
+	if opts.FlowID != "" {
+		args["flow_id"] = opts.FlowID
+	}
+	if opts.ReplayID != "" {
+		args["replay_id"] = opts.ReplayID
+	}
+	if opts.Method != "" {
+		args["method"] = opts.Method
+	}
+	if opts.Body != "" {
+		args["body"] = opts.Body
+	}
+	if opts.Target != "" {
+		args["target"] = opts.Target
+	}
+	if len(opts.AddHeaders) > 0 {
+		args["add_headers"] = opts.AddHeaders
+	}
+	if len(opts.RemoveHeaders) > 0 {
+		args["remove_headers"] = opts.RemoveHeaders
+	}
+	if opts.Path != "" {
+		args["path"] = opts.Path
+	}
+	if opts.Query != "" {
+		args["query"] = opts.Query
+	}
+	if len(opts.SetQuery) > 0 {
+		args["set_query"] = opts.SetQuery
+	}
+	if len(opts.RemoveQuery) > 0 {
+		args["remove_query"] = opts.RemoveQuery
+	}
+	if len(opts.SetJSON) > 0 {
+		args["set_json"] = opts.SetJSON
+	}
+	if len(opts.RemoveJSON) > 0 {
+		args["remove_json"] = opts.RemoveJSON
+	}
+	if len(opts.SetForm) > 0 {
+		args["set_form"] = opts.SetForm
+	}
+	if len(opts.RemoveForm) > 0 {
+		args["remove_form"] = opts.RemoveForm
+	}
+	if len(opts.SetXML) > 0 {
+		args["set_xml"] = opts.SetXML
+	}
+	if len(opts.RemoveXML) > 0 {
+		args["remove_xml"] = opts.RemoveXML
+	}
+	if opts.XMLDoctype != "" {
+		args["xml_doctype"] = opts.XMLDoctype
+	}
+	if opts.FollowRedirects {
+		args["follow_redirects"] = opts.FollowRedirects
+	}
+	if opts.KeepEncoding {
+		args["keep_encoding"] = opts.KeepEncoding
+	}
+	if opts.Timeout != "" {
+		args["timeout"] = opts.Timeout
+	}
+	if opts.Force {
+		args["force"] = opts.Force
+	}
+	if opts.Label != "" {
+		args["label"] = opts.Label
+	}
+	if opts.AuthProfile != "" {
+		args["auth_profile"] = opts.AuthProfile
+	}
+	if opts.Session != "" {
+		args["session"] = opts.Session
+	}
+	if opts.ReplayAs != "" {
+		args["replay_as"] = opts.ReplayAs
+	}
+	if opts.RefreshCSRF {
+		args["refresh_csrf"] = true
+	}
+
+	var resp protocol.ReplaySendResponse
+	if err := c.CallToolJSON(ctx, "replay_send", args, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// ReplayGet calls replay_get and returns full response data.
+func (c *Client) ReplayGet(ctx context.Context, replayID string) (*protocol.ReplayGetResponse, error) {
+	args := map[string]interface{}{"replay_id": replayID, "full_body": true}
+	var resp protocol.ReplayGetResponse
+	if err := c.CallToolJSON(ctx, "replay_get", args, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// ReplayRender calls replay_get with the response body decoded and formatted per render mode.
+func (c *Client) ReplayRender(ctx context.Context, replayID, render string) (*protocol.ReplayGetResponse, error) {
+	args := map[string]interface{}{"replay_id": replayID, "render": render}
+	var resp protocol.ReplayGetResponse
+	if err := c.CallToolJSON(ctx, "replay_get", args, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// ReplayHistory calls replay_history and returns previous replays, newest first.
+func (c *Client) ReplayHistory(ctx context.Context, limit int) (*protocol.ReplayHistoryResponse, error) {
+	args := make(map[string]interface{})
+	if limit > 0 {
+		args["limit"] = limit
+	}
+	var resp protocol.ReplayHistoryResponse
+	if err := c.CallToolJSON(ctx, "replay_history", args, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// ReplayDiff calls replay_diff and returns the comparison of two replay responses.
+func (c *Client) ReplayDiff(ctx context.Context, opts ReplayDiffOpts) (*protocol.ReplayDiffResponse, error) {
+	args := map[string]interface{}{
+		"replay_a":     opts.ReplayA,
+		"replay_b":     opts.ReplayB,
+		"noise_filter": !opts.NoNoiseFilter,
+	}
+	if len(opts.IgnoreHeaders) > 0 {
+		args["ignore_headers"] = opts.IgnoreHeaders
+	}
+	if len(opts.IgnorePatterns) > 0 {
+		args["ignore_patterns"] = opts.IgnorePatterns
+	}
+
+	var resp protocol.ReplayDiffResponse
+	if err := c.CallToolJSON(ctx, "replay_diff", args, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// ResponseExtract calls response_extract and returns the matches per replay.
+func (c *Client) ResponseExtract(ctx context.Context, opts ResponseExtractOpts) (*protocol.ResponseExtractResponse, error) {
+	args := map[string]interface{}{"replay_ids": opts.ReplayIDs}
+	if opts.Regex != "" {
+		args["regex"] = opts.Regex
+	}
+	if opts.In != "" {
+		args["in"] = opts.In
+	}
+	if opts.JSONPath != "" {
+		args["json_path"] = opts.JSONPath
+	}
+	if opts.CSS != "" {
+		args["css"] = opts.CSS
+	}
+	if opts.Attr != "" {
+		args["attr"] = opts.Attr
+	}
+	if opts.Limit > 0 {
+		args["limit"] = opts.Limit
+	}
+
+	var resp protocol.ResponseExtractResponse
+	if err := c.CallToolJSON(ctx, "response_extract", args, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// ParamDiscover calls param_discover to find hidden parameters of a request.
+func (c *Client) ParamDiscover(ctx context.Context, opts ParamDiscoverOpts) (*protocol.ParamDiscoverResponse, error) {
+	args := map[string]interface{}{}
+	for key, value := range map[string]string{
+		"flow_id":   opts.FlowID,
+		"replay_id": opts.ReplayID,
+		"location":  opts.Location,
+		"timeout":   opts.Timeout,
+	} {
+		if value != "" {
+			args[key] = value
+		}
+	}
+	if len(opts.Names) > 0 {
+		args["names"] = opts.Names
+	}
+	if opts.ChunkSize > 0 {
+		args["chunk_size"] = opts.ChunkSize
+	}
+	if opts.MaxRequests > 0 {
+		args["max_requests"] = opts.MaxRequests
+	}
+
+	if opts.Rate != nil {
+		args["rate"] = *opts.Rate
+	}
+
+	var resp protocol.ParamDiscoverResponse
+	if err := c.CallToolJSON(ctx, "param_discover", args, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// SSRFTest calls ssrf_test to send SSRF and open redirect payloads in a parameter or header.
+func (c *Client) SSRFTest(ctx context.Context, opts SSRFTestOpts) (*protocol.SSRFTestResponse, error) {
+	args := map[string]interface{}{}
+	for key, value := range map[string]string{
+		"flow_id":   opts.FlowID,
+		"replay_id": opts.ReplayID,
+		"param":     opts.Param,
+		"header":    opts.Header,
+		"location":  opts.Location,
+		"oast_id":   opts.OastID,
+		"wait":      opts.Wait,
+		"timeout":   opts.Timeout,
+	} {
+		if value != "" {
+			args[key] = value
+		}
+	}
+	if len(opts.Categories) > 0 {
+		args["categories"] = opts.Categories
+	}
+	if len(opts.Payloads) > 0 {
+		args["payloads"] = opts.Payloads
+	}
+
+	if opts.Rate != nil {
+		args["rate"] = *opts.Rate
+	}
+
+	var resp protocol.SSRFTestResponse
+	if err := c.CallToolJSON(ctx, "ssrf_test", args, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// EnvList calls env_list and returns the environments.
+func (c *Client) EnvList(ctx context.Context) (*protocol.EnvListResponse, error) {
+	var resp protocol.EnvListResponse
+	if err := c.CallToolJSON(ctx, "env_list", map[string]interface{}{}, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// EnvSet calls env_set to update, activate or delete an environment.
+func (c *Client) EnvSet(ctx context.Context, opts EnvSetOpts) (*protocol.EnvListResponse, error) {
+	args := map[string]interface{}{"name": opts.Name}
+	if len(opts.Vars) > 0 {
+		vars := make(map[string]interface{}, len(opts.Vars))
+		for k, v := range opts.Vars {
+			vars[k] = v
+		}
+		args["vars"] = vars
+	}
+	if len(opts.Unset) > 0 {
+		args["unset"] = opts.Unset
+	}
+	if opts.Activate {
+		args["activate"] = true
+	}
+	if opts.Delete {
+		args["delete"] = true
+	}
+
+	var resp protocol.EnvListResponse
+	if err := c.CallToolJSON(ctx, "env_set", args, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// SessionList calls session_list.
+func (c *Client) SessionList(ctx context.Context) (*protocol.SessionListResponse, error) {
+	var resp protocol.SessionListResponse
+	if err := c.CallToolJSON(ctx, "session_list", map[string]interface{}{}, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// SessionSet calls session_set to define (and log in) or delete a login session.
+func (c *Client) SessionSet(ctx context.Context, opts SessionSetOpts) (*protocol.SessionListResponse, error) {
+	args := map[string]interface{}{"name": opts.Name}
+	for key, value := range map[string]string{
+		"flow_id":        opts.FlowID,
+		"replay_id":      opts.ReplayID,
+		"target":         opts.Target,
+		"extract_json":   opts.ExtractJSON,
+		"extract_header": opts.ExtractHeader,
+		"extract_cookie": opts.ExtractCookie,
+		"extract_regex":  opts.ExtractRegex,
+		"inject":         opts.Inject,
+		"refresh_on":     opts.RefreshOn,
+	} {
+		if value != "" {
+			args[key] = value
+		}
+	}
+	if opts.Delete {
+		args["delete"] = true
+	}
+
+	var resp protocol.SessionListResponse
+	if err := c.CallToolJSON(ctx, "session_set", args, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// CSRFSet calls csrf_set to define (and validate) or delete a host's CSRF token rule.
+func (c *Client) CSRFSet(ctx context.Context, opts CSRFSetOpts) (*protocol.CSRFListResponse, error) {
+	args := make(map[string]interface{})
+	for key, value := range map[string]string{
+		"host":           opts.Host,
+		"flow_id":        opts.FlowID,
+		"replay_id":      opts.ReplayID,
+		"target":         opts.Target,
+		"extract_field":  opts.ExtractField,
+		"extract_json":   opts.ExtractJSON,
+		"extract_header": opts.ExtractHeader,
+		"extract_cookie": opts.ExtractCookie,
+		"extract_regex":  opts.ExtractRegex,
+		"param":          opts.Param,
+		"header":         opts.Header,
+	} {
+		if value != "" {
+			args[key] = value
+		}
+	}
+	if opts.Delete {
+		args["delete"] = true
+	}
+
+	var resp protocol.CSRFListResponse
+	if err := c.CallToolJSON(ctx, "csrf_set", args, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// CookieJarList calls cookie_jar_list.
+func (c *Client) CookieJarList(ctx context.Context) (*protocol.CookieJarListResponse, error) {
+	var resp protocol.CookieJarListResponse
+	if err := c.CallToolJSON(ctx, "cookie_jar_list", map[string]interface{}{}, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// CookieJarSet calls cookie_jar_set to create, change or delete a host's cookie jar.
+func (c *Client) CookieJarSet(ctx context.Context, opts CookieJarSetOpts) (*protocol.CookieJarListResponse, error) {
+	args := map[string]interface{}{"host": opts.Host}
+	if opts.Enabled != nil {
+		args["enabled"] = *opts.Enabled
+	}
+	if len(opts.Cookies) > 0 {
+		args["cookies"] = opts.Cookies
+	}
+	if len(opts.Remove) > 0 {
+		args["remove"] = opts.Remove
+	}
+	if opts.Clear {
+		args["clear"] = true
+	}
+	if opts.Delete {
+		args["delete"] = true
+	}
+
+	var resp protocol.CookieJarListResponse
+	if err := c.CallToolJSON(ctx, "cookie_jar_set", args, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// SequenceList calls sequence_list.
+func (c *Client) SequenceList(ctx context.Context) (*protocol.SequenceListResponse, error) {
+	var resp protocol.SequenceListResponse
+	if err := c.CallToolJSON(ctx, "sequence_list", map[string]interface{}{}, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// SequenceSet calls sequence_set to define or delete a sequence.
+func (c *Client) SequenceSet(ctx context.Context, opts SequenceSetOpts) (*protocol.SequenceListResponse, error) {
+	args := map[string]interface{}{"name": opts.Name}
+	if len(opts.Steps) > 0 {
+		args["steps"] = opts.Steps
+	}
+	if opts.Delete {
+		args["delete"] = true
+	}
+
+	var resp protocol.SequenceListResponse
+	if err := c.CallToolJSON(ctx, "sequence_set", args, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// SequenceRun calls sequence_run to run a sequence with input variables.
+func (c *Client) SequenceRun(ctx context.Context, name string, vars map[string]string) (*protocol.SequenceRunResponse, error) {
+	args := map[string]interface{}{"name": name}
+	if len(vars) > 0 {
+		args["vars"] = vars
+	}
+
+	var resp protocol.SequenceRunResponse
+	if err := c.CallToolJSON(ctx, "sequence_run", args, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// IdentityList calls identity_list.
+func (c *Client) IdentityList(ctx context.Context) (*protocol.IdentityListResponse, error) {
+	var resp protocol.IdentityListResponse
+	if err := c.CallToolJSON(ctx, "identity_list", map[string]interface{}{}, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// IdentitySet calls identity_set to replace or delete an identity.
+func (c *Client) IdentitySet(ctx context.Context, opts IdentitySetOpts) (*protocol.IdentityListResponse, error) {
+	args := map[string]interface{}{"name": opts.Name}
+	if len(opts.Headers) > 0 {
+		args["headers"] = opts.Headers
+	}
+	if len(opts.Cookies) > 0 {
+		args["cookies"] = opts.Cookies
+	}
+	if len(opts.RemoveHeaders) > 0 {
+		args["remove_headers"] = opts.RemoveHeaders
+	}
+	if opts.Delete {
+		args["delete"] = true
+	}
+
+	var resp protocol.IdentityListResponse
+	if err := c.CallToolJSON(ctx, "identity_set", args, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// AuthzMatrix calls authz_matrix to replay a request as every identity.
+func (c *Client) AuthzMatrix(ctx context.Context, opts AuthzMatrixOpts) (*protocol.AuthzMatrixResponse, error) {
+	args := map[string]interface{}{}
+	for key, value := range map[string]string{
+		"flow_id":   opts.FlowID,
+		"replay_id": opts.ReplayID,
+		"baseline":  opts.Baseline,
+		"timeout":   opts.Timeout,
+	} {
+		if value != "" {
+			args[key] = value
+		}
+	}
+	if len(opts.Identities) > 0 {
+		args["identities"] = opts.Identities
+	}
+
+	var resp protocol.AuthzMatrixResponse
+	if err := c.CallToolJSON(ctx, "authz_matrix", args, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// ScanStart calls scan_start to start a Burp scan.
+func (c *Client) ScanStart(ctx context.Context, opts ScanStartOpts) (*protocol.ScanStartResponse, error) {
+	args := map[string]interface{}{}
+	if opts.FlowID != "" {
+		args["flow_id"] = opts.FlowID
+	}
+	if len(opts.URLs) > 0 {
+		args["urls"] = opts.URLs
+	}
+	if len(opts.Configurations) > 0 {
+		args["configurations"] = opts.Configurations
+	}
+
+	var resp protocol.ScanStartResponse
+	if err := c.CallToolJSON(ctx, "scan_start", args, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// ScanStatus calls scan_status.
+func (c *Client) ScanStatus(ctx context.Context, scanID string) (*protocol.ScanStatusResponse, error) {
+	var resp protocol.ScanStatusResponse
+	if err := c.CallToolJSON(ctx, "scan_status", map[string]interface{}{"scan_id": scanID}, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// ScanIssues calls scan_issues.
+func (c *Client) ScanIssues(ctx context.Context, opts ScanIssuesOpts) (*protocol.ScanIssuesResponse, error) {
+	args := map[string]interface{}{}
+	for key, value := range map[string]string{
+		"scan_id":  opts.ScanID,
+		"severity": opts.Severity,
+		"url":      opts.URL,
+	} {
+		if value != "" {
+			args[key] = value
+		}
+	}
+	if opts.Details {
+		args["details"] = true
+	}
+	if opts.Limit > 0 {
+		args["limit"] = opts.Limit
+	}
+	if opts.Offset > 0 {
+		args["offset"] = opts.Offset
+	}
+
+	var resp protocol.ScanIssuesResponse
+	if err := c.CallToolJSON(ctx, "scan_issues", args, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// FindingList calls finding_list.
+func (c *Client) FindingList(ctx context.Context, opts FindingListOpts) (*protocol.FindingListResponse, error) {
+	args := map[string]interface{}{"limit": opts.Limit}
+	if opts.Severity != "" {
+		args["severity"] = opts.Severity
+	}
+	if opts.Status != "" {
+		args["status"] = opts.Status
+	}
+
+	var resp protocol.FindingListResponse
+	if err := c.CallToolJSON(ctx, "finding_list", args, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// NoteList calls note_list and returns notes, newest first.
+func (c *Client) NoteList(ctx context.Context, opts NoteListOpts) (*protocol.NoteListResponse, error) {
+	args := map[string]interface{}{"limit": opts.Limit}
+	if opts.Tag != "" {
+		args["tag"] = opts.Tag
+	}
+	if opts.Ref != "" {
+		args["ref"] = opts.Ref
+	}
+
+	var resp protocol.NoteListResponse
+	if err := c.CallToolJSON(ctx, "note_list", args, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// FindingGet calls finding_get and returns the full finding.
+func (c *Client) FindingGet(ctx context.Context, findingID string) (*protocol.Finding, error) {
+	var resp protocol.Finding
+	if err := c.CallToolJSON(ctx, "finding_get", map[string]interface{}{"finding_id": findingID}, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// Export calls export to write results to a file under .sectool/exports/.
+func (c *Client) Export(ctx context.Context, opts ExportOpts) (*protocol.ExportResponse, error) {
+	args := map[string]interface{}{"source": opts.Source, "format": opts.Format}
+	for key, value := range map[string]string{
+		"host":           opts.Host,
+		"path":           opts.Path,
+		"method":         opts.Method,
+		"status":         opts.Status,
+		"contains":       opts.Contains,
+		"contains_body":  opts.ContainsBody,
+		"since":          opts.Since,
+		"exclude_host":   opts.ExcludeHost,
+		"exclude_path":   opts.ExcludePath,
+		"severity":       opts.Severity,
+		"finding_status": opts.FindingStatus,
+	} {
+		if value != "" {
+			args[key] = value
+		}
+	}
+	if opts.Limit > 0 {
+		args["limit"] = opts.Limit
+	}
+
+	var resp protocol.ExportResponse
+	if err := c.CallToolJSON(ctx, "export", args, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// SpecImport calls spec_import and returns the imported operations.
+func (c *Client) SpecImport(ctx context.Context, opts SpecImportOpts) (*protocol.SpecImportResponse, error) {
+	args := make(map[string]interface{})
+	if opts.Source != "" {
+		args["source"] = opts.Source
+	}
+	if opts.Content != "" {
+		args["content"] = opts.Content
+	}
+	if opts.BaseURL != "" {
+		args["base_url"] = opts.BaseURL
+	}
+
+	var resp protocol.SpecImportResponse
+	if err := c.CallToolJSON(ctx, "spec_import", args, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// GraphQLIntrospect calls graphql_introspect and returns the schema summary or one type.
+func (c *Client) GraphQLIntrospect(ctx context.Context, opts GraphQLIntrospectOpts) (*protocol.GraphQLIntrospectResponse, error) {
+	args := make(map[string]interface{})
+	if opts.URL != "" {
+		args["url"] = opts.URL
+	}
+	if opts.FlowID != "" {
+		args["flow_id"] = opts.FlowID
+	}
+	if opts.SDL != "" {
+		args["sdl"] = opts.SDL
+	}
+	if len(opts.Headers) > 0 {
+		args["headers"] = opts.Headers
+	}
+	if opts.AuthProfile != "" {
+		args["auth_profile"] = opts.AuthProfile
+	}
+	if opts.Type != "" {
+		args["type"] = opts.Type
+	}
+	if opts.Refresh {
+		args["refresh"] = true
+	}
+	if opts.Depth > 0 {
+		args["depth"] = opts.Depth
+	}
+
+	var resp protocol.GraphQLIntrospectResponse
+	if err := c.CallToolJSON(ctx, "graphql_introspect", args, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// GRPCSchema calls grpc_schema and returns the loaded methods.
+func (c *Client) GRPCSchema(ctx context.Context, opts GRPCSchemaOpts) (*protocol.GRPCSchemaResponse, error) {
+	args := make(map[string]interface{})
+	if len(opts.Paths) > 0 {
+		args["paths"] = opts.Paths
+	}
+	if opts.Root != "" {
+		args["root"] = opts.Root
+	}
+	if opts.Proto != "" {
+		args["proto"] = opts.Proto
+	}
+	if opts.Name != "" {
+		args["name"] = opts.Name
+	}
+
+	var resp protocol.GRPCSchemaResponse
+	if err := c.CallToolJSON(ctx, "grpc_schema", args, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// GRPCList calls grpc_list and returns gRPC calls from proxy history.
+func (c *Client) GRPCList(ctx context.Context, opts GRPCListOpts) (*protocol.GRPCListResponse, error) {
+	args := make(map[string]interface{})
+	if opts.Host != "" {
+		args["host"] = opts.Host
+	}
+	if opts.Method != "" {
+		args["method"] = opts.Method
+	}
+	if opts.Limit > 0 {
+		args["limit"] = opts.Limit
+	}
+
+	var resp protocol.GRPCListResponse
+	if err := c.CallToolJSON(ctx, "grpc_list", args, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// GRPCGet calls grpc_get for a flow or replay and returns the decoded messages.
+func (c *Client) GRPCGet(ctx context.Context, flowID, replayID string) (*protocol.GRPCGetResponse, error) {
+	args := make(map[string]interface{})
+	if flowID != "" {
+		args["flow_id"] = flowID
+	}
+	if replayID != "" {
+		args["replay_id"] = replayID
+	}
+
+	var resp protocol.GRPCGetResponse
+	if err := c.CallToolJSON(ctx, "grpc_get", args, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// GRPCSend calls grpc_send and returns the decoded response.
+func (c *Client) GRPCSend(ctx context.Context, opts GRPCSendOpts) (*protocol.GRPCSendResponse, error) {
+	args := make(map[string]interface{})
+	if opts.FlowID != "" {
+		args["flow_id"] = opts.FlowID
+	}
+	if opts.ReplayID != "" {
+		args["replay_id"] = opts.ReplayID
+	}
+	if opts.Message != nil {
+		args["message"] = opts.Message
+	}
+	if len(opts.SetFields) > 0 {
+		args["set_fields"] = opts.SetFields
+	}
+	if len(opts.RemoveFields) > 0 {
+		args["remove_fields"] = opts.RemoveFields
+	}
+	if len(opts.Metadata) > 0 {
+		args["metadata"] = opts.Metadata
+	}
+	if opts.Target != "" {
+		args["target"] = opts.Target
+	}
+	if opts.Timeout != "" {
+		args["timeout"] = opts.Timeout
+	}
+	if opts.Label != "" {
+		args["label"] = opts.Label
+	}
+
+	var resp protocol.GRPCSendResponse
+	if err := c.CallToolJSON(ctx, "grpc_send", args, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// WSList calls ws_list and returns captured WebSocket messages.
+func (c *Client) WSList(ctx context.Context, opts WSListOpts) (*protocol.WSListResponse, error) {
+	args := make(map[string]interface{})
+	if opts.Host != "" {
+		args["host"] = opts.Host
+	}
+	if opts.Direction != "" {
+		args["direction"] = opts.Direction
+	}
+	if opts.Contains != "" {
+		args["contains"] = opts.Contains
+	}
+	if opts.Since != "" {
+		args["since"] = opts.Since
+	}
+	if opts.Limit > 0 {
+		args["limit"] = opts.Limit
+	}
+	if opts.Cursor != "" {
+		args["cursor"] = opts.Cursor
+	}
+
+	var resp protocol.WSListResponse
+	if err := c.CallToolJSON(ctx, "ws_list", args, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// WSSend calls ws_send and returns the handshake result and replies.
+func (c *Client) WSSend(ctx context.Context, opts WSSendOpts) (*protocol.WSSendResponse, error) {
+	args := make(map[string]interface{})
+	if opts.URL != "" {
+		args["url"] = opts.URL
+	}
+	if opts.FlowID != "" {
+		args["flow_id"] = opts.FlowID
+	}
+	if opts.Target != "" {
+		args["target"] = opts.Target
+	}
+	if len(opts.Messages) > 0 {
+		args["messages"] = opts.Messages
+	}
+	if opts.MessageID != "" {
+		args["message_id"] = opts.MessageID
+	}
+	if len(opts.AddHeaders) > 0 {
+		args["add_headers"] = opts.AddHeaders
+	}
+	if len(opts.RemoveHeaders) > 0 {
+		args["remove_headers"] = opts.RemoveHeaders
+	}
+	if opts.Wait != "" {
+		args["wait"] = opts.Wait
+	}
+	if opts.MaxReplies > 0 {
+		args["max_replies"] = opts.MaxReplies
+	}
+	if opts.Timeout != "" {
+		args["timeout"] = opts.Timeout
 	}
 
-	var resp protocol.RuleListResponse
-	if err := c.CallToolJSON(ctx, "proxy_rule_list", args, &resp); err != nil {
+	var resp protocol.WSSendResponse
+	if err := c.CallToolJSON(ctx, "ws_send", args, &resp); err != nil {
 		return nil, err
 	}
 	return &resp, nil
 }
 
-// ProxyRuleAdd calls proxy_rule_add and returns the created rule.
-func (c *Client) ProxyRuleAdd(ctx context.Context, opts RuleAddOpts) (*protocol.RuleEntry, error) {
+// RequestSend calls request_send and returns the result.
+func (c *Client) RequestSend(ctx context.Context, opts RequestSendOpts) (*protocol.ReplaySendResponse, error) {
 	args := map[string]interface{}{
-		"type": opts.Type,
-	}
-	if opts.Match != "" {
-		args["match"] = opts.Match
-	}
-	if opts.Replace != "" {
-		args["replace"] = opts.Replace
+		"url": opts.URL,
 	}
-	if opts.Label != "" {
-		args["label"] = opts.Label
+	if opts.Method != "" {
+		args["method"] = opts.Method
 	}
-	if opts.IsRegex {
-		args["is_regex"] = opts.IsRegex
+	if len(opts.Headers) > 0 {
+		args["headers"] = opts.Headers
 	}
-
-	var resp protocol.RuleEntry
-	if err := c.CallToolJSON(ctx, "proxy_rule_add", args, &resp); err != nil {
-		return nil, err
+	if opts.Body != "" {
+		args["body"] = opts.Body
 	}
-	return &resp, nil
-}
-
-// ProxyRuleUpdate calls proxy_rule_update and returns the updated rule.
-func (c *Client) ProxyRuleUpdate(ctx context.Context, ruleID string, opts RuleUpdateOpts) (*protocol.RuleEntry, error) {
-	args := map[string]interface{}{
-		"rule_id": ruleID,
-		"type":    opts.Type,
+	if opts.FollowRedirects {
+		args["follow_redirects"] = opts.FollowRedirects
 	}
-	if opts.Match != "" {
-		args["match"] = opts.Match
+	if opts.KeepEncoding {
+		args["keep_encoding"] = opts.KeepEncoding
 	}
-	if opts.Replace != "" {
-		args["replace"] = opts.Replace
+	if opts.Timeout != "" {
+		args["timeout"] = opts.Timeout
 	}
 	if opts.Label != "" {
 		args["label"] = opts.Label
 	}
-	if opts.IsRegex != nil {
-		args["is_regex"] = *opts.IsRegex
+	if opts.AuthProfile != "" {
+		args["auth_profile"] = opts.AuthProfile
 	}
 
-	var resp protocol.RuleEntry
-	if err := c.CallToolJSON(ctx, "proxy_rule_update", args, &resp); err != nil {
+	var resp protocol.ReplaySendResponse
+	if err := c.CallToolJSON(ctx, "request_send", args, &resp); err != nil {
 		return nil, err
 	}
 	return &resp, nil
 }
 
-// ProxyRuleDelete calls proxy_rule_delete.
-func (c *Client) ProxyRuleDelete(ctx context.Context, ruleID string) error {
-	_, err := c.CallTool(ctx, "proxy_rule_delete", map[string]interface{}{"rule_id": ruleID})
-	return err
-}
-
-// ReplaySend calls replay_send and returns the result.
-func (c *Client) ReplaySend(ctx context.Context, opts ReplaySendOpts) (*protocol.ReplaySendResponse, error) {
-	args := map[string]interface{}{
-		"flow_id": opts.FlowID,
+// RequestCraft calls request_craft and returns the result.
+func (c *Client) RequestCraft(ctx context.Context, opts RequestCraftOpts) (*protocol.ReplaySendResponse, error) {
+	args := make(map[string]interface{})
+	if opts.Raw != "" {
+		args["raw"] = opts.Raw
+	}
+	if opts.Target != "" {
+		args["target"] = opts.Target
+	}
+	if opts.URL != "" {
+		args["url"] = opts.URL
 	}
 	if opts.Method != "" {
 		args["method"] = opts.Method
 	}
+	if len(opts.Headers) > 0 {
+		args["headers"] = opts.Headers
+	}
 	if opts.Body != "" {
 		args["body"] = opts.Body
 	}
-	if opts.Target != "" {
-		args["target"] = opts.Target
+	if opts.FollowRedirects {
+		args["follow_redirects"] = opts.FollowRedirects
 	}
-	if len(opts.AddHeaders) > 0 {
-		args["add_headers"] = opts.AddHeaders
+	if opts.KeepEncoding {
+		args["keep_encoding"] = opts.KeepEncoding
 	}
-	if len(opts.RemoveHeaders) > 0 {
-		args["remove_headers"] = opts.RemoveHeaders
+	if opts.Timeout != "" {
+		args["timeout"] = opts.Timeout
 	}
-	if opts.Path != "" {
-		args["path"] = opts.Path
+	if opts.Force {
+		args["force"] = opts.Force
 	}
-	if opts.Query != "" {
-		args["query"] = opts.Query
+	if opts.Label != "" {
+		args["label"] = opts.Label
 	}
-	if len(opts.SetQuery) > 0 {
-		args["set_query"] = opts.SetQuery
+	if opts.AuthProfile != "" {
+		args["auth_profile"] = opts.AuthProfile
 	}
-	if len(opts.RemoveQuery) > 0 {
-		args["remove_query"] = opts.RemoveQuery
+
+	var resp protocol.ReplaySendResponse
+	if err := c.CallToolJSON(ctx, "request_craft", args, &resp); err != nil {
+		return nil, err
 	}
-	if len(opts.SetJSON) > 0 {
-		args["set_json"] = opts.SetJSON
+	return &resp, nil
+}
+
+// RawSend calls raw_send and returns the bytes read back.
+func (c *Client) RawSend(ctx context.Context, opts RawSendOpts) (*protocol.RawSendResponse, error) {
+	args := map[string]interface{}{
+		"host": opts.Host,
+		"port": opts.Port,
+		"data": base64.StdEncoding.EncodeToString(opts.Data),
 	}
-	if len(opts.RemoveJSON) > 0 {
-		args["remove_json"] = opts.RemoveJSON
+	if opts.TLS {
+		args["tls"] = true
 	}
-	if opts.FollowRedirects {
-		args["follow_redirects"] = opts.FollowRedirects
+	if opts.SNI != "" {
+		args["sni"] = opts.SNI
+	}
+	if len(opts.ALPN) > 0 {
+		args["alpn"] = opts.ALPN
+	}
+	if opts.HalfClose {
+		args["half_close"] = true
+	}
+	if opts.Wait != "" {
+		args["wait"] = opts.Wait
 	}
 	if opts.Timeout != "" {
 		args["timeout"] = opts.Timeout
 	}
-	if opts.Force {
-		args["force"] = opts.Force
+	if opts.MaxBytes > 0 {
+		args["max_bytes"] = opts.MaxBytes
 	}
 
-	var resp protocol.ReplaySendResponse
-	if err := c.CallToolJSON(ctx, "replay_send", args, &resp); err != nil {
+	var resp protocol.RawSendResponse
+	if err := c.CallToolJSON(ctx, "raw_send", args, &resp); err != nil {
 		return nil, err
 	}
 	return &resp, nil
 }
 
-// ReplayGet calls replay_get and returns full response data.
-func (c *Client) ReplayGet(ctx context.Context, replayID string) (*protocol.ReplayGetResponse, error) {
-	args := map[string]interface{}{"replay_id": replayID, "full_body": true}
-	var resp protocol.ReplayGetResponse
-	if err := c.CallToolJSON(ctx, "replay_get", args, &resp); err != nil {
+// TLSProbe calls tls_probe and returns the server's TLS configuration.
+func (c *Client) TLSProbe(ctx context.Context, opts TLSProbeOpts) (*protocol.TLSProbeResponse, error) {
+	args := map[string]interface{}{"host": opts.Host}
+	if opts.Port > 0 {
+		args["port"] = opts.Port
+	}
+	if opts.SNI != "" {
+		args["sni"] = opts.SNI
+	}
+	if opts.NoCiphers {
+		args["ciphers"] = false
+	}
+	if opts.Timeout != "" {
+		args["timeout"] = opts.Timeout
+	}
+
+	var resp protocol.TLSProbeResponse
+	if err := c.CallToolJSON(ctx, "tls_probe", args, &resp); err != nil {
 		return nil, err
 	}
 	return &resp, nil
 }
 
-// RequestSend calls request_send and returns the result.
-func (c *Client) RequestSend(ctx context.Context, opts RequestSendOpts) (*protocol.ReplaySendResponse, error) {
-	args := map[string]interface{}{
-		"url": opts.URL,
+// HTTP2Send calls http2_send and returns the stream result.
+func (c *Client) HTTP2Send(ctx context.Context, opts HTTP2SendOpts) (*protocol.HTTP2SendResponse, error) {
+	args := make(map[string]interface{})
+	if opts.FlowID != "" {
+		args["flow_id"] = opts.FlowID
 	}
-	if opts.Method != "" {
-		args["method"] = opts.Method
+	if opts.ReplayID != "" {
+		args["replay_id"] = opts.ReplayID
+	}
+	if opts.Raw != "" {
+		args["raw"] = opts.Raw
+	}
+	if opts.Target != "" {
+		args["target"] = opts.Target
+	}
+	if len(opts.Pseudo) > 0 {
+		args["pseudo"] = opts.Pseudo
+	}
+	if len(opts.AddHeaders) > 0 {
+		args["add_headers"] = opts.AddHeaders
+	}
+	if len(opts.RemoveHeaders) > 0 {
+		args["remove_headers"] = opts.RemoveHeaders
 	}
 	if len(opts.Headers) > 0 {
 		args["headers"] = opts.Headers
 	}
-	if opts.Body != "" {
-		args["body"] = opts.Body
+	if opts.Body != nil {
+		args["body"] = *opts.Body
 	}
-	if opts.FollowRedirects {
-		args["follow_redirects"] = opts.FollowRedirects
+	if len(opts.Settings) > 0 {
+		args["settings"] = opts.Settings
 	}
 	if opts.Timeout != "" {
 		args["timeout"] = opts.Timeout
 	}
+	if opts.Label != "" {
+		args["label"] = opts.Label
+	}
 
-	var resp protocol.ReplaySendResponse
-	if err := c.CallToolJSON(ctx, "request_send", args, &resp); err != nil {
+	var resp protocol.HTTP2SendResponse
+	if err := c.CallToolJSON(ctx, "http2_send", args, &resp); err != nil {
 		return nil, err
 	}
 	return &resp, nil
@@ -258,12 +1360,18 @@ func (c *Client) OastPoll(ctx context.Context, oastID string, opts OastPollOpts)
 	if opts.EventType != "" {
 		args["type"] = opts.EventType
 	}
+	if opts.Subdomain != "" {
+		args["subdomain_contains"] = opts.Subdomain
+	}
 	if opts.Wait != "" {
 		args["wait"] = opts.Wait
 	}
 	if opts.Limit > 0 {
 		args["limit"] = opts.Limit
 	}
+	if opts.Cursor != "" {
+		args["cursor"] = opts.Cursor
+	}
 
 	var resp protocol.OastPollResponse
 	if err := c.CallToolJSON(ctx, "oast_poll", args, &resp); err != nil {
@@ -300,6 +1408,27 @@ func (c *Client) OastList(ctx context.Context, limit int) (*protocol.OastListRes
 	return &resp, nil
 }
 
+// OastExpect calls oast_expect and returns the registered expectation.
+func (c *Client) OastExpect(ctx context.Context, oastID string, opts OastExpectOpts) (*protocol.OastExpectation, error) {
+	args := map[string]interface{}{
+		"oast_id":   oastID,
+		"subdomain": opts.Subdomain,
+		"title":     opts.Title,
+	}
+	if opts.Severity != "" {
+		args["severity"] = opts.Severity
+	}
+	if opts.ReplayID != "" {
+		args["replay_id"] = opts.ReplayID
+	}
+
+	var resp protocol.OastExpectation
+	if err := c.CallToolJSON(ctx, "oast_expect", args, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
 // OastDelete calls oast_delete.
 func (c *Client) OastDelete(ctx context.Context, oastID string) error {
 	_, err := c.CallTool(ctx, "oast_delete", map[string]interface{}{"oast_id": oastID})
@@ -422,6 +1551,9 @@ func (c *Client) CrawlPoll(ctx context.Context, sessionID string, opts CrawlPoll
 	if opts.Offset > 0 {
 		args["offset"] = opts.Offset
 	}
+	if opts.Cursor != "" {
+		args["cursor"] = opts.Cursor
+	}
 
 	var resp protocol.CrawlPollResponse
 	if err := c.CallToolJSON(ctx, "crawl_poll", args, &resp); err != nil {
@@ -444,6 +1576,176 @@ func (c *Client) CrawlSessions(ctx context.Context, limit int) (*protocol.CrawlS
 	return &resp, nil
 }
 
+// CrawlResults calls crawl_results and returns the discovered endpoints.
+func (c *Client) CrawlResults(ctx context.Context, opts CrawlResultsOpts) (*protocol.CrawlResultsResponse, error) {
+	args := make(map[string]interface{})
+	if opts.SessionID != "" {
+		args["session_id"] = opts.SessionID
+	}
+	if opts.Host != "" {
+		args["host"] = opts.Host
+	}
+	if opts.Path != "" {
+		args["path"] = opts.Path
+	}
+	if opts.Method != "" {
+		args["method"] = opts.Method
+	}
+	if opts.Limit > 0 {
+		args["limit"] = opts.Limit
+	}
+	if opts.Cursor != "" {
+		args["cursor"] = opts.Cursor
+	}
+
+	var resp protocol.CrawlResultsResponse
+	if err := c.CallToolJSON(ctx, "crawl_results", args, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// Dirbust calls dirbust to brute-force directories and files under a URL.
+func (c *Client) Dirbust(ctx context.Context, opts DirbustOpts) (*protocol.DirbustResponse, error) {
+	args := map[string]interface{}{"url": opts.URL}
+	for key, value := range map[string]string{
+		"extensions":    opts.Extensions,
+		"filter_status": opts.FilterStatus,
+		"timeout":       opts.Timeout,
+	} {
+		if value != "" {
+			args[key] = value
+		}
+	}
+	if len(opts.Words) > 0 {
+		args["words"] = opts.Words
+	}
+	if len(opts.Headers) > 0 {
+		args["headers"] = opts.Headers
+	}
+	if opts.MaxDepth > 0 {
+		args["max_depth"] = opts.MaxDepth
+	}
+	if opts.Threads > 0 {
+		args["threads"] = opts.Threads
+	}
+	if opts.MaxRequests > 0 {
+		args["max_requests"] = opts.MaxRequests
+	}
+
+	if opts.Rate != nil {
+		args["rate"] = *opts.Rate
+	}
+
+	var resp protocol.DirbustResponse
+	if err := c.CallToolJSON(ctx, "dirbust", args, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// VhostDiscover calls vhost_discover to find virtual hosts by Host header.
+func (c *Client) VhostDiscover(ctx context.Context, opts VhostDiscoverOpts) (*protocol.VhostDiscoverResponse, error) {
+	args := map[string]interface{}{"url": opts.URL}
+	for key, value := range map[string]string{
+		"domain":        opts.Domain,
+		"filter_status": opts.FilterStatus,
+		"timeout":       opts.Timeout,
+	} {
+		if value != "" {
+			args[key] = value
+		}
+	}
+	if len(opts.Words) > 0 {
+		args["words"] = opts.Words
+	}
+	if len(opts.Hosts) > 0 {
+		args["hosts"] = opts.Hosts
+	}
+	if len(opts.Headers) > 0 {
+		args["headers"] = opts.Headers
+	}
+	if opts.Threads > 0 {
+		args["threads"] = opts.Threads
+	}
+	if opts.MaxRequests > 0 {
+		args["max_requests"] = opts.MaxRequests
+	}
+
+	if opts.Rate != nil {
+		args["rate"] = *opts.Rate
+	}
+
+	var resp protocol.VhostDiscoverResponse
+	if err := c.CallToolJSON(ctx, "vhost_discover", args, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// SubdomainEnum calls subdomain_enum to find subdomains by DNS and CT logs.
+func (c *Client) SubdomainEnum(ctx context.Context, opts SubdomainEnumOpts) (*protocol.SubdomainEnumResponse, error) {
+	args := map[string]interface{}{"domain": opts.Domain}
+	if len(opts.Words) > 0 {
+		args["words"] = opts.Words
+	}
+	if opts.NoCT {
+		args["ct"] = false
+	}
+	if opts.Threads > 0 {
+		args["threads"] = opts.Threads
+	}
+
+	var resp protocol.SubdomainEnumResponse
+	if err := c.CallToolJSON(ctx, "subdomain_enum", args, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// PortScan calls port_scan to check TCP ports and grab banners on in-scope hosts.
+func (c *Client) PortScan(ctx context.Context, opts PortScanOpts) (*protocol.PortScanResponse, error) {
+	args := map[string]interface{}{"hosts": opts.Hosts}
+	if opts.Ports != "" {
+		args["ports"] = opts.Ports
+	}
+	if opts.Rate > 0 {
+		args["rate"] = opts.Rate
+	}
+	if opts.Threads > 0 {
+		args["threads"] = opts.Threads
+	}
+	if opts.Timeout != "" {
+		args["timeout"] = opts.Timeout
+	}
+	if opts.BannerWait != "" {
+		args["banner_wait"] = opts.BannerWait
+	}
+
+	var resp protocol.PortScanResponse
+	if err := c.CallToolJSON(ctx, "port_scan", args, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// DNSQuery calls dns_query to resolve records for a name or IP address.
+func (c *Client) DNSQuery(ctx context.Context, opts DNSQueryOpts) (*protocol.DNSQueryResponse, error) {
+	args := map[string]interface{}{"name": opts.Name}
+	if len(opts.Types) > 0 {
+		args["types"] = opts.Types
+	}
+	if opts.Server != "" {
+		args["server"] = opts.Server
+	}
+
+	var resp protocol.DNSQueryResponse
+	if err := c.CallToolJSON(ctx, "dns_query", args, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
 // CrawlStop calls crawl_stop to stop a session.
 func (c *Client) CrawlStop(ctx context.Context, sessionID string) error {
 	_, err := c.CallTool(ctx, "crawl_stop", map[string]interface{}{"session_id": sessionID})
@@ -459,3 +1761,12 @@ func (c *Client) CrawlGet(ctx context.Context, flowID string) (*protocol.CrawlGe
 	}
 	return &resp, nil
 }
+
+// Status calls status and returns service health.
+func (c *Client) Status(ctx context.Context) (*protocol.StatusResponse, error) {
+	var resp protocol.StatusResponse
+	if err := c.CallToolJSON(ctx, "status", map[string]interface{}{}, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
@@ -16,8 +16,9 @@ type ProxyPollOpts struct {
 	Since        string // list mode
 	ExcludeHost  string
 	ExcludePath  string
-	Limit        int // list mode
-	Offset       int // list mode
+	Limit        int    // list mode
+	Offset       int    // list mode
+	Cursor       string // list mode, next_cursor from a previous page
 }
 
 // RuleAddOpts are options for ProxyRuleAdd.
@@ -38,6 +39,18 @@ type RuleUpdateOpts struct {
 	IsRegex *bool // nil = preserve existing, non-nil = set to value
 }
 
+// RuleTestOpts are options for ProxyRuleTest. Set RuleID to test an existing rule,
+// or Type/Match/Replace/IsRegex for a proposed one.
+type RuleTestOpts struct {
+	RuleID  string
+	Type    string
+	Match   string
+	Replace string
+	IsRegex bool
+	FlowID  string
+	Sample  string
+}
+
 // =============================================================================
 // Replay Options
 // =============================================================================
@@ -45,6 +58,7 @@ type RuleUpdateOpts struct {
 // ReplaySendOpts are options for ReplaySend.
 type ReplaySendOpts struct {
 	FlowID          string
+	ReplayID        string // base request from a previous replay instead of FlowID
 	Method          string
 	Body            string
 	Target          string
@@ -56,9 +70,303 @@ type ReplaySendOpts struct {
 	RemoveQuery     []string
 	SetJSON         map[string]interface{}
 	RemoveJSON      []string
+	SetForm         map[string]string // form-urlencoded or multipart fields
+	RemoveForm      []string
+	SetXML          map[string]string // XPath -> element text or attribute value
+	RemoveXML       []string
+	XMLDoctype      string // DOCTYPE or entity declarations for XXE
 	FollowRedirects bool
+	KeepEncoding    bool
 	Timeout         string
 	Force           bool
+	Label           string
+	AuthProfile     string // config auth profile: NTLM/Negotiate/Digest handshake or SigV4 signing
+	Session         string // login session from session_set
+	ReplayAs        string // identity from identity_set
+	RefreshCSRF     bool   // fetch and inject a fresh token with the host's csrf_set rule
+}
+
+// ReplayDiffOpts are options for ReplayDiff.
+type ReplayDiffOpts struct {
+	ReplayA        string
+	ReplayB        string
+	NoNoiseFilter  bool
+	IgnoreHeaders  []string
+	IgnorePatterns []string
+}
+
+// ResponseExtractOpts are options for ResponseExtract. Exactly one of Regex, JSONPath or CSS is required.
+type ResponseExtractOpts struct {
+	ReplayIDs []string
+	Regex     string
+	In        string // regex input: body, headers or all
+	JSONPath  string
+	CSS       string
+	Attr      string
+	Limit     int
+}
+
+// ParamDiscoverOpts are options for ParamDiscover. Set one of FlowID or ReplayID.
+type ParamDiscoverOpts struct {
+	FlowID      string
+	ReplayID    string
+	Location    string   // query, form or json; empty to infer from the request
+	Names       []string // empty for the built-in params list
+	ChunkSize   int
+	MaxRequests int
+	Timeout     string
+	Rate        *float64 // requests per second to each host; nil for the configured rate
+}
+
+// SSRFTestOpts are options for SSRFTest. Set one of FlowID or ReplayID, and one
+// of Param or Header.
+type SSRFTestOpts struct {
+	FlowID     string
+	ReplayID   string
+	Param      string
+	Header     string
+	Location   string   // query or body; empty to infer from the request
+	Categories []string // empty for every built-in category
+	Payloads   []string // replace the built-in payloads
+	OastID     string   // empty to create a session
+	Wait       string
+	Timeout    string
+	Rate       *float64 // requests per second to each host; nil for the configured rate
+}
+
+// WSListOpts are options for WSList.
+type WSListOpts struct {
+	Host      string
+	Direction string
+	Contains  string
+	Since     string
+	Limit     int
+	Cursor    string
+}
+
+// WSSendOpts are options for WSSend.
+type WSSendOpts struct {
+	URL           string
+	FlowID        string
+	Target        string
+	Messages      []string
+	MessageID     string
+	AddHeaders    []string
+	RemoveHeaders []string
+	Wait          string
+	MaxReplies    int
+	Timeout       string
+}
+
+// EnvSetOpts are options for EnvSet.
+type EnvSetOpts struct {
+	Name     string
+	Vars     map[string]string
+	Unset    []string
+	Activate bool
+	Delete   bool
+}
+
+// SessionSetOpts are options for SessionSet. Set one of FlowID or ReplayID and one
+// Extract* field, or only Name with Delete.
+type SessionSetOpts struct {
+	Name          string
+	FlowID        string
+	ReplayID      string
+	Target        string
+	ExtractJSON   string
+	ExtractHeader string
+	ExtractCookie string
+	ExtractRegex  string
+	Inject        string
+	RefreshOn     string
+	Delete        bool
+}
+
+// CSRFSetOpts are options for CSRFSet. Set one of FlowID or ReplayID, one Extract*
+// field and Param and/or Header, or only Host with Delete.
+type CSRFSetOpts struct {
+	Host          string
+	FlowID        string
+	ReplayID      string
+	Target        string
+	ExtractField  string
+	ExtractJSON   string
+	ExtractHeader string
+	ExtractCookie string
+	ExtractRegex  string
+	Param         string
+	Header        string
+	Delete        bool
+}
+
+// CookieJarSetOpts are options for CookieJarSet. Enabled nil leaves the jar's
+// state unchanged (new jars are enabled).
+type CookieJarSetOpts struct {
+	Host    string
+	Enabled *bool
+	Cookies map[string]string
+	Remove  []string
+	Clear   bool
+	Delete  bool
+}
+
+// SequenceSetOpts are options for SequenceSet. Each step is a sequence_set step
+// object: {"name", "tool", "args", "extract", "expect"}.
+type SequenceSetOpts struct {
+	Name   string
+	Steps  []map[string]interface{}
+	Delete bool
+}
+
+// IdentitySetOpts are options for IdentitySet.
+type IdentitySetOpts struct {
+	Name          string
+	Headers       []string
+	Cookies       map[string]string
+	RemoveHeaders []string
+	Delete        bool
+}
+
+// AuthzMatrixOpts are options for AuthzMatrix. Set one of FlowID or ReplayID.
+type AuthzMatrixOpts struct {
+	FlowID     string
+	ReplayID   string
+	Identities []string // empty for all
+	Baseline   string   // identity with the expected response; empty for the original request
+	Timeout    string
+}
+
+// ScanStartOpts are options for ScanStart. Set one of FlowID or URLs.
+type ScanStartOpts struct {
+	FlowID         string
+	URLs           []string
+	Configurations []string
+}
+
+// ScanIssuesOpts are options for ScanIssues.
+type ScanIssuesOpts struct {
+	ScanID   string // empty for all project issues
+	Severity string // minimum severity
+	URL      string
+	Details  bool
+	Limit    int
+	Offset   int
+}
+
+// FindingListOpts are options for FindingList.
+type FindingListOpts struct {
+	Severity string // minimum severity
+	Status   string
+	Limit    int
+}
+
+// NoteListOpts are options for NoteList.
+type NoteListOpts struct {
+	Tag   string
+	Ref   string // flow, replay, OAST or finding ID
+	Limit int    // 0 for all
+}
+
+// ExportOpts are options for Export. The proxy filters apply to source "proxy",
+// Severity and FindingStatus to source "findings".
+type ExportOpts struct {
+	Source        string // proxy, replay or findings
+	Format        string // har, jsonl or sarif
+	Host          string
+	Path          string
+	Method        string
+	Status        string
+	Contains      string
+	ContainsBody  string
+	Since         string
+	ExcludeHost   string
+	ExcludePath   string
+	Severity      string
+	FindingStatus string
+	Limit         int
+}
+
+// SpecImportOpts are options for SpecImport. Set exactly one of Source or Content.
+type SpecImportOpts struct {
+	Source  string // file path or http(s) URL, resolved by the service
+	Content string
+	BaseURL string
+}
+
+// GraphQLIntrospectOpts are options for GraphQLIntrospect. Set exactly one of URL or FlowID.
+type GraphQLIntrospectOpts struct {
+	URL         string
+	FlowID      string
+	SDL         string // schema text to parse instead of sending introspection
+	Headers     map[string]string
+	AuthProfile string
+	Type        string
+	Refresh     bool
+	Depth       int
+}
+
+// GRPCSchemaOpts are options for GRPCSchema. Without any field, the loaded methods are listed.
+type GRPCSchemaOpts struct {
+	Paths []string // .proto files or descriptor sets, resolved by the service
+	Root  string   // import root of .proto paths
+	Proto string   // .proto source text
+	Name  string   // import path of Proto
+}
+
+// GRPCListOpts are options for GRPCList.
+type GRPCListOpts struct {
+	Host   string
+	Method string
+	Limit  int
+}
+
+// GRPCSendOpts are options for GRPCSend. Set exactly one of FlowID or ReplayID.
+type GRPCSendOpts struct {
+	FlowID       string
+	ReplayID     string
+	Message      map[string]interface{}
+	SetFields    map[string]interface{}
+	RemoveFields []string
+	Metadata     map[string]string
+	Target       string
+	Timeout      string
+	Label        string
+}
+
+// ProxyFindingsOpts are options for ProxyFindings.
+type ProxyFindingsOpts struct {
+	Detectors []string
+	Severity  string
+	Host      string
+	NoRecord  bool
+	Reveal    bool
+	Limit     int
+}
+
+// AnalyzeHeadersOpts are options for AnalyzeHeaders.
+type AnalyzeHeadersOpts struct {
+	Host     string
+	Severity string
+}
+
+// CSRFAnalyzeOpts are options for CSRFAnalyze.
+type CSRFAnalyzeOpts struct {
+	Host string
+}
+
+// FlowTagOpts are options for FlowTag. Set exactly one of FlowID or ReplayID.
+type FlowTagOpts struct {
+	FlowID   string
+	ReplayID string
+	Tags     []string
+	Remove   []string
+}
+
+// ProxyImportOpts are options for ProxyImport; set exactly one field.
+type ProxyImportOpts struct {
+	Path    string // HAR file, resolved by the service
+	Content string
 }
 
 // RequestSendOpts are options for RequestSend.
@@ -68,7 +376,65 @@ type RequestSendOpts struct {
 	Headers         map[string]string
 	Body            string
 	FollowRedirects bool
+	KeepEncoding    bool
+	Timeout         string
+	Label           string
+	AuthProfile     string
+}
+
+// RequestCraftOpts are options for RequestCraft. Set exactly one of Raw or URL.
+type RequestCraftOpts struct {
+	Raw             string
+	Target          string
+	URL             string
+	Method          string
+	Headers         map[string]string
+	Body            string
+	FollowRedirects bool
+	KeepEncoding    bool
 	Timeout         string
+	Force           bool
+	Label           string
+	AuthProfile     string
+}
+
+// RawSendOpts are options for RawSend.
+type RawSendOpts struct {
+	Host      string
+	Port      int
+	TLS       bool
+	SNI       string
+	ALPN      []string
+	Data      []byte // sent base64-encoded
+	HalfClose bool
+	Wait      string
+	Timeout   string
+	MaxBytes  int
+}
+
+// TLSProbeOpts are options for TLSProbe.
+type TLSProbeOpts struct {
+	Host      string
+	Port      int
+	SNI       string
+	NoCiphers bool // skip cipher suite enumeration
+	Timeout   string
+}
+
+// HTTP2SendOpts are options for HTTP2Send. Exactly one of FlowID, ReplayID or Raw is required.
+type HTTP2SendOpts struct {
+	FlowID        string
+	ReplayID      string
+	Raw           string
+	Target        string
+	Pseudo        map[string]string // "" removes a pseudo-header
+	AddHeaders    []string
+	RemoveHeaders []string
+	Headers       []string // complete header block, replaces the derived one
+	Body          *string  // nil = keep the base body
+	Settings      map[string]uint32
+	Timeout       string
+	Label         string
 }
 
 // =============================================================================
@@ -105,6 +471,70 @@ type CrawlPollOpts struct {
 	Since        string // flows mode
 	Limit        int
 	Offset       int
+	Cursor       string // flows mode, next_cursor from a previous page
+}
+
+// CrawlResultsOpts are options for CrawlResults.
+type CrawlResultsOpts struct {
+	SessionID string // empty for all sessions
+	Host      string
+	Path      string
+	Method    string
+	Limit     int
+	Cursor    string
+}
+
+// DirbustOpts are options for Dirbust.
+type DirbustOpts struct {
+	URL          string
+	Words        []string // empty for the built-in dirs list
+	Extensions   string   // comma-separated
+	FilterStatus string   // comma-separated, empty for 404
+	MaxDepth     int
+	Threads      int
+	MaxRequests  int
+	Headers      map[string]string
+	Timeout      string
+	Rate         *float64 // requests per second to each host; nil for the configured rate
+}
+
+// VhostDiscoverOpts are options for VhostDiscover.
+type VhostDiscoverOpts struct {
+	URL          string
+	Domain       string
+	Words        []string // empty for the built-in subdomains list
+	Hosts        []string
+	FilterStatus string // comma-separated
+	Threads      int
+	MaxRequests  int
+	Headers      map[string]string
+	Timeout      string
+	Rate         *float64 // requests per second to each host; nil for the configured rate
+}
+
+// SubdomainEnumOpts are options for SubdomainEnum.
+type SubdomainEnumOpts struct {
+	Domain  string
+	Words   []string // empty for the built-in subdomains list
+	NoCT    bool
+	Threads int
+}
+
+// PortScanOpts are options for PortScan.
+type PortScanOpts struct {
+	Hosts      []string
+	Ports      string // e.g. "22,80,8000-8100"; empty for common service ports
+	Rate       int
+	Threads    int
+	Timeout    string
+	BannerWait string
+}
+
+// DNSQueryOpts are options for DNSQuery.
+type DNSQueryOpts struct {
+	Name   string
+	Types  []string // empty for A, AAAA, CNAME, MX, TXT, NS (PTR for an IP)
+	Server string
 }
 
 // OastPollOpts are options for OastPoll.
@@ -112,6 +542,16 @@ type OastPollOpts struct {
 	OutputMode string // "summary" or "events"
 	Since      string
 	EventType  string
+	Subdomain  string // subdomain_contains filter
 	Wait       string
 	Limit      int
+	Cursor     string // events mode, next_cursor from a previous page
+}
+
+// OastExpectOpts are options for OastExpect.
+type OastExpectOpts struct {
+	Subdomain string
+	Title     string
+	Severity  string
+	ReplayID  string
 }
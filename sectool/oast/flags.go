@@ -9,9 +9,11 @@ import (
 	"github.com/spf13/pflag"
 
 	"github.com/go-harden/llm-security-toolbox/sectool/cli"
+	"github.com/go-harden/llm-security-toolbox/sectool/cliutil"
+	"github.com/go-harden/llm-security-toolbox/sectool/mcpclient"
 )
 
-var oastSubcommands = []string{"create", "summary", "poll", "get", "list", "delete", "help"}
+var oastSubcommands = []string{"create", "summary", "poll", "get", "list", "expect", "delete", "help"}
 
 func Parse(args []string, mcpURL string) error {
 	if len(args) < 1 {
@@ -30,6 +32,8 @@ func Parse(args []string, mcpURL string) error {
 		return parseGet(args[1:], mcpURL)
 	case "list":
 		return parseList(args[1:], mcpURL)
+	case "expect":
+		return parseExpect(args[1:], mcpURL)
 	case "delete":
 		return parseDelete(args[1:], mcpURL)
 	case "help", "--help", "-h":
@@ -82,13 +86,16 @@ oast poll <oast_id|label|domain> [options]
     --type <type>      filter by type (dns, http, smtp, ftp, ldap, smb, responder)
     --wait <dur>       max wait time for events (default: 2m, max: 2m)
     --limit <n>        maximum number of events to return
+    --format <fmt>     output format: markdown, plain, csv, tsv
+    --columns <list>   columns to show (event_id,time,type,source_ip,subdomain,correlated_with)
 
   Examples:
     sectool oast poll abc123 --since evt_xyz         # events after specific ID
     sectool oast poll abc123 --type dns              # only DNS events
     sectool oast poll abc123 --wait 30s              # wait up to 30s for events
+    sectool oast poll abc123 --format tsv --columns source_ip,subdomain
 
-  Output: Markdown table with event_id, time, type, source_ip, subdomain
+  Output: Table with event_id, time, type, source_ip, subdomain (Markdown by default)
 
 ---
 
@@ -110,8 +117,28 @@ oast list [options]
 
   Options:
     --limit <n>        maximum number of sessions to return
+    --format <fmt>     output format: markdown, plain, csv, tsv
+    --columns <list>   columns to show (oast_id,label,domain,created_at)
 
-  Output: Markdown table with oast_id, domain, created_at
+  Output: Table with oast_id, domain, created_at (Markdown by default), then
+  registered expectations and the findings they created
+
+---
+
+oast expect <oast_id|label|domain> <subdomain> --title <str> [options]
+
+  Create a draft finding when an interaction arrives on a matching subdomain.
+  The session is watched in the background; no polling needed.
+
+  Options:
+    --title <str>      finding title (required)
+    --severity <sev>   info, low, medium (default), high, critical
+    --replay-id <id>   replay that planted the payload, recorded as evidence
+
+  Example:
+    sectool oast expect abc123 ssrf-step3 --title "Blind SSRF in webhook" --severity high
+
+  Output: expectation_id; findings are written to .sectool/findings/
 
 ---
 
@@ -158,12 +185,13 @@ func parseSummary(args []string, mcpURL string) error {
 	fs := pflag.NewFlagSet("oast summary", pflag.ContinueOnError)
 	fs.SetInterspersed(true)
 	var timeout, wait time.Duration
-	var since, eventType string
+	var since, eventType, subdomain string
 	var limit int
 
 	fs.DurationVar(&timeout, "timeout", 30*time.Second, "client-side timeout")
 	fs.StringVar(&since, "since", "", "filter events since event_id or timestamp")
 	fs.StringVar(&eventType, "type", "", "filter by event type (dns, http, smtp, ftp, ldap, smb, responder)")
+	fs.StringVar(&subdomain, "subdomain", "", "only events whose subdomain contains this text")
 	fs.DurationVar(&wait, "wait", 120*time.Second, "max wait time for events (max 120s)")
 	fs.IntVar(&limit, "limit", 0, "maximum number of events to aggregate")
 
@@ -189,23 +217,27 @@ Options:
 		return errors.New("oast_id required (get from 'sectool oast create' or 'sectool oast list')")
 	}
 
-	return summary(mcpURL, timeout, fs.Args()[0], since, eventType, wait, limit)
+	return summary(mcpURL, timeout, fs.Args()[0], since, eventType, subdomain, wait, limit)
 }
 
 func parsePoll(args []string, mcpURL string) error {
 	fs := pflag.NewFlagSet("oast poll", pflag.ContinueOnError)
 	fs.SetInterspersed(true)
 	var timeout, wait time.Duration
-	var since, eventType string
+	var since, eventType, subdomain string
 	var limit int
+	var format, columns string
 
 	fs.DurationVar(&timeout, "timeout", 30*time.Second, "client-side timeout")
 	fs.StringVar(&since, "since", "", "filter events since event_id or timestamp")
 	fs.StringVar(&eventType, "type", "", "filter by event type (dns, http, smtp, ftp, ldap, smb, responder)")
+	fs.StringVar(&subdomain, "subdomain", "", "only events whose subdomain contains this text")
 	fs.DurationVar(&wait, "wait", 120*time.Second, "max wait time for events (max 120s)")
 	fs.IntVar(&limit, "limit", 0, "maximum number of events to return")
 	fs.IntVar(&limit, "count", 0, "alias for --limit")
 	_ = fs.MarkHidden("count")
+	fs.StringVar(&format, "format", "markdown", "output format: markdown, plain, csv, tsv")
+	fs.StringVar(&columns, "columns", "", "comma-separated columns to show (event_id,time,type,source_ip,subdomain,correlated_with)")
 
 	fs.Usage = func() {
 		_, _ = fmt.Fprint(os.Stderr, `Usage: sectool oast poll <oast_id> [options]
@@ -229,7 +261,16 @@ Options:
 		return errors.New("oast_id required (get from 'sectool oast create' or 'sectool oast list')")
 	}
 
-	return poll(mcpURL, timeout, fs.Args()[0], since, eventType, wait, limit)
+	outFormat, err := cliutil.ParseFormat(format)
+	if err != nil {
+		return err
+	}
+	cols, err := cliutil.SelectColumns(columns, eventColumns)
+	if err != nil {
+		return err
+	}
+
+	return poll(mcpURL, timeout, fs.Args()[0], since, eventType, subdomain, wait, limit, outFormat, cols)
 }
 
 func parseGet(args []string, mcpURL string) error {
@@ -270,11 +311,14 @@ func parseList(args []string, mcpURL string) error {
 	fs.SetInterspersed(true)
 	var timeout time.Duration
 	var limit int
+	var format, columns string
 
 	fs.DurationVar(&timeout, "timeout", 30*time.Second, "client-side timeout")
 	fs.IntVar(&limit, "limit", 0, "maximum number of sessions to return (most recent first)")
 	fs.IntVar(&limit, "count", 0, "alias for --limit")
 	_ = fs.MarkHidden("count")
+	fs.StringVar(&format, "format", "markdown", "output format: markdown, plain, csv, tsv")
+	fs.StringVar(&columns, "columns", "", "comma-separated columns to show (oast_id,label,domain,created_at)")
 
 	fs.Usage = func() {
 		_, _ = fmt.Fprint(os.Stderr, `Usage: sectool oast list [options]
@@ -290,7 +334,55 @@ Options:
 		return err
 	}
 
-	return list(mcpURL, timeout, limit)
+	outFormat, err := cliutil.ParseFormat(format)
+	if err != nil {
+		return err
+	}
+	var cols []string
+	if columns != "" {
+		if cols, err = cliutil.SelectColumns(columns, sessionColumns); err != nil {
+			return err
+		}
+	}
+
+	return list(mcpURL, timeout, limit, outFormat, cols)
+}
+
+func parseExpect(args []string, mcpURL string) error {
+	fs := pflag.NewFlagSet("oast expect", pflag.ContinueOnError)
+	fs.SetInterspersed(true)
+	var timeout time.Duration
+	var opts mcpclient.OastExpectOpts
+
+	fs.DurationVar(&timeout, "timeout", 30*time.Second, "client-side timeout")
+	fs.StringVar(&opts.Title, "title", "", "finding title (required)")
+	fs.StringVar(&opts.Severity, "severity", "", "finding severity: info, low, medium (default), high, critical")
+	fs.StringVar(&opts.ReplayID, "replay-id", "", "replay that planted the payload")
+
+	fs.Usage = func() {
+		_, _ = fmt.Fprint(os.Stderr, `Usage: sectool oast expect <oast_id> <subdomain> --title <str> [options]
+
+Register an expectation: the first interaction whose subdomain matches (a tag
+like "ssrf-step3" or a glob like "ssrf-*") creates a draft finding with the
+event and replay as evidence.
+
+Options:
+`)
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	} else if len(fs.Args()) < 2 {
+		fs.Usage()
+		return errors.New("oast_id and subdomain required")
+	} else if opts.Title == "" {
+		fs.Usage()
+		return errors.New("--title is required")
+	}
+	opts.Subdomain = fs.Args()[1]
+
+	return expect(mcpURL, timeout, fs.Args()[0], opts)
 }
 
 func parseDelete(args []string, mcpURL string) error {
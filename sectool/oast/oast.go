@@ -3,6 +3,7 @@ package oast
 import (
 	"context"
 	"fmt"
+	"os"
 	"slices"
 	"strings"
 	"time"
@@ -12,6 +13,11 @@ import (
 	"github.com/go-harden/llm-security-toolbox/sectool/protocol"
 )
 
+var (
+	eventColumns   = []string{"event_id", "time", "type", "source_ip", "subdomain", "correlated_with"}
+	sessionColumns = []string{"oast_id", "label", "domain", "created_at"}
+)
+
 func create(mcpURL string, timeout time.Duration, label string) error {
 	ctx, cancel := context.WithTimeout(context.Background(), timeout)
 	defer cancel()
@@ -35,18 +41,17 @@ func create(mcpURL string, timeout time.Duration, label string) error {
 		fmt.Printf("Label: `%s`\n", resp.Label)
 	}
 	fmt.Println()
-	fmt.Println("Use any subdomain for tagging (e.g., `sqli-test." + resp.Domain + "`)")
-	fmt.Println()
+	cliutil.Hintf("Use any subdomain for tagging (e.g., `sqli-test.%s`)\n\n", resp.Domain)
 	pollRef := resp.OastID
 	if resp.Label != "" {
 		pollRef = resp.Label
 	}
-	fmt.Printf("To poll for events: `sectool oast poll %s`\n", pollRef)
+	cliutil.Hintf("To poll for events: `sectool oast poll %s`\n", pollRef)
 
 	return nil
 }
 
-func summary(mcpURL string, timeout time.Duration, oastID, since, eventType string, wait time.Duration, limit int) error {
+func summary(mcpURL string, timeout time.Duration, oastID, since, eventType, subdomain string, wait time.Duration, limit int) error {
 	totalTimeout := timeout + wait
 	ctx, cancel := context.WithTimeout(context.Background(), totalTimeout)
 	defer cancel()
@@ -61,6 +66,7 @@ func summary(mcpURL string, timeout time.Duration, oastID, since, eventType stri
 		OutputMode: "summary",
 		Since:      since,
 		EventType:  eventType,
+		Subdomain:  subdomain,
 		Wait:       wait.String(),
 		Limit:      limit,
 	})
@@ -76,12 +82,12 @@ func summary(mcpURL string, timeout time.Duration, oastID, since, eventType stri
 		return nil
 	}
 
-	fmt.Println("| subdomain | source_ip | type | count |")
-	fmt.Println("|-----------|-----------|------|-------|")
+	fmt.Println("| subdomain | source_ip | type | count | correlated_with |")
+	fmt.Println("|-----------|-----------|------|-------|-----------------|")
 	for _, agg := range resp.Aggregates {
-		fmt.Printf("| %s | %s | %s | %d |\n",
+		fmt.Printf("| %s | %s | %s | %d | %s |\n",
 			cliutil.EscapeMarkdown(agg.Subdomain), agg.SourceIP,
-			strings.ToUpper(agg.Type), agg.Count)
+			strings.ToUpper(agg.Type), agg.Count, strings.Join(agg.CorrelatedWith, " "))
 	}
 	fmt.Printf("\n*%d unique interaction patterns*\n", len(resp.Aggregates))
 
@@ -92,7 +98,7 @@ func summary(mcpURL string, timeout time.Duration, oastID, since, eventType stri
 	return nil
 }
 
-func poll(mcpURL string, timeout time.Duration, oastID, since, eventType string, wait time.Duration, limit int) error {
+func poll(mcpURL string, timeout time.Duration, oastID, since, eventType, subdomain string, wait time.Duration, limit int, format cliutil.Format, columns []string) error {
 	totalTimeout := timeout + wait
 	ctx, cancel := context.WithTimeout(context.Background(), totalTimeout)
 	defer cancel()
@@ -107,6 +113,7 @@ func poll(mcpURL string, timeout time.Duration, oastID, since, eventType string,
 		OutputMode: "events",
 		Since:      since,
 		EventType:  eventType,
+		Subdomain:  subdomain,
 		Wait:       wait.String(),
 		Limit:      limit,
 	})
@@ -114,7 +121,8 @@ func poll(mcpURL string, timeout time.Duration, oastID, since, eventType string,
 		return fmt.Errorf("oast poll failed: %w", err)
 	}
 
-	if len(resp.Events) == 0 {
+	t := cliutil.NewTable(os.Stdout, format, eventColumns, columns)
+	if len(resp.Events) == 0 && t.Markdown() {
 		fmt.Println("No events received.")
 		if resp.DroppedCount > 0 {
 			fmt.Printf("\n*Note: %d events were dropped due to buffer limit*\n", resp.DroppedCount)
@@ -122,12 +130,13 @@ func poll(mcpURL string, timeout time.Duration, oastID, since, eventType string,
 		return nil
 	}
 
-	fmt.Println("| event_id | time | type | source_ip | subdomain |")
-	fmt.Println("|----------|------|------|-----------|-----------|")
+	t.Header()
 	for _, event := range resp.Events {
-		fmt.Printf("| %s | %s | %s | %s | %s |\n",
-			event.EventID, event.Time, strings.ToUpper(event.Type),
-			event.SourceIP, cliutil.EscapeMarkdown(event.Subdomain))
+		t.Row(event.EventID, event.Time, strings.ToUpper(event.Type), event.SourceIP, event.Subdomain, strings.Join(event.CorrelatedWith, " "))
+	}
+	t.Flush()
+	if !t.Markdown() {
+		return nil
 	}
 	fmt.Printf("\n*%d event(s)*\n", len(resp.Events))
 
@@ -136,10 +145,10 @@ func poll(mcpURL string, timeout time.Duration, oastID, since, eventType string,
 	}
 
 	// Show hints for next actions
-	fmt.Printf("\nTo view event details: `sectool oast get %s <event_id>`\n", oastID)
+	cliutil.Hintf("\nTo view event details: `sectool oast get %s <event_id>`\n", oastID)
 	if len(resp.Events) > 0 {
 		lastEvent := resp.Events[len(resp.Events)-1]
-		fmt.Printf("To poll for new events: `sectool oast poll %s --since %s`\n", oastID, lastEvent.EventID)
+		cliutil.Hintf("To poll for new events: `sectool oast poll %s --since %s`\n", oastID, lastEvent.EventID)
 	}
 
 	return nil
@@ -165,6 +174,9 @@ func get(mcpURL string, timeout time.Duration, oastID, eventID string) error {
 	fmt.Printf("- Type: %s\n", strings.ToUpper(resp.Type))
 	fmt.Printf("- Source IP: %s\n", resp.SourceIP)
 	fmt.Printf("- Subdomain: `%s`\n", resp.Subdomain)
+	if len(resp.CorrelatedWith) > 0 {
+		fmt.Printf("- Sent by replays: %s\n", strings.Join(resp.CorrelatedWith, ", "))
+	}
 
 	if len(resp.Details) > 0 {
 		fmt.Println()
@@ -193,7 +205,7 @@ func get(mcpURL string, timeout time.Duration, oastID, eventID string) error {
 	return nil
 }
 
-func list(mcpURL string, timeout time.Duration, limit int) error {
+func list(mcpURL string, timeout time.Duration, limit int, format cliutil.Format, columns []string) error {
 	ctx, cancel := context.WithTimeout(context.Background(), timeout)
 	defer cancel()
 
@@ -208,33 +220,71 @@ func list(mcpURL string, timeout time.Duration, limit int) error {
 		return fmt.Errorf("oast list failed: %w", err)
 	}
 
-	if len(resp.Sessions) == 0 {
+	defer cliutil.StartPager()()
+
+	if len(resp.Sessions) == 0 && (format == cliutil.FormatMarkdown || format == "") {
 		fmt.Println("No active OAST sessions.")
-		fmt.Println("\nTo create one: `sectool oast create`")
+		cliutil.Hintf("\nTo create one: `sectool oast create`\n")
 		return nil
 	}
 
-	hasLabels := slices.ContainsFunc(resp.Sessions, func(s protocol.OastSession) bool {
-		return s.Label != ""
-	})
-
-	if hasLabels {
-		fmt.Println("| oast_id | label | domain | created_at |")
-		fmt.Println("|---------|-------|--------|------------|")
-		for _, sess := range resp.Sessions {
-			fmt.Printf("| %s | %s | %s | %s |\n",
-				sess.OastID, sess.Label, sess.Domain, sess.CreatedAt)
-		}
-	} else {
-		fmt.Println("| oast_id | domain | created_at |")
-		fmt.Println("|---------|--------|------------|")
-		for _, sess := range resp.Sessions {
-			fmt.Printf("| %s | %s | %s |\n",
-				sess.OastID, sess.Domain, sess.CreatedAt)
+	if columns == nil {
+		// Only show the label column when some session has one
+		hasLabels := slices.ContainsFunc(resp.Sessions, func(s protocol.OastSession) bool {
+			return s.Label != ""
+		})
+		columns = sessionColumns
+		if !hasLabels {
+			columns = []string{"oast_id", "domain", "created_at"}
 		}
 	}
+
+	t := cliutil.NewTable(os.Stdout, format, sessionColumns, columns)
+	t.Header()
+	for _, sess := range resp.Sessions {
+		t.Row(sess.OastID, sess.Label, sess.Domain, sess.CreatedAt)
+	}
+	t.Flush()
+	if !t.Markdown() {
+		return nil
+	}
 	fmt.Printf("\n*%d active session(s)*\n", len(resp.Sessions))
 
+	var header bool
+	for _, sess := range resp.Sessions {
+		for _, exp := range sess.Expectations {
+			if !header {
+				fmt.Println("\nExpectations:")
+				header = true
+			}
+			status := "pending"
+			if exp.FindingID != "" {
+				status = "confirmed, finding `" + exp.FindingID + "`"
+			}
+			fmt.Printf("- `%s` expects `%s`: %s (%s) - %s\n", sess.OastID, exp.Subdomain, exp.Title, exp.Severity, status)
+		}
+	}
+	return nil
+}
+
+func expect(mcpURL string, timeout time.Duration, oastID string, opts mcpclient.OastExpectOpts) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	client, err := mcpclient.Connect(ctx, mcpURL)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = client.Close() }()
+
+	resp, err := client.OastExpect(ctx, oastID, opts)
+	if err != nil {
+		return fmt.Errorf("oast expect failed: %w", err)
+	}
+
+	fmt.Printf("Registered expectation `%s`: `%s` on session `%s` confirms %q (%s)\n",
+		resp.ExpectationID, resp.Subdomain, resp.OastID, resp.Title, resp.Severity)
+	cliutil.Hintf("Matching interactions create a draft finding in .sectool/findings/; check with `sectool oast list`\n")
 	return nil
 }
 
@@ -0,0 +1,178 @@
+package payload
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"html"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// Encoding is a named reversible payload encoding.
+type Encoding struct {
+	Name        string
+	Description string
+	Encode      func(string) string
+	Decode      func(string) (string, error)
+}
+
+var encodings = []Encoding{
+	{
+		Name:        "url",
+		Description: "URL query encoding: hello world -> hello+world",
+		Encode:      url.QueryEscape,
+		Decode:      url.QueryUnescape,
+	},
+	{
+		Name:        "double-url",
+		Description: "URL encoding applied twice, for targets that decode twice: ../ -> ..%252F",
+		Encode:      func(s string) string { return url.QueryEscape(url.QueryEscape(s)) },
+		Decode: func(s string) (string, error) {
+			once, err := url.QueryUnescape(s)
+			if err != nil {
+				return "", err
+			}
+			return url.QueryUnescape(once)
+		},
+	},
+	{
+		Name:        "base64",
+		Description: "Standard base64 with padding",
+		Encode:      func(s string) string { return base64.StdEncoding.EncodeToString([]byte(s)) },
+		Decode:      decodeWith(base64.StdEncoding),
+	},
+	{
+		Name:        "base64url",
+		Description: "URL-safe base64 without padding, as in JWTs (padding accepted when decoding)",
+		Encode:      func(s string) string { return base64.RawURLEncoding.EncodeToString([]byte(s)) },
+		Decode: func(s string) (string, error) {
+			return decodeWith(base64.RawURLEncoding)(strings.TrimRight(s, "="))
+		},
+	},
+	{
+		Name:        "hex",
+		Description: "Hex bytes: abc -> 616263 (\\x, 0x and whitespace separators accepted when decoding)",
+		Encode:      func(s string) string { return hex.EncodeToString([]byte(s)) },
+		Decode: func(s string) (string, error) {
+			s = strings.NewReplacer(`\x`, "", "0x", "", " ", "", "\n", "", "\t", "").Replace(s)
+			b, err := hex.DecodeString(s)
+			return string(b), err
+		},
+	},
+	{
+		Name:        "unicode",
+		Description: "JavaScript/JSON \\uXXXX escapes for every character: <a> -> \\u003c\\u0061\\u003e",
+		Encode:      unicodeEscape,
+		Decode:      unicodeUnescape,
+	},
+	{
+		Name:        "html",
+		Description: "HTML escaping of special characters: <script> -> &lt;script&gt;",
+		Encode:      html.EscapeString,
+		Decode:      unescapeHTML,
+	},
+	{
+		Name:        "html-decimal",
+		Description: "Decimal HTML entity for every character: <a -> &#60;&#97;",
+		Encode:      func(s string) string { return htmlEntities(s, "&#%d;") },
+		Decode:      unescapeHTML,
+	},
+	{
+		Name:        "html-hex",
+		Description: "Hex HTML entity for every character: <a -> &#x3c;&#x61;",
+		Encode:      func(s string) string { return htmlEntities(s, "&#x%x;") },
+		Decode:      unescapeHTML,
+	},
+}
+
+// Encodings returns the available encodings.
+func Encodings() []Encoding {
+	return slices.Clone(encodings)
+}
+
+// EncodingNames returns the encoding names.
+func EncodingNames() []string {
+	names := make([]string, len(encodings))
+	for i, e := range encodings {
+		names[i] = e.Name
+	}
+	return names
+}
+
+// GetEncoding returns the named encoding.
+func GetEncoding(name string) (Encoding, error) {
+	name = strings.TrimSpace(strings.ToLower(name))
+	idx := slices.IndexFunc(encodings, func(e Encoding) bool { return e.Name == name })
+	if idx < 0 {
+		return Encoding{}, fmt.Errorf("unknown encoding %q (available: %s)", name, strings.Join(EncodingNames(), ", "))
+	}
+	return encodings[idx], nil
+}
+
+func decodeWith(enc *base64.Encoding) func(string) (string, error) {
+	return func(s string) (string, error) {
+		b, err := enc.DecodeString(s)
+		return string(b), err
+	}
+}
+
+func unescapeHTML(s string) (string, error) {
+	return html.UnescapeString(s), nil
+}
+
+func htmlEntities(s, format string) string {
+	var sb strings.Builder
+	for _, r := range s {
+		_, _ = fmt.Fprintf(&sb, format, r)
+	}
+	return sb.String()
+}
+
+// unicodeEscape writes each rune as \uXXXX, using a surrogate pair outside the BMP.
+func unicodeEscape(s string) string {
+	var sb strings.Builder
+	for _, r := range s {
+		if r1, r2 := utf16.EncodeRune(r); r1 != utf8.RuneError {
+			_, _ = fmt.Fprintf(&sb, `\u%04x\u%04x`, r1, r2)
+		} else {
+			_, _ = fmt.Fprintf(&sb, `\u%04x`, r)
+		}
+	}
+	return sb.String()
+}
+
+// unicodeUnescape replaces \uXXXX escapes (joining surrogate pairs) and leaves other
+// text as is.
+func unicodeUnescape(s string) (string, error) {
+	var units []uint16
+	var sb strings.Builder
+	flush := func() {
+		sb.WriteString(string(utf16.Decode(units)))
+		units = units[:0]
+	}
+	for i := 0; i < len(s); {
+		if strings.HasPrefix(s[i:], `\u`) || strings.HasPrefix(s[i:], `\U`) {
+			if i+6 > len(s) {
+				return "", errors.New("truncated \\u escape")
+			}
+			v, err := strconv.ParseUint(s[i+2:i+6], 16, 16)
+			if err != nil {
+				return "", fmt.Errorf("invalid \\u escape %q", s[i:i+6])
+			}
+			units = append(units, uint16(v))
+			i += 6
+			continue
+		}
+		flush()
+		sb.WriteByte(s[i])
+		i++
+	}
+	flush()
+	return sb.String(), nil
+}
@@ -0,0 +1,71 @@
+package payload
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncodings(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		encoding string
+		input    string
+		encoded  string
+	}{
+		{"url", "a b&c", "a+b%26c"},
+		{"double-url", "../a b", "..%252Fa%2Bb"},
+		{"base64", "hi?", "aGk/"},
+		{"base64url", "hi?>", "aGk_Pg"},
+		{"hex", "abc", "616263"},
+		{"unicode", "<a>", "\\u003c\\u0061\\u003e"},
+		{"unicode", "\U0001F600", "\\ud83d\\ude00"},
+		{"html", "<a href='x'>", "&lt;a href=&#39;x&#39;&gt;"},
+		{"html-decimal", "<a", "&#60;&#97;"},
+		{"html-hex", "<é", "&#x3c;&#xe9;"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.encoding, func(t *testing.T) {
+			enc, err := GetEncoding(tc.encoding)
+			require.NoError(t, err)
+			assert.Equal(t, tc.encoded, enc.Encode(tc.input))
+
+			decoded, err := enc.Decode(tc.encoded)
+			require.NoError(t, err)
+			assert.Equal(t, tc.input, decoded)
+		})
+	}
+}
+
+func TestEncodingsDecodeLenient(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		encoding string
+		input    string
+		want     string
+	}{
+		{"hex", `\x61\x62 0x63`, "abc"},
+		{"base64url", "aGk_Pg==", "hi?>"},
+		{"unicode", "x\\u0041y\\U0042", "xAyB"},
+	}
+	for _, tc := range tests {
+		enc, err := GetEncoding(tc.encoding)
+		require.NoError(t, err)
+		got, err := enc.Decode(tc.input)
+		require.NoError(t, err, tc.encoding)
+		assert.Equal(t, tc.want, got, tc.encoding)
+	}
+
+	unicode, err := GetEncoding("unicode")
+	require.NoError(t, err)
+	_, err = unicode.Decode(`\u00`)
+	assert.Error(t, err)
+	_, err = unicode.Decode(`\uzzzz`)
+	assert.Error(t, err)
+
+	_, err = GetEncoding("rot13")
+	assert.ErrorContains(t, err, "unknown encoding")
+}
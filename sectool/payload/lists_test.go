@@ -0,0 +1,53 @@
+package payload
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetList(t *testing.T) {
+	t.Parallel()
+
+	t.Run("known", func(t *testing.T) {
+		l, err := GetList(" XSS ")
+		require.NoError(t, err)
+		assert.Equal(t, "xss", l.Name)
+		assert.Contains(t, l.Payloads, "<script>alert(1)</script>")
+	})
+
+	t.Run("unknown", func(t *testing.T) {
+		_, err := GetList("nope")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "sqli")
+	})
+
+	t.Run("returns_copy", func(t *testing.T) {
+		l, err := GetList("sqli")
+		require.NoError(t, err)
+		l.Payloads[0] = "changed"
+		again, err := GetList("sqli")
+		require.NoError(t, err)
+		assert.Equal(t, "'", again.Payloads[0])
+	})
+}
+
+func TestListsWellFormed(t *testing.T) {
+	t.Parallel()
+
+	names := make(map[string]bool)
+	for _, l := range Lists() {
+		assert.False(t, names[l.Name], "duplicate list %s", l.Name)
+		names[l.Name] = true
+		assert.NotEmpty(t, l.Description, l.Name)
+		require.NotEmpty(t, l.Payloads, l.Name)
+
+		seen := make(map[string]bool)
+		for _, p := range l.Payloads {
+			assert.NotEmpty(t, p, l.Name)
+			assert.False(t, seen[p], "duplicate payload in %s: %q", l.Name, p)
+			seen[p] = true
+		}
+	}
+}
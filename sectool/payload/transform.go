@@ -0,0 +1,194 @@
+// Package payload provides payload transformations shared by the CLI and the MCP service.
+package payload
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Preset is a named WAF-evasion transformation. Apply returns the variants it produces
+// for a payload; variants identical to the input are dropped by Transform.
+type Preset struct {
+	Name        string
+	Description string
+	Apply       func(string) []string
+}
+
+// PresetAll selects every preset.
+const PresetAll = "all"
+
+// keywordRe matches keywords WAF rules commonly key on: SQL, script/XSS, and shell.
+var keywordRe = regexp.MustCompile(`(?i)\b(select|union|from|where|and|or|insert|update|delete|drop|order|group|by|having|sleep|benchmark|null|script|alert|prompt|confirm|onerror|onload|javascript|iframe|svg|cat|whoami|exec)\b`)
+
+var presets = []Preset{
+	{
+		Name:        "case",
+		Description: "Alternate the case of keywords (or all letters when none are found): SeLeCt, sElEcT",
+		Apply: func(s string) []string {
+			return []string{mangleCase(s, true), mangleCase(s, false)}
+		},
+	},
+	{
+		Name:        "sql-comment",
+		Description: "Insert SQL comments: spaces to /**/, keywords split as UN/**/ION, MySQL /*!UNION*/",
+		Apply: func(s string) []string {
+			return []string{
+				strings.ReplaceAll(s, " ", "/**/"),
+				replaceKeywords(s, func(k string) string { return k[:len(k)/2] + "/**/" + k[len(k)/2:] }),
+				replaceKeywords(s, func(k string) string { return "/*!" + k + "*/" }),
+			}
+		},
+	},
+	{
+		Name:        "whitespace",
+		Description: "Replace spaces with URL-encoded alternative whitespace: %09, %0a, %0d, %0b, %0c, +",
+		Apply: func(s string) []string {
+			var out []string
+			for _, ws := range []string{"%09", "%0a", "%0d", "%0b", "%0c", "+"} {
+				out = append(out, strings.ReplaceAll(s, " ", ws))
+			}
+			return out
+		},
+	},
+	{
+		Name:        "keyword-split",
+		Description: "Nest each keyword inside itself for filters that strip it once: SELSELECTECT, <scrscriptipt>",
+		Apply: func(s string) []string {
+			return []string{replaceKeywords(s, func(k string) string { return k[:len(k)/2] + k + k[len(k)/2:] })}
+		},
+	},
+	{
+		Name:        "overlong-utf8",
+		Description: "URL-encoded overlong UTF-8 for punctuation, for decoders that accept it: / -> %c0%af, %e0%80%af",
+		Apply: func(s string) []string {
+			return []string{overlong(s, 2), overlong(s, 3)}
+		},
+	},
+	{
+		Name:        "null-byte",
+		Description: "URL-encoded null bytes for truncation and keyword filters: payload%00, %00payload, SEL%00ECT",
+		Apply: func(s string) []string {
+			return []string{
+				s + "%00",
+				"%00" + s,
+				replaceKeywords(s, func(k string) string { return k[:len(k)/2] + "%00" + k[len(k)/2:] }),
+			}
+		},
+	},
+}
+
+// Presets returns the available presets.
+func Presets() []Preset {
+	return slices.Clone(presets)
+}
+
+// PresetNames returns the preset names.
+func PresetNames() []string {
+	names := make([]string, len(presets))
+	for i, p := range presets {
+		names[i] = p.Name
+	}
+	return names
+}
+
+// Variant is one transformed payload.
+type Variant struct {
+	Original string
+	Preset   string
+	Payload  string
+}
+
+// Transform applies the named presets (or PresetAll) to each payload and returns the
+// distinct variants that differ from their original, in payload then preset order.
+func Transform(payloads, presetNames []string) ([]Variant, error) {
+	selected, err := selectPresets(presetNames)
+	if err != nil {
+		return nil, err
+	}
+
+	var variants []Variant
+	seen := make(map[string]bool)
+	for _, original := range payloads {
+		for _, p := range selected {
+			for _, v := range p.Apply(original) {
+				if v == original || seen[v] {
+					continue
+				}
+				seen[v] = true
+				variants = append(variants, Variant{Original: original, Preset: p.Name, Payload: v})
+			}
+		}
+	}
+	return variants, nil
+}
+
+func selectPresets(names []string) ([]Preset, error) {
+	if len(names) == 0 || slices.Contains(names, PresetAll) {
+		return presets, nil
+	}
+	var selected []Preset
+	for _, name := range names {
+		name = strings.TrimSpace(strings.ToLower(name))
+		idx := slices.IndexFunc(presets, func(p Preset) bool { return p.Name == name })
+		if idx < 0 {
+			available := PresetNames()
+			sort.Strings(available)
+			return nil, fmt.Errorf("unknown preset %q (available: %s, %s)", name, strings.Join(available, ", "), PresetAll)
+		}
+		selected = append(selected, presets[idx])
+	}
+	return selected, nil
+}
+
+func replaceKeywords(s string, fn func(string) string) string {
+	return keywordRe.ReplaceAllStringFunc(s, fn)
+}
+
+// mangleCase alternates letter case within keywords, or across the whole payload
+// when it has no keywords, starting with upper case when upperFirst is set.
+func mangleCase(s string, upperFirst bool) string {
+	alternate := func(k string) string {
+		var b strings.Builder
+		upper := upperFirst
+		for _, r := range k {
+			if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' {
+				if upper {
+					b.WriteString(strings.ToUpper(string(r)))
+				} else {
+					b.WriteString(strings.ToLower(string(r)))
+				}
+				upper = !upper
+				continue
+			}
+			b.WriteRune(r)
+		}
+		return b.String()
+	}
+	if keywordRe.MatchString(s) {
+		return replaceKeywords(s, alternate)
+	}
+	return alternate(s)
+}
+
+// overlong percent-encodes each ASCII punctuation or symbol character of s as an
+// invalid n-byte (2 or 3) UTF-8 sequence.
+func overlong(s string, n int) string {
+	var b strings.Builder
+	for _, r := range s {
+		if r >= utf8.RuneSelf || !unicode.IsPunct(r) && !unicode.IsSymbol(r) {
+			b.WriteRune(r)
+			continue
+		}
+		if n == 2 {
+			_, _ = fmt.Fprintf(&b, "%%%02x%%%02x", 0xc0|r>>6, 0x80|r&0x3f)
+		} else {
+			_, _ = fmt.Fprintf(&b, "%%e0%%%02x%%%02x", 0x80|r>>6, 0x80|r&0x3f)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,80 @@
+package payload
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransform(t *testing.T) {
+	t.Parallel()
+
+	payloads := func(variants []Variant) []string {
+		out := make([]string, len(variants))
+		for i, v := range variants {
+			out[i] = v.Payload
+		}
+		return out
+	}
+
+	t.Run("sql_comment", func(t *testing.T) {
+		variants, err := Transform([]string{"1 UNION SELECT 1"}, []string{"sql-comment"})
+		require.NoError(t, err)
+		assert.Equal(t, []string{
+			"1/**/UNION/**/SELECT/**/1",
+			"1 UN/**/ION SEL/**/ECT 1",
+			"1 /*!UNION*/ /*!SELECT*/ 1",
+		}, payloads(variants))
+		assert.Equal(t, "sql-comment", variants[0].Preset)
+		assert.Equal(t, "1 UNION SELECT 1", variants[0].Original)
+	})
+
+	t.Run("case", func(t *testing.T) {
+		variants, err := Transform([]string{"<script>alert(1)</script>"}, []string{"case"})
+		require.NoError(t, err)
+		assert.Equal(t, []string{
+			"<ScRiPt>AlErT(1)</ScRiPt>",
+			"<sCrIpT>aLeRt(1)</sCrIpT>",
+		}, payloads(variants))
+
+		variants, err = Transform([]string{"../etc"}, []string{"case"})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"../EtC", "../eTc"}, payloads(variants))
+	})
+
+	t.Run("keyword_split", func(t *testing.T) {
+		variants, err := Transform([]string{"<script>"}, []string{"keyword-split"})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"<scrscriptipt>"}, payloads(variants))
+	})
+
+	t.Run("whitespace", func(t *testing.T) {
+		variants, err := Transform([]string{"a b"}, []string{"whitespace"})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"a%09b", "a%0ab", "a%0db", "a%0bb", "a%0cb", "a+b"}, payloads(variants))
+	})
+
+	t.Run("overlong_utf8", func(t *testing.T) {
+		variants, err := Transform([]string{"../a"}, []string{"overlong-utf8"})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"%c0%ae%c0%ae%c0%afa", "%e0%80%ae%e0%80%ae%e0%80%afa"}, payloads(variants))
+	})
+
+	t.Run("null_byte", func(t *testing.T) {
+		variants, err := Transform([]string{"x.php", "' OR 1"}, []string{"null-byte"})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"x.php%00", "%00x.php", "' OR 1%00", "%00' OR 1", "' O%00R 1"}, payloads(variants))
+	})
+
+	t.Run("all_drops_unchanged_and_duplicates", func(t *testing.T) {
+		variants, err := Transform([]string{"x", "x"}, nil)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"X", "x%00", "%00x"}, payloads(variants))
+	})
+
+	t.Run("unknown_preset", func(t *testing.T) {
+		_, err := Transform([]string{"x"}, []string{"rot13"})
+		assert.ErrorContains(t, err, "unknown preset")
+	})
+}
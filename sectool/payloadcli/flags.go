@@ -0,0 +1,86 @@
+package payloadcli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/spf13/pflag"
+
+	"github.com/go-harden/llm-security-toolbox/sectool/cli"
+)
+
+var payloadsSubcommands = []string{"list", "get", "help"}
+
+// Parse handles `sectool payloads`.
+func Parse(args []string) error {
+	if len(args) < 1 {
+		printUsage()
+		return errors.New("subcommand required")
+	}
+
+	switch args[0] {
+	case "list":
+		return parseList(args[1:])
+	case "get":
+		return parseGet(args[1:])
+	case "help", "--help", "-h":
+		printUsage()
+		return nil
+	default:
+		return cli.UnknownSubcommandError("payloads", args[0], payloadsSubcommands)
+	}
+}
+
+func printUsage() {
+	_, _ = fmt.Fprint(os.Stderr, `Usage: sectool payloads <command> [options]
+
+Built-in payload lists (SQLi, XSS, path traversal, SSTI, CRLF, command
+injection). Runs locally, no service required.
+
+---
+
+payloads list
+
+  List the payload lists with their descriptions and sizes.
+
+---
+
+payloads get <name>...
+
+  Print the payloads of one or more lists, one per line.
+
+  Examples:
+    sectool payloads get sqli
+    sectool payloads get xss | sectool encode transform -f - > xss-waf.txt
+
+  Output: payloads to stdout, one per line
+`)
+}
+
+func parseList(args []string) error {
+	fs := pflag.NewFlagSet("payloads list", pflag.ContinueOnError)
+	fs.Usage = func() {
+		_, _ = fmt.Fprint(os.Stderr, "Usage: sectool payloads list\n")
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	return list()
+}
+
+func parseGet(args []string) error {
+	fs := pflag.NewFlagSet("payloads get", pflag.ContinueOnError)
+	fs.SetInterspersed(true)
+	fs.Usage = func() {
+		_, _ = fmt.Fprint(os.Stderr, "Usage: sectool payloads get <name>...\n\nRun 'sectool payloads list' for the available lists.\n")
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return errors.New("list name required")
+	}
+	return get(fs.Args())
+}
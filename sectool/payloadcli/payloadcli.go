@@ -0,0 +1,37 @@
+package payloadcli
+
+import (
+	"fmt"
+
+	"github.com/go-harden/llm-security-toolbox/sectool/cliutil"
+	"github.com/go-harden/llm-security-toolbox/sectool/payload"
+)
+
+func list() error {
+	for _, l := range payload.Lists() {
+		fmt.Printf("%-15s %3d  %s\n", l.Name, len(l.Payloads), l.Description)
+	}
+	return nil
+}
+
+// get prints the payloads of each named list, one per line, so they can feed other tools.
+func get(names []string) error {
+	var selected []payload.List
+	for _, name := range names {
+		l, err := payload.GetList(name)
+		if err != nil {
+			return err
+		}
+		selected = append(selected, l)
+	}
+
+	var total int
+	for _, l := range selected {
+		for _, p := range l.Payloads {
+			fmt.Println(p)
+		}
+		total += len(l.Payloads)
+	}
+	cliutil.Logf(cliutil.VerbosityNormal, "%d payload(s) from %d list(s)", total, len(selected))
+	return nil
+}
@@ -1,5 +1,7 @@
 package protocol
 
+import "encoding/json"
+
 // =============================================================================
 // Proxy Types
 // =============================================================================
@@ -13,6 +15,19 @@ type SummaryEntry struct {
 	Count  int    `json:"count"`
 }
 
+// EndpointEntry represents traffic grouped by (host, path, method) for reporting.
+type EndpointEntry struct {
+	Host      string `json:"host"`
+	Path      string `json:"path"`
+	Method    string `json:"method"`
+	Count     int    `json:"count"`
+	Statuses  string `json:"statuses"` // "code:count" pairs, e.g. "200:12,403:2"
+	MinSize   int    `json:"min_size"`
+	MaxSize   int    `json:"max_size"`
+	FirstSeen string `json:"first_seen,omitempty"` // from response Date headers
+	LastSeen  string `json:"last_seen,omitempty"`
+}
+
 // FlowEntry represents a single proxy history entry in list view.
 type FlowEntry struct {
 	FlowID         string `json:"flow_id"`
@@ -33,8 +48,11 @@ type RequestLine struct {
 
 // ProxyPollResponse is the unified response for proxy_poll.
 type ProxyPollResponse struct {
-	Aggregates []SummaryEntry `json:"aggregates,omitempty"` // summary mode
-	Flows      []FlowEntry    `json:"flows,omitempty"`      // list mode
+	Aggregates []SummaryEntry  `json:"aggregates,omitempty"`  // summary mode
+	Endpoints  []EndpointEntry `json:"endpoints,omitempty"`   // endpoints mode
+	Flows      []FlowEntry     `json:"flows,omitempty"`       // list mode
+	NextCursor string          `json:"next_cursor,omitempty"` // list mode, set when more flows match
+	TotalCount int             `json:"total_count,omitempty"` // list mode, flows matching the filters across all pages
 }
 
 // ProxyGetResponse is the response for proxy_get.
@@ -55,6 +73,78 @@ type ProxyGetResponse struct {
 	RespSize          int                 `json:"response_size"`
 }
 
+// ProxyImportResponse is the response for proxy_import.
+type ProxyImportResponse struct {
+	Imported int      `json:"imported"`
+	Skipped  int      `json:"skipped,omitempty"` // non-HTTP entries such as data: URLs
+	Hosts    []string `json:"hosts"`
+}
+
+// ProxyFindingsResponse is the response for proxy_findings.
+type ProxyFindingsResponse struct {
+	Scanned    int               `json:"scanned"`  // flows analyzed by this call
+	Recorded   int               `json:"recorded"` // draft findings added by this call
+	Total      int               `json:"total"`    // detections matching the filters
+	Detections []SecretDetection `json:"detections"`
+}
+
+// SecretDetection is a distinct secret or PII value found in proxy history.
+type SecretDetection struct {
+	Detector  string `json:"detector"`
+	Severity  string `json:"severity"`
+	Value     string `json:"value"`    // redacted unless reveal is set
+	Location  string `json:"location"` // request_url, response_header or response_body
+	FlowID    string `json:"flow_id"`  // first flow containing the value
+	Method    string `json:"method"`
+	Host      string `json:"host"`
+	Path      string `json:"path"`
+	Count     int    `json:"count"` // flows containing the value
+	FindingID string `json:"finding_id,omitempty"`
+}
+
+// AnalyzeHeadersResponse is the response for analyze_headers.
+type AnalyzeHeadersResponse struct {
+	Hosts []HeaderHostSummary `json:"hosts"`
+}
+
+// HeaderHostSummary lists the response header issues seen for one host.
+type HeaderHostSummary struct {
+	Host      string        `json:"host"`
+	Responses int           `json:"responses"` // responses analyzed
+	Issues    []HeaderIssue `json:"issues"`
+}
+
+// HeaderIssue is a security header, cookie or CORS problem and how often it occurred.
+type HeaderIssue struct {
+	Check    string   `json:"check"`
+	Severity string   `json:"severity"`
+	Detail   string   `json:"detail,omitempty"` // e.g. cookie name or reflected origin
+	Count    int      `json:"count"`            // responses with the issue
+	FlowIDs  []string `json:"flow_ids"`         // examples
+}
+
+// FlowTagResponse is the response for flow_tag.
+type FlowTagResponse struct {
+	ID   string   `json:"id"`
+	Tags []string `json:"tags"`
+}
+
+// FlowListResponse is the response for flow_list.
+type FlowListResponse struct {
+	Items []TaggedFlow `json:"items"`
+}
+
+// TaggedFlow is a tagged proxy flow or replay.
+type TaggedFlow struct {
+	ID     string   `json:"id"`
+	Kind   string   `json:"kind"` // "flow" or "replay"
+	Tags   []string `json:"tags"`
+	Method string   `json:"method,omitempty"`
+	URL    string   `json:"url,omitempty"`
+	Status int      `json:"status,omitempty"`
+	Label  string   `json:"label,omitempty"` // replay label
+}
+
 // =============================================================================
 // Response Types
 // =============================================================================
@@ -66,6 +156,7 @@ type ResponseDetails struct {
 	RespHeaders string `json:"response_headers"`
 	RespPreview string `json:"response_preview,omitempty"`
 	RespSize    int    `json:"response_size"`
+	Decoded     string `json:"decoded,omitempty"` // codings removed from the response body
 }
 
 // =============================================================================
@@ -77,6 +168,10 @@ type ReplaySendResponse struct {
 	ReplayID string `json:"replay_id"`
 	Duration string `json:"duration"`
 	ResponseDetails
+	SessionRefreshed bool     `json:"session_refreshed,omitempty"` // session re-logged in and the request was resent
+	CSRFToken        string   `json:"csrf_token,omitempty"`        // fresh token injected by refresh_csrf
+	JarSent          []string `json:"jar_sent,omitempty"`          // cookie names the host's cookie jar added
+	JarStored        []string `json:"jar_stored,omitempty"`        // Set-Cookie names stored in (or expired from) the jar
 }
 
 // ReplayGetResponse is the response for replay_get.
@@ -89,6 +184,406 @@ type ReplayGetResponse struct {
 	RespHeadersParsed map[string][]string `json:"response_headers_parsed,omitempty"`
 	RespBody          string              `json:"response_body"`
 	RespSize          int                 `json:"response_size"`
+	BodyRange         *BodyRange          `json:"body_range,omitempty"` // set when a window was requested
+	Rendered          string              `json:"rendered,omitempty"`   // render mode applied to response_body
+}
+
+// BodyRange describes the window of a large body returned by replay_get or oast_get.
+type BodyRange struct {
+	Offset     int    `json:"offset"` // first byte of the window
+	Length     int    `json:"length"`
+	TotalBytes int    `json:"total_bytes"`
+	NextOffset int    `json:"next_offset,omitempty"` // absent at the end of the body
+	NextLine   int    `json:"next_line,omitempty"`   // line windows only
+	Encoding   string `json:"encoding"`              // text, or base64 for binary windows
+}
+
+// ReplayHistoryResponse is the response for replay_history.
+type ReplayHistoryResponse struct {
+	Replays []ReplayHistoryEntry `json:"replays"`
+}
+
+// ReflectionCheckResponse is the response for reflection_check.
+type ReflectionCheckResponse struct {
+	ReplayID          string        `json:"replay_id"`
+	Probe             string        `json:"probe"`
+	Verdict           string        `json:"verdict"` // none, unlikely, ambiguous, likely
+	Reflections       []Reflection  `json:"reflections,omitempty"`
+	Adjudication      *Adjudication `json:"adjudication,omitempty"`
+	AdjudicationError string        `json:"adjudication_error,omitempty"`
+}
+
+// Reflection is one occurrence of a probe in a response body.
+type Reflection struct {
+	Offset   int    `json:"offset"`
+	Context  string `json:"context"`   // html, attribute, script, comment, non_html
+	RawChars string `json:"raw_chars"` // probe special characters that survived unencoded
+	Verdict  string `json:"verdict"`
+	Snippet  string `json:"snippet"`
+}
+
+// ReplayDiffResponse is the response for replay_diff.
+type ReplayDiffResponse struct {
+	ReplayA string `json:"replay_a"`
+	ReplayB string `json:"replay_b"`
+	ResponseDiff
+}
+
+// ResponseExtractResponse is the response for response_extract.
+type ResponseExtractResponse struct {
+	Results []ExtractResult `json:"results"`
+}
+
+// ExtractResult holds the matches found in one replay response.
+type ExtractResult struct {
+	ReplayID string   `json:"replay_id"`
+	Status   int      `json:"status"`
+	Matches  []string `json:"matches"`
+	Total    int      `json:"total"` // matches found, before limit
+	Error    string   `json:"error,omitempty"`
+}
+
+// ResponseDiff compares two responses; Identical ignores filtered noise.
+type ResponseDiff struct {
+	Identical bool       `json:"identical"`
+	Status    StatusDiff `json:"status"`
+	Headers   HeaderDiff `json:"headers"`
+	Body      BodyDiff   `json:"body"`
+}
+
+// StatusDiff compares two status codes.
+type StatusDiff struct {
+	A       int  `json:"a"`
+	B       int  `json:"b"`
+	Changed bool `json:"changed"`
+}
+
+// HeaderDiff lists headers only in B (added), only in A (removed), or with different values.
+type HeaderDiff struct {
+	Added   []HeaderValue  `json:"added,omitempty"`
+	Removed []HeaderValue  `json:"removed,omitempty"`
+	Changed []HeaderChange `json:"changed,omitempty"`
+}
+
+// HeaderValue is a header and its values joined with ", ".
+type HeaderValue struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// HeaderChange is a header present in both responses with different values.
+type HeaderChange struct {
+	Name string `json:"name"`
+	A    string `json:"a"`
+	B    string `json:"b"`
+}
+
+// BodyDiff compares two decoded bodies line by line; JSON bodies are indented first.
+type BodyDiff struct {
+	SizeA      int     `json:"size_a"`
+	SizeB      int     `json:"size_b"`
+	Changed    bool    `json:"changed"`
+	Similarity float64 `json:"similarity"` // 0..1 share of common lines
+	Diff       string  `json:"diff,omitempty"`
+	Truncated  bool    `json:"truncated,omitempty"`
+}
+
+// Adjudication is the client model's judgment of ambiguous evidence, obtained via MCP sampling.
+type Adjudication struct {
+	Verdict string `json:"verdict"` // likely or unlikely
+	Reason  string `json:"reason"`
+	Model   string `json:"model,omitempty"`
+}
+
+// ReplayHistoryEntry summarizes a stored replay result.
+type ReplayHistoryEntry struct {
+	ReplayID  string   `json:"replay_id"`
+	Label     string   `json:"label,omitempty"`
+	Base      string   `json:"base,omitempty"`
+	Method    string   `json:"method"`
+	URL       string   `json:"url"`
+	Status    int      `json:"status"`
+	RespSize  int      `json:"response_size"`
+	Duration  string   `json:"duration"`
+	CreatedAt string   `json:"created_at"`
+	Tags      []string `json:"tags,omitempty"`
+}
+
+// =============================================================================
+// Environment Types
+// =============================================================================
+
+// EnvListResponse is the response for env_list and env_set.
+type EnvListResponse struct {
+	Active       string        `json:"active,omitempty"`
+	Environments []Environment `json:"environments"`
+}
+
+// Environment is a named set of request template variables.
+type Environment struct {
+	Name string            `json:"name"`
+	Vars map[string]string `json:"vars"`
+}
+
+// =============================================================================
+// Spec Import Types
+// =============================================================================
+
+// SpecImportResponse is the response for spec_import.
+type SpecImportResponse struct {
+	Title       string          `json:"title,omitempty"`
+	Version     string          `json:"version,omitempty"`
+	SpecVersion string          `json:"spec_version"`
+	BaseURL     string          `json:"base_url"`
+	Operations  []SpecOperation `json:"operations"`
+}
+
+// SpecOperation is an imported operation; flow_id is usable as replay_send flow_id.
+type SpecOperation struct {
+	FlowID      string   `json:"flow_id"`
+	Method      string   `json:"method"`
+	Path        string   `json:"path"` // path template, e.g. /users/{id}
+	OperationID string   `json:"operation_id,omitempty"`
+	Summary     string   `json:"summary,omitempty"`
+	Params      []string `json:"params,omitempty"`
+	ContentType string   `json:"content_type,omitempty"`
+}
+
+// =============================================================================
+// GraphQL Types
+// =============================================================================
+
+// GraphQLIntrospectResponse is the response for graphql_introspect. Without a
+// type filter it lists types and operations; with one it holds Type.
+type GraphQLIntrospectResponse struct {
+	Endpoint         string               `json:"endpoint"`
+	Source           string               `json:"source"` // introspection or sdl
+	Cached           bool                 `json:"cached,omitempty"`
+	ReplayID         string               `json:"replay_id,omitempty"`
+	QueryType        string               `json:"query_type,omitempty"`
+	MutationType     string               `json:"mutation_type,omitempty"`
+	SubscriptionType string               `json:"subscription_type,omitempty"`
+	Types            []GraphQLTypeSummary `json:"types,omitempty"`
+	Type             *GraphQLType         `json:"type,omitempty"`
+	Operations       []GraphQLOperation   `json:"operations,omitempty"`
+}
+
+// GraphQLTypeSummary is a named type in the schema listing.
+type GraphQLTypeSummary struct {
+	Name   string `json:"name"`
+	Kind   string `json:"kind"`
+	Fields int    `json:"fields,omitempty"`
+}
+
+// GraphQLType is the full definition of a named type.
+type GraphQLType struct {
+	Name          string         `json:"name"`
+	Kind          string         `json:"kind"`
+	Description   string         `json:"description,omitempty"`
+	Fields        []GraphQLField `json:"fields,omitempty"`
+	EnumValues    []string       `json:"enum_values,omitempty"`
+	PossibleTypes []string       `json:"possible_types,omitempty"`
+	Interfaces    []string       `json:"interfaces,omitempty"`
+}
+
+// GraphQLField is a field or input field of a type.
+type GraphQLField struct {
+	Name         string   `json:"name"`
+	Type         string   `json:"type"`           // SDL notation, e.g. [User!]!
+	Args         []string `json:"args,omitempty"` // "name: Type"
+	Description  string   `json:"description,omitempty"`
+	DefaultValue string   `json:"default_value,omitempty"`
+	Deprecated   bool     `json:"deprecated,omitempty"`
+}
+
+// GraphQLOperation is a generated query or mutation; flow_id is usable as replay_send flow_id.
+type GraphQLOperation struct {
+	FlowID     string   `json:"flow_id"`
+	Kind       string   `json:"kind"` // query or mutation
+	Name       string   `json:"name"`
+	Args       []string `json:"args,omitempty"`
+	ReturnType string   `json:"return_type"`
+}
+
+// =============================================================================
+// gRPC Types
+// =============================================================================
+
+// GRPCSchemaResponse is the response for grpc_schema.
+type GRPCSchemaResponse struct {
+	Loaded  []string     `json:"loaded,omitempty"` // files added by this call
+	Methods []GRPCMethod `json:"methods"`
+}
+
+// GRPCMethod is an RPC of a loaded service.
+type GRPCMethod struct {
+	Path      string `json:"path"` // /package.Service/Method
+	Input     string `json:"input"`
+	Output    string `json:"output"`
+	Streaming string `json:"streaming,omitempty"` // client, server or bidi
+}
+
+// GRPCListResponse is the response for grpc_list.
+type GRPCListResponse struct {
+	Calls []GRPCCall `json:"calls"`
+}
+
+// GRPCCall is a gRPC or gRPC-Web call in proxy history.
+type GRPCCall struct {
+	FlowID     string `json:"flow_id"`
+	Host       string `json:"host"`
+	Method     string `json:"method"`   // /package.Service/Method
+	Protocol   string `json:"protocol"` // grpc, grpc-web or grpc-web-text
+	HTTPStatus int    `json:"http_status,omitempty"`
+	GRPCStatus string `json:"grpc_status,omitempty"`
+	Messages   int    `json:"messages"`         // request messages
+	Schema     bool   `json:"schema,omitempty"` // method is in the loaded schema
+}
+
+// GRPCGetResponse is the response for grpc_get.
+type GRPCGetResponse struct {
+	Method      string        `json:"method"`
+	Protocol    string        `json:"protocol"`
+	Schema      bool          `json:"schema,omitempty"`
+	Request     []GRPCMessage `json:"request"`
+	Response    []GRPCMessage `json:"response,omitempty"`
+	HTTPStatus  int           `json:"http_status,omitempty"`
+	GRPCStatus  string        `json:"grpc_status,omitempty"`
+	GRPCMessage string        `json:"grpc_message,omitempty"`
+	FrameError  string        `json:"frame_error,omitempty"` // body was only partly parsed
+}
+
+// GRPCMessage is one decoded message. Without a schema, JSON is keyed by field number.
+type GRPCMessage struct {
+	JSON  json.RawMessage `json:"json,omitempty"`
+	Raw   bool            `json:"raw,omitempty"`
+	Size  int             `json:"size"`
+	Error string          `json:"error,omitempty"`
+}
+
+// GRPCSendResponse is the response for grpc_send.
+type GRPCSendResponse struct {
+	ReplayID    string        `json:"replay_id"`
+	Duration    string        `json:"duration"`
+	Method      string        `json:"method"`
+	HTTPStatus  int           `json:"http_status"`
+	GRPCStatus  string        `json:"grpc_status,omitempty"`
+	GRPCMessage string        `json:"grpc_message,omitempty"`
+	Response    []GRPCMessage `json:"response,omitempty"`
+	FrameError  string        `json:"frame_error,omitempty"`
+}
+
+// =============================================================================
+// HTTP/2 Types
+// =============================================================================
+
+// HTTP2SendResponse is the response for http2_send. Response details are absent when
+// the stream was reset or the connection closed before response headers.
+type HTTP2SendResponse struct {
+	ReplayID string   `json:"replay_id"`
+	Duration string   `json:"duration"`
+	Sent     []string `json:"sent"` // header block as sent, in order
+	*ResponseDetails
+	StreamError string `json:"stream_error,omitempty"` // RST_STREAM error code
+	GoAway      string `json:"goaway,omitempty"`       // GOAWAY error code and debug data
+}
+
+// =============================================================================
+// Raw Send Types
+// =============================================================================
+
+// RawSendResponse is the response for raw_send. The reply is in Text when it is
+// valid UTF-8, else base64 in Data.
+type RawSendResponse struct {
+	Target    string `json:"target"` // host:port
+	Sent      int    `json:"sent"`
+	Received  int    `json:"received"`
+	Text      string `json:"text,omitempty"`
+	Data      string `json:"data,omitempty"`
+	Closed    bool   `json:"closed,omitempty"`    // the peer closed the connection
+	Truncated bool   `json:"truncated,omitempty"` // max_bytes was reached
+	ALPN      string `json:"alpn,omitempty"`
+	Duration  string `json:"duration"`
+}
+
+// TLSProbeResponse is the response for tls_probe.
+type TLSProbeResponse struct {
+	Target       string           `json:"target"` // host:port
+	SNI          string           `json:"sni,omitempty"`
+	Versions     []TLSVersion     `json:"versions"`
+	ALPN         []string         `json:"alpn,omitempty"` // accepted of h2 and http/1.1
+	Certificates []TLSCertificate `json:"certificates"`   // leaf first, as sent by the server
+	Trusted      bool             `json:"trusted"`        // chain verifies against system roots for the server name
+	TrustError   string           `json:"trust_error,omitempty"`
+	Issues       []TLSIssue       `json:"issues"`
+	Untested     string           `json:"untested,omitempty"` // protocols the probe cannot offer
+}
+
+// TLSVersion is one protocol version and the cipher suites the server accepted with it.
+type TLSVersion struct {
+	Version   string      `json:"version"` // e.g. "TLS 1.2"
+	Supported bool        `json:"supported"`
+	Ciphers   []TLSCipher `json:"ciphers,omitempty"` // TLS 1.3: the negotiated suite only
+}
+
+// TLSCipher is an accepted cipher suite.
+type TLSCipher struct {
+	Name     string `json:"name"`
+	Insecure bool   `json:"insecure,omitempty"` // RC4, 3DES, or CBC with SHA-256 per Go's classification
+}
+
+// TLSCertificate summarizes one certificate of the served chain.
+type TLSCertificate struct {
+	Subject            string   `json:"subject"`
+	Issuer             string   `json:"issuer"`
+	SerialNumber       string   `json:"serial_number"`
+	NotBefore          string   `json:"not_before"`
+	NotAfter           string   `json:"not_after"`
+	DaysLeft           int      `json:"days_left"` // negative once expired
+	DNSNames           []string `json:"dns_names,omitempty"`
+	IPAddresses        []string `json:"ip_addresses,omitempty"`
+	Key                string   `json:"key"` // e.g. "RSA 2048", "ECDSA P-256"
+	SignatureAlgorithm string   `json:"signature_algorithm"`
+	SelfSigned         bool     `json:"self_signed,omitempty"`
+}
+
+// TLSIssue is a weak TLS configuration or certificate problem.
+type TLSIssue struct {
+	Check    string `json:"check"`
+	Severity string `json:"severity"` // high, medium, low, info
+	Detail   string `json:"detail,omitempty"`
+}
+
+// =============================================================================
+// WebSocket Types
+// =============================================================================
+
+// WSMessage is a captured or received WebSocket message.
+type WSMessage struct {
+	MessageID string `json:"message_id,omitempty"` // ws_list; usable as ws_send message_id
+	URL       string `json:"url,omitempty"`
+	Direction string `json:"direction"` // to-server or to-client
+	Opcode    string `json:"opcode,omitempty"`
+	Size      int    `json:"size"`
+	Payload   string `json:"payload"` // preview; binary payloads as <BINARY:N Bytes>
+	Time      string `json:"time,omitempty"`
+}
+
+// WSListResponse is the response for ws_list.
+type WSListResponse struct {
+	Messages   []WSMessage `json:"messages"`
+	NextCursor string      `json:"next_cursor,omitempty"`
+}
+
+// WSSendResponse is the response for ws_send.
+type WSSendResponse struct {
+	URL         string      `json:"url"`
+	Status      int         `json:"status"` // handshake status, 101 when upgraded
+	RespHeaders string      `json:"response_headers,omitempty"`
+	Sent        int         `json:"sent"`
+	Messages    []WSMessage `json:"messages"` // received after sending
+	Closed      bool        `json:"closed,omitempty"`
+	Duration    string      `json:"duration"`
 }
 
 // =============================================================================
@@ -104,10 +599,11 @@ type OastCreateResponse struct {
 
 // OastSummaryEntry represents aggregated OAST events by (subdomain, source_ip, type).
 type OastSummaryEntry struct {
-	Subdomain string `json:"subdomain"`
-	SourceIP  string `json:"source_ip"`
-	Type      string `json:"type"`
-	Count     int    `json:"count"`
+	Subdomain      string   `json:"subdomain"`
+	SourceIP       string   `json:"source_ip"`
+	Type           string   `json:"type"`
+	Count          int      `json:"count"`
+	CorrelatedWith []string `json:"correlated_with,omitempty"` // replay IDs whose request carried the subdomain
 }
 
 // OastPollResponse is the response for oast_poll.
@@ -115,16 +611,27 @@ type OastPollResponse struct {
 	Aggregates   []OastSummaryEntry `json:"aggregates,omitempty"` // summary mode
 	Events       []OastEvent        `json:"events,omitempty"`     // list mode
 	DroppedCount int                `json:"dropped_count,omitempty"`
+	NextCursor   string             `json:"next_cursor,omitempty"` // list mode, set when the page was full
 }
 
 // OastEvent represents a single OAST interaction event.
 type OastEvent struct {
-	EventID   string                 `json:"event_id"`
-	Time      string                 `json:"time"`
-	Type      string                 `json:"type"`
-	SourceIP  string                 `json:"source_ip"`
-	Subdomain string                 `json:"subdomain,omitempty"`
-	Details   map[string]interface{} `json:"details,omitempty"`
+	EventID        string                 `json:"event_id"`
+	Time           string                 `json:"time"`
+	Type           string                 `json:"type"`
+	SourceIP       string                 `json:"source_ip"`
+	Subdomain      string                 `json:"subdomain,omitempty"`
+	Details        map[string]interface{} `json:"details,omitempty"`
+	CorrelatedWith []string               `json:"correlated_with,omitempty"` // replay IDs whose request carried the subdomain
+}
+
+// OastNotification is an interaction pushed to oast.notify_url or written as a line
+// to oast.notify_file as it arrives.
+type OastNotification struct {
+	OastID string `json:"oast_id"`
+	Domain string `json:"domain"`
+	Label  string `json:"label,omitempty"`
+	OastEvent
 }
 
 // OastListResponse is the response for oast_list.
@@ -138,16 +645,33 @@ type OastSession struct {
 	Domain    string `json:"domain"`
 	Label     string `json:"label,omitempty"`
 	CreatedAt string `json:"created_at"`
+
+	Expectations []OastExpectation `json:"expectations,omitempty"`
+}
+
+// OastExpectation links interactions on a subdomain pattern to a draft finding,
+// created by oast_expect.
+type OastExpectation struct {
+	ExpectationID string `json:"expectation_id"`
+	OastID        string `json:"oast_id"`
+	Subdomain     string `json:"subdomain"` // glob matched against event subdomains
+	Title         string `json:"title"`
+	Severity      string `json:"severity"`
+	ReplayID      string `json:"replay_id,omitempty"`
+	FindingID     string `json:"finding_id,omitempty"` // set once an interaction confirms it
+	ConfirmedAt   string `json:"confirmed_at,omitempty"`
 }
 
 // OastGetResponse is the response for oast_get.
 type OastGetResponse struct {
-	EventID   string                 `json:"event_id"`
-	Time      string                 `json:"time"`
-	Type      string                 `json:"type"`
-	SourceIP  string                 `json:"source_ip"`
-	Subdomain string                 `json:"subdomain,omitempty"`
-	Details   map[string]interface{} `json:"details,omitempty"`
+	EventID        string                 `json:"event_id"`
+	Time           string                 `json:"time"`
+	Type           string                 `json:"type"`
+	SourceIP       string                 `json:"source_ip"`
+	Subdomain      string                 `json:"subdomain,omitempty"`
+	Details        map[string]interface{} `json:"details,omitempty"`
+	CorrelatedWith []string               `json:"correlated_with,omitempty"` // replay IDs whose request carried the subdomain
+	BodyRange      *BodyRange             `json:"body_range,omitempty"`      // window of the field detail, when requested
 }
 
 // =============================================================================
@@ -161,12 +685,50 @@ type RuleListResponse struct {
 
 // RuleEntry represents a match/replace rule.
 type RuleEntry struct {
-	RuleID  string `json:"rule_id"`
+	RuleID   string `json:"rule_id"`
+	Position int    `json:"position,omitempty"` // 1-based apply order within the HTTP or WebSocket set (list and move)
+	Type     string `json:"type"`
+	Label    string `json:"label,omitempty"`
+	IsRegex  bool   `json:"is_regex,omitempty"`
+	Match    string `json:"match,omitempty"`
+	Replace  string `json:"replace,omitempty"`
+	Enabled  bool   `json:"enabled"`
+	// Hits counts messages the rule modified; nil when the backend does not track hits (Burp).
+	Hits      *int64 `json:"hits,omitempty"`
+	LastHitAt string `json:"last_hit_at,omitempty"`
+}
+
+// RuleAddResponse is the response for proxy_rule_add: the created rule plus regex
+// warnings and a preview against recent proxy history.
+type RuleAddResponse struct {
+	RuleEntry
+	Warnings []string     `json:"warnings,omitempty"`
+	Preview  *RulePreview `json:"preview,omitempty"`
+}
+
+// RulePreview reports how a rule would have affected recent proxy history.
+type RulePreview struct {
+	Scanned  int                `json:"scanned"` // recent flows checked
+	Matched  int                `json:"matched"` // flows the rule would modify
+	Examples []RulePreviewMatch `json:"examples,omitempty"`
+}
+
+// RulePreviewMatch is the first match of a rule in one flow.
+type RulePreviewMatch struct {
+	FlowID string `json:"flow_id"`
+	Match  string `json:"match"`
+	Result string `json:"result"`
+}
+
+// RuleTestResponse is the response for proxy_rule_test.
+type RuleTestResponse struct {
 	Type    string `json:"type"`
-	Label   string `json:"label,omitempty"`
-	IsRegex bool   `json:"is_regex,omitempty"`
-	Match   string `json:"match,omitempty"`
-	Replace string `json:"replace,omitempty"`
+	Section string `json:"section"` // headers, body, or message (WebSocket)
+	Matches int    `json:"matches"`
+	Changed bool   `json:"changed"`
+	Before  string `json:"before"`
+	After   string `json:"after"`
+	Warning string `json:"warning,omitempty"`
 }
 
 // =============================================================================
@@ -207,6 +769,7 @@ type CrawlPollResponse struct {
 	Flows      []CrawlFlow    `json:"flows,omitempty"`
 	Forms      []CrawlForm    `json:"forms,omitempty"`
 	Errors     []CrawlError   `json:"errors,omitempty"`
+	NextCursor string         `json:"next_cursor,omitempty"` // flows mode, set when the page was full
 }
 
 // CrawlFlow is a crawled request/response summary.
@@ -246,6 +809,28 @@ type CrawlError struct {
 	Error  string `json:"error"`
 }
 
+// CrawlResultsResponse is the response for crawl_results.
+type CrawlResultsResponse struct {
+	Endpoints  []CrawlEndpoint `json:"endpoints"`
+	Services   []OpenPort      `json:"services,omitempty"` // open ports from port_scan
+	Sessions   int             `json:"sessions"`           // crawl sessions the endpoints were drawn from
+	NextCursor string          `json:"next_cursor,omitempty"`
+}
+
+// CrawlEndpoint is a unique method/host/path discovered by crawling, merged across sessions.
+type CrawlEndpoint struct {
+	Method   string   `json:"method"`
+	Host     string   `json:"host"`
+	Path     string   `json:"path"`             // numeric IDs and UUIDs replaced with *
+	Params   []string `json:"params,omitempty"` // query parameter and form field names
+	Status   []int    `json:"status,omitempty"`
+	Sources  []string `json:"sources"` // link (visited flow), form and/or dirbust
+	Count    int      `json:"count"`
+	FlowID   string   `json:"flow_id,omitempty"`   // example flow for crawl_get
+	ReplayID string   `json:"replay_id,omitempty"` // dirbust hit for replay_get
+	HasCSRF  bool     `json:"has_csrf,omitempty"`
+}
+
 // CrawlSessionsResponse is the response for crawl_sessions.
 type CrawlSessionsResponse struct {
 	Sessions []CrawlSession `json:"sessions"`
@@ -279,3 +864,758 @@ type CrawlGetResponse struct {
 	Truncated         bool                `json:"truncated,omitempty"`
 	Duration          string              `json:"duration"`
 }
+
+// =============================================================================
+// Scanner Types
+// =============================================================================
+
+// ScanStartResponse is the response for scan_start.
+type ScanStartResponse struct {
+	ScanID string   `json:"scan_id"`
+	URLs   []string `json:"urls"`
+}
+
+// ScanStatusResponse is the response for scan_status.
+type ScanStatusResponse struct {
+	ScanID     string         `json:"scan_id"`
+	Status     string         `json:"status"`
+	Done       bool           `json:"done"`
+	Progress   int            `json:"progress"` // percent
+	Caption    string         `json:"caption,omitempty"`
+	Requests   int            `json:"requests"`
+	Elapsed    string         `json:"elapsed"`
+	IssueCount int            `json:"issue_count"`
+	Severities map[string]int `json:"severities,omitempty"` // issue count per severity
+}
+
+// ScanIssuesResponse is the response for scan_issues.
+type ScanIssuesResponse struct {
+	Issues     []ScanIssue `json:"issues"`
+	Total      int         `json:"total"` // matching issues before limit/offset
+	NextOffset int         `json:"next_offset,omitempty"`
+}
+
+// ScanIssue is a scanner finding.
+type ScanIssue struct {
+	Name        string `json:"name"`
+	Severity    string `json:"severity"`   // high, medium, low, info, false_positive
+	Confidence  string `json:"confidence"` // certain, firm, tentative
+	URL         string `json:"url"`
+	Detail      string `json:"detail,omitempty"`
+	Remediation string `json:"remediation,omitempty"`
+}
+
+// =============================================================================
+// Output Types
+// =============================================================================
+
+// OutputGetResponse is a chunk of a truncated tool result returned by output_get.
+type OutputGetResponse struct {
+	OutputID   string `json:"output_id"`
+	Offset     int    `json:"offset"`
+	TotalBytes int    `json:"total_bytes"`
+	Data       string `json:"data"`
+	NextOffset int    `json:"next_offset,omitempty"`
+}
+
+// =============================================================================
+// Status Types
+// =============================================================================
+
+// StatusResponse describes the running service, returned by status.
+type StatusResponse struct {
+	Version        string            `json:"version"`
+	Uptime         string            `json:"uptime"`
+	WorkflowMode   string            `json:"workflow_mode,omitempty"`
+	Backends       []BackendStatus   `json:"backends"`
+	Metrics        map[string]string `json:"metrics,omitempty"`
+	Scope          ScopeResponse     `json:"scope"`
+	MaxOutputBytes int               `json:"max_output_bytes,omitempty"` // 0: results are not capped
+	RateLimit      *RateLimitStatus  `json:"rate_limit,omitempty"`       // nil: requests are not limited
+}
+
+// RateLimitStatus reports the configured politeness limits on outbound requests.
+// Zero values are unlimited.
+type RateLimitStatus struct {
+	RequestsPerSecond     float64 `json:"requests_per_second,omitempty"`
+	HostRequestsPerSecond float64 `json:"host_requests_per_second,omitempty"`
+	MaxConcurrent         int     `json:"max_concurrent,omitempty"`
+	HostMaxConcurrent     int     `json:"host_max_concurrent,omitempty"`
+	JitterMS              int     `json:"jitter_ms,omitempty"`
+}
+
+// BackendStatus reports the health of one backend.
+type BackendStatus struct {
+	Role         string          `json:"role"` // http, oast, or crawler
+	Name         string          `json:"name"`
+	Detail       string          `json:"detail,omitempty"`
+	Healthy      bool            `json:"healthy"`
+	Error        string          `json:"error,omitempty"`
+	Capabilities map[string]bool `json:"capabilities,omitempty"`
+}
+
+// =============================================================================
+// Scope Types
+// =============================================================================
+
+// ScopeResponse is the response for scope_get and scope_set.
+type ScopeResponse struct {
+	Defined bool     `json:"defined"`
+	Targets []string `json:"targets,omitempty"`
+	Include []string `json:"include,omitempty"`
+	Exclude []string `json:"exclude,omitempty"`
+
+	TargetAllowlist []string `json:"target_allowlist,omitempty"` // from config, read-only to tools
+}
+
+// =============================================================================
+// Batch Types
+// =============================================================================
+
+// BatchResponse holds the results of the calls batch ran, in order.
+type BatchResponse struct {
+	Results []BatchResult `json:"results"`
+	Skipped int           `json:"skipped,omitempty"` // calls not run after a failure
+}
+
+// BatchResult is the outcome of one batched call. Output holds JSON results
+// as-is; other text goes in Text.
+type BatchResult struct {
+	Tool   string          `json:"tool"`
+	Error  string          `json:"error,omitempty"`
+	Output json.RawMessage `json:"output,omitempty"`
+	Text   string          `json:"text,omitempty"`
+}
+
+// =============================================================================
+// Checklist Types
+// =============================================================================
+
+// Checklist item statuses.
+const (
+	ChecklistUntested   = "untested"
+	ChecklistTested     = "tested"
+	ChecklistNA         = "na"
+	ChecklistVulnerable = "vulnerable"
+)
+
+// ChecklistResponse is the response for checklist_get and checklist_mark.
+type ChecklistResponse struct {
+	Methodology string            `json:"methodology"`
+	Coverage    ChecklistCoverage `json:"coverage"`
+	Items       []ChecklistItem   `json:"items,omitempty"`
+}
+
+// ChecklistCoverage counts checklist items by status.
+type ChecklistCoverage struct {
+	Total      int `json:"total"`
+	Untested   int `json:"untested"`
+	Tested     int `json:"tested"`
+	NA         int `json:"na"`
+	Vulnerable int `json:"vulnerable"`
+	Percent    int `json:"percent"` // items no longer untested, of total
+}
+
+// ChecklistItem is one methodology step and its testing state.
+type ChecklistItem struct {
+	ID        string `json:"id"`
+	Category  string `json:"category"`
+	Title     string `json:"title"`
+	Status    string `json:"status"`
+	Note      string `json:"note,omitempty"`
+	UpdatedAt string `json:"updated_at,omitempty"`
+}
+
+// =============================================================================
+// Note Types
+// =============================================================================
+
+// Note is a persisted observation, returned by note_add, note_list and note_search.
+type Note struct {
+	NoteID    string   `json:"note_id"`
+	Text      string   `json:"text"`
+	Tags      []string `json:"tags,omitempty"`
+	Refs      []string `json:"refs,omitempty"` // flow, replay, OAST or finding IDs the note is about
+	CreatedAt string   `json:"created_at"`
+}
+
+// NoteListResponse is the response for note_list and note_search.
+type NoteListResponse struct {
+	Notes []Note `json:"notes"`
+	Total int    `json:"total"` // matching notes before limit
+}
+
+// =============================================================================
+// Finding Types
+// =============================================================================
+
+// Finding severities.
+const (
+	SeverityInfo     = "info"
+	SeverityLow      = "low"
+	SeverityMedium   = "medium"
+	SeverityHigh     = "high"
+	SeverityCritical = "critical"
+)
+
+// Finding statuses.
+const (
+	FindingStatusDraft     = "draft"     // recorded automatically, not yet reviewed
+	FindingStatusConfirmed = "confirmed" // verified and reproducible
+)
+
+// Finding is a recorded vulnerability, persisted in .sectool/findings/.
+type Finding struct {
+	FindingID   string             `json:"finding_id"`
+	Title       string             `json:"title"`
+	Severity    string             `json:"severity"`
+	Status      string             `json:"status"`
+	Description string             `json:"description,omitempty"`
+	FlowIDs     []string           `json:"flow_ids,omitempty"`
+	ReplayIDs   []string           `json:"replay_ids,omitempty"`
+	OastEvents  []FindingOastEvent `json:"oast_events,omitempty"`
+	Steps       []string           `json:"steps,omitempty"`
+	Evidence    []FindingEvidence  `json:"evidence,omitempty"`
+	CreatedAt   string             `json:"created_at"`
+}
+
+// FindingEvidence is a request and response captured when the finding was recorded.
+// Replays are lost on service restart, so findings keep their own copy.
+type FindingEvidence struct {
+	Ref      string `json:"ref"`  // flow_id or replay_id
+	Kind     string `json:"kind"` // "flow" or "replay"
+	Method   string `json:"method"`
+	URL      string `json:"url"`
+	Status   int    `json:"status,omitempty"`
+	Request  string `json:"request"`
+	Response string `json:"response,omitempty"`
+}
+
+// FindingOastEvent references the OAST interaction that evidences a finding.
+type FindingOastEvent struct {
+	OastID    string `json:"oast_id"`
+	EventID   string `json:"event_id"`
+	Type      string `json:"type"`
+	SourceIP  string `json:"source_ip"`
+	Subdomain string `json:"subdomain,omitempty"`
+	Time      string `json:"time"`
+}
+
+// FindingSummary is a finding without its evidence, as listed by finding_list.
+type FindingSummary struct {
+	FindingID string `json:"finding_id"`
+	Title     string `json:"title"`
+	Severity  string `json:"severity"`
+	Status    string `json:"status"`
+	Evidence  int    `json:"evidence"` // evidence items, see finding_get
+	CreatedAt string `json:"created_at"`
+}
+
+// FindingListResponse is the response for finding_list.
+type FindingListResponse struct {
+	Findings []FindingSummary `json:"findings"`
+	Total    int              `json:"total"` // matching findings before limit
+}
+
+// =============================================================================
+// Export Types
+// =============================================================================
+
+// ExportResponse is the response for export.
+type ExportResponse struct {
+	Path   string `json:"path"` // written file, under .sectool/exports/
+	Source string `json:"source"`
+	Format string `json:"format"`
+	Count  int    `json:"count"` // exported flows, replays or findings
+	Bytes  int    `json:"bytes"`
+}
+
+// =============================================================================
+// Payload Types
+// =============================================================================
+
+// PayloadTransformResponse is the response for payload_transform.
+type PayloadTransformResponse struct {
+	Variants []PayloadVariant `json:"variants"`
+}
+
+// MutateResponse is the response for mutate.
+type MutateResponse struct {
+	Payload   string            `json:"payload"`
+	Mutations []PayloadMutation `json:"mutations"`
+	Payloads  []string          `json:"payloads"` // mutation payloads in order, for replay_send or batch
+}
+
+// PayloadMutation is one payload produced by a mutation technique.
+type PayloadMutation struct {
+	Technique string `json:"technique"`
+	Payload   string `json:"payload"`
+}
+
+// DigestResponse is the response for hash and hmac.
+type DigestResponse struct {
+	Algorithm string `json:"algorithm"`
+	Hex       string `json:"hex"`
+	Base64    string `json:"base64"`
+	Match     *bool  `json:"match,omitempty"` // whether the digest equals expected, when given
+}
+
+// PayloadVariant is one payload produced by a transformation preset.
+type PayloadVariant struct {
+	Original string `json:"original"`
+	Preset   string `json:"preset"`
+	Payload  string `json:"payload"`
+}
+
+// PayloadListsResponse is the response for payloads_list.
+type PayloadListsResponse struct {
+	Lists []PayloadListSummary `json:"lists"`
+}
+
+// PayloadListSummary describes a built-in payload list.
+type PayloadListSummary struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Count       int    `json:"count"`
+}
+
+// PayloadsGetResponse is the response for payloads_get.
+type PayloadsGetResponse struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Payloads    []string `json:"payloads"`
+}
+
+// =============================================================================
+// JWT Types
+// =============================================================================
+
+// IdentityListResponse is the response for identity_list and identity_set.
+type IdentityListResponse struct {
+	Identities []Identity `json:"identities"`
+}
+
+// Identity is a named credential set applied by replay_send replay_as and authz_matrix.
+type Identity struct {
+	Name          string            `json:"name"`
+	Headers       []string          `json:"headers,omitempty"`
+	Cookies       map[string]string `json:"cookies,omitempty"`
+	RemoveHeaders []string          `json:"remove_headers,omitempty"`
+}
+
+// AuthzMatrixResponse is the response for authz_matrix.
+type AuthzMatrixResponse struct {
+	Base       string        `json:"base"`     // flow_id or replay_id replayed
+	Baseline   string        `json:"baseline"` // identity whose response the others are compared to
+	Results    []AuthzResult `json:"results"`
+	Suspicious []string      `json:"suspicious,omitempty"` // identities served the baseline response
+}
+
+// AuthzResult is the outcome of replaying the base request as one identity.
+type AuthzResult struct {
+	Identity   string  `json:"identity"`
+	ReplayID   string  `json:"replay_id,omitempty"`
+	Status     int     `json:"status,omitempty"`
+	Size       int     `json:"size"`
+	Similarity float64 `json:"similarity"` // body similarity to the baseline, 0..1
+	Verdict    string  `json:"verdict"`    // baseline, same, different, denied, redirected, error
+	Error      string  `json:"error,omitempty"`
+}
+
+// ParamDiscoverResponse is the response for param_discover.
+type ParamDiscoverResponse struct {
+	Base       string           `json:"base"`     // flow_id or replay_id tested
+	Location   string           `json:"location"` // query, form or json
+	Tested     int              `json:"tested"`   // names tried
+	Requests   int              `json:"requests"` // including the two baseline requests
+	Candidates []ParamCandidate `json:"candidates"`
+	Echoed     bool             `json:"echoed,omitempty"`  // the response echoes every added value, so reflection was ignored
+	Stopped    string           `json:"stopped,omitempty"` // max_requests when the budget ran out before every name was tested
+}
+
+// ParamCandidate is a parameter name that changed the response.
+type ParamCandidate struct {
+	Name     string   `json:"name"`
+	Evidence []string `json:"evidence"`  // e.g. "status 200 -> 500", "value reflected in response"
+	ReplayID string   `json:"replay_id"` // request that showed the change
+	Status   int      `json:"status"`
+	Size     int      `json:"size"`
+}
+
+// SSRFTestResponse is the response for ssrf_test.
+type SSRFTestResponse struct {
+	Base      string              `json:"base"` // flow_id or replay_id tested
+	Param     string              `json:"param,omitempty"`
+	Header    string              `json:"header,omitempty"`
+	Location  string              `json:"location"`          // query, body or header
+	OastID    string              `json:"oast_id,omitempty"` // session oast and redirect payloads point at
+	Domain    string              `json:"domain,omitempty"`
+	Baseline  SSRFResponseSummary `json:"baseline"` // unmodified request
+	Control   SSRFResponseSummary `json:"control"`  // unresolvable URL payloads are compared against
+	Requests  int                 `json:"requests"` // including baseline and control
+	Confirmed int                 `json:"confirmed"`
+	Differs   int                 `json:"differs"`
+	Results   []SSRFResult        `json:"results"`
+}
+
+// SSRFResponseSummary describes a reference response of an ssrf_test run.
+type SSRFResponseSummary struct {
+	Status   int    `json:"status"`
+	Size     int    `json:"size"`
+	Duration string `json:"duration"`
+}
+
+// SSRFResult is the outcome of one ssrf_test payload.
+type SSRFResult struct {
+	Payload  string   `json:"payload"`
+	Category string   `json:"category"`           // loopback, internal, metadata, scheme, oast, redirect or custom
+	Verdict  string   `json:"verdict"`            // confirmed, differs, none or error
+	Evidence []string `json:"evidence,omitempty"` // e.g. "oast http interaction from 1.2.3.4"
+	Status   int      `json:"status,omitempty"`
+	Size     int      `json:"size,omitempty"`
+	Duration string   `json:"duration,omitempty"`
+	ReplayID string   `json:"replay_id,omitempty"` // stored unless the verdict is none or error
+}
+
+// DirbustResponse is the response for dirbust.
+type DirbustResponse struct {
+	URL         string       `json:"url"`    // base directory
+	Tested      int          `json:"tested"` // words per directory, times extensions
+	Requests    int          `json:"requests"`
+	Directories int          `json:"directories"`       // directories brute-forced, including recursion
+	Hits        []DirbustHit `json:"hits"`              // also listed by crawl_results with source dirbust
+	Skipped     int          `json:"skipped,omitempty"` // paths outside the project scope or target allowlist
+	Errors      int          `json:"errors,omitempty"`  // failed requests, with the last in LastError
+	LastError   string       `json:"last_error,omitempty"`
+	Stopped     string       `json:"stopped,omitempty"` // max_requests when the budget ran out
+}
+
+// DirbustHit is a path that answered differently from the not-found fingerprint.
+type DirbustHit struct {
+	Path      string `json:"path"`
+	Status    int    `json:"status"`
+	Size      int    `json:"size"`
+	Location  string `json:"location,omitempty"`
+	Directory bool   `json:"directory,omitempty"` // redirected to the path with a trailing slash
+	Depth     int    `json:"depth"`               // recursion level, 0 for the base directory
+	ReplayID  string `json:"replay_id"`
+}
+
+// VhostDiscoverResponse is the response for vhost_discover.
+type VhostDiscoverResponse struct {
+	URL       string     `json:"url"` // where every request is sent
+	Tested    int        `json:"tested"`
+	Requests  int        `json:"requests"`
+	Baseline  string     `json:"baseline"` // status and size of a random host, e.g. "404 (162 bytes)"
+	Hits      []VhostHit `json:"hits"`
+	Errors    int        `json:"errors,omitempty"`
+	LastError string     `json:"last_error,omitempty"`
+	Stopped   string     `json:"stopped,omitempty"` // max_requests when the budget ran out
+}
+
+// VhostHit is a Host header value answered differently from a random host.
+type VhostHit struct {
+	Host       string `json:"host"`
+	Status     int    `json:"status"`
+	Size       int    `json:"size"`
+	Location   string `json:"location,omitempty"`
+	Title      string `json:"title,omitempty"`
+	OutOfScope bool   `json:"out_of_scope,omitempty"`
+	ReplayID   string `json:"replay_id"`
+}
+
+// SubdomainEnumResponse is the response for subdomain_enum.
+type SubdomainEnumResponse struct {
+	Domain     string      `json:"domain"`
+	Tested     int         `json:"tested"`             // wordlist names resolved
+	Wildcard   []string    `json:"wildcard,omitempty"` // addresses a random name resolves to; names resolving only to these are dropped
+	Subdomains []Subdomain `json:"subdomains"`
+	CTError    string      `json:"ct_error,omitempty"` // certificate transparency lookup failure
+	Errors     int         `json:"errors,omitempty"`   // DNS failures other than not found
+}
+
+// Subdomain is a name found by wordlist resolution or certificate transparency.
+type Subdomain struct {
+	Host       string   `json:"host"`
+	Addresses  []string `json:"addresses,omitempty"` // empty when found only in CT logs and not resolving
+	Sources    []string `json:"sources"`             // dns and/or ct
+	OutOfScope bool     `json:"out_of_scope,omitempty"`
+}
+
+// PortScanResponse is the response for port_scan.
+type PortScanResponse struct {
+	Hosts       []string   `json:"hosts"`
+	Ports       int        `json:"ports"`       // ports checked per host
+	Connections int        `json:"connections"` // including banner and TLS follow-ups
+	Open        []OpenPort `json:"open"`
+	Closed      int        `json:"closed"`
+	Filtered    int        `json:"filtered"`          // no answer within the timeout
+	Skipped     int        `json:"skipped,omitempty"` // host:port pairs outside the project scope or target allowlist
+	Stopped     bool       `json:"stopped,omitempty"` // cancelled before all ports were checked
+	Duration    string     `json:"duration"`
+}
+
+// OpenPort is an open TCP port and the service identified on it.
+type OpenPort struct {
+	Host    string `json:"host"`
+	Port    int    `json:"port"`
+	Service string `json:"service,omitempty"` // from the banner, else the port's usual service
+	Banner  string `json:"banner,omitempty"`  // first line; TLS version and subject for TLS services
+	TLS     bool   `json:"tls,omitempty"`
+}
+
+// DNSQueryResponse is the response for dns_query.
+type DNSQueryResponse struct {
+	Name       string       `json:"name"` // queried name; in-addr.arpa/ip6.arpa form for PTR
+	Server     string       `json:"server"`
+	Status     string       `json:"status"`                // response code of the first query, e.g. NOERROR, NXDOMAIN
+	CNAMEChain []string     `json:"cname_chain,omitempty"` // aliases followed from name, in order
+	Records    []DNSRecord  `json:"records"`
+	Takeover   *DNSTakeover `json:"takeover,omitempty"`
+	Errors     []string     `json:"errors,omitempty"` // per-type failures, e.g. "MX: i/o timeout"
+	OutOfScope bool         `json:"out_of_scope,omitempty"`
+}
+
+// DNSRecord is one answer record.
+type DNSRecord struct {
+	Type     string `json:"type"`
+	Name     string `json:"name"`
+	Value    string `json:"value"`
+	TTL      uint32 `json:"ttl"`
+	Priority int    `json:"priority,omitempty"` // MX preference
+}
+
+// DNSTakeover flags a CNAME that may allow subdomain takeover.
+type DNSTakeover struct {
+	Target   string `json:"target"`             // CNAME target
+	Provider string `json:"provider,omitempty"` // hosting service the target belongs to
+	Dangling bool   `json:"dangling"`           // target does not resolve (NXDOMAIN)
+	Detail   string `json:"detail"`
+}
+
+// SessionListResponse is the response for session_list and session_set.
+type SessionListResponse struct {
+	Sessions []SessionInfo `json:"sessions"`
+}
+
+// SessionInfo describes a login session used by replay_send.
+type SessionInfo struct {
+	Name       string `json:"name"`
+	Login      string `json:"login"` // flow_id or replay_id of the login request
+	URL        string `json:"url"`
+	Extract    string `json:"extract"` // kind:expression, e.g. json:access_token
+	Inject     string `json:"inject"`
+	RefreshOn  string `json:"refresh_on"`
+	Token      string `json:"token,omitempty"`
+	LoggedInAt string `json:"logged_in_at,omitempty"`
+	Logins     int    `json:"logins"`
+	LastError  string `json:"last_error,omitempty"`
+}
+
+// CSRFListResponse is the response for csrf_set.
+type CSRFListResponse struct {
+	Rules []CSRFRuleInfo `json:"rules"`
+}
+
+// CSRFRuleInfo describes how replay_send refresh_csrf fetches a token for a host.
+type CSRFRuleInfo struct {
+	Host      string `json:"host"`
+	Source    string `json:"source"` // flow_id or replay_id of the token page request
+	URL       string `json:"url"`
+	Extract   string `json:"extract"` // kind:expression, e.g. field:csrf_token
+	Param     string `json:"param,omitempty"`
+	Header    string `json:"header,omitempty"`
+	Token     string `json:"token,omitempty"` // last fetched token
+	FetchedAt string `json:"fetched_at,omitempty"`
+	LastError string `json:"last_error,omitempty"`
+}
+
+// CookieJarListResponse is the response for cookie_jar_list and cookie_jar_set.
+type CookieJarListResponse struct {
+	Jars []CookieJar `json:"jars"`
+}
+
+// CookieJar holds the cookies replay_send captured from, and attaches to, one host.
+type CookieJar struct {
+	Host    string      `json:"host"`
+	Enabled bool        `json:"enabled"`
+	Cookies []JarCookie `json:"cookies,omitempty"`
+}
+
+// JarCookie is a cookie in a cookie jar.
+type JarCookie struct {
+	Name    string `json:"name"`
+	Value   string `json:"value"`
+	Path    string `json:"path"`
+	Secure  bool   `json:"secure,omitempty"`
+	Expires string `json:"expires,omitempty"` // RFC 3339; empty for session cookies
+}
+
+// SequenceListResponse is the response for sequence_list and sequence_set.
+type SequenceListResponse struct {
+	Sequences []SequenceInfo `json:"sequences"`
+}
+
+// SequenceInfo describes a sequence run by sequence_run.
+type SequenceInfo struct {
+	Name   string             `json:"name"`
+	Inputs []string           `json:"inputs,omitempty"` // placeholders no step extracts: set by sequence_run vars or an environment
+	Steps  []SequenceStepInfo `json:"steps"`
+}
+
+// SequenceStepInfo describes one step of a sequence.
+type SequenceStepInfo struct {
+	Name    string            `json:"name,omitempty"`
+	Tool    string            `json:"tool"`
+	Source  string            `json:"source"`            // flow_id, replay_id or URL the request comes from
+	Extract map[string]string `json:"extract,omitempty"` // variable to kind:expression, e.g. json:data.token
+	Expect  string            `json:"expect,omitempty"`
+}
+
+// SequenceRunResponse is the response for sequence_run.
+type SequenceRunResponse struct {
+	Name      string               `json:"name"`
+	Completed bool                 `json:"completed"` // every step ran and passed
+	Steps     []SequenceStepResult `json:"steps"`
+	Skipped   int                  `json:"skipped,omitempty"` // steps not run after a failure
+	Vars      map[string]string    `json:"vars,omitempty"`    // inputs and extracted values after the last step run
+}
+
+// SequenceStepResult is the outcome of one sequence step.
+type SequenceStepResult struct {
+	Step      int               `json:"step"` // 1-based
+	Name      string            `json:"name,omitempty"`
+	ReplayID  string            `json:"replay_id,omitempty"`
+	Method    string            `json:"method,omitempty"`
+	URL       string            `json:"url,omitempty"`
+	Status    int               `json:"status,omitempty"`
+	RespSize  int               `json:"resp_size,omitempty"`
+	Duration  string            `json:"duration,omitempty"`
+	Extracted map[string]string `json:"extracted,omitempty"`
+	Error     string            `json:"error,omitempty"`
+}
+
+// CSRFAnalyzeResponse is the response for csrf_analyze.
+type CSRFAnalyzeResponse struct {
+	Hosts []CSRFHostSummary `json:"hosts"`
+}
+
+// CSRFHostSummary lists the anti-CSRF tokens seen for one host and the
+// state-changing requests that carry none.
+type CSRFHostSummary struct {
+	Host        string        `json:"host"`
+	Requests    int           `json:"requests"` // state-changing requests sent with cookies
+	Tokens      []CSRFToken   `json:"tokens,omitempty"`
+	Unprotected []CSRFRequest `json:"unprotected,omitempty"`
+	Suggest     string        `json:"suggest,omitempty"` // csrf_set arguments for the most used token
+}
+
+// CSRFToken is a token name seen in one place. Locations hidden_input, meta,
+// set_cookie, response_header and response_json are where responses issue it;
+// header, form, multipart, json and query are where requests send it.
+type CSRFToken struct {
+	Name     string `json:"name"`
+	Location string `json:"location"`
+	Count    int    `json:"count"`
+	FlowID   string `json:"flow_id"` // example flow
+}
+
+// CSRFRequest is a state-changing endpoint sent with cookies but no token.
+type CSRFRequest struct {
+	Method string `json:"method"`
+	Path   string `json:"path"`
+	Count  int    `json:"count"`
+	FlowID string `json:"flow_id"`
+}
+
+// JWTCrackResponse is the response for jwt_crack.
+type JWTCrackResponse struct {
+	Found        bool     `json:"found"`
+	Secret       string   `json:"secret,omitempty"`
+	Algorithm    string   `json:"algorithm"`
+	Tried        int      `json:"tried"`
+	Duration     string   `json:"duration"`
+	Stopped      string   `json:"stopped"` // found, exhausted, timeout, limit
+	ForgedToken  string   `json:"forged_token,omitempty"`
+	ForgedClaims []string `json:"forged_claims,omitempty"` // claims changed in forged_token
+}
+
+// JWTDecodeResponse is the response for jwt_decode.
+type JWTDecodeResponse struct {
+	Tokens    []JWTDecoded `json:"tokens"`
+	Encrypted []JWEDecoded `json:"encrypted,omitempty"` // JWEs: header and segment sizes only
+}
+
+// JWEDecoded is one JWE, described without decrypting it.
+type JWEDecoded struct {
+	Token             string                 `json:"token"`
+	Algorithm         string                 `json:"algorithm"`  // key management (alg)
+	Encryption        string                 `json:"encryption"` // content encryption (enc)
+	Header            map[string]interface{} `json:"header"`
+	EncryptedKeyBytes int                    `json:"encrypted_key_bytes"`
+	IVBytes           int                    `json:"iv_bytes"`
+	CiphertextBytes   int                    `json:"ciphertext_bytes"`
+	TagBytes          int                    `json:"tag_bytes"`
+	Hints             []string               `json:"hints,omitempty"`
+}
+
+// JWTDecoded is one decoded (unverified) JWT.
+type JWTDecoded struct {
+	Token     string                 `json:"token"`
+	Algorithm string                 `json:"algorithm"`
+	Header    map[string]interface{} `json:"header"`
+	Claims    map[string]interface{} `json:"claims"`
+	IssuedAt  string                 `json:"issued_at,omitempty"`  // RFC 3339 from iat
+	NotBefore string                 `json:"not_before,omitempty"` // RFC 3339 from nbf
+	ExpiresAt string                 `json:"expires_at,omitempty"` // RFC 3339 from exp
+	Expired   bool                   `json:"expired,omitempty"`
+	Hints     []string               `json:"hints,omitempty"` // attack suggestions
+}
+
+// JWTForgeResponse is the response for jwt_forge.
+type JWTForgeResponse struct {
+	Token     string                 `json:"token"`
+	Algorithm string                 `json:"algorithm"`
+	Header    map[string]interface{} `json:"header"`
+	Claims    map[string]interface{} `json:"claims"`
+	Changed   []string               `json:"changed,omitempty"` // claims changed from the input token
+	Secret    string                 `json:"secret,omitempty"`  // HMAC secret recovered by guess_secret
+	AddHeader string                 `json:"add_header"`        // ready for replay_send add_headers
+}
+
+// =============================================================================
+// SAML Types
+// =============================================================================
+
+// SAMLDecodeResponse is the response for saml_decode.
+type SAMLDecodeResponse struct {
+	Messages []SAMLMessage `json:"messages"`
+}
+
+// SAMLMessage is one decoded (unverified) SAML message.
+type SAMLMessage struct {
+	Binding            string              `json:"binding,omitempty"` // post or redirect (deflated)
+	Type               string              `json:"type"`              // root element, e.g. Response, AuthnRequest
+	ID                 string              `json:"id,omitempty"`
+	IssueInstant       string              `json:"issue_instant,omitempty"`
+	Destination        string              `json:"destination,omitempty"`
+	InResponseTo       string              `json:"in_response_to,omitempty"`
+	ACSURL             string              `json:"acs_url,omitempty"`
+	Issuer             string              `json:"issuer,omitempty"`
+	Status             string              `json:"status,omitempty"`
+	NameID             string              `json:"name_id,omitempty"`
+	Audiences          []string            `json:"audiences,omitempty"`
+	NotBefore          string              `json:"not_before,omitempty"`
+	NotOnOrAfter       string              `json:"not_on_or_after,omitempty"`
+	Attributes         map[string][]string `json:"attributes,omitempty"`
+	Signed             []string            `json:"signed,omitempty"` // message and/or assertion
+	EncryptedAssertion bool                `json:"encrypted_assertion,omitempty"`
+	Hints              []string            `json:"hints,omitempty"`
+	XML                string              `json:"xml"`
+}
+
+// SAMLEncodeResponse is the response for saml_encode.
+type SAMLEncodeResponse struct {
+	Encoded    string `json:"encoded"`     // base64, DEFLATE-compressed first for the redirect binding
+	URLEncoded string `json:"url_encoded"` // encoded, escaped for a query string or form body
+}
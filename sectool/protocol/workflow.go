@@ -5,5 +5,21 @@ const (
 	WorkflowModeNone       = "none"
 	WorkflowModeExplore    = "explore"
 	WorkflowModeTestReport = "test-report"
+	WorkflowModeAPI        = "api"
+	WorkflowModeLLMApp     = "llm-app"
+	WorkflowModeMobile     = "mobile-backend"
 	WorkflowModeCLI        = "cli" // undocumented, for CLI client use only
 )
+
+// ClientHeader names a client across MCP sessions. Clients sending the same name share
+// per-client state such as "since=last" markers; the CLI sends ClientNameCLI because
+// each invocation opens a new session.
+const (
+	ClientHeader  = "X-Sectool-Client"
+	ClientNameCLI = "cli"
+)
+
+// ConfirmOutOfScopeHeader carries the service's confirm key (config.ConfirmKeyFileName)
+// on CLI requests the user confirmed with --confirm-out-of-scope. Sends made for
+// them skip the target allowlist and project scope checks; MCP tools have no way to.
+const ConfirmOutOfScopeHeader = "X-Sectool-Confirm-Out-Of-Scope"
@@ -0,0 +1,77 @@
+package proxy
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-harden/llm-security-toolbox/sectool/cliutil"
+	"github.com/go-harden/llm-security-toolbox/sectool/mcpclient"
+)
+
+// csrfRuleFlags turns csrf_set arguments into 'sectool session csrf' flags.
+var csrfRuleFlags = strings.NewReplacer(
+	"flow_id=", "--flow ",
+	"extract_field=", "--field ",
+	"extract_json=", "--json ",
+	"extract_header=", "--header ",
+	"extract_cookie=", "--cookie ",
+	"param=", "--param ",
+	"header=", "--set-header ",
+)
+
+func csrf(mcpURL string, timeout time.Duration, opts mcpclient.CSRFAnalyzeOpts) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	client, err := mcpclient.Connect(ctx, mcpURL)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = client.Close() }()
+
+	resp, err := client.CSRFAnalyze(ctx, opts)
+	if err != nil {
+		return fmt.Errorf("proxy csrf failed: %w", err)
+	}
+	if len(resp.Hosts) == 0 {
+		fmt.Println("No CSRF tokens or state-changing requests with cookies found.")
+		return nil
+	}
+
+	defer cliutil.StartPager()()
+
+	var suggested bool
+	for _, h := range resp.Hosts {
+		fmt.Printf("## %s\n\n", h.Host)
+		fmt.Printf("State-changing requests with cookies: %d\n\n", h.Requests)
+		if len(h.Tokens) > 0 {
+			fmt.Println("| name | location | count | flow_id |")
+			fmt.Println("|------|----------|-------|---------|")
+			for _, tok := range h.Tokens {
+				fmt.Printf("| %s | %s | %d | %s |\n", cliutil.EscapeMarkdown(tok.Name), tok.Location, tok.Count, tok.FlowID)
+			}
+			fmt.Println()
+		}
+		if len(h.Unprotected) > 0 {
+			fmt.Println("Without a token:")
+			fmt.Println()
+			fmt.Println("| method | path | count | flow_id |")
+			fmt.Println("|--------|------|-------|---------|")
+			for _, r := range h.Unprotected {
+				fmt.Printf("| %s | %s | %s | %s |\n", r.Method, cliutil.EscapeMarkdown(r.Path), strconv.Itoa(r.Count), r.FlowID)
+			}
+			fmt.Println()
+		}
+		if h.Suggest != "" {
+			fmt.Printf("Suggested rule: `sectool session csrf %s`\n\n", csrfRuleFlags.Replace(h.Suggest))
+			suggested = true
+		}
+	}
+	if suggested {
+		cliutil.Hintf("After defining the rule: `sectool replay send --flow <flow_id> --refresh-csrf`\n")
+	}
+	return nil
+}
@@ -6,10 +6,11 @@ import (
 	"time"
 
 	"github.com/go-harden/llm-security-toolbox/sectool/bundle"
+	"github.com/go-harden/llm-security-toolbox/sectool/cliutil"
 	"github.com/go-harden/llm-security-toolbox/sectool/mcpclient"
 )
 
-func export(mcpURL string, timeout time.Duration, flowID string) error {
+func export(mcpURL string, timeout time.Duration, flowID string, copyRaw bool) error {
 	ctx, cancel := context.WithTimeout(context.Background(), timeout)
 	defer cancel()
 
@@ -34,6 +35,7 @@ func export(mcpURL string, timeout time.Duration, flowID string) error {
 		return fmt.Errorf("decode response body: %w", err)
 	}
 
+	flowID = resp.FlowID // resolve shortcuts such as "last"
 	bundleDir, err := bundle.Write(flowID,
 		resp.URL, resp.Method, resp.ReqHeaders, reqBody,
 		resp.RespHeaders, respBody)
@@ -51,8 +53,48 @@ func export(mcpURL string, timeout time.Duration, flowID string) error {
 		fmt.Println("- response.http - response headers")
 		fmt.Println("- response.body - response body")
 	}
+	if copyRaw {
+		method, err := cliutil.CopyToClipboard(resp.ReqHeaders + string(reqBody))
+		if err != nil {
+			return fmt.Errorf("copy to clipboard: %w", err)
+		}
+		fmt.Printf("\nRaw request copied to clipboard (%s)\n", method)
+	}
 	fmt.Println()
-	fmt.Printf("To replay: `sectool replay send --bundle %s`\n", flowID)
+	cliutil.Hintf("To replay: `sectool replay send --bundle %s`\n", flowID)
+
+	return nil
+}
+
+// exportBody writes a flow's decoded response body to output. A content encoding
+// that cannot be removed is reported, and the body is saved as received.
+func exportBody(mcpURL string, timeout time.Duration, flowID, output string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	client, err := mcpclient.Connect(ctx, mcpURL)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = client.Close() }()
+
+	resp, err := client.ProxyGet(ctx, flowID)
+	if err != nil {
+		return fmt.Errorf("get flow: %w", err)
+	}
 
+	body, err := bundle.DecodeBase64Body(resp.RespBody)
+	if err != nil {
+		return fmt.Errorf("decode response body: %w", err)
+	}
+	if body, err = bundle.DecodeBody(resp.RespHeaders, body); err != nil {
+		cliutil.Logf(cliutil.VerbosityNormal, "warning: %v", err)
+	}
+	if err := bundle.WriteBody(output, body); err != nil {
+		return fmt.Errorf("write body: %w", err)
+	}
+	if output != "-" {
+		fmt.Printf("Wrote %d bytes from flow `%s` to `%s`\n", len(body), resp.FlowID, output)
+	}
 	return nil
 }
@@ -0,0 +1,52 @@
+package proxy
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/go-harden/llm-security-toolbox/sectool/cliutil"
+	"github.com/go-harden/llm-security-toolbox/sectool/mcpclient"
+)
+
+var secretColumns = []string{"severity", "detector", "value", "location", "flow_id", "method", "host", "path", "count", "finding_id"}
+
+func findings(mcpURL string, timeout time.Duration, opts mcpclient.ProxyFindingsOpts, format cliutil.Format, columns []string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	client, err := mcpclient.Connect(ctx, mcpURL)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = client.Close() }()
+
+	resp, err := client.ProxyFindings(ctx, opts)
+	if err != nil {
+		return fmt.Errorf("proxy findings failed: %w", err)
+	}
+
+	defer cliutil.StartPager()()
+
+	t := cliutil.NewTable(os.Stdout, format, secretColumns, columns)
+	if len(resp.Detections) == 0 && t.Markdown() {
+		fmt.Printf("No secrets or PII detected (%d new flows scanned).\n", resp.Scanned)
+		return nil
+	}
+	t.Header()
+	for _, d := range resp.Detections {
+		t.Row(d.Severity, d.Detector, d.Value, d.Location, d.FlowID, d.Method, d.Host, d.Path, strconv.Itoa(d.Count), d.FindingID)
+	}
+	t.Flush()
+	if !t.Markdown() {
+		return nil
+	}
+	fmt.Printf("\n*%d of %d detections shown, %d new flows scanned*\n", len(resp.Detections), resp.Total, resp.Scanned)
+	if resp.Recorded > 0 {
+		fmt.Printf("\n%d draft findings recorded.\n", resp.Recorded)
+		cliutil.Hintf("To review them: `sectool report --status draft`\n")
+	}
+	return nil
+}
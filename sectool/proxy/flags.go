@@ -4,14 +4,18 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/spf13/pflag"
 
 	"github.com/go-harden/llm-security-toolbox/sectool/cli"
+	"github.com/go-harden/llm-security-toolbox/sectool/cliutil"
+	"github.com/go-harden/llm-security-toolbox/sectool/mcpclient"
 )
 
-var proxySubcommands = []string{"summary", "list", "export", "rule", "help"}
+var proxySubcommands = []string{"start", "summary", "endpoints", "list", "export", "import", "findings", "headers", "csrf", "tag", "tagged", "rule", "help"}
 
 func Parse(args []string, mcpURL string) error {
 	if len(args) < 1 {
@@ -22,10 +26,24 @@ func Parse(args []string, mcpURL string) error {
 	switch args[0] {
 	case "summary":
 		return parseSummary(args[1:], mcpURL)
+	case "endpoints":
+		return parseEndpoints(args[1:], mcpURL)
 	case "list":
 		return parseList(args[1:], mcpURL)
 	case "export":
 		return parseExport(args[1:], mcpURL)
+	case "import":
+		return parseImport(args[1:], mcpURL)
+	case "findings":
+		return parseFindings(args[1:], mcpURL)
+	case "headers":
+		return parseHeaders(args[1:], mcpURL)
+	case "csrf":
+		return parseCSRF(args[1:], mcpURL)
+	case "tag":
+		return parseTag(args[1:], mcpURL)
+	case "tagged":
+		return parseTagged(args[1:], mcpURL)
 	case "rule":
 		return parseRule(args[1:], mcpURL)
 	case "help", "--help", "-h":
@@ -45,6 +63,28 @@ Run 'sectool replay --help' to see all replay options and modification support.
 
 ---
 
+proxy start [options]
+
+  Run the built-in intercepting proxy in the foreground, without Burp. The
+  MCP service runs in the same process (instead of 'sectool mcp'), so the
+  other commands work against the captured traffic. A CA certificate is
+  generated in the config directory on first start; trust it in the browser
+  or device to intercept HTTPS.
+
+  Options:
+    --listen <addr>         listen address host:port (default: 127.0.0.1 and
+                            proxy_port from config, 8080); ':8080' listens on
+                            all interfaces, e.g. for a mobile device
+    --port <n>              MCP server port (default: from config or 9119)
+    --project-dir <dir>     project directory for .sectool/
+    --workflow <mode>       MCP workflow mode (default: none)
+
+  Examples:
+    sectool proxy start
+    sectool proxy start --listen :8080
+
+---
+
 proxy summary [options]
 
   Get aggregated summary of proxy history grouped by host/path/method/status.
@@ -59,13 +99,35 @@ proxy summary [options]
     --contains-body <text>  search request/response body
     --exclude-host <pat>    exclude matching hosts
     --exclude-path <pat>    exclude matching paths
+    --format <fmt>          output format: markdown, plain, csv, tsv
+    --columns <list>        columns to show (host,path,method,status,count)
 
   Examples:
     sectool proxy summary                                 # full summary
     sectool proxy summary --host api.example.com          # summary for host
     sectool proxy summary --exclude-host "*.google.com"   # filter out noise
 
-  Output: Markdown table with host, path, method, status, count
+  Output: Table with host, path, method, status, count (Markdown by default)
+
+---
+
+proxy endpoints [options]
+
+  Export one row per endpoint (host/path/method) for spreadsheets and
+  coverage tracking: request count, status breakdown, response size range,
+  and first/last seen (from response Date headers). CSV by default.
+
+  Options:
+    Same filters as proxy summary (--host, --path, --method, --status, ...)
+    --format <fmt>          output format: csv (default), tsv, markdown, plain
+    --columns <list>        columns to show (host,path,method,count,statuses,
+                            min_size,max_size,first_seen,last_seen)
+
+  Examples:
+    sectool proxy endpoints > endpoints.csv
+    sectool proxy endpoints --host "*.example.com" --format tsv
+
+  Output: Table with one row per endpoint
 
 ---
 
@@ -86,14 +148,23 @@ proxy list [options]
     --exclude-path <pat>    exclude matching paths
     --limit <n>             maximum number of flows to return
     --offset <n>            skip first N results (applied after filtering)
+    --page <n>              page number (1-based, uses --limit as page size)
+    --cursor <token>        continue from a previous page's next cursor
+    --follow                keep polling and print new flows as they arrive
+    --interval <dur>        poll interval for --follow (default: 2s)
+    --format <fmt>          output format: markdown, plain, csv, tsv
+    --columns <list>        columns to show (flow_id,method,host,path,status,size)
 
   Examples:
     sectool proxy list --host api.example.com             # flows for host
     sectool proxy list --host "*.example.com" --method POST,PUT
     sectool proxy list --path "/api/*" --status 200,201
     sectool proxy list --since f7k2x --limit 10            # flows after specific ID
+    sectool proxy list --follow --host api.example.com    # watch new traffic live
+    sectool proxy list --limit 500 --format csv --columns flow_id,host,path
 
-  Output: Markdown table with flow_id, method, host, path, status, size
+  Output: Table with flow_id, method, host, path, status, size (Markdown by
+  default); when paged, the number of matching flows across all pages
 
 ---
 
@@ -115,11 +186,130 @@ proxy export <flow_id>
     sectool proxy list --host example.com     # find flow_id
     sectool proxy export f7k2x                # exports to sectool-requests/f7k2x/
     sectool replay send --bundle f7k2x        # replay the exported bundle
+    sectool proxy export f7k2x --copy         # also copy raw request to clipboard
+    sectool proxy export last                 # most recent proxy entry (or last-N)
+    sectool proxy export f7k2x -o export.zip  # only save the decoded response body
 
   Output: Bundle path and files created
 
 ---
 
+proxy import --har <file>
+
+  Load a HAR capture (e.g., a browser DevTools "Save all as HAR" export)
+  into proxy history, so its flows work with proxy list, replay and the
+  other flow commands. Requires the built-in proxy.
+
+  Examples:
+    sectool proxy import --har app.har
+    sectool proxy list --host app.example.com   # find the imported flows
+
+  Output: Number of imported flows and their hosts
+
+---
+
+proxy findings [options]
+
+  Passively scan proxy history for leaked secrets and PII: private keys,
+  AWS, Google, GitHub, Slack and Stripe keys, generic API keys, JWTs,
+  payment card numbers and emails. Response headers and bodies and request
+  URLs are scanned; each call covers flows captured since the last one.
+  Detections above info are added as draft findings (see 'sectool report').
+
+  Options:
+    --detector <name>       only this detector (repeatable)
+    --severity <level>      minimum severity: info, low, medium, high, critical
+    --host <pattern>        only flows whose host matches (glob: *, ?)
+    --no-record             do not add draft findings
+    --reveal                show full values instead of redacted ones
+    --limit <n>             maximum detections to show (default: 50)
+    --format <fmt>          output format: markdown, plain, csv, tsv
+
+  Examples:
+    sectool proxy findings
+    sectool proxy findings --severity high --reveal
+    sectool proxy findings --detector email --no-record
+
+  Output: Table with severity, detector, value, location, flow_id, URL, count
+
+---
+
+proxy headers [options]
+
+  Passively check response headers in proxy history, per host: missing HSTS,
+  CSP, X-Content-Type-Options and frame protection, weak CSP script sources,
+  cookies without Secure, HttpOnly or SameSite, and CORS policies that allow
+  the null origin, wildcard with credentials, or reflect the request Origin
+  with credentials. Nothing is sent.
+
+  Options:
+    --host <pattern>        only hosts matching (glob: *, ?)
+    --severity <level>      minimum severity: info, low, medium, high, critical
+    --format <fmt>          output format: markdown, plain, csv, tsv
+
+  Examples:
+    sectool proxy headers
+    sectool proxy headers --host "*.example.com" --severity medium
+
+  Output: Table with host, severity, check, detail, count and example flow_ids
+
+---
+
+proxy csrf [options]
+
+  Passively find anti-CSRF tokens in proxy history, per host: where responses
+  issue them (hidden inputs, meta tags, cookies, headers, JSON) and where
+  requests send them (headers, form, multipart or JSON fields, query). Lists
+  POST/PUT/PATCH/DELETE requests sent with cookies but no token, and suggests
+  a 'sectool session csrf' rule. Nothing is sent.
+
+  Options:
+    --host <pattern>        only hosts matching (glob: *, ?)
+
+  Examples:
+    sectool proxy csrf
+    sectool proxy csrf --host "app.example.com"
+
+  Output: Per host, tokens with location, count and example flow_id, and
+          endpoints without a token
+
+---
+
+proxy tag (--flow <id> | --replay <id>) [tag...] [options]
+
+  Tag a proxy flow or replay to find it again later with 'proxy tagged'.
+  Tags are kept in memory by the MCP service. Without tags or --remove,
+  prints the current tags.
+
+  Options:
+    --flow <id>             flow ID (or last, last-N)
+    --replay <id>           replay ID or label (or last, last-N)
+    --remove <tag>          remove a tag (repeatable)
+
+  Examples:
+    sectool proxy tag --flow f7k2x1 auth login
+    sectool proxy tag --replay last idor-candidate
+    sectool proxy tag --flow f7k2x1 --remove login
+
+---
+
+proxy tagged [options]
+
+  List tagged flows and replays in the order first tagged.
+
+  Options:
+    --tag <tag>             only items with this tag
+    --format <fmt>          output format: markdown, plain, csv, tsv
+    --columns <list>        columns to show (id,kind,tags,method,url,status,label)
+
+  Examples:
+    sectool proxy tagged
+    sectool proxy tagged --tag auth
+
+  Output: Table with id, kind, tags, method, url, status and label
+
+---
+
 proxy rule <command> [options]
 
   Manage match and replace rules for request/response modification.
@@ -129,11 +319,17 @@ proxy rule <command> [options]
     list       List configured rules
     add        Add a new rule
     update     Modify an existing rule
+    enable     Re-enable a disabled rule
+    disable    Switch a rule off without deleting it
+    move       Change the order rules apply in
+    test       Dry-run a rule against a flow or sample message
     delete     Remove a rule
 
   Types:
     HTTP:      request_header (default), request_body, response_header, response_body
     WebSocket: ws:to-server, ws:to-client, ws:both
+    Session:   session_token (built-in proxy only): keep a refreshed cookie or
+               token in later requests
 
 proxy rule list [options]
 
@@ -158,6 +354,9 @@ proxy rule add [options] [match] [replace]
     sectool proxy rule add --type response_header "X-Frame-Options: DENY"
     sectool proxy rule add --regex "^User-Agent.*$" "User-Agent: Custom"
     sectool proxy rule add --type ws:both "old" "new"
+    sectool proxy rule add --type session_token --match SESSIONID
+    sectool proxy rule add --type session_token --regex \
+      --match 'name="csrf" value="([^"]+)"' --replace 'csrf=([^&]*)'
 
 proxy rule update <rule_id> [options] [match] [replace]
 
@@ -175,6 +374,40 @@ proxy rule update <rule_id> [options] [match] [replace]
     sectool proxy rule update abc123 --type request_header "X-New: value"
     sectool proxy rule update my-rule --type request_body "updated" "value"
 
+proxy rule enable <rule_id>
+proxy rule disable <rule_id>
+
+  Switch a rule on or off by ID or label, keeping its configuration.
+  Useful for comparing traffic with and without a rule.
+
+  Examples:
+    sectool proxy rule disable my-rule
+    sectool proxy rule enable my-rule
+
+proxy rule move <rule_id> <position>
+
+  Move a rule to a 1-based position within its HTTP or WebSocket set.
+  Rules apply in list order, position 1 first.
+
+  Examples:
+    sectool proxy rule move strip-auth 1
+
+proxy rule test [rule_id] [options] [match] [replace]
+
+  Apply an existing rule, or a proposed one, to a flow or sample message
+  and show the targeted headers or body before and after. Live traffic is
+  not touched.
+
+  Options:
+    --type <type>           Rule type for a proposed rule (default: request_header)
+    --regex                 Treat match as regex pattern
+    --flow <flow_id>        Test against a proxy flow
+    --sample <path>         Test against a raw message file (- for stdin)
+
+  Examples:
+    sectool proxy rule test my-rule --flow f7k2x
+    sectool proxy rule test --type response_body --regex --flow f7k2x '"role":"\w+"' '"role":"admin"'
+
 proxy rule delete <rule_id>
 
   Delete a rule by ID or label.
@@ -191,6 +424,7 @@ func parseSummary(args []string, mcpURL string) error {
 	fs.SetInterspersed(true)
 	var timeout time.Duration
 	var host, path, method, status, contains, containsBody, excludeHost, excludePath string
+	var format, columns string
 
 	fs.DurationVar(&timeout, "timeout", 30*time.Second, "client-side timeout")
 	fs.StringVar(&host, "host", "", "filter by host pattern (glob: *, ?)")
@@ -201,6 +435,8 @@ func parseSummary(args []string, mcpURL string) error {
 	fs.StringVar(&containsBody, "contains-body", "", "search in request/response body")
 	fs.StringVar(&excludeHost, "exclude-host", "", "exclude hosts matching pattern")
 	fs.StringVar(&excludePath, "exclude-path", "", "exclude paths matching pattern")
+	fs.StringVar(&format, "format", "markdown", "output format: markdown, plain, csv, tsv")
+	fs.StringVar(&columns, "columns", "", "comma-separated columns to show (host,path,method,status,count)")
 
 	fs.Usage = func() {
 		_, _ = fmt.Fprint(os.Stderr, `Usage: sectool proxy summary [options]
@@ -222,15 +458,88 @@ Options:
 		return err
 	}
 
-	return summary(mcpURL, timeout, host, path, method, status, contains, containsBody, excludeHost, excludePath)
+	outFormat, err := cliutil.ParseFormat(format)
+	if err != nil {
+		return err
+	}
+	cols, err := cliutil.SelectColumns(columns, summaryColumns)
+	if err != nil {
+		return err
+	}
+
+	return summary(mcpURL, timeout, host, path, method, status, contains, containsBody, excludeHost, excludePath, outFormat, cols)
+}
+
+func parseEndpoints(args []string, mcpURL string) error {
+	fs := pflag.NewFlagSet("proxy endpoints", pflag.ContinueOnError)
+	fs.SetInterspersed(true)
+	var timeout time.Duration
+	var host, path, method, status, contains, containsBody, excludeHost, excludePath string
+	var format, columns string
+
+	fs.DurationVar(&timeout, "timeout", 30*time.Second, "client-side timeout")
+	fs.StringVar(&host, "host", "", "filter by host pattern (glob: *, ?)")
+	fs.StringVar(&path, "path", "", "filter by path pattern (glob: *, ?)")
+	fs.StringVar(&method, "method", "", "filter by HTTP method (comma-separated)")
+	fs.StringVar(&status, "status", "", "filter by status code (e.g., 200,4XX)")
+	fs.StringVar(&contains, "contains", "", "search in URL and headers")
+	fs.StringVar(&containsBody, "contains-body", "", "search in request/response body")
+	fs.StringVar(&excludeHost, "exclude-host", "", "exclude hosts matching pattern")
+	fs.StringVar(&excludePath, "exclude-path", "", "exclude paths matching pattern")
+	fs.StringVar(&format, "format", "csv", "output format: csv, tsv, markdown, plain")
+	fs.StringVar(&columns, "columns", "", "comma-separated columns to show (host,path,method,count,statuses,min_size,max_size,first_seen,last_seen)")
+
+	fs.Usage = func() {
+		_, _ = fmt.Fprint(os.Stderr, `Usage: sectool proxy endpoints [options]
+
+Export proxy history as one row per endpoint (host/path/method), suited to
+spreadsheets and coverage tracking. Paths are normalized the same way as
+proxy summary (numeric and ID segments collapse to *).
+
+Columns:
+  count                 number of captured requests
+  statuses              status breakdown as code:count pairs (200:12,403:2)
+  min_size, max_size    response size range in bytes
+  first_seen, last_seen from response Date headers (empty when absent)
+
+Options:
+`)
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	outFormat, err := cliutil.ParseFormat(format)
+	if err != nil {
+		return err
+	}
+	cols, err := cliutil.SelectColumns(columns, endpointColumns)
+	if err != nil {
+		return err
+	}
+
+	return endpoints(mcpURL, timeout, mcpclient.ProxyPollOpts{
+		Host:         host,
+		Path:         path,
+		Method:       method,
+		Status:       status,
+		Contains:     contains,
+		ContainsBody: containsBody,
+		ExcludeHost:  excludeHost,
+		ExcludePath:  excludePath,
+	}, outFormat, cols)
 }
 
 func parseList(args []string, mcpURL string) error {
 	fs := pflag.NewFlagSet("proxy list", pflag.ContinueOnError)
 	fs.SetInterspersed(true)
-	var timeout time.Duration
-	var limit, offset int
-	var host, path, method, status, contains, containsBody, since, excludeHost, excludePath string
+	var timeout, interval time.Duration
+	var limit, offset, page int
+	var follow bool
+	var host, path, method, status, contains, containsBody, since, excludeHost, excludePath, cursor string
+	var format, columns string
 
 	fs.DurationVar(&timeout, "timeout", 30*time.Second, "client-side timeout")
 	fs.StringVar(&host, "host", "", "filter by host pattern (glob: *, ?)")
@@ -244,8 +553,14 @@ func parseList(args []string, mcpURL string) error {
 	fs.StringVar(&excludePath, "exclude-path", "", "exclude paths matching pattern")
 	fs.IntVar(&limit, "limit", 0, "maximum number of flows to return")
 	fs.IntVar(&offset, "offset", 0, "skip first N results for pagination")
+	fs.IntVar(&page, "page", 0, "page number (1-based, uses --limit as page size)")
+	fs.StringVar(&cursor, "cursor", "", "continue from a previous page's next cursor (replaces --offset)")
 	fs.IntVar(&limit, "count", 0, "alias for --limit")
 	_ = fs.MarkHidden("count")
+	fs.BoolVar(&follow, "follow", false, "keep polling and print new flows as they arrive")
+	fs.DurationVar(&interval, "interval", 2*time.Second, "poll interval for --follow")
+	fs.StringVar(&format, "format", "markdown", "output format: markdown, plain, csv, tsv")
+	fs.StringVar(&columns, "columns", "", "comma-separated columns to show (flow_id,method,host,path,status,size)")
 
 	fs.Usage = func() {
 		_, _ = fmt.Fprint(os.Stderr, `Usage: sectool proxy list [options]
@@ -253,6 +568,10 @@ func parseList(args []string, mcpURL string) error {
 List individual flows with flow_id for export or replay.
 At least one filter or --limit is REQUIRED. Use 'proxy summary' first.
 
+With --follow, prints matching flows then keeps polling for new ones until
+interrupted (Ctrl+C). Filters apply to every poll; --limit and --offset
+only apply to the initial listing.
+
 Filter examples:
   --host api.example.com          Exact host match
   --host "*.example.com"          Glob pattern (subdomains)
@@ -270,24 +589,64 @@ Options:
 		return err
 	}
 
-	// Require at least one filter or limit
+	// Require at least one filter or limit (--follow defaults to since=last)
 	hasFilters := host != "" || path != "" || method != "" || status != "" ||
 		contains != "" || containsBody != "" || since != "" ||
-		excludeHost != "" || excludePath != "" || limit > 0
-	if !hasFilters {
+		excludeHost != "" || excludePath != "" || limit > 0 || cursor != ""
+	if !hasFilters && !follow {
 		fs.Usage()
 		return errors.New("at least one filter or --limit is required; use 'sectool proxy summary' first to see available traffic")
 	}
+	offset, err := cliutil.PageOffset(page, limit, offset)
+	if err != nil {
+		return err
+	}
+	outFormat, err := cliutil.ParseFormat(format)
+	if err != nil {
+		return err
+	}
+	cols, err := cliutil.SelectColumns(columns, flowColumns)
+	if err != nil {
+		return err
+	}
 
-	return list(mcpURL, timeout, host, path, method, status, contains, containsBody, since, excludeHost, excludePath, limit, offset)
+	opts := mcpclient.ProxyPollOpts{
+		OutputMode:   "flows",
+		Host:         host,
+		Path:         path,
+		Method:       method,
+		Status:       status,
+		Contains:     contains,
+		ContainsBody: containsBody,
+		Since:        since,
+		ExcludeHost:  excludeHost,
+		ExcludePath:  excludePath,
+		Limit:        limit,
+		Offset:       offset,
+		Cursor:       cursor,
+	}
+	if follow {
+		if !hasFilters {
+			opts.Since = "last" // no filters, start from new traffic
+		}
+		if interval <= 0 {
+			return errors.New("--interval must be positive")
+		}
+		return followList(mcpURL, timeout, interval, opts, outFormat, cols)
+	}
+	return list(mcpURL, timeout, opts, outFormat, cols)
 }
 
 func parseExport(args []string, mcpURL string) error {
 	fs := pflag.NewFlagSet("proxy export", pflag.ContinueOnError)
 	fs.SetInterspersed(true)
 	var timeout time.Duration
+	var copyRaw bool
+	var output string
 
 	fs.DurationVar(&timeout, "timeout", 30*time.Second, "client-side timeout")
+	fs.BoolVar(&copyRaw, "copy", false, "also copy the raw request to the clipboard (OSC52 over SSH)")
+	fs.StringVarP(&output, "output", "o", "", "write only the decoded response body to this file (- for stdout)")
 
 	fs.Usage = func() {
 		_, _ = fmt.Fprint(os.Stderr, `Usage: sectool proxy export <flow_id> [options]
@@ -298,6 +657,7 @@ Export is useful for complex edits (raw body, binary data, etc).
 
 First, find the flow_id using 'sectool proxy list' with filters:
   sectool proxy list --host example.com --path /api/*
+Or use 'last' / 'last-N' to export the most recent proxy entries.
 
 The bundle_id matches the flow_id for simplicity. Re-exporting the same
 flow overwrites the bundle, restoring it to the original captured state.
@@ -313,6 +673,10 @@ After replay, response files are added:
 
 Edit body for body modifications; Content-Length is auto-updated on replay.
 
+With --output, no bundle is created: the flow's response body is written to
+the file with chunked and gzip/deflate/br encoding removed (binary-safe), for
+analysis with external tools.
+
 Options:
 `)
 		fs.PrintDefaults()
@@ -325,10 +689,221 @@ Options:
 		return errors.New("flow_id required (get from 'sectool proxy list' with filters)")
 	}
 
-	return export(mcpURL, timeout, fs.Args()[0])
+	if output != "" {
+		return exportBody(mcpURL, timeout, fs.Args()[0], output)
+	}
+	return export(mcpURL, timeout, fs.Args()[0], copyRaw)
+}
+
+func parseImport(args []string, mcpURL string) error {
+	fs := pflag.NewFlagSet("proxy import", pflag.ContinueOnError)
+	fs.SetInterspersed(true)
+	var timeout time.Duration
+	var harPath string
+
+	fs.DurationVar(&timeout, "timeout", 2*time.Minute, "client-side timeout")
+	fs.StringVar(&harPath, "har", "", "HAR file to import (required)")
+
+	fs.Usage = func() {
+		_, _ = fmt.Fprint(os.Stderr, `Usage: sectool proxy import --har <file> [options]
+
+Load a HAR capture into proxy history. Messages are rebuilt as HTTP/1.1 with
+decoded response bodies; non-HTTP entries (data:, ws:) are skipped.
+Requires the built-in proxy: Burp history can't be written.
+
+Options:
+`)
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	} else if harPath == "" {
+		fs.Usage()
+		return errors.New("--har is required")
+	}
+
+	return importHAR(mcpURL, timeout, harPath)
+}
+
+func parseFindings(args []string, mcpURL string) error {
+	fs := pflag.NewFlagSet("proxy findings", pflag.ContinueOnError)
+	fs.SetInterspersed(true)
+	var timeout time.Duration
+	var opts mcpclient.ProxyFindingsOpts
+	var format, columns string
+
+	fs.DurationVar(&timeout, "timeout", 2*time.Minute, "client-side timeout")
+	fs.StringArrayVar(&opts.Detectors, "detector", nil, "only this detector (repeatable)")
+	fs.StringVar(&opts.Severity, "severity", "", "minimum severity: info, low, medium, high, critical")
+	fs.StringVar(&opts.Host, "host", "", "only flows whose host matches (glob: *, ?)")
+	fs.BoolVar(&opts.NoRecord, "no-record", false, "do not add draft findings")
+	fs.BoolVar(&opts.Reveal, "reveal", false, "show full values instead of redacted ones")
+	fs.IntVar(&opts.Limit, "limit", 0, "maximum detections to show (default: 50)")
+	fs.StringVar(&format, "format", "markdown", "output format: markdown, plain, csv, tsv")
+	fs.StringVar(&columns, "columns", "", "comma-separated columns to show ("+strings.Join(secretColumns, ",")+")")
+
+	fs.Usage = func() {
+		_, _ = fmt.Fprint(os.Stderr, `Usage: sectool proxy findings [options]
+
+Scan proxy history for leaked secrets and PII, most severe first.
+
+Options:
+`)
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	outFormat, err := cliutil.ParseFormat(format)
+	if err != nil {
+		return err
+	}
+	cols, err := cliutil.SelectColumns(columns, secretColumns)
+	if err != nil {
+		return err
+	}
+
+	return findings(mcpURL, timeout, opts, outFormat, cols)
+}
+
+func parseHeaders(args []string, mcpURL string) error {
+	fs := pflag.NewFlagSet("proxy headers", pflag.ContinueOnError)
+	fs.SetInterspersed(true)
+	var timeout time.Duration
+	var opts mcpclient.AnalyzeHeadersOpts
+	var format, columns string
+
+	fs.DurationVar(&timeout, "timeout", 2*time.Minute, "client-side timeout")
+	fs.StringVar(&opts.Host, "host", "", "only hosts matching (glob: *, ?)")
+	fs.StringVar(&opts.Severity, "severity", "", "minimum severity: info, low, medium, high, critical")
+	fs.StringVar(&format, "format", "markdown", "output format: markdown, plain, csv, tsv")
+	fs.StringVar(&columns, "columns", "", "comma-separated columns to show ("+strings.Join(headerColumns, ",")+")")
+
+	fs.Usage = func() {
+		_, _ = fmt.Fprint(os.Stderr, `Usage: sectool proxy headers [options]
+
+Summarize security header, cookie and CORS issues per host.
+
+Options:
+`)
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	outFormat, err := cliutil.ParseFormat(format)
+	if err != nil {
+		return err
+	}
+	cols, err := cliutil.SelectColumns(columns, headerColumns)
+	if err != nil {
+		return err
+	}
+
+	return headers(mcpURL, timeout, opts, outFormat, cols)
+}
+
+func parseCSRF(args []string, mcpURL string) error {
+	fs := pflag.NewFlagSet("proxy csrf", pflag.ContinueOnError)
+	fs.SetInterspersed(true)
+	var timeout time.Duration
+	var opts mcpclient.CSRFAnalyzeOpts
+
+	fs.DurationVar(&timeout, "timeout", 2*time.Minute, "client-side timeout")
+	fs.StringVar(&opts.Host, "host", "", "only hosts matching (glob: *, ?)")
+
+	fs.Usage = func() {
+		_, _ = fmt.Fprint(os.Stderr, `Usage: sectool proxy csrf [options]
+
+Find anti-CSRF tokens and state-changing requests without one, per host.
+
+Options:
+`)
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	return csrf(mcpURL, timeout, opts)
+}
+
+func parseTag(args []string, mcpURL string) error {
+	fs := pflag.NewFlagSet("proxy tag", pflag.ContinueOnError)
+	fs.SetInterspersed(true)
+	var timeout time.Duration
+	var opts mcpclient.FlowTagOpts
+
+	fs.DurationVar(&timeout, "timeout", 30*time.Second, "client-side timeout")
+	fs.StringVar(&opts.FlowID, "flow", "", "flow ID (or last, last-N)")
+	fs.StringVar(&opts.ReplayID, "replay", "", "replay ID or label (or last, last-N)")
+	fs.StringArrayVar(&opts.Remove, "remove", nil, "remove a tag (repeatable)")
+
+	fs.Usage = func() {
+		_, _ = fmt.Fprint(os.Stderr, `Usage: sectool proxy tag (--flow <id> | --replay <id>) [tag...] [options]
+
+Tag a proxy flow or replay.
+
+Options:
+`)
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if (opts.FlowID == "") == (opts.ReplayID == "") {
+		return errors.New("exactly one of --flow or --replay is required")
+	}
+	opts.Tags = fs.Args()
+
+	return tag(mcpURL, timeout, opts)
+}
+
+func parseTagged(args []string, mcpURL string) error {
+	fs := pflag.NewFlagSet("proxy tagged", pflag.ContinueOnError)
+	fs.SetInterspersed(true)
+	var timeout time.Duration
+	var tagFilter, format, columns string
+
+	fs.DurationVar(&timeout, "timeout", time.Minute, "client-side timeout")
+	fs.StringVar(&tagFilter, "tag", "", "only items with this tag")
+	fs.StringVar(&format, "format", "markdown", "output format: markdown, plain, csv, tsv")
+	fs.StringVar(&columns, "columns", "", "comma-separated columns to show ("+strings.Join(taggedColumns, ",")+")")
+
+	fs.Usage = func() {
+		_, _ = fmt.Fprint(os.Stderr, `Usage: sectool proxy tagged [options]
+
+List tagged flows and replays.
+
+Options:
+`)
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	outFormat, err := cliutil.ParseFormat(format)
+	if err != nil {
+		return err
+	}
+	cols, err := cliutil.SelectColumns(columns, taggedColumns)
+	if err != nil {
+		return err
+	}
+
+	return tagged(mcpURL, timeout, tagFilter, outFormat, cols)
 }
 
-var ruleSubcommands = []string{"list", "add", "update", "delete", "help"}
+var ruleSubcommands = []string{"list", "add", "update", "enable", "disable", "move", "test", "delete", "help"}
 
 func parseRule(args []string, mcpURL string) error {
 	if len(args) < 1 {
@@ -343,6 +918,14 @@ func parseRule(args []string, mcpURL string) error {
 		return parseRuleAdd(args[1:], mcpURL)
 	case "update":
 		return parseRuleUpdate(args[1:], mcpURL)
+	case "enable":
+		return parseRuleToggle(args[1:], mcpURL, true)
+	case "disable":
+		return parseRuleToggle(args[1:], mcpURL, false)
+	case "move":
+		return parseRuleMove(args[1:], mcpURL)
+	case "test":
+		return parseRuleTest(args[1:], mcpURL)
 	case "delete":
 		return parseRuleDelete(args[1:], mcpURL)
 	case "help", "--help", "-h":
@@ -362,6 +945,10 @@ Commands:
   list       List configured rules
   add        Add a new rule
   update     Modify an existing rule
+  enable     Re-enable a disabled rule
+  disable    Switch a rule off without deleting it
+  move       Change the order rules apply in
+  test       Dry-run a rule against a flow or sample message
   delete     Remove a rule
 
 Use "sectool proxy rule <command> --help" for more information.
@@ -422,12 +1009,17 @@ For replacements: both match and replace are needed.
 Types:
   HTTP:      request_header (default), request_body, response_header, response_body
   WebSocket: ws:to-server, ws:to-client, ws:both
+  Session:   session_token (built-in proxy only). --match <cookie> tracks a cookie
+             from Set-Cookie into the Cookie header. With --regex, --match captures
+             the token from responses and --replace locates it in requests (group 1
+             of each).
 
 Examples:
   sectool proxy rule add "X-Custom: value"                              # Add request header
   sectool proxy rule add --type response_header "X-Frame-Options: DENY" # Add response header
   sectool proxy rule add --regex "^User-Agent.*$" "User-Agent: X"       # Replace User-Agent
   sectool proxy rule add --type ws:both "old" "new"                     # WebSocket replacement
+  sectool proxy rule add --type session_token --match SESSIONID         # Keep session cookie fresh
 
 Options:
 `)
@@ -482,6 +1074,7 @@ Searches both HTTP and WebSocket rules automatically.
 Types:
   HTTP:      request_header, request_body, response_header, response_body
   WebSocket: ws:to-server, ws:to-client, ws:both
+  Session:   session_token (built-in proxy only)
 
 Options:
 `)
@@ -522,6 +1115,129 @@ Options:
 	return ruleUpdate(mcpURL, timeout, ruleID, ruleType, match, replace, label, isRegexPtr)
 }
 
+func parseRuleToggle(args []string, mcpURL string, enabled bool) error {
+	action, verb := "disable", "Disable"
+	if enabled {
+		action, verb = "enable", "Enable"
+	}
+	fs := pflag.NewFlagSet("proxy rule "+action, pflag.ContinueOnError)
+	fs.SetInterspersed(true)
+	var timeout time.Duration
+
+	fs.DurationVar(&timeout, "timeout", 30*time.Second, "client-side timeout")
+
+	fs.Usage = func() {
+		_, _ = fmt.Fprintf(os.Stderr, `Usage: sectool proxy rule %s <rule_id> [options]
+
+%s a rule by ID or label, keeping its configuration.
+Searches both HTTP and WebSocket rules automatically.
+
+Options:
+`, action, verb)
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	} else if len(fs.Args()) < 1 {
+		fs.Usage()
+		return errors.New("rule_id required")
+	}
+
+	return ruleToggle(mcpURL, timeout, fs.Args()[0], enabled)
+}
+
+func parseRuleMove(args []string, mcpURL string) error {
+	fs := pflag.NewFlagSet("proxy rule move", pflag.ContinueOnError)
+	fs.SetInterspersed(true)
+	var timeout time.Duration
+
+	fs.DurationVar(&timeout, "timeout", 30*time.Second, "client-side timeout")
+
+	fs.Usage = func() {
+		_, _ = fmt.Fprint(os.Stderr, `Usage: sectool proxy rule move <rule_id> <position> [options]
+
+Move a rule to a 1-based position within its HTTP or WebSocket set.
+Rules apply in list order, position 1 first.
+
+Options:
+`)
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	} else if len(fs.Args()) < 2 {
+		fs.Usage()
+		return errors.New("rule_id and position required")
+	}
+
+	position, err := strconv.Atoi(fs.Args()[1])
+	if err != nil || position < 1 {
+		return fmt.Errorf("invalid position %q: must be 1 or greater", fs.Args()[1])
+	}
+	return ruleMove(mcpURL, timeout, fs.Args()[0], position)
+}
+
+func parseRuleTest(args []string, mcpURL string) error {
+	fs := pflag.NewFlagSet("proxy rule test", pflag.ContinueOnError)
+	fs.SetInterspersed(true)
+	var timeout time.Duration
+	var isRegex bool
+	var ruleType, flowID, samplePath string
+
+	fs.DurationVar(&timeout, "timeout", 30*time.Second, "client-side timeout")
+	fs.StringVar(&ruleType, "type", "request_header", "rule type for a proposed rule")
+	fs.BoolVar(&isRegex, "regex", false, "treat match as regex pattern")
+	fs.StringVar(&flowID, "flow", "", "proxy flow to test against")
+	fs.StringVar(&samplePath, "sample", "", "raw message file to test against (- for stdin)")
+
+	fs.Usage = func() {
+		_, _ = fmt.Fprint(os.Stderr, `Usage: sectool proxy rule test [rule_id] [options] [match] [replace]
+
+Dry-run an existing rule (by ID or label) or a proposed rule against a proxy
+flow or sample message. Shows the targeted headers or body before and after.
+
+Examples:
+  sectool proxy rule test my-rule --flow f7k2x
+  sectool proxy rule test --type response_body --flow f7k2x "user" "admin"
+  sectool proxy rule test --type request_body --sample req.txt --regex "pass=[^&]*" "pass=x"
+
+Options:
+`)
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	opts := mcpclient.RuleTestOpts{FlowID: flowID, IsRegex: isRegex}
+	switch posArgs := fs.Args(); len(posArgs) {
+	case 0:
+		fs.Usage()
+		return errors.New("rule_id or match/replace required")
+	case 1:
+		if fs.Changed("type") {
+			opts.Type, opts.Replace = ruleType, posArgs[0]
+		} else {
+			opts.RuleID = posArgs[0]
+		}
+	default:
+		opts.Type, opts.Match, opts.Replace = ruleType, posArgs[0], posArgs[1]
+	}
+
+	if samplePath != "" {
+		sample, err := readSample(samplePath)
+		if err != nil {
+			return err
+		}
+		opts.Sample = sample
+	}
+
+	return ruleTest(mcpURL, timeout, opts)
+}
+
 func parseRuleDelete(args []string, mcpURL string) error {
 	fs := pflag.NewFlagSet("proxy rule delete", pflag.ContinueOnError)
 	fs.SetInterspersed(true)
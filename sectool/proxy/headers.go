@@ -0,0 +1,62 @@
+package proxy
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-harden/llm-security-toolbox/sectool/cliutil"
+	"github.com/go-harden/llm-security-toolbox/sectool/mcpclient"
+)
+
+var headerColumns = []string{"host", "severity", "check", "detail", "count", "flow_ids"}
+
+func headers(mcpURL string, timeout time.Duration, opts mcpclient.AnalyzeHeadersOpts, format cliutil.Format, columns []string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	client, err := mcpclient.Connect(ctx, mcpURL)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = client.Close() }()
+
+	resp, err := client.AnalyzeHeaders(ctx, opts)
+	if err != nil {
+		return fmt.Errorf("proxy headers failed: %w", err)
+	}
+
+	defer cliutil.StartPager()()
+
+	var issues, responses int
+	for _, h := range resp.Hosts {
+		issues += len(h.Issues)
+		responses += h.Responses
+	}
+	t := cliutil.NewTable(os.Stdout, format, headerColumns, columns)
+	if issues == 0 && t.Markdown() {
+		fmt.Printf("No header issues found (%d responses from %d hosts).\n", responses, len(resp.Hosts))
+		return nil
+	}
+	t.Header()
+	for _, h := range resp.Hosts {
+		for _, issue := range h.Issues {
+			t.Row(h.Host, issue.Severity, issue.Check, issue.Detail, strconv.Itoa(issue.Count), strings.Join(issue.FlowIDs, " "))
+		}
+	}
+	t.Flush()
+	if !t.Markdown() {
+		return nil
+	}
+	fmt.Printf("\n*%d issues across %d hosts, %d responses analyzed*\n", issues, len(resp.Hosts), responses)
+	for _, h := range resp.Hosts {
+		if len(h.Issues) > 0 && len(h.Issues[0].FlowIDs) > 0 {
+			cliutil.Hintf("\nTo resend one: `sectool replay send --flow %s`\n", h.Issues[0].FlowIDs[0])
+			break
+		}
+	}
+	return nil
+}
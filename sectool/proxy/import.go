@@ -0,0 +1,45 @@
+package proxy
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/go-harden/llm-security-toolbox/sectool/cliutil"
+	"github.com/go-harden/llm-security-toolbox/sectool/mcpclient"
+)
+
+func importHAR(mcpURL string, timeout time.Duration, harPath string) error {
+	// Absolute so the service resolves it regardless of its working directory
+	path, err := filepath.Abs(harPath)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	client, err := mcpclient.Connect(ctx, mcpURL)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = client.Close() }()
+
+	resp, err := client.ProxyImport(ctx, mcpclient.ProxyImportOpts{Path: path})
+	if err != nil {
+		return fmt.Errorf("import failed: %w", err)
+	}
+
+	fmt.Printf("Imported %d flows from `%s`", resp.Imported, harPath)
+	if resp.Skipped > 0 {
+		fmt.Printf(" (%d non-HTTP entries skipped)", resp.Skipped)
+	}
+	fmt.Println()
+	if len(resp.Hosts) > 0 {
+		fmt.Printf("Hosts: %s\n", cliutil.EscapeMarkdown(strings.Join(resp.Hosts, ", ")))
+		cliutil.Hintf("\nTo list them: `sectool proxy list --host %s`\n", resp.Hosts[0])
+	}
+	return nil
+}
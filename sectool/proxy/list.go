@@ -3,6 +3,10 @@ package proxy
 import (
 	"context"
 	"fmt"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
 	"time"
 
 	"github.com/go-harden/llm-security-toolbox/sectool/cliutil"
@@ -10,7 +14,13 @@ import (
 	"github.com/go-harden/llm-security-toolbox/sectool/protocol"
 )
 
-func summary(mcpURL string, timeout time.Duration, host, path, method, status, contains, containsBody, excludeHost, excludePath string) error {
+var (
+	summaryColumns  = []string{"host", "path", "method", "status", "count"}
+	flowColumns     = []string{"flow_id", "method", "host", "path", "status", "size"}
+	endpointColumns = []string{"host", "path", "method", "count", "statuses", "min_size", "max_size", "first_seen", "last_seen"}
+)
+
+func summary(mcpURL string, timeout time.Duration, host, path, method, status, contains, containsBody, excludeHost, excludePath string, format cliutil.Format, columns []string) error {
 	ctx, cancel := context.WithTimeout(context.Background(), timeout)
 	defer cancel()
 
@@ -35,8 +45,11 @@ func summary(mcpURL string, timeout time.Duration, host, path, method, status, c
 		return fmt.Errorf("proxy summary failed: %w", err)
 	}
 
-	if len(resp.Aggregates) > 0 {
-		printAggregateTable(resp.Aggregates)
+	defer cliutil.StartPager()()
+
+	t := cliutil.NewTable(os.Stdout, format, summaryColumns, columns)
+	if len(resp.Aggregates) > 0 || !t.Markdown() {
+		printAggregateTable(t, resp.Aggregates)
 	} else {
 		fmt.Println("No matching entries found.")
 	}
@@ -44,7 +57,8 @@ func summary(mcpURL string, timeout time.Duration, host, path, method, status, c
 	return nil
 }
 
-func list(mcpURL string, timeout time.Duration, host, path, method, status, contains, containsBody, since, excludeHost, excludePath string, limit, offset int) error {
+// endpoints prints one row per (host, path, method) for reporting and coverage tracking.
+func endpoints(mcpURL string, timeout time.Duration, opts mcpclient.ProxyPollOpts, format cliutil.Format, columns []string) error {
 	ctx, cancel := context.WithTimeout(context.Background(), timeout)
 	defer cancel()
 
@@ -54,58 +68,164 @@ func list(mcpURL string, timeout time.Duration, host, path, method, status, cont
 	}
 	defer func() { _ = client.Close() }()
 
-	resp, err := client.ProxyPoll(ctx, mcpclient.ProxyPollOpts{
-		OutputMode:   "flows",
-		Host:         host,
-		Path:         path,
-		Method:       method,
-		Status:       status,
-		Contains:     contains,
-		ContainsBody: containsBody,
-		Since:        since,
-		ExcludeHost:  excludeHost,
-		ExcludePath:  excludePath,
-		Limit:        limit,
-		Offset:       offset,
-	})
+	opts.OutputMode = "endpoints"
+	resp, err := client.ProxyPoll(ctx, opts)
+	if err != nil {
+		return fmt.Errorf("proxy endpoints failed: %w", err)
+	}
+
+	defer cliutil.StartPager()()
+
+	t := cliutil.NewTable(os.Stdout, format, endpointColumns, columns)
+	if len(resp.Endpoints) == 0 && t.Markdown() {
+		fmt.Println("No matching entries found.")
+		return nil
+	}
+
+	t.Header()
+	for _, e := range resp.Endpoints {
+		t.Row(e.Host, e.Path, e.Method, strconv.Itoa(e.Count), e.Statuses,
+			strconv.Itoa(e.MinSize), strconv.Itoa(e.MaxSize), e.FirstSeen, e.LastSeen)
+	}
+	t.Flush()
+	if t.Markdown() {
+		fmt.Printf("\n*%d endpoints*\n", len(resp.Endpoints))
+	}
+
+	return nil
+}
+
+func list(mcpURL string, timeout time.Duration, opts mcpclient.ProxyPollOpts, format cliutil.Format, columns []string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	client, err := mcpclient.Connect(ctx, mcpURL)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = client.Close() }()
+
+	resp, err := client.ProxyPoll(ctx, opts)
 	if err != nil {
 		return fmt.Errorf("proxy list failed: %w", err)
 	}
 
-	if len(resp.Flows) > 0 {
-		printFlowTable(resp.Flows)
+	defer cliutil.StartPager()()
+
+	t := cliutil.NewTable(os.Stdout, format, flowColumns, columns)
+	if len(resp.Flows) > 0 || !t.Markdown() {
+		printFlowTable(t, resp.Flows)
 	} else {
 		fmt.Println("No matching entries found.")
 	}
+	if resp.TotalCount > len(resp.Flows) && t.Markdown() {
+		fmt.Printf("\n*%d of %d matching flows shown*\n", len(resp.Flows), resp.TotalCount)
+	}
+	if resp.NextCursor != "" && t.Markdown() {
+		cliutil.Hintf("More flows match. Next page: rerun with the same filters and `--cursor %s`\n", resp.NextCursor)
+	}
+
+	return nil
+}
+
+// followList prints matching flows, then polls for new flows until interrupted.
+func followList(mcpURL string, timeout, interval time.Duration, opts mcpclient.ProxyPollOpts, format cliutil.Format, columns []string) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	connectCtx, cancel := context.WithTimeout(ctx, timeout)
+	client, err := mcpclient.Connect(connectCtx, mcpURL)
+	cancel()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = client.Close() }()
+
+	t := cliutil.NewTable(os.Stdout, format, flowColumns, columns)
+	t.Header()
+	total, err := followFlows(ctx, client, timeout, interval, opts, t)
+	if err != nil {
+		return err
+	}
 
+	if t.Markdown() {
+		fmt.Printf("\n*%d flows*\n", total)
+	}
 	return nil
 }
 
-func printAggregateTable(agg []protocol.SummaryEntry) {
-	fmt.Println("| host | path | method | status | count |")
-	fmt.Println("|------|------|--------|--------|-------|")
+// flowPoller is the proxy_poll call followFlows makes.
+type flowPoller interface {
+	ProxyPoll(ctx context.Context, opts mcpclient.ProxyPollOpts) (*protocol.ProxyPollResponse, error)
+}
+
+// followFlows prints the matching flows, then those newer than the newest match
+// until ctx is done, and returns how many it printed.
+func followFlows(ctx context.Context, client flowPoller, timeout, interval time.Duration, opts mcpclient.ProxyPollOpts, t *cliutil.Table) (int, error) {
+	var total int
+	for {
+		pollCtx, cancel := context.WithTimeout(ctx, timeout)
+		resp, err := client.ProxyPoll(pollCtx, opts)
+		newest := resp
+		if err == nil && (opts.Limit > 0 || opts.Offset > 0 || opts.Cursor != "") {
+			// Only bound the initial listing. Its page may end before the newest match,
+			// so follow from the newest rather than print the rest as new flows.
+			opts.Limit, opts.Offset, opts.Cursor = 0, 0, ""
+			newest, err = client.ProxyPoll(pollCtx, opts)
+		}
+		cancel()
+		if err != nil {
+			if ctx.Err() != nil {
+				break
+			}
+			return total, fmt.Errorf("proxy list failed: %w", err)
+		}
+
+		printFlowRows(t, resp.Flows)
+		t.Flush()
+		total += len(resp.Flows)
+		if len(newest.Flows) > 0 {
+			opts.Since = newest.Flows[len(newest.Flows)-1].FlowID
+		}
+
+		select {
+		case <-ctx.Done():
+		case <-time.After(interval):
+			continue
+		}
+		break
+	}
+	return total, nil
+}
+
+func printAggregateTable(t *cliutil.Table, agg []protocol.SummaryEntry) {
+	t.Header()
 	for _, e := range agg {
-		fmt.Printf("| %s | %s | %s | %d | %d |\n",
-			cliutil.EscapeMarkdown(e.Host), cliutil.EscapeMarkdown(e.Path),
-			e.Method, e.Status, e.Count)
+		t.Row(e.Host, e.Path, e.Method, strconv.Itoa(e.Status), strconv.Itoa(e.Count))
+	}
+	t.Flush()
+	if t.Markdown() {
+		fmt.Printf("\n*%d unique request patterns*\n", len(agg))
 	}
-	fmt.Printf("\n*%d unique request patterns*\n", len(agg))
 }
 
-func printFlowTable(flows []protocol.FlowEntry) {
-	fmt.Println("| flow_id | method | host | path | status | size |")
-	fmt.Println("|---------|--------|------|------|--------|------|")
-	for _, f := range flows {
-		fmt.Printf("| %s | %s | %s | %s | %d | %d |\n",
-			f.FlowID, f.Method,
-			cliutil.EscapeMarkdown(f.Host),
-			cliutil.EscapeMarkdown(f.Path),
-			f.Status, f.ResponseLength)
+func printFlowTable(t *cliutil.Table, flows []protocol.FlowEntry) {
+	t.Header()
+	printFlowRows(t, flows)
+	t.Flush()
+	if !t.Markdown() {
+		return
 	}
 	fmt.Printf("\n*%d flows*\n", len(flows))
 
 	if len(flows) > 0 {
 		lastFlow := flows[len(flows)-1]
-		fmt.Printf("\nTo list flows after this: `sectool proxy list --since %s`\n", lastFlow.FlowID)
+		cliutil.Hintf("\nTo list flows after this: `sectool proxy list --since %s`\n", lastFlow.FlowID)
+	}
+}
+
+func printFlowRows(t *cliutil.Table, flows []protocol.FlowEntry) {
+	for _, f := range flows {
+		t.Row(f.FlowID, f.Method, f.Host, f.Path, strconv.Itoa(f.Status), strconv.Itoa(f.ResponseLength))
 	}
 }
@@ -0,0 +1,100 @@
+package proxy
+
+import (
+	"bytes"
+	"context"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/go-harden/llm-security-toolbox/sectool/cliutil"
+	"github.com/go-harden/llm-security-toolbox/sectool/mcpclient"
+	"github.com/go-harden/llm-security-toolbox/sectool/protocol"
+)
+
+// fakePoller serves proxy_poll list pages from flows, calling onPoll after each poll.
+type fakePoller struct {
+	flows  []protocol.FlowEntry
+	onPoll func(calls int)
+	calls  int
+}
+
+func (p *fakePoller) ProxyPoll(ctx context.Context, opts mcpclient.ProxyPollOpts) (*protocol.ProxyPollResponse, error) {
+	flows := p.flows
+	if opts.Since != "" {
+		i := slices.IndexFunc(flows, func(f protocol.FlowEntry) bool { return f.FlowID == opts.Since })
+		flows = flows[i+1:]
+	}
+	total := len(flows)
+	flows = flows[min(opts.Offset, len(flows)):]
+	if opts.Limit > 0 && len(flows) > opts.Limit {
+		flows = flows[:opts.Limit]
+	}
+	p.calls++
+	if p.onPoll != nil {
+		p.onPoll(p.calls)
+	}
+	return &protocol.ProxyPollResponse{Flows: slices.Clone(flows), TotalCount: total}, nil
+}
+
+// printedFlowIDs returns the flow_id column of TSV flow rows.
+func printedFlowIDs(out string) []string {
+	var ids []string
+	for _, line := range strings.Split(strings.TrimSpace(out), "\n") {
+		ids = append(ids, strings.Split(line, "\t")[0])
+	}
+	return ids
+}
+
+func TestFollowFlows(t *testing.T) {
+	t.Parallel()
+
+	flow := func(id string) protocol.FlowEntry {
+		return protocol.FlowEntry{FlowID: id, Method: "GET", Host: "example.com", Path: "/" + id, Status: 200}
+	}
+
+	t.Run("limit", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(t.Context())
+		defer cancel()
+		poller := &fakePoller{flows: []protocol.FlowEntry{flow("f1"), flow("f2"), flow("f3"), flow("f4")}}
+		poller.onPoll = func(calls int) {
+			switch calls {
+			case 2: // first page and the newest match fetched
+				poller.flows = append(poller.flows, flow("f5"))
+			case 3:
+				cancel()
+			}
+		}
+
+		var buf bytes.Buffer
+		table := cliutil.NewTable(&buf, cliutil.FormatTSV, flowColumns, flowColumns)
+		total, err := followFlows(ctx, poller, time.Second, time.Millisecond, mcpclient.ProxyPollOpts{Limit: 2}, table)
+		require.NoError(t, err)
+
+		// f3 and f4 matched before following started but were past the first page.
+		assert.Equal(t, []string{"f1", "f2", "f5"}, printedFlowIDs(buf.String()))
+		assert.Equal(t, 3, total)
+	})
+
+	t.Run("unbounded", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(t.Context())
+		defer cancel()
+		poller := &fakePoller{flows: []protocol.FlowEntry{flow("f1"), flow("f2")}}
+		poller.onPoll = func(calls int) {
+			if calls == 2 {
+				cancel()
+			}
+		}
+
+		var buf bytes.Buffer
+		table := cliutil.NewTable(&buf, cliutil.FormatTSV, flowColumns, flowColumns)
+		total, err := followFlows(ctx, poller, time.Second, time.Millisecond, mcpclient.ProxyPollOpts{}, table)
+		require.NoError(t, err)
+		assert.Equal(t, 2, total)
+		assert.Equal(t, []string{"f1", "f2"}, printedFlowIDs(buf.String()))
+	})
+}
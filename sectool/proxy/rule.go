@@ -3,7 +3,11 @@ package proxy
 import (
 	"context"
 	"fmt"
+	"io"
+	"os"
 	"slices"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/go-harden/llm-security-toolbox/sectool/cliutil"
@@ -31,6 +35,8 @@ func ruleList(mcpURL string, timeout time.Duration, websocket bool, limit int) e
 		return fmt.Errorf("rule list failed: %w", err)
 	}
 
+	defer cliutil.StartPager()()
+
 	if len(resp.Rules) == 0 {
 		ruleType := "HTTP"
 		if websocket {
@@ -48,33 +54,58 @@ func printRuleTable(rules []protocol.RuleEntry) {
 	hasLabels := slices.ContainsFunc(rules, func(r protocol.RuleEntry) bool {
 		return r.Label != ""
 	})
+	hasHits := slices.ContainsFunc(rules, func(r protocol.RuleEntry) bool {
+		return r.Hits != nil
+	})
+	hasDisabled := slices.ContainsFunc(rules, func(r protocol.RuleEntry) bool {
+		return !r.Enabled
+	})
 
+	header := []string{"#", "rule_id"}
 	if hasLabels {
-		fmt.Println("| rule_id | label | type | regex | match | replace |")
-		fmt.Println("|---------|-------|------|-------|-------|---------|")
-		for _, r := range rules {
-			regex := ""
-			if r.IsRegex {
-				regex = "yes"
+		header = append(header, "label")
+	}
+	header = append(header, "type", "regex", "match", "replace")
+	if hasDisabled {
+		header = append(header, "enabled")
+	}
+	if hasHits {
+		header = append(header, "hits", "last_hit")
+	}
+	fmt.Println("| " + strings.Join(header, " | ") + " |")
+	sep := make([]string, len(header))
+	for i, h := range header {
+		sep[i] = strings.Repeat("-", len(h)+2)
+	}
+	fmt.Println("|" + strings.Join(sep, "|") + "|")
+
+	for _, r := range rules {
+		regex := ""
+		if r.IsRegex {
+			regex = "yes"
+		}
+		row := []string{strconv.Itoa(r.Position), r.RuleID}
+		if hasLabels {
+			row = append(row, cliutil.EscapeMarkdown(r.Label))
+		}
+		row = append(row, r.Type, regex,
+			cliutil.EscapeMarkdown(truncate(r.Match, 30)),
+			cliutil.EscapeMarkdown(truncate(r.Replace, 30)))
+		if hasDisabled {
+			enabled := "no"
+			if r.Enabled {
+				enabled = "yes"
 			}
-			fmt.Printf("| %s | %s | %s | %s | %s | %s |\n",
-				r.RuleID, cliutil.EscapeMarkdown(r.Label), r.Type, regex,
-				cliutil.EscapeMarkdown(truncate(r.Match, 30)),
-				cliutil.EscapeMarkdown(truncate(r.Replace, 30)))
+			row = append(row, enabled)
 		}
-	} else {
-		fmt.Println("| rule_id | type | regex | match | replace |")
-		fmt.Println("|---------|------|-------|-------|---------|")
-		for _, r := range rules {
-			regex := ""
-			if r.IsRegex {
-				regex = "yes"
+		if hasHits {
+			var hits int64
+			if r.Hits != nil {
+				hits = *r.Hits
 			}
-			fmt.Printf("| %s | %s | %s | %s | %s |\n",
-				r.RuleID, r.Type, regex,
-				cliutil.EscapeMarkdown(truncate(r.Match, 30)),
-				cliutil.EscapeMarkdown(truncate(r.Replace, 30)))
+			row = append(row, strconv.FormatInt(hits, 10), r.LastHitAt)
 		}
+		fmt.Println("| " + strings.Join(row, " | ") + " |")
 	}
 	fmt.Printf("\n*%d rules*\n", len(rules))
 }
@@ -121,6 +152,15 @@ func ruleAdd(mcpURL string, timeout time.Duration, ruleType, match, replace, lab
 	if resp.Replace != "" {
 		fmt.Printf("Replace: `%s`\n", resp.Replace)
 	}
+	for _, w := range resp.Warnings {
+		fmt.Printf("Warning: %s\n", w)
+	}
+	if p := resp.Preview; p != nil {
+		fmt.Printf("Preview: matched %d of %d recent flows\n", p.Matched, p.Scanned)
+		for _, ex := range p.Examples {
+			fmt.Printf("  %s: `%s` -> `%s`\n", ex.FlowID, ex.Match, ex.Result)
+		}
+	}
 	return nil
 }
 
@@ -162,6 +202,92 @@ func ruleUpdate(mcpURL string, timeout time.Duration, ruleID, ruleType, match, r
 	return nil
 }
 
+func ruleToggle(mcpURL string, timeout time.Duration, ruleID string, enabled bool) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	client, err := mcpclient.Connect(ctx, mcpURL)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = client.Close() }()
+
+	resp, err := client.ProxyRuleToggle(ctx, ruleID, enabled)
+	if err != nil {
+		return fmt.Errorf("rule toggle failed: %w", err)
+	}
+
+	state := "Disabled"
+	if resp.Enabled {
+		state = "Enabled"
+	}
+	fmt.Printf("%s rule `%s`\n", state, resp.RuleID)
+	return nil
+}
+
+func ruleMove(mcpURL string, timeout time.Duration, ruleID string, position int) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	client, err := mcpclient.Connect(ctx, mcpURL)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = client.Close() }()
+
+	resp, err := client.ProxyRuleMove(ctx, ruleID, position)
+	if err != nil {
+		return fmt.Errorf("rule move failed: %w", err)
+	}
+
+	fmt.Printf("Moved rule `%s` to position %d\n", resp.RuleID, resp.Position)
+	return nil
+}
+
+func ruleTest(mcpURL string, timeout time.Duration, opts mcpclient.RuleTestOpts) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	client, err := mcpclient.Connect(ctx, mcpURL)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = client.Close() }()
+
+	resp, err := client.ProxyRuleTest(ctx, opts)
+	if err != nil {
+		return fmt.Errorf("rule test failed: %w", err)
+	}
+
+	fmt.Printf("Type: %s (%s)\n", resp.Type, resp.Section)
+	fmt.Printf("Matches: %d\n", resp.Matches)
+	if resp.Warning != "" {
+		fmt.Printf("Warning: %s\n", resp.Warning)
+	}
+	fmt.Printf("\n### Before\n\n```\n%s\n```\n", strings.TrimRight(resp.Before, "\r\n"))
+	if resp.Changed {
+		fmt.Printf("\n### After\n\n```\n%s\n```\n", strings.TrimRight(resp.After, "\r\n"))
+	} else {
+		fmt.Println("\nNo change.")
+	}
+	return nil
+}
+
+// readSample reads a sample message from a file path or stdin ("-").
+func readSample(path string) (string, error) {
+	var data []byte
+	var err error
+	if path == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read sample: %w", err)
+	}
+	return string(data), nil
+}
+
 func ruleDelete(mcpURL string, timeout time.Duration, ruleID string) error {
 	ctx, cancel := context.WithTimeout(context.Background(), timeout)
 	defer cancel()
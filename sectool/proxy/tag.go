@@ -0,0 +1,77 @@
+package proxy
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-harden/llm-security-toolbox/sectool/cliutil"
+	"github.com/go-harden/llm-security-toolbox/sectool/mcpclient"
+)
+
+var taggedColumns = []string{"id", "kind", "tags", "method", "url", "status", "label"}
+
+func tag(mcpURL string, timeout time.Duration, opts mcpclient.FlowTagOpts) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	client, err := mcpclient.Connect(ctx, mcpURL)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = client.Close() }()
+
+	resp, err := client.FlowTag(ctx, opts)
+	if err != nil {
+		return fmt.Errorf("proxy tag failed: %w", err)
+	}
+
+	if len(resp.Tags) == 0 {
+		fmt.Printf("`%s` has no tags\n", resp.ID)
+		return nil
+	}
+	fmt.Printf("`%s` tags: %s\n", resp.ID, strings.Join(resp.Tags, ", "))
+	return nil
+}
+
+func tagged(mcpURL string, timeout time.Duration, tagFilter string, format cliutil.Format, columns []string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	client, err := mcpclient.Connect(ctx, mcpURL)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = client.Close() }()
+
+	resp, err := client.FlowList(ctx, tagFilter)
+	if err != nil {
+		return fmt.Errorf("proxy tagged failed: %w", err)
+	}
+
+	defer cliutil.StartPager()()
+
+	t := cliutil.NewTable(os.Stdout, format, taggedColumns, columns)
+	if len(resp.Items) == 0 && t.Markdown() {
+		fmt.Println("No tagged flows or replays.")
+		return nil
+	}
+	t.Header()
+	for _, item := range resp.Items {
+		var status string
+		if item.Status > 0 {
+			status = strconv.Itoa(item.Status)
+		}
+		t.Row(item.ID, item.Kind, strings.Join(item.Tags, " "), item.Method, item.URL, status, item.Label)
+	}
+	t.Flush()
+	if !t.Markdown() {
+		return nil
+	}
+	fmt.Printf("\n*%d tagged*\n", len(resp.Items))
+	cliutil.Hintf("\nTo resend a flow: `sectool replay send --flow <id>`; to view a replay: `sectool replay get <id>`\n")
+	return nil
+}
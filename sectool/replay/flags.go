@@ -9,9 +9,57 @@ import (
 	"github.com/spf13/pflag"
 
 	"github.com/go-harden/llm-security-toolbox/sectool/cli"
+	"github.com/go-harden/llm-security-toolbox/sectool/cliutil"
+	"github.com/go-harden/llm-security-toolbox/sectool/mcpclient"
 )
 
-var replaySubcommands = []string{"send", "get", "create", "help"}
+var replaySubcommands = []string{"send", "get", "history", "rerun", "diff", "extract", "params", "ssrf", "create", "help"}
+
+// requestMods holds the request modification flags shared by send and rerun.
+type requestMods struct {
+	target          string
+	headers         []string
+	removeHeaders   []string
+	path, query     string
+	setQuery        []string
+	removeQuery     []string
+	setJSON         []string
+	removeJSON      []string
+	setForm         []string
+	removeForm      []string
+	setXML          []string
+	removeXML       []string
+	xmlDoctype      string
+	followRedirects bool
+	keepEncoding    bool
+	requestTimeout  time.Duration
+	force           bool
+	label           string
+	authProfile     string
+}
+
+func (r *requestMods) register(fs *pflag.FlagSet) {
+	fs.StringVar(&r.target, "target", "", "override target URL (scheme://host:port)")
+	fs.StringArrayVar(&r.headers, "set-header", nil, "add or replace header (repeatable)")
+	fs.StringArrayVar(&r.removeHeaders, "remove-header", nil, "remove header by name (repeatable)")
+	fs.StringVar(&r.path, "path", "", "replace URL path (e.g., /api/v2/users)")
+	fs.StringVar(&r.query, "query", "", "replace entire query string (e.g., id=1&debug=true)")
+	fs.StringArrayVar(&r.setQuery, "set-query", nil, "add or replace query param (repeatable, e.g., id=123)")
+	fs.StringArrayVar(&r.removeQuery, "remove-query", nil, "remove query param by name (repeatable)")
+	fs.StringArrayVar(&r.setJSON, "set-json", nil, "set JSON key (repeatable, e.g., user.role=admin)")
+	fs.StringArrayVar(&r.removeJSON, "remove-json", nil, "remove JSON key (repeatable)")
+	fs.StringArrayVar(&r.setForm, "set-form", nil, "set form or multipart field (repeatable, e.g., role=admin)")
+	fs.StringArrayVar(&r.removeForm, "remove-form", nil, "remove form or multipart field (repeatable)")
+	fs.StringArrayVar(&r.setXML, "set-xml", nil, "set XML node by XPath (repeatable, e.g., //user/role=admin)")
+	fs.StringArrayVar(&r.removeXML, "remove-xml", nil, "remove XML node by XPath (repeatable)")
+	fs.StringVar(&r.xmlDoctype, "xml-doctype", "", "DOCTYPE before the XML root: entity declarations or a full <!DOCTYPE ...>")
+	fs.BoolVar(&r.followRedirects, "follow-redirects", false, "follow 3xx redirects")
+	fs.BoolVar(&r.keepEncoding, "keep-encoding", false, "keep the response chunked/compressed as received")
+	fs.DurationVar(&r.requestTimeout, "request-timeout", 0, "HTTP request timeout (0 = no timeout)")
+	fs.BoolVar(&r.force, "force", false, "send request even if validation fails")
+	fs.StringVar(&r.label, "label", "", "label for referencing this replay later (e.g., replay get <label>)")
+	fs.StringVar(&r.authProfile, "auth-profile", "", "auth profile from config auth_profiles (NTLM/Negotiate/Digest handshake or SigV4 signing)")
+}
 
 func Parse(args []string, mcpURL string) error {
 	if len(args) < 1 {
@@ -24,6 +72,18 @@ func Parse(args []string, mcpURL string) error {
 		return parseSend(args[1:], mcpURL)
 	case "get":
 		return parseGet(args[1:], mcpURL)
+	case "history":
+		return parseHistory(args[1:], mcpURL)
+	case "rerun":
+		return parseRerun(args[1:], mcpURL)
+	case "diff":
+		return parseDiff(args[1:], mcpURL)
+	case "extract":
+		return parseExtract(args[1:], mcpURL)
+	case "params":
+		return parseParams(args[1:], mcpURL)
+	case "ssrf":
+		return parseSSRF(args[1:], mcpURL)
 	case "create":
 		return parseCreate(args[1:], mcpURL)
 	case "help", "--help", "-h":
@@ -46,7 +106,7 @@ replay send [options]
   Send a request through the HTTP backend.
 
   Input sources (exactly one required):
-    --flow <flow_id>      replay from proxy history
+    --flow <flow_id>      replay from proxy history (last, last-N for recent)
     --bundle <bundle_id>  replay from exported bundle (from proxy export)
     --file <path>         replay from raw HTTP file (- for stdin)
 
@@ -67,19 +127,46 @@ replay send [options]
     Nested paths: user.email, items[0].id, data.users[0].name
     Objects/arrays: --set-json 'meta={"k":"v"}' or 'ids=[1,2,3]'
 
+  Form body modifications (form-urlencoded or multipart/form-data):
+    --set-form "name=value"        set field (every occurrence), else append it
+    --remove-form "name"           remove field
+
+    Multipart parts keep their filename and Content-Type when set; the
+    boundary is regenerated if a value contains it.
+
+  XML body modifications (XPath):
+    --set-xml "xpath=value"        set element text or attribute, else create it
+    --remove-xml "xpath"           remove matched elements/attributes
+    --xml-doctype "<!ENTITY ...>"  set the DOCTYPE (XXE entity declarations)
+
   Note: Content-Length header is automatically updated when body changes.
 
   Other options:
     --follow-redirects             follow 3xx redirects
+    --keep-encoding                keep the response gzip/deflate/br/chunked (decoded by default)
     --request-timeout <dur>        HTTP timeout (0 = no timeout)
     --force                        send even if validation fails
     --body <path>                  body file (with --file)
+    --label <name>                 name this replay for 'replay get <name>'
+    --auth-profile <name>          authenticate with NTLM/Negotiate/Digest/SigV4 (config auth_profiles)
+    --session <name>               inject a login session token, re-login on 401/403
+    --as <identity>                replace credentials with an identity's (sectool identity)
+    --refresh-csrf                 fetch and inject a fresh CSRF token (sectool session csrf)
+    --confirm-out-of-scope         send outside the scope and target allowlist after you
+                                   confirm at the terminal (not available to MCP clients)
 
   Examples:
     sectool replay send --flow f7k2x
+    sectool replay send --flow last --label baseline
     sectool replay send --flow f7k2x --set-header "Authorization: Bearer tok"
     sectool replay send --flow f7k2x --path /api/v2/users --set-query "id=123"
     sectool replay send --flow f7k2x --set-json "user.role=admin"
+    sectool replay send --flow f7k2x --set-form "role=admin" --remove-form "csrf"
+    sectool replay send --flow f7k2x --xml-doctype '<!ENTITY xxe SYSTEM "file:///etc/passwd">' --set-xml "//name=&xxe;"
+    sectool replay send --flow f7k2x --auth-profile corp
+    sectool replay send --flow f7k2x --session admin
+    sectool replay send --flow f7k2x --as user_b
+    sectool replay send --flow f7k2x --refresh-csrf
     sectool replay send --bundle abc123
     sectool replay send --file request.http --body payload
 
@@ -89,15 +176,150 @@ replay send [options]
 
 replay get <replay_id>
 
-  Retrieve full details of a previous replay.
+  Retrieve full details of a previous replay. Accepts a replay_id, a label
+  set with 'replay send --label', or last / last-N for recent replays.
 
-  Example:
+  Examples:
     sectool replay get rpl_abc123           # get full response
+    sectool replay get last                 # most recent replay
+    sectool replay get baseline             # by label
+    sectool replay get last -o report.pdf   # save decoded body to a file
+    sectool replay get last --render auto   # pretty JSON, HTML text or hex dump
 
   Output: Markdown with status, headers, and complete response body
 
 ---
 
+replay history [options]
+
+  List previous replays, newest first.
+
+  Options:
+    --limit <n>             maximum number of replays to return
+    --format <fmt>          output format: markdown, plain, csv, tsv
+    --columns <list>        columns to show (replay_id,label,base,method,url,status,size,duration,created_at)
+
+  Example:
+    sectool replay history --limit 10
+
+  Output: Table with replay_id, method, url, status, size (Markdown by default)
+
+---
+
+replay rerun <replay_id> [options]
+
+  Re-send a previous replay's request. Accepts the same modification flags
+  as 'replay send' (--set-header, --set-json, --path, --target, ...).
+
+  Examples:
+    sectool replay rerun last
+    sectool replay rerun baseline --set-header "Cookie: session=other"
+
+  Output: Same as 'replay send'
+
+---
+
+replay diff <replay_a> <replay_b> [options]
+
+  Compare two replay responses: status, headers added/removed/changed, and
+  a unified body diff. Volatile headers, timestamps, UUIDs and CSRF tokens
+  are filtered unless --no-noise-filter is set.
+
+  Options:
+    --no-noise-filter            compare everything
+    --ignore-header <name>       skip a header (repeatable)
+    --ignore <regex>             ignore matches in header values and bodies (repeatable)
+
+  Examples:
+    sectool replay diff admin-view user-view
+    sectool replay diff last-1 last --ignore '"requestId":"[^"]*"'
+
+  Output: Markdown summary and unified body diff
+
+---
+
+replay extract <replay_id>... (--regex <re> | --json <path> | --css <sel>) [options]
+
+  Pull values out of replay responses without printing the full body.
+  Bodies are decoded (chunked, gzip, deflate) first.
+
+  Query (exactly one required):
+    --regex <re>            capture group 1 when present, else the whole match
+    --json <path>           dot notation with [index] and [*] (e.g., items[*].id)
+    --css <selector>        element text, or an attribute with --attr
+
+  Options:
+    --in <part>             regex input: body (default), headers, all
+    --attr <name>           attribute to return for --css matches
+    --limit <n>             maximum matches per replay (default: 50)
+
+  Examples:
+    sectool replay extract last --css 'input[name=csrf_token]' --attr value
+    sectool replay extract baseline --json 'data.users[*].id'
+    sectool replay extract last-1 last --regex 'sessionid=(\w+)' --in headers
+
+  Output: matches per replay, one per line
+
+---
+
+replay params (--flow <id> | --replay <id>) [options]
+
+  Discover hidden parameters (arjun style): names are added in chunks with
+  unique values, and chunks that change the response (status, body,
+  headers, reflected value) are bisected down to single names. The
+  unmodified request is sent twice first to measure normal variation.
+
+  Options:
+    --location <loc>        query, form or json (default: body for form/JSON
+                            requests, otherwise query)
+    -w, --wordlist <path>   names to try, one per line (default: built-in
+                            'params' list, see 'sectool payloads get params')
+    --name <name>           name to try (repeatable, with or instead of -w)
+    --chunk-size <n>        names per request (default: 30)
+    --max-requests <n>      request budget (default: 100)
+    --request-timeout <d>   per-request timeout
+    --rate <n>              requests per second to each host (default:
+                            rate_limit.host_requests_per_second config)
+
+  Examples:
+    sectool replay params --flow f7k2x
+    sectool replay params --flow last --location json -w params.txt
+
+  Output: candidate parameters with evidence and the replay_id showing it
+
+---
+
+replay ssrf (--flow <id> | --replay <id>) (--param <name> | --header <name>) [options]
+
+  Test a URL-taking parameter or header for SSRF and open redirect. The
+  unmodified request and an unresolvable control URL are sent first, then
+  each payload; responses are classified as confirmed (OAST interaction,
+  redirect to the payload host, metadata or file content), differs (from
+  the control), none or error.
+
+  Options:
+    --location <loc>        query or body (default: query if the param is
+                            there, else the form, multipart or JSON body)
+    --category <name>       loopback, internal, metadata, scheme, oast or
+                            redirect (repeatable, default: all)
+    --payload <value>       custom payload instead of the built-in ones
+                            (repeatable, {oast} becomes an OAST host)
+    --oast <oast_id>        OAST session to use (default: create one)
+    --wait <d>              how long to collect OAST interactions (default: 5s)
+    --request-timeout <d>   per-request timeout
+    --rate <n>              requests per second to each host (default:
+                            rate_limit.host_requests_per_second config)
+    --all                   list payloads with verdict none too
+
+  Examples:
+    sectool replay ssrf --flow f7k2x --param url
+    sectool replay ssrf --flow last --header Referer --category oast
+    sectool replay ssrf --flow last --param webhook --payload 'http://{oast}:8080/'
+
+  Output: payloads with verdict, evidence and the replay_id to inspect
+
+---
+
 replay create <url> [options]
 
   Create a request bundle from scratch (without capturing traffic first).
@@ -123,31 +345,21 @@ replay create <url> [options]
 func parseSend(args []string, mcpURL string) error {
 	fs := pflag.NewFlagSet("replay send", pflag.ContinueOnError)
 	fs.SetInterspersed(true)
-	var timeout, requestTimeout time.Duration
-	var flow, bundle, file, body, target string
-	var followRedirects, force bool
-	var headers, removeHeaders []string
-	var path, query string
-	var setQuery, removeQuery []string
-	var setJSON, removeJSON []string
+	var timeout time.Duration
+	var flow, bundle, file, body, session, as string
+	var refreshCSRF, confirmOutOfScope bool
+	var mods requestMods
 
 	fs.DurationVar(&timeout, "timeout", 30*time.Second, "client-side timeout")
 	fs.StringVar(&flow, "flow", "", "flow_id to replay from proxy history")
 	fs.StringVar(&bundle, "bundle", "", "bundle_id from proxy export")
 	fs.StringVar(&file, "file", "", "path to request.http file (- for stdin)")
 	fs.StringVar(&body, "body", "", "path to body file (use with --file)")
-	fs.StringVar(&target, "target", "", "override target URL (scheme://host:port)")
-	fs.StringArrayVar(&headers, "set-header", nil, "add or replace header (repeatable)")
-	fs.StringArrayVar(&removeHeaders, "remove-header", nil, "remove header by name (repeatable)")
-	fs.StringVar(&path, "path", "", "replace URL path (e.g., /api/v2/users)")
-	fs.StringVar(&query, "query", "", "replace entire query string (e.g., id=1&debug=true)")
-	fs.StringArrayVar(&setQuery, "set-query", nil, "add or replace query param (repeatable, e.g., id=123)")
-	fs.StringArrayVar(&removeQuery, "remove-query", nil, "remove query param by name (repeatable)")
-	fs.StringArrayVar(&setJSON, "set-json", nil, "set JSON key (repeatable, e.g., user.role=admin)")
-	fs.StringArrayVar(&removeJSON, "remove-json", nil, "remove JSON key (repeatable)")
-	fs.BoolVar(&followRedirects, "follow-redirects", false, "follow 3xx redirects")
-	fs.DurationVar(&requestTimeout, "request-timeout", 0, "HTTP request timeout (0 = no timeout)")
-	fs.BoolVar(&force, "force", false, "send request even if validation fails")
+	fs.StringVar(&session, "session", "", "login session from 'sectool session set' (with --flow)")
+	fs.StringVar(&as, "as", "", "identity from 'sectool identity set' (with --flow)")
+	fs.BoolVar(&refreshCSRF, "refresh-csrf", false, "fetch a fresh CSRF token with 'sectool session csrf' rule (with --flow)")
+	fs.BoolVar(&confirmOutOfScope, "confirm-out-of-scope", false, "send outside the target allowlist and project scope after confirming at the terminal")
+	mods.register(fs)
 
 	fs.Usage = func() {
 		_, _ = fmt.Fprint(os.Stderr, `Usage: sectool replay send [options]
@@ -155,7 +367,8 @@ func parseSend(args []string, mcpURL string) error {
 Send a request through the HTTP backend.
 
 Input sources (exactly one required):
-  --flow <flow_id>      Replay from proxy history (get flow_id from 'sectool proxy list')
+  --flow <flow_id>      Replay from proxy history (get flow_id from 'sectool proxy list',
+                        or use last / last-N for the most recent entries)
   --bundle <bundle_id>  Replay from exported bundle (create with 'sectool proxy export')
   --file <path>         Replay from raw HTTP file (- for stdin)
 
@@ -208,11 +421,80 @@ JSON body modifications:
 
   Note: Content-Length header is automatically updated when body changes.
 
+Form body modifications:
+  Modify application/x-www-form-urlencoded or multipart/form-data bodies
+  inline. Values are sent as text; an empty body without Content-Type is
+  treated as form-urlencoded.
+
+    --set-form "name=value"        Replace every occurrence of the field in
+                                   place, or append it
+    --set-form "name"              Set the field to an empty value
+    --remove-form "name"           Remove every occurrence of the field
+
+  Multipart parts keep their headers (filename, Content-Type) when set, so
+  --set-form replaces an uploaded file's content. If a new value contains
+  the boundary, a fresh boundary is used and Content-Type updated.
+
+  Modification order: remove -> set
+
+XML body modifications:
+  Modify XML bodies (SOAP, XML APIs) by XPath. Namespace prefixes declared in
+  the document can be used, e.g. //soap:Body/u:GetUser.
+
+    --set-xml "xpath=value"        Set the text of matched elements or the
+                                   value of matched attributes (//user/@id)
+    --set-xml "xpath"              Set to an empty value
+    --remove-xml "xpath"           Remove matched elements or attributes
+    --xml-doctype "decls"          Put a DOCTYPE before the root element,
+                                   replacing any existing one
+
+  The first = outside brackets and quotes ends the XPath, so predicates like
+  //item[@id='2']/qty=5 work. When an XPath matches nothing and its last step
+  is a plain element or @attribute name, it is created under the parent
+  matches. Values are escaped except for entity references (&xxe;).
+
+  --xml-doctype takes internal subset declarations, which are wrapped in a
+  DOCTYPE named after the root element, or a full <!DOCTYPE ...>:
+    --xml-doctype '<!ENTITY xxe SYSTEM "file:///etc/passwd">' --set-xml "//name=&xxe;"
+    --xml-doctype '<!DOCTYPE r SYSTEM "http://oast.example/x.dtd">'
+
+  Modification order: remove -> set -> doctype. The XML declaration and
+  DOCTYPE are kept as sent; the rest of the document is re-serialized.
+
+Authentication:
+  --auth-profile <name> answers NTLM, Negotiate or Digest challenges, or
+  AWS SigV4-signs the request, using credentials from the auth_profiles config
+  section. SigV4 signs after all modifications are applied. Digest and SigV4
+  requests go through the HTTP backend. NTLM and
+  Negotiate need one persistent connection, so those requests are sent
+  directly rather than through the HTTP backend and do not appear in proxy
+  history.
+
+  --session <name> sets the token of a login session defined with
+  'sectool session set', after all modifications. A 401/403 response makes the
+  session log in again, and the request is resent once with the fresh token.
+  Only --flow sends support sessions.
+
+  --as <identity> replaces the request's credentials with the headers and
+  cookies of an identity defined with 'sectool identity set', after all
+  modifications. Only --flow sends support identities.
+
+  --refresh-csrf requests the token page of the target host's CSRF rule
+  (defined with 'sectool session csrf') with the request's cookies, and sets
+  the fresh token on the request after all modifications. Cookies the page sets
+  are merged into the request. Only --flow sends support it.
+
 Validation:
   Requests are validated before sending. If validation fails, the request
   is NOT sent and errors are displayed. Use --force to send anyway (useful
   for testing HTTP parser behavior with intentionally malformed requests).
 
+Scope:
+  Requests to targets outside the project scope or the target_allowlist
+  config are refused. --confirm-out-of-scope asks you to type "yes" at the
+  terminal and then sends anyway, redirects included. It needs an
+  interactive terminal, and MCP clients have no equivalent.
+
 Options:
 `)
 		fs.PrintDefaults()
@@ -237,26 +519,50 @@ Options:
 		return errors.New("one of --flow, --bundle, or --file is required")
 	} else if sources > 1 {
 		return errors.New("only one of --flow, --bundle, or --file can be specified")
+	} else if session != "" && flow == "" {
+		return errors.New("--session requires --flow")
+	} else if as != "" && flow == "" {
+		return errors.New("--as requires --flow")
+	} else if refreshCSRF && flow == "" {
+		return errors.New("--refresh-csrf requires --flow")
+	}
+	if confirmOutOfScope {
+		if err := mcpclient.ConfirmOutOfScope(); err != nil {
+			return err
+		}
 	}
 
-	return send(mcpURL, timeout, flow, bundle, file, body, target, headers, removeHeaders,
-		path, query, setQuery, removeQuery,
-		setJSON, removeJSON,
-		followRedirects, requestTimeout, force)
+	return send(mcpURL, timeout, flow, bundle, file, body, mods.target, mods.headers, mods.removeHeaders,
+		mods.path, mods.query, mods.setQuery, mods.removeQuery,
+		mods.setJSON, mods.removeJSON,
+		mods.setForm, mods.removeForm,
+		mods.setXML, mods.removeXML, mods.xmlDoctype,
+		mods.followRedirects, mods.keepEncoding, mods.requestTimeout, mods.force, mods.label, mods.authProfile, session, as, refreshCSRF)
 }
 
 func parseGet(args []string, mcpURL string) error {
 	fs := pflag.NewFlagSet("replay get", pflag.ContinueOnError)
 	fs.SetInterspersed(true)
 	var timeout time.Duration
+	var output, render string
 
 	fs.DurationVar(&timeout, "timeout", 30*time.Second, "client-side timeout")
+	fs.StringVarP(&output, "output", "o", "", "write the decoded response body to this file (- for stdout)")
+	fs.StringVar(&render, "render", "", "show the body decoded and formatted: auto, json, text, hex, raw")
 
 	fs.Usage = func() {
 		_, _ = fmt.Fprint(os.Stderr, `Usage: sectool replay get <replay_id> [options]
 
 Get details of a previous replay.
 
+With --output, the response body is written to a file instead of printed:
+chunked transfer coding and gzip/deflate/br content encoding are removed and
+binary content is written byte-for-byte.
+
+With --render, the body is shown decoded and formatted for reading: json
+pretty-prints, text extracts the visible text of HTML, hex dumps binary
+content, and auto picks one from the Content-Type.
+
 Options:
 `)
 		fs.PrintDefaults()
@@ -269,7 +575,90 @@ Options:
 		return errors.New("replay_id required (get from 'sectool replay send' output)")
 	}
 
-	return get(mcpURL, timeout, fs.Args()[0])
+	if output != "" && render != "" {
+		return errors.New("--output and --render are mutually exclusive")
+	}
+
+	return get(mcpURL, timeout, fs.Args()[0], output, render)
+}
+
+func parseHistory(args []string, mcpURL string) error {
+	fs := pflag.NewFlagSet("replay history", pflag.ContinueOnError)
+	fs.SetInterspersed(true)
+	var timeout time.Duration
+	var limit int
+	var format, columns string
+
+	fs.DurationVar(&timeout, "timeout", 30*time.Second, "client-side timeout")
+	fs.IntVar(&limit, "limit", 0, "maximum number of replays to return")
+	fs.StringVar(&format, "format", "markdown", "output format: markdown, plain, csv, tsv")
+	fs.StringVar(&columns, "columns", "", "comma-separated columns to show (replay_id,label,base,method,url,status,size,duration,created_at)")
+
+	fs.Usage = func() {
+		_, _ = fmt.Fprint(os.Stderr, `Usage: sectool replay history [options]
+
+List previous replays (from replay send), newest first.
+Replay results are ephemeral and cleared on service restart.
+
+Options:
+`)
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	outFormat, err := cliutil.ParseFormat(format)
+	if err != nil {
+		return err
+	}
+	cols, err := cliutil.SelectColumns(columns, historyColumns)
+	if err != nil {
+		return err
+	}
+
+	return history(mcpURL, timeout, limit, outFormat, cols)
+}
+
+func parseRerun(args []string, mcpURL string) error {
+	fs := pflag.NewFlagSet("replay rerun", pflag.ContinueOnError)
+	fs.SetInterspersed(true)
+	var timeout time.Duration
+	var body string
+	var mods requestMods
+
+	fs.DurationVar(&timeout, "timeout", 30*time.Second, "client-side timeout")
+	fs.StringVar(&body, "body", "", "path to replacement body file (- for stdin)")
+	mods.register(fs)
+
+	fs.Usage = func() {
+		_, _ = fmt.Fprint(os.Stderr, `Usage: sectool replay rerun <replay_id> [options]
+
+Send a previous replay's request again, optionally with modifications.
+The replay may be referenced by replay_id, label, or last / last-N.
+The original destination is reused unless --target is given.
+
+Accepts the same modification flags as 'replay send'.
+
+Examples:
+  sectool replay rerun last
+  sectool replay rerun rpl_abc123 --set-header "Authorization: Bearer other"
+  sectool replay rerun baseline --set-json "user.role=admin" --label escalated
+
+Options:
+`)
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	} else if len(fs.Args()) < 1 {
+		fs.Usage()
+		return errors.New("replay_id required (get from 'sectool replay history')")
+	}
+
+	return rerun(mcpURL, timeout, fs.Args()[0], body, mods)
 }
 
 func parseCreate(args []string, mcpURL string) error {
@@ -316,3 +705,190 @@ Output: Bundle path that can be used with 'sectool replay send --bundle'
 
 	return create(mcpURL, timeout, fs.Args()[0], method, headers, bodyPath)
 }
+
+func parseExtract(args []string, mcpURL string) error {
+	fs := pflag.NewFlagSet("replay extract", pflag.ContinueOnError)
+	fs.SetInterspersed(true)
+	var timeout time.Duration
+	var opts mcpclient.ResponseExtractOpts
+
+	fs.DurationVar(&timeout, "timeout", 30*time.Second, "client-side timeout")
+	fs.StringVar(&opts.Regex, "regex", "", "regex to match (capture group 1 when present)")
+	fs.StringVar(&opts.JSONPath, "json", "", "JSON path to read (e.g., data.items[*].id)")
+	fs.StringVar(&opts.CSS, "css", "", "CSS selector over the HTML body")
+	fs.StringVar(&opts.In, "in", "", "regex input: body, headers or all (default: body)")
+	fs.StringVar(&opts.Attr, "attr", "", "attribute to return for --css matches")
+	fs.IntVar(&opts.Limit, "limit", 0, "maximum matches per replay (default: 50)")
+
+	fs.Usage = func() {
+		_, _ = fmt.Fprint(os.Stderr, `Usage: sectool replay extract <replay_id>... (--regex <re> | --json <path> | --css <sel>) [options]
+
+Extract values from replay responses. Each argument accepts a replay_id,
+a label, or last / last-N.
+
+Options:
+`)
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	} else if fs.NArg() == 0 {
+		fs.Usage()
+		return errors.New("at least one replay ID required")
+	}
+	var queries int
+	for _, s := range []string{opts.Regex, opts.JSONPath, opts.CSS} {
+		if s != "" {
+			queries++
+		}
+	}
+	if queries != 1 {
+		fs.Usage()
+		return errors.New("exactly one of --regex, --json or --css is required")
+	}
+	opts.ReplayIDs = fs.Args()
+
+	return extract(mcpURL, timeout, opts)
+}
+
+func parseParams(args []string, mcpURL string) error {
+	fs := pflag.NewFlagSet("replay params", pflag.ContinueOnError)
+	fs.SetInterspersed(true)
+	var timeout, requestTimeout time.Duration
+	var rate float64
+	var wordlist string
+	var opts mcpclient.ParamDiscoverOpts
+
+	fs.DurationVar(&timeout, "timeout", 10*time.Minute, "client-side timeout")
+	fs.StringVar(&opts.FlowID, "flow", "", "flow_id to test")
+	fs.StringVar(&opts.ReplayID, "replay", "", "replay_id or label to test")
+	fs.StringVar(&opts.Location, "location", "", "query, form or json (default: from the request)")
+	fs.StringVarP(&wordlist, "wordlist", "w", "", "names to try, one per line (- for stdin)")
+	fs.StringArrayVar(&opts.Names, "name", nil, "name to try (repeatable)")
+	fs.IntVar(&opts.ChunkSize, "chunk-size", 0, "names per request (default: 30)")
+	fs.IntVar(&opts.MaxRequests, "max-requests", 0, "request budget (default: 100)")
+	fs.DurationVar(&requestTimeout, "request-timeout", 0, "per-request timeout")
+	fs.Float64Var(&rate, "rate", 0, "requests per second to each host (default: from config)")
+
+	fs.Usage = func() {
+		_, _ = fmt.Fprint(os.Stderr, `Usage: sectool replay params (--flow <id> | --replay <id>) [options]
+
+Discover hidden query, form or JSON parameters by response differences.
+
+Options:
+`)
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if (opts.FlowID == "") == (opts.ReplayID == "") {
+		fs.Usage()
+		return errors.New("exactly one of --flow or --replay is required")
+	}
+	if wordlist != "" {
+		names, err := cliutil.ReadWordlist(wordlist)
+		if err != nil {
+			return err
+		}
+		opts.Names = append(opts.Names, names...)
+	}
+	if requestTimeout > 0 {
+		opts.Timeout = requestTimeout.String()
+	}
+	if fs.Changed("rate") {
+		opts.Rate = &rate
+	}
+
+	return params(mcpURL, timeout, opts)
+}
+
+func parseSSRF(args []string, mcpURL string) error {
+	fs := pflag.NewFlagSet("replay ssrf", pflag.ContinueOnError)
+	fs.SetInterspersed(true)
+	var timeout, requestTimeout, wait time.Duration
+	var rate float64
+	var all bool
+	var opts mcpclient.SSRFTestOpts
+
+	fs.DurationVar(&timeout, "timeout", 5*time.Minute, "client-side timeout")
+	fs.StringVar(&opts.FlowID, "flow", "", "flow_id to test")
+	fs.StringVar(&opts.ReplayID, "replay", "", "replay_id or label to test")
+	fs.StringVar(&opts.Param, "param", "", "parameter to set to each payload")
+	fs.StringVar(&opts.Header, "header", "", "header to set to each payload")
+	fs.StringVar(&opts.Location, "location", "", "query or body (default: from the request)")
+	fs.StringArrayVar(&opts.Categories, "category", nil, "payload category (repeatable)")
+	fs.StringArrayVar(&opts.Payloads, "payload", nil, "custom payload (repeatable)")
+	fs.StringVar(&opts.OastID, "oast", "", "OAST session oast_id (default: create one)")
+	fs.DurationVar(&wait, "wait", 0, "how long to collect OAST interactions (default: 5s)")
+	fs.DurationVar(&requestTimeout, "request-timeout", 0, "per-request timeout")
+	fs.Float64Var(&rate, "rate", 0, "requests per second to each host (default: from config)")
+	fs.BoolVar(&all, "all", false, "list payloads with verdict none too")
+
+	fs.Usage = func() {
+		_, _ = fmt.Fprint(os.Stderr, `Usage: sectool replay ssrf (--flow <id> | --replay <id>) (--param <name> | --header <name>) [options]
+
+Send SSRF and open redirect payloads in a parameter or header and classify the responses.
+
+Options:
+`)
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if (opts.FlowID == "") == (opts.ReplayID == "") {
+		fs.Usage()
+		return errors.New("exactly one of --flow or --replay is required")
+	} else if (opts.Param == "") == (opts.Header == "") {
+		fs.Usage()
+		return errors.New("exactly one of --param or --header is required")
+	}
+	if wait > 0 {
+		opts.Wait = wait.String()
+	}
+	if requestTimeout > 0 {
+		opts.Timeout = requestTimeout.String()
+	}
+	if fs.Changed("rate") {
+		opts.Rate = &rate
+	}
+
+	return ssrf(mcpURL, timeout, opts, all)
+}
+
+func parseDiff(args []string, mcpURL string) error {
+	fs := pflag.NewFlagSet("replay diff", pflag.ContinueOnError)
+	fs.SetInterspersed(true)
+	var timeout time.Duration
+	var noNoise bool
+	var ignoreHeaders, ignorePatterns []string
+
+	fs.DurationVar(&timeout, "timeout", 30*time.Second, "client-side timeout")
+	fs.BoolVar(&noNoise, "no-noise-filter", false, "do not filter volatile headers, timestamps, UUIDs and CSRF tokens")
+	fs.StringArrayVar(&ignoreHeaders, "ignore-header", nil, "header name to skip (repeatable)")
+	fs.StringArrayVar(&ignorePatterns, "ignore", nil, "regex whose matches are ignored in header values and bodies (repeatable)")
+
+	fs.Usage = func() {
+		_, _ = fmt.Fprint(os.Stderr, `Usage: sectool replay diff <replay_a> <replay_b> [options]
+
+Compare the responses of two replays. Each argument accepts a replay_id,
+a label, or last / last-N.
+
+Options:
+`)
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	} else if len(fs.Args()) != 2 {
+		fs.Usage()
+		return errors.New("two replay IDs required")
+	}
+
+	return diff(mcpURL, timeout, fs.Args()[0], fs.Args()[1], !noNoise, ignoreHeaders, ignorePatterns)
+}
@@ -12,19 +12,30 @@ import (
 	"net/textproto"
 	"net/url"
 	"os"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/go-harden/llm-security-toolbox/sectool/bundle"
+	"github.com/go-harden/llm-security-toolbox/sectool/cliutil"
 	"github.com/go-harden/llm-security-toolbox/sectool/mcpclient"
 	"github.com/go-harden/llm-security-toolbox/sectool/protocol"
 	"github.com/go-harden/llm-security-toolbox/sectool/service"
 )
 
+var historyColumns = []string{"replay_id", "label", "base", "method", "url", "status", "size", "duration", "created_at"}
+
+var paramColumns = []string{"name", "evidence", "status", "size", "replay_id"}
+
+var ssrfColumns = []string{"payload", "category", "verdict", "evidence", "status", "size", "replay_id"}
+
 func send(mcpURL string, timeout time.Duration, flow, bundleArg, file, body, target string, headers, removeHeaders []string,
 	path, query string, setQuery, removeQuery []string,
 	setJSON, removeJSON []string,
-	followRedirects bool, requestTimeout time.Duration, force bool) error {
+	setForm, removeForm []string,
+	setXML, removeXML []string, xmlDoctype string,
+	followRedirects, keepEncoding bool, requestTimeout time.Duration, force bool, label, authProfile, session, as string, refreshCSRF bool) error {
 	if flow == "" && bundleArg == "" && file == "" {
 		return errors.New("one of --flow, --bundle, or --file is required")
 	}
@@ -34,46 +45,20 @@ func send(mcpURL string, timeout time.Duration, flow, bundleArg, file, body, tar
 	}
 
 	// read in customized body content if specified
-	var hasBodyOverride bool
-	var bodyOverride []byte
-	if body != "" {
-		var err error
-		if body == "-" {
-			bodyOverride, err = io.ReadAll(os.Stdin)
-			if err != nil {
-				return fmt.Errorf("failed to read body from stdin: %w", err)
-			}
-		} else {
-			bodyOverride, err = os.ReadFile(body)
-			if err != nil {
-				return fmt.Errorf("failed to read body file: %w", err)
-			}
-		}
-		hasBodyOverride = true
-	}
-
-	// Build setJSON map
-	var setJSONMap map[string]interface{}
-	if len(setJSON) > 0 {
-		setJSONMap = make(map[string]interface{})
-		for _, kv := range setJSON {
-			if idx := strings.Index(kv, "="); idx > 0 {
-				key := kv[:idx]
-				value := kv[idx+1:]
-				setJSONMap[key] = value
-			} else {
-				// key without = means set to null
-				setJSONMap[kv] = nil
-			}
-		}
+	bodyOverride, hasBodyOverride, err := readBodyOverride(body)
+	if err != nil {
+		return err
 	}
+	setJSONMap := buildSetJSONMap(setJSON)
+	setFormMap := buildSetFormMap(setForm)
+	setXMLMap := buildSetXMLMap(setXML)
 
 	if bundleArg != "" {
-		return sendFromBundle(mcpURL, timeout, bundleArg, target, headers, removeHeaders, path, query, setQuery, removeQuery, setJSONMap, removeJSON, bodyOverride, hasBodyOverride, followRedirects, requestTimeout)
+		return sendFromBundle(mcpURL, timeout, bundleArg, target, headers, removeHeaders, path, query, setQuery, removeQuery, setJSONMap, removeJSON, setFormMap, removeForm, setXMLMap, removeXML, xmlDoctype, bodyOverride, hasBodyOverride, followRedirects, keepEncoding, requestTimeout, label, authProfile)
 	}
 
 	if file != "" {
-		return sendFromFile(mcpURL, timeout, file, target, headers, removeHeaders, path, query, setQuery, removeQuery, setJSONMap, removeJSON, bodyOverride, hasBodyOverride, followRedirects, requestTimeout)
+		return sendFromFile(mcpURL, timeout, file, target, headers, removeHeaders, path, query, setQuery, removeQuery, setJSONMap, removeJSON, setFormMap, removeForm, setXMLMap, removeXML, xmlDoctype, bodyOverride, hasBodyOverride, followRedirects, keepEncoding, requestTimeout, label, authProfile)
 	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), timeout)
@@ -107,32 +92,406 @@ func send(mcpURL string, timeout time.Duration, flow, bundleArg, file, body, tar
 		RemoveQuery:     removeQuery,
 		SetJSON:         setJSONMap,
 		RemoveJSON:      removeJSON,
+		SetForm:         setFormMap,
+		RemoveForm:      removeForm,
+		SetXML:          setXMLMap,
+		RemoveXML:       removeXML,
+		XMLDoctype:      xmlDoctype,
 		FollowRedirects: followRedirects,
+		KeepEncoding:    keepEncoding,
 		Timeout:         timeoutStr,
 		Force:           force,
+		Label:           label,
+		AuthProfile:     authProfile,
+		Session:         session,
+		ReplayAs:        as,
+		RefreshCSRF:     refreshCSRF,
 	})
 	if err != nil {
 		return fmt.Errorf("replay send failed: %w", err)
 	}
 
-	fmt.Printf("## Replay Result\n\n")
-	fmt.Printf("Replay ID: `%s`\n", resp.ReplayID)
-	fmt.Printf("Duration: %s\n\n", resp.Duration)
+	printReplayResult(resp)
+	return nil
+}
 
-	fmt.Printf("### Response\n\n")
-	fmt.Printf("Status: %d %s\n", resp.Status, resp.StatusLine)
-	fmt.Printf("Size: %d bytes\n\n", resp.RespSize)
-	if resp.RespHeaders != "" {
-		fmt.Printf("Headers:\n```\n%s```\n\n", resp.RespHeaders)
+// readBodyOverride reads a replacement body from a file path or stdin ("-").
+func readBodyOverride(body string) ([]byte, bool, error) {
+	if body == "" {
+		return nil, false, nil
+	} else if body == "-" {
+		data, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to read body from stdin: %w", err)
+		}
+		return data, true, nil
 	}
-	if resp.RespPreview != "" {
-		fmt.Printf("Body Preview:\n```\n%s\n```\n", resp.RespPreview)
+	data, err := os.ReadFile(body)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to read body file: %w", err)
+	}
+	return data, true, nil
+}
+
+// buildSetJSONMap converts key=value flags to a set_json map; a key without = sets null.
+func buildSetJSONMap(setJSON []string) map[string]interface{} {
+	if len(setJSON) == 0 {
+		return nil
+	}
+	setJSONMap := make(map[string]interface{})
+	for _, kv := range setJSON {
+		if key, value, ok := strings.Cut(kv, "="); ok && key != "" {
+			setJSONMap[key] = value
+		} else {
+			setJSONMap[kv] = nil
+		}
+	}
+	return setJSONMap
+}
+
+// buildSetFormMap converts name=value flags to a set_form map; a name without = sets an empty value.
+func buildSetFormMap(setForm []string) map[string]string {
+	if len(setForm) == 0 {
+		return nil
+	}
+	setFormMap := make(map[string]string, len(setForm))
+	for _, kv := range setForm {
+		name, value, _ := strings.Cut(kv, "=")
+		setFormMap[name] = value
+	}
+	return setFormMap
+}
+
+// buildSetXMLMap converts xpath=value flags to a set_xml map. The XPath ends at the
+// first = outside brackets and quotes; without one the node is set to an empty value.
+func buildSetXMLMap(setXML []string) map[string]string {
+	if len(setXML) == 0 {
+		return nil
+	}
+	setXMLMap := make(map[string]string, len(setXML))
+	for _, kv := range setXML {
+		xpath, value := kv, ""
+		var quote rune
+		var depth int
+	scan:
+		for i, c := range kv {
+			switch {
+			case quote != 0:
+				if c == quote {
+					quote = 0
+				}
+			case c == '"' || c == '\'':
+				quote = c
+			case c == '[' || c == '(':
+				depth++
+			case c == ']' || c == ')':
+				depth--
+			case c == '=' && depth == 0:
+				xpath, value = kv[:i], kv[i+1:]
+				break scan
+			}
+		}
+		setXMLMap[xpath] = value
+	}
+	return setXMLMap
+}
+
+func rerun(mcpURL string, timeout time.Duration, replayID, body string, mods requestMods) error {
+	bodyOverride, _, err := readBodyOverride(body)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	client, err := mcpclient.Connect(ctx, mcpURL)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = client.Close() }()
+
+	var timeoutStr string
+	if mods.requestTimeout > 0 {
+		timeoutStr = mods.requestTimeout.String()
+	}
+
+	resp, err := client.ReplaySend(ctx, mcpclient.ReplaySendOpts{
+		ReplayID:        replayID,
+		Body:            string(bodyOverride),
+		Target:          mods.target,
+		AddHeaders:      mods.headers,
+		RemoveHeaders:   mods.removeHeaders,
+		Path:            mods.path,
+		Query:           mods.query,
+		SetQuery:        mods.setQuery,
+		RemoveQuery:     mods.removeQuery,
+		SetJSON:         buildSetJSONMap(mods.setJSON),
+		RemoveJSON:      mods.removeJSON,
+		SetForm:         buildSetFormMap(mods.setForm),
+		RemoveForm:      mods.removeForm,
+		SetXML:          buildSetXMLMap(mods.setXML),
+		RemoveXML:       mods.removeXML,
+		XMLDoctype:      mods.xmlDoctype,
+		FollowRedirects: mods.followRedirects,
+		KeepEncoding:    mods.keepEncoding,
+		Timeout:         timeoutStr,
+		Force:           mods.force,
+		Label:           mods.label,
+		AuthProfile:     mods.authProfile,
+	})
+	if err != nil {
+		return fmt.Errorf("replay rerun failed: %w", err)
+	}
+
+	printReplayResult(resp)
+	return nil
+}
+
+func history(mcpURL string, timeout time.Duration, limit int, format cliutil.Format, columns []string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	client, err := mcpclient.Connect(ctx, mcpURL)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = client.Close() }()
+
+	resp, err := client.ReplayHistory(ctx, limit)
+	if err != nil {
+		return fmt.Errorf("replay history failed: %w", err)
+	}
+
+	defer cliutil.StartPager()()
+
+	if len(resp.Replays) == 0 && (format == cliutil.FormatMarkdown || format == "") {
+		fmt.Println("No replays recorded.")
+		cliutil.Hintf("\nTo send one: `sectool replay send --flow <flow_id>`\n")
+		return nil
+	}
+
+	if columns == nil {
+		// Only show the label and base columns when some replay has one
+		hasLabels := slices.ContainsFunc(resp.Replays, func(r protocol.ReplayHistoryEntry) bool {
+			return r.Label != ""
+		})
+		hasBases := slices.ContainsFunc(resp.Replays, func(r protocol.ReplayHistoryEntry) bool {
+			return r.Base != ""
+		})
+		columns = slices.DeleteFunc(slices.Clone(historyColumns), func(c string) bool {
+			return (c == "label" && !hasLabels) || (c == "base" && !hasBases)
+		})
+	}
+
+	t := cliutil.NewTable(os.Stdout, format, historyColumns, columns)
+	t.Header()
+	for _, r := range resp.Replays {
+		t.Row(r.ReplayID, r.Label, r.Base, r.Method, r.URL, strconv.Itoa(r.Status), strconv.Itoa(r.RespSize), r.Duration, r.CreatedAt)
+	}
+	t.Flush()
+	if !t.Markdown() {
+		return nil
+	}
+	fmt.Printf("\n*%d replay(s)*\n", len(resp.Replays))
+	cliutil.Hintf("\nTo view a response: `sectool replay get <replay_id>`\n")
+	cliutil.Hintf("To send again with changes: `sectool replay rerun <replay_id> [modifications]`\n")
+
+	return nil
+}
+
+func diff(mcpURL string, timeout time.Duration, replayA, replayB string, noiseFilter bool, ignoreHeaders, ignorePatterns []string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	client, err := mcpclient.Connect(ctx, mcpURL)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = client.Close() }()
+
+	resp, err := client.ReplayDiff(ctx, mcpclient.ReplayDiffOpts{
+		ReplayA:        replayA,
+		ReplayB:        replayB,
+		NoNoiseFilter:  !noiseFilter,
+		IgnoreHeaders:  ignoreHeaders,
+		IgnorePatterns: ignorePatterns,
+	})
+	if err != nil {
+		return fmt.Errorf("replay diff failed: %w", err)
+	}
+
+	defer cliutil.StartPager()()
+
+	fmt.Printf("## Replay Diff\n\n")
+	fmt.Printf("A: `%s`  B: `%s`\n\n", resp.ReplayA, resp.ReplayB)
+	if resp.Identical {
+		fmt.Println("Responses are identical (after noise filtering).")
+		return nil
+	}
+
+	if resp.Status.Changed {
+		fmt.Printf("Status: %d -> %d\n", resp.Status.A, resp.Status.B)
+	} else {
+		fmt.Printf("Status: %d (unchanged)\n", resp.Status.A)
+	}
+	fmt.Printf("Body: %d -> %d bytes, %.0f%% similar\n\n", resp.Body.SizeA, resp.Body.SizeB, resp.Body.Similarity*100)
+
+	if h := resp.Headers; len(h.Added)+len(h.Removed)+len(h.Changed) > 0 {
+		fmt.Printf("### Headers\n\n")
+		for _, v := range h.Removed {
+			fmt.Printf("- removed `%s: %s`\n", v.Name, v.Value)
+		}
+		for _, v := range h.Added {
+			fmt.Printf("- added `%s: %s`\n", v.Name, v.Value)
+		}
+		for _, c := range h.Changed {
+			fmt.Printf("- changed `%s`: `%s` -> `%s`\n", c.Name, c.A, c.B)
+		}
+		fmt.Println()
+	}
+
+	if resp.Body.Diff != "" {
+		fmt.Printf("### Body\n\n```diff\n%s```\n", resp.Body.Diff)
+		if resp.Body.Truncated {
+			fmt.Println("\n*(diff truncated)*")
+		}
+	}
+	return nil
+}
+
+func extract(mcpURL string, timeout time.Duration, opts mcpclient.ResponseExtractOpts) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	client, err := mcpclient.Connect(ctx, mcpURL)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = client.Close() }()
+
+	resp, err := client.ResponseExtract(ctx, opts)
+	if err != nil {
+		return fmt.Errorf("replay extract failed: %w", err)
+	}
+
+	defer cliutil.StartPager()()
+
+	for i, r := range resp.Results {
+		if i > 0 {
+			fmt.Println()
+		}
+		fmt.Printf("## `%s` (status %d)\n\n", r.ReplayID, r.Status)
+		switch {
+		case r.Error != "":
+			fmt.Printf("Error: %s\n", r.Error)
+		case r.Total == 0:
+			fmt.Println("No matches.")
+		default:
+			fmt.Printf("```\n%s\n```\n", strings.Join(r.Matches, "\n"))
+			if r.Total > len(r.Matches) {
+				fmt.Printf("\n%d of %d matches shown (--limit)\n", len(r.Matches), r.Total)
+			}
+		}
+	}
+	return nil
+}
+
+func params(mcpURL string, timeout time.Duration, opts mcpclient.ParamDiscoverOpts) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	client, err := mcpclient.Connect(ctx, mcpURL)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = client.Close() }()
+
+	resp, err := client.ParamDiscover(ctx, opts)
+	if err != nil {
+		return fmt.Errorf("replay params failed: %w", err)
+	}
+
+	fmt.Printf("Tested %d names in the %s of %s with %d requests\n\n", resp.Tested, resp.Location, resp.Base, resp.Requests)
+	if len(resp.Candidates) == 0 {
+		fmt.Println("No parameter changed the response.")
+	} else {
+		t := cliutil.NewTable(os.Stdout, cliutil.FormatMarkdown, paramColumns, paramColumns)
+		t.Header()
+		for _, c := range resp.Candidates {
+			t.Row(c.Name, strings.Join(c.Evidence, "; "), strconv.Itoa(c.Status), strconv.Itoa(c.Size), c.ReplayID)
+		}
+		t.Flush()
+	}
+	if resp.Echoed {
+		fmt.Println("\nThe response echoes every added value, so reflection was not counted as evidence.")
+	}
+	if resp.Stopped != "" {
+		cliutil.Hintf("\nStopped at the request budget before every name was tested; raise --max-requests\n")
+	} else if len(resp.Candidates) > 0 {
+		cliutil.Hintf("\nConfirm with `sectool replay get <replay_id>` or `sectool replay send --flow <id> --set-query name=value`\n")
+	}
+	return nil
+}
+
+func ssrf(mcpURL string, timeout time.Duration, opts mcpclient.SSRFTestOpts, all bool) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	client, err := mcpclient.Connect(ctx, mcpURL)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = client.Close() }()
+
+	resp, err := client.SSRFTest(ctx, opts)
+	if err != nil {
+		return fmt.Errorf("replay ssrf failed: %w", err)
+	}
+
+	name := resp.Param
+	if name == "" {
+		name = resp.Header
+	}
+	fmt.Printf("Sent %d payloads in %s %s of %s (%d requests)\n", len(resp.Results), resp.Location, name, resp.Base, resp.Requests)
+	fmt.Printf("Baseline: %d, %d bytes, %s. Control: %d, %d bytes, %s\n",
+		resp.Baseline.Status, resp.Baseline.Size, resp.Baseline.Duration, resp.Control.Status, resp.Control.Size, resp.Control.Duration)
+	if resp.OastID != "" {
+		fmt.Printf("OAST session: %s (%s)\n", resp.OastID, resp.Domain)
+	}
+	fmt.Println()
+
+	var hidden int
+	t := cliutil.NewTable(os.Stdout, cliutil.FormatMarkdown, ssrfColumns, ssrfColumns)
+	t.Header()
+	for _, r := range resp.Results {
+		if r.Verdict == "none" && !all {
+			hidden++
+			continue
+		}
+		var status, size string
+		if r.Status > 0 {
+			status, size = strconv.Itoa(r.Status), strconv.Itoa(r.Size)
+		}
+		t.Row(r.Payload, r.Category, r.Verdict, strings.Join(r.Evidence, "; "), status, size, r.ReplayID)
 	}
+	t.Flush()
 
+	fmt.Printf("\n%d confirmed, %d differ", resp.Confirmed, resp.Differs)
+	if hidden > 0 {
+		fmt.Printf(", %d with verdict none hidden (--all)", hidden)
+	}
+	fmt.Println()
+	if resp.Confirmed > 0 || resp.Differs > 0 {
+		cliutil.Hintf("\nInspect with `sectool replay get <replay_id>`\n")
+	}
+	if resp.OastID != "" {
+		cliutil.Hintf("Later OAST interactions show in `sectool oast poll %s`\n", resp.OastID)
+	}
 	return nil
 }
 
-func get(mcpURL string, timeout time.Duration, replayID string) error {
+func get(mcpURL string, timeout time.Duration, replayID, output, render string) error {
 	ctx, cancel := context.WithTimeout(context.Background(), timeout)
 	defer cancel()
 
@@ -142,11 +501,22 @@ func get(mcpURL string, timeout time.Duration, replayID string) error {
 	}
 	defer func() { _ = client.Close() }()
 
-	resp, err := client.ReplayGet(ctx, replayID)
+	var resp *protocol.ReplayGetResponse
+	if render != "" {
+		resp, err = client.ReplayRender(ctx, replayID, render)
+	} else {
+		resp, err = client.ReplayGet(ctx, replayID)
+	}
 	if err != nil {
 		return fmt.Errorf("replay get failed: %w", err)
 	}
 
+	if output != "" {
+		return saveBody(output, resp.RespHeaders, resp.RespBody)
+	}
+
+	defer cliutil.StartPager()()
+
 	fmt.Printf("## Replay Details\n\n")
 	fmt.Printf("Replay ID: `%s`\n", resp.ReplayID)
 	fmt.Printf("Duration: %s\n", resp.Duration)
@@ -156,7 +526,9 @@ func get(mcpURL string, timeout time.Duration, replayID string) error {
 		fmt.Printf("Headers:\n```\n%s```\n\n", resp.RespHeaders)
 	}
 
-	if resp.RespBody != "" {
+	if resp.Rendered != "" {
+		fmt.Printf("Body (%s):\n```\n%s\n```\n", resp.Rendered, strings.TrimRight(resp.RespBody, "\n"))
+	} else if resp.RespBody != "" {
 		body, err := base64.StdEncoding.DecodeString(resp.RespBody)
 		if err != nil {
 			fmt.Printf("Body: (failed to decode: %v)\n", err)
@@ -168,6 +540,25 @@ func get(mcpURL string, timeout time.Duration, replayID string) error {
 	return nil
 }
 
+// saveBody decodes a base64 response body and writes it to output. A content
+// encoding that cannot be removed is reported, and the body is saved as received.
+func saveBody(output, headers, encoded string) error {
+	body, err := bundle.DecodeBase64Body(encoded)
+	if err != nil {
+		return fmt.Errorf("decode response body: %w", err)
+	}
+	if body, err = bundle.DecodeBody(headers, body); err != nil {
+		cliutil.Logf(cliutil.VerbosityNormal, "warning: %v", err)
+	}
+	if err := bundle.WriteBody(output, body); err != nil {
+		return fmt.Errorf("write body: %w", err)
+	}
+	if output != "-" {
+		fmt.Printf("Wrote %d bytes to `%s`\n", len(body), output)
+	}
+	return nil
+}
+
 func create(_ string, _ time.Duration, urlArg, method string, headers []string, bodyPath string) error {
 	// Parse and normalize URL
 	if !strings.Contains(urlArg, "://") {
@@ -236,7 +627,7 @@ func create(_ string, _ time.Duration, urlArg, method string, headers []string,
 	fmt.Printf("- `%s/request.http` - HTTP headers (edit this)\n", bundlePath)
 	fmt.Printf("- `%s/body` - Request body (edit this)\n", bundlePath)
 	fmt.Printf("- `%s/request.meta.json` - Metadata\n\n", bundlePath)
-	fmt.Printf("To send: `sectool replay send --bundle %s`\n", bundleID)
+	cliutil.Hintf("To send: `sectool replay send --bundle %s`\n", bundleID)
 
 	return nil
 }
@@ -244,8 +635,10 @@ func create(_ string, _ time.Duration, urlArg, method string, headers []string,
 func sendFromBundle(mcpURL string, timeout time.Duration, bundleArg, target string, addHeaders, removeHeaders []string,
 	path, query string, setQuery, removeQuery []string,
 	setJSON map[string]interface{}, removeJSON []string,
+	setForm map[string]string, removeForm []string,
+	setXML map[string]string, removeXML []string, xmlDoctype string,
 	bodyOverride []byte, hasBodyOverride bool,
-	followRedirects bool, requestTimeout time.Duration) error {
+	followRedirects, keepEncoding bool, requestTimeout time.Duration, label, authProfile string) error {
 	bundlePath, err := bundle.ResolvePath(bundleArg)
 	if err != nil {
 		return err
@@ -274,6 +667,12 @@ func sendFromBundle(mcpURL string, timeout time.Duration, bundleArg, target stri
 
 	headerMap = applyHeaderModifications(headerMap, addHeaders, removeHeaders)
 	deleteHeaderCaseInsensitive(headerMap, "Content-Length")
+	if body, err = applyFormModifications(headerMap, body, setForm, removeForm); err != nil {
+		return err
+	}
+	if body, err = service.ModifyXMLBody(body, setXML, removeXML, xmlDoctype); err != nil {
+		return err
+	}
 
 	urlStr, err := applyURLModifications(meta.URL, target, path, query, setQuery, removeQuery)
 	if err != nil {
@@ -300,7 +699,10 @@ func sendFromBundle(mcpURL string, timeout time.Duration, bundleArg, target stri
 		Headers:         headerMap,
 		Body:            string(body),
 		FollowRedirects: followRedirects,
+		KeepEncoding:    keepEncoding,
 		Timeout:         timeoutStr,
+		Label:           label,
+		AuthProfile:     authProfile,
 	})
 	if err != nil {
 		return fmt.Errorf("request send: %w", err)
@@ -313,8 +715,10 @@ func sendFromBundle(mcpURL string, timeout time.Duration, bundleArg, target stri
 func sendFromFile(mcpURL string, timeout time.Duration, file, target string, addHeaders, removeHeaders []string,
 	path, query string, setQuery, removeQuery []string,
 	setJSON map[string]interface{}, removeJSON []string,
+	setForm map[string]string, removeForm []string,
+	setXML map[string]string, removeXML []string, xmlDoctype string,
 	bodyOverride []byte, hasBodyOverride bool,
-	followRedirects bool, requestTimeout time.Duration) error {
+	followRedirects, keepEncoding bool, requestTimeout time.Duration, label, authProfile string) error {
 	data, err := readRequestData(file)
 	if err != nil {
 		return err
@@ -352,6 +756,12 @@ func sendFromFile(mcpURL string, timeout time.Duration, file, target string, add
 
 	headerMap = applyHeaderModifications(headerMap, addHeaders, removeHeaders)
 	deleteHeaderCaseInsensitive(headerMap, "Content-Length")
+	if body, err = applyFormModifications(headerMap, body, setForm, removeForm); err != nil {
+		return err
+	}
+	if body, err = service.ModifyXMLBody(body, setXML, removeXML, xmlDoctype); err != nil {
+		return err
+	}
 
 	baseURL, err := buildURLFromHTTPRequest(req, target)
 	if err != nil {
@@ -382,7 +792,10 @@ func sendFromFile(mcpURL string, timeout time.Duration, file, target string, add
 		Headers:         headerMap,
 		Body:            string(body),
 		FollowRedirects: followRedirects,
+		KeepEncoding:    keepEncoding,
 		Timeout:         timeoutStr,
+		Label:           label,
+		AuthProfile:     authProfile,
 	})
 	if err != nil {
 		return fmt.Errorf("request send: %w", err)
@@ -427,6 +840,29 @@ func applyHeaderModifications(headers map[string]string, addHeaders, removeHeade
 	return result
 }
 
+// applyFormModifications edits a form body for bundle and file sends, replacing
+// Content-Type when the multipart boundary changes.
+func applyFormModifications(headers map[string]string, body []byte, setForm map[string]string, removeForm []string) ([]byte, error) {
+	if len(setForm) == 0 && len(removeForm) == 0 {
+		return body, nil
+	}
+	var contentType string
+	for name, value := range headers {
+		if strings.EqualFold(name, "Content-Type") {
+			contentType = value
+		}
+	}
+	newContentType, body, err := service.ModifyFormBody(contentType, body, setForm, removeForm)
+	if err != nil {
+		return nil, err
+	}
+	if newContentType != contentType {
+		deleteHeaderCaseInsensitive(headers, "Content-Type")
+		headers["Content-Type"] = newContentType
+	}
+	return body, nil
+}
+
 func deleteHeaderCaseInsensitive(headers map[string]string, name string) {
 	name = strings.TrimSpace(name)
 	for k := range headers {
@@ -546,11 +982,31 @@ func parseHeaders(raw []byte) (map[string]string, error) {
 func printReplayResult(resp *protocol.ReplaySendResponse) {
 	fmt.Printf("## Replay Result\n\n")
 	fmt.Printf("Replay ID: `%s`\n", resp.ReplayID)
-	fmt.Printf("Duration: %s\n\n", resp.Duration)
+	fmt.Printf("Duration: %s\n", resp.Duration)
+	if resp.SessionRefreshed {
+		fmt.Println("Session: token expired, logged in again and resent")
+	}
+	if resp.CSRFToken != "" {
+		fmt.Printf("CSRF token: `%s`\n", resp.CSRFToken)
+	}
+	if len(resp.JarSent) > 0 {
+		fmt.Printf("Cookie jar sent: %s\n", strings.Join(resp.JarSent, ", "))
+	}
+	if len(resp.JarStored) > 0 {
+		fmt.Printf("Cookie jar stored: %s\n", strings.Join(resp.JarStored, ", "))
+	}
+	fmt.Println()
 
 	fmt.Printf("### Response\n\n")
 	fmt.Printf("Status: %d %s\n", resp.Status, resp.StatusLine)
-	fmt.Printf("Size: %d bytes\n\n", resp.RespSize)
+	fmt.Printf("Size: %d bytes\n", resp.RespSize)
+	if resp.Decoded != "" {
+		fmt.Printf("Decoded: %s\n", resp.Decoded)
+	}
+	fmt.Println()
+	if cliutil.Quiet() {
+		return // status only; use `replay get` for payloads
+	}
 	if resp.RespHeaders != "" {
 		fmt.Printf("Headers:\n```\n%s```\n\n", resp.RespHeaders)
 	}
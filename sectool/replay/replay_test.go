@@ -338,3 +338,43 @@ func mustParseURL(raw string) *url.URL {
 	}
 	return u
 }
+
+func TestApplyFormModifications(t *testing.T) {
+	t.Parallel()
+
+	headers := map[string]string{"content-type": "application/x-www-form-urlencoded"}
+	body, err := applyFormModifications(headers, []byte("a=1&b=2"), buildSetFormMap([]string{"a=x=y", "c"}), []string{"b"})
+	require.NoError(t, err)
+	assert.Equal(t, "a=x%3Dy&c=", string(body))
+	assert.Equal(t, map[string]string{"content-type": "application/x-www-form-urlencoded"}, headers)
+
+	headers = map[string]string{"Content-Type": "multipart/form-data; boundary=q"}
+	body, err = applyFormModifications(headers, nil, map[string]string{"f": "--q"}, nil)
+	require.NoError(t, err)
+	assert.NotEqual(t, "multipart/form-data; boundary=q", headers["Content-Type"])
+	assert.Contains(t, string(body), "\r\n\r\n--q\r\n")
+
+	_, err = applyFormModifications(map[string]string{"Content-Type": "text/plain"}, []byte("x"), map[string]string{"a": "b"}, nil)
+	assert.Error(t, err)
+}
+
+func TestBuildSetXMLMap(t *testing.T) {
+	t.Parallel()
+
+	assert.Nil(t, buildSetXMLMap(nil))
+	assert.Equal(t, map[string]string{
+		"//user/role":              "admin",
+		"//item[@id='2']/qty":      "5",
+		`//a[b="x=y"]/@c`:          "d=e",
+		"//name":                   "&xxe;",
+		"//note":                   "",
+		"//p[contains(., '=')]/@v": "1",
+	}, buildSetXMLMap([]string{
+		"//user/role=admin",
+		"//item[@id='2']/qty=5",
+		`//a[b="x=y"]/@c=d=e`,
+		"//name=&xxe;",
+		"//note",
+		"//p[contains(., '=')]/@v=1",
+	}))
+}
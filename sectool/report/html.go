@@ -0,0 +1,112 @@
+package report
+
+import (
+	"html/template"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/go-harden/llm-security-toolbox/sectool/protocol"
+)
+
+var htmlTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"inc":      func(i int) int { return i + 1 },
+	"heading":  evidenceHeading,
+	"unixText": func(s string) string { return strings.ReplaceAll(s, "\r\n", "\n") },
+	"notes":    findingNotes,
+	"noteMeta": noteMeta,
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { font-family: system-ui, sans-serif; max-width: 60rem; margin: 2rem auto; padding: 0 1rem; color: #222; }
+table { border-collapse: collapse; margin: 1rem 0; }
+th, td { border: 1px solid #ccc; padding: .3rem .6rem; text-align: left; }
+pre { background: #f5f5f5; padding: .6rem; overflow-x: auto; white-space: pre-wrap; word-break: break-all; }
+.sev { font-weight: bold; text-transform: uppercase; }
+.critical { color: #7b0000; } .high { color: #c00; } .medium { color: #d67200; } .low { color: #2a6ebb; } .info { color: #666; }
+.desc { white-space: pre-wrap; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<p>Generated {{.Generated}}. {{.Summary}}.</p>
+{{- if .Findings}}
+<table>
+<tr><th>#</th><th>Severity</th><th>Title</th><th>Status</th><th>ID</th></tr>
+{{- range $i, $f := .Findings}}
+<tr><td>{{inc $i}}</td><td class="sev {{$f.Severity}}">{{$f.Severity}}</td><td><a href="#{{$f.FindingID}}">{{$f.Title}}</a></td><td>{{$f.Status}}</td><td>{{$f.FindingID}}</td></tr>
+{{- end}}
+</table>
+{{- end}}
+{{- range $i, $f := .Findings}}
+<section id="{{$f.FindingID}}">
+<h2>{{inc $i}}. {{$f.Title}}</h2>
+<p><span class="sev {{$f.Severity}}">{{$f.Severity}}</span> · {{$f.Status}} · {{$f.FindingID}} · recorded {{$f.CreatedAt}}</p>
+{{- if $f.Description}}
+<p class="desc">{{$f.Description}}</p>
+{{- end}}
+{{- if $f.Steps}}
+<h3>Steps to reproduce</h3>
+<ol>
+{{- range $f.Steps}}
+<li>{{.}}</li>
+{{- end}}
+</ol>
+{{- end}}
+{{- if $f.OastEvents}}
+<h3>Out-of-band interactions</h3>
+<table>
+<tr><th>Time</th><th>Type</th><th>Subdomain</th><th>Source IP</th></tr>
+{{- range $f.OastEvents}}
+<tr><td>{{.Time}}</td><td>{{.Type}}</td><td>{{.Subdomain}}</td><td>{{.SourceIP}}</td></tr>
+{{- end}}
+</table>
+{{- end}}
+{{- if $f.Evidence}}
+<h3>Evidence</h3>
+{{- range $f.Evidence}}
+<h4>{{heading .}} <small>({{.Kind}} {{.Ref}})</small></h4>
+<pre>{{unixText .Request}}</pre>
+{{- if .Response}}
+<pre>{{unixText .Response}}</pre>
+{{- end}}
+{{- end}}
+{{- end}}
+{{- with notes $f $.Notes}}
+<h3>Notes</h3>
+<ul>
+{{- range .}}
+<li><span class="desc">{{.Text}}</span>{{with noteMeta .}} <small>({{.}})</small>{{end}}</li>
+{{- end}}
+</ul>
+{{- end}}
+</section>
+{{- end}}
+{{- with .OtherNotes}}
+<h2>Notes</h2>
+<ul>
+{{- range .}}
+<li><span class="desc">{{.Text}}</span>{{with noteMeta .}} <small>({{.}})</small>{{end}}</li>
+{{- end}}
+</ul>
+{{- end}}
+</body>
+</html>
+`))
+
+func writeHTML(w io.Writer, r Report) error {
+	return htmlTemplate.Execute(w, struct {
+		Report
+		Generated  string
+		Summary    string
+		OtherNotes []protocol.Note
+	}{
+		Report:     r,
+		Generated:  r.Generated.UTC().Format(time.RFC3339),
+		Summary:    summaryLine(r.Findings),
+		OtherNotes: otherNotes(r),
+	})
+}
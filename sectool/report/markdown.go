@@ -0,0 +1,128 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/go-harden/llm-security-toolbox/sectool/protocol"
+)
+
+func writeMarkdown(w io.Writer, r Report) error {
+	var sb strings.Builder
+	_, _ = fmt.Fprintf(&sb, "# %s\n\n", r.Title)
+	_, _ = fmt.Fprintf(&sb, "Generated %s. %s.\n\n", r.Generated.UTC().Format(time.RFC3339), summaryLine(r.Findings))
+
+	if len(r.Findings) > 0 {
+		sb.WriteString("| # | Severity | Title | Status | ID |\n|---|---|---|---|---|\n")
+		for i, f := range r.Findings {
+			_, _ = fmt.Fprintf(&sb, "| %d | %s | %s | %s | %s |\n", i+1, f.Severity, escapeCell(f.Title), f.Status, f.FindingID)
+		}
+	}
+
+	for i, f := range r.Findings {
+		_, _ = fmt.Fprintf(&sb, "\n## %d. %s\n\n", i+1, f.Title)
+		_, _ = fmt.Fprintf(&sb, "- **Severity:** %s\n- **Status:** %s\n- **ID:** %s\n- **Recorded:** %s\n", f.Severity, f.Status, f.FindingID, f.CreatedAt)
+		if f.Description != "" {
+			_, _ = fmt.Fprintf(&sb, "\n%s\n", strings.TrimSpace(f.Description))
+		}
+		if len(f.Steps) > 0 {
+			sb.WriteString("\n### Steps to reproduce\n\n")
+			for j, step := range f.Steps {
+				_, _ = fmt.Fprintf(&sb, "%d. %s\n", j+1, step)
+			}
+		}
+		if len(f.OastEvents) > 0 {
+			sb.WriteString("\n### Out-of-band interactions\n\n| Time | Type | Subdomain | Source IP |\n|---|---|---|---|\n")
+			for _, ev := range f.OastEvents {
+				_, _ = fmt.Fprintf(&sb, "| %s | %s | %s | %s |\n", ev.Time, ev.Type, escapeCell(ev.Subdomain), ev.SourceIP)
+			}
+		}
+		if len(f.Evidence) > 0 {
+			sb.WriteString("\n### Evidence\n")
+			for _, ev := range f.Evidence {
+				_, _ = fmt.Fprintf(&sb, "\n**%s** (%s %s)\n\n", evidenceHeading(ev), ev.Kind, ev.Ref)
+				writeFenced(&sb, ev.Request)
+				if ev.Response != "" {
+					sb.WriteString("\n")
+					writeFenced(&sb, ev.Response)
+				}
+			}
+		}
+		if notes := findingNotes(f, r.Notes); len(notes) > 0 {
+			sb.WriteString("\n### Notes\n\n")
+			writeNotes(&sb, notes)
+		}
+	}
+
+	if notes := otherNotes(r); len(notes) > 0 {
+		sb.WriteString("\n## Notes\n\n")
+		writeNotes(&sb, notes)
+	}
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// summaryLine describes the number of findings by severity, e.g. "3 findings: 1 high, 2 low".
+func summaryLine(findings []protocol.Finding) string {
+	if len(findings) == 0 {
+		return "No findings"
+	}
+	parts := make([]string, 0, len(severities))
+	for _, c := range countBySeverity(findings) {
+		parts = append(parts, fmt.Sprintf("%d %s", c.Count, c.Severity))
+	}
+	noun := "findings"
+	if len(findings) == 1 {
+		noun = "finding"
+	}
+	return fmt.Sprintf("%d %s: %s", len(findings), noun, strings.Join(parts, ", "))
+}
+
+func evidenceHeading(ev protocol.FindingEvidence) string {
+	heading := ev.Method + " " + ev.URL
+	if ev.Status > 0 {
+		heading += fmt.Sprintf(" → %d", ev.Status)
+	}
+	return heading
+}
+
+// writeFenced writes an HTTP message as a code block, with a fence longer than any
+// backtick run inside it.
+func writeFenced(sb *strings.Builder, msg string) {
+	fence := "```"
+	for strings.Contains(msg, fence) {
+		fence += "`"
+	}
+	msg = strings.ReplaceAll(msg, "\r\n", "\n")
+	sb.WriteString(fence + "http\n" + strings.TrimRight(msg, "\n") + "\n" + fence + "\n")
+}
+
+// writeNotes writes notes as a list.
+func writeNotes(sb *strings.Builder, notes []protocol.Note) {
+	for _, n := range notes {
+		_, _ = fmt.Fprintf(sb, "- %s", strings.ReplaceAll(strings.TrimSpace(n.Text), "\n", "\n  "))
+		if meta := noteMeta(n); meta != "" {
+			_, _ = fmt.Fprintf(sb, " (%s)", meta)
+		}
+		sb.WriteString("\n")
+	}
+}
+
+// noteMeta describes a note's tags and references, e.g. "auth; refs: f7k2x1".
+func noteMeta(n protocol.Note) string {
+	var parts []string
+	if len(n.Tags) > 0 {
+		parts = append(parts, strings.Join(n.Tags, ", "))
+	}
+	if len(n.Refs) > 0 {
+		parts = append(parts, "refs: "+strings.Join(n.Refs, ", "))
+	}
+	return strings.Join(parts, "; ")
+}
+
+func escapeCell(s string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(s, "|", `\|`), "\n", " ")
+}
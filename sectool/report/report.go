@@ -0,0 +1,128 @@
+// Package report renders recorded findings as Markdown, HTML or SARIF.
+package report
+
+import (
+	"cmp"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/go-harden/llm-security-toolbox/sectool/protocol"
+)
+
+// Report formats.
+const (
+	FormatMarkdown = "md"
+	FormatHTML     = "html"
+	FormatSARIF    = "sarif"
+)
+
+// Formats lists the supported report formats.
+var Formats = []string{FormatMarkdown, FormatHTML, FormatSARIF}
+
+// severities is ordered from most to least severe.
+var severities = []string{
+	protocol.SeverityCritical,
+	protocol.SeverityHigh,
+	protocol.SeverityMedium,
+	protocol.SeverityLow,
+	protocol.SeverityInfo,
+}
+
+// Report is the input to a renderer.
+type Report struct {
+	Title     string
+	Generated time.Time
+	Findings  []protocol.Finding // ordered as they should appear, see SortFindings
+	Notes     []protocol.Note    // newest first, as note_list returns them; see findingNotes
+}
+
+// Write renders r in format to w.
+func Write(w io.Writer, format string, r Report) error {
+	switch format {
+	case FormatMarkdown:
+		return writeMarkdown(w, r)
+	case FormatHTML:
+		return writeHTML(w, r)
+	case FormatSARIF:
+		return writeSARIF(w, r)
+	}
+	return fmt.Errorf("unknown report format %q: use %s", format, strings.Join(Formats, ", "))
+}
+
+// SeverityRank orders severities, 0 being critical. Unknown severities rank last.
+func SeverityRank(severity string) int {
+	if i := slices.Index(severities, severity); i >= 0 {
+		return i
+	}
+	return len(severities)
+}
+
+// SortFindings orders findings most severe first, then oldest first.
+func SortFindings(findings []protocol.Finding) {
+	slices.SortStableFunc(findings, func(a, b protocol.Finding) int {
+		return cmp.Or(
+			cmp.Compare(SeverityRank(a.Severity), SeverityRank(b.Severity)),
+			strings.Compare(a.CreatedAt, b.CreatedAt),
+		)
+	})
+}
+
+// findingNotes returns the notes referencing f, its flows, replays, evidence or OAST
+// sessions and events, oldest first.
+func findingNotes(f protocol.Finding, notes []protocol.Note) []protocol.Note {
+	refs := []string{f.FindingID}
+	refs = append(refs, f.FlowIDs...)
+	refs = append(refs, f.ReplayIDs...)
+	for _, ev := range f.Evidence {
+		refs = append(refs, ev.Ref)
+	}
+	for _, ev := range f.OastEvents {
+		refs = append(refs, ev.OastID, ev.EventID)
+	}
+
+	var matched []protocol.Note
+	for _, n := range slices.Backward(notes) {
+		if slices.ContainsFunc(n.Refs, func(ref string) bool { return ref != "" && slices.Contains(refs, ref) }) {
+			matched = append(matched, n)
+		}
+	}
+	return matched
+}
+
+// otherNotes returns the notes not shown under any of the findings, oldest first.
+func otherNotes(r Report) []protocol.Note {
+	var other []protocol.Note
+	for _, n := range slices.Backward(r.Notes) {
+		if !slices.ContainsFunc(r.Findings, func(f protocol.Finding) bool {
+			return len(findingNotes(f, []protocol.Note{n})) > 0
+		}) {
+			other = append(other, n)
+		}
+	}
+	return other
+}
+
+// countBySeverity returns the number of findings per severity, in severity order.
+func countBySeverity(findings []protocol.Finding) []severityCount {
+	var counts []severityCount
+	for _, sev := range severities {
+		var n int
+		for _, f := range findings {
+			if f.Severity == sev {
+				n++
+			}
+		}
+		if n > 0 {
+			counts = append(counts, severityCount{Severity: sev, Count: n})
+		}
+	}
+	return counts
+}
+
+type severityCount struct {
+	Severity string
+	Count    int
+}
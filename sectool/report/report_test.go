@@ -0,0 +1,136 @@
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/go-harden/llm-security-toolbox/sectool/protocol"
+)
+
+func testReport() Report {
+	return Report{
+		Title:     "Acme API",
+		Generated: time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC),
+		Findings: []protocol.Finding{
+			{
+				FindingID:   "f1",
+				Title:       "IDOR on GET /api/orders/{id}",
+				Severity:    protocol.SeverityHigh,
+				Status:      protocol.FindingStatusConfirmed,
+				Description: "Any user can read any order.",
+				Steps:       []string{"Log in as bob", "GET /api/orders/8"},
+				OastEvents: []protocol.FindingOastEvent{{
+					OastID: "o1", EventID: "e1", Type: "dns", SourceIP: "203.0.113.9", Subdomain: "x.oast.site", Time: "2026-03-01T11:00:00Z",
+				}},
+				Evidence: []protocol.FindingEvidence{{
+					Ref:      "r1",
+					Kind:     "replay",
+					Method:   "GET",
+					URL:      "https://example.com/api/orders/8?v=1",
+					Status:   200,
+					Request:  "GET /api/orders/8?v=1 HTTP/1.1\r\nHost: example.com\r\n\r\n",
+					Response: "HTTP/1.1 200 OK\r\n\r\n```<script>x</script>",
+				}},
+				CreatedAt: "2026-03-01T11:05:00Z",
+			},
+			{
+				FindingID: "f2",
+				Title:     "Server banner | version",
+				Severity:  protocol.SeverityInfo,
+				Status:    protocol.FindingStatusDraft,
+				CreatedAt: "2026-03-01T11:00:00Z",
+			},
+		},
+		Notes: []protocol.Note{
+			{NoteID: "n3", Text: "Staging uses the same <session> key", CreatedAt: "2026-03-01T11:30:00Z"},
+			{NoteID: "n2", Text: "DNS hit came from the PDF renderer", Refs: []string{"e1"}, CreatedAt: "2026-03-01T11:20:00Z"},
+			{NoteID: "n1", Text: "Order IDs are sequential", Tags: []string{"idor"}, Refs: []string{"r1"}, CreatedAt: "2026-03-01T11:10:00Z"},
+		},
+	}
+}
+
+func TestSortFindings(t *testing.T) {
+	t.Parallel()
+
+	findings := []protocol.Finding{
+		{FindingID: "low", Severity: protocol.SeverityLow, CreatedAt: "2026-01-01T00:00:00Z"},
+		{FindingID: "crit-new", Severity: protocol.SeverityCritical, CreatedAt: "2026-01-03T00:00:00Z"},
+		{FindingID: "odd", Severity: "unknown"},
+		{FindingID: "crit-old", Severity: protocol.SeverityCritical, CreatedAt: "2026-01-02T00:00:00Z"},
+	}
+	SortFindings(findings)
+
+	var got []string
+	for _, f := range findings {
+		got = append(got, f.FindingID)
+	}
+	assert.Equal(t, []string{"crit-old", "crit-new", "low", "odd"}, got)
+}
+
+func TestWrite(t *testing.T) {
+	t.Parallel()
+
+	t.Run("markdown", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, Write(&buf, FormatMarkdown, testReport()))
+		out := buf.String()
+		assert.True(t, strings.HasPrefix(out, "# Acme API\n"))
+		assert.Contains(t, out, "2 findings: 1 high, 1 info")
+		assert.Contains(t, out, `| 2 | info | Server banner \| version | draft | f2 |`)
+		assert.Contains(t, out, "## 1. IDOR on GET /api/orders/{id}")
+		assert.Contains(t, out, "1. Log in as bob\n2. GET /api/orders/8\n")
+		assert.Contains(t, out, "**GET https://example.com/api/orders/8?v=1 → 200** (replay r1)")
+		// Fence is longer than the backtick run in the response
+		assert.Contains(t, out, "````http\nHTTP/1.1 200 OK\n\n```<script>x</script>\n````\n")
+		assert.NotContains(t, out, "\r")
+		// Notes referencing the finding's replay and OAST event follow it, oldest first
+		assert.Contains(t, out, "### Notes\n\n- Order IDs are sequential (idor; refs: r1)\n- DNS hit came from the PDF renderer (refs: e1)\n")
+		assert.True(t, strings.HasSuffix(out, "## Notes\n\n- Staging uses the same <session> key\n"))
+	})
+
+	t.Run("html", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, Write(&buf, FormatHTML, testReport()))
+		out := buf.String()
+		assert.Contains(t, out, "<title>Acme API</title>")
+		assert.Contains(t, out, `<a href="#f1">IDOR on GET /api/orders/{id}</a>`)
+		assert.Contains(t, out, "&lt;script&gt;x&lt;/script&gt;")
+		assert.NotContains(t, out, "<script>")
+		assert.Contains(t, out, "<li>Log in as bob</li>")
+		assert.Contains(t, out, `<li><span class="desc">Order IDs are sequential</span> <small>(idor; refs: r1)</small></li>`)
+		assert.Contains(t, out, "Staging uses the same &lt;session&gt; key")
+	})
+
+	t.Run("sarif", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, Write(&buf, FormatSARIF, testReport()))
+		var log sarifLog
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &log))
+		assert.Equal(t, "2.1.0", log.Version)
+		require.Len(t, log.Runs, 1)
+		run := log.Runs[0]
+		require.Len(t, run.Tool.Driver.Rules, 2)
+		assert.Equal(t, "8.0", run.Tool.Driver.Rules[0].Properties["security-severity"])
+		require.Len(t, run.Results, 2)
+		assert.Equal(t, "error", run.Results[0].Level)
+		assert.Equal(t, "note", run.Results[1].Level)
+		require.Len(t, run.Results[0].Locations, 1)
+		assert.Equal(t, "https://example.com/api/orders/8", run.Results[0].Locations[0].PhysicalLocation.ArtifactLocation.URI)
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, Write(&buf, FormatMarkdown, Report{Title: "Empty"}))
+		assert.Contains(t, buf.String(), "No findings.")
+	})
+
+	t.Run("unknown_format", func(t *testing.T) {
+		assert.Error(t, Write(&bytes.Buffer{}, "pdf", testReport()))
+	})
+}
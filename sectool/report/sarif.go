@@ -0,0 +1,154 @@
+package report
+
+import (
+	"encoding/json"
+	"io"
+	"strings"
+
+	"github.com/go-harden/llm-security-toolbox/sectool/config"
+	"github.com/go-harden/llm-security-toolbox/sectool/protocol"
+)
+
+const (
+	sarifVersion = "2.1.0"
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+	toolURI      = "https://github.com/go-harden/llm-security-toolbox"
+)
+
+// securitySeverity maps severities to the CVSS-like scores code scanning tools
+// read from the security-severity rule property.
+var securitySeverity = map[string]string{
+	protocol.SeverityCritical: "9.5",
+	protocol.SeverityHigh:     "8.0",
+	protocol.SeverityMedium:   "5.5",
+	protocol.SeverityLow:      "3.0",
+	protocol.SeverityInfo:     "0.0",
+}
+
+// SARIF 2.1.0 log, limited to the properties sectool fills.
+type sarifLog struct {
+	Version string     `json:"version"`
+	Schema  string     `json:"$schema"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	Version        string      `json:"version"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string                 `json:"id"`
+	Name             string                 `json:"name"`
+	ShortDescription sarifText              `json:"shortDescription"`
+	FullDescription  *sarifText             `json:"fullDescription,omitempty"`
+	Properties       map[string]interface{} `json:"properties"`
+}
+
+type sarifText struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID              string                 `json:"ruleId"`
+	Level               string                 `json:"level"`
+	Message             sarifText              `json:"message"`
+	Locations           []sarifLocation        `json:"locations,omitempty"`
+	PartialFingerprints map[string]string      `json:"partialFingerprints"`
+	Properties          map[string]interface{} `json:"properties"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+func writeSARIF(w io.Writer, r Report) error {
+	run := sarifRun{
+		Tool: sarifTool{Driver: sarifDriver{
+			Name:           "sectool",
+			Version:        config.Version,
+			InformationURI: toolURI,
+			Rules:          []sarifRule{},
+		}},
+		Results: []sarifResult{},
+	}
+	for _, f := range r.Findings {
+		rule := sarifRule{
+			ID:               f.FindingID,
+			Name:             f.Title,
+			ShortDescription: sarifText{Text: f.Title},
+			Properties: map[string]interface{}{
+				"security-severity": securitySeverity[f.Severity],
+				"tags":              []string{"security"},
+			},
+		}
+		if f.Description != "" {
+			rule.FullDescription = &sarifText{Text: f.Description}
+		}
+		run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, rule)
+
+		message := f.Title
+		if f.Description != "" {
+			message += "\n\n" + f.Description
+		}
+		result := sarifResult{
+			RuleID:              f.FindingID,
+			Level:               sarifLevel(f.Severity),
+			Message:             sarifText{Text: message},
+			PartialFingerprints: map[string]string{"sectoolFindingId": f.FindingID},
+			Properties: map[string]interface{}{
+				"severity": f.Severity,
+				"status":   f.Status,
+			},
+		}
+		if len(f.Steps) > 0 {
+			result.Properties["steps"] = f.Steps
+		}
+		seen := make(map[string]bool)
+		for _, ev := range f.Evidence {
+			uri, _, _ := strings.Cut(ev.URL, "?")
+			if uri == "" || seen[uri] {
+				continue
+			}
+			seen[uri] = true
+			result.Locations = append(result.Locations, sarifLocation{
+				PhysicalLocation: sarifPhysicalLocation{ArtifactLocation: sarifArtifactLocation{URI: uri}},
+			})
+		}
+		run.Results = append(run.Results, result)
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(sarifLog{Version: sarifVersion, Schema: sarifSchema, Runs: []sarifRun{run}})
+}
+
+// sarifLevel maps a severity to a SARIF result level.
+func sarifLevel(severity string) string {
+	switch severity {
+	case protocol.SeverityCritical, protocol.SeverityHigh:
+		return "error"
+	case protocol.SeverityMedium:
+		return "warning"
+	}
+	return "note"
+}
@@ -0,0 +1,59 @@
+package reportcli
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/pflag"
+
+	"github.com/go-harden/llm-security-toolbox/sectool/mcpclient"
+	"github.com/go-harden/llm-security-toolbox/sectool/report"
+)
+
+const defaultTitle = "Security Assessment Findings"
+
+func Parse(args []string, mcpURL string) error {
+	fs := pflag.NewFlagSet("report", pflag.ContinueOnError)
+	fs.SetInterspersed(true)
+	var timeout time.Duration
+	var format, output, title string
+	var noNotes bool
+	var opts mcpclient.FindingListOpts
+
+	fs.DurationVar(&timeout, "timeout", time.Minute, "client-side timeout")
+	fs.StringVar(&format, "format", report.FormatMarkdown, "report format: "+strings.Join(report.Formats, ", "))
+	fs.StringVarP(&output, "output", "o", "", "write the report to this file instead of stdout")
+	fs.StringVar(&title, "title", defaultTitle, "report title")
+	fs.StringVar(&opts.Severity, "severity", "", "minimum severity: info, low, medium, high, critical")
+	fs.StringVar(&opts.Status, "status", "", "only findings with this status: draft, confirmed")
+	fs.BoolVar(&noNotes, "no-notes", false, "leave out project notes (note_add)")
+
+	fs.Usage = func() {
+		_, _ = fmt.Fprint(os.Stderr, `Usage: sectool report [options]
+
+Render the project findings (.sectool/findings/, recorded with the
+finding_add MCP tool) as a Markdown, HTML or SARIF report, most severe
+first, with reproduction steps, OAST interactions and the captured
+request/response evidence. Project notes follow the findings whose
+flows, replays, OAST events or IDs they reference (note_add refs); the
+others are listed at the end. SARIF output leaves notes out.
+
+Options:
+`)
+		fs.PrintDefaults()
+		_, _ = fmt.Fprint(os.Stderr, `
+Examples:
+  sectool report -o findings.md
+  sectool report --format html --severity medium -o report.html
+  sectool report --format sarif --status confirmed -o sectool.sarif
+`)
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	return run(mcpURL, timeout, format, output, title, !noNotes, opts)
+}
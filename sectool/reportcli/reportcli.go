@@ -0,0 +1,77 @@
+package reportcli
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/go-harden/llm-security-toolbox/sectool/cliutil"
+	"github.com/go-harden/llm-security-toolbox/sectool/mcpclient"
+	"github.com/go-harden/llm-security-toolbox/sectool/protocol"
+	"github.com/go-harden/llm-security-toolbox/sectool/report"
+)
+
+func run(mcpURL string, timeout time.Duration, format, output, title string, withNotes bool, opts mcpclient.FindingListOpts) error {
+	if !slices.Contains(report.Formats, format) {
+		return fmt.Errorf("unknown report format %q: use %s", format, strings.Join(report.Formats, ", "))
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	client, err := mcpclient.Connect(ctx, mcpURL)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = client.Close() }()
+
+	list, err := client.FindingList(ctx, opts)
+	if err != nil {
+		return fmt.Errorf("finding list failed: %w", err)
+	}
+	findings := make([]protocol.Finding, 0, len(list.Findings))
+	for _, summary := range list.Findings {
+		f, err := client.FindingGet(ctx, summary.FindingID)
+		if err != nil {
+			return fmt.Errorf("finding get %s failed: %w", summary.FindingID, err)
+		}
+		findings = append(findings, *f)
+	}
+	report.SortFindings(findings)
+
+	var notes []protocol.Note
+	if withNotes && format != report.FormatSARIF {
+		resp, err := client.NoteList(ctx, mcpclient.NoteListOpts{})
+		if err != nil {
+			return fmt.Errorf("note list failed: %w", err)
+		}
+		notes = resp.Notes
+	}
+
+	var buf bytes.Buffer
+	if err := report.Write(&buf, format, report.Report{
+		Title:     title,
+		Generated: time.Now(),
+		Findings:  findings,
+		Notes:     notes,
+	}); err != nil {
+		return err
+	}
+
+	if output == "" || output == "-" {
+		_, err = os.Stdout.Write(buf.Bytes())
+		return err
+	}
+	if err := os.WriteFile(output, buf.Bytes(), 0644); err != nil {
+		return err
+	}
+	fmt.Printf("Wrote %d findings to `%s`\n", len(findings), output)
+	if len(findings) == 0 {
+		cliutil.Hintf("\nRecord findings with the finding_add MCP tool.\n")
+	}
+	return nil
+}
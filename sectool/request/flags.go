@@ -0,0 +1,342 @@
+package request
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/pflag"
+
+	"github.com/go-harden/llm-security-toolbox/sectool/cli"
+	"github.com/go-harden/llm-security-toolbox/sectool/mcpclient"
+)
+
+var requestSubcommands = []string{"new", "raw", "tls", "h2", "help"}
+
+func Parse(args []string, mcpURL string) error {
+	if len(args) < 1 {
+		printUsage()
+		return errors.New("subcommand required")
+	}
+
+	switch args[0] {
+	case "new":
+		return parseNew(args[1:], mcpURL)
+	case "raw":
+		return parseRaw(args[1:], mcpURL)
+	case "tls":
+		return parseTLS(args[1:], mcpURL)
+	case "h2":
+		return parseH2(args[1:], mcpURL)
+	case "help", "--help", "-h":
+		printUsage()
+		return nil
+	default:
+		return cli.UnknownSubcommandError("request", args[0], requestSubcommands)
+	}
+}
+
+func printUsage() {
+	_, _ = fmt.Fprint(os.Stderr, `Usage: sectool request <command> [options]
+
+Craft and send new HTTP requests without a proxy history flow.
+
+---
+
+request new [options]
+
+  Send a request built from a raw HTTP file or from fields. The result is
+  stored as a replay: use 'replay get', 'replay history' and 'replay rerun'.
+
+  Input (exactly one required):
+    --file <path>         raw HTTP request (- for stdin)
+    --url <url>           build the request from --method/--header/--body
+
+  Examples:
+    sectool request new --url https://example.com/api --method POST --body '{"a":1}'
+    sectool request new --file req.http --target https://example.com:8443
+    printf 'GET / HTTP/1.1\nHost: example.com\n\n' | sectool request new --file -
+
+  Output: Markdown with replay_id, status, headers, body preview
+
+---
+
+request raw <host:port> [options]
+
+  Send bytes unchanged over TCP or TLS and print what comes back, for
+  request smuggling, malformed framing and non-HTTP services (SMTP, Redis).
+  Bare LF in --text is sent as is; use $'...\r\n' quoting for CRLF.
+
+  Input (exactly one required):
+    --file <path>         bytes to send (- for stdin)
+    --text <string>       text to send
+
+  Options:
+    --tls                 wrap the connection in TLS (not verified)
+    --sni <name>          TLS server name (default: host)
+    --alpn <proto>        TLS ALPN protocol to offer (repeatable)
+    --half-close          close the write side after sending
+    --wait <d>            idle time that ends reading (default: 2s)
+    --max-bytes <n>       maximum response bytes (default: 1 MiB)
+
+  Examples:
+    sectool request raw example.com:443 --tls --file smuggle.bin
+    sectool request raw 10.0.0.5:6379 --text $'INFO\r\n'
+
+  Output: the response text, or base64 when it is not UTF-8
+
+---
+
+request tls <host[:port]> [options]
+
+  Report a TLS service's configuration: protocol versions, accepted cipher
+  suites, ALPN, the certificate chain, and weak settings as issues (old
+  protocol versions, insecure ciphers, expired or untrusted certificates,
+  weak keys). The port defaults to 443. SSL 2.0/3.0 cannot be tested.
+
+  Options:
+    --sni <name>          TLS server name and name to verify (default: host)
+    --no-ciphers          skip cipher suite enumeration (fewer handshakes)
+
+  Examples:
+    sectool request tls example.com
+    sectool request tls mail.example.com:465 --no-ciphers
+
+  Output: Markdown tables of versions, certificates and issues
+
+---
+
+request h2 [options]
+
+  Send a request as native HTTP/2 frames with control of pseudo-headers,
+  header order and SETTINGS, for HTTP/2 smuggling research (H2.CL, H2.TE,
+  CRLF or pseudo-header injection). HTTPS targets negotiate h2 by ALPN;
+  http targets use prior-knowledge h2c. The result is stored as a replay.
+
+  Base (exactly one required):
+    --flow <id>           proxy history flow
+    --replay <id>         earlier replay or label
+    --file <path>         raw HTTP request (- for stdin)
+
+  Options:
+    --pseudo <name=value> set a pseudo-header, empty value removes (repeatable)
+    -H, --header <h>      append a header verbatim (repeatable)
+    --remove-header <n>   remove a header (repeatable)
+    --header-block <h>    complete header block in send order (repeatable)
+    --body <s>            replace the body (@path reads a file)
+    --setting <name=n>    SETTINGS value, e.g. initial_window_size=1 (repeatable)
+
+  Examples:
+    sectool request h2 --flow f7k2x --pseudo :path=/admin -H 'transfer-encoding: chunked'
+    sectool request h2 --replay r1 --header-block ':method: GET' --header-block 'x: 1' \
+      --header-block ':path: /' --header-block ':scheme: https' --header-block ':authority: example.com'
+
+  Output: Markdown with replay_id, the header block as sent, status, headers,
+  body preview, and any RST_STREAM or GOAWAY error
+`)
+}
+
+func parseNew(args []string, mcpURL string) error {
+	fs := pflag.NewFlagSet("request new", pflag.ContinueOnError)
+	fs.SetInterspersed(true)
+	var timeout, requestTimeout time.Duration
+	var file, target, urlArg, method, body, label, authProfile string
+	var headers []string
+	var followRedirects, force, confirmOutOfScope bool
+
+	fs.DurationVar(&timeout, "timeout", 30*time.Second, "client-side timeout")
+	fs.StringVar(&file, "file", "", "path to raw HTTP request file (- for stdin)")
+	fs.StringVar(&target, "target", "", "destination for --file (scheme://host:port); default from the Host header")
+	fs.StringVar(&urlArg, "url", "", "target URL (builds the request from fields)")
+	fs.StringVarP(&method, "method", "X", "GET", "HTTP method (with --url)")
+	fs.StringArrayVarP(&headers, "header", "H", nil, "header \"Name: Value\" (with --url, repeatable)")
+	fs.StringVar(&body, "body", "", "request body, or @path to read from a file (with --url)")
+	fs.BoolVar(&followRedirects, "follow-redirects", false, "follow 3xx redirects")
+	fs.DurationVar(&requestTimeout, "request-timeout", 0, "HTTP request timeout (0 = no timeout)")
+	fs.BoolVar(&force, "force", false, "send the file bytes unchanged and skip validation")
+	fs.StringVar(&label, "label", "", "label for referencing this replay later (e.g., replay get <label>)")
+	fs.StringVar(&authProfile, "auth-profile", "", "auth profile from config auth_profiles (NTLM/Negotiate/Digest handshake or SigV4 signing)")
+	fs.BoolVar(&confirmOutOfScope, "confirm-out-of-scope", false, "send outside the target allowlist and project scope after confirming at the terminal")
+
+	fs.Usage = func() {
+		_, _ = fmt.Fprint(os.Stderr, `Usage: sectool request new [options]
+
+Craft and send a request without a proxy history flow.
+
+Input (exactly one required):
+  --file <path>   Raw HTTP/1.1 request (- for stdin). Bare LF line endings in
+                  the headers are converted to CRLF and Content-Length is set
+                  from the body; --force sends the bytes unchanged.
+                  The destination is --target, else the Host header (HTTPS
+                  unless the Host port is 80).
+  --url <url>     Build the request from --method, --header and --body.
+
+Validation:
+  Requests are validated before sending. Use --force to send anyway.
+
+Scope:
+  Requests outside the project scope or the target_allowlist config are
+  refused. --confirm-out-of-scope asks you to type "yes" at the terminal
+  and then sends anyway. MCP clients have no equivalent.
+
+Options:
+`)
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if (file == "") == (urlArg == "") {
+		fs.Usage()
+		return errors.New("exactly one of --file or --url is required")
+	}
+	if confirmOutOfScope {
+		if err := mcpclient.ConfirmOutOfScope(); err != nil {
+			return err
+		}
+	}
+
+	return craft(mcpURL, timeout, file, target, urlArg, method, headers, body,
+		followRedirects, requestTimeout, force, label, authProfile)
+}
+
+func parseH2(args []string, mcpURL string) error {
+	fs := pflag.NewFlagSet("request h2", pflag.ContinueOnError)
+	fs.SetInterspersed(true)
+	var timeout, requestTimeout time.Duration
+	var opts h2Options
+
+	fs.DurationVar(&timeout, "timeout", time.Minute, "client-side timeout")
+	fs.StringVar(&opts.flowID, "flow", "", "proxy history flow to use as base")
+	fs.StringVar(&opts.replayID, "replay", "", "replay ID or label to use as base")
+	fs.StringVar(&opts.file, "file", "", "raw HTTP request to use as base (- for stdin)")
+	fs.StringVar(&opts.target, "target", "", "destination (scheme://host:port); default from the base")
+	fs.StringArrayVar(&opts.pseudo, "pseudo", nil, "pseudo-header \":name=value\", empty value removes (repeatable)")
+	fs.StringArrayVarP(&opts.addHeaders, "header", "H", nil, "header \"name: value\" to append verbatim (repeatable)")
+	fs.StringArrayVar(&opts.removeHeaders, "remove-header", nil, "header name to remove (repeatable)")
+	fs.StringArrayVar(&opts.headerBlock, "header-block", nil, "complete header block in send order (repeatable)")
+	fs.StringVar(&opts.body, "body", "", "replacement body, or @path to read from a file")
+	fs.StringArrayVar(&opts.settings, "setting", nil, "SETTINGS value \"name=n\" (repeatable)")
+	fs.DurationVar(&requestTimeout, "request-timeout", 0, "limit for the exchange (default: 30s)")
+	fs.StringVar(&opts.label, "label", "", "label for referencing this replay later (e.g., replay get <label>)")
+
+	fs.Usage = func() {
+		_, _ = fmt.Fprint(os.Stderr, `Usage: sectool request h2 (--flow <id> | --replay <id> | --file <path>) [options]
+
+Send a request as native HTTP/2 frames. --header-block replaces the derived
+header block and is sent without validation, so invalid pseudo-header
+ordering and duplicates reach the server as given.
+
+Options:
+`)
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	var bases int
+	for _, s := range []string{opts.flowID, opts.replayID, opts.file} {
+		if s != "" {
+			bases++
+		}
+	}
+	if bases != 1 {
+		fs.Usage()
+		return errors.New("exactly one of --flow, --replay or --file is required")
+	}
+	opts.bodySet = fs.Changed("body")
+	if requestTimeout > 0 {
+		opts.timeout = requestTimeout.String()
+	}
+
+	return h2(mcpURL, timeout, opts)
+}
+
+func parseRaw(args []string, mcpURL string) error {
+	fs := pflag.NewFlagSet("request raw", pflag.ContinueOnError)
+	fs.SetInterspersed(true)
+	var timeout, wait, requestTimeout time.Duration
+	var opts rawOptions
+
+	fs.DurationVar(&timeout, "timeout", time.Minute, "client-side timeout")
+	fs.StringVar(&opts.file, "file", "", "file with the bytes to send (- for stdin)")
+	fs.StringVar(&opts.text, "text", "", "text to send")
+	fs.BoolVar(&opts.tls, "tls", false, "wrap the connection in TLS")
+	fs.StringVar(&opts.sni, "sni", "", "TLS server name (default: host)")
+	fs.StringArrayVar(&opts.alpn, "alpn", nil, "TLS ALPN protocol to offer (repeatable)")
+	fs.BoolVar(&opts.halfClose, "half-close", false, "close the write side after sending")
+	fs.DurationVar(&wait, "wait", 0, "idle time that ends reading (default: 2s)")
+	fs.DurationVar(&requestTimeout, "request-timeout", 0, "limit for the whole exchange (default: 30s)")
+	fs.IntVar(&opts.maxBytes, "max-bytes", 0, "maximum response bytes (default: 1 MiB)")
+
+	fs.Usage = func() {
+		_, _ = fmt.Fprint(os.Stderr, `Usage: sectool request raw <host:port> [options]
+
+Send bytes unchanged over TCP or TLS.
+
+Options:
+`)
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return errors.New("host:port is required")
+	}
+	if (opts.file == "") == (opts.text == "") {
+		fs.Usage()
+		return errors.New("exactly one of --file or --text is required")
+	}
+	opts.addr = fs.Arg(0)
+	if wait > 0 {
+		opts.wait = wait.String()
+	}
+	if requestTimeout > 0 {
+		opts.timeout = requestTimeout.String()
+	}
+
+	return raw(mcpURL, timeout, opts)
+}
+
+func parseTLS(args []string, mcpURL string) error {
+	fs := pflag.NewFlagSet("request tls", pflag.ContinueOnError)
+	fs.SetInterspersed(true)
+	var timeout, requestTimeout time.Duration
+	var opts tlsOptions
+
+	fs.DurationVar(&timeout, "timeout", 3*time.Minute, "client-side timeout")
+	fs.StringVar(&opts.sni, "sni", "", "TLS server name and name to verify (default: host)")
+	fs.BoolVar(&opts.noCiphers, "no-ciphers", false, "skip cipher suite enumeration")
+	fs.DurationVar(&requestTimeout, "request-timeout", 0, "limit for the whole probe (default: 2m)")
+
+	fs.Usage = func() {
+		_, _ = fmt.Fprint(os.Stderr, `Usage: sectool request tls <host[:port]> [options]
+
+Report a TLS service's protocol versions, cipher suites and certificate.
+
+Options:
+`)
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return errors.New("host is required")
+	}
+	opts.addr = fs.Arg(0)
+	if requestTimeout > 0 {
+		opts.timeout = requestTimeout.String()
+	}
+
+	return tlsProbe(mcpURL, timeout, opts)
+}
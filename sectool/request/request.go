@@ -0,0 +1,395 @@
+package request
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-harden/llm-security-toolbox/sectool/cliutil"
+	"github.com/go-harden/llm-security-toolbox/sectool/mcpclient"
+	"github.com/go-harden/llm-security-toolbox/sectool/protocol"
+)
+
+func craft(mcpURL string, timeout time.Duration, file, target, urlArg, method string, headers []string, body string,
+	followRedirects bool, requestTimeout time.Duration, force bool, label, authProfile string) error {
+	opts := mcpclient.RequestCraftOpts{
+		FollowRedirects: followRedirects,
+		Force:           force,
+		Label:           label,
+		AuthProfile:     authProfile,
+	}
+	if requestTimeout > 0 {
+		opts.Timeout = requestTimeout.String()
+	}
+
+	if file != "" {
+		raw, err := readFile(file)
+		if err != nil {
+			return err
+		}
+		opts.Raw = string(raw)
+		opts.Target = target
+	} else {
+		headerMap, err := parseHeaderFlags(headers)
+		if err != nil {
+			return err
+		}
+		if path, ok := strings.CutPrefix(body, "@"); ok {
+			data, err := readFile(path)
+			if err != nil {
+				return err
+			}
+			body = string(data)
+		}
+		opts.URL = urlArg
+		opts.Method = method
+		opts.Headers = headerMap
+		opts.Body = body
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	client, err := mcpclient.Connect(ctx, mcpURL)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = client.Close() }()
+
+	resp, err := client.RequestCraft(ctx, opts)
+	if err != nil {
+		return fmt.Errorf("request new failed: %w", err)
+	}
+
+	printResult(resp)
+	cliutil.Hintf("\nTo view the full response: `sectool replay get %s`\n", resp.ReplayID)
+	cliutil.Hintf("To send again with changes: `sectool replay rerun %s [modifications]`\n", resp.ReplayID)
+	return nil
+}
+
+type rawOptions struct {
+	addr      string
+	file      string
+	text      string
+	tls       bool
+	sni       string
+	alpn      []string
+	halfClose bool
+	wait      string
+	timeout   string
+	maxBytes  int
+}
+
+func raw(mcpURL string, timeout time.Duration, opts rawOptions) error {
+	host, portStr, err := net.SplitHostPort(opts.addr)
+	if err != nil {
+		return fmt.Errorf("invalid address %q: expected host:port", opts.addr)
+	}
+	port, err := strconv.Atoi(portStr)
+	if err != nil {
+		return fmt.Errorf("invalid port %q", portStr)
+	}
+	clientOpts := mcpclient.RawSendOpts{
+		Host:      host,
+		Port:      port,
+		TLS:       opts.tls,
+		SNI:       opts.sni,
+		ALPN:      opts.alpn,
+		HalfClose: opts.halfClose,
+		Wait:      opts.wait,
+		Timeout:   opts.timeout,
+		MaxBytes:  opts.maxBytes,
+		Data:      []byte(opts.text),
+	}
+	if opts.file != "" {
+		if clientOpts.Data, err = readFile(opts.file); err != nil {
+			return err
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	client, err := mcpclient.Connect(ctx, mcpURL)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = client.Close() }()
+
+	resp, err := client.RawSend(ctx, clientOpts)
+	if err != nil {
+		return fmt.Errorf("request raw failed: %w", err)
+	}
+
+	fmt.Printf("## Raw Result\n\n")
+	fmt.Printf("Target: %s", resp.Target)
+	if resp.ALPN != "" {
+		fmt.Printf(" (ALPN %s)", resp.ALPN)
+	}
+	fmt.Printf("\nSent: %d bytes, received: %d bytes in %s", resp.Sent, resp.Received, resp.Duration)
+	switch {
+	case resp.Closed:
+		fmt.Print(", closed by peer")
+	case resp.Truncated:
+		fmt.Print(", truncated at --max-bytes")
+	}
+	fmt.Printf("\n\n")
+	if cliutil.Quiet() || resp.Received == 0 {
+		return nil
+	}
+	if resp.Text != "" {
+		fmt.Printf("```\n%s\n```\n", resp.Text)
+	} else {
+		fmt.Printf("Base64 (not UTF-8):\n```\n%s\n```\n", resp.Data)
+	}
+	return nil
+}
+
+type tlsOptions struct {
+	addr      string
+	sni       string
+	noCiphers bool
+	timeout   string
+}
+
+func tlsProbe(mcpURL string, timeout time.Duration, opts tlsOptions) error {
+	clientOpts := mcpclient.TLSProbeOpts{
+		Host:      opts.addr,
+		SNI:       opts.sni,
+		NoCiphers: opts.noCiphers,
+		Timeout:   opts.timeout,
+	}
+	if host, portStr, err := net.SplitHostPort(opts.addr); err == nil {
+		port, err := strconv.Atoi(portStr)
+		if err != nil {
+			return fmt.Errorf("invalid port %q", portStr)
+		}
+		clientOpts.Host, clientOpts.Port = host, port
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	client, err := mcpclient.Connect(ctx, mcpURL)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = client.Close() }()
+
+	resp, err := client.TLSProbe(ctx, clientOpts)
+	if err != nil {
+		return fmt.Errorf("request tls failed: %w", err)
+	}
+
+	fmt.Printf("## TLS Probe: %s\n\n", resp.Target)
+	fmt.Println("| version | supported | ciphers |")
+	fmt.Println("|---------|-----------|---------|")
+	for _, v := range resp.Versions {
+		ciphers := make([]string, 0, len(v.Ciphers))
+		for _, c := range v.Ciphers {
+			name := c.Name
+			if c.Insecure {
+				name += " (insecure)"
+			}
+			ciphers = append(ciphers, name)
+		}
+		fmt.Printf("| %s | %t | %s |\n", v.Version, v.Supported, strings.Join(ciphers, ", "))
+	}
+	if len(resp.ALPN) > 0 {
+		fmt.Printf("\nALPN: %s\n", strings.Join(resp.ALPN, ", "))
+	}
+	if resp.Untested != "" {
+		fmt.Printf("Untested: %s\n", resp.Untested)
+	}
+
+	fmt.Printf("\n### Certificates\n\n")
+	if resp.Trusted {
+		fmt.Printf("Chain is trusted.\n\n")
+	} else {
+		fmt.Printf("Chain is not trusted: %s\n\n", resp.TrustError)
+	}
+	fmt.Println("| subject | issuer | expires | days left | key | signature |")
+	fmt.Println("|---------|--------|---------|-----------|-----|-----------|")
+	for _, c := range resp.Certificates {
+		fmt.Printf("| %s | %s | %s | %d | %s | %s |\n", cliutil.EscapeMarkdown(c.Subject), cliutil.EscapeMarkdown(c.Issuer),
+			c.NotAfter, c.DaysLeft, c.Key, c.SignatureAlgorithm)
+	}
+	if len(resp.Certificates) > 0 && len(resp.Certificates[0].DNSNames) > 0 {
+		fmt.Printf("\nNames: %s\n", strings.Join(resp.Certificates[0].DNSNames, ", "))
+	}
+
+	fmt.Printf("\n### Issues\n\n")
+	if len(resp.Issues) == 0 {
+		fmt.Println("No issues found.")
+		return nil
+	}
+	fmt.Println("| severity | check | detail |")
+	fmt.Println("|----------|-------|--------|")
+	for _, i := range resp.Issues {
+		fmt.Printf("| %s | %s | %s |\n", i.Severity, i.Check, cliutil.EscapeMarkdown(i.Detail))
+	}
+	cliutil.Hintf("\nRecord confirmed issues with the finding_add MCP tool.\n")
+	return nil
+}
+
+type h2Options struct {
+	flowID        string
+	replayID      string
+	file          string
+	target        string
+	pseudo        []string
+	addHeaders    []string
+	removeHeaders []string
+	headerBlock   []string
+	body          string
+	bodySet       bool
+	settings      []string
+	timeout       string
+	label         string
+}
+
+func h2(mcpURL string, timeout time.Duration, opts h2Options) error {
+	clientOpts := mcpclient.HTTP2SendOpts{
+		FlowID:        opts.flowID,
+		ReplayID:      opts.replayID,
+		Target:        opts.target,
+		AddHeaders:    opts.addHeaders,
+		RemoveHeaders: opts.removeHeaders,
+		Headers:       opts.headerBlock,
+		Timeout:       opts.timeout,
+		Label:         opts.label,
+	}
+	if opts.file != "" {
+		raw, err := readFile(opts.file)
+		if err != nil {
+			return err
+		}
+		clientOpts.Raw = string(raw)
+	}
+	if opts.bodySet {
+		body := opts.body
+		if path, ok := strings.CutPrefix(body, "@"); ok {
+			data, err := readFile(path)
+			if err != nil {
+				return err
+			}
+			body = string(data)
+		}
+		clientOpts.Body = &body
+	}
+	if len(opts.pseudo) > 0 {
+		clientOpts.Pseudo = make(map[string]string, len(opts.pseudo))
+		for _, p := range opts.pseudo {
+			name, value, ok := strings.Cut(p, "=")
+			if !ok || !strings.HasPrefix(name, ":") {
+				return fmt.Errorf("invalid --pseudo %q: expected \":name=value\"", p)
+			}
+			clientOpts.Pseudo[name] = value
+		}
+	}
+	if len(opts.settings) > 0 {
+		clientOpts.Settings = make(map[string]uint32, len(opts.settings))
+		for _, s := range opts.settings {
+			name, value, ok := strings.Cut(s, "=")
+			n, err := strconv.ParseUint(value, 10, 32)
+			if !ok || err != nil {
+				return fmt.Errorf("invalid --setting %q: expected \"name=n\"", s)
+			}
+			clientOpts.Settings[name] = uint32(n)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	client, err := mcpclient.Connect(ctx, mcpURL)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = client.Close() }()
+
+	resp, err := client.HTTP2Send(ctx, clientOpts)
+	if err != nil {
+		return fmt.Errorf("request h2 failed: %w", err)
+	}
+
+	fmt.Printf("## HTTP/2 Result\n\n")
+	fmt.Printf("Replay ID: `%s`\n", resp.ReplayID)
+	fmt.Printf("Duration: %s\n\n", resp.Duration)
+	if !cliutil.Quiet() {
+		fmt.Printf("Sent:\n```\n%s\n```\n\n", strings.Join(resp.Sent, "\n"))
+	}
+	if resp.StreamError != "" {
+		fmt.Printf("Stream reset: %s\n", resp.StreamError)
+	}
+	if resp.GoAway != "" {
+		fmt.Printf("GOAWAY: %s\n", resp.GoAway)
+	}
+	if resp.ResponseDetails == nil {
+		fmt.Printf("\nNo response headers received.\n")
+		return nil
+	}
+	fmt.Printf("\n### Response\n\n")
+	fmt.Printf("Status: %d %s\n", resp.Status, resp.StatusLine)
+	fmt.Printf("Size: %d bytes\n\n", resp.RespSize)
+	if !cliutil.Quiet() {
+		fmt.Printf("Headers:\n```\n%s```\n\n", resp.RespHeaders)
+		if resp.RespPreview != "" {
+			fmt.Printf("Body Preview:\n```\n%s\n```\n", resp.RespPreview)
+		}
+	}
+	cliutil.Hintf("\nTo view the full response: `sectool replay get %s`\n", resp.ReplayID)
+	return nil
+}
+
+func readFile(path string) ([]byte, error) {
+	if path == "-" {
+		data, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return nil, fmt.Errorf("read stdin: %w", err)
+		}
+		return data, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read file: %w", err)
+	}
+	return data, nil
+}
+
+// parseHeaderFlags converts "Name: Value" flags to a header map.
+func parseHeaderFlags(headers []string) (map[string]string, error) {
+	out := make(map[string]string, len(headers))
+	for _, h := range headers {
+		name, value, ok := strings.Cut(h, ":")
+		if !ok || strings.TrimSpace(name) == "" {
+			return nil, fmt.Errorf("invalid header %q: expected \"Name: Value\"", h)
+		}
+		out[strings.TrimSpace(name)] = strings.TrimSpace(value)
+	}
+	return out, nil
+}
+
+func printResult(resp *protocol.ReplaySendResponse) {
+	fmt.Printf("## Request Result\n\n")
+	fmt.Printf("Replay ID: `%s`\n", resp.ReplayID)
+	fmt.Printf("Duration: %s\n\n", resp.Duration)
+
+	fmt.Printf("### Response\n\n")
+	fmt.Printf("Status: %d %s\n", resp.Status, resp.StatusLine)
+	fmt.Printf("Size: %d bytes\n\n", resp.RespSize)
+	if cliutil.Quiet() {
+		return // status only; use `replay get` for payloads
+	}
+	if resp.RespHeaders != "" {
+		fmt.Printf("Headers:\n```\n%s```\n\n", resp.RespHeaders)
+	}
+	if resp.RespPreview != "" {
+		fmt.Printf("Body Preview:\n```\n%s\n```\n", resp.RespPreview)
+	}
+}
@@ -0,0 +1,27 @@
+package request
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseHeaderFlags(t *testing.T) {
+	t.Parallel()
+
+	t.Run("valid", func(t *testing.T) {
+		got, err := parseHeaderFlags([]string{"Content-Type: application/json", "X-Empty:", "X-Colon: a:b"})
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{
+			"Content-Type": "application/json",
+			"X-Empty":      "",
+			"X-Colon":      "a:b",
+		}, got)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		_, err := parseHeaderFlags([]string{"NoColon"})
+		assert.Error(t, err)
+	})
+}
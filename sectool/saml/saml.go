@@ -0,0 +1,239 @@
+// Package saml decodes, summarizes and re-encodes SAML protocol messages for SSO
+// testing. Nothing is verified or decrypted.
+package saml
+
+import (
+	"bytes"
+	"compress/flate"
+	"encoding/base64"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Bindings a message was decoded from.
+const (
+	BindingPOST     = "post"     // base64 XML, as in an auto-submitted form
+	BindingRedirect = "redirect" // raw DEFLATE then base64, as in a query string
+)
+
+// Parts of a message that may carry a signature.
+const (
+	SignedMessage   = "message"
+	SignedAssertion = "assertion"
+)
+
+// maxInflated caps the XML inflated from a redirect-binding message.
+const maxInflated = 10 << 20
+
+// Message is a decoded SAML message with the fields most relevant to testing.
+type Message struct {
+	XML     string
+	Binding string
+
+	Type         string // root element: Response, AuthnRequest, LogoutRequest, ...
+	ID           string
+	IssueInstant string
+	Destination  string
+	InResponseTo string
+	ACSURL       string // AssertionConsumerServiceURL of an AuthnRequest
+	Issuer       string
+	Status       string // top-level StatusCode value
+
+	NameID       string
+	Audiences    []string
+	NotBefore    string
+	NotOnOrAfter string
+	Attributes   map[string][]string
+
+	Signed             []string // SignedMessage and/or SignedAssertion
+	EncryptedAssertion bool
+}
+
+// Decode decodes a SAMLRequest or SAMLResponse value: URL-encoded or not, base64, and
+// DEFLATE-compressed for the redirect binding. Plain XML is accepted as is.
+func Decode(value string) (*Message, error) {
+	value = strings.TrimSpace(value)
+	if strings.HasPrefix(value, "<") {
+		return Parse([]byte(value), "")
+	}
+
+	if strings.Contains(value, "%") {
+		unescaped, err := url.PathUnescape(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid URL encoding: %w", err)
+		}
+		value = unescaped
+	}
+	// Form decoding turns "+" into a space; other whitespace is line wrapping
+	value = strings.ReplaceAll(value, " ", "+")
+	value = strings.Join(strings.Fields(value), "")
+
+	enc := base64.RawStdEncoding
+	if strings.ContainsAny(value, "-_") {
+		enc = base64.RawURLEncoding
+	}
+	data, err := enc.DecodeString(strings.TrimRight(value, "="))
+	if err != nil {
+		return nil, fmt.Errorf("invalid base64: %w", err)
+	}
+
+	if trimmed := bytes.TrimSpace(data); bytes.HasPrefix(trimmed, []byte("<")) {
+		return Parse(trimmed, BindingPOST)
+	}
+	inflated, err := io.ReadAll(io.LimitReader(flate.NewReader(bytes.NewReader(data)), maxInflated))
+	if err != nil {
+		return nil, errors.New("decoded data is neither XML nor DEFLATE-compressed XML")
+	}
+	return Parse(inflated, BindingRedirect)
+}
+
+// Encode base64-encodes message XML for the POST binding, or with deflate compresses
+// it first for the redirect binding.
+func Encode(message string, deflate bool) (string, error) {
+	data := []byte(message)
+	if deflate {
+		var buf bytes.Buffer
+		w, err := flate.NewWriter(&buf, flate.BestCompression)
+		if err != nil {
+			return "", err
+		}
+		if _, err := w.Write(data); err != nil {
+			return "", err
+		}
+		if err := w.Close(); err != nil {
+			return "", err
+		}
+		data = buf.Bytes()
+	}
+	return base64.StdEncoding.EncodeToString(data), nil
+}
+
+// Parse summarizes message XML decoded from binding.
+func Parse(data []byte, binding string) (*Message, error) {
+	m := &Message{XML: string(data), Binding: binding}
+
+	var stack []string
+	var text strings.Builder
+	var attrName string
+	dec := xml.NewDecoder(bytes.NewReader(data))
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("invalid SAML XML: %w", err)
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			parent := ""
+			if len(stack) > 0 {
+				parent = stack[len(stack)-1]
+			}
+			m.start(t, parent, len(stack) == 0, &attrName)
+			stack = append(stack, t.Name.Local)
+			text.Reset()
+		case xml.CharData:
+			text.Write(t)
+		case xml.EndElement:
+			m.end(t.Name.Local, strings.TrimSpace(text.String()), attrName)
+			stack = stack[:len(stack)-1]
+			text.Reset()
+		}
+	}
+	if m.Type == "" {
+		return nil, errors.New("invalid SAML XML: no root element")
+	}
+	return m, nil
+}
+
+func (m *Message) start(el xml.StartElement, parent string, root bool, attrName *string) {
+	attr := func(name string) string {
+		for _, a := range el.Attr {
+			if a.Name.Local == name {
+				return a.Value
+			}
+		}
+		return ""
+	}
+
+	if root {
+		m.Type = el.Name.Local
+		m.ID = attr("ID")
+		m.IssueInstant = attr("IssueInstant")
+		m.Destination = attr("Destination")
+		m.InResponseTo = attr("InResponseTo")
+		m.ACSURL = attr("AssertionConsumerServiceURL")
+		return
+	}
+
+	switch el.Name.Local {
+	case "Signature":
+		part := SignedMessage
+		if parent == "Assertion" {
+			part = SignedAssertion
+		}
+		if !slices.Contains(m.Signed, part) {
+			m.Signed = append(m.Signed, part)
+		}
+	case "EncryptedAssertion":
+		m.EncryptedAssertion = true
+	case "StatusCode":
+		if parent == "Status" && m.Status == "" {
+			m.Status = attr("Value")
+		}
+	case "Conditions":
+		m.NotBefore = attr("NotBefore")
+		m.NotOnOrAfter = attr("NotOnOrAfter")
+	case "Attribute":
+		*attrName = attr("Name")
+		if m.Attributes == nil {
+			m.Attributes = make(map[string][]string)
+		}
+		if _, ok := m.Attributes[*attrName]; !ok {
+			m.Attributes[*attrName] = []string{}
+		}
+	}
+}
+
+func (m *Message) end(name, text, attrName string) {
+	switch name {
+	case "Issuer":
+		if m.Issuer == "" {
+			m.Issuer = text
+		}
+	case "NameID":
+		if m.NameID == "" {
+			m.NameID = text
+		}
+	case "Audience":
+		m.Audiences = append(m.Audiences, text)
+	case "AttributeValue":
+		if m.Attributes != nil {
+			m.Attributes[attrName] = append(m.Attributes[attrName], text)
+		}
+	}
+}
+
+// paramRe matches SAMLRequest/SAMLResponse query or form parameters, and the hidden
+// inputs of an auto-submitted form.
+var paramRe = regexp.MustCompile(`(?:SAMLRequest|SAMLResponse)=([^&\s"']+)|name=["'](?:SAMLRequest|SAMLResponse)["'][^>]*?value=["']([^"']+)`)
+
+// Find returns the distinct SAMLRequest and SAMLResponse values in text, in order of
+// appearance.
+func Find(text string) []string {
+	var found []string
+	for _, m := range paramRe.FindAllStringSubmatch(text, -1) {
+		v := m[1] + m[2]
+		if !slices.Contains(found, v) {
+			found = append(found, v)
+		}
+	}
+	return found
+}
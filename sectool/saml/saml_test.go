@@ -0,0 +1,98 @@
+package saml
+
+import (
+	"encoding/base64"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testResponse = `<samlp:Response xmlns:samlp="urn:oasis:names:tc:SAML:2.0:protocol" xmlns:saml="urn:oasis:names:tc:SAML:2.0:assertion" xmlns:ds="http://www.w3.org/2000/09/xmldsig#" ID="_r1" IssueInstant="2026-01-01T00:00:00Z" Destination="https://sp.test/acs" InResponseTo="_req1">
+  <saml:Issuer>https://idp.test</saml:Issuer>
+  <samlp:Status><samlp:StatusCode Value="urn:oasis:names:tc:SAML:2.0:status:Success"/></samlp:Status>
+  <saml:Assertion ID="_a1">
+    <saml:Issuer>https://idp.test</saml:Issuer>
+    <ds:Signature><ds:SignedInfo/></ds:Signature>
+    <saml:Subject><saml:NameID>alice@corp.test</saml:NameID></saml:Subject>
+    <saml:Conditions NotBefore="2026-01-01T00:00:00Z" NotOnOrAfter="2026-01-01T00:05:00Z">
+      <saml:AudienceRestriction><saml:Audience>https://sp.test</saml:Audience></saml:AudienceRestriction>
+    </saml:Conditions>
+    <saml:AttributeStatement>
+      <saml:Attribute Name="role"><saml:AttributeValue>user</saml:AttributeValue><saml:AttributeValue>dev</saml:AttributeValue></saml:Attribute>
+    </saml:AttributeStatement>
+  </saml:Assertion>
+</samlp:Response>`
+
+const testAuthnRequest = `<samlp:AuthnRequest xmlns:samlp="urn:oasis:names:tc:SAML:2.0:protocol" ID="_req1" AssertionConsumerServiceURL="https://sp.test/acs"><saml:Issuer xmlns:saml="urn:oasis:names:tc:SAML:2.0:assertion">https://sp.test</saml:Issuer></samlp:AuthnRequest>`
+
+func TestDecodePOST(t *testing.T) {
+	t.Parallel()
+
+	encoded, err := Encode(testResponse, false)
+	require.NoError(t, err)
+
+	for name, value := range map[string]string{
+		"base64":      encoded,
+		"url_encoded": url.QueryEscape(encoded),
+		"form_spaces": strings.ReplaceAll(base64.StdEncoding.EncodeToString([]byte(testResponse+"<!-- >>> -->")), "+", " "),
+	} {
+		t.Run(name, func(t *testing.T) {
+			m, err := Decode(value)
+			require.NoError(t, err)
+			assert.Equal(t, BindingPOST, m.Binding)
+			assert.Equal(t, "Response", m.Type)
+		})
+	}
+
+	m, err := Decode(encoded)
+	require.NoError(t, err)
+	assert.Equal(t, testResponse, m.XML)
+	assert.Equal(t, "_r1", m.ID)
+	assert.Equal(t, "https://sp.test/acs", m.Destination)
+	assert.Equal(t, "_req1", m.InResponseTo)
+	assert.Equal(t, "https://idp.test", m.Issuer)
+	assert.Equal(t, "urn:oasis:names:tc:SAML:2.0:status:Success", m.Status)
+	assert.Equal(t, "alice@corp.test", m.NameID)
+	assert.Equal(t, []string{"https://sp.test"}, m.Audiences)
+	assert.Equal(t, "2026-01-01T00:05:00Z", m.NotOnOrAfter)
+	assert.Equal(t, map[string][]string{"role": {"user", "dev"}}, m.Attributes)
+	assert.Equal(t, []string{SignedAssertion}, m.Signed)
+	assert.False(t, m.EncryptedAssertion)
+}
+
+func TestDecodeRedirect(t *testing.T) {
+	t.Parallel()
+
+	encoded, err := Encode(testAuthnRequest, true)
+	require.NoError(t, err)
+	m, err := Decode(url.QueryEscape(encoded))
+	require.NoError(t, err)
+	assert.Equal(t, BindingRedirect, m.Binding)
+	assert.Equal(t, "AuthnRequest", m.Type)
+	assert.Equal(t, "https://sp.test/acs", m.ACSURL)
+	assert.Equal(t, "https://sp.test", m.Issuer)
+	assert.Empty(t, m.Signed)
+}
+
+func TestDecodeErrors(t *testing.T) {
+	t.Parallel()
+
+	_, err := Decode("!!!")
+	assert.ErrorContains(t, err, "invalid base64")
+	_, err = Decode(base64.StdEncoding.EncodeToString([]byte("plain text")))
+	assert.ErrorContains(t, err, "neither XML")
+	_, err = Decode("<unclosed>")
+	assert.ErrorContains(t, err, "invalid SAML XML")
+}
+
+func TestFind(t *testing.T) {
+	t.Parallel()
+
+	text := "GET /sso?SAMLRequest=fZBBa%2B&RelayState=x HTTP/1.1\r\n\r\n" +
+		`<form><input type="hidden" name="SAMLResponse" value="PHNhbWxwOlJlc3BvbnNlLz4="/></form>` +
+		"SAMLResponse=PHNhbWxwOlJlc3BvbnNlLz4="
+	assert.Equal(t, []string{"fZBBa%2B", "PHNhbWxwOlJlc3BvbnNlLz4="}, Find(text))
+}
@@ -0,0 +1,170 @@
+package scan
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/pflag"
+
+	"github.com/go-harden/llm-security-toolbox/sectool/cli"
+	"github.com/go-harden/llm-security-toolbox/sectool/mcpclient"
+)
+
+var scanSubcommands = []string{"start", "status", "issues", "help"}
+
+func Parse(args []string, mcpURL string) error {
+	if len(args) < 1 {
+		printUsage()
+		return errors.New("subcommand required")
+	}
+
+	switch args[0] {
+	case "start":
+		return parseStart(args[1:], mcpURL)
+	case "status":
+		return parseStatus(args[1:], mcpURL)
+	case "issues":
+		return parseIssues(args[1:], mcpURL)
+	case "help", "--help", "-h":
+		printUsage()
+		return nil
+	default:
+		return cli.UnknownSubcommandError("scan", args[0], scanSubcommands)
+	}
+}
+
+func printUsage() {
+	_, _ = fmt.Fprint(os.Stderr, `Usage: sectool scan <command> [options]
+
+Burp Scanner crawl and audit (Burp Suite Professional). Scans are started
+through Burp's REST API (enable it in Burp's settings; set config
+burp_rest_url, with the API key as the first path segment if one is set).
+Issues are read through the Burp MCP extension.
+
+---
+
+scan start (--flow <id> | --url <url>...) [options]
+
+  Start a scan limited to the given URL prefixes.
+
+  Options:
+    --config <name>        named scan configuration (repeatable)
+
+  Examples:
+    sectool scan start --flow f7k2x
+    sectool scan start --url https://example.com/api/ --config "Audit checks - light active"
+
+---
+
+scan status <scan_id>
+
+  Show scan progress and issue counts by severity.
+
+---
+
+scan issues [scan_id] [options]
+
+  List issues, most severe first. Without scan_id, lists every issue in the
+  Burp project, including passive findings on proxied traffic.
+
+  Options:
+    --severity <level>     minimum severity: high, medium, low, info (default: info)
+    --url <text>           only issues whose URL contains text
+    --details              include detail and remediation
+    --limit <n>            maximum issues (default: 50)
+    --offset <n>           skip first n issues
+`)
+}
+
+func parseStart(args []string, mcpURL string) error {
+	fs := pflag.NewFlagSet("scan start", pflag.ContinueOnError)
+	fs.SetInterspersed(true)
+	var timeout time.Duration
+	var opts mcpclient.ScanStartOpts
+
+	fs.DurationVar(&timeout, "timeout", 30*time.Second, "client-side timeout")
+	fs.StringVar(&opts.FlowID, "flow", "", "scan the URL of this flow_id")
+	fs.StringArrayVar(&opts.URLs, "url", nil, "URL to scan (repeatable)")
+	fs.StringArrayVar(&opts.Configurations, "config", nil, "named scan configuration (repeatable)")
+
+	fs.Usage = func() {
+		_, _ = fmt.Fprint(os.Stderr, `Usage: sectool scan start (--flow <id> | --url <url>...) [options]
+
+Start a Burp scan.
+
+Options:
+`)
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if (opts.FlowID == "") == (len(opts.URLs) == 0) {
+		fs.Usage()
+		return errors.New("exactly one of --flow or --url is required")
+	}
+
+	return start(mcpURL, timeout, opts)
+}
+
+func parseStatus(args []string, mcpURL string) error {
+	fs := pflag.NewFlagSet("scan status", pflag.ContinueOnError)
+	fs.SetInterspersed(true)
+	var timeout time.Duration
+
+	fs.DurationVar(&timeout, "timeout", 30*time.Second, "client-side timeout")
+
+	fs.Usage = func() {
+		_, _ = fmt.Fprint(os.Stderr, `Usage: sectool scan status <scan_id> [options]
+
+Show scan progress.
+
+Options:
+`)
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() < 1 {
+		fs.Usage()
+		return errors.New("scan_id required")
+	}
+
+	return status(mcpURL, timeout, fs.Arg(0))
+}
+
+func parseIssues(args []string, mcpURL string) error {
+	fs := pflag.NewFlagSet("scan issues", pflag.ContinueOnError)
+	fs.SetInterspersed(true)
+	var timeout time.Duration
+	var opts mcpclient.ScanIssuesOpts
+
+	fs.DurationVar(&timeout, "timeout", 60*time.Second, "client-side timeout")
+	fs.StringVar(&opts.Severity, "severity", "", "minimum severity: high, medium, low, info")
+	fs.StringVar(&opts.URL, "url", "", "only issues whose URL contains text")
+	fs.BoolVar(&opts.Details, "details", false, "include detail and remediation")
+	fs.IntVar(&opts.Limit, "limit", 50, "maximum issues")
+	fs.IntVar(&opts.Offset, "offset", 0, "skip first n issues")
+
+	fs.Usage = func() {
+		_, _ = fmt.Fprint(os.Stderr, `Usage: sectool scan issues [scan_id] [options]
+
+List scanner issues.
+
+Options:
+`)
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	opts.ScanID = fs.Arg(0)
+
+	return issues(mcpURL, timeout, opts)
+}
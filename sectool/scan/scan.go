@@ -0,0 +1,113 @@
+package scan
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/go-harden/llm-security-toolbox/sectool/cliutil"
+	"github.com/go-harden/llm-security-toolbox/sectool/mcpclient"
+)
+
+var issueColumns = []string{"severity", "confidence", "name", "url"}
+
+func start(mcpURL string, timeout time.Duration, opts mcpclient.ScanStartOpts) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	client, err := mcpclient.Connect(ctx, mcpURL)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = client.Close() }()
+
+	resp, err := client.ScanStart(ctx, opts)
+	if err != nil {
+		return fmt.Errorf("scan start failed: %w", err)
+	}
+	fmt.Printf("Scan %s started: %s\n", resp.ScanID, strings.Join(resp.URLs, ", "))
+	cliutil.Hintf("\nTo check progress: `sectool scan status %s`\n", resp.ScanID)
+	return nil
+}
+
+func status(mcpURL string, timeout time.Duration, scanID string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	client, err := mcpclient.Connect(ctx, mcpURL)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = client.Close() }()
+
+	resp, err := client.ScanStatus(ctx, scanID)
+	if err != nil {
+		return fmt.Errorf("scan status failed: %w", err)
+	}
+
+	fmt.Printf("Scan %s: %s (%d%%)\n", resp.ScanID, resp.Status, resp.Progress)
+	if resp.Caption != "" {
+		fmt.Println(resp.Caption)
+	}
+	fmt.Printf("Requests: %d, elapsed %s\n", resp.Requests, resp.Elapsed)
+	counts := make([]string, 0, len(resp.Severities))
+	for _, severity := range slices.Sorted(maps.Keys(resp.Severities)) {
+		counts = append(counts, fmt.Sprintf("%s %d", severity, resp.Severities[severity]))
+	}
+	fmt.Printf("Issues: %d", resp.IssueCount)
+	if len(counts) > 0 {
+		fmt.Printf(" (%s)", strings.Join(counts, ", "))
+	}
+	fmt.Println()
+	if resp.IssueCount > 0 {
+		cliutil.Hintf("\nTo list them: `sectool scan issues %s`\n", resp.ScanID)
+	}
+	return nil
+}
+
+func issues(mcpURL string, timeout time.Duration, opts mcpclient.ScanIssuesOpts) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	client, err := mcpclient.Connect(ctx, mcpURL)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = client.Close() }()
+
+	resp, err := client.ScanIssues(ctx, opts)
+	if err != nil {
+		return fmt.Errorf("scan issues failed: %w", err)
+	}
+	if len(resp.Issues) == 0 {
+		fmt.Println("No issues found.")
+		return nil
+	}
+
+	if opts.Details {
+		for _, issue := range resp.Issues {
+			fmt.Printf("## %s (%s, %s)\n\n%s\n\n", issue.Name, issue.Severity, issue.Confidence, issue.URL)
+			if issue.Detail != "" {
+				fmt.Printf("%s\n\n", issue.Detail)
+			}
+			if issue.Remediation != "" {
+				fmt.Printf("Remediation: %s\n\n", issue.Remediation)
+			}
+		}
+	} else {
+		t := cliutil.NewTable(os.Stdout, cliutil.FormatMarkdown, issueColumns, issueColumns)
+		t.Header()
+		for _, issue := range resp.Issues {
+			t.Row(issue.Severity, issue.Confidence, issue.Name, issue.URL)
+		}
+		t.Flush()
+	}
+	if resp.NextOffset > 0 {
+		cliutil.Hintf("\nShowing %d of %d: pass --offset %d for more\n", len(resp.Issues), resp.Total, resp.NextOffset)
+	}
+	return nil
+}
@@ -0,0 +1,208 @@
+package sequence
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/pflag"
+
+	"github.com/go-harden/llm-security-toolbox/sectool/cli"
+	"github.com/go-harden/llm-security-toolbox/sectool/mcpclient"
+)
+
+var sequenceSubcommands = []string{"list", "set", "run", "help"}
+
+func Parse(args []string, mcpURL string) error {
+	if len(args) < 1 {
+		printUsage()
+		return errors.New("subcommand required")
+	}
+
+	switch args[0] {
+	case "list":
+		return parseList(args[1:], mcpURL)
+	case "set":
+		return parseSet(args[1:], mcpURL)
+	case "run":
+		return parseRun(args[1:], mcpURL)
+	case "help", "--help", "-h":
+		printUsage()
+		return nil
+	default:
+		return cli.UnknownSubcommandError("sequence", args[0], sequenceSubcommands)
+	}
+}
+
+func printUsage() {
+	_, _ = fmt.Fprint(os.Stderr, `Usage: sectool sequence <command> [options]
+
+Multi-step request flows (checkout, password reset, ...) run end-to-end.
+Each step replays a captured flow or sends a crafted request; values
+extracted from one response fill {{name}} placeholders in later steps.
+Sequences are cleared when the service restarts.
+
+---
+
+sequence list
+
+  List sequences with their steps and input variables.
+
+---
+
+sequence set <name> (--steps <file> | --delete)
+
+  Define a sequence from a JSON array of steps ('-' reads stdin). A step is
+  {"name", "tool", "args", "extract", "expect"}: tool is replay_send
+  (default), request_send or request_craft, args are that tool's arguments,
+  extract maps variables to kind:expr rules (json, header, cookie, regex,
+  field), and expect lists the statuses the step must return.
+
+  Options:
+    --steps <file>         JSON steps file, or - for stdin
+    --delete               delete the sequence
+
+  Example steps:
+    [{"name": "request", "args": {"flow_id": "f7k2x", "set_json": {"email": "{{email}}"}},
+      "extract": {"token": "regex:token=([a-f0-9]+)"}, "expect": "200"},
+     {"name": "reset", "args": {"flow_id": "a1b2c", "set_json": {"token": "{{token}}"}},
+      "expect": "2xx"}]
+
+---
+
+sequence run <name> [KEY=VALUE ...]
+
+  Run the steps in order with the given input variables, stopping at the
+  first step that fails, misses its expect status, or cannot extract a value.
+  Each step is stored as a replay.
+
+  Examples:
+    sectool sequence run reset email=victim@example.com
+    sectool replay get <replay_id>
+`)
+}
+
+func parseList(args []string, mcpURL string) error {
+	fs := pflag.NewFlagSet("sequence list", pflag.ContinueOnError)
+	fs.SetInterspersed(true)
+	var timeout time.Duration
+
+	fs.DurationVar(&timeout, "timeout", 30*time.Second, "client-side timeout")
+
+	fs.Usage = func() {
+		_, _ = fmt.Fprint(os.Stderr, `Usage: sectool sequence list [options]
+
+List sequences.
+
+Options:
+`)
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	return list(mcpURL, timeout)
+}
+
+func parseSet(args []string, mcpURL string) error {
+	fs := pflag.NewFlagSet("sequence set", pflag.ContinueOnError)
+	fs.SetInterspersed(true)
+	var timeout time.Duration
+	var stepsFile string
+	var opts mcpclient.SequenceSetOpts
+
+	fs.DurationVar(&timeout, "timeout", 30*time.Second, "client-side timeout")
+	fs.StringVar(&stepsFile, "steps", "", "JSON steps file, or - for stdin")
+	fs.BoolVar(&opts.Delete, "delete", false, "delete the sequence")
+
+	fs.Usage = func() {
+		_, _ = fmt.Fprint(os.Stderr, `Usage: sectool sequence set <name> (--steps <file> | --delete) [options]
+
+Define or delete a sequence.
+
+Options:
+`)
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() < 1 {
+		fs.Usage()
+		return errors.New("sequence name required")
+	}
+	opts.Name = fs.Arg(0)
+	if !opts.Delete {
+		if stepsFile == "" {
+			return errors.New("--steps is required")
+		}
+		steps, err := readSteps(stepsFile)
+		if err != nil {
+			return err
+		}
+		opts.Steps = steps
+	}
+
+	return set(mcpURL, timeout, opts)
+}
+
+func readSteps(path string) ([]map[string]interface{}, error) {
+	var data []byte
+	var err error
+	if path == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read steps: %w", err)
+	}
+	var steps []map[string]interface{}
+	if err := json.Unmarshal(data, &steps); err != nil {
+		return nil, fmt.Errorf("steps must be a JSON array of step objects: %w", err)
+	}
+	return steps, nil
+}
+
+func parseRun(args []string, mcpURL string) error {
+	fs := pflag.NewFlagSet("sequence run", pflag.ContinueOnError)
+	fs.SetInterspersed(true)
+	var timeout time.Duration
+
+	fs.DurationVar(&timeout, "timeout", 5*time.Minute, "client-side timeout")
+
+	fs.Usage = func() {
+		_, _ = fmt.Fprint(os.Stderr, `Usage: sectool sequence run <name> [KEY=VALUE ...] [options]
+
+Run a sequence.
+
+Options:
+`)
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() < 1 {
+		fs.Usage()
+		return errors.New("sequence name required")
+	}
+	vars := make(map[string]string)
+	for _, pair := range fs.Args()[1:] {
+		key, value, ok := strings.Cut(pair, "=")
+		if !ok || key == "" {
+			return fmt.Errorf("invalid variable %q: expected KEY=VALUE", pair)
+		}
+		vars[key] = value
+	}
+
+	return run(mcpURL, timeout, fs.Arg(0), vars)
+}
@@ -0,0 +1,127 @@
+package sequence
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-harden/llm-security-toolbox/sectool/cliutil"
+	"github.com/go-harden/llm-security-toolbox/sectool/mcpclient"
+	"github.com/go-harden/llm-security-toolbox/sectool/protocol"
+)
+
+var stepColumns = []string{"step", "name", "tool", "source", "extract", "expect"}
+
+var runColumns = []string{"step", "name", "replay_id", "method", "url", "status", "extracted"}
+
+func list(mcpURL string, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	client, err := mcpclient.Connect(ctx, mcpURL)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = client.Close() }()
+
+	resp, err := client.SequenceList(ctx)
+	if err != nil {
+		return fmt.Errorf("sequence list failed: %w", err)
+	}
+	printSequences(resp)
+	return nil
+}
+
+func set(mcpURL string, timeout time.Duration, opts mcpclient.SequenceSetOpts) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	client, err := mcpclient.Connect(ctx, mcpURL)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = client.Close() }()
+
+	resp, err := client.SequenceSet(ctx, opts)
+	if err != nil {
+		return fmt.Errorf("sequence set failed: %w", err)
+	}
+	printSequences(resp)
+	if !opts.Delete {
+		cliutil.Hintf("To run it: `sectool sequence run %s [KEY=VALUE ...]`\n", opts.Name)
+	}
+	return nil
+}
+
+func run(mcpURL string, timeout time.Duration, name string, vars map[string]string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	client, err := mcpclient.Connect(ctx, mcpURL)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = client.Close() }()
+
+	resp, err := client.SequenceRun(ctx, name, vars)
+	if err != nil {
+		return fmt.Errorf("sequence run failed: %w", err)
+	}
+
+	t := cliutil.NewTable(os.Stdout, cliutil.FormatMarkdown, runColumns, runColumns)
+	t.Header()
+	for _, s := range resp.Steps {
+		var status string
+		if s.Status != 0 {
+			status = strconv.Itoa(s.Status)
+		}
+		t.Row(strconv.Itoa(s.Step), s.Name, s.ReplayID, s.Method, s.URL, status, formatVars(s.Extracted))
+	}
+	t.Flush()
+	if last := resp.Steps[len(resp.Steps)-1]; last.Error != "" {
+		fmt.Printf("\nStep %d failed: %s\n", last.Step, last.Error)
+		if resp.Skipped > 0 {
+			fmt.Printf("%d later steps skipped.\n", resp.Skipped)
+		}
+	} else {
+		fmt.Printf("\nCompleted %d steps.\n", len(resp.Steps))
+	}
+	cliutil.Hintf("\nTo see a step's full response: `sectool replay get <replay_id>`\n")
+	return nil
+}
+
+func printSequences(resp *protocol.SequenceListResponse) {
+	if len(resp.Sequences) == 0 {
+		fmt.Println("No sequences defined.")
+		cliutil.Hintf("\nTo create one: `sectool sequence set <name> --steps steps.json`\n")
+		return
+	}
+
+	for _, q := range resp.Sequences {
+		fmt.Printf("## %s\n\n", q.Name)
+		if len(q.Inputs) > 0 {
+			fmt.Printf("Inputs: %s\n\n", strings.Join(q.Inputs, ", "))
+		}
+		t := cliutil.NewTable(os.Stdout, cliutil.FormatMarkdown, stepColumns, stepColumns)
+		t.Header()
+		for i, s := range q.Steps {
+			t.Row(strconv.Itoa(i+1), s.Name, s.Tool, s.Source, formatVars(s.Extract), s.Expect)
+		}
+		t.Flush()
+		fmt.Println()
+	}
+}
+
+// formatVars renders variables as name=value pairs sorted by name.
+func formatVars(vars map[string]string) string {
+	pairs := make([]string, 0, len(vars))
+	for _, name := range slices.Sorted(maps.Keys(vars)) {
+		pairs = append(pairs, name+"="+vars[name])
+	}
+	return strings.Join(pairs, " ")
+}
@@ -0,0 +1,178 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-harden/llm-security-toolbox/sectool/config"
+	"github.com/go-harden/llm-security-toolbox/sectool/protocol"
+)
+
+// targetGuard enforces the target_allowlist config on every outbound request. Unlike
+// the project scope, which scope_set changes, nothing an MCP client sends can widen
+// or skip it: only CLI requests carrying the confirm key, which the CLI sends after
+// the user confirms --confirm-out-of-scope at a terminal, go outside the allowlist
+// and project scope. A nil guard allows everything.
+type targetGuard struct {
+	allowlist  []string // host or host/path globs; empty allows any target
+	confirmKey string   // empty when confirmation is unavailable
+	keyPath    string   // confirm key file, which tools must not read
+}
+
+// newTargetGuard returns a guard for allowlist, with the confirm key read from
+// keyPath or, when the file is missing, generated and written there owner-only.
+// Services sharing a config directory share the key.
+func newTargetGuard(allowlist []string, keyPath string) (*targetGuard, error) {
+	g := &targetGuard{allowlist: allowlist, keyPath: keyPath}
+	key, err := readConfirmKey(keyPath)
+	if err == nil {
+		g.confirmKey = key
+		return g, nil
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+
+	var raw [32]byte
+	_, _ = rand.Read(raw[:])
+	key = hex.EncodeToString(raw[:])
+	// written aside and linked into place, so other services never read a partial key
+	tmp, err := os.CreateTemp(filepath.Dir(keyPath), ".confirm-*") // mode 0600
+	if err != nil {
+		return nil, fmt.Errorf("write confirm key: %w", err)
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+	_, err = tmp.WriteString(key + "\n")
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Link(tmp.Name(), keyPath)
+	}
+	if errors.Is(err, os.ErrExist) { // another service created it first
+		if key, err = readConfirmKey(keyPath); err != nil {
+			return nil, err
+		}
+	} else if err != nil {
+		return nil, fmt.Errorf("write confirm key: %w", err)
+	}
+	g.confirmKey = key
+	return g, nil
+}
+
+func readConfirmKey(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return "", err
+	} else if err != nil {
+		return "", fmt.Errorf("read confirm key: %w", err)
+	}
+	key := strings.TrimSpace(string(data))
+	if len(key) < 32 {
+		return "", fmt.Errorf("confirm key %s is too short: delete it and restart the service", path)
+	}
+	return key, nil
+}
+
+// CheckReadable returns an error when path is the confirm key file, so tools that
+// read files named by MCP clients cannot reveal the key.
+func (g *targetGuard) CheckReadable(path string) error {
+	if g == nil || g.keyPath == "" {
+		return nil
+	}
+	want, err := os.Stat(g.keyPath)
+	if err != nil {
+		return nil
+	}
+	if got, err := os.Stat(path); err == nil && os.SameFile(got, want) {
+		return errors.New("permission denied: " + path + " holds the out of scope confirm key")
+	}
+	return nil
+}
+
+type outOfScopeConfirmedKey struct{}
+
+// withConfirmation marks ctx as confirmed when r carries the confirm key.
+func (g *targetGuard) withConfirmation(ctx context.Context, r *http.Request) context.Context {
+	if g == nil || g.confirmKey == "" {
+		return ctx
+	}
+	key := r.Header.Get(protocol.ConfirmOutOfScopeHeader)
+	if key == "" {
+		return ctx
+	} else if subtle.ConstantTimeCompare([]byte(key), []byte(g.confirmKey)) != 1 {
+		log.Printf("target guard: rejected %s header with a wrong key", protocol.ConfirmOutOfScopeHeader)
+		return ctx
+	}
+	return context.WithValue(ctx, outOfScopeConfirmedKey{}, true)
+}
+
+// Confirmed reports whether the user confirmed out of scope sends for ctx.
+func (g *targetGuard) Confirmed(ctx context.Context) bool {
+	confirmed, _ := ctx.Value(outOfScopeConfirmedKey{}).(bool)
+	return g != nil && confirmed
+}
+
+// Allows reports whether host (which may include a port) and path match the allowlist.
+func (g *targetGuard) Allows(host, path string) bool {
+	if g == nil || len(g.allowlist) == 0 {
+		return true
+	}
+	for _, pattern := range g.allowlist {
+		if matchesScopePattern(pattern, host, path) {
+			return true
+		}
+	}
+	return false
+}
+
+// Check returns an error when host and path are outside the allowlist, unless confirmed.
+func (g *targetGuard) Check(ctx context.Context, host, path string) error {
+	if g.Allows(host, path) || g.Confirmed(ctx) {
+		return nil
+	}
+	return fmt.Errorf("out of scope: %s%s is not in the target_allowlist config, which only the user can change", host, pathWithoutQuery(path))
+}
+
+// CheckRequest returns an error when req targets a host or path outside the project
+// scope or the allowlist. Requests the user confirmed pass both.
+func (g *targetGuard) CheckRequest(ctx context.Context, scope *config.Scope, req SendRequestInput) error {
+	if !g.Confirmed(ctx) {
+		if err := checkRequestScope(scope, req); err != nil {
+			return err
+		}
+	}
+	host, path := requestScopeTarget(req)
+	return g.CheckTarget(ctx, scope, host, path)
+}
+
+// CheckTarget is CheckRequest for tools given a host (which may include a port) and
+// path rather than a request, such as raw_send and tls_probe.
+func (g *targetGuard) CheckTarget(ctx context.Context, scope *config.Scope, host, path string) error {
+	if g.Confirmed(ctx) {
+		if scope != nil && !inScope(scope, host, path) || !g.Allows(host, path) {
+			log.Printf("target guard: sending to %s%s, out of scope confirmed by the CLI user", host, pathWithoutQuery(path))
+		}
+		return nil
+	} else if scope != nil && !inScope(scope, host, path) {
+		return fmt.Errorf("out of scope: %s%s is outside the project scope (see scope_get)", host, pathWithoutQuery(path))
+	}
+	return g.Check(ctx, host, path)
+}
+
+// Restricts reports whether CheckRequest can reject requests for ctx, so redirect
+// hops must be checked one by one.
+func (g *targetGuard) Restricts(ctx context.Context, scope *config.Scope) bool {
+	if g.Confirmed(ctx) {
+		return false
+	}
+	return scope != nil || (g != nil && len(g.allowlist) > 0)
+}
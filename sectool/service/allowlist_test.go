@@ -0,0 +1,165 @@
+package service
+
+import (
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/go-harden/llm-security-toolbox/sectool/config"
+	"github.com/go-harden/llm-security-toolbox/sectool/protocol"
+)
+
+func TestNewTargetGuard(t *testing.T) {
+	t.Parallel()
+
+	keyPath := filepath.Join(t.TempDir(), config.ConfirmKeyFileName)
+	g, err := newTargetGuard(nil, keyPath)
+	require.NoError(t, err)
+	assert.Len(t, g.confirmKey, 64)
+
+	info, err := os.Stat(keyPath)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	again, err := newTargetGuard([]string{"example.com"}, keyPath)
+	require.NoError(t, err)
+	assert.Equal(t, g.confirmKey, again.confirmKey, "existing key is reused")
+
+	require.NoError(t, os.WriteFile(keyPath, []byte("short\n"), 0600))
+	_, err = newTargetGuard(nil, keyPath)
+	assert.ErrorContains(t, err, "too short")
+}
+
+func TestTargetGuard(t *testing.T) {
+	t.Parallel()
+
+	g := &targetGuard{allowlist: []string{"*.example.com", "api.test.com/v2/*"}, confirmKey: "0123456789abcdef0123456789abcdef"}
+	send := func(rawURL string) SendRequestInput {
+		req := httptest.NewRequest("GET", rawURL, nil)
+		return SendRequestInput{
+			RawRequest: []byte("GET " + req.URL.RequestURI() + " HTTP/1.1\r\nHost: " + req.Host + "\r\n\r\n"),
+			Target:     targetFromURL(req.URL),
+		}
+	}
+
+	t.Run("allowlist", func(t *testing.T) {
+		assert.True(t, g.Allows("app.example.com:443", "/"))
+		assert.True(t, g.Allows("api.test.com", "/v2/users?id=1"))
+		assert.False(t, g.Allows("api.test.com", "/v1/users"))
+		assert.False(t, g.Allows("evil.com", "/"))
+		assert.True(t, (*targetGuard)(nil).Allows("evil.com", "/"))
+		assert.True(t, (&targetGuard{}).Allows("evil.com", "/"))
+	})
+
+	t.Run("allowlist_variants", func(t *testing.T) {
+		g := &targetGuard{allowlist: []string{"example.com", "app.test.com/api/*"}}
+		cases := []struct {
+			name, host, path string
+			want             bool
+		}{
+			{"upper_host", "EXAMPLE.com", "/", true},
+			{"trailing_dot", "example.com.:443", "/", true},
+			{"upper_path_host", "App.Test.com", "/api/users", true},
+			{"dot_dot_escape", "app.test.com", "/api/../admin", false},
+			{"encoded_dot_dot_escape", "app.test.com", "/api/%2e%2e/admin", false},
+			{"double_slash", "app.test.com", "//api/users", true},
+			{"dot_dot_within", "app.test.com", "/admin/../api/users", true},
+		}
+		for _, tc := range cases {
+			t.Run(tc.name, func(t *testing.T) {
+				assert.Equal(t, tc.want, g.Allows(tc.host, tc.path))
+				assert.Equal(t, tc.want, g.CheckTarget(t.Context(), nil, tc.host, tc.path) == nil)
+			})
+		}
+	})
+
+	t.Run("check_request", func(t *testing.T) {
+		require.NoError(t, g.CheckRequest(t.Context(), nil, send("https://app.example.com/")))
+		err := g.CheckRequest(t.Context(), nil, send("https://evil.com/login"))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "out of scope: evil.com:443/login is not in the target_allowlist")
+
+		// A project scope wider than the allowlist does not widen it.
+		scope := &config.Scope{Include: []string{"*"}}
+		require.Error(t, g.CheckRequest(t.Context(), scope, send("https://evil.com/")))
+		// A narrower scope still applies.
+		scope = &config.Scope{Include: []string{"app.example.com"}}
+		err = g.CheckRequest(t.Context(), scope, send("https://www.example.com/"))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "outside the project scope")
+
+		assert.True(t, g.Restricts(t.Context(), nil))
+		assert.False(t, (&targetGuard{}).Restricts(t.Context(), nil))
+	})
+
+	t.Run("check_target", func(t *testing.T) {
+		scope := &config.Scope{Include: []string{"app.example.com"}}
+		require.NoError(t, g.CheckTarget(t.Context(), scope, "app.example.com:443", "/"))
+		err := g.CheckTarget(t.Context(), scope, "www.example.com:443", "/")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "out of scope: www.example.com:443/ is outside the project scope")
+		err = g.CheckTarget(t.Context(), nil, "evil.com:22", "/")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "target_allowlist")
+	})
+
+	t.Run("confirmation", func(t *testing.T) {
+		r := httptest.NewRequest("POST", "/mcp", nil)
+		assert.False(t, g.Confirmed(g.withConfirmation(t.Context(), r)))
+
+		r.Header.Set(protocol.ConfirmOutOfScopeHeader, "wrong")
+		assert.False(t, g.Confirmed(g.withConfirmation(t.Context(), r)))
+
+		r.Header.Set(protocol.ConfirmOutOfScopeHeader, g.confirmKey)
+		ctx := g.withConfirmation(t.Context(), r)
+		require.True(t, g.Confirmed(ctx))
+		scope := &config.Scope{Include: []string{"app.example.com"}}
+		require.NoError(t, g.CheckRequest(ctx, scope, send("https://evil.com/")))
+		require.NoError(t, g.CheckTarget(ctx, scope, "evil.com:22", "/"))
+		assert.False(t, g.Restricts(ctx, scope))
+
+		// Without a key, no header confirms.
+		r.Header.Set(protocol.ConfirmOutOfScopeHeader, "")
+		assert.False(t, (&targetGuard{}).Confirmed((&targetGuard{}).withConfirmation(t.Context(), r)))
+	})
+
+	t.Run("key_file_unreadable", func(t *testing.T) {
+		dir := t.TempDir()
+		guard, err := newTargetGuard(nil, filepath.Join(dir, config.ConfirmKeyFileName))
+		require.NoError(t, err)
+		assert.ErrorContains(t, guard.CheckReadable(filepath.Join(dir, ".", config.ConfirmKeyFileName)), "permission denied")
+		assert.NoError(t, guard.CheckReadable(filepath.Join(dir, "missing.proto")))
+	})
+}
+
+func TestMCP_TargetAllowlist(t *testing.T) {
+	t.Parallel()
+
+	srv, mcpClient, _, _, _ := setupMCPServerWithMock(t)
+	srv.guard = &targetGuard{allowlist: []string{"app.example.com"}}
+
+	CallMCPToolJSONOK[protocol.ReplaySendResponse](t, mcpClient, "request_send", map[string]interface{}{
+		"url": "https://app.example.com/home",
+	})
+
+	// scope_set cannot widen the allowlist.
+	scope := CallMCPToolJSONOK[protocol.ScopeResponse](t, mcpClient, "scope_set", map[string]interface{}{
+		"include": []string{"*"},
+	})
+	assert.Equal(t, []string{"app.example.com"}, scope.TargetAllowlist)
+	result := CallMCPTool(t, mcpClient, "request_send", map[string]interface{}{
+		"url": "https://evil.com/",
+	})
+	require.True(t, result.IsError)
+	assert.Contains(t, ExtractMCPText(t, result), "target_allowlist")
+
+	result = CallMCPTool(t, mcpClient, "raw_send", map[string]interface{}{
+		"host": "evil.com", "port": 80, "text": "x",
+	})
+	require.True(t, result.IsError)
+	assert.Contains(t, ExtractMCPText(t, result), "target_allowlist")
+}
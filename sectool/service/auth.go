@@ -0,0 +1,290 @@
+package service
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-harden/llm-security-toolbox/sectool/config"
+)
+
+// resolveAuthProfile returns the named auth profile from config, or nil for an empty name.
+func (s *Server) resolveAuthProfile(name string) (*config.AuthProfile, error) {
+	if name == "" {
+		return nil, nil
+	}
+	return s.cfg.AuthProfile(name)
+}
+
+// sendRequest sends req through the HTTP backend, performing the handshake of auth
+// when set. Digest answers challenges and SigV4 signs each request through the backend. Connection-oriented schemes
+// such as NTLM authenticate a TCP connection rather than a request, so they cannot go
+// through a backend that opens a fresh connection per send; those sends go directly
+// to the target and are not recorded in proxy history.
+//
+// When a project scope is defined, the request and every redirect hop must be in scope.
+// Each hop, and each retry of a Digest challenge, waits on the rate limiter.
+// Unless req.KeepEncoding is set, the response's chunked and content codings are removed.
+func (s *Server) sendRequest(ctx context.Context, name string, req SendRequestInput, auth *config.AuthProfile) (*SendRequestResult, error) {
+	result, err := s.sendEncoded(ctx, name, req, auth)
+	if err == nil && !req.KeepEncoding {
+		result.Headers, result.Body, result.Decoded = decodeResponseEncoding(result.Headers, result.Body)
+	}
+	return result, err
+}
+
+// sendEncoded is sendRequest without response decoding.
+func (s *Server) sendEncoded(ctx context.Context, name string, req SendRequestInput, auth *config.AuthProfile) (*SendRequestResult, error) {
+	scope := s.projectScope()
+	if err := s.guard.CheckRequest(ctx, scope, req); err != nil {
+		return nil, err
+	}
+	if auth == nil && (!req.FollowRedirects || !s.guard.Restricts(ctx, scope) && !s.rateLimiter.Restricts(ctx)) {
+		release, err := s.rateLimiter.Wait(ctx, req.Target.Hostname)
+		if err != nil {
+			return nil, err
+		}
+		defer release()
+		return s.httpBackend.SendRequest(ctx, name, req)
+	}
+
+	if auth != nil {
+		log.Printf("auth: sending request %s to %s with %s authentication", name, req.Target.origin(), auth.Type)
+	}
+	if req.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, req.Timeout)
+		defer cancel()
+	}
+	origin := req.Target.origin()
+	sender := func(ctx context.Context, req SendRequestInput, start time.Time) (*SendRequestResult, error) {
+		if err := s.guard.CheckRequest(ctx, scope, req); err != nil {
+			return nil, fmt.Errorf("redirect not followed: %w", err)
+		}
+		if auth != nil && strings.EqualFold(auth.Type, config.AuthTypeDigest) {
+			return s.sendDigest(ctx, name, req, start, auth) // waits for each attempt
+		}
+		release, err := s.rateLimiter.Wait(ctx, req.Target.Hostname)
+		if err != nil {
+			return nil, err
+		}
+		defer release()
+		if auth == nil {
+			return s.sendHop(ctx, name, req, start)
+		}
+		if strings.EqualFold(auth.Type, config.AuthTypeSigV4) {
+			return s.sendSigV4(ctx, name, req, start, auth, req.Target.origin() == origin)
+		}
+		return sendNTLMHandshake(ctx, req, start, auth)
+	}
+	if req.FollowRedirects {
+		return FollowRedirects(ctx, req, time.Now(), 10, sender)
+	}
+	return sender(ctx, req, time.Now())
+}
+
+// sendHop sends one request of a redirect chain through the HTTP backend.
+func (s *Server) sendHop(ctx context.Context, name string, req SendRequestInput, start time.Time) (*SendRequestResult, error) {
+	req.FollowRedirects = false // handled by sendRequest so each hop is scope checked
+	result, err := s.httpBackend.SendRequest(ctx, name, req)
+	if err != nil {
+		return nil, err
+	}
+	result.Duration = time.Since(start)
+	return result, nil
+}
+
+// sendDigest sends req through the HTTP backend, authenticating up front when a Digest
+// challenge for the user and origin is cached, and answers a new challenge (first
+// contact, stale or rotated nonce) with one retry.
+func (s *Server) sendDigest(ctx context.Context, name string, req SendRequestInput, start time.Time, auth *config.AuthProfile) (*SendRequestResult, error) {
+	req.FollowRedirects = false // handled by sendRequest so each hop is authenticated
+	key := auth.Username + "@" + req.Target.origin()
+	headers, body := splitHeadersBody(req.RawRequest)
+	requestLine, _, _ := strings.Cut(string(headers), "\r\n")
+	method, path, query, _ := parseRequestLine(requestLine)
+	uri := path
+	if query != "" {
+		uri += "?" + query
+	}
+
+	send := func(authorization string) (*SendRequestResult, error) {
+		r := req
+		if authorization != "" {
+			r.RawRequest = append(setHeader(headers, "Authorization", authorization), body...)
+		}
+		release, err := s.rateLimiter.Wait(ctx, req.Target.Hostname)
+		if err != nil {
+			return nil, err
+		}
+		defer release()
+		return s.httpBackend.SendRequest(ctx, name, r)
+	}
+
+	result, err := send(s.digest.authorize(key, auth.Username, auth.Secret(), method, uri, body))
+	if err != nil {
+		return nil, err
+	}
+	if code, _ := parseResponseStatus(result.Headers); code == http.StatusUnauthorized {
+		challenge := bestDigestChallenge(parseHeadersToMap(string(result.Headers))["Www-Authenticate"])
+		if challenge != nil {
+			s.digest.update(key, challenge)
+			if result, err = send(s.digest.authorize(key, auth.Username, auth.Secret(), method, uri, body)); err != nil {
+				return nil, err
+			}
+		}
+	}
+	result.Duration = time.Since(start)
+	return result, nil
+}
+
+// sendSigV4 signs req (after all edits have been applied) and sends it through the
+// HTTP backend. Redirects to another origin are sent unsigned.
+func (s *Server) sendSigV4(ctx context.Context, name string, req SendRequestInput, start time.Time, auth *config.AuthProfile, sign bool) (*SendRequestResult, error) {
+	req.FollowRedirects = false // handled by sendRequest so each same-origin hop is re-signed
+	if sign {
+		req.RawRequest = signSigV4(req.RawRequest, req.Target, auth, time.Now())
+	}
+	result, err := s.httpBackend.SendRequest(ctx, name, req)
+	if err != nil {
+		return nil, err
+	}
+	result.Duration = time.Since(start)
+	return result, nil
+}
+
+// sendNTLMHandshake sends req with an NTLM negotiate message, answers the server's
+// challenge on the same connection, and returns the final response. A response that
+// does not carry a challenge (no auth required, or the scheme is not offered) is
+// returned as-is.
+func sendNTLMHandshake(ctx context.Context, req SendRequestInput, start time.Time, auth *config.AuthProfile) (*SendRequestResult, error) {
+	scheme := "NTLM"
+	if strings.EqualFold(auth.Type, config.AuthTypeNegotiate) {
+		scheme = "Negotiate"
+	}
+
+	conn, err := dialTarget(ctx, req.Target)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = conn.Close() }()
+	stop := context.AfterFunc(ctx, func() { _ = conn.Close() })
+	defer stop()
+
+	headers, body := splitHeadersBody(req.RawRequest)
+	method := extractMethod(req.RawRequest)
+	headers = setHeader(headers, "Connection", "keep-alive")
+	br := bufio.NewReader(conn)
+
+	roundTrip := func(token []byte) (*http.Response, []byte, error) {
+		h := setHeader(headers, "Authorization", scheme+" "+base64.StdEncoding.EncodeToString(token))
+		if _, err := conn.Write(append(h, body...)); err != nil {
+			return nil, nil, wrapConnErr(ctx, "send request", err)
+		}
+		resp, err := http.ReadResponse(br, &http.Request{Method: method})
+		if err != nil {
+			return nil, nil, wrapConnErr(ctx, "read response", err)
+		}
+		respBody, err := io.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+		if err != nil {
+			return nil, nil, wrapConnErr(ctx, "read response body", err)
+		}
+		return resp, respBody, nil
+	}
+
+	resp, respBody, err := roundTrip(ntlmNegotiateMessage())
+	if err != nil {
+		return nil, err
+	}
+	token := authChallenge(resp, scheme)
+	if resp.StatusCode != http.StatusUnauthorized || token == nil {
+		return dumpAuthResponse(resp, respBody, start)
+	}
+	challenge, err := parseNTLMChallenge(token)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Close {
+		return nil, errors.New("server closed the connection after the NTLM challenge; the handshake needs a persistent connection")
+	}
+
+	user, domain := auth.Credentials()
+	resp, respBody, err = roundTrip(ntlmAuthenticateMessage(challenge, user, domain, auth.Secret(), "", newClientChallenge(), time.Now()))
+	if err != nil {
+		return nil, err
+	}
+	return dumpAuthResponse(resp, respBody, start)
+}
+
+// authChallenge returns the decoded token of the first WWW-Authenticate challenge for
+// scheme, or nil if the response carries none.
+func authChallenge(resp *http.Response, scheme string) []byte {
+	for _, v := range resp.Header.Values("WWW-Authenticate") {
+		name, param, _ := strings.Cut(strings.TrimSpace(v), " ")
+		if !strings.EqualFold(name, scheme) || param == "" {
+			continue
+		}
+		if token, err := base64.StdEncoding.DecodeString(strings.TrimSpace(param)); err == nil {
+			return token
+		}
+	}
+	return nil
+}
+
+// dialTarget opens a TCP connection to target, negotiating TLS (without verification,
+// as for other sends) when it uses HTTPS.
+func dialTarget(ctx context.Context, target Target) (net.Conn, error) {
+	addr := net.JoinHostPort(target.Hostname, strconv.Itoa(target.Port))
+	conn, err := dialContext(ctx, "tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("dial %s: %w", addr, err)
+	}
+	if !target.UsesHTTPS {
+		return conn, nil
+	}
+	tlsConn := tls.Client(conn, &tls.Config{
+		ServerName:         target.Hostname,
+		InsecureSkipVerify: true,
+		NextProtos:         []string{"http/1.1"},
+	})
+	if err := tlsConn.HandshakeContext(ctx); err != nil {
+		_ = conn.Close()
+		return nil, fmt.Errorf("TLS handshake with %s: %w", addr, err)
+	}
+	return tlsConn, nil
+}
+
+// wrapConnErr reports a context error in place of the connection error it caused.
+func wrapConnErr(ctx context.Context, op string, err error) error {
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		err = ctxErr
+	}
+	return fmt.Errorf("%s: %w", op, err)
+}
+
+func dumpAuthResponse(resp *http.Response, body []byte, start time.Time) (*SendRequestResult, error) {
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	dump, err := httputil.DumpResponse(resp, true)
+	if err != nil {
+		return nil, fmt.Errorf("dump response: %w", err)
+	}
+	headers, respBody := splitHeadersBody(dump)
+	return &SendRequestResult{
+		Headers:  headers,
+		Body:     respBody,
+		Duration: time.Since(start),
+	}, nil
+}
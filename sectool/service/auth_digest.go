@@ -0,0 +1,238 @@
+package service
+
+import (
+	"crypto/md5"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// digestChallenge is a parsed Digest WWW-Authenticate challenge (RFC 7616).
+type digestChallenge struct {
+	realm     string
+	nonce     string
+	opaque    string
+	algorithm string
+	qop       []string
+	stale     bool
+	userhash  bool
+}
+
+// digestAlgorithms lists supported algorithms, strongest first; each also has a
+// "-sess" variant.
+var digestAlgorithms = []string{"SHA-512-256", "SHA-256", "MD5"}
+
+func digestHash(algorithm string) (newHash func() hash.Hash, sess bool, ok bool) {
+	base, sess := strings.CutSuffix(strings.ToUpper(algorithm), "-SESS")
+	switch base {
+	case "MD5":
+		return md5.New, sess, true
+	case "SHA-256":
+		return sha256.New, sess, true
+	case "SHA-512-256":
+		return sha512.New512_256, sess, true
+	}
+	return nil, false, false
+}
+
+// bestDigestChallenge returns the supported Digest challenge with the strongest
+// algorithm from WWW-Authenticate header values, or nil if none is offered.
+func bestDigestChallenge(values []string) *digestChallenge {
+	var best *digestChallenge
+	rank := func(c *digestChallenge) int {
+		base, _ := strings.CutSuffix(strings.ToUpper(c.algorithm), "-SESS")
+		return len(digestAlgorithms) - slices.Index(digestAlgorithms, base)
+	}
+	for _, c := range parseDigestChallenges(values) {
+		if _, _, ok := digestHash(c.algorithm); !ok || c.nonce == "" {
+			continue
+		}
+		if best == nil || rank(c) > rank(best) {
+			best = c
+		}
+	}
+	return best
+}
+
+// parseDigestChallenges extracts the Digest challenges from WWW-Authenticate values,
+// each of which may hold several comma-separated challenges of any scheme.
+func parseDigestChallenges(values []string) []*digestChallenge {
+	var out []*digestChallenge
+	for _, s := range values {
+		var cur *digestChallenge
+		for {
+			s = strings.TrimLeft(s, " \t,")
+			if s == "" {
+				break
+			}
+			end := strings.IndexAny(s, " \t,=")
+			if end < 0 {
+				end = len(s)
+			}
+			name, rest := s[:end], strings.TrimLeft(s[end:], " \t")
+			if !strings.HasPrefix(rest, "=") { // a scheme name starts the next challenge
+				cur = nil
+				if strings.EqualFold(name, "Digest") {
+					cur = &digestChallenge{algorithm: "MD5"}
+					out = append(out, cur)
+				}
+				s = rest
+				continue
+			}
+
+			var value string
+			value, s = parseAuthParamValue(strings.TrimLeft(rest[1:], " \t"))
+			if cur == nil {
+				continue
+			}
+			switch strings.ToLower(name) {
+			case "realm":
+				cur.realm = value
+			case "nonce":
+				cur.nonce = value
+			case "opaque":
+				cur.opaque = value
+			case "algorithm":
+				cur.algorithm = value
+			case "qop":
+				for _, q := range strings.Split(value, ",") {
+					cur.qop = append(cur.qop, strings.ToLower(strings.TrimSpace(q)))
+				}
+			case "stale":
+				cur.stale = strings.EqualFold(value, "true")
+			case "userhash":
+				cur.userhash = strings.EqualFold(value, "true")
+			}
+		}
+	}
+	return out
+}
+
+// parseAuthParamValue reads a token or quoted-string value, returning it and the rest.
+func parseAuthParamValue(s string) (string, string) {
+	if !strings.HasPrefix(s, `"`) {
+		end := strings.IndexByte(s, ',')
+		if end < 0 {
+			return strings.TrimSpace(s), ""
+		}
+		return strings.TrimSpace(s[:end]), s[end:]
+	}
+	var b strings.Builder
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			if i+1 < len(s) {
+				i++
+				b.WriteByte(s[i])
+			}
+		case '"':
+			return b.String(), s[i+1:]
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+	return b.String(), "" // unterminated
+}
+
+// authorization computes the Authorization header value for a request. nc is the
+// nonce count for this use of the nonce and cnonce the client nonce.
+func (c *digestChallenge) authorization(user, password, method, uri string, body []byte, nc uint32, cnonce string) string {
+	newHash, sess, _ := digestHash(c.algorithm)
+	h := func(parts ...string) string {
+		hh := newHash()
+		hh.Write([]byte(strings.Join(parts, ":")))
+		return hex.EncodeToString(hh.Sum(nil))
+	}
+
+	var qop string
+	if slices.Contains(c.qop, "auth") {
+		qop = "auth"
+	} else if slices.Contains(c.qop, "auth-int") {
+		qop = "auth-int"
+	}
+
+	ha1 := h(user, c.realm, password)
+	if sess {
+		ha1 = h(ha1, c.nonce, cnonce)
+	}
+	ha2 := h(method, uri)
+	if qop == "auth-int" {
+		ha2 = h(method, uri, h(string(body)))
+	}
+
+	username := user
+	if c.userhash {
+		username = h(user, c.realm)
+	}
+	params := []string{
+		"username=" + digestQuote(username),
+		"realm=" + digestQuote(c.realm),
+		"uri=" + digestQuote(uri),
+		"algorithm=" + c.algorithm,
+		"nonce=" + digestQuote(c.nonce),
+	}
+	if qop != "" {
+		ncValue := fmt.Sprintf("%08x", nc)
+		params = append(params, "nc="+ncValue, "cnonce="+digestQuote(cnonce), "qop="+qop,
+			"response="+digestQuote(h(ha1, c.nonce, ncValue, cnonce, qop, ha2)))
+	} else { // RFC 2069 compatibility
+		params = append(params, "response="+digestQuote(h(ha1, c.nonce, ha2)))
+	}
+	if c.opaque != "" {
+		params = append(params, "opaque="+digestQuote(c.opaque))
+	}
+	if c.userhash {
+		params = append(params, "userhash=true")
+	}
+	return "Digest " + strings.Join(params, ", ")
+}
+
+func digestQuote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+// digestSessions remembers the last Digest challenge per user and origin so later
+// sends authenticate up front, reusing the nonce with an incrementing nonce count
+// until the server issues a new one. Thread-safe.
+type digestSessions struct {
+	mu       sync.Mutex
+	sessions map[string]*digestSession
+}
+
+type digestSession struct {
+	challenge *digestChallenge
+	nc        uint32
+}
+
+func newDigestSessions() *digestSessions {
+	return &digestSessions{sessions: make(map[string]*digestSession)}
+}
+
+// update replaces the challenge for key, resetting the nonce count.
+func (d *digestSessions) update(key string, c *digestChallenge) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.sessions[key] = &digestSession{challenge: c}
+}
+
+// authorize returns the Authorization value for the next use of key's nonce, or ""
+// when no challenge is known yet.
+func (d *digestSessions) authorize(key, user, password, method, uri string, body []byte) string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	sess, ok := d.sessions[key]
+	if !ok {
+		return ""
+	}
+	sess.nc++
+	var cnonce [16]byte
+	_, _ = rand.Read(cnonce[:])
+	return sess.challenge.authorization(user, password, method, uri, body, sess.nc, hex.EncodeToString(cnonce[:]))
+}
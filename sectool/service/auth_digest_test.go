@@ -0,0 +1,248 @@
+package service
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/go-harden/llm-security-toolbox/sectool/config"
+)
+
+func TestDigestAuthorization(t *testing.T) {
+	t.Parallel()
+
+	// RFC 7616 section 3.9.1 examples.
+	c := &digestChallenge{
+		realm:  "http-auth@example.org",
+		nonce:  "7ypf/xlj9XXwfDPEoM4URrv/xwf94BcCAzFZH4GiTo0v",
+		opaque: "FQhe/qaU925kfnzjCev0ciny7QMkPqMAFRtzCUYo5tdS",
+		qop:    []string{"auth", "auth-int"},
+	}
+	const cnonce = "f2/wE4q74E6zIJEtWaHKaf5wv/H5QzzpXusqGemxURZJ"
+
+	t.Run("md5", func(t *testing.T) {
+		c := *c
+		c.algorithm = "MD5"
+		got := c.authorization("Mufasa", "Circle of Life", "GET", "/dir/index.html", nil, 1, cnonce)
+		assert.Contains(t, got, `response="8ca523f5e9506fed4657c9700eebdbec"`)
+		assert.Contains(t, got, "nc=00000001")
+		assert.Contains(t, got, "qop=auth,")
+		assert.Contains(t, got, `opaque="FQhe/qaU925kfnzjCev0ciny7QMkPqMAFRtzCUYo5tdS"`)
+	})
+
+	t.Run("sha256", func(t *testing.T) {
+		c := *c
+		c.algorithm = "SHA-256"
+		got := c.authorization("Mufasa", "Circle of Life", "GET", "/dir/index.html", nil, 1, cnonce)
+		assert.Contains(t, got, `response="753927fa0e85d155564e2e272a28d1802ca10daf4496794697cf8db5856cb6c1"`)
+	})
+
+	t.Run("rfc2617", func(t *testing.T) {
+		c := &digestChallenge{realm: "testrealm@host.com", nonce: "dcd98b7102dd2f0e8b11d0f600bfb0c093", algorithm: "MD5", qop: []string{"auth"}}
+		got := c.authorization("Mufasa", "Circle Of Life", "GET", "/dir/index.html", nil, 1, "0a4f113b")
+		assert.Contains(t, got, `response="6629fae49393a05397450978507c4ef1"`)
+	})
+}
+
+func TestBestDigestChallenge(t *testing.T) {
+	t.Parallel()
+
+	t.Run("strongest_algorithm", func(t *testing.T) {
+		c := bestDigestChallenge([]string{
+			`Digest realm="r", qop="auth, auth-int", algorithm=MD5, nonce="n1", opaque="o"`,
+			`Digest realm="r", qop="auth", algorithm=SHA-256, nonce="n2", stale=TRUE`,
+		})
+		require.NotNil(t, c)
+		assert.Equal(t, "SHA-256", c.algorithm)
+		assert.Equal(t, "n2", c.nonce)
+		assert.True(t, c.stale)
+	})
+
+	t.Run("mixed_schemes_one_header", func(t *testing.T) {
+		c := bestDigestChallenge([]string{`Basic realm="x", Digest realm="a \"quoted\" realm", nonce="n", qop="auth"`})
+		require.NotNil(t, c)
+		assert.Equal(t, `a "quoted" realm`, c.realm)
+		assert.Equal(t, "MD5", c.algorithm)
+		assert.Equal(t, []string{"auth"}, c.qop)
+	})
+
+	t.Run("none", func(t *testing.T) {
+		assert.Nil(t, bestDigestChallenge([]string{`Basic realm="x"`, `Negotiate`}))
+		assert.Nil(t, bestDigestChallenge([]string{`Digest realm="x", nonce="n", algorithm=SHA-1`}))
+	})
+}
+
+// newDigestTestServer requires MD5 Digest auth, rotating the nonce (as stale) after
+// maxUses requests and rejecting replayed nonce counts.
+func newDigestTestServer(t *testing.T, user, password string, maxUses int) (*httptest.Server, *int) {
+	t.Helper()
+
+	var mu sync.Mutex
+	nonce, uses, lastNC, challenges := "nonce-0", 0, uint64(0), 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		challenge := func(stale bool) {
+			challenges++
+			w.Header().Set("WWW-Authenticate", `Digest realm="test", qop="auth", nonce="`+nonce+`", opaque="op", stale=`+strconv.FormatBool(stale))
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+		params := parseTestDigestParams(r.Header.Get("Authorization"))
+		if params == nil {
+			challenge(false)
+			return
+		}
+		nc, _ := strconv.ParseUint(params["nc"], 16, 32)
+		if params["nonce"] != nonce || uses >= maxUses {
+			nonce, uses, lastNC = "nonce-"+strconv.Itoa(challenges), 0, 0
+			challenge(true)
+			return
+		}
+		c := &digestChallenge{realm: "test", nonce: nonce, opaque: "op", algorithm: "MD5", qop: []string{"auth"}}
+		want := parseTestDigestParams(c.authorization(user, password, r.Method, r.URL.RequestURI(), nil, uint32(nc), params["cnonce"]))
+		if nc <= lastNC || params["response"] != want["response"] || params["uri"] != r.URL.RequestURI() {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		uses++
+		lastNC = nc
+		_, _ = w.Write([]byte("ok " + params["nc"]))
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &challenges
+}
+
+func parseTestDigestParams(header string) map[string]string {
+	rest, ok := strings.CutPrefix(header, "Digest ")
+	if !ok {
+		return nil
+	}
+	params := make(map[string]string)
+	for rest != "" {
+		name, after, ok := strings.Cut(strings.TrimLeft(rest, " ,"), "=")
+		if !ok {
+			break
+		}
+		params[name], rest = parseAuthParamValue(after)
+	}
+	return params
+}
+
+func TestSendDigest(t *testing.T) {
+	t.Parallel()
+
+	backend, err := NewGoProxyBackend(0, t.TempDir())
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = backend.Close() })
+	s := &Server{httpBackend: backend, digest: newDigestSessions()}
+
+	target, challenges := newDigestTestServer(t, "alice", "s3cret", 2)
+	auth := &config.AuthProfile{Type: config.AuthTypeDigest, Username: "alice", Password: "s3cret"}
+	host, portStr, _ := strings.Cut(strings.TrimPrefix(target.URL, "http://"), ":")
+	port, _ := strconv.Atoi(portStr)
+	send := func(path string) (int, string) {
+		result, err := s.sendRequest(t.Context(), "test", SendRequestInput{
+			RawRequest: []byte("GET " + path + " HTTP/1.1\r\nHost: " + host + "\r\n\r\n"),
+			Target:     Target{Hostname: host, Port: port},
+		}, auth)
+		require.NoError(t, err)
+		code, _ := parseResponseStatus(result.Headers)
+		return code, string(result.Body)
+	}
+
+	code, body := send("/a?x=1")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "ok 00000001", body)
+	assert.Equal(t, 1, *challenges)
+
+	// The cached nonce is reused with the next nonce count, without a new challenge.
+	code, body = send("/b")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "ok 00000002", body)
+	assert.Equal(t, 1, *challenges)
+
+	// The server marks the nonce stale; the send retries once with the new nonce.
+	code, body = send("/c")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "ok 00000001", body)
+	assert.Equal(t, 2, *challenges)
+
+	t.Run("wrong_password", func(t *testing.T) {
+		wrong := &config.AuthProfile{Type: config.AuthTypeDigest, Username: "mallory", Password: "nope"}
+		result, err := s.sendRequest(t.Context(), "test", SendRequestInput{
+			RawRequest: []byte("GET / HTTP/1.1\r\nHost: " + host + "\r\n\r\n"),
+			Target:     Target{Hostname: host, Port: port},
+		}, wrong)
+		require.NoError(t, err)
+		code, _ := parseResponseStatus(result.Headers)
+		assert.Equal(t, http.StatusUnauthorized, code)
+	})
+}
+
+func TestSendRequestRateLimit(t *testing.T) {
+	t.Parallel()
+
+	backend, err := NewGoProxyBackend(0, t.TempDir())
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = backend.Close() })
+
+	hostPort := func(u string) (string, int) {
+		host, portStr, _ := strings.Cut(strings.TrimPrefix(u, "http://"), ":")
+		port, _ := strconv.Atoi(portStr)
+		return host, port
+	}
+
+	t.Run("redirect_hops", func(t *testing.T) {
+		s := &Server{httpBackend: backend, digest: newDigestSessions(), rateLimiter: newRateLimiter(rateLimits{})}
+		final := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte("done"))
+		}))
+		t.Cleanup(final.Close)
+		_, finalPort := hostPort(final.URL)
+		start := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.Redirect(w, r, "http://localhost:"+strconv.Itoa(finalPort)+"/end", http.StatusFound)
+		}))
+		t.Cleanup(start.Close)
+		host, port := hostPort(start.URL)
+
+		ctx := withRunRate(t.Context(), 1000)
+		result, err := s.sendRequest(ctx, "test", SendRequestInput{
+			RawRequest:      []byte("GET / HTTP/1.1\r\nHost: " + host + "\r\n\r\n"),
+			Target:          Target{Hostname: host, Port: port},
+			FollowRedirects: true,
+		}, nil)
+		require.NoError(t, err)
+		assert.Equal(t, "done", string(result.Body))
+
+		// Each hop waited on the limiter for its own host.
+		run := ctx.Value(runRateKey{}).(*runRate)
+		assert.Contains(t, run.hosts, host)
+		assert.Contains(t, run.hosts, "localhost")
+	})
+
+	t.Run("digest_retry", func(t *testing.T) {
+		s := &Server{httpBackend: backend, digest: newDigestSessions(), rateLimiter: newRateLimiter(rateLimits{HostRPS: 10})}
+		target, challenges := newDigestTestServer(t, "alice", "s3cret", 2)
+		host, port := hostPort(target.URL)
+
+		began := time.Now()
+		result, err := s.sendRequest(t.Context(), "test", SendRequestInput{
+			RawRequest: []byte("GET / HTTP/1.1\r\nHost: " + host + "\r\n\r\n"),
+			Target:     Target{Hostname: host, Port: port},
+		}, &config.AuthProfile{Type: config.AuthTypeDigest, Username: "alice", Password: "s3cret"})
+		require.NoError(t, err)
+		code, _ := parseResponseStatus(result.Headers)
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, 1, *challenges)
+		// The retry answering the challenge waited for a second rate token.
+		assert.GreaterOrEqual(t, time.Since(began), 90*time.Millisecond)
+	})
+}
@@ -0,0 +1,222 @@
+package service
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/md5"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"math/bits"
+	"strings"
+	"time"
+	"unicode/utf16"
+)
+
+// NTLM negotiate flags (MS-NLMP 2.2.2.5) used by the handshake.
+const (
+	ntlmFlagUnicode         = 0x00000001
+	ntlmFlagOEM             = 0x00000002
+	ntlmFlagRequestTarget   = 0x00000004
+	ntlmFlagNTLM            = 0x00000200
+	ntlmFlagAlwaysSign      = 0x00008000
+	ntlmFlagExtendedSession = 0x00080000
+	ntlmFlagTargetInfo      = 0x00800000
+	ntlmFlagVersion         = 0x02000000
+	ntlmFlag128             = 0x20000000
+	ntlmFlagKeyExchange     = 0x40000000
+	ntlmFlag56              = 0x80000000
+)
+
+const (
+	ntlmNegotiateFlags = ntlmFlagUnicode | ntlmFlagOEM | ntlmFlagRequestTarget | ntlmFlagNTLM |
+		ntlmFlagAlwaysSign | ntlmFlagExtendedSession | ntlmFlagTargetInfo | ntlmFlag128 | ntlmFlag56
+
+	ntlmAvEOL       = 0
+	ntlmAvTimestamp = 7
+
+	// ntlmEpochDelta is the number of 100ns intervals between 1601-01-01 and 1970-01-01.
+	ntlmEpochDelta = 116444736000000000
+)
+
+var ntlmSignature = []byte("NTLMSSP\x00")
+
+// ntlmChallenge holds the fields of a CHALLENGE_MESSAGE needed to answer it.
+type ntlmChallenge struct {
+	flags           uint32
+	serverChallenge [8]byte
+	targetInfo      []byte
+}
+
+// ntlmNegotiateMessage builds the NEGOTIATE_MESSAGE (type 1) opening a handshake.
+func ntlmNegotiateMessage() []byte {
+	msg := make([]byte, 32)
+	copy(msg, ntlmSignature)
+	binary.LittleEndian.PutUint32(msg[8:], 1)
+	binary.LittleEndian.PutUint32(msg[12:], ntlmNegotiateFlags)
+	return msg // domain and workstation fields left empty
+}
+
+// parseNTLMChallenge parses a CHALLENGE_MESSAGE (type 2). The message may be wrapped
+// in a SPNEGO token, in which case the embedded NTLMSSP message is used.
+func parseNTLMChallenge(token []byte) (*ntlmChallenge, error) {
+	idx := bytes.Index(token, ntlmSignature)
+	if idx < 0 {
+		return nil, errors.New("not an NTLM challenge (Kerberos-only Negotiate is not supported)")
+	}
+	msg := token[idx:]
+	if len(msg) < 32 || binary.LittleEndian.Uint32(msg[8:]) != 2 {
+		return nil, errors.New("malformed NTLM challenge message")
+	}
+
+	c := &ntlmChallenge{flags: binary.LittleEndian.Uint32(msg[20:])}
+	copy(c.serverChallenge[:], msg[24:32])
+	if len(msg) >= 48 {
+		length := int(binary.LittleEndian.Uint16(msg[40:]))
+		offset := int(binary.LittleEndian.Uint32(msg[44:]))
+		if offset+length > len(msg) {
+			return nil, errors.New("malformed NTLM challenge: target info out of range")
+		}
+		c.targetInfo = msg[offset : offset+length]
+	}
+	return c, nil
+}
+
+// ntlmAuthenticateMessage builds the AUTHENTICATE_MESSAGE (type 3) answering c with an
+// NTLMv2 response. clientChallenge and now are parameters for reproducible tests.
+func ntlmAuthenticateMessage(c *ntlmChallenge, user, domain, password, workstation string, clientChallenge [8]byte, now time.Time) []byte {
+	timestamp := ntlmTimestamp(c.targetInfo)
+	if timestamp == nil {
+		timestamp = binary.LittleEndian.AppendUint64(nil, uint64(now.UnixNano()/100+ntlmEpochDelta))
+	}
+	ntResponse := ntlmV2Response(ntlmV2Hash(user, domain, password), c.serverChallenge, clientChallenge, timestamp, c.targetInfo)
+	lmResponse := make([]byte, 24) // zeroed LMv2, as required when the server sends a timestamp
+
+	encode := func(s string) []byte {
+		if c.flags&ntlmFlagUnicode != 0 {
+			return utf16LE(s)
+		}
+		return []byte(s)
+	}
+	// Session security is not negotiated: no key exchange, and no version field.
+	flags := c.flags &^ (ntlmFlagKeyExchange | ntlmFlagVersion)
+
+	const headerLen = 64
+	payload := [][]byte{encode(domain), encode(user), encode(workstation), lmResponse, ntResponse, nil}
+	fieldOffsets := []int{28, 36, 44, 12, 20, 52} // domain, user, workstation, lm, nt, session key
+
+	msg := make([]byte, headerLen)
+	copy(msg, ntlmSignature)
+	binary.LittleEndian.PutUint32(msg[8:], 3)
+	binary.LittleEndian.PutUint32(msg[60:], flags)
+	for i, field := range payload {
+		at := fieldOffsets[i]
+		binary.LittleEndian.PutUint16(msg[at:], uint16(len(field)))
+		binary.LittleEndian.PutUint16(msg[at+2:], uint16(len(field)))
+		binary.LittleEndian.PutUint32(msg[at+4:], uint32(len(msg)))
+		msg = append(msg, field...)
+	}
+	return msg
+}
+
+// ntlmV2Hash computes NTOWFv2: HMAC-MD5 keyed by the NT hash over the upper-cased user
+// and the domain.
+func ntlmV2Hash(user, domain, password string) []byte {
+	mac := hmac.New(md5.New, md4(utf16LE(password)))
+	mac.Write(utf16LE(strings.ToUpper(user) + domain))
+	return mac.Sum(nil)
+}
+
+// ntlmV2Response computes NTProofStr followed by the client blob it covers.
+func ntlmV2Response(v2Hash []byte, serverChallenge, clientChallenge [8]byte, timestamp, targetInfo []byte) []byte {
+	blob := []byte{1, 1, 0, 0, 0, 0, 0, 0}
+	blob = append(blob, timestamp...)
+	blob = append(blob, clientChallenge[:]...)
+	blob = append(blob, 0, 0, 0, 0)
+	blob = append(blob, targetInfo...)
+	blob = append(blob, 0, 0, 0, 0)
+
+	mac := hmac.New(md5.New, v2Hash)
+	mac.Write(serverChallenge[:])
+	mac.Write(blob)
+	return append(mac.Sum(nil), blob...)
+}
+
+// ntlmTimestamp returns the MsvAvTimestamp value from target info, if present.
+func ntlmTimestamp(targetInfo []byte) []byte {
+	for len(targetInfo) >= 4 {
+		id := binary.LittleEndian.Uint16(targetInfo)
+		length := int(binary.LittleEndian.Uint16(targetInfo[2:]))
+		if id == ntlmAvEOL || 4+length > len(targetInfo) {
+			return nil
+		}
+		if id == ntlmAvTimestamp && length == 8 {
+			return targetInfo[4:12]
+		}
+		targetInfo = targetInfo[4+length:]
+	}
+	return nil
+}
+
+// newClientChallenge returns a random 8-byte NTLM client challenge.
+func newClientChallenge() [8]byte {
+	var c [8]byte
+	_, _ = rand.Read(c[:])
+	return c
+}
+
+func utf16LE(s string) []byte {
+	units := utf16.Encode([]rune(s))
+	b := make([]byte, 2*len(units))
+	for i, u := range units {
+		binary.LittleEndian.PutUint16(b[2*i:], u)
+	}
+	return b
+}
+
+var (
+	md4Shifts      = [3][4]int{{3, 7, 11, 19}, {3, 5, 9, 13}, {3, 9, 11, 15}}
+	md4Round3Order = [16]int{0, 8, 4, 12, 2, 10, 6, 14, 1, 9, 5, 13, 3, 11, 7, 15}
+)
+
+// md4 implements RFC 1320, which NTLM requires for the NT hash and the standard
+// library does not provide.
+func md4(data []byte) []byte {
+	msg := append(bytes.Clone(data), 0x80)
+	for len(msg)%64 != 56 {
+		msg = append(msg, 0)
+	}
+	msg = binary.LittleEndian.AppendUint64(msg, uint64(len(data))*8)
+
+	state := [4]uint32{0x67452301, 0xefcdab89, 0x98badcfe, 0x10325476}
+	var x [16]uint32
+	for ; len(msg) > 0; msg = msg[64:] {
+		for i := range x {
+			x[i] = binary.LittleEndian.Uint32(msg[4*i:])
+		}
+		a, b, c, d := state[0], state[1], state[2], state[3]
+		// Each step updates a; rotating the registers makes the next step update d.
+		for i := 0; i < 16; i++ {
+			f := (b & c) | (^b & d)
+			a, b, c, d = d, bits.RotateLeft32(a+f+x[i], md4Shifts[0][i%4]), b, c
+		}
+		for i := 0; i < 16; i++ {
+			g := (b & c) | (b & d) | (c & d)
+			a, b, c, d = d, bits.RotateLeft32(a+g+x[(i%4)*4+i/4]+0x5a827999, md4Shifts[1][i%4]), b, c
+		}
+		for i := 0; i < 16; i++ {
+			h := b ^ c ^ d
+			a, b, c, d = d, bits.RotateLeft32(a+h+x[md4Round3Order[i]]+0x6ed9eba1, md4Shifts[2][i%4]), b, c
+		}
+		state[0] += a
+		state[1] += b
+		state[2] += c
+		state[3] += d
+	}
+
+	out := make([]byte, 0, 16)
+	for _, v := range state {
+		out = binary.LittleEndian.AppendUint32(out, v)
+	}
+	return out
+}
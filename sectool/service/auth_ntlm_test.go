@@ -0,0 +1,104 @@
+package service
+
+import (
+	"encoding/hex"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMD4(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]string{
+		"":               "31d6cfe0d16ae931b73c59d7e0c089c0",
+		"abc":            "a448017aaf21d8525fc10ae87aa6729d",
+		"message digest": "d9130a8164549fe818874806e1c7014b",
+		"12345678901234567890123456789012345678901234567890123456789012345678901234567890": "e33b4ddc9c38f2199c3e7b164fcc0536",
+	}
+	for in, want := range tests {
+		assert.Equal(t, want, hex.EncodeToString(md4([]byte(in))), "md4(%q)", in)
+	}
+}
+
+// MS-NLMP 4.2.4 NTLMv2 test vectors.
+func TestNTLMv2Response(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "a4f49c406510bdcab6824ee7c30fd852", hex.EncodeToString(md4(utf16LE("Password"))))
+
+	v2Hash := ntlmV2Hash("User", "Domain", "Password")
+	assert.Equal(t, "0c868a403bfd7a93a3001ef22ef02e3f", hex.EncodeToString(v2Hash))
+
+	targetInfo, _ := hex.DecodeString("02000c0044006f006d00610069006e0001000c0053006500720076006500720000000000")
+	serverChallenge := [8]byte{0x01, 0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef}
+	clientChallenge := [8]byte{0xaa, 0xaa, 0xaa, 0xaa, 0xaa, 0xaa, 0xaa, 0xaa}
+	resp := ntlmV2Response(v2Hash, serverChallenge, clientChallenge, make([]byte, 8), targetInfo)
+	assert.Equal(t, "68cd0ab851e51c96aabc927bebef6a1c", hex.EncodeToString(resp[:16]))
+}
+
+func TestNTLMMessages(t *testing.T) {
+	t.Parallel()
+
+	t.Run("negotiate", func(t *testing.T) {
+		msg := ntlmNegotiateMessage()
+		assert.Equal(t, "NTLMSSP\x00\x01\x00\x00\x00", string(msg[:12]))
+		assert.Len(t, msg, 32)
+	})
+
+	t.Run("challenge_round_trip", func(t *testing.T) {
+		challenge := testNTLMChallenge(t, []byte{7, 0, 8, 0, 1, 2, 3, 4, 5, 6, 7, 8, 0, 0, 0, 0})
+		assert.Equal(t, [8]byte{1, 2, 3, 4, 5, 6, 7, 8}, challenge.serverChallenge)
+		assert.Equal(t, []byte{1, 2, 3, 4, 5, 6, 7, 8}, ntlmTimestamp(challenge.targetInfo))
+
+		msg := ntlmAuthenticateMessage(challenge, "alice", "CORP", "pw", "", [8]byte{}, time.Time{})
+		assert.Equal(t, "NTLMSSP\x00\x03\x00\x00\x00", string(msg[:12]))
+		user, domain := testNTLMField(msg, 36), testNTLMField(msg, 28)
+		assert.Equal(t, utf16LE("alice"), user)
+		assert.Equal(t, utf16LE("CORP"), domain)
+		assert.Len(t, testNTLMField(msg, 12), 24)
+		// NTProofStr + blob header + timestamp + client challenge + reserved + target info + reserved
+		assert.Len(t, testNTLMField(msg, 20), 16+8+8+8+4+16+4)
+	})
+
+	t.Run("spnego_wrapped", func(t *testing.T) {
+		raw := testNTLMChallengeBytes(nil)
+		wrapped := append([]byte{0xa1, 0x81, 0x90, 0x30}, raw...)
+		c, err := parseNTLMChallenge(wrapped)
+		require.NoError(t, err)
+		assert.Equal(t, [8]byte{1, 2, 3, 4, 5, 6, 7, 8}, c.serverChallenge)
+	})
+
+	t.Run("not_ntlm", func(t *testing.T) {
+		_, err := parseNTLMChallenge([]byte{0x60, 0x82, 0x01})
+		assert.ErrorContains(t, err, "Kerberos")
+	})
+}
+
+func testNTLMChallengeBytes(targetInfo []byte) []byte {
+	msg := make([]byte, 48)
+	copy(msg, ntlmSignature)
+	msg[8] = 2
+	msg[20] = ntlmFlagUnicode
+	copy(msg[24:], []byte{1, 2, 3, 4, 5, 6, 7, 8})
+	msg[40] = byte(len(targetInfo))
+	msg[42] = byte(len(targetInfo))
+	msg[44] = 48
+	return append(msg, targetInfo...)
+}
+
+func testNTLMChallenge(t *testing.T, targetInfo []byte) *ntlmChallenge {
+	t.Helper()
+
+	c, err := parseNTLMChallenge(testNTLMChallengeBytes(targetInfo))
+	require.NoError(t, err)
+	return c
+}
+
+func testNTLMField(msg []byte, at int) []byte {
+	length := int(msg[at]) | int(msg[at+1])<<8
+	offset := int(msg[at+4]) | int(msg[at+5])<<8
+	return msg[offset : offset+length]
+}
@@ -0,0 +1,175 @@
+package service
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/url"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-harden/llm-security-toolbox/sectool/config"
+)
+
+const (
+	sigV4Algorithm  = "AWS4-HMAC-SHA256"
+	sigV4DateFormat = "20060102T150405Z"
+)
+
+var sigV4SpaceRe = regexp.MustCompile(`\s+`)
+
+// signSigV4 returns raw with AWS Signature Version 4 headers for auth, replacing any
+// earlier signature. It signs host, content-type and x-amz-* headers, which proxies
+// and the send backends leave untouched.
+func signSigV4(raw []byte, target Target, auth *config.AuthProfile, now time.Time) []byte {
+	headers, body := splitHeadersBody(raw)
+	for _, name := range []string{"Authorization", "X-Amz-Date", "X-Amz-Security-Token", "X-Amz-Content-Sha256"} {
+		headers = removeHeader(headers, name)
+	}
+
+	amzDate := now.UTC().Format(sigV4DateFormat)
+	payloadHash := sha256Hex(body)
+	headers = setHeader(headers, "X-Amz-Date", amzDate)
+	if auth.SessionToken != "" {
+		headers = setHeader(headers, "X-Amz-Security-Token", auth.SessionToken)
+	}
+	if strings.EqualFold(auth.Service, "s3") {
+		headers = setHeader(headers, "X-Amz-Content-Sha256", payloadHash)
+	}
+
+	requestLine, _, _ := strings.Cut(string(headers), "\r\n")
+	method, path, query, _ := parseRequestLine(requestLine)
+	signed := parseHeadersToMap(string(headers))
+	if _, ok := signed["Host"]; !ok {
+		host := target.Hostname
+		if target.Port != 0 && !(target.UsesHTTPS && target.Port == 443) && !(!target.UsesHTTPS && target.Port == 80) {
+			host += ":" + strconv.Itoa(target.Port)
+		}
+		signed["Host"] = []string{host}
+	}
+
+	var names []string
+	canonicalHeaders := make(map[string]string)
+	for name, values := range signed {
+		lower := strings.ToLower(name)
+		if lower != "host" && lower != "content-type" && !strings.HasPrefix(lower, "x-amz-") {
+			continue
+		}
+		trimmed := make([]string, len(values))
+		for i, v := range values {
+			trimmed[i] = sigV4SpaceRe.ReplaceAllString(strings.TrimSpace(v), " ")
+		}
+		names = append(names, lower)
+		canonicalHeaders[lower] = strings.Join(trimmed, ",")
+	}
+	slices.Sort(names)
+	var headerBlock strings.Builder
+	for _, name := range names {
+		headerBlock.WriteString(name + ":" + canonicalHeaders[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		method,
+		sigV4CanonicalPath(path, !strings.EqualFold(auth.Service, "s3")),
+		sigV4CanonicalQuery(query),
+		headerBlock.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	date := amzDate[:8]
+	scope := date + "/" + auth.Region + "/" + auth.Service + "/aws4_request"
+	stringToSign := sigV4Algorithm + "\n" + amzDate + "\n" + scope + "\n" + sha256Hex([]byte(canonicalRequest))
+
+	key := []byte("AWS4" + auth.Secret())
+	for _, part := range []string{date, auth.Region, auth.Service, "aws4_request"} {
+		key = hmacSHA256(key, part)
+	}
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	headers = setHeader(headers, "Authorization", sigV4Algorithm+" Credential="+auth.Username+"/"+scope+
+		", SignedHeaders="+signedHeaders+", Signature="+signature)
+	return append(headers, body...)
+}
+
+// sigV4CanonicalPath URI-encodes each path segment; every service except S3 encodes
+// the already-encoded segments a second time.
+func sigV4CanonicalPath(path string, doubleEncode bool) string {
+	if path == "" {
+		return "/"
+	}
+	segments := strings.Split(path, "/")
+	for i, seg := range segments {
+		if decoded, err := url.PathUnescape(seg); err == nil {
+			seg = decoded
+		}
+		seg = sigV4Escape(seg)
+		if doubleEncode {
+			seg = sigV4Escape(seg)
+		}
+		segments[i] = seg
+	}
+	return strings.Join(segments, "/")
+}
+
+// sigV4CanonicalQuery encodes query parameters and sorts them by name, then value.
+func sigV4CanonicalQuery(query string) string {
+	if query == "" {
+		return ""
+	}
+	var params []string
+	for _, pair := range strings.Split(query, "&") {
+		if pair == "" {
+			continue
+		}
+		k, v, _ := strings.Cut(pair, "=")
+		if decoded, err := url.QueryUnescape(k); err == nil {
+			k = decoded
+		}
+		if decoded, err := url.QueryUnescape(v); err == nil {
+			v = decoded
+		}
+		params = append(params, sigV4Escape(k)+"="+sigV4Escape(v))
+	}
+	slices.SortFunc(params, func(a, b string) int {
+		ak, av, _ := strings.Cut(a, "=")
+		bk, bv, _ := strings.Cut(b, "=")
+		if c := strings.Compare(ak, bk); c != 0 {
+			return c
+		}
+		return strings.Compare(av, bv)
+	})
+	return strings.Join(params, "&")
+}
+
+// sigV4Escape percent-encodes every byte except RFC 3986 unreserved characters.
+func sigV4Escape(s string) string {
+	const hexDigits = "0123456789ABCDEF"
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if 'A' <= c && c <= 'Z' || 'a' <= c && c <= 'z' || '0' <= c && c <= '9' || c == '-' || c == '_' || c == '.' || c == '~' {
+			b.WriteByte(c)
+			continue
+		}
+		b.WriteByte('%')
+		b.WriteByte(hexDigits[c>>4])
+		b.WriteByte(hexDigits[c&0xf])
+	}
+	return b.String()
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,111 @@
+package service
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/go-harden/llm-security-toolbox/sectool/config"
+)
+
+// AWS SigV4 test suite requests, signed for 2015-08-30T12:36:00Z.
+func TestSignSigV4(t *testing.T) {
+	t.Parallel()
+
+	auth := &config.AuthProfile{
+		Type:     config.AuthTypeSigV4,
+		Username: "AKIDEXAMPLE",
+		Password: "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY",
+		Region:   "us-east-1",
+		Service:  "service",
+	}
+	now := time.Date(2015, 8, 30, 12, 36, 0, 0, time.UTC)
+	target := Target{Hostname: "example.amazonaws.com", Port: 443, UsesHTTPS: true}
+	const credential = "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/service/aws4_request, "
+
+	tests := []struct {
+		name string
+		raw  string
+		want string
+	}{
+		{
+			name: "get_vanilla",
+			raw:  "GET / HTTP/1.1\r\nHost: example.amazonaws.com\r\n\r\n",
+			want: credential + "SignedHeaders=host;x-amz-date, Signature=5fa00fa31553b73ebf1942676e86291e8372ff2a2260956d9b8aae1d763fbf31",
+		},
+		{
+			name: "query_order_key_case",
+			raw:  "GET /?Param2=value2&Param1=value1 HTTP/1.1\r\nHost: example.amazonaws.com\r\n\r\n",
+			want: credential + "SignedHeaders=host;x-amz-date, Signature=b97d918cfa904a5beff61c982a1b6f458b799221646efd99d3219ec94cdf2500",
+		},
+		{
+			name: "host_from_target_replaces_old_signature",
+			raw:  "GET / HTTP/1.1\r\nAuthorization: AWS4-HMAC-SHA256 stale\r\nX-Amz-Date: 20000101T000000Z\r\n\r\n",
+			want: credential + "SignedHeaders=host;x-amz-date, Signature=5fa00fa31553b73ebf1942676e86291e8372ff2a2260956d9b8aae1d763fbf31",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			signed := parseHeadersToMap(string(signSigV4([]byte(tc.raw), target, auth, now)))
+			assert.Equal(t, []string{tc.want}, signed["Authorization"])
+			assert.Equal(t, []string{"20150830T123600Z"}, signed["X-Amz-Date"])
+		})
+	}
+
+	t.Run("session_token_signed", func(t *testing.T) {
+		withToken := *auth
+		withToken.SessionToken = "tok"
+		signed := parseHeadersToMap(string(signSigV4([]byte("GET / HTTP/1.1\r\nHost: example.amazonaws.com\r\n\r\n"), target, &withToken, now)))
+		assert.Equal(t, []string{"tok"}, signed["X-Amz-Security-Token"])
+		assert.Contains(t, signed["Authorization"][0], "SignedHeaders=host;x-amz-date;x-amz-security-token,")
+	})
+}
+
+func TestSigV4CanonicalPath(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "/", sigV4CanonicalPath("", true))
+	assert.Equal(t, "/my%2520bucket/a%252Fb", sigV4CanonicalPath("/my%20bucket/a%2Fb", true))
+	assert.Equal(t, "/my%20bucket/key~1", sigV4CanonicalPath("/my bucket/key~1", false))
+}
+
+func TestSendSigV4(t *testing.T) {
+	t.Parallel()
+
+	backend, err := NewGoProxyBackend(0, t.TempDir())
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = backend.Close() })
+	s := &Server{httpBackend: backend}
+
+	var got *http.Request
+	var gotBody []byte
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r
+		gotBody, _ = io.ReadAll(r.Body)
+	}))
+	t.Cleanup(target.Close)
+	host, portStr, _ := strings.Cut(strings.TrimPrefix(target.URL, "http://"), ":")
+	port, _ := strconv.Atoi(portStr)
+
+	auth := &config.AuthProfile{Type: config.AuthTypeSigV4, Username: "AKID", Password: "secret", Region: "eu-west-1", Service: "s3"}
+	body := `{"edited":true}`
+	_, err = s.sendRequest(t.Context(), "test", SendRequestInput{
+		RawRequest: []byte("PUT /bucket/key HTTP/1.1\r\nHost: " + target.Listener.Addr().String() +
+			"\r\nContent-Length: " + strconv.Itoa(len(body)) + "\r\n\r\n" + body),
+		Target: Target{Hostname: host, Port: port},
+	}, auth)
+	require.NoError(t, err)
+
+	require.NotNil(t, got)
+	assert.Equal(t, body, string(gotBody))
+	assert.Equal(t, sha256Hex(gotBody), got.Header.Get("X-Amz-Content-Sha256"))
+	assert.True(t, strings.HasPrefix(got.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=AKID/"))
+	assert.Contains(t, got.Header.Get("Authorization"), "/eu-west-1/s3/aws4_request, SignedHeaders=host;x-amz-content-sha256;x-amz-date,")
+}
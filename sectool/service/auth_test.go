@@ -0,0 +1,104 @@
+package service
+
+import (
+	"crypto/hmac"
+	"crypto/md5"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/go-harden/llm-security-toolbox/sectool/config"
+	"github.com/go-harden/llm-security-toolbox/sectool/protocol"
+)
+
+// newNTLMTestServer returns a server that requires an NTLMv2 handshake for scheme with
+// the given credentials, answering on the connection that received the challenge.
+func newNTLMTestServer(t *testing.T, scheme, user, domain, password string) *httptest.Server {
+	t.Helper()
+
+	var mu sync.Mutex
+	challenged := make(map[string]bool) // by remote address
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		name, param, _ := strings.Cut(r.Header.Get("Authorization"), " ")
+		token, _ := base64.StdEncoding.DecodeString(param)
+		if name != scheme || len(token) < 12 {
+			w.Header().Set("WWW-Authenticate", scheme)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		switch token[8] {
+		case 1:
+			challenged[r.RemoteAddr] = true
+			w.Header().Set("WWW-Authenticate", scheme+" "+base64.StdEncoding.EncodeToString(testNTLMChallengeBytes(nil)))
+			w.WriteHeader(http.StatusUnauthorized)
+		case 3:
+			nt := testNTLMField(token, 20)
+			mac := hmac.New(md5.New, ntlmV2Hash(user, domain, password))
+			mac.Write([]byte{1, 2, 3, 4, 5, 6, 7, 8})
+			mac.Write(nt[16:])
+			if !challenged[r.RemoteAddr] || !hmac.Equal(mac.Sum(nil), nt[:16]) {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			_, _ = w.Write([]byte("welcome " + r.URL.Path))
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestMCP_RequestSendAuthProfile(t *testing.T) {
+	t.Parallel()
+
+	srv, mcpClient, _, _, _ := setupMCPServerWithMock(t)
+	srv.cfg.AuthProfiles = map[string]*config.AuthProfile{
+		"corp":  {Type: config.AuthTypeNTLM, Username: `CORP\alice`, Password: "s3cret"},
+		"nego":  {Type: config.AuthTypeNegotiate, Username: "alice", Domain: "CORP", Password: "s3cret"},
+		"wrong": {Type: config.AuthTypeNTLM, Username: `CORP\alice`, Password: "nope"},
+	}
+
+	t.Run("ntlm", func(t *testing.T) {
+		target := newNTLMTestServer(t, "NTLM", "alice", "CORP", "s3cret")
+		resp := CallMCPToolJSONOK[protocol.ReplaySendResponse](t, mcpClient, "request_send", map[string]interface{}{
+			"url":          target.URL + "/intranet",
+			"auth_profile": "corp",
+		})
+		assert.Equal(t, http.StatusOK, resp.Status)
+		assert.Equal(t, "welcome /intranet", resp.RespPreview)
+	})
+
+	t.Run("negotiate", func(t *testing.T) {
+		target := newNTLMTestServer(t, "Negotiate", "alice", "CORP", "s3cret")
+		resp := CallMCPToolJSONOK[protocol.ReplaySendResponse](t, mcpClient, "request_send", map[string]interface{}{
+			"url":          target.URL + "/",
+			"auth_profile": "nego",
+		})
+		assert.Equal(t, http.StatusOK, resp.Status)
+	})
+
+	t.Run("wrong_password", func(t *testing.T) {
+		target := newNTLMTestServer(t, "NTLM", "alice", "CORP", "s3cret")
+		resp := CallMCPToolJSONOK[protocol.ReplaySendResponse](t, mcpClient, "request_send", map[string]interface{}{
+			"url":          target.URL + "/",
+			"auth_profile": "wrong",
+		})
+		assert.Equal(t, http.StatusUnauthorized, resp.Status)
+	})
+
+	t.Run("unknown_profile", func(t *testing.T) {
+		result := CallMCPTool(t, mcpClient, "request_send", map[string]interface{}{
+			"url":          "http://127.0.0.1:1/",
+			"auth_profile": "missing",
+		})
+		assert.True(t, result.IsError)
+		assert.Contains(t, ExtractMCPText(t, result), "unknown auth profile")
+	})
+}
@@ -3,6 +3,8 @@ package service
 import (
 	"context"
 	"errors"
+	"net"
+	"strconv"
 	"strings"
 	"time"
 
@@ -21,6 +23,10 @@ const (
 	RuleTypeRequestBody    = "request_body"
 	RuleTypeResponseHeader = "response_header"
 	RuleTypeResponseBody   = "response_body"
+
+	// RuleTypeSessionToken captures a token from responses and rewrites it into later
+	// requests. Supported by the built-in proxy only.
+	RuleTypeSessionToken = "session_token"
 )
 
 // isWSType returns true if the type is a WebSocket type (ws: prefix).
@@ -42,7 +48,7 @@ type HttpBackend interface {
 	// The request is raw HTTP bytes. Response is returned as headers and body.
 	SendRequest(ctx context.Context, name string, req SendRequestInput) (*SendRequestResult, error)
 
-	// ListRules returns all enabled match/replace rules managed by sectool.
+	// ListRules returns all match/replace rules managed by sectool, including disabled ones.
 	// websocket=true returns WebSocket rules, false returns HTTP rules.
 	ListRules(ctx context.Context, websocket bool) ([]protocol.RuleEntry, error)
 
@@ -58,6 +64,48 @@ type HttpBackend interface {
 	// DeleteRule removes a rule by ID or label.
 	// Searches both HTTP and WebSocket rules automatically.
 	DeleteRule(ctx context.Context, idOrLabel string) error
+
+	// MoveRule moves a rule to a 1-based position within its HTTP or WebSocket rule
+	// set; rules apply in order. Out-of-range positions are clamped.
+	MoveRule(ctx context.Context, idOrLabel string, position int) (*protocol.RuleEntry, error)
+
+	// ToggleRule enables or disables a rule by ID or label, keeping its configuration.
+	// enabled=nil flips the current state. Searches both HTTP and WebSocket rules.
+	ToggleRule(ctx context.Context, idOrLabel string, enabled *bool) (*protocol.RuleEntry, error)
+}
+
+// Capabilities gating HTTP backend tools.
+const (
+	CapabilityRules   = "rules"   // proxy_rule_* tools, on match/replace rule support
+	CapabilityScanner = "scanner" // scan_* tools, on backends implementing Scanner
+)
+
+// CapabilityReporter is optionally implemented by backends whose features depend on
+// the environment (e.g. Burp settings). Backends without it support every capability.
+type CapabilityReporter interface {
+	// HasCapability reports whether the named capability is currently available.
+	HasCapability(name string) bool
+
+	// OnCapabilitiesChanged sets a handler called asynchronously when availability changes.
+	OnCapabilitiesChanged(handler func())
+}
+
+// HealthChecker is optionally implemented by backends that depend on an external
+// service. Backends without it are considered healthy while running.
+type HealthChecker interface {
+	// CheckHealth returns an error when the backend can't currently serve requests.
+	CheckHealth(ctx context.Context) error
+}
+
+// hasCapability reports whether backend supports the named capability.
+func hasCapability(backend any, name string) bool {
+	if _, ok := backend.(Scanner); !ok && name == CapabilityScanner {
+		return false
+	}
+	if reporter, ok := backend.(CapabilityReporter); ok {
+		return reporter.HasCapability(name)
+	}
+	return true
 }
 
 // ProxyRuleInput contains parameters for creating/updating a rule.
@@ -83,12 +131,25 @@ type Target struct {
 	UsesHTTPS bool
 }
 
+// origin formats the target as scheme://host[:port], omitting the default port.
+func (t Target) origin() string {
+	scheme, defaultPort := schemeHTTP, 80
+	if t.UsesHTTPS {
+		scheme, defaultPort = schemeHTTPS, 443
+	}
+	if t.Port == 0 || t.Port == defaultPort {
+		return scheme + "://" + t.Hostname
+	}
+	return scheme + "://" + net.JoinHostPort(t.Hostname, strconv.Itoa(t.Port))
+}
+
 // SendRequestInput contains all parameters for sending a request.
 type SendRequestInput struct {
 	RawRequest      []byte
 	Target          Target
 	FollowRedirects bool
 	Timeout         time.Duration
+	KeepEncoding    bool // return the response as received, without removing chunked and content codings
 }
 
 // SendRequestResult contains the response from a sent request.
@@ -96,6 +157,74 @@ type SendRequestResult struct {
 	Headers  []byte
 	Body     []byte
 	Duration time.Duration
+	Decoded  string // codings removed from the response, e.g. "chunked, gzip"
+}
+
+// WebSocket message directions, matching the ws:to-server/ws:to-client rule types.
+const (
+	WSDirectionToServer = "to-server"
+	WSDirectionToClient = "to-client"
+)
+
+// WebSocketMessage is a captured WebSocket message in HttpBackend-agnostic form.
+type WebSocketMessage struct {
+	URL       string    // ws(s)://host/path of the connection; empty when the backend doesn't report it
+	Direction string    // WSDirectionToServer or WSDirectionToClient
+	Opcode    string    // "text" or "binary"; empty when unknown
+	Payload   []byte    // message payload after match/replace rules
+	Time      time.Time // zero when the backend doesn't report it
+}
+
+// WebSocketHistorySource is optionally implemented by HTTP backends that capture
+// proxied WebSocket messages.
+type WebSocketHistorySource interface {
+	// GetWebSocketHistory returns up to count messages starting from offset, oldest first.
+	GetWebSocketHistory(ctx context.Context, count int, offset uint32) ([]WebSocketMessage, error)
+}
+
+// HistoryImporter is optionally implemented by HTTP backends whose proxy history can
+// take entries captured elsewhere, such as a browser HAR export.
+type HistoryImporter interface {
+	// ImportHistory appends entries to proxy history in order.
+	ImportHistory(ctx context.Context, entries []ProxyEntry) error
+}
+
+// Scanner is optionally implemented by HTTP backends that run vulnerability scans.
+type Scanner interface {
+	// StartScan crawls and audits urls, staying within those URL prefixes, with
+	// optional named scan configurations. Returns the scan ID.
+	StartScan(ctx context.Context, urls, configurations []string) (string, error)
+
+	// GetScan returns the progress and issues of a scan started with StartScan.
+	GetScan(ctx context.Context, scanID string) (*ScanState, error)
+
+	// ListIssues returns up to count issues from offset that the passive and active
+	// scanners reported for the whole project, including scans started elsewhere.
+	ListIssues(ctx context.Context, count, offset int) ([]ScanIssue, error)
+}
+
+// Scan issue severities, most severe first.
+var scanSeverities = []string{"high", "medium", "low", "info", "false_positive"}
+
+// ScanState is the progress of a scan in HttpBackend-agnostic form.
+type ScanState struct {
+	ID             string
+	Status         string // e.g. queued, crawling, auditing, succeeded, failed, paused
+	Progress       int    // percent, 0 when unknown
+	Caption        string
+	Requests       int
+	ElapsedSeconds int
+	Issues         []ScanIssue
+}
+
+// ScanIssue is a scanner finding in HttpBackend-agnostic form.
+type ScanIssue struct {
+	Name        string
+	Severity    string // one of scanSeverities
+	Confidence  string // certain, firm or tentative
+	URL         string
+	Detail      string // may contain HTML
+	Remediation string // may contain HTML
 }
 
 // MaxOastEventsPerSession is the maximum number of events stored per session.
@@ -113,10 +242,11 @@ type OastBackend interface {
 	// idOrDomain accepts either the short ID or the full domain.
 	// since filters events: empty returns all, "last" returns since last poll, or an event ID.
 	// eventType filters by protocol: empty returns all, otherwise one of dns, http, smtp, ftp, ldap, smb, responder.
+	// subdomainContains keeps events whose subdomain contains it, case-insensitively (empty = all).
 	// wait specifies how long to block waiting for events (0 = return immediately).
 	// limit caps the number of events returned (0 = no limit). When used with "since last",
 	// the last position is updated to the last returned event (for pagination).
-	PollSession(ctx context.Context, idOrDomain string, since string, eventType string, wait time.Duration, limit int) (*OastPollResultInfo, error)
+	PollSession(ctx context.Context, idOrDomain string, since string, eventType string, subdomainContains string, wait time.Duration, limit int) (*OastPollResultInfo, error)
 
 	// GetEvent retrieves a single event by ID from a session.
 	// Returns the full event details without truncation.
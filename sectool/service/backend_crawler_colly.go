@@ -49,6 +49,10 @@ type CollyBackend struct {
 	// For resolving seed flows from proxy history
 	proxyFlowStore *store.FlowStore
 	httpBackend    HttpBackend
+
+	limiter *rateLimiter
+	guard   *targetGuard
+	hosts   hostOverrides
 }
 
 // crawlSession holds the state for a single crawl session.
@@ -105,11 +109,22 @@ type capturedData struct {
 	Error        error
 }
 
+// crawlBaseTransport is http.DefaultTransport dialing through dialContext, so crawls
+// honor host overrides.
+var crawlBaseTransport = func() http.RoundTripper {
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	t.DialContext = dialContext
+	return t
+}()
+
 // capturingTransport wraps http.RoundTripper to capture raw request/response bytes.
 type capturingTransport struct {
 	base         http.RoundTripper
 	session      *crawlSession
-	maxBodyBytes int // 0 or negative = unlimited
+	maxBodyBytes int           // 0 or negative = unlimited
+	limiter      *rateLimiter  // rate_limit config, on top of the crawl's own delay
+	guard        *targetGuard  // target_allowlist config
+	hosts        hostOverrides // hosts config, for base's dials
 }
 
 func (t *capturingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
@@ -118,8 +133,17 @@ func (t *capturingTransport) RoundTrip(req *http.Request) (*http.Response, error
 
 	reqBytes, _ := httputil.DumpRequestOut(req, true)
 
+	if err := t.guard.Check(req.Context(), req.URL.Host, req.URL.RequestURI()); err != nil {
+		return nil, err
+	}
+	release, err := t.limiter.Wait(req.Context(), req.URL.Hostname())
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
 	start := time.Now()
-	resp, err := t.base.RoundTrip(req)
+	resp, err := t.base.RoundTrip(req.WithContext(withHosts(req.Context(), t.hosts)))
 	duration := time.Since(start)
 
 	if err != nil {
@@ -193,7 +217,7 @@ func readBodyLimited(r io.Reader, limit int) ([]byte, int, bool) {
 }
 
 // NewCollyBackend creates a new Colly-backed CrawlerBackend.
-func NewCollyBackend(cfg config.CrawlerConfig, flowStore *store.CrawlFlowStore, proxyFlowStore *store.FlowStore, httpBackend HttpBackend) *CollyBackend {
+func NewCollyBackend(cfg config.CrawlerConfig, flowStore *store.CrawlFlowStore, proxyFlowStore *store.FlowStore, httpBackend HttpBackend, limiter *rateLimiter, guard *targetGuard, hosts hostOverrides) *CollyBackend {
 	return &CollyBackend{
 		sessions:       make(map[string]*crawlSession),
 		byLabel:        make(map[string]string),
@@ -201,6 +225,9 @@ func NewCollyBackend(cfg config.CrawlerConfig, flowStore *store.CrawlFlowStore,
 		config:         cfg,
 		proxyFlowStore: proxyFlowStore,
 		httpBackend:    httpBackend,
+		limiter:        limiter,
+		guard:          guard,
+		hosts:          hosts,
 	}
 }
 
@@ -303,9 +330,12 @@ func (b *CollyBackend) CreateSession(ctx context.Context, opts CrawlOptions) (*C
 
 	// Install capturing transport with body size limit
 	transport := &capturingTransport{
-		base:         http.DefaultTransport,
+		base:         crawlBaseTransport,
 		session:      sess,
 		maxBodyBytes: b.config.MaxResponseBodyBytes,
+		limiter:      b.limiter,
+		guard:        b.guard,
+		hosts:        b.hosts,
 	}
 	c.WithTransport(transport)
 
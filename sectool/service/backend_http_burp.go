@@ -8,6 +8,8 @@ import (
 	"log"
 	"slices"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/go-harden/llm-security-toolbox/sectool/protocol"
@@ -15,25 +17,51 @@ import (
 	"github.com/go-harden/llm-security-toolbox/sectool/service/mcp"
 )
 
+// rulesRecheckInterval is how often rule editing is re-probed after Burp rejected it.
+const rulesRecheckInterval = 30 * time.Second
+
 // BurpBackend implements HttpBackend using Burp Suite via MCP.
 type BurpBackend struct {
 	client *mcp.BurpClient
+	rest   *mcp.RESTClient // scans, which the MCP extension cannot start
+	done   chan struct{}
+
+	// rulesDisabled is set when Burp rejects config edits ('Edit config' is off in its MCP settings)
+	rulesDisabled atomic.Bool
+
+	capMu                 sync.Mutex
+	onCapabilitiesChanged func()
 }
 
-// Compile-time check that BurpBackend implements HttpBackend
-var _ HttpBackend = (*BurpBackend)(nil)
+// Compile-time checks that BurpBackend implements HttpBackend and its optional interfaces
+var (
+	_ HttpBackend        = (*BurpBackend)(nil)
+	_ CapabilityReporter = (*BurpBackend)(nil)
+	_ HealthChecker      = (*BurpBackend)(nil)
+
+	_ WebSocketHistorySource = (*BurpBackend)(nil)
+	_ Scanner                = (*BurpBackend)(nil)
+)
 
 // NewBurpBackend creates a new Burp HttpBackend with the given MCP URL.
 func NewBurpBackend(url string, opts ...mcp.Option) *BurpBackend {
 	return &BurpBackend{
 		client: mcp.New(url, opts...),
+		rest:   mcp.NewRESTClient(""),
+		done:   make(chan struct{}),
 	}
 }
 
+// SetRESTURL sets the Burp REST API used for scans (default config.DefaultBurpRESTURL).
+func (b *BurpBackend) SetRESTURL(url string) {
+	b.rest = mcp.NewRESTClient(url)
+}
+
 func (b *BurpBackend) Connect(ctx context.Context) error {
 	log.Printf("burp: connecting to MCP at %s", b.client.URL())
 	b.client.OnConnectionLost(func(err error) {
 		log.Printf("Burp MCP connection lost: %v", err)
+		b.setRulesDisabled(false) // settings may differ once Burp is back
 	})
 	if err := b.client.Connect(ctx); err != nil {
 		log.Printf("burp: connection failed: %v", err)
@@ -44,9 +72,79 @@ func (b *BurpBackend) Connect(ctx context.Context) error {
 
 func (b *BurpBackend) Close() error {
 	log.Printf("burp: closing connection")
+	b.capMu.Lock()
+	select {
+	case <-b.done:
+	default:
+		close(b.done)
+	}
+	b.capMu.Unlock()
 	return b.client.Close()
 }
 
+// CheckHealth reconnects if the health loop dropped the connection, reporting why it fails.
+func (b *BurpBackend) CheckHealth(ctx context.Context) error {
+	return b.client.Connect(ctx)
+}
+
+func (b *BurpBackend) HasCapability(name string) bool {
+	switch name {
+	case CapabilityRules:
+		return !b.rulesDisabled.Load()
+	default:
+		return true
+	}
+}
+
+func (b *BurpBackend) OnCapabilitiesChanged(handler func()) {
+	b.capMu.Lock()
+	defer b.capMu.Unlock()
+	b.onCapabilitiesChanged = handler
+}
+
+// setRulesDisabled records whether Burp accepts rule edits, notifying on change. While
+// disabled, editing is re-probed so the rule tools return once the setting is enabled.
+func (b *BurpBackend) setRulesDisabled(disabled bool) {
+	if b.rulesDisabled.Swap(disabled) == disabled {
+		return
+	}
+	log.Printf("burp: match/replace rule editing available=%v", !disabled)
+	if disabled {
+		go b.recheckRulesEditing()
+	}
+
+	b.capMu.Lock()
+	handler := b.onCapabilitiesChanged
+	b.capMu.Unlock()
+	if handler != nil {
+		go handler()
+	}
+}
+
+// recheckRulesEditing writes the unchanged HTTP rules back until Burp accepts the edit.
+func (b *BurpBackend) recheckRulesEditing() {
+	ticker := time.NewTicker(rulesRecheckInterval)
+	defer ticker.Stop()
+
+	for b.rulesDisabled.Load() {
+		select {
+		case <-b.done:
+			return
+		case <-ticker.C:
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), mcp.DefaultDialTimeout)
+		rules, err := b.client.GetMatchReplaceRules(ctx)
+		if err == nil {
+			err = b.client.SetMatchReplaceRules(ctx, rules)
+		}
+		cancel()
+		if err == nil {
+			b.setRulesDisabled(false)
+		}
+	}
+}
+
 func (b *BurpBackend) GetProxyHistory(ctx context.Context, count int, offset uint32) ([]ProxyEntry, error) {
 	log.Printf("burp: sending proxy history offset: %d", offset)
 
@@ -66,6 +164,94 @@ func (b *BurpBackend) GetProxyHistory(ctx context.Context, count int, offset uin
 	return result, nil
 }
 
+// GetWebSocketHistory returns proxied WebSocket messages. Burp does not report the
+// connection URL or message time.
+func (b *BurpBackend) GetWebSocketHistory(ctx context.Context, count int, offset uint32) ([]WebSocketMessage, error) {
+	entries, err := b.client.GetProxyWebsocketHistory(ctx, count, int(offset))
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]WebSocketMessage, len(entries))
+	for i, e := range entries {
+		direction := strings.ToLower(e.Direction)
+		switch direction {
+		case "client_to_server":
+			direction = WSDirectionToServer
+		case "server_to_client":
+			direction = WSDirectionToClient
+		}
+		result[i] = WebSocketMessage{
+			Direction: direction,
+			Opcode:    strings.ToLower(e.Opcode),
+			Payload:   []byte(e.Payload),
+		}
+	}
+	return result, nil
+}
+
+func (b *BurpBackend) StartScan(ctx context.Context, urls, configurations []string) (string, error) {
+	log.Printf("burp: starting scan of %v via %s", urls, b.rest.URL())
+	return b.rest.StartScan(ctx, mcp.ScanParams{URLs: urls, Configurations: configurations})
+}
+
+func (b *BurpBackend) GetScan(ctx context.Context, scanID string) (*ScanState, error) {
+	task, err := b.rest.GetScan(ctx, scanID, 0)
+	if err != nil {
+		return nil, err
+	}
+	state := &ScanState{
+		ID:             task.TaskID,
+		Status:         task.Status,
+		Progress:       task.Metrics.Progress,
+		Caption:        task.Metrics.Caption,
+		Requests:       task.Metrics.CrawlRequestsMade + task.Metrics.AuditRequestsMade,
+		ElapsedSeconds: task.Metrics.ElapsedSeconds,
+	}
+	for _, e := range task.IssueEvents {
+		if e.Type != "" && e.Type != "issue_found" {
+			continue
+		}
+		state.Issues = append(state.Issues, ScanIssue{
+			Name:        e.Issue.Name,
+			Severity:    burpSeverity(e.Issue.Severity),
+			Confidence:  strings.ToLower(e.Issue.Confidence),
+			URL:         e.Issue.Origin + e.Issue.Path,
+			Detail:      e.Issue.Description,
+			Remediation: e.Issue.Remediation,
+		})
+	}
+	return state, nil
+}
+
+func (b *BurpBackend) ListIssues(ctx context.Context, count, offset int) ([]ScanIssue, error) {
+	issues, err := b.client.GetScannerIssues(ctx, count, offset)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]ScanIssue, len(issues))
+	for i, issue := range issues {
+		result[i] = ScanIssue{
+			Name:        issue.Name,
+			Severity:    burpSeverity(issue.Severity),
+			Confidence:  strings.ToLower(issue.Confidence),
+			URL:         issue.BaseURL,
+			Detail:      issue.Detail,
+			Remediation: issue.Remediation,
+		}
+	}
+	return result, nil
+}
+
+// burpSeverity maps MCP (HIGH, INFORMATION) and REST API (high, info) severities to scanSeverities.
+func burpSeverity(severity string) string {
+	severity = strings.ToLower(severity)
+	if severity == "information" {
+		return "info"
+	}
+	return severity
+}
+
 func (b *BurpBackend) SendRequest(ctx context.Context, name string, req SendRequestInput) (*SendRequestResult, error) {
 	scheme := schemeHTTP
 	if req.Target.UsesHTTPS {
@@ -202,33 +388,45 @@ func (b *BurpBackend) ListRules(ctx context.Context, websocket bool) ([]protocol
 
 	rules := make([]protocol.RuleEntry, 0, len(burpRules))
 	for _, r := range burpRules {
-		if !r.Enabled {
-			continue
-		}
-		id, label, ok := parseSectoolComment(r.Comment)
-		if !ok {
+		if _, _, ok := parseSectoolComment(r.Comment); !ok {
 			continue
 		}
+		entry := burpRuleEntry(r, websocket)
+		entry.Position = len(rules) + 1
+		rules = append(rules, entry)
+	}
+	return rules, nil
+}
 
-		// Convert Burp's format to ws: prefixed types for WebSocket rules
-		ruleType := r.RuleType
-		if websocket {
-			ruleType = burpToWSType(r.RuleType)
-		}
+// burpRuleEntry converts a sectool-managed Burp rule to its protocol form.
+func burpRuleEntry(r mcp.MatchReplaceRule, websocket bool) protocol.RuleEntry {
+	id, label, _ := parseSectoolComment(r.Comment)
 
-		rules = append(rules, protocol.RuleEntry{
-			RuleID:  id,
-			Label:   label,
-			Type:    ruleType,
-			IsRegex: r.Category == mcp.RuleCategoryRegex,
-			Match:   r.StringMatch,
-			Replace: r.StringReplace,
-		})
+	// Convert Burp's format to ws: prefixed types for WebSocket rules
+	ruleType := r.RuleType
+	if websocket {
+		ruleType = burpToWSType(r.RuleType)
+	}
+
+	return protocol.RuleEntry{
+		RuleID:  id,
+		Label:   label,
+		Type:    ruleType,
+		IsRegex: r.Category == mcp.RuleCategoryRegex,
+		Match:   r.StringMatch,
+		Replace: r.StringReplace,
+		Enabled: r.Enabled,
 	}
-	return rules, nil
 }
 
+// errSessionRuleUnsupported is returned for session_token rules, which Burp's
+// match/replace cannot express.
+var errSessionRuleUnsupported = fmt.Errorf("%s rules require the built-in proxy; in Burp use a session handling rule instead", RuleTypeSessionToken)
+
 func (b *BurpBackend) AddRule(ctx context.Context, input ProxyRuleInput) (*protocol.RuleEntry, error) {
+	if input.Type == RuleTypeSessionToken {
+		return nil, errSessionRuleUnsupported
+	}
 	httpRules, err := b.getAllRules(ctx, false)
 	if err != nil {
 		return nil, fmt.Errorf("add rule: %w", err)
@@ -281,10 +479,14 @@ func (b *BurpBackend) AddRule(ctx context.Context, input ProxyRuleInput) (*proto
 		IsRegex: newRule.Category == mcp.RuleCategoryRegex,
 		Match:   input.Match,
 		Replace: input.Replace,
+		Enabled: true,
 	}, nil
 }
 
 func (b *BurpBackend) UpdateRule(ctx context.Context, idOrLabel string, input ProxyRuleInput) (*protocol.RuleEntry, error) {
+	if input.Type == RuleTypeSessionToken {
+		return nil, errSessionRuleUnsupported
+	}
 	httpRules, err := b.getAllRules(ctx, false)
 	if err != nil {
 		return nil, fmt.Errorf("update rule: %w", err)
@@ -358,6 +560,7 @@ func (b *BurpBackend) updateRuleInSet(ctx context.Context, websocket bool, rules
 		IsRegex: rules[idx].Category == mcp.RuleCategoryRegex,
 		Match:   input.Match,
 		Replace: input.Replace,
+		Enabled: rules[idx].Enabled,
 	}, nil
 }
 
@@ -393,6 +596,73 @@ func (b *BurpBackend) DeleteRule(ctx context.Context, idOrLabel string) error {
 	return ErrNotFound
 }
 
+func (b *BurpBackend) ToggleRule(ctx context.Context, idOrLabel string, enabled *bool) (*protocol.RuleEntry, error) {
+	for _, websocket := range []bool{false, true} {
+		rules, err := b.getAllRules(ctx, websocket)
+		if err != nil {
+			return nil, fmt.Errorf("toggle rule: %w", err)
+		}
+		idx := b.findRuleIndex(rules, idOrLabel)
+		if idx < 0 {
+			continue
+		}
+
+		if enabled == nil {
+			rules[idx].Enabled = !rules[idx].Enabled
+		} else {
+			rules[idx].Enabled = *enabled
+		}
+		if err := b.setAllRules(ctx, websocket, rules); err != nil {
+			return nil, fmt.Errorf("toggle rule: %w", err)
+		}
+
+		entry := burpRuleEntry(rules[idx], websocket)
+		return &entry, nil
+	}
+	return nil, ErrNotFound
+}
+
+// MoveRule positions the rule among the sectool-managed rules of its set. Rules not
+// managed by sectool keep their place in Burp's list.
+func (b *BurpBackend) MoveRule(ctx context.Context, idOrLabel string, position int) (*protocol.RuleEntry, error) {
+	for _, websocket := range []bool{false, true} {
+		rules, err := b.getAllRules(ctx, websocket)
+		if err != nil {
+			return nil, fmt.Errorf("move rule: %w", err)
+		}
+		idx := b.findRuleIndex(rules, idOrLabel)
+		if idx < 0 {
+			continue
+		}
+
+		rule := rules[idx]
+		rules = slices.Delete(rules, idx, idx+1)
+		var managed []int // indexes of the other sectool rules
+		for i, r := range rules {
+			if _, _, ok := parseSectoolComment(r.Comment); ok {
+				managed = append(managed, i)
+			}
+		}
+		position = min(max(position, 1), len(managed)+1)
+
+		insertAt := idx
+		if position <= len(managed) {
+			insertAt = managed[position-1]
+		} else if len(managed) > 0 {
+			insertAt = managed[len(managed)-1] + 1
+		}
+		rules = slices.Insert(rules, insertAt, rule)
+		if err := b.setAllRules(ctx, websocket, rules); err != nil {
+			return nil, fmt.Errorf("move rule: %w", err)
+		}
+
+		entry := burpRuleEntry(rule, websocket)
+		entry.Position = position
+		return &entry, nil
+	}
+	return nil, ErrNotFound
+}
+
 func (b *BurpBackend) getAllRules(ctx context.Context, websocket bool) ([]mcp.MatchReplaceRule, error) {
 	if websocket {
 		return b.client.GetWSMatchReplaceRules(ctx)
@@ -408,6 +678,7 @@ func (b *BurpBackend) setAllRules(ctx context.Context, websocket bool, rules []m
 		err = b.client.SetMatchReplaceRules(ctx, rules)
 	}
 	if errors.Is(err, mcp.ErrConfigEditingDisabled) {
+		b.setRulesDisabled(true)
 		return fmt.Errorf("%w; enable 'Edit config' in Burp's MCP settings", err)
 	}
 	return err
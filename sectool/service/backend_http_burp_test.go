@@ -412,4 +412,54 @@ func TestBurpBackendUpdateTypeMismatch(t *testing.T) {
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "cannot update WebSocket rule with HTTP type")
 	})
+	t.Run("session_token_unsupported", func(t *testing.T) {
+		_, err := backend.AddRule(t.Context(), ProxyRuleInput{Type: RuleTypeSessionToken, Match: "SESSIONID"})
+		assert.ErrorIs(t, err, errSessionRuleUnsupported)
+		_, err = backend.UpdateRule(t.Context(), httpRule.RuleID, ProxyRuleInput{Type: RuleTypeSessionToken, Match: "SESSIONID"})
+		assert.ErrorIs(t, err, errSessionRuleUnsupported)
+	})
+}
+
+func TestBurpBackendMoveRule(t *testing.T) {
+	t.Parallel()
+
+	mockServer := NewTestMCPServer(t)
+	client := mcp.New(mockServer.URL())
+	require.NoError(t, client.Connect(t.Context()))
+	t.Cleanup(func() { _ = client.Close() })
+
+	backend := &BurpBackend{client: client}
+
+	require.NoError(t, client.SetMatchReplaceRules(t.Context(), []mcp.MatchReplaceRule{
+		{Category: mcp.RuleCategoryLiteral, Comment: "user rule", Enabled: true, RuleType: mcp.RuleTypeRequestHeader, StringReplace: "X-User: 1"},
+	}))
+	for _, label := range []string{"first", "second", "third"} {
+		_, err := backend.AddRule(t.Context(), ProxyRuleInput{Label: label, Type: mcp.RuleTypeRequestHeader, Replace: "X-" + label + ": 1"})
+		require.NoError(t, err)
+	}
+
+	moved, err := backend.MoveRule(t.Context(), "third", 1)
+	require.NoError(t, err)
+	assert.Equal(t, 1, moved.Position)
+
+	rules, err := backend.ListRules(t.Context(), false)
+	require.NoError(t, err)
+	var labels []string
+	for _, r := range rules {
+		labels = append(labels, r.Label)
+	}
+	assert.Equal(t, []string{"third", "first", "second"}, labels)
+	assert.Equal(t, 3, rules[2].Position)
+
+	// The user's own rule keeps its place at the front
+	raw, err := client.GetMatchReplaceRules(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, "user rule", raw[0].Comment)
+
+	moved, err = backend.MoveRule(t.Context(), "third", 10)
+	require.NoError(t, err)
+	assert.Equal(t, 3, moved.Position)
+
+	_, err = backend.MoveRule(t.Context(), "missing", 1)
+	assert.ErrorIs(t, err, ErrNotFound)
 }
@@ -48,6 +48,7 @@ const (
 	caKeyFile  = "ca-key.pem"
 
 	maxWebSocketFrameSize = 100 * 1024 * 1024 // 100 MB
+	maxWebSocketHistory   = 10000             // captured messages kept; oldest are dropped
 )
 
 // TODO - FUTURE - Replace goproxy with custom proxy implementation
@@ -105,6 +106,12 @@ type GoProxyBackend struct {
 	nextOffset     uint32            // TODO - should be removed and indexed from storage records
 	offsetToKey    map[uint32]string // TODO - this should be captured in the storage
 
+	// WebSocket message history; wsDropped counts messages evicted from the front so
+	// offsets stay stable (protected by wsMu)
+	wsMu      sync.RWMutex
+	wsHistory []WebSocketMessage
+	wsDropped uint32
+
 	// Match/replace rules (separated by type for efficient iteration)
 	rulesMu   sync.RWMutex
 	httpRules []storedRule
@@ -127,17 +134,146 @@ type storedRule struct {
 	IsRegex bool   `json:"is_regex"`
 	Match   string `json:"match"`
 	Replace string `json:"replace"`
+	// Disabled rules are kept but not applied
+	Disabled bool `json:"disabled,omitempty"`
 
 	// compiled is the pre-compiled regex (nil if not a regex rule)
 	compiled *regexp.Regexp
+	// hits counts messages modified by the rule; shared by copies of the rule
+	hits *ruleHits
+	// session holds the captured token for session_token rules (nil otherwise)
+	session *sessionToken
+}
+
+// sessionToken tracks the latest token seen by a session_token rule. Thread-safe.
+type sessionToken struct {
+	capture *regexp.Regexp // run on responses; group 1 is the token
+	target  *regexp.Regexp // run on requests; group 1 is replaced with the token
+
+	mu    sync.RWMutex
+	value string
+}
+
+// newSessionToken compiles a session_token rule. With isRegex, match captures the
+// token from responses and replace locates it in requests (group 1 of each). Otherwise
+// match is a cookie name tracked from Set-Cookie into the Cookie header.
+func newSessionToken(match, replace string, isRegex bool) (*sessionToken, error) {
+	if !isRegex {
+		if match == "" || strings.ContainsAny(match, "=; \t\r\n") {
+			return nil, fmt.Errorf("invalid cookie name %q for %s rule", match, RuleTypeSessionToken)
+		}
+		name := regexp.QuoteMeta(match)
+		return &sessionToken{
+			capture: regexp.MustCompile(`(?im)^Set-Cookie:\s*` + name + `=([^;\r\n]*)`),
+			target:  regexp.MustCompile(`(?im)^Cookie:(?:.*?;)?\s*` + name + `=([^;\r\n]*)`),
+		}, nil
+	}
+
+	capture, err := regexp.Compile(match)
+	if err != nil {
+		return nil, fmt.Errorf("invalid regex pattern: %w", err)
+	}
+	target, err := regexp.Compile(replace)
+	if err != nil {
+		return nil, fmt.Errorf("invalid regex pattern: %w", err)
+	}
+	if capture.NumSubexp() < 1 || target.NumSubexp() < 1 {
+		return nil, fmt.Errorf("%s rule needs a capture group in both match (response) and replace (request) patterns", RuleTypeSessionToken)
+	}
+	return &sessionToken{capture: capture, target: target}, nil
+}
+
+// observe records the last token captured from a response section.
+func (s *sessionToken) observe(data []byte) {
+	var latest []byte
+	for _, m := range s.capture.FindAllSubmatch(data, -1) {
+		if len(m[1]) > 0 {
+			latest = m[1]
+		}
+	}
+	if latest == nil {
+		return
+	}
+	s.mu.Lock()
+	s.value = string(latest)
+	s.mu.Unlock()
+}
+
+// rewrite replaces group 1 of every target match in data with the latest token.
+func (s *sessionToken) rewrite(data []byte) []byte {
+	s.mu.RLock()
+	value := s.value
+	s.mu.RUnlock()
+	if value == "" {
+		return data
+	}
+
+	var out []byte
+	last := 0
+	for _, idx := range s.target.FindAllSubmatchIndex(data, -1) {
+		if idx[2] < 0 {
+			continue
+		}
+		out = append(out, data[last:idx[2]]...)
+		out = append(out, value...)
+		last = idx[3]
+	}
+	if out == nil {
+		return data
+	}
+	return append(out, data[last:]...)
+}
+
+// ruleHits tracks how often a rule modified a message. Thread-safe.
+type ruleHits struct {
+	count   atomic.Int64
+	lastHit atomic.Int64 // unix nanoseconds, 0 if never hit
+}
+
+func (h *ruleHits) record() {
+	if h == nil {
+		return
+	}
+	h.count.Add(1)
+	h.lastHit.Store(time.Now().UnixNano())
+}
+
+// entry converts the rule to its protocol form, including hit counters.
+func (r *storedRule) entry() *protocol.RuleEntry {
+	e := &protocol.RuleEntry{
+		RuleID:  r.ID,
+		Label:   r.Label,
+		Type:    r.Type,
+		IsRegex: r.IsRegex,
+		Match:   r.Match,
+		Replace: r.Replace,
+		Enabled: !r.Disabled,
+	}
+	if r.hits != nil {
+		hits := r.hits.count.Load()
+		e.Hits = &hits
+		if last := r.hits.lastHit.Load(); last > 0 {
+			e.LastHitAt = time.Unix(0, last).UTC().Format(time.RFC3339)
+		}
+	}
+	return e
 }
 
 // Compile-time check that GoProxyBackend implements HttpBackend.
-var _ HttpBackend = (*GoProxyBackend)(nil)
+var (
+	_ HttpBackend            = (*GoProxyBackend)(nil)
+	_ WebSocketHistorySource = (*GoProxyBackend)(nil)
+)
 
-// NewGoProxyBackend creates a new built-in proxy backend.
+// NewGoProxyBackend creates a new built-in proxy backend listening on 127.0.0.1:port.
 // configDir is the directory for CA certificates (e.g., ~/.sectool).
 func NewGoProxyBackend(port int, configDir string) (*GoProxyBackend, error) {
+	return NewGoProxyBackendAddr(net.JoinHostPort("127.0.0.1", strconv.Itoa(port)), configDir)
+}
+
+// NewGoProxyBackendAddr creates a new built-in proxy backend listening on addr
+// ("host:port"; an empty host listens on all interfaces).
+func NewGoProxyBackendAddr(addr, configDir string) (*GoProxyBackend, error) {
 	b := &GoProxyBackend{
 		historyStorage: store.NewMemStorage(),
 		offsetToKey:    make(map[uint32]string),
@@ -162,7 +298,6 @@ func NewGoProxyBackend(port int, configDir string) (*GoProxyBackend, error) {
 	b.proxy = proxy
 
 	// Start HTTP server
-	addr := fmt.Sprintf("127.0.0.1:%d", port)
 	listener, err := net.Listen("tcp", addr)
 	if err != nil {
 		return nil, fmt.Errorf("listen %s: %w", addr, err)
@@ -245,6 +380,32 @@ func (b *GoProxyBackend) GetProxyHistory(ctx context.Context, count int, offset
 	return result, nil
 }
 
+// GetWebSocketHistory returns captured WebSocket messages. Only ws:// connections
+// are captured; wss:// upgrades are tunneled by goproxy without frame access.
+func (b *GoProxyBackend) GetWebSocketHistory(ctx context.Context, count int, offset uint32) ([]WebSocketMessage, error) {
+	b.wsMu.RLock()
+	defer b.wsMu.RUnlock()
+
+	start := max(offset, b.wsDropped) - b.wsDropped
+	if int(start) >= len(b.wsHistory) {
+		return nil, nil
+	}
+	end := min(int(start)+count, len(b.wsHistory))
+	return slices.Clone(b.wsHistory[start:end]), nil
+}
+
+// recordWebSocketMessage appends a message to the WebSocket history.
+func (b *GoProxyBackend) recordWebSocketMessage(msg WebSocketMessage) {
+	b.wsMu.Lock()
+	defer b.wsMu.Unlock()
+
+	if len(b.wsHistory) >= maxWebSocketHistory {
+		b.wsHistory = slices.Delete(b.wsHistory, 0, 1)
+		b.wsDropped++
+	}
+	b.wsHistory = append(b.wsHistory, msg)
+}
+
 func (b *GoProxyBackend) SendRequest(ctx context.Context, name string, req SendRequestInput) (*SendRequestResult, error) {
 	scheme := schemeHTTP
 	if req.Target.UsesHTTPS {
@@ -299,6 +460,7 @@ func (b *GoProxyBackend) sendSingle(ctx context.Context, req SendRequestInput, s
 		TLSClientConfig: &tls.Config{
 			InsecureSkipVerify: true,
 		},
+		DialContext:         dialContext, // host overrides from the tool call's context
 		DisableKeepAlives:   true,
 		ForceAttemptHTTP2:   false, // Prevent HTTP/2 upgrade to match HTTP/1.1 request format
 		DisableCompression:  true,  // Prevent Accept-Encoding injection
@@ -344,15 +506,10 @@ func (b *GoProxyBackend) ListRules(ctx context.Context, websocket bool) ([]proto
 	}
 
 	result := make([]protocol.RuleEntry, 0, len(rules))
-	for _, r := range rules {
-		result = append(result, protocol.RuleEntry{
-			RuleID:  r.ID,
-			Label:   r.Label,
-			Type:    r.Type,
-			IsRegex: r.IsRegex,
-			Match:   r.Match,
-			Replace: r.Replace,
-		})
+	for i := range rules {
+		entry := rules[i].entry()
+		entry.Position = i + 1
+		result = append(result, *entry)
 	}
 	return result, nil
 }
@@ -374,6 +531,13 @@ func (b *GoProxyBackend) AddRule(ctx context.Context, input ProxyRuleInput) (*pr
 			return nil, fmt.Errorf("invalid regex pattern: %w", err)
 		}
 	}
+	var session *sessionToken
+	if input.Type == RuleTypeSessionToken {
+		var err error
+		if session, err = newSessionToken(input.Match, input.Replace, isRegex); err != nil {
+			return nil, err
+		}
+	}
 
 	b.rulesMu.Lock()
 	defer b.rulesMu.Unlock()
@@ -393,6 +557,8 @@ func (b *GoProxyBackend) AddRule(ctx context.Context, input ProxyRuleInput) (*pr
 		Match:    input.Match,
 		Replace:  input.Replace,
 		compiled: compiled,
+		hits:     &ruleHits{},
+		session:  session,
 	}
 	if isWSType(input.Type) {
 		b.wsRules = append(b.wsRules, rule)
@@ -400,14 +566,7 @@ func (b *GoProxyBackend) AddRule(ctx context.Context, input ProxyRuleInput) (*pr
 		b.httpRules = append(b.httpRules, rule)
 	}
 
-	return &protocol.RuleEntry{
-		RuleID:  rule.ID,
-		Label:   rule.Label,
-		Type:    rule.Type,
-		IsRegex: rule.IsRegex,
-		Match:   rule.Match,
-		Replace: rule.Replace,
-	}, nil
+	return rule.entry(), nil
 }
 
 func (b *GoProxyBackend) UpdateRule(ctx context.Context, idOrLabel string, input ProxyRuleInput) (*protocol.RuleEntry, error) {
@@ -455,21 +614,23 @@ func (b *GoProxyBackend) UpdateRule(ctx context.Context, idOrLabel string, input
 			return nil, fmt.Errorf("invalid regex pattern: %w", err)
 		}
 	}
+	var session *sessionToken
+	if input.Type == RuleTypeSessionToken {
+		var err error
+		if session, err = newSessionToken(input.Match, input.Replace, newIsRegex); err != nil {
+			return nil, err
+		}
+	}
 
 	rule.Type = input.Type
 	rule.Match = input.Match
 	rule.Replace = input.Replace
 	rule.IsRegex = newIsRegex
 	rule.compiled = compiled
+	rule.hits = &ruleHits{} // counts reflect the current definition
+	rule.session = session
 
-	return &protocol.RuleEntry{
-		RuleID:  rule.ID,
-		Label:   rule.Label,
-		Type:    rule.Type,
-		IsRegex: rule.IsRegex,
-		Match:   rule.Match,
-		Replace: rule.Replace,
-	}, nil
+	return rule.entry(), nil
 }
 
 func (b *GoProxyBackend) DeleteRule(ctx context.Context, idOrLabel string) error {
@@ -491,6 +652,47 @@ func (b *GoProxyBackend) DeleteRule(ctx context.Context, idOrLabel string) error
 	return ErrNotFound
 }
 
+func (b *GoProxyBackend) MoveRule(ctx context.Context, idOrLabel string, position int) (*protocol.RuleEntry, error) {
+	b.rulesMu.Lock()
+	defer b.rulesMu.Unlock()
+
+	for _, rules := range []*[]storedRule{&b.httpRules, &b.wsRules} {
+		idx := slices.IndexFunc(*rules, func(r storedRule) bool {
+			return r.ID == idOrLabel || r.Label == idOrLabel
+		})
+		if idx < 0 {
+			continue
+		}
+
+		rule := (*rules)[idx]
+		*rules = slices.Delete(*rules, idx, idx+1)
+		position = min(max(position, 1), len(*rules)+1)
+		*rules = slices.Insert(*rules, position-1, rule)
+
+		entry := rule.entry()
+		entry.Position = position
+		return entry, nil
+	}
+	return nil, ErrNotFound
+}
+
+func (b *GoProxyBackend) ToggleRule(ctx context.Context, idOrLabel string, enabled *bool) (*protocol.RuleEntry, error) {
+	b.rulesMu.Lock()
+	defer b.rulesMu.Unlock()
+
+	rule, _ := b.findRule(idOrLabel)
+	if rule == nil {
+		return nil, ErrNotFound
+	}
+
+	if enabled == nil {
+		rule.Disabled = !rule.Disabled
+	} else {
+		rule.Disabled = !*enabled
+	}
+	return rule.entry(), nil
+}
+
 // findRule finds a rule by ID or label, returning the rule and whether it's a WebSocket rule.
 // Caller must hold rulesMu.
 func (b *GoProxyBackend) findRule(idOrLabel string) (*storedRule, bool) {
@@ -538,6 +740,19 @@ func (b *GoProxyBackend) labelExistsExcluding(label, excludeID string) bool {
 	return false
 }
 
+// ImportHistory appends entries captured outside the proxy to its history.
+func (b *GoProxyBackend) ImportHistory(ctx context.Context, entries []ProxyEntry) error {
+	for _, e := range entries {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := b.storeHistoryEntry(e.Request, e.Response); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // storeHistoryEntry stores a request/response pair in proxy history.
 func (b *GoProxyBackend) storeHistoryEntry(req, resp string) error {
 	b.mu.Lock()
@@ -798,12 +1013,12 @@ func (b *GoProxyBackend) installHandlers(proxy *goproxy.ProxyHttpServer) {
 	})
 }
 
-// hasWebSocketRules returns true if any WebSocket rules are configured.
+// hasWebSocketRules returns true if any enabled WebSocket rules are configured.
 func (b *GoProxyBackend) hasWebSocketRules() bool {
 	b.rulesMu.RLock()
 	defer b.rulesMu.RUnlock()
 
-	return len(b.wsRules) > 0
+	return slices.ContainsFunc(b.wsRules, func(r storedRule) bool { return !r.Disabled })
 }
 
 // wsAwareHandler wraps goproxy to intercept WebSocket upgrades before goproxy processes them.
@@ -881,8 +1096,13 @@ func (b *GoProxyBackend) handleWebSocket(w http.ResponseWriter, req *http.Reques
 	}
 
 	// Start bidirectional proxy
+	scheme := "ws"
+	if isSecure {
+		scheme = "wss"
+	}
 	proxy := &wsProxy{
 		backend:      b,
+		url:          scheme + "://" + host + req.URL.RequestURI(),
 		clientConn:   clientConn,
 		clientBuf:    clientBuf,
 		upstreamConn: upstreamConn,
@@ -893,6 +1113,7 @@ func (b *GoProxyBackend) handleWebSocket(w http.ResponseWriter, req *http.Reques
 // wsProxy handles bidirectional WebSocket frame proxying with rule application.
 type wsProxy struct {
 	backend      *GoProxyBackend
+	url          string // ws(s)://host/path recorded with captured messages
 	clientConn   net.Conn
 	clientBuf    *bufio.ReadWriter
 	upstreamConn net.Conn
@@ -937,6 +1158,8 @@ func (p *wsProxy) run() {
 //
 // Current limitation: Rules do not apply to fragmented WebSocket messages.
 func (p *wsProxy) proxyFrames(src io.Reader, dst net.Conn, direction string, outputMasked bool) {
+	var message []byte // payload of a fragmented message being reassembled for history
+	var messageOp byte
 	for {
 		select {
 		case <-p.done:
@@ -958,6 +1181,18 @@ func (p *wsProxy) proxyFrames(src io.Reader, dst net.Conn, direction string, out
 			frame.payload = p.backend.applyWSRules(frame.payload, direction)
 		}
 
+		// Record data messages (text, binary and their continuations) once complete
+		if frame.opcode == 1 || frame.opcode == 2 || (frame.opcode == 0 && messageOp != 0) {
+			if frame.opcode != 0 {
+				message, messageOp = nil, frame.opcode
+			}
+			message = append(message, frame.payload...)
+			if frame.fin {
+				p.record(messageOp, message, direction)
+				message, messageOp = nil, 0
+			}
+		}
+
 		// Set masking for output per RFC 6455
 		frame.masked = outputMasked
 		if outputMasked {
@@ -982,6 +1217,21 @@ func (p *wsProxy) proxyFrames(src io.Reader, dst net.Conn, direction string, out
 	}
 }
 
+// record adds a complete data message to the backend's WebSocket history.
+func (p *wsProxy) record(opcode byte, payload []byte, direction string) {
+	opName := "text"
+	if opcode == 2 {
+		opName = "binary"
+	}
+	p.backend.recordWebSocketMessage(WebSocketMessage{
+		URL:       p.url,
+		Direction: strings.TrimPrefix(direction, "ws:"),
+		Opcode:    opName,
+		Payload:   slices.Clone(payload),
+		Time:      time.Now(),
+	})
+}
+
 func (p *wsProxy) close() {
 	p.closeOnce.Do(func() {
 		close(p.done)
@@ -1192,7 +1442,7 @@ func (b *GoProxyBackend) applyWSRules(payload []byte, direction string) []byte {
 	defer b.rulesMu.RUnlock()
 
 	for _, rule := range b.wsRules {
-		if rule.Type != "ws:both" && rule.Type != direction {
+		if rule.Disabled || (rule.Type != "ws:both" && rule.Type != direction) {
 			continue
 		}
 		payload = applyMatchReplace(payload, rule)
@@ -1208,11 +1458,18 @@ func (b *GoProxyBackend) applyRequestRules(req *http.Request) (*http.Request, er
 
 	var headerRules, bodyRules []storedRule
 	for _, rule := range b.httpRules {
+		if rule.Disabled {
+			continue
+		}
 		switch rule.Type {
 		case RuleTypeRequestHeader:
 			headerRules = append(headerRules, rule)
 		case RuleTypeRequestBody:
 			bodyRules = append(bodyRules, rule)
+		case RuleTypeSessionToken:
+			// The token may live in a header (cookie, bearer) or the body (CSRF field)
+			headerRules = append(headerRules, rule)
+			bodyRules = append(bodyRules, rule)
 		}
 	}
 
@@ -1247,13 +1504,25 @@ func (b *GoProxyBackend) applyResponseRules(resp *http.Response) (*http.Response
 	b.rulesMu.RLock()
 	defer b.rulesMu.RUnlock()
 
-	var headerRules, bodyRules []storedRule
+	var headerRules, bodyRules, sessionRules []storedRule
 	for _, rule := range b.httpRules {
+		if rule.Disabled {
+			continue
+		}
 		switch rule.Type {
 		case RuleTypeResponseHeader:
 			headerRules = append(headerRules, rule)
 		case RuleTypeResponseBody:
 			bodyRules = append(bodyRules, rule)
+		case RuleTypeSessionToken:
+			sessionRules = append(sessionRules, rule)
+		}
+	}
+
+	// Capture session tokens from the response as the server sent it
+	if len(sessionRules) > 0 {
+		if err := observeSessionTokens(resp, sessionRules); err != nil {
+			return nil, fmt.Errorf("read response body: %w", err)
 		}
 	}
 
@@ -1292,6 +1561,36 @@ func (b *GoProxyBackend) applyResponseRules(resp *http.Response) (*http.Response
 	return resp, nil
 }
 
+// observeSessionTokens feeds the response headers and (decompressed) body to each
+// session_token rule. The response body is restored unchanged.
+func observeSessionTokens(resp *http.Response, rules []storedRule) error {
+	var headerBuf bytes.Buffer
+	_ = resp.Header.Write(&headerBuf)
+
+	var body []byte
+	if resp.Body != nil {
+		raw, err := io.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+		if err != nil {
+			return err
+		}
+		resp.Body = io.NopCloser(bytes.NewReader(raw))
+
+		decoded := &http.Response{Header: resp.Header, Body: io.NopCloser(bytes.NewReader(raw))}
+		if body, _, _, err = readAndDecompressBody(decoded); err != nil {
+			return err
+		}
+	}
+
+	for _, rule := range rules {
+		if rule.session != nil {
+			rule.session.observe(headerBuf.Bytes())
+			rule.session.observe(body)
+		}
+	}
+	return nil
+}
+
 // readAndDecompressBody reads the response body, decompressing if needed.
 // Returns the body, the original encoding (for re-compression), whether to skip
 // body rules, and any error. skipRules is true when decompression fails - the
@@ -1372,21 +1671,7 @@ func compressBody(body []byte, encoding string) ([]byte, error) {
 // net/http stores Host separately in req.Host, so we include it in serialization
 // to allow rules to match "Host:" patterns. Returns modified headers and Host.
 func (b *GoProxyBackend) applyRequestHeaderRule(header http.Header, host string, rule storedRule) (http.Header, string) {
-	keys := bulk.MapKeysSlice(header)
-	sort.Strings(keys)
-
-	var headerBuf bytes.Buffer
-	// Include Host first (standard position in HTTP requests)
-	if host != "" {
-		headerBuf.WriteString("Host: " + host + "\r\n")
-	}
-	for _, name := range keys {
-		for _, v := range header[name] {
-			headerBuf.WriteString(name + ": " + v + "\r\n")
-		}
-	}
-
-	original := headerBuf.Bytes()
+	original := ruleHeaderBlock(header, host)
 	modified := applyMatchReplace(original, rule)
 	if bytes.Equal(modified, original) {
 		return header, host
@@ -1418,17 +1703,7 @@ func (b *GoProxyBackend) applyRequestHeaderRule(header http.Header, host string,
 
 // applyHeaderRule applies a rule to headers (sorted keys for determinism).
 func (b *GoProxyBackend) applyHeaderRule(header http.Header, rule storedRule) http.Header {
-	keys := bulk.MapKeysSlice(header)
-	sort.Strings(keys)
-
-	var headerBuf bytes.Buffer
-	for _, name := range keys {
-		for _, v := range header[name] {
-			headerBuf.WriteString(name + ": " + v + "\r\n")
-		}
-	}
-
-	original := headerBuf.Bytes()
+	original := ruleHeaderBlock(header, "")
 	modified := applyMatchReplace(original, rule)
 	if bytes.Equal(modified, original) {
 		return header
@@ -1449,19 +1724,47 @@ func (b *GoProxyBackend) applyHeaderRule(header http.Header, rule storedRule) ht
 	return result
 }
 
-// applyMatchReplace applies a match/replace rule to data.
-func applyMatchReplace(input []byte, rule storedRule) []byte {
-	if !rule.IsRegex {
-		return bytes.ReplaceAll(input, []byte(rule.Match), []byte(rule.Replace))
+// ruleHeaderBlock serializes headers as header rules match them: "Name: value" lines
+// sorted by name, with Host first when set.
+func ruleHeaderBlock(header http.Header, host string) []byte {
+	keys := bulk.MapKeysSlice(header)
+	sort.Strings(keys)
+
+	var headerBuf bytes.Buffer
+	// Include Host first (standard position in HTTP requests)
+	if host != "" {
+		headerBuf.WriteString("Host: " + host + "\r\n")
+	}
+	for _, name := range keys {
+		for _, v := range header[name] {
+			headerBuf.WriteString(name + ": " + v + "\r\n")
+		}
 	}
+	return headerBuf.Bytes()
+}
 
-	re := rule.compiled
-	if re == nil {
-		var err error
-		re, err = regexp.Compile(rule.Match)
-		if err != nil {
-			return input
+// applyMatchReplace applies a match/replace rule to data, counting a hit on the rule
+// when the data changes.
+func applyMatchReplace(input []byte, rule storedRule) []byte {
+	var output []byte
+	if rule.session != nil {
+		output = rule.session.rewrite(input)
+	} else if !rule.IsRegex {
+		output = bytes.ReplaceAll(input, []byte(rule.Match), []byte(rule.Replace))
+	} else {
+		re := rule.compiled
+		if re == nil {
+			var err error
+			re, err = regexp.Compile(rule.Match)
+			if err != nil {
+				return input
+			}
 		}
+		output = re.ReplaceAll(input, []byte(rule.Replace))
+	}
+
+	if !bytes.Equal(output, input) {
+		rule.hits.record()
 	}
-	return re.ReplaceAll(input, []byte(rule.Replace))
+	return output
 }
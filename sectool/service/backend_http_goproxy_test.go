@@ -12,6 +12,7 @@ import (
 	"os"
 	"path/filepath"
 	"strconv"
+	"strings"
 	"testing"
 	"time"
 
@@ -33,6 +34,22 @@ func TestGoProxyBackend_Creation(t *testing.T) {
 	assert.FileExists(t, filepath.Join(configDir, caKeyFile))
 }
 
+func TestNewGoProxyBackendAddr(t *testing.T) {
+	t.Parallel()
+
+	backend, err := NewGoProxyBackendAddr("localhost:0", t.TempDir())
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = backend.Close() })
+
+	host, port, err := net.SplitHostPort(backend.Addr())
+	require.NoError(t, err)
+	assert.True(t, net.ParseIP(host).IsLoopback())
+	assert.NotEqual(t, "0", port)
+
+	_, err = NewGoProxyBackendAddr("bad address", t.TempDir())
+	require.Error(t, err)
+}
+
 func TestGoProxyBackend_CAReuse(t *testing.T) {
 	t.Parallel()
 
@@ -1016,6 +1033,27 @@ func TestGoProxyBackend_GetProxyHistory_WithOffset(t *testing.T) {
 	assert.Contains(t, entries[1].Request, "/3")
 }
 
+func TestGoProxyBackend_ImportHistory(t *testing.T) {
+	t.Parallel()
+
+	backend, err := NewGoProxyBackend(0, t.TempDir())
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = backend.Close() })
+
+	require.NoError(t, backend.storeHistoryEntry("GET /live HTTP/1.1\r\nHost: example.com\r\n\r\n", "HTTP/1.1 200 OK\r\n\r\n"))
+	require.NoError(t, backend.ImportHistory(t.Context(), []ProxyEntry{
+		{Request: "GET /a HTTP/1.1\r\nHost: example.com\r\n\r\n", Response: "HTTP/1.1 200 OK\r\n\r\na"},
+		{Request: "GET /b HTTP/1.1\r\nHost: example.com\r\n\r\n"},
+	}))
+
+	entries, err := backend.GetProxyHistory(t.Context(), 10, 0)
+	require.NoError(t, err)
+	require.Len(t, entries, 3)
+	assert.Contains(t, entries[1].Request, "/a")
+	assert.Contains(t, entries[2].Request, "/b")
+	assert.Empty(t, entries[2].Response)
+}
+
 func TestIsWebSocketUpgrade(t *testing.T) {
 	t.Parallel()
 
@@ -1158,6 +1196,75 @@ func TestApplyRequestRules(t *testing.T) {
 		require.NoError(t, err)
 		assert.Equal(t, "Bearer new-token", modifiedReq.Header.Get("Authorization"))
 	})
+
+	t.Run("hit_counters", func(t *testing.T) {
+		backend, err := NewGoProxyBackend(0, t.TempDir())
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = backend.Close() })
+
+		added, err := backend.AddRule(t.Context(), ProxyRuleInput{
+			Label: "hit-rule", Type: RuleTypeRequestHeader, Match: "X-Debug: 0", Replace: "X-Debug: 1",
+		})
+		require.NoError(t, err)
+		require.NotNil(t, added.Hits)
+		assert.Zero(t, *added.Hits)
+		assert.Empty(t, added.LastHitAt)
+
+		for _, value := range []string{"0", "0", "other"} {
+			req, err := http.NewRequest("GET", "http://example.com/api", nil)
+			require.NoError(t, err)
+			req.Header.Set("X-Debug", value)
+			_, err = backend.applyRequestRules(req)
+			require.NoError(t, err)
+		}
+
+		rules, err := backend.ListRules(t.Context(), false)
+		require.NoError(t, err)
+		require.Len(t, rules, 1)
+		require.NotNil(t, rules[0].Hits)
+		assert.Equal(t, int64(2), *rules[0].Hits)
+		assert.NotEmpty(t, rules[0].LastHitAt)
+
+		updated, err := backend.UpdateRule(t.Context(), "hit-rule", ProxyRuleInput{
+			Type: RuleTypeRequestHeader, Match: "X-Debug: 1", Replace: "X-Debug: 2",
+		})
+		require.NoError(t, err)
+		assert.Zero(t, *updated.Hits)
+	})
+
+	t.Run("disabled_rule_skipped", func(t *testing.T) {
+		backend, err := NewGoProxyBackend(0, t.TempDir())
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = backend.Close() })
+
+		_, err = backend.AddRule(t.Context(), ProxyRuleInput{
+			Label: "toggle-rule", Type: RuleTypeRequestHeader, Match: "X-Debug: 0", Replace: "X-Debug: 1",
+		})
+		require.NoError(t, err)
+
+		apply := func() string {
+			req, err := http.NewRequest("GET", "http://example.com/api", nil)
+			require.NoError(t, err)
+			req.Header.Set("X-Debug", "0")
+			req, err = backend.applyRequestRules(req)
+			require.NoError(t, err)
+			return req.Header.Get("X-Debug")
+		}
+
+		disabled := false
+		rule, err := backend.ToggleRule(t.Context(), "toggle-rule", &disabled)
+		require.NoError(t, err)
+		assert.False(t, rule.Enabled)
+		assert.Equal(t, "0", apply())
+
+		rule, err = backend.ToggleRule(t.Context(), "toggle-rule", nil)
+		require.NoError(t, err)
+		assert.True(t, rule.Enabled)
+		assert.Equal(t, "1", apply())
+
+		_, err = backend.ToggleRule(t.Context(), "missing", nil)
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
 }
 
 func TestApplyResponseRules(t *testing.T) {
@@ -1533,3 +1640,166 @@ func TestDialWebSocket_NonUpgradeResponse(t *testing.T) {
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "unexpected status: 200")
 }
+
+func TestSessionTokenRule(t *testing.T) {
+	t.Parallel()
+
+	sendRequest := func(t *testing.T, backend *GoProxyBackend, cookie, body string) *http.Request {
+		t.Helper()
+		req, err := http.NewRequest("POST", "http://example.com/api", strings.NewReader(body))
+		require.NoError(t, err)
+		req.Header.Set("Cookie", cookie)
+		req, err = backend.applyRequestRules(req)
+		require.NoError(t, err)
+		return req
+	}
+	receiveResponse := func(t *testing.T, backend *GoProxyBackend, header http.Header, body string) string {
+		t.Helper()
+		resp := &http.Response{StatusCode: 200, Header: header, Body: io.NopCloser(strings.NewReader(body))}
+		resp, err := backend.applyResponseRules(resp)
+		require.NoError(t, err)
+		out, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return string(out)
+	}
+
+	t.Run("cookie", func(t *testing.T) {
+		backend, err := NewGoProxyBackend(0, t.TempDir())
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = backend.Close() })
+
+		_, err = backend.AddRule(t.Context(), ProxyRuleInput{Label: "session", Type: RuleTypeSessionToken, Match: "SID"})
+		require.NoError(t, err)
+
+		// No token captured yet: requests pass through
+		req := sendRequest(t, backend, "theme=dark; SID=stale", "")
+		assert.Equal(t, "theme=dark; SID=stale", req.Header.Get("Cookie"))
+
+		header := make(http.Header)
+		header.Add("Set-Cookie", "other=1; Path=/")
+		header.Add("Set-Cookie", "SID=fresh123; Path=/; HttpOnly")
+		assert.Equal(t, "ok", receiveResponse(t, backend, header, "ok"))
+
+		req = sendRequest(t, backend, "theme=dark; SID=stale", "")
+		assert.Equal(t, "theme=dark; SID=fresh123", req.Header.Get("Cookie"))
+		req = sendRequest(t, backend, "SID=stale", "")
+		assert.Equal(t, "SID=fresh123", req.Header.Get("Cookie"))
+
+		rules, err := backend.ListRules(t.Context(), false)
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), *rules[0].Hits)
+	})
+
+	t.Run("regex_csrf_body", func(t *testing.T) {
+		backend, err := NewGoProxyBackend(0, t.TempDir())
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = backend.Close() })
+
+		isRegex := true
+		_, err = backend.AddRule(t.Context(), ProxyRuleInput{
+			Type: RuleTypeSessionToken, IsRegex: &isRegex,
+			Match: `name="csrf" value="([^"]+)"`, Replace: `csrf=([^&]*)`,
+		})
+		require.NoError(t, err)
+
+		receiveResponse(t, backend, make(http.Header), `<input name="csrf" value="tok-2">`)
+
+		req := sendRequest(t, backend, "", "csrf=tok-1&amount=5")
+		body, err := io.ReadAll(req.Body)
+		require.NoError(t, err)
+		assert.Equal(t, "csrf=tok-2&amount=5", string(body))
+		assert.Equal(t, int64(len(body)), req.ContentLength)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		backend, err := NewGoProxyBackend(0, t.TempDir())
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = backend.Close() })
+
+		isRegex := true
+		_, err = backend.AddRule(t.Context(), ProxyRuleInput{Type: RuleTypeSessionToken, IsRegex: &isRegex, Match: "csrf=\\w+", Replace: "csrf=(\\w+)"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "capture group")
+
+		_, err = backend.AddRule(t.Context(), ProxyRuleInput{Type: RuleTypeSessionToken, Match: "bad name"})
+		assert.Error(t, err)
+	})
+}
+
+func TestGoProxyBackend_MoveRule(t *testing.T) {
+	t.Parallel()
+
+	backend, err := NewGoProxyBackend(0, t.TempDir())
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = backend.Close() })
+
+	// Strip then re-add: the result depends on order
+	_, err = backend.AddRule(t.Context(), ProxyRuleInput{Label: "add", Type: RuleTypeRequestHeader, Match: "X-Role: user", Replace: "X-Role: admin"})
+	require.NoError(t, err)
+	_, err = backend.AddRule(t.Context(), ProxyRuleInput{Label: "strip", Type: RuleTypeRequestHeader, Match: "X-Role: admin", Replace: "X-Role: none"})
+	require.NoError(t, err)
+	_, err = backend.AddRule(t.Context(), ProxyRuleInput{Label: "ws", Type: "ws:both", Match: "a", Replace: "b"})
+	require.NoError(t, err)
+
+	apply := func() string {
+		req, err := http.NewRequest("GET", "http://example.com/", nil)
+		require.NoError(t, err)
+		req.Header.Set("X-Role", "user")
+		req, err = backend.applyRequestRules(req)
+		require.NoError(t, err)
+		return req.Header.Get("X-Role")
+	}
+	assert.Equal(t, "none", apply())
+
+	moved, err := backend.MoveRule(t.Context(), "strip", 1)
+	require.NoError(t, err)
+	assert.Equal(t, 1, moved.Position)
+	assert.Equal(t, "admin", apply())
+
+	rules, err := backend.ListRules(t.Context(), false)
+	require.NoError(t, err)
+	require.Len(t, rules, 2)
+	assert.Equal(t, "strip", rules[0].Label)
+	assert.Equal(t, 2, rules[1].Position)
+
+	moved, err = backend.MoveRule(t.Context(), "ws", 5)
+	require.NoError(t, err)
+	assert.Equal(t, 1, moved.Position)
+
+	_, err = backend.MoveRule(t.Context(), "missing", 1)
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestGoProxyBackend_WebSocketHistory(t *testing.T) {
+	t.Parallel()
+
+	backend, err := NewGoProxyBackend(0, t.TempDir())
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = backend.Close() })
+
+	// A text message, then a binary message fragmented across two frames
+	var src strings.Builder
+	src.Write(encodeWSFrame(&wsFrame{fin: true, opcode: 1, payload: []byte("hello")}))
+	src.Write(encodeWSFrame(&wsFrame{fin: false, opcode: 2, payload: []byte{0xff, 0x00}}))
+	src.Write(encodeWSFrame(&wsFrame{fin: true, opcode: 9, payload: []byte("ping")}))
+	src.Write(encodeWSFrame(&wsFrame{fin: true, opcode: 0, payload: []byte{0x01}}))
+
+	dst, peer := net.Pipe()
+	go func() { _, _ = io.Copy(io.Discard, peer) }()
+	p := &wsProxy{backend: backend, url: "ws://example.com/chat", clientConn: dst, upstreamConn: peer, done: make(chan struct{})}
+	p.proxyFrames(strings.NewReader(src.String()), dst, "ws:to-client", false)
+
+	msgs, err := backend.GetWebSocketHistory(t.Context(), 10, 0)
+	require.NoError(t, err)
+	require.Len(t, msgs, 2)
+	assert.Equal(t, "hello", string(msgs[0].Payload))
+	assert.Equal(t, "text", msgs[0].Opcode)
+	assert.Equal(t, WSDirectionToClient, msgs[0].Direction)
+	assert.Equal(t, "ws://example.com/chat", msgs[0].URL)
+	assert.Equal(t, []byte{0xff, 0x00, 0x01}, msgs[1].Payload)
+	assert.Equal(t, "binary", msgs[1].Opcode)
+
+	msgs, err = backend.GetWebSocketHistory(t.Context(), 10, 1)
+	require.NoError(t, err)
+	assert.Len(t, msgs, 1)
+}
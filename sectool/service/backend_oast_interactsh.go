@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"slices"
 	"strings"
 	"sync"
 	"time"
@@ -12,6 +13,7 @@ import (
 	"github.com/go-analyze/bulk"
 	"github.com/go-harden/interactsh-lite/oobclient"
 
+	"github.com/go-harden/llm-security-toolbox/sectool/config"
 	"github.com/go-harden/llm-security-toolbox/sectool/service/ids"
 )
 
@@ -29,6 +31,7 @@ type InteractshBackend struct {
 	byID     map[string]string       // short ID -> domain
 	byLabel  map[string]string       // label -> domain (only non-empty labels)
 	closed   bool
+	opts     oobclient.Options // client options for new sessions
 }
 
 // Compile-time check that InteractshBackend implements OastBackend
@@ -49,12 +52,19 @@ type oastSession struct {
 	stopped     bool
 }
 
-// NewInteractshBackend creates a new Interactsh-backed OastBackend.
-func NewInteractshBackend() *InteractshBackend {
+// NewInteractshBackend creates a new Interactsh-backed OastBackend registering with
+// the servers in cfg, or the public interactsh servers when none are configured.
+func NewInteractshBackend(cfg config.OastConfig) *InteractshBackend {
+	opts := oobclient.DefaultOptions
+	if len(cfg.ServerURLs) > 0 {
+		opts.ServerURLs = slices.Clone(cfg.ServerURLs)
+	}
+	opts.Token = cfg.ServerToken()
 	return &InteractshBackend{
 		sessions: make(map[string]*oastSession),
 		byID:     make(map[string]string),
 		byLabel:  make(map[string]string),
+		opts:     opts,
 	}
 }
 
@@ -75,7 +85,7 @@ func (b *InteractshBackend) CreateSession(ctx context.Context, label string) (*O
 	}
 	b.mu.Unlock()
 
-	c, err := oobclient.New(ctx, nil)
+	c, err := oobclient.New(ctx, &b.opts)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create interactsh client: %w", err)
 	}
@@ -150,20 +160,6 @@ func (b *InteractshBackend) pollLoop(sess *oastSession) {
 			eventTime = time.Now()
 		}
 
-		details := make(map[string]interface{}, 4)
-		if interaction.RawRequest != "" {
-			details["raw_request"] = interaction.RawRequest
-		}
-		if interaction.RawResponse != "" {
-			details["raw_response"] = interaction.RawResponse
-		}
-		if interaction.QType != "" {
-			details["query_type"] = interaction.QType
-		}
-		if interaction.SMTPFrom != "" {
-			details["smtp_from"] = interaction.SMTPFrom
-		}
-
 		if len(sess.events) >= MaxOastEventsPerSession {
 			sess.events = sess.events[1:]
 			sess.droppedCount++
@@ -177,7 +173,7 @@ func (b *InteractshBackend) pollLoop(sess *oastSession) {
 			Type:      strings.ToLower(interaction.Protocol),
 			SourceIP:  interaction.RemoteAddress,
 			Subdomain: interaction.FullId,
-			Details:   details,
+			Details:   interactionDetails(interaction),
 		}
 		sess.events = append(sess.events, event)
 
@@ -199,7 +195,7 @@ func (b *InteractshBackend) pollLoop(sess *oastSession) {
 	<-sess.stopPolling
 }
 
-func (b *InteractshBackend) PollSession(ctx context.Context, idOrDomain string, since string, eventType string, wait time.Duration, limit int) (*OastPollResultInfo, error) {
+func (b *InteractshBackend) PollSession(ctx context.Context, idOrDomain string, since string, eventType string, subdomainContains string, wait time.Duration, limit int) (*OastPollResultInfo, error) {
 	sess, err := b.resolveSession(idOrDomain)
 	if err != nil {
 		return nil, err
@@ -214,7 +210,7 @@ func (b *InteractshBackend) PollSession(ctx context.Context, idOrDomain string,
 			return nil, errors.New("session has been deleted")
 		}
 
-		events := sess.filterEvents(since, eventType)
+		events := sess.filterEvents(since, eventType, subdomainContains)
 		if len(events) > 0 || wait == 0 || time.Now().After(deadline) || ctx.Err() != nil {
 			if limit > 0 && len(events) > limit {
 				events = events[:limit]
@@ -239,9 +235,9 @@ func (b *InteractshBackend) PollSession(ctx context.Context, idOrDomain string,
 	}
 }
 
-// filterEvents returns events based on the since and eventType filters.
+// filterEvents returns events based on the since, eventType and subdomainContains filters.
 // Caller must hold s.mu until result slice is discarded.
-func (s *oastSession) filterEvents(since, eventType string) []OastEventInfo {
+func (s *oastSession) filterEvents(since, eventType, subdomainContains string) []OastEventInfo {
 	var events []OastEventInfo
 	switch since {
 	case "":
@@ -278,12 +274,14 @@ func (s *oastSession) filterEvents(since, eventType string) []OastEventInfo {
 		}
 	}
 
-	if eventType == "" || len(events) == 0 {
+	if (eventType == "" && subdomainContains == "") || len(events) == 0 {
 		return events
 	}
 
+	subdomainContains = strings.ToLower(subdomainContains)
 	return bulk.SliceFilter(func(e OastEventInfo) bool {
-		return e.Type == eventType
+		return (eventType == "" || e.Type == eventType) &&
+			strings.Contains(strings.ToLower(e.Subdomain), subdomainContains)
 	}, events)
 }
 
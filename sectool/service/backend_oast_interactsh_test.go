@@ -5,8 +5,11 @@ import (
 	"testing"
 	"time"
 
+	"github.com/go-harden/interactsh-lite/oobclient"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/go-harden/llm-security-toolbox/sectool/config"
 )
 
 func TestInteractshBackend_CreateAndClose(t *testing.T) {
@@ -15,7 +18,7 @@ func TestInteractshBackend_CreateAndClose(t *testing.T) {
 	}
 	t.Parallel()
 
-	backend := NewInteractshBackend()
+	backend := NewInteractshBackend(config.OastConfig{})
 	t.Cleanup(func() { _ = backend.Close() })
 
 	ctx, cancel := context.WithTimeout(t.Context(), 30*time.Second)
@@ -44,14 +47,34 @@ func TestInteractshBackend_CreateAndClose(t *testing.T) {
 	assert.Empty(t, sessions)
 }
 
+func TestNewInteractshBackend_Options(t *testing.T) {
+	t.Setenv("SECTOOL_TEST_OAST_TOKEN", "secret")
+
+	t.Run("defaults", func(t *testing.T) {
+		backend := NewInteractshBackend(config.OastConfig{})
+		assert.Equal(t, oobclient.DefaultOptions.ServerURLs, backend.opts.ServerURLs)
+		assert.Empty(t, backend.opts.Token)
+		assert.Equal(t, oobclient.DefaultOptions.KeepAliveInterval, backend.opts.KeepAliveInterval)
+	})
+
+	t.Run("self_hosted", func(t *testing.T) {
+		backend := NewInteractshBackend(config.OastConfig{
+			ServerURLs: []string{"oast.example.com"},
+			TokenEnv:   "SECTOOL_TEST_OAST_TOKEN",
+		})
+		assert.Equal(t, []string{"oast.example.com"}, backend.opts.ServerURLs)
+		assert.Equal(t, "secret", backend.opts.Token)
+	})
+}
+
 func TestInteractshBackend_PollSession(t *testing.T) {
 	t.Parallel()
 
 	t.Run("nonexistent", func(t *testing.T) {
-		backend := NewInteractshBackend()
+		backend := NewInteractshBackend(config.OastConfig{})
 		t.Cleanup(func() { _ = backend.Close() })
 
-		_, err := backend.PollSession(t.Context(), "nonexistent", "", "", 0, 0)
+		_, err := backend.PollSession(t.Context(), "nonexistent", "", "", "", 0, 0)
 		require.Error(t, err)
 		assert.ErrorIs(t, err, ErrNotFound)
 	})
@@ -62,7 +85,7 @@ func TestInteractshBackend_PollSession(t *testing.T) {
 		}
 		t.Parallel()
 
-		backend := NewInteractshBackend()
+		backend := NewInteractshBackend(config.OastConfig{})
 		t.Cleanup(func() { _ = backend.Close() })
 
 		ctx, cancel := context.WithTimeout(t.Context(), 30*time.Second)
@@ -72,7 +95,7 @@ func TestInteractshBackend_PollSession(t *testing.T) {
 		require.NoError(t, err)
 
 		// Should be able to poll by domain
-		result, err := backend.PollSession(ctx, sess.Domain, "", "", 0, 0)
+		result, err := backend.PollSession(ctx, sess.Domain, "", "", "", 0, 0)
 		require.NoError(t, err)
 		assert.Empty(t, result.Events)
 
@@ -82,7 +105,7 @@ func TestInteractshBackend_PollSession(t *testing.T) {
 	})
 
 	t.Run("since_last", func(t *testing.T) {
-		backend := NewInteractshBackend()
+		backend := NewInteractshBackend(config.OastConfig{})
 		sess := &oastSession{
 			info: OastSessionInfo{
 				ID:        "test123",
@@ -101,26 +124,26 @@ func TestInteractshBackend_PollSession(t *testing.T) {
 			{ID: "e3", Time: time.Now(), Type: "dns"},
 		}
 
-		result, err := backend.PollSession(t.Context(), "test123", "", "", 0, 0)
+		result, err := backend.PollSession(t.Context(), "test123", "", "", "", 0, 0)
 		require.NoError(t, err)
 		assert.Len(t, result.Events, 3)
 
 		// Poll with "last" should return nothing (we just polled)
-		result, err = backend.PollSession(t.Context(), "test123", "last", "", 0, 0)
+		result, err = backend.PollSession(t.Context(), "test123", "last", "", "", 0, 0)
 		require.NoError(t, err)
 		assert.Empty(t, result.Events)
 
 		sess.events = append(sess.events, OastEventInfo{ID: "e4", Time: time.Now(), Type: "smtp"})
 
 		// Poll with "last" should return the new event
-		result, err = backend.PollSession(t.Context(), "test123", "last", "", 0, 0)
+		result, err = backend.PollSession(t.Context(), "test123", "last", "", "", 0, 0)
 		require.NoError(t, err)
 		assert.Len(t, result.Events, 1)
 		assert.Equal(t, "e4", result.Events[0].ID)
 	})
 
 	t.Run("since_id", func(t *testing.T) {
-		backend := NewInteractshBackend()
+		backend := NewInteractshBackend(config.OastConfig{})
 		sess := &oastSession{
 			info: OastSessionInfo{
 				ID:        "test456",
@@ -140,25 +163,25 @@ func TestInteractshBackend_PollSession(t *testing.T) {
 		}
 
 		// Poll since e1 should return e2 and e3
-		result, err := backend.PollSession(t.Context(), "test456", "e1", "", 0, 0)
+		result, err := backend.PollSession(t.Context(), "test456", "e1", "", "", 0, 0)
 		require.NoError(t, err)
 		assert.Len(t, result.Events, 2)
 		assert.Equal(t, "e2", result.Events[0].ID)
 		assert.Equal(t, "e3", result.Events[1].ID)
 
 		// Poll since e3 should return nothing
-		result, err = backend.PollSession(t.Context(), "test456", "e3", "", 0, 0)
+		result, err = backend.PollSession(t.Context(), "test456", "e3", "", "", 0, 0)
 		require.NoError(t, err)
 		assert.Empty(t, result.Events)
 
 		// Poll since nonexistent ID should return all events
-		result, err = backend.PollSession(t.Context(), "test456", "nonexistent", "", 0, 0)
+		result, err = backend.PollSession(t.Context(), "test456", "nonexistent", "", "", 0, 0)
 		require.NoError(t, err)
 		assert.Len(t, result.Events, 3)
 	})
 
 	t.Run("buffer_limit", func(t *testing.T) {
-		backend := NewInteractshBackend()
+		backend := NewInteractshBackend(config.OastConfig{})
 		sess := &oastSession{
 			info: OastSessionInfo{
 				ID:        "testlimit",
@@ -186,7 +209,7 @@ func TestInteractshBackend_PollSession(t *testing.T) {
 			sess.mu.Unlock()
 		}
 
-		result, err := backend.PollSession(t.Context(), "testlimit", "", "", 0, 0)
+		result, err := backend.PollSession(t.Context(), "testlimit", "", "", "", 0, 0)
 		require.NoError(t, err)
 		assert.Len(t, result.Events, MaxOastEventsPerSession)
 		assert.Equal(t, 100, result.DroppedCount)
@@ -194,7 +217,7 @@ func TestInteractshBackend_PollSession(t *testing.T) {
 
 	// Helper to create a backend with a mock session
 	setupBackend := func(id, domain string) (*InteractshBackend, *oastSession, func()) {
-		backend := NewInteractshBackend()
+		backend := NewInteractshBackend(config.OastConfig{})
 		sess := &oastSession{
 			info: OastSessionInfo{
 				ID:        id,
@@ -229,7 +252,7 @@ func TestInteractshBackend_PollSession(t *testing.T) {
 		done := make(chan pollResult, 1)
 
 		go func() {
-			result, err := backend.PollSession(ctx, "testctx", "", "", 30*time.Second, 0)
+			result, err := backend.PollSession(ctx, "testctx", "", "", "", 30*time.Second, 0)
 			done <- pollResult{result, err}
 		}()
 
@@ -256,7 +279,7 @@ func TestInteractshBackend_PollSession(t *testing.T) {
 		done := make(chan pollResult, 1)
 
 		go func() {
-			result, err := backend.PollSession(t.Context(), "testwait", "", "", 5*time.Second, 0)
+			result, err := backend.PollSession(t.Context(), "testwait", "", "", "", 5*time.Second, 0)
 			done <- pollResult{result, err}
 		}()
 
@@ -286,7 +309,7 @@ func TestInteractshBackend_PollSession(t *testing.T) {
 		t.Cleanup(cleanup)
 
 		start := time.Now()
-		result, err := backend.PollSession(t.Context(), "testzero", "", "", 0, 0)
+		result, err := backend.PollSession(t.Context(), "testzero", "", "", "", 0, 0)
 		elapsed := time.Since(start)
 
 		require.NoError(t, err)
@@ -303,7 +326,7 @@ func TestInteractshBackend_PollSession(t *testing.T) {
 		close(sess.notify)
 		sess.mu.Unlock()
 
-		_, err := backend.PollSession(t.Context(), "teststopped", "", "", 0, 0)
+		_, err := backend.PollSession(t.Context(), "teststopped", "", "", "", 0, 0)
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "deleted")
 	})
@@ -317,7 +340,7 @@ func TestInteractshBackend_PollSession(t *testing.T) {
 			{ID: "e2", Time: time.Now(), Type: "http"},
 		}
 
-		_, err := backend.PollSession(t.Context(), "testidx", "", "", 0, 0)
+		_, err := backend.PollSession(t.Context(), "testidx", "", "", "", 0, 0)
 		require.NoError(t, err)
 		assert.Equal(t, 2, sess.lastPollIdx)
 
@@ -326,7 +349,7 @@ func TestInteractshBackend_PollSession(t *testing.T) {
 		sess.events = append(sess.events, OastEventInfo{ID: "e3", Time: time.Now(), Type: "dns"})
 		sess.mu.Unlock()
 
-		_, err = backend.PollSession(t.Context(), "testidx", "last", "", 0, 0)
+		_, err = backend.PollSession(t.Context(), "testidx", "last", "", "", 0, 0)
 		require.NoError(t, err)
 		assert.Equal(t, 3, sess.lastPollIdx)
 	})
@@ -335,7 +358,7 @@ func TestInteractshBackend_PollSession(t *testing.T) {
 func TestInteractshBackend_CloseWhileClosed(t *testing.T) {
 	t.Parallel()
 
-	backend := NewInteractshBackend()
+	backend := NewInteractshBackend(config.OastConfig{})
 
 	// Close once
 	err := backend.Close()
@@ -349,7 +372,7 @@ func TestInteractshBackend_CloseWhileClosed(t *testing.T) {
 func TestInteractshBackend_CreateAfterClose(t *testing.T) {
 	t.Parallel()
 
-	backend := NewInteractshBackend()
+	backend := NewInteractshBackend(config.OastConfig{})
 	_ = backend.Close()
 
 	_, err := backend.CreateSession(t.Context(), "")
@@ -375,7 +398,7 @@ func TestOastSession_FilterEvents(t *testing.T) {
 
 	t.Run("empty_since_returns_all", func(t *testing.T) {
 		sess := &oastSession{events: makeEvents("e1", "e2", "e3")}
-		result := sess.filterEvents("", "")
+		result := sess.filterEvents("", "", "")
 		require.Len(t, result, 3)
 		assert.Equal(t, "e1", result[0].ID)
 		assert.Equal(t, "e3", result[2].ID)
@@ -383,7 +406,7 @@ func TestOastSession_FilterEvents(t *testing.T) {
 
 	t.Run("empty_since_with_no_events", func(t *testing.T) {
 		sess := &oastSession{}
-		result := sess.filterEvents("", "")
+		result := sess.filterEvents("", "", "")
 		assert.Empty(t, result)
 	})
 
@@ -392,7 +415,7 @@ func TestOastSession_FilterEvents(t *testing.T) {
 			events:      makeEvents("e1", "e2", "e3", "e4"),
 			lastPollIdx: 2,
 		}
-		result := sess.filterEvents("last", "")
+		result := sess.filterEvents("last", "", "")
 		require.Len(t, result, 2)
 		assert.Equal(t, "e3", result[0].ID)
 		assert.Equal(t, "e4", result[1].ID)
@@ -403,7 +426,7 @@ func TestOastSession_FilterEvents(t *testing.T) {
 			events:      makeEvents("e1", "e2"),
 			lastPollIdx: 2,
 		}
-		result := sess.filterEvents("last", "")
+		result := sess.filterEvents("last", "", "")
 		assert.Empty(t, result)
 	})
 
@@ -412,13 +435,13 @@ func TestOastSession_FilterEvents(t *testing.T) {
 			events:      makeEvents("e1"),
 			lastPollIdx: 5,
 		}
-		result := sess.filterEvents("last", "")
+		result := sess.filterEvents("last", "", "")
 		assert.Empty(t, result)
 	})
 
 	t.Run("event_id_returns_events_after", func(t *testing.T) {
 		sess := &oastSession{events: makeEvents("e1", "e2", "e3", "e4")}
-		result := sess.filterEvents("e2", "")
+		result := sess.filterEvents("e2", "", "")
 		require.Len(t, result, 2)
 		assert.Equal(t, "e3", result[0].ID)
 		assert.Equal(t, "e4", result[1].ID)
@@ -426,20 +449,20 @@ func TestOastSession_FilterEvents(t *testing.T) {
 
 	t.Run("event_id_at_end_returns_empty", func(t *testing.T) {
 		sess := &oastSession{events: makeEvents("e1", "e2", "e3")}
-		result := sess.filterEvents("e3", "")
+		result := sess.filterEvents("e3", "", "")
 		assert.Empty(t, result)
 	})
 
 	t.Run("event_id_first_returns_rest", func(t *testing.T) {
 		sess := &oastSession{events: makeEvents("e1", "e2", "e3")}
-		result := sess.filterEvents("e1", "")
+		result := sess.filterEvents("e1", "", "")
 		require.Len(t, result, 2)
 		assert.Equal(t, "e2", result[0].ID)
 	})
 
 	t.Run("unknown_event_id_returns_all", func(t *testing.T) {
 		sess := &oastSession{events: makeEvents("e1", "e2", "e3")}
-		result := sess.filterEvents("nonexistent", "")
+		result := sess.filterEvents("nonexistent", "", "")
 		require.Len(t, result, 3)
 	})
 
@@ -450,7 +473,7 @@ func TestOastSession_FilterEvents(t *testing.T) {
 			{ID: "e3", Time: baseTime.Add(2 * time.Second), Type: "dns"},
 			{ID: "e4", Time: baseTime.Add(3 * time.Second), Type: "smtp"},
 		}}
-		result := sess.filterEvents("", "dns")
+		result := sess.filterEvents("", "dns", "")
 		require.Len(t, result, 2)
 		assert.Equal(t, "e1", result[0].ID)
 		assert.Equal(t, "e3", result[1].ID)
@@ -458,7 +481,7 @@ func TestOastSession_FilterEvents(t *testing.T) {
 
 	t.Run("type_filter_no_matches", func(t *testing.T) {
 		sess := &oastSession{events: makeEvents("e1", "e2", "e3")}
-		result := sess.filterEvents("", "http")
+		result := sess.filterEvents("", "http", "")
 		assert.Empty(t, result)
 	})
 
@@ -469,7 +492,7 @@ func TestOastSession_FilterEvents(t *testing.T) {
 			{ID: "e3", Time: baseTime.Add(2 * time.Second), Type: "dns"},
 			{ID: "e4", Time: baseTime.Add(3 * time.Second), Type: "http"},
 		}}
-		result := sess.filterEvents("e1", "http")
+		result := sess.filterEvents("e1", "http", "")
 		require.Len(t, result, 2)
 		assert.Equal(t, "e2", result[0].ID)
 		assert.Equal(t, "e4", result[1].ID)
@@ -485,7 +508,24 @@ func TestOastSession_FilterEvents(t *testing.T) {
 			},
 			lastPollIdx: 2,
 		}
-		result := sess.filterEvents("last", "dns")
+		result := sess.filterEvents("last", "dns", "")
+		require.Len(t, result, 1)
+		assert.Equal(t, "e3", result[0].ID)
+	})
+
+	t.Run("subdomain_filter", func(t *testing.T) {
+		sess := &oastSession{events: []OastEventInfo{
+			{ID: "e1", Time: baseTime, Type: "http", Subdomain: "abc123"},
+			{ID: "e2", Time: baseTime.Add(time.Second), Type: "dns", Subdomain: "SSRF1.abc123"},
+			{ID: "e3", Time: baseTime.Add(2 * time.Second), Type: "http", Subdomain: "ssrf1.abc123"},
+			{ID: "e4", Time: baseTime.Add(3 * time.Second), Type: "http", Subdomain: "xxe.abc123"},
+		}}
+		result := sess.filterEvents("", "", "ssrf1.")
+		require.Len(t, result, 2)
+		assert.Equal(t, "e2", result[0].ID)
+		assert.Equal(t, "e3", result[1].ID)
+
+		result = sess.filterEvents("", "http", "ssrf1")
 		require.Len(t, result, 1)
 		assert.Equal(t, "e3", result[0].ID)
 	})
@@ -552,7 +592,7 @@ func TestOastSession_BufferRotation(t *testing.T) {
 		}
 
 		// Poll all events, setting lastPollIdx
-		result := sess.filterEvents("", "")
+		result := sess.filterEvents("", "", "")
 		assert.Len(t, result, MaxOastEventsPerSession)
 		sess.lastPollIdx = len(sess.events)
 
@@ -565,7 +605,7 @@ func TestOastSession_BufferRotation(t *testing.T) {
 		assert.Equal(t, MaxOastEventsPerSession-10, sess.lastPollIdx)
 
 		// "last" filter should return only the new events
-		result = sess.filterEvents("last", "")
+		result = sess.filterEvents("last", "", "")
 		assert.Len(t, result, 10)
 		assert.Equal(t, "new0", result[0].ID)
 	})
@@ -590,7 +630,7 @@ func TestInteractshBackend_GetEvent(t *testing.T) {
 	t.Parallel()
 
 	t.Run("session_not_found", func(t *testing.T) {
-		backend := NewInteractshBackend()
+		backend := NewInteractshBackend(config.OastConfig{})
 		t.Cleanup(func() { _ = backend.Close() })
 
 		_, err := backend.GetEvent(t.Context(), "nonexistent", "event1")
@@ -599,7 +639,7 @@ func TestInteractshBackend_GetEvent(t *testing.T) {
 	})
 
 	t.Run("event_not_found", func(t *testing.T) {
-		backend := NewInteractshBackend()
+		backend := NewInteractshBackend(config.OastConfig{})
 		t.Cleanup(func() { _ = backend.Close() })
 
 		sess := &oastSession{
@@ -622,7 +662,7 @@ func TestInteractshBackend_GetEvent(t *testing.T) {
 	})
 
 	t.Run("returns_event_by_id", func(t *testing.T) {
-		backend := NewInteractshBackend()
+		backend := NewInteractshBackend(config.OastConfig{})
 		t.Cleanup(func() { _ = backend.Close() })
 
 		eventTime := time.Date(2024, 6, 15, 10, 30, 0, 0, time.UTC)
@@ -659,7 +699,7 @@ func TestInteractshBackend_GetEvent(t *testing.T) {
 	})
 
 	t.Run("by_domain", func(t *testing.T) {
-		backend := NewInteractshBackend()
+		backend := NewInteractshBackend(config.OastConfig{})
 		t.Cleanup(func() { _ = backend.Close() })
 
 		sess := &oastSession{
@@ -682,7 +722,7 @@ func TestInteractshBackend_GetEvent(t *testing.T) {
 	})
 
 	t.Run("stopped_session_returns_error", func(t *testing.T) {
-		backend := NewInteractshBackend()
+		backend := NewInteractshBackend(config.OastConfig{})
 		t.Cleanup(func() { _ = backend.Close() })
 
 		notify := make(chan struct{})
@@ -712,7 +752,7 @@ func TestInteractshBackend_DeleteSession(t *testing.T) {
 	t.Parallel()
 
 	t.Run("second_delete_returns_not_found", func(t *testing.T) {
-		backend := NewInteractshBackend()
+		backend := NewInteractshBackend(config.OastConfig{})
 		t.Cleanup(func() { _ = backend.Close() })
 
 		sess := &oastSession{
@@ -735,7 +775,7 @@ func TestInteractshBackend_DeleteSession(t *testing.T) {
 	})
 
 	t.Run("delete_by_domain", func(t *testing.T) {
-		backend := NewInteractshBackend()
+		backend := NewInteractshBackend(config.OastConfig{})
 		t.Cleanup(func() { _ = backend.Close() })
 
 		sess := &oastSession{
@@ -0,0 +1,112 @@
+package service
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"unicode/utf8"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/go-harden/llm-security-toolbox/sectool/protocol"
+)
+
+// defaultRangeLines is the window size in lines when only start_line is given.
+const defaultRangeLines = 200
+
+// bodyRange selects a window of a large body, by bytes or by 1-based inclusive lines.
+type bodyRange struct {
+	offset, length     int
+	startLine, endLine int
+}
+
+// bodyRangeOptions are the parameters shared by tools that return body windows.
+func bodyRangeOptions() []mcp.ToolOption {
+	return []mcp.ToolOption{
+		mcp.WithNumber("offset", mcp.Description("Byte offset of a body window; use next_offset from body_range to continue")),
+		mcp.WithNumber("length", mcp.Description("Window size in bytes (default 16384)")),
+		mcp.WithNumber("start_line", mcp.Description("First line of a body window (1-based; alternative to offset)")),
+		mcp.WithNumber("end_line", mcp.Description("Last line of a body window, inclusive (default start_line+199)")),
+	}
+}
+
+// parseBodyRange reads the window parameters, reporting false when none are set.
+func parseBodyRange(req mcp.CallToolRequest) (bodyRange, bool, error) {
+	args := req.GetArguments()
+	_, hasOffset := args["offset"]
+	_, hasLength := args["length"]
+	_, hasStart := args["start_line"]
+	_, hasEnd := args["end_line"]
+	if !hasOffset && !hasLength && !hasStart && !hasEnd {
+		return bodyRange{}, false, nil
+	}
+
+	r := bodyRange{
+		offset:    req.GetInt("offset", 0),
+		length:    req.GetInt("length", defaultOutputChunk),
+		startLine: req.GetInt("start_line", 0),
+		endLine:   req.GetInt("end_line", 0),
+	}
+	switch {
+	case (hasOffset || hasLength) && (hasStart || hasEnd):
+		return r, true, errors.New("use either offset/length or start_line/end_line")
+	case r.offset < 0 || r.length <= 0:
+		return r, true, errors.New("invalid offset or length")
+	case hasEnd && !hasStart:
+		r.startLine = 1
+	case hasStart && r.startLine < 1:
+		return r, true, errors.New("start_line must be at least 1")
+	}
+	if r.startLine > 0 {
+		if r.endLine == 0 {
+			r.endLine = r.startLine + defaultRangeLines - 1
+		} else if r.endLine < r.startLine {
+			return r, true, errors.New("end_line must not be before start_line")
+		}
+	}
+	return r, true, nil
+}
+
+// slice returns the window of data, as text when it is valid UTF-8 and base64 otherwise.
+func (r bodyRange) slice(data []byte) (string, *protocol.BodyRange, error) {
+	start, end := r.offset, r.offset+r.length
+	if r.startLine > 0 {
+		start, end = lineOffset(data, r.startLine), lineOffset(data, r.endLine+1)
+	}
+	if start > len(data) || (start == len(data) && len(data) > 0) {
+		return "", nil, errors.New("range starts beyond end of body")
+	}
+	end = min(end, len(data))
+	if r.startLine == 0 && utf8.Valid(data) {
+		for end < len(data) && end > start && !utf8.RuneStart(data[end]) {
+			end--
+		}
+	}
+
+	window := data[start:end]
+	info := &protocol.BodyRange{Offset: start, Length: len(window), TotalBytes: len(data), Encoding: "text"}
+	if end < len(data) {
+		info.NextOffset = end
+		if r.startLine > 0 {
+			info.NextLine = r.endLine + 1
+		}
+	}
+	if !utf8.Valid(window) {
+		info.Encoding = "base64"
+		return base64.StdEncoding.EncodeToString(window), info, nil
+	}
+	return string(window), info, nil
+}
+
+// lineOffset returns the byte offset where 1-based line n starts, or len(data) past the end.
+func lineOffset(data []byte, n int) int {
+	var off int
+	for line := 1; line < n; line++ {
+		i := bytes.IndexByte(data[off:], '\n')
+		if i < 0 {
+			return len(data)
+		}
+		off += i + 1
+	}
+	return off
+}
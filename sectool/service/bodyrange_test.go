@@ -0,0 +1,45 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/go-harden/llm-security-toolbox/sectool/protocol"
+)
+
+func TestBodyRangeSlice(t *testing.T) {
+	t.Parallel()
+
+	text := []byte("line1\nline2\nline3\nline4\n")
+	tests := []struct {
+		name    string
+		r       bodyRange
+		data    []byte
+		want    string
+		info    protocol.BodyRange
+		wantErr bool
+	}{
+		{"bytes", bodyRange{offset: 6, length: 5}, text, "line2", protocol.BodyRange{Offset: 6, Length: 5, TotalBytes: 24, NextOffset: 11, Encoding: "text"}, false},
+		{"bytes_to_end", bodyRange{offset: 18, length: 100}, text, "line4\n", protocol.BodyRange{Offset: 18, Length: 6, TotalBytes: 24, Encoding: "text"}, false},
+		{"lines", bodyRange{startLine: 2, endLine: 3}, text, "line2\nline3\n", protocol.BodyRange{Offset: 6, Length: 12, TotalBytes: 24, NextOffset: 18, NextLine: 4, Encoding: "text"}, false},
+		{"lines_past_end", bodyRange{startLine: 4, endLine: 10}, text, "line4\n", protocol.BodyRange{Offset: 18, Length: 6, TotalBytes: 24, Encoding: "text"}, false},
+		{"rune_boundary", bodyRange{offset: 0, length: 2}, []byte("aé"), "a", protocol.BodyRange{Offset: 0, Length: 1, TotalBytes: 3, NextOffset: 1, Encoding: "text"}, false},
+		{"binary", bodyRange{offset: 1, length: 2}, []byte{0xff, 0x00, 0xfe, 0x01}, "AP4=", protocol.BodyRange{Offset: 1, Length: 2, TotalBytes: 4, NextOffset: 3, Encoding: "base64"}, false},
+		{"beyond_end", bodyRange{offset: 24, length: 10}, text, "", protocol.BodyRange{}, true},
+		{"lines_beyond_end", bodyRange{startLine: 9, endLine: 10}, text, "", protocol.BodyRange{}, true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, info, err := tc.r.slice(tc.data)
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+			assert.Equal(t, tc.info, *info)
+		})
+	}
+}
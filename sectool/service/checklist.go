@@ -0,0 +1,233 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/go-harden/llm-security-toolbox/sectool/config"
+	"github.com/go-harden/llm-security-toolbox/sectool/protocol"
+)
+
+// checklistFileName is the project checklist file within config.ProjectDirName.
+const checklistFileName = "checklist.json"
+
+type checklistFile struct {
+	Methodology string                   `json:"methodology"`
+	Items       []protocol.ChecklistItem `json:"items"`
+}
+
+// checklistStore persists the project checklist in .sectool/checklist.json. Every
+// operation re-reads the file so edits from other services or by hand are kept.
+// Thread-safe.
+type checklistStore struct {
+	mu   sync.Mutex
+	path string
+}
+
+func newChecklistStore(projectDir string) *checklistStore {
+	return &checklistStore{path: filepath.Join(projectDir, config.ProjectDirName, checklistFileName)}
+}
+
+// Get returns the checklist, seeding it from methodology on first use.
+func (c *checklistStore) Get(methodology string) (*checklistFile, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.loadOrSeed(methodology)
+}
+
+// Mark sets the status and note of item id. An unknown id adds a custom item, which
+// requires title.
+func (c *checklistStore) Mark(methodology, id, status, note, category, title string) (*checklistFile, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	list, err := c.loadOrSeed(methodology)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC().Format(time.RFC3339)
+	idx := -1
+	for i, item := range list.Items {
+		if item.ID == id {
+			idx = i
+			break
+		}
+	}
+	if idx < 0 {
+		if title == "" {
+			return nil, fmt.Errorf("unknown item %q: pass title (and category) to add a custom item", id)
+		}
+		if category == "" {
+			category = "custom"
+		}
+		list.Items = append(list.Items, protocol.ChecklistItem{ID: id, Category: category, Title: title})
+		idx = len(list.Items) - 1
+	}
+
+	item := &list.Items[idx]
+	item.Status = status
+	if note != "" {
+		item.Note = note
+	}
+	item.UpdatedAt = now
+	if err := c.save(list); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (c *checklistStore) loadOrSeed(methodology string) (*checklistFile, error) {
+	data, err := os.ReadFile(c.path)
+	if errors.Is(err, os.ErrNotExist) {
+		list := seedChecklist(methodology)
+		if err := c.save(list); err != nil {
+			return nil, err
+		}
+		return list, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	var list checklistFile
+	if err := json.Unmarshal(data, &list); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", c.path, err)
+	}
+	return &list, nil
+}
+
+// save writes the checklist atomically so concurrent readers never see a partial file.
+func (c *checklistStore) save(list *checklistFile) error {
+	if err := os.MkdirAll(filepath.Dir(c.path), 0755); err != nil {
+		return fmt.Errorf("create checklist directory: %w", err)
+	}
+	data, err := json.MarshalIndent(list, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := c.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, c.path)
+}
+
+// checklistCoverage counts items by status.
+func checklistCoverage(items []protocol.ChecklistItem) protocol.ChecklistCoverage {
+	cov := protocol.ChecklistCoverage{Total: len(items)}
+	for _, item := range items {
+		switch item.Status {
+		case protocol.ChecklistTested:
+			cov.Tested++
+		case protocol.ChecklistNA:
+			cov.NA++
+		case protocol.ChecklistVulnerable:
+			cov.Vulnerable++
+		default:
+			cov.Untested++
+		}
+	}
+	if cov.Total > 0 {
+		cov.Percent = (cov.Total - cov.Untested) * 100 / cov.Total
+	}
+	return cov
+}
+
+type checklistSeed struct {
+	id, category, title string
+}
+
+// seedChecklist returns a fresh checklist for methodology (a workflow mode), falling
+// back to the explore methodology for modes without their own.
+func seedChecklist(methodology string) *checklistFile {
+	seeds, ok := checklistSeeds[methodology]
+	if !ok {
+		methodology = WorkflowModeExplore
+		seeds = checklistSeeds[methodology]
+	}
+	list := &checklistFile{Methodology: methodology, Items: make([]protocol.ChecklistItem, len(seeds))}
+	for i, s := range seeds {
+		list.Items[i] = protocol.ChecklistItem{
+			ID:       s.id,
+			Category: s.category,
+			Title:    s.title,
+			Status:   protocol.ChecklistUntested,
+		}
+	}
+	return list
+}
+
+var webChecklistSeeds = []checklistSeed{
+	{"recon-endpoints", "recon", "Map endpoints, parameters and roles from traffic"},
+	{"recon-tech", "recon", "Identify technologies, frameworks and versions"},
+	{"authn-login", "authentication", "Login: brute-force protection and user enumeration"},
+	{"authn-reset", "authentication", "Password reset and account recovery flow"},
+	{"session-tokens", "session", "Session token expiry, rotation and logout invalidation"},
+	{"authz-idor", "authorization", "Object-level access control (IDOR) across users and tenants"},
+	{"authz-function", "authorization", "Function-level access control (privilege escalation)"},
+	{"input-xss", "input", "Reflected and stored XSS"},
+	{"input-sqli", "input", "SQL and NoSQL injection"},
+	{"input-cmdi", "input", "Command and template injection"},
+	{"ssrf", "input", "Server-side request forgery via URL parameters and webhooks"},
+	{"xxe-upload", "input", "File upload handling and XML parsing (XXE)"},
+	{"csrf", "client", "Cross-site request forgery on state-changing actions"},
+	{"cors-redirect", "client", "CORS policy and open redirects"},
+	{"business-logic", "logic", "Business logic: race conditions, price and quantity tampering"},
+	{"headers-cookies", "config", "Security headers and cookie flags"},
+}
+
+var checklistSeeds = map[string][]checklistSeed{
+	WorkflowModeExplore: webChecklistSeeds,
+	WorkflowModeAPI: {
+		{"recon-endpoints", "recon", "Build endpoint inventory: methods, parameters, object IDs"},
+		{"recon-versions", "recon", "Find undocumented, legacy and versioned endpoints"},
+		{"authn-mechanism", "authentication", "Token validation: signature, expiry, audience, revocation"},
+		{"authz-bola", "authorization", "Object-level authorization (BOLA) across users and tenants"},
+		{"authz-bfla", "authorization", "Function-level authorization (admin endpoints, HTTP methods)"},
+		{"mass-assignment", "input", "Mass assignment of privileged or read-only fields"},
+		{"input-injection", "input", "SQL, NoSQL and command injection"},
+		{"input-types", "input", "Type confusion and malformed body handling"},
+		{"ssrf", "input", "Server-side request forgery via URL fields"},
+		{"rate-limit", "abuse", "Rate limiting and resource exhaustion"},
+		{"data-exposure", "data", "Excessive data exposure in responses and errors"},
+		{"business-logic", "logic", "Business logic and workflow bypass"},
+	},
+	WorkflowModeMobile: {
+		{"traffic-capture", "setup", "All app traffic captured (proxy and pinning handled)"},
+		{"recon-endpoints", "recon", "Map API hosts, endpoints and client-supplied identifiers"},
+		{"recon-versions", "recon", "Legacy and versioned API endpoints"},
+		{"client-enforced", "logic", "Checks enforced only by the app: feature flags, prices, limits"},
+		{"authz-idor", "authorization", "Object-level access control across accounts"},
+		{"token-lifecycle", "session", "Token refresh, revocation after logout, reuse across devices"},
+		{"device-binding", "session", "Device and platform headers trusted by the back-end"},
+		{"input-injection", "input", "Injection in API parameters"},
+		{"data-exposure", "data", "Sensitive data returned beyond what the app displays"},
+		{"rate-limit", "abuse", "Rate limiting on OTP, login and costly operations"},
+	},
+	WorkflowModeLLMApp: {
+		{"recon-context", "recon", "Locate prompt, history and retrieved context in requests"},
+		{"prompt-direct", "injection", "Direct prompt injection: instruction override and role confusion"},
+		{"prompt-indirect", "injection", "Indirect injection via content the model reads"},
+		{"system-prompt", "disclosure", "System prompt and configuration disclosure"},
+		{"cross-user-data", "disclosure", "Other users' data or conversations reachable via the model"},
+		{"exfil-render", "exfiltration", "Data exfiltration via rendered links and images"},
+		{"tool-abuse", "agency", "Unauthorized tool or plugin actions"},
+		{"output-xss", "output", "Model output rendered unsafely (XSS)"},
+		{"guardrail-bypass", "policy", "Guardrail and content filter bypass"},
+		{"cost-abuse", "abuse", "Cost and resource abuse (long outputs, loops)"},
+	},
+	WorkflowModeTestReport: {
+		{"understand", "validation", "Understand the claimed issue and impact"},
+		{"reproduce", "validation", "Reproduce with captured traffic"},
+		{"prerequisites", "validation", "Confirm prerequisites: roles, configuration, user interaction"},
+		{"impact", "validation", "Assess real impact and exploitability"},
+		{"mitigations", "validation", "Check mitigating controls"},
+		{"variants", "validation", "Test related variants and other endpoints"},
+	},
+}
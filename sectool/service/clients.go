@@ -0,0 +1,144 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"sync"
+	"sync/atomic"
+
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/go-harden/llm-security-toolbox/sectool/protocol"
+)
+
+// maxTrackedClients bounds per-client state; the least recently used client is dropped
+// when exceeded (streamable HTTP sessions are never explicitly closed by most clients).
+const maxTrackedClients = 256
+
+type clientNameKey struct{}
+
+// withClientName records the ClientHeader value of r in ctx.
+func withClientName(ctx context.Context, r *http.Request) context.Context {
+	if name := r.Header.Get(protocol.ClientHeader); name != "" {
+		return context.WithValue(ctx, clientNameKey{}, name)
+	}
+	return ctx
+}
+
+// isCLIClient reports whether the caller identified itself as the sectool CLI.
+func isCLIClient(ctx context.Context) bool {
+	name, _ := ctx.Value(clientNameKey{}).(string)
+	return name == protocol.ClientNameCLI
+}
+
+// clientKey identifies the caller: a ClientHeader name when sent, otherwise the MCP
+// session. Callers with neither share the "" client.
+func clientKey(ctx context.Context) string {
+	if name, _ := ctx.Value(clientNameKey{}).(string); name != "" {
+		return "name:" + name
+	} else if session := server.ClientSessionFromContext(ctx); session != nil && session.SessionID() != "" {
+		return "session:" + session.SessionID()
+	}
+	return ""
+}
+
+// clientState holds state that must not leak between concurrently connected clients.
+type clientState struct {
+	// proxyNextOffset is one past the highest proxy offset returned to this client, for
+	// proxy_poll since=last. Zero means nothing was returned yet.
+	proxyNextOffset atomic.Uint32
+
+	mu        sync.Mutex
+	oastLast  map[string]string // oast_id -> last event_id returned, for oast_poll since=last
+	crawlLast map[string]string // crawl session_id -> last flow_id returned, for crawl_poll since=last
+
+	// cookieJars holds cookies captured from this client's replay responses and
+	// attached to its later replays, by host (ephemeral)
+	cookieJars *cookieJarStore
+}
+
+// markProxyOffset records that the flow at offset was returned to this client.
+func (c *clientState) markProxyOffset(offset uint32) {
+	for {
+		current := c.proxyNextOffset.Load()
+		if offset < current || c.proxyNextOffset.CompareAndSwap(current, offset+1) {
+			return
+		}
+	}
+}
+
+// resolveSince expands since=last to the marker recorded in markers for id. Without a
+// marker "last" means from the beginning.
+func (c *clientState) resolveSince(markers map[string]string, id, since string) string {
+	if since != "last" {
+		return since
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return markers[id]
+}
+
+// mark records the newest ID returned to this client for id.
+func (c *clientState) mark(markers map[string]string, id, last string) {
+	if last == "" {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	markers[id] = last
+}
+
+// clientRegistry tracks clientState per clientKey. Thread-safe.
+type clientRegistry struct {
+	mu      sync.Mutex
+	order   []string // least recently used first
+	clients map[string]*clientState
+}
+
+func newClientRegistry() *clientRegistry {
+	return &clientRegistry{clients: make(map[string]*clientState)}
+}
+
+// Get returns the state for key, creating it on first use.
+func (r *clientRegistry) Get(key string) *clientState {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if idx := slices.Index(r.order, key); idx >= 0 {
+		r.order = append(slices.Delete(r.order, idx, idx+1), key)
+		return r.clients[key]
+	}
+
+	state := &clientState{
+		oastLast:   make(map[string]string),
+		crawlLast:  make(map[string]string),
+		cookieJars: newCookieJarStore(),
+	}
+	r.clients[key] = state
+	r.order = append(r.order, key)
+	for len(r.order) > maxTrackedClients {
+		delete(r.clients, r.order[0])
+		r.order = slices.Delete(r.order, 0, 1)
+	}
+	return state
+}
+
+// Remove drops the state for key.
+func (r *clientRegistry) Remove(key string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if idx := slices.Index(r.order, key); idx >= 0 {
+		r.order = slices.Delete(r.order, idx, idx+1)
+		delete(r.clients, key)
+	}
+}
+
+// Count returns the number of tracked clients.
+func (r *clientRegistry) Count() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return len(r.clients)
+}
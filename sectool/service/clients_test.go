@@ -0,0 +1,164 @@
+package service
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	mcpclient "github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/client/transport"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/go-harden/llm-security-toolbox/sectool/protocol"
+)
+
+func TestClientRegistry(t *testing.T) {
+	t.Parallel()
+
+	t.Run("get_reuses_state", func(t *testing.T) {
+		r := newClientRegistry()
+		a := r.Get("a")
+		a.markProxyOffset(5)
+		assert.Same(t, a, r.Get("a"))
+		assert.NotSame(t, a, r.Get("b"))
+		assert.Equal(t, 2, r.Count())
+	})
+
+	t.Run("remove", func(t *testing.T) {
+		r := newClientRegistry()
+		r.Get("a").markProxyOffset(5)
+		r.Remove("a")
+		assert.Equal(t, 0, r.Count())
+		assert.Equal(t, uint32(0), r.Get("a").proxyNextOffset.Load())
+	})
+
+	t.Run("evicts_least_recently_used", func(t *testing.T) {
+		r := newClientRegistry()
+		first := r.Get("first")
+		for i := 0; i < maxTrackedClients-1; i++ {
+			r.Get(strconv.Itoa(i))
+		}
+		r.Get("first") // touch so "0" becomes the oldest
+		r.Get("overflow")
+
+		assert.Equal(t, maxTrackedClients, r.Count())
+		assert.Same(t, first, r.Get("first"))
+		assert.NotContains(t, r.clients, "0")
+	})
+
+	t.Run("mark_proxy_offset_never_decreases", func(t *testing.T) {
+		var c clientState
+		c.markProxyOffset(0)
+		assert.Equal(t, uint32(1), c.proxyNextOffset.Load())
+		c.markProxyOffset(7)
+		c.markProxyOffset(3)
+		assert.Equal(t, uint32(8), c.proxyNextOffset.Load())
+	})
+
+	t.Run("resolve_since", func(t *testing.T) {
+		c := newClientRegistry().Get("a")
+		assert.Empty(t, c.resolveSince(c.oastLast, "oast1", "last"))
+		assert.Equal(t, "evt1", c.resolveSince(c.oastLast, "oast1", "evt1"))
+
+		c.mark(c.oastLast, "oast1", "evt2")
+		assert.Equal(t, "evt2", c.resolveSince(c.oastLast, "oast1", "last"))
+		assert.Empty(t, c.resolveSince(c.oastLast, "oast2", "last"))
+	})
+}
+
+// connectHTTPClient opens a streamable HTTP MCP session to srv, optionally naming the client.
+func connectHTTPClient(t *testing.T, srv *Server, name string) *mcpclient.Client {
+	t.Helper()
+
+	var opts []transport.StreamableHTTPCOption
+	if name != "" {
+		opts = append(opts, transport.WithHTTPHeaders(map[string]string{protocol.ClientHeader: name}))
+	}
+	client, err := mcpclient.NewStreamableHttpClient("http://"+srv.mcpServer.Addr()+"/mcp", opts...)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = client.Close() })
+
+	ctx, cancel := context.WithTimeout(t.Context(), 10*time.Second)
+	defer cancel()
+	_, err = client.Initialize(ctx, mcp.InitializeRequest{
+		Params: mcp.InitializeParams{
+			ClientInfo:      mcp.Implementation{Name: "sectool-test", Version: "1.0.0"},
+			ProtocolVersion: mcp.LATEST_PROTOCOL_VERSION,
+		},
+	})
+	require.NoError(t, err)
+	return client
+}
+
+func TestMCP_PerClientSinceLast(t *testing.T) {
+	t.Parallel()
+
+	srv, _, mockMCP, _, _ := setupMCPServerWithMock(t)
+	addEntry := func(path string) {
+		mockMCP.AddProxyEntry("GET "+path+" HTTP/1.1\r\nHost: example.com\r\n\r\n", "HTTP/1.1 200 OK\r\n\r\n", "")
+	}
+	pollNew := func(client *mcpclient.Client) []string {
+		resp := CallMCPToolJSONOK[protocol.ProxyPollResponse](t, client, "proxy_poll", map[string]interface{}{
+			"output_mode": "flows",
+			"since":       "last",
+		})
+		paths := []string{}
+		for _, f := range resp.Flows {
+			paths = append(paths, f.Path)
+		}
+		return paths
+	}
+
+	addEntry("/a")
+	agentA := connectHTTPClient(t, srv, "")
+	agentB := connectHTTPClient(t, srv, "")
+
+	assert.Equal(t, []string{"/a"}, pollNew(agentA))
+	assert.Empty(t, pollNew(agentA))
+	assert.Equal(t, []string{"/a"}, pollNew(agentB)) // agentA's marker doesn't affect agentB
+
+	// Named clients share markers across sessions
+	assert.Equal(t, []string{"/a"}, pollNew(connectHTTPClient(t, srv, protocol.ClientNameCLI)))
+	assert.Empty(t, pollNew(connectHTTPClient(t, srv, protocol.ClientNameCLI)))
+
+	addEntry("/b")
+	assert.Equal(t, []string{"/b"}, pollNew(agentA))
+	assert.Equal(t, []string{"/b"}, pollNew(agentB))
+}
+
+func TestMCP_PerClientCookieJars(t *testing.T) {
+	t.Parallel()
+
+	srv, _, mockMCP, _, _ := setupMCPServerWithMock(t)
+	mockMCP.AddProxyEntry("GET /account HTTP/1.1\r\nHost: app.example.com\r\n\r\n", "HTTP/1.1 200 OK\r\n\r\n", "")
+	setJar := func(client *mcpclient.Client, value string) {
+		CallMCPToolJSONOK[protocol.CookieJarListResponse](t, client, "cookie_jar_set", map[string]interface{}{
+			"host":    "app.example.com",
+			"cookies": map[string]interface{}{"sid": value},
+		})
+	}
+	listJars := func(client *mcpclient.Client) []protocol.CookieJar {
+		return CallMCPToolJSONOK[protocol.CookieJarListResponse](t, client, "cookie_jar_list", nil).Jars
+	}
+
+	agentA := connectHTTPClient(t, srv, "")
+	agentB := connectHTTPClient(t, srv, "")
+	setJar(agentA, "a")
+	require.Len(t, listJars(agentA), 1)
+	assert.Empty(t, listJars(agentB)) // agentA's jar doesn't reach agentB
+
+	resp := CallMCPToolJSONOK[protocol.ReplaySendResponse](t, agentB, "replay_send", map[string]interface{}{"flow_id": "last"})
+	assert.Empty(t, resp.JarSent)
+	resp = CallMCPToolJSONOK[protocol.ReplaySendResponse](t, agentA, "replay_send", map[string]interface{}{"flow_id": "last"})
+	assert.Equal(t, []string{"sid"}, resp.JarSent)
+
+	// Named clients share jars across sessions
+	setJar(connectHTTPClient(t, srv, protocol.ClientNameCLI), "cli")
+	jars := listJars(connectHTTPClient(t, srv, protocol.ClientNameCLI))
+	require.Len(t, jars, 1)
+	assert.Equal(t, "cli", jars[0].Cookies[0].Value)
+	assert.Equal(t, "a", listJars(agentA)[0].Cookies[0].Value)
+}
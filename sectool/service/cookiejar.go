@@ -0,0 +1,223 @@
+package service
+
+import (
+	"maps"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-harden/llm-security-toolbox/sectool/protocol"
+)
+
+// jarCookie is a cookie held in a host's jar. Path and Secure follow RFC 6265;
+// Domain is ignored since jars are per hostname.
+type jarCookie struct {
+	Name    string
+	Value   string
+	Path    string
+	Secure  bool
+	Expires time.Time // zero for session cookies
+}
+
+func (c *jarCookie) expired(now time.Time) bool {
+	return !c.Expires.IsZero() && !c.Expires.After(now)
+}
+
+// cookieJar holds the cookies of one hostname, keyed by name and path.
+type cookieJar struct {
+	Host    string
+	Enabled bool
+	cookies map[string]*jarCookie
+}
+
+func jarKey(name, path string) string {
+	return name + "\x00" + path
+}
+
+// cookieJarStore holds cookie jars by hostname for the service lifetime. Thread-safe.
+type cookieJarStore struct {
+	mu   sync.Mutex
+	jars map[string]*cookieJar
+}
+
+func newCookieJarStore() *cookieJarStore {
+	return &cookieJarStore{jars: make(map[string]*cookieJar)}
+}
+
+// Update creates the jar for host if needed and applies the changes in order:
+// clear, remove by name, set (path /). enabled is left unchanged when nil, and
+// defaults to true for a new jar.
+func (s *cookieJarStore) Update(host string, enabled *bool, reset bool, remove []string, set map[string]string) {
+	host = strings.ToLower(host)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	jar, ok := s.jars[host]
+	if !ok {
+		jar = &cookieJar{Host: host, Enabled: true, cookies: make(map[string]*jarCookie)}
+		s.jars[host] = jar
+	}
+	if enabled != nil {
+		jar.Enabled = *enabled
+	}
+	if reset {
+		clear(jar.cookies)
+	}
+	maps.DeleteFunc(jar.cookies, func(_ string, c *jarCookie) bool {
+		return slices.Contains(remove, c.Name)
+	})
+	for name, value := range set {
+		jar.cookies[jarKey(name, "/")] = &jarCookie{Name: name, Value: value, Path: "/"}
+	}
+}
+
+// Delete removes the jar for a hostname, reporting whether it existed.
+func (s *cookieJarStore) Delete(host string) bool {
+	host = strings.ToLower(host)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, ok := s.jars[host]
+	delete(s.jars, host)
+	return ok
+}
+
+// Attach merges the unexpired cookies of host's enabled jar that match the
+// request path and scheme into the Cookie header of raw, replacing same-named
+// cookies. Returns the request and the names of the cookies attached.
+func (s *cookieJarStore) Attach(raw []byte, host string, usesHTTPS bool) ([]byte, []string) {
+	path := jarRequestPath(raw)
+	now := time.Now()
+	s.mu.Lock()
+	jar, ok := s.jars[strings.ToLower(host)]
+	var matched []*jarCookie
+	if ok && jar.Enabled {
+		for _, c := range jar.cookies {
+			if !c.expired(now) && (usesHTTPS || !c.Secure) && cookiePathMatch(path, c.Path) {
+				matched = append(matched, c)
+			}
+		}
+	}
+	s.mu.Unlock()
+	if len(matched) == 0 {
+		return raw, nil
+	}
+
+	// Longer paths first, as browsers send them; a name on several paths takes the most specific.
+	slices.SortStableFunc(matched, func(a, b *jarCookie) int {
+		if n := len(b.Path) - len(a.Path); n != 0 {
+			return n
+		}
+		return strings.Compare(a.Name, b.Name)
+	})
+	var names, pairs []string
+	for _, c := range matched {
+		if !slices.Contains(names, c.Name) {
+			names = append(names, c.Name)
+			pairs = append(pairs, c.Name+"="+c.Value)
+		}
+	}
+	headers, body := splitHeadersBody(raw)
+	value := mergeCookies(parseHeadersToMap(string(headers))["Cookie"], strings.Join(pairs, "; "))
+	headers = setHeader(removeHeader(headers, "Cookie"), "Cookie", value)
+	return append(headers, body...), names
+}
+
+// Capture stores the Set-Cookie headers of a response to request raw in host's
+// enabled jar; cookies that are expired or have a negative Max-Age are removed.
+// Returns the names stored or removed.
+func (s *cookieJarStore) Capture(raw []byte, host string, respHeaders []byte) []string {
+	cookies := (&http.Response{Header: http.Header(parseHeadersToMap(string(respHeaders)))}).Cookies()
+	if len(cookies) == 0 {
+		return nil
+	}
+	defaultPath := cookieDefaultPath(jarRequestPath(raw))
+	now := time.Now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	jar, ok := s.jars[strings.ToLower(host)]
+	if !ok || !jar.Enabled {
+		return nil
+	}
+	var names []string
+	for _, c := range cookies {
+		stored := &jarCookie{Name: c.Name, Value: c.Value, Path: c.Path, Secure: c.Secure}
+		if !strings.HasPrefix(stored.Path, "/") {
+			stored.Path = defaultPath
+		}
+		switch {
+		case c.MaxAge < 0:
+			stored.Expires = now
+		case c.MaxAge > 0:
+			stored.Expires = now.Add(time.Duration(c.MaxAge) * time.Second)
+		case !c.Expires.IsZero():
+			stored.Expires = c.Expires
+		}
+		key := jarKey(stored.Name, stored.Path)
+		if stored.expired(now) {
+			delete(jar.cookies, key)
+		} else {
+			jar.cookies[key] = stored
+		}
+		if !slices.Contains(names, c.Name) {
+			names = append(names, c.Name)
+		}
+	}
+	return names
+}
+
+// List returns all jars sorted by host, with unexpired cookies sorted by name and path.
+func (s *cookieJarStore) List() []protocol.CookieJar {
+	now := time.Now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make([]protocol.CookieJar, 0, len(s.jars))
+	for _, host := range slices.Sorted(maps.Keys(s.jars)) {
+		jar := s.jars[host]
+		info := protocol.CookieJar{Host: host, Enabled: jar.Enabled}
+		for _, key := range slices.Sorted(maps.Keys(jar.cookies)) {
+			c := jar.cookies[key]
+			if c.expired(now) {
+				continue
+			}
+			cookie := protocol.JarCookie{Name: c.Name, Value: c.Value, Path: c.Path, Secure: c.Secure}
+			if !c.Expires.IsZero() {
+				cookie.Expires = c.Expires.UTC().Format(time.RFC3339)
+			}
+			info.Cookies = append(info.Cookies, cookie)
+		}
+		out = append(out, info)
+	}
+	return out
+}
+
+// jarRequestPath returns the path of the request target, also for absolute-form targets.
+func jarRequestPath(raw []byte) string {
+	path := extractRequestPath(raw)
+	if !strings.HasPrefix(path, "/") {
+		if u, err := url.Parse(path); err == nil && u.Path != "" {
+			return u.Path
+		}
+		return "/"
+	}
+	return path
+}
+
+// cookieDefaultPath is the path of a cookie set without a Path attribute (RFC 6265 5.1.4).
+func cookieDefaultPath(requestPath string) string {
+	i := strings.LastIndex(requestPath, "/")
+	if i <= 0 {
+		return "/"
+	}
+	return requestPath[:i]
+}
+
+// cookiePathMatch reports whether a request path is within a cookie path (RFC 6265 5.1.4).
+func cookiePathMatch(requestPath, cookiePath string) bool {
+	if !strings.HasPrefix(requestPath, cookiePath) {
+		return false
+	}
+	return len(requestPath) == len(cookiePath) || strings.HasSuffix(cookiePath, "/") || requestPath[len(cookiePath)] == '/'
+}
@@ -0,0 +1,84 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCookieJarCaptureAttach(t *testing.T) {
+	t.Parallel()
+
+	jars := newCookieJarStore()
+	login := []byte("POST /app/login HTTP/1.1\r\nHost: app.example.com\r\n\r\n")
+	resp := []byte("HTTP/1.1 302 Found\r\n" +
+		"Set-Cookie: sid=s1; Path=/; HttpOnly\r\n" +
+		"Set-Cookie: pref=dark\r\n" +
+		"Set-Cookie: tok=t1; Path=/api; Secure\r\n" +
+		"Set-Cookie: gone=x; Max-Age=0\r\n\r\n")
+
+	assert.Nil(t, jars.Capture(login, "app.example.com", resp), "no jar for host")
+	jars.Update("App.Example.com", nil, false, nil, nil)
+	assert.Equal(t, []string{"sid", "pref", "tok", "gone"}, jars.Capture(login, "app.example.com", resp))
+
+	list := jars.List()
+	require.Len(t, list, 1)
+	assert.True(t, list[0].Enabled)
+	require.Len(t, list[0].Cookies, 3)
+	assert.Equal(t, "pref", list[0].Cookies[0].Name)
+	assert.Equal(t, "/app", list[0].Cookies[0].Path) // default path of /app/login
+
+	raw := []byte("GET /app/profile HTTP/1.1\r\nHost: app.example.com\r\nCookie: sid=old; theme=x\r\n\r\n")
+	got, sent := jars.Attach(raw, "app.example.com", true)
+	assert.Equal(t, []string{"pref", "sid"}, sent)
+	assert.Equal(t, "GET /app/profile HTTP/1.1\r\nHost: app.example.com\r\nCookie: sid=s1; theme=x; pref=dark\r\n\r\n", string(got))
+
+	_, sent = jars.Attach([]byte("GET /api/me HTTP/1.1\r\nHost: app.example.com\r\n\r\n"), "app.example.com", false)
+	assert.Equal(t, []string{"sid"}, sent, "secure cookie needs https, /app path does not match")
+	_, sent = jars.Attach([]byte("GET /api/me HTTP/1.1\r\nHost: app.example.com\r\n\r\n"), "app.example.com", true)
+	assert.Equal(t, []string{"tok", "sid"}, sent)
+
+	jars.Capture(login, "app.example.com", []byte("HTTP/1.1 200 OK\r\nSet-Cookie: sid=; Path=/; Expires=Thu, 01 Jan 1970 00:00:00 GMT\r\n\r\n"))
+	_, sent = jars.Attach(raw, "app.example.com", true)
+	assert.Equal(t, []string{"pref"}, sent)
+
+	disabled := false
+	jars.Update("app.example.com", &disabled, false, nil, nil)
+	got, sent = jars.Attach(raw, "app.example.com", true)
+	assert.Empty(t, sent)
+	assert.Equal(t, raw, got)
+}
+
+func TestCookieJarUpdate(t *testing.T) {
+	t.Parallel()
+
+	jars := newCookieJarStore()
+	jars.Update("x.test", nil, false, nil, map[string]string{"a": "1", "b": "2"})
+	jars.Update("x.test", nil, false, []string{"a"}, map[string]string{"c": "3"})
+	list := jars.List()
+	require.Len(t, list, 1)
+	require.Len(t, list[0].Cookies, 2)
+	assert.Equal(t, "b", list[0].Cookies[0].Name)
+	assert.Equal(t, "c", list[0].Cookies[1].Name)
+
+	jars.Update("x.test", nil, true, nil, nil)
+	assert.Empty(t, jars.List()[0].Cookies)
+	assert.True(t, jars.Delete("X.test"))
+	assert.False(t, jars.Delete("x.test"))
+}
+
+func TestCookiePathMatch(t *testing.T) {
+	t.Parallel()
+
+	assert.True(t, cookiePathMatch("/", "/"))
+	assert.True(t, cookiePathMatch("/app/x", "/app"))
+	assert.True(t, cookiePathMatch("/app/x", "/app/"))
+	assert.True(t, cookiePathMatch("/app", "/app"))
+	assert.False(t, cookiePathMatch("/application", "/app"))
+	assert.False(t, cookiePathMatch("/", "/app"))
+
+	assert.Equal(t, "/", cookieDefaultPath("/login"))
+	assert.Equal(t, "/a/b", cookieDefaultPath("/a/b/c"))
+	assert.Equal(t, "/a", jarRequestPath([]byte("GET http://x.test/a?q=1 HTTP/1.1\r\n\r\n")))
+}
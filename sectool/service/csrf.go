@@ -0,0 +1,581 @@
+package service
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/http"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+
+	"github.com/go-harden/llm-security-toolbox/sectool/bundle"
+	"github.com/go-harden/llm-security-toolbox/sectool/protocol"
+)
+
+// csrfNamePattern matches the field, cookie and header names of anti-CSRF tokens.
+var csrfNamePattern = regexp.MustCompile(`(?i)csrf|xsrf|authenticity_token|verificationtoken|antiforgery|^_token$`)
+
+// Token locations reported by csrf_analyze. Responses issue a token in the first
+// five, requests send it in the rest.
+const (
+	csrfInHiddenInput = "hidden_input"
+	csrfInMeta        = "meta"
+	csrfInSetCookie   = "set_cookie"
+	csrfInRespHeader  = "response_header"
+	csrfInRespJSON    = "response_json"
+	csrfInHeader      = "header"
+	csrfInForm        = "form"
+	csrfInMultipart   = "multipart"
+	csrfInJSON        = "json"
+	csrfInQuery       = "query"
+)
+
+type csrfExtraction struct{ location, kind string }
+
+// csrfIssueExtract is the csrf_set extraction that reads a token from where it is
+// issued, in order of preference for suggestions.
+var csrfIssueExtract = []csrfExtraction{
+	{csrfInHiddenInput, extractField},
+	{csrfInMeta, extractField},
+	{csrfInRespHeader, extractHeader},
+	{csrfInRespJSON, extractJSON},
+	{csrfInSetCookie, extractCookie},
+}
+
+// csrfMaxUnprotected caps the unprotected endpoints listed per host.
+const csrfMaxUnprotected = 20
+
+var multipartNameRe = regexp.MustCompile(`(?i)content-disposition:[^\r\n]*\bname="([^"]+)"`)
+
+// csrfTokenRef is a token name in one location.
+type csrfTokenRef struct {
+	name     string
+	location string
+}
+
+// csrfIssuedTokens returns the tokens a response hands out in its headers, cookies,
+// HTML form fields and meta tags, or top-level JSON keys.
+func csrfIssuedTokens(response []byte) []csrfTokenRef {
+	headers, body := splitHeadersBody(response)
+	body, _ = bundle.DecodeBody(string(headers), body)
+	h := http.Header(parseHeadersToMap(string(headers)))
+
+	var refs []csrfTokenRef
+	for _, name := range slices.Sorted(maps.Keys(h)) {
+		if csrfNamePattern.MatchString(name) {
+			refs = append(refs, csrfTokenRef{name: name, location: csrfInRespHeader})
+		}
+	}
+	for _, c := range (&http.Response{Header: h}).Cookies() {
+		if c.MaxAge >= 0 && csrfNamePattern.MatchString(c.Name) {
+			refs = append(refs, csrfTokenRef{name: c.Name, location: csrfInSetCookie})
+		}
+	}
+
+	contentType := strings.ToLower(h.Get("Content-Type"))
+	switch {
+	case strings.Contains(contentType, "html"):
+		doc, err := goquery.NewDocumentFromReader(bytes.NewReader(body))
+		if err != nil {
+			break
+		}
+		doc.Find("input[name], meta[name]").Each(func(_ int, s *goquery.Selection) {
+			name := s.AttrOr("name", "")
+			if !csrfNamePattern.MatchString(name) {
+				return
+			}
+			location := csrfInHiddenInput
+			if goquery.NodeName(s) == "meta" {
+				location = csrfInMeta
+			}
+			refs = append(refs, csrfTokenRef{name: name, location: location})
+		})
+	case strings.Contains(contentType, "json"):
+		for _, name := range csrfJSONKeys(body) {
+			refs = append(refs, csrfTokenRef{name: name, location: csrfInRespJSON})
+		}
+	}
+	return refs
+}
+
+// htmlFieldValue returns the value of the first input, or the content of the first
+// meta tag, with the given name.
+func htmlFieldValue(body []byte, name string) (string, bool) {
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(body))
+	if err != nil {
+		return "", false
+	}
+	var value string
+	var found bool
+	doc.Find("input[name], meta[name]").EachWithBreak(func(_ int, s *goquery.Selection) bool {
+		if s.AttrOr("name", "") != name {
+			return true
+		}
+		attr := "value"
+		if goquery.NodeName(s) == "meta" {
+			attr = "content"
+		}
+		value, found = s.AttrOr(attr, ""), true
+		return false
+	})
+	return value, found
+}
+
+// csrfSentTokens returns the tokens a request carries in headers, the query string
+// or a form, multipart or JSON body. Cookies are left out: a cookie alone does not
+// stop a cross-site request.
+func csrfSentTokens(request []byte) []csrfTokenRef {
+	headers, body := splitHeadersBody(request)
+	h := http.Header(parseHeadersToMap(string(headers)))
+
+	var refs []csrfTokenRef
+	for _, name := range slices.Sorted(maps.Keys(h)) {
+		if name != "Cookie" && csrfNamePattern.MatchString(name) {
+			refs = append(refs, csrfTokenRef{name: name, location: csrfInHeader})
+		}
+	}
+	addParams := func(encoded, location string) {
+		values, _ := url.ParseQuery(encoded)
+		for _, name := range slices.Sorted(maps.Keys(values)) {
+			if csrfNamePattern.MatchString(name) {
+				refs = append(refs, csrfTokenRef{name: name, location: location})
+			}
+		}
+	}
+	if line, _, ok := bytes.Cut(headers, []byte("\r\n")); ok {
+		_, _, query, _ := parseRequestLine(string(line))
+		addParams(query, csrfInQuery)
+	}
+
+	contentType := strings.ToLower(h.Get("Content-Type"))
+	switch {
+	case strings.Contains(contentType, "application/x-www-form-urlencoded"):
+		addParams(string(body), csrfInForm)
+	case strings.Contains(contentType, "multipart/form-data"):
+		for _, m := range multipartNameRe.FindAllSubmatch(body, -1) {
+			if name := string(m[1]); csrfNamePattern.MatchString(name) {
+				refs = append(refs, csrfTokenRef{name: name, location: csrfInMultipart})
+			}
+		}
+	case strings.Contains(contentType, "json"):
+		for _, name := range csrfJSONKeys(body) {
+			refs = append(refs, csrfTokenRef{name: name, location: csrfInJSON})
+		}
+	}
+	return refs
+}
+
+// csrfJSONKeys returns the top-level keys of a JSON object body that name a token.
+func csrfJSONKeys(body []byte) []string {
+	var data map[string]interface{}
+	if err := json.Unmarshal(body, &data); err != nil {
+		return nil
+	}
+	var names []string
+	for _, name := range slices.Sorted(maps.Keys(data)) {
+		if _, ok := data[name].(string); ok && csrfNamePattern.MatchString(name) {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// csrfNameKey folds a token name so that a cookie or field matches the header or
+// parameter it is echoed in, e.g. XSRF-TOKEN and X-XSRF-TOKEN.
+func csrfNameKey(name string) string {
+	name = strings.TrimPrefix(strings.ToLower(name), "x-")
+	return strings.NewReplacer("-", "", "_", "").Replace(name)
+}
+
+func stateChangingMethod(method string) bool {
+	switch strings.ToUpper(method) {
+	case "POST", "PUT", "PATCH", "DELETE":
+		return true
+	}
+	return false
+}
+
+// analyzeCSRF summarizes anti-CSRF tokens per host over the flows, and lists the
+// state-changing requests sent with cookies but without a token. register assigns
+// example flow IDs; an issuing example is a GET when one exists.
+func analyzeCSRF(entries []flowEntry, register func(flowEntry) string) []protocol.CSRFHostSummary {
+	type tokenAgg struct {
+		count   int
+		example flowEntry
+	}
+	type endpointKey struct{ method, path string }
+	type csrfHost struct {
+		summary     protocol.CSRFHostSummary
+		tokens      map[csrfTokenRef]*tokenAgg
+		unprotected map[endpointKey]*tokenAgg
+	}
+	hosts := make(map[string]*csrfHost)
+	host := func(name string) *csrfHost {
+		h, ok := hosts[name]
+		if !ok {
+			h = &csrfHost{
+				summary:     protocol.CSRFHostSummary{Host: name},
+				tokens:      make(map[csrfTokenRef]*tokenAgg),
+				unprotected: make(map[endpointKey]*tokenAgg),
+			}
+			hosts[name] = h
+		}
+		return h
+	}
+	count := func(aggs map[csrfTokenRef]*tokenAgg, ref csrfTokenRef, entry flowEntry) {
+		agg, ok := aggs[ref]
+		if !ok {
+			agg = &tokenAgg{example: entry}
+			aggs[ref] = agg
+		} else if agg.example.method != "GET" && entry.method == "GET" {
+			agg.example = entry
+		}
+		agg.count++
+	}
+
+	for _, entry := range entries {
+		var h *csrfHost
+		if entry.status != 0 {
+			for _, ref := range csrfIssuedTokens([]byte(entry.response)) {
+				h = host(entry.host)
+				count(h.tokens, ref, entry)
+			}
+		}
+		sent := csrfSentTokens([]byte(entry.request))
+		for _, ref := range sent {
+			h = host(entry.host)
+			count(h.tokens, ref, entry)
+		}
+		reqHeaders, _ := splitHeadersBody([]byte(entry.request))
+		if !stateChangingMethod(entry.method) || http.Header(parseHeadersToMap(string(reqHeaders))).Get("Cookie") == "" {
+			continue
+		}
+		h = host(entry.host)
+		h.summary.Requests++
+		if len(sent) == 0 {
+			key := endpointKey{method: entry.method, path: normalizePath(pathWithoutQuery(entry.path))}
+			if agg, ok := h.unprotected[key]; ok {
+				agg.count++
+			} else {
+				h.unprotected[key] = &tokenAgg{count: 1, example: entry}
+			}
+		}
+	}
+
+	summaries := make([]protocol.CSRFHostSummary, 0, len(hosts))
+	for _, h := range hosts {
+		for ref, agg := range h.tokens {
+			h.summary.Tokens = append(h.summary.Tokens, protocol.CSRFToken{
+				Name:     ref.name,
+				Location: ref.location,
+				Count:    agg.count,
+				FlowID:   register(agg.example),
+			})
+		}
+		slices.SortFunc(h.summary.Tokens, func(a, b protocol.CSRFToken) int {
+			return cmp.Or(cmp.Compare(b.Count, a.Count), strings.Compare(a.Name, b.Name), strings.Compare(a.Location, b.Location))
+		})
+		for key, agg := range h.unprotected {
+			h.summary.Unprotected = append(h.summary.Unprotected, protocol.CSRFRequest{
+				Method: key.method,
+				Path:   key.path,
+				Count:  agg.count,
+				FlowID: register(agg.example),
+			})
+		}
+		slices.SortFunc(h.summary.Unprotected, func(a, b protocol.CSRFRequest) int {
+			return cmp.Or(cmp.Compare(b.Count, a.Count), strings.Compare(a.Path, b.Path), strings.Compare(a.Method, b.Method))
+		})
+		if len(h.summary.Unprotected) > csrfMaxUnprotected {
+			h.summary.Unprotected = h.summary.Unprotected[:csrfMaxUnprotected]
+		}
+		h.summary.Suggest = csrfSuggestion(h.summary.Tokens)
+		summaries = append(summaries, h.summary)
+	}
+	slices.SortFunc(summaries, func(a, b protocol.CSRFHostSummary) int { return strings.Compare(a.Host, b.Host) })
+	return summaries
+}
+
+// csrfSuggestion returns csrf_set arguments pairing the most sent token with where
+// a response issues it, or "" when no issuing response was seen. tokens are sorted
+// most seen first.
+func csrfSuggestion(tokens []protocol.CSRFToken) string {
+	issued := func(location string) bool {
+		return slices.ContainsFunc(csrfIssueExtract, func(e csrfExtraction) bool { return e.location == location })
+	}
+	var sent *protocol.CSRFToken
+	for i := range tokens {
+		if !issued(tokens[i].Location) {
+			sent = &tokens[i]
+			break
+		}
+	}
+	for _, e := range csrfIssueExtract {
+		for _, t := range tokens {
+			if t.Location != e.location || (sent != nil && csrfNameKey(t.Name) != csrfNameKey(sent.Name)) {
+				continue
+			}
+			args := "flow_id=" + t.FlowID + " extract_" + e.kind + "=" + t.Name
+			switch {
+			case sent == nil && e.kind == extractCookie:
+				continue // a cookie token is echoed somewhere not yet seen
+			case sent == nil:
+				return args + " param=" + t.Name
+			case sent.Location == csrfInHeader:
+				return args + " header=" + sent.Name
+			default:
+				return args + " param=" + sent.Name
+			}
+		}
+	}
+	return ""
+}
+
+// csrfRule fetches a fresh anti-CSRF token for replays to one host. The request for
+// the page that issues the token is resent with the replay's credentials, the token
+// is read with Extractor, and set as Param and/or Header on the replay.
+type csrfRule struct {
+	Host      string // lowercase hostname, without port
+	Base      string // flow_id or replay_id the token page request came from
+	Request   []byte
+	Target    Target
+	Extractor tokenExtractor
+	Param     string // body or query parameter set to the token
+	Header    string // request header set to the token
+
+	mu        sync.Mutex
+	token     string
+	fetchedAt time.Time
+	lastErr   string
+}
+
+// csrfState is a consistent snapshot of a rule's last fetch.
+type csrfState struct {
+	Token     string
+	FetchedAt time.Time
+	LastErr   string
+}
+
+// State returns the result of the last fetch.
+func (r *csrfRule) State() csrfState {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return csrfState{Token: r.token, FetchedAt: r.fetchedAt, LastErr: r.lastErr}
+}
+
+// Fetch requests the token page with the Cookie and Authorization headers of raw,
+// when given, and returns the token and the cookies the page set.
+func (r *csrfRule) Fetch(ctx context.Context, s *Server, raw []byte) (string, []*http.Cookie, error) {
+	page := r.Request
+	if raw != nil {
+		page = withCredentials(page, raw)
+	}
+	result, err := s.sendRequest(ctx, "sectool-csrf-"+r.Host, SendRequestInput{
+		RawRequest: page,
+		Target:     r.Target,
+		Timeout:    30 * time.Second,
+	}, nil)
+	var token string
+	var cookies []*http.Cookie
+	if err == nil {
+		code, _ := parseResponseStatus(result.Headers)
+		cookies = (&http.Response{Header: http.Header(parseHeadersToMap(string(result.Headers)))}).Cookies()
+		if token, err = r.Extractor.ExtractFrom("token page", result.Headers, result.Body); err == nil && token == "" {
+			err = fmt.Errorf("token page (status %d) has an empty token", code)
+		} else if err != nil {
+			err = fmt.Errorf("%w (status %d)", err, code)
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err != nil {
+		r.lastErr = err.Error()
+		return "", nil, fmt.Errorf("CSRF token fetch for %s failed: %w", r.Host, err)
+	}
+	r.token, r.fetchedAt, r.lastErr = token, time.Now(), ""
+	return token, cookies, nil
+}
+
+// Apply merges the cookies set by the token page into the request's Cookie header
+// and sets the token as the rule's header and parameter.
+func (r *csrfRule) Apply(raw []byte, token string, cookies []*http.Cookie) []byte {
+	headers, body := splitHeadersBody(raw)
+	var pairs []string
+	for _, c := range cookies {
+		if c.MaxAge >= 0 {
+			pairs = append(pairs, c.Name+"="+c.Value)
+		}
+	}
+	if len(pairs) > 0 {
+		value := mergeCookies(parseHeadersToMap(string(headers))["Cookie"], strings.Join(pairs, "; "))
+		headers = setHeader(removeHeader(headers, "Cookie"), "Cookie", value)
+	}
+	if r.Header != "" {
+		headers = setHeader(headers, r.Header, token)
+	}
+	raw = append(headers, body...)
+	if r.Param != "" {
+		raw = setRequestParam(raw, r.Param, token)
+	}
+	return raw
+}
+
+// withCredentials replaces the Cookie and Authorization headers of page with those
+// of raw, where raw has them.
+func withCredentials(page, raw []byte) []byte {
+	rawHeaders, _ := splitHeadersBody(raw)
+	creds := http.Header(parseHeadersToMap(string(rawHeaders)))
+	headers, body := splitHeadersBody(page)
+	if cookies := creds.Values("Cookie"); len(cookies) > 0 {
+		headers = setHeader(removeHeader(headers, "Cookie"), "Cookie", mergeCookies(cookies, ""))
+	}
+	if auth := creds.Get("Authorization"); auth != "" {
+		headers = setHeader(headers, "Authorization", auth)
+	}
+	return append(headers, body...)
+}
+
+// setRequestParam sets a parameter in a form, multipart or JSON request body, or in
+// the query string for other requests. Form and query parameters keep their order,
+// and a multipart field is only replaced, never added.
+func setRequestParam(raw []byte, name, value string) []byte {
+	headers, body := splitHeadersBody(raw)
+	contentType := strings.ToLower(http.Header(parseHeadersToMap(string(headers))).Get("Content-Type"))
+	switch {
+	case strings.Contains(contentType, "application/x-www-form-urlencoded"):
+		body = []byte(setEncodedParam(string(body), name, value))
+	case strings.Contains(contentType, "multipart/form-data"):
+		body = setMultipartField(body, name, value)
+	case strings.Contains(contentType, "json"):
+		if modified, err := setJSONString(body, name, value); err == nil {
+			body = modified
+		}
+	default:
+		lineEnd := bytes.Index(headers, []byte("\r\n"))
+		if lineEnd < 0 {
+			return raw
+		}
+		method, path, query, version := parseRequestLine(string(headers[:lineEnd]))
+		line := buildRequestLine(method, path, setEncodedParam(query, name, value), version)
+		return append(append([]byte(line), headers[lineEnd:]...), body...)
+	}
+	headers = updateContentLength(headers, len(body))
+	return append(headers, body...)
+}
+
+// setEncodedParam sets name to value in a URL-encoded string, replacing every
+// existing occurrence in place or appending it.
+func setEncodedParam(encoded, name, value string) string {
+	var pairs []string
+	if encoded != "" {
+		pairs = strings.Split(encoded, "&")
+	}
+	escaped := url.QueryEscape(value)
+	var found bool
+	for i, pair := range pairs {
+		key, _, _ := strings.Cut(pair, "=")
+		if k, err := url.QueryUnescape(key); err == nil && k == name {
+			pairs[i] = key + "=" + escaped
+			found = true
+		}
+	}
+	if !found {
+		pairs = append(pairs, url.QueryEscape(name)+"="+escaped)
+	}
+	return strings.Join(pairs, "&")
+}
+
+// setMultipartField replaces the value of the named multipart form field.
+func setMultipartField(body []byte, name, value string) []byte {
+	re := regexp.MustCompile(`(?is)content-disposition:[^\r\n]*\bname="` + regexp.QuoteMeta(name) + `"[^\r\n]*\r\n(?:[^\r\n]+\r\n)*\r\n(.*?)\r\n--`)
+	loc := re.FindSubmatchIndex(body)
+	if loc == nil {
+		return body
+	}
+	out := append(slices.Clone(body[:loc[2]]), value...)
+	return append(out, body[loc[3]:]...)
+}
+
+// setJSONString sets a JSON path to a string value, without the type inference of
+// set_json so numeric tokens stay strings.
+func setJSONString(body []byte, path, value string) ([]byte, error) {
+	if len(bytes.TrimSpace(body)) == 0 {
+		body = []byte("{}")
+	}
+	var data interface{}
+	if err := json.Unmarshal(body, &data); err != nil {
+		return nil, err
+	}
+	segments, err := parseJSONPath(path)
+	if err != nil {
+		return nil, err
+	}
+	if data, err = setValueAtPath(data, segments, value); err != nil {
+		return nil, err
+	}
+	return json.Marshal(data)
+}
+
+// csrfStore holds CSRF rules by host for the service lifetime. Thread-safe.
+type csrfStore struct {
+	mu    sync.Mutex
+	rules map[string]*csrfRule
+}
+
+func newCSRFStore() *csrfStore {
+	return &csrfStore{rules: make(map[string]*csrfRule)}
+}
+
+// Get returns the rule for a hostname.
+func (s *csrfStore) Get(host string) (*csrfRule, error) {
+	host = strings.ToLower(host)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	rule, ok := s.rules[host]
+	if !ok {
+		if len(s.rules) == 0 {
+			return nil, fmt.Errorf("no CSRF rule for %s: create one with csrf_set", host)
+		}
+		return nil, fmt.Errorf("no CSRF rule for %s (rules exist for: %s)", host, strings.Join(slices.Sorted(maps.Keys(s.rules)), ", "))
+	}
+	return rule, nil
+}
+
+// Set adds or replaces the rule for its host.
+func (s *csrfStore) Set(rule *csrfRule) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.rules[rule.Host] = rule
+}
+
+// Delete removes the rule for a hostname, reporting whether it existed.
+func (s *csrfStore) Delete(host string) bool {
+	host = strings.ToLower(host)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, ok := s.rules[host]
+	delete(s.rules, host)
+	return ok
+}
+
+// List returns all rules sorted by host.
+func (s *csrfStore) List() []*csrfRule {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	hosts := slices.Sorted(maps.Keys(s.rules))
+	out := make([]*csrfRule, len(hosts))
+	for i, host := range hosts {
+		out[i] = s.rules[host]
+	}
+	return out
+}
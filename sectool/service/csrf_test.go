@@ -0,0 +1,144 @@
+package service
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCSRFIssuedTokens(t *testing.T) {
+	t.Parallel()
+
+	html := "HTTP/1.1 200 OK\r\nContent-Type: text/html\r\nSet-Cookie: XSRF-TOKEN=c1; Path=/\r\nSet-Cookie: sid=s1\r\n\r\n" +
+		`<html><head><meta name="csrf-token" content="m1"></head><body><form><input type="hidden" name="authenticity_token" value="f1"><input name="q"></form></body></html>`
+	assert.Equal(t, []csrfTokenRef{
+		{name: "XSRF-TOKEN", location: csrfInSetCookie},
+		{name: "csrf-token", location: csrfInMeta},
+		{name: "authenticity_token", location: csrfInHiddenInput},
+	}, csrfIssuedTokens([]byte(html)))
+
+	json := "HTTP/1.1 200 OK\r\nContent-Type: application/json\r\nX-CSRF-Token: h1\r\n\r\n{\"csrfToken\":\"j1\",\"count\":1}"
+	assert.Equal(t, []csrfTokenRef{
+		{name: "X-Csrf-Token", location: csrfInRespHeader},
+		{name: "csrfToken", location: csrfInRespJSON},
+	}, csrfIssuedTokens([]byte(json)))
+}
+
+func TestCSRFSentTokens(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, []csrfTokenRef{
+		{name: "X-Xsrf-Token", location: csrfInHeader},
+		{name: "_csrf", location: csrfInQuery},
+		{name: "csrfmiddlewaretoken", location: csrfInForm},
+	}, csrfSentTokens([]byte("POST /save?_csrf=a HTTP/1.1\r\nHost: x\r\nCookie: csrftoken=c\r\nX-XSRF-TOKEN: b\r\nContent-Type: application/x-www-form-urlencoded\r\n\r\nname=n&csrfmiddlewaretoken=d")))
+
+	multipart := "POST /upload HTTP/1.1\r\nHost: x\r\nContent-Type: multipart/form-data; boundary=b\r\n\r\n" +
+		"--b\r\nContent-Disposition: form-data; name=\"_token\"\r\n\r\nt\r\n--b--\r\n"
+	assert.Equal(t, []csrfTokenRef{{name: "_token", location: csrfInMultipart}}, csrfSentTokens([]byte(multipart)))
+	assert.Empty(t, csrfSentTokens([]byte("POST /api HTTP/1.1\r\nHost: x\r\nContent-Type: application/json\r\n\r\n{\"token\":\"x\"}")))
+}
+
+func TestAnalyzeCSRF(t *testing.T) {
+	t.Parallel()
+
+	entries := []flowEntry{
+		{offset: 1, method: "GET", host: "app.example.com", path: "/profile", status: 200,
+			request:  "GET /profile HTTP/1.1\r\nHost: app.example.com\r\nCookie: sid=1\r\n\r\n",
+			response: "HTTP/1.1 200 OK\r\nContent-Type: text/html\r\n\r\n<form><input type=\"hidden\" name=\"csrf_token\" value=\"abc\"></form>"},
+		{offset: 2, method: "POST", host: "app.example.com", path: "/profile", status: 302,
+			request:  "POST /profile HTTP/1.1\r\nHost: app.example.com\r\nCookie: sid=1\r\nContent-Type: application/x-www-form-urlencoded\r\n\r\nname=a&csrf_token=abc",
+			response: "HTTP/1.1 302 Found\r\nLocation: /profile\r\n\r\n"},
+		{offset: 3, method: "POST", host: "app.example.com", path: "/users/12/delete", status: 200,
+			request: "POST /users/12/delete HTTP/1.1\r\nHost: app.example.com\r\nCookie: sid=1\r\n\r\n", response: "HTTP/1.1 200 OK\r\n\r\n"},
+		{offset: 4, method: "POST", host: "app.example.com", path: "/users/13/delete", status: 200,
+			request: "POST /users/13/delete HTTP/1.1\r\nHost: app.example.com\r\nCookie: sid=1\r\n\r\n", response: "HTTP/1.1 200 OK\r\n\r\n"},
+		{offset: 5, method: "POST", host: "api.example.com", path: "/v1/items", status: 201,
+			request: "POST /v1/items HTTP/1.1\r\nHost: api.example.com\r\nAuthorization: Bearer t\r\n\r\n{}", response: "HTTP/1.1 201 Created\r\n\r\n"},
+	}
+	register := func(e flowEntry) string { return "f" + string(rune('0'+e.offset)) }
+
+	hosts := analyzeCSRF(entries, register)
+	require.Len(t, hosts, 1) // api.example.com: no cookies, no tokens
+	h := hosts[0]
+	assert.Equal(t, "app.example.com", h.Host)
+	assert.Equal(t, 3, h.Requests)
+	require.Len(t, h.Tokens, 2)
+	assert.Equal(t, "csrf_token", h.Tokens[0].Name)
+	assert.Equal(t, csrfInForm, h.Tokens[0].Location)
+	assert.Equal(t, "f2", h.Tokens[0].FlowID)
+	assert.Equal(t, csrfInHiddenInput, h.Tokens[1].Location)
+	assert.Equal(t, "f1", h.Tokens[1].FlowID)
+	require.Len(t, h.Unprotected, 1)
+	assert.Equal(t, "/users/*/delete", h.Unprotected[0].Path)
+	assert.Equal(t, 2, h.Unprotected[0].Count)
+	assert.Equal(t, "flow_id=f1 extract_field=csrf_token param=csrf_token", h.Suggest)
+}
+
+func TestCSRFSuggestionPairsCookieAndHeader(t *testing.T) {
+	t.Parallel()
+
+	entries := []flowEntry{
+		{offset: 1, method: "GET", host: "spa.example.com", path: "/", status: 200,
+			request: "GET / HTTP/1.1\r\nHost: spa.example.com\r\n\r\n", response: "HTTP/1.1 200 OK\r\nSet-Cookie: XSRF-TOKEN=abc\r\n\r\n"},
+		{offset: 2, method: "PUT", host: "spa.example.com", path: "/api/me", status: 200,
+			request: "PUT /api/me HTTP/1.1\r\nHost: spa.example.com\r\nCookie: XSRF-TOKEN=abc\r\nX-XSRF-TOKEN: abc\r\n\r\n{}", response: "HTTP/1.1 200 OK\r\n\r\n"},
+	}
+	hosts := analyzeCSRF(entries, func(e flowEntry) string { return "f" + string(rune('0'+e.offset)) })
+	require.Len(t, hosts, 1)
+	assert.Empty(t, hosts[0].Unprotected)
+	assert.Equal(t, "flow_id=f1 extract_cookie=XSRF-TOKEN header=X-Xsrf-Token", hosts[0].Suggest)
+}
+
+func TestSetRequestParam(t *testing.T) {
+	t.Parallel()
+
+	form := "POST /save HTTP/1.1\r\nHost: x\r\nContent-Type: application/x-www-form-urlencoded\r\nContent-Length: 18\r\n\r\ncsrf=old&name=a+b"
+	assert.Equal(t, "POST /save HTTP/1.1\r\nHost: x\r\nContent-Type: application/x-www-form-urlencoded\r\nContent-Length: 20\r\n\r\ncsrf=n%2Fw1&name=a+b",
+		string(setRequestParam([]byte(form), "csrf", "n/w1")))
+
+	json := "PUT /api HTTP/1.1\r\nHost: x\r\nContent-Type: application/json\r\nContent-Length: 8\r\n\r\n{\"a\":1}"
+	assert.Equal(t, "PUT /api HTTP/1.1\r\nHost: x\r\nContent-Type: application/json\r\nContent-Length: 21\r\n\r\n{\"_csrf\":\"123\",\"a\":1}",
+		string(setRequestParam([]byte(json), "_csrf", "123")))
+
+	multipart := "POST /up HTTP/1.1\r\nHost: x\r\nContent-Type: multipart/form-data; boundary=b\r\n\r\n" +
+		"--b\r\nContent-Disposition: form-data; name=\"_token\"\r\n\r\nold\r\n--b\r\nContent-Disposition: form-data; name=\"f\"\r\n\r\nv\r\n--b--\r\n"
+	got := string(setRequestParam([]byte(multipart), "_token", "fresh"))
+	assert.Contains(t, got, "name=\"_token\"\r\n\r\nfresh\r\n--b\r\n")
+	assert.Contains(t, got, "name=\"f\"\r\n\r\nv\r\n")
+
+	assert.Equal(t, "GET /delete?id=1&token=t HTTP/1.1\r\nHost: x\r\n\r\n",
+		string(setRequestParam([]byte("GET /delete?id=1 HTTP/1.1\r\nHost: x\r\n\r\n"), "token", "t")))
+}
+
+func TestCSRFRuleApply(t *testing.T) {
+	t.Parallel()
+
+	rule := &csrfRule{Header: "X-CSRF-Token", Param: "csrf"}
+	raw := "POST /save HTTP/1.1\r\nHost: x\r\nCookie: sid=1; csrftoken=old\r\nContent-Type: application/x-www-form-urlencoded\r\nContent-Length: 8\r\n\r\ncsrf=old"
+	got := string(rule.Apply([]byte(raw), "new", []*http.Cookie{{Name: "csrftoken", Value: "c2"}}))
+	assert.Contains(t, got, "Cookie: sid=1; csrftoken=c2\r\n")
+	assert.Contains(t, got, "X-CSRF-Token: new\r\n")
+	assert.Contains(t, got, "\r\n\r\ncsrf=new")
+
+	page := withCredentials([]byte("GET /form HTTP/1.1\r\nHost: x\r\nCookie: sid=captured\r\n\r\n"), []byte(raw))
+	assert.Equal(t, "GET /form HTTP/1.1\r\nHost: x\r\nCookie: sid=1; csrftoken=old\r\n\r\n", string(page))
+}
+
+func TestTokenExtractorField(t *testing.T) {
+	t.Parallel()
+
+	body := []byte(`<html><head><meta name="csrf-token" content="m1"></head><form><input name="_csrf" value="f1"></form></html>`)
+	for expr, want := range map[string]string{"csrf-token": "m1", "_csrf": "f1"} {
+		e, err := newTokenExtractor(extractField, expr)
+		require.NoError(t, err)
+		got, err := e.ExtractFrom("token page", nil, body)
+		require.NoError(t, err)
+		assert.Equal(t, want, got)
+	}
+	e, _ := newTokenExtractor(extractField, "missing")
+	_, err := e.ExtractFrom("token page", nil, body)
+	assert.EqualError(t, err, "no input or meta tag named missing in token page")
+}
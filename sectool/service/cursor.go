@@ -0,0 +1,29 @@
+package service
+
+import (
+	"encoding/base64"
+	"errors"
+	"strings"
+)
+
+// Page cursors are opaque tokens returned as next_cursor by list-style tools.
+// Each token carries the tool it was issued for so that a cursor from one tool
+// can't silently resume a different listing.
+
+// encodeCursor builds an opaque cursor for tool resuming after pos.
+func encodeCursor(tool, pos string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(tool + ":" + pos))
+}
+
+// decodeCursor returns the position stored in a cursor issued by tool.
+func decodeCursor(tool, cursor string) (string, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return "", errors.New("invalid cursor")
+	}
+	prefix, pos, ok := strings.Cut(string(raw), ":")
+	if !ok || prefix != tool || pos == "" {
+		return "", errors.New("invalid cursor for " + tool)
+	}
+	return pos, nil
+}
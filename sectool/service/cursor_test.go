@@ -0,0 +1,28 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeCursor(t *testing.T) {
+	t.Parallel()
+
+	t.Run("round_trip", func(t *testing.T) {
+		pos, err := decodeCursor("oast_poll", encodeCursor("oast_poll", "evt:42"))
+		require.NoError(t, err)
+		assert.Equal(t, "evt:42", pos)
+	})
+
+	t.Run("other_tool", func(t *testing.T) {
+		_, err := decodeCursor("crawl_poll", encodeCursor("proxy_poll", "7"))
+		assert.Error(t, err)
+	})
+
+	t.Run("garbage", func(t *testing.T) {
+		_, err := decodeCursor("proxy_poll", "%%%")
+		assert.Error(t, err)
+	})
+}
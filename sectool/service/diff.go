@@ -0,0 +1,304 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/go-harden/llm-security-toolbox/sectool/bundle"
+	"github.com/go-harden/llm-security-toolbox/sectool/protocol"
+)
+
+const (
+	diffContextLines = 2
+	maxDiffLines     = 200     // diff output lines kept before truncating
+	maxDiffCells     = 4000000 // LCS table size above which changed regions are not aligned
+	diffSplitLineLen = 512     // longer lines are split between tags
+)
+
+// diffNoiseHeaders differ between otherwise identical responses and are skipped when
+// noise filtering is on.
+var diffNoiseHeaders = []string{
+	"Age", "Cf-Ray", "Content-Length", "Date", "Etag", "Expires", "Last-Modified",
+	"Report-To", "Server-Timing", "X-Amz-Cf-Id", "X-Amzn-Requestid", "X-Amzn-Trace-Id",
+	"X-Cache", "X-Correlation-Id", "X-Request-Id", "X-Runtime", "X-Served-By", "X-Timer", "X-Trace-Id",
+}
+
+// diffNoise rewrites values that change on every response (timestamps, IDs, CSRF
+// tokens and nonces) to placeholders before comparing.
+var diffNoise = []struct {
+	re   *regexp.Regexp
+	repl string
+}{
+	{regexp.MustCompile(`(?i)(name=["'][^"']*(?:csrf|xsrf|token|nonce)[^"']*["'][^>]*?\b(?:value|content)=["'])[^"']*`), "${1}<token>"},
+	{regexp.MustCompile(`(?i)((?:csrf|xsrf|authenticity_token|requestverificationtoken|nonce)[\w-]*["']?\s*[:=]\s*["']?)[\w+/=.-]{8,}`), "${1}<token>"},
+	{regexp.MustCompile(`\b\d{4}-\d{2}-\d{2}[T ]\d{2}:\d{2}:\d{2}(?:\.\d+)?(?:Z|[+-]\d{2}:?\d{2})?`), "<timestamp>"},
+	{regexp.MustCompile(`\b(?:Mon|Tue|Wed|Thu|Fri|Sat|Sun), \d{2} [A-Z][a-z]{2} \d{4} \d{2}:\d{2}:\d{2} GMT\b`), "<timestamp>"},
+	{regexp.MustCompile(`\b1\d{9}(?:\d{3})?\b`), "<timestamp>"},
+	{regexp.MustCompile(`(?i)\b[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}\b`), "<uuid>"},
+}
+
+// diffOptions controls response comparison.
+type diffOptions struct {
+	noise          bool             // filter built-in noise (headers and values)
+	ignoreHeaders  []string         // extra header names to skip
+	ignorePatterns []*regexp.Regexp // extra patterns replaced before comparing
+}
+
+// normalize applies the noise filters of opts to s.
+func (o diffOptions) normalize(s string) string {
+	if o.noise {
+		for _, n := range diffNoise {
+			s = n.re.ReplaceAllString(s, n.repl)
+		}
+	}
+	for _, re := range o.ignorePatterns {
+		s = re.ReplaceAllString(s, "<ignored>")
+	}
+	return s
+}
+
+func (o diffOptions) skipHeader(name string) bool {
+	if o.noise && slices.Contains(diffNoiseHeaders, name) {
+		return true
+	}
+	return slices.ContainsFunc(o.ignoreHeaders, func(h string) bool { return strings.EqualFold(h, name) })
+}
+
+// diffResponses compares two stored responses: status, headers, and body.
+func diffResponses(aHeaders, aBody, bHeaders, bBody []byte, opts diffOptions) protocol.ResponseDiff {
+	aStatus, _ := parseResponseStatus(aHeaders)
+	bStatus, _ := parseResponseStatus(bHeaders)
+
+	out := protocol.ResponseDiff{
+		Status:  protocol.StatusDiff{A: aStatus, B: bStatus, Changed: aStatus != bStatus},
+		Headers: diffHeaders(string(aHeaders), string(bHeaders), opts),
+		Body:    diffBodies(string(aHeaders), aBody, string(bHeaders), bBody, opts),
+	}
+	out.Identical = !out.Status.Changed && !out.Body.Changed &&
+		len(out.Headers.Added) == 0 && len(out.Headers.Removed) == 0 && len(out.Headers.Changed) == 0
+	return out
+}
+
+func diffHeaders(a, b string, opts diffOptions) protocol.HeaderDiff {
+	aMap, bMap := parseHeadersToMap(a), parseHeadersToMap(b)
+	var out protocol.HeaderDiff
+	for name, aValues := range aMap {
+		if opts.skipHeader(name) {
+			continue
+		}
+		bValues, ok := bMap[name]
+		if !ok {
+			out.Removed = append(out.Removed, protocol.HeaderValue{Name: name, Value: strings.Join(aValues, ", ")})
+			continue
+		}
+		aJoined, bJoined := strings.Join(aValues, ", "), strings.Join(bValues, ", ")
+		if opts.normalize(aJoined) != opts.normalize(bJoined) {
+			out.Changed = append(out.Changed, protocol.HeaderChange{Name: name, A: aJoined, B: bJoined})
+		}
+	}
+	for name, bValues := range bMap {
+		if _, ok := aMap[name]; !ok && !opts.skipHeader(name) {
+			out.Added = append(out.Added, protocol.HeaderValue{Name: name, Value: strings.Join(bValues, ", ")})
+		}
+	}
+	slices.SortFunc(out.Added, func(x, y protocol.HeaderValue) int { return strings.Compare(x.Name, y.Name) })
+	slices.SortFunc(out.Removed, func(x, y protocol.HeaderValue) int { return strings.Compare(x.Name, y.Name) })
+	slices.SortFunc(out.Changed, func(x, y protocol.HeaderChange) int { return strings.Compare(x.Name, y.Name) })
+	return out
+}
+
+func diffBodies(aHeaders string, aBody []byte, bHeaders string, bBody []byte, opts diffOptions) protocol.BodyDiff {
+	// Decoding is best effort: an undecodable body is compared as sent.
+	aBody, _ = bundle.DecodeBody(aHeaders, aBody)
+	bBody, _ = bundle.DecodeBody(bHeaders, bBody)
+
+	out := protocol.BodyDiff{SizeA: len(aBody), SizeB: len(bBody)}
+	if bytes.Equal(aBody, bBody) {
+		out.Similarity = 1
+		return out
+	}
+
+	aLines := diffSplitLines(opts.normalize(diffPrettyJSON(aBody)))
+	bLines := diffSplitLines(opts.normalize(diffPrettyJSON(bBody)))
+	ops, common := diffLines(aLines, bLines)
+	out.Similarity = 1
+	if total := len(aLines) + len(bLines); total > 0 {
+		out.Similarity = float64(2*common) / float64(total)
+	}
+	out.Changed = common != len(aLines) || common != len(bLines)
+	if out.Changed {
+		out.Diff, out.Truncated = formatUnifiedDiff(ops)
+	}
+	return out
+}
+
+// diffPrettyJSON indents a JSON body so object fields land on separate lines.
+func diffPrettyJSON(body []byte) string {
+	trimmed := bytes.TrimSpace(body)
+	if len(trimmed) > 0 && (trimmed[0] == '{' || trimmed[0] == '[') && json.Valid(trimmed) {
+		var buf bytes.Buffer
+		if json.Indent(&buf, trimmed, "", "  ") == nil {
+			return buf.String()
+		}
+	}
+	return string(body)
+}
+
+// diffSplitLines splits s into lines, breaking very long lines (minified HTML)
+// between adjacent tags.
+func diffSplitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	var out []string
+	for _, line := range strings.Split(strings.TrimSuffix(s, "\n"), "\n") {
+		line = strings.TrimSuffix(line, "\r")
+		if len(line) > diffSplitLineLen && strings.Contains(line, "><") {
+			out = append(out, strings.SplitAfter(line, ">")...)
+			if out[len(out)-1] == "" {
+				out = out[:len(out)-1]
+			}
+			continue
+		}
+		out = append(out, line)
+	}
+	return out
+}
+
+// diffOp is one line of an edit script: ' ' kept, '-' only in a, '+' only in b.
+type diffOp struct {
+	kind byte
+	text string
+}
+
+// diffLines returns the edit script turning a into b and the number of common lines.
+// Common prefix and suffix are matched first; the remainder is aligned by longest
+// common subsequence when small enough, otherwise reported as replaced wholesale.
+func diffLines(a, b []string) ([]diffOp, int) {
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	ops := make([]diffOp, 0, len(a)+len(b))
+	for _, line := range a[:prefix] {
+		ops = append(ops, diffOp{' ', line})
+	}
+	midA, midB := a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]
+	common := prefix + suffix
+
+	if n, m := len(midA), len(midB); n > 0 && m > 0 && (n+1)*(m+1) <= maxDiffCells {
+		// lcs[i][j] is the LCS length of midA[i:] and midB[j:].
+		lcs := make([]int32, (n+1)*(m+1))
+		at := func(i, j int) int32 { return lcs[i*(m+1)+j] }
+		for i := n - 1; i >= 0; i-- {
+			for j := m - 1; j >= 0; j-- {
+				if midA[i] == midB[j] {
+					lcs[i*(m+1)+j] = at(i+1, j+1) + 1
+				} else {
+					lcs[i*(m+1)+j] = max(at(i+1, j), at(i, j+1))
+				}
+			}
+		}
+		i, j := 0, 0
+		for i < n && j < m {
+			switch {
+			case midA[i] == midB[j]:
+				ops = append(ops, diffOp{' ', midA[i]})
+				common++
+				i++
+				j++
+			case at(i+1, j) >= at(i, j+1):
+				ops = append(ops, diffOp{'-', midA[i]})
+				i++
+			default:
+				ops = append(ops, diffOp{'+', midB[j]})
+				j++
+			}
+		}
+		midA, midB = midA[i:], midB[j:]
+	}
+	for _, line := range midA {
+		ops = append(ops, diffOp{'-', line})
+	}
+	for _, line := range midB {
+		ops = append(ops, diffOp{'+', line})
+	}
+	for _, line := range a[len(a)-suffix:] {
+		ops = append(ops, diffOp{' ', line})
+	}
+	return ops, common
+}
+
+// formatUnifiedDiff renders changed lines with diffContextLines of context and
+// "@@ -a,n +b,m @@" hunk headers, reporting whether output was cut at maxDiffLines.
+func formatUnifiedDiff(ops []diffOp) (string, bool) {
+	var b strings.Builder
+	var written int
+	aLine, bLine := make([]int, len(ops)), make([]int, len(ops))
+	for i, x, y := 0, 1, 1; i < len(ops); i++ {
+		aLine[i], bLine[i] = x, y
+		if ops[i].kind != '+' {
+			x++
+		}
+		if ops[i].kind != '-' {
+			y++
+		}
+	}
+
+	for i := 0; i < len(ops); {
+		if ops[i].kind == ' ' {
+			i++
+			continue
+		}
+		// Extend the hunk while changes are within 2*context of each other.
+		start := max(0, i-diffContextLines)
+		end := i
+		for end < len(ops) {
+			if ops[end].kind != ' ' {
+				end++
+				continue
+			}
+			next := end
+			for next < len(ops) && ops[next].kind == ' ' {
+				next++
+			}
+			if next == len(ops) || next-end > 2*diffContextLines {
+				end = min(len(ops), end+diffContextLines)
+				break
+			}
+			end = next
+		}
+
+		var aCount, bCount int
+		for _, op := range ops[start:end] {
+			if op.kind != '+' {
+				aCount++
+			}
+			if op.kind != '-' {
+				bCount++
+			}
+		}
+		_, _ = fmt.Fprintf(&b, "@@ -%d,%d +%d,%d @@\n", aLine[start], aCount, bLine[start], bCount)
+		written++
+		for _, op := range ops[start:end] {
+			if written >= maxDiffLines {
+				return b.String(), true
+			}
+			b.WriteByte(op.kind)
+			b.WriteString(op.text)
+			b.WriteByte('\n')
+			written++
+		}
+		i = end
+	}
+	return b.String(), false
+}
@@ -0,0 +1,61 @@
+package service
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffNoise(t *testing.T) {
+	t.Parallel()
+
+	opts := diffOptions{noise: true}
+	cases := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{name: "hidden_input", in: `<input type="hidden" name="csrf_token" value="a1b2c3">`, want: `<input type="hidden" name="csrf_token" value="<token>">`},
+		{name: "meta", in: `<meta name="csrf-token" content="xyz">`, want: `<meta name="csrf-token" content="<token>">`},
+		{name: "json_token", in: `"csrfToken": "abcdef123456"`, want: `"csrfToken": "<token>"`},
+		{name: "nonce_attr", in: `<script nonce="r4nd0mN0nce">`, want: `<script nonce="<token>">`},
+		{name: "iso_time", in: `at 2026-01-02T10:00:00.123+02:00.`, want: `at <timestamp>.`},
+		{name: "epoch_ms", in: `"t":1767348000123`, want: `"t":<timestamp>`},
+		{name: "uuid", in: `id=123e4567-e89b-12d3-a456-426614174000`, want: `id=<uuid>`},
+		{name: "plain", in: `role=admin`, want: `role=admin`},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, opts.normalize(tc.in))
+		})
+	}
+}
+
+func TestDiffLines(t *testing.T) {
+	t.Parallel()
+
+	t.Run("unified", func(t *testing.T) {
+		a := strings.Split("1\n2\n3\n4\n5\n6\n7\n8\n9\n10", "\n")
+		b := strings.Split("1\n2\nthree\n4\n5\n6\n7\n8\n9\n10\n11", "\n")
+		ops, common := diffLines(a, b)
+		assert.Equal(t, 9, common)
+		diff, truncated := formatUnifiedDiff(ops)
+		assert.False(t, truncated)
+		assert.Equal(t, "@@ -1,5 +1,5 @@\n 1\n 2\n-3\n+three\n 4\n 5\n@@ -9,2 +9,3 @@\n 9\n 10\n+11\n", diff)
+	})
+
+	t.Run("identical", func(t *testing.T) {
+		ops, common := diffLines([]string{"a", "b"}, []string{"a", "b"})
+		assert.Equal(t, 2, common)
+		diff, _ := formatUnifiedDiff(ops)
+		assert.Empty(t, diff)
+	})
+
+	t.Run("minified_html_split", func(t *testing.T) {
+		line := "<div>" + strings.Repeat("<p>x</p>", 100) + "</div>"
+		lines := diffSplitLines(line)
+		assert.Greater(t, len(lines), 100)
+		assert.Equal(t, line, strings.Join(lines, ""))
+	})
+}
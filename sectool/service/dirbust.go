@@ -0,0 +1,306 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/go-harden/llm-security-toolbox/sectool/service/ids"
+)
+
+const (
+	defaultDirbustMaxRequests = 1000
+	defaultDirbustThreads     = 5
+	maxDirbustThreads         = 20
+	// notFoundSimilarity is the body similarity at or above which a response
+	// matches a not-found fingerprint.
+	notFoundSimilarity = 0.9
+	// notFoundNameLength is the length of the random names requested to fingerprint
+	// not-found responses.
+	notFoundNameLength = 12
+)
+
+// dirbustProbe is one request of a dirbust run and its response.
+type dirbustProbe struct {
+	path     string // escaped request path
+	request  []byte
+	result   *SendRequestResult
+	status   int
+	location string // absolute, resolved against the request URL
+	replayID string // set when stored as a hit
+}
+
+// notFoundPrint fingerprints the response to a random name that does not exist (a
+// path for dirbust, a host for vhost_discover). The name is replaced with a
+// placeholder so responses reflecting it still match.
+type notFoundPrint struct {
+	status   int
+	headers  string
+	body     []byte
+	location string
+}
+
+const notFoundPlaceholder = "{name}"
+
+func newNotFoundPrint(status int, result *SendRequestResult, location, name string) notFoundPrint {
+	return notFoundPrint{
+		status:   status,
+		headers:  string(result.Headers),
+		body:     bytes.ReplaceAll(result.Body, []byte(name), []byte(notFoundPlaceholder)),
+		location: strings.ReplaceAll(location, name, notFoundPlaceholder),
+	}
+}
+
+// matches reports whether a response, requested for name, looks like the fingerprinted
+// not-found response.
+func (f notFoundPrint) matches(status int, result *SendRequestResult, location, name string) bool {
+	if status != f.status {
+		return false
+	} else if f.location != "" || location != "" {
+		return strings.ReplaceAll(location, name, notFoundPlaceholder) == f.location
+	}
+	body := bytes.ReplaceAll(result.Body, []byte(name), []byte(notFoundPlaceholder))
+	if delta := len(body) - len(f.body); max(delta, -delta) <= max(8, len(f.body)/50) {
+		return true
+	}
+	return diffBodies(f.headers, f.body, string(result.Headers), body, diffOptions{noise: true}).Similarity >= notFoundSimilarity
+}
+
+// dirbustHit is a path that answered differently from the not-found fingerprints of
+// its directory.
+type dirbustHit struct {
+	probe     *dirbustProbe
+	directory bool // redirected to the path with a trailing slash
+	depth     int
+}
+
+// dirbustWord is a wordlist entry with one of the extensions applied.
+type dirbustWord struct {
+	name      string
+	extension string // "" or ".php" style
+}
+
+// dirbuster brute-forces paths under a directory, ffuf style. Each directory is first
+// sent random names per extension to fingerprint its not-found responses (soft 404
+// pages, catch-all redirects), and responses matching a fingerprint or a filtered
+// status are dropped. Hits that redirect to a trailing slash are recursed into.
+type dirbuster struct {
+	send         func(ctx context.Context, path string) (*dirbustProbe, error)
+	allowed      func(path string) bool // project scope and target allowlist
+	onHit        func(dirbustHit)       // called as hits are found, from worker goroutines
+	words        []dirbustWord
+	extensions   []string
+	filterStatus []int
+	maxDepth     int
+	threads      int
+	maxRequests  int
+
+	mu          sync.Mutex
+	requests    int
+	skipped     int
+	errors      int
+	lastErr     error
+	directories int
+	stopped     bool
+	hits        []dirbustHit
+}
+
+// newDirbuster combines words with extensions. Extensions are normalized to a leading
+// dot, and words already ending in an extension are also tried as is.
+func newDirbuster(words, extensions []string, send func(ctx context.Context, path string) (*dirbustProbe, error)) *dirbuster {
+	d := &dirbuster{send: send, extensions: []string{""}}
+	for _, ext := range extensions {
+		if ext = strings.TrimSpace(ext); ext == "" {
+			continue
+		} else if !strings.HasPrefix(ext, ".") {
+			ext = "." + ext
+		}
+		if !slices.Contains(d.extensions, ext) {
+			d.extensions = append(d.extensions, ext)
+		}
+	}
+	seen := make(map[string]bool)
+	for _, word := range words {
+		word = strings.Trim(strings.TrimSpace(word), "/")
+		if word == "" || strings.HasPrefix(word, "#") {
+			continue // blank lines and comments in wordlist files
+		}
+		for _, ext := range d.extensions {
+			if name := word + ext; !seen[name] {
+				seen[name] = true
+				d.words = append(d.words, dirbustWord{name: name, extension: ext})
+			}
+		}
+	}
+	return d
+}
+
+// run brute-forces root, which must end with a slash, and the directories found under
+// it down to maxDepth.
+func (d *dirbuster) run(ctx context.Context, root string) error {
+	type directory struct {
+		path  string
+		depth int
+	}
+	queue := []directory{{path: root}}
+	for len(queue) > 0 && !d.isStopped() {
+		dir := queue[0]
+		queue = queue[1:]
+		found, err := d.scan(ctx, dir.path, dir.depth)
+		if err != nil {
+			return err
+		}
+		for _, hit := range found {
+			if hit.directory && hit.depth < d.maxDepth {
+				queue = append(queue, directory{path: hit.probe.path + "/", depth: hit.depth + 1})
+			}
+		}
+	}
+	return nil
+}
+
+// reserve takes one request from the budget, or marks the run stopped.
+func (d *dirbuster) reserve() bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.requests >= d.maxRequests {
+		d.stopped = true
+		return false
+	}
+	d.requests++
+	return true
+}
+
+// calibrate requests a random name per extension under dir and returns the not-found
+// fingerprints by extension.
+func (d *dirbuster) calibrate(ctx context.Context, dir string) (map[string]notFoundPrint, error) {
+	prints := make(map[string]notFoundPrint, len(d.extensions))
+	for _, ext := range d.extensions {
+		if !d.reserve() {
+			return nil, nil
+		}
+		name := strings.ToLower(ids.Generate(notFoundNameLength)) + ext
+		probe, err := d.send(ctx, dir+name)
+		if err != nil {
+			return nil, fmt.Errorf("not-found fingerprint request for %s failed: %w", dir+name, err)
+		}
+		prints[ext] = newNotFoundPrint(probe.status, probe.result, probe.location, name)
+	}
+	return prints, nil
+}
+
+// scan fingerprints dir and sends every word under it, returning the hits.
+func (d *dirbuster) scan(ctx context.Context, dir string, depth int) ([]dirbustHit, error) {
+	prints, err := d.calibrate(ctx, dir)
+	if err != nil || prints == nil {
+		return nil, err
+	}
+	d.directories++
+
+	jobs := make(chan dirbustWord)
+	var found []dirbustHit
+	var wg sync.WaitGroup
+	for range d.threads {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for word := range jobs {
+				if hit, ok := d.try(ctx, dir, word, prints[word.extension], depth); ok {
+					d.mu.Lock()
+					found = append(found, hit)
+					d.hits = append(d.hits, hit)
+					d.mu.Unlock()
+					if d.onHit != nil {
+						d.onHit(hit)
+					}
+				}
+			}
+		}()
+	}
+	for _, word := range d.words {
+		if ctx.Err() != nil || d.isStopped() {
+			break
+		}
+		jobs <- word
+	}
+	close(jobs)
+	wg.Wait()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	slices.SortFunc(found, func(a, b dirbustHit) int { return strings.Compare(a.probe.path, b.probe.path) })
+	return found, nil
+}
+
+func (d *dirbuster) isStopped() bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.stopped
+}
+
+// try requests one word under dir and reports whether it is a hit.
+func (d *dirbuster) try(ctx context.Context, dir string, word dirbustWord, notFound notFoundPrint, depth int) (dirbustHit, bool) {
+	path := dir + (&url.URL{Path: word.name}).EscapedPath()
+	if !d.allowed(path) {
+		d.mu.Lock()
+		d.skipped++
+		d.mu.Unlock()
+		return dirbustHit{}, false
+	} else if !d.reserve() {
+		return dirbustHit{}, false
+	}
+
+	probe, err := d.send(ctx, path)
+	if err != nil {
+		if !errors.Is(err, context.Canceled) {
+			d.mu.Lock()
+			d.errors++
+			d.lastErr = err
+			d.mu.Unlock()
+		}
+		return dirbustHit{}, false
+	}
+	if slices.Contains(d.filterStatus, probe.status) || notFound.matches(probe.status, probe.result, probe.location, word.name) {
+		return dirbustHit{}, false
+	}
+
+	hit := dirbustHit{probe: probe, depth: depth}
+	if probe.status >= 300 && probe.status < 400 && probe.location != "" {
+		if u, err := url.Parse(probe.location); err == nil && u.EscapedPath() == path+"/" {
+			hit.directory = true
+		}
+	}
+	return hit, true
+}
+
+// dirbustEntry is a dirbust hit kept for crawl_results.
+type dirbustEntry struct {
+	url      string
+	status   int
+	replayID string
+}
+
+// dirbustStore collects dirbust hits across runs so crawl_results lists them with the
+// crawled endpoints.
+type dirbustStore struct {
+	mu      sync.Mutex
+	entries []dirbustEntry
+}
+
+func (s *dirbustStore) add(entry dirbustEntry) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries = append(s.entries, entry)
+}
+
+func (s *dirbustStore) list() []dirbustEntry {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return slices.Clone(s.entries)
+}
@@ -0,0 +1,138 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testDirbustApp answers like a site with a soft 404 page that echoes the path, an
+// /admin/ directory holding config.php, and a robots.txt file.
+func testDirbustApp(t *testing.T) (func(ctx context.Context, path string) (*dirbustProbe, error), *[]string) {
+	var mu sync.Mutex
+	var sent []string
+	return func(ctx context.Context, path string) (*dirbustProbe, error) {
+		mu.Lock()
+		sent = append(sent, path)
+		mu.Unlock()
+
+		probe := &dirbustProbe{path: path, status: 200}
+		headers, body := "HTTP/1.1 200 OK\r\nContent-Type: text/html\r\n", "<html><body><h1>Page not found</h1><p>"+path+" does not exist</p></body></html>"
+		switch path {
+		case "/admin":
+			probe.status, probe.location = 301, "https://example.com/admin/"
+			headers, body = "HTTP/1.1 301 Moved Permanently\r\nLocation: /admin/\r\n", ""
+		case "/admin/":
+			body = "<html><body>login</body></html>"
+		case "/admin/config.php":
+			body = "<?php // config"
+		case "/robots.txt":
+			headers, body = "HTTP/1.1 200 OK\r\nContent-Type: text/plain\r\n", "User-agent: *\nDisallow: /admin/"
+		}
+		probe.result = &SendRequestResult{Headers: []byte(headers + "\r\n"), Body: []byte(body)}
+		return probe, nil
+	}, &sent
+}
+
+func TestDirbuster(t *testing.T) {
+	t.Parallel()
+
+	words := []string{"admin", "robots.txt", "# comment", "backup", "config", "/login/", ""}
+	newTest := func(t *testing.T, extensions []string) (*dirbuster, *[]string) {
+		t.Helper()
+		send, sent := testDirbustApp(t)
+		d := newDirbuster(words, extensions, send)
+		d.allowed = func(string) bool { return true }
+		d.threads = 2
+		d.maxRequests = 100
+		return d, sent
+	}
+	hitPaths := func(d *dirbuster) []string {
+		paths := make([]string, 0, len(d.hits))
+		for _, hit := range d.hits {
+			paths = append(paths, hit.probe.path)
+		}
+		return paths
+	}
+
+	t.Run("words", func(t *testing.T) {
+		d, _ := newTest(t, []string{"php", ".bak", "php"})
+		assert.Equal(t, []string{"", ".php", ".bak"}, d.extensions)
+		assert.Len(t, d.words, 15) // 5 words, 3 extensions
+		assert.Equal(t, dirbustWord{name: "login.php", extension: ".php"}, d.words[13])
+	})
+
+	t.Run("soft_404", func(t *testing.T) {
+		d, _ := newTest(t, nil)
+		require.NoError(t, d.run(t.Context(), "/"))
+		assert.ElementsMatch(t, []string{"/admin", "/robots.txt"}, hitPaths(d))
+		assert.Equal(t, 1, d.directories)
+		assert.Equal(t, 6, d.requests) // fingerprint and 5 words
+		for _, hit := range d.hits {
+			assert.Equal(t, hit.probe.path == "/admin", hit.directory)
+		}
+	})
+
+	t.Run("recursion", func(t *testing.T) {
+		d, sent := newTest(t, []string{"php"})
+		d.maxDepth = 1
+		require.NoError(t, d.run(t.Context(), "/"))
+		assert.ElementsMatch(t, []string{"/admin", "/robots.txt", "/admin/config.php"}, hitPaths(d))
+		assert.Equal(t, 2, d.directories)
+		assert.Contains(t, *sent, "/admin/login.php")
+		for _, hit := range d.hits {
+			assert.Equal(t, strings.Count(hit.probe.path, "/")-1, hit.depth)
+		}
+	})
+
+	t.Run("scope", func(t *testing.T) {
+		d, sent := newTest(t, nil)
+		d.allowed = func(path string) bool { return !strings.HasPrefix(path, "/admin") }
+		require.NoError(t, d.run(t.Context(), "/"))
+		assert.Equal(t, []string{"/robots.txt"}, hitPaths(d))
+		assert.Equal(t, 1, d.skipped)
+		assert.NotContains(t, *sent, "/admin")
+	})
+
+	t.Run("budget", func(t *testing.T) {
+		d, sent := newTest(t, nil)
+		d.maxRequests = 3
+		require.NoError(t, d.run(t.Context(), "/"))
+		assert.True(t, d.stopped)
+		assert.Equal(t, 3, d.requests)
+		assert.Len(t, *sent, 3)
+	})
+
+	t.Run("status_filter", func(t *testing.T) {
+		d, _ := newTest(t, nil)
+		d.filterStatus = []int{301}
+		require.NoError(t, d.run(t.Context(), "/"))
+		assert.Equal(t, []string{"/robots.txt"}, hitPaths(d))
+	})
+}
+
+func TestNotFoundPrint(t *testing.T) {
+	t.Parallel()
+
+	empty := &SendRequestResult{}
+	t.Run("redirect", func(t *testing.T) {
+		notFound := newNotFoundPrint(302, empty, "https://example.com/login?next=/x7k2q", "x7k2q")
+		assert.True(t, notFound.matches(302, empty, "https://example.com/login?next=/backup", "backup"))
+		assert.False(t, notFound.matches(302, empty, "https://example.com/admin/", "admin"))
+		assert.False(t, notFound.matches(200, empty, "", "admin"))
+	})
+
+	t.Run("reflected_name", func(t *testing.T) {
+		page := func(name string) *SendRequestResult {
+			return &SendRequestResult{Body: []byte("<html><body><p>" + name + " was not found on this server</p></body></html>")}
+		}
+		notFound := newNotFoundPrint(404, page("x7k2q9"), "", "x7k2q9")
+		assert.True(t, notFound.matches(404, page("a-much-longer-name-than-the-random-one"), "", "a-much-longer-name-than-the-random-one"))
+		other := &SendRequestResult{Body: []byte(strings.Repeat("<p>the admin console</p>", 10))}
+		assert.False(t, notFound.matches(404, other, "", "admin"))
+	})
+}
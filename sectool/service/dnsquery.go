@@ -0,0 +1,339 @@
+package service
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net"
+	"net/netip"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/net/dns/dnsmessage"
+
+	"github.com/go-harden/llm-security-toolbox/sectool/protocol"
+)
+
+const (
+	dnsQueryTimeout = 5 * time.Second
+	maxDNSUDPSize   = 4096
+	maxCNAMEChain   = 10
+)
+
+// resolvConfPath is read for the default DNS server, replaced in tests.
+var resolvConfPath = "/etc/resolv.conf"
+
+// dnsQueryTypes are the record types dns_query accepts.
+var dnsQueryTypes = map[string]dnsmessage.Type{
+	"A":     dnsmessage.TypeA,
+	"AAAA":  dnsmessage.TypeAAAA,
+	"CNAME": dnsmessage.TypeCNAME,
+	"MX":    dnsmessage.TypeMX,
+	"TXT":   dnsmessage.TypeTXT,
+	"NS":    dnsmessage.TypeNS,
+	"PTR":   dnsmessage.TypePTR,
+}
+
+// defaultDNSTypes are queried for a name when no types are given; an IP address
+// defaults to PTR.
+var defaultDNSTypes = []string{"A", "AAAA", "CNAME", "MX", "TXT", "NS"}
+
+// takeoverProviders maps CNAME target suffixes to hosting services where an unclaimed
+// resource name can be registered by anyone.
+var takeoverProviders = []struct{ suffix, provider string }{
+	{".s3.amazonaws.com", "AWS S3"},
+	{".cloudfront.net", "AWS CloudFront"},
+	{".elasticbeanstalk.com", "AWS Elastic Beanstalk"},
+	{".azurewebsites.net", "Azure App Service"},
+	{".cloudapp.net", "Azure Cloud Services"},
+	{".cloudapp.azure.com", "Azure VM"},
+	{".trafficmanager.net", "Azure Traffic Manager"},
+	{".blob.core.windows.net", "Azure Blob Storage"},
+	{".azureedge.net", "Azure CDN"},
+	{".herokuapp.com", "Heroku"},
+	{".herokudns.com", "Heroku"},
+	{".github.io", "GitHub Pages"},
+	{".bitbucket.io", "Bitbucket"},
+	{".netlify.app", "Netlify"},
+	{".vercel.app", "Vercel"},
+	{".surge.sh", "Surge"},
+	{".myshopify.com", "Shopify"},
+	{".ghost.io", "Ghost"},
+	{".pantheonsite.io", "Pantheon"},
+	{".readthedocs.io", "Read the Docs"},
+	{".zendesk.com", "Zendesk"},
+	{".helpscoutdocs.com", "Help Scout"},
+	{".unbouncepages.com", "Unbounce"},
+	{".wordpress.com", "WordPress.com"},
+	{".fly.dev", "Fly.io"},
+}
+
+// dnsQueryInput describes a dns_query run.
+type dnsQueryInput struct {
+	Name   string   // hostname or IP address
+	Types  []string // uppercase names from dnsQueryTypes
+	Server string   // host:port
+}
+
+// dnsRecordName returns the name to query: the reverse lookup name for IP addresses
+// and the fully qualified name otherwise.
+func dnsRecordName(name string) string {
+	addr, err := netip.ParseAddr(name)
+	if err != nil {
+		return strings.TrimSuffix(strings.ToLower(name), ".") + "."
+	}
+	addr = addr.Unmap()
+	if addr.Is4() {
+		b := addr.As4()
+		return fmt.Sprintf("%d.%d.%d.%d.in-addr.arpa.", b[3], b[2], b[1], b[0])
+	}
+	b := addr.As16()
+	var sb strings.Builder
+	for i := len(b) - 1; i >= 0; i-- {
+		sb.WriteString(strconv.FormatUint(uint64(b[i]&0x0f), 16) + ".")
+		sb.WriteString(strconv.FormatUint(uint64(b[i]>>4), 16) + ".")
+	}
+	return sb.String() + "ip6.arpa."
+}
+
+// systemDNSServer returns the first nameserver in resolv.conf as host:port.
+func systemDNSServer() (string, error) {
+	f, err := os.Open(resolvConfPath)
+	if err != nil {
+		return "", fmt.Errorf("no system DNS server (set server): %w", err)
+	}
+	defer func() { _ = f.Close() }()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if fields := strings.Fields(scanner.Text()); len(fields) >= 2 && fields[0] == "nameserver" {
+			host, _, _ := strings.Cut(fields[1], "%") // drop an IPv6 zone
+			return net.JoinHostPort(host, "53"), nil
+		}
+	}
+	return "", fmt.Errorf("no nameserver in %s (set server)", resolvConfPath)
+}
+
+// dnsExchange sends one recursive query to server over UDP, retrying over TCP when
+// the answer is truncated.
+func dnsExchange(ctx context.Context, server, name string, qtype dnsmessage.Type) (*dnsmessage.Message, error) {
+	qname, err := dnsmessage.NewName(name)
+	if err != nil {
+		return nil, fmt.Errorf("invalid name %q: %w", name, err)
+	}
+	id := uint16(rand.Uint32())
+	b := dnsmessage.NewBuilder(nil, dnsmessage.Header{ID: id, RecursionDesired: true})
+	b.EnableCompression()
+	_ = b.StartQuestions()
+	_ = b.Question(dnsmessage.Question{Name: qname, Type: qtype, Class: dnsmessage.ClassINET})
+	_ = b.StartAdditionals()
+	var opt dnsmessage.ResourceHeader
+	_ = opt.SetEDNS0(maxDNSUDPSize, dnsmessage.RCodeSuccess, false)
+	_ = b.OPTResource(opt, dnsmessage.OPTResource{})
+	query, err := b.Finish()
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, dnsQueryTimeout)
+	defer cancel()
+	msg, err := dnsRoundTrip(ctx, "udp", server, query)
+	if err == nil && msg.Truncated {
+		msg, err = dnsRoundTrip(ctx, "tcp", server, query)
+	}
+	if err != nil {
+		return nil, err
+	} else if msg.ID != id {
+		return nil, errors.New("response ID does not match the query")
+	}
+	return msg, nil
+}
+
+func dnsRoundTrip(ctx context.Context, network, server string, query []byte) (*dnsmessage.Message, error) {
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, network, server)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = conn.Close() }()
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	var buf []byte
+	if network == "tcp" {
+		if _, err := conn.Write(binary.BigEndian.AppendUint16(nil, uint16(len(query)))); err != nil {
+			return nil, err
+		} else if _, err := conn.Write(query); err != nil {
+			return nil, err
+		}
+		var length [2]byte
+		if _, err := io.ReadFull(conn, length[:]); err != nil {
+			return nil, err
+		}
+		buf = make([]byte, binary.BigEndian.Uint16(length[:]))
+		if _, err := io.ReadFull(conn, buf); err != nil {
+			return nil, err
+		}
+	} else {
+		if _, err := conn.Write(query); err != nil {
+			return nil, err
+		}
+		buf = make([]byte, maxDNSUDPSize)
+		n, err := conn.Read(buf)
+		if err != nil {
+			return nil, err
+		}
+		buf = buf[:n]
+	}
+
+	var msg dnsmessage.Message
+	if err := msg.Unpack(buf); err != nil {
+		return nil, fmt.Errorf("malformed response: %w", err)
+	}
+	return &msg, nil
+}
+
+// dnsRCodeName returns the conventional name of a response code, e.g. NXDOMAIN.
+func dnsRCodeName(rcode dnsmessage.RCode) string {
+	switch rcode {
+	case dnsmessage.RCodeSuccess:
+		return "NOERROR"
+	case dnsmessage.RCodeFormatError:
+		return "FORMERR"
+	case dnsmessage.RCodeServerFailure:
+		return "SERVFAIL"
+	case dnsmessage.RCodeNameError:
+		return "NXDOMAIN"
+	case dnsmessage.RCodeNotImplemented:
+		return "NOTIMP"
+	case dnsmessage.RCodeRefused:
+		return "REFUSED"
+	}
+	return "RCODE" + strconv.Itoa(int(rcode))
+}
+
+// dnsRecord converts an answer resource, returning false for types dns_query does
+// not report.
+func dnsRecord(r dnsmessage.Resource) (protocol.DNSRecord, bool) {
+	rec := protocol.DNSRecord{
+		Type: strings.TrimPrefix(r.Header.Type.String(), "Type"),
+		Name: strings.TrimSuffix(r.Header.Name.String(), "."),
+		TTL:  r.Header.TTL,
+	}
+	switch body := r.Body.(type) {
+	case *dnsmessage.AResource:
+		rec.Value = netip.AddrFrom4(body.A).String()
+	case *dnsmessage.AAAAResource:
+		rec.Value = netip.AddrFrom16(body.AAAA).String()
+	case *dnsmessage.CNAMEResource:
+		rec.Value = strings.TrimSuffix(body.CNAME.String(), ".")
+	case *dnsmessage.MXResource:
+		rec.Value = strings.TrimSuffix(body.MX.String(), ".")
+		rec.Priority = int(body.Pref)
+	case *dnsmessage.TXTResource:
+		rec.Value = strings.Join(body.TXT, "")
+	case *dnsmessage.NSResource:
+		rec.Value = strings.TrimSuffix(body.NS.String(), ".")
+	case *dnsmessage.PTRResource:
+		rec.Value = strings.TrimSuffix(body.PTR.String(), ".")
+	default:
+		return rec, false
+	}
+	return rec, true
+}
+
+// queryDNS queries each record type for in.Name and follows the CNAME chain. When the
+// name is an alias, the final target is also checked for takeover indicators: a
+// target that does not resolve (NXDOMAIN) or that belongs to a hosting service where
+// names can be claimed.
+func queryDNS(ctx context.Context, in dnsQueryInput) (*protocol.DNSQueryResponse, error) {
+	name := dnsRecordName(in.Name)
+	resp := &protocol.DNSQueryResponse{
+		Name:    strings.TrimSuffix(name, "."),
+		Server:  in.Server,
+		Records: []protocol.DNSRecord{},
+	}
+
+	cnames := make(map[string]string)
+	var answered bool
+	for _, t := range in.Types {
+		msg, err := dnsExchange(ctx, in.Server, name, dnsQueryTypes[t])
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			resp.Errors = append(resp.Errors, t+": "+err.Error())
+			continue
+		}
+		if !answered {
+			resp.Status = dnsRCodeName(msg.RCode)
+			answered = true
+		} else if msg.RCode != dnsmessage.RCodeSuccess && msg.RCode != dnsmessage.RCodeNameError {
+			resp.Errors = append(resp.Errors, t+": "+dnsRCodeName(msg.RCode))
+		}
+		for _, answer := range msg.Answers {
+			rec, ok := dnsRecord(answer)
+			if !ok || slices.ContainsFunc(resp.Records, func(r protocol.DNSRecord) bool {
+				return r.Type == rec.Type && r.Name == rec.Name && r.Value == rec.Value
+			}) {
+				continue
+			}
+			resp.Records = append(resp.Records, rec)
+			if rec.Type == "CNAME" {
+				cnames[strings.ToLower(rec.Name)] = rec.Value
+			}
+		}
+	}
+	if !answered {
+		return nil, fmt.Errorf("no response from %s: %s", in.Server, strings.Join(resp.Errors, "; "))
+	}
+
+	for next := resp.Name; len(resp.CNAMEChain) < maxCNAMEChain; {
+		target, ok := cnames[strings.ToLower(next)]
+		if !ok {
+			break
+		}
+		resp.CNAMEChain = append(resp.CNAMEChain, target)
+		next = target
+	}
+	if len(resp.CNAMEChain) > 0 {
+		target := resp.CNAMEChain[len(resp.CNAMEChain)-1]
+		var dangling bool
+		if msg, err := dnsExchange(ctx, in.Server, target+".", dnsmessage.TypeA); err == nil {
+			dangling = msg.RCode == dnsmessage.RCodeNameError
+		}
+		resp.Takeover = dnsTakeover(target, dangling)
+	}
+	return resp, nil
+}
+
+// dnsTakeover assesses a CNAME target, returning nil when nothing suggests takeover.
+func dnsTakeover(target string, dangling bool) *protocol.DNSTakeover {
+	var provider string
+	lower := "." + strings.ToLower(target)
+	for _, p := range takeoverProviders {
+		if strings.HasSuffix(lower, p.suffix) {
+			provider = p.provider
+			break
+		}
+	}
+	t := &protocol.DNSTakeover{Target: target, Provider: provider, Dangling: dangling}
+	switch {
+	case dangling && provider != "":
+		t.Detail = "CNAME target does not resolve; claim the name on " + provider + " to take over the subdomain"
+	case dangling:
+		t.Detail = "CNAME target does not resolve; if its domain is unregistered or released, the subdomain can be taken over"
+	case provider != "":
+		t.Detail = "CNAME points to " + provider + "; request the host and check for an unclaimed-resource error page"
+	default:
+		return nil
+	}
+	return t
+}
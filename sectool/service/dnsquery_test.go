@@ -0,0 +1,200 @@
+package service
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/dns/dnsmessage"
+
+	"github.com/go-harden/llm-security-toolbox/sectool/protocol"
+)
+
+// newTestDNSServer answers UDP queries from zone, keyed by lowercase FQDN. CNAMEs are
+// followed within the zone and names without any record answer NXDOMAIN. It returns
+// the server address as host:port.
+func newTestDNSServer(t *testing.T, zone map[string][]dnsmessage.ResourceBody) string {
+	t.Helper()
+
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	answer := func(query []byte) []byte {
+		var p dnsmessage.Parser
+		hdr, err := p.Start(query)
+		if err != nil {
+			return nil
+		}
+		q, err := p.Question()
+		if err != nil {
+			return nil
+		}
+		resp := dnsmessage.Message{Header: dnsmessage.Header{ID: hdr.ID, Response: true, RecursionAvailable: true}, Questions: []dnsmessage.Question{q}}
+		name := q.Name
+		for range 10 {
+			bodies, ok := zone[strings.ToLower(name.String())]
+			if !ok {
+				resp.RCode = dnsmessage.RCodeNameError
+				break
+			}
+			var next *dnsmessage.Name
+			for _, body := range bodies {
+				if cname, isCNAME := body.(*dnsmessage.CNAMEResource); isCNAME && q.Type != dnsmessage.TypeCNAME {
+					next = &cname.CNAME
+				} else if testDNSType(body) != q.Type && !isCNAME {
+					continue
+				}
+				resp.Answers = append(resp.Answers, dnsmessage.Resource{
+					Header: dnsmessage.ResourceHeader{Name: name, Type: testDNSType(body), Class: dnsmessage.ClassINET, TTL: 300},
+					Body:   body,
+				})
+			}
+			if next == nil {
+				break
+			}
+			name = *next
+		}
+		b, _ := resp.Pack()
+		return b
+	}
+
+	go func() {
+		buf := make([]byte, 512)
+		for {
+			n, addr, err := conn.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			if resp := answer(buf[:n]); resp != nil {
+				_, _ = conn.WriteTo(resp, addr)
+			}
+		}
+	}()
+	return conn.LocalAddr().String()
+}
+
+func testDNSType(body dnsmessage.ResourceBody) dnsmessage.Type {
+	switch body.(type) {
+	case *dnsmessage.AResource:
+		return dnsmessage.TypeA
+	case *dnsmessage.AAAAResource:
+		return dnsmessage.TypeAAAA
+	case *dnsmessage.CNAMEResource:
+		return dnsmessage.TypeCNAME
+	case *dnsmessage.MXResource:
+		return dnsmessage.TypeMX
+	case *dnsmessage.TXTResource:
+		return dnsmessage.TypeTXT
+	case *dnsmessage.NSResource:
+		return dnsmessage.TypeNS
+	case *dnsmessage.PTRResource:
+		return dnsmessage.TypePTR
+	}
+	return 0
+}
+
+func testDNSZone() map[string][]dnsmessage.ResourceBody {
+	name := dnsmessage.MustNewName
+	return map[string][]dnsmessage.ResourceBody{
+		"example.com.": {
+			&dnsmessage.AResource{A: [4]byte{203, 0, 113, 10}},
+			&dnsmessage.MXResource{Pref: 10, MX: name("mail.example.com.")},
+			&dnsmessage.TXTResource{TXT: []string{"v=spf1 ", "-all"}},
+			&dnsmessage.NSResource{NS: name("ns1.example.com.")},
+		},
+		"www.example.com.":    {&dnsmessage.CNAMEResource{CNAME: name("example.com.")}},
+		"assets.example.com.": {&dnsmessage.CNAMEResource{CNAME: name("old-assets.herokuapp.com.")}},
+		"10.113.0.203.in-addr.arpa.": {
+			&dnsmessage.PTRResource{PTR: name("web.example.com.")},
+		},
+	}
+}
+
+func TestDNSRecordName(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "example.com.", dnsRecordName("Example.COM."))
+	assert.Equal(t, "10.113.0.203.in-addr.arpa.", dnsRecordName("203.0.113.10"))
+	assert.Equal(t, "1.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.8.b.d.0.1.0.0.2.ip6.arpa.", dnsRecordName("2001:db8::1"))
+}
+
+func TestQueryDNS(t *testing.T) {
+	t.Parallel()
+
+	server := newTestDNSServer(t, testDNSZone())
+
+	t.Run("records", func(t *testing.T) {
+		resp, err := queryDNS(t.Context(), dnsQueryInput{Name: "example.com", Types: defaultDNSTypes, Server: server})
+		require.NoError(t, err)
+		assert.Equal(t, "NOERROR", resp.Status)
+		assert.Equal(t, []protocol.DNSRecord{
+			{Type: "A", Name: "example.com", Value: "203.0.113.10", TTL: 300},
+			{Type: "MX", Name: "example.com", Value: "mail.example.com", TTL: 300, Priority: 10},
+			{Type: "TXT", Name: "example.com", Value: "v=spf1 -all", TTL: 300},
+			{Type: "NS", Name: "example.com", Value: "ns1.example.com", TTL: 300},
+		}, resp.Records)
+		assert.Empty(t, resp.CNAMEChain)
+		assert.Nil(t, resp.Takeover)
+		assert.Empty(t, resp.Errors)
+	})
+
+	t.Run("cname", func(t *testing.T) {
+		resp, err := queryDNS(t.Context(), dnsQueryInput{Name: "www.example.com", Types: []string{"A"}, Server: server})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"example.com"}, resp.CNAMEChain)
+		require.Len(t, resp.Records, 2)
+		assert.Equal(t, "203.0.113.10", resp.Records[1].Value)
+		assert.Nil(t, resp.Takeover)
+	})
+
+	t.Run("dangling", func(t *testing.T) {
+		resp, err := queryDNS(t.Context(), dnsQueryInput{Name: "assets.example.com", Types: []string{"A", "CNAME"}, Server: server})
+		require.NoError(t, err)
+		assert.Equal(t, "NXDOMAIN", resp.Status)
+		assert.Equal(t, []string{"old-assets.herokuapp.com"}, resp.CNAMEChain)
+		require.NotNil(t, resp.Takeover)
+		assert.Equal(t, "old-assets.herokuapp.com", resp.Takeover.Target)
+		assert.Equal(t, "Heroku", resp.Takeover.Provider)
+		assert.True(t, resp.Takeover.Dangling)
+	})
+
+	t.Run("ptr", func(t *testing.T) {
+		resp, err := queryDNS(t.Context(), dnsQueryInput{Name: "203.0.113.10", Types: []string{"PTR"}, Server: server})
+		require.NoError(t, err)
+		assert.Equal(t, "10.113.0.203.in-addr.arpa", resp.Name)
+		require.Len(t, resp.Records, 1)
+		assert.Equal(t, "web.example.com", resp.Records[0].Value)
+	})
+}
+
+func TestDNSTakeover(t *testing.T) {
+	t.Parallel()
+
+	assert.Nil(t, dnsTakeover("lb.example.net", false))
+	assert.Equal(t, "GitHub Pages", dnsTakeover("acme.GitHub.io", false).Provider)
+	assert.False(t, dnsTakeover("acme.github.io", false).Dangling)
+	dangling := dnsTakeover("gone.example.net", true)
+	assert.Empty(t, dangling.Provider)
+	assert.Contains(t, dangling.Detail, "does not resolve")
+}
+
+func TestSystemDNSServer(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "resolv.conf")
+	require.NoError(t, os.WriteFile(path, []byte("# generated\nsearch corp.example\nnameserver fe80::1%eth0\nnameserver 10.0.0.2\n"), 0o644))
+	orig := resolvConfPath
+	resolvConfPath = path
+	t.Cleanup(func() { resolvConfPath = orig })
+
+	server, err := systemDNSServer()
+	require.NoError(t, err)
+	assert.Equal(t, "[fe80::1]:53", server)
+
+	require.NoError(t, os.WriteFile(path, []byte("search corp.example\n"), 0o644))
+	_, err = systemDNSServer()
+	assert.Error(t, err)
+}
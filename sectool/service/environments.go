@@ -0,0 +1,162 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sync"
+
+	"github.com/go-harden/llm-security-toolbox/sectool/config"
+)
+
+// environmentsFileName is the project environments file within config.ProjectDirName.
+const environmentsFileName = "environments.json"
+
+// templateVarRe matches {{name}} placeholders. Names are identifiers, so template
+// injection payloads such as {{7*7}} are never treated as variables.
+var templateVarRe = regexp.MustCompile(`\{\{\s*([A-Za-z_][A-Za-z0-9_.-]*)\s*\}\}`)
+
+type environmentsFile struct {
+	Active       string                       `json:"active,omitempty"`
+	Environments map[string]map[string]string `json:"environments"`
+}
+
+// envStore persists named variable sets in .sectool/environments.json. Every
+// operation re-reads the file so hand edits are kept. Thread-safe.
+type envStore struct {
+	mu   sync.Mutex
+	path string
+}
+
+func newEnvStore(projectDir string) *envStore {
+	return &envStore{path: filepath.Join(projectDir, config.ProjectDirName, environmentsFileName)}
+}
+
+// Load returns all environments.
+func (e *envStore) Load() (*environmentsFile, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return e.load()
+}
+
+// Set merges vars into environment name, removing unset keys, and optionally makes
+// it the active environment. The environment is created if missing.
+func (e *envStore) Set(name string, vars map[string]string, unset []string, activate bool) (*environmentsFile, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	file, err := e.load()
+	if err != nil {
+		return nil, err
+	}
+	env := file.Environments[name]
+	if env == nil {
+		env = make(map[string]string)
+		file.Environments[name] = env
+	}
+	maps.Copy(env, vars)
+	for _, key := range unset {
+		delete(env, key)
+	}
+	if activate {
+		file.Active = name
+	}
+	return file, e.save(file)
+}
+
+// Delete removes environment name, clearing it as active.
+func (e *envStore) Delete(name string) (*environmentsFile, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	file, err := e.load()
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := file.Environments[name]; !ok {
+		return nil, fmt.Errorf("environment %q not found", name)
+	}
+	delete(file.Environments, name)
+	if file.Active == name {
+		file.Active = ""
+	}
+	return file, e.save(file)
+}
+
+// Resolve returns the variables of environment name, or of the active environment
+// when name is empty. No active environment resolves to nil without error.
+func (e *envStore) Resolve(name string) (string, map[string]string, error) {
+	file, err := e.Load()
+	if err != nil {
+		return "", nil, err
+	}
+	if name == "" {
+		name = file.Active
+		if name == "" {
+			return "", nil, nil
+		}
+	}
+	env, ok := file.Environments[name]
+	if !ok {
+		return "", nil, fmt.Errorf("environment %q not found: known environments %v", name, slices.Sorted(maps.Keys(file.Environments)))
+	}
+	return name, env, nil
+}
+
+func (e *envStore) load() (*environmentsFile, error) {
+	file := &environmentsFile{}
+	data, err := os.ReadFile(e.path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	} else if err == nil {
+		if err := json.Unmarshal(data, file); err != nil {
+			return nil, fmt.Errorf("parse %s: %w", e.path, err)
+		}
+	}
+	if file.Environments == nil {
+		file.Environments = make(map[string]map[string]string)
+	}
+	return file, nil
+}
+
+// save writes the file atomically. Values often hold tokens, so it is owner-only.
+func (e *envStore) save(file *environmentsFile) error {
+	if err := os.MkdirAll(filepath.Dir(e.path), 0755); err != nil {
+		return fmt.Errorf("create environments directory: %w", err)
+	}
+	data, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := e.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, e.path)
+}
+
+// expandTemplateVars replaces {{name}} placeholders defined in vars. Undefined
+// placeholders are left as-is and returned, so literal braces in payloads survive.
+func expandTemplateVars(s string, vars map[string]string) (string, []string) {
+	if len(vars) == 0 || !templateVarRe.MatchString(s) {
+		return s, nil
+	}
+	var undefined []string
+	out := templateVarRe.ReplaceAllStringFunc(s, func(match string) string {
+		name := templateVarRe.FindStringSubmatch(match)[1]
+		if v, ok := vars[name]; ok {
+			return v
+		}
+		if !slices.Contains(undefined, name) {
+			undefined = append(undefined, name)
+		}
+		return match
+	})
+	return out, undefined
+}
@@ -0,0 +1,164 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/andybalholm/cascadia"
+
+	"github.com/go-harden/llm-security-toolbox/sectool/bundle"
+)
+
+const (
+	defaultExtractLimit = 50
+	maxExtractValueLen  = 2000 // longer match values are cut and marked
+)
+
+// Response parts a regex is applied to.
+const (
+	extractInBody    = "body"
+	extractInHeaders = "headers"
+	extractInAll     = "all"
+)
+
+// responseExtractor applies one regex, JSON path or CSS selector to stored responses.
+type responseExtractor struct {
+	re       *regexp.Regexp
+	in       string          // regex only: body, headers or all
+	jsonPath [][]pathSegment // path split at [*] wildcards
+	css      cascadia.Matcher
+	attr     string // CSS only: attribute to return instead of text
+}
+
+func newRegexExtractor(expr, in string) (*responseExtractor, error) {
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid regex: %w", err)
+	}
+	switch in {
+	case "":
+		in = extractInBody
+	case extractInBody, extractInHeaders, extractInAll:
+	default:
+		return nil, fmt.Errorf("invalid in %q: use body, headers or all", in)
+	}
+	return &responseExtractor{re: re, in: in}, nil
+}
+
+// newJSONPathExtractor accepts the dot notation of parseJSONPath with an optional
+// leading "$." and [*] to expand every array element, e.g. "$.items[*].id".
+func newJSONPathExtractor(expr string) (*responseExtractor, error) {
+	expr = strings.TrimPrefix(strings.TrimPrefix(expr, "$"), ".")
+	e := &responseExtractor{}
+	for _, part := range strings.Split(expr, "[*]") {
+		part = strings.TrimPrefix(part, ".")
+		if part == "" {
+			e.jsonPath = append(e.jsonPath, nil)
+			continue
+		}
+		segments, err := parseJSONPath(part)
+		if err != nil {
+			return nil, fmt.Errorf("invalid JSON path %q: %w", expr, err)
+		}
+		e.jsonPath = append(e.jsonPath, segments)
+	}
+	return e, nil
+}
+
+func newCSSExtractor(selector, attr string) (*responseExtractor, error) {
+	sel, err := cascadia.ParseGroup(selector)
+	if err != nil {
+		return nil, fmt.Errorf("invalid CSS selector: %w", err)
+	}
+	return &responseExtractor{css: sel, attr: attr}, nil
+}
+
+// Extract returns up to limit matches from the response and the total number found.
+// Bodies are decoded (chunked, gzip, deflate) first. Regex matches return the first
+// capture group when the regex has one, else the whole match; JSON values that are
+// not strings are returned as JSON.
+func (e *responseExtractor) Extract(headers, body []byte, limit int) ([]string, int, error) {
+	body, _ = bundle.DecodeBody(string(headers), body)
+
+	var values []string
+	switch {
+	case e.re != nil:
+		var text []byte
+		switch e.in {
+		case extractInHeaders:
+			text = headers
+		case extractInAll:
+			text = append(append(bytes.Clone(headers), "\r\n"...), body...)
+		default:
+			text = body
+		}
+		for _, m := range e.re.FindAllSubmatch(text, -1) {
+			if len(m) > 1 {
+				values = append(values, string(m[1]))
+			} else {
+				values = append(values, string(m[0]))
+			}
+		}
+	case e.css != nil:
+		doc, err := goquery.NewDocumentFromReader(bytes.NewReader(body))
+		if err != nil {
+			return nil, 0, fmt.Errorf("parse HTML: %w", err)
+		}
+		doc.FindNodes(cascadia.QueryAll(doc.Get(0), e.css)...).Each(func(_ int, s *goquery.Selection) {
+			if e.attr == "" {
+				values = append(values, strings.TrimSpace(s.Text()))
+			} else if v, ok := s.Attr(e.attr); ok {
+				values = append(values, v)
+			}
+		})
+	default:
+		var data interface{}
+		if err := json.Unmarshal(body, &data); err != nil {
+			return nil, 0, errors.New("response body is not JSON")
+		}
+		for _, v := range jsonPathValues(data, e.jsonPath) {
+			if s, ok := v.(string); ok {
+				values = append(values, s)
+			} else {
+				b, _ := json.Marshal(v)
+				values = append(values, string(b))
+			}
+		}
+	}
+
+	total := len(values)
+	if len(values) > limit {
+		values = values[:limit]
+	}
+	for i, v := range values {
+		if len(v) > maxExtractValueLen {
+			values[i] = v[:maxExtractValueLen] + "...[truncated]"
+		}
+	}
+	return values, total, nil
+}
+
+// jsonPathValues walks parts, expanding every element of the array reached between
+// two parts. Paths that do not resolve produce no values.
+func jsonPathValues(data interface{}, parts [][]pathSegment) []interface{} {
+	v, err := getValueAtPath(data, parts[0])
+	if err != nil {
+		return nil
+	} else if len(parts) == 1 {
+		return []interface{}{v}
+	}
+	arr, ok := v.([]interface{})
+	if !ok {
+		return nil
+	}
+	var out []interface{}
+	for _, elem := range arr {
+		out = append(out, jsonPathValues(elem, parts[1:])...)
+	}
+	return out
+}
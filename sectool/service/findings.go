@@ -0,0 +1,118 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-harden/llm-security-toolbox/sectool/config"
+	"github.com/go-harden/llm-security-toolbox/sectool/protocol"
+	"github.com/go-harden/llm-security-toolbox/sectool/service/ids"
+)
+
+// findingsDirName is the project findings directory within config.ProjectDirName,
+// one JSON file per finding.
+const findingsDirName = "findings"
+
+var validSeverities = map[string]bool{
+	protocol.SeverityInfo:     true,
+	protocol.SeverityLow:      true,
+	protocol.SeverityMedium:   true,
+	protocol.SeverityHigh:     true,
+	protocol.SeverityCritical: true,
+}
+
+// findingsStore persists findings as .sectool/findings/<finding_id>.json so each can be
+// reviewed and edited on its own. Thread-safe.
+type findingsStore struct {
+	mu  sync.Mutex
+	dir string
+}
+
+func newFindingsStore(projectDir string) *findingsStore {
+	return &findingsStore{dir: filepath.Join(projectDir, config.ProjectDirName, findingsDirName)}
+}
+
+// Add assigns f an ID and creation time, writes it, and returns it.
+func (fs *findingsStore) Add(f protocol.Finding) (protocol.Finding, error) {
+	f.FindingID = ids.Generate(ids.DefaultLength)
+	f.CreatedAt = time.Now().UTC().Format(time.RFC3339)
+	if f.Status == "" {
+		f.Status = protocol.FindingStatusDraft
+	}
+
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	if err := os.MkdirAll(fs.dir, 0755); err != nil {
+		return protocol.Finding{}, fmt.Errorf("create findings directory: %w", err)
+	}
+	data, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return protocol.Finding{}, err
+	}
+	if err := os.WriteFile(fs.path(f.FindingID), data, 0644); err != nil {
+		return protocol.Finding{}, err
+	}
+	return f, nil
+}
+
+// Get reads a finding by ID.
+func (fs *findingsStore) Get(id string) (*protocol.Finding, error) {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	data, err := os.ReadFile(fs.path(id))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	var f protocol.Finding
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("parse finding %s: %w", id, err)
+	}
+	return &f, nil
+}
+
+// List returns all findings, newest first. Files that fail to parse are skipped.
+func (fs *findingsStore) List() ([]protocol.Finding, error) {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	entries, err := os.ReadDir(fs.dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	var findings []protocol.Finding
+	for _, e := range entries {
+		if e.IsDir() || filepath.Ext(e.Name()) != ".json" {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(fs.dir, e.Name()))
+		if err != nil {
+			return nil, err
+		}
+		var f protocol.Finding
+		if err := json.Unmarshal(data, &f); err != nil {
+			continue // skip files damaged by hand edits
+		}
+		findings = append(findings, f)
+	}
+	slices.SortStableFunc(findings, func(a, b protocol.Finding) int {
+		return strings.Compare(b.CreatedAt, a.CreatedAt)
+	})
+	return findings, nil
+}
+
+func (fs *findingsStore) path(id string) string {
+	return filepath.Join(fs.dir, filepath.Base(id)+".json")
+}
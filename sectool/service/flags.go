@@ -2,6 +2,8 @@ package service
 
 import (
 	"fmt"
+	"net"
+	"os"
 
 	"github.com/spf13/pflag"
 
@@ -16,15 +18,22 @@ const (
 	WorkflowModeNone       = protocol.WorkflowModeNone
 	WorkflowModeExplore    = protocol.WorkflowModeExplore
 	WorkflowModeTestReport = protocol.WorkflowModeTestReport
+	WorkflowModeAPI        = protocol.WorkflowModeAPI
+	WorkflowModeLLMApp     = protocol.WorkflowModeLLMApp
+	WorkflowModeMobile     = protocol.WorkflowModeMobile
 	WorkflowModeCLI        = protocol.WorkflowModeCLI // undocumented, for CLI client use only
 )
 
 // MCPServerFlags holds flags for MCP server mode.
 type MCPServerFlags struct {
 	ConfigPath   string
+	ProjectDir   string // project directory holding .sectool/, "" for the working directory
+	Profile      string // config profile name, "" for none
 	BurpMCPURL   string
 	MCPPort      int
 	ProxyPort    int    // 0 = not set via CLI
+	ProxyListen  string // built-in proxy "host:port", "" for 127.0.0.1 and ProxyPort
+	BuiltinProxy bool   // use the built-in proxy without trying Burp
 	RequireBurp  bool   // --burp flag: require Burp, error if unavailable
 	WorkflowMode string // "", "none", "explore", "test-report"
 }
@@ -33,28 +42,78 @@ type MCPServerFlags struct {
 func ParseMCPServerFlags(args []string) (MCPServerFlags, error) {
 	fs := pflag.NewFlagSet("mcp", pflag.ContinueOnError)
 	fs.SetInterspersed(true)
-	flags := MCPServerFlags{
-		BurpMCPURL: config.DefaultBurpMCPURL,
-	}
+	var flags MCPServerFlags
 
 	fs.StringVar(&flags.ConfigPath, "config", "", "config file path (default: ~/.sectool/config.json)")
-	fs.StringVar(&flags.BurpMCPURL, "burp-mcp-url", flags.BurpMCPURL, "Burp MCP SSE endpoint URL")
+	fs.StringVar(&flags.Profile, "profile", "", "config profile to apply (default: $SECTOOL_PROFILE)")
+	fs.StringVar(&flags.BurpMCPURL, "burp-mcp-url", "", "Burp MCP SSE endpoint URL (default: from config or "+config.DefaultBurpMCPURL+")")
 	fs.IntVar(&flags.MCPPort, "port", 0, "MCP server port (default: from config or 9119)")
 	fs.IntVar(&flags.ProxyPort, "proxy-port", 0, "built-in proxy port (skips Burp, default: from config or 8080)")
+	fs.StringVar(&flags.ProxyListen, "proxy-listen", "", "built-in proxy listen address host:port (skips Burp; ':8080' for all interfaces)")
 	fs.BoolVar(&flags.RequireBurp, "burp", false, "require Burp MCP (error if unavailable)")
-	fs.StringVar(&flags.WorkflowMode, "workflow", "", "MCP workflow mode: none, explore, test-report")
+	fs.StringVar(&flags.ProjectDir, "project-dir", "", "project directory for .sectool/ scope, guides and checklist (default: working directory)")
+	fs.StringVar(&flags.WorkflowMode, "workflow", "", "MCP workflow mode: none, explore, test-report, api, llm-app, mobile-backend")
 
 	if err := fs.Parse(args); err != nil {
 		return flags, err
 	}
 
-	// Validate workflow mode value
-	switch flags.WorkflowMode {
-	case "", WorkflowModeNone, WorkflowModeExplore, WorkflowModeTestReport:
-		// Valid
+	if err := validateProxyListen(flags.ProxyListen); err != nil {
+		return flags, err
+	}
+	return flags, validateWorkflowMode(flags.WorkflowMode)
+}
+
+// ParseProxyStartFlags parses flags for 'sectool proxy start', which runs the service
+// with the built-in proxy and never connects to Burp.
+func ParseProxyStartFlags(args []string) (MCPServerFlags, error) {
+	fs := pflag.NewFlagSet("proxy start", pflag.ContinueOnError)
+	fs.SetInterspersed(true)
+	flags := MCPServerFlags{BuiltinProxy: true}
+
+	fs.StringVar(&flags.ProxyListen, "listen", "", "proxy listen address host:port (default: 127.0.0.1 and proxy_port from config; ':8080' for all interfaces)")
+	fs.IntVar(&flags.MCPPort, "port", 0, "MCP server port (default: from config or 9119)")
+	fs.StringVar(&flags.ProjectDir, "project-dir", "", "project directory for .sectool/ scope, guides and checklist (default: working directory)")
+	fs.StringVar(&flags.WorkflowMode, "workflow", WorkflowModeNone, "MCP workflow mode for agents connecting to this service")
+
+	fs.Usage = func() {
+		_, _ = fmt.Fprint(os.Stderr, `Usage: sectool proxy start [options]
+
+Run the built-in intercepting proxy without Burp. Point a browser or device at
+the listen address and trust the CA certificate printed at startup; captured
+traffic works with every proxy, replay and crawl command. The MCP service runs
+in the same process, so this replaces 'sectool mcp' while it is running.
+
+Options:
+`)
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return flags, err
+	}
+	if err := validateProxyListen(flags.ProxyListen); err != nil {
+		return flags, err
+	}
+	return flags, validateWorkflowMode(flags.WorkflowMode)
+}
+
+func validateWorkflowMode(mode string) error {
+	switch mode {
+	case "", WorkflowModeNone, WorkflowModeExplore, WorkflowModeTestReport,
+		WorkflowModeAPI, WorkflowModeLLMApp, WorkflowModeMobile:
+		return nil
 	default:
-		return flags, fmt.Errorf("invalid --workflow value %q: must be none, explore, or test-report", flags.WorkflowMode)
+		return fmt.Errorf("invalid --workflow value %q: must be none, explore, test-report, api, llm-app, or mobile-backend", mode)
 	}
+}
 
-	return flags, nil
+func validateProxyListen(addr string) error {
+	if addr == "" {
+		return nil
+	}
+	if _, port, err := net.SplitHostPort(addr); err != nil || port == "" {
+		return fmt.Errorf("invalid proxy listen address %q: expected host:port (e.g., 127.0.0.1:8080 or :8080)", addr)
+	}
+	return nil
 }
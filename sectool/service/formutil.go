@@ -0,0 +1,230 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"mime"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/go-harden/llm-security-toolbox/sectool/service/ids"
+)
+
+const formURLEncoded = "application/x-www-form-urlencoded"
+
+// modifyFormBody applies set_form/remove_form edits to an
+// application/x-www-form-urlencoded or multipart/form-data body.
+// An empty body without a Content-Type is treated as URL-encoded. Returns the
+// Content-Type to send, which changes when a multipart boundary is regenerated
+// because a new value contains it, or when one is inferred.
+func modifyFormBody(contentType string, body []byte, setForm map[string]string, removeForm []string) (string, []byte, error) {
+	if len(setForm) == 0 && len(removeForm) == 0 {
+		return contentType, body, nil
+	}
+
+	if contentType == "" && len(bytes.TrimSpace(body)) == 0 {
+		contentType = formURLEncoded
+	}
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return "", nil, fmt.Errorf("body is not a form: invalid Content-Type %q (hint: set Content-Type to %s or multipart/form-data)", contentType, formURLEncoded)
+	}
+	switch mediaType {
+	case formURLEncoded:
+		return contentType, modifyEncodedForm(body, setForm, removeForm), nil
+	case "multipart/form-data":
+		boundary := params["boundary"]
+		if boundary == "" {
+			return "", nil, errors.New("multipart body has no boundary in Content-Type")
+		}
+		parts, err := splitMultipart(body, boundary)
+		if err != nil {
+			return "", nil, err
+		}
+		parts.modify(setForm, removeForm)
+		if parts.contains("--" + boundary) {
+			boundary = "sectool" + ids.Generate(24)
+			params["boundary"] = boundary
+			contentType = mime.FormatMediaType(mediaType, params)
+		}
+		return contentType, parts.build(boundary), nil
+	default:
+		return "", nil, fmt.Errorf("body is not a form: Content-Type is %s (hint: use set_json for JSON bodies)", mediaType)
+	}
+}
+
+// ModifyFormBody applies form modifications to a URL-encoded or multipart body
+// and returns the Content-Type to send with it.
+//
+// Exported for CLI parity when sending requests from bundles/files.
+func ModifyFormBody(contentType string, body []byte, setForm map[string]string, removeForm []string) (string, []byte, error) {
+	return modifyFormBody(contentType, body, setForm, removeForm)
+}
+
+// formFieldMap converts a set_form or set_xml argument to string values. Strings
+// are used as is, null as an empty value, and other JSON values in their JSON encoding.
+func formFieldMap(raw interface{}) map[string]string {
+	fields, ok := raw.(map[string]interface{})
+	if !ok || len(fields) == 0 {
+		return nil
+	}
+	values := make(map[string]string, len(fields))
+	for name, v := range fields {
+		switch v := v.(type) {
+		case string:
+			values[name] = v
+		case nil:
+			values[name] = ""
+		default:
+			encoded, _ := json.Marshal(v)
+			values[name] = string(encoded)
+		}
+	}
+	return values
+}
+
+// modifyEncodedForm removes then sets fields of a URL-encoded body. Untouched
+// pairs keep their original encoding and order; new fields are appended in name order.
+func modifyEncodedForm(body []byte, setForm map[string]string, removeForm []string) []byte {
+	var pairs []string
+	if encoded := strings.TrimRight(string(body), "\r\n"); encoded != "" {
+		pairs = strings.Split(encoded, "&")
+	}
+	pairs = slices.DeleteFunc(pairs, func(pair string) bool {
+		key, _, _ := strings.Cut(pair, "=")
+		name, err := url.QueryUnescape(key)
+		return err == nil && slices.Contains(removeForm, name)
+	})
+	encoded := strings.Join(pairs, "&")
+	for _, name := range slices.Sorted(maps.Keys(setForm)) {
+		encoded = setEncodedParam(encoded, name, setForm[name])
+	}
+	return []byte(encoded)
+}
+
+// multipartPart is one part of a multipart body, kept as raw bytes so untouched
+// parts are resent exactly.
+type multipartPart struct {
+	header  []byte // header lines, without the blank line that ends them
+	name    string // form field name from Content-Disposition
+	content []byte
+}
+
+// multipartBody is a parsed multipart body.
+type multipartBody struct {
+	preamble []byte
+	parts    []multipartPart
+	epilogue []byte
+}
+
+// splitMultipart parses a CRLF-delimited multipart body. An empty body has no parts.
+func splitMultipart(body []byte, boundary string) (*multipartBody, error) {
+	if len(bytes.TrimSpace(body)) == 0 {
+		return &multipartBody{epilogue: []byte("\r\n")}, nil
+	}
+	delim := []byte("--" + boundary)
+	start := bytes.Index(body, delim)
+	if start < 0 || (start > 0 && !bytes.HasSuffix(body[:start], []byte("\r\n"))) {
+		return nil, fmt.Errorf("multipart body does not contain boundary %q", boundary)
+	}
+
+	mb := &multipartBody{preamble: body[:start]}
+	next := append([]byte("\r\n"), delim...)
+	rest := body[start+len(delim):]
+	for {
+		if epilogue, ok := bytes.CutPrefix(rest, []byte("--")); ok {
+			mb.epilogue = epilogue
+			return mb, nil
+		}
+		part, ok := bytes.CutPrefix(rest, []byte("\r\n"))
+		if !ok {
+			return nil, errors.New("malformed multipart body: boundary not followed by CRLF")
+		}
+		end := bytes.Index(part, next)
+		if end < 0 {
+			return nil, errors.New("malformed multipart body: missing closing boundary")
+		}
+		rest = part[end+len(next):]
+
+		var p multipartPart
+		if content, ok := bytes.CutPrefix(part[:end], []byte("\r\n")); ok {
+			p.content = content // no headers
+		} else if p.header, p.content, ok = bytes.Cut(part[:end], []byte("\r\n\r\n")); !ok {
+			return nil, errors.New("malformed multipart body: part headers not terminated")
+		}
+		p.name = multipartFieldName(p.header)
+		mb.parts = append(mb.parts, p)
+	}
+}
+
+// multipartFieldName returns the name parameter of a part's Content-Disposition.
+func multipartFieldName(header []byte) string {
+	for _, line := range strings.Split(string(header), "\r\n") {
+		key, value, ok := strings.Cut(line, ":")
+		if !ok || !strings.EqualFold(strings.TrimSpace(key), "Content-Disposition") {
+			continue
+		}
+		if _, params, err := mime.ParseMediaType(strings.TrimSpace(value)); err == nil {
+			return params["name"]
+		}
+	}
+	return ""
+}
+
+var multipartQuoteEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+
+// modify removes then sets fields. Setting replaces the content of every part
+// with the name, keeping its headers (filename, Content-Type); fields not present
+// are appended as text parts in name order.
+func (mb *multipartBody) modify(setForm map[string]string, removeForm []string) {
+	mb.parts = slices.DeleteFunc(mb.parts, func(p multipartPart) bool {
+		return slices.Contains(removeForm, p.name)
+	})
+	for _, name := range slices.Sorted(maps.Keys(setForm)) {
+		value := []byte(setForm[name])
+		var found bool
+		for i := range mb.parts {
+			if mb.parts[i].name == name {
+				mb.parts[i].content = value
+				found = true
+			}
+		}
+		if !found {
+			mb.parts = append(mb.parts, multipartPart{
+				header:  []byte(`Content-Disposition: form-data; name="` + multipartQuoteEscaper.Replace(name) + `"`),
+				name:    name,
+				content: value,
+			})
+		}
+	}
+}
+
+// contains reports whether any part's content contains s.
+func (mb *multipartBody) contains(s string) bool {
+	return slices.ContainsFunc(mb.parts, func(p multipartPart) bool {
+		return bytes.Contains(p.content, []byte(s))
+	})
+}
+
+// build serializes the body with boundary.
+func (mb *multipartBody) build(boundary string) []byte {
+	var buf bytes.Buffer
+	buf.Write(mb.preamble)
+	for _, p := range mb.parts {
+		buf.WriteString("--" + boundary + "\r\n")
+		if len(p.header) > 0 {
+			buf.Write(p.header)
+			buf.WriteString("\r\n")
+		}
+		buf.WriteString("\r\n")
+		buf.Write(p.content)
+		buf.WriteString("\r\n")
+	}
+	buf.WriteString("--" + boundary + "--")
+	buf.Write(mb.epilogue)
+	return buf.Bytes()
+}
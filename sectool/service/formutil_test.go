@@ -0,0 +1,105 @@
+package service
+
+import (
+	"mime"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestModifyFormBodyURLEncoded(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		ct     string
+		body   string
+		set    map[string]string
+		remove []string
+		want   string
+		wantCT string
+	}{
+		{"replace_in_place", formURLEncoded, "a=1&role=user&b=x%2By", map[string]string{"role": "admin"}, nil, "a=1&role=admin&b=x%2By", formURLEncoded},
+		{"every_occurrence", formURLEncoded, "id=1&id=2", map[string]string{"id": "3"}, nil, "id=3&id=3", formURLEncoded},
+		{"append_sorted", formURLEncoded, "a=1", map[string]string{"z": "1", "m": "a b&c"}, nil, "a=1&m=a+b%26c&z=1", formURLEncoded},
+		{"remove_encoded_name", formURLEncoded + "; charset=UTF-8", "user%5Bname%5D=x&csrf=t&csrf=u", nil, []string{"user[name]", "csrf"}, "", formURLEncoded + "; charset=UTF-8"},
+		{"remove_then_set", formURLEncoded, "a=1&b=2", map[string]string{"a": "new"}, []string{"a"}, "b=2&a=new", formURLEncoded},
+		{"empty_body_infers_type", "", "", map[string]string{"q": "1"}, nil, "q=1", formURLEncoded},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ct, body, err := modifyFormBody(tc.ct, []byte(tc.body), tc.set, tc.remove)
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, string(body))
+			assert.Equal(t, tc.wantCT, ct)
+		})
+	}
+}
+
+func TestModifyFormBodyMultipart(t *testing.T) {
+	t.Parallel()
+
+	const ct = "multipart/form-data; boundary=XyZ"
+	body := "--XyZ\r\nContent-Disposition: form-data; name=\"title\"\r\n\r\nhello\r\n" +
+		"--XyZ\r\nContent-Disposition: form-data; name=\"file\"; filename=\"a.png\"\r\nContent-Type: image/png\r\n\r\n\x89PNG\r\n\x00\r\n" +
+		"--XyZ\r\nContent-Disposition: form-data; name=\"csrf\"\r\n\r\ntok\r\n" +
+		"--XyZ--\r\n"
+
+	t.Run("set_remove_append", func(t *testing.T) {
+		gotCT, got, err := modifyFormBody(ct, []byte(body), map[string]string{"file": "<?php system($_GET[c]); ?>", "role": "admin"}, []string{"csrf"})
+		require.NoError(t, err)
+		assert.Equal(t, ct, gotCT)
+		assert.Equal(t, "--XyZ\r\nContent-Disposition: form-data; name=\"title\"\r\n\r\nhello\r\n"+
+			"--XyZ\r\nContent-Disposition: form-data; name=\"file\"; filename=\"a.png\"\r\nContent-Type: image/png\r\n\r\n<?php system($_GET[c]); ?>\r\n"+
+			"--XyZ\r\nContent-Disposition: form-data; name=\"role\"\r\n\r\nadmin\r\n"+
+			"--XyZ--\r\n", string(got))
+	})
+
+	t.Run("untouched_parts_unchanged", func(t *testing.T) {
+		_, got, err := modifyFormBody(ct, []byte(body), map[string]string{"title": "hi"}, nil)
+		require.NoError(t, err)
+		assert.Equal(t, strings.Replace(body, "\r\n\r\nhello\r\n", "\r\n\r\nhi\r\n", 1), string(got))
+	})
+
+	t.Run("boundary_regenerated", func(t *testing.T) {
+		gotCT, got, err := modifyFormBody(ct, []byte(body), map[string]string{"title": "a\r\n--XyZ--\r\n"}, nil)
+		require.NoError(t, err)
+		mediaType, params, err := mime.ParseMediaType(gotCT)
+		require.NoError(t, err)
+		assert.Equal(t, "multipart/form-data", mediaType)
+		boundary := params["boundary"]
+		require.NotEqual(t, "XyZ", boundary)
+		parts, err := splitMultipart(got, boundary)
+		require.NoError(t, err)
+		require.Len(t, parts.parts, 3)
+		assert.Equal(t, "a\r\n--XyZ--\r\n", string(parts.parts[0].content))
+		assert.Equal(t, "\x89PNG\r\n\x00", string(parts.parts[1].content))
+	})
+
+	t.Run("empty_body", func(t *testing.T) {
+		_, got, err := modifyFormBody(ct, nil, map[string]string{`we"ird`: "v"}, nil)
+		require.NoError(t, err)
+		assert.Equal(t, "--XyZ\r\nContent-Disposition: form-data; name=\"we\\\"ird\"\r\n\r\nv\r\n--XyZ--\r\n", string(got))
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		_, _, err := modifyFormBody(ct, []byte("--other\r\n\r\nx\r\n--other--"), map[string]string{"a": "b"}, nil)
+		assert.ErrorContains(t, err, `does not contain boundary "XyZ"`)
+		_, _, err = modifyFormBody(ct, []byte("--XyZ\r\nContent-Disposition: form-data; name=\"a\"\r\n\r\nno end"), map[string]string{"a": "b"}, nil)
+		assert.ErrorContains(t, err, "missing closing boundary")
+		_, _, err = modifyFormBody("application/json", []byte(`{}`), map[string]string{"a": "b"}, nil)
+		assert.ErrorContains(t, err, "use set_json")
+		_, _, err = modifyFormBody("multipart/form-data", nil, map[string]string{"a": "b"}, nil)
+		assert.ErrorContains(t, err, "no boundary")
+	})
+}
+
+func TestFormFieldMap(t *testing.T) {
+	t.Parallel()
+
+	assert.Nil(t, formFieldMap(nil))
+	assert.Equal(t, map[string]string{"s": "x", "n": "5", "b": "true", "z": "", "o": `{"k":1}`},
+		formFieldMap(map[string]interface{}{"s": "x", "n": float64(5), "b": true, "z": nil, "o": map[string]interface{}{"k": 1}}))
+}
@@ -0,0 +1,189 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testGraphQLSDL = `
+"""Root query"""
+type Query {
+  "Look up a user"
+  user(id: ID!): User
+  users(first: Int = 10, role: Role): [User!]!
+  search(term: String!): [SearchResult]
+}
+
+type Mutation {
+  updateUser(input: UpdateUserInput!): User @auth(requires: ADMIN)
+}
+
+type User implements Node & Named {
+  id: ID!
+  name: String
+  role: Role
+  friends(first: Int!): [User]
+  manager: User
+  legacy: String @deprecated(reason: "unused")
+}
+
+interface Node { id: ID! }
+interface Named { name: String }
+
+enum Role { ADMIN USER }
+
+union SearchResult = | User | Post
+
+type Post { id: ID! title: String }
+
+input UpdateUserInput {
+  id: ID!
+  name: String
+  address: AddressInput
+}
+
+input AddressInput { city: String! zip: String }
+
+scalar DateTime @specifiedBy(url: "https://example.com")
+
+directive @auth(requires: Role = ADMIN) repeatable on OBJECT | FIELD_DEFINITION
+
+extend type Query { me: User }
+`
+
+const testGraphQLIntrospection = `{"data":{"__schema":{
+  "queryType":{"name":"Query"},"mutationType":null,"subscriptionType":null,
+  "types":[
+    {"kind":"OBJECT","name":"Query","fields":[
+      {"name":"user","args":[{"name":"id","type":{"kind":"NON_NULL","ofType":{"kind":"SCALAR","name":"ID"}},"defaultValue":null}],
+       "type":{"kind":"OBJECT","name":"User"},"isDeprecated":false},
+      {"name":"users","args":[{"name":"first","type":{"kind":"SCALAR","name":"Int"},"defaultValue":"10"}],
+       "type":{"kind":"NON_NULL","ofType":{"kind":"LIST","ofType":{"kind":"OBJECT","name":"User"}}},"isDeprecated":false}]},
+    {"kind":"OBJECT","name":"User","fields":[
+      {"name":"id","args":[],"type":{"kind":"NON_NULL","ofType":{"kind":"SCALAR","name":"ID"}},"isDeprecated":false},
+      {"name":"email","args":[],"type":{"kind":"SCALAR","name":"String"},"isDeprecated":false}],
+     "interfaces":[{"kind":"INTERFACE","name":"Node"}]},
+    {"kind":"ENUM","name":"Role","enumValues":[{"name":"ADMIN"},{"name":"USER"}]},
+    {"kind":"SCALAR","name":"ID"},
+    {"kind":"OBJECT","name":"__Schema","fields":[]}
+  ]}}}`
+
+func TestParseGraphQLSDL(t *testing.T) {
+	t.Parallel()
+
+	schema, err := parseGraphQLSDL(testGraphQLSDL)
+	require.NoError(t, err)
+	assert.Equal(t, "Query", schema.QueryType)
+	assert.Equal(t, "Mutation", schema.MutationType)
+	assert.Empty(t, schema.SubscriptionType)
+
+	query := schema.Types["Query"]
+	require.NotNil(t, query)
+	assert.Equal(t, "Root query", query.Description)
+	require.Len(t, query.Fields, 4) // includes the extension
+	assert.Equal(t, "Look up a user", query.Fields[0].Description)
+	assert.Equal(t, "[User!]!", query.Fields[1].Type)
+	require.Len(t, query.Fields[1].Args, 2)
+	assert.Equal(t, "10", query.Fields[1].Args[0].DefaultValue)
+	assert.Equal(t, "me", query.Fields[3].Name)
+
+	user := schema.Types["User"]
+	assert.Equal(t, []string{"Node", "Named"}, user.Interfaces)
+	assert.True(t, user.Fields[5].Deprecated)
+	assert.Equal(t, []string{"ADMIN", "USER"}, schema.Types["Role"].EnumValues)
+	assert.Equal(t, []string{"User", "Post"}, schema.Types["SearchResult"].PossibleTypes)
+	assert.Equal(t, "INPUT_OBJECT", schema.Types["UpdateUserInput"].Kind)
+	assert.Equal(t, "SCALAR", schema.Types["DateTime"].Kind)
+
+	t.Run("schema_block", func(t *testing.T) {
+		schema, err := parseGraphQLSDL("schema { query: RootQuery }\ntype RootQuery { ping: String }")
+		require.NoError(t, err)
+		assert.Equal(t, "RootQuery", schema.QueryType)
+	})
+
+	t.Run("no_root", func(t *testing.T) {
+		_, err := parseGraphQLSDL("type User { id: ID }")
+		assert.Error(t, err)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		_, err := parseGraphQLSDL("type Query { user(id: ID!: User }")
+		assert.Error(t, err)
+	})
+}
+
+func TestParseGraphQLIntrospection(t *testing.T) {
+	t.Parallel()
+
+	schema, err := parseGraphQLIntrospection([]byte(testGraphQLIntrospection))
+	require.NoError(t, err)
+	assert.Equal(t, "Query", schema.QueryType)
+	assert.Empty(t, schema.MutationType)
+
+	query := schema.Types["Query"]
+	require.Len(t, query.Fields, 2)
+	assert.Equal(t, "User", query.Fields[0].Type)
+	assert.Equal(t, "ID!", query.Fields[0].Args[0].Type)
+	assert.Equal(t, "[User]!", query.Fields[1].Type)
+	assert.Equal(t, "10", query.Fields[1].Args[0].DefaultValue)
+	assert.Equal(t, []string{"Node"}, schema.Types["User"].Interfaces)
+	assert.Equal(t, []string{"ADMIN", "USER"}, schema.Types["Role"].EnumValues)
+
+	t.Run("rejected", func(t *testing.T) {
+		_, err := parseGraphQLIntrospection([]byte(`{"errors":[{"message":"GraphQL introspection is not allowed"}]}`))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "introspection rejected: GraphQL introspection is not allowed")
+	})
+
+	t.Run("not_json", func(t *testing.T) {
+		_, err := parseGraphQLIntrospection([]byte("<html>"))
+		assert.Error(t, err)
+	})
+}
+
+func TestBuildGraphQLOperations(t *testing.T) {
+	t.Parallel()
+
+	schema, err := parseGraphQLSDL(testGraphQLSDL)
+	require.NoError(t, err)
+
+	ops := buildGraphQLOperations(schema, graphQLDefaultDepth)
+	require.Len(t, ops, 5)
+	byField := make(map[string]graphQLOperation)
+	for _, op := range ops {
+		byField[op.Field] = op
+	}
+
+	user := byField["user"]
+	assert.Equal(t, "query", user.Kind)
+	assert.Equal(t, []string{"id: ID!"}, user.Args)
+	// friends needs an argument and legacy is deprecated; manager recurses into User
+	assert.Equal(t, "query User($id: ID!) { user(id: $id) { id name role } }", user.Document)
+	assert.Equal(t, map[string]interface{}{"id": "1"}, user.Variables)
+
+	users := byField["users"]
+	assert.Equal(t, "[User!]!", users.ReturnType)
+	assert.Equal(t, map[string]interface{}{"first": 1, "role": "ADMIN"}, users.Variables)
+
+	assert.Equal(t, "query Search($term: String!) { search(term: $term) { __typename } }", byField["search"].Document)
+
+	update := byField["updateUser"]
+	assert.Equal(t, "mutation", update.Kind)
+	assert.Equal(t, map[string]interface{}{
+		"input": map[string]interface{}{
+			"id":      "1",
+			"name":    "test",
+			"address": map[string]interface{}{"city": "test"},
+		},
+	}, update.Variables)
+
+	t.Run("depth", func(t *testing.T) {
+		schema, err := parseGraphQLSDL("type Query { post: Post }\ntype Post { title: String author: Author }\ntype Author { name: String }")
+		require.NoError(t, err)
+
+		assert.Equal(t, "query Post { post { title } }", buildGraphQLOperations(schema, 1)[0].Document)
+		assert.Equal(t, "query Post { post { title author { name } } }", buildGraphQLOperations(schema, 2)[0].Document)
+	})
+}
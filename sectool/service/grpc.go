@@ -0,0 +1,182 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"github.com/go-harden/llm-security-toolbox/sectool/grpc"
+	"github.com/go-harden/llm-security-toolbox/sectool/protocol"
+)
+
+// grpcExchange is a gRPC or gRPC-Web call split into its messages and status.
+type grpcExchange struct {
+	method       string // /package.Service/Method
+	protocol     string // grpc.ProtocolGRPC, ProtocolWeb or ProtocolWebText
+	encoding     string // request grpc-encoding
+	request      []grpc.Frame
+	respEncoding string
+	response     []grpc.Frame
+	httpStatus   int
+	status       string // grpc-status, from headers, trailers or a gRPC-Web trailer frame
+	message      string // grpc-message
+	frameErr     string // framing error of a body that was only partly parsed
+}
+
+// isGRPCRequest reports whether a raw request carries a gRPC Content-Type.
+func isGRPCRequest(raw []byte) bool {
+	headers, _ := splitHeadersBody(raw)
+	return grpc.ContentProtocol(http.Header(parseHeadersToMap(string(headers))).Get("Content-Type")) != ""
+}
+
+// parseGRPCExchange splits a raw request and optional response. Truncated framing
+// keeps the frames before it and is reported in frameErr.
+func parseGRPCExchange(rawReq, rawResp []byte) (*grpcExchange, error) {
+	reqHeaders, reqBody := splitHeadersBody(rawReq)
+	h := http.Header(parseHeadersToMap(string(reqHeaders)))
+	ex := &grpcExchange{
+		method:   extractRequestPath(rawReq),
+		protocol: grpc.ContentProtocol(h.Get("Content-Type")),
+		encoding: h.Get("Grpc-Encoding"),
+	}
+	if ex.protocol == "" {
+		return nil, errors.New("not a gRPC request: Content-Type is " + strconv.Quote(h.Get("Content-Type")))
+	}
+	var err error
+	if ex.request, err = grpc.ParseFrames(reqBody, ex.protocol); err != nil {
+		ex.frameErr = "request: " + err.Error()
+	}
+	if len(rawResp) == 0 {
+		return ex, nil
+	}
+
+	respHeaders, respBody := splitHeadersBody(rawResp)
+	rh := http.Header(parseHeadersToMap(string(respHeaders)))
+	ex.httpStatus = readResponseStatusCode(rawResp)
+	ex.respEncoding = rh.Get("Grpc-Encoding")
+	ex.status, ex.message = rh.Get("Grpc-Status"), rh.Get("Grpc-Message")
+	// A non-gRPC response (e.g. an error page from a gateway) has no frames
+	if respProtocol := grpc.ContentProtocol(rh.Get("Content-Type")); respProtocol != "" && len(respBody) > 0 {
+		if ex.response, err = grpc.ParseFrames(respBody, respProtocol); err != nil && ex.frameErr == "" {
+			ex.frameErr = "response: " + err.Error()
+		}
+	}
+	for _, f := range ex.response {
+		if f.Trailer {
+			trailer := grpc.ParseTrailer(f.Data)
+			ex.status, ex.message = trailer["grpc-status"], trailer["grpc-message"]
+		}
+	}
+	return ex, nil
+}
+
+// requestBody serializes the request frames with their original protocol.
+func (ex *grpcExchange) requestBody() []byte {
+	return grpc.EncodeFrames(ex.request, ex.protocol)
+}
+
+// grpcMethodTypes returns the input and output types of method from the loaded
+// schema, or nils when it is not known.
+func (s *Server) grpcMethodTypes(method string) (in, out protoreflect.MessageDescriptor) {
+	md, ok := s.grpcSchema.Method(method)
+	if !ok {
+		return nil, nil
+	}
+	return md.Input(), md.Output()
+}
+
+// decodeGRPCMessages decodes data frames with md, or without a schema when md is nil
+// or the payload doesn't match it. Trailer frames are skipped.
+func (s *Server) decodeGRPCMessages(frames []grpc.Frame, encoding string, md protoreflect.MessageDescriptor) []protocol.GRPCMessage {
+	var out []protocol.GRPCMessage
+	for _, f := range frames {
+		if f.Trailer {
+			continue
+		}
+		out = append(out, s.decodeGRPCMessage(f, encoding, md))
+	}
+	return out
+}
+
+func (s *Server) decodeGRPCMessage(f grpc.Frame, encoding string, md protoreflect.MessageDescriptor) protocol.GRPCMessage {
+	data := f.Data
+	msg := protocol.GRPCMessage{Size: len(data)}
+	if f.Compressed {
+		var err error
+		if data, err = grpc.Decompress(data, encoding); err != nil {
+			msg.Error = err.Error()
+			return msg
+		}
+	}
+	if md != nil {
+		decoded, err := s.grpcSchema.DecodeMessage(md, data)
+		if err == nil {
+			msg.JSON = decoded
+			return msg
+		}
+		msg.Error = err.Error() + "; decoded without schema"
+	}
+	raw, err := grpc.DecodeRaw(data)
+	if err != nil {
+		msg.Error = "not a protobuf message: " + err.Error()
+		return msg
+	}
+	msg.JSON, _ = json.Marshal(raw)
+	msg.Raw = true
+	return msg
+}
+
+// editGRPCMessage applies a replacement message and set/remove edits to one frame,
+// returning an uncompressed frame. Without md the message uses the field-number
+// keyed rendering of grpc.DecodeRaw.
+func (s *Server) editGRPCMessage(f grpc.Frame, encoding string, md protoreflect.MessageDescriptor, replacement []byte, setFields map[string]interface{}, removeFields []string) (grpc.Frame, error) {
+	var current []byte
+	if replacement != nil {
+		current = replacement
+	} else {
+		decoded := s.decodeGRPCMessage(f, encoding, md)
+		if decoded.JSON == nil || (md != nil && decoded.Raw) {
+			return f, errors.New("cannot edit message: " + decoded.Error)
+		}
+		current = decoded.JSON
+	}
+	edited, err := modifyJSONBodyMap(current, setFields, removeFields)
+	if err != nil {
+		return f, err
+	}
+
+	var data []byte
+	if md != nil {
+		data, err = s.grpcSchema.EncodeMessage(md, edited)
+	} else {
+		var fields map[string]interface{}
+		dec := json.NewDecoder(bytes.NewReader(edited))
+		dec.UseNumber()
+		if err = dec.Decode(&fields); err != nil {
+			return f, fmt.Errorf("message must be a JSON object: %w", err)
+		}
+		data, err = grpc.EncodeRaw(fields)
+	}
+	if err != nil {
+		return f, err
+	}
+	return grpc.Frame{Data: data}, nil
+}
+
+// grpcStreaming describes the streaming mode of a method.
+func grpcStreaming(m grpc.Method) string {
+	switch {
+	case m.ClientStreaming && m.ServerStreaming:
+		return "bidi"
+	case m.ClientStreaming:
+		return "client"
+	case m.ServerStreaming:
+		return "server"
+	}
+	return ""
+}
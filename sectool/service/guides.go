@@ -0,0 +1,155 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"maps"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/go-harden/llm-security-toolbox/sectool/config"
+)
+
+// guidesDirName is the directory, next to the config file and under a project's .sectool
+// directory, holding custom workflow guides as <task>.md.
+const guidesDirName = "guides"
+
+var guideTaskRe = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// guideVarRe matches {{name}} placeholders in guides.
+var guideVarRe = regexp.MustCompile(`\{\{\s*([a-z][a-z0-9_]*)\s*\}\}`)
+
+// guideVarUnset replaces placeholders without a value.
+const guideVarUnset = "(not configured)"
+
+// builtinGuides returns the embedded workflow guides keyed by task.
+func builtinGuides() map[string]string {
+	return map[string]string{
+		WorkflowModeExplore:    workflowExploreContent,
+		WorkflowModeTestReport: workflowTestReportContent,
+		WorkflowModeAPI:        workflowAPIContent,
+		WorkflowModeLLMApp:     workflowLLMAppContent,
+		WorkflowModeMobile:     workflowMobileContent,
+	}
+}
+
+// guideDirs returns the custom guide directories in increasing precedence: user-wide
+// (next to configPath), then the project's .sectool directory.
+func guideDirs(configPath, projectDir string) []string {
+	var dirs []string
+	if configPath != "" {
+		dirs = append(dirs, filepath.Join(filepath.Dir(configPath), guidesDirName))
+	}
+	if projectDir != "" {
+		dirs = append(dirs, filepath.Join(projectDir, config.ProjectDirName, guidesDirName))
+	}
+	return slices.Compact(dirs)
+}
+
+// loadGuides merges the built-in guides with <task>.md files found in dirs. Later
+// directories override earlier ones and the built-ins. Missing directories are skipped,
+// unreadable files and invalid names are logged and skipped.
+func loadGuides(dirs ...string) map[string]string {
+	guides := builtinGuides()
+	for _, dir := range dirs {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			if !os.IsNotExist(err) {
+				log.Printf("guides: failed to read %s: %v", dir, err)
+			}
+			continue
+		}
+		for _, entry := range entries {
+			name := entry.Name()
+			if entry.IsDir() || filepath.Ext(name) != ".md" {
+				continue
+			}
+			task := strings.TrimSuffix(name, ".md")
+			if !guideTaskRe.MatchString(task) || task == WorkflowModeNone || task == WorkflowModeCLI {
+				log.Printf("guides: skipping %s: invalid task name %q", filepath.Join(dir, name), task)
+				continue
+			}
+			content, err := os.ReadFile(filepath.Join(dir, name))
+			if err != nil {
+				log.Printf("guides: failed to read %s: %v", filepath.Join(dir, name), err)
+				continue
+			}
+			if _, exists := guides[task]; exists {
+				log.Printf("guides: %s overrides task %q", filepath.Join(dir, name), task)
+			}
+			guides[task] = string(content)
+		}
+	}
+	return guides
+}
+
+// guideTasks returns the tasks of guides sorted, built-ins first.
+func guideTasks(guides map[string]string) []string {
+	builtin := builtinGuides()
+	tasks := make([]string, 0, len(guides))
+	for task := range guides {
+		tasks = append(tasks, task)
+	}
+	slices.SortFunc(tasks, func(a, b string) int {
+		_, aBuiltin := builtin[a]
+		_, bBuiltin := builtin[b]
+		if aBuiltin != bBuiltin {
+			if aBuiltin {
+				return -1
+			}
+			return 1
+		}
+		return strings.Compare(a, b)
+	})
+	return tasks
+}
+
+// guideTitle returns the first markdown heading of content, or fallback.
+func guideTitle(content, fallback string) string {
+	for _, line := range strings.Split(content, "\n") {
+		if title, ok := strings.CutPrefix(strings.TrimSpace(line), "# "); ok && title != "" {
+			return strings.TrimSpace(title)
+		}
+	}
+	return fallback
+}
+
+// guideVars returns values for guide placeholders: target, targets, scope and exclude
+// from the project scope, mcp_url, then config guide_vars (which take precedence).
+func (s *Server) guideVars() map[string]string {
+	vars := map[string]string{
+		"mcp_url": fmt.Sprintf("http://127.0.0.1:%d/mcp", s.mcpPort),
+	}
+	if scope := s.projectScope(); scope != nil {
+		if len(scope.Targets) > 0 {
+			vars["target"] = scope.Targets[0]
+			vars["targets"] = strings.Join(scope.Targets, ", ")
+		}
+		vars["scope"] = strings.Join(scope.Include, ", ")
+		vars["exclude"] = strings.Join(scope.Exclude, ", ")
+	}
+	if s.cfg != nil {
+		maps.Copy(vars, s.cfg.GuideVars)
+	}
+	return vars
+}
+
+// renderGuide fills {{name}} placeholders in content from vars, returning the names
+// that had no value (replaced with guideVarUnset).
+func renderGuide(content string, vars map[string]string) (string, []string) {
+	var missing []string
+	rendered := guideVarRe.ReplaceAllStringFunc(content, func(match string) string {
+		name := guideVarRe.FindStringSubmatch(match)[1]
+		if value, ok := vars[name]; ok && value != "" {
+			return value
+		}
+		if !slices.Contains(missing, name) {
+			missing = append(missing, name)
+		}
+		return guideVarUnset
+	})
+	return rendered, missing
+}
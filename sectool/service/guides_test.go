@@ -0,0 +1,82 @@
+package service
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/go-harden/llm-security-toolbox/sectool/config"
+)
+
+func TestLoadGuides(t *testing.T) {
+	t.Parallel()
+
+	writeGuide := func(t *testing.T, dir, name, content string) {
+		t.Helper()
+		require.NoError(t, os.MkdirAll(dir, 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644))
+	}
+
+	t.Run("builtin_only", func(t *testing.T) {
+		guides := loadGuides(filepath.Join(t.TempDir(), "missing"))
+		assert.Equal(t, builtinGuides(), guides)
+	})
+
+	t.Run("custom_and_override", func(t *testing.T) {
+		user := filepath.Join(t.TempDir(), "user")
+		project := filepath.Join(t.TempDir(), "project")
+		writeGuide(t, user, "api-review.md", "# API Review\nuser")
+		writeGuide(t, user, "explore.md", "# Team Explore")
+		writeGuide(t, project, "api-review.md", "# API Review\nproject")
+		writeGuide(t, user, "notes.txt", "ignored")
+		writeGuide(t, user, "none.md", "reserved")
+		writeGuide(t, user, "Bad Name.md", "invalid")
+
+		guides := loadGuides(user, project)
+		assert.Equal(t, "# API Review\nproject", guides["api-review"])
+		assert.Equal(t, "# Team Explore", guides[WorkflowModeExplore])
+		assert.Equal(t, workflowTestReportContent, guides[WorkflowModeTestReport])
+		assert.Len(t, guides, 6)
+		assert.Equal(t, []string{WorkflowModeAPI, WorkflowModeExplore, WorkflowModeLLMApp, WorkflowModeMobile, WorkflowModeTestReport, "api-review"}, guideTasks(guides))
+	})
+}
+
+func TestGuideTitle(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "API Review", guideTitle("\n# API Review\n\n## Steps", "fallback"))
+	assert.Equal(t, "fallback", guideTitle("## Steps only", "fallback"))
+}
+
+func TestRenderGuide(t *testing.T) {
+	t.Parallel()
+
+	rendered, missing := renderGuide(
+		"Target: {{target}}\nScope: {{ scope }}\nReport: {{report_path}} / {{report_path}}\nRef: {{0.flows}}",
+		map[string]string{"target": "https://app.example.com", "scope": "app.example.com"},
+	)
+	assert.Equal(t, "Target: https://app.example.com\nScope: app.example.com\nReport: (not configured) / (not configured)\nRef: {{0.flows}}", rendered)
+	assert.Equal(t, []string{"report_path"}, missing)
+}
+
+func TestServerGuideVars(t *testing.T) {
+	t.Parallel()
+
+	s := &Server{
+		mcpPort: 9119,
+		scope: &config.Scope{
+			Targets: []string{"https://app.example.com", "https://api.example.com"},
+			Include: []string{"app.example.com", "api.example.com"},
+		},
+		cfg: &config.Config{GuideVars: map[string]string{"target": "https://override.example.com", "report_path": "r.md"}},
+	}
+	vars := s.guideVars()
+	assert.Equal(t, "https://override.example.com", vars["target"])
+	assert.Equal(t, "https://app.example.com, https://api.example.com", vars["targets"])
+	assert.Equal(t, "app.example.com, api.example.com", vars["scope"])
+	assert.Equal(t, "r.md", vars["report_path"])
+	assert.Equal(t, "http://127.0.0.1:9119/mcp", vars["mcp_url"])
+}
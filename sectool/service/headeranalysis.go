@@ -0,0 +1,197 @@
+package service
+
+import (
+	"cmp"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/go-harden/llm-security-toolbox/sectool/protocol"
+	"github.com/go-harden/llm-security-toolbox/sectool/report"
+)
+
+// headerIssueMaxFlows caps the example flow IDs kept per host issue.
+const headerIssueMaxFlows = 3
+
+// Header analysis checks. HTML-only checks apply to 2xx text/html responses.
+const (
+	checkMissingHSTS         = "missing_hsts"
+	checkMissingCSP          = "missing_csp"              // HTML only
+	checkWeakCSP             = "weak_csp"                 // HTML only
+	checkMissingFrameOptions = "missing_frame_protection" // HTML only
+	checkMissingNosniff      = "missing_nosniff"
+	checkCookieNoSecure      = "cookie_no_secure"
+	checkCookieNoHttpOnly    = "cookie_no_httponly"
+	checkCookieNoSameSite    = "cookie_no_samesite"
+	checkCORSReflected       = "cors_reflected_origin"
+	checkCORSNull            = "cors_null_origin"
+	checkCORSWildcardCreds   = "cors_wildcard_credentials"
+)
+
+var headerCheckSeverity = map[string]string{
+	checkMissingHSTS:         protocol.SeverityLow,
+	checkMissingCSP:          protocol.SeverityLow,
+	checkWeakCSP:             protocol.SeverityLow,
+	checkMissingFrameOptions: protocol.SeverityLow,
+	checkMissingNosniff:      protocol.SeverityInfo,
+	checkCookieNoSecure:      protocol.SeverityMedium,
+	checkCookieNoHttpOnly:    protocol.SeverityLow,
+	checkCookieNoSameSite:    protocol.SeverityInfo,
+	checkCORSReflected:       protocol.SeverityMedium,
+	checkCORSNull:            protocol.SeverityMedium,
+	checkCORSWildcardCreds:   protocol.SeverityLow,
+}
+
+// headerIssue is one problem found in a response; Detail distinguishes issues of
+// the same check, such as the cookie name.
+type headerIssue struct {
+	Check  string
+	Detail string
+}
+
+// analyzeResponseHeaders checks one exchange's response headers.
+func analyzeResponseHeaders(request, response []byte, usesHTTPS bool) []headerIssue {
+	respHeaders, _ := splitHeadersBody(response)
+	status, _ := parseResponseStatus(respHeaders)
+	if status == 0 {
+		return nil
+	}
+	h := http.Header(parseHeadersToMap(string(respHeaders)))
+	reqHeaders, _ := splitHeadersBody(request)
+	origin := http.Header(parseHeadersToMap(string(reqHeaders))).Get("Origin")
+
+	var issues []headerIssue
+	if usesHTTPS && h.Get("Strict-Transport-Security") == "" {
+		issues = append(issues, headerIssue{Check: checkMissingHSTS})
+	}
+	if status >= 200 && status < 300 {
+		if !strings.EqualFold(h.Get("X-Content-Type-Options"), "nosniff") {
+			issues = append(issues, headerIssue{Check: checkMissingNosniff})
+		}
+		if strings.Contains(strings.ToLower(h.Get("Content-Type")), "text/html") {
+			csp := strings.ToLower(strings.Join(h.Values("Content-Security-Policy"), "; "))
+			if csp == "" {
+				issues = append(issues, headerIssue{Check: checkMissingCSP})
+			} else if weakness := weakCSP(csp); weakness != "" {
+				issues = append(issues, headerIssue{Check: checkWeakCSP, Detail: weakness})
+			}
+			if h.Get("X-Frame-Options") == "" && !strings.Contains(csp, "frame-ancestors") {
+				issues = append(issues, headerIssue{Check: checkMissingFrameOptions})
+			}
+		}
+	}
+
+	resp := http.Response{Header: h}
+	for _, c := range resp.Cookies() {
+		if c.MaxAge < 0 {
+			continue // deletion
+		}
+		if usesHTTPS && !c.Secure {
+			issues = append(issues, headerIssue{Check: checkCookieNoSecure, Detail: c.Name})
+		}
+		if !c.HttpOnly {
+			issues = append(issues, headerIssue{Check: checkCookieNoHttpOnly, Detail: c.Name})
+		}
+		if c.SameSite == 0 || c.SameSite == http.SameSiteDefaultMode || (c.SameSite == http.SameSiteNoneMode && !c.Secure) {
+			issues = append(issues, headerIssue{Check: checkCookieNoSameSite, Detail: c.Name})
+		}
+	}
+
+	acao := h.Get("Access-Control-Allow-Origin")
+	creds := strings.EqualFold(h.Get("Access-Control-Allow-Credentials"), "true")
+	switch {
+	case acao == "":
+	case acao == "null":
+		issues = append(issues, headerIssue{Check: checkCORSNull, Detail: credentialsDetail(creds)})
+	case acao == "*":
+		if creds {
+			issues = append(issues, headerIssue{Check: checkCORSWildcardCreds})
+		}
+	case origin != "" && acao == origin && creds:
+		issues = append(issues, headerIssue{Check: checkCORSReflected, Detail: origin})
+	}
+	return issues
+}
+
+// weakCSP names the first unsafe source a policy allows for scripts, or "".
+func weakCSP(csp string) string {
+	directive := ""
+	for _, d := range strings.Split(csp, ";") {
+		name, value, _ := strings.Cut(strings.TrimSpace(d), " ")
+		if name == "script-src" || (name == "default-src" && directive == "") {
+			directive = value
+		}
+	}
+	for _, src := range []string{"'unsafe-inline'", "'unsafe-eval'", "data:", "*"} {
+		for _, token := range strings.Fields(directive) {
+			if token == src && !(src == "'unsafe-inline'" && strings.Contains(directive, "'nonce-")) {
+				return "script sources allow " + src
+			}
+		}
+	}
+	return ""
+}
+
+func credentialsDetail(creds bool) string {
+	if creds {
+		return "with credentials"
+	}
+	return ""
+}
+
+// analyzeHeaders summarizes header issues per host over the flows, hosts sorted by
+// name and issues most severe first. register assigns example flow IDs.
+func analyzeHeaders(entries []flowEntry, register func(flowEntry) string) []protocol.HeaderHostSummary {
+	type hostIssues struct {
+		summary protocol.HeaderHostSummary
+		byKey   map[headerIssue]*protocol.HeaderIssue
+	}
+	hosts := make(map[string]*hostIssues)
+	for _, entry := range entries {
+		if entry.status == 0 {
+			continue
+		}
+		_, _, usesHTTPS := parseTarget([]byte(entry.request), "")
+		issues := analyzeResponseHeaders([]byte(entry.request), []byte(entry.response), usesHTTPS)
+		h, ok := hosts[entry.host]
+		if !ok {
+			h = &hostIssues{summary: protocol.HeaderHostSummary{Host: entry.host}, byKey: make(map[headerIssue]*protocol.HeaderIssue)}
+			hosts[entry.host] = h
+		}
+		h.summary.Responses++
+		var flowID string
+		for _, issue := range issues {
+			hi, ok := h.byKey[issue]
+			if !ok {
+				hi = &protocol.HeaderIssue{Check: issue.Check, Severity: headerCheckSeverity[issue.Check], Detail: issue.Detail}
+				h.byKey[issue] = hi
+			}
+			hi.Count++
+			if len(hi.FlowIDs) < headerIssueMaxFlows {
+				if flowID == "" {
+					flowID = register(entry)
+				}
+				if !slices.Contains(hi.FlowIDs, flowID) {
+					hi.FlowIDs = append(hi.FlowIDs, flowID)
+				}
+			}
+		}
+	}
+
+	summaries := make([]protocol.HeaderHostSummary, 0, len(hosts))
+	for _, h := range hosts {
+		for _, hi := range h.byKey {
+			h.summary.Issues = append(h.summary.Issues, *hi)
+		}
+		slices.SortFunc(h.summary.Issues, func(a, b protocol.HeaderIssue) int {
+			return cmp.Or(
+				cmp.Compare(report.SeverityRank(a.Severity), report.SeverityRank(b.Severity)),
+				strings.Compare(a.Check, b.Check),
+				strings.Compare(a.Detail, b.Detail),
+			)
+		})
+		summaries = append(summaries, h.summary)
+	}
+	slices.SortFunc(summaries, func(a, b protocol.HeaderHostSummary) int { return strings.Compare(a.Host, b.Host) })
+	return summaries
+}
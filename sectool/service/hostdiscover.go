@@ -0,0 +1,323 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"maps"
+	"net"
+	"net/http"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-harden/llm-security-toolbox/sectool/config"
+	"github.com/go-harden/llm-security-toolbox/sectool/service/ids"
+)
+
+const (
+	defaultVhostMaxRequests = 500
+	defaultHostThreads      = 10
+	maxHostThreads          = 50
+	ctLookupTimeout         = 30 * time.Second
+	maxCTResponseBytes      = 20 << 20
+)
+
+// crtshURL is the certificate transparency search endpoint, replaced in tests.
+var crtshURL = "https://crt.sh/"
+
+var htmlTitleRe = regexp.MustCompile(`(?is)<title[^>]*>(.*?)</title>`)
+
+// htmlTitle returns the trimmed <title> of an HTML body, or "".
+func htmlTitle(body []byte) string {
+	m := htmlTitleRe.FindSubmatch(body)
+	if m == nil {
+		return ""
+	}
+	return strings.Join(strings.Fields(html.UnescapeString(string(m[1]))), " ")
+}
+
+// hostWords joins wordlist labels to domain and appends explicit hosts, lowercased and
+// without duplicates. Labels may already end in domain.
+func hostWords(words, hosts []string, domain string) []string {
+	domain = strings.Trim(strings.ToLower(domain), ".")
+	var result []string
+	add := func(host string) {
+		if host = strings.Trim(strings.ToLower(strings.TrimSpace(host)), "."); host != "" && !slices.Contains(result, host) {
+			result = append(result, host)
+		}
+	}
+	for _, word := range words {
+		word = strings.Trim(strings.ToLower(strings.TrimSpace(word)), ".")
+		if word == "" || strings.HasPrefix(word, "#") {
+			continue
+		} else if domain != "" && word != domain && !strings.HasSuffix(word, "."+domain) {
+			word += "." + domain
+		}
+		add(word)
+	}
+	for _, host := range hosts {
+		add(host)
+	}
+	return result
+}
+
+// vhostProbe is one request of a vhost_discover run and its response.
+type vhostProbe struct {
+	host     string // Host header value
+	request  []byte
+	result   *SendRequestResult
+	status   int
+	location string
+	replayID string // set when stored as a hit
+}
+
+// vhostDiscoverer sends one request per candidate Host header to the same address,
+// first with a random host to fingerprint the default response; hosts answering
+// differently are virtual hosts the server routes.
+type vhostDiscoverer struct {
+	send         func(ctx context.Context, host string) (*vhostProbe, error)
+	onHit        func(*vhostProbe) // called as hits are found, from worker goroutines
+	filterStatus []int
+	threads      int
+	maxRequests  int
+
+	baseline *vhostProbe
+
+	mu       sync.Mutex
+	requests int
+	errors   int
+	lastErr  error
+	stopped  bool
+	hits     []*vhostProbe
+}
+
+// run fingerprints a random host under domain, or .invalid when domain is empty, then
+// sends hosts.
+func (v *vhostDiscoverer) run(ctx context.Context, domain string, hosts []string) error {
+	if domain == "" {
+		domain = "invalid"
+	}
+	random := strings.ToLower(ids.Generate(notFoundNameLength)) + "." + strings.Trim(domain, ".")
+	v.requests++
+	baseline, err := v.send(ctx, random)
+	if err != nil {
+		return fmt.Errorf("baseline request with Host %s failed: %w", random, err)
+	}
+	v.baseline = baseline
+	notFound := newNotFoundPrint(baseline.status, baseline.result, baseline.location, random)
+
+	jobs := make(chan string)
+	var wg sync.WaitGroup
+	for range v.threads {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for host := range jobs {
+				v.try(ctx, host, notFound)
+			}
+		}()
+	}
+	for _, host := range hosts {
+		if ctx.Err() != nil || !v.reserve() {
+			break
+		}
+		jobs <- host
+	}
+	close(jobs)
+	wg.Wait()
+	return ctx.Err()
+}
+
+// reserve takes one request from the budget, or marks the run stopped.
+func (v *vhostDiscoverer) reserve() bool {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if v.requests >= v.maxRequests {
+		v.stopped = true
+		return false
+	}
+	v.requests++
+	return true
+}
+
+func (v *vhostDiscoverer) try(ctx context.Context, host string, notFound notFoundPrint) {
+	probe, err := v.send(ctx, host)
+	if err != nil {
+		if !errors.Is(err, context.Canceled) {
+			v.mu.Lock()
+			v.errors++
+			v.lastErr = err
+			v.mu.Unlock()
+		}
+		return
+	}
+	if slices.Contains(v.filterStatus, probe.status) || notFound.matches(probe.status, probe.result, probe.location, host) {
+		return
+	}
+	if v.onHit != nil {
+		v.onHit(probe)
+	}
+	v.mu.Lock()
+	v.hits = append(v.hits, probe)
+	v.mu.Unlock()
+}
+
+// subdomain is a name found by subdomainEnumerator.
+type subdomain struct {
+	host      string
+	addresses []string
+	sources   []string
+}
+
+// subdomainEnumerator resolves wordlist names under a domain and merges them with
+// names from certificate transparency logs. A random name is resolved first; when
+// it answers (wildcard DNS), names resolving only to the wildcard addresses are
+// dropped.
+type subdomainEnumerator struct {
+	lookup   func(ctx context.Context, host string) ([]string, error)
+	ctLookup func(ctx context.Context, domain string) ([]string, error) // nil to skip
+	threads  int
+
+	wildcard []string
+	ctErr    error
+	errors   int
+}
+
+func (e *subdomainEnumerator) run(ctx context.Context, domain string, hosts []string) ([]subdomain, error) {
+	random := strings.ToLower(ids.Generate(notFoundNameLength)) + "." + domain
+	if addrs, err := e.lookup(ctx, random); err == nil {
+		e.wildcard = slices.Sorted(slices.Values(addrs))
+	}
+
+	found := make(map[string]*subdomain)
+	var mu sync.Mutex
+	add := func(host string, addrs []string, source string) {
+		mu.Lock()
+		defer mu.Unlock()
+		s := found[host]
+		if s == nil {
+			s = &subdomain{host: host}
+			found[host] = s
+		}
+		for _, addr := range addrs {
+			if !slices.Contains(s.addresses, addr) {
+				s.addresses = append(s.addresses, addr)
+			}
+		}
+		if !slices.Contains(s.sources, source) {
+			s.sources = append(s.sources, source)
+		}
+	}
+
+	var ctHosts []string
+	if e.ctLookup != nil {
+		ctHosts, e.ctErr = e.ctLookup(ctx, domain)
+	}
+
+	resolve := func(host, source string) {
+		addrs, err := e.lookup(ctx, host)
+		if err != nil {
+			var dnsErr *net.DNSError
+			if !errors.As(err, &dnsErr) || !dnsErr.IsNotFound {
+				mu.Lock()
+				e.errors++
+				mu.Unlock()
+			}
+			if source == "ct" {
+				add(host, nil, source) // still worth listing; may resolve internally
+			}
+			return
+		}
+		slices.Sort(addrs)
+		if source == "dns" && len(e.wildcard) > 0 && !slices.ContainsFunc(addrs, func(a string) bool {
+			return !slices.Contains(e.wildcard, a)
+		}) {
+			return
+		}
+		add(host, addrs, source)
+	}
+
+	type job struct{ host, source string }
+	jobs := make(chan job)
+	var wg sync.WaitGroup
+	for range e.threads {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range jobs {
+				resolve(j.host, j.source)
+			}
+		}()
+	}
+	for _, host := range hosts {
+		if ctx.Err() != nil {
+			break
+		}
+		jobs <- job{host: host, source: "dns"}
+	}
+	for _, host := range ctHosts {
+		if ctx.Err() != nil {
+			break
+		}
+		jobs <- job{host: host, source: "ct"}
+	}
+	close(jobs)
+	wg.Wait()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	result := make([]subdomain, 0, len(found))
+	for _, s := range found {
+		slices.Sort(s.addresses)
+		slices.Sort(s.sources)
+		result = append(result, *s)
+	}
+	slices.SortFunc(result, func(a, b subdomain) int { return strings.Compare(a.host, b.host) })
+	return result, nil
+}
+
+// crtshLookup returns the names under domain in certificate transparency logs, as
+// indexed by crt.sh. Wildcard entries are returned without the "*." label.
+func crtshLookup(ctx context.Context, domain string) ([]string, error) {
+	ctx, cancel := context.WithTimeout(ctx, ctLookupTimeout)
+	defer cancel()
+
+	u := crtshURL + "?" + url.Values{"q": {"%." + domain}, "output": {"json"}}.Encode()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", config.UserAgent())
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("crt.sh lookup: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("crt.sh lookup: status %d", resp.StatusCode)
+	}
+
+	var entries []struct {
+		NameValue string `json:"name_value"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxCTResponseBytes)).Decode(&entries); err != nil {
+		return nil, fmt.Errorf("crt.sh lookup: %w", err)
+	}
+	seen := make(map[string]bool)
+	for _, entry := range entries {
+		for _, name := range strings.Fields(entry.NameValue) {
+			if name = strings.TrimPrefix(strings.ToLower(name), "*."); name == domain || strings.HasSuffix(name, "."+domain) {
+				seen[name] = true
+			}
+		}
+	}
+	return slices.Sorted(maps.Keys(seen)), nil
+}
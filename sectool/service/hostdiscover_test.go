@@ -0,0 +1,128 @@
+package service
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHostWords(t *testing.T) {
+	t.Parallel()
+
+	hosts := hostWords([]string{"www", "API", "# comment", "", "dev.example.com", "www"}, []string{"other.test", "api.example.com"}, "Example.com.")
+	assert.Equal(t, []string{"www.example.com", "api.example.com", "dev.example.com", "other.test"}, hosts)
+	assert.Equal(t, []string{"other.test"}, hostWords(nil, []string{"other.test"}, ""))
+}
+
+func TestHTMLTitle(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "Admin & Console", htmlTitle([]byte("<html><head><TITLE lang=en>\n Admin &amp;\n Console </TITLE></head>")))
+	assert.Empty(t, htmlTitle([]byte(`{"title": "json"}`)))
+}
+
+func TestVhostDiscoverer(t *testing.T) {
+	t.Parallel()
+
+	send := func(ctx context.Context, host string) (*vhostProbe, error) {
+		probe := &vhostProbe{host: host, status: 404}
+		body := "<html><body>No site configured for " + host + "</body></html>"
+		switch host {
+		case "admin.example.com":
+			probe.status, body = 200, "<html><head><title>Admin</title></head></html>"
+		case "old.example.com":
+			probe.status, probe.location = 302, "https://www.example.com/"
+		}
+		probe.result = &SendRequestResult{Headers: []byte("HTTP/1.1 " + http.StatusText(probe.status) + "\r\n\r\n"), Body: []byte(body)}
+		return probe, nil
+	}
+	hosts := hostWords([]string{"www", "admin", "old", "dev"}, nil, "example.com")
+
+	t.Run("hits", func(t *testing.T) {
+		v := &vhostDiscoverer{send: send, threads: 2, maxRequests: 100}
+		require.NoError(t, v.run(t.Context(), "example.com", hosts))
+		var found []string
+		for _, probe := range v.hits {
+			found = append(found, probe.host)
+		}
+		assert.ElementsMatch(t, []string{"admin.example.com", "old.example.com"}, found)
+		assert.Equal(t, 5, v.requests)
+		assert.Equal(t, 404, v.baseline.status)
+		assert.True(t, strings.HasSuffix(v.baseline.host, ".example.com"))
+	})
+
+	t.Run("filter_and_budget", func(t *testing.T) {
+		v := &vhostDiscoverer{send: send, threads: 1, maxRequests: 3, filterStatus: []int{302}}
+		require.NoError(t, v.run(t.Context(), "example.com", hosts))
+		require.Len(t, v.hits, 1)
+		assert.Equal(t, "admin.example.com", v.hits[0].host)
+		assert.True(t, v.stopped)
+		assert.Equal(t, 3, v.requests)
+	})
+}
+
+func TestSubdomainEnumerator(t *testing.T) {
+	t.Parallel()
+
+	records := map[string][]string{
+		"www.example.com":      {"203.0.113.10"},
+		"api.example.com":      {"203.0.113.20", "203.0.113.21"},
+		"internal.example.com": {"10.0.0.5"},
+	}
+	lookup := func(wildcard []string) func(ctx context.Context, host string) ([]string, error) {
+		return func(ctx context.Context, host string) ([]string, error) {
+			if addrs, ok := records[host]; ok {
+				return addrs, nil
+			} else if wildcard != nil {
+				return wildcard, nil
+			}
+			return nil, &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+		}
+	}
+	ct := func(ctx context.Context, domain string) ([]string, error) {
+		return []string{"api.example.com", "vpn.example.com"}, nil
+	}
+	hosts := hostWords([]string{"www", "api", "dev", "mail"}, nil, "example.com")
+
+	t.Run("dns_and_ct", func(t *testing.T) {
+		e := &subdomainEnumerator{lookup: lookup(nil), ctLookup: ct, threads: 3}
+		found, err := e.run(t.Context(), "example.com", hosts)
+		require.NoError(t, err)
+		require.Len(t, found, 3)
+		assert.Equal(t, subdomain{host: "api.example.com", addresses: []string{"203.0.113.20", "203.0.113.21"}, sources: []string{"ct", "dns"}}, found[0])
+		assert.Equal(t, subdomain{host: "vpn.example.com", sources: []string{"ct"}}, found[1])
+		assert.Equal(t, "www.example.com", found[2].host)
+		assert.Empty(t, e.wildcard)
+		assert.Zero(t, e.errors)
+	})
+
+	t.Run("wildcard", func(t *testing.T) {
+		e := &subdomainEnumerator{lookup: lookup([]string{"203.0.113.10"}), threads: 2}
+		found, err := e.run(t.Context(), "example.com", hosts)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"203.0.113.10"}, e.wildcard)
+		require.Len(t, found, 1) // www resolves only to the wildcard address
+		assert.Equal(t, "api.example.com", found[0].host)
+	})
+}
+
+func TestCrtshLookup(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "%.example.com", r.URL.Query().Get("q"))
+		_, _ = w.Write([]byte(`[{"name_value":"example.com\n*.example.com"},{"name_value":"API.example.com\nwww.example.com"},{"name_value":"evil-example.com"}]`))
+	}))
+	t.Cleanup(srv.Close)
+	orig := crtshURL
+	crtshURL = srv.URL + "/"
+	t.Cleanup(func() { crtshURL = orig })
+
+	names, err := crtshLookup(t.Context(), "example.com")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"api.example.com", "example.com", "www.example.com"}, names)
+}
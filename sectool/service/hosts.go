@@ -0,0 +1,65 @@
+package service
+
+import (
+	"context"
+	"net"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// hostOverrides maps lowercase hostnames to the address sectool connects to in their
+// place (the config's hosts). Host headers, TLS server names and scope checks keep
+// the original hostname. Sends through Burp resolve hosts in Burp.
+type hostOverrides map[string]string
+
+func newHostOverrides(hosts map[string]string) hostOverrides {
+	if len(hosts) == 0 {
+		return nil
+	}
+	h := make(hostOverrides, len(hosts))
+	for name, addr := range hosts {
+		h[strings.ToLower(name)] = addr
+	}
+	return h
+}
+
+type hostOverridesKey struct{}
+
+// withHosts makes the connections opened under ctx use hosts.
+func withHosts(ctx context.Context, hosts hostOverrides) context.Context {
+	if len(hosts) == 0 {
+		return ctx
+	}
+	return context.WithValue(ctx, hostOverridesKey{}, hosts)
+}
+
+// dialAddr returns addr ("host:port") with its host replaced by the override on ctx.
+func dialAddr(ctx context.Context, addr string) string {
+	hosts, _ := ctx.Value(hostOverridesKey{}).(hostOverrides)
+	if len(hosts) == 0 {
+		return addr
+	}
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return addr
+	} else if to, ok := hosts[strings.ToLower(host)]; ok {
+		return net.JoinHostPort(to, port)
+	}
+	return addr
+}
+
+// dialContext dials addr, or its host override on ctx. Every direct connection made
+// for a tool goes through it.
+func dialContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	var d net.Dialer
+	return d.DialContext(ctx, network, dialAddr(ctx, addr))
+}
+
+// withHostOverrides runs a tool handler with the config's host overrides on its context.
+func (m *mcpServer) withHostOverrides(handler server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return handler(withHosts(ctx, m.service.hosts), req)
+	}
+}
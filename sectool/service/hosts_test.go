@@ -0,0 +1,61 @@
+package service
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/go-harden/llm-security-toolbox/sectool/config"
+	"github.com/go-harden/llm-security-toolbox/sectool/protocol"
+)
+
+func TestDialAddr(t *testing.T) {
+	t.Parallel()
+
+	ctx := withHosts(t.Context(), newHostOverrides(map[string]string{"App.Example.com": "10.0.0.7", "v6.example.com": "fd00::7"}))
+	assert.Equal(t, "10.0.0.7:443", dialAddr(ctx, "app.example.com:443"))
+	assert.Equal(t, "[fd00::7]:80", dialAddr(ctx, "V6.example.com:80"))
+	assert.Equal(t, "other.example.com:443", dialAddr(ctx, "other.example.com:443"))
+	assert.Equal(t, "app.example.com:443", dialAddr(t.Context(), "app.example.com:443"))
+	assert.Equal(t, "not-an-addr", dialAddr(ctx, "not-an-addr"))
+}
+
+func TestMCP_HostOverrides(t *testing.T) {
+	t.Parallel()
+
+	srv, mcpClient, _, _, _ := setupMCPServerWithMock(t)
+	host, port := newRawTestServer(t, func(conn net.Conn) {
+		line, _ := bufio.NewReader(conn).ReadString('\n')
+		_, _ = conn.Write([]byte("+PONG " + line))
+		_, _ = io.Copy(io.Discard, conn)
+	})
+	srv.hosts = hostOverrides{"app.sectool.invalid": host}
+
+	resp := CallMCPToolJSONOK[protocol.RawSendResponse](t, mcpClient, "raw_send", map[string]interface{}{
+		"host": "app.sectool.invalid",
+		"port": port,
+		"text": "PING\r\n",
+		"wait": "200ms",
+	})
+	assert.Equal(t, "+PONG PING\r\n", resp.Text)
+}
+
+func TestLoadScopeFromConfig(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	require.NoError(t, (&config.Scope{Include: []string{"file.example.com"}}).Save(config.ProjectScopePath(dir)))
+
+	s := &Server{cfg: config.DefaultConfig(), flagProjectDir: dir}
+	require.NoError(t, s.loadScope())
+	assert.Equal(t, []string{"file.example.com"}, s.projectScope().Include)
+
+	// A config scope (usually from --profile) replaces the project's scope file
+	s.cfg.Scope = &config.Scope{Include: []string{"*.staging.example.com"}}
+	require.NoError(t, s.loadScope())
+	assert.Equal(t, []string{"*.staging.example.com"}, s.projectScope().Include)
+}
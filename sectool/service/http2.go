@@ -0,0 +1,119 @@
+package service
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"log"
+	"maps"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// http2HopHeaders are connection-specific headers HTTP/2 forbids; they are dropped
+// from requests converted from HTTP/1.1 form.
+var http2HopHeaders = []string{"Connection", "Keep-Alive", "Proxy-Connection", "Transfer-Encoding", "Upgrade"}
+
+// sendHTTP2Request sends a request over HTTP/2 directly rather than through the HTTP
+// backend, whose send path is HTTP/1.1. The scope, allowlist and rate limits apply as for sendRequest.
+func (s *Server) sendHTTP2Request(ctx context.Context, name string, req SendRequestInput) (*SendRequestResult, error) {
+	if err := s.guard.CheckRequest(ctx, s.projectScope(), req); err != nil {
+		return nil, err
+	}
+	release, err := s.rateLimiter.Wait(ctx, req.Target.Hostname)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	log.Printf("http2: sending request %s to %s", name, req.Target.origin())
+	if req.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, req.Timeout)
+		defer cancel()
+	}
+	return sendHTTP2(ctx, req, time.Now())
+}
+
+// sendHTTP2 sends req, given in HTTP/1.1 or HTTP/2 request-line form, negotiating h2
+// by ALPN for HTTPS targets and using prior-knowledge h2c otherwise. The response
+// starts with an "HTTP/2" status line; trailers follow the headers in the header block.
+func sendHTTP2(ctx context.Context, req SendRequestInput, start time.Time) (*SendRequestResult, error) {
+	httpReq, err := http.ReadRequest(bufio.NewReader(bytes.NewReader(transformRequestForValidation(req.RawRequest))))
+	if err != nil {
+		return nil, fmt.Errorf("parse request: %w", err)
+	}
+	body, err := io.ReadAll(httpReq.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read body: %w", err)
+	}
+	for _, name := range http2HopHeaders {
+		httpReq.Header.Del(name)
+	}
+	httpReq.TransferEncoding = nil
+	httpReq.Close = false
+
+	scheme := schemeHTTP
+	if req.Target.UsesHTTPS {
+		scheme = schemeHTTPS
+	}
+	httpReq.URL.Scheme = scheme
+	httpReq.URL.Host = fmt.Sprintf("%s:%d", req.Target.Hostname, req.Target.Port)
+	httpReq.RequestURI = ""
+	httpReq.Body = io.NopCloser(bytes.NewReader(body))
+	httpReq.ContentLength = int64(len(body))
+
+	var protocols http.Protocols
+	protocols.SetHTTP2(true)
+	protocols.SetUnencryptedHTTP2(true)
+	transport := &http.Transport{
+		TLSClientConfig:    &tls.Config{InsecureSkipVerify: true},
+		DialContext:        dialContext,
+		Protocols:          &protocols,
+		DisableKeepAlives:  true,
+		DisableCompression: true,
+		Proxy:              nil,
+	}
+	defer transport.CloseIdleConnections()
+
+	resp, err := transport.RoundTrip(httpReq.WithContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("send HTTP/2 request: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read response body: %w", err)
+	}
+
+	return &SendRequestResult{
+		Headers:  dumpHTTP2Head(resp),
+		Body:     respBody,
+		Duration: time.Since(start),
+	}, nil
+}
+
+// dumpHTTP2Head renders the status line, headers and trailers (once the body is read)
+// as an HTTP/1.1-style header block with a blank line.
+func dumpHTTP2Head(resp *http.Response) []byte {
+	var buf bytes.Buffer
+	buf.WriteString("HTTP/2 " + strconv.Itoa(resp.StatusCode))
+	if text := http.StatusText(resp.StatusCode); text != "" {
+		buf.WriteString(" " + text)
+	}
+	buf.WriteString("\r\n")
+	for _, h := range []http.Header{resp.Header, resp.Trailer} {
+		for _, name := range slices.Sorted(maps.Keys(h)) {
+			for _, v := range h[name] {
+				buf.WriteString(strings.ToLower(name) + ": " + v + "\r\n")
+			}
+		}
+	}
+	buf.WriteString("\r\n")
+	return buf.Bytes()
+}
@@ -0,0 +1,412 @@
+package service
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"net"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/hpack"
+)
+
+// h2Field is a header field of an HTTP/2 header block, pseudo-headers included.
+type h2Field struct {
+	Name, Value string
+}
+
+func (f h2Field) String() string {
+	return f.Name + ": " + f.Value
+}
+
+// parseH2Field parses "name: value", where name may be a pseudo-header (":path: /").
+func parseH2Field(line string) (h2Field, bool) {
+	start := 0
+	if strings.HasPrefix(line, ":") {
+		start = 1
+	}
+	i := strings.IndexByte(line[start:], ':')
+	if i < 0 {
+		return h2Field{}, false
+	}
+	i += start
+	name := strings.TrimSpace(line[:i])
+	if name == "" || name == ":" {
+		return h2Field{}, false
+	}
+	return h2Field{Name: name, Value: strings.TrimSpace(line[i+1:])}, true
+}
+
+// http2Settings maps http2_send settings names to their identifiers.
+var http2Settings = map[string]http2.SettingID{
+	"header_table_size":       http2.SettingHeaderTableSize,
+	"enable_push":             http2.SettingEnablePush,
+	"max_concurrent_streams":  http2.SettingMaxConcurrentStreams,
+	"initial_window_size":     http2.SettingInitialWindowSize,
+	"max_frame_size":          http2.SettingMaxFrameSize,
+	"max_header_list_size":    http2.SettingMaxHeaderListSize,
+	"enable_connect_protocol": http2.SettingEnableConnectProtocol,
+}
+
+// http2RequestFields converts a raw request, in HTTP/1.1 or HTTP/2 request-line form,
+// to pseudo-headers followed by its lowercased headers. Host becomes :authority and
+// connection-specific headers are dropped.
+func http2RequestFields(raw []byte, target Target) ([]h2Field, []byte) {
+	headers, body := splitHeadersBody(raw)
+	lines := strings.Split(strings.TrimRight(string(headers), "\r\n"), "\r\n")
+	method, path := "GET", "/"
+	if parts := strings.SplitN(lines[0], " ", 3); len(parts) >= 2 {
+		method, path = parts[0], parts[1]
+	}
+	scheme := schemeHTTP
+	if target.UsesHTTPS {
+		scheme = schemeHTTPS
+	}
+	var authority string
+	// Absolute-form targets carry the authority
+	if u, err := url.Parse(path); err == nil && u.IsAbs() {
+		authority, path = u.Host, u.RequestURI()
+	}
+
+	var regular []h2Field
+	for _, line := range lines[1:] {
+		name, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		name, value = strings.ToLower(strings.TrimSpace(name)), strings.TrimSpace(value)
+		switch {
+		case name == "host":
+			if authority == "" {
+				authority = value
+			}
+		case name == "te" && !strings.EqualFold(value, "trailers"):
+		case slices.ContainsFunc(http2HopHeaders, func(h string) bool { return strings.EqualFold(h, name) }):
+		default:
+			regular = append(regular, h2Field{Name: name, Value: value})
+		}
+	}
+	if authority == "" {
+		authority = strings.TrimPrefix(strings.TrimPrefix(target.origin(), "https://"), "http://")
+	}
+	fields := []h2Field{
+		{Name: ":method", Value: method},
+		{Name: ":scheme", Value: scheme},
+		{Name: ":authority", Value: authority},
+		{Name: ":path", Value: path},
+	}
+	return append(fields, regular...), body
+}
+
+// overridePseudoHeaders replaces pseudo-header values in fields, removing those set
+// to "" and adding new ones after the existing pseudo-headers.
+func overridePseudoHeaders(fields []h2Field, pseudo map[string]string) []h2Field {
+	out := make([]h2Field, 0, len(fields)+len(pseudo))
+	seen := make(map[string]bool)
+	insertAt := 0
+	for _, f := range fields {
+		if value, ok := pseudo[f.Name]; ok {
+			seen[f.Name] = true
+			if value == "" {
+				continue
+			}
+			f.Value = value
+		}
+		out = append(out, f)
+		if strings.HasPrefix(f.Name, ":") {
+			insertAt = len(out)
+		}
+	}
+	var added []h2Field
+	for _, name := range slices.Sorted(maps.Keys(pseudo)) {
+		if !seen[name] && pseudo[name] != "" {
+			added = append(added, h2Field{Name: name, Value: pseudo[name]})
+		}
+	}
+	return slices.Insert(out, insertAt, added...)
+}
+
+// http2RawRequest renders fields and body as a request in HTTP/2 request-line form,
+// as stored for replays.
+func http2RawRequest(fields []h2Field, body []byte) []byte {
+	var method, path, authority string
+	var buf bytes.Buffer
+	for _, f := range fields {
+		switch f.Name {
+		case ":method":
+			method = f.Value
+		case ":path":
+			path = f.Value
+		case ":authority":
+			authority = f.Value
+		}
+	}
+	buf.WriteString(method + " " + path + " HTTP/2\r\n")
+	if authority != "" {
+		buf.WriteString("Host: " + authority + "\r\n")
+	}
+	for _, f := range fields {
+		if !strings.HasPrefix(f.Name, ":") && f.Name != "host" {
+			buf.WriteString(f.String() + "\r\n")
+		}
+	}
+	buf.WriteString("\r\n")
+	buf.Write(body)
+	return buf.Bytes()
+}
+
+// http2FrameInput is a single-stream HTTP/2 exchange written frame by frame.
+type http2FrameInput struct {
+	Target   Target
+	Fields   []h2Field // encoded in order, without validation
+	Body     []byte
+	Settings []http2.Setting
+}
+
+// http2FrameResult is the response of stream 1 and any connection or stream error.
+type http2FrameResult struct {
+	Status      int
+	Headers     []h2Field // final response fields, :status included, in received order
+	Trailers    []h2Field
+	Body        []byte
+	StreamError string // RST_STREAM error code
+	GoAway      string // GOAWAY error code and debug data
+	Duration    time.Duration
+}
+
+// sendHTTP2Frames opens a connection (TLS with ALPN h2, else prior-knowledge h2c) and
+// sends one request on stream 1 with the header fields exactly as given, so pseudo-header
+// order, duplicates, uppercase names and connection headers reach the server unchanged.
+// The exchange ends with the response stream, RST_STREAM or GOAWAY.
+func sendHTTP2Frames(ctx context.Context, in http2FrameInput) (*http2FrameResult, error) {
+	start := time.Now()
+	addr := net.JoinHostPort(in.Target.Hostname, strconv.Itoa(in.Target.Port))
+	conn, err := dialContext(ctx, "tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("dial %s: %w", addr, err)
+	}
+	defer func() { _ = conn.Close() }()
+	stop := context.AfterFunc(ctx, func() { _ = conn.SetDeadline(time.Now()) })
+	defer stop()
+
+	rw := io.ReadWriter(conn)
+	if in.Target.UsesHTTPS {
+		tlsConn := tls.Client(conn, &tls.Config{
+			ServerName:         in.Target.Hostname,
+			InsecureSkipVerify: true,
+			NextProtos:         []string{"h2"},
+		})
+		if err := tlsConn.HandshakeContext(ctx); err != nil {
+			return nil, wrapConnErr(ctx, "TLS handshake with "+addr, err)
+		}
+		if proto := tlsConn.ConnectionState().NegotiatedProtocol; proto != "h2" {
+			return nil, fmt.Errorf("%s did not negotiate h2 (ALPN %q)", addr, proto)
+		}
+		rw = tlsConn
+	}
+
+	bw := bufio.NewWriter(rw)
+	fr := http2.NewFramer(bw, bufio.NewReader(rw))
+	fr.AllowIllegalWrites = true
+	fr.AllowIllegalReads = true
+	fr.SetMaxReadFrameSize(1<<24 - 1)
+
+	settings := in.Settings
+	if !slices.ContainsFunc(settings, func(s http2.Setting) bool { return s.ID == http2.SettingEnablePush }) {
+		settings = append([]http2.Setting{{ID: http2.SettingEnablePush, Val: 0}}, settings...)
+	}
+	var block bytes.Buffer
+	enc := hpack.NewEncoder(&block)
+	for _, f := range in.Fields {
+		if err := enc.WriteField(hpack.HeaderField{Name: f.Name, Value: f.Value}); err != nil {
+			return nil, fmt.Errorf("encode header %s: %w", f.Name, err)
+		}
+	}
+
+	const streamID = 1
+	peerMaxFrame := 16384
+	if _, err := bw.WriteString(http2.ClientPreface); err != nil {
+		return nil, wrapConnErr(ctx, "write preface", err)
+	}
+	if err := fr.WriteSettings(settings...); err != nil {
+		return nil, wrapConnErr(ctx, "write settings", err)
+	}
+	fragment := block.Bytes()
+	first := fragment[:min(len(fragment), peerMaxFrame)]
+	fragment = fragment[len(first):]
+	if err := fr.WriteHeaders(http2.HeadersFrameParam{
+		StreamID:      streamID,
+		BlockFragment: first,
+		EndStream:     len(in.Body) == 0,
+		EndHeaders:    len(fragment) == 0,
+	}); err != nil {
+		return nil, wrapConnErr(ctx, "write headers", err)
+	}
+	for len(fragment) > 0 {
+		n := min(len(fragment), peerMaxFrame)
+		if err := fr.WriteContinuation(streamID, n == len(fragment), fragment[:n]); err != nil {
+			return nil, wrapConnErr(ctx, "write continuation", err)
+		}
+		fragment = fragment[n:]
+	}
+
+	result := &http2FrameResult{}
+	dec := hpack.NewDecoder(4096, nil)
+	body := in.Body
+	connWindow, streamWindow, peerInitialWindow := int64(65535), int64(65535), int64(65535)
+	var headerBlock []byte
+	var blockEndsStream, gotFinal, done bool
+	for !done {
+		// Send as much of the body as flow control allows
+		for len(body) > 0 && connWindow > 0 && streamWindow > 0 {
+			n := min(len(body), peerMaxFrame, int(connWindow), int(streamWindow))
+			if err := fr.WriteData(streamID, n == len(body), body[:n]); err != nil {
+				return nil, wrapConnErr(ctx, "write data", err)
+			}
+			body = body[n:]
+			connWindow -= int64(n)
+			streamWindow -= int64(n)
+		}
+		if err := bw.Flush(); err != nil {
+			return nil, wrapConnErr(ctx, "write", err)
+		}
+
+		frame, err := fr.ReadFrame()
+		if err != nil {
+			if gotFinal && (errors.Is(err, io.EOF) || ctx.Err() != nil) {
+				break // partial response
+			}
+			return nil, wrapConnErr(ctx, "read frame", err)
+		}
+		switch f := frame.(type) {
+		case *http2.SettingsFrame:
+			if f.IsAck() {
+				continue
+			}
+			_ = f.ForeachSetting(func(s http2.Setting) error {
+				switch s.ID {
+				case http2.SettingInitialWindowSize:
+					streamWindow += int64(s.Val) - peerInitialWindow
+					peerInitialWindow = int64(s.Val)
+				case http2.SettingMaxFrameSize:
+					peerMaxFrame = int(s.Val)
+				}
+				return nil
+			})
+			err = fr.WriteSettingsAck()
+		case *http2.PingFrame:
+			if !f.IsAck() {
+				err = fr.WritePing(true, f.Data)
+			}
+		case *http2.WindowUpdateFrame:
+			switch f.StreamID {
+			case 0:
+				connWindow += int64(f.Increment)
+			case streamID:
+				streamWindow += int64(f.Increment)
+			}
+		case *http2.HeadersFrame:
+			if f.StreamID != streamID {
+				continue
+			}
+			headerBlock = append(headerBlock[:0], f.HeaderBlockFragment()...)
+			blockEndsStream = f.StreamEnded()
+			if f.HeadersEnded() {
+				done, err = result.addHeaderBlock(dec, headerBlock, blockEndsStream, &gotFinal)
+			}
+		case *http2.ContinuationFrame:
+			if f.StreamID != streamID {
+				continue
+			}
+			headerBlock = append(headerBlock, f.HeaderBlockFragment()...)
+			if f.HeadersEnded() {
+				done, err = result.addHeaderBlock(dec, headerBlock, blockEndsStream, &gotFinal)
+			}
+		case *http2.DataFrame:
+			if f.StreamID == streamID {
+				result.Body = append(result.Body, f.Data()...)
+				done = f.StreamEnded()
+			}
+			if n := f.Length; n > 0 && !done {
+				if err = fr.WriteWindowUpdate(0, n); err == nil {
+					err = fr.WriteWindowUpdate(f.StreamID, n)
+				}
+			}
+		case *http2.RSTStreamFrame:
+			if f.StreamID == streamID {
+				result.StreamError, done = f.ErrCode.String(), true
+			}
+		case *http2.GoAwayFrame:
+			result.GoAway = f.ErrCode.String()
+			if debug := f.DebugData(); len(debug) > 0 {
+				result.GoAway += ": " + string(debug)
+			}
+			done = true
+		}
+		if err != nil {
+			return nil, wrapConnErr(ctx, "handle frame", err)
+		}
+	}
+	result.Duration = time.Since(start)
+	return result, nil
+}
+
+// addHeaderBlock decodes a complete header block as the response headers, skipping
+// 1xx responses, or as trailers once the final headers arrived. It reports whether
+// the stream ended.
+func (r *http2FrameResult) addHeaderBlock(dec *hpack.Decoder, block []byte, endStream bool, gotFinal *bool) (bool, error) {
+	fields, err := dec.DecodeFull(block)
+	if err != nil {
+		return false, fmt.Errorf("decode header block: %w", err)
+	}
+	converted := make([]h2Field, len(fields))
+	for i, f := range fields {
+		converted[i] = h2Field{Name: f.Name, Value: f.Value}
+	}
+	if *gotFinal {
+		r.Trailers = append(r.Trailers, converted...)
+		return endStream, nil
+	}
+	for _, f := range converted {
+		if f.Name == ":status" {
+			r.Status, _ = strconv.Atoi(f.Value)
+		}
+	}
+	if r.Status >= 100 && r.Status < 200 {
+		return endStream, nil // informational
+	}
+	r.Headers, *gotFinal = converted, true
+	return endStream, nil
+}
+
+// head renders the status line, headers in received order and trailers as an
+// HTTP/1.1-style header block with a blank line, or nil without a response.
+func (r *http2FrameResult) head() []byte {
+	if r.Headers == nil {
+		return nil
+	}
+	var buf bytes.Buffer
+	buf.WriteString("HTTP/2 " + strconv.Itoa(r.Status))
+	if text := http.StatusText(r.Status); text != "" {
+		buf.WriteString(" " + text)
+	}
+	buf.WriteString("\r\n")
+	for _, f := range slices.Concat(r.Headers, r.Trailers) {
+		if f.Name != ":status" {
+			buf.WriteString(f.String() + "\r\n")
+		}
+	}
+	buf.WriteString("\r\n")
+	return buf.Bytes()
+}
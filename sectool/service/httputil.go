@@ -7,6 +7,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"log"
 	"net/http"
 	"net/url"
 	"path"
@@ -18,6 +19,7 @@ import (
 	"time"
 	"unicode/utf8"
 
+	"github.com/go-harden/llm-security-toolbox/sectool/bundle"
 	"github.com/go-harden/llm-security-toolbox/sectool/config"
 	"github.com/go-harden/llm-security-toolbox/sectool/protocol"
 )
@@ -181,6 +183,22 @@ func inferSchemeAndPort(host string) (scheme string, port int, hostOnly string)
 	return scheme, port, hostOnly
 }
 
+// extractResponseDate returns the parsed Date header of a raw response, or zero if absent or invalid.
+func extractResponseDate(raw string) time.Time {
+	headers, _, _ := strings.Cut(raw, "\r\n\r\n")
+	for _, line := range strings.Split(headers, "\r\n")[1:] {
+		name, value, ok := strings.Cut(line, ":")
+		if ok && strings.EqualFold(strings.TrimSpace(name), "Date") {
+			t, err := http.ParseTime(strings.TrimSpace(value))
+			if err != nil {
+				return time.Time{}
+			}
+			return t
+		}
+	}
+	return time.Time{}
+}
+
 func readResponseBytes(resp []byte) (*http.Response, error) {
 	// Converts "HTTP/2 " to "HTTP/2.0 " since Go's parser requires major.minor format.
 	if bytes.HasPrefix(resp, []byte("HTTP/2 ")) {
@@ -207,6 +225,35 @@ func previewBody(body []byte, maxLen int) string {
 	return string(runes[:maxLen]) + "..."
 }
 
+// decodeResponseEncoding removes the chunked transfer coding and content codings from
+// a response, rewriting the framing headers to match. It returns the codings removed,
+// or "" with the response unchanged when there were none or decoding failed.
+func decodeResponseEncoding(headers, body []byte) ([]byte, []byte, string) {
+	h := http.Header(parseHeadersToMap(string(headers)))
+	var codings []string
+	if strings.Contains(strings.ToLower(strings.Join(h.Values("Transfer-Encoding"), ",")), "chunked") {
+		codings = append(codings, "chunked")
+	}
+	for _, value := range h.Values("Content-Encoding") {
+		for _, coding := range strings.Split(value, ",") {
+			if coding = strings.ToLower(strings.TrimSpace(coding)); coding != "" && coding != "identity" {
+				codings = append(codings, coding)
+			}
+		}
+	}
+	if len(codings) == 0 {
+		return headers, body, ""
+	}
+
+	decoded, err := bundle.DecodeBody(string(headers), body)
+	if err != nil {
+		log.Printf("response decoding skipped: %v", err)
+		return headers, body, ""
+	}
+	headers = removeHeader(removeHeader(headers, "Transfer-Encoding"), "Content-Encoding")
+	return setHeader(headers, "Content-Length", strconv.Itoa(len(decoded))), decoded, strings.Join(codings, ", ")
+}
+
 // transformRequestForValidation converts HTTP/2 request lines to HTTP/1.1 for Go's parser.
 // "POST /path HTTP/2\r\n" -> "POST /path HTTP/1.1\r\n"
 // The original request should still be sent to Burp (which handles HTTP/2 natively).
@@ -1,7 +1,10 @@
 package service
 
 import (
+	"bytes"
+	"compress/gzip"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -1408,3 +1411,100 @@ func TestSetHeaderIfMissing(t *testing.T) {
 		})
 	}
 }
+
+func TestExtractResponseDate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		raw  string
+		want time.Time
+	}{
+		{
+			name: "date_header",
+			raw:  "HTTP/1.1 200 OK\r\nDate: Tue, 15 Nov 1994 08:12:31 GMT\r\n\r\nbody",
+			want: time.Date(1994, 11, 15, 8, 12, 31, 0, time.UTC),
+		},
+		{
+			name: "case_insensitive",
+			raw:  "HTTP/1.1 200 OK\r\ndate: Tue, 15 Nov 1994 08:12:31 GMT\r\n\r\n",
+			want: time.Date(1994, 11, 15, 8, 12, 31, 0, time.UTC),
+		},
+		{
+			name: "missing",
+			raw:  "HTTP/1.1 200 OK\r\nContent-Type: text/plain\r\n\r\nDate: Tue, 15 Nov 1994 08:12:31 GMT",
+		},
+		{
+			name: "invalid",
+			raw:  "HTTP/1.1 200 OK\r\nDate: yesterday\r\n\r\n",
+		},
+		{
+			name: "empty",
+			raw:  "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.True(t, tt.want.Equal(extractResponseDate(tt.raw)))
+		})
+	}
+}
+
+func TestDecodeResponseEncoding(t *testing.T) {
+	t.Parallel()
+
+	var gz bytes.Buffer
+	zw := gzip.NewWriter(&gz)
+	_, _ = zw.Write([]byte("hello world"))
+	require.NoError(t, zw.Close())
+
+	tests := []struct {
+		name        string
+		headers     string
+		body        []byte
+		wantHeaders string
+		wantBody    string
+		wantDecoded string
+	}{
+		{
+			name:        "gzip",
+			headers:     "HTTP/1.1 200 OK\r\nContent-Encoding: gzip\r\nContent-Length: 31\r\n\r\n",
+			body:        gz.Bytes(),
+			wantHeaders: "HTTP/1.1 200 OK\r\nContent-Length: 11\r\n\r\n",
+			wantBody:    "hello world",
+			wantDecoded: "gzip",
+		},
+		{
+			name:        "chunked",
+			headers:     "HTTP/1.1 200 OK\r\nTransfer-Encoding: chunked\r\n\r\n",
+			body:        []byte("5\r\nhello\r\n0\r\n\r\n"),
+			wantHeaders: "HTTP/1.1 200 OK\r\nContent-Length: 5\r\n\r\n",
+			wantBody:    "hello",
+			wantDecoded: "chunked",
+		},
+		{
+			name:        "plain",
+			headers:     "HTTP/1.1 200 OK\r\nContent-Length: 5\r\n\r\n",
+			body:        []byte("hello"),
+			wantHeaders: "HTTP/1.1 200 OK\r\nContent-Length: 5\r\n\r\n",
+			wantBody:    "hello",
+		},
+		{
+			name:        "unsupported_left_encoded",
+			headers:     "HTTP/1.1 200 OK\r\nContent-Encoding: zstd\r\n\r\n",
+			body:        []byte("\x28\xb5\x2f\xfd"),
+			wantHeaders: "HTTP/1.1 200 OK\r\nContent-Encoding: zstd\r\n\r\n",
+			wantBody:    "\x28\xb5\x2f\xfd",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			headers, body, decoded := decodeResponseEncoding([]byte(tt.headers), tt.body)
+			assert.Equal(t, tt.wantHeaders, string(headers))
+			assert.Equal(t, tt.wantBody, string(body))
+			assert.Equal(t, tt.wantDecoded, decoded)
+		})
+	}
+}
@@ -0,0 +1,145 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/go-harden/llm-security-toolbox/sectool/config"
+)
+
+// identitiesFileName is the project identities file within config.ProjectDirName.
+const identitiesFileName = "identities.json"
+
+// identity is the credential set of one user, applied to a request in place of
+// the credentials it was captured with.
+type identity struct {
+	Headers       []string          `json:"headers,omitempty"` // "Name: Value", replacing same-named headers
+	Cookies       map[string]string `json:"cookies,omitempty"` // merged into the Cookie header by name
+	RemoveHeaders []string          `json:"remove_headers,omitempty"`
+}
+
+// Apply returns raw with the identity's headers removed, set and cookies merged,
+// in that order, so remove_headers ["Cookie"] plus cookies yields only those cookies.
+func (id *identity) Apply(raw []byte) []byte {
+	headers, body := splitHeadersBody(raw)
+	for _, name := range id.RemoveHeaders {
+		headers = removeHeader(headers, name)
+	}
+	for _, line := range id.Headers {
+		name, value, _ := strings.Cut(line, ":")
+		headers = setHeader(headers, strings.TrimSpace(name), strings.TrimSpace(value))
+	}
+	if len(id.Cookies) > 0 {
+		pairs := make([]string, 0, len(id.Cookies))
+		for _, name := range slices.Sorted(maps.Keys(id.Cookies)) {
+			pairs = append(pairs, name+"="+id.Cookies[name])
+		}
+		cookie := mergeCookies(parseHeadersToMap(string(headers))["Cookie"], strings.Join(pairs, "; "))
+		headers = setHeader(removeHeader(headers, "Cookie"), "Cookie", cookie)
+	}
+	return append(headers, body...)
+}
+
+type identitiesFile struct {
+	Identities map[string]*identity `json:"identities"`
+}
+
+// identityStore persists named identities in .sectool/identities.json. Every
+// operation re-reads the file so hand edits are kept. Thread-safe.
+type identityStore struct {
+	mu   sync.Mutex
+	path string
+}
+
+func newIdentityStore(projectDir string) *identityStore {
+	return &identityStore{path: filepath.Join(projectDir, config.ProjectDirName, identitiesFileName)}
+}
+
+// Load returns all identities.
+func (s *identityStore) Load() (*identitiesFile, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.load()
+}
+
+// Get returns the named identity.
+func (s *identityStore) Get(name string) (*identity, error) {
+	file, err := s.Load()
+	if err != nil {
+		return nil, err
+	}
+	id, ok := file.Identities[name]
+	if !ok || id == nil {
+		return nil, fmt.Errorf("identity %q not found: known identities %v", name, slices.Sorted(maps.Keys(file.Identities)))
+	}
+	return id, nil
+}
+
+// Set adds or replaces identity name.
+func (s *identityStore) Set(name string, id *identity) (*identitiesFile, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	file, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+	file.Identities[name] = id
+	return file, s.save(file)
+}
+
+// Delete removes identity name.
+func (s *identityStore) Delete(name string) (*identitiesFile, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	file, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := file.Identities[name]; !ok {
+		return nil, fmt.Errorf("identity %q not found", name)
+	}
+	delete(file.Identities, name)
+	return file, s.save(file)
+}
+
+func (s *identityStore) load() (*identitiesFile, error) {
+	file := &identitiesFile{}
+	data, err := os.ReadFile(s.path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	} else if err == nil {
+		if err := json.Unmarshal(data, file); err != nil {
+			return nil, fmt.Errorf("parse %s: %w", s.path, err)
+		}
+	}
+	if file.Identities == nil {
+		file.Identities = make(map[string]*identity)
+	}
+	return file, nil
+}
+
+// save writes the file atomically. Identities hold credentials, so it is owner-only.
+func (s *identityStore) save(file *identitiesFile) error {
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return fmt.Errorf("create identities directory: %w", err)
+	}
+	data, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, s.path)
+}
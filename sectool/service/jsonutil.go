@@ -170,6 +170,31 @@ func parseJSONPath(path string) ([]pathSegment, error) {
 	return segments, nil
 }
 
+// getValueAtPath returns the value at the path.
+func getValueAtPath(data interface{}, segments []pathSegment) (interface{}, error) {
+	for _, seg := range segments {
+		if seg.Index >= 0 {
+			arr, ok := data.([]interface{})
+			if !ok {
+				return nil, fmt.Errorf("expected array at index [%d], got %T", seg.Index, data)
+			} else if seg.Index >= len(arr) {
+				return nil, fmt.Errorf("index [%d] out of range (length %d)", seg.Index, len(arr))
+			}
+			data = arr[seg.Index]
+			continue
+		}
+
+		obj, ok := data.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("expected object at key %q, got %T", seg.Key, data)
+		}
+		if data, ok = obj[seg.Key]; !ok {
+			return nil, fmt.Errorf("key %q not found", seg.Key)
+		}
+	}
+	return data, nil
+}
+
 // setValueAtPath recursively sets a value at the path.
 func setValueAtPath(data interface{}, segments []pathSegment, value interface{}) (interface{}, error) {
 	if len(segments) == 0 {
@@ -778,6 +778,49 @@ func (c *BurpClient) SetTaskExecutionEngineState(ctx context.Context, running bo
 	})
 }
 
+// GetScannerIssues retrieves issues Burp's passive and active scanner reported for
+// the project. Burp Suite Community has no scanner and returns an error.
+func (c *BurpClient) GetScannerIssues(ctx context.Context, count, offset int) ([]ScannerIssue, error) {
+	var issues []ScannerIssue
+	err := c.withConn(ctx, func(opCtx context.Context) error {
+		result, err := c.mcpClient.CallTool(opCtx, mcp.CallToolRequest{
+			Params: mcp.CallToolParams{
+				Name: "get_scanner_issues",
+				Arguments: map[string]interface{}{
+					"count":  count,
+					"offset": offset,
+				},
+			},
+		})
+		if err != nil {
+			return fmt.Errorf("get_scanner_issues failed: %w", err)
+		} else if result.IsError {
+			return fmt.Errorf("MCP error: %s", extractTextContent(result.Content))
+		}
+		var parseErr error
+		issues, parseErr = parseScannerIssues(extractTextContent(result.Content))
+		return parseErr
+	})
+	return issues, err
+}
+
+// parseScannerIssues parses the JSON objects, one per line, from get_scanner_issues.
+func parseScannerIssues(text string) ([]ScannerIssue, error) {
+	var issues []ScannerIssue
+	for i, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if !strings.HasPrefix(line, "{") {
+			continue // blank separators and the end-of-items marker
+		}
+		var issue ScannerIssue
+		if err := json.Unmarshal([]byte(line), &issue); err != nil {
+			return issues, fmt.Errorf("failed to parse scanner issue at line %d: %w", i+1, err)
+		}
+		issues = append(issues, issue)
+	}
+	return issues, nil
+}
+
 // GetActiveEditorContents retrieves the contents of the user's active message editor.
 func (c *BurpClient) GetActiveEditorContents(ctx context.Context) (string, error) {
 	var contents string
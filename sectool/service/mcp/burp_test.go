@@ -36,6 +36,9 @@ func TestBurpClientClosed(t *testing.T) {
 
 	err = client.CreateRepeaterTab(ctx, RepeaterTabParams{})
 	require.ErrorIs(t, err, ErrClientClosed)
+
+	_, err = client.GetScannerIssues(ctx, 10, 0)
+	require.ErrorIs(t, err, ErrClientClosed)
 }
 
 func TestSanitizeBurpJSON(t *testing.T) {
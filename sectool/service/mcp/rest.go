@@ -0,0 +1,137 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-harden/llm-security-toolbox/sectool/config"
+)
+
+// restTimeout bounds each REST API call; scans run in Burp after the start call returns.
+const restTimeout = 30 * time.Second
+
+// RESTClient calls Burp Suite Professional's REST API (Settings > Suite > REST API).
+// The MCP extension reports scanner issues but cannot start scans, so scans go here.
+type RESTClient struct {
+	baseURL    string // http://host:port[/api_key]
+	httpClient *http.Client
+}
+
+// NewRESTClient creates a client for the REST API at baseURL, which includes the
+// API key as its first path segment when one is configured.
+func NewRESTClient(baseURL string) *RESTClient {
+	if baseURL == "" {
+		baseURL = config.DefaultBurpRESTURL
+	}
+	return &RESTClient{
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		httpClient: &http.Client{Timeout: restTimeout},
+	}
+}
+
+// URL returns the REST API base URL.
+func (c *RESTClient) URL() string {
+	return c.baseURL
+}
+
+// StartScan starts a crawl and audit of params.URLs, limited to those URL prefixes,
+// and returns the scan task ID.
+func (c *RESTClient) StartScan(ctx context.Context, params ScanParams) (string, error) {
+	type scopeRule struct {
+		Rule string `json:"rule"`
+	}
+	type namedConfig struct {
+		Name string `json:"name"`
+		Type string `json:"type"`
+	}
+	body := struct {
+		URLs  []string `json:"urls"`
+		Scope struct {
+			Type    string      `json:"type"`
+			Include []scopeRule `json:"include"`
+		} `json:"scope"`
+		Configurations []namedConfig `json:"scan_configurations,omitempty"`
+	}{URLs: params.URLs}
+	body.Scope.Type = "SimpleScope"
+	for _, u := range params.URLs {
+		body.Scope.Include = append(body.Scope.Include, scopeRule{Rule: u})
+	}
+	for _, name := range params.Configurations {
+		body.Configurations = append(body.Configurations, namedConfig{Name: name, Type: "NamedConfiguration"})
+	}
+	data, err := json.Marshal(body)
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := c.do(ctx, http.MethodPost, "/v0.1/scan", data)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusCreated {
+		return "", restError(resp)
+	}
+	taskID := resp.Header.Get("Location")
+	if taskID == "" {
+		return "", fmt.Errorf("burp REST API returned no scan task ID")
+	}
+	return taskID, nil
+}
+
+// GetScan returns the state of a scan task with its issue events from index issuesFrom.
+func (c *RESTClient) GetScan(ctx context.Context, taskID string, issuesFrom int) (*ScanTask, error) {
+	path := "/v0.1/scan/" + url.PathEscape(taskID)
+	if issuesFrom > 0 {
+		path += "?issue_events_from=" + strconv.Itoa(issuesFrom)
+	}
+	resp, err := c.do(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return nil, restError(resp)
+	}
+
+	var task ScanTask
+	if err := json.NewDecoder(resp.Body).Decode(&task); err != nil {
+		return nil, fmt.Errorf("parse scan task: %w", err)
+	}
+	if task.TaskID == "" {
+		task.TaskID = taskID
+	}
+	return &task, nil
+}
+
+func (c *RESTClient) do(ctx context.Context, method, path string, body []byte) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("burp REST API unavailable at %s (enable it in Burp Suite Professional settings, see config burp_rest_url): %w", c.baseURL, err)
+	}
+	return resp, nil
+}
+
+// restError describes a failed REST API response.
+func restError(resp *http.Response) error {
+	msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	if resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusUnauthorized {
+		return fmt.Errorf("burp REST API returned %s: check the scan ID and the API key in burp_rest_url", resp.Status)
+	}
+	return fmt.Errorf("burp REST API returned %s: %s", resp.Status, strings.TrimSpace(string(msg)))
+}
@@ -1,5 +1,7 @@
 package mcp
 
+import "encoding/json"
+
 // ProxyHistoryEntry represents a single NDJSON entry from get_proxy_http_history.
 type ProxyHistoryEntry struct {
 	Request  string `json:"request"`
@@ -51,6 +53,62 @@ type WebSocketHistoryEntry struct {
 	Opcode    string `json:"opcode,omitempty"`
 }
 
+// ScannerIssue is an issue from get_scanner_issues.
+type ScannerIssue struct {
+	Name        string `json:"name"`
+	Detail      string `json:"detail"`
+	Remediation string `json:"remediation"`
+	BaseURL     string `json:"baseUrl"`
+	Severity    string `json:"severity"`   // HIGH, MEDIUM, LOW, INFORMATION or FALSE_POSITIVE
+	Confidence  string `json:"confidence"` // CERTAIN, FIRM or TENTATIVE
+}
+
+// ScanParams are the parameters for starting a REST API scan.
+type ScanParams struct {
+	URLs           []string // scanned and used as include-scope prefixes
+	Configurations []string // named scan configurations, e.g. "Audit checks - light active"
+}
+
+// ScanTask is the state of a REST API scan task.
+type ScanTask struct {
+	TaskID      string           `json:"task_id"`
+	Status      string           `json:"scan_status"` // queued, crawling, auditing, succeeded, failed, paused, ...
+	Metrics     ScanMetrics      `json:"scan_metrics"`
+	IssueEvents []ScanIssueEvent `json:"issue_events"`
+}
+
+// ScanMetrics are the progress counters of a REST API scan task.
+type ScanMetrics struct {
+	Caption           string `json:"crawl_and_audit_caption"`
+	Progress          int    `json:"crawl_and_audit_progress"` // percent
+	CrawlRequestsMade int    `json:"crawl_requests_made"`
+	AuditRequestsMade int    `json:"audit_requests_made"`
+	AuditItemsDone    int    `json:"audit_queue_items_completed"`
+	AuditItemsWaiting int    `json:"audit_queue_items_waiting"`
+	IssueEvents       int    `json:"issue_events"`
+	ElapsedSeconds    int    `json:"total_elapsed_time"`
+}
+
+// ScanIssueEvent is an issue reported by a REST API scan task.
+type ScanIssueEvent struct {
+	ID    string    `json:"id"`
+	Type  string    `json:"type"` // issue_found
+	Issue ScanIssue `json:"issue"`
+}
+
+// ScanIssue is the issue of a ScanIssueEvent.
+type ScanIssue struct {
+	Name         string      `json:"name"`
+	TypeIndex    json.Number `json:"type_index"`
+	SerialNumber json.Number `json:"serial_number"`
+	Origin       string      `json:"origin"` // scheme://host[:port]
+	Path         string      `json:"path"`
+	Severity     string      `json:"severity"`   // high, medium, low or info
+	Confidence   string      `json:"confidence"` // certain, firm or tentative
+	Description  string      `json:"description"`
+	Remediation  string      `json:"remediation"`
+}
+
 // MatchReplaceRule represents a Burp proxy match and replace rule.
 // HTTP rules use RuleType values: request_header, request_body, response_header, response_body
 // WebSocket rules use RuleType values: client_to_server, server_to_client, both_directions
@@ -0,0 +1,210 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"regexp"
+	"strconv"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/go-harden/llm-security-toolbox/sectool/protocol"
+)
+
+const maxBatchCalls = 20
+
+// batchRefRe matches {{N}} or {{N.path}} references to the output of call N.
+var batchRefRe = regexp.MustCompile(`\{\{(\d+)(?:\.([^{}]+))?\}\}`)
+
+func (m *mcpServer) batchTool() mcp.Tool {
+	return mcp.NewTool("batch",
+		mcp.WithDescription(`Run several tool calls in order in one round-trip.
+
+Each call is {"tool": "<name>", "args": {...}}. String args may reference earlier JSON outputs as {{N.path}} (N is the 0-based call index, path uses dots and [i], e.g. {{0.flows[0].flow_id}}).
+A string that is exactly one reference takes the referenced value with its JSON type; otherwise references are spliced in as text.
+Stops at the first failing call unless continue_on_error is set. batch cannot be nested. Max 20 calls.
+
+Example (list -> get -> replay):
+[{"tool":"proxy_poll","args":{"output_mode":"flows","path":"/api/*","limit":1}},
+ {"tool":"proxy_get","args":{"flow_id":"{{0.flows[0].flow_id}}"}},
+ {"tool":"replay_send","args":{"flow_id":"{{0.flows[0].flow_id}}","set_query":["id=2"]}}]`),
+		mcp.WithArray("calls", mcp.Required(), mcp.Items(map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"tool": map[string]interface{}{"type": "string"},
+				"args": map[string]interface{}{"type": "object"},
+			},
+			"required": []string{"tool"},
+		}), mcp.Description("Ordered tool calls")),
+		mcp.WithBoolean("continue_on_error", mcp.Description("Keep running after a failed call (default false)")),
+		annotateSendsTraffic, // may run any tool
+	)
+}
+
+type batchCall struct {
+	Tool string                 `json:"tool"`
+	Args map[string]interface{} `json:"args"`
+}
+
+func (m *mcpServer) handleBatch(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if err := m.requireWorkflow(); err != nil {
+		return err, nil
+	}
+
+	var calls []batchCall
+	if raw, err := json.Marshal(req.GetArguments()["calls"]); err != nil {
+		return errorResult("invalid calls: " + err.Error()), nil
+	} else if err := json.Unmarshal(raw, &calls); err != nil {
+		return errorResult("invalid calls: " + err.Error()), nil
+	}
+	if len(calls) == 0 {
+		return errorResult("calls is required"), nil
+	} else if len(calls) > maxBatchCalls {
+		return errorResult(fmt.Sprintf("too many calls: %d (max %d)", len(calls), maxBatchCalls)), nil
+	}
+	for i, call := range calls {
+		if call.Tool == "batch" {
+			return errorResult(fmt.Sprintf("call %d: batch cannot be nested", i)), nil
+		} else if m.server.GetTool(call.Tool) == nil {
+			return errorResult(fmt.Sprintf("call %d: unknown tool %q", i, call.Tool)), nil
+		}
+	}
+	continueOnError := req.GetBool("continue_on_error", false)
+
+	log.Printf("mcp/batch: running %d calls", len(calls))
+
+	outputs := make([]interface{}, len(calls)) // decoded JSON output per call, nil if none
+	resp := protocol.BatchResponse{Results: make([]protocol.BatchResult, 0, len(calls))}
+	for i, call := range calls {
+		result := m.runBatchCall(ctx, call, outputs)
+		resp.Results = append(resp.Results, result)
+		if result.Output != nil {
+			_ = json.Unmarshal(result.Output, &outputs[i])
+		}
+		if result.Error != "" && !continueOnError {
+			resp.Skipped = len(calls) - i - 1
+			log.Printf("mcp/batch: call %d (%s) failed, skipping %d", i, call.Tool, resp.Skipped)
+			break
+		}
+	}
+
+	return jsonResult(resp)
+}
+
+// runBatchCall resolves references in call's args and runs it.
+func (m *mcpServer) runBatchCall(ctx context.Context, call batchCall, outputs []interface{}) protocol.BatchResult {
+	result := protocol.BatchResult{Tool: call.Tool}
+
+	args, err := resolveBatchRefs(call.Args, outputs)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	argsMap, _ := args.(map[string]interface{})
+
+	tool := m.server.GetTool(call.Tool)
+	if tool == nil { // removed since validation (capability change)
+		result.Error = "tool no longer available"
+		return result
+	}
+	toolResult, err := tool.Handler(ctx, mcp.CallToolRequest{
+		Params: mcp.CallToolParams{Name: call.Tool, Arguments: argsMap},
+	})
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+
+	text := resultText(toolResult)
+	if toolResult.IsError {
+		result.Error = text
+	} else if json.Valid([]byte(text)) {
+		result.Output = json.RawMessage(text)
+	} else {
+		result.Text = text
+	}
+	return result
+}
+
+// resolveBatchRefs replaces {{N.path}} references in string values of v.
+func resolveBatchRefs(v interface{}, outputs []interface{}) (interface{}, error) {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		resolved := make(map[string]interface{}, len(val))
+		for k, item := range val {
+			r, err := resolveBatchRefs(item, outputs)
+			if err != nil {
+				return nil, err
+			}
+			resolved[k] = r
+		}
+		return resolved, nil
+	case []interface{}:
+		resolved := make([]interface{}, len(val))
+		for i, item := range val {
+			r, err := resolveBatchRefs(item, outputs)
+			if err != nil {
+				return nil, err
+			}
+			resolved[i] = r
+		}
+		return resolved, nil
+	case string:
+		return resolveBatchString(val, outputs)
+	default:
+		return v, nil
+	}
+}
+
+func resolveBatchString(s string, outputs []interface{}) (interface{}, error) {
+	matches := batchRefRe.FindAllStringSubmatchIndex(s, -1)
+	if len(matches) == 0 {
+		return s, nil
+	}
+
+	lookup := func(match []int) (interface{}, error) {
+		ref := s[match[0]:match[1]]
+		n, _ := strconv.Atoi(s[match[2]:match[3]])
+		if n >= len(outputs) || outputs[n] == nil {
+			return nil, fmt.Errorf("reference %s: call %d has no JSON output yet", ref, n)
+		} else if match[4] < 0 {
+			return outputs[n], nil
+		}
+		segments, err := parseJSONPath(s[match[4]:match[5]])
+		if err == nil {
+			var value interface{}
+			if value, err = getValueAtPath(outputs[n], segments); err == nil {
+				return value, nil
+			}
+		}
+		return nil, fmt.Errorf("reference %s: %w", ref, err)
+	}
+
+	// A lone reference keeps the referenced value's JSON type
+	if len(matches) == 1 && matches[0][0] == 0 && matches[0][1] == len(s) {
+		return lookup(matches[0])
+	}
+
+	var out []byte
+	var last int
+	for _, match := range matches {
+		value, err := lookup(match)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, s[last:match[0]]...)
+		if str, ok := value.(string); ok {
+			out = append(out, str...)
+		} else {
+			encoded, err := json.Marshal(value)
+			if err != nil {
+				return nil, fmt.Errorf("reference %s: %w", s[match[0]:match[1]], err)
+			}
+			out = append(out, encoded...)
+		}
+		last = match[1]
+	}
+	return string(append(out, s[last:]...)), nil
+}
@@ -0,0 +1,120 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/go-harden/llm-security-toolbox/sectool/protocol"
+)
+
+func TestResolveBatchRefs(t *testing.T) {
+	t.Parallel()
+
+	outputs := []interface{}{
+		map[string]interface{}{
+			"flows": []interface{}{
+				map[string]interface{}{"flow_id": "abc", "status": float64(200)},
+			},
+		},
+		nil, // call without JSON output
+	}
+
+	tests := []struct {
+		name     string
+		input    interface{}
+		expected interface{}
+		wantErr  string
+	}{
+		{name: "plain_string", input: "no refs", expected: "no refs"},
+		{name: "lone_ref_string", input: "{{0.flows[0].flow_id}}", expected: "abc"},
+		{name: "lone_ref_keeps_type", input: "{{0.flows[0].status}}", expected: float64(200)},
+		{name: "whole_output", input: "{{0}}", expected: outputs[0]},
+		{name: "spliced", input: "id={{0.flows[0].flow_id}}&s={{0.flows[0].status}}", expected: "id=abc&s=200"},
+		{
+			name:     "nested_args",
+			input:    map[string]interface{}{"list": []interface{}{"{{0.flows[0].flow_id}}", 5}},
+			expected: map[string]interface{}{"list": []interface{}{"abc", 5}},
+		},
+		{name: "missing_key", input: "{{0.flows[0].nope}}", wantErr: `key "nope" not found`},
+		{name: "out_of_range", input: "{{0.flows[3]}}", wantErr: "out of range"},
+		{name: "no_json_output", input: "{{1.x}}", wantErr: "call 1 has no JSON output"},
+		{name: "future_call", input: "{{5}}", wantErr: "call 5 has no JSON output"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := resolveBatchRefs(tc.input, outputs)
+			if tc.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, got)
+		})
+	}
+}
+
+func TestMCP_Batch(t *testing.T) {
+	t.Parallel()
+
+	_, mcpClient, mockMCP, _, _ := setupMCPServerWithMock(t)
+
+	mockMCP.AddProxyEntry(
+		"GET /api/users HTTP/1.1\r\nHost: example.com\r\n\r\n",
+		"HTTP/1.1 200 OK\r\nContent-Type: application/json\r\n\r\n{\"users\":[]}",
+		"",
+	)
+
+	t.Run("list_then_get", func(t *testing.T) {
+		resp := CallMCPToolJSONOK[protocol.BatchResponse](t, mcpClient, "batch", map[string]interface{}{
+			"calls": []interface{}{
+				map[string]interface{}{"tool": "proxy_poll", "args": map[string]interface{}{"output_mode": "flows", "limit": 1}},
+				map[string]interface{}{"tool": "proxy_get", "args": map[string]interface{}{"flow_id": "{{0.flows[0].flow_id}}"}},
+				map[string]interface{}{"tool": "encode_base64", "args": map[string]interface{}{"input": "x"}},
+			},
+		})
+		require.Len(t, resp.Results, 3)
+		assert.Empty(t, resp.Results[0].Error)
+		assert.Empty(t, resp.Results[1].Error)
+		assert.Contains(t, string(resp.Results[1].Output), "/api/users")
+		assert.Equal(t, "eA==", resp.Results[2].Text)
+	})
+
+	t.Run("stops_on_error", func(t *testing.T) {
+		resp := CallMCPToolJSONOK[protocol.BatchResponse](t, mcpClient, "batch", map[string]interface{}{
+			"calls": []interface{}{
+				map[string]interface{}{"tool": "proxy_get", "args": map[string]interface{}{"flow_id": "missing"}},
+				map[string]interface{}{"tool": "encode_base64", "args": map[string]interface{}{"input": "x"}},
+			},
+		})
+		require.Len(t, resp.Results, 1)
+		assert.NotEmpty(t, resp.Results[0].Error)
+		assert.Equal(t, 1, resp.Skipped)
+	})
+
+	t.Run("continue_on_error", func(t *testing.T) {
+		resp := CallMCPToolJSONOK[protocol.BatchResponse](t, mcpClient, "batch", map[string]interface{}{
+			"continue_on_error": true,
+			"calls": []interface{}{
+				map[string]interface{}{"tool": "proxy_get", "args": map[string]interface{}{"flow_id": "missing"}},
+				map[string]interface{}{"tool": "encode_base64", "args": map[string]interface{}{"input": "x"}},
+			},
+		})
+		require.Len(t, resp.Results, 2)
+		assert.Equal(t, "eA==", resp.Results[1].Text)
+	})
+
+	t.Run("rejected_calls", func(t *testing.T) {
+		for name, calls := range map[string][]interface{}{
+			"nested":  {map[string]interface{}{"tool": "batch"}},
+			"unknown": {map[string]interface{}{"tool": "nope"}},
+			"empty":   {},
+		} {
+			result := CallMCPTool(t, mcpClient, "batch", map[string]interface{}{"calls": calls})
+			assert.True(t, result.IsError, name)
+		}
+	})
+}
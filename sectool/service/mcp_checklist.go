@@ -0,0 +1,115 @@
+package service
+
+import (
+	"context"
+	"log"
+	"slices"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/go-harden/llm-security-toolbox/sectool/protocol"
+)
+
+var checklistStatuses = []string{protocol.ChecklistUntested, protocol.ChecklistTested, protocol.ChecklistNA, protocol.ChecklistVulnerable}
+
+func (m *mcpServer) checklistGetTool() mcp.Tool {
+	return mcp.NewTool("checklist_get",
+		mcp.WithDescription(`Get the project's methodology checklist and coverage summary.
+
+The checklist is seeded from the workflow mode's methodology on first use and persisted in the project (.sectool/checklist.json), so it survives restarts and is shared by all agents on this project.
+Check it when starting or resuming work to avoid repeating covered areas; update items with checklist_mark.`),
+		mcp.WithString("status", mcp.Description("Only items with this status: untested, tested, na, vulnerable")),
+		mcp.WithString("category", mcp.Description("Only items in this category")),
+		annotateReadOnly,
+	)
+}
+
+func (m *mcpServer) checklistMarkTool() mcp.Tool {
+	return mcp.NewTool("checklist_mark",
+		mcp.WithDescription(`Mark a checklist item as tested, na (not applicable), vulnerable, or back to untested.
+
+Use a note for evidence (flow_id/replay_id) or why it doesn't apply. An unknown item_id adds a custom item; title is then required.`),
+		mcp.WithString("item_id", mcp.Required(), mcp.Description("Checklist item ID from checklist_get, or a new ID for a custom item")),
+		mcp.WithString("status", mcp.Required(), mcp.Description("New status: untested, tested, na, vulnerable")),
+		mcp.WithString("note", mcp.Description("Evidence or reasoning (replaces the existing note)")),
+		mcp.WithString("title", mcp.Description("Custom item: what to test")),
+		mcp.WithString("category", mcp.Description("Custom item: category (default 'custom')")),
+		annotateLocalChange,
+	)
+}
+
+// methodology returns the workflow mode the checklist is seeded from.
+func (m *mcpServer) methodology() string {
+	if task, _ := m.workflowTask.Load().(string); task != "" {
+		return task
+	}
+	switch m.workflowMode {
+	case "", WorkflowModeNone, WorkflowModeCLI:
+		return WorkflowModeExplore
+	default:
+		return m.workflowMode
+	}
+}
+
+func (m *mcpServer) handleChecklistGet(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if err := m.requireWorkflow(); err != nil {
+		return err, nil
+	}
+
+	status := req.GetString("status", "")
+	category := req.GetString("category", "")
+	if status != "" && !slices.Contains(checklistStatuses, status) {
+		return errorResult("invalid status: use " + strings.Join(checklistStatuses, ", ")), nil
+	}
+
+	list, err := m.service.checklist.Get(m.methodology())
+	if err != nil {
+		return errorResultFromErr("failed to load checklist: ", err), nil
+	}
+
+	resp := protocol.ChecklistResponse{
+		Methodology: list.Methodology,
+		Coverage:    checklistCoverage(list.Items),
+	}
+	for _, item := range list.Items {
+		if (status != "" && item.Status != status) || (category != "" && item.Category != category) {
+			continue
+		}
+		resp.Items = append(resp.Items, item)
+	}
+	return jsonResult(resp)
+}
+
+func (m *mcpServer) handleChecklistMark(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if err := m.requireWorkflow(); err != nil {
+		return err, nil
+	}
+
+	itemID := req.GetString("item_id", "")
+	status := req.GetString("status", "")
+	if itemID == "" {
+		return errorResult("item_id is required"), nil
+	} else if !slices.Contains(checklistStatuses, status) {
+		return errorResult("invalid status: use " + strings.Join(checklistStatuses, ", ")), nil
+	}
+
+	list, err := m.service.checklist.Mark(m.methodology(), itemID, status,
+		req.GetString("note", ""), req.GetString("category", ""), req.GetString("title", ""))
+	if err != nil {
+		return errorResultFromErr("checklist_mark failed: ", err), nil
+	}
+	log.Printf("mcp/checklist_mark: %s -> %s", itemID, status)
+
+	resp := protocol.ChecklistResponse{
+		Methodology: list.Methodology,
+		Coverage:    checklistCoverage(list.Items),
+	}
+	for _, item := range list.Items {
+		if item.ID == itemID {
+			resp.Items = []protocol.ChecklistItem{item}
+			break
+		}
+	}
+	return jsonResult(resp)
+}
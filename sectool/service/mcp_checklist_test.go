@@ -0,0 +1,101 @@
+package service
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/go-harden/llm-security-toolbox/sectool/config"
+	"github.com/go-harden/llm-security-toolbox/sectool/protocol"
+)
+
+func TestMCP_Checklist(t *testing.T) {
+	t.Parallel()
+
+	srv, mcpClient, _, _, _ := setupMCPServerWithMock(t)
+
+	resp := CallMCPToolJSONOK[protocol.ChecklistResponse](t, mcpClient, "checklist_get", nil)
+	assert.Equal(t, WorkflowModeExplore, resp.Methodology)
+	require.Len(t, resp.Items, len(checklistSeeds[WorkflowModeExplore]))
+	assert.Equal(t, resp.Coverage.Total, resp.Coverage.Untested)
+	assert.Zero(t, resp.Coverage.Percent)
+
+	t.Run("mark_existing", func(t *testing.T) {
+		resp := CallMCPToolJSONOK[protocol.ChecklistResponse](t, mcpClient, "checklist_mark", map[string]interface{}{
+			"item_id": "authz-idor",
+			"status":  "vulnerable",
+			"note":    "replay abc123 reads other user's order",
+		})
+		require.Len(t, resp.Items, 1)
+		assert.Equal(t, protocol.ChecklistVulnerable, resp.Items[0].Status)
+		assert.NotEmpty(t, resp.Items[0].UpdatedAt)
+
+		filtered := CallMCPToolJSONOK[protocol.ChecklistResponse](t, mcpClient, "checklist_get", map[string]interface{}{
+			"status": "vulnerable",
+		})
+		require.Len(t, filtered.Items, 1)
+		assert.Equal(t, "replay abc123 reads other user's order", filtered.Items[0].Note)
+	})
+
+	t.Run("custom_item", func(t *testing.T) {
+		result := CallMCPTool(t, mcpClient, "checklist_mark", map[string]interface{}{
+			"item_id": "graphql-introspection",
+			"status":  "tested",
+		})
+		assert.True(t, result.IsError)
+
+		resp := CallMCPToolJSONOK[protocol.ChecklistResponse](t, mcpClient, "checklist_mark", map[string]interface{}{
+			"item_id":  "graphql-introspection",
+			"status":   "na",
+			"title":    "GraphQL introspection",
+			"category": "recon",
+		})
+		require.Len(t, resp.Items, 1)
+		assert.Equal(t, "recon", resp.Items[0].Category)
+	})
+
+	t.Run("invalid_status", func(t *testing.T) {
+		result := CallMCPTool(t, mcpClient, "checklist_mark", map[string]interface{}{
+			"item_id": "csrf",
+			"status":  "done",
+		})
+		assert.True(t, result.IsError)
+	})
+
+	t.Run("persisted", func(t *testing.T) {
+		_, err := os.Stat(filepath.Join(srv.projectDir, config.ProjectDirName, checklistFileName))
+		require.NoError(t, err)
+
+		list, err := newChecklistStore(srv.projectDir).Get(WorkflowModeAPI)
+		require.NoError(t, err)
+		assert.Equal(t, WorkflowModeExplore, list.Methodology) // existing checklist is not reseeded
+	})
+}
+
+func TestChecklistCoverage(t *testing.T) {
+	t.Parallel()
+
+	cov := checklistCoverage([]protocol.ChecklistItem{
+		{Status: protocol.ChecklistTested},
+		{Status: protocol.ChecklistNA},
+		{Status: protocol.ChecklistVulnerable},
+		{Status: protocol.ChecklistUntested},
+	})
+	assert.Equal(t, protocol.ChecklistCoverage{Total: 4, Untested: 1, Tested: 1, NA: 1, Vulnerable: 1, Percent: 75}, cov)
+}
+
+func TestSeedChecklist(t *testing.T) {
+	t.Parallel()
+
+	for mode, seeds := range checklistSeeds {
+		ids := make(map[string]bool)
+		for _, s := range seeds {
+			assert.False(t, ids[s.id], "%s: duplicate id %s", mode, s.id)
+			ids[s.id] = true
+		}
+	}
+	assert.Equal(t, WorkflowModeExplore, seedChecklist("custom-guide").Methodology)
+}
@@ -0,0 +1,79 @@
+package service
+
+import (
+	"context"
+	"log"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/go-harden/llm-security-toolbox/sectool/protocol"
+)
+
+func (m *mcpServer) cookieJarSetTool() mcp.Tool {
+	return mcp.NewTool("cookie_jar_set",
+		mcp.WithDescription(`Create, change or delete the cookie jar of a host, so multi-step authenticated flows carry cookies without copying headers.
+
+While a host's jar is enabled, replay_send captures Set-Cookie from every response to that host (expired and negative Max-Age cookies are removed) and merges the jar's cookies into the Cookie header of later replays to the host, replacing same-named cookies. Path and Secure are honored.
+The jar applies after the request edits and before session and refresh_csrf; replay_as sends skip it so identities never mix cookies.
+Changes apply in order: clear, remove, cookies. Returns all jars. Jars belong to the calling client (other agents and the CLI have their own), are ephemeral and are cleared on service restart.`),
+		mcp.WithString("host", mcp.Required(), mcp.Description("Hostname of the jar (e.g., 'app.example.com')")),
+		mcp.WithBoolean("enabled", mcp.Description("Capture and attach cookies (default: true for a new jar, else unchanged)")),
+		mcp.WithObject("cookies", mcp.Description(`Cookies to put in the jar with path /: {"session": "abc"}`)),
+		mcp.WithArray("remove", mcp.Items(map[string]interface{}{"type": "string"}), mcp.Description("Cookie names to remove from the jar")),
+		mcp.WithBoolean("clear", mcp.Description("Remove every cookie from the jar")),
+		mcp.WithBoolean("delete", mcp.Description("Delete the jar instead")),
+		annotateLocalChange,
+	)
+}
+
+func (m *mcpServer) cookieJarListTool() mcp.Tool {
+	return mcp.NewTool("cookie_jar_list",
+		mcp.WithDescription("List cookie jars and their cookies for replay_send."),
+		annotateReadOnly,
+	)
+}
+
+func (m *mcpServer) handleCookieJarSet(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if err := m.requireWorkflow(); err != nil {
+		return err, nil
+	}
+
+	host := strings.ToLower(strings.TrimSpace(req.GetString("host", "")))
+	if host == "" {
+		return errorResult("host is required"), nil
+	} else if strings.ContainsAny(host, ":/ ") {
+		return errorResult("host must be a bare hostname (e.g., 'app.example.com')"), nil
+	}
+
+	jars := m.client(ctx).cookieJars
+	if req.GetBool("delete", false) {
+		if !jars.Delete(host) {
+			return errorResult("no cookie jar for " + host), nil
+		}
+		log.Printf("mcp/cookie_jar_set: deleted jar for %s", host)
+		return jsonResult(protocol.CookieJarListResponse{Jars: jars.List()})
+	}
+
+	var enabled *bool
+	if v, ok := req.GetArguments()["enabled"].(bool); ok {
+		enabled = &v
+	}
+	cookies := stringMapArg(req, "cookies")
+	for name := range cookies {
+		if name == "" || strings.ContainsAny(name, "=; \t\r\n") {
+			return errorResult("invalid cookie name " + name), nil
+		}
+	}
+	jars.Update(host, enabled, req.GetBool("clear", false), req.GetStringSlice("remove", nil), cookies)
+
+	log.Printf("mcp/cookie_jar_set: updated jar for %s", host)
+	return jsonResult(protocol.CookieJarListResponse{Jars: jars.List()})
+}
+
+func (m *mcpServer) handleCookieJarList(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if err := m.requireWorkflow(); err != nil {
+		return err, nil
+	}
+	return jsonResult(protocol.CookieJarListResponse{Jars: m.client(ctx).cookieJars.List()})
+}
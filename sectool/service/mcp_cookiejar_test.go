@@ -0,0 +1,79 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/go-harden/llm-security-toolbox/sectool/protocol"
+)
+
+func TestMCP_CookieJar(t *testing.T) {
+	t.Parallel()
+
+	srv, mcpClient, mockMCP, _, _ := setupMCPServerWithMock(t)
+	mockMCP.AddProxyEntry(
+		"GET /account HTTP/1.1\r\nHost: app.example.com\r\nCookie: sid=captured\r\n\r\n",
+		"HTTP/1.1 200 OK\r\n\r\n",
+		"",
+	)
+	sentRequest := func(t *testing.T, resp protocol.ReplaySendResponse) string {
+		t.Helper()
+		entry, ok := srv.requestStore.Get(resp.ReplayID)
+		require.True(t, ok)
+		return string(entry.Request)
+	}
+
+	jars := CallMCPToolJSONOK[protocol.CookieJarListResponse](t, mcpClient, "cookie_jar_set", map[string]interface{}{
+		"host":    "App.Example.com",
+		"cookies": map[string]interface{}{"lang": "en"},
+	})
+	require.Len(t, jars.Jars, 1)
+	assert.Equal(t, protocol.CookieJar{Host: "app.example.com", Enabled: true, Cookies: []protocol.JarCookie{{Name: "lang", Value: "en", Path: "/"}}}, jars.Jars[0])
+
+	mockMCP.SetSendResponse("HttpRequestResponse{httpRequest=GET /account HTTP/1.1, httpResponse=HTTP/1.1 200 OK\r\nSet-Cookie: sid=fresh; Path=/\r\n\r\nok}")
+	resp := CallMCPToolJSONOK[protocol.ReplaySendResponse](t, mcpClient, "replay_send", map[string]interface{}{"flow_id": "last"})
+	assert.Equal(t, []string{"lang"}, resp.JarSent)
+	assert.Equal(t, []string{"sid"}, resp.JarStored)
+	assert.Contains(t, sentRequest(t, resp), "Cookie: sid=captured; lang=en\r\n")
+
+	resp = CallMCPToolJSONOK[protocol.ReplaySendResponse](t, mcpClient, "replay_send", map[string]interface{}{"flow_id": "last"})
+	assert.Equal(t, []string{"lang", "sid"}, resp.JarSent)
+	assert.Empty(t, resp.JarStored)
+	assert.Contains(t, sentRequest(t, resp), "Cookie: sid=fresh; lang=en\r\n")
+
+	t.Run("disabled", func(t *testing.T) {
+		jars := CallMCPToolJSONOK[protocol.CookieJarListResponse](t, mcpClient, "cookie_jar_set", map[string]interface{}{
+			"host": "app.example.com", "enabled": false,
+		})
+		assert.False(t, jars.Jars[0].Enabled)
+		assert.Len(t, jars.Jars[0].Cookies, 2)
+
+		mockMCP.SetSendResponse("HttpRequestResponse{httpRequest=GET /account HTTP/1.1, httpResponse=HTTP/1.1 200 OK\r\nSet-Cookie: sid=other\r\n\r\nok}")
+		resp := CallMCPToolJSONOK[protocol.ReplaySendResponse](t, mcpClient, "replay_send", map[string]interface{}{"flow_id": "last"})
+		assert.Empty(t, resp.JarSent)
+		assert.Empty(t, resp.JarStored)
+		assert.Contains(t, sentRequest(t, resp), "Cookie: sid=captured\r\n")
+
+		list := CallMCPToolJSONOK[protocol.CookieJarListResponse](t, mcpClient, "cookie_jar_list", nil)
+		assert.Equal(t, "fresh", list.Jars[0].Cookies[1].Value)
+	})
+
+	t.Run("validation", func(t *testing.T) {
+		result := CallMCPTool(t, mcpClient, "cookie_jar_set", map[string]interface{}{"host": "https://app.example.com"})
+		assert.True(t, result.IsError)
+		assert.Contains(t, ExtractMCPText(t, result), "bare hostname")
+
+		result = CallMCPTool(t, mcpClient, "cookie_jar_set", map[string]interface{}{"host": "other.test", "delete": true})
+		assert.True(t, result.IsError)
+		assert.Contains(t, ExtractMCPText(t, result), "no cookie jar for other.test")
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		jars := CallMCPToolJSONOK[protocol.CookieJarListResponse](t, mcpClient, "cookie_jar_set", map[string]interface{}{
+			"host": "app.example.com", "delete": true,
+		})
+		assert.Empty(t, jars.Jars)
+	})
+}
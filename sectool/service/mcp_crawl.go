@@ -1,10 +1,16 @@
 package service
 
 import (
+	"cmp"
 	"context"
 	"encoding/base64"
 	"errors"
 	"log"
+	"maps"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/mark3labs/mcp-go/mcp"
@@ -30,7 +36,7 @@ The crawler automatically:
 - Groups similar paths in summary`),
 		mcp.WithString("label", mcp.Description("Optional unique label for easy reference")),
 		mcp.WithString("seed_urls", mcp.Description("Comma-separated list of URLs to start crawling from")),
-		mcp.WithString("seed_flows", mcp.Description("Comma-separated list of proxy flow_ids to use as seeds")),
+		mcp.WithString("seed_flows", mcp.Description("Comma-separated list of proxy flow_ids to use as seeds ('last'/'last-N' accepted)")),
 		mcp.WithString("domains", mcp.Description("Comma-separated list of additional domains to allow")),
 		mcp.WithObject("headers", mcp.Description("Custom headers as object: {\"Name\": \"Value\"}")),
 		mcp.WithNumber("max_depth", mcp.Description("Maximum crawl depth (0 = unlimited)")),
@@ -39,6 +45,7 @@ The crawler automatically:
 		mcp.WithNumber("parallelism", mcp.Description("Number of concurrent requests (default: 2)")),
 		mcp.WithBoolean("include_subdomains", mcp.Description("Include subdomains of seed hosts (default: true)")),
 		mcp.WithBoolean("ignore_robots", mcp.Description("Ignore robots.txt restrictions (default: false)")),
+		annotateSendsTraffic,
 	)
 }
 
@@ -56,7 +63,11 @@ func (m *mcpServer) handleCrawlCreate(ctx context.Context, req mcp.CallToolReque
 	}
 	if seedFlows := req.GetString("seed_flows", ""); seedFlows != "" {
 		for _, f := range parseCommaSeparated(seedFlows) {
-			seeds = append(seeds, CrawlSeed{FlowID: f})
+			flowID, err := m.service.resolveFlowRef(ctx, f)
+			if err != nil {
+				return errorResultFromErr("", err), nil
+			}
+			seeds = append(seeds, CrawlSeed{FlowID: flowID})
 		}
 	}
 
@@ -124,7 +135,8 @@ func (m *mcpServer) crawlSeedTool() mcp.Tool {
 Can only add seeds while session is running.`),
 		mcp.WithString("session_id", mcp.Required(), mcp.Description("Session ID or label")),
 		mcp.WithString("seed_urls", mcp.Description("Comma-separated list of URLs to add")),
-		mcp.WithString("seed_flows", mcp.Description("Comma-separated list of proxy flow_ids to add")),
+		mcp.WithString("seed_flows", mcp.Description("Comma-separated list of proxy flow_ids to add ('last'/'last-N' accepted)")),
+		annotateSendsTraffic,
 	)
 }
 
@@ -146,7 +158,11 @@ func (m *mcpServer) handleCrawlSeed(ctx context.Context, req mcp.CallToolRequest
 	}
 	if seedFlows := req.GetString("seed_flows", ""); seedFlows != "" {
 		for _, f := range parseCommaSeparated(seedFlows) {
-			seeds = append(seeds, CrawlSeed{FlowID: f})
+			flowID, err := m.service.resolveFlowRef(ctx, f)
+			if err != nil {
+				return errorResultFromErr("", err), nil
+			}
+			seeds = append(seeds, CrawlSeed{FlowID: flowID})
 		}
 	}
 
@@ -172,6 +188,7 @@ func (m *mcpServer) crawlStatusTool() mcp.Tool {
 
 Returns progress metrics including URLs visited, queued, errors, and forms discovered.`),
 		mcp.WithString("session_id", mcp.Required(), mcp.Description("Session ID or label")),
+		annotateReadOnly,
 	)
 }
 
@@ -218,7 +235,8 @@ Output modes:
 - "errors": Returns errors encountered during crawling.
 
 Filters apply to summary and flows modes: host/path/exclude_host/exclude_path use glob (*, ?). method/status are comma-separated (status supports ranges like 2XX).
-Incremental (summary/flows): since accepts flow_id, timestamp, or "last". Flows mode only: pagination with limit/offset.`),
+Incremental (summary/flows): since accepts flow_id, timestamp, or "last". Flows mode only: pagination with limit/offset.
+A full flows page includes next_cursor; pass it back as cursor with the same filters to continue (replaces since/offset).`),
 		mcp.WithString("session_id", mcp.Required(), mcp.Description("Session ID or label")),
 		mcp.WithString("output_mode", mcp.Description("Output mode: 'summary' (default), 'flows', 'forms', or 'errors'")),
 		mcp.WithString("host", mcp.Description("Filter by host glob pattern (e.g., '*.example.com')")),
@@ -232,6 +250,8 @@ Incremental (summary/flows): since accepts flow_id, timestamp, or "last". Flows
 		mcp.WithString("since", mcp.Description("flow_id, timestamp (RFC3339, '2006-01-02 15:04:05', '15:04:05'), or 'last' (cursor)")),
 		mcp.WithNumber("limit", mcp.Description("Maximum number of results (default: 100 for flows/forms/errors)")),
 		mcp.WithNumber("offset", mcp.Description("Skip first N results for pagination (flows mode)")),
+		mcp.WithString("cursor", mcp.Description("Flows mode: next_cursor from a previous page")),
+		annotateReadOnly,
 	)
 }
 
@@ -247,6 +267,7 @@ func (m *mcpServer) handleCrawlPoll(ctx context.Context, req mcp.CallToolRequest
 
 	outputMode := req.GetString("output_mode", "summary")
 	limit := req.GetInt("limit", 100)
+	client := m.client(ctx)
 
 	log.Printf("mcp/crawl_poll: mode=%s session=%s (limit=%d)", outputMode, sessionID, limit)
 
@@ -282,6 +303,16 @@ func (m *mcpServer) handleCrawlPoll(ctx context.Context, req mcp.CallToolRequest
 		return jsonResult(protocol.CrawlPollResponse{SessionID: sessionID, Errors: apiErrors})
 
 	case "flows":
+		since, offset := req.GetString("since", ""), req.GetInt("offset", 0)
+		if cursor := req.GetString("cursor", ""); cursor != "" {
+			pos, err := decodeCursor("crawl_poll", cursor)
+			if err != nil {
+				return errorResult(err.Error()), nil
+			}
+			since, offset = pos, 0
+		}
+		since = client.resolveSince(client.crawlLast, sessionID, since)
+
 		opts := CrawlListOptions{
 			Host:         req.GetString("host", ""),
 			PathPattern:  req.GetString("path", ""),
@@ -291,9 +322,9 @@ func (m *mcpServer) handleCrawlPoll(ctx context.Context, req mcp.CallToolRequest
 			ContainsBody: req.GetString("contains_body", ""),
 			ExcludeHost:  req.GetString("exclude_host", ""),
 			ExcludePath:  req.GetString("exclude_path", ""),
-			Since:        req.GetString("since", ""),
+			Since:        since,
 			Limit:        limit,
-			Offset:       req.GetInt("offset", 0),
+			Offset:       offset,
 		}
 
 		flows, err := m.service.crawlerBackend.ListFlows(ctx, sessionID, opts)
@@ -303,6 +334,9 @@ func (m *mcpServer) handleCrawlPoll(ctx context.Context, req mcp.CallToolRequest
 			}
 			return errorResultFromErr("failed to list flows: ", err), nil
 		}
+		if len(flows) > 0 {
+			client.mark(client.crawlLast, sessionID, flows[len(flows)-1].ID)
+		}
 
 		var apiFlows []protocol.CrawlFlow
 		for _, f := range flows {
@@ -317,7 +351,11 @@ func (m *mcpServer) handleCrawlPoll(ctx context.Context, req mcp.CallToolRequest
 				FoundOn:        f.FoundOn,
 			})
 		}
-		return jsonResult(protocol.CrawlPollResponse{SessionID: sessionID, Flows: apiFlows})
+		var nextCursor string
+		if limit > 0 && len(apiFlows) == limit {
+			nextCursor = encodeCursor("crawl_poll", apiFlows[len(apiFlows)-1].FlowID)
+		}
+		return jsonResult(protocol.CrawlPollResponse{SessionID: sessionID, Flows: apiFlows, NextCursor: nextCursor})
 
 	default: // summary
 		// Get status for state and duration
@@ -339,7 +377,7 @@ func (m *mcpServer) handleCrawlPoll(ctx context.Context, req mcp.CallToolRequest
 			ContainsBody: req.GetString("contains_body", ""),
 			ExcludeHost:  req.GetString("exclude_host", ""),
 			ExcludePath:  req.GetString("exclude_path", ""),
-			Since:        req.GetString("since", ""),
+			Since:        client.resolveSince(client.crawlLast, sessionID, req.GetString("since", "")),
 			Limit:        0, // no limit for summary
 		}
 
@@ -347,6 +385,9 @@ func (m *mcpServer) handleCrawlPoll(ctx context.Context, req mcp.CallToolRequest
 		if err != nil {
 			return errorResultFromErr("failed to get flows: ", err), nil
 		}
+		if len(flows) > 0 {
+			client.mark(client.crawlLast, sessionID, flows[len(flows)-1].ID)
+		}
 
 		aggregates := aggregateByTuple(flows, func(f CrawlFlow) (string, string, string, int) {
 			return f.Host, f.Path, f.Method, f.StatusCode
@@ -361,12 +402,210 @@ func (m *mcpServer) handleCrawlPoll(ctx context.Context, req mcp.CallToolRequest
 	}
 }
 
+func (m *mcpServer) crawlResultsTool() mcp.Tool {
+	return mcp.NewTool("crawl_results",
+		mcp.WithDescription(`List the endpoints discovered by crawling, merged across crawl sessions.
+
+Each endpoint is a unique (method, host, path) with numeric IDs and UUIDs in the path replaced by *.
+Visited links, discovered forms and dirbust hits are combined; params lists query parameter and form field names seen for the endpoint.
+Use this as the test surface inventory; flow_id is an example for crawl_get, replay_id a dirbust hit for replay_get.
+Omit session_id to include every session, dirbust hits, and open ports from port_scan as services (host filter applies). host/path use glob (*, ?), path matches without the query string.`),
+		mcp.WithString("session_id", mcp.Description("Session ID or label (default: all sessions)")),
+		mcp.WithString("host", mcp.Description("Filter by host glob pattern (e.g., '*.example.com')")),
+		mcp.WithString("path", mcp.Description("Filter by path glob pattern (e.g., '/api/*')")),
+		mcp.WithString("method", mcp.Description("Filter by HTTP method (comma-separated)")),
+		mcp.WithNumber("limit", mcp.Description("Maximum number of endpoints (default: 200)")),
+		mcp.WithString("cursor", mcp.Description("next_cursor from a previous page")),
+		annotateReadOnly,
+	)
+}
+
+func (m *mcpServer) handleCrawlResults(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if err := m.requireWorkflow(); err != nil {
+		return err, nil
+	}
+
+	sessionID := req.GetString("session_id", "")
+	limit := req.GetInt("limit", 200)
+	var offset int
+	if cursor := req.GetString("cursor", ""); cursor != "" {
+		pos, err := decodeCursor("crawl_results", cursor)
+		if err != nil {
+			return errorResult(err.Error()), nil
+		} else if offset, err = strconv.Atoi(pos); err != nil || offset < 0 {
+			return errorResult("invalid cursor"), nil
+		}
+	}
+
+	log.Printf("mcp/crawl_results: session=%q (limit=%d offset=%d)", sessionID, limit, offset)
+
+	sessionIDs := []string{sessionID}
+	if sessionID == "" {
+		sessions, err := m.service.crawlerBackend.ListSessions(ctx, 0)
+		if err != nil {
+			return errorResultFromErr("failed to list sessions: ", err), nil
+		}
+		sessionIDs = sessionIDs[:0]
+		for _, sess := range sessions {
+			sessionIDs = append(sessionIDs, sess.ID)
+		}
+	}
+
+	var flows []CrawlFlow
+	var forms []DiscoveredForm
+	for _, id := range sessionIDs {
+		sessFlows, err := m.service.crawlerBackend.ListFlows(ctx, id, CrawlListOptions{})
+		if err != nil {
+			if errors.Is(err, ErrNotFound) {
+				return errorResult("session not found"), nil
+			}
+			return errorResultFromErr("failed to list flows: ", err), nil
+		}
+		sessForms, err := m.service.crawlerBackend.ListForms(ctx, id, 0)
+		if err != nil {
+			return errorResultFromErr("failed to list forms: ", err), nil
+		}
+		flows = append(flows, sessFlows...)
+		forms = append(forms, sessForms...)
+	}
+
+	var hits []dirbustEntry
+	var services []protocol.OpenPort
+	host, path := req.GetString("host", ""), req.GetString("path", "")
+	if sessionID == "" {
+		hits = m.service.dirbusts.list()
+		for _, r := range m.service.portScans.list() {
+			if matchesGlob(r.host, host) {
+				services = append(services, openPort(r))
+			}
+		}
+	}
+	endpoints := crawlEndpoints(flows, forms, hits)
+	methods := parseCommaSeparated(req.GetString("method", ""))
+	endpoints = slices.DeleteFunc(endpoints, func(e protocol.CrawlEndpoint) bool {
+		if len(methods) > 0 && !slices.ContainsFunc(methods, func(method string) bool {
+			return strings.EqualFold(method, e.Method)
+		}) {
+			return true
+		}
+		return !matchesGlob(e.Host, host) || !matchesGlob(e.Path, path)
+	})
+
+	if offset > len(endpoints) {
+		offset = len(endpoints)
+	}
+	endpoints = endpoints[offset:]
+	var nextCursor string
+	if limit > 0 && len(endpoints) > limit {
+		endpoints = endpoints[:limit]
+		nextCursor = encodeCursor("crawl_results", strconv.Itoa(offset+limit))
+	}
+
+	return jsonResult(protocol.CrawlResultsResponse{
+		Endpoints:  endpoints,
+		Services:   services,
+		Sessions:   len(sessionIDs),
+		NextCursor: nextCursor,
+	})
+}
+
+// crawlEndpoints merges crawled flows, form actions and dirbust hits into unique endpoints sorted by host, path and method.
+func crawlEndpoints(flows []CrawlFlow, forms []DiscoveredForm, hits []dirbustEntry) []protocol.CrawlEndpoint {
+	type endpointKey struct {
+		method, host, path string
+	}
+	byKey := make(map[endpointKey]*protocol.CrawlEndpoint)
+	params := make(map[endpointKey]map[string]bool)
+	add := func(method, rawURL, source string) (*protocol.CrawlEndpoint, map[string]bool) {
+		u, err := url.Parse(rawURL)
+		if err != nil || u.Host == "" {
+			return nil, nil
+		}
+		path := u.EscapedPath()
+		if path == "" {
+			path = "/"
+		}
+		key := endpointKey{method: strings.ToUpper(method), host: u.Hostname(), path: normalizePath(path)}
+		e := byKey[key]
+		if e == nil {
+			e = &protocol.CrawlEndpoint{Method: key.method, Host: key.host, Path: key.path}
+			byKey[key] = e
+			params[key] = make(map[string]bool)
+		}
+		if !slices.Contains(e.Sources, source) {
+			e.Sources = append(e.Sources, source)
+		}
+		e.Count++
+		for name := range u.Query() {
+			params[key][name] = true
+		}
+		return e, params[key]
+	}
+
+	for _, f := range flows {
+		e, _ := add(f.Method, f.URL, "link")
+		if e == nil {
+			continue
+		}
+		if e.FlowID == "" {
+			e.FlowID = f.ID
+		}
+		if f.StatusCode != 0 && !slices.Contains(e.Status, f.StatusCode) {
+			e.Status = append(e.Status, f.StatusCode)
+		}
+	}
+	for _, form := range forms {
+		method := form.Method
+		if method == "" {
+			method = "GET"
+		}
+		action := form.Action
+		if action == "" {
+			action = form.URL
+		}
+		e, names := add(method, action, "form")
+		if e == nil {
+			continue
+		}
+		e.HasCSRF = e.HasCSRF || form.HasCSRF
+		for _, inp := range form.Inputs {
+			if inp.Name != "" {
+				names[inp.Name] = true
+			}
+		}
+	}
+	for _, hit := range hits {
+		e, _ := add("GET", hit.url, "dirbust")
+		if e == nil {
+			continue
+		}
+		if e.ReplayID == "" {
+			e.ReplayID = hit.replayID
+		}
+		if !slices.Contains(e.Status, hit.status) {
+			e.Status = append(e.Status, hit.status)
+		}
+	}
+
+	result := make([]protocol.CrawlEndpoint, 0, len(byKey))
+	for key, e := range byKey {
+		e.Params = slices.Sorted(maps.Keys(params[key]))
+		slices.Sort(e.Status)
+		result = append(result, *e)
+	}
+	slices.SortFunc(result, func(a, b protocol.CrawlEndpoint) int {
+		return cmp.Or(cmp.Compare(a.Host, b.Host), cmp.Compare(a.Path, b.Path), cmp.Compare(a.Method, b.Method))
+	})
+	return result
+}
+
 func (m *mcpServer) crawlSessionsTool() mcp.Tool {
 	return mcp.NewTool("crawl_sessions",
 		mcp.WithDescription(`List all crawl sessions.
 
 Returns sessions ordered by creation time (most recent first).`),
 		mcp.WithNumber("limit", mcp.Description("Maximum number of sessions to return (0 = all)")),
+		annotateReadOnly,
 	)
 }
 
@@ -403,6 +642,7 @@ func (m *mcpServer) crawlStopTool() mcp.Tool {
 
 In-flight requests are abandoned immediately.`),
 		mcp.WithString("session_id", mcp.Required(), mcp.Description("Session ID or label")),
+		annotateLocalChange,
 	)
 }
 
@@ -434,6 +674,7 @@ func (m *mcpServer) crawlGetTool() mcp.Tool {
 
 Returns the complete request and response for a flow captured during crawling.`),
 		mcp.WithString("flow_id", mcp.Required(), mcp.Description("The flow_id from crawl_poll (output_mode=flows)")),
+		annotateReadOnly,
 	)
 }
 
@@ -238,6 +238,97 @@ func TestMCP_CrawlSeedWithMock(t *testing.T) {
 	assert.Equal(t, queuedBefore+2, statusAfter.URLsQueued)
 }
 
+func TestMCP_CrawlResultsWithMock(t *testing.T) {
+	t.Parallel()
+
+	_, mcpClient, _, _, mockCrawler := setupMCPServerWithMock(t)
+
+	var sessionIDs []string
+	for _, label := range []string{"first", "second"} {
+		resp := CallMCPToolJSONOK[protocol.CrawlCreateResponse](t, mcpClient, "crawl_create", map[string]interface{}{
+			"seed_urls": "https://example.com",
+			"label":     label,
+		})
+		sessionIDs = append(sessionIDs, resp.SessionID)
+	}
+
+	addFlow := func(sessionID, id, rawURL string, status int) {
+		require.NoError(t, mockCrawler.AddFlow(sessionID, CrawlFlow{
+			ID: id, URL: rawURL, Method: "GET", StatusCode: status, DiscoveredAt: time.Now(),
+		}))
+	}
+	addFlow(sessionIDs[0], "f1", "https://example.com/users/1?tab=profile", 200)
+	addFlow(sessionIDs[0], "f2", "https://example.com/users/2", 404)
+	addFlow(sessionIDs[1], "f3", "https://example.com/users/3?sort=asc", 200)
+	addFlow(sessionIDs[1], "f4", "https://api.example.com/v1/items", 200)
+	require.NoError(t, mockCrawler.AddForm(sessionIDs[1], DiscoveredForm{
+		ID:      "form-1",
+		URL:     "https://example.com/login",
+		Action:  "https://example.com/login",
+		Method:  "post",
+		Inputs:  []FormInput{{Name: "username"}, {Name: "password"}, {Name: "csrf"}},
+		HasCSRF: true,
+	}))
+
+	t.Run("all_sessions", func(t *testing.T) {
+		resp := CallMCPToolJSONOK[protocol.CrawlResultsResponse](t, mcpClient, "crawl_results", nil)
+		assert.Equal(t, 2, resp.Sessions)
+		require.Len(t, resp.Endpoints, 3)
+
+		assert.Equal(t, "api.example.com", resp.Endpoints[0].Host)
+
+		login := resp.Endpoints[1]
+		assert.Equal(t, "POST", login.Method)
+		assert.Equal(t, "/login", login.Path)
+		assert.Equal(t, []string{"form"}, login.Sources)
+		assert.Equal(t, []string{"csrf", "password", "username"}, login.Params)
+		assert.True(t, login.HasCSRF)
+
+		users := resp.Endpoints[2]
+		assert.Equal(t, "/users/*", users.Path)
+		assert.Equal(t, 3, users.Count)
+		assert.Equal(t, []int{200, 404}, users.Status)
+		assert.Equal(t, []string{"sort", "tab"}, users.Params)
+		assert.NotEmpty(t, users.FlowID)
+	})
+
+	t.Run("session_filter", func(t *testing.T) {
+		resp := CallMCPToolJSONOK[protocol.CrawlResultsResponse](t, mcpClient, "crawl_results", map[string]interface{}{
+			"session_id": "first",
+		})
+		assert.Equal(t, 1, resp.Sessions)
+		require.Len(t, resp.Endpoints, 1)
+		assert.Equal(t, 2, resp.Endpoints[0].Count)
+	})
+
+	t.Run("filters", func(t *testing.T) {
+		resp := CallMCPToolJSONOK[protocol.CrawlResultsResponse](t, mcpClient, "crawl_results", map[string]interface{}{
+			"host":   "example.com",
+			"method": "get",
+		})
+		require.Len(t, resp.Endpoints, 1)
+		assert.Equal(t, "/users/*", resp.Endpoints[0].Path)
+	})
+
+	t.Run("cursor", func(t *testing.T) {
+		page := CallMCPToolJSONOK[protocol.CrawlResultsResponse](t, mcpClient, "crawl_results", map[string]interface{}{"limit": 2})
+		require.Len(t, page.Endpoints, 2)
+		require.NotEmpty(t, page.NextCursor)
+
+		next := CallMCPToolJSONOK[protocol.CrawlResultsResponse](t, mcpClient, "crawl_results", map[string]interface{}{
+			"limit":  2,
+			"cursor": page.NextCursor,
+		})
+		require.Len(t, next.Endpoints, 1)
+		assert.Empty(t, next.NextCursor)
+	})
+
+	t.Run("unknown_session", func(t *testing.T) {
+		result := CallMCPTool(t, mcpClient, "crawl_results", map[string]interface{}{"session_id": "nope"})
+		assert.True(t, result.IsError)
+	})
+}
+
 func TestMCP_CrawlValidation(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,173 @@
+package service
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/go-harden/llm-security-toolbox/sectool/protocol"
+)
+
+func (m *mcpServer) csrfAnalyzeTool() mcp.Tool {
+	return mcp.NewTool("csrf_analyze",
+		mcp.WithDescription(`Passively find anti-CSRF tokens in proxy history and the state-changing requests that lack one, per host.
+
+Tokens are names matching csrf, xsrf, authenticity_token, RequestVerificationToken, antiforgery or _token.
+Issued by responses in: hidden_input, meta, set_cookie, response_header, response_json (top-level key).
+Sent by requests in: header, form, multipart, json (top-level key), query.
+unprotected lists POST/PUT/PATCH/DELETE endpoints sent with cookies but no token: CSRF candidates to confirm with replay_send.
+suggest holds csrf_set arguments pairing the most sent token with a response that issues it. Nothing is sent.`),
+		mcp.WithString("host", mcp.Description("Only hosts matching this glob")),
+		annotateReadOnly,
+	)
+}
+
+func (m *mcpServer) csrfSetTool() mcp.Tool {
+	return mcp.NewTool("csrf_set",
+		mcp.WithDescription(`Define how replay_send refresh_csrf=true gets a fresh anti-CSRF token for a host.
+
+The token source is a captured or crafted request for the page that issues the token (flow_id, or replay_id from request_send/request_craft) plus one extraction rule; it is sent immediately to validate the rule.
+On refresh, the page is requested with the replay's Cookie and Authorization headers, cookies it sets are merged into the replay, and the token is set as param (form, multipart or JSON body field, else query parameter) and/or header.
+csrf_analyze suggests arguments. Returns all rules. Rules are ephemeral and cleared on service restart.`),
+		mcp.WithString("host", mcp.Description("Hostname whose replays use the rule (default: host of the token page)")),
+		mcp.WithString("flow_id", mcp.Description("Token page request flow_id, or "+recentRefUsage+" proxy entry (exclusive with replay_id)")),
+		mcp.WithString("replay_id", mcp.Description("Token page request replay_id or label (exclusive with flow_id)")),
+		mcp.WithString("target", mcp.Description("Override the token page destination (scheme+host[:port])")),
+		mcp.WithString("extract_field", mcp.Description("Name of the HTML input (value) or meta tag (content) holding the token (e.g., 'csrf_token')")),
+		mcp.WithString("extract_json", mcp.Description("JSON path of the token in the response body")),
+		mcp.WithString("extract_header", mcp.Description("Response header holding the token")),
+		mcp.WithString("extract_cookie", mcp.Description("Cookie set by the response (e.g., 'XSRF-TOKEN')")),
+		mcp.WithString("extract_regex", mcp.Description("Regex over the response headers and body; the first capture group is the token")),
+		mcp.WithString("param", mcp.Description("Request parameter set to the token")),
+		mcp.WithString("header", mcp.Description("Request header set to the token (e.g., 'X-CSRF-Token')")),
+		mcp.WithBoolean("delete", mcp.Description("Delete the rule for host instead")),
+		annotateSendsTraffic,
+	)
+}
+
+func (m *mcpServer) handleCSRFAnalyze(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if err := m.requireWorkflow(); err != nil {
+		return err, nil
+	}
+
+	hostGlob := req.GetString("host", "")
+	entries, err := m.service.fetchAllProxyEntries(ctx)
+	if err != nil {
+		return errorResultFromErr("failed to fetch proxy history: ", err), nil
+	}
+	var matched []flowEntry
+	for _, entry := range entries {
+		if matchesGlob(entry.host, hostGlob) {
+			matched = append(matched, entry)
+		}
+	}
+
+	hosts := analyzeCSRF(matched, m.service.registerFlow)
+	var unprotected int
+	for _, h := range hosts {
+		unprotected += len(h.Unprotected)
+	}
+	log.Printf("mcp/csrf_analyze: %d flows, %d hosts, %d unprotected endpoints", len(matched), len(hosts), unprotected)
+
+	return jsonResult(protocol.CSRFAnalyzeResponse{Hosts: hosts})
+}
+
+func (m *mcpServer) handleCSRFSet(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if err := m.requireWorkflow(); err != nil {
+		return err, nil
+	}
+
+	host := strings.ToLower(strings.TrimSpace(req.GetString("host", "")))
+	if req.GetBool("delete", false) {
+		if host == "" {
+			return errorResult("host is required with delete"), nil
+		} else if !m.service.csrfRules.Delete(host) {
+			return errorResult("no CSRF rule for " + host), nil
+		}
+		log.Printf("mcp/csrf_set: deleted rule for %s", host)
+		return jsonResult(csrfListResponse(m.service.csrfRules.List()))
+	}
+
+	flowID, replayID := req.GetString("flow_id", ""), req.GetString("replay_id", "")
+	if (flowID == "") == (replayID == "") {
+		return errorResult("exactly one of flow_id or replay_id is required for the token page request"), nil
+	}
+
+	var kind, expr string
+	for _, k := range []string{extractField, extractJSON, extractHeader, extractCookie, extractRegex} {
+		if v := req.GetString("extract_"+k, ""); v != "" {
+			if kind != "" {
+				return errorResult("set only one of extract_field, extract_json, extract_header, extract_cookie or extract_regex"), nil
+			}
+			kind, expr = k, v
+		}
+	}
+	if kind == "" {
+		return errorResult("one of extract_field, extract_json, extract_header, extract_cookie or extract_regex is required"), nil
+	}
+	extractor, err := newTokenExtractor(kind, expr)
+	if err != nil {
+		return errorResultFromErr("", err), nil
+	}
+
+	param, header := strings.TrimSpace(req.GetString("param", "")), strings.TrimSpace(req.GetString("header", ""))
+	if param == "" && header == "" {
+		return errorResult("param or header is required to place the token"), nil
+	} else if strings.ContainsAny(header, ": \r\n") {
+		return errorResult("header must be a bare header name (e.g., 'X-CSRF-Token')"), nil
+	}
+
+	rawRequest, baseTarget, base, err := m.service.loadBaseRequest(ctx, flowID, replayID)
+	if err != nil {
+		return errorResultFromErr("", err), nil
+	}
+	targetOverride := req.GetString("target", baseTarget)
+	pageHost, port, usesHTTPS := parseTarget(rawRequest, targetOverride)
+	if host == "" {
+		host = strings.ToLower(pageHost)
+	}
+
+	rule := &csrfRule{
+		Host:      host,
+		Base:      base,
+		Request:   rawRequest,
+		Target:    Target{Hostname: pageHost, Port: port, UsesHTTPS: usesHTTPS},
+		Extractor: extractor,
+		Param:     param,
+		Header:    http.CanonicalHeaderKey(header),
+	}
+	if _, _, err := rule.Fetch(ctx, m.service, nil); err != nil {
+		return errorResultFromErr("", err), nil
+	}
+	m.service.csrfRules.Set(rule)
+
+	log.Printf("mcp/csrf_set: rule for %s fetches from %s (%s)", host, base, extractor)
+	return jsonResult(csrfListResponse(m.service.csrfRules.List()))
+}
+
+func csrfListResponse(rules []*csrfRule) protocol.CSRFListResponse {
+	resp := protocol.CSRFListResponse{Rules: make([]protocol.CSRFRuleInfo, 0, len(rules))}
+	for _, rule := range rules {
+		state := rule.State()
+		_, _, path := extractRequestMeta(string(rule.Request))
+		info := protocol.CSRFRuleInfo{
+			Host:      rule.Host,
+			Source:    rule.Base,
+			URL:       rule.Target.origin() + path,
+			Extract:   rule.Extractor.String(),
+			Param:     rule.Param,
+			Header:    rule.Header,
+			Token:     state.Token,
+			LastError: state.LastErr,
+		}
+		if !state.FetchedAt.IsZero() {
+			info.FetchedAt = state.FetchedAt.UTC().Format(time.RFC3339)
+		}
+		resp.Rules = append(resp.Rules, info)
+	}
+	return resp
+}
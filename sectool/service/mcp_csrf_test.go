@@ -0,0 +1,115 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/go-harden/llm-security-toolbox/sectool/protocol"
+)
+
+func TestMCP_CSRF(t *testing.T) {
+	t.Parallel()
+
+	srv, mcpClient, mockMCP, _, _ := setupMCPServerWithMock(t)
+	tokenPage := func(token string) string {
+		return "HttpRequestResponse{httpRequest=GET /settings HTTP/1.1, httpResponse=HTTP/1.1 200 OK\r\nContent-Type: text/html\r\nSet-Cookie: csrf_seed=" + token + "\r\n\r\n" +
+			`<form><input type="hidden" name="csrf_token" value="` + token + `"></form>}`
+	}
+
+	mockMCP.AddProxyEntry(
+		"GET /settings HTTP/1.1\r\nHost: app.example.com\r\nCookie: sid=1\r\n\r\n",
+		"HTTP/1.1 200 OK\r\nContent-Type: text/html\r\n\r\n<form><input type=\"hidden\" name=\"csrf_token\" value=\"captured\"></form>",
+		"",
+	)
+	mockMCP.AddProxyEntry(
+		"POST /settings HTTP/1.1\r\nHost: app.example.com\r\nCookie: sid=1\r\nContent-Type: application/x-www-form-urlencoded\r\nContent-Length: 41\r\n\r\nemail=a%40example.com&csrf_token=captured",
+		"HTTP/1.1 302 Found\r\nLocation: /settings\r\n\r\n",
+		"",
+	)
+	mockMCP.AddProxyEntry(
+		"POST /account/delete HTTP/1.1\r\nHost: app.example.com\r\nCookie: sid=1\r\n\r\n",
+		"HTTP/1.1 200 OK\r\n\r\n",
+		"",
+	)
+
+	t.Run("analyze", func(t *testing.T) {
+		resp := CallMCPToolJSONOK[protocol.CSRFAnalyzeResponse](t, mcpClient, "csrf_analyze", nil)
+		require.Len(t, resp.Hosts, 1)
+		h := resp.Hosts[0]
+		assert.Equal(t, 2, h.Requests)
+		require.Len(t, h.Tokens, 2)
+		require.Len(t, h.Unprotected, 1)
+		assert.Equal(t, "/account/delete", h.Unprotected[0].Path)
+		assert.Regexp(t, `^flow_id=\S+ extract_field=csrf_token param=csrf_token$`, h.Suggest)
+
+		resp = CallMCPToolJSONOK[protocol.CSRFAnalyzeResponse](t, mcpClient, "csrf_analyze", map[string]interface{}{"host": "other.*"})
+		assert.Empty(t, resp.Hosts)
+	})
+
+	t.Run("refresh", func(t *testing.T) {
+		mockMCP.SetSendResponse(tokenPage("t1"))
+		rules := CallMCPToolJSONOK[protocol.CSRFListResponse](t, mcpClient, "csrf_set", map[string]interface{}{
+			"flow_id":       "last-2",
+			"extract_field": "csrf_token",
+			"param":         "csrf_token",
+		})
+		require.Len(t, rules.Rules, 1)
+		assert.Equal(t, protocol.CSRFRuleInfo{
+			Host:      "app.example.com",
+			Source:    rules.Rules[0].Source,
+			URL:       "https://app.example.com/settings",
+			Extract:   "field:csrf_token",
+			Param:     "csrf_token",
+			Token:     "t1",
+			FetchedAt: rules.Rules[0].FetchedAt,
+		}, rules.Rules[0])
+		assert.NotEmpty(t, rules.Rules[0].FetchedAt)
+
+		mockMCP.SetSendResponse(tokenPage("t2"))
+		mockMCP.SetSendResponse("HttpRequestResponse{httpRequest=POST /settings HTTP/1.1, httpResponse=HTTP/1.1 302 Found\r\nLocation: /settings\r\n\r\n}")
+		sent := CallMCPToolJSONOK[protocol.ReplaySendResponse](t, mcpClient, "replay_send", map[string]interface{}{
+			"flow_id":      "last-1",
+			"refresh_csrf": true,
+		})
+		assert.Equal(t, "t2", sent.CSRFToken)
+		entry, ok := srv.requestStore.Get(sent.ReplayID)
+		require.True(t, ok)
+		assert.Contains(t, string(entry.Request), "Cookie: sid=1; csrf_seed=t2\r\n")
+		assert.Contains(t, string(entry.Request), "email=a%40example.com&csrf_token=t2")
+		assert.NotContains(t, string(entry.Request), "captured")
+	})
+
+	t.Run("fetch_error", func(t *testing.T) {
+		mockMCP.SetSendResponse("HttpRequestResponse{httpRequest=GET /settings HTTP/1.1, httpResponse=HTTP/1.1 302 Found\r\nLocation: /login\r\n\r\n}")
+		result := CallMCPTool(t, mcpClient, "replay_send", map[string]interface{}{"flow_id": "last-1", "refresh_csrf": true})
+		assert.True(t, result.IsError)
+		assert.Contains(t, ExtractMCPText(t, result), "no input or meta tag named csrf_token in token page (status 302)")
+	})
+
+	t.Run("validation", func(t *testing.T) {
+		result := CallMCPTool(t, mcpClient, "csrf_set", map[string]interface{}{"flow_id": "last", "param": "csrf"})
+		assert.True(t, result.IsError)
+		assert.Contains(t, ExtractMCPText(t, result), "extract_field")
+
+		result = CallMCPTool(t, mcpClient, "csrf_set", map[string]interface{}{"flow_id": "last", "extract_field": "csrf"})
+		assert.True(t, result.IsError)
+		assert.Contains(t, ExtractMCPText(t, result), "param or header is required")
+
+		result = CallMCPTool(t, mcpClient, "csrf_set", map[string]interface{}{"flow_id": "last", "extract_field": "csrf", "header": "X-CSRF: x"})
+		assert.True(t, result.IsError)
+		assert.Contains(t, ExtractMCPText(t, result), "bare header name")
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		rules := CallMCPToolJSONOK[protocol.CSRFListResponse](t, mcpClient, "csrf_set", map[string]interface{}{
+			"host": "App.Example.com", "delete": true,
+		})
+		assert.Empty(t, rules.Rules)
+
+		result := CallMCPTool(t, mcpClient, "replay_send", map[string]interface{}{"flow_id": "last-1", "refresh_csrf": true})
+		assert.True(t, result.IsError)
+		assert.Contains(t, ExtractMCPText(t, result), "no CSRF rule for app.example.com")
+	})
+}
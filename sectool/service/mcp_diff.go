@@ -0,0 +1,71 @@
+package service
+
+import (
+	"context"
+	"log"
+	"regexp"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/go-harden/llm-security-toolbox/sectool/protocol"
+)
+
+func (m *mcpServer) replayDiffTool() mcp.Tool {
+	return mcp.NewTool("replay_diff",
+		mcp.WithDescription(`Compare the responses of two replays: status, headers added/removed/changed, and a unified body diff.
+
+Use for authorization and tampering tests: replay as two users, or with and without an edit, then diff.
+Noise is filtered by default: volatile headers (Date, ETag, request IDs, ...) are skipped, and timestamps, UUIDs, CSRF tokens and nonces are replaced with placeholders before comparing.
+Bodies are decoded (chunked, gzip, deflate); JSON is indented so fields diff line by line. The diff shows the filtered text.
+Returns: identical, status {a, b, changed}, headers {added, removed, changed}, body {size_a, size_b, changed, similarity, diff}.`),
+		mcp.WithString("replay_a", mcp.Required(), mcp.Description("Baseline replay_id, label, or "+recentRefUsage)),
+		mcp.WithString("replay_b", mcp.Required(), mcp.Description("Replay to compare, same forms as replay_a")),
+		mcp.WithBoolean("noise_filter", mcp.Description("Filter volatile headers and values (default true)")),
+		mcp.WithArray("ignore_headers", mcp.Items(map[string]interface{}{"type": "string"}), mcp.Description("Extra header names to skip")),
+		mcp.WithArray("ignore_patterns", mcp.Items(map[string]interface{}{"type": "string"}), mcp.Description("Extra regexes (Go RE2) whose matches are ignored in header values and bodies")),
+		annotateReadOnly,
+	)
+}
+
+func (m *mcpServer) handleReplayDiff(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if err := m.requireWorkflow(); err != nil {
+		return err, nil
+	}
+
+	refA, refB := req.GetString("replay_a", ""), req.GetString("replay_b", "")
+	if refA == "" || refB == "" {
+		return errorResult("replay_a and replay_b are required"), nil
+	}
+
+	opts := diffOptions{
+		noise:         req.GetBool("noise_filter", true),
+		ignoreHeaders: req.GetStringSlice("ignore_headers", nil),
+	}
+	for _, pattern := range req.GetStringSlice("ignore_patterns", nil) {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return errorResult("invalid ignore pattern: " + err.Error()), nil
+		}
+		opts.ignorePatterns = append(opts.ignorePatterns, re)
+	}
+
+	var ids [2]string
+	var entries [2]struct{ headers, body []byte }
+	for i, ref := range []string{refA, refB} {
+		id, err := m.service.resolveReplayRef(ref)
+		if err != nil {
+			return errorResultFromErr("", err), nil
+		}
+		entry, ok := m.service.requestStore.Get(id)
+		if !ok {
+			return errorResult("replay not found: replay results are ephemeral and cleared on service restart"), nil
+		}
+		ids[i] = id
+		entries[i].headers, entries[i].body = entry.Headers, entry.Body
+	}
+
+	diff := diffResponses(entries[0].headers, entries[0].body, entries[1].headers, entries[1].body, opts)
+	log.Printf("mcp/replay_diff: %s vs %s identical=%v (similarity=%.2f)", ids[0], ids[1], diff.Identical, diff.Body.Similarity)
+
+	return jsonResult(protocol.ReplayDiffResponse{ReplayA: ids[0], ReplayB: ids[1], ResponseDiff: diff})
+}
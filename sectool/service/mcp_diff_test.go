@@ -0,0 +1,71 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/go-harden/llm-security-toolbox/sectool/protocol"
+	"github.com/go-harden/llm-security-toolbox/sectool/service/store"
+)
+
+func TestMCP_ReplayDiff(t *testing.T) {
+	t.Parallel()
+
+	srv, mcpClient, _, _, _ := setupMCPServerWithMock(t)
+	srv.requestStore.Store("admin", &store.RequestEntry{
+		Headers: []byte("HTTP/1.1 200 OK\r\nContent-Type: application/json\r\nDate: Mon, 02 Jan 2026 10:00:00 GMT\r\n\r\n"),
+		Body:    []byte(`{"user":"alice","role":"admin","ts":"2026-01-02T10:00:00Z"}`),
+	})
+	srv.requestStore.Store("user", &store.RequestEntry{
+		Headers: []byte("HTTP/1.1 200 OK\r\nContent-Type: application/json\r\nDate: Mon, 02 Jan 2026 10:00:05 GMT\r\n\r\n"),
+		Body:    []byte(`{"user":"alice","role":"user","ts":"2026-01-02T10:00:05Z"}`),
+	})
+	srv.requestStore.Store("denied", &store.RequestEntry{
+		Headers: []byte("HTTP/1.1 403 Forbidden\r\nContent-Type: text/plain\r\nX-Reason: role\r\n\r\n"),
+		Body:    []byte("forbidden"),
+	})
+
+	t.Run("body_change", func(t *testing.T) {
+		resp := CallMCPToolJSONOK[protocol.ReplayDiffResponse](t, mcpClient, "replay_diff", map[string]interface{}{
+			"replay_a": "admin",
+			"replay_b": "user",
+		})
+		assert.False(t, resp.Identical)
+		assert.False(t, resp.Status.Changed)
+		assert.Empty(t, resp.Headers.Changed)
+		assert.True(t, resp.Body.Changed)
+		assert.Contains(t, resp.Body.Diff, `-  "role": "admin",`)
+		assert.Contains(t, resp.Body.Diff, `+  "role": "user",`)
+		assert.Contains(t, resp.Body.Diff, `   "ts": "<timestamp>"`)
+	})
+
+	t.Run("ignore_pattern", func(t *testing.T) {
+		resp := CallMCPToolJSONOK[protocol.ReplayDiffResponse](t, mcpClient, "replay_diff", map[string]interface{}{
+			"replay_a":        "admin",
+			"replay_b":        "user",
+			"ignore_patterns": []string{`"role": "\w+"`},
+		})
+		assert.True(t, resp.Identical)
+	})
+
+	t.Run("status_and_headers", func(t *testing.T) {
+		resp := CallMCPToolJSONOK[protocol.ReplayDiffResponse](t, mcpClient, "replay_diff", map[string]interface{}{
+			"replay_a": "admin",
+			"replay_b": "denied",
+		})
+		assert.Equal(t, protocol.StatusDiff{A: 200, B: 403, Changed: true}, resp.Status)
+		assert.Equal(t, []protocol.HeaderValue{{Name: "X-Reason", Value: "role"}}, resp.Headers.Added)
+		require.Len(t, resp.Headers.Changed, 1)
+		assert.Equal(t, "Content-Type", resp.Headers.Changed[0].Name)
+	})
+
+	t.Run("missing", func(t *testing.T) {
+		result := CallMCPTool(t, mcpClient, "replay_diff", map[string]interface{}{
+			"replay_a": "admin",
+			"replay_b": "nope",
+		})
+		assert.True(t, result.IsError)
+	})
+}
@@ -0,0 +1,202 @@
+package service
+
+import (
+	"context"
+	"log"
+	"net"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/go-harden/llm-security-toolbox/sectool/payload"
+	"github.com/go-harden/llm-security-toolbox/sectool/protocol"
+	"github.com/go-harden/llm-security-toolbox/sectool/service/ids"
+	"github.com/go-harden/llm-security-toolbox/sectool/service/store"
+)
+
+func (m *mcpServer) dirbustTool() mcp.Tool {
+	return mcp.NewTool("dirbust",
+		mcp.WithDescription(`Brute-force directories and files under a base URL (ffuf style).
+
+Each directory is first sent random names (one per extension) to fingerprint its not-found response, so soft 404 pages and catch-all redirects are filtered out along with filter_status codes.
+Hits that redirect to the same path with a trailing slash are directories; they are brute-forced in turn while depth < max_depth.
+Paths outside the project scope are skipped, not sent. Hits are stored as replays and listed by crawl_results with source dirbust as they are found.
+Default wordlist: the built-in 'dirs' payload list (see payloads_get).`),
+		mcp.WithString("url", mcp.Required(), mcp.Description("Base directory URL (e.g., 'https://example.com/app/')")),
+		mcp.WithArray("words", mcp.Items(map[string]interface{}{"type": "string"}), mcp.Description("Names to try (default: built-in dirs list)")),
+		mcp.WithString("extensions", mcp.Description("Extensions to also try per word, comma-separated (e.g., 'php,bak')")),
+		mcp.WithString("filter_status", mcp.Description("Status codes never reported, comma-separated (default: 404)")),
+		mcp.WithNumber("max_depth", mcp.Description("Recursion depth into found directories (default 0: base directory only)")),
+		mcp.WithNumber("threads", mcp.Description("Concurrent requests (default 5, max 20)")),
+		mcp.WithNumber("max_requests", mcp.Description("Request budget including not-found fingerprints (default 1000)")),
+		mcp.WithObject("headers", mcp.Description("Headers to send, e.g. {\"Cookie\": \"session=...\"}")),
+		mcp.WithString("timeout", mcp.Description("Per-request timeout (e.g., '30s')")),
+		mcp.WithNumber("rate", mcp.Description(rateParamDescription)),
+		annotateSendsTraffic,
+	)
+}
+
+func (m *mcpServer) handleDirbust(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if err := m.requireWorkflow(); err != nil {
+		return err, nil
+	}
+	ctx, err := applyRunRate(ctx, req)
+	if err != nil {
+		return errorResult(err.Error()), nil
+	}
+
+	rawURL := req.GetString("url", "")
+	if rawURL == "" {
+		return errorResult("url is required"), nil
+	}
+	base, err := parseURLWithDefaultHTTPS(rawURL)
+	if err != nil || base.Hostname() == "" {
+		return errorResult("invalid url: " + rawURL), nil
+	}
+	base.RawQuery, base.Fragment = "", ""
+	root := base.EscapedPath()
+	if !strings.HasSuffix(root, "/") {
+		root += "/"
+	}
+
+	var timeout time.Duration
+	if s := req.GetString("timeout", ""); s != "" {
+		if timeout, err = time.ParseDuration(s); err != nil {
+			return errorResult("invalid timeout duration: " + err.Error()), nil
+		}
+	}
+	filterStatus := []int{404}
+	if s := req.GetString("filter_status", ""); s != "" {
+		filterStatus = filterStatus[:0]
+		for _, code := range parseCommaSeparated(s) {
+			status, err := strconv.Atoi(code)
+			if err != nil {
+				return errorResult("invalid filter_status code: " + code), nil
+			}
+			filterStatus = append(filterStatus, status)
+		}
+	}
+	maxDepth := req.GetInt("max_depth", 0)
+	threads := req.GetInt("threads", defaultDirbustThreads)
+	maxRequests := req.GetInt("max_requests", defaultDirbustMaxRequests)
+	if maxDepth < 0 {
+		return errorResult("max_depth must not be negative"), nil
+	} else if threads < 1 || threads > maxDirbustThreads {
+		return errorResult("threads must be between 1 and " + strconv.Itoa(maxDirbustThreads)), nil
+	} else if maxRequests < 2 {
+		return errorResult("max_requests must be at least 2"), nil
+	}
+
+	words := req.GetStringSlice("words", nil)
+	if len(words) == 0 {
+		list, err := payload.GetList("dirs")
+		if err != nil {
+			return errorResultFromErr("", err), nil
+		}
+		words = list.Payloads
+	}
+
+	target := targetFromURL(base)
+	hostPort := net.JoinHostPort(target.Hostname, strconv.Itoa(target.Port))
+	scope := m.service.projectScope()
+	allowed := func(path string) bool {
+		return (scope == nil || inScope(scope, hostPort, path)) && m.service.guard.Allows(hostPort, path)
+	}
+	if scope != nil && !inScope(scope, hostPort, root) {
+		return errorResult("out of scope: " + base.Host + root + " is outside the project scope (see scope_get)"), nil
+	} else if err := m.service.guard.Check(ctx, hostPort, root); err != nil {
+		return errorResult(err.Error()), nil
+	}
+	headers := stringMapArg(req, "headers")
+
+	send := func(ctx context.Context, path string) (*dirbustProbe, error) {
+		u, err := base.Parse(path)
+		if err != nil {
+			return nil, err
+		}
+		request := buildRawRequest("GET", u, headers, nil)
+		result, err := m.service.sendRequest(ctx, "sectool-dirbust", SendRequestInput{
+			RawRequest: request,
+			Target:     target,
+			Timeout:    timeout,
+		}, nil)
+		if err != nil {
+			return nil, err
+		}
+		probe := &dirbustProbe{path: path, request: request, result: result}
+		probe.status, _ = parseResponseStatus(result.Headers)
+		if locations := parseHeadersToMap(string(result.Headers))["Location"]; len(locations) > 0 {
+			if loc, err := u.Parse(locations[0]); err == nil {
+				probe.location = loc.String()
+			} else {
+				probe.location = locations[0]
+			}
+		}
+		return probe, nil
+	}
+
+	d := newDirbuster(words, parseCommaSeparated(req.GetString("extensions", "")), send)
+	if len(d.words) == 0 {
+		return errorResult("no words to test"), nil
+	}
+	d.allowed = allowed
+	d.filterStatus = filterStatus
+	d.maxDepth = maxDepth
+	d.threads = threads
+	d.maxRequests = maxRequests
+	d.onHit = func(hit dirbustHit) {
+		id := ids.Generate(ids.DefaultLength)
+		hit.probe.replayID = id
+		m.service.requestStore.Store(id, &store.RequestEntry{
+			Request:  hit.probe.request,
+			Target:   target.origin(),
+			Headers:  hit.probe.result.Headers,
+			Body:     hit.probe.result.Body,
+			Duration: hit.probe.result.Duration,
+		})
+		m.service.dirbusts.add(dirbustEntry{
+			url:      target.origin() + hit.probe.path,
+			status:   hit.probe.status,
+			replayID: id,
+		})
+	}
+
+	log.Printf("mcp/dirbust: %d words under %s%s (max_depth=%d threads=%d)", len(d.words), target.origin(), root, maxDepth, threads)
+	if err := d.run(ctx, root); err != nil {
+		return errorResultFromErr("dirbust failed: ", err), nil
+	}
+
+	resp := protocol.DirbustResponse{
+		URL:         target.origin() + root,
+		Tested:      len(d.words),
+		Requests:    d.requests,
+		Directories: d.directories,
+		Hits:        make([]protocol.DirbustHit, 0, len(d.hits)),
+		Skipped:     d.skipped,
+		Errors:      d.errors,
+	}
+	if d.lastErr != nil {
+		resp.LastError = d.lastErr.Error()
+	}
+	if d.stopped {
+		resp.Stopped = "max_requests"
+	}
+	slices.SortFunc(d.hits, func(a, b dirbustHit) int { return strings.Compare(a.probe.path, b.probe.path) })
+	for _, hit := range d.hits {
+		resp.Hits = append(resp.Hits, protocol.DirbustHit{
+			Path:      hit.probe.path,
+			Status:    hit.probe.status,
+			Size:      len(hit.probe.result.Body),
+			Location:  hit.probe.location,
+			Directory: hit.directory,
+			Depth:     hit.depth,
+			ReplayID:  hit.probe.replayID,
+		})
+	}
+
+	log.Printf("mcp/dirbust: %s done after %d requests, %d hits", resp.URL, d.requests, len(resp.Hits))
+	return jsonResult(resp)
+}
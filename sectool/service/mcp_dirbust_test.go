@@ -0,0 +1,74 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/go-harden/llm-security-toolbox/sectool/config"
+	"github.com/go-harden/llm-security-toolbox/sectool/protocol"
+)
+
+func TestMCP_Dirbust(t *testing.T) {
+	t.Parallel()
+
+	srv, mcpClient, mockMCP, _, _ := setupMCPServerWithMock(t)
+
+	respond := func(status, body string) {
+		mockMCP.SetSendResponse("HttpRequestResponse{httpRequest=GET / HTTP/1.1, httpResponse=HTTP/1.1 " + status + "\r\nContent-Type: text/html\r\n\r\n" + body + "}")
+	}
+
+	t.Run("hits", func(t *testing.T) {
+		respond("404 Not Found", "not found") // fingerprint
+		respond("200 OK", "<html>admin panel</html>")
+		respond("404 Not Found", "not found")
+
+		resp := CallMCPToolJSONOK[protocol.DirbustResponse](t, mcpClient, "dirbust", map[string]interface{}{
+			"url":     "https://example.com/app",
+			"words":   []interface{}{"admin", "missing"},
+			"threads": 1,
+		})
+		assert.Equal(t, "https://example.com/app/", resp.URL)
+		assert.Equal(t, 2, resp.Tested)
+		assert.Equal(t, 3, resp.Requests)
+		require.Len(t, resp.Hits, 1)
+		hit := resp.Hits[0]
+		assert.Equal(t, "/app/admin", hit.Path)
+		assert.Equal(t, 200, hit.Status)
+
+		entry, ok := srv.requestStore.Get(hit.ReplayID)
+		require.True(t, ok)
+		assert.Contains(t, string(entry.Request), "GET /app/admin HTTP/1.1")
+
+		results := CallMCPToolJSONOK[protocol.CrawlResultsResponse](t, mcpClient, "crawl_results", nil)
+		require.Len(t, results.Endpoints, 1)
+		e := results.Endpoints[0]
+		assert.Equal(t, "/app/admin", e.Path)
+		assert.Equal(t, []string{"dirbust"}, e.Sources)
+		assert.Equal(t, hit.ReplayID, e.ReplayID)
+	})
+
+	t.Run("out_of_scope", func(t *testing.T) {
+		require.NoError(t, srv.setProjectScope(&config.Scope{Include: []string{"example.com/app/*"}}))
+		t.Cleanup(func() { _ = srv.setProjectScope(nil) })
+
+		result := CallMCPTool(t, mcpClient, "dirbust", map[string]interface{}{"url": "https://example.com/other/"})
+		assert.True(t, result.IsError)
+		assert.Contains(t, ExtractMCPText(t, result), "out of scope")
+	})
+
+	t.Run("validation", func(t *testing.T) {
+		result := CallMCPTool(t, mcpClient, "dirbust", map[string]interface{}{})
+		assert.True(t, result.IsError)
+		result = CallMCPTool(t, mcpClient, "dirbust", map[string]interface{}{"url": "https://example.com/", "threads": 50})
+		assert.True(t, result.IsError)
+		assert.Contains(t, ExtractMCPText(t, result), "threads must be")
+		result = CallMCPTool(t, mcpClient, "dirbust", map[string]interface{}{"url": "https://example.com/", "filter_status": "4xx"})
+		assert.True(t, result.IsError)
+		assert.Contains(t, ExtractMCPText(t, result), "invalid filter_status")
+		result = CallMCPTool(t, mcpClient, "dirbust", map[string]interface{}{"url": "https://example.com/", "rate": -1})
+		assert.True(t, result.IsError)
+		assert.Contains(t, ExtractMCPText(t, result), "rate must be")
+	})
+}
@@ -0,0 +1,81 @@
+package service
+
+import (
+	"context"
+	"log"
+	"net"
+	"net/netip"
+	"slices"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func (m *mcpServer) dnsQueryTool() mcp.Tool {
+	return mcp.NewTool("dns_query",
+		mcp.WithDescription(`Resolve DNS records for a name (A, AAAA, CNAME, MX, TXT, NS) or reverse-resolve an IP address (PTR).
+
+Queries go directly to the system's first resolv.conf nameserver, or to server when set; compare answers from an internal and a public server (e.g., 8.8.8.8) to check split-horizon DNS.
+The CNAME chain is followed and its final target checked for subdomain takeover: takeover.dangling means the target does not resolve (NXDOMAIN); takeover.provider names a hosting service where unclaimed names can be registered. Confirm by requesting the host before recording a finding.
+No traffic reaches the target's web servers. Names outside the project scope are marked out_of_scope.`),
+		mcp.WithString("name", mcp.Required(), mcp.Description("Hostname to resolve, or an IP address for PTR")),
+		mcp.WithArray("types", mcp.Items(map[string]interface{}{"type": "string"}),
+			mcp.Description("Record types: A, AAAA, CNAME, MX, TXT, NS, PTR (default: A, AAAA, CNAME, MX, TXT, NS; PTR for an IP)")),
+		mcp.WithString("server", mcp.Description("DNS server as ip or ip:port (default: system resolver)")),
+		annotateExternalLookup,
+	)
+}
+
+func (m *mcpServer) handleDNSQuery(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if err := m.requireWorkflow(); err != nil {
+		return err, nil
+	}
+
+	in := dnsQueryInput{Name: strings.TrimSpace(req.GetString("name", ""))}
+	if in.Name == "" {
+		return errorResult("name is required"), nil
+	} else if strings.ContainsAny(in.Name, "/ ") {
+		return errorResult("name must be a bare hostname or IP address"), nil
+	}
+	_, ipErr := netip.ParseAddr(in.Name)
+	for _, t := range req.GetStringSlice("types", nil) {
+		t = strings.ToUpper(strings.TrimSpace(t))
+		if _, ok := dnsQueryTypes[t]; !ok {
+			return errorResult("unsupported record type: " + t + " (use A, AAAA, CNAME, MX, TXT, NS or PTR)"), nil
+		} else if !slices.Contains(in.Types, t) {
+			in.Types = append(in.Types, t)
+		}
+	}
+	if len(in.Types) == 0 {
+		if ipErr == nil {
+			in.Types = []string{"PTR"}
+		} else {
+			in.Types = defaultDNSTypes
+		}
+	}
+
+	if server := req.GetString("server", ""); server != "" {
+		if _, _, err := net.SplitHostPort(server); err != nil {
+			server = net.JoinHostPort(strings.Trim(server, "[]"), "53")
+		}
+		in.Server = server
+	} else {
+		server, err := systemDNSServer()
+		if err != nil {
+			return errorResultFromErr("", err), nil
+		}
+		in.Server = server
+	}
+
+	log.Printf("mcp/dns_query: %s %v via %s", in.Name, in.Types, in.Server)
+	resp, err := queryDNS(ctx, in)
+	if err != nil {
+		return errorResultFromErr("dns_query failed: ", err), nil
+	}
+	if scope := m.service.projectScope(); scope != nil && ipErr != nil {
+		resp.OutOfScope = !inScope(scope, resp.Name, "/")
+	}
+
+	log.Printf("mcp/dns_query: %s %s, %d records", resp.Name, resp.Status, len(resp.Records))
+	return jsonResult(resp)
+}
@@ -0,0 +1,51 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/go-harden/llm-security-toolbox/sectool/protocol"
+)
+
+func TestMCP_DNSQuery(t *testing.T) {
+	t.Parallel()
+
+	_, mcpClient, _, _, _ := setupMCPServerWithMock(t)
+	server := newTestDNSServer(t, testDNSZone())
+
+	t.Run("takeover", func(t *testing.T) {
+		resp := CallMCPToolJSONOK[protocol.DNSQueryResponse](t, mcpClient, "dns_query", map[string]interface{}{
+			"name":   "assets.example.com",
+			"types":  []interface{}{"cname"},
+			"server": server,
+		})
+		assert.Equal(t, server, resp.Server)
+		require.Len(t, resp.Records, 1)
+		assert.Equal(t, "CNAME", resp.Records[0].Type)
+		require.NotNil(t, resp.Takeover)
+		assert.True(t, resp.Takeover.Dangling)
+	})
+
+	t.Run("ptr_default", func(t *testing.T) {
+		resp := CallMCPToolJSONOK[protocol.DNSQueryResponse](t, mcpClient, "dns_query", map[string]interface{}{
+			"name":   "203.0.113.10",
+			"server": server,
+		})
+		require.Len(t, resp.Records, 1)
+		assert.Equal(t, "PTR", resp.Records[0].Type)
+	})
+
+	t.Run("validation", func(t *testing.T) {
+		result := CallMCPTool(t, mcpClient, "dns_query", map[string]interface{}{})
+		assert.True(t, result.IsError)
+		assert.Contains(t, ExtractMCPText(t, result), "name is required")
+		result = CallMCPTool(t, mcpClient, "dns_query", map[string]interface{}{"name": "https://example.com/"})
+		assert.True(t, result.IsError)
+		assert.Contains(t, ExtractMCPText(t, result), "bare hostname")
+		result = CallMCPTool(t, mcpClient, "dns_query", map[string]interface{}{"name": "example.com", "types": []interface{}{"SRV"}})
+		assert.True(t, result.IsError)
+		assert.Contains(t, ExtractMCPText(t, result), "unsupported record type")
+	})
+}
@@ -3,10 +3,15 @@ package service
 import (
 	"context"
 	"encoding/base64"
+	"encoding/hex"
 	"html"
 	"net/url"
 
 	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/go-harden/llm-security-toolbox/sectool/digest"
+	"github.com/go-harden/llm-security-toolbox/sectool/payload"
+	"github.com/go-harden/llm-security-toolbox/sectool/protocol"
 )
 
 func (m *mcpServer) encodeURLTool() mcp.Tool {
@@ -14,6 +19,7 @@ func (m *mcpServer) encodeURLTool() mcp.Tool {
 		mcp.WithDescription("URL encode or decode a string."),
 		mcp.WithString("input", mcp.Required(), mcp.Description("String to encode or decode")),
 		mcp.WithBoolean("decode", mcp.Description("Decode instead of encode")),
+		annotateReadOnly,
 	)
 }
 
@@ -22,6 +28,7 @@ func (m *mcpServer) encodeBase64Tool() mcp.Tool {
 		mcp.WithDescription("Base64 encode or decode a string."),
 		mcp.WithString("input", mcp.Required(), mcp.Description("String to encode or decode")),
 		mcp.WithBoolean("decode", mcp.Description("Decode instead of encode")),
+		annotateReadOnly,
 	)
 }
 
@@ -30,8 +37,96 @@ func (m *mcpServer) encodeHTMLTool() mcp.Tool {
 		mcp.WithDescription("HTML entity encode or decode a string."),
 		mcp.WithString("input", mcp.Required(), mcp.Description("String to encode or decode")),
 		mcp.WithBoolean("decode", mcp.Description("Decode instead of encode")),
+		annotateReadOnly,
+	)
+}
+
+func (m *mcpServer) encodeTool() mcp.Tool {
+	return mcp.NewTool("encode",
+		mcp.WithDescription(`Encode or decode a string with a named encoding, e.g. to get a payload past a WAF or filter.
+
+Encodings: url, double-url, base64, base64url (unpadded, as in JWTs), hex, unicode (\uXXXX per character), html, html-decimal (&#60;), html-hex (&#x3c;).`),
+		mcp.WithString("input", mcp.Required(), mcp.Description("String to encode or decode")),
+		mcp.WithString("encoding", mcp.Required(), mcp.Description("Encoding name (see above)")),
+		mcp.WithBoolean("decode", mcp.Description("Decode instead of encode")),
+		annotateReadOnly,
+	)
+}
+
+func (m *mcpServer) hashTool() mcp.Tool {
+	return mcp.NewTool("hash",
+		mcp.WithDescription(`Hash input with md5, sha1, sha256 (default) or sha512.
+
+Returns {algorithm, hex, base64}; set expected to compare against a leaked or observed hash (hex or base64), adding match.`),
+		mcp.WithString("input", mcp.Required(), mcp.Description("Data to hash")),
+		mcp.WithString("algorithm", mcp.Description("md5, sha1, sha256 (default) or sha512")),
+		mcp.WithString("input_encoding", mcp.Description("How input is written: text (default), hex or base64")),
+		mcp.WithString("expected", mcp.Description("Hash to compare against (hex or base64)")),
+		annotateReadOnly,
 	)
 }
+
+func (m *mcpServer) hmacTool() mcp.Tool {
+	return mcp.NewTool("hmac",
+		mcp.WithDescription(`Compute an HMAC of input under key with md5, sha1, sha256 (default) or sha512, e.g. to sign a webhook body or API request.
+
+Returns {algorithm, hex, base64}; set expected to check an observed signature (hex or base64), adding match.`),
+		mcp.WithString("input", mcp.Required(), mcp.Description("Data to sign (e.g., the request body or canonical string)")),
+		mcp.WithString("key", mcp.Required(), mcp.Description("Secret key")),
+		mcp.WithString("algorithm", mcp.Description("md5, sha1, sha256 (default) or sha512")),
+		mcp.WithString("input_encoding", mcp.Description("How input is written: text (default), hex or base64")),
+		mcp.WithString("key_encoding", mcp.Description("How key is written: text (default), hex or base64")),
+		mcp.WithString("expected", mcp.Description("Signature to compare against (hex or base64)")),
+		annotateReadOnly,
+	)
+}
+
+func (m *mcpServer) payloadTransformTool() mcp.Tool {
+	return mcp.NewTool("payload_transform",
+		mcp.WithDescription(`Apply WAF-evasion presets to payloads, returning every distinct variant.
+
+Presets: case (SeLeCt), sql-comment (/**/ spacing, UN/**/ION, /*!UNION*/), whitespace (spaces to %09, %0a, %0d, %0b, %0c, +; already URL-encoded), keyword-split (SELSELECTECT), overlong-utf8 (/ to %c0%af), null-byte (%00 suffix, prefix, SEL%00ECT), or all (default).
+Keywords are SQL, script/XSS, and shell words; variants equal to the original are dropped.
+Returns {variants: [{original, preset, payload}]}; feed the payloads to replay_send one by one and compare responses.`),
+		mcp.WithArray("payloads", mcp.Required(), mcp.Items(map[string]interface{}{"type": "string"}), mcp.Description("Payloads to transform")),
+		mcp.WithArray("presets", mcp.Items(map[string]interface{}{"type": "string"}), mcp.Description("Preset names (default: all)")),
+		annotateReadOnly,
+	)
+}
+
+func (m *mcpServer) mutateTool() mcp.Tool {
+	return mcp.NewTool("mutate",
+		mcp.WithDescription(`Generate WAF-evasion mutations of one payload as a structured list.
+
+Techniques: case (SeLeCt), sql-comment (/**/ spacing, UN/**/ION, /*!UNION*/), whitespace (%09, %0a, %0d, %0b, %0c, +), keyword-split (SELSELECTECT), overlong-utf8 (../ to %c0%ae%c0%ae%c0%af or 3-byte %e0%80%af), null-byte (payload%00, %00payload, SEL%00ECT), or all (default).
+whitespace, overlong-utf8 and null-byte output is already URL-encoded: place it in a query string or form body as is.
+Returns {payload, mutations: [{technique, payload}], payloads}; send payloads one by one with replay_send (or a batch of replay_send calls) and compare responses.`),
+		mcp.WithString("payload", mcp.Required(), mcp.Description("Payload to mutate")),
+		mcp.WithArray("techniques", mcp.Items(map[string]interface{}{"type": "string"}), mcp.Description("Technique names (default: all)")),
+		annotateReadOnly,
+	)
+}
+
+func (m *mcpServer) payloadsListTool() mcp.Tool {
+	return mcp.NewTool("payloads_list",
+		mcp.WithDescription(`List the built-in payload lists: sqli, sqli-time, xss, path-traversal, ssti, crlf, cmdi, params (parameter names for param_discover), dirs (paths for dirbust), subdomains (labels for subdomain_enum and vhost_discover).
+
+Returns {lists: [{name, description, count}]}. Fetch one with payloads_get instead of writing payloads from memory.`),
+		annotateReadOnly,
+	)
+}
+
+func (m *mcpServer) payloadsGetTool() mcp.Tool {
+	return mcp.NewTool("payloads_get",
+		mcp.WithDescription(`Get the payloads of a built-in list (see payloads_list).
+
+Payloads are raw, not URL-encoded, except where encoding is the technique (path-traversal, crlf).
+Returns {name, description, payloads}; feed them to replay_send one by one, or to payload_transform for WAF-evasion variants.`),
+		mcp.WithString("name", mcp.Required(), mcp.Description("List name (e.g., sqli, xss)")),
+		annotateReadOnly,
+	)
+}
+
 func (m *mcpServer) handleEncodeURL(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	input := req.GetString("input", "")
 	if input == "" {
@@ -93,3 +188,135 @@ func (m *mcpServer) handleEncodeHTML(ctx context.Context, req mcp.CallToolReques
 
 	return mcp.NewToolResultText(result), nil
 }
+
+func (m *mcpServer) handleEncode(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	input := req.GetString("input", "")
+	if input == "" {
+		return errorResult("input is required"), nil
+	}
+	enc, err := payload.GetEncoding(req.GetString("encoding", ""))
+	if err != nil {
+		return errorResult(err.Error()), nil
+	}
+
+	if !req.GetBool("decode", false) {
+		return mcp.NewToolResultText(enc.Encode(input)), nil
+	}
+	decoded, err := enc.Decode(input)
+	if err != nil {
+		return errorResult(enc.Name + " decode error: " + err.Error()), nil
+	}
+	return mcp.NewToolResultText(decoded), nil
+}
+
+func (m *mcpServer) handleHash(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	input, err := digest.Decode(req.GetString("input", ""), req.GetString("input_encoding", ""))
+	if err != nil {
+		return errorResult("input: " + err.Error()), nil
+	}
+
+	alg := req.GetString("algorithm", "")
+	sum, err := digest.Sum(alg, input)
+	if err != nil {
+		return errorResult(err.Error()), nil
+	}
+	return digestResult(alg, sum, req.GetString("expected", ""))
+}
+
+func (m *mcpServer) handleHMAC(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	key := req.GetString("key", "")
+	if key == "" {
+		return errorResult("key is required"), nil
+	}
+	keyBytes, err := digest.Decode(key, req.GetString("key_encoding", ""))
+	if err != nil {
+		return errorResult("key: " + err.Error()), nil
+	}
+	input, err := digest.Decode(req.GetString("input", ""), req.GetString("input_encoding", ""))
+	if err != nil {
+		return errorResult("input: " + err.Error()), nil
+	}
+
+	alg := req.GetString("algorithm", "")
+	mac, err := digest.HMAC(alg, keyBytes, input)
+	if err != nil {
+		return errorResult(err.Error()), nil
+	}
+	return digestResult(alg, mac, req.GetString("expected", ""))
+}
+
+func digestResult(alg string, sum []byte, expected string) (*mcp.CallToolResult, error) {
+	resp := protocol.DigestResponse{
+		Algorithm: digest.Name(alg),
+		Hex:       hex.EncodeToString(sum),
+		Base64:    base64.StdEncoding.EncodeToString(sum),
+	}
+	if expected != "" {
+		match := digest.Matches(sum, expected)
+		resp.Match = &match
+	}
+	return jsonResult(resp)
+}
+
+func (m *mcpServer) handlePayloadTransform(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	payloads := req.GetStringSlice("payloads", nil)
+	if len(payloads) == 0 {
+		return errorResult("payloads is required"), nil
+	}
+
+	variants, err := payload.Transform(payloads, req.GetStringSlice("presets", nil))
+	if err != nil {
+		return errorResult(err.Error()), nil
+	}
+
+	resp := protocol.PayloadTransformResponse{Variants: make([]protocol.PayloadVariant, len(variants))}
+	for i, v := range variants {
+		resp.Variants[i] = protocol.PayloadVariant{Original: v.Original, Preset: v.Preset, Payload: v.Payload}
+	}
+	return jsonResult(resp)
+}
+
+func (m *mcpServer) handleMutate(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	input := req.GetString("payload", "")
+	if input == "" {
+		return errorResult("payload is required"), nil
+	}
+
+	variants, err := payload.Transform([]string{input}, req.GetStringSlice("techniques", nil))
+	if err != nil {
+		return errorResult(err.Error()), nil
+	}
+
+	resp := protocol.MutateResponse{
+		Payload:   input,
+		Mutations: make([]protocol.PayloadMutation, len(variants)),
+		Payloads:  make([]string, len(variants)),
+	}
+	for i, v := range variants {
+		resp.Mutations[i] = protocol.PayloadMutation{Technique: v.Preset, Payload: v.Payload}
+		resp.Payloads[i] = v.Payload
+	}
+	return jsonResult(resp)
+}
+
+func (m *mcpServer) handlePayloadsList(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	all := payload.Lists()
+	resp := protocol.PayloadListsResponse{Lists: make([]protocol.PayloadListSummary, len(all))}
+	for i, l := range all {
+		resp.Lists[i] = protocol.PayloadListSummary{Name: l.Name, Description: l.Description, Count: len(l.Payloads)}
+	}
+	return jsonResult(resp)
+}
+
+func (m *mcpServer) handlePayloadsGet(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	name := req.GetString("name", "")
+	if name == "" {
+		return errorResult("name is required"), nil
+	}
+
+	l, err := payload.GetList(name)
+	if err != nil {
+		return errorResult(err.Error()), nil
+	}
+	return jsonResult(protocol.PayloadsGetResponse{Name: l.Name, Description: l.Description, Payloads: l.Payloads})
+}
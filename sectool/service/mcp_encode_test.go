@@ -4,6 +4,9 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/go-harden/llm-security-toolbox/sectool/protocol"
 )
 
 func TestMCP_EncodeURL(t *testing.T) {
@@ -87,6 +90,169 @@ func TestMCP_EncodeHTML(t *testing.T) {
 	})
 }
 
+func TestMCP_PayloadTransform(t *testing.T) {
+	t.Parallel()
+
+	_, mcpClient, _, _, _ := setupMCPServerWithMock(t)
+
+	t.Run("presets", func(t *testing.T) {
+		resp := CallMCPToolJSONOK[protocol.PayloadTransformResponse](t, mcpClient, "payload_transform", map[string]interface{}{
+			"payloads": []string{"' OR 1=1"},
+			"presets":  []string{"keyword-split", "case"},
+		})
+		require.Len(t, resp.Variants, 3)
+		assert.Equal(t, protocol.PayloadVariant{Original: "' OR 1=1", Preset: "keyword-split", Payload: "' OORR 1=1"}, resp.Variants[0])
+		assert.Equal(t, "case", resp.Variants[1].Preset)
+	})
+
+	t.Run("unknown_preset", func(t *testing.T) {
+		result := CallMCPTool(t, mcpClient, "payload_transform", map[string]interface{}{
+			"payloads": []string{"x"},
+			"presets":  []string{"rot13"},
+		})
+		assert.True(t, result.IsError)
+		assert.Contains(t, ExtractMCPText(t, result), "unknown preset")
+	})
+}
+
+func TestMCP_Mutate(t *testing.T) {
+	t.Parallel()
+
+	_, mcpClient, _, _, _ := setupMCPServerWithMock(t)
+
+	t.Run("techniques", func(t *testing.T) {
+		resp := CallMCPToolJSONOK[protocol.MutateResponse](t, mcpClient, "mutate", map[string]interface{}{
+			"payload":    "../etc/passwd",
+			"techniques": []string{"overlong-utf8", "null-byte"},
+		})
+		assert.Equal(t, "../etc/passwd", resp.Payload)
+		require.Len(t, resp.Mutations, 4)
+		assert.Equal(t, protocol.PayloadMutation{Technique: "overlong-utf8", Payload: "%c0%ae%c0%ae%c0%afetc%c0%afpasswd"}, resp.Mutations[0])
+		assert.Equal(t, protocol.PayloadMutation{Technique: "null-byte", Payload: "../etc/passwd%00"}, resp.Mutations[2])
+		assert.Len(t, resp.Payloads, 4)
+		assert.Equal(t, resp.Mutations[3].Payload, resp.Payloads[3])
+	})
+
+	t.Run("validation", func(t *testing.T) {
+		result := CallMCPTool(t, mcpClient, "mutate", map[string]interface{}{})
+		assert.True(t, result.IsError)
+		result = CallMCPTool(t, mcpClient, "mutate", map[string]interface{}{"payload": "x", "techniques": []string{"rot13"}})
+		assert.True(t, result.IsError)
+		assert.Contains(t, ExtractMCPText(t, result), "unknown preset")
+	})
+}
+
+func TestMCP_Payloads(t *testing.T) {
+	t.Parallel()
+
+	_, mcpClient, _, _, _ := setupMCPServerWithMock(t)
+
+	list := CallMCPToolJSONOK[protocol.PayloadListsResponse](t, mcpClient, "payloads_list", map[string]interface{}{})
+	require.NotEmpty(t, list.Lists)
+	assert.Equal(t, "sqli", list.Lists[0].Name)
+	assert.Positive(t, list.Lists[0].Count)
+
+	got := CallMCPToolJSONOK[protocol.PayloadsGetResponse](t, mcpClient, "payloads_get", map[string]interface{}{
+		"name": "ssti",
+	})
+	assert.Equal(t, "ssti", got.Name)
+	assert.Contains(t, got.Payloads, "{{7*7}}")
+
+	result := CallMCPTool(t, mcpClient, "payloads_get", map[string]interface{}{
+		"name": "nope",
+	})
+	assert.True(t, result.IsError)
+	assert.Contains(t, ExtractMCPText(t, result), "unknown payload list")
+}
+
+func TestMCP_Encode(t *testing.T) {
+	t.Parallel()
+
+	_, mcpClient, _, _, _ := setupMCPServerWithMock(t)
+
+	t.Run("encode", func(t *testing.T) {
+		text := CallMCPToolTextOK(t, mcpClient, "encode", map[string]interface{}{
+			"input":    "<svg>",
+			"encoding": "html-hex",
+		})
+		assert.Equal(t, "&#x3c;&#x73;&#x76;&#x67;&#x3e;", text)
+	})
+
+	t.Run("decode", func(t *testing.T) {
+		text := CallMCPToolTextOK(t, mcpClient, "encode", map[string]interface{}{
+			"input":    "..%252Fetc%252Fpasswd",
+			"encoding": "double-url",
+			"decode":   true,
+		})
+		assert.Equal(t, "../etc/passwd", text)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		result := CallMCPTool(t, mcpClient, "encode", map[string]interface{}{"input": "x", "encoding": "rot13"})
+		assert.True(t, result.IsError)
+		assert.Contains(t, ExtractMCPText(t, result), "unknown encoding")
+
+		result = CallMCPTool(t, mcpClient, "encode", map[string]interface{}{"input": "zz", "encoding": "hex", "decode": true})
+		assert.True(t, result.IsError)
+		assert.Contains(t, ExtractMCPText(t, result), "hex decode error")
+	})
+}
+
+func TestMCP_HashHMAC(t *testing.T) {
+	t.Parallel()
+
+	_, mcpClient, _, _, _ := setupMCPServerWithMock(t)
+
+	t.Run("hash", func(t *testing.T) {
+		resp := CallMCPToolJSONOK[protocol.DigestResponse](t, mcpClient, "hash", map[string]interface{}{
+			"input":     "hello",
+			"algorithm": "MD5",
+			"expected":  "5D41402ABC4B2A76B9719D911017C592",
+		})
+		assert.Equal(t, "md5", resp.Algorithm)
+		assert.Equal(t, "5d41402abc4b2a76b9719d911017c592", resp.Hex)
+		assert.Equal(t, "XUFAKrxLKna5cZ2REBfFkg==", resp.Base64)
+		require.NotNil(t, resp.Match)
+		assert.True(t, *resp.Match)
+	})
+
+	t.Run("hash_default_sha256_hex_input", func(t *testing.T) {
+		resp := CallMCPToolJSONOK[protocol.DigestResponse](t, mcpClient, "hash", map[string]interface{}{
+			"input":          "68656c6c6f",
+			"input_encoding": "hex",
+		})
+		assert.Equal(t, "sha256", resp.Algorithm)
+		assert.Equal(t, "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824", resp.Hex)
+		assert.Nil(t, resp.Match)
+	})
+
+	t.Run("hmac", func(t *testing.T) {
+		resp := CallMCPToolJSONOK[protocol.DigestResponse](t, mcpClient, "hmac", map[string]interface{}{
+			"input":        "what do ya want for nothing?",
+			"key":          "SmVmZQ==",
+			"key_encoding": "base64",
+			"expected":     "5bdcc146bf60754e6a042426089575c75a003f089d2739839dec58b964ec3844",
+		})
+		assert.Equal(t, "5bdcc146bf60754e6a042426089575c75a003f089d2739839dec58b964ec3843", resp.Hex)
+		require.NotNil(t, resp.Match)
+		assert.False(t, *resp.Match)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		result := CallMCPTool(t, mcpClient, "hash", map[string]interface{}{"input": "x", "algorithm": "sha3"})
+		assert.True(t, result.IsError)
+		assert.Contains(t, ExtractMCPText(t, result), "unknown algorithm")
+
+		result = CallMCPTool(t, mcpClient, "hmac", map[string]interface{}{"input": "x"})
+		assert.True(t, result.IsError)
+		assert.Contains(t, ExtractMCPText(t, result), "key is required")
+
+		result = CallMCPTool(t, mcpClient, "hmac", map[string]interface{}{"input": "x", "key": "zz", "key_encoding": "hex"})
+		assert.True(t, result.IsError)
+		assert.Contains(t, ExtractMCPText(t, result), "key: hex decode error")
+	})
+}
+
 func TestMCP_EncodeValidation(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,133 @@
+package service
+
+import (
+	"context"
+	"log"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/go-harden/llm-security-toolbox/sectool/protocol"
+)
+
+// envParamDescription documents the env argument of the sending tools.
+const envParamDescription = "Environment whose {{name}} variables are substituted into the arguments and request (default: the active environment, see env_list)"
+
+func (m *mcpServer) envSetTool() mcp.Tool {
+	return mcp.NewTool("env_set",
+		mcp.WithDescription(`Create or update a named environment of request variables (.sectool/environments.json).
+
+replay_send, request_send and request_craft replace {{name}} placeholders in their arguments and in the request with the environment's values, so one request can target dev, staging and prod (e.g., target "{{base_url}}", header "Authorization: Bearer {{token}}").
+Values merge into the existing environment; unset removes keys. Undefined placeholders are left as-is.`),
+		mcp.WithString("name", mcp.Required(), mcp.Description("Environment name (e.g., 'staging')")),
+		mcp.WithObject("vars", mcp.Description(`Variables to set, e.g. {"base_url": "https://staging.example.com", "token": "..."}`)),
+		mcp.WithArray("unset", mcp.Items(map[string]interface{}{"type": "string"}), mcp.Description("Variable names to remove")),
+		mcp.WithBoolean("activate", mcp.Description("Make this the active environment used when env is not given")),
+		mcp.WithBoolean("delete", mcp.Description("Delete the environment instead")),
+		annotateLocalChange,
+	)
+}
+
+func (m *mcpServer) envListTool() mcp.Tool {
+	return mcp.NewTool("env_list",
+		mcp.WithDescription("List request variable environments and the active one."),
+		annotateReadOnly,
+	)
+}
+
+func (m *mcpServer) handleEnvSet(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if err := m.requireWorkflow(); err != nil {
+		return err, nil
+	}
+
+	name := strings.TrimSpace(req.GetString("name", ""))
+	if name == "" {
+		return errorResult("name is required"), nil
+	}
+
+	if req.GetBool("delete", false) {
+		file, err := m.service.envs.Delete(name)
+		if err != nil {
+			return errorResultFromErr("", err), nil
+		}
+		log.Printf("mcp/env_set: deleted environment %s", name)
+		return jsonResult(envListResponse(file))
+	}
+
+	vars := stringMapArg(req, "vars")
+	for key := range vars {
+		if !templateVarRe.MatchString("{{" + key + "}}") {
+			return errorResult("invalid variable name " + key + ": use letters, digits, _, . and -"), nil
+		}
+	}
+	file, err := m.service.envs.Set(name, vars, req.GetStringSlice("unset", nil), req.GetBool("activate", false))
+	if err != nil {
+		return errorResultFromErr("failed to save environment: ", err), nil
+	}
+	log.Printf("mcp/env_set: updated environment %s (%d vars)", name, len(file.Environments[name]))
+	return jsonResult(envListResponse(file))
+}
+
+func (m *mcpServer) handleEnvList(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if err := m.requireWorkflow(); err != nil {
+		return err, nil
+	}
+
+	file, err := m.service.envs.Load()
+	if err != nil {
+		return errorResultFromErr("failed to load environments: ", err), nil
+	}
+	return jsonResult(envListResponse(file))
+}
+
+func envListResponse(file *environmentsFile) protocol.EnvListResponse {
+	resp := protocol.EnvListResponse{
+		Active:       file.Active,
+		Environments: make([]protocol.Environment, 0, len(file.Environments)),
+	}
+	for _, name := range slices.Sorted(maps.Keys(file.Environments)) {
+		resp.Environments = append(resp.Environments, protocol.Environment{
+			Name: name,
+			Vars: file.Environments[name],
+		})
+	}
+	return resp
+}
+
+// applyEnv substitutes the selected environment's variables into every string
+// argument of req and returns the variables (nil without an environment).
+func (m *mcpServer) applyEnv(req mcp.CallToolRequest) (map[string]string, error) {
+	name, vars, err := m.service.envs.Resolve(req.GetString("env", ""))
+	if err != nil || vars == nil {
+		return nil, err
+	}
+
+	args := req.GetArguments()
+	for key, value := range args {
+		if key != "env" {
+			args[key] = expandEnvValue(value, vars)
+		}
+	}
+	log.Printf("mcp: applied environment %s", name)
+	return vars, nil
+}
+
+// expandEnvValue expands placeholders in strings nested in JSON argument values.
+func expandEnvValue(v interface{}, vars map[string]string) interface{} {
+	switch val := v.(type) {
+	case string:
+		s, _ := expandTemplateVars(val, vars)
+		return s
+	case []interface{}:
+		for i := range val {
+			val[i] = expandEnvValue(val[i], vars)
+		}
+	case map[string]interface{}:
+		for k := range val {
+			val[k] = expandEnvValue(val[k], vars)
+		}
+	}
+	return v
+}
@@ -0,0 +1,124 @@
+package service
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/go-harden/llm-security-toolbox/sectool/config"
+	"github.com/go-harden/llm-security-toolbox/sectool/protocol"
+)
+
+func TestExpandTemplateVars(t *testing.T) {
+	t.Parallel()
+
+	vars := map[string]string{"host": "staging.example.com", "token": "abc", "api.key": "k1"}
+	tests := []struct {
+		name, in, want string
+		undefined      []string
+	}{
+		{name: "plain", in: "GET / HTTP/1.1", want: "GET / HTTP/1.1"},
+		{name: "vars", in: "Host: {{host}}\r\nAuthorization: Bearer {{ token }}", want: "Host: staging.example.com\r\nAuthorization: Bearer abc"},
+		{name: "dotted", in: "key={{api.key}}", want: "key=k1"},
+		{name: "undefined_kept", in: "{{host}}/{{missing}}", want: "staging.example.com/{{missing}}", undefined: []string{"missing"}},
+		{name: "ssti_payload_kept", in: "name={{7*7}}&x={{''.__class__}}", want: "name={{7*7}}&x={{''.__class__}}"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, undefined := expandTemplateVars(tc.in, vars)
+			assert.Equal(t, tc.want, got)
+			assert.Equal(t, tc.undefined, undefined)
+		})
+	}
+}
+
+func TestMCP_Env(t *testing.T) {
+	t.Parallel()
+
+	srv, mcpClient, mockMCP, _, _ := setupMCPServerWithMock(t)
+	mockMCP.SetSendResponse("HttpRequestResponse{httpRequest=GET / HTTP/1.1, httpResponse=HTTP/1.1 200 OK\r\n\r\nok}")
+
+	t.Run("set_and_list", func(t *testing.T) {
+		resp := CallMCPToolJSONOK[protocol.EnvListResponse](t, mcpClient, "env_set", map[string]interface{}{
+			"name": "staging",
+			"vars": map[string]interface{}{"base_url": "https://staging.example.com", "token": "stage-token", "old": "x"},
+		})
+		assert.Empty(t, resp.Active)
+		require.Len(t, resp.Environments, 1)
+
+		resp = CallMCPToolJSONOK[protocol.EnvListResponse](t, mcpClient, "env_set", map[string]interface{}{
+			"name":     "staging",
+			"unset":    []interface{}{"old"},
+			"activate": true,
+		})
+		assert.Equal(t, "staging", resp.Active)
+		assert.Equal(t, map[string]string{"base_url": "https://staging.example.com", "token": "stage-token"}, resp.Environments[0].Vars)
+
+		CallMCPToolJSONOK[protocol.EnvListResponse](t, mcpClient, "env_set", map[string]interface{}{
+			"name": "prod",
+			"vars": map[string]interface{}{"base_url": "https://www.example.com", "token": "prod-token"},
+		})
+		list := CallMCPToolJSONOK[protocol.EnvListResponse](t, mcpClient, "env_list", nil)
+		require.Len(t, list.Environments, 2)
+		assert.Equal(t, "prod", list.Environments[0].Name)
+
+		info, err := os.Stat(filepath.Join(srv.projectDir, config.ProjectDirName, environmentsFileName))
+		require.NoError(t, err)
+		assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+	})
+
+	t.Run("request_craft_active_env", func(t *testing.T) {
+		resp := CallMCPToolJSONOK[protocol.ReplaySendResponse](t, mcpClient, "request_craft", map[string]interface{}{
+			"url":     "{{base_url}}/api/me",
+			"headers": map[string]interface{}{"Authorization": "Bearer {{token}}"},
+		})
+		entry, ok := srv.requestStore.Get(resp.ReplayID)
+		require.True(t, ok)
+		assert.Equal(t, "https://staging.example.com", entry.Target)
+		assert.Contains(t, string(entry.Request), "Authorization: Bearer stage-token\r\n")
+	})
+
+	t.Run("replay_send_named_env", func(t *testing.T) {
+		base := CallMCPToolJSONOK[protocol.ReplaySendResponse](t, mcpClient, "request_craft", map[string]interface{}{
+			"raw":    "POST /api/items HTTP/1.1\r\nHost: staging.example.com\r\nContent-Type: application/json\r\n\r\n{\"q\":\"{{7*7}}\"}",
+			"target": "{{base_url}}",
+		})
+
+		resp := CallMCPToolJSONOK[protocol.ReplaySendResponse](t, mcpClient, "replay_send", map[string]interface{}{
+			"replay_id":   base.ReplayID,
+			"env":         "prod",
+			"target":      "{{base_url}}",
+			"add_headers": []interface{}{"Authorization: Bearer {{token}}"},
+		})
+		entry, ok := srv.requestStore.Get(resp.ReplayID)
+		require.True(t, ok)
+		assert.Equal(t, "https://www.example.com", entry.Target)
+		assert.Contains(t, string(entry.Request), "Authorization: Bearer prod-token\r\n")
+		assert.True(t, strings.HasSuffix(string(entry.Request), `{"q":"{{7*7}}"}`))
+	})
+
+	t.Run("unknown_env", func(t *testing.T) {
+		result := CallMCPTool(t, mcpClient, "request_send", map[string]interface{}{
+			"url": "https://example.com/",
+			"env": "qa",
+		})
+		assert.True(t, result.IsError)
+		assert.Contains(t, ExtractMCPText(t, result), `environment "qa" not found`)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		resp := CallMCPToolJSONOK[protocol.EnvListResponse](t, mcpClient, "env_set", map[string]interface{}{
+			"name":   "staging",
+			"delete": true,
+		})
+		assert.Empty(t, resp.Active)
+		require.Len(t, resp.Environments, 1)
+
+		result := CallMCPTool(t, mcpClient, "env_set", map[string]interface{}{"name": "staging", "delete": true})
+		assert.True(t, result.IsError)
+	})
+}
@@ -0,0 +1,256 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/go-harden/llm-security-toolbox/sectool/config"
+	"github.com/go-harden/llm-security-toolbox/sectool/export"
+	"github.com/go-harden/llm-security-toolbox/sectool/protocol"
+	"github.com/go-harden/llm-security-toolbox/sectool/report"
+)
+
+// exportsDirName is the export output directory within config.ProjectDirName.
+const exportsDirName = "exports"
+
+// Export sources.
+const (
+	exportSourceProxy    = "proxy"
+	exportSourceReplay   = "replay"
+	exportSourceFindings = "findings"
+)
+
+func (m *mcpServer) exportTool() mcp.Tool {
+	return mcp.NewTool("export",
+		mcp.WithDescription(`Write proxy history, replays or findings to a file under .sectool/exports/ for CI systems and other security tools.
+
+Sources and formats:
+- proxy: a filtered slice of proxy history as har or jsonl (filters as in proxy_poll; in-scope flows only when a scope is defined)
+- replay: replay results, newest first, as har or jsonl
+- findings: recorded findings as sarif (SARIF 2.1.0, for code scanning dashboards) or jsonl
+HAR imports into browsers, Burp and ZAP; jsonl has one object per line with the raw request and response.
+Returns the file path and entry count, not the content.`),
+		mcp.WithString("source", mcp.Required(), mcp.Description("proxy, replay or findings")),
+		mcp.WithString("format", mcp.Required(), mcp.Description("har, jsonl or sarif")),
+		mcp.WithString("host", mcp.Description("proxy: filter by host (glob pattern)")),
+		mcp.WithString("path", mcp.Description("proxy: filter by path (glob pattern)")),
+		mcp.WithString("method", mcp.Description("proxy: filter by HTTP method(s), comma-separated")),
+		mcp.WithString("status", mcp.Description("proxy: filter by status code(s) or ranges (e.g., '2XX,403')")),
+		mcp.WithString("contains", mcp.Description("proxy: filter by text in URL or headers")),
+		mcp.WithString("contains_body", mcp.Description("proxy: filter by text in request or response body")),
+		mcp.WithString("since", mcp.Description("proxy: entries after flow_id")),
+		mcp.WithString("exclude_host", mcp.Description("proxy: exclude hosts matching glob pattern")),
+		mcp.WithString("exclude_path", mcp.Description("proxy: exclude paths matching glob pattern")),
+		mcp.WithBoolean("in_scope", mcp.Description("proxy: only flows within the project scope (default: true when a scope is defined)")),
+		mcp.WithString("severity", mcp.Description("findings: minimum severity")),
+		mcp.WithString("finding_status", mcp.Description("findings: only draft or confirmed findings")),
+		mcp.WithNumber("limit", mcp.Description("proxy: first N matching flows; replay: newest N replays; findings: most severe N")),
+		annotateLocalChange,
+	)
+}
+
+func (m *mcpServer) handleExport(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if err := m.requireWorkflow(); err != nil {
+		return err, nil
+	}
+
+	source, format := req.GetString("source", ""), req.GetString("format", "")
+	switch {
+	case !slices.Contains([]string{exportSourceProxy, exportSourceReplay, exportSourceFindings}, source):
+		return errorResult("invalid source: use proxy, replay or findings"), nil
+	case !slices.Contains([]string{export.FormatHAR, export.FormatJSONL, export.FormatSARIF}, format):
+		return errorResult("invalid format: use har, jsonl or sarif"), nil
+	case source == exportSourceFindings && format == export.FormatHAR:
+		return errorResult("findings export as sarif or jsonl"), nil
+	case source != exportSourceFindings && format == export.FormatSARIF:
+		return errorResult("sarif is for findings; export " + source + " as har or jsonl"), nil
+	}
+	limit := req.GetInt("limit", 0)
+
+	var buf bytes.Buffer
+	var count int
+	switch source {
+	case exportSourceFindings:
+		findings, err := m.service.findings.List()
+		if err != nil {
+			return errorResultFromErr("failed to read findings: ", err), nil
+		}
+		findings, errResult := filterFindings(findings, req.GetString("severity", ""), req.GetString("finding_status", ""), limit)
+		if errResult != nil {
+			return errResult, nil
+		}
+		count = len(findings)
+		if format == export.FormatSARIF {
+			err = report.Write(&buf, report.FormatSARIF, report.Report{Findings: findings})
+		} else {
+			err = export.WriteJSONL(&buf, findings)
+		}
+		if err != nil {
+			return errorResultFromErr("failed to serialize findings: ", err), nil
+		}
+	default:
+		var exchanges []export.Exchange
+		if source == exportSourceProxy {
+			var errResult *mcp.CallToolResult
+			if exchanges, errResult = m.proxyExchanges(ctx, req, limit); errResult != nil {
+				return errResult, nil
+			}
+		} else {
+			exchanges = m.service.replayExchanges(limit)
+		}
+		count = len(exchanges)
+		var err error
+		if format == export.FormatHAR {
+			err = export.WriteHAR(&buf, exchanges)
+		} else {
+			err = export.WriteExchangesJSONL(&buf, exchanges)
+		}
+		if err != nil {
+			return errorResultFromErr("failed to serialize "+source+": ", err), nil
+		}
+	}
+
+	path, err := m.service.writeExport(source, format, buf.Bytes())
+	if err != nil {
+		return errorResultFromErr("failed to write export: ", err), nil
+	}
+	log.Printf("mcp/export: wrote %d %s entries as %s to %s", count, source, format, path)
+	return jsonResult(protocol.ExportResponse{
+		Path:   path,
+		Source: source,
+		Format: format,
+		Count:  count,
+		Bytes:  buf.Len(),
+	})
+}
+
+// filterFindings orders findings most severe first and applies the export filters.
+func filterFindings(findings []protocol.Finding, minSeverity, status string, limit int) ([]protocol.Finding, *mcp.CallToolResult) {
+	if minSeverity = strings.ToLower(minSeverity); minSeverity == "" {
+		minSeverity = protocol.SeverityInfo
+	} else if !validSeverities[minSeverity] {
+		return nil, errorResult("invalid severity: " + minSeverity + " (use info, low, medium, high, critical)")
+	}
+	report.SortFindings(findings)
+	matched := make([]protocol.Finding, 0, len(findings))
+	for _, f := range findings {
+		if report.SeverityRank(f.Severity) <= report.SeverityRank(minSeverity) && (status == "" || f.Status == status) {
+			matched = append(matched, f)
+		}
+	}
+	if limit > 0 && len(matched) > limit {
+		matched = matched[:limit]
+	}
+	return matched, nil
+}
+
+// proxyExchanges returns the proxy history entries matching the proxy_poll filters in req.
+func (m *mcpServer) proxyExchanges(ctx context.Context, req mcp.CallToolRequest, limit int) ([]export.Exchange, *mcp.CallToolResult) {
+	listReq := &ProxyListRequest{
+		Host:         req.GetString("host", ""),
+		Path:         req.GetString("path", ""),
+		Method:       req.GetString("method", ""),
+		Status:       req.GetString("status", ""),
+		Contains:     req.GetString("contains", ""),
+		ContainsBody: req.GetString("contains_body", ""),
+		Since:        req.GetString("since", ""),
+		ExcludeHost:  req.GetString("exclude_host", ""),
+		ExcludePath:  req.GetString("exclude_path", ""),
+	}
+	scope := m.service.projectScope()
+	if _, set := req.GetArguments()["in_scope"]; set && req.GetBool("in_scope", false) && scope == nil {
+		return nil, errorResult("no project scope defined; set one with scope_set or `sectool config scope --target <url>`")
+	} else if req.GetBool("in_scope", true) {
+		listReq.Scope = scope
+	}
+
+	entries, err := m.service.fetchAllProxyEntries(ctx)
+	if err != nil {
+		return nil, errorResultFromErr("failed to fetch proxy history: ", err)
+	}
+	entries = applyProxyFilters(entries, listReq, m.service.flowStore, m.client(ctx).proxyNextOffset.Load())
+	if limit > 0 && len(entries) > limit {
+		entries = entries[:limit]
+	}
+
+	now := time.Now()
+	exchanges := make([]export.Exchange, 0, len(entries))
+	for _, e := range entries {
+		scheme, port, host := inferSchemeAndPort(e.host)
+		started := extractResponseDate(e.response)
+		if started.IsZero() {
+			started = now
+		}
+		exchanges = append(exchanges, export.Exchange{
+			Kind:     "flow",
+			ID:       m.service.registerFlow(e),
+			URL:      Target{Hostname: host, Port: port, UsesHTTPS: scheme == schemeHTTPS}.origin() + e.path,
+			Request:  []byte(e.request),
+			Response: []byte(e.response),
+			Started:  started,
+		})
+	}
+	return exchanges, nil
+}
+
+// replayExchanges returns the newest limit replays (all when limit is 0), newest first.
+func (s *Server) replayExchanges(limit int) []export.Exchange {
+	ids := s.requestStore.RecentIDs(limit)
+	exchanges := make([]export.Exchange, 0, len(ids))
+	for _, id := range ids {
+		entry, ok := s.requestStore.Get(id)
+		if !ok || len(entry.Request) == 0 {
+			continue // expired since listing, or a saved request without a send
+		}
+		host, port, usesHTTPS := parseTarget(entry.Request, entry.Target)
+		_, _, path := extractRequestMeta(string(entry.Request))
+		exchanges = append(exchanges, export.Exchange{
+			Kind:     "replay",
+			ID:       id,
+			URL:      Target{Hostname: host, Port: port, UsesHTTPS: usesHTTPS}.origin() + path,
+			Request:  entry.Request,
+			Response: append(slices.Clip(entry.Headers), entry.Body...),
+			Started:  entry.CreatedAt,
+			Duration: entry.Duration,
+		})
+	}
+	return exchanges
+}
+
+// writeExport writes data to a new file in .sectool/exports/ and returns its path.
+func (s *Server) writeExport(source, format string, data []byte) (string, error) {
+	dir := filepath.Join(s.projectDir, config.ProjectDirName, exportsDirName)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("create exports directory: %w", err)
+	}
+	base := source + "-" + time.Now().UTC().Format("20060102-150405")
+	for n := 1; ; n++ {
+		name := base
+		if n > 1 {
+			name += "-" + strconv.Itoa(n)
+		}
+		path := filepath.Join(dir, name+"."+format)
+		f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+		if errors.Is(err, os.ErrExist) {
+			continue
+		} else if err != nil {
+			return "", err
+		}
+		if _, err := f.Write(data); err != nil {
+			_ = f.Close()
+			return "", err
+		}
+		return path, f.Close()
+	}
+}
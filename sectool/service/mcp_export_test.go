@@ -0,0 +1,134 @@
+package service
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/go-harden/llm-security-toolbox/sectool/config"
+	"github.com/go-harden/llm-security-toolbox/sectool/protocol"
+)
+
+func TestMCP_Export(t *testing.T) {
+	t.Parallel()
+
+	srv, mcpClient, mockMCP, _, _ := setupMCPServerWithMock(t)
+	mockMCP.AddProxyEntry(
+		"GET /api/users HTTP/1.1\r\nHost: example.com\r\n\r\n",
+		"HTTP/1.1 200 OK\r\nDate: Mon, 02 Mar 2026 10:00:00 GMT\r\nContent-Type: application/json\r\n\r\n[]",
+		"",
+	)
+	mockMCP.AddProxyEntry(
+		"POST /login HTTP/1.1\r\nHost: example.com\r\nContent-Type: application/json\r\n\r\n{\"u\":\"a\"}",
+		"HTTP/1.1 401 Unauthorized\r\n\r\n",
+		"",
+	)
+	mockMCP.SetSendResponse("HttpRequestResponse{httpRequest=GET / HTTP/1.1, httpResponse=HTTP/1.1 200 OK\r\n\r\nreplayed}")
+	sent := CallMCPToolJSONOK[protocol.ReplaySendResponse](t, mcpClient, "replay_send", map[string]interface{}{"flow_id": "last"})
+	CallMCPToolJSONOK[protocol.Finding](t, mcpClient, "finding_add", map[string]interface{}{
+		"title": "Login leaks user existence", "severity": "low", "replay_ids": []interface{}{sent.ReplayID},
+	})
+
+	exportsDir := filepath.Join(srv.projectDir, config.ProjectDirName, exportsDirName)
+	read := func(t *testing.T, resp protocol.ExportResponse) []byte {
+		t.Helper()
+		assert.Equal(t, exportsDir, filepath.Dir(resp.Path))
+		data, err := os.ReadFile(resp.Path)
+		require.NoError(t, err)
+		assert.Len(t, data, resp.Bytes)
+		return data
+	}
+
+	t.Run("proxy_har", func(t *testing.T) {
+		resp := CallMCPToolJSONOK[protocol.ExportResponse](t, mcpClient, "export", map[string]interface{}{
+			"source": "proxy", "format": "har",
+		})
+		assert.Equal(t, 2, resp.Count)
+		assert.Equal(t, ".har", filepath.Ext(resp.Path))
+
+		var har struct {
+			Log struct {
+				Entries []struct {
+					StartedDateTime string `json:"startedDateTime"`
+					Request         struct {
+						URL string `json:"url"`
+					} `json:"request"`
+					Response struct {
+						Status int `json:"status"`
+					} `json:"response"`
+				} `json:"entries"`
+			} `json:"log"`
+		}
+		require.NoError(t, json.Unmarshal(read(t, resp), &har))
+		require.Len(t, har.Log.Entries, 2)
+		assert.Equal(t, "https://example.com/api/users", har.Log.Entries[0].Request.URL)
+		assert.Equal(t, "2026-03-02T10:00:00Z", har.Log.Entries[0].StartedDateTime)
+		assert.Equal(t, 401, har.Log.Entries[1].Response.Status)
+	})
+
+	t.Run("proxy_jsonl_filtered", func(t *testing.T) {
+		resp := CallMCPToolJSONOK[protocol.ExportResponse](t, mcpClient, "export", map[string]interface{}{
+			"source": "proxy", "format": "jsonl", "method": "POST",
+		})
+		assert.Equal(t, 1, resp.Count)
+		lines := strings.Split(strings.TrimSpace(string(read(t, resp))), "\n")
+		require.Len(t, lines, 1)
+		assert.Contains(t, lines[0], `"url":"https://example.com/login"`)
+	})
+
+	t.Run("replay", func(t *testing.T) {
+		resp := CallMCPToolJSONOK[protocol.ExportResponse](t, mcpClient, "export", map[string]interface{}{
+			"source": "replay", "format": "jsonl",
+		})
+		assert.Equal(t, 1, resp.Count)
+		data := string(read(t, resp))
+		assert.Contains(t, data, `"id":"`+sent.ReplayID+`"`)
+		assert.Contains(t, data, "replayed")
+	})
+
+	t.Run("findings_sarif", func(t *testing.T) {
+		resp := CallMCPToolJSONOK[protocol.ExportResponse](t, mcpClient, "export", map[string]interface{}{
+			"source": "findings", "format": "sarif",
+		})
+		assert.Equal(t, 1, resp.Count)
+		var sarif struct {
+			Version string `json:"version"`
+			Runs    []struct {
+				Results []struct {
+					Level string `json:"level"`
+				} `json:"results"`
+			} `json:"runs"`
+		}
+		require.NoError(t, json.Unmarshal(read(t, resp), &sarif))
+		assert.Equal(t, "2.1.0", sarif.Version)
+		require.Len(t, sarif.Runs[0].Results, 1)
+		assert.Equal(t, "note", sarif.Runs[0].Results[0].Level)
+
+		filtered := CallMCPToolJSONOK[protocol.ExportResponse](t, mcpClient, "export", map[string]interface{}{
+			"source": "findings", "format": "jsonl", "severity": "high",
+		})
+		assert.Equal(t, 0, filtered.Count)
+		assert.NotEqual(t, resp.Path, filtered.Path)
+	})
+
+	t.Run("validation", func(t *testing.T) {
+		for name, args := range map[string]map[string]interface{}{
+			"bad_source":    {"source": "crawl", "format": "har"},
+			"bad_format":    {"source": "proxy", "format": "csv"},
+			"findings_har":  {"source": "findings", "format": "har"},
+			"proxy_sarif":   {"source": "proxy", "format": "sarif"},
+			"bad_severity":  {"source": "findings", "format": "jsonl", "severity": "urgent"},
+			"missing_scope": {"source": "proxy", "format": "har", "in_scope": true},
+		} {
+			t.Run(name, func(t *testing.T) {
+				result := CallMCPTool(t, mcpClient, "export", args)
+				assert.True(t, result.IsError)
+			})
+		}
+	})
+}
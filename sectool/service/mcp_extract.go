@@ -0,0 +1,90 @@
+package service
+
+import (
+	"context"
+	"log"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/go-harden/llm-security-toolbox/sectool/protocol"
+)
+
+func (m *mcpServer) responseExtractTool() mcp.Tool {
+	return mcp.NewTool("response_extract",
+		mcp.WithDescription(`Pull values out of stored replay responses with a regex, JSON path or CSS selector, without fetching the full body.
+
+Use to read CSRF tokens, IDs, links or leaked secrets from large responses, then feed them into replay_send.
+Query (exactly one):
+- regex: Go RE2, applied to the body by default (in: headers or all); returns capture group 1 when present, else the whole match
+- json_path: dot notation with [index] and [*] (e.g., "data.items[*].id", "$.token"); non-string values are returned as JSON
+- css: selector over the HTML body (e.g., "input[name=csrf_token]"); returns element text, or the attr value when attr is set
+Bodies are decoded (chunked, gzip, deflate) first. Per replay: matches (up to limit, default 50), total, or error.`),
+		mcp.WithArray("replay_ids", mcp.Required(), mcp.Items(map[string]interface{}{"type": "string"}), mcp.Description("Replay IDs, labels, or "+recentRefUsage)),
+		mcp.WithString("regex", mcp.Description("Regex to match")),
+		mcp.WithString("in", mcp.Description("Regex input: body (default), headers or all")),
+		mcp.WithString("json_path", mcp.Description("JSON path to read")),
+		mcp.WithString("css", mcp.Description("CSS selector to match")),
+		mcp.WithString("attr", mcp.Description("Attribute to return for CSS matches (e.g., value, href)")),
+		mcp.WithNumber("limit", mcp.Description("Maximum matches per replay (default 50)")),
+		annotateReadOnly,
+	)
+}
+
+func (m *mcpServer) handleResponseExtract(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if err := m.requireWorkflow(); err != nil {
+		return err, nil
+	}
+
+	refs := req.GetStringSlice("replay_ids", nil)
+	if len(refs) == 0 {
+		return errorResult("replay_ids is required"), nil
+	}
+	regex, jsonPath, css := req.GetString("regex", ""), req.GetString("json_path", ""), req.GetString("css", "")
+	var set int
+	for _, s := range []string{regex, jsonPath, css} {
+		if s != "" {
+			set++
+		}
+	}
+	if set != 1 {
+		return errorResult("exactly one of regex, json_path or css is required"), nil
+	}
+	var extractor *responseExtractor
+	var err error
+	switch {
+	case regex != "":
+		extractor, err = newRegexExtractor(regex, req.GetString("in", ""))
+	case jsonPath != "":
+		extractor, err = newJSONPathExtractor(jsonPath)
+	default:
+		extractor, err = newCSSExtractor(css, req.GetString("attr", ""))
+	}
+	if err != nil {
+		return errorResultFromErr("", err), nil
+	}
+	limit := req.GetInt("limit", defaultExtractLimit)
+	if limit <= 0 {
+		limit = defaultExtractLimit
+	}
+
+	resp := protocol.ResponseExtractResponse{Results: make([]protocol.ExtractResult, 0, len(refs))}
+	for _, ref := range refs {
+		id, err := m.service.resolveReplayRef(ref)
+		if err != nil {
+			return errorResultFromErr("", err), nil
+		}
+		entry, ok := m.service.requestStore.Get(id)
+		if !ok {
+			return errorResult("replay not found: " + ref + ": replay results are ephemeral and cleared on service restart"), nil
+		}
+		status, _ := parseResponseStatus(entry.Headers)
+		result := protocol.ExtractResult{ReplayID: id, Status: status}
+		if result.Matches, result.Total, err = extractor.Extract(entry.Headers, entry.Body, limit); err != nil {
+			result.Error = err.Error()
+		}
+		resp.Results = append(resp.Results, result)
+	}
+	log.Printf("mcp/response_extract: %d replays queried", len(resp.Results))
+
+	return jsonResult(resp)
+}
@@ -0,0 +1,126 @@
+package service
+
+import (
+	"bytes"
+	"compress/gzip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/go-harden/llm-security-toolbox/sectool/protocol"
+	"github.com/go-harden/llm-security-toolbox/sectool/service/store"
+)
+
+func TestResponseExtractor(t *testing.T) {
+	t.Parallel()
+
+	headers := []byte("HTTP/1.1 200 OK\r\nContent-Type: application/json\r\nX-Request-Id: abc123\r\n\r\n")
+	body := []byte(`{"data":{"items":[{"id":1,"tags":["a"]},{"id":"two"},{"name":"x"}]},"token":"t0k"}`)
+
+	tests := []struct {
+		name    string
+		newFn   func() (*responseExtractor, error)
+		want    []string
+		wantErr bool
+	}{
+		{"json_key", func() (*responseExtractor, error) { return newJSONPathExtractor("$.token") }, []string{"t0k"}, false},
+		{"json_wildcard", func() (*responseExtractor, error) { return newJSONPathExtractor("data.items[*].id") }, []string{"1", "two"}, false},
+		{"json_object", func() (*responseExtractor, error) { return newJSONPathExtractor("data.items[0]") }, []string{`{"id":1,"tags":["a"]}`}, false},
+		{"json_missing", func() (*responseExtractor, error) { return newJSONPathExtractor("nope") }, nil, false},
+		{"regex_group", func() (*responseExtractor, error) { return newRegexExtractor(`"id":"?(\w+)`, "") }, []string{"1", "two"}, false},
+		{"regex_headers", func() (*responseExtractor, error) { return newRegexExtractor(`X-Request-Id: \w+`, "headers") }, []string{"X-Request-Id: abc123"}, false},
+		{"css_not_html", func() (*responseExtractor, error) { return newCSSExtractor("input", "") }, nil, false},
+		{"bad_regex", func() (*responseExtractor, error) { return newRegexExtractor("(", "") }, nil, true},
+		{"bad_in", func() (*responseExtractor, error) { return newRegexExtractor("x", "cookies") }, nil, true},
+		{"bad_css", func() (*responseExtractor, error) { return newCSSExtractor("[[", "") }, nil, true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			e, err := tc.newFn()
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			got, total, err := e.Extract(headers, body, defaultExtractLimit)
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+			assert.Equal(t, len(tc.want), total)
+		})
+	}
+}
+
+func TestMCP_ResponseExtract(t *testing.T) {
+	t.Parallel()
+
+	srv, mcpClient, _, _, _ := setupMCPServerWithMock(t)
+	var gz bytes.Buffer
+	zw := gzip.NewWriter(&gz)
+	_, _ = zw.Write([]byte(`<html><form><input type="hidden" name="csrf_token" value="s3cr3t"><a href="/a">A</a><a href="/b">B</a></form></html>`))
+	require.NoError(t, zw.Close())
+	srv.requestStore.Store("page", &store.RequestEntry{
+		Label:   "login-page",
+		Headers: []byte("HTTP/1.1 200 OK\r\nContent-Type: text/html\r\nContent-Encoding: gzip\r\n\r\n"),
+		Body:    gz.Bytes(),
+	})
+	srv.requestStore.Store("api", &store.RequestEntry{
+		Headers: []byte("HTTP/1.1 201 Created\r\nContent-Type: application/json\r\n\r\n"),
+		Body:    []byte(`{"users":[{"id":7},{"id":8},{"id":9}]}`),
+	})
+
+	t.Run("css_attr", func(t *testing.T) {
+		resp := CallMCPToolJSONOK[protocol.ResponseExtractResponse](t, mcpClient, "response_extract", map[string]interface{}{
+			"replay_ids": []string{"login-page"},
+			"css":        "input[name=csrf_token]",
+			"attr":       "value",
+		})
+		require.Len(t, resp.Results, 1)
+		assert.Equal(t, protocol.ExtractResult{ReplayID: "page", Status: 200, Matches: []string{"s3cr3t"}, Total: 1}, resp.Results[0])
+	})
+
+	t.Run("css_text", func(t *testing.T) {
+		resp := CallMCPToolJSONOK[protocol.ResponseExtractResponse](t, mcpClient, "response_extract", map[string]interface{}{
+			"replay_ids": []string{"page"},
+			"css":        "a",
+		})
+		require.Len(t, resp.Results, 1)
+		assert.Equal(t, []string{"A", "B"}, resp.Results[0].Matches)
+	})
+
+	t.Run("json_limit", func(t *testing.T) {
+		resp := CallMCPToolJSONOK[protocol.ResponseExtractResponse](t, mcpClient, "response_extract", map[string]interface{}{
+			"replay_ids": []string{"api"},
+			"json_path":  "users[*].id",
+			"limit":      2,
+		})
+		require.Len(t, resp.Results, 1)
+		assert.Equal(t, 201, resp.Results[0].Status)
+		assert.Equal(t, []string{"7", "8"}, resp.Results[0].Matches)
+		assert.Equal(t, 3, resp.Results[0].Total)
+	})
+
+	t.Run("per_replay_error", func(t *testing.T) {
+		resp := CallMCPToolJSONOK[protocol.ResponseExtractResponse](t, mcpClient, "response_extract", map[string]interface{}{
+			"replay_ids": []string{"api", "page"},
+			"json_path":  "users[0].id",
+		})
+		require.Len(t, resp.Results, 2)
+		assert.Equal(t, []string{"7"}, resp.Results[0].Matches)
+		assert.Equal(t, "response body is not JSON", resp.Results[1].Error)
+	})
+
+	t.Run("validation", func(t *testing.T) {
+		for name, args := range map[string]map[string]interface{}{
+			"no_ids":     {"regex": "x"},
+			"no_query":   {"replay_ids": []string{"api"}},
+			"two_query":  {"replay_ids": []string{"api"}, "regex": "x", "css": "a"},
+			"bad_regex":  {"replay_ids": []string{"api"}, "regex": "("},
+			"not_found":  {"replay_ids": []string{"missing"}, "regex": "x"},
+			"bad_region": {"replay_ids": []string{"api"}, "regex": "x", "in": "trailers"},
+		} {
+			result := CallMCPTool(t, mcpClient, "response_extract", args)
+			assert.True(t, result.IsError, name)
+		}
+	})
+}
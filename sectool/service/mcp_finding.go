@@ -0,0 +1,249 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/go-harden/llm-security-toolbox/sectool/bundle"
+	"github.com/go-harden/llm-security-toolbox/sectool/protocol"
+	"github.com/go-harden/llm-security-toolbox/sectool/report"
+)
+
+const (
+	defaultFindingLimit = 50
+
+	// findingEvidenceMaxBytes caps each request and response kept as finding evidence.
+	findingEvidenceMaxBytes = 16 * 1024
+)
+
+func (m *mcpServer) findingAddTool() mcp.Tool {
+	return mcp.NewTool("finding_add",
+		mcp.WithDescription(`Record a vulnerability in the project findings (.sectool/findings/<finding_id>.json) for reporting with 'sectool report'.
+
+The requests and responses of flow_ids and replay_ids are copied into the finding as evidence, so it stays reproducible after replays expire or the service restarts.
+Reference the flows and replays that demonstrate the issue, any OAST interactions confirming it, and write steps so a reader can reproduce it without you.`),
+		mcp.WithString("title", mcp.Required(), mcp.Description("Short title (e.g., 'IDOR on GET /api/orders/{id}')")),
+		mcp.WithString("severity", mcp.Required(), mcp.Description("info, low, medium, high or critical")),
+		mcp.WithString("description", mcp.Description("Impact and root cause (Markdown)")),
+		mcp.WithArray("flow_ids", mcp.Items(map[string]interface{}{"type": "string"}), mcp.Description("Proxy or crawler flows evidencing the issue")),
+		mcp.WithArray("replay_ids", mcp.Items(map[string]interface{}{"type": "string"}), mcp.Description("Replays evidencing the issue")),
+		mcp.WithString("oast_id", mcp.Description("OAST session of oast_event_ids")),
+		mcp.WithArray("oast_event_ids", mcp.Items(map[string]interface{}{"type": "string"}), mcp.Description("OAST interactions confirming the issue (requires oast_id)")),
+		mcp.WithArray("steps", mcp.Items(map[string]interface{}{"type": "string"}), mcp.Description("Reproduction steps, in order")),
+		mcp.WithString("status", mcp.Description("confirmed (default) or draft for an unverified lead")),
+		annotateLocalChange,
+	)
+}
+
+func (m *mcpServer) findingListTool() mcp.Tool {
+	return mcp.NewTool("finding_list",
+		mcp.WithDescription("List recorded findings, most severe first. Includes drafts created by confirmed oast_expect expectations; use finding_get for the full record."),
+		mcp.WithString("severity", mcp.Description("Minimum severity: info, low, medium, high or critical")),
+		mcp.WithString("status", mcp.Description("Only findings with this status: draft or confirmed")),
+		mcp.WithNumber("limit", mcp.Description("Max findings to return (default 50)")),
+		annotateReadOnly,
+	)
+}
+
+func (m *mcpServer) findingGetTool() mcp.Tool {
+	return mcp.NewTool("finding_get",
+		mcp.WithDescription("Get a finding with its steps, OAST events and captured request/response evidence."),
+		mcp.WithString("finding_id", mcp.Required(), mcp.Description("Finding ID from finding_add or finding_list")),
+		annotateReadOnly,
+	)
+}
+
+func (m *mcpServer) handleFindingAdd(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if err := m.requireWorkflow(); err != nil {
+		return err, nil
+	}
+
+	title := strings.TrimSpace(req.GetString("title", ""))
+	if title == "" {
+		return errorResult("title is required"), nil
+	}
+	severity := strings.ToLower(req.GetString("severity", ""))
+	if !validSeverities[severity] {
+		return errorResult("invalid severity: " + severity + " (use info, low, medium, high, critical)"), nil
+	}
+	status := req.GetString("status", protocol.FindingStatusConfirmed)
+	if status != protocol.FindingStatusConfirmed && status != protocol.FindingStatusDraft {
+		return errorResult("invalid status: use confirmed or draft"), nil
+	}
+	oastID, eventIDs := req.GetString("oast_id", ""), req.GetStringSlice("oast_event_ids", nil)
+	if len(eventIDs) > 0 && oastID == "" {
+		return errorResult("oast_event_ids requires oast_id"), nil
+	}
+
+	finding := protocol.Finding{
+		Title:       title,
+		Severity:    severity,
+		Status:      status,
+		Description: req.GetString("description", ""),
+		Steps:       req.GetStringSlice("steps", nil),
+	}
+	for _, ref := range req.GetStringSlice("flow_ids", nil) {
+		ev, err := m.service.flowEvidence(ctx, ref)
+		if err != nil {
+			return errorResultFromErr("flow "+ref+": ", err), nil
+		}
+		finding.FlowIDs = append(finding.FlowIDs, ev.Ref)
+		finding.Evidence = append(finding.Evidence, ev)
+	}
+	for _, ref := range req.GetStringSlice("replay_ids", nil) {
+		ev, err := m.service.replayEvidence(ref)
+		if err != nil {
+			return errorResultFromErr("replay "+ref+": ", err), nil
+		}
+		finding.ReplayIDs = append(finding.ReplayIDs, ev.Ref)
+		finding.Evidence = append(finding.Evidence, ev)
+	}
+	for _, eventID := range eventIDs {
+		ev, err := m.service.oastBackend.GetEvent(ctx, oastID, eventID)
+		if err != nil {
+			return errorResultFromErr("OAST event "+eventID+": ", err), nil
+		}
+		finding.OastEvents = append(finding.OastEvents, protocol.FindingOastEvent{
+			OastID:    oastID,
+			EventID:   ev.ID,
+			Type:      ev.Type,
+			SourceIP:  ev.SourceIP,
+			Subdomain: ev.Subdomain,
+			Time:      ev.Time.UTC().Format(time.RFC3339),
+		})
+	}
+
+	finding, err := m.service.findings.Add(finding)
+	if err != nil {
+		return errorResultFromErr("failed to save finding: ", err), nil
+	}
+	log.Printf("mcp/finding_add: saved %s (%s) with %d evidence items", finding.FindingID, severity, len(finding.Evidence))
+	return jsonResult(finding)
+}
+
+func (m *mcpServer) handleFindingList(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if err := m.requireWorkflow(); err != nil {
+		return err, nil
+	}
+
+	minSeverity := strings.ToLower(req.GetString("severity", protocol.SeverityInfo))
+	if !validSeverities[minSeverity] {
+		return errorResult("invalid severity: " + minSeverity + " (use info, low, medium, high, critical)"), nil
+	}
+	status := req.GetString("status", "")
+	limit := req.GetInt("limit", defaultFindingLimit)
+
+	findings, err := m.service.findings.List()
+	if err != nil {
+		return errorResultFromErr("failed to read findings: ", err), nil
+	}
+	report.SortFindings(findings)
+
+	resp := protocol.FindingListResponse{Findings: []protocol.FindingSummary{}}
+	for _, f := range findings {
+		if report.SeverityRank(f.Severity) > report.SeverityRank(minSeverity) || (status != "" && f.Status != status) {
+			continue
+		}
+		resp.Total++
+		if limit > 0 && len(resp.Findings) >= limit {
+			continue
+		}
+		resp.Findings = append(resp.Findings, protocol.FindingSummary{
+			FindingID: f.FindingID,
+			Title:     f.Title,
+			Severity:  f.Severity,
+			Status:    f.Status,
+			Evidence:  len(f.Evidence),
+			CreatedAt: f.CreatedAt,
+		})
+	}
+	return jsonResult(resp)
+}
+
+func (m *mcpServer) handleFindingGet(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if err := m.requireWorkflow(); err != nil {
+		return err, nil
+	}
+
+	id := req.GetString("finding_id", "")
+	if id == "" {
+		return errorResult("finding_id is required"), nil
+	}
+	finding, err := m.service.findings.Get(id)
+	if errors.Is(err, ErrNotFound) {
+		return errorResult("finding " + id + " not found"), nil
+	} else if err != nil {
+		return errorResultFromErr("failed to read finding: ", err), nil
+	}
+	return jsonResult(finding)
+}
+
+// flowEvidence captures a proxy or crawler flow as finding evidence.
+func (s *Server) flowEvidence(ctx context.Context, ref string) (protocol.FindingEvidence, error) {
+	flowID, err := s.resolveFlowRef(ctx, ref)
+	if err != nil {
+		return protocol.FindingEvidence{}, err
+	}
+	if entry, ok := s.flowStore.Lookup(flowID); ok {
+		proxyEntries, err := s.httpBackend.GetProxyHistory(ctx, 1, entry.Offset)
+		if err != nil {
+			return protocol.FindingEvidence{}, fmt.Errorf("failed to fetch flow: %w", err)
+		} else if len(proxyEntries) == 0 {
+			return protocol.FindingEvidence{}, errors.New("flow not found in proxy history")
+		}
+		return newFindingEvidence(flowID, "flow", []byte(proxyEntries[0].Request), []byte(proxyEntries[0].Response), ""), nil
+	} else if flow, err := s.crawlerBackend.GetFlow(ctx, flowID); err == nil && flow != nil {
+		return newFindingEvidence(flowID, "flow", flow.Request, flow.Response, ""), nil
+	}
+	return protocol.FindingEvidence{}, errors.New("flow_id not found: run proxy_poll or crawl_poll to see available flows")
+}
+
+// replayEvidence captures a replay as finding evidence.
+func (s *Server) replayEvidence(ref string) (protocol.FindingEvidence, error) {
+	replayID, err := s.resolveReplayRef(ref)
+	if err != nil {
+		return protocol.FindingEvidence{}, err
+	}
+	entry, ok := s.requestStore.Get(replayID)
+	if !ok || len(entry.Request) == 0 {
+		return protocol.FindingEvidence{}, errors.New("replay not found: replay results are ephemeral and cleared on service restart")
+	}
+	return newFindingEvidence(replayID, "replay", entry.Request, append(entry.Headers, entry.Body...), entry.Target), nil
+}
+
+func newFindingEvidence(ref, kind string, request, response []byte, target string) protocol.FindingEvidence {
+	method, _, path := extractRequestMeta(string(request))
+	host, port, usesHTTPS := parseTarget(request, target)
+	respHeaders, _ := splitHeadersBody(response)
+	status, _ := parseResponseStatus(respHeaders)
+	return protocol.FindingEvidence{
+		Ref:      ref,
+		Kind:     kind,
+		Method:   method,
+		URL:      Target{Hostname: host, Port: port, UsesHTTPS: usesHTTPS}.origin() + path,
+		Status:   status,
+		Request:  evidenceText(request),
+		Response: evidenceText(response),
+	}
+}
+
+// evidenceText renders a raw HTTP message for a finding: body decoded, binary bodies
+// replaced by a placeholder, and the middle elided past findingEvidenceMaxBytes.
+func evidenceText(raw []byte) string {
+	if len(raw) == 0 {
+		return ""
+	}
+	headers, body := splitHeadersBody(raw)
+	body, _ = bundle.DecodeBody(string(headers), body)
+	if !utf8.Valid(body) {
+		body = []byte(fmt.Sprintf("<BINARY:%d Bytes>", len(body)))
+	}
+	return elideMiddle(string(headers)+string(body), findingEvidenceMaxBytes)
+}
@@ -0,0 +1,134 @@
+package service
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/go-harden/llm-security-toolbox/sectool/protocol"
+)
+
+func TestEvidenceText(t *testing.T) {
+	t.Parallel()
+
+	t.Run("text", func(t *testing.T) {
+		raw := "HTTP/1.1 200 OK\r\nContent-Type: text/plain\r\n\r\nhello"
+		assert.Equal(t, raw, evidenceText([]byte(raw)))
+	})
+
+	t.Run("binary", func(t *testing.T) {
+		got := evidenceText([]byte("HTTP/1.1 200 OK\r\n\r\n\xff\xfe\x00\x01"))
+		assert.Equal(t, "HTTP/1.1 200 OK\r\n\r\n<BINARY:4 Bytes>", got)
+	})
+
+	t.Run("elided", func(t *testing.T) {
+		got := evidenceText([]byte("HTTP/1.1 200 OK\r\n\r\n" + strings.Repeat("a", 3*findingEvidenceMaxBytes)))
+		assert.Less(t, len(got), 2*findingEvidenceMaxBytes)
+		assert.Contains(t, got, "bytes elided")
+	})
+}
+
+func TestMCP_Findings(t *testing.T) {
+	t.Parallel()
+
+	srv, mcpClient, mockMCP, mockOast, _ := setupMCPServerWithMock(t)
+	mockMCP.AddProxyEntry(
+		"GET /api/orders/7 HTTP/1.1\r\nHost: example.com\r\nAuthorization: Bearer bob\r\n\r\n",
+		"HTTP/1.1 200 OK\r\nContent-Type: application/json\r\n\r\n{\"id\":7,\"owner\":\"alice\"}",
+		"",
+	)
+	mockMCP.SetSendResponse("HttpRequestResponse{httpRequest=GET / HTTP/1.1, httpResponse=HTTP/1.1 200 OK\r\n\r\n{\"id\":8,\"owner\":\"carol\"}}")
+	sent := CallMCPToolJSONOK[protocol.ReplaySendResponse](t, mcpClient, "replay_send", map[string]interface{}{
+		"flow_id": "last",
+		"path":    "/api/orders/8",
+	})
+
+	sess := CallMCPToolJSONOK[protocol.OastCreateResponse](t, mcpClient, "oast_create", nil)
+	mockOast.events[sess.OastID] = []OastEventInfo{{
+		ID:        "ev1",
+		Time:      time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+		Type:      "http",
+		SourceIP:  "203.0.113.9",
+		Subdomain: "idor." + sess.Domain,
+	}}
+
+	added := CallMCPToolJSONOK[protocol.Finding](t, mcpClient, "finding_add", map[string]interface{}{
+		"title":          "IDOR on GET /api/orders/{id}",
+		"severity":       "High",
+		"description":    "Any user can read any order.",
+		"flow_ids":       []interface{}{"last"},
+		"replay_ids":     []interface{}{sent.ReplayID},
+		"oast_id":        sess.OastID,
+		"oast_event_ids": []interface{}{"ev1"},
+		"steps":          []interface{}{"Log in as bob", "GET /api/orders/8"},
+	})
+	assert.NotEmpty(t, added.FindingID)
+	assert.Equal(t, protocol.SeverityHigh, added.Severity)
+	assert.Equal(t, protocol.FindingStatusConfirmed, added.Status)
+	require.Len(t, added.Evidence, 2)
+	assert.Equal(t, "flow", added.Evidence[0].Kind)
+	assert.Equal(t, "https://example.com/api/orders/7", added.Evidence[0].URL)
+	assert.Equal(t, 200, added.Evidence[0].Status)
+	assert.Contains(t, added.Evidence[0].Response, `"owner":"alice"`)
+	assert.Equal(t, "replay", added.Evidence[1].Kind)
+	assert.Equal(t, sent.ReplayID, added.Evidence[1].Ref)
+	assert.Contains(t, added.Evidence[1].Request, "GET /api/orders/8 ")
+	require.Len(t, added.OastEvents, 1)
+	assert.Equal(t, "203.0.113.9", added.OastEvents[0].SourceIP)
+
+	CallMCPToolJSONOK[protocol.Finding](t, mcpClient, "finding_add", map[string]interface{}{
+		"title":    "Verbose server banner",
+		"severity": "info",
+		"status":   "draft",
+	})
+
+	t.Run("get", func(t *testing.T) {
+		got := CallMCPToolJSONOK[protocol.Finding](t, mcpClient, "finding_get", map[string]interface{}{"finding_id": added.FindingID})
+		assert.Equal(t, added, got)
+
+		// Evidence survives the replay being evicted
+		srv.requestStore.Delete(sent.ReplayID)
+		got = CallMCPToolJSONOK[protocol.Finding](t, mcpClient, "finding_get", map[string]interface{}{"finding_id": added.FindingID})
+		assert.Len(t, got.Evidence, 2)
+
+		result := CallMCPTool(t, mcpClient, "finding_get", map[string]interface{}{"finding_id": "nope"})
+		assert.True(t, result.IsError)
+		assert.Contains(t, ExtractMCPText(t, result), "not found")
+	})
+
+	t.Run("list", func(t *testing.T) {
+		resp := CallMCPToolJSONOK[protocol.FindingListResponse](t, mcpClient, "finding_list", nil)
+		require.Len(t, resp.Findings, 2)
+		assert.Equal(t, added.FindingID, resp.Findings[0].FindingID)
+		assert.Equal(t, 2, resp.Findings[0].Evidence)
+
+		resp = CallMCPToolJSONOK[protocol.FindingListResponse](t, mcpClient, "finding_list", map[string]interface{}{"severity": "medium"})
+		assert.Len(t, resp.Findings, 1)
+
+		resp = CallMCPToolJSONOK[protocol.FindingListResponse](t, mcpClient, "finding_list", map[string]interface{}{"status": "draft"})
+		require.Len(t, resp.Findings, 1)
+		assert.Equal(t, "Verbose server banner", resp.Findings[0].Title)
+
+		resp = CallMCPToolJSONOK[protocol.FindingListResponse](t, mcpClient, "finding_list", map[string]interface{}{"limit": 1})
+		assert.Len(t, resp.Findings, 1)
+		assert.Equal(t, 2, resp.Total)
+	})
+
+	t.Run("validation", func(t *testing.T) {
+		for name, args := range map[string]map[string]interface{}{
+			"no_title":       {"severity": "low"},
+			"bad_severity":   {"title": "x", "severity": "urgent"},
+			"bad_status":     {"title": "x", "severity": "low", "status": "fixed"},
+			"events_no_oast": {"title": "x", "severity": "low", "oast_event_ids": []interface{}{"ev1"}},
+			"unknown_replay": {"title": "x", "severity": "low", "replay_ids": []interface{}{"missing"}},
+		} {
+			t.Run(name, func(t *testing.T) {
+				result := CallMCPTool(t, mcpClient, "finding_add", args)
+				assert.True(t, result.IsError)
+			})
+		}
+	})
+}
@@ -0,0 +1,242 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/go-harden/llm-security-toolbox/sectool/bundle"
+	"github.com/go-harden/llm-security-toolbox/sectool/protocol"
+	"github.com/go-harden/llm-security-toolbox/sectool/service/ids"
+	"github.com/go-harden/llm-security-toolbox/sectool/service/store"
+)
+
+// GraphQL schema sources.
+const (
+	graphQLSourceIntrospection = "introspection"
+	graphQLSourceSDL           = "sdl"
+)
+
+func (m *mcpServer) graphqlIntrospectTool() mcp.Tool {
+	return mcp.NewTool("graphql_introspect",
+		mcp.WithDescription(`Load a GraphQL schema and turn each query and mutation into a request template.
+
+Set url (the GraphQL endpoint) or flow_id (a captured GraphQL request; its headers, such as auth cookies, are reused). Without sdl, an introspection query is sent (recorded as replay_id); with sdl, the provided schema text is parsed instead, e.g. when introspection is disabled.
+Schemas are cached per endpoint: later calls return the cache without traffic (refresh=true re-runs introspection).
+Without type, returns the root types, a listing of named types with field counts, and operations. Each operation's flow_id works as replay_send flow_id; the body holds the query document and example variables (edit with set_json on variables.<name>).
+With type, returns that type's fields and arguments, enum values or union members.`),
+		mcp.WithString("url", mcp.Description("GraphQL endpoint URL (e.g., 'https://example.com/graphql')")),
+		mcp.WithString("flow_id", mcp.Description("Captured GraphQL request to take the endpoint and headers from, instead of url")),
+		mcp.WithString("sdl", mcp.Description("Schema definition language text to parse instead of sending introspection")),
+		mcp.WithObject("headers", mcp.Description("Headers to add or replace on the introspection and templates (e.g., {\"Authorization\": \"Bearer ...\"})")),
+		mcp.WithString("auth_profile", mcp.Description("Named auth profile from config for the introspection request")),
+		mcp.WithString("type", mcp.Description("Return only this type's definition")),
+		mcp.WithBoolean("refresh", mcp.Description("Re-run introspection instead of using the cached schema")),
+		mcp.WithNumber("depth", mcp.Description("Selection set depth of generated operations (default: 2)")),
+		annotateSendsTraffic,
+	)
+}
+
+func (m *mcpServer) handleGraphQLIntrospect(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if err := m.requireWorkflow(); err != nil {
+		return err, nil
+	}
+
+	urlStr, flowID, sdl := req.GetString("url", ""), req.GetString("flow_id", ""), req.GetString("sdl", "")
+	if (urlStr == "") == (flowID == "") {
+		return errorResult("exactly one of url or flow_id is required"), nil
+	}
+	depth := req.GetInt("depth", graphQLDefaultDepth)
+	if depth < 1 {
+		return errorResult("depth must be at least 1"), nil
+	}
+
+	// Base request: the endpoint with caller headers, body replaced per operation
+	var base []byte
+	var baseTarget string
+	if flowID != "" {
+		raw, target, _, err := m.service.loadBaseRequest(ctx, flowID, "")
+		if err != nil {
+			return errorResultFromErr("", err), nil
+		}
+		base, baseTarget = raw, target
+	} else {
+		parsedURL, err := parseURLWithDefaultHTTPS(urlStr)
+		if err != nil {
+			return errorResult("invalid URL: " + err.Error()), nil
+		}
+		if base = buildRawRequest("POST", parsedURL, nil, nil); base == nil {
+			return errorResult("failed to build request: invalid URL"), nil
+		}
+	}
+	headers, _ := splitHeadersBody(base)
+	for name, value := range stringMapArg(req, "headers") {
+		headers = setHeader(headers, name, value)
+	}
+	base = graphQLPostLine(headers)
+	host, port, usesHTTPS := parseTarget(base, baseTarget)
+	target := Target{Hostname: host, Port: port, UsesHTTPS: usesHTTPS}
+	endpoint := target.origin() + extractRequestPath(base)
+
+	entry, cached := m.service.graphql.get(endpoint)
+	switch {
+	case sdl != "":
+		schema, err := parseGraphQLSDL(sdl)
+		if err != nil {
+			return errorResultFromErr("failed to parse SDL: ", err), nil
+		}
+		entry, cached = &graphQLCacheEntry{Schema: schema, Source: graphQLSourceSDL}, false
+	case !cached || req.GetBool("refresh", false):
+		auth, err := m.service.resolveAuthProfile(req.GetString("auth_profile", ""))
+		if err != nil {
+			return errorResultFromErr("", err), nil
+		}
+		body, _ := json.Marshal(map[string]string{"query": graphQLIntrospectionQuery})
+		rawRequest := graphQLRequest(base, body)
+		replayID := ids.Generate(ids.DefaultLength)
+		log.Printf("mcp/graphql_introspect: %s introspecting %s", replayID, endpoint)
+		result, err := m.service.sendRequest(ctx, "sectool-"+replayID, SendRequestInput{RawRequest: rawRequest, Target: target}, auth)
+		if err != nil {
+			return errorResultFromErr("introspection request failed: ", err), nil
+		}
+		m.service.requestStore.Store(replayID, &store.RequestEntry{
+			Label:    "graphql-introspection",
+			Request:  rawRequest,
+			Target:   target.origin(),
+			Headers:  result.Headers,
+			Body:     result.Body,
+			Duration: result.Duration,
+		})
+		respBody, _ := bundle.DecodeBody(string(result.Headers), result.Body)
+		schema, err := parseGraphQLIntrospection(respBody)
+		if err != nil {
+			return errorResult(err.Error() + " (replay_id " + replayID + "); if introspection is disabled, pass the schema as sdl"), nil
+		}
+		entry, cached = &graphQLCacheEntry{Schema: schema, Source: graphQLSourceIntrospection, ReplayID: replayID}, false
+	}
+	if !cached || entry.Depth != depth {
+		entry = &graphQLCacheEntry{
+			Schema:     entry.Schema,
+			Source:     entry.Source,
+			ReplayID:   entry.ReplayID,
+			Depth:      depth,
+			Operations: m.service.storeGraphQLTemplates(base, target, entry.Schema, depth),
+		}
+		m.service.graphql.set(endpoint, entry)
+	}
+
+	schema := entry.Schema
+	resp := protocol.GraphQLIntrospectResponse{
+		Endpoint:         endpoint,
+		Source:           entry.Source,
+		Cached:           cached,
+		ReplayID:         entry.ReplayID,
+		QueryType:        schema.QueryType,
+		MutationType:     schema.MutationType,
+		SubscriptionType: schema.SubscriptionType,
+	}
+	if typeName := req.GetString("type", ""); typeName != "" {
+		t, ok := schema.Types[typeName]
+		if !ok {
+			return errorResult("type not found: " + typeName), nil
+		}
+		resp.Type = graphQLTypeDetail(t)
+		return jsonResult(resp)
+	}
+
+	for _, name := range schema.Order {
+		t := schema.Types[name]
+		if strings.HasPrefix(name, "__") || (t.Kind == "SCALAR" && slices.Contains(graphQLBuiltinScalars, name)) {
+			continue
+		}
+		resp.Types = append(resp.Types, protocol.GraphQLTypeSummary{Name: name, Kind: t.Kind, Fields: len(t.Fields)})
+	}
+	resp.Operations = make([]protocol.GraphQLOperation, 0, len(entry.Operations))
+	for _, op := range entry.Operations {
+		resp.Operations = append(resp.Operations, protocol.GraphQLOperation{
+			FlowID:     op.FlowID,
+			Kind:       op.Kind,
+			Name:       op.Field,
+			Args:       op.Args,
+			ReturnType: op.ReturnType,
+		})
+	}
+	log.Printf("mcp/graphql_introspect: %s has %d types and %d operations (source=%s, cached=%v)",
+		endpoint, len(resp.Types), len(resp.Operations), entry.Source, cached)
+	return jsonResult(resp)
+}
+
+// storeGraphQLTemplates generates the schema's operations and stores each as a
+// request template, labeled kind.field (e.g. query.user).
+func (s *Server) storeGraphQLTemplates(base []byte, target Target, schema *graphQLSchema, depth int) []graphQLTemplate {
+	ops := buildGraphQLOperations(schema, depth)
+	templates := make([]graphQLTemplate, 0, len(ops))
+	for _, op := range ops {
+		body, _ := json.Marshal(struct {
+			Query         string                 `json:"query"`
+			Variables     map[string]interface{} `json:"variables"`
+			OperationName string                 `json:"operationName"`
+		}{op.Document, op.Variables, graphQLOperationName(op.Field)})
+		id := ids.Generate(ids.DefaultLength)
+		s.specStore.Store(id, &store.RequestEntry{
+			Label:   op.Kind + "." + op.Field,
+			Request: graphQLRequest(base, body),
+			Target:  target.origin(),
+		})
+		templates = append(templates, graphQLTemplate{FlowID: id, graphQLOperation: op})
+	}
+	return templates
+}
+
+// graphQLPostLine turns a request head into a POST of the same endpoint, dropping
+// GraphQL-over-GET query parameters.
+func graphQLPostLine(headers []byte) []byte {
+	line, rest, _ := strings.Cut(string(headers), "\r\n")
+	_, path, query, version := parseRequestLine(line)
+	if values, err := url.ParseQuery(query); err == nil && query != "" {
+		for _, name := range []string{"query", "variables", "operationName", "extensions"} {
+			values.Del(name)
+		}
+		query = values.Encode()
+	}
+	return []byte(buildRequestLine("POST", path, query, version) + "\r\n" + rest)
+}
+
+// graphQLRequest returns base with a JSON body.
+func graphQLRequest(base, body []byte) []byte {
+	headers, _ := splitHeadersBody(base)
+	headers = removeHeader(headers, "Transfer-Encoding")
+	headers = setHeader(headers, "Content-Type", "application/json")
+	headers = updateContentLength(headers, len(body))
+	return append(slices.Clip(headers), body...)
+}
+
+func graphQLTypeDetail(t *graphQLType) *protocol.GraphQLType {
+	detail := &protocol.GraphQLType{
+		Name:          t.Name,
+		Kind:          t.Kind,
+		Description:   t.Description,
+		EnumValues:    t.EnumValues,
+		PossibleTypes: t.PossibleTypes,
+		Interfaces:    t.Interfaces,
+	}
+	for _, f := range t.Fields {
+		field := protocol.GraphQLField{
+			Name:         f.Name,
+			Type:         f.Type,
+			Description:  f.Description,
+			DefaultValue: f.DefaultValue,
+			Deprecated:   f.Deprecated,
+		}
+		for _, arg := range f.Args {
+			field.Args = append(field.Args, arg.Name+": "+arg.Type)
+		}
+		detail.Fields = append(detail.Fields, field)
+	}
+	return detail
+}
@@ -0,0 +1,116 @@
+package service
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/go-harden/llm-security-toolbox/sectool/protocol"
+)
+
+func TestMCP_GraphQLIntrospect(t *testing.T) {
+	t.Parallel()
+
+	srv, mcpClient, mockMCP, _, _ := setupMCPServerWithMock(t)
+	mockMCP.SetSendResponse("HttpRequestResponse{httpRequest=POST /graphql HTTP/1.1, httpResponse=HTTP/1.1 200 OK\r\nContent-Type: application/json\r\n\r\n" + testGraphQLIntrospection + "}")
+
+	t.Run("introspection", func(t *testing.T) {
+		resp := CallMCPToolJSONOK[protocol.GraphQLIntrospectResponse](t, mcpClient, "graphql_introspect", map[string]interface{}{
+			"url":     "https://api.example.com/graphql",
+			"headers": map[string]interface{}{"Authorization": "Bearer abc"},
+		})
+		assert.Equal(t, "https://api.example.com/graphql", resp.Endpoint)
+		assert.Equal(t, "introspection", resp.Source)
+		assert.False(t, resp.Cached)
+		assert.Equal(t, "Query", resp.QueryType)
+		// builtin scalars and __ types are left out
+		assert.Equal(t, []protocol.GraphQLTypeSummary{
+			{Name: "Query", Kind: "OBJECT", Fields: 2},
+			{Name: "User", Kind: "OBJECT", Fields: 2},
+			{Name: "Role", Kind: "ENUM"},
+		}, resp.Types)
+		require.Len(t, resp.Operations, 2)
+		assert.Equal(t, "user", resp.Operations[0].Name)
+		assert.Equal(t, []string{"id: ID!"}, resp.Operations[0].Args)
+
+		introspection, ok := srv.requestStore.Get(resp.ReplayID)
+		require.True(t, ok)
+		request := string(introspection.Request)
+		assert.True(t, strings.HasPrefix(request, "POST /graphql HTTP/1.1\r\n"), request)
+		assert.Contains(t, request, "Authorization: Bearer abc\r\n")
+		assert.Contains(t, request, "Content-Type: application/json\r\n")
+		assert.Contains(t, request, "__schema")
+
+		sent := CallMCPToolJSONOK[protocol.ReplaySendResponse](t, mcpClient, "replay_send", map[string]interface{}{
+			"flow_id":  resp.Operations[0].FlowID,
+			"set_json": map[string]interface{}{"variables.id": "42"},
+		})
+		replayed, ok := srv.requestStore.Get(sent.ReplayID)
+		require.True(t, ok)
+		assert.Equal(t, "https://api.example.com", replayed.Target)
+		assert.Contains(t, string(replayed.Request), "Authorization: Bearer abc\r\n")
+		assert.Contains(t, string(replayed.Request), `"query":"query User($id: ID!) { user(id: $id) { id email } }"`)
+		assert.Contains(t, string(replayed.Request), `"variables":{"id":42}`)
+	})
+
+	t.Run("cached", func(t *testing.T) {
+		resp := CallMCPToolJSONOK[protocol.GraphQLIntrospectResponse](t, mcpClient, "graphql_introspect", map[string]interface{}{
+			"url":  "https://api.example.com/graphql",
+			"type": "User",
+		})
+		assert.True(t, resp.Cached)
+		require.NotNil(t, resp.Type)
+		assert.Equal(t, []string{"Node"}, resp.Type.Interfaces)
+		require.Len(t, resp.Type.Fields, 2)
+		assert.Equal(t, "ID!", resp.Type.Fields[0].Type)
+		assert.Empty(t, resp.Operations)
+	})
+
+	t.Run("sdl", func(t *testing.T) {
+		resp := CallMCPToolJSONOK[protocol.GraphQLIntrospectResponse](t, mcpClient, "graphql_introspect", map[string]interface{}{
+			"url": "https://sdl.example.com/api/graphql?query=x&v=1",
+			"sdl": testGraphQLSDL,
+		})
+		assert.Equal(t, "https://sdl.example.com/api/graphql", resp.Endpoint)
+		assert.Equal(t, "sdl", resp.Source)
+		assert.Empty(t, resp.ReplayID)
+		assert.Equal(t, "Mutation", resp.MutationType)
+		assert.Len(t, resp.Operations, 5)
+
+		template, ok := srv.specStore.Get(resp.Operations[4].FlowID)
+		require.True(t, ok)
+		assert.Equal(t, "mutation.updateUser", template.Label)
+		assert.True(t, strings.HasPrefix(string(template.Request), "POST /api/graphql?v=1 HTTP/1.1\r\n"), string(template.Request))
+	})
+
+	t.Run("unknown_type", func(t *testing.T) {
+		result := CallMCPTool(t, mcpClient, "graphql_introspect", map[string]interface{}{
+			"url":  "https://api.example.com/graphql",
+			"type": "Missing",
+		})
+		assert.True(t, result.IsError)
+		assert.Contains(t, ExtractMCPText(t, result), "type not found")
+	})
+
+	t.Run("missing_target", func(t *testing.T) {
+		result := CallMCPTool(t, mcpClient, "graphql_introspect", map[string]interface{}{"sdl": testGraphQLSDL})
+		assert.True(t, result.IsError)
+	})
+}
+
+func TestMCP_GraphQLIntrospectRejected(t *testing.T) {
+	t.Parallel()
+
+	_, mcpClient, mockMCP, _, _ := setupMCPServerWithMock(t)
+	mockMCP.SetSendResponse("HttpRequestResponse{httpRequest=POST /graphql HTTP/1.1, httpResponse=HTTP/1.1 400 Bad Request\r\n\r\n{\"errors\":[{\"message\":\"introspection disabled\"}]}}")
+
+	result := CallMCPTool(t, mcpClient, "graphql_introspect", map[string]interface{}{
+		"url": "https://api.example.com/graphql",
+	})
+	assert.True(t, result.IsError)
+	text := ExtractMCPText(t, result)
+	assert.Contains(t, text, "introspection rejected: introspection disabled")
+	assert.Contains(t, text, "sdl")
+}
@@ -0,0 +1,395 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/go-harden/llm-security-toolbox/sectool/grpc"
+	"github.com/go-harden/llm-security-toolbox/sectool/protocol"
+	"github.com/go-harden/llm-security-toolbox/sectool/service/ids"
+	"github.com/go-harden/llm-security-toolbox/sectool/service/store"
+)
+
+func (m *mcpServer) grpcSchemaTool() mcp.Tool {
+	return mcp.NewTool("grpc_schema",
+		mcp.WithDescription(`Load protobuf definitions used to decode and edit gRPC messages.
+
+paths: .proto source files or binary descriptor sets (protoc --descriptor_set_out, buf build -o). Files import each other by their path relative to root (default: the file's base name); the well-known google/protobuf types are built in.
+proto: .proto source text, loaded as name (default "input.proto").
+Without arguments, lists the loaded methods. Schemas are ephemeral and cleared on service restart.`),
+		mcp.WithArray("paths", mcp.Items(map[string]interface{}{"type": "string"}), mcp.Description("Paths of .proto files or descriptor sets")),
+		mcp.WithString("root", mcp.Description("Import root for .proto paths (e.g., the protoc -I directory)")),
+		mcp.WithString("proto", mcp.Description(".proto source text")),
+		mcp.WithString("name", mcp.Description("Import path for proto text (default: input.proto)")),
+		annotateLocalChange,
+	)
+}
+
+func (m *mcpServer) grpcListTool() mcp.Tool {
+	return mcp.NewTool("grpc_list",
+		mcp.WithDescription(`List gRPC and gRPC-Web calls in proxy history.
+
+Returns flow_id, host, method path, protocol, HTTP and gRPC status, and the number of request messages for each call. schema=true means the method is known from grpc_schema, so grpc_get decodes fields by name.`),
+		mcp.WithString("host", mcp.Description("Host glob filter (e.g., '*.example.com')")),
+		mcp.WithString("method", mcp.Description("Method path glob filter (e.g., '/shop.v1.*/Get*')")),
+		mcp.WithNumber("limit", mcp.Description("Maximum calls to return, most recent first")),
+		annotateReadOnly,
+	)
+}
+
+func (m *mcpServer) grpcGetTool() mcp.Tool {
+	return mcp.NewTool("grpc_get",
+		mcp.WithDescription(`Decode the request and response messages of a gRPC call as JSON.
+
+Set flow_id (proxy history) or replay_id (a grpc_send or replay_send result).
+With a schema for the method, messages are protojson with proto field names. Without one, fields are keyed by number: nested messages are objects, repeated fields arrays, and {"bytes": base64}, {"fixed32": n}, {"fixed64": "n"} or {"varint": "n"} mark values JSON can't carry directly (raw=true). Both forms are accepted by grpc_send.`),
+		mcp.WithString("flow_id", mcp.Description("Proxy flow ID of the call")),
+		mcp.WithString("replay_id", mcp.Description("Replay ID of the call")),
+		annotateReadOnly,
+	)
+}
+
+func (m *mcpServer) grpcSendTool() mcp.Tool {
+	return mcp.NewTool("grpc_send",
+		mcp.WithDescription(`Re-send a captured gRPC call with field-level edits to its first request message.
+
+Base: flow_id or replay_id. Edits use the grpc_get JSON form of the message:
+- message: replacement message object
+- set_fields/remove_fields: dot-path edits as in replay_send set_json/remove_json (e.g., {"user.id": "42"}, {"3[0].1": 7})
+- metadata: gRPC metadata (headers) to add or replace
+The edited message is re-encoded uncompressed. Native gRPC is sent over HTTP/2 (h2c for http targets); gRPC-Web goes through the proxy. Returns replay_id, the gRPC status and the decoded response messages.`),
+		mcp.WithString("flow_id", mcp.Description("Proxy flow ID to use as base")),
+		mcp.WithString("replay_id", mcp.Description("Replay ID to use as base")),
+		mcp.WithObject("message", mcp.Description("Replacement for the first request message")),
+		mcp.WithObject("set_fields", mcp.Description("Message fields to set as object: {\"path\": value}")),
+		mcp.WithArray("remove_fields", mcp.Items(map[string]interface{}{"type": "string"}), mcp.Description("Message fields to remove (dot path)")),
+		mcp.WithObject("metadata", mcp.Description("Metadata to add or replace (e.g., {\"authorization\": \"Bearer ...\"})")),
+		mcp.WithString("target", mcp.Description("Override destination (e.g., 'https://staging.example.com')")),
+		mcp.WithString("timeout", mcp.Description("Request timeout (e.g., '30s')")),
+		mcp.WithString("label", mcp.Description("Label for the replay entry")),
+		annotateSendsTraffic,
+	)
+}
+
+func (m *mcpServer) handleGRPCSchema(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if err := m.requireWorkflow(); err != nil {
+		return err, nil
+	}
+
+	schema := m.service.grpcSchema
+	var loaded []string
+	protos := map[string]string{} // import path -> source
+	if source := req.GetString("proto", ""); source != "" {
+		protos[req.GetString("name", "input.proto")] = source
+	}
+	root := req.GetString("root", "")
+	for _, path := range req.GetStringSlice("paths", nil) {
+		if err := m.service.guard.CheckReadable(path); err != nil {
+			return errorResultFromErr("", err), nil
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return errorResultFromErr("", err), nil
+		}
+		if !strings.EqualFold(filepath.Ext(path), ".proto") {
+			added, err := schema.AddDescriptorSet(data)
+			if err != nil {
+				return errorResultFromErr(path+": ", err), nil
+			}
+			loaded = append(loaded, added...)
+			continue
+		}
+		name := filepath.Base(path)
+		if root != "" {
+			rel, err := filepath.Rel(root, path)
+			if err != nil || strings.HasPrefix(rel, "..") {
+				return errorResult(path + " is not under root " + root), nil
+			}
+			name = filepath.ToSlash(rel)
+		}
+		protos[name] = string(data)
+	}
+
+	// Load in dependency order; each pass adds every file whose imports resolve
+	for len(protos) > 0 {
+		var lastErr error
+		progress := false
+		for name, source := range protos {
+			if err := schema.AddProto(name, source); err != nil {
+				lastErr = err
+				continue
+			}
+			delete(protos, name)
+			loaded = append(loaded, name)
+			progress = true
+		}
+		if !progress {
+			return errorResultFromErr("failed to load proto: ", lastErr), nil
+		}
+	}
+	if len(loaded) > 0 {
+		log.Printf("mcp/grpc_schema: loaded %s", strings.Join(loaded, ", "))
+	}
+
+	resp := protocol.GRPCSchemaResponse{Loaded: loaded}
+	for _, method := range schema.Methods() {
+		resp.Methods = append(resp.Methods, protocol.GRPCMethod{
+			Path:      method.Path,
+			Input:     method.Input,
+			Output:    method.Output,
+			Streaming: grpcStreaming(method),
+		})
+	}
+	return jsonResult(resp)
+}
+
+func (m *mcpServer) handleGRPCList(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if err := m.requireWorkflow(); err != nil {
+		return err, nil
+	}
+
+	entries, err := m.service.fetchAllProxyEntries(ctx)
+	if err != nil {
+		return errorResultFromErr("failed to fetch proxy history: ", err), nil
+	}
+	hostGlob, methodGlob := req.GetString("host", ""), req.GetString("method", "")
+	limit := req.GetInt("limit", 0)
+
+	var calls []protocol.GRPCCall
+	for i := len(entries) - 1; i >= 0; i-- {
+		entry := entries[i]
+		if !isGRPCRequest([]byte(entry.request)) || !matchesGlob(entry.host, hostGlob) || !matchesGlob(entry.path, methodGlob) {
+			continue
+		}
+		ex, err := parseGRPCExchange([]byte(entry.request), []byte(entry.response))
+		if err != nil {
+			continue
+		}
+		_, known := m.service.grpcSchema.Method(ex.method)
+		var messages int
+		for _, f := range ex.request {
+			if !f.Trailer {
+				messages++
+			}
+		}
+		calls = append(calls, protocol.GRPCCall{
+			FlowID:     m.service.registerFlow(entry),
+			Host:       entry.host,
+			Method:     ex.method,
+			Protocol:   ex.protocol,
+			HTTPStatus: ex.httpStatus,
+			GRPCStatus: grpcStatusName(ex.status),
+			Messages:   messages,
+			Schema:     known,
+		})
+		if limit > 0 && len(calls) >= limit {
+			break
+		}
+	}
+	log.Printf("mcp/grpc_list: %d calls", len(calls))
+	return jsonResult(protocol.GRPCListResponse{Calls: calls})
+}
+
+func (m *mcpServer) handleGRPCGet(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if err := m.requireWorkflow(); err != nil {
+		return err, nil
+	}
+
+	ex, _, _, err := m.service.loadGRPCExchange(ctx, req.GetString("flow_id", ""), req.GetString("replay_id", ""))
+	if err != nil {
+		return errorResultFromErr("", err), nil
+	}
+	in, out := m.service.grpcMethodTypes(ex.method)
+	resp := protocol.GRPCGetResponse{
+		Method:      ex.method,
+		Protocol:    ex.protocol,
+		Schema:      in != nil,
+		Request:     m.service.decodeGRPCMessages(ex.request, ex.encoding, in),
+		Response:    m.service.decodeGRPCMessages(ex.response, ex.respEncoding, out),
+		HTTPStatus:  ex.httpStatus,
+		GRPCStatus:  grpcStatusName(ex.status),
+		GRPCMessage: ex.message,
+		FrameError:  ex.frameErr,
+	}
+	return jsonResult(resp)
+}
+
+func (m *mcpServer) handleGRPCSend(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if err := m.requireWorkflow(); err != nil {
+		return err, nil
+	}
+
+	ex, rawBase, baseTarget, err := m.service.loadGRPCExchange(ctx, req.GetString("flow_id", ""), req.GetString("replay_id", ""))
+	if err != nil {
+		return errorResultFromErr("", err), nil
+	}
+	in, out := m.service.grpcMethodTypes(ex.method)
+
+	args := req.GetArguments()
+	var replacement []byte
+	if msg, ok := args["message"].(map[string]interface{}); ok {
+		replacement, _ = json.Marshal(msg)
+	}
+	setFields, _ := args["set_fields"].(map[string]interface{})
+	removeFields := req.GetStringSlice("remove_fields", nil)
+
+	frames := ex.request
+	if replacement != nil || len(setFields) > 0 || len(removeFields) > 0 {
+		first := -1
+		for i, f := range frames {
+			if !f.Trailer {
+				first = i
+				break
+			}
+		}
+		if first < 0 {
+			if replacement == nil {
+				return errorResult("request has no message to edit; pass message"), nil
+			}
+			frames = append([]grpc.Frame{{}}, frames...)
+			first = 0
+		}
+		edited, err := m.service.editGRPCMessage(frames[first], ex.encoding, in, replacement, setFields, removeFields)
+		if err != nil {
+			return errorResultFromErr("message edit failed: ", err), nil
+		}
+		// The compressed flag is per frame, so other frames may stay compressed
+		frames = append(frames[:first:first], append([]grpc.Frame{edited}, frames[first+1:]...)...)
+	}
+
+	headers, _ := splitHeadersBody(rawBase)
+	for name, value := range stringMapArg(req, "metadata") {
+		headers = setHeader(headers, name, value)
+	}
+	body := grpc.EncodeFrames(frames, ex.protocol)
+	rawRequest := updateContentLength(append(headers, body...), len(body))
+
+	targetOverride := baseTarget
+	if target := req.GetString("target", ""); target != "" {
+		targetOverride = target
+	}
+	host, port, usesHTTPS := parseTarget(rawRequest, targetOverride)
+	if host == "" {
+		return errorResult("could not determine target host; set target"), nil
+	}
+	var timeout time.Duration
+	if timeoutStr := req.GetString("timeout", ""); timeoutStr != "" {
+		parsed, err := time.ParseDuration(timeoutStr)
+		if err != nil {
+			return errorResult("invalid timeout duration: " + err.Error()), nil
+		}
+		timeout = parsed
+	}
+	sendInput := SendRequestInput{
+		RawRequest: rawRequest,
+		Target:     Target{Hostname: host, Port: port, UsesHTTPS: usesHTTPS},
+		Timeout:    timeout,
+	}
+
+	replayID := ids.Generate(ids.DefaultLength)
+	log.Printf("mcp/grpc_send: %s sending %s to %s (%s)", replayID, ex.method, sendInput.Target.origin(), ex.protocol)
+	var result *SendRequestResult
+	if ex.protocol == grpc.ProtocolGRPC {
+		result, err = m.service.sendHTTP2Request(ctx, "sectool-"+replayID, sendInput)
+	} else {
+		result, err = m.service.sendRequest(ctx, "sectool-"+replayID, sendInput, nil)
+	}
+	if err != nil {
+		return errorResultFromErr("request failed: ", err), nil
+	}
+	m.service.requestStore.Store(replayID, &store.RequestEntry{
+		Label:    req.GetString("label", ""),
+		Request:  rawRequest,
+		Target:   sendInput.Target.origin(),
+		Headers:  result.Headers,
+		Body:     result.Body,
+		Duration: result.Duration,
+	})
+	m.service.correlateOast(ctx, replayID, rawRequest)
+
+	sent, err := parseGRPCExchange(rawRequest, append(slices.Clone(result.Headers), result.Body...))
+	if err != nil {
+		return errorResultFromErr("", err), nil
+	}
+	log.Printf("mcp/grpc_send: %s completed in %v (status=%d, grpc-status=%s)", replayID, result.Duration, sent.httpStatus, sent.status)
+	return jsonResult(protocol.GRPCSendResponse{
+		ReplayID:    replayID,
+		Duration:    result.Duration.String(),
+		Method:      sent.method,
+		HTTPStatus:  sent.httpStatus,
+		GRPCStatus:  grpcStatusName(sent.status),
+		GRPCMessage: sent.message,
+		Response:    m.service.decodeGRPCMessages(sent.response, sent.respEncoding, out),
+		FrameError:  sent.frameErr,
+	})
+}
+
+// loadGRPCExchange loads a gRPC call from proxy history or the replay store, along
+// with the raw request and its target override.
+func (s *Server) loadGRPCExchange(ctx context.Context, flowID, replayID string) (*grpcExchange, []byte, string, error) {
+	if (flowID == "") == (replayID == "") {
+		return nil, nil, "", errors.New("exactly one of flow_id or replay_id is required")
+	}
+	var rawReq, rawResp []byte
+	var target string
+	if replayID != "" {
+		id, err := s.resolveReplayRef(replayID)
+		if err != nil {
+			return nil, nil, "", err
+		}
+		entry, ok := s.requestStore.Get(id)
+		if !ok || len(entry.Request) == 0 {
+			return nil, nil, "", errors.New("replay not found: replay results are ephemeral and cleared on service restart")
+		}
+		rawReq, target = entry.Request, entry.Target
+		rawResp = append(slices.Clone(entry.Headers), entry.Body...)
+	} else {
+		id, err := s.resolveFlowRef(ctx, flowID)
+		if err != nil {
+			return nil, nil, "", err
+		}
+		entry, ok := s.flowStore.Lookup(id)
+		if !ok {
+			return nil, nil, "", errors.New("flow_id not found: run grpc_list to see gRPC calls")
+		}
+		proxyEntries, err := s.httpBackend.GetProxyHistory(ctx, 1, entry.Offset)
+		if err != nil {
+			return nil, nil, "", fmt.Errorf("failed to fetch flow: %w", err)
+		} else if len(proxyEntries) == 0 {
+			return nil, nil, "", errors.New("flow not found in proxy history")
+		}
+		rawReq, rawResp = []byte(proxyEntries[0].Request), []byte(proxyEntries[0].Response)
+	}
+	ex, err := parseGRPCExchange(rawReq, rawResp)
+	if err != nil {
+		return nil, nil, "", err
+	}
+	return ex, rawReq, target, nil
+}
+
+// grpcStatusName renders a numeric grpc-status with its code name.
+func grpcStatusName(status string) string {
+	code, err := strconv.Atoi(status)
+	if err != nil || code < 0 || code >= len(grpcCodeNames) {
+		return status
+	}
+	return status + " " + grpcCodeNames[code]
+}
+
+var grpcCodeNames = []string{
+	"OK", "CANCELLED", "UNKNOWN", "INVALID_ARGUMENT", "DEADLINE_EXCEEDED", "NOT_FOUND",
+	"ALREADY_EXISTS", "PERMISSION_DENIED", "RESOURCE_EXHAUSTED", "FAILED_PRECONDITION",
+	"ABORTED", "OUT_OF_RANGE", "UNIMPLEMENTED", "INTERNAL", "UNAVAILABLE", "DATA_LOSS",
+	"UNAUTHENTICATED",
+}
@@ -0,0 +1,184 @@
+package service
+
+import (
+	"encoding/base64"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protowire"
+
+	"github.com/go-harden/llm-security-toolbox/sectool/grpc"
+	"github.com/go-harden/llm-security-toolbox/sectool/protocol"
+)
+
+const testGRPCProto = `
+syntax = "proto3";
+package users.v1;
+
+service Users {
+  rpc GetUser (GetUserRequest) returns (User);
+}
+
+message GetUserRequest {
+  int64 id = 1;
+}
+
+message User {
+  int64 id = 1;
+  string name = 2;
+}
+`
+
+// testGRPCMessage encodes {1: id, 2: name}, which fits both test message types.
+func testGRPCMessage(id uint64, name string) []byte {
+	var b []byte
+	b = protowire.AppendTag(b, 1, protowire.VarintType)
+	b = protowire.AppendVarint(b, id)
+	if name != "" {
+		b = protowire.AppendTag(b, 2, protowire.BytesType)
+		b = protowire.AppendString(b, name)
+	}
+	return b
+}
+
+func testGRPCWebText(frames ...grpc.Frame) string {
+	return base64.StdEncoding.EncodeToString(grpc.EncodeFrames(frames, grpc.ProtocolWeb))
+}
+
+func TestMCP_GRPC(t *testing.T) {
+	t.Parallel()
+
+	srv, mcpClient, mockMCP, _, _ := setupMCPServerWithMock(t)
+	trailer := grpc.Frame{Trailer: true, Data: []byte("grpc-status:7\r\ngrpc-message:denied\r\n")}
+	mockMCP.AddProxyEntry("GET / HTTP/1.1\r\nHost: api.example.com\r\n\r\n", "HTTP/1.1 200 OK\r\n\r\n", "")
+	mockMCP.AddProxyEntry(
+		"POST /users.v1.Users/GetUser HTTP/1.1\r\nHost: api.example.com\r\nContent-Type: application/grpc-web-text\r\nX-Grpc-Web: 1\r\n\r\n"+
+			testGRPCWebText(grpc.Frame{Data: testGRPCMessage(5, "")}),
+		"HTTP/1.1 200 OK\r\nContent-Type: application/grpc-web-text+proto\r\n\r\n"+
+			testGRPCWebText(grpc.Frame{Data: testGRPCMessage(5, "alice")}, trailer),
+		"")
+
+	list := CallMCPToolJSONOK[protocol.GRPCListResponse](t, mcpClient, "grpc_list", nil)
+	require.Len(t, list.Calls, 1)
+	call := list.Calls[0]
+	assert.Equal(t, "/users.v1.Users/GetUser", call.Method)
+	assert.Equal(t, grpc.ProtocolWebText, call.Protocol)
+	assert.Equal(t, "7 PERMISSION_DENIED", call.GRPCStatus)
+	assert.Equal(t, 1, call.Messages)
+	assert.False(t, call.Schema)
+
+	t.Run("get_raw", func(t *testing.T) {
+		resp := CallMCPToolJSONOK[protocol.GRPCGetResponse](t, mcpClient, "grpc_get", map[string]interface{}{"flow_id": call.FlowID})
+		require.Len(t, resp.Request, 1)
+		assert.True(t, resp.Request[0].Raw)
+		assert.JSONEq(t, `{"1":5}`, string(resp.Request[0].JSON))
+		require.Len(t, resp.Response, 1)
+		assert.JSONEq(t, `{"1":5,"2":"alice"}`, string(resp.Response[0].JSON))
+		assert.Equal(t, "denied", resp.GRPCMessage)
+	})
+
+	t.Run("filter", func(t *testing.T) {
+		resp := CallMCPToolJSONOK[protocol.GRPCListResponse](t, mcpClient, "grpc_list", map[string]interface{}{"method": "/orders.*"})
+		assert.Empty(t, resp.Calls)
+	})
+
+	schema := CallMCPToolJSONOK[protocol.GRPCSchemaResponse](t, mcpClient, "grpc_schema", map[string]interface{}{
+		"proto": testGRPCProto,
+		"name":  "users.proto",
+	})
+	assert.Equal(t, []string{"users.proto"}, schema.Loaded)
+	assert.Equal(t, []protocol.GRPCMethod{{Path: "/users.v1.Users/GetUser", Input: "users.v1.GetUserRequest", Output: "users.v1.User"}}, schema.Methods)
+
+	t.Run("get_schema", func(t *testing.T) {
+		resp := CallMCPToolJSONOK[protocol.GRPCGetResponse](t, mcpClient, "grpc_get", map[string]interface{}{"flow_id": call.FlowID})
+		assert.True(t, resp.Schema)
+		assert.False(t, resp.Request[0].Raw)
+		assert.JSONEq(t, `{"id":"5"}`, string(resp.Request[0].JSON))
+		assert.JSONEq(t, `{"id":"5","name":"alice"}`, string(resp.Response[0].JSON))
+	})
+
+	t.Run("send_web", func(t *testing.T) {
+		mockMCP.SetSendResponse("HttpRequestResponse{httpRequest=POST /users.v1.Users/GetUser HTTP/1.1, httpResponse=HTTP/1.1 200 OK\r\nContent-Type: application/grpc-web-text\r\n\r\n" +
+			testGRPCWebText(grpc.Frame{Data: testGRPCMessage(6, "mallory")}, grpc.Frame{Trailer: true, Data: []byte("grpc-status:0\r\n")}) + "}")
+		resp := CallMCPToolJSONOK[protocol.GRPCSendResponse](t, mcpClient, "grpc_send", map[string]interface{}{
+			"flow_id":    call.FlowID,
+			"set_fields": map[string]interface{}{"id": "6"},
+			"metadata":   map[string]interface{}{"Authorization": "Bearer abc"},
+		})
+		assert.Equal(t, "0 OK", resp.GRPCStatus)
+		require.Len(t, resp.Response, 1)
+		assert.JSONEq(t, `{"id":"6","name":"mallory"}`, string(resp.Response[0].JSON))
+
+		entry, ok := srv.requestStore.Get(resp.ReplayID)
+		require.True(t, ok)
+		headers, body := splitHeadersBody(entry.Request)
+		assert.Contains(t, string(headers), "Authorization: Bearer abc\r\n")
+		frames, err := grpc.ParseFrames(body, grpc.ProtocolWebText)
+		require.NoError(t, err)
+		require.Len(t, frames, 1)
+		assert.Equal(t, testGRPCMessage(6, ""), frames[0].Data)
+
+		// The replay is a valid base for grpc_get
+		got := CallMCPToolJSONOK[protocol.GRPCGetResponse](t, mcpClient, "grpc_get", map[string]interface{}{"replay_id": resp.ReplayID})
+		assert.JSONEq(t, `{"id":"6"}`, string(got.Request[0].JSON))
+	})
+
+	t.Run("invalid_edit", func(t *testing.T) {
+		result := CallMCPTool(t, mcpClient, "grpc_send", map[string]interface{}{
+			"flow_id": call.FlowID,
+			"message": map[string]interface{}{"unknown": 1},
+		})
+		assert.True(t, result.IsError)
+	})
+
+	t.Run("missing_flow", func(t *testing.T) {
+		result := CallMCPTool(t, mcpClient, "grpc_get", map[string]interface{}{"flow_id": "missing"})
+		assert.True(t, result.IsError)
+	})
+}
+
+func TestMCP_GRPCSendHTTP2(t *testing.T) {
+	t.Parallel()
+
+	var gotProto, gotBody string
+	h2c := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotProto = r.Proto
+		body, _ := io.ReadAll(r.Body)
+		gotBody = string(body)
+		w.Header().Set("Content-Type", "application/grpc")
+		w.Header().Set("Trailer", "Grpc-Status")
+		_, _ = w.Write(grpc.EncodeFrames([]grpc.Frame{{Data: testGRPCMessage(9, "")}}, grpc.ProtocolGRPC))
+		w.Header().Set("Grpc-Status", "0")
+	}))
+	var protocols http.Protocols
+	protocols.SetUnencryptedHTTP2(true)
+	h2c.Config.Protocols = &protocols
+	h2c.Start()
+	t.Cleanup(h2c.Close)
+
+	_, mcpClient, mockMCP, _, _ := setupMCPServerWithMock(t)
+	host := strings.TrimPrefix(h2c.URL, "http://")
+	mockMCP.AddProxyEntry(
+		"POST /users.v1.Users/GetUser HTTP/2\r\nHost: "+host+"\r\nContent-Type: application/grpc\r\nTe: trailers\r\n\r\n"+
+			string(grpc.EncodeFrames([]grpc.Frame{{Data: testGRPCMessage(1, "")}}, grpc.ProtocolGRPC)),
+		"", "")
+	list := CallMCPToolJSONOK[protocol.GRPCListResponse](t, mcpClient, "grpc_list", nil)
+	require.Len(t, list.Calls, 1)
+
+	resp := CallMCPToolJSONOK[protocol.GRPCSendResponse](t, mcpClient, "grpc_send", map[string]interface{}{
+		"flow_id":    list.Calls[0].FlowID,
+		"set_fields": map[string]interface{}{"1": 2},
+		"target":     h2c.URL,
+	})
+	assert.Equal(t, "HTTP/2.0", gotProto)
+	assert.Equal(t, string(grpc.EncodeFrames([]grpc.Frame{{Data: testGRPCMessage(2, "")}}, grpc.ProtocolGRPC)), gotBody)
+	assert.Equal(t, 200, resp.HTTPStatus)
+	assert.Equal(t, "0 OK", resp.GRPCStatus)
+	require.Len(t, resp.Response, 1)
+	assert.JSONEq(t, `{"1":9}`, string(resp.Response[0].JSON))
+}
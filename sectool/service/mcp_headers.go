@@ -0,0 +1,68 @@
+package service
+
+import (
+	"context"
+	"log"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/go-harden/llm-security-toolbox/sectool/protocol"
+	"github.com/go-harden/llm-security-toolbox/sectool/report"
+)
+
+func (m *mcpServer) analyzeHeadersTool() mcp.Tool {
+	return mcp.NewTool("analyze_headers",
+		mcp.WithDescription(`Passively check response headers across proxy history and summarize issues per host.
+
+Checks:
+- missing_hsts (HTTPS responses), missing_nosniff (2xx)
+- missing_csp, weak_csp (unsafe-inline without nonce, unsafe-eval, data: or * script sources), missing_frame_protection (2xx HTML)
+- cookie_no_secure (HTTPS), cookie_no_httponly, cookie_no_samesite (unset, or None without Secure), per cookie name
+- cors_reflected_origin (request Origin echoed with credentials), cors_null_origin, cors_wildcard_credentials
+HTTPS is assumed unless the port is 80 or the request line is an absolute http:// URL.
+Each issue has a severity, the number of responses showing it and up to 3 example flow_ids. Nothing is sent.`),
+		mcp.WithString("host", mcp.Description("Only hosts matching this glob")),
+		mcp.WithString("severity", mcp.Description("Minimum issue severity: info, low, medium, high or critical (default: info)")),
+		annotateReadOnly,
+	)
+}
+
+func (m *mcpServer) handleAnalyzeHeaders(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if err := m.requireWorkflow(); err != nil {
+		return err, nil
+	}
+
+	minSeverity := strings.ToLower(req.GetString("severity", protocol.SeverityInfo))
+	if !validSeverities[minSeverity] {
+		return errorResult("invalid severity: " + minSeverity + " (use info, low, medium, high, critical)"), nil
+	}
+	hostGlob := req.GetString("host", "")
+
+	entries, err := m.service.fetchAllProxyEntries(ctx)
+	if err != nil {
+		return errorResultFromErr("failed to fetch proxy history: ", err), nil
+	}
+	var matched []flowEntry
+	for _, entry := range entries {
+		if matchesGlob(entry.host, hostGlob) {
+			matched = append(matched, entry)
+		}
+	}
+
+	hosts := analyzeHeaders(matched, m.service.registerFlow)
+	var issues int
+	for i := range hosts {
+		kept := hosts[i].Issues[:0]
+		for _, issue := range hosts[i].Issues {
+			if report.SeverityRank(issue.Severity) <= report.SeverityRank(minSeverity) {
+				kept = append(kept, issue)
+			}
+		}
+		hosts[i].Issues = kept
+		issues += len(kept)
+	}
+	log.Printf("mcp/analyze_headers: %d flows, %d hosts, %d issues", len(matched), len(hosts), issues)
+
+	return jsonResult(protocol.AnalyzeHeadersResponse{Hosts: hosts})
+}
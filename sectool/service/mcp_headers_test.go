@@ -0,0 +1,132 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/go-harden/llm-security-toolbox/sectool/protocol"
+)
+
+func TestAnalyzeResponseHeaders(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		request  string
+		response string
+		https    bool
+		want     []headerIssue
+	}{
+		{
+			name:     "hardened",
+			response: "HTTP/1.1 200 OK\r\nContent-Type: text/html\r\nStrict-Transport-Security: max-age=31536000\r\nX-Content-Type-Options: nosniff\r\nContent-Security-Policy: default-src 'self'; frame-ancestors 'none'\r\nSet-Cookie: s=1; Secure; HttpOnly; SameSite=Lax\r\n\r\n",
+			https:    true,
+		},
+		{
+			name:     "missing_html",
+			response: "HTTP/1.1 200 OK\r\nContent-Type: text/html; charset=utf-8\r\n\r\n",
+			https:    true,
+			want: []headerIssue{
+				{Check: checkMissingHSTS}, {Check: checkMissingNosniff}, {Check: checkMissingCSP}, {Check: checkMissingFrameOptions},
+			},
+		},
+		{
+			name:     "plain_http_redirect",
+			response: "HTTP/1.1 302 Found\r\nLocation: /\r\n\r\n",
+		},
+		{
+			name:     "weak_csp",
+			response: "HTTP/1.1 200 OK\r\nContent-Type: text/html\r\nX-Content-Type-Options: nosniff\r\nX-Frame-Options: DENY\r\nContent-Security-Policy: script-src 'self' 'unsafe-inline'\r\n\r\n",
+			want:     []headerIssue{{Check: checkWeakCSP, Detail: "script sources allow 'unsafe-inline'"}},
+		},
+		{
+			name:     "cookies",
+			response: "HTTP/1.1 204 No Content\r\nStrict-Transport-Security: max-age=1\r\nSet-Cookie: sid=abc; Path=/\r\nSet-Cookie: old=; Max-Age=0\r\n\r\n",
+			https:    true,
+			want: []headerIssue{
+				{Check: checkMissingNosniff},
+				{Check: checkCookieNoSecure, Detail: "sid"}, {Check: checkCookieNoHttpOnly, Detail: "sid"}, {Check: checkCookieNoSameSite, Detail: "sid"},
+			},
+		},
+		{
+			name:     "cors_reflected",
+			request:  "GET /api HTTP/1.1\r\nHost: api.example.com\r\nOrigin: https://evil.example\r\n\r\n",
+			response: "HTTP/1.1 404 Not Found\r\nAccess-Control-Allow-Origin: https://evil.example\r\nAccess-Control-Allow-Credentials: true\r\n\r\n",
+			want:     []headerIssue{{Check: checkCORSReflected, Detail: "https://evil.example"}},
+		},
+		{
+			name:     "cors_null",
+			response: "HTTP/1.1 404 Not Found\r\nAccess-Control-Allow-Origin: null\r\n\r\n",
+			want:     []headerIssue{{Check: checkCORSNull}},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, analyzeResponseHeaders([]byte(tc.request), []byte(tc.response), tc.https))
+		})
+	}
+
+	t.Run("weak_csp", func(t *testing.T) {
+		assert.Empty(t, weakCSP("script-src 'self' 'nonce-abc' 'unsafe-inline'"))
+		assert.Empty(t, weakCSP("default-src *; script-src 'self'"))
+		assert.Equal(t, "script sources allow *", weakCSP("default-src *"))
+		assert.Equal(t, "script sources allow 'unsafe-eval'", weakCSP("script-src 'self' 'unsafe-eval'"))
+	})
+}
+
+func TestMCP_AnalyzeHeaders(t *testing.T) {
+	t.Parallel()
+
+	_, mcpClient, mockMCP, _, _ := setupMCPServerWithMock(t)
+	for range 2 {
+		mockMCP.AddProxyEntry(
+			"GET / HTTP/1.1\r\nHost: app.example.com\r\n\r\n",
+			"HTTP/1.1 200 OK\r\nContent-Type: text/html\r\nX-Content-Type-Options: nosniff\r\nX-Frame-Options: DENY\r\nContent-Security-Policy: default-src 'self'\r\nSet-Cookie: session=abc; HttpOnly; SameSite=Lax\r\n\r\n<html></html>",
+			"",
+		)
+	}
+	mockMCP.AddProxyEntry(
+		"GET /me HTTP/1.1\r\nHost: api.example.com\r\nOrigin: https://evil.example\r\n\r\n",
+		"HTTP/1.1 200 OK\r\nStrict-Transport-Security: max-age=31536000\r\nX-Content-Type-Options: nosniff\r\nAccess-Control-Allow-Origin: https://evil.example\r\nAccess-Control-Allow-Credentials: true\r\n\r\n{}",
+		"",
+	)
+
+	resp := CallMCPToolJSONOK[protocol.AnalyzeHeadersResponse](t, mcpClient, "analyze_headers", nil)
+	require.Len(t, resp.Hosts, 2)
+
+	api := resp.Hosts[0]
+	assert.Equal(t, "api.example.com", api.Host)
+	assert.Equal(t, 1, api.Responses)
+	require.Len(t, api.Issues, 1)
+	assert.Equal(t, checkCORSReflected, api.Issues[0].Check)
+	assert.Equal(t, protocol.SeverityMedium, api.Issues[0].Severity)
+	assert.Len(t, api.Issues[0].FlowIDs, 1)
+
+	app := resp.Hosts[1]
+	assert.Equal(t, "app.example.com", app.Host)
+	assert.Equal(t, 2, app.Responses)
+	var checks []string
+	for _, issue := range app.Issues {
+		checks = append(checks, issue.Check)
+		assert.Equal(t, 2, issue.Count)
+		assert.Len(t, issue.FlowIDs, 2)
+	}
+	assert.Equal(t, []string{checkCookieNoSecure, checkMissingHSTS}, checks)
+
+	t.Run("filters", func(t *testing.T) {
+		resp := CallMCPToolJSONOK[protocol.AnalyzeHeadersResponse](t, mcpClient, "analyze_headers", map[string]interface{}{
+			"host":     "app.*",
+			"severity": "medium",
+		})
+		require.Len(t, resp.Hosts, 1)
+		require.Len(t, resp.Hosts[0].Issues, 1)
+		assert.Equal(t, checkCookieNoSecure, resp.Hosts[0].Issues[0].Check)
+	})
+
+	t.Run("bad_severity", func(t *testing.T) {
+		result := CallMCPTool(t, mcpClient, "analyze_headers", map[string]interface{}{"severity": "urgent"})
+		assert.True(t, result.IsError)
+	})
+}
@@ -0,0 +1,261 @@
+package service
+
+import (
+	"context"
+	"log"
+	"net"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/go-harden/llm-security-toolbox/sectool/payload"
+	"github.com/go-harden/llm-security-toolbox/sectool/protocol"
+	"github.com/go-harden/llm-security-toolbox/sectool/service/ids"
+	"github.com/go-harden/llm-security-toolbox/sectool/service/store"
+)
+
+func (m *mcpServer) vhostDiscoverTool() mcp.Tool {
+	return mcp.NewTool("vhost_discover",
+		mcp.WithDescription(`Find virtual hosts served from one address by varying the Host header.
+
+Every request goes to url; a random host under domain is sent first to fingerprint the default response, and Host values answering differently (status, redirect, body) are reported.
+Candidates are wordlist labels joined to domain plus any full hosts given. Hits are stored as replays; replay_send with the same Host header reaches the virtual host.
+The url's host must be in the project scope; hits outside it are marked out_of_scope, add them with scope_set before testing.
+Default wordlist: the built-in 'subdomains' payload list (see payloads_get).`),
+		mcp.WithString("url", mcp.Required(), mcp.Description("Address to send every request to (e.g., 'https://203.0.113.10/' or 'https://example.com/')")),
+		mcp.WithString("domain", mcp.Description("Domain the labels are joined to (default: the url's host when it is a name)")),
+		mcp.WithArray("words", mcp.Items(map[string]interface{}{"type": "string"}), mcp.Description("Labels to try (default: built-in subdomains list)")),
+		mcp.WithArray("hosts", mcp.Items(map[string]interface{}{"type": "string"}), mcp.Description("Full Host values to try in addition (e.g., from subdomain_enum)")),
+		mcp.WithString("filter_status", mcp.Description("Status codes never reported, comma-separated")),
+		mcp.WithNumber("threads", mcp.Description("Concurrent requests (default 10, max 50)")),
+		mcp.WithNumber("max_requests", mcp.Description("Request budget including the baseline (default 500)")),
+		mcp.WithObject("headers", mcp.Description("Headers to send, e.g. {\"Cookie\": \"session=...\"}")),
+		mcp.WithString("timeout", mcp.Description("Per-request timeout (e.g., '30s')")),
+		mcp.WithNumber("rate", mcp.Description(rateParamDescription)),
+		annotateSendsTraffic,
+	)
+}
+
+func (m *mcpServer) handleVhostDiscover(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if err := m.requireWorkflow(); err != nil {
+		return err, nil
+	}
+	ctx, err := applyRunRate(ctx, req)
+	if err != nil {
+		return errorResult(err.Error()), nil
+	}
+
+	rawURL := req.GetString("url", "")
+	if rawURL == "" {
+		return errorResult("url is required"), nil
+	}
+	base, err := parseURLWithDefaultHTTPS(rawURL)
+	if err != nil || base.Hostname() == "" {
+		return errorResult("invalid url: " + rawURL), nil
+	}
+	base.RawQuery, base.Fragment = "", ""
+	if base.Path == "" {
+		base.Path = "/"
+	}
+	domain := req.GetString("domain", "")
+	if domain == "" && net.ParseIP(base.Hostname()) == nil {
+		domain = base.Hostname()
+	}
+
+	var timeout time.Duration
+	if s := req.GetString("timeout", ""); s != "" {
+		if timeout, err = time.ParseDuration(s); err != nil {
+			return errorResult("invalid timeout duration: " + err.Error()), nil
+		}
+	}
+	var filterStatus []int
+	for _, code := range parseCommaSeparated(req.GetString("filter_status", "")) {
+		status, err := strconv.Atoi(code)
+		if err != nil {
+			return errorResult("invalid filter_status code: " + code), nil
+		}
+		filterStatus = append(filterStatus, status)
+	}
+	threads := req.GetInt("threads", defaultHostThreads)
+	maxRequests := req.GetInt("max_requests", defaultVhostMaxRequests)
+	if threads < 1 || threads > maxHostThreads {
+		return errorResult("threads must be between 1 and " + strconv.Itoa(maxHostThreads)), nil
+	} else if maxRequests < 2 {
+		return errorResult("max_requests must be at least 2"), nil
+	}
+
+	words := req.GetStringSlice("words", nil)
+	explicit := req.GetStringSlice("hosts", nil)
+	if len(words) == 0 && (len(explicit) == 0 || domain != "") {
+		list, err := payload.GetList("subdomains")
+		if err != nil {
+			return errorResultFromErr("", err), nil
+		}
+		words = list.Payloads
+	}
+	if domain == "" {
+		words = nil // labels need a domain
+	}
+	hosts := hostWords(words, explicit, domain)
+	if len(hosts) == 0 {
+		return errorResult("no hosts to test: set domain for wordlist labels, or hosts"), nil
+	}
+
+	target := targetFromURL(base)
+	headers := stringMapArg(req, "headers")
+	send := func(ctx context.Context, host string) (*vhostProbe, error) {
+		h := make(map[string]string, len(headers)+1)
+		for name, value := range headers {
+			h[name] = value
+		}
+		h["Host"] = host
+		request := buildRawRequest("GET", base, h, nil)
+		result, err := m.service.sendRequest(ctx, "sectool-vhost-discover", SendRequestInput{
+			RawRequest: request,
+			Target:     target,
+			Timeout:    timeout,
+		}, nil)
+		if err != nil {
+			return nil, err
+		}
+		probe := &vhostProbe{host: host, request: request, result: result}
+		probe.status, _ = parseResponseStatus(result.Headers)
+		if locations := parseHeadersToMap(string(result.Headers))["Location"]; len(locations) > 0 {
+			probe.location = locations[0]
+		}
+		return probe, nil
+	}
+
+	v := &vhostDiscoverer{
+		send:         send,
+		filterStatus: filterStatus,
+		threads:      threads,
+		maxRequests:  maxRequests,
+		onHit: func(probe *vhostProbe) {
+			probe.replayID = ids.Generate(ids.DefaultLength)
+			m.service.requestStore.Store(probe.replayID, &store.RequestEntry{
+				Request:  probe.request,
+				Target:   target.origin(),
+				Headers:  probe.result.Headers,
+				Body:     probe.result.Body,
+				Duration: probe.result.Duration,
+			})
+		},
+	}
+
+	log.Printf("mcp/vhost_discover: %d hosts against %s", len(hosts), target.origin())
+	if err := v.run(ctx, domain, hosts); err != nil {
+		return errorResultFromErr("vhost_discover failed: ", err), nil
+	}
+
+	resp := protocol.VhostDiscoverResponse{
+		URL:      base.String(),
+		Tested:   len(hosts),
+		Requests: v.requests,
+		Baseline: strconv.Itoa(v.baseline.status) + " (" + strconv.Itoa(len(v.baseline.result.Body)) + " bytes)",
+		Hits:     make([]protocol.VhostHit, 0, len(v.hits)),
+		Errors:   v.errors,
+	}
+	if v.lastErr != nil {
+		resp.LastError = v.lastErr.Error()
+	}
+	if v.stopped {
+		resp.Stopped = "max_requests"
+	}
+	scope := m.service.projectScope()
+	for _, probe := range v.hits {
+		resp.Hits = append(resp.Hits, protocol.VhostHit{
+			Host:       probe.host,
+			Status:     probe.status,
+			Size:       len(probe.result.Body),
+			Location:   probe.location,
+			Title:      htmlTitle(probe.result.Body),
+			OutOfScope: scope != nil && !inScope(scope, probe.host, base.EscapedPath()),
+			ReplayID:   probe.replayID,
+		})
+	}
+	slices.SortFunc(resp.Hits, func(a, b protocol.VhostHit) int { return strings.Compare(a.Host, b.Host) })
+
+	log.Printf("mcp/vhost_discover: %s done after %d requests, %d hits", target.origin(), v.requests, len(resp.Hits))
+	return jsonResult(resp)
+}
+
+func (m *mcpServer) subdomainEnumTool() mcp.Tool {
+	return mcp.NewTool("subdomain_enum",
+		mcp.WithDescription(`Enumerate subdomains of a domain by DNS resolution of a wordlist and certificate transparency logs (crt.sh).
+
+Sends no traffic to the target's web servers: names are resolved with the system resolver and CT logs are queried directly, not through the proxy.
+A random name is resolved first; with wildcard DNS, wordlist names resolving only to the wildcard addresses are dropped. CT names are listed even when they do not resolve (they may be internal).
+Names outside the project scope are marked out_of_scope. Follow up with vhost_discover (hosts) or crawl_create to expand discovery.
+Default wordlist: the built-in 'subdomains' payload list (see payloads_get).`),
+		mcp.WithString("domain", mcp.Required(), mcp.Description("Domain to enumerate (e.g., 'example.com')")),
+		mcp.WithArray("words", mcp.Items(map[string]interface{}{"type": "string"}), mcp.Description("Labels to resolve (default: built-in subdomains list)")),
+		mcp.WithBoolean("ct", mcp.Description("Query certificate transparency logs (default true)")),
+		mcp.WithNumber("threads", mcp.Description("Concurrent DNS lookups (default 10, max 50)")),
+		annotateExternalLookup,
+	)
+}
+
+func (m *mcpServer) handleSubdomainEnum(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if err := m.requireWorkflow(); err != nil {
+		return err, nil
+	}
+
+	domain := strings.Trim(strings.ToLower(strings.TrimSpace(req.GetString("domain", ""))), ".")
+	if domain == "" {
+		return errorResult("domain is required"), nil
+	} else if strings.ContainsAny(domain, "/: ") {
+		return errorResult("domain must be a bare name such as example.com"), nil
+	}
+	threads := req.GetInt("threads", defaultHostThreads)
+	if threads < 1 || threads > maxHostThreads {
+		return errorResult("threads must be between 1 and " + strconv.Itoa(maxHostThreads)), nil
+	}
+
+	words := req.GetStringSlice("words", nil)
+	if len(words) == 0 {
+		list, err := payload.GetList("subdomains")
+		if err != nil {
+			return errorResultFromErr("", err), nil
+		}
+		words = list.Payloads
+	}
+	hosts := hostWords(words, nil, domain)
+
+	e := &subdomainEnumerator{lookup: net.DefaultResolver.LookupHost, threads: threads}
+	if req.GetBool("ct", true) {
+		e.ctLookup = crtshLookup
+	}
+
+	log.Printf("mcp/subdomain_enum: %d names under %s (ct=%t)", len(hosts), domain, e.ctLookup != nil)
+	found, err := e.run(ctx, domain, hosts)
+	if err != nil {
+		return errorResultFromErr("subdomain_enum failed: ", err), nil
+	}
+
+	resp := protocol.SubdomainEnumResponse{
+		Domain:     domain,
+		Tested:     len(hosts),
+		Wildcard:   e.wildcard,
+		Subdomains: make([]protocol.Subdomain, 0, len(found)),
+		Errors:     e.errors,
+	}
+	if e.ctErr != nil {
+		resp.CTError = e.ctErr.Error()
+	}
+	scope := m.service.projectScope()
+	for _, s := range found {
+		resp.Subdomains = append(resp.Subdomains, protocol.Subdomain{
+			Host:       s.host,
+			Addresses:  s.addresses,
+			Sources:    s.sources,
+			OutOfScope: scope != nil && !inScope(scope, s.host, "/"),
+		})
+	}
+
+	log.Printf("mcp/subdomain_enum: %s done, %d subdomains", domain, len(resp.Subdomains))
+	return jsonResult(resp)
+}
@@ -0,0 +1,70 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/go-harden/llm-security-toolbox/sectool/protocol"
+)
+
+func TestMCP_VhostDiscover(t *testing.T) {
+	t.Parallel()
+
+	srv, mcpClient, mockMCP, _, _ := setupMCPServerWithMock(t)
+
+	respond := func(status, body string) {
+		mockMCP.SetSendResponse("HttpRequestResponse{httpRequest=GET / HTTP/1.1, httpResponse=HTTP/1.1 " + status + "\r\nContent-Type: text/html\r\n\r\n" + body + "}")
+	}
+
+	t.Run("hits", func(t *testing.T) {
+		respond("404 Not Found", "no such site") // baseline
+		respond("200 OK", "<html><head><title>Staging</title></head></html>")
+		respond("404 Not Found", "no such site")
+
+		resp := CallMCPToolJSONOK[protocol.VhostDiscoverResponse](t, mcpClient, "vhost_discover", map[string]interface{}{
+			"url":     "https://203.0.113.10",
+			"domain":  "example.com",
+			"words":   []interface{}{"staging", "missing"},
+			"threads": 1,
+		})
+		assert.Equal(t, "https://203.0.113.10/", resp.URL)
+		assert.Equal(t, 2, resp.Tested)
+		assert.Equal(t, 3, resp.Requests)
+		assert.Equal(t, "404 (12 bytes)", resp.Baseline)
+		require.Len(t, resp.Hits, 1)
+		hit := resp.Hits[0]
+		assert.Equal(t, "staging.example.com", hit.Host)
+		assert.Equal(t, "Staging", hit.Title)
+
+		entry, ok := srv.requestStore.Get(hit.ReplayID)
+		require.True(t, ok)
+		assert.Contains(t, string(entry.Request), "Host: staging.example.com")
+		assert.Equal(t, "https://203.0.113.10", entry.Target)
+	})
+
+	t.Run("validation", func(t *testing.T) {
+		result := CallMCPTool(t, mcpClient, "vhost_discover", map[string]interface{}{})
+		assert.True(t, result.IsError)
+		result = CallMCPTool(t, mcpClient, "vhost_discover", map[string]interface{}{"url": "https://203.0.113.10/"})
+		assert.True(t, result.IsError)
+		assert.Contains(t, ExtractMCPText(t, result), "no hosts to test")
+		result = CallMCPTool(t, mcpClient, "vhost_discover", map[string]interface{}{"url": "https://example.com/", "threads": 0})
+		assert.True(t, result.IsError)
+		assert.Contains(t, ExtractMCPText(t, result), "threads must be")
+	})
+}
+
+func TestMCP_SubdomainEnum(t *testing.T) {
+	t.Parallel()
+
+	_, mcpClient, _, _, _ := setupMCPServerWithMock(t)
+
+	result := CallMCPTool(t, mcpClient, "subdomain_enum", map[string]interface{}{})
+	assert.True(t, result.IsError)
+	assert.Contains(t, ExtractMCPText(t, result), "domain is required")
+	result = CallMCPTool(t, mcpClient, "subdomain_enum", map[string]interface{}{"domain": "https://example.com/"})
+	assert.True(t, result.IsError)
+	assert.Contains(t, ExtractMCPText(t, result), "bare name")
+}
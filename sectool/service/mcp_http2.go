@@ -0,0 +1,208 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"golang.org/x/net/http2"
+
+	"github.com/go-harden/llm-security-toolbox/sectool/protocol"
+	"github.com/go-harden/llm-security-toolbox/sectool/service/ids"
+	"github.com/go-harden/llm-security-toolbox/sectool/service/store"
+)
+
+const defaultHTTP2Timeout = 30 * time.Second
+
+func (m *mcpServer) http2SendTool() mcp.Tool {
+	return mcp.NewTool("http2_send",
+		mcp.WithDescription(`Send a request as native HTTP/2 frames with full control of the header block.
+
+replay_send and request_craft send HTTP/1.1; use this for HTTP/2-only behavior and HTTP/2 smuggling research (H2.CL, H2.TE, CRLF or pseudo-header injection).
+Base (exactly one): flow_id, replay_id, or raw (HTTP/1.1 or HTTP/2 request-line form). It is converted to :method, :scheme, :authority (from Host) and :path, then lowercased headers; connection-specific headers are dropped.
+Edits: pseudo overrides pseudo-header values ("" removes, new names like :protocol are added); add_headers/remove_headers edit regular headers (added values are sent verbatim, including transfer-encoding or uppercase names); body replaces the body and updates content-length.
+headers: the complete header block in send order, pseudo-headers included, instead of the derived one; nothing is validated, so invalid pseudo-header ordering, duplicates and pseudo-headers after regular headers are sent as given.
+settings: SETTINGS sent with the preface, by name (header_table_size, enable_push, max_concurrent_streams, initial_window_size, max_frame_size, max_header_list_size, enable_connect_protocol).
+HTTPS targets negotiate h2 by ALPN; http targets use prior-knowledge h2c. Sent directly, not through the HTTP backend, so it is not in proxy history; project scope applies.
+Returns replay_id, the header block as sent, the response, and any RST_STREAM or GOAWAY error code.`),
+		mcp.WithString("flow_id", mcp.Description("Flow ID to use as base request, or "+recentRefUsage)),
+		mcp.WithString("replay_id", mcp.Description("Replay ID or label to use as base request")),
+		mcp.WithString("raw", mcp.Description("Raw request to use as base")),
+		mcp.WithString("target", mcp.Description("Override destination (scheme+host[:port])")),
+		mcp.WithObject("pseudo", mcp.Description("Pseudo-header values to set (e.g., {\":path\": \"/admin\", \":method\": \"POST\"})")),
+		mcp.WithArray("add_headers", mcp.Items(map[string]interface{}{"type": "string"}), mcp.Description("Headers to append verbatim ('name: value')")),
+		mcp.WithArray("remove_headers", mcp.Items(map[string]interface{}{"type": "string"}), mcp.Description("Header names to remove")),
+		mcp.WithArray("headers", mcp.Items(map[string]interface{}{"type": "string"}), mcp.Description("Complete header block in order, e.g. [':method: GET', ':path: /', ':authority: x', ':scheme: https']")),
+		mcp.WithString("body", mcp.Description("Request body (replaces the base body)")),
+		mcp.WithObject("settings", mcp.Description("SETTINGS values by name (e.g., {\"initial_window_size\": 1})")),
+		mcp.WithString("timeout", mcp.Description("Exchange timeout (e.g., '30s')")),
+		mcp.WithString("label", mcp.Description("Optional label; later replay_get calls accept it in place of replay_id")),
+		annotateSendsTraffic,
+	)
+}
+
+func (m *mcpServer) handleHTTP2Send(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if err := m.requireWorkflow(); err != nil {
+		return err, nil
+	}
+
+	flowID, replayID, raw := req.GetString("flow_id", ""), req.GetString("replay_id", ""), req.GetString("raw", "")
+	var set int
+	for _, s := range []string{flowID, replayID, raw} {
+		if s != "" {
+			set++
+		}
+	}
+	if set != 1 {
+		return errorResult("exactly one of flow_id, replay_id or raw is required"), nil
+	}
+
+	var base []byte
+	var baseTarget, baseID string
+	if raw != "" {
+		base = normalizeRawRequest([]byte(raw))
+	} else {
+		var err error
+		if base, baseTarget, baseID, err = m.service.loadBaseRequest(ctx, flowID, replayID); err != nil {
+			return errorResultFromErr("", err), nil
+		}
+	}
+	targetOverride := baseTarget
+	if target := req.GetString("target", ""); target != "" {
+		targetOverride = target
+	}
+	host, port, usesHTTPS := parseTarget(base, targetOverride)
+	if host == "" {
+		return errorResult("could not determine target host; set target"), nil
+	}
+	target := Target{Hostname: host, Port: port, UsesHTTPS: usesHTTPS}
+
+	fields, body := http2RequestFields(base, target)
+	bodyArg, bodySet := req.GetArguments()["body"].(string)
+	if bodySet {
+		body = []byte(bodyArg)
+	}
+	if verbatim := req.GetStringSlice("headers", nil); len(verbatim) > 0 {
+		fields = fields[:0]
+		for _, line := range verbatim {
+			f, ok := parseH2Field(line)
+			if !ok {
+				return errorResult("invalid header " + strconv.Quote(line) + ": expected 'name: value'"), nil
+			}
+			fields = append(fields, f)
+		}
+	} else {
+		if bodySet {
+			for i, f := range fields {
+				if f.Name == "content-length" {
+					fields[i].Value = strconv.Itoa(len(body))
+				}
+			}
+		}
+		for _, name := range req.GetStringSlice("remove_headers", nil) {
+			fields = slices.DeleteFunc(fields, func(f h2Field) bool {
+				return !strings.HasPrefix(f.Name, ":") && strings.EqualFold(f.Name, strings.TrimSpace(name))
+			})
+		}
+		for _, line := range req.GetStringSlice("add_headers", nil) {
+			f, ok := parseH2Field(line)
+			if !ok {
+				return errorResult("invalid header " + strconv.Quote(line) + ": expected 'name: value'"), nil
+			}
+			fields = append(fields, f)
+		}
+		if pseudo := stringMapArg(req, "pseudo"); len(pseudo) > 0 {
+			for name := range pseudo {
+				if !strings.HasPrefix(name, ":") {
+					return errorResult("pseudo-header names start with ':': " + name), nil
+				}
+			}
+			fields = overridePseudoHeaders(fields, pseudo)
+		}
+	}
+
+	settings, err := http2SettingsArg(req)
+	if err != nil {
+		return errorResultFromErr("", err), nil
+	}
+	timeout := defaultHTTP2Timeout
+	if s := req.GetString("timeout", ""); s != "" {
+		d, err := time.ParseDuration(s)
+		if err != nil || d <= 0 {
+			return errorResult("invalid timeout duration: " + s), nil
+		}
+		timeout = d
+	}
+
+	rawRequest := http2RawRequest(fields, body)
+	if err := m.service.guard.CheckRequest(ctx, m.service.projectScope(), SendRequestInput{RawRequest: rawRequest, Target: target}); err != nil {
+		return errorResultFromErr("", err), nil
+	}
+
+	replayID = ids.Generate(ids.DefaultLength)
+	log.Printf("mcp/http2_send: %s sending %d header fields to %s (base=%s)", replayID, len(fields), target.origin(), baseID)
+	sendCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	result, err := sendHTTP2Frames(sendCtx, http2FrameInput{Target: target, Fields: fields, Body: body, Settings: settings})
+	if err != nil {
+		return errorResultFromErr("HTTP/2 request failed: ", err), nil
+	}
+	log.Printf("mcp/http2_send: %s completed in %v (status=%d, rst=%s, goaway=%s)", replayID, result.Duration, result.Status, result.StreamError, result.GoAway)
+
+	respHeaders := result.head()
+	m.service.requestStore.Store(replayID, &store.RequestEntry{
+		Label:    req.GetString("label", ""),
+		Base:     baseID,
+		Request:  rawRequest,
+		Target:   target.origin(),
+		Headers:  respHeaders,
+		Body:     result.Body,
+		Duration: result.Duration,
+	})
+	m.service.correlateOast(ctx, replayID, rawRequest)
+
+	resp := protocol.HTTP2SendResponse{
+		ReplayID:    replayID,
+		Duration:    result.Duration.String(),
+		StreamError: result.StreamError,
+		GoAway:      result.GoAway,
+	}
+	for _, f := range fields {
+		resp.Sent = append(resp.Sent, f.String())
+	}
+	if respHeaders != nil {
+		code, statusLine := parseResponseStatus(respHeaders)
+		resp.ResponseDetails = &protocol.ResponseDetails{
+			Status:      code,
+			StatusLine:  statusLine,
+			RespHeaders: string(respHeaders),
+			RespSize:    len(result.Body),
+			RespPreview: previewBody(result.Body, responsePreviewSize),
+		}
+	}
+	return jsonResult(resp)
+}
+
+// http2SettingsArg reads the settings object in the order of http2Settings IDs.
+func http2SettingsArg(req mcp.CallToolRequest) ([]http2.Setting, error) {
+	obj, _ := req.GetArguments()["settings"].(map[string]interface{})
+	var settings []http2.Setting
+	for name, v := range obj {
+		id, ok := http2Settings[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown setting %q", name)
+		}
+		n, ok := v.(float64)
+		if !ok || n < 0 || n > 1<<32-1 || n != float64(uint32(n)) {
+			return nil, fmt.Errorf("setting %s must be a 32-bit unsigned integer", name)
+		}
+		settings = append(settings, http2.Setting{ID: id, Val: uint32(n)})
+	}
+	slices.SortFunc(settings, func(a, b http2.Setting) int { return int(a.ID) - int(b.ID) })
+	return settings, nil
+}
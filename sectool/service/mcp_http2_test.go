@@ -0,0 +1,160 @@
+package service
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/go-harden/llm-security-toolbox/sectool/config"
+	"github.com/go-harden/llm-security-toolbox/sectool/protocol"
+)
+
+func TestHTTP2RequestFields(t *testing.T) {
+	t.Parallel()
+
+	raw := []byte("POST /api?x=1 HTTP/1.1\r\nHost: example.com\r\nConnection: keep-alive\r\nTE: trailers\r\nX-Custom: A\r\nContent-Length: 2\r\n\r\nhi")
+	fields, body := http2RequestFields(raw, Target{Hostname: "example.com", Port: 443, UsesHTTPS: true})
+	var lines []string
+	for _, f := range fields {
+		lines = append(lines, f.String())
+	}
+	assert.Equal(t, []string{
+		":method: POST", ":scheme: https", ":authority: example.com", ":path: /api?x=1",
+		"te: trailers", "x-custom: A", "content-length: 2",
+	}, lines)
+	assert.Equal(t, "hi", string(body))
+
+	fields = overridePseudoHeaders(fields, map[string]string{":path": "/admin", ":scheme": "", ":protocol": "websocket"})
+	lines = lines[:0]
+	for _, f := range fields[:4] {
+		lines = append(lines, f.String())
+	}
+	assert.Equal(t, []string{":method: POST", ":authority: example.com", ":path: /admin", ":protocol: websocket"}, lines)
+
+	f, ok := parseH2Field(":path: /x: y")
+	require.True(t, ok)
+	assert.Equal(t, h2Field{Name: ":path", Value: "/x: y"}, f)
+	_, ok = parseH2Field("no-colon")
+	assert.False(t, ok)
+}
+
+func TestMCP_HTTP2Send(t *testing.T) {
+	t.Parallel()
+
+	var mu sync.Mutex
+	var got *http.Request
+	var gotBody string
+	h2c := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		got, gotBody = r, string(body)
+		mu.Unlock()
+		w.Header().Set("X-Proto", r.Proto)
+		_, _ = w.Write([]byte("h2 ok " + r.Method + " " + r.URL.RequestURI()))
+	}))
+	var protocols http.Protocols
+	protocols.SetUnencryptedHTTP2(true)
+	h2c.Config.Protocols = &protocols
+	h2c.Start()
+	t.Cleanup(h2c.Close)
+	host := strings.TrimPrefix(h2c.URL, "http://")
+	received := func() (*http.Request, string) {
+		mu.Lock()
+		defer mu.Unlock()
+		return got, gotBody
+	}
+
+	srv, mcpClient, _, _, _ := setupMCPServerWithMock(t)
+
+	t.Run("raw_base", func(t *testing.T) {
+		resp := CallMCPToolJSONOK[protocol.HTTP2SendResponse](t, mcpClient, "http2_send", map[string]interface{}{
+			"raw":         "GET /start HTTP/1.1\r\nHost: " + host + "\r\nX-Test: 1\r\n\r\n",
+			"target":      h2c.URL,
+			"pseudo":      map[string]interface{}{":path": "/over", ":method": "POST"},
+			"add_headers": []interface{}{"x-added: yes"},
+			"body":        "payload",
+			"settings":    map[string]interface{}{"initial_window_size": 1 << 20},
+			"label":       "h2-probe",
+		})
+		require.NotNil(t, resp.ResponseDetails)
+		assert.Equal(t, 200, resp.Status)
+		assert.Contains(t, resp.RespHeaders, "HTTP/2 200")
+		assert.Equal(t, "h2 ok POST /over", resp.RespPreview)
+		assert.Contains(t, resp.Sent, ":path: /over")
+		assert.Contains(t, resp.Sent, "x-added: yes")
+
+		r, body := received()
+		assert.Equal(t, "HTTP/2.0", r.Proto)
+		assert.Equal(t, "1", r.Header.Get("X-Test"))
+		assert.Equal(t, "yes", r.Header.Get("X-Added"))
+		assert.Equal(t, "payload", body)
+
+		replay := CallMCPToolJSONOK[protocol.ReplayGetResponse](t, mcpClient, "replay_get", map[string]interface{}{"replay_id": "h2-probe"})
+		assert.Equal(t, resp.ReplayID, replay.ReplayID)
+		assert.Equal(t, 200, replay.Status)
+	})
+
+	t.Run("replay_base", func(t *testing.T) {
+		first := CallMCPToolJSONOK[protocol.HTTP2SendResponse](t, mcpClient, "http2_send", map[string]interface{}{
+			"raw":    "GET /first HTTP/1.1\r\nHost: " + host + "\r\n\r\n",
+			"target": h2c.URL,
+		})
+		resp := CallMCPToolJSONOK[protocol.HTTP2SendResponse](t, mcpClient, "http2_send", map[string]interface{}{
+			"replay_id": first.ReplayID,
+			"target":    h2c.URL,
+		})
+		require.NotNil(t, resp.ResponseDetails)
+		assert.Equal(t, "h2 ok GET /first", resp.RespPreview)
+	})
+
+	t.Run("verbatim_invalid_order", func(t *testing.T) {
+		resp := CallMCPToolJSONOK[protocol.HTTP2SendResponse](t, mcpClient, "http2_send", map[string]interface{}{
+			"raw":    "GET / HTTP/1.1\r\nHost: " + host + "\r\n\r\n",
+			"target": h2c.URL,
+			"headers": []interface{}{
+				":method: GET", ":scheme: http", "x-early: 1", ":path: /", ":authority: " + host,
+			},
+		})
+		assert.Equal(t, []string{":method: GET", ":scheme: http", "x-early: 1", ":path: /", ":authority: " + host}, resp.Sent)
+		assert.Nil(t, resp.ResponseDetails)
+		assert.Equal(t, "PROTOCOL_ERROR", resp.StreamError)
+	})
+
+	t.Run("validation", func(t *testing.T) {
+		base := "GET / HTTP/1.1\r\nHost: " + host + "\r\n\r\n"
+		for name, args := range map[string]map[string]interface{}{
+			"no_base":       {},
+			"two_bases":     {"raw": base, "replay_id": "x"},
+			"bad_header":    {"raw": base, "add_headers": []interface{}{"nocolon"}},
+			"bad_pseudo":    {"raw": base, "pseudo": map[string]interface{}{"path": "/"}},
+			"bad_setting":   {"raw": base, "settings": map[string]interface{}{"nope": 1}},
+			"setting_range": {"raw": base, "settings": map[string]interface{}{"max_frame_size": -1}},
+			"bad_timeout":   {"raw": base, "timeout": "soon"},
+		} {
+			result := CallMCPTool(t, mcpClient, "http2_send", args)
+			assert.True(t, result.IsError, name)
+		}
+	})
+
+	t.Run("out_of_scope", func(t *testing.T) {
+		srv.scopeMu.Lock()
+		srv.scope = &config.Scope{Include: []string{"*.example.com"}}
+		srv.scopeMu.Unlock()
+		t.Cleanup(func() {
+			srv.scopeMu.Lock()
+			srv.scope = nil
+			srv.scopeMu.Unlock()
+		})
+		result := CallMCPTool(t, mcpClient, "http2_send", map[string]interface{}{
+			"raw": "GET / HTTP/1.1\r\nHost: " + host + "\r\n\r\n",
+		})
+		assert.True(t, result.IsError)
+		assert.Contains(t, ExtractMCPText(t, result), "out of scope")
+	})
+}
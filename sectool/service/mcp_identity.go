@@ -0,0 +1,270 @@
+package service
+
+import (
+	"context"
+	"log"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/go-harden/llm-security-toolbox/sectool/protocol"
+	"github.com/go-harden/llm-security-toolbox/sectool/service/ids"
+	"github.com/go-harden/llm-security-toolbox/sectool/service/store"
+)
+
+const (
+	// authzOriginal names the unmodified request in an authz matrix.
+	authzOriginal = "original"
+
+	// authzSameThreshold is the body similarity at which a response counts as the baseline's.
+	authzSameThreshold = 0.95
+)
+
+// Verdicts of an authz matrix row.
+const (
+	authzBaseline   = "baseline"
+	authzSame       = "same"
+	authzDifferent  = "different"
+	authzDenied     = "denied"
+	authzRedirected = "redirected"
+	authzError      = "error"
+)
+
+func (m *mcpServer) identitySetTool() mcp.Tool {
+	return mcp.NewTool("identity_set",
+		mcp.WithDescription(`Create, replace or delete a named identity for authorization testing (.sectool/identities.json).
+
+An identity is the credential set of one user: headers (e.g., 'Authorization: Bearer ...'), cookies, and headers to strip.
+replay_send replay_as=<name> and authz_matrix apply it after all edits: remove_headers first, then headers replace same-named ones, then cookies merge into the Cookie header by name.
+For an unauthenticated identity, set only remove_headers: ["Authorization", "Cookie"].`),
+		mcp.WithString("name", mcp.Required(), mcp.Description("Identity name (e.g., 'admin', 'user_a', 'anonymous')")),
+		mcp.WithArray("headers", mcp.Items(map[string]interface{}{"type": "string"}), mcp.Description("Headers to set (format: 'Name: Value')")),
+		mcp.WithObject("cookies", mcp.Description(`Cookies to set: {"session": "abc"}`)),
+		mcp.WithArray("remove_headers", mcp.Items(map[string]interface{}{"type": "string"}), mcp.Description("Header names to remove (e.g., another user's Authorization)")),
+		mcp.WithBoolean("delete", mcp.Description("Delete the identity instead")),
+		annotateLocalChange,
+	)
+}
+
+func (m *mcpServer) identityListTool() mcp.Tool {
+	return mcp.NewTool("identity_list",
+		mcp.WithDescription("List identities for replay_as and authz_matrix."),
+		annotateReadOnly,
+	)
+}
+
+func (m *mcpServer) authzMatrixTool() mcp.Tool {
+	return mcp.NewTool("authz_matrix",
+		mcp.WithDescription(`Replay one request as the original sender and as every identity, and compare the responses to find IDOR/BOLA and missing function-level authorization.
+
+Each response is compared to the baseline (default: the original request, i.e. the resource owner) by status and noise-filtered body similarity.
+Verdicts: same (served the baseline response: suspicious unless the identity should have access), different, denied (401/403/404), redirected (3xx), error.
+Every send is stored as a replay; inspect with replay_get or compare with replay_diff. Use a flow that reads or changes another user's object.`),
+		mcp.WithString("flow_id", mcp.Description("Flow to replay, or "+recentRefUsage+" proxy entry (exclusive with replay_id)")),
+		mcp.WithString("replay_id", mcp.Description("Replay to use as the request (exclusive with flow_id)")),
+		mcp.WithArray("identities", mcp.Items(map[string]interface{}{"type": "string"}), mcp.Description("Identities to replay as (default: all)")),
+		mcp.WithString("baseline", mcp.Description("Identity whose response is the expected one (default: 'original', the unmodified request)")),
+		mcp.WithString("timeout", mcp.Description("Per-request timeout (e.g., '30s')")),
+		annotateSendsTraffic,
+	)
+}
+
+func (m *mcpServer) handleIdentitySet(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if err := m.requireWorkflow(); err != nil {
+		return err, nil
+	}
+
+	name := strings.TrimSpace(req.GetString("name", ""))
+	if name == "" {
+		return errorResult("name is required"), nil
+	} else if name == authzOriginal {
+		return errorResult("'" + authzOriginal + "' is reserved for the unmodified request"), nil
+	}
+
+	if req.GetBool("delete", false) {
+		file, err := m.service.identities.Delete(name)
+		if err != nil {
+			return errorResultFromErr("", err), nil
+		}
+		log.Printf("mcp/identity_set: deleted identity %s", name)
+		return jsonResult(identityListResponse(file))
+	}
+
+	id := &identity{
+		Headers:       req.GetStringSlice("headers", nil),
+		Cookies:       stringMapArg(req, "cookies"),
+		RemoveHeaders: req.GetStringSlice("remove_headers", nil),
+	}
+	for _, h := range id.Headers {
+		if headerName, _, ok := strings.Cut(h, ":"); !ok || strings.TrimSpace(headerName) == "" {
+			return errorResult("invalid header " + h + ": expected 'Name: Value'"), nil
+		}
+	}
+	if len(id.Headers) == 0 && len(id.Cookies) == 0 && len(id.RemoveHeaders) == 0 {
+		return errorResult("set at least one of headers, cookies or remove_headers"), nil
+	}
+
+	file, err := m.service.identities.Set(name, id)
+	if err != nil {
+		return errorResultFromErr("failed to save identity: ", err), nil
+	}
+	log.Printf("mcp/identity_set: saved identity %s", name)
+	return jsonResult(identityListResponse(file))
+}
+
+func (m *mcpServer) handleIdentityList(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if err := m.requireWorkflow(); err != nil {
+		return err, nil
+	}
+
+	file, err := m.service.identities.Load()
+	if err != nil {
+		return errorResultFromErr("failed to load identities: ", err), nil
+	}
+	return jsonResult(identityListResponse(file))
+}
+
+func identityListResponse(file *identitiesFile) protocol.IdentityListResponse {
+	resp := protocol.IdentityListResponse{Identities: make([]protocol.Identity, 0, len(file.Identities))}
+	for _, name := range slices.Sorted(maps.Keys(file.Identities)) {
+		id := file.Identities[name]
+		resp.Identities = append(resp.Identities, protocol.Identity{
+			Name:          name,
+			Headers:       id.Headers,
+			Cookies:       id.Cookies,
+			RemoveHeaders: id.RemoveHeaders,
+		})
+	}
+	return resp
+}
+
+// authzSend is one request of an authz matrix and its response.
+type authzSend struct {
+	name     string
+	replayID string
+	result   *SendRequestResult
+	err      error
+}
+
+func (m *mcpServer) handleAuthzMatrix(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if err := m.requireWorkflow(); err != nil {
+		return err, nil
+	}
+
+	flowID, replayID := req.GetString("flow_id", ""), req.GetString("replay_id", "")
+	if (flowID == "") == (replayID == "") {
+		return errorResult("exactly one of flow_id or replay_id is required"), nil
+	}
+	var timeout time.Duration
+	if s := req.GetString("timeout", ""); s != "" {
+		var err error
+		if timeout, err = time.ParseDuration(s); err != nil {
+			return errorResult("invalid timeout duration: " + err.Error()), nil
+		}
+	}
+
+	file, err := m.service.identities.Load()
+	if err != nil {
+		return errorResultFromErr("failed to load identities: ", err), nil
+	}
+	names := req.GetStringSlice("identities", nil)
+	if len(names) == 0 {
+		names = slices.Sorted(maps.Keys(file.Identities))
+	}
+	if len(names) == 0 {
+		return errorResult("no identities defined: create them with identity_set"), nil
+	}
+	for _, name := range names {
+		if _, ok := file.Identities[name]; !ok {
+			return errorResult("identity " + name + " not found"), nil
+		}
+	}
+	baseline := req.GetString("baseline", authzOriginal)
+	if baseline != authzOriginal && !slices.Contains(names, baseline) {
+		return errorResult("baseline must be '" + authzOriginal + "' or one of the replayed identities"), nil
+	}
+
+	rawRequest, baseTarget, base, err := m.service.loadBaseRequest(ctx, flowID, replayID)
+	if err != nil {
+		return errorResultFromErr("", err), nil
+	}
+	host, port, usesHTTPS := parseTarget(rawRequest, baseTarget)
+	target := Target{Hostname: host, Port: port, UsesHTTPS: usesHTTPS}
+
+	log.Printf("mcp/authz_matrix: replaying %s as %d identities (baseline %s)", base, len(names), baseline)
+	sends := make([]authzSend, 0, len(names)+1)
+	for _, name := range append([]string{authzOriginal}, names...) {
+		raw := rawRequest
+		if name != authzOriginal {
+			raw = file.Identities[name].Apply(rawRequest)
+		}
+		send := authzSend{name: name, replayID: ids.Generate(ids.DefaultLength)}
+		send.result, send.err = m.service.sendRequest(ctx, "sectool-"+send.replayID, SendRequestInput{
+			RawRequest: raw,
+			Target:     target,
+			Timeout:    timeout,
+		}, nil)
+		if send.err == nil {
+			m.service.requestStore.Store(send.replayID, &store.RequestEntry{
+				Base:     base,
+				Request:  raw,
+				Target:   target.origin(),
+				Headers:  send.result.Headers,
+				Body:     send.result.Body,
+				Duration: send.result.Duration,
+			})
+		} else if ctx.Err() != nil {
+			return errorResultFromErr("authz matrix interrupted: ", ctx.Err()), nil
+		}
+		sends = append(sends, send)
+	}
+
+	resp := protocol.AuthzMatrixResponse{Base: base, Baseline: baseline}
+	ref := sends[slices.IndexFunc(sends, func(s authzSend) bool { return s.name == baseline })]
+	if ref.err != nil {
+		return errorResultFromErr("baseline request failed: ", ref.err), nil
+	}
+	refStatus, _ := parseResponseStatus(ref.result.Headers)
+	for _, send := range sends {
+		row := protocol.AuthzResult{Identity: send.name}
+		if send.err != nil {
+			row.Verdict, row.Error = authzError, send.err.Error()
+			resp.Results = append(resp.Results, row)
+			continue
+		}
+		row.ReplayID = send.replayID
+		row.Status, _ = parseResponseStatus(send.result.Headers)
+		row.Size = len(send.result.Body)
+		row.Similarity = diffBodies(string(ref.result.Headers), ref.result.Body,
+			string(send.result.Headers), send.result.Body, diffOptions{noise: true}).Similarity
+		row.Verdict = authzVerdict(send.name == baseline, row.Status, refStatus, row.Similarity)
+		// The original request is sent with its captured credentials, so matching is expected
+		if row.Verdict == authzSame && send.name != authzOriginal {
+			resp.Suspicious = append(resp.Suspicious, send.name)
+		}
+		resp.Results = append(resp.Results, row)
+	}
+
+	log.Printf("mcp/authz_matrix: %s done, %d suspicious", base, len(resp.Suspicious))
+	return jsonResult(resp)
+}
+
+// authzVerdict classifies a response against the baseline response.
+func authzVerdict(isBaseline bool, status, baselineStatus int, similarity float64) string {
+	switch {
+	case isBaseline:
+		return authzBaseline
+	case status == 401 || status == 403 || status == 404:
+		return authzDenied
+	case status >= 300 && status < 400:
+		return authzRedirected
+	case status >= 500:
+		return authzError
+	case status == baselineStatus && similarity >= authzSameThreshold:
+		return authzSame
+	}
+	return authzDifferent
+}
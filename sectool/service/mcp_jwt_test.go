@@ -0,0 +1,193 @@
+package service
+
+import (
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/go-harden/llm-security-toolbox/sectool/jwt"
+	"github.com/go-harden/llm-security-toolbox/sectool/protocol"
+)
+
+func TestMCP_JWTCrack(t *testing.T) {
+	t.Parallel()
+
+	_, mcpClient, _, _, _ := setupMCPServerWithMock(t)
+
+	token, err := jwt.Sign(map[string]interface{}{"typ": "JWT"}, map[string]interface{}{"sub": "42", "role": "user"}, "HS256", []byte("hunter2"))
+	require.NoError(t, err)
+
+	t.Run("wordlist_file_and_forge", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "words.txt")
+		require.NoError(t, os.WriteFile(path, []byte("letmein\nhunter2\n"), 0o644))
+
+		resp := CallMCPToolJSONOK[protocol.JWTCrackResponse](t, mcpClient, "jwt_crack", map[string]interface{}{
+			"token":         token,
+			"wordlist":      []string{"password"},
+			"wordlist_file": path,
+			"set_claims":    map[string]interface{}{"role": "admin"},
+		})
+		assert.True(t, resp.Found)
+		assert.Equal(t, "hunter2", resp.Secret)
+		assert.Equal(t, jwt.StopFound, resp.Stopped)
+		assert.Equal(t, []string{"role"}, resp.ForgedClaims)
+
+		forged, err := jwt.Parse(resp.ForgedToken)
+		require.NoError(t, err)
+		assert.Equal(t, "admin", forged.Claims["role"])
+		assert.Equal(t, "42", forged.Claims["sub"])
+	})
+
+	t.Run("not_found", func(t *testing.T) {
+		resp := CallMCPToolJSONOK[protocol.JWTCrackResponse](t, mcpClient, "jwt_crack", map[string]interface{}{
+			"token": token,
+		})
+		assert.False(t, resp.Found)
+		assert.Equal(t, jwt.StopExhausted, resp.Stopped)
+		assert.Equal(t, len(jwt.CommonSecrets), resp.Tried)
+		assert.Empty(t, resp.ForgedToken)
+	})
+
+	t.Run("validation", func(t *testing.T) {
+		result := CallMCPTool(t, mcpClient, "jwt_crack", map[string]interface{}{"token": "abc"})
+		assert.True(t, result.IsError)
+		assert.Contains(t, ExtractMCPText(t, result), "invalid JWT")
+
+		rs256, err := jwt.Parse("eyJhbGciOiJSUzI1NiJ9.e30.c2ln")
+		require.NoError(t, err)
+		result = CallMCPTool(t, mcpClient, "jwt_crack", map[string]interface{}{"token": "eyJhbGciOiJSUzI1NiJ9.e30.c2ln"})
+		assert.True(t, result.IsError)
+		assert.Contains(t, ExtractMCPText(t, result), "unsupported algorithm "+rs256.Algorithm())
+	})
+}
+
+func TestMCP_JWTDecode(t *testing.T) {
+	t.Parallel()
+
+	_, mcpClient, mockMCP, _, _ := setupMCPServerWithMock(t)
+
+	token, err := jwt.Sign(map[string]interface{}{"kid": "k1"}, map[string]interface{}{"sub": "42", "exp": float64(1)}, "HS256", []byte("hunter2"))
+	require.NoError(t, err)
+
+	t.Run("text", func(t *testing.T) {
+		resp := CallMCPToolJSONOK[protocol.JWTDecodeResponse](t, mcpClient, "jwt_decode", map[string]interface{}{
+			"text": "Cookie: theme=dark; session=" + token,
+		})
+		require.Len(t, resp.Tokens, 1)
+		decoded := resp.Tokens[0]
+		assert.Equal(t, "HS256", decoded.Algorithm)
+		assert.Equal(t, "42", decoded.Claims["sub"])
+		assert.Equal(t, "1970-01-01T00:00:01Z", decoded.ExpiresAt)
+		assert.True(t, decoded.Expired)
+		assert.Len(t, decoded.Hints, 2)
+	})
+
+	t.Run("flow", func(t *testing.T) {
+		mockMCP.AddProxyEntry(
+			"GET /api/me HTTP/1.1\r\nHost: example.com\r\nAuthorization: Bearer "+token+"\r\n\r\n",
+			"HTTP/1.1 200 OK\r\n\r\n{\"refresh\":\"eyJhbGciOiJub25lIn0.eyJzdWIiOiI0MiJ9.\"}",
+			"",
+		)
+		resp := CallMCPToolJSONOK[protocol.JWTDecodeResponse](t, mcpClient, "jwt_decode", map[string]interface{}{
+			"flow_id": "last",
+		})
+		require.Len(t, resp.Tokens, 2)
+		assert.Equal(t, token, resp.Tokens[0].Token)
+		assert.Equal(t, "none", resp.Tokens[1].Algorithm)
+	})
+
+	t.Run("jwe", func(t *testing.T) {
+		header := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"RSA1_5","enc":"A128CBC-HS256","cty":"JWT"}`))
+		jwe := header + ".AAAA.AAAAAAAAAAAAAAAAAAAAAA.Y2lwaGVy.AAAAAAAAAAAAAAAAAAAAAA"
+
+		resp := CallMCPToolJSONOK[protocol.JWTDecodeResponse](t, mcpClient, "jwt_decode", map[string]interface{}{
+			"text": "Authorization: Bearer " + token + "\r\nCookie: enc=" + jwe,
+		})
+		require.Len(t, resp.Tokens, 1)
+		require.Len(t, resp.Encrypted, 1)
+		decoded := resp.Encrypted[0]
+		assert.Equal(t, jwe, decoded.Token)
+		assert.Equal(t, "RSA1_5", decoded.Algorithm)
+		assert.Equal(t, "A128CBC-HS256", decoded.Encryption)
+		assert.Equal(t, 3, decoded.EncryptedKeyBytes)
+		assert.Equal(t, 16, decoded.IVBytes)
+		assert.Equal(t, 6, decoded.CiphertextBytes)
+		assert.Len(t, decoded.Hints, 3)
+
+		resp = CallMCPToolJSONOK[protocol.JWTDecodeResponse](t, mcpClient, "jwt_decode", map[string]interface{}{"token": jwe})
+		assert.Empty(t, resp.Tokens)
+		assert.Len(t, resp.Encrypted, 1)
+	})
+
+	t.Run("validation", func(t *testing.T) {
+		result := CallMCPTool(t, mcpClient, "jwt_decode", map[string]interface{}{})
+		assert.True(t, result.IsError)
+		result = CallMCPTool(t, mcpClient, "jwt_decode", map[string]interface{}{"text": "no tokens here"})
+		assert.True(t, result.IsError)
+		assert.Contains(t, ExtractMCPText(t, result), "no JWT found")
+	})
+}
+
+func TestMCP_JWTForge(t *testing.T) {
+	t.Parallel()
+
+	_, mcpClient, _, _, _ := setupMCPServerWithMock(t)
+
+	token, err := jwt.Sign(map[string]interface{}{"typ": "JWT"}, map[string]interface{}{"sub": "42", "role": "user", "iat": float64(1)}, "HS256", []byte("secret"))
+	require.NoError(t, err)
+
+	t.Run("none", func(t *testing.T) {
+		resp := CallMCPToolJSONOK[protocol.JWTForgeResponse](t, mcpClient, "jwt_forge", map[string]interface{}{
+			"token":         token,
+			"alg":           "none",
+			"set_claims":    map[string]interface{}{"role": "admin"},
+			"remove_claims": []string{"iat"},
+		})
+		assert.Equal(t, []string{"iat", "role"}, resp.Changed)
+		assert.Equal(t, "Authorization: Bearer "+resp.Token, resp.AddHeader)
+		forged, err := jwt.Parse(resp.Token)
+		require.NoError(t, err)
+		assert.Equal(t, "none", forged.Algorithm())
+		assert.Empty(t, forged.Signature)
+		assert.Equal(t, map[string]interface{}{"sub": "42", "role": "admin"}, forged.Claims)
+	})
+
+	t.Run("guess_secret", func(t *testing.T) {
+		resp := CallMCPToolJSONOK[protocol.JWTForgeResponse](t, mcpClient, "jwt_forge", map[string]interface{}{
+			"token":        token,
+			"guess_secret": true,
+			"set_header":   map[string]interface{}{"kid": "0"},
+			"expires_in":   "1h",
+			"header_name":  "X-Auth-Token",
+		})
+		assert.Equal(t, "secret", resp.Secret)
+		assert.Equal(t, []string{"exp"}, resp.Changed)
+		assert.Equal(t, "X-Auth-Token: "+resp.Token, resp.AddHeader)
+
+		forged, err := jwt.Parse(resp.Token)
+		require.NoError(t, err)
+		assert.Equal(t, "0", forged.Header["kid"])
+		res, err := jwt.Crack(t.Context(), forged, slices.Values([]string{"secret"}), jwt.CrackOptions{})
+		require.NoError(t, err)
+		assert.True(t, res.Found)
+	})
+
+	t.Run("validation", func(t *testing.T) {
+		result := CallMCPTool(t, mcpClient, "jwt_forge", map[string]interface{}{"token": token})
+		assert.True(t, result.IsError)
+		assert.Contains(t, ExtractMCPText(t, result), "requires secret or guess_secret")
+
+		result = CallMCPTool(t, mcpClient, "jwt_forge", map[string]interface{}{"token": token, "alg": "RS256"})
+		assert.True(t, result.IsError)
+		assert.Contains(t, ExtractMCPText(t, result), "requires private_key")
+
+		result = CallMCPTool(t, mcpClient, "jwt_forge", map[string]interface{}{"token": token, "alg": "RS256", "private_key": "nope"})
+		assert.True(t, result.IsError)
+		assert.Contains(t, ExtractMCPText(t, result), "no PEM block")
+	})
+}
@@ -0,0 +1,102 @@
+package service
+
+import (
+	"context"
+	"log"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/go-harden/llm-security-toolbox/sectool/protocol"
+)
+
+const defaultNoteLimit = 50
+
+func (m *mcpServer) noteAddTool() mcp.Tool {
+	return mcp.NewTool("note_add",
+		mcp.WithDescription(`Save an observation to the project notes (.sectool/notes.jsonl).
+
+Notes persist across context resets and service restarts. Record anything worth recalling later: credentials and test accounts, interesting endpoints, hypotheses, dead ends, and flow_id/replay_id evidence.
+
+Set refs to attach the note to flows, replays, OAST sessions or events, or findings; the findings report shows each note under the findings it references.`),
+		mcp.WithString("text", mcp.Required(), mcp.Description("Note text")),
+		mcp.WithArray("tags", mcp.Items(map[string]interface{}{"type": "string"}), mcp.Description("Tags for filtering (e.g., 'creds', 'endpoint', 'hypothesis')")),
+		mcp.WithArray("refs", mcp.Items(map[string]interface{}{"type": "string"}), mcp.Description("IDs the note is about: flow_id, replay_id, oast_id, OAST event_id or finding_id")),
+		annotateLocalChange,
+	)
+}
+
+func (m *mcpServer) noteListTool() mcp.Tool {
+	return mcp.NewTool("note_list",
+		mcp.WithDescription("List project notes, newest first. Review at the start of a session to recover earlier context."),
+		mcp.WithString("tag", mcp.Description("Only notes with this tag")),
+		mcp.WithString("ref", mcp.Description("Only notes attached to this ID")),
+		mcp.WithNumber("limit", mcp.Description("Max notes to return (default 50, 0 for all)")),
+		annotateReadOnly,
+	)
+}
+
+func (m *mcpServer) noteSearchTool() mcp.Tool {
+	return mcp.NewTool("note_search",
+		mcp.WithDescription("Search project notes for all given words (case-insensitive, text and tags), newest first."),
+		mcp.WithString("query", mcp.Required(), mcp.Description("Words to search for")),
+		mcp.WithString("tag", mcp.Description("Only notes with this tag")),
+		mcp.WithString("ref", mcp.Description("Only notes attached to this ID")),
+		mcp.WithNumber("limit", mcp.Description("Max notes to return (default 50, 0 for all)")),
+		annotateReadOnly,
+	)
+}
+
+func (m *mcpServer) handleNoteAdd(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if err := m.requireWorkflow(); err != nil {
+		return err, nil
+	}
+
+	text := strings.TrimSpace(req.GetString("text", ""))
+	if text == "" {
+		return errorResult("text is required"), nil
+	}
+
+	note, err := m.service.notes.Add(text, req.GetStringSlice("tags", nil), trimValues(req.GetStringSlice("refs", nil)))
+	if err != nil {
+		return errorResultFromErr("failed to save note: ", err), nil
+	}
+	log.Printf("mcp/note_add: saved %s", note.NoteID)
+	return jsonResult(note)
+}
+
+func (m *mcpServer) handleNoteList(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if err := m.requireWorkflow(); err != nil {
+		return err, nil
+	}
+
+	return m.listNotes(req.GetString("tag", ""), req.GetString("ref", ""), "", req.GetInt("limit", defaultNoteLimit))
+}
+
+func (m *mcpServer) handleNoteSearch(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if err := m.requireWorkflow(); err != nil {
+		return err, nil
+	}
+
+	query := req.GetString("query", "")
+	if strings.TrimSpace(query) == "" {
+		return errorResult("query is required"), nil
+	}
+	return m.listNotes(req.GetString("tag", ""), req.GetString("ref", ""), query, req.GetInt("limit", defaultNoteLimit))
+}
+
+func (m *mcpServer) listNotes(tag, ref, query string, limit int) (*mcp.CallToolResult, error) {
+	notes, err := m.service.notes.List(func(n protocol.Note) bool { return noteMatches(n, tag, ref, query) })
+	if err != nil {
+		return errorResultFromErr("failed to read notes: ", err), nil
+	}
+
+	resp := protocol.NoteListResponse{Notes: notes, Total: len(notes)}
+	if limit > 0 && len(resp.Notes) > limit {
+		resp.Notes = resp.Notes[:limit]
+	}
+	if resp.Notes == nil {
+		resp.Notes = []protocol.Note{}
+	}
+	return jsonResult(resp)
+}
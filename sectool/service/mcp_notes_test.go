@@ -0,0 +1,81 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/go-harden/llm-security-toolbox/sectool/protocol"
+)
+
+func TestMCP_Notes(t *testing.T) {
+	t.Parallel()
+
+	srv, mcpClient, _, _, _ := setupMCPServerWithMock(t)
+
+	first := CallMCPToolJSONOK[protocol.Note](t, mcpClient, "note_add", map[string]interface{}{
+		"text": "Test account alice / Winter2024! works on staging",
+		"tags": []string{"creds"},
+	})
+	assert.NotEmpty(t, first.NoteID)
+	assert.NotEmpty(t, first.CreatedAt)
+	CallMCPToolJSONOK[protocol.Note](t, mcpClient, "note_add", map[string]interface{}{
+		"text": "Hypothesis: /api/orders/{id} lacks owner check",
+		"tags": []string{"hypothesis", "endpoint"},
+	})
+
+	t.Run("list_newest_first", func(t *testing.T) {
+		resp := CallMCPToolJSONOK[protocol.NoteListResponse](t, mcpClient, "note_list", nil)
+		require.Len(t, resp.Notes, 2)
+		assert.Equal(t, first.NoteID, resp.Notes[1].NoteID)
+
+		limited := CallMCPToolJSONOK[protocol.NoteListResponse](t, mcpClient, "note_list", map[string]interface{}{"limit": 1})
+		assert.Len(t, limited.Notes, 1)
+		assert.Equal(t, 2, limited.Total)
+	})
+
+	t.Run("list_by_tag", func(t *testing.T) {
+		resp := CallMCPToolJSONOK[protocol.NoteListResponse](t, mcpClient, "note_list", map[string]interface{}{"tag": "creds"})
+		require.Len(t, resp.Notes, 1)
+		assert.Equal(t, first.NoteID, resp.Notes[0].NoteID)
+	})
+
+	t.Run("search", func(t *testing.T) {
+		resp := CallMCPToolJSONOK[protocol.NoteListResponse](t, mcpClient, "note_search", map[string]interface{}{"query": "ORDERS owner"})
+		require.Len(t, resp.Notes, 1)
+		assert.Contains(t, resp.Notes[0].Text, "/api/orders")
+
+		resp = CallMCPToolJSONOK[protocol.NoteListResponse](t, mcpClient, "note_search", map[string]interface{}{"query": "endpoint"})
+		assert.Len(t, resp.Notes, 1)
+
+		resp = CallMCPToolJSONOK[protocol.NoteListResponse](t, mcpClient, "note_search", map[string]interface{}{"query": "nothing-matches"})
+		assert.Empty(t, resp.Notes)
+	})
+
+	t.Run("validation", func(t *testing.T) {
+		assert.True(t, CallMCPTool(t, mcpClient, "note_add", map[string]interface{}{"text": "  "}).IsError)
+		assert.True(t, CallMCPTool(t, mcpClient, "note_search", map[string]interface{}{"query": ""}).IsError)
+	})
+
+	t.Run("persists_across_restart", func(t *testing.T) {
+		notes, err := newNotesStore(srv.projectDir).List(nil)
+		require.NoError(t, err)
+		assert.Len(t, notes, 2)
+	})
+
+	t.Run("refs", func(t *testing.T) {
+		note := CallMCPToolJSONOK[protocol.Note](t, mcpClient, "note_add", map[string]interface{}{
+			"text": "PDF renderer fetches attacker URLs",
+			"refs": []string{"abc123", " ev9 ", ""},
+		})
+		assert.Equal(t, []string{"abc123", "ev9"}, note.Refs)
+
+		resp := CallMCPToolJSONOK[protocol.NoteListResponse](t, mcpClient, "note_list", map[string]interface{}{"ref": "ev9"})
+		require.Len(t, resp.Notes, 1)
+		assert.Equal(t, note.NoteID, resp.Notes[0].NoteID)
+
+		resp = CallMCPToolJSONOK[protocol.NoteListResponse](t, mcpClient, "note_search", map[string]interface{}{"query": "abc123"})
+		assert.Len(t, resp.Notes, 1)
+	})
+}
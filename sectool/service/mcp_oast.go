@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"log"
+	"maps"
 	"sort"
 	"strings"
 	"time"
@@ -19,8 +20,11 @@ func (m *mcpServer) oastCreateTool() mcp.Tool {
 
 Returns {oast_id, domain} for blind out-of-band detection (DNS/HTTP/SMTP).
 Workflow: create -> inject domain in payload -> trigger target -> oast_poll -> oast_get for details.
-Use cases: blind SSRF, blind XXE, DNS exfiltration, email verification bypass.`),
+Use cases: blind SSRF, blind XXE, DNS exfiltration, email verification bypass.
+Use a distinct subdomain tag per payload (e.g., ssrf1.<domain>): replays that send it are remembered, and oast_poll/oast_get report them as correlated_with.
+When oast.notify_url or oast.notify_file is configured, each interaction is also pushed there as it arrives.`),
 		mcp.WithString("label", mcp.Description("Optional unique label for this session")),
+		annotateLocalChange,
 	)
 }
 
@@ -34,32 +38,65 @@ Output modes:
 
 Options:
 - Immediate: omit wait
-- Long-poll: set wait (e.g., '30s', max 120s)
+- Long-poll: set wait (e.g., '30s', max 120s); reports progress every 5s when the request has a progressToken
 - Incremental: use since parameter, accepts event_id, timestamp, or "last"
 - Filter by type: dns, http, smtp, ftp, ldap, smb, responder
+- Filter by subdomain_contains (e.g., the tag you planted) to drop scanner noise hitting the bare domain
+- Paginate (events mode): a full page includes next_cursor; pass it back as cursor to continue (replaces since)
 
-Response includes events/aggregates and optional dropped_count; use oast_get for full event details.`),
+Response includes events/aggregates and optional dropped_count; use oast_get for full event details.
+correlated_with lists the replay_ids (replay_send, request_send, request_craft, http2_send, grpc_send) whose request carried the interaction's hostname.`),
 		mcp.WithString("oast_id", mcp.Required(), mcp.Description("OAST session ID, label, or domain")),
 		mcp.WithString("output_mode", mcp.Description("Output mode: 'summary' (default) or 'events'")),
 		mcp.WithString("since", mcp.Description("event_id, timestamp (e.g., RFC3339, '2006-01-02 15:04:05', '15:04:05'), or 'last' (per-session cursor)")),
 		mcp.WithString("type", mcp.Description("Filter by event type: dns, http, smtp, ftp, ldap, smb, responder")),
+		mcp.WithString("subdomain_contains", mcp.Description("Only events whose subdomain contains this text (case-insensitive)")),
 		mcp.WithString("wait", mcp.Description("Long-poll duration (e.g., '30s', max 120s)")),
 		mcp.WithNumber("limit", mcp.Description("Maximum number of events to return")),
+		mcp.WithString("cursor", mcp.Description("Events mode: next_cursor from a previous page")),
+		annotateReadOnly,
 	)
 }
 
 func (m *mcpServer) oastGetTool() mcp.Tool {
-	return mcp.NewTool("oast_get",
-		mcp.WithDescription("Get full OAST event data: HTTP request/response, DNS query type/answer, SMTP headers/body."),
+	opts := []mcp.ToolOption{
+		mcp.WithDescription(`Get full OAST event data: HTTP request/response, DNS query type/answer, SMTP headers/body, LDAP requests.
+
+details adds parsed fields per protocol: smtp_from (MAIL FROM), smtp_header_from, smtp_to, smtp_subject, smtp_message_id;
+ldap_operation (bind, search), ldap_bind_dn, ldap_auth_method, ldap_base_dn (the path of a JNDI ldap:// URL), ldap_filter.
+
+Large payloads: pass offset/length (bytes) or start_line/end_line to replace one detail (field, default raw_request) with a window of it;
+continue from body_range.next_offset or next_line.`),
 		mcp.WithString("oast_id", mcp.Required(), mcp.Description("OAST session ID, label, or domain")),
 		mcp.WithString("event_id", mcp.Required(), mcp.Description("Event ID from oast_poll")),
-	)
+		mcp.WithString("field", mcp.Description("Detail to window: raw_request (default) or raw_response")),
+	}
+	opts = append(opts, bodyRangeOptions()...)
+	return mcp.NewTool("oast_get", append(opts, annotateReadOnly)...)
 }
 
 func (m *mcpServer) oastListTool() mcp.Tool {
 	return mcp.NewTool("oast_list",
 		mcp.WithDescription("List active OAST sessions."),
 		mcp.WithNumber("limit", mcp.Description("Maximum number of sessions to return")),
+		annotateReadOnly,
+	)
+}
+
+func (m *mcpServer) oastExpectTool() mcp.Tool {
+	return mcp.NewTool("oast_expect",
+		mcp.WithDescription(`Register an OAST expectation: the first interaction on a matching subdomain confirms it and creates a draft finding.
+
+Use a distinct subdomain tag per probe (e.g., ssrf-step3.<domain>), then expect it with subdomain "ssrf-step3" or a glob like "ssrf-*".
+Sessions with expectations are watched in the background, so no oast_poll is needed; interactions that already arrived also count.
+The draft finding (.sectool/findings/<finding_id>.json) records the title, severity, the matching event, and replay_id.
+Returns the expectation; oast_list shows its finding_id once confirmed.`),
+		mcp.WithString("oast_id", mcp.Required(), mcp.Description("OAST session ID, label, or domain")),
+		mcp.WithString("subdomain", mcp.Required(), mcp.Description("Subdomain tag or glob matched case-insensitively against interaction subdomains")),
+		mcp.WithString("title", mcp.Required(), mcp.Description("Finding title, e.g. 'Blind SSRF in webhook URL'")),
+		mcp.WithString("severity", mcp.Description("Finding severity: info, low, medium (default), high, critical")),
+		mcp.WithString("replay_id", mcp.Description("Replay that planted the payload, recorded as evidence")),
+		annotateLocalChange,
 	)
 }
 
@@ -67,6 +104,7 @@ func (m *mcpServer) oastDeleteTool() mcp.Tool {
 	return mcp.NewTool("oast_delete",
 		mcp.WithDescription("Delete an OAST session and stop monitoring its domain."),
 		mcp.WithString("oast_id", mcp.Required(), mcp.Description("OAST session ID, label, or domain")),
+		annotateDestructive,
 	)
 }
 func (m *mcpServer) handleOastCreate(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
@@ -81,6 +119,10 @@ func (m *mcpServer) handleOastCreate(ctx context.Context, req mcp.CallToolReques
 		return errorResultFromErr("failed to create OAST session: ", err), nil
 	}
 
+	if m.service.oastNotify != nil {
+		m.service.oastNotify.Watch(*sess)
+	}
+
 	log.Printf("mcp/oast_create: created session %s with domain %s (label=%q)", sess.ID, sess.Domain, sess.Label)
 	return jsonResult(protocol.OastCreateResponse{
 		OastID: sess.ID,
@@ -114,41 +156,72 @@ func (m *mcpServer) handleOastPoll(ctx context.Context, req mcp.CallToolRequest)
 	}
 
 	since := req.GetString("since", "")
+	if cursor := req.GetString("cursor", ""); cursor != "" {
+		pos, err := decodeCursor("oast_poll", cursor)
+		if err != nil {
+			return errorResult(err.Error()), nil
+		}
+		since = pos
+	}
+	client := m.client(ctx)
+	since = client.resolveSince(client.oastLast, oastID, since)
 	eventType := strings.ToLower(req.GetString("type", ""))
+	subdomainContains := strings.TrimSpace(req.GetString("subdomain_contains", ""))
 	limit := req.GetInt("limit", 0)
 
-	log.Printf("mcp/oast_poll: mode=%s session=%s (wait=%v since=%q type=%q limit=%d)", outputMode, oastID, wait, since, eventType, limit)
+	log.Printf("mcp/oast_poll: mode=%s session=%s (wait=%v since=%q type=%q subdomain_contains=%q limit=%d)",
+		outputMode, oastID, wait, since, eventType, subdomainContains, limit)
 
-	result, err := m.service.oastBackend.PollSession(ctx, oastID, since, eventType, wait, limit)
+	result, err := pollOastWithProgress(ctx, m.service.oastBackend, newProgressReporter(ctx, req), oastProgressInterval,
+		oastID, since, eventType, subdomainContains, wait, limit)
 	if err != nil {
 		if errors.Is(err, ErrNotFound) {
 			return errorResult("session not found"), nil
 		}
 		return errorResultFromErr("failed to poll session: ", err), nil
 	}
+	if len(result.Events) > 0 {
+		client.mark(client.oastLast, oastID, result.Events[len(result.Events)-1].ID)
+	}
+
+	var sessionID string
+	if sess, found, err := m.service.findOastSession(ctx, oastID); err == nil && found {
+		sessionID = sess.ID
+	}
 
 	switch outputMode {
 	case "events":
 		events := make([]protocol.OastEvent, len(result.Events))
 		for i, e := range result.Events {
 			events[i] = protocol.OastEvent{
-				EventID:   e.ID,
-				Time:      e.Time.UTC().Format(time.RFC3339),
-				Type:      e.Type,
-				SourceIP:  e.SourceIP,
-				Subdomain: e.Subdomain,
-				Details:   e.Details,
+				EventID:        e.ID,
+				Time:           e.Time.UTC().Format(time.RFC3339),
+				Type:           e.Type,
+				SourceIP:       e.SourceIP,
+				Subdomain:      e.Subdomain,
+				Details:        e.Details,
+				CorrelatedWith: m.service.oastUses.Lookup(sessionID, e.Subdomain),
 			}
 		}
 
+		// The backend caps at limit without reporting whether more remain, so a full page gets a cursor
+		var nextCursor string
+		if limit > 0 && len(events) == limit {
+			nextCursor = encodeCursor("oast_poll", events[len(events)-1].EventID)
+		}
+
 		log.Printf("mcp/oast_poll: session %s returned %d events", oastID, len(events))
 		return jsonResult(protocol.OastPollResponse{
 			Events:       events,
 			DroppedCount: result.DroppedCount,
+			NextCursor:   nextCursor,
 		})
 
 	default: // summary
 		agg := aggregateOastEvents(result.Events)
+		for i := range agg {
+			agg[i].CorrelatedWith = m.service.oastUses.Lookup(sessionID, agg[i].Subdomain)
+		}
 		log.Printf("mcp/oast_poll: session %s returned %d aggregates from %d events", oastID, len(agg), len(result.Events))
 		return jsonResult(protocol.OastPollResponse{
 			Aggregates:   agg,
@@ -157,6 +230,27 @@ func (m *mcpServer) handleOastPoll(ctx context.Context, req mcp.CallToolRequest)
 	}
 }
 
+// oastProgressInterval is how often a long-poll reports progress while no events arrive.
+const oastProgressInterval = 5 * time.Second
+
+// pollOastWithProgress long-polls in interval-sized chunks, reporting progress between
+// chunks, so clients show liveness and can cancel. Without a reporter it polls once.
+func pollOastWithProgress(ctx context.Context, backend OastBackend, progress *progressReporter, interval time.Duration,
+	oastID, since, eventType, subdomainContains string, wait time.Duration, limit int) (*OastPollResultInfo, error) {
+	if !progress.Enabled() || wait <= interval {
+		return backend.PollSession(ctx, oastID, since, eventType, subdomainContains, wait, limit)
+	}
+
+	for waited := time.Duration(0); ; waited += interval {
+		chunk := min(interval, wait-waited)
+		result, err := backend.PollSession(ctx, oastID, since, eventType, subdomainContains, chunk, limit)
+		if err != nil || len(result.Events) > 0 || waited+chunk >= wait {
+			return result, err
+		}
+		progress.Report((waited + chunk).Seconds(), wait.Seconds(), "waiting for OAST interactions")
+	}
+}
+
 // aggregateOastEvents aggregates OAST events by (subdomain, source_ip, type).
 func aggregateOastEvents(events []OastEventInfo) []protocol.OastSummaryEntry {
 	type key struct {
@@ -202,6 +296,14 @@ func (m *mcpServer) handleOastGet(ctx context.Context, req mcp.CallToolRequest)
 	if eventID == "" {
 		return errorResult("event_id is required"), nil
 	}
+	field := req.GetString("field", "raw_request")
+	if field != "raw_request" && field != "raw_response" {
+		return errorResult("field must be raw_request or raw_response"), nil
+	}
+	window, hasWindow, err := parseBodyRange(req)
+	if err != nil {
+		return errorResultFromErr("", err), nil
+	}
 
 	log.Printf("mcp/oast_get: getting event %s from session %s", eventID, oastID)
 
@@ -213,14 +315,29 @@ func (m *mcpServer) handleOastGet(ctx context.Context, req mcp.CallToolRequest)
 		return errorResultFromErr("failed to get event: ", err), nil
 	}
 
-	return jsonResult(protocol.OastGetResponse{
+	resp := protocol.OastGetResponse{
 		EventID:   event.ID,
 		Time:      event.Time.UTC().Format(time.RFC3339),
 		Type:      event.Type,
 		SourceIP:  event.SourceIP,
 		Subdomain: event.Subdomain,
 		Details:   event.Details,
-	})
+	}
+	if sess, found, err := m.service.findOastSession(ctx, oastID); err == nil && found {
+		resp.CorrelatedWith = m.service.oastUses.Lookup(sess.ID, event.Subdomain)
+	}
+	if hasWindow {
+		raw, ok := event.Details[field].(string)
+		if !ok {
+			return errorResult("event has no " + field), nil
+		}
+		details := maps.Clone(event.Details)
+		if details[field], resp.BodyRange, err = window.slice([]byte(raw)); err != nil {
+			return errorResultFromErr("", err), nil
+		}
+		resp.Details = details
+	}
+	return jsonResult(resp)
 }
 
 func (m *mcpServer) handleOastList(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
@@ -251,6 +368,8 @@ func (m *mcpServer) handleOastList(ctx context.Context, req mcp.CallToolRequest)
 			Domain:    sess.Domain,
 			Label:     sess.Label,
 			CreatedAt: sess.CreatedAt.UTC().Format(time.RFC3339),
+
+			Expectations: m.service.oastWatch.Expectations(sess.ID),
 		}
 	}
 
@@ -258,6 +377,52 @@ func (m *mcpServer) handleOastList(ctx context.Context, req mcp.CallToolRequest)
 	return jsonResult(&protocol.OastListResponse{Sessions: apiSessions})
 }
 
+func (m *mcpServer) handleOastExpect(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if err := m.requireWorkflow(); err != nil {
+		return err, nil
+	}
+
+	oastID := req.GetString("oast_id", "")
+	if oastID == "" {
+		return errorResult("oast_id is required"), nil
+	}
+	subdomain := strings.TrimSpace(req.GetString("subdomain", ""))
+	if subdomain == "" {
+		return errorResult("subdomain is required"), nil
+	}
+	title := strings.TrimSpace(req.GetString("title", ""))
+	if title == "" {
+		return errorResult("title is required"), nil
+	}
+	severity := strings.ToLower(req.GetString("severity", protocol.SeverityMedium))
+	if !validSeverities[severity] {
+		return errorResult("invalid severity: " + severity + " (use info, low, medium, high, critical)"), nil
+	}
+	replayID := req.GetString("replay_id", "")
+	if replayID != "" {
+		var err error
+		if replayID, err = m.service.resolveReplayRef(replayID); err != nil {
+			return errorResult(err.Error()), nil
+		}
+	}
+
+	sess, found, err := m.service.findOastSession(ctx, oastID)
+	if err != nil {
+		return errorResultFromErr("failed to list OAST sessions: ", err), nil
+	} else if !found {
+		return errorResult("session not found"), nil
+	}
+
+	exp := m.service.oastWatch.Expect(sess.ID, protocol.OastExpectation{
+		Subdomain: subdomain,
+		Title:     title,
+		Severity:  severity,
+		ReplayID:  replayID,
+	})
+	log.Printf("mcp/oast_expect: session %s expects %q (expectation %s)", exp.OastID, subdomain, exp.ExpectationID)
+	return jsonResult(exp)
+}
+
 func (m *mcpServer) handleOastDelete(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	if err := m.requireWorkflow(); err != nil {
 		return err, nil
@@ -270,6 +435,9 @@ func (m *mcpServer) handleOastDelete(ctx context.Context, req mcp.CallToolReques
 
 	log.Printf("mcp/oast_delete: deleting session %s", oastID)
 
+	if sess, found, err := m.service.findOastSession(ctx, oastID); err == nil && found {
+		m.service.oastUses.Forget(sess.ID)
+	}
 	if err := m.service.oastBackend.DeleteSession(ctx, oastID); err != nil {
 		if errors.Is(err, ErrNotFound) {
 			return errorResult("session not found"), nil
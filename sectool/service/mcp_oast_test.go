@@ -2,6 +2,7 @@ package service
 
 import (
 	"slices"
+	"strings"
 	"testing"
 	"time"
 
@@ -112,6 +113,16 @@ func TestMCP_OastLifecycleWithMock(t *testing.T) {
 		assert.Equal(t, "5.6.7.8", resp.SourceIP)
 	})
 
+	t.Run("poll_with_subdomain_filter", func(t *testing.T) {
+		resp := CallMCPToolJSONOK[protocol.OastPollResponse](t, mcpClient, "oast_poll", map[string]interface{}{
+			"output_mode":        "events",
+			"oast_id":            oastID,
+			"subdomain_contains": "TES",
+		})
+		require.Len(t, resp.Events, 1)
+		assert.Equal(t, "event-get-test", resp.Events[0].EventID)
+	})
+
 	t.Run("delete", func(t *testing.T) {
 		_ = CallMCPToolTextOK(t, mcpClient, "oast_delete", map[string]interface{}{
 			"oast_id": oastID,
@@ -201,3 +212,144 @@ func TestMCP_OastValidation(t *testing.T) {
 		assert.Contains(t, ExtractMCPText(t, result), "not found")
 	})
 }
+
+func TestMCP_OastGetRange(t *testing.T) {
+	t.Parallel()
+
+	_, mcpClient, _, mockOast, _ := setupMCPServerWithMock(t)
+
+	sess := CallMCPToolJSONOK[protocol.OastCreateResponse](t, mcpClient, "oast_create", nil)
+	mockOast.events[sess.OastID] = []OastEventInfo{{
+		ID: "e1", Time: time.Now(), Type: "http", SourceIP: "1.2.3.4",
+		Details: map[string]interface{}{
+			"raw_request":  "POST /cb HTTP/1.1\r\nHost: x\r\n\r\n" + strings.Repeat("A", 100),
+			"raw_response": "HTTP/1.1 200 OK\r\n\r\n",
+		},
+	}}
+
+	resp := CallMCPToolJSONOK[protocol.OastGetResponse](t, mcpClient, "oast_get", map[string]interface{}{
+		"oast_id": sess.OastID, "event_id": "e1", "start_line": 1, "end_line": 1,
+	})
+	assert.Equal(t, "POST /cb HTTP/1.1\r\n", resp.Details["raw_request"])
+	assert.Equal(t, "HTTP/1.1 200 OK\r\n\r\n", resp.Details["raw_response"])
+	require.NotNil(t, resp.BodyRange)
+	assert.Equal(t, 2, resp.BodyRange.NextLine)
+
+	resp = CallMCPToolJSONOK[protocol.OastGetResponse](t, mcpClient, "oast_get", map[string]interface{}{
+		"oast_id": sess.OastID, "event_id": "e1", "field": "raw_response", "offset": 9, "length": 6,
+	})
+	assert.Equal(t, "200 OK", resp.Details["raw_response"])
+
+	result := CallMCPTool(t, mcpClient, "oast_get", map[string]interface{}{
+		"oast_id": sess.OastID, "event_id": "e1", "field": "query_type", "offset": 0,
+	})
+	assert.True(t, result.IsError)
+}
+
+func TestMCP_OastCorrelation(t *testing.T) {
+	t.Parallel()
+
+	_, mcpClient, mockMCP, mockOast, _ := setupMCPServerWithMock(t)
+
+	sess := CallMCPToolJSONOK[protocol.OastCreateResponse](t, mcpClient, "oast_create", map[string]interface{}{"label": "blind"})
+	mockMCP.SetSendResponse("HttpRequestResponse{httpRequest=GET / HTTP/1.1, httpResponse=HTTP/1.1 200 OK\r\n\r\n}")
+	sent := CallMCPToolJSONOK[protocol.ReplaySendResponse](t, mcpClient, "request_send", map[string]interface{}{
+		"url": "https://example.com/fetch?u=http://ssrf1." + sess.Domain + "/",
+	})
+
+	// Interactsh reports the subdomain without the server domain
+	tag := "ssrf1." + strings.TrimSuffix(sess.Domain, ".test.invalid")
+	mockOast.events[sess.OastID] = []OastEventInfo{
+		{ID: "e1", Time: time.Now(), Type: "http", SourceIP: "1.2.3.4", Subdomain: tag},
+		{ID: "e2", Time: time.Now(), Type: "dns", SourceIP: "1.2.3.4", Subdomain: "other." + strings.TrimSuffix(sess.Domain, ".test.invalid")},
+	}
+
+	t.Run("poll_events", func(t *testing.T) {
+		resp := CallMCPToolJSONOK[protocol.OastPollResponse](t, mcpClient, "oast_poll", map[string]interface{}{
+			"oast_id": "blind", "output_mode": "events", "since": "",
+		})
+		require.Len(t, resp.Events, 2)
+		assert.Equal(t, []string{sent.ReplayID}, resp.Events[0].CorrelatedWith)
+		assert.Empty(t, resp.Events[1].CorrelatedWith)
+	})
+
+	t.Run("poll_summary", func(t *testing.T) {
+		resp := CallMCPToolJSONOK[protocol.OastPollResponse](t, mcpClient, "oast_poll", map[string]interface{}{
+			"oast_id": sess.OastID, "since": "",
+		})
+		idx := slices.IndexFunc(resp.Aggregates, func(a protocol.OastSummaryEntry) bool { return a.Subdomain == tag })
+		require.GreaterOrEqual(t, idx, 0)
+		assert.Equal(t, []string{sent.ReplayID}, resp.Aggregates[idx].CorrelatedWith)
+	})
+
+	t.Run("get", func(t *testing.T) {
+		resp := CallMCPToolJSONOK[protocol.OastGetResponse](t, mcpClient, "oast_get", map[string]interface{}{
+			"oast_id": sess.Domain, "event_id": "e1",
+		})
+		assert.Equal(t, []string{sent.ReplayID}, resp.CorrelatedWith)
+	})
+}
+
+func TestMCP_OastExpect(t *testing.T) {
+	t.Parallel()
+
+	srv, mcpClient, _, mockOast, _ := setupMCPServerWithMock(t)
+
+	sess := CallMCPToolJSONOK[protocol.OastCreateResponse](t, mcpClient, "oast_create", map[string]interface{}{
+		"label": "blind-ssrf",
+	})
+	// Events are added before the expectation so the background watch only reads the mock
+	mockOast.events[sess.OastID] = []OastEventInfo{
+		{ID: "e1", Time: time.Now(), Type: "dns", SourceIP: "1.2.3.4", Subdomain: "scanner.abc123"},
+		{ID: "e2", Time: time.Now(), Type: "http", SourceIP: "5.6.7.8", Subdomain: "SSRF-step3.abc123"},
+	}
+
+	t.Run("validation", func(t *testing.T) {
+		result := CallMCPTool(t, mcpClient, "oast_expect", map[string]interface{}{
+			"oast_id": "blind-ssrf", "subdomain": "ssrf-step3", "title": "x", "severity": "urgent",
+		})
+		assert.True(t, result.IsError)
+		assert.Contains(t, ExtractMCPText(t, result), "invalid severity")
+
+		result = CallMCPTool(t, mcpClient, "oast_expect", map[string]interface{}{
+			"oast_id": "missing", "subdomain": "ssrf-step3", "title": "x",
+		})
+		assert.True(t, result.IsError)
+		assert.Contains(t, ExtractMCPText(t, result), "session not found")
+	})
+
+	t.Run("confirmed_creates_finding", func(t *testing.T) {
+		exp := CallMCPToolJSONOK[protocol.OastExpectation](t, mcpClient, "oast_expect", map[string]interface{}{
+			"oast_id":   "blind-ssrf",
+			"subdomain": "ssrf-step3",
+			"title":     "Blind SSRF in webhook URL",
+			"severity":  "high",
+			"replay_id": "rp1",
+		})
+		assert.Equal(t, sess.OastID, exp.OastID)
+		assert.NotEmpty(t, exp.ExpectationID)
+
+		var confirmed protocol.OastExpectation
+		require.Eventually(t, func() bool {
+			resp := CallMCPToolJSONOK[protocol.OastListResponse](t, mcpClient, "oast_list", nil)
+			for _, s := range resp.Sessions {
+				for _, e := range s.Expectations {
+					if e.ExpectationID == exp.ExpectationID && e.FindingID != "" {
+						confirmed = e
+						return true
+					}
+				}
+			}
+			return false
+		}, 5*time.Second, 50*time.Millisecond)
+
+		finding, err := srv.findings.Get(confirmed.FindingID)
+		require.NoError(t, err)
+		assert.Equal(t, "Blind SSRF in webhook URL", finding.Title)
+		assert.Equal(t, protocol.SeverityHigh, finding.Severity)
+		assert.Equal(t, protocol.FindingStatusDraft, finding.Status)
+		assert.Equal(t, []string{"rp1"}, finding.ReplayIDs)
+		require.Len(t, finding.OastEvents, 1)
+		assert.Equal(t, "e2", finding.OastEvents[0].EventID)
+	})
+}
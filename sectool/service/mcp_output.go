@@ -0,0 +1,146 @@
+package service
+
+import (
+	"context"
+	"log"
+	"unicode/utf8"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/go-harden/llm-security-toolbox/sectool/protocol"
+	"github.com/go-harden/llm-security-toolbox/sectool/service/ids"
+)
+
+const (
+	maxOutputBytesParam = "max_output_bytes"
+	defaultOutputChunk  = 16384
+	maxRetainedOutputs  = 32
+	// outputGetOverhead leaves room for the output_get response fields around the chunk.
+	outputGetOverhead = 256
+)
+
+// outputHints suggest a narrower follow-up call for tools whose results are commonly shaped.
+var outputHints = map[string]string{
+	"proxy_poll":    "narrow with filters or page with limit and cursor",
+	"crawl_poll":    "narrow with filters or page with limit and cursor",
+	"crawl_results": "page with limit and cursor",
+	"oast_poll":     "page with limit and cursor",
+	"replay_send":   "read the body in windows with replay_get offset/length, or pull values with response_extract",
+	"replay_get":    "read the body in windows with replay_get offset/length, or pull values with response_extract",
+	"request_send":  "read the body in windows with replay_get offset/length, or pull values with response_extract",
+	"proxy_get":     "search bodies with proxy_poll contains_body, or replay the flow and use response_extract",
+}
+
+func (m *mcpServer) outputGetTool() mcp.Tool {
+	return mcp.NewTool("output_get",
+		mcp.WithDescription(`Fetch the full text of a tool result that was truncated by max_output_bytes.
+
+Returns a chunk starting at offset; repeat with next_offset until it is absent. Recent truncated outputs only.`),
+		mcp.WithString("output_id", mcp.Required(), mcp.Description("output_id from a truncated result")),
+		mcp.WithNumber("offset", mcp.Description("Byte offset to start from (default 0)")),
+		mcp.WithNumber("length", mcp.Description("Maximum bytes to return (default 16384, at most the server's max_output_bytes)")),
+		annotateReadOnly,
+	)
+}
+
+// addTool registers a tool with the shared max_output_bytes parameter and shaping.
+func (m *mcpServer) addTool(tool mcp.Tool, handler server.ToolHandlerFunc) {
+	m.server.AddTools(m.serverTool(tool, handler))
+}
+
+// serverTool adds the shared max_output_bytes parameter and shaping to a tool.
+func (m *mcpServer) serverTool(tool mcp.Tool, handler server.ToolHandlerFunc) server.ServerTool {
+	if tool.InputSchema.Properties == nil {
+		tool.InputSchema.Properties = make(map[string]any)
+	}
+	tool.InputSchema.Properties[maxOutputBytesParam] = map[string]any{
+		"type":        "number",
+		"description": "Approximate cap on response size in bytes (at most the server's max_output_bytes); large fields are elided and the full output is kept for output_get",
+	}
+	return server.ServerTool{Tool: tool, Handler: m.withOutputBudget(tool.Name, m.withHostOverrides(handler))}
+}
+
+// withOutputBudget shapes text results larger than the caller's max_output_bytes, or
+// the configured server-wide budget when that is smaller or the caller set none. The
+// server-wide budget does not apply to the CLI, which decodes full results (base64
+// bodies for export and --output) rather than reading them into a context.
+func (m *mcpServer) withOutputBudget(name string, handler server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		result, err := handler(ctx, req)
+		budget := req.GetInt(maxOutputBytesParam, 0)
+		if limit := m.service.maxOutputBytes(); limit > 0 && !isCLIClient(ctx) && (budget <= 0 || budget > limit) {
+			budget = limit
+		}
+		if err != nil || result == nil || result.IsError || budget <= 0 {
+			return result, err
+		}
+		budget = max(budget, minOutputBudget)
+
+		for i, c := range result.Content {
+			tc, ok := c.(mcp.TextContent)
+			if !ok || len(tc.Text) <= budget {
+				continue
+			}
+			outputID := ids.Generate(ids.DefaultLength)
+			m.service.outputStore.Store(outputID, tc.Text)
+			original := len(tc.Text)
+			tc.Text = shapeOutput(tc.Text, budget, outputID, outputHints[name])
+			result.Content[i] = tc
+			log.Printf("mcp/%s: shaped %d byte result to %d bytes (output_id=%s)", name, original, len(tc.Text), outputID)
+		}
+		return result, nil
+	}
+}
+
+// maxOutputBytes returns the configured cap on tool result size, or 0 for none.
+func (s *Server) maxOutputBytes() int {
+	if s.cfg == nil {
+		return 0
+	}
+	return s.cfg.MaxOutputBytes
+}
+
+func (m *mcpServer) handleOutputGet(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if err := m.requireWorkflow(); err != nil {
+		return err, nil
+	}
+
+	outputID := req.GetString("output_id", "")
+	if outputID == "" {
+		return errorResult("output_id is required"), nil
+	}
+	offset := req.GetInt("offset", 0)
+	length := req.GetInt("length", defaultOutputChunk)
+	if offset < 0 || length <= 0 {
+		return errorResult("invalid offset or length"), nil
+	}
+	// chunks stay within the configured budget, since output_get skips shaping
+	if limit := m.service.maxOutputBytes(); limit > 0 {
+		length = min(length, max(limit-outputGetOverhead, minOutputBudget))
+	}
+
+	out, ok := m.service.outputStore.Get(outputID)
+	if !ok {
+		return errorResult("output not found"), nil
+	} else if offset > len(out) {
+		return errorResult("offset beyond end of output"), nil
+	}
+
+	end := min(offset+length, len(out))
+	for end < len(out) && end > offset && !utf8.RuneStart(out[end]) {
+		end--
+	}
+	resp := protocol.OutputGetResponse{
+		OutputID:   outputID,
+		Offset:     offset,
+		TotalBytes: len(out),
+		Data:       out[offset:end],
+	}
+	if end < len(out) {
+		resp.NextOffset = end
+	}
+
+	log.Printf("mcp/output_get: %s bytes %d-%d of %d", outputID, offset, end, len(out))
+	return jsonResult(resp)
+}
@@ -0,0 +1,147 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/go-harden/llm-security-toolbox/sectool/payload"
+	"github.com/go-harden/llm-security-toolbox/sectool/protocol"
+	"github.com/go-harden/llm-security-toolbox/sectool/service/ids"
+	"github.com/go-harden/llm-security-toolbox/sectool/service/store"
+)
+
+func (m *mcpServer) paramDiscoverTool() mcp.Tool {
+	return mcp.NewTool("param_discover",
+		mcp.WithDescription(`Brute-force hidden parameter names on a request by comparing responses (arjun style).
+
+Names are added in chunks with unique values; chunks whose response differs from the unmodified request (status, body similarity, headers) are bisected down to single names, and reflected values identify their name directly.
+The unmodified request is sent twice first to measure how much the body varies on its own. Names already in the request are skipped.
+Default wordlist: the built-in 'params' payload list (see payloads_get). Location defaults to the body for form or JSON requests, otherwise the query string.
+Returns candidates with evidence and the replay_id of the request that showed each; confirm with replay_send before reporting.`),
+		mcp.WithString("flow_id", mcp.Description("Flow to test, or "+recentRefUsage+" proxy entry (exclusive with replay_id)")),
+		mcp.WithString("replay_id", mcp.Description("Replay to use as the request (exclusive with flow_id)")),
+		mcp.WithString("location", mcp.Description("Where to add parameters: query, form, or json (default: from the request)")),
+		mcp.WithArray("names", mcp.Items(map[string]interface{}{"type": "string"}), mcp.Description("Parameter names to try (default: built-in params list)")),
+		mcp.WithNumber("chunk_size", mcp.Description("Names per request (default 30)")),
+		mcp.WithNumber("max_requests", mcp.Description("Request budget including the two baseline requests (default 100)")),
+		mcp.WithString("timeout", mcp.Description("Per-request timeout (e.g., '30s')")),
+		mcp.WithNumber("rate", mcp.Description(rateParamDescription)),
+		annotateSendsTraffic,
+	)
+}
+
+func (m *mcpServer) handleParamDiscover(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if err := m.requireWorkflow(); err != nil {
+		return err, nil
+	}
+	ctx, err := applyRunRate(ctx, req)
+	if err != nil {
+		return errorResult(err.Error()), nil
+	}
+
+	flowID, replayID := req.GetString("flow_id", ""), req.GetString("replay_id", "")
+	if (flowID == "") == (replayID == "") {
+		return errorResult("exactly one of flow_id or replay_id is required"), nil
+	}
+	var timeout time.Duration
+	if s := req.GetString("timeout", ""); s != "" {
+		var err error
+		if timeout, err = time.ParseDuration(s); err != nil {
+			return errorResult("invalid timeout duration: " + err.Error()), nil
+		}
+	}
+	chunkSize := req.GetInt("chunk_size", defaultParamChunkSize)
+	maxRequests := req.GetInt("max_requests", defaultParamMaxRequests)
+	if chunkSize < 1 {
+		return errorResult("chunk_size must be at least 1"), nil
+	} else if maxRequests < 3 {
+		return errorResult("max_requests must be at least 3"), nil
+	}
+
+	names := req.GetStringSlice("names", nil)
+	if len(names) == 0 {
+		list, err := payload.GetList("params")
+		if err != nil {
+			return errorResultFromErr("", err), nil
+		}
+		names = list.Payloads
+	}
+
+	rawRequest, baseTarget, base, err := m.service.loadBaseRequest(ctx, flowID, replayID)
+	if err != nil {
+		return errorResultFromErr("", err), nil
+	}
+	location := req.GetString("location", "")
+	switch location {
+	case "":
+		location = paramLocation(rawRequest)
+	case paramLocationQuery, paramLocationForm, paramLocationJSON:
+	default:
+		return errorResult("location must be query, form, or json"), nil
+	}
+	host, port, usesHTTPS := parseTarget(rawRequest, baseTarget)
+	target := Target{Hostname: host, Port: port, UsesHTTPS: usesHTTPS}
+
+	send := func(ctx context.Context, request []byte) (*paramProbe, error) {
+		result, err := m.service.sendRequest(ctx, "sectool-param-discover", SendRequestInput{
+			RawRequest: request,
+			Target:     target,
+			Timeout:    timeout,
+		}, nil)
+		if err != nil {
+			return nil, err
+		}
+		status, _ := parseResponseStatus(result.Headers)
+		return &paramProbe{request: request, result: result, status: status}, nil
+	}
+	d, tested := newParamDiscoverer(rawRequest, location, names, maxRequests, send)
+	if len(tested) == 0 {
+		return errorResult("no names to test: all are already in the request"), nil
+	}
+
+	log.Printf("mcp/param_discover: testing %d names in %s of %s", len(tested), location, base)
+	if err := d.run(ctx, tested, chunkSize); err != nil {
+		return errorResultFromErr("", err), nil
+	}
+
+	resp := protocol.ParamDiscoverResponse{
+		Base:       base,
+		Location:   location,
+		Tested:     len(tested),
+		Requests:   d.requests,
+		Echoed:     d.echoed,
+		Candidates: make([]protocol.ParamCandidate, 0, len(d.candidates)),
+	}
+	if d.stopped {
+		resp.Stopped = "max_requests"
+	}
+	stored := make(map[*paramProbe]string)
+	for _, c := range d.candidates {
+		id, ok := stored[c.probe]
+		if !ok {
+			id = ids.Generate(ids.DefaultLength)
+			stored[c.probe] = id
+			m.service.requestStore.Store(id, &store.RequestEntry{
+				Base:     base,
+				Request:  c.probe.request,
+				Target:   target.origin(),
+				Headers:  c.probe.result.Headers,
+				Body:     c.probe.result.Body,
+				Duration: c.probe.result.Duration,
+			})
+		}
+		resp.Candidates = append(resp.Candidates, protocol.ParamCandidate{
+			Name:     c.name,
+			Evidence: c.evidence,
+			ReplayID: id,
+			Status:   c.probe.status,
+			Size:     len(c.probe.result.Body),
+		})
+	}
+
+	log.Printf("mcp/param_discover: %s done after %d requests, %d candidates", base, d.requests, len(resp.Candidates))
+	return jsonResult(resp)
+}
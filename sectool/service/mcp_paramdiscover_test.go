@@ -0,0 +1,68 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/go-harden/llm-security-toolbox/sectool/protocol"
+)
+
+func TestMCP_ParamDiscover(t *testing.T) {
+	t.Parallel()
+
+	srv, mcpClient, mockMCP, _, _ := setupMCPServerWithMock(t)
+	mockMCP.AddProxyEntry("GET /api/items?page=1 HTTP/1.1\r\nHost: example.com\r\n\r\n", "HTTP/1.1 200 OK\r\n\r\n[]", "")
+
+	respond := func(status, body string) {
+		mockMCP.SetSendResponse("HttpRequestResponse{httpRequest=GET / HTTP/1.1, httpResponse=HTTP/1.1 " + status + "\r\nContent-Type: application/json\r\n\r\n" + body + "}")
+	}
+
+	t.Run("bisects", func(t *testing.T) {
+		respond("200 OK", "[]")                       // baseline
+		respond("200 OK", "[]")                       // baseline
+		respond("500 Internal Server Error", "error") // debug, verbose
+		respond("200 OK", "[]")                       // debug
+		respond("500 Internal Server Error", "error") // verbose
+
+		resp := CallMCPToolJSONOK[protocol.ParamDiscoverResponse](t, mcpClient, "param_discover", map[string]interface{}{
+			"flow_id": "last",
+			"names":   []interface{}{"debug", "page", "verbose"},
+		})
+		assert.Equal(t, paramLocationQuery, resp.Location)
+		assert.Equal(t, 2, resp.Tested) // page is already in the request
+		assert.Equal(t, 5, resp.Requests)
+		require.Len(t, resp.Candidates, 1)
+		c := resp.Candidates[0]
+		assert.Equal(t, "verbose", c.Name)
+		assert.Equal(t, 500, c.Status)
+		assert.Contains(t, c.Evidence, "status 200 -> 500")
+
+		entry, ok := srv.requestStore.Get(c.ReplayID)
+		require.True(t, ok)
+		assert.Contains(t, string(entry.Request), "GET /api/items?page=1&verbose=")
+	})
+
+	t.Run("no_change", func(t *testing.T) {
+		resp := CallMCPToolJSONOK[protocol.ParamDiscoverResponse](t, mcpClient, "param_discover", map[string]interface{}{
+			"flow_id":      "last",
+			"max_requests": 5,
+		})
+		assert.Greater(t, resp.Tested, 100)
+		assert.Equal(t, 5, resp.Requests)
+		assert.Empty(t, resp.Candidates)
+		assert.Equal(t, "max_requests", resp.Stopped)
+	})
+
+	t.Run("validation", func(t *testing.T) {
+		result := CallMCPTool(t, mcpClient, "param_discover", map[string]interface{}{})
+		assert.True(t, result.IsError)
+		result = CallMCPTool(t, mcpClient, "param_discover", map[string]interface{}{"flow_id": "last", "location": "header"})
+		assert.True(t, result.IsError)
+		assert.Contains(t, ExtractMCPText(t, result), "location must be")
+		result = CallMCPTool(t, mcpClient, "param_discover", map[string]interface{}{"flow_id": "last", "names": []interface{}{"page"}})
+		assert.True(t, result.IsError)
+		assert.Contains(t, ExtractMCPText(t, result), "no names to test")
+	})
+}
@@ -0,0 +1,133 @@
+package service
+
+import (
+	"context"
+	"log"
+	"net"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/go-harden/llm-security-toolbox/sectool/protocol"
+)
+
+func (m *mcpServer) portScanTool() mcp.Tool {
+	return mcp.NewTool("port_scan",
+		mcp.WithDescription(`Check TCP ports on in-scope hosts and grab service banners.
+
+Requires a project scope with include patterns (scope_set); host:port pairs outside it are skipped and counted, never connected to.
+Connections are made directly from sectool at a global rate (default 20/s), also held to the rate_limit config. Open ports are given banner_wait to send a banner (SSH, SMTP, FTP), then sent an HTTP HEAD request; if neither answers, a TLS handshake is tried.
+Default ports: about 50 common service ports (web, databases, remote access, caches, container APIs).
+Open ports are listed by crawl_results as services. Follow up with tls_probe on TLS ports, raw_send for other protocols, or crawl_create on HTTP services.`),
+		mcp.WithArray("hosts", mcp.Required(), mcp.Items(map[string]interface{}{"type": "string"}), mcp.Description("Hostnames or IP addresses")),
+		mcp.WithString("ports", mcp.Description("Ports and ranges, comma-separated (e.g., '22,80,8000-8100'; default: common service ports)")),
+		mcp.WithNumber("rate", mcp.Description("Connections per second across all workers (default 20, max 200); rate_limit config limits still apply")),
+		mcp.WithNumber("threads", mcp.Description("Concurrent connections (default 10, max 50)")),
+		mcp.WithString("timeout", mcp.Description("Connect timeout per port (e.g., '2s', default 3s); slower answers count as filtered")),
+		mcp.WithString("banner_wait", mcp.Description("Time to wait for a banner after connecting and after the HTTP probe (default 1s)")),
+		annotateSendsTraffic,
+	)
+}
+
+func (m *mcpServer) handlePortScan(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if err := m.requireWorkflow(); err != nil {
+		return err, nil
+	}
+
+	var hosts []string
+	for _, host := range req.GetStringSlice("hosts", nil) {
+		host = strings.Trim(strings.ToLower(strings.TrimSpace(host)), "[]")
+		if host == "" {
+			continue
+		} else if strings.ContainsAny(host, "/ ") {
+			return errorResult("hosts must be bare hostnames or IP addresses: " + host), nil
+		} else if !slices.Contains(hosts, host) {
+			hosts = append(hosts, host)
+		}
+	}
+	if len(hosts) == 0 {
+		return errorResult("hosts is required"), nil
+	}
+	ports := defaultScanPorts
+	if spec := req.GetString("ports", ""); spec != "" {
+		var err error
+		if ports, err = parsePortSpec(spec); err != nil {
+			return errorResultFromErr("", err), nil
+		}
+	}
+	if len(hosts)*len(ports) > maxPortScanTargets {
+		return errorResult("too many host and port pairs: " + strconv.Itoa(len(hosts)*len(ports)) + " (max " + strconv.Itoa(maxPortScanTargets) + ")"), nil
+	}
+
+	scanner := &portScanner{
+		dial:       dialContext,
+		threads:    req.GetInt("threads", defaultPortScanThreads),
+		rate:       req.GetInt("rate", defaultPortScanRate),
+		limiter:    m.service.rateLimiter,
+		timeout:    defaultPortTimeout,
+		bannerWait: defaultBannerWait,
+	}
+	if scanner.threads < 1 || scanner.threads > maxPortScanThreads {
+		return errorResult("threads must be between 1 and " + strconv.Itoa(maxPortScanThreads)), nil
+	} else if scanner.rate < 1 || scanner.rate > maxPortScanRate {
+		return errorResult("rate must be between 1 and " + strconv.Itoa(maxPortScanRate)), nil
+	}
+	for name, dst := range map[string]*time.Duration{"timeout": &scanner.timeout, "banner_wait": &scanner.bannerWait} {
+		if s := req.GetString(name, ""); s != "" {
+			d, err := time.ParseDuration(s)
+			if err != nil || d <= 0 {
+				return errorResult("invalid " + name + " duration: " + s), nil
+			}
+			*dst = d
+		}
+	}
+
+	scope := m.service.projectScope()
+	if scope == nil || len(scope.Include) == 0 {
+		return errorResult("port_scan requires a project scope with include patterns; set one with scope_set"), nil
+	}
+	resp := protocol.PortScanResponse{Hosts: hosts, Ports: len(ports), Open: []protocol.OpenPort{}}
+	var targets []portTarget
+	for _, host := range hosts {
+		for _, port := range ports {
+			addr := net.JoinHostPort(host, strconv.Itoa(port))
+			if inScope(scope, addr, "/") && m.service.guard.Allows(addr, "/") {
+				targets = append(targets, portTarget{host: host, port: port})
+			} else {
+				resp.Skipped++
+			}
+		}
+	}
+	if len(targets) == 0 {
+		return errorResult("all hosts are outside the project scope (see scope_get) or the target_allowlist config"), nil
+	}
+
+	log.Printf("mcp/port_scan: %d targets on %d hosts (rate=%d/s threads=%d)", len(targets), len(hosts), scanner.rate, scanner.threads)
+	start := time.Now()
+	results := scanner.run(ctx, targets)
+	m.service.portScans.add(results)
+
+	for _, r := range results {
+		switch r.state {
+		case "open":
+			resp.Open = append(resp.Open, openPort(r))
+		case "closed":
+			resp.Closed++
+		default:
+			resp.Filtered++
+		}
+	}
+	resp.Connections = scanner.connections
+	resp.Stopped = len(results) < len(targets)
+	resp.Duration = time.Since(start).Round(time.Millisecond).String()
+
+	log.Printf("mcp/port_scan: done, %d open of %d checked", len(resp.Open), len(results))
+	return jsonResult(resp)
+}
+
+func openPort(r portResult) protocol.OpenPort {
+	return protocol.OpenPort{Host: r.host, Port: r.port, Service: r.service, Banner: r.banner, TLS: r.tls}
+}
@@ -0,0 +1,68 @@
+package service
+
+import (
+	"io"
+	"net"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/go-harden/llm-security-toolbox/sectool/config"
+	"github.com/go-harden/llm-security-toolbox/sectool/protocol"
+)
+
+func TestMCP_PortScan(t *testing.T) {
+	t.Parallel()
+
+	srv, mcpClient, _, _, _ := setupMCPServerWithMock(t)
+	sshPort := listenTestPort(t, func(conn net.Conn) {
+		_, _ = conn.Write([]byte("SSH-2.0-OpenSSH_9.6\r\n"))
+		_, _ = io.Copy(io.Discard, conn)
+	})
+
+	t.Run("requires_scope", func(t *testing.T) {
+		result := CallMCPTool(t, mcpClient, "port_scan", map[string]interface{}{"hosts": []interface{}{"127.0.0.1"}})
+		assert.True(t, result.IsError)
+		assert.Contains(t, ExtractMCPText(t, result), "requires a project scope")
+	})
+
+	t.Run("scan", func(t *testing.T) {
+		require.NoError(t, srv.setProjectScope(&config.Scope{Include: []string{"127.0.0.1"}}))
+		t.Cleanup(func() { _ = srv.setProjectScope(nil) })
+
+		resp := CallMCPToolJSONOK[protocol.PortScanResponse](t, mcpClient, "port_scan", map[string]interface{}{
+			"hosts":       []interface{}{"127.0.0.1", "evil.example.com"},
+			"ports":       strconv.Itoa(sshPort),
+			"rate":        100,
+			"banner_wait": "200ms",
+		})
+		assert.Equal(t, 1, resp.Ports)
+		assert.Equal(t, 1, resp.Skipped)
+		assert.Equal(t, 1, resp.Connections)
+		require.Len(t, resp.Open, 1)
+		assert.Equal(t, protocol.OpenPort{Host: "127.0.0.1", Port: sshPort, Service: "ssh", Banner: "SSH-2.0-OpenSSH_9.6"}, resp.Open[0])
+
+		results := CallMCPToolJSONOK[protocol.CrawlResultsResponse](t, mcpClient, "crawl_results", nil)
+		assert.Equal(t, resp.Open, results.Services)
+		results = CallMCPToolJSONOK[protocol.CrawlResultsResponse](t, mcpClient, "crawl_results", map[string]interface{}{"host": "other.*"})
+		assert.Empty(t, results.Services)
+
+		result := CallMCPTool(t, mcpClient, "port_scan", map[string]interface{}{"hosts": []interface{}{"evil.example.com"}})
+		assert.True(t, result.IsError)
+		assert.Contains(t, ExtractMCPText(t, result), "outside the project scope")
+	})
+
+	t.Run("validation", func(t *testing.T) {
+		result := CallMCPTool(t, mcpClient, "port_scan", map[string]interface{}{})
+		assert.True(t, result.IsError)
+		assert.Contains(t, ExtractMCPText(t, result), "hosts is required")
+		result = CallMCPTool(t, mcpClient, "port_scan", map[string]interface{}{"hosts": []interface{}{"example.com"}, "ports": "1-5000", "rate": 0})
+		assert.True(t, result.IsError)
+		assert.Contains(t, ExtractMCPText(t, result), "rate must be")
+		result = CallMCPTool(t, mcpClient, "port_scan", map[string]interface{}{"hosts": []interface{}{"a.example.com", "b.example.com"}, "ports": "1-3000"})
+		assert.True(t, result.IsError)
+		assert.Contains(t, ExtractMCPText(t, result), "too many")
+	})
+}
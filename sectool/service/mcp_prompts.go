@@ -0,0 +1,143 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// testingPrompt is a methodology prompt that expands into tool-oriented instructions.
+type testingPrompt struct {
+	name        string
+	description string
+	args        []promptArg
+	render      func(args map[string]string) string
+}
+
+type promptArg struct {
+	name        string
+	description string
+	required    bool
+}
+
+var testingPrompts = []testingPrompt{
+	{
+		name:        "test-for-idor",
+		description: "Test a captured request for insecure direct object references (IDOR/BOLA)",
+		args: []promptArg{
+			{name: "flow_id", description: "Proxy flow_id of a request that accesses an object by identifier", required: true},
+			{name: "other_identity", description: "Credentials of a second user (e.g. 'Cookie: session=...'), if available"},
+		},
+		render: renderIDORPrompt,
+	},
+	{
+		name:        "validate-xss",
+		description: "Determine whether a parameter is reflected into a response in an exploitable XSS context",
+		args: []promptArg{
+			{name: "flow_id", description: "Proxy flow_id of the request carrying the parameter", required: true},
+			{name: "parameter", description: "Query, form, or JSON parameter to test (all reflected inputs if omitted)"},
+		},
+		render: renderXSSPrompt,
+	},
+	{
+		name:        "triage-oast-event",
+		description: "Assess whether an out-of-band interaction demonstrates a real vulnerability",
+		args: []promptArg{
+			{name: "oast_id", description: "OAST session ID or label", required: true},
+			{name: "event_id", description: "Specific event to triage (latest events if omitted)"},
+		},
+		render: renderOastTriagePrompt,
+	},
+}
+
+// addPrompts registers the methodology prompts for clients that support prompt discovery.
+func (m *mcpServer) addPrompts() {
+	for _, p := range testingPrompts {
+		opts := []mcp.PromptOption{mcp.WithPromptDescription(p.description)}
+		for _, a := range p.args {
+			argOpts := []mcp.ArgumentOption{mcp.ArgumentDescription(a.description)}
+			if a.required {
+				argOpts = append(argOpts, mcp.RequiredArgument())
+			}
+			opts = append(opts, mcp.WithArgument(a.name, argOpts...))
+		}
+		m.server.AddPrompt(mcp.NewPrompt(p.name, opts...), promptHandler(p))
+	}
+}
+
+func promptHandler(p testingPrompt) func(context.Context, mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+	return func(ctx context.Context, req mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+		args := req.Params.Arguments
+		for _, a := range p.args {
+			if a.required && strings.TrimSpace(args[a.name]) == "" {
+				return nil, errors.New(a.name + " is required")
+			}
+		}
+		log.Printf("mcp/prompt: rendering %s", p.name)
+
+		return mcp.NewGetPromptResult(p.description, []mcp.PromptMessage{
+			mcp.NewPromptMessage(mcp.RoleUser, mcp.NewTextContent(p.render(args))),
+		}), nil
+	}
+}
+
+func renderIDORPrompt(args map[string]string) string {
+	identityStep := "5. Ask the user for a second account's credentials (or a logged-out session) and replay the original request with them via add_headers/remove_headers."
+	if other := args["other_identity"]; other != "" {
+		identityStep = fmt.Sprintf("5. Replay the original request with the second identity: add_headers [%q] (remove the original credential headers first).", other)
+	}
+
+	return fmt.Sprintf(`# Test flow %s for IDOR
+
+1. proxy_get flow_id=%s to see the full request. List every object identifier (path segments, query params, JSON fields, headers) and note which user owns it.
+2. replay_send flow_id=%s label=idor-baseline to record the unmodified response.
+3. For each identifier, replay_send with a neighboring or known-foreign value (path, set_query, or set_json). Prefer IDs observed elsewhere in proxy history over guesses.
+4. Compare each result with the baseline using replay_get: status, size, and whether the body contains another user's data.
+%s
+6. Check write methods too: if a GET is protected, try the same object with PUT/PATCH/DELETE (method override) only after confirming with the user.
+
+Report: which identifiers are enforced, which are not, and the exact replay_ids that demonstrate access to foreign objects.`,
+		args["flow_id"], args["flow_id"], args["flow_id"], identityStep)
+}
+
+func renderXSSPrompt(args map[string]string) string {
+	target := "each user-controlled parameter"
+	if p := args["parameter"]; p != "" {
+		target = "parameter `" + p + "`"
+	}
+
+	return fmt.Sprintf(`# Validate XSS in flow %s
+
+1. proxy_get flow_id=%s and identify %s.
+2. replay_send with a unique alphanumeric canary (e.g. sx7q9k) in %s, then replay_get to find where it is reflected.
+3. For each reflection, classify the context: HTML text, attribute (quoted/unquoted), script block, URL, or JSON. Note the response Content-Type and any Content-Security-Policy header.
+4. Probe only the characters the context needs (< > " ' `+"`"+` / ;). Use encode_url/encode_html to test whether encoding bypasses filtering.
+5. Build a minimal context-appropriate payload and replay it. A reflection is exploitable only if it executes in a browser: JSON responses, text/plain, or fully encoded output are not.
+6. Ask the user to confirm execution in their browser before reporting.
+
+Report: reflection context, filtered characters, working payload (or why none works), CSP impact, and the replay_ids used.`,
+		args["flow_id"], args["flow_id"], target, target)
+}
+
+func renderOastTriagePrompt(args map[string]string) string {
+	eventStep := "oast_poll oast_id=" + args["oast_id"] + " to list recent events, then oast_get each unexplained one."
+	if id := args["event_id"]; id != "" {
+		eventStep = "oast_get oast_id=" + args["oast_id"] + " event_id=" + id + " for the full interaction."
+	}
+
+	return fmt.Sprintf(`# Triage OAST interactions for %s
+
+1. %s
+2. Map the subdomain tag back to the payload and request that used it (replay_history helps). Untagged hits are weak evidence.
+3. Classify the interaction: DNS only (resolver lookup, possibly from a mail filter or link scanner), HTTP (server-side fetch; check User-Agent and path), or SMTP.
+4. Check the source IP: target infrastructure vs. a public resolver or a security vendor. Scanners and link previewers produce false positives.
+5. Reproduce with a fresh subdomain tag via replay_send and confirm the interaction follows the request, not something else.
+6. Escalate carefully: for HTTP callbacks, test whether response data is returned or internal hosts are reachable, after confirming scope with the user.
+
+Report: interaction type, triggering request, reproducibility, and likely impact (blind SSRF, DNS-only, or false positive).`,
+		args["oast_id"], eventStep)
+}
@@ -0,0 +1,83 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMCP_Prompts(t *testing.T) {
+	t.Parallel()
+
+	_, mcpClient, _, _, _ := setupMCPServerWithMock(t)
+
+	list, err := mcpClient.ListPrompts(t.Context(), mcp.ListPromptsRequest{})
+	require.NoError(t, err)
+	var names []string
+	for _, p := range list.Prompts {
+		names = append(names, p.Name)
+	}
+	assert.ElementsMatch(t, []string{"test-for-idor", "validate-xss", "triage-oast-event"}, names)
+
+	tests := []struct {
+		name     string
+		prompt   string
+		args     map[string]string
+		contains []string
+		wantErr  string
+	}{
+		{
+			name:     "idor",
+			prompt:   "test-for-idor",
+			args:     map[string]string{"flow_id": "f7k2x", "other_identity": "Cookie: session=b"},
+			contains: []string{"proxy_get flow_id=f7k2x", `"Cookie: session=b"`},
+		},
+		{
+			name:     "idor_without_identity",
+			prompt:   "test-for-idor",
+			args:     map[string]string{"flow_id": "f7k2x"},
+			contains: []string{"Ask the user for a second account"},
+		},
+		{
+			name:     "xss_parameter",
+			prompt:   "validate-xss",
+			args:     map[string]string{"flow_id": "abc", "parameter": "q"},
+			contains: []string{"parameter `q`", "Content-Security-Policy"},
+		},
+		{
+			name:     "oast_event",
+			prompt:   "triage-oast-event",
+			args:     map[string]string{"oast_id": "o1", "event_id": "e1"},
+			contains: []string{"oast_get oast_id=o1 event_id=e1"},
+		},
+		{
+			name:    "missing_required",
+			prompt:  "validate-xss",
+			args:    map[string]string{},
+			wantErr: "flow_id is required",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var req mcp.GetPromptRequest
+			req.Params.Name = tt.prompt
+			req.Params.Arguments = tt.args
+			result, err := mcpClient.GetPrompt(t.Context(), req)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, result.Messages, 1)
+			text, ok := result.Messages[0].Content.(mcp.TextContent)
+			require.True(t, ok)
+			for _, s := range tt.contains {
+				assert.Contains(t, text.Text, s)
+			}
+		})
+	}
+}
@@ -1,33 +1,52 @@
 package service
 
 import (
+	"bufio"
+	"bytes"
+	"cmp"
 	"context"
 	"encoding/base64"
 	"errors"
 	"fmt"
+	"io"
 	"log"
+	"maps"
+	"net/http"
+	"net/textproto"
+	"net/url"
+	"os"
+	"regexp"
 	"slices"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/go-analyze/bulk"
 	"github.com/mark3labs/mcp-go/mcp"
 
+	"github.com/go-harden/llm-security-toolbox/sectool/export"
 	"github.com/go-harden/llm-security-toolbox/sectool/protocol"
 	"github.com/go-harden/llm-security-toolbox/sectool/service/store"
 )
 
+// maxHARBytes caps the size of an imported HAR capture.
+const maxHARBytes = 200 << 20
+
 func (m *mcpServer) proxyPollTool() mcp.Tool {
 	return mcp.NewTool("proxy_poll",
-		mcp.WithDescription(`Query proxy history: summary (default) or flows mode.
+		mcp.WithDescription(`Query proxy history: summary (default), endpoints, or flows mode.
 
 Output modes:
 - "summary" (default): Returns traffic grouped by (host, path, method, status). Use first to understand available traffic.
+- "endpoints": Returns one row per (host, path, method) with count, status breakdown, size range, and first/last seen (from response Date headers). Suited to coverage reports.
 - "flows": Returns individual flows with flow_id for use with proxy_get or replay_send. Requires at least one filter or limit.
 
 Filters: host/path/exclude_host/exclude_path use glob (*, ?). method/status are comma-separated (status supports ranges like 2XX).
 Search: contains searches URL+headers; contains_body searches bodies.
-Incremental: since accepts flow_id or "last" (no timestamps). Flows mode only: pagination with limit/offset.`),
-		mcp.WithString("output_mode", mcp.Description("Output mode: 'summary' (default) or 'flows'")),
+Scope: when a project scope is defined (scope_get), only in-scope flows are returned unless in_scope=false.
+Incremental: since accepts flow_id or "last" (no timestamps).
+Pagination (flows mode): flows are ordered by history position and total_count is the number matching the filters across all pages. When more flows remain than limit, the response includes next_cursor; pass it back as cursor with the same filters to fetch the next page. cursor replaces offset.`),
+		mcp.WithString("output_mode", mcp.Description("Output mode: 'summary' (default), 'endpoints', or 'flows'")),
 		mcp.WithString("host", mcp.Description("Filter by host (glob pattern, e.g., '*.example.com')")),
 		mcp.WithString("path", mcp.Description("Filter by path (glob pattern, e.g., '/api/*')")),
 		mcp.WithString("method", mcp.Description("Filter by HTTP method(s), comma-separated (e.g., 'GET,POST')")),
@@ -37,8 +56,11 @@ Incremental: since accepts flow_id or "last" (no timestamps). Flows mode only: p
 		mcp.WithString("since", mcp.Description("Entries after flow_id, or 'last' (cursor). No timestamp support.")),
 		mcp.WithString("exclude_host", mcp.Description("Exclude hosts matching glob pattern")),
 		mcp.WithString("exclude_path", mcp.Description("Exclude paths matching glob pattern")),
+		mcp.WithBoolean("in_scope", mcp.Description("Only flows within the project scope (default: true when a scope is defined)")),
 		mcp.WithNumber("limit", mcp.Description("List mode: max results to return")),
 		mcp.WithNumber("offset", mcp.Description("List mode: skip first N results (applied after filtering)")),
+		mcp.WithString("cursor", mcp.Description("Flows mode: next_cursor from a previous page")),
+		annotateReadOnly,
 	)
 }
 
@@ -47,16 +69,33 @@ func (m *mcpServer) proxyGetTool() mcp.Tool {
 		mcp.WithDescription(`Get full request and response data for a proxy history entry.
 
 Returns headers and body for both request and response. Binary bodies are returned as "<BINARY:N Bytes>" placeholder.
-Use flow_id from proxy_poll (output_mode=list) to identify the entry.`),
-		mcp.WithString("flow_id", mcp.Required(), mcp.Description("Flow ID from proxy_poll")),
+Use flow_id from proxy_poll (output_mode=list) to identify the entry, or 'last'/'last-N' for the most recent history entries.`),
+		mcp.WithString("flow_id", mcp.Required(), mcp.Description("Flow ID from proxy_poll, or "+recentRefUsage)),
+		annotateReadOnly,
+	)
+}
+
+func (m *mcpServer) proxyImportTool() mcp.Tool {
+	return mcp.NewTool("proxy_import",
+		mcp.WithDescription(`Load a HAR capture (e.g., a browser DevTools "Save all as HAR" export) into proxy history.
+
+Imported exchanges become regular flows: find them with proxy_poll and use them with proxy_get, replay_send and the other flow tools.
+Messages are rebuilt as HTTP/1.1 with decoded response bodies; entries that are not http(s) (data:, ws:) are skipped.
+Set exactly one of path (HAR file on the service host) or content (the HAR JSON). Requires the built-in proxy; Burp history can't be written.`),
+		mcp.WithString("path", mcp.Description("Path to a .har file")),
+		mcp.WithString("content", mcp.Description("HAR JSON text, instead of path")),
+		annotateLocalChange,
 	)
 }
 
 func (m *mcpServer) proxyRuleListTool() mcp.Tool {
 	return mcp.NewTool("proxy_rule_list",
-		mcp.WithDescription("List proxy match/replace rules. Use type_filter to control which rules are returned."),
+		mcp.WithDescription(`List proxy match/replace rules in apply order (position 1 first; reorder with proxy_rule_move). Use type_filter to control which rules are returned.
+
+With the built-in proxy, each rule reports hits (messages it modified) and last_hit_at, so a rule that never fires points to a mismatched pattern. Updating a rule resets its counters. Burp does not expose hit counts.`),
 		mcp.WithString("type_filter", mcp.Description("Filter by rule type: 'http', 'websocket', or 'all' (default: 'all')")),
 		mcp.WithNumber("limit", mcp.Description("Maximum number of rules to return")),
+		annotateReadOnly,
 	)
 }
 
@@ -67,13 +106,19 @@ func (m *mcpServer) proxyRuleAddTool() mcp.Tool {
 Types:
   HTTP:      request_header (default), request_body, response_header, response_body
   WebSocket: ws:to-server, ws:to-client, ws:both
-
-Regex: is_regex=true (Java regex). Labels must be unique.`),
-		mcp.WithString("type", mcp.Required(), mcp.Description("Rule type: request_header, request_body, response_header, response_body, ws:to-server, ws:to-client, ws:both")),
+  Session:   session_token (built-in proxy only) keeps a refreshed token in later requests.
+             match=<cookie name> tracks Set-Cookie into the Cookie header; with is_regex=true, match
+             captures the token from responses (group 1) and replace locates it in requests (group 1),
+             e.g. match 'name="csrf" value="([^"]+)"', replace 'csrf=([^&]*)'.
+
+Regex: is_regex=true (Java regex with Burp, Go RE2 with the built-in proxy). Patterns are validated for the active engine, with warnings for Java/Go differences.
+The response includes a preview against the 100 most recent flows: how many the rule would modify and example matches. Labels must be unique.`),
+		mcp.WithString("type", mcp.Required(), mcp.Description("Rule type: request_header, request_body, response_header, response_body, session_token, ws:to-server, ws:to-client, ws:both")),
 		mcp.WithString("match", mcp.Description("Pattern to find")),
 		mcp.WithString("replace", mcp.Description("Replacement text")),
 		mcp.WithString("label", mcp.Description("Optional unique label (usable as rule_id)")),
 		mcp.WithBoolean("is_regex", mcp.Description("Treat match as regex pattern (Java regex syntax)")),
+		annotateDestructive,
 	)
 }
 
@@ -83,11 +128,52 @@ func (m *mcpServer) proxyRuleUpdateTool() mcp.Tool {
 
 Requires at least match or replace. To rename label only, resend existing values with new label.`),
 		mcp.WithString("rule_id", mcp.Required(), mcp.Description("Rule ID or label to update")),
-		mcp.WithString("type", mcp.Required(), mcp.Description("Rule type: HTTP uses request_header/request_body/response_header/response_body; WebSocket uses ws:to-server/ws:to-client/ws:both")),
+		mcp.WithString("type", mcp.Required(), mcp.Description("Rule type: HTTP uses request_header/request_body/response_header/response_body/session_token; WebSocket uses ws:to-server/ws:to-client/ws:both")),
 		mcp.WithString("match", mcp.Description("Pattern to match")),
 		mcp.WithString("replace", mcp.Description("Replacement text")),
 		mcp.WithString("label", mcp.Description("Optional new label (unique); omit to keep existing")),
 		mcp.WithBoolean("is_regex", mcp.Description("Treat match as regex pattern (Java regex syntax)")),
+		annotateDestructive,
+	)
+}
+
+func (m *mcpServer) proxyRuleToggleTool() mcp.Tool {
+	return mcp.NewTool("proxy_rule_toggle",
+		mcp.WithDescription(`Enable or disable a proxy match/replace rule by rule_id or label (searches HTTP+WS).
+
+Disabled rules keep their configuration and stay in proxy_rule_list; use this to compare traffic with and without a rule instead of deleting and re-adding it.`),
+		mcp.WithString("rule_id", mcp.Required(), mcp.Description("Rule ID or label to toggle")),
+		mcp.WithBoolean("enabled", mcp.Description("New state; omit to flip the current state")),
+		annotateDestructive,
+	)
+}
+
+func (m *mcpServer) proxyRuleMoveTool() mcp.Tool {
+	return mcp.NewTool("proxy_rule_move",
+		mcp.WithDescription(`Move a proxy match/replace rule to a new position (searches HTTP+WS).
+
+Rules apply in order (position 1 first), so interacting rules such as a header strip followed by a header add need a fixed order. Positions are within the rule's HTTP or WebSocket set, as shown by proxy_rule_list; out-of-range positions are clamped.`),
+		mcp.WithString("rule_id", mcp.Required(), mcp.Description("Rule ID or label to move")),
+		mcp.WithNumber("position", mcp.Required(), mcp.Description("New 1-based position (1 = applied first)")),
+		annotateDestructive,
+	)
+}
+
+func (m *mcpServer) proxyRuleTestTool() mcp.Tool {
+	return mcp.NewTool("proxy_rule_test",
+		mcp.WithDescription(`Dry-run a match/replace rule against a sample message without touching live traffic.
+
+Test an existing rule (rule_id) or a proposed one (type, match, replace, is_regex). Input is flow_id (uses the request for request_* types, the response for response_* types) or sample (raw HTTP message, or a WebSocket message for ws:* types).
+Returns the targeted section (headers or body) before and after, with the match count. Header rules match "Name: value" lines with canonical names, sorted, Host first and no start line, as the built-in proxy applies them. Header rules with empty match add the replace line as a new header.
+Patterns run with Go regex syntax (RE2); Burp's Java regex differs for lookarounds and backreferences.`),
+		mcp.WithString("rule_id", mcp.Description("Existing rule ID or label to test (overrides type/match/replace/is_regex)")),
+		mcp.WithString("type", mcp.Description("Rule type for a proposed rule: request_header, request_body, response_header, response_body, ws:to-server, ws:to-client, ws:both")),
+		mcp.WithString("match", mcp.Description("Pattern to find")),
+		mcp.WithString("replace", mcp.Description("Replacement text")),
+		mcp.WithBoolean("is_regex", mcp.Description("Treat match as regex pattern")),
+		mcp.WithString("flow_id", mcp.Description("Proxy flow to test against")),
+		mcp.WithString("sample", mcp.Description("Raw sample message to test against (alternative to flow_id)")),
+		annotateReadOnly,
 	)
 }
 
@@ -95,6 +181,7 @@ func (m *mcpServer) proxyRuleDeleteTool() mcp.Tool {
 	return mcp.NewTool("proxy_rule_delete",
 		mcp.WithDescription("Delete a proxy match/replace rule by rule_id or label (searches HTTP+WS)."),
 		mcp.WithString("rule_id", mcp.Required(), mcp.Description("Rule ID or label to delete")),
+		annotateDestructive,
 	)
 }
 func (m *mcpServer) handleProxyPoll(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
@@ -118,8 +205,29 @@ func (m *mcpServer) handleProxyPoll(ctx context.Context, req mcp.CallToolRequest
 		Offset:       req.GetInt("offset", 0),
 	}
 
+	scope := m.service.projectScope()
+	if _, set := req.GetArguments()["in_scope"]; set && req.GetBool("in_scope", false) && scope == nil {
+		return errorResult("no project scope defined; set one with scope_set or `sectool config scope --target <url>`"), nil
+	} else if req.GetBool("in_scope", true) {
+		listReq.Scope = scope
+	}
+
+	var cursorOffset uint32
+	cursor := req.GetString("cursor", "")
+	if cursor != "" {
+		pos, err := decodeCursor("proxy_poll", cursor)
+		if err != nil {
+			return errorResult(err.Error()), nil
+		}
+		n, err := strconv.ParseUint(pos, 10, 32)
+		if err != nil {
+			return errorResult("invalid cursor for proxy_poll"), nil
+		}
+		cursorOffset = uint32(n)
+	}
+
 	// Flows mode requires at least one filter
-	if outputMode == "flows" && !listReq.HasFilters() {
+	if outputMode == "flows" && cursor == "" && !listReq.HasFilters() {
 		return errorResult("flows mode requires at least one filter or limit; use output_mode=summary first to see available traffic"), nil
 	}
 
@@ -130,21 +238,28 @@ func (m *mcpServer) handleProxyPoll(ctx context.Context, req mcp.CallToolRequest
 		return errorResultFromErr("failed to fetch proxy history: ", err), nil
 	}
 
-	lastOffset := m.service.proxyLastOffset.Load()
-	filtered := applyProxyFilters(allEntries, listReq, m.service.flowStore, lastOffset)
+	client := m.client(ctx)
+	filtered := applyProxyFilters(allEntries, listReq, m.service.flowStore, client.proxyNextOffset.Load())
 
 	switch outputMode {
 	case "flows":
-		// Apply offset after filtering
-		if listReq.Offset > 0 && listReq.Offset < len(filtered) {
+		totalCount := len(filtered)
+		// A cursor resumes after the last flow of the previous page, otherwise apply offset after filtering
+		if cursor != "" {
+			filtered = bulk.SliceFilter(func(e flowEntry) bool {
+				return e.offset > cursorOffset
+			}, filtered)
+		} else if listReq.Offset > 0 && listReq.Offset < len(filtered) {
 			filtered = filtered[listReq.Offset:]
 		} else if listReq.Offset >= len(filtered) {
 			filtered = nil
 		}
 
-		// Apply limit after offset
+		// Apply limit after offset, leaving a cursor when flows remain
+		var nextCursor string
 		if listReq.Limit > 0 && len(filtered) > listReq.Limit {
 			filtered = filtered[:listReq.Limit]
+			nextCursor = encodeCursor("proxy_poll", strconv.FormatUint(uint64(filtered[len(filtered)-1].offset), 10))
 		}
 
 		var maxOffset uint32
@@ -156,10 +271,7 @@ func (m *mcpServer) handleProxyPoll(ctx context.Context, req mcp.CallToolRequest
 
 		flows := make([]protocol.FlowEntry, 0, len(filtered))
 		for _, entry := range filtered {
-			headerLines := extractHeaderLines(entry.request)
-			_, reqBody := splitHeadersBody([]byte(entry.request))
-			hash := store.ComputeFlowHashSimple(entry.method, entry.host, entry.path, headerLines, reqBody)
-			flowID := m.service.flowStore.Register(entry.offset, hash)
+			flowID := m.service.registerFlow(entry)
 
 			scheme, port, _ := inferSchemeAndPort(entry.host)
 
@@ -174,13 +286,19 @@ func (m *mcpServer) handleProxyPoll(ctx context.Context, req mcp.CallToolRequest
 				ResponseLength: entry.respLen,
 			})
 		}
-		log.Printf("proxy/poll: returning %d flows", len(flows))
+		log.Printf("proxy/poll: returning %d of %d flows", len(flows), totalCount)
 
-		if maxOffset > lastOffset {
-			m.service.proxyLastOffset.Store(maxOffset)
+		if len(filtered) > 0 {
+			client.markProxyOffset(maxOffset)
 		}
 
-		return jsonResult(&protocol.ProxyPollResponse{Flows: flows})
+		return jsonResult(&protocol.ProxyPollResponse{Flows: flows, NextCursor: nextCursor, TotalCount: totalCount})
+
+	case "endpoints":
+		endpoints := aggregateEndpoints(filtered)
+		log.Printf("proxy/poll: returning %d endpoints from %d entries", len(endpoints), len(filtered))
+
+		return jsonResult(&protocol.ProxyPollResponse{Endpoints: endpoints})
 
 	default: // summary
 		agg := aggregateByTuple(filtered, func(e flowEntry) (string, string, string, int) {
@@ -201,6 +319,10 @@ func (m *mcpServer) handleProxyGet(ctx context.Context, req mcp.CallToolRequest)
 	if flowID == "" {
 		return errorResult("flow_id is required"), nil
 	}
+	flowID, err := m.service.resolveFlowRef(ctx, flowID)
+	if err != nil {
+		return errorResultFromErr("", err), nil
+	}
 
 	// Hidden parameter for CLI: returns full base64-encoded bodies instead of previews
 	fullBody := req.GetBool("full_body", false)
@@ -267,6 +389,68 @@ func (m *mcpServer) handleProxyGet(ctx context.Context, req mcp.CallToolRequest)
 	})
 }
 
+func (m *mcpServer) handleProxyImport(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if err := m.requireWorkflow(); err != nil {
+		return err, nil
+	}
+	importer, ok := m.service.httpBackend.(HistoryImporter)
+	if !ok {
+		return errorResult("the HTTP backend can't import history: use the built-in proxy (start the service without Burp)"), nil
+	}
+
+	path, content := req.GetString("path", ""), req.GetString("content", "")
+	if (path == "") == (content == "") {
+		return errorResult("exactly one of path or content is required"), nil
+	}
+	var r io.Reader = strings.NewReader(content)
+	if path != "" {
+		if err := m.service.guard.CheckReadable(path); err != nil {
+			return errorResultFromErr("failed to open HAR: ", err), nil
+		}
+		f, err := os.Open(path)
+		if err != nil {
+			return errorResultFromErr("failed to open HAR: ", err), nil
+		}
+		defer func() { _ = f.Close() }()
+		r = f
+	}
+
+	exchanges, skipped, err := export.ReadHAR(io.LimitReader(r, maxHARBytes))
+	if err != nil {
+		return errorResultFromErr("", err), nil
+	}
+	entries := make([]ProxyEntry, 0, len(exchanges))
+	hosts := make(map[string]bool)
+	for _, ex := range exchanges {
+		entries = append(entries, harProxyEntry(ex))
+		if u, err := url.Parse(ex.URL); err == nil {
+			hosts[u.Host] = true
+		}
+	}
+	if err := importer.ImportHistory(ctx, entries); err != nil {
+		return errorResultFromErr("failed to import history: ", err), nil
+	}
+
+	log.Printf("mcp/proxy_import: imported %d entries (%d skipped) across %d hosts", len(entries), skipped, len(hosts))
+	return jsonResult(protocol.ProxyImportResponse{
+		Imported: len(entries),
+		Skipped:  skipped,
+		Hosts:    slices.Sorted(maps.Keys(hosts)),
+	})
+}
+
+// harProxyEntry converts an exchange read from a HAR into a proxy history entry.
+// History infers https unless the Host header names port 80, so plain-HTTP
+// requests on the default port get an explicit one.
+func harProxyEntry(ex export.Exchange) ProxyEntry {
+	req := ex.Request
+	if u, err := url.Parse(ex.URL); err == nil && u.Scheme == schemeHTTP && u.Port() == "" {
+		headers, body := splitHeadersBody(req)
+		req = append(setHeader(headers, "Host", u.Host+":80"), body...)
+	}
+	return ProxyEntry{Request: string(req), Response: string(ex.Response)}
+}
+
 func (m *mcpServer) handleProxyRuleList(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	if err := m.requireWorkflow(); err != nil {
 		return err, nil
@@ -334,6 +518,15 @@ func (m *mcpServer) handleProxyRuleAdd(ctx context.Context, req mcp.CallToolRequ
 	log.Printf("mcp/proxy_rule_add: type=%s label=%q", ruleType, label)
 
 	isRegex := req.GetBool("is_regex", false)
+	var warnings []string
+	if isRegex && ruleType != RuleTypeSessionToken {
+		_, java := m.service.httpBackend.(*BurpBackend)
+		var err error
+		if warnings, err = validateRuleRegex(match, replace, java); err != nil {
+			return errorResult(err.Error()), nil
+		}
+	}
+
 	rule, err := m.service.httpBackend.AddRule(ctx, ProxyRuleInput{
 		Label:   label,
 		Type:    ruleType,
@@ -349,7 +542,17 @@ func (m *mcpServer) handleProxyRuleAdd(ctx context.Context, req mcp.CallToolRequ
 	}
 
 	log.Printf("mcp/proxy_rule_add: created rule %s", rule.RuleID)
-	return jsonResult(rule)
+
+	resp := protocol.RuleAddResponse{RuleEntry: *rule, Warnings: warnings}
+	if entries, err := m.service.fetchAllProxyEntries(ctx); err != nil {
+		resp.Warnings = append(resp.Warnings, "preview skipped: "+err.Error())
+	} else {
+		resp.Preview = m.service.previewRule(entries, storedRule{Type: ruleType, Match: match, Replace: replace, IsRegex: isRegex})
+		if resp.Preview != nil && resp.Preview.Scanned > 0 && resp.Preview.Matched == 0 {
+			resp.Warnings = append(resp.Warnings, fmt.Sprintf("pattern matched none of the %d most recent flows; check it with proxy_rule_test", resp.Preview.Scanned))
+		}
+	}
+	return jsonResult(resp)
 }
 
 func (m *mcpServer) handleProxyRuleUpdate(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
@@ -406,6 +609,218 @@ func (m *mcpServer) handleProxyRuleUpdate(ctx context.Context, req mcp.CallToolR
 	return jsonResult(rule)
 }
 
+func (m *mcpServer) handleProxyRuleToggle(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if err := m.requireWorkflow(); err != nil {
+		return err, nil
+	}
+
+	ruleID := req.GetString("rule_id", "")
+	if ruleID == "" {
+		return errorResult("rule_id is required"), nil
+	}
+
+	var enabled *bool
+	if args := req.GetArguments(); args != nil {
+		if _, ok := args["enabled"]; ok {
+			v := req.GetBool("enabled", true)
+			enabled = &v
+		}
+	}
+
+	rule, err := m.service.httpBackend.ToggleRule(ctx, ruleID, enabled)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return errorResult("rule not found"), nil
+		}
+		return errorResultFromErr("failed to toggle rule: ", err), nil
+	}
+
+	log.Printf("mcp/proxy_rule_toggle: rule %s enabled=%v", rule.RuleID, rule.Enabled)
+	return jsonResult(rule)
+}
+
+func (m *mcpServer) handleProxyRuleMove(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if err := m.requireWorkflow(); err != nil {
+		return err, nil
+	}
+
+	ruleID := req.GetString("rule_id", "")
+	if ruleID == "" {
+		return errorResult("rule_id is required"), nil
+	}
+	position := req.GetInt("position", 0)
+	if position < 1 {
+		return errorResult("position must be 1 or greater"), nil
+	}
+
+	rule, err := m.service.httpBackend.MoveRule(ctx, ruleID, position)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return errorResult("rule not found"), nil
+		}
+		return errorResultFromErr("failed to move rule: ", err), nil
+	}
+
+	log.Printf("mcp/proxy_rule_move: rule %s now at position %d", rule.RuleID, rule.Position)
+	return jsonResult(rule)
+}
+
+func (m *mcpServer) handleProxyRuleTest(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if err := m.requireWorkflow(); err != nil {
+		return err, nil
+	}
+
+	rule := storedRule{
+		Type:    req.GetString("type", ""),
+		Match:   req.GetString("match", ""),
+		Replace: req.GetString("replace", ""),
+		IsRegex: req.GetBool("is_regex", false),
+	}
+	if ruleID := req.GetString("rule_id", ""); ruleID != "" {
+		entry, err := m.findRuleEntry(ctx, ruleID)
+		if err != nil {
+			return errorResultFromErr("failed to look up rule: ", err), nil
+		} else if entry == nil {
+			return errorResult("rule not found"), nil
+		}
+		rule = storedRule{Type: entry.Type, Match: entry.Match, Replace: entry.Replace, IsRegex: entry.IsRegex}
+	}
+	if rule.Type == "" {
+		return errorResult("type is required (or pass rule_id)"), nil
+	} else if rule.Type == RuleTypeSessionToken {
+		return errorResult(RuleTypeSessionToken + " rules depend on live responses; check proxy_rule_list hits after browsing instead"), nil
+	} else if err := validateRuleTypeAny(rule.Type); err != nil {
+		return errorResult(err.Error()), nil
+	} else if rule.Match == "" && rule.Replace == "" {
+		return errorResult("match or replace is required"), nil
+	}
+	if rule.IsRegex {
+		compiled, err := regexp.Compile(rule.Match)
+		if err != nil {
+			return errorResult("invalid regex pattern: " + err.Error()), nil
+		}
+		rule.compiled = compiled
+	}
+
+	sample := req.GetString("sample", "")
+	flowID := req.GetString("flow_id", "")
+	switch {
+	case flowID != "" && sample != "":
+		return errorResult("pass flow_id or sample, not both"), nil
+	case flowID != "":
+		if isWSType(rule.Type) {
+			return errorResult("flow_id is not supported for WebSocket rules; pass the message as sample"), nil
+		}
+		flowID, err := m.service.resolveFlowRef(ctx, flowID)
+		if err != nil {
+			return errorResultFromErr("", err), nil
+		}
+		entry, ok := m.service.flowStore.Lookup(flowID)
+		if !ok {
+			return errorResult("flow_id not found: run proxy_poll to see available flows"), nil
+		}
+		proxyEntries, err := m.service.httpBackend.GetProxyHistory(ctx, 1, entry.Offset)
+		if err != nil {
+			return errorResultFromErr("failed to fetch flow: ", err), nil
+		} else if len(proxyEntries) == 0 {
+			return errorResult("flow not found in proxy history"), nil
+		}
+		sample = proxyEntries[0].Request
+		if rule.Type == RuleTypeResponseHeader || rule.Type == RuleTypeResponseBody {
+			sample = proxyEntries[0].Response
+		}
+	case sample == "":
+		return errorResult("flow_id or sample is required"), nil
+	}
+
+	resp := dryRunRule(rule, sample)
+	if _, ok := m.service.httpBackend.(*BurpBackend); ok && rule.IsRegex && resp.Warning == "" {
+		resp.Warning = "evaluated with Go regex; Burp applies Java regex, which differs for lookarounds and backreferences"
+	}
+	log.Printf("mcp/proxy_rule_test: type=%s matches=%d", rule.Type, resp.Matches)
+	return jsonResult(resp)
+}
+
+// findRuleEntry returns the HTTP or WebSocket rule with the given ID or label, or nil.
+func (m *mcpServer) findRuleEntry(ctx context.Context, idOrLabel string) (*protocol.RuleEntry, error) {
+	for _, websocket := range []bool{false, true} {
+		rules, err := m.service.httpBackend.ListRules(ctx, websocket)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range rules {
+			if r.RuleID == idOrLabel || r.Label == idOrLabel {
+				return &r, nil
+			}
+		}
+	}
+	return nil, nil
+}
+
+// dryRunRule applies rule to the section of sample it targets: the header block or body
+// of an HTTP message, or the whole message for WebSocket rules. Header rules see the
+// headers as the built-in proxy serializes them (see ruleHeaderBlock).
+func dryRunRule(rule storedRule, sample string) protocol.RuleTestResponse {
+	resp := protocol.RuleTestResponse{Type: rule.Type, Section: "message"}
+
+	var before []byte
+	if isWSType(rule.Type) {
+		before = []byte(sample)
+	} else {
+		// Accept LF-only samples as typed by hand
+		if !strings.Contains(sample, "\r\n") {
+			sample = strings.ReplaceAll(sample, "\n", "\r\n")
+		}
+		headers, body := splitHeadersBody([]byte(sample))
+		if strings.HasSuffix(rule.Type, "_header") {
+			resp.Section, before = "headers", sampleHeaderBlock(headers, rule.Type == RuleTypeRequestHeader)
+		} else {
+			resp.Section, before = "body", body
+		}
+	}
+
+	var after []byte
+	switch {
+	case rule.Match == "" && resp.Section == "headers":
+		head := bytes.TrimRight(before, "\r\n") // insert ahead of the blank line
+		after = slices.Concat(head, []byte("\r\n"+rule.Replace), before[len(head):])
+		resp.Matches = 1
+	case rule.Match == "":
+		after = before
+		resp.Warning = "empty match only adds headers; it never changes a " + resp.Section
+	case rule.IsRegex:
+		resp.Matches = len(rule.compiled.FindAllIndex(before, -1))
+		after = applyMatchReplace(before, rule)
+	default:
+		resp.Matches = bytes.Count(before, []byte(rule.Match))
+		after = applyMatchReplace(before, rule)
+	}
+
+	resp.Before = string(before)
+	resp.After = string(after)
+	resp.Changed = resp.Before != resp.After
+	if resp.Matches == 0 && resp.Warning == "" {
+		resp.Warning = "pattern did not match; the rule would not fire on this " + resp.Section
+	}
+	return resp
+}
+
+// sampleHeaderBlock parses the header block of a sample message, start line first, and
+// serializes it with ruleHeaderBlock. Request Host moves to the front, as net/http
+// keeps it outside the header map.
+func sampleHeaderBlock(headers []byte, request bool) []byte {
+	_, fields, _ := bytes.Cut(headers, []byte("\r\n"))
+	r := textproto.NewReader(bufio.NewReader(bytes.NewReader(append(fields, "\r\n\r\n"...))))
+	mime, _ := r.ReadMIMEHeader() // keeps the fields parsed before an error
+	header := http.Header(mime)
+	var host string
+	if request {
+		host = header.Get("Host")
+		header.Del("Host")
+	}
+	return ruleHeaderBlock(header, host)
+}
+
 func (m *mcpServer) handleProxyRuleDelete(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	if err := m.requireWorkflow(); err != nil {
 		return err, nil
@@ -439,6 +854,80 @@ type flowEntry struct {
 	response string
 }
 
+// aggregateEndpoints groups entries by (host, normalized path, method), sorted by host, path, then method.
+func aggregateEndpoints(entries []flowEntry) []protocol.EndpointEntry {
+	type endpointKey struct {
+		Host   string
+		Path   string
+		Method string
+	}
+	type endpointAgg struct {
+		statuses         map[int]int
+		count            int
+		minSize, maxSize int
+		first, last      time.Time
+	}
+
+	groups := make(map[endpointKey]*endpointAgg)
+	for _, e := range entries {
+		key := endpointKey{Host: e.host, Path: normalizePath(e.path), Method: e.method}
+		agg, ok := groups[key]
+		if !ok {
+			agg = &endpointAgg{statuses: make(map[int]int), minSize: e.respLen, maxSize: e.respLen}
+			groups[key] = agg
+		}
+		agg.count++
+		agg.statuses[e.status]++
+		agg.minSize = min(agg.minSize, e.respLen)
+		agg.maxSize = max(agg.maxSize, e.respLen)
+		if seen := extractResponseDate(e.response); !seen.IsZero() {
+			if agg.first.IsZero() || seen.Before(agg.first) {
+				agg.first = seen
+			}
+			if seen.After(agg.last) {
+				agg.last = seen
+			}
+		}
+	}
+
+	result := make([]protocol.EndpointEntry, 0, len(groups))
+	for key, agg := range groups {
+		codes := slices.Sorted(maps.Keys(agg.statuses))
+		statuses := make([]string, len(codes))
+		for i, code := range codes {
+			statuses[i] = strconv.Itoa(code) + ":" + strconv.Itoa(agg.statuses[code])
+		}
+
+		entry := protocol.EndpointEntry{
+			Host:     key.Host,
+			Path:     truncateString(key.Path, maxPathLength),
+			Method:   key.Method,
+			Count:    agg.count,
+			Statuses: strings.Join(statuses, ","),
+			MinSize:  agg.minSize,
+			MaxSize:  agg.maxSize,
+		}
+		if !agg.first.IsZero() {
+			entry.FirstSeen = agg.first.UTC().Format(time.RFC3339)
+			entry.LastSeen = agg.last.UTC().Format(time.RFC3339)
+		}
+		result = append(result, entry)
+	}
+	slices.SortFunc(result, func(a, b protocol.EndpointEntry) int {
+		return cmp.Or(cmp.Compare(a.Host, b.Host), cmp.Compare(a.Path, b.Path), cmp.Compare(a.Method, b.Method))
+	})
+
+	return result
+}
+
+// registerFlow assigns (or returns the existing) flow_id for a proxy history entry.
+func (s *Server) registerFlow(entry flowEntry) string {
+	headerLines := extractHeaderLines(entry.request)
+	_, reqBody := splitHeadersBody([]byte(entry.request))
+	hash := store.ComputeFlowHashSimple(entry.method, entry.host, entry.path, headerLines, reqBody)
+	return s.flowStore.Register(entry.offset, hash)
+}
+
 // fetchAllProxyEntries retrieves all proxy history entries from the backend.
 func (s *Server) fetchAllProxyEntries(ctx context.Context) ([]flowEntry, error) {
 	var allEntries []flowEntry
@@ -478,7 +967,8 @@ func (s *Server) fetchAllProxyEntries(ctx context.Context) ([]flowEntry, error)
 }
 
 // applyProxyFilters applies filters that can't be expressed in Burp regex.
-func applyProxyFilters(entries []flowEntry, req *ProxyListRequest, flowStore *store.FlowStore, lastOffset uint32) []flowEntry {
+// nextOffset is where since=last resumes (0 when the client has seen no flows).
+func applyProxyFilters(entries []flowEntry, req *ProxyListRequest, flowStore *store.FlowStore, nextOffset uint32) []flowEntry {
 	if !req.HasFilters() {
 		return entries
 	}
@@ -490,8 +980,8 @@ func applyProxyFilters(entries []flowEntry, req *ProxyListRequest, flowStore *st
 	var hasSince bool
 	if req.Since != "" {
 		if req.Since == "last" {
-			sinceOffset = lastOffset
-			hasSince = true
+			sinceOffset = nextOffset - 1
+			hasSince = nextOffset > 0
 		} else if entry, ok := flowStore.Lookup(req.Since); ok {
 			sinceOffset = entry.Offset
 			hasSince = true
@@ -513,6 +1003,8 @@ func applyProxyFilters(entries []flowEntry, req *ProxyListRequest, flowStore *st
 			return false // Exclude host
 		} else if req.ExcludePath != "" && matchesGlob(e.path, req.ExcludePath) {
 			return false // Exclude path
+		} else if req.Scope != nil && !inScope(req.Scope, e.host, e.path) {
+			return false // Project scope
 		}
 		if req.Contains != "" {
 			// Search URL and headers only (not body)
@@ -542,6 +1034,7 @@ var validRuleTypes = map[string]bool{
 	RuleTypeRequestBody:    true,
 	RuleTypeResponseHeader: true,
 	RuleTypeResponseBody:   true,
+	RuleTypeSessionToken:   true,
 	// WebSocket types
 	"ws:to-server": true,
 	"ws:to-client": true,
@@ -1,12 +1,22 @@
 package service
 
 import (
+	"context"
+	"encoding/base64"
 	"encoding/json"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/go-harden/llm-security-toolbox/sectool/mcpclient"
 	"github.com/go-harden/llm-security-toolbox/sectool/protocol"
 )
 
@@ -395,6 +405,56 @@ func TestMCP_ProxyRulesCRUDWithMock(t *testing.T) {
 		assert.Equal(t, RuleTypeRequestBody, rule.Type)
 	})
 
+	t.Run("toggle_rule", func(t *testing.T) {
+		rule := CallMCPToolJSONOK[protocol.RuleEntry](t, mcpClient, "proxy_rule_toggle", map[string]interface{}{
+			"rule_id": ruleID,
+			"enabled": false,
+		})
+		assert.False(t, rule.Enabled)
+		assert.Equal(t, "new", rule.Replace)
+
+		resp := CallMCPToolJSONOK[protocol.RuleListResponse](t, mcpClient, "proxy_rule_list", nil)
+		idx := slices.IndexFunc(resp.Rules, func(r protocol.RuleEntry) bool { return r.RuleID == ruleID })
+		require.GreaterOrEqual(t, idx, 0)
+		assert.False(t, resp.Rules[idx].Enabled)
+
+		rule = CallMCPToolJSONOK[protocol.RuleEntry](t, mcpClient, "proxy_rule_toggle", map[string]interface{}{
+			"rule_id": "mock-test-updated",
+		})
+		assert.True(t, rule.Enabled)
+
+		result := CallMCPTool(t, mcpClient, "proxy_rule_toggle", map[string]interface{}{"rule_id": "missing"})
+		assert.True(t, result.IsError)
+	})
+
+	t.Run("move_rule", func(t *testing.T) {
+		rule := CallMCPToolJSONOK[protocol.RuleEntry](t, mcpClient, "proxy_rule_move", map[string]interface{}{
+			"rule_id":  ruleID,
+			"position": 99,
+		})
+		resp := CallMCPToolJSONOK[protocol.RuleListResponse](t, mcpClient, "proxy_rule_list", map[string]interface{}{
+			"type_filter": "http",
+		})
+		require.NotEmpty(t, resp.Rules)
+		last := resp.Rules[len(resp.Rules)-1]
+		assert.Equal(t, ruleID, last.RuleID)
+		assert.Equal(t, len(resp.Rules), last.Position)
+		assert.Equal(t, last.Position, rule.Position)
+
+		rule = CallMCPToolJSONOK[protocol.RuleEntry](t, mcpClient, "proxy_rule_move", map[string]interface{}{
+			"rule_id":  ruleID,
+			"position": 1,
+		})
+		assert.Equal(t, 1, rule.Position)
+		resp = CallMCPToolJSONOK[protocol.RuleListResponse](t, mcpClient, "proxy_rule_list", map[string]interface{}{
+			"type_filter": "http",
+		})
+		assert.Equal(t, ruleID, resp.Rules[0].RuleID)
+
+		result := CallMCPTool(t, mcpClient, "proxy_rule_move", map[string]interface{}{"rule_id": ruleID, "position": 0})
+		assert.True(t, result.IsError)
+	})
+
 	t.Run("delete_rule", func(t *testing.T) {
 		_ = CallMCPToolTextOK(t, mcpClient, "proxy_rule_delete", map[string]interface{}{
 			"rule_id": ruleID,
@@ -554,3 +614,511 @@ func TestMCP_ProxyRuleValidation(t *testing.T) {
 		assert.Contains(t, ExtractMCPText(t, result), "not found")
 	})
 }
+
+func TestAggregateEndpoints(t *testing.T) {
+	t.Parallel()
+
+	entries := []flowEntry{
+		{method: "GET", host: "b.com", path: "/users/1", status: 200, respLen: 50,
+			response: "HTTP/1.1 200 OK\r\nDate: Tue, 15 Nov 1994 08:12:31 GMT\r\n\r\n"},
+		{method: "GET", host: "b.com", path: "/users/2", status: 404, respLen: 10,
+			response: "HTTP/1.1 404 Not Found\r\nDate: Tue, 15 Nov 1994 09:00:00 GMT\r\n\r\n"},
+		{method: "GET", host: "b.com", path: "/users/3", status: 200, respLen: 70},
+		{method: "POST", host: "a.com", path: "/login", status: 302, respLen: 0},
+	}
+
+	result := aggregateEndpoints(entries)
+	require.Len(t, result, 2)
+
+	assert.Equal(t, protocol.EndpointEntry{
+		Host: "a.com", Path: "/login", Method: "POST", Count: 1, Statuses: "302:1",
+	}, result[0])
+	assert.Equal(t, protocol.EndpointEntry{
+		Host: "b.com", Path: "/users/*", Method: "GET", Count: 3, Statuses: "200:2,404:1",
+		MinSize: 10, MaxSize: 70,
+		FirstSeen: "1994-11-15T08:12:31Z", LastSeen: "1994-11-15T09:00:00Z",
+	}, result[1])
+}
+
+func TestMCP_ProxyEndpointsWithMock(t *testing.T) {
+	t.Parallel()
+
+	_, mcpClient, mockMCP, _, _ := setupMCPServerWithMock(t)
+
+	mockMCP.AddProxyEntry(
+		"GET /api/items/1 HTTP/1.1\r\nHost: example.com\r\n\r\n",
+		"HTTP/1.1 200 OK\r\n\r\nok",
+		"",
+	)
+	mockMCP.AddProxyEntry(
+		"GET /api/items/2 HTTP/1.1\r\nHost: example.com\r\n\r\n",
+		"HTTP/1.1 403 Forbidden\r\n\r\n",
+		"",
+	)
+
+	resp := CallMCPToolJSONOK[protocol.ProxyPollResponse](t, mcpClient, "proxy_poll", map[string]interface{}{
+		"output_mode": "endpoints",
+	})
+	require.Len(t, resp.Endpoints, 1)
+	assert.Equal(t, "/api/items/*", resp.Endpoints[0].Path)
+	assert.Equal(t, 2, resp.Endpoints[0].Count)
+	assert.Equal(t, "200:1,403:1", resp.Endpoints[0].Statuses)
+	assert.Empty(t, resp.Aggregates)
+}
+
+func TestMCP_ProxyPollCursor(t *testing.T) {
+	t.Parallel()
+
+	_, mcpClient, mockMCP, _, _ := setupMCPServerWithMock(t)
+
+	for _, path := range []string{"/a", "/b", "/c"} {
+		mockMCP.AddProxyEntry(
+			"GET "+path+" HTTP/1.1\r\nHost: example.com\r\n\r\n",
+			"HTTP/1.1 200 OK\r\n\r\n",
+			"",
+		)
+	}
+
+	first := CallMCPToolJSONOK[protocol.ProxyPollResponse](t, mcpClient, "proxy_poll", map[string]interface{}{
+		"output_mode": "flows",
+		"limit":       2,
+	})
+	require.Len(t, first.Flows, 2)
+	assert.Equal(t, "/a", first.Flows[0].Path)
+	assert.Equal(t, 3, first.TotalCount)
+	require.NotEmpty(t, first.NextCursor)
+
+	second := CallMCPToolJSONOK[protocol.ProxyPollResponse](t, mcpClient, "proxy_poll", map[string]interface{}{
+		"output_mode": "flows",
+		"limit":       2,
+		"cursor":      first.NextCursor,
+	})
+	require.Len(t, second.Flows, 1)
+	assert.Equal(t, "/c", second.Flows[0].Path)
+	assert.Equal(t, 3, second.TotalCount)
+	assert.Empty(t, second.NextCursor)
+
+	result := CallMCPTool(t, mcpClient, "proxy_poll", map[string]interface{}{
+		"output_mode": "flows",
+		"cursor":      "not-a-cursor",
+	})
+	assert.True(t, result.IsError)
+}
+
+func TestMCP_MaxOutputBytes(t *testing.T) {
+	t.Parallel()
+
+	_, mcpClient, mockMCP, _, _ := setupMCPServerWithMock(t)
+
+	body := strings.Repeat("a", 20000) + "TAIL"
+	mockMCP.AddProxyEntry(
+		"GET /big HTTP/1.1\r\nHost: example.com\r\n\r\n",
+		"HTTP/1.1 200 OK\r\nContent-Type: text/plain\r\n\r\n"+body,
+		"",
+	)
+	list := CallMCPToolJSONOK[protocol.ProxyPollResponse](t, mcpClient, "proxy_poll", map[string]interface{}{
+		"output_mode": "flows",
+		"limit":       1,
+	})
+	require.Len(t, list.Flows, 1)
+
+	result := CallMCPTool(t, mcpClient, "proxy_get", map[string]interface{}{
+		"flow_id":          list.Flows[0].FlowID,
+		"full_body":        true,
+		"max_output_bytes": 2000,
+	})
+	require.False(t, result.IsError, ExtractMCPText(t, result))
+	text := ExtractMCPText(t, result)
+	assert.LessOrEqual(t, len(text), 2000)
+
+	var shaped struct {
+		OutputTruncated bool   `json:"output_truncated"`
+		OutputID        string `json:"output_id"`
+		OriginalBytes   int    `json:"original_bytes"`
+		Status          int    `json:"status"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(text), &shaped))
+	assert.True(t, shaped.OutputTruncated)
+	assert.Equal(t, 200, shaped.Status)
+	require.NotEmpty(t, shaped.OutputID)
+
+	var full strings.Builder
+	var offset int
+	for {
+		chunk := CallMCPToolJSONOK[protocol.OutputGetResponse](t, mcpClient, "output_get", map[string]interface{}{
+			"output_id": shaped.OutputID,
+			"offset":    offset,
+			"length":    8000,
+		})
+		full.WriteString(chunk.Data)
+		if chunk.NextOffset == 0 {
+			break
+		}
+		offset = chunk.NextOffset
+	}
+	assert.Equal(t, shaped.OriginalBytes, full.Len())
+
+	var original protocol.ProxyGetResponse
+	require.NoError(t, json.Unmarshal([]byte(full.String()), &original))
+	decoded, err := base64.StdEncoding.DecodeString(original.RespBody)
+	require.NoError(t, err)
+	assert.Equal(t, body, string(decoded))
+}
+
+func TestMCP_ConfiguredMaxOutputBytes(t *testing.T) {
+	t.Parallel()
+
+	srv, mcpClient, mockMCP, _, _ := setupMCPServerWithMock(t)
+	srv.cfg.MaxOutputBytes = 2000
+
+	mockMCP.AddProxyEntry(
+		"GET /big HTTP/1.1\r\nHost: example.com\r\n\r\n",
+		"HTTP/1.1 200 OK\r\nContent-Type: text/plain\r\n\r\n"+strings.Repeat("a", 20000),
+		"",
+	)
+	list := CallMCPToolJSONOK[protocol.ProxyPollResponse](t, mcpClient, "proxy_poll", map[string]interface{}{
+		"output_mode": "flows",
+		"limit":       1,
+	})
+	require.Len(t, list.Flows, 1)
+
+	for name, args := range map[string]map[string]interface{}{
+		"default":      {"flow_id": list.Flows[0].FlowID, "full_body": true},
+		"larger_param": {"flow_id": list.Flows[0].FlowID, "full_body": true, "max_output_bytes": 50000},
+	} {
+		t.Run(name, func(t *testing.T) {
+			text := ExtractMCPText(t, CallMCPTool(t, mcpClient, "proxy_get", args))
+			assert.LessOrEqual(t, len(text), 2000)
+
+			var shaped struct {
+				OutputTruncated bool   `json:"output_truncated"`
+				OutputHint      string `json:"output_hint"`
+			}
+			require.NoError(t, json.Unmarshal([]byte(text), &shaped))
+			assert.True(t, shaped.OutputTruncated)
+			assert.Contains(t, shaped.OutputHint, "output_get")
+			assert.Contains(t, shaped.OutputHint, "response_extract")
+		})
+	}
+
+	t.Run("output_get_clamped", func(t *testing.T) {
+		text := ExtractMCPText(t, CallMCPTool(t, mcpClient, "proxy_get", map[string]interface{}{
+			"flow_id": list.Flows[0].FlowID, "full_body": true,
+		}))
+		var shaped struct {
+			OutputID string `json:"output_id"`
+		}
+		require.NoError(t, json.Unmarshal([]byte(text), &shaped))
+		require.NotEmpty(t, shaped.OutputID)
+
+		result := CallMCPTool(t, mcpClient, "output_get", map[string]interface{}{
+			"output_id": shaped.OutputID,
+			"length":    10000000,
+		})
+		require.False(t, result.IsError, ExtractMCPText(t, result))
+		assert.LessOrEqual(t, len(ExtractMCPText(t, result)), 2000)
+		var chunk protocol.OutputGetResponse
+		require.NoError(t, json.Unmarshal([]byte(ExtractMCPText(t, result)), &chunk))
+		assert.Len(t, chunk.Data, 2000-outputGetOverhead)
+		assert.Equal(t, 2000-outputGetOverhead, chunk.NextOffset)
+	})
+}
+
+func TestMCP_ConfiguredMaxOutputBytesCLI(t *testing.T) {
+	t.Parallel()
+
+	srv, mcpClient, mockMCP, _, _ := setupMCPServerWithMock(t)
+	srv.cfg.MaxOutputBytes = 2000
+
+	body := strings.Repeat("a", 20000) + "TAIL"
+	mockMCP.AddProxyEntry(
+		"GET /big HTTP/1.1\r\nHost: example.com\r\n\r\n",
+		"HTTP/1.1 200 OK\r\nContent-Type: text/plain\r\n\r\n"+body,
+		"",
+	)
+	list := CallMCPToolJSONOK[protocol.ProxyPollResponse](t, mcpClient, "proxy_poll", map[string]interface{}{
+		"output_mode": "flows",
+		"limit":       1,
+	})
+	require.Len(t, list.Flows, 1)
+
+	ctx, cancel := context.WithTimeout(t.Context(), 10*time.Second)
+	defer cancel()
+	cli, err := mcpclient.New(ctx, "http://"+srv.mcpServer.Addr()+"/mcp")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = cli.Close() })
+
+	flow, err := cli.ProxyGet(ctx, list.Flows[0].FlowID)
+	require.NoError(t, err)
+	decoded, err := base64.StdEncoding.DecodeString(flow.RespBody)
+	require.NoError(t, err)
+	assert.Equal(t, body, string(decoded))
+}
+
+func TestMCP_ProxyRuleTest(t *testing.T) {
+	t.Parallel()
+
+	_, mcpClient, mockMCP, _, _ := setupMCPServerWithMock(t)
+
+	mockMCP.AddProxyEntry(
+		"GET /api/me HTTP/1.1\r\nHost: mock.example.com\r\nAuthorization: Bearer user-token\r\n\r\n",
+		"HTTP/1.1 200 OK\r\nContent-Type: application/json\r\n\r\n{\"role\":\"user\"}",
+		"",
+	)
+	listResp := CallMCPToolJSONOK[protocol.ProxyPollResponse](t, mcpClient, "proxy_poll", map[string]interface{}{
+		"output_mode": "flows",
+		"method":      "GET",
+	})
+	require.NotEmpty(t, listResp.Flows)
+	flowID := listResp.Flows[0].FlowID
+
+	t.Run("proposed_rule_on_flow_response", func(t *testing.T) {
+		resp := CallMCPToolJSONOK[protocol.RuleTestResponse](t, mcpClient, "proxy_rule_test", map[string]interface{}{
+			"flow_id":  flowID,
+			"type":     RuleTypeResponseBody,
+			"match":    `"role":"\w+"`,
+			"replace":  `"role":"admin"`,
+			"is_regex": true,
+		})
+		assert.Equal(t, "body", resp.Section)
+		assert.Equal(t, 1, resp.Matches)
+		assert.True(t, resp.Changed)
+		assert.JSONEq(t, `{"role":"admin"}`, resp.After)
+	})
+
+	t.Run("existing_rule_on_sample", func(t *testing.T) {
+		_ = CallMCPToolJSONOK[protocol.RuleEntry](t, mcpClient, "proxy_rule_add", map[string]interface{}{
+			"type":    RuleTypeRequestHeader,
+			"label":   "swap-token",
+			"match":   "Bearer user-token",
+			"replace": "Bearer admin-token",
+		})
+		resp := CallMCPToolJSONOK[protocol.RuleTestResponse](t, mcpClient, "proxy_rule_test", map[string]interface{}{
+			"rule_id": "swap-token",
+			"sample":  "GET / HTTP/1.1\nHost: example.com\nAuthorization: Bearer other\n\n",
+		})
+		assert.Equal(t, "headers", resp.Section)
+		assert.False(t, resp.Changed)
+		assert.Contains(t, resp.Warning, "did not match")
+	})
+
+	t.Run("validation", func(t *testing.T) {
+		result := CallMCPTool(t, mcpClient, "proxy_rule_test", map[string]interface{}{
+			"type": RuleTypeRequestBody, "match": "(", "is_regex": true, "sample": "x",
+		})
+		assert.True(t, result.IsError)
+		assert.Contains(t, ExtractMCPText(t, result), "invalid regex")
+
+		result = CallMCPTool(t, mcpClient, "proxy_rule_test", map[string]interface{}{
+			"type": RuleTypeRequestBody, "match": "a", "replace": "b",
+		})
+		assert.True(t, result.IsError)
+
+		result = CallMCPTool(t, mcpClient, "proxy_rule_test", map[string]interface{}{
+			"rule_id": "missing", "sample": "x",
+		})
+		assert.True(t, result.IsError)
+	})
+}
+
+func TestDryRunRule(t *testing.T) {
+	t.Parallel()
+
+	const sample = "POST /login HTTP/1.1\r\nHost: example.com\r\n\r\nuser=alice&pass=secret"
+
+	tests := []struct {
+		name    string
+		rule    storedRule
+		sample  string
+		section string
+		matches int
+		after   string
+	}{
+		{
+			name:    "literal_body",
+			rule:    storedRule{Type: RuleTypeRequestBody, Match: "alice", Replace: "bob"},
+			sample:  sample,
+			section: "body",
+			matches: 1,
+			after:   "user=bob&pass=secret",
+		},
+		{
+			name:    "header_add",
+			rule:    storedRule{Type: RuleTypeRequestHeader, Replace: "X-Debug: 1"},
+			sample:  sample,
+			section: "headers",
+			matches: 1,
+			after:   "Host: example.com\r\nX-Debug: 1\r\n",
+		},
+		{
+			// Matched against sorted, canonicalized lines with Host first, as the proxy sees them.
+			name:    "header_serialized",
+			rule:    storedRule{Type: RuleTypeRequestHeader, Match: "Host: example.com\r\nAccept: */*\r\nX-Token: a", Replace: "Host: example.com\r\nX-Token: b"},
+			sample:  "GET / HTTP/1.1\r\nx-token: a\r\nAccept: */*\r\nHost: example.com\r\n\r\n",
+			section: "headers",
+			matches: 1,
+			after:   "Host: example.com\r\nX-Token: b\r\n",
+		},
+		{
+			name:    "response_header",
+			rule:    storedRule{Type: RuleTypeResponseHeader, Match: "Set-Cookie: a=1", Replace: "Set-Cookie: a=2"},
+			sample:  "HTTP/1.1 200 OK\nset-cookie: a=1\nContent-Type: text/html\n\n<p>hi</p>",
+			section: "headers",
+			matches: 1,
+			after:   "Content-Type: text/html\r\nSet-Cookie: a=2\r\n",
+		},
+		{
+			name:    "regex_multiple",
+			rule:    storedRule{Type: RuleTypeRequestBody, Match: `=\w+`, Replace: "=x", IsRegex: true, compiled: regexp.MustCompile(`=\w+`)},
+			sample:  sample,
+			section: "body",
+			matches: 2,
+			after:   "user=x&pass=x",
+		},
+		{
+			name:    "websocket_message",
+			rule:    storedRule{Type: "ws:both", Match: "ping", Replace: "pong"},
+			sample:  `{"op":"ping"}`,
+			section: "message",
+			matches: 1,
+			after:   `{"op":"pong"}`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			resp := dryRunRule(tc.rule, tc.sample)
+			assert.Equal(t, tc.section, resp.Section)
+			assert.Equal(t, tc.matches, resp.Matches)
+			assert.Equal(t, tc.after, resp.After)
+			assert.True(t, resp.Changed)
+			assert.Empty(t, resp.Warning)
+		})
+	}
+}
+
+func TestMCP_ProxyRuleAddPreview(t *testing.T) {
+	t.Parallel()
+
+	_, mcpClient, mockMCP, _, _ := setupMCPServerWithMock(t)
+
+	mockMCP.AddProxyEntry(
+		"GET /api/me HTTP/1.1\r\nHost: mock.example.com\r\n\r\n",
+		"HTTP/1.1 200 OK\r\nContent-Type: application/json\r\n\r\n{\"role\":\"user\"}",
+		"",
+	)
+
+	t.Run("regex_preview", func(t *testing.T) {
+		resp := CallMCPToolJSONOK[protocol.RuleAddResponse](t, mcpClient, "proxy_rule_add", map[string]interface{}{
+			"type":     RuleTypeResponseBody,
+			"match":    `"role":"(\w+)"`,
+			"replace":  `"role":"admin","was":"$1"`,
+			"is_regex": true,
+		})
+		assert.NotEmpty(t, resp.RuleID)
+		require.NotNil(t, resp.Preview)
+		assert.Equal(t, 1, resp.Preview.Scanned)
+		assert.Equal(t, 1, resp.Preview.Matched)
+		require.Len(t, resp.Preview.Examples, 1)
+		assert.NotEmpty(t, resp.Preview.Examples[0].FlowID)
+		assert.Equal(t, `"role":"admin","was":"user"`, resp.Preview.Examples[0].Result)
+		assert.Empty(t, resp.Warnings)
+	})
+
+	t.Run("no_match_warning", func(t *testing.T) {
+		resp := CallMCPToolJSONOK[protocol.RuleAddResponse](t, mcpClient, "proxy_rule_add", map[string]interface{}{
+			"type":    RuleTypeRequestHeader,
+			"match":   "X-Missing: 1",
+			"replace": "X-Missing: 2",
+		})
+		require.NotNil(t, resp.Preview)
+		assert.Equal(t, 0, resp.Preview.Matched)
+		require.Len(t, resp.Warnings, 1)
+		assert.Contains(t, resp.Warnings[0], "matched none")
+	})
+
+	t.Run("invalid_regex", func(t *testing.T) {
+		result := CallMCPTool(t, mcpClient, "proxy_rule_add", map[string]interface{}{
+			"type": RuleTypeRequestBody, "match": "(?P<id>\\d+)", "replace": "1", "is_regex": true,
+		})
+		assert.True(t, result.IsError)
+		assert.Contains(t, ExtractMCPText(t, result), "(?<name>...)")
+
+		list := CallMCPToolJSONOK[protocol.RuleListResponse](t, mcpClient, "proxy_rule_list", map[string]interface{}{})
+		for _, r := range list.Rules {
+			assert.NotEqual(t, "(?P<id>\\d+)", r.Match)
+		}
+	})
+}
+
+func TestMCP_ProxyImport(t *testing.T) {
+	t.Parallel()
+
+	const har = `{"log":{"version":"1.2","entries":[
+		{"startedDateTime":"2026-01-02T03:04:05Z","time":5,
+		 "request":{"method":"GET","url":"http://example.com/a","httpVersion":"HTTP/1.1","headers":[{"name":"Host","value":"example.com"}]},
+		 "response":{"status":200,"statusText":"OK","httpVersion":"HTTP/1.1","headers":[],"content":{"size":2,"mimeType":"text/plain","text":"hi"}}},
+		{"startedDateTime":"2026-01-02T03:04:06Z","time":5,
+		 "request":{"method":"GET","url":"https://api.example.com/b","httpVersion":"h2","headers":[]},
+		 "response":{"status":204,"statusText":"","httpVersion":"h2","headers":[],"content":{"size":0,"mimeType":""}}},
+		{"startedDateTime":"2026-01-02T03:04:07Z","time":0,
+		 "request":{"method":"GET","url":"data:text/plain,x","httpVersion":"","headers":[]},
+		 "response":{"status":200,"statusText":"OK","httpVersion":"","headers":[],"content":{"size":1,"mimeType":"text/plain"}}}
+	]}}`
+
+	t.Run("built_in_proxy", func(t *testing.T) {
+		backend, err := NewGoProxyBackend(0, t.TempDir())
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = backend.Close() })
+		m := &mcpServer{workflowMode: WorkflowModeNone, service: &Server{httpBackend: backend}}
+
+		harPath := filepath.Join(t.TempDir(), "capture.har")
+		require.NoError(t, os.WriteFile(harPath, []byte(har), 0644))
+		var req mcp.CallToolRequest
+		req.Params.Arguments = map[string]interface{}{"path": harPath}
+		result, err := m.handleProxyImport(t.Context(), req)
+		require.NoError(t, err)
+		require.False(t, result.IsError, ExtractMCPText(t, result))
+
+		var resp protocol.ProxyImportResponse
+		require.NoError(t, json.Unmarshal([]byte(ExtractMCPText(t, result)), &resp))
+		assert.Equal(t, 2, resp.Imported)
+		assert.Equal(t, 1, resp.Skipped)
+		assert.Equal(t, []string{"api.example.com", "example.com"}, resp.Hosts)
+
+		entries, err := backend.GetProxyHistory(t.Context(), 10, 0)
+		require.NoError(t, err)
+		require.Len(t, entries, 2)
+		// Port 80 keeps the plain-HTTP flow on http when replayed
+		assert.Contains(t, entries[0].Request, "Host: example.com:80\r\n")
+		assert.Contains(t, entries[1].Request, "Host: api.example.com\r\n")
+		assert.True(t, strings.HasPrefix(entries[1].Response, "HTTP/1.1 204 No Content\r\n"))
+	})
+
+	t.Run("burp_backend", func(t *testing.T) {
+		_, mcpClient, _, _, _ := setupMCPServerWithMock(t)
+		result := CallMCPTool(t, mcpClient, "proxy_import", map[string]interface{}{"content": har})
+		assert.True(t, result.IsError)
+		assert.Contains(t, ExtractMCPText(t, result), "built-in proxy")
+	})
+
+	t.Run("validation", func(t *testing.T) {
+		backend, err := NewGoProxyBackend(0, t.TempDir())
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = backend.Close() })
+		m := &mcpServer{workflowMode: WorkflowModeNone, service: &Server{httpBackend: backend}}
+
+		for name, args := range map[string]map[string]interface{}{
+			"neither":      {},
+			"both":         {"path": "a.har", "content": har},
+			"missing_file": {"path": filepath.Join(t.TempDir(), "none.har")},
+			"bad_json":     {"content": "{"},
+		} {
+			var req mcp.CallToolRequest
+			req.Params.Arguments = args
+			result, err := m.handleProxyImport(t.Context(), req)
+			require.NoError(t, err)
+			assert.True(t, result.IsError, name)
+		}
+	})
+}
@@ -0,0 +1,135 @@
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"log"
+	"net"
+	"strconv"
+	"time"
+	"unicode/utf8"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/go-harden/llm-security-toolbox/sectool/protocol"
+)
+
+const (
+	defaultRawWait     = 2 * time.Second
+	defaultRawTimeout  = 30 * time.Second
+	defaultRawMaxBytes = 1 << 20
+)
+
+func (m *mcpServer) rawSendTool() mcp.Tool {
+	return mcp.NewTool("raw_send",
+		mcp.WithDescription(`Open a TCP or TLS connection, send bytes exactly as given, and return what comes back.
+
+For probes the HTTP client refuses or rewrites: request smuggling (conflicting Content-Length/Transfer-Encoding, bare LF), malformed framing, pipelined requests, and non-HTTP services such as SMTP or Redis when verifying SSRF.
+Input (exactly one): data (base64) or text (sent as UTF-8; write \r\n line endings explicitly).
+Reading stops when the peer closes, wait (default 2s) passes without new data, max_bytes (default 1 MiB) arrive, or timeout (default 30s) ends the exchange.
+The connection is made directly from sectool, not through the HTTP backend, so it does not appear in proxy history. Project scope applies to host:port.
+The response is returned as text when it is valid UTF-8, else as base64 data.`),
+		mcp.WithString("host", mcp.Required(), mcp.Description("Hostname or IP address")),
+		mcp.WithNumber("port", mcp.Required(), mcp.Description("TCP port")),
+		mcp.WithBoolean("tls", mcp.Description("Wrap the connection in TLS (certificates are not verified)")),
+		mcp.WithString("sni", mcp.Description("TLS server name (default: host)")),
+		mcp.WithArray("alpn", mcp.Items(map[string]interface{}{"type": "string"}), mcp.Description("TLS ALPN protocols to offer (e.g., ['http/1.1'])")),
+		mcp.WithString("data", mcp.Description("Bytes to send, base64-encoded")),
+		mcp.WithString("text", mcp.Description("Text to send instead of data")),
+		mcp.WithBoolean("half_close", mcp.Description("Close the write side after sending, for servers that wait for EOF")),
+		mcp.WithString("wait", mcp.Description("Idle time that ends reading (e.g., '2s')")),
+		mcp.WithString("timeout", mcp.Description("Limit for the whole exchange (e.g., '30s')")),
+		mcp.WithNumber("max_bytes", mcp.Description("Maximum response bytes to read (default 1048576)")),
+		annotateSendsTraffic,
+	)
+}
+
+func (m *mcpServer) handleRawSend(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if err := m.requireWorkflow(); err != nil {
+		return err, nil
+	}
+
+	in := rawSendInput{
+		Host:      req.GetString("host", ""),
+		Port:      req.GetInt("port", 0),
+		TLS:       req.GetBool("tls", false),
+		SNI:       req.GetString("sni", ""),
+		ALPN:      req.GetStringSlice("alpn", nil),
+		HalfClose: req.GetBool("half_close", false),
+		Wait:      defaultRawWait,
+		MaxBytes:  req.GetInt("max_bytes", defaultRawMaxBytes),
+	}
+	if in.Host == "" {
+		return errorResult("host is required"), nil
+	} else if in.Port < 1 || in.Port > 65535 {
+		return errorResult("port must be between 1 and 65535"), nil
+	} else if in.MaxBytes <= 0 {
+		in.MaxBytes = defaultRawMaxBytes
+	}
+
+	data, text := req.GetString("data", ""), req.GetString("text", "")
+	switch {
+	case (data == "") == (text == ""):
+		return errorResult("exactly one of data or text is required"), nil
+	case data != "":
+		decoded, err := base64.StdEncoding.DecodeString(data)
+		if err != nil {
+			return errorResult("invalid base64 data: " + err.Error()), nil
+		}
+		in.Data = decoded
+	default:
+		in.Data = []byte(text)
+	}
+
+	if s := req.GetString("wait", ""); s != "" {
+		d, err := time.ParseDuration(s)
+		if err != nil || d <= 0 {
+			return errorResult("invalid wait duration: " + s), nil
+		}
+		in.Wait = d
+	}
+	timeout := defaultRawTimeout
+	if s := req.GetString("timeout", ""); s != "" {
+		d, err := time.ParseDuration(s)
+		if err != nil || d <= 0 {
+			return errorResult("invalid timeout duration: " + s), nil
+		}
+		timeout = d
+	}
+
+	addr := net.JoinHostPort(in.Host, strconv.Itoa(in.Port))
+	if err := m.service.guard.CheckTarget(ctx, m.service.projectScope(), addr, "/"); err != nil {
+		return errorResult(err.Error()), nil
+	}
+
+	release, err := m.service.rateLimiter.Wait(ctx, in.Host)
+	if err != nil {
+		return errorResultFromErr("", err), nil
+	}
+	defer release()
+
+	log.Printf("mcp/raw_send: sending %d bytes to %s (tls=%v)", len(in.Data), addr, in.TLS)
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	result, err := sendRaw(ctx, in)
+	if err != nil {
+		return errorResultFromErr("raw send failed: ", err), nil
+	}
+	log.Printf("mcp/raw_send: %s returned %d bytes in %v (closed=%v)", addr, len(result.Data), result.Duration, result.Closed)
+
+	resp := protocol.RawSendResponse{
+		Target:    addr,
+		Sent:      len(in.Data),
+		Received:  len(result.Data),
+		Closed:    result.Closed,
+		Truncated: result.Truncated,
+		ALPN:      result.ALPN,
+		Duration:  result.Duration.String(),
+	}
+	if utf8.Valid(result.Data) {
+		resp.Text = string(result.Data)
+	} else {
+		resp.Data = base64.StdEncoding.EncodeToString(result.Data)
+	}
+	return jsonResult(resp)
+}
@@ -0,0 +1,142 @@
+package service
+
+import (
+	"bufio"
+	"encoding/base64"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/go-harden/llm-security-toolbox/sectool/config"
+	"github.com/go-harden/llm-security-toolbox/sectool/protocol"
+)
+
+// newRawTestServer accepts connections and runs handle on each.
+func newRawTestServer(t *testing.T, handle func(net.Conn)) (string, int) {
+	t.Helper()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = ln.Close() })
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer func() { _ = conn.Close() }()
+				handle(conn)
+			}()
+		}
+	}()
+	addr := ln.Addr().(*net.TCPAddr)
+	return addr.IP.String(), addr.Port
+}
+
+func TestMCP_RawSend(t *testing.T) {
+	t.Parallel()
+
+	srv, mcpClient, _, _, _ := setupMCPServerWithMock(t)
+
+	t.Run("text_idle", func(t *testing.T) {
+		host, port := newRawTestServer(t, func(conn net.Conn) {
+			line, _ := bufio.NewReader(conn).ReadString('\n')
+			_, _ = conn.Write([]byte("+PONG " + line))
+			_, _ = io.Copy(io.Discard, conn) // keep the connection open
+		})
+		resp := CallMCPToolJSONOK[protocol.RawSendResponse](t, mcpClient, "raw_send", map[string]interface{}{
+			"host": host,
+			"port": port,
+			"text": "PING\r\n",
+			"wait": "200ms",
+		})
+		assert.Equal(t, net.JoinHostPort(host, strconv.Itoa(port)), resp.Target)
+		assert.Equal(t, 6, resp.Sent)
+		assert.Equal(t, "+PONG PING\r\n", resp.Text)
+		assert.False(t, resp.Closed)
+	})
+
+	t.Run("binary_half_close", func(t *testing.T) {
+		host, port := newRawTestServer(t, func(conn net.Conn) {
+			data, _ := io.ReadAll(conn) // until the client's half close
+			_, _ = conn.Write(append([]byte{0xff}, data...))
+		})
+		resp := CallMCPToolJSONOK[protocol.RawSendResponse](t, mcpClient, "raw_send", map[string]interface{}{
+			"host":       host,
+			"port":       port,
+			"data":       base64.StdEncoding.EncodeToString([]byte{0, 1, 2}),
+			"half_close": true,
+		})
+		assert.True(t, resp.Closed)
+		assert.Empty(t, resp.Text)
+		assert.Equal(t, base64.StdEncoding.EncodeToString([]byte{0xff, 0, 1, 2}), resp.Data)
+	})
+
+	t.Run("max_bytes", func(t *testing.T) {
+		host, port := newRawTestServer(t, func(conn net.Conn) {
+			_, _ = conn.Write([]byte("0123456789"))
+		})
+		resp := CallMCPToolJSONOK[protocol.RawSendResponse](t, mcpClient, "raw_send", map[string]interface{}{
+			"host":      host,
+			"port":      port,
+			"text":      "x",
+			"max_bytes": 4,
+		})
+		assert.Equal(t, "0123", resp.Text)
+		assert.True(t, resp.Truncated)
+	})
+
+	t.Run("tls", func(t *testing.T) {
+		ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte("raw ok"))
+		}))
+		t.Cleanup(ts.Close)
+		u, _ := url.Parse(ts.URL)
+		port, _ := strconv.Atoi(u.Port())
+		resp := CallMCPToolJSONOK[protocol.RawSendResponse](t, mcpClient, "raw_send", map[string]interface{}{
+			"host": u.Hostname(),
+			"port": port,
+			"tls":  true,
+			"alpn": []interface{}{"http/1.1"},
+			"text": "GET / HTTP/1.1\r\nHost: test\r\nConnection: close\r\n\r\n",
+		})
+		assert.Equal(t, "http/1.1", resp.ALPN)
+		assert.Contains(t, resp.Text, "HTTP/1.1 200 OK\r\n")
+		assert.Contains(t, resp.Text, "raw ok")
+		assert.True(t, resp.Closed)
+	})
+
+	t.Run("validation", func(t *testing.T) {
+		for name, args := range map[string]map[string]interface{}{
+			"no_input":   {"host": "127.0.0.1", "port": 1},
+			"both_input": {"host": "127.0.0.1", "port": 1, "data": "AA==", "text": "x"},
+			"bad_base64": {"host": "127.0.0.1", "port": 1, "data": "%%%"},
+			"bad_port":   {"host": "127.0.0.1", "port": 70000, "text": "x"},
+		} {
+			result := CallMCPTool(t, mcpClient, "raw_send", args)
+			assert.True(t, result.IsError, name)
+		}
+	})
+
+	t.Run("out_of_scope", func(t *testing.T) {
+		srv.scopeMu.Lock()
+		srv.scope = &config.Scope{Include: []string{"*.example.com"}}
+		srv.scopeMu.Unlock()
+		t.Cleanup(func() {
+			srv.scopeMu.Lock()
+			srv.scope = nil
+			srv.scopeMu.Unlock()
+		})
+		result := CallMCPTool(t, mcpClient, "raw_send", map[string]interface{}{"host": "127.0.0.1", "port": 6379, "text": "PING\r\n"})
+		assert.True(t, result.IsError)
+		assert.Contains(t, ExtractMCPText(t, result), "out of scope")
+	})
+}
@@ -0,0 +1,138 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/go-harden/llm-security-toolbox/sectool/protocol"
+)
+
+const adjudicationMaxTokens = 300
+
+const adjudicationSystemPrompt = `You assist an authorized security tester. Judge whether a reflected probe is exploitable as XSS in the browser context shown. Answer with "VERDICT: likely" or "VERDICT: unlikely" on the first line, then one sentence explaining why.`
+
+func (m *mcpServer) reflectionCheckTool() mcp.Tool {
+	return mcp.NewTool("reflection_check",
+		mcp.WithDescription(`Check where a probe string is reflected in a replay response and whether it could break out of its HTML context.
+
+Send a probe like 'sect0x<"'>' with replay_send first: the leading alphanumeric canary locates reflections, and the trailing special characters show what survived encoding.
+Each reflection gets a context (html, attribute, script, comment, non_html) and a verdict (likely, unlikely, ambiguous).
+When evidence is ambiguous and the client supports sampling, the client model is asked to adjudicate with only the relevant snippets; otherwise judge the snippets yourself.`),
+		mcp.WithString("replay_id", mcp.Required(), mcp.Description("replay_id, label, or last/last-N of the replay that carried the probe")),
+		mcp.WithString("probe", mcp.Required(), mcp.Description("Probe string that was injected (alphanumeric canary followed by special characters)")),
+		mcp.WithBoolean("adjudicate", mcp.Description("Ask the client model via sampling when the verdict is ambiguous (default true)")),
+		annotateReadOnly,
+	)
+}
+
+func (m *mcpServer) handleReflectionCheck(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if err := m.requireWorkflow(); err != nil {
+		return err, nil
+	}
+
+	replayID := req.GetString("replay_id", "")
+	if replayID == "" {
+		return errorResult("replay_id is required"), nil
+	}
+	probe := req.GetString("probe", "")
+	if probe == "" {
+		return errorResult("probe is required"), nil
+	}
+	replayID, err := m.service.resolveReplayRef(replayID)
+	if err != nil {
+		return errorResultFromErr("", err), nil
+	}
+
+	entry, ok := m.service.requestStore.Get(replayID)
+	if !ok {
+		return errorResult("replay not found: replay results are ephemeral and cleared on service restart"), nil
+	}
+
+	var contentType string
+	if values := parseHeadersToMap(string(entry.Headers))["Content-Type"]; len(values) > 0 {
+		contentType = strings.ToLower(values[0])
+	}
+	reflections, err := findReflections(string(entry.Body), contentType, probe)
+	if err != nil {
+		return errorResult("invalid probe: " + err.Error()), nil
+	}
+
+	resp := protocol.ReflectionCheckResponse{
+		ReplayID:    replayID,
+		Probe:       probe,
+		Verdict:     overallVerdict(reflections),
+		Reflections: reflections,
+	}
+	log.Printf("mcp/reflection_check: %s has %d reflections (verdict=%s)", replayID, len(reflections), resp.Verdict)
+
+	if resp.Verdict == reflectionAmbiguous && req.GetBool("adjudicate", true) {
+		adjudication, err := m.adjudicateReflections(ctx, probe, contentType, reflections)
+		if err != nil {
+			log.Printf("mcp/reflection_check: adjudication unavailable: %v", err)
+			resp.AdjudicationError = err.Error()
+		} else {
+			resp.Adjudication = adjudication
+		}
+	}
+
+	return jsonResult(resp)
+}
+
+// adjudicateReflections asks the client model, via MCP sampling, to judge the ambiguous
+// reflections. Only the snippets around those reflections are sent.
+func (m *mcpServer) adjudicateReflections(ctx context.Context, probe, contentType string, reflections []protocol.Reflection) (*protocol.Adjudication, error) {
+	srv := server.ServerFromContext(ctx)
+	if srv == nil {
+		return nil, errors.New("no client session")
+	}
+
+	var prompt strings.Builder
+	_, _ = fmt.Fprintf(&prompt, "Probe: %s\nResponse Content-Type: %s\n", probe, contentType)
+	for _, r := range reflections {
+		if r.Verdict != reflectionAmbiguous {
+			continue
+		}
+		_, _ = fmt.Fprintf(&prompt, "\nReflection at offset %d in %s context; unencoded probe characters: %q\n```\n%s\n```\n",
+			r.Offset, r.Context, r.RawChars, r.Snippet)
+	}
+
+	result, err := srv.RequestSampling(ctx, mcp.CreateMessageRequest{
+		CreateMessageParams: mcp.CreateMessageParams{
+			Messages: []mcp.SamplingMessage{{
+				Role:    mcp.RoleUser,
+				Content: mcp.NewTextContent(prompt.String()),
+			}},
+			SystemPrompt: adjudicationSystemPrompt,
+			MaxTokens:    adjudicationMaxTokens,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	text, ok := result.Content.(mcp.TextContent)
+	if !ok {
+		return nil, errors.New("sampling returned non-text content")
+	}
+	return parseAdjudication(text.Text, result.Model)
+}
+
+// parseAdjudication reads the "VERDICT: likely|unlikely" line and the reason that follows it.
+func parseAdjudication(text, model string) (*protocol.Adjudication, error) {
+	first, rest, _ := strings.Cut(strings.TrimSpace(text), "\n")
+	verdict := strings.ToLower(strings.TrimSpace(strings.TrimPrefix(strings.ToUpper(first), "VERDICT:")))
+	if verdict != reflectionLikely && verdict != reflectionUnlikely {
+		return nil, fmt.Errorf("unrecognized sampling answer: %q", first)
+	}
+	return &protocol.Adjudication{
+		Verdict: verdict,
+		Reason:  strings.TrimSpace(rest),
+		Model:   model,
+	}, nil
+}
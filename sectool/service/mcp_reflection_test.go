@@ -0,0 +1,86 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	mcpclient "github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/go-harden/llm-security-toolbox/sectool/protocol"
+	"github.com/go-harden/llm-security-toolbox/sectool/service/store"
+)
+
+type stubSampler struct {
+	prompts []string
+}
+
+func (s *stubSampler) CreateMessage(ctx context.Context, req mcp.CreateMessageRequest) (*mcp.CreateMessageResult, error) {
+	if text, ok := req.Messages[0].Content.(mcp.TextContent); ok {
+		s.prompts = append(s.prompts, text.Text)
+	}
+	return &mcp.CreateMessageResult{
+		SamplingMessage: mcp.SamplingMessage{
+			Role:    mcp.RoleAssistant,
+			Content: mcp.NewTextContent("VERDICT: likely\nThe single quote closes the script string."),
+		},
+		Model: "stub-model",
+	}, nil
+}
+
+func TestMCP_ReflectionCheck(t *testing.T) {
+	t.Parallel()
+
+	srv, mcpClient, _, _, _ := setupMCPServerWithMock(t)
+	srv.requestStore.Store("r-script", &store.RequestEntry{
+		Headers: []byte("HTTP/1.1 200 OK\r\nContent-Type: text/html\r\n\r\n"),
+		Body:    []byte(`<script>var q = 'sect0x<"'>';</script>`),
+	})
+	args := map[string]interface{}{"replay_id": "r-script", "probe": `sect0x<"'>`}
+
+	t.Run("without_sampling", func(t *testing.T) {
+		resp := CallMCPToolJSONOK[protocol.ReflectionCheckResponse](t, mcpClient, "reflection_check", args)
+		assert.Equal(t, reflectionAmbiguous, resp.Verdict)
+		require.Len(t, resp.Reflections, 1)
+		assert.Equal(t, "script", resp.Reflections[0].Context)
+		assert.Nil(t, resp.Adjudication)
+		assert.NotEmpty(t, resp.AdjudicationError)
+	})
+
+	t.Run("with_sampling", func(t *testing.T) {
+		sampler := &stubSampler{}
+		client, err := mcpclient.NewInProcessClientWithSamplingHandler(srv.mcpServer.server, sampler)
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = client.Close() })
+
+		ctx, cancel := context.WithTimeout(t.Context(), 10*time.Second)
+		t.Cleanup(cancel)
+		require.NoError(t, client.Start(ctx))
+		_, err = client.Initialize(ctx, mcp.InitializeRequest{
+			Params: mcp.InitializeParams{
+				ClientInfo:      mcp.Implementation{Name: "sectool-test", Version: "1.0.0"},
+				ProtocolVersion: mcp.LATEST_PROTOCOL_VERSION,
+				Capabilities:    mcp.ClientCapabilities{Sampling: &struct{}{}},
+			},
+		})
+		require.NoError(t, err)
+
+		resp := CallMCPToolJSONOK[protocol.ReflectionCheckResponse](t, client, "reflection_check", args)
+		require.NotNil(t, resp.Adjudication, resp.AdjudicationError)
+		assert.Equal(t, reflectionLikely, resp.Adjudication.Verdict)
+		assert.Equal(t, "stub-model", resp.Adjudication.Model)
+		require.Len(t, sampler.prompts, 1)
+		assert.Contains(t, sampler.prompts[0], "script context")
+	})
+
+	t.Run("invalid_probe", func(t *testing.T) {
+		result := CallMCPTool(t, mcpClient, "reflection_check", map[string]interface{}{
+			"replay_id": "r-script",
+			"probe":     "<x>",
+		})
+		assert.True(t, result.IsError)
+	})
+}
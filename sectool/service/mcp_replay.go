@@ -3,7 +3,12 @@ package service
 import (
 	"context"
 	"encoding/base64"
+	"errors"
+	"fmt"
 	"log"
+	"net/http"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/mark3labs/mcp-go/mcp"
@@ -16,7 +21,8 @@ import (
 
 func (m *mcpServer) replaySendTool() mcp.Tool {
 	return mcp.NewTool("replay_send",
-		mcp.WithDescription(`Replay a proxied request (flow_id from proxy_poll) with edits.
+		mcp.WithDescription(`Replay a proxied request (flow_id from proxy_poll) or a previous replay (replay_id) with edits.
+Chain replays by passing a replay_id: the base is the exact request that replay sent, after its edits.
 
 Returns: replay_id, status, headers, response_preview. Full body via replay_get.
 
@@ -28,13 +34,22 @@ Edits:
 - add_headers/remove_headers: header edits
 - body: replace entire body
 - set_json/remove_json: selective JSON edits; requires body to be valid JSON
+- set_form/remove_form: selective form field edits; requires a form-urlencoded or multipart/form-data body
+- set_xml/remove_xml: XPath edits; xml_doctype: DOCTYPE/entity declarations for XXE; requires body to be valid XML
 
 JSON paths: dot notation with array brackets (e.g., "user.email", "items[0].id", "data.users[0].name").
 set_json object: {"user.email": "x", "items[0].id": 5}
 Types auto-parsed: null/true/false/numbers/{}/[], else string.
+set_form object: {"email": "x", "role": "admin"}; values are sent as text. Setting a field replaces every occurrence in place (multipart parts keep their filename and Content-Type), else appends it.
+A multipart boundary found in a new value is replaced with a fresh one in the body and Content-Type.
+set_xml object: {"//u:role": "admin", "//user/@id": "1"}; sets element text or attribute/text values of every match, or creates a missing last element/@attribute step. Entity references like &xxe; are kept unescaped.
+xml_doctype: '<!ENTITY xxe SYSTEM "file:///etc/passwd">' is wrapped in a DOCTYPE named after the root element; a full '<!DOCTYPE ...>' is used as is. Either replaces any existing DOCTYPE.
 Processing: remove_* then set_*. Content-Length/Host auto-updated.
+After the edits, the host's cookie_jar_set jar (if enabled) adds its cookies and stores the response's Set-Cookie; jar_sent/jar_stored name them.
 Validation: fix issues or use force=true for protocol testing.`),
-		mcp.WithString("flow_id", mcp.Required(), mcp.Description("Flow ID from proxy_poll or crawl_poll to use as base request")),
+		mcp.WithString("flow_id", mcp.Description("Flow ID from proxy_poll or crawl_poll, or an operation flow_id/operationId from spec_import, to use as base request, or "+recentRefUsage+" proxy entry (exclusive with replay_id)")),
+		mcp.WithString("replay_id", mcp.Description("Replay ID, label, or "+recentRefUsage+" replay to use as base request; its target is kept unless overridden (exclusive with flow_id)")),
+		mcp.WithString("label", mcp.Description("Optional label; later replay_get calls accept it in place of replay_id")),
 		mcp.WithString("method", mcp.Description("Override HTTP method (GET, POST, PUT, DELETE, PATCH, etc.)")),
 		mcp.WithString("body", mcp.Description("Request body content (replaces existing body)")),
 		mcp.WithString("target", mcp.Description("Override destination (scheme+host[:port]); keeps original path/query")),
@@ -46,19 +61,49 @@ Validation: fix issues or use force=true for protocol testing.`),
 		mcp.WithArray("remove_query", mcp.Items(map[string]interface{}{"type": "string"}), mcp.Description("Query param names to remove")),
 		mcp.WithObject("set_json", mcp.Description("JSON fields to set as object: {\"path\": value} (e.g., {\"user.email\": \"x\", \"items[0].id\": 5})")),
 		mcp.WithArray("remove_json", mcp.Items(map[string]interface{}{"type": "string"}), mcp.Description("JSON fields to remove (dot path: 'user.temp', 'items[2]')")),
+		mcp.WithObject("set_form", mcp.Description("Form fields to set as object: {\"name\": value} (form-urlencoded or multipart body)")),
+		mcp.WithArray("remove_form", mcp.Items(map[string]interface{}{"type": "string"}), mcp.Description("Form field names to remove, every occurrence")),
+		mcp.WithObject("set_xml", mcp.Description("XML nodes to set as object: {\"xpath\": value} (e.g., {\"//u:role\": \"admin\", \"//user/@id\": \"1\"})")),
+		mcp.WithArray("remove_xml", mcp.Items(map[string]interface{}{"type": "string"}), mcp.Description("XPath of XML elements or attributes to remove (e.g., '//soap:Header', '//user/@id')")),
+		mcp.WithString("xml_doctype", mcp.Description("DOCTYPE to put before the root element: entity declarations or a full '<!DOCTYPE ...>'")),
 		mcp.WithBoolean("follow_redirects", mcp.Description("Follow HTTP redirects (default: false)")),
+		mcp.WithBoolean("keep_encoding", mcp.Description("Keep the response chunked/compressed as received instead of decoding gzip/deflate/br (default: false)")),
 		mcp.WithString("timeout", mcp.Description("Request timeout (e.g., '30s', '1m')")),
 		mcp.WithBoolean("force", mcp.Description("Skip validation for protocol-level tests (smuggling, CRLF injection)")),
+		mcp.WithString("auth_profile", mcp.Description("Auth profile from config auth_profiles: answers NTLM/Negotiate/Digest challenges or SigV4-signs the edited request; NTLM/Negotiate sends bypass proxy history")),
+		mcp.WithString("env", mcp.Description(envParamDescription)),
+		mcp.WithString("replay_as", mcp.Description("Identity from identity_set whose headers and cookies replace the request's credentials after the edits")),
+		mcp.WithString("session", mcp.Description("Login session from session_set: sets its token header after the edits, and on a refresh_on status logs in again and resends once")),
+		mcp.WithBoolean("refresh_csrf", mcp.Description("Fetch a fresh anti-CSRF token with the target host's csrf_set rule and inject it after the edits (default: false)")),
+		annotateSendsTraffic,
 	)
 }
 
 func (m *mcpServer) replayGetTool() mcp.Tool {
-	return mcp.NewTool("replay_get",
+	opts := []mcp.ToolOption{
 		mcp.WithDescription(`Retrieve full response from a previous replay_send.
 
 Returns headers and body. Binary bodies are returned as "<BINARY:N Bytes>" placeholder.
+Large bodies: pass offset/length (bytes) or start_line/end_line to get one window of the body as response_body
+(text, or base64 for binary windows; see body_range.encoding), then continue from body_range.next_offset or next_line.
+render returns the body decoded (chunked, gzip, deflate, br) and formatted instead: json (pretty-printed), text (visible text of HTML),
+hex (dump of the first 8 KiB), raw (decoded text), or auto to choose by Content-Type; windows then apply to the rendered text.
 Results are ephemeral and cleared on service restart.`),
-		mcp.WithString("replay_id", mcp.Required(), mcp.Description("Replay ID from replay_send response")),
+		mcp.WithString("replay_id", mcp.Required(), mcp.Description("Replay ID from replay_send response, a replay label, or "+recentRefUsage)),
+		mcp.WithString("render", mcp.Description("Decode and format the body: auto, json, text, hex or raw")),
+	}
+	opts = append(opts, bodyRangeOptions()...)
+	return mcp.NewTool("replay_get", append(opts, annotateReadOnly)...)
+}
+
+func (m *mcpServer) replayHistoryTool() mcp.Tool {
+	return mcp.NewTool("replay_history",
+		mcp.WithDescription(`List previous replay_send/request_send results, newest first.
+
+Returns: replay_id, label, method, url, status, response_size, duration, created_at.
+Results are ephemeral and cleared on service restart.`),
+		mcp.WithNumber("limit", mcp.Description("Maximum number of replays to return")),
+		annotateReadOnly,
 	)
 }
 
@@ -73,34 +118,63 @@ Returns: replay_id, status, headers, response_preview. Full body via replay_get.
 		mcp.WithObject("headers", mcp.Description("Headers as object: {\"Name\": \"Value\"}")),
 		mcp.WithString("body", mcp.Description("Request body content")),
 		mcp.WithBoolean("follow_redirects", mcp.Description("Follow HTTP redirects (default: false)")),
+		mcp.WithBoolean("keep_encoding", mcp.Description("Keep the response chunked/compressed as received instead of decoding gzip/deflate/br (default: false)")),
+		mcp.WithString("timeout", mcp.Description("Request timeout (e.g., '30s', '1m')")),
+		mcp.WithString("label", mcp.Description("Optional label; later replay_get calls accept it in place of replay_id")),
+		mcp.WithString("auth_profile", mcp.Description("Auth profile from config auth_profiles: answers NTLM/Negotiate/Digest challenges or SigV4-signs the edited request; NTLM/Negotiate sends bypass proxy history")),
+		mcp.WithString("env", mcp.Description(envParamDescription)),
+		annotateSendsTraffic,
+	)
+}
+func (m *mcpServer) requestCraftTool() mcp.Tool {
+	return mcp.NewTool("request_craft",
+		mcp.WithDescription(`Craft and send a request directly, either as a raw HTTP/1.1 request or from method/url/headers/body fields.
+
+Raw mode sends your exact request line and headers. Bare LF line endings in the header block are converted to CRLF and Content-Length is set from the body, unless force=true, which sends the bytes unchanged (smuggling, malformed requests).
+Destination: target if given, else the absolute URL in the request line or the Host header (HTTPS unless the Host port is 80).
+Returns: replay_id, status, headers, response_preview. The result is stored like any replay: replay_get, replay_history, and rerun via replay_send.`),
+		mcp.WithString("raw", mcp.Description("Raw HTTP request: request line, headers, blank line, optional body (exclusive with url)")),
+		mcp.WithString("target", mcp.Description("Destination for raw mode (scheme+host[:port]); default from the request line or Host header")),
+		mcp.WithString("url", mcp.Description("Target URL for field mode (exclusive with raw)")),
+		mcp.WithString("method", mcp.Description("HTTP method for field mode (default: GET)")),
+		mcp.WithObject("headers", mcp.Description("Headers for field mode as object: {\"Name\": \"Value\"}")),
+		mcp.WithString("body", mcp.Description("Request body for field mode")),
+		mcp.WithBoolean("follow_redirects", mcp.Description("Follow HTTP redirects (default: false)")),
+		mcp.WithBoolean("keep_encoding", mcp.Description("Keep the response chunked/compressed as received instead of decoding gzip/deflate/br (default: false)")),
 		mcp.WithString("timeout", mcp.Description("Request timeout (e.g., '30s', '1m')")),
+		mcp.WithBoolean("force", mcp.Description("Send raw bytes unchanged and skip validation")),
+		mcp.WithString("label", mcp.Description("Optional label; later replay_get calls accept it in place of replay_id")),
+		mcp.WithString("auth_profile", mcp.Description("Auth profile from config auth_profiles: answers NTLM/Negotiate/Digest challenges or SigV4-signs the request; NTLM/Negotiate sends bypass proxy history")),
+		mcp.WithString("env", mcp.Description(envParamDescription)),
+		annotateSendsTraffic,
 	)
 }
+
 func (m *mcpServer) handleReplaySend(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	if err := m.requireWorkflow(); err != nil {
 		return err, nil
 	}
 
+	envVars, err := m.applyEnv(req)
+	if err != nil {
+		return errorResultFromErr("", err), nil
+	}
+
+	baseReplayID := req.GetString("replay_id", "")
 	flowID := req.GetString("flow_id", "")
-	if flowID == "" {
-		return errorResult("flow_id is required"), nil
+	if flowID == "" && baseReplayID == "" {
+		return errorResult("flow_id or replay_id is required"), nil
+	} else if flowID != "" && baseReplayID != "" {
+		return errorResult("only one of flow_id or replay_id can be set"), nil
 	}
+	targetOverride := req.GetString("target", "")
 
-	// Try proxy flowStore first, then crawler backend
-	var rawRequest []byte
-	if entry, ok := m.service.flowStore.Lookup(flowID); ok {
-		proxyEntries, err := m.service.httpBackend.GetProxyHistory(ctx, 1, entry.Offset)
-		if err != nil {
-			return errorResultFromErr("failed to fetch flow: ", err), nil
-		}
-		if len(proxyEntries) == 0 {
-			return errorResult("flow not found in proxy history"), nil
-		}
-		rawRequest = []byte(proxyEntries[0].Request)
-	} else if flow, err := m.service.crawlerBackend.GetFlow(ctx, flowID); err == nil && flow != nil {
-		rawRequest = flow.Request
-	} else {
-		return errorResult("flow_id not found: run proxy_poll or crawl_poll to see available flows"), nil
+	rawRequest, baseTarget, flowID, err := m.service.loadBaseRequest(ctx, flowID, baseReplayID)
+	if err != nil {
+		return errorResultFromErr("", err), nil
+	}
+	if targetOverride == "" {
+		targetOverride = baseTarget
 	}
 
 	rawRequest = modifyRequestLine(rawRequest, &PathQueryOpts{
@@ -112,11 +186,17 @@ func (m *mcpServer) handleReplaySend(ctx context.Context, req mcp.CallToolReques
 	})
 
 	headers, reqBody := splitHeadersBody(rawRequest)
+	if envVars != nil {
+		// The base may hold placeholders too, e.g. a request crafted before the environment existed
+		expandedHeaders, _ := expandTemplateVars(string(headers), envVars)
+		expandedBody, _ := expandTemplateVars(string(reqBody), envVars)
+		headers, reqBody = []byte(expandedHeaders), []byte(expandedBody)
+	}
 
 	sendReq := &ReplaySendRequest{
 		AddHeaders:    req.GetStringSlice("add_headers", nil),
 		RemoveHeaders: req.GetStringSlice("remove_headers", nil),
-		Target:        req.GetString("target", ""),
+		Target:        targetOverride,
 	}
 	headers = applyHeaderModifications(headers, sendReq)
 	headers = setHeaderIfMissing(headers, "User-Agent", config.UserAgent())
@@ -143,6 +223,30 @@ func (m *mcpServer) handleReplaySend(ctx context.Context, req mcp.CallToolReques
 		reqBody = modifiedBody
 	}
 
+	setForm := formFieldMap(req.GetArguments()["set_form"])
+	removeForm := req.GetStringSlice("remove_form", nil)
+	if len(setForm) > 0 || len(removeForm) > 0 {
+		contentType := http.Header(parseHeadersToMap(string(headers))).Get("Content-Type")
+		newContentType, modifiedBody, err := modifyFormBody(contentType, reqBody, setForm, removeForm)
+		if err != nil {
+			return errorResult("form body modification failed: " + err.Error()), nil
+		}
+		if newContentType != contentType {
+			headers = setHeader(headers, "Content-Type", newContentType)
+		}
+		reqBody = modifiedBody
+	}
+
+	setXML := formFieldMap(req.GetArguments()["set_xml"])
+	removeXML := req.GetStringSlice("remove_xml", nil)
+	if doctype := req.GetString("xml_doctype", ""); len(setXML) > 0 || len(removeXML) > 0 || doctype != "" {
+		modifiedBody, err := modifyXMLBody(reqBody, setXML, removeXML, doctype)
+		if err != nil {
+			return errorResult("XML body modification failed: " + err.Error()), nil
+		}
+		reqBody = modifiedBody
+	}
+
 	headers = updateContentLength(headers, len(reqBody))
 	rawRequest = append(headers, reqBody...)
 
@@ -152,7 +256,54 @@ func (m *mcpServer) handleReplaySend(ctx context.Context, req mcp.CallToolReques
 		}
 	}
 
-	host, port, usesHTTPS := parseTarget(rawRequest, req.GetString("target", ""))
+	auth, err := m.service.resolveAuthProfile(req.GetString("auth_profile", ""))
+	if err != nil {
+		return errorResultFromErr("", err), nil
+	}
+
+	// Identities are complete credential sets, so their sends neither use nor feed the cookie jar
+	useJar := req.GetString("replay_as", "") == ""
+	jars := m.client(ctx).cookieJars
+	var jarSent []string
+	if useJar {
+		jarHost, _, jarHTTPS := parseTarget(rawRequest, targetOverride)
+		rawRequest, jarSent = jars.Attach(rawRequest, jarHost, jarHTTPS)
+	}
+
+	if name := req.GetString("replay_as", ""); name != "" {
+		id, err := m.service.identities.Get(name)
+		if err != nil {
+			return errorResultFromErr("", err), nil
+		}
+		rawRequest = id.Apply(rawRequest)
+	}
+
+	var sess *loginSession
+	var sessToken string
+	if name := req.GetString("session", ""); name != "" {
+		if sess, err = m.service.sessions.Get(name); err != nil {
+			return errorResultFromErr("", err), nil
+		} else if sessToken, err = sess.Token(ctx, m.service); err != nil {
+			return errorResultFromErr("", err), nil
+		}
+		rawRequest = sess.Apply(rawRequest, sessToken)
+	}
+
+	host, port, usesHTTPS := parseTarget(rawRequest, targetOverride)
+
+	var csrfToken string
+	if req.GetBool("refresh_csrf", false) {
+		rule, err := m.service.csrfRules.Get(host)
+		if err != nil {
+			return errorResultFromErr("", err), nil
+		}
+		token, cookies, err := rule.Fetch(ctx, m.service, rawRequest)
+		if err != nil {
+			return errorResultFromErr("", err), nil
+		}
+		rawRequest = rule.Apply(rawRequest, token, cookies)
+		csrfToken = token
+	}
 
 	replayID := ids.Generate(ids.DefaultLength)
 
@@ -160,7 +311,7 @@ func (m *mcpServer) handleReplaySend(ctx context.Context, req mcp.CallToolReques
 	if usesHTTPS {
 		scheme = schemeHTTPS
 	}
-	log.Printf("mcp/replay_send: %s sending to %s://%s:%d (flow=%s)", replayID, scheme, host, port, flowID)
+	log.Printf("mcp/replay_send: %s sending to %s://%s:%d (base=%s)", replayID, scheme, host, port, flowID)
 
 	var timeout time.Duration
 	if timeoutStr := req.GetString("timeout", ""); timeoutStr != "" {
@@ -179,24 +330,51 @@ func (m *mcpServer) handleReplaySend(ctx context.Context, req mcp.CallToolReques
 			UsesHTTPS: usesHTTPS,
 		},
 		FollowRedirects: req.GetBool("follow_redirects", false),
+		KeepEncoding:    req.GetBool("keep_encoding", false),
 		Timeout:         timeout,
 	}
 
-	result, err := m.service.httpBackend.SendRequest(ctx, "sectool-"+replayID, sendInput)
+	result, err := m.service.sendRequest(ctx, "sectool-"+replayID, sendInput, auth)
 	if err != nil {
 		return errorResultFromErr("request failed: ", err), nil
 	}
 
+	respCode, respStatusLine := parseResponseStatus(result.Headers)
+	var refreshed bool
+	if sess != nil && sess.ShouldRefresh(respCode) {
+		log.Printf("mcp/replay_send: %s got status %d, refreshing session %s", replayID, respCode, sess.Name)
+		token, err := sess.Refresh(ctx, m.service, sessToken)
+		if err != nil {
+			return errorResultFromErr(fmt.Sprintf("request got status %d and ", respCode), err), nil
+		}
+		rawRequest = sess.Apply(rawRequest, token)
+		sendInput.RawRequest = rawRequest
+		if result, err = m.service.sendRequest(ctx, "sectool-"+replayID, sendInput, auth); err != nil {
+			return errorResultFromErr("request failed after session refresh: ", err), nil
+		}
+		respCode, respStatusLine = parseResponseStatus(result.Headers)
+		refreshed = true
+	}
+
 	respHeaders := result.Headers
 	respBody := result.Body
-	respCode, respStatusLine := parseResponseStatus(respHeaders)
 	log.Printf("mcp/replay_send: %s completed in %v (status=%d, size=%d)", replayID, result.Duration, respCode, len(respBody))
 
+	var jarStored []string
+	if useJar {
+		jarStored = jars.Capture(rawRequest, host, respHeaders)
+	}
+
 	m.service.requestStore.Store(replayID, &store.RequestEntry{
+		Label:    req.GetString("label", ""),
+		Base:     flowID,
+		Request:  rawRequest,
+		Target:   sendInput.Target.origin(),
 		Headers:  respHeaders,
 		Body:     respBody,
 		Duration: result.Duration,
 	})
+	m.service.correlateOast(ctx, replayID, rawRequest)
 
 	return jsonResult(protocol.ReplaySendResponse{
 		ReplayID: replayID,
@@ -207,7 +385,12 @@ func (m *mcpServer) handleReplaySend(ctx context.Context, req mcp.CallToolReques
 			RespHeaders: string(respHeaders),
 			RespSize:    len(respBody),
 			RespPreview: previewBody(respBody, responsePreviewSize),
+			Decoded:     result.Decoded,
 		},
+		SessionRefreshed: refreshed,
+		CSRFToken:        csrfToken,
+		JarSent:          jarSent,
+		JarStored:        jarStored,
 	})
 }
 
@@ -220,9 +403,21 @@ func (m *mcpServer) handleReplayGet(ctx context.Context, req mcp.CallToolRequest
 	if replayID == "" {
 		return errorResult("replay_id is required"), nil
 	}
+	replayID, err := m.service.resolveReplayRef(replayID)
+	if err != nil {
+		return errorResultFromErr("", err), nil
+	}
 
 	// Hidden parameter for CLI: returns full base64-encoded body instead of preview
 	fullBody := req.GetBool("full_body", false)
+	window, hasWindow, err := parseBodyRange(req)
+	if err != nil {
+		return errorResultFromErr("", err), nil
+	}
+	render := strings.ToLower(req.GetString("render", ""))
+	if render != "" && !validRenderModes[render] {
+		return errorResult("invalid render: " + render + " (use auto, json, text, hex, raw)"), nil
+	}
 
 	log.Printf("mcp/replay_get: retrieving %s", replayID)
 	result, ok := m.service.requestStore.Get(replayID)
@@ -232,11 +427,26 @@ func (m *mcpServer) handleReplayGet(ctx context.Context, req mcp.CallToolRequest
 
 	respCode, respStatusLine := parseResponseStatus(result.Headers)
 
-	// Format body based on full_body flag
-	var respBodyStr string
-	if fullBody {
+	// Format body based on the requested rendering, window or full_body flag;
+	// a window applies to the rendered text
+	var respBodyStr, rendered string
+	var bodyRange *protocol.BodyRange
+	body := result.Body
+	if render != "" {
+		if respBodyStr, rendered, err = renderBody(result.Headers, result.Body, render); err != nil {
+			return errorResultFromErr("", err), nil
+		}
+		body = []byte(respBodyStr)
+	}
+	switch {
+	case hasWindow:
+		if respBodyStr, bodyRange, err = window.slice(body); err != nil {
+			return errorResultFromErr("", err), nil
+		}
+	case render != "":
+	case fullBody:
 		respBodyStr = base64.StdEncoding.EncodeToString(result.Body)
-	} else {
+	default:
 		respBodyStr = previewBody(result.Body, fullBodyMaxSize)
 	}
 
@@ -249,14 +459,67 @@ func (m *mcpServer) handleReplayGet(ctx context.Context, req mcp.CallToolRequest
 		RespHeadersParsed: parseHeadersToMap(string(result.Headers)),
 		RespBody:          respBodyStr,
 		RespSize:          len(result.Body),
+		BodyRange:         bodyRange,
+		Rendered:          rendered,
 	})
 }
 
+func (m *mcpServer) handleReplayHistory(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if err := m.requireWorkflow(); err != nil {
+		return err, nil
+	}
+
+	replayIDs := m.service.requestStore.RecentIDs(req.GetInt("limit", 0))
+	replays := make([]protocol.ReplayHistoryEntry, 0, len(replayIDs))
+	for _, id := range replayIDs {
+		entry, ok := m.service.requestStore.Get(id)
+		if !ok {
+			continue // expired since listing
+		}
+		method, reqURL := replayURL(entry)
+		status, _ := parseResponseStatus(entry.Headers)
+
+		replays = append(replays, protocol.ReplayHistoryEntry{
+			ReplayID:  id,
+			Label:     entry.Label,
+			Base:      entry.Base,
+			Method:    method,
+			URL:       reqURL,
+			Status:    status,
+			RespSize:  len(entry.Body),
+			Duration:  entry.Duration.String(),
+			CreatedAt: entry.CreatedAt.UTC().Format(time.RFC3339),
+			Tags:      m.service.flowStore.Tags(id),
+		})
+	}
+
+	log.Printf("mcp/replay_history: returning %d replays", len(replays))
+	return jsonResult(protocol.ReplayHistoryResponse{Replays: replays})
+}
+
+// replayURL returns the method and full URL of a stored replay's request.
+func replayURL(entry *store.RequestEntry) (string, string) {
+	firstLine, _, _ := strings.Cut(string(entry.Request), "\r\n")
+	method, path, query, _ := parseRequestLine(firstLine)
+	reqURL := path
+	if !strings.HasPrefix(path, "http://") && !strings.HasPrefix(path, "https://") {
+		reqURL = entry.Target + path
+	}
+	if query != "" {
+		reqURL += "?" + query
+	}
+	return method, reqURL
+}
+
 func (m *mcpServer) handleRequestSend(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	if err := m.requireWorkflow(); err != nil {
 		return err, nil
 	}
 
+	if _, err := m.applyEnv(req); err != nil {
+		return errorResultFromErr("", err), nil
+	}
+
 	urlStr := req.GetString("url", "")
 	if urlStr == "" {
 		return errorResult("url is required"), nil
@@ -264,20 +527,7 @@ func (m *mcpServer) handleRequestSend(ctx context.Context, req mcp.CallToolReque
 
 	method := req.GetString("method", "GET")
 
-	// Parse headers from object
-	var headers map[string]string
-	if args := req.GetArguments(); args != nil {
-		if headersRaw, ok := args["headers"]; ok && headersRaw != nil {
-			if headersMap, ok := headersRaw.(map[string]interface{}); ok {
-				headers = make(map[string]string)
-				for k, v := range headersMap {
-					if vs, ok := v.(string); ok {
-						headers[k] = vs
-					}
-				}
-			}
-		}
-	}
+	headers := stringMapArg(req, "headers")
 
 	body := []byte(req.GetString("body", ""))
 
@@ -291,6 +541,10 @@ func (m *mcpServer) handleRequestSend(ctx context.Context, req mcp.CallToolReque
 		return errorResult("failed to build request: invalid method or URL"), nil
 	}
 	target := targetFromURL(parsedURL)
+	auth, err := m.service.resolveAuthProfile(req.GetString("auth_profile", ""))
+	if err != nil {
+		return errorResultFromErr("", err), nil
+	}
 	replayID := ids.Generate(ids.DefaultLength)
 
 	log.Printf("mcp/request_send: %s sending to %s", replayID, parsedURL)
@@ -308,10 +562,11 @@ func (m *mcpServer) handleRequestSend(ctx context.Context, req mcp.CallToolReque
 		RawRequest:      rawRequest,
 		Target:          target,
 		FollowRedirects: req.GetBool("follow_redirects", false),
+		KeepEncoding:    req.GetBool("keep_encoding", false),
 		Timeout:         timeout,
 	}
 
-	result, err := m.service.httpBackend.SendRequest(ctx, "sectool-"+replayID, sendInput)
+	result, err := m.service.sendRequest(ctx, "sectool-"+replayID, sendInput, auth)
 	if err != nil {
 		return errorResultFromErr("request failed: ", err), nil
 	}
@@ -320,10 +575,14 @@ func (m *mcpServer) handleRequestSend(ctx context.Context, req mcp.CallToolReque
 	log.Printf("mcp/request_send: %s completed in %v (status=%d, size=%d)", replayID, result.Duration, respCode, len(result.Body))
 
 	m.service.requestStore.Store(replayID, &store.RequestEntry{
+		Label:    req.GetString("label", ""),
+		Request:  rawRequest,
+		Target:   target.origin(),
 		Headers:  result.Headers,
 		Body:     result.Body,
 		Duration: result.Duration,
 	})
+	m.service.correlateOast(ctx, replayID, rawRequest)
 
 	return jsonResult(protocol.ReplaySendResponse{
 		ReplayID: replayID,
@@ -334,6 +593,182 @@ func (m *mcpServer) handleRequestSend(ctx context.Context, req mcp.CallToolReque
 			RespHeaders: string(result.Headers),
 			RespSize:    len(result.Body),
 			RespPreview: previewBody(result.Body, responsePreviewSize),
+			Decoded:     result.Decoded,
 		},
 	})
 }
+
+func (m *mcpServer) handleRequestCraft(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if err := m.requireWorkflow(); err != nil {
+		return err, nil
+	}
+
+	if _, err := m.applyEnv(req); err != nil {
+		return errorResultFromErr("", err), nil
+	}
+
+	raw := req.GetString("raw", "")
+	urlStr := req.GetString("url", "")
+	if (raw == "") == (urlStr == "") {
+		return errorResult("exactly one of raw or url is required"), nil
+	}
+	force := req.GetBool("force", false)
+
+	var rawRequest []byte
+	var target Target
+	if raw != "" {
+		rawRequest = []byte(raw)
+		if !force {
+			rawRequest = normalizeRawRequest(rawRequest)
+		}
+		host, port, usesHTTPS := parseTarget(rawRequest, req.GetString("target", ""))
+		if host == "" {
+			return errorResult("no destination: set target or include a Host header"), nil
+		}
+		target = Target{Hostname: host, Port: port, UsesHTTPS: usesHTTPS}
+	} else {
+		parsedURL, err := parseURLWithDefaultHTTPS(urlStr)
+		if err != nil {
+			return errorResult("invalid URL: " + err.Error()), nil
+		}
+		rawRequest = buildRawRequest(req.GetString("method", "GET"), parsedURL, stringMapArg(req, "headers"), []byte(req.GetString("body", "")))
+		if rawRequest == nil {
+			return errorResult("failed to build request: invalid method or URL"), nil
+		}
+		target = targetFromURL(parsedURL)
+	}
+
+	if !force {
+		if issues := validateRequest(rawRequest); len(issues) > 0 {
+			return errorResult("validation failed:\n" + formatIssues(issues)), nil
+		}
+	}
+
+	var timeout time.Duration
+	if timeoutStr := req.GetString("timeout", ""); timeoutStr != "" {
+		parsed, err := time.ParseDuration(timeoutStr)
+		if err != nil {
+			return errorResult("invalid timeout duration: " + err.Error()), nil
+		}
+		timeout = parsed
+	}
+	auth, err := m.service.resolveAuthProfile(req.GetString("auth_profile", ""))
+	if err != nil {
+		return errorResultFromErr("", err), nil
+	}
+
+	replayID := ids.Generate(ids.DefaultLength)
+	log.Printf("mcp/request_craft: %s sending to %s (raw=%v, force=%v)", replayID, target.origin(), raw != "", force)
+
+	sendInput := SendRequestInput{
+		RawRequest:      rawRequest,
+		Target:          target,
+		FollowRedirects: req.GetBool("follow_redirects", false),
+		KeepEncoding:    req.GetBool("keep_encoding", false),
+		Timeout:         timeout,
+	}
+	result, err := m.service.sendRequest(ctx, "sectool-"+replayID, sendInput, auth)
+	if err != nil {
+		return errorResultFromErr("request failed: ", err), nil
+	}
+
+	respCode, respStatusLine := parseResponseStatus(result.Headers)
+	log.Printf("mcp/request_craft: %s completed in %v (status=%d, size=%d)", replayID, result.Duration, respCode, len(result.Body))
+
+	m.service.requestStore.Store(replayID, &store.RequestEntry{
+		Label:    req.GetString("label", ""),
+		Request:  rawRequest,
+		Target:   target.origin(),
+		Headers:  result.Headers,
+		Body:     result.Body,
+		Duration: result.Duration,
+	})
+	m.service.correlateOast(ctx, replayID, rawRequest)
+
+	return jsonResult(protocol.ReplaySendResponse{
+		ReplayID: replayID,
+		Duration: result.Duration.String(),
+		ResponseDetails: protocol.ResponseDetails{
+			Status:      respCode,
+			StatusLine:  respStatusLine,
+			RespHeaders: string(result.Headers),
+			RespSize:    len(result.Body),
+			RespPreview: previewBody(result.Body, responsePreviewSize),
+			Decoded:     result.Decoded,
+		},
+	})
+}
+
+// stringMapArg returns an object argument's string values, or nil when absent.
+func stringMapArg(req mcp.CallToolRequest, name string) map[string]string {
+	obj, ok := req.GetArguments()[name].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	out := make(map[string]string, len(obj))
+	for k, v := range obj {
+		if vs, ok := v.(string); ok {
+			out[k] = vs
+		}
+	}
+	return out
+}
+
+// normalizeRawRequest converts bare LF line endings in the header block to CRLF,
+// adding the blank line that ends the headers if missing, and sets Content-Length
+// when the request has a body.
+func normalizeRawRequest(raw []byte) []byte {
+	text := string(raw)
+	var head, body string
+	if h, b, ok := strings.Cut(text, "\r\n\r\n"); ok {
+		head, body = h, b
+	} else if h, b, ok := strings.Cut(text, "\n\n"); ok {
+		head, body = h, b
+	} else {
+		head = strings.TrimRight(text, "\r\n")
+	}
+	head = strings.ReplaceAll(strings.ReplaceAll(head, "\r\n", "\n"), "\n", "\r\n")
+
+	headers := []byte(head + "\r\n\r\n")
+	if body != "" || len(removeHeader(headers, "Content-Length")) != len(headers) {
+		headers = updateContentLength(headers, len(body))
+	}
+	return append(headers, body...)
+}
+
+// loadBaseRequest returns a copy of the request behind a flow_id (proxy, crawler or
+// spec template) or a replay_id, the target to send it to when the request alone
+// does not determine it, and the resolved ID.
+func (s *Server) loadBaseRequest(ctx context.Context, flowID, replayID string) ([]byte, string, string, error) {
+	if replayID != "" {
+		replayID, err := s.resolveReplayRef(replayID)
+		if err != nil {
+			return nil, "", "", err
+		}
+		entry, ok := s.requestStore.Get(replayID)
+		if !ok || len(entry.Request) == 0 {
+			return nil, "", "", errors.New("replay not found: replay results are ephemeral and cleared on service restart")
+		}
+		return slices.Clone(entry.Request), entry.Target, replayID, nil
+	}
+
+	flowID, err := s.resolveFlowRef(ctx, flowID)
+	if err != nil {
+		return nil, "", "", err
+	}
+	// Try proxy flowStore first, then crawler backend
+	if entry, ok := s.flowStore.Lookup(flowID); ok {
+		proxyEntries, err := s.httpBackend.GetProxyHistory(ctx, 1, entry.Offset)
+		if err != nil {
+			return nil, "", "", fmt.Errorf("failed to fetch flow: %w", err)
+		} else if len(proxyEntries) == 0 {
+			return nil, "", "", errors.New("flow not found in proxy history")
+		}
+		return []byte(proxyEntries[0].Request), "", flowID, nil
+	} else if flow, err := s.crawlerBackend.GetFlow(ctx, flowID); err == nil && flow != nil {
+		return flow.Request, "", flowID, nil
+	} else if op, ok := s.lookupSpecOperation(flowID); ok {
+		return slices.Clone(op.Request), op.Target, flowID, nil
+	}
+	return nil, "", "", errors.New("flow_id not found: run proxy_poll or crawl_poll to see available flows")
+}
@@ -2,12 +2,16 @@ package service
 
 import (
 	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	"github.com/go-harden/llm-security-toolbox/sectool/protocol"
+	"github.com/go-harden/llm-security-toolbox/sectool/service/store"
 )
 
 func TestMCP_ReplayWithMock(t *testing.T) {
@@ -50,6 +54,39 @@ func TestMCP_ReplayWithMock(t *testing.T) {
 	assert.NotEmpty(t, getResp.RespHeaders)
 }
 
+func TestMCP_ReplayDecodesResponse(t *testing.T) {
+	t.Parallel()
+
+	_, mcpClient, mockMCP, _, _ := setupMCPServerWithMock(t)
+
+	const chunkedResponse = "HttpRequestResponse{httpRequest=GET /chunked HTTP/1.1, httpResponse=HTTP/1.1 200 OK\r\nTransfer-Encoding: chunked\r\n\r\n5\r\nhello\r\n0\r\n\r\n}"
+
+	t.Run("decoded_by_default", func(t *testing.T) {
+		mockMCP.SetSendResponse(chunkedResponse)
+		resp := CallMCPToolJSONOK[protocol.ReplaySendResponse](t, mcpClient, "request_send", map[string]interface{}{
+			"url": "https://example.com/chunked",
+		})
+		assert.Equal(t, "chunked", resp.Decoded)
+		assert.Equal(t, "hello", resp.RespPreview)
+		assert.NotContains(t, resp.RespHeaders, "Transfer-Encoding")
+
+		getResp := CallMCPToolJSONOK[protocol.ReplayGetResponse](t, mcpClient, "replay_get", map[string]interface{}{
+			"replay_id": resp.ReplayID,
+		})
+		assert.Equal(t, "hello", getResp.RespBody)
+	})
+
+	t.Run("keep_encoding", func(t *testing.T) {
+		mockMCP.SetSendResponse(chunkedResponse)
+		resp := CallMCPToolJSONOK[protocol.ReplaySendResponse](t, mcpClient, "request_send", map[string]interface{}{
+			"url":           "https://example.com/chunked",
+			"keep_encoding": true,
+		})
+		assert.Empty(t, resp.Decoded)
+		assert.Contains(t, resp.RespHeaders, "Transfer-Encoding: chunked")
+	})
+}
+
 func TestMCP_RequestSendWithMock(t *testing.T) {
 	t.Parallel()
 
@@ -303,3 +340,393 @@ func TestMCP_ReplaySendModifications(t *testing.T) {
 		})
 	}
 }
+
+func TestMCP_ReplaySendFormModifications(t *testing.T) {
+	t.Parallel()
+
+	srv, mcpClient, mockMCP, _, _ := setupMCPServerWithMock(t)
+	mockMCP.AddProxyEntry(
+		"POST /upload HTTP/1.1\r\nHost: mock.test\r\nContent-Type: multipart/form-data; boundary=b1\r\nContent-Length: 94\r\n\r\n"+
+			"--b1\r\nContent-Disposition: form-data; name=\"file\"; filename=\"a.txt\"\r\n\r\nhello\r\n--b1--\r\n",
+		"HTTP/1.1 200 OK\r\n\r\n",
+		"",
+	)
+	mockMCP.AddProxyEntry(
+		"POST /profile HTTP/1.1\r\nHost: mock.test\r\nContent-Type: application/x-www-form-urlencoded\r\nContent-Length: 21\r\n\r\nname=a&role=user&x=1",
+		"HTTP/1.1 200 OK\r\n\r\n",
+		"",
+	)
+
+	t.Run("urlencoded", func(t *testing.T) {
+		resp := CallMCPToolJSONOK[protocol.ReplaySendResponse](t, mcpClient, "replay_send", map[string]interface{}{
+			"flow_id":     "last",
+			"set_form":    map[string]interface{}{"role": "admin", "admin": true},
+			"remove_form": []interface{}{"x"},
+		})
+		entry, ok := srv.requestStore.Get(resp.ReplayID)
+		require.True(t, ok)
+		_, body := splitHeadersBody(entry.Request)
+		assert.Equal(t, "name=a&role=admin&admin=true", string(body))
+		assert.Contains(t, string(entry.Request), "Content-Length: 28\r\n")
+	})
+
+	t.Run("multipart_boundary", func(t *testing.T) {
+		resp := CallMCPToolJSONOK[protocol.ReplaySendResponse](t, mcpClient, "replay_send", map[string]interface{}{
+			"flow_id":  "last-1",
+			"set_form": map[string]interface{}{"file": "x\r\n--b1--"},
+		})
+		entry, ok := srv.requestStore.Get(resp.ReplayID)
+		require.True(t, ok)
+		headers, body := splitHeadersBody(entry.Request)
+		contentType := http.Header(parseHeadersToMap(string(headers))).Get("Content-Type")
+		require.NotContains(t, contentType, "boundary=b1")
+		_, params, err := mime.ParseMediaType(contentType)
+		require.NoError(t, err)
+		parts, err := splitMultipart(body, params["boundary"])
+		require.NoError(t, err)
+		require.Len(t, parts.parts, 1)
+		assert.Equal(t, "x\r\n--b1--", string(parts.parts[0].content))
+		assert.Contains(t, string(headers), fmt.Sprintf("Content-Length: %d\r\n", len(body)))
+	})
+
+	t.Run("not_a_form", func(t *testing.T) {
+		mockMCP.AddProxyEntry(
+			"POST /api HTTP/1.1\r\nHost: mock.test\r\nContent-Type: application/json\r\nContent-Length: 2\r\n\r\n{}",
+			"HTTP/1.1 200 OK\r\n\r\n",
+			"",
+		)
+		result := CallMCPTool(t, mcpClient, "replay_send", map[string]interface{}{
+			"flow_id":  "last",
+			"set_form": map[string]interface{}{"a": "b"},
+		})
+		assert.True(t, result.IsError)
+		assert.Contains(t, ExtractMCPText(t, result), "form body modification failed: body is not a form")
+	})
+}
+
+func TestMCP_ReplaySendXMLModifications(t *testing.T) {
+	t.Parallel()
+
+	srv, mcpClient, mockMCP, _, _ := setupMCPServerWithMock(t)
+	mockMCP.AddProxyEntry(
+		"POST /ws HTTP/1.1\r\nHost: mock.test\r\nContent-Type: text/xml\r\nContent-Length: 63\r\n\r\n"+
+			`<req><user id="7"><name>bob</name><debug>0</debug></user></req>`,
+		"HTTP/1.1 200 OK\r\n\r\n",
+		"",
+	)
+
+	t.Run("xpath_and_doctype", func(t *testing.T) {
+		resp := CallMCPToolJSONOK[protocol.ReplaySendResponse](t, mcpClient, "replay_send", map[string]interface{}{
+			"flow_id":     "last",
+			"set_xml":     map[string]interface{}{"//user/@id": 1, "//name": "&xxe;"},
+			"remove_xml":  []interface{}{"//debug"},
+			"xml_doctype": `<!ENTITY xxe SYSTEM "file:///etc/passwd">`,
+		})
+		entry, ok := srv.requestStore.Get(resp.ReplayID)
+		require.True(t, ok)
+		headers, body := splitHeadersBody(entry.Request)
+		assert.Equal(t, "<!DOCTYPE req [<!ENTITY xxe SYSTEM \"file:///etc/passwd\">]>\n"+
+			`<req><user id="1"><name>&xxe;</name></user></req>`, string(body))
+		assert.Contains(t, string(headers), fmt.Sprintf("Content-Length: %d\r\n", len(body)))
+	})
+
+	t.Run("no_match", func(t *testing.T) {
+		result := CallMCPTool(t, mcpClient, "replay_send", map[string]interface{}{
+			"flow_id":    "last",
+			"remove_xml": []interface{}{"//missing"},
+		})
+		assert.True(t, result.IsError)
+		assert.Contains(t, ExtractMCPText(t, result), `XML body modification failed: remove_xml "//missing": matched no nodes`)
+	})
+}
+
+func TestMCP_ReplayRefShortcuts(t *testing.T) {
+	t.Parallel()
+
+	_, mcpClient, mockMCP, _, _ := setupMCPServerWithMock(t)
+
+	mockMCP.AddProxyEntry(
+		"GET /first HTTP/1.1\r\nHost: mock.test\r\n\r\n",
+		"HTTP/1.1 200 OK\r\n\r\nfirst",
+		"",
+	)
+	mockMCP.AddProxyEntry(
+		"GET /second HTTP/1.1\r\nHost: mock.test\r\n\r\n",
+		"HTTP/1.1 200 OK\r\n\r\nsecond",
+		"",
+	)
+	mockMCP.SetSendResponse(
+		"HttpRequestResponse{httpRequest=GET /second HTTP/1.1, httpResponse=HTTP/1.1 200 OK\r\n\r\nok}",
+	)
+
+	t.Run("proxy_get_last", func(t *testing.T) {
+		last := CallMCPToolJSONOK[protocol.ProxyGetResponse](t, mcpClient, "proxy_get", map[string]interface{}{
+			"flow_id": "last",
+		})
+		assert.Contains(t, last.URL, "/second")
+		assert.NotEqual(t, "last", last.FlowID)
+
+		prev := CallMCPToolJSONOK[protocol.ProxyGetResponse](t, mcpClient, "proxy_get", map[string]interface{}{
+			"flow_id": "last-1",
+		})
+		assert.Contains(t, prev.URL, "/first")
+	})
+
+	t.Run("proxy_get_out_of_range", func(t *testing.T) {
+		result := CallMCPTool(t, mcpClient, "proxy_get", map[string]interface{}{
+			"flow_id": "last-5",
+		})
+		assert.True(t, result.IsError)
+		assert.Contains(t, ExtractMCPText(t, result), "out of range")
+	})
+
+	t.Run("replay_label_and_last", func(t *testing.T) {
+		sendResp := CallMCPToolJSONOK[protocol.ReplaySendResponse](t, mcpClient, "replay_send", map[string]interface{}{
+			"flow_id": "last",
+			"label":   "baseline",
+		})
+
+		byLabel := CallMCPToolJSONOK[protocol.ReplayGetResponse](t, mcpClient, "replay_get", map[string]interface{}{
+			"replay_id": "baseline",
+		})
+		assert.Equal(t, sendResp.ReplayID, byLabel.ReplayID)
+
+		byLast := CallMCPToolJSONOK[protocol.ReplayGetResponse](t, mcpClient, "replay_get", map[string]interface{}{
+			"replay_id": "last",
+		})
+		assert.Equal(t, sendResp.ReplayID, byLast.ReplayID)
+	})
+}
+
+func TestMCP_ReplayHistoryAndRerun(t *testing.T) {
+	t.Parallel()
+
+	_, mcpClient, mockMCP, _, _ := setupMCPServerWithMock(t)
+
+	mockMCP.SetSendResponse(
+		"HttpRequestResponse{httpRequest=GET /hist HTTP/1.1, httpResponse=HTTP/1.1 200 OK\r\n\r\nok}",
+	)
+
+	empty := CallMCPToolJSONOK[protocol.ReplayHistoryResponse](t, mcpClient, "replay_history", map[string]interface{}{})
+	assert.Empty(t, empty.Replays)
+
+	first := CallMCPToolJSONOK[protocol.ReplaySendResponse](t, mcpClient, "request_send", map[string]interface{}{
+		"url":   "https://example.com/hist?a=1",
+		"label": "first",
+	})
+
+	rerun := CallMCPToolJSONOK[protocol.ReplaySendResponse](t, mcpClient, "replay_send", map[string]interface{}{
+		"replay_id":   "first",
+		"set_query":   []interface{}{"a=2"},
+		"add_headers": []interface{}{"X-Rerun: 1"},
+	})
+	assert.NotEqual(t, first.ReplayID, rerun.ReplayID)
+
+	hist := CallMCPToolJSONOK[protocol.ReplayHistoryResponse](t, mcpClient, "replay_history", map[string]interface{}{})
+	require.Len(t, hist.Replays, 2)
+	assert.Equal(t, rerun.ReplayID, hist.Replays[0].ReplayID)
+	assert.Equal(t, "https://example.com/hist?a=2", hist.Replays[0].URL)
+	assert.Equal(t, first.ReplayID, hist.Replays[1].ReplayID)
+	assert.Equal(t, "first", hist.Replays[1].Label)
+	assert.Equal(t, "GET", hist.Replays[1].Method)
+	assert.Equal(t, "https://example.com/hist?a=1", hist.Replays[1].URL)
+	assert.Equal(t, 200, hist.Replays[1].Status)
+	assert.Equal(t, first.ReplayID, hist.Replays[0].Base)
+	assert.Empty(t, hist.Replays[1].Base)
+
+	chained := CallMCPToolJSONOK[protocol.ReplaySendResponse](t, mcpClient, "replay_send", map[string]interface{}{
+		"replay_id":    rerun.ReplayID,
+		"remove_query": []interface{}{"a"},
+	})
+	hist = CallMCPToolJSONOK[protocol.ReplayHistoryResponse](t, mcpClient, "replay_history", map[string]interface{}{})
+	require.Len(t, hist.Replays, 3)
+	assert.Equal(t, chained.ReplayID, hist.Replays[0].ReplayID)
+	assert.Equal(t, rerun.ReplayID, hist.Replays[0].Base)
+	assert.Equal(t, "https://example.com/hist", hist.Replays[0].URL)
+
+	both := CallMCPTool(t, mcpClient, "replay_send", map[string]interface{}{
+		"replay_id": "first",
+		"flow_id":   "abc",
+	})
+	assert.True(t, both.IsError)
+	assert.Contains(t, ExtractMCPText(t, both), "only one of flow_id or replay_id")
+
+	limited := CallMCPToolJSONOK[protocol.ReplayHistoryResponse](t, mcpClient, "replay_history", map[string]interface{}{
+		"limit": 1,
+	})
+	assert.Len(t, limited.Replays, 1)
+
+	result := CallMCPTool(t, mcpClient, "replay_send", map[string]interface{}{
+		"replay_id": "missing",
+	})
+	assert.True(t, result.IsError)
+	assert.Contains(t, ExtractMCPText(t, result), "replay not found")
+}
+
+func TestMCP_RequestCraft(t *testing.T) {
+	t.Parallel()
+
+	_, mcpClient, mockMCP, _, _ := setupMCPServerWithMock(t)
+
+	mockMCP.SetSendResponse(
+		"HttpRequestResponse{httpRequest=POST /craft HTTP/1.1, httpResponse=HTTP/1.1 201 Created\r\n\r\ncrafted}",
+	)
+
+	t.Run("raw", func(t *testing.T) {
+		resp := CallMCPToolJSONOK[protocol.ReplaySendResponse](t, mcpClient, "request_craft", map[string]interface{}{
+			"raw":   "POST /craft HTTP/1.1\nHost: example.com\n\na=1",
+			"label": "crafted",
+		})
+		assert.NotEmpty(t, resp.ReplayID)
+		assert.Equal(t, 201, resp.Status)
+
+		got := CallMCPToolJSONOK[protocol.ReplayGetResponse](t, mcpClient, "replay_get", map[string]interface{}{
+			"replay_id": "crafted",
+		})
+		assert.Equal(t, resp.ReplayID, got.ReplayID)
+		assert.Equal(t, 201, got.Status)
+
+		hist := CallMCPToolJSONOK[protocol.ReplayHistoryResponse](t, mcpClient, "replay_history", map[string]interface{}{})
+		require.NotEmpty(t, hist.Replays)
+		assert.Equal(t, "POST", hist.Replays[0].Method)
+		assert.Equal(t, "https://example.com/craft", hist.Replays[0].URL)
+	})
+
+	t.Run("fields", func(t *testing.T) {
+		resp := CallMCPToolJSONOK[protocol.ReplaySendResponse](t, mcpClient, "request_craft", map[string]interface{}{
+			"url":     "http://example.com/craft",
+			"method":  "POST",
+			"headers": map[string]interface{}{"Content-Type": "text/plain"},
+			"body":    "x",
+		})
+		assert.NotEmpty(t, resp.ReplayID)
+	})
+
+	t.Run("raw_and_url", func(t *testing.T) {
+		result := CallMCPTool(t, mcpClient, "request_craft", map[string]interface{}{
+			"raw": "GET / HTTP/1.1\r\nHost: example.com\r\n\r\n",
+			"url": "https://example.com/",
+		})
+		assert.True(t, result.IsError)
+		assert.Contains(t, ExtractMCPText(t, result), "exactly one of raw or url")
+	})
+
+	t.Run("no_destination", func(t *testing.T) {
+		result := CallMCPTool(t, mcpClient, "request_craft", map[string]interface{}{
+			"raw": "GET / HTTP/1.1\r\n\r\n",
+		})
+		assert.True(t, result.IsError)
+		assert.Contains(t, ExtractMCPText(t, result), "no destination")
+	})
+}
+
+func TestMCP_ReplayGetRange(t *testing.T) {
+	t.Parallel()
+
+	srv, mcpClient, _, _, _ := setupMCPServerWithMock(t)
+	srv.requestStore.Store("big", &store.RequestEntry{
+		Headers: []byte("HTTP/1.1 200 OK\r\nContent-Type: text/plain\r\n\r\n"),
+		Body:    []byte("alpha\nbravo\ncharlie\ndelta\n"),
+	})
+
+	t.Run("bytes", func(t *testing.T) {
+		resp := CallMCPToolJSONOK[protocol.ReplayGetResponse](t, mcpClient, "replay_get", map[string]interface{}{
+			"replay_id": "big", "offset": 6, "length": 5,
+		})
+		assert.Equal(t, "bravo", resp.RespBody)
+		require.NotNil(t, resp.BodyRange)
+		assert.Equal(t, 11, resp.BodyRange.NextOffset)
+		assert.Equal(t, 26, resp.BodyRange.TotalBytes)
+	})
+
+	t.Run("lines", func(t *testing.T) {
+		resp := CallMCPToolJSONOK[protocol.ReplayGetResponse](t, mcpClient, "replay_get", map[string]interface{}{
+			"replay_id": "big", "start_line": 3,
+		})
+		assert.Equal(t, "charlie\ndelta\n", resp.RespBody)
+		require.NotNil(t, resp.BodyRange)
+		assert.Zero(t, resp.BodyRange.NextOffset)
+	})
+
+	t.Run("no_range", func(t *testing.T) {
+		resp := CallMCPToolJSONOK[protocol.ReplayGetResponse](t, mcpClient, "replay_get", map[string]interface{}{
+			"replay_id": "big",
+		})
+		assert.Nil(t, resp.BodyRange)
+	})
+
+	t.Run("validation", func(t *testing.T) {
+		for name, args := range map[string]map[string]interface{}{
+			"mixed":       {"replay_id": "big", "offset": 0, "start_line": 1},
+			"negative":    {"replay_id": "big", "offset": -1},
+			"end_before":  {"replay_id": "big", "start_line": 3, "end_line": 2},
+			"beyond_body": {"replay_id": "big", "offset": 100},
+		} {
+			result := CallMCPTool(t, mcpClient, "replay_get", args)
+			assert.True(t, result.IsError, name)
+		}
+	})
+}
+
+func TestMCP_ReplayGetRender(t *testing.T) {
+	t.Parallel()
+
+	srv, mcpClient, _, _, _ := setupMCPServerWithMock(t)
+	srv.requestStore.Store("page", &store.RequestEntry{
+		Headers: []byte("HTTP/1.1 200 OK\r\nContent-Type: text/html\r\n\r\n"),
+		Body:    []byte("<html><body><p>one</p><p>two</p><p>three</p></body></html>"),
+	})
+
+	resp := CallMCPToolJSONOK[protocol.ReplayGetResponse](t, mcpClient, "replay_get", map[string]interface{}{
+		"replay_id": "page", "render": "auto",
+	})
+	assert.Equal(t, "text", resp.Rendered)
+	assert.Equal(t, "one\ntwo\nthree", resp.RespBody)
+
+	resp = CallMCPToolJSONOK[protocol.ReplayGetResponse](t, mcpClient, "replay_get", map[string]interface{}{
+		"replay_id": "page", "render": "raw", "offset": 6, "length": 6,
+	})
+	assert.Equal(t, "<body>", resp.RespBody)
+	require.NotNil(t, resp.BodyRange)
+
+	result := CallMCPTool(t, mcpClient, "replay_get", map[string]interface{}{"replay_id": "page", "render": "pdf"})
+	assert.True(t, result.IsError)
+	result = CallMCPTool(t, mcpClient, "replay_get", map[string]interface{}{"replay_id": "page", "render": "json"})
+	assert.True(t, result.IsError)
+}
+
+func TestNormalizeRawRequest(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name string
+		raw  string
+		want string
+	}{
+		{
+			name: "lf_headers",
+			raw:  "GET / HTTP/1.1\nHost: example.com\n\n",
+			want: "GET / HTTP/1.1\r\nHost: example.com\r\n\r\n",
+		},
+		{
+			name: "missing_blank_line",
+			raw:  "GET / HTTP/1.1\r\nHost: example.com",
+			want: "GET / HTTP/1.1\r\nHost: example.com\r\n\r\n",
+		},
+		{
+			name: "body_sets_length",
+			raw:  "POST / HTTP/1.1\nHost: example.com\n\nab\ncd",
+			want: "POST / HTTP/1.1\r\nHost: example.com\r\nContent-Length: 5\r\n\r\nab\ncd",
+		},
+		{
+			name: "stale_length",
+			raw:  "POST / HTTP/1.1\r\nHost: example.com\r\nContent-Length: 99\r\n\r\nab",
+			want: "POST / HTTP/1.1\r\nHost: example.com\r\nContent-Length: 2\r\n\r\nab",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, string(normalizeRawRequest([]byte(tc.raw))))
+		})
+	}
+}
@@ -0,0 +1,100 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	mimeMarkdown = "text/markdown"
+	mimeJSON     = "application/json"
+)
+
+// addResources publishes guides and evidence (flows, replays) as MCP resources.
+// Reads go through the same handlers as the equivalent tools.
+func (m *mcpServer) addResources() {
+	guideDescriptions := map[string]string{
+		WorkflowModeExplore:    "Instructions for exploratory security testing with sectool",
+		WorkflowModeTestReport: "Instructions for validating a reported vulnerability with sectool",
+		WorkflowModeAPI:        "Instructions for API and back-end security testing with sectool",
+		WorkflowModeLLMApp:     "Instructions for prompt injection and model-boundary testing with sectool",
+		WorkflowModeMobile:     "Instructions for testing a mobile app's back-end with sectool",
+	}
+	for _, task := range guideTasks(m.guides) {
+		content := m.guides[task]
+		desc, ok := guideDescriptions[task]
+		if !ok {
+			desc = "Custom workflow guide for task '" + task + "'"
+		}
+		m.server.AddResource(mcp.NewResource("sectool://guide/"+task, guideTitle(content, task),
+			mcp.WithResourceDescription(desc),
+			mcp.WithMIMEType(mimeMarkdown),
+		), staticResource(content, mimeMarkdown))
+	}
+
+	m.server.AddResourceTemplate(mcp.NewResourceTemplate("sectool://flow/{flow_id}", "Proxy flow",
+		mcp.WithTemplateDescription("Request and response for a proxy flow_id, as returned by proxy_get ('last'/'last-N' accepted)"),
+		mcp.WithTemplateMIMEType(mimeJSON),
+	), toolResource("flow_id", m.handleProxyGet))
+	m.server.AddResourceTemplate(mcp.NewResourceTemplate("sectool://replay/{replay_id}", "Replay result",
+		mcp.WithTemplateDescription("Response for a replay_id or replay label, as returned by replay_get"),
+		mcp.WithTemplateMIMEType(mimeJSON),
+	), toolResource("replay_id", m.handleReplayGet))
+}
+
+func staticResource(content, mimeType string) server.ResourceHandlerFunc {
+	return func(ctx context.Context, req mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		return []mcp.ResourceContents{mcp.TextResourceContents{
+			URI:      req.Params.URI,
+			MIMEType: mimeType,
+			Text:     content,
+		}}, nil
+	}
+}
+
+// toolResource adapts a tool handler taking a single ID parameter into a resource template handler.
+func toolResource(param string, handler server.ToolHandlerFunc) server.ResourceTemplateHandlerFunc {
+	return func(ctx context.Context, req mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		var id string
+		switch v := req.Params.Arguments[param].(type) {
+		case string:
+			id = v
+		case []string:
+			if len(v) > 0 {
+				id = v[0]
+			}
+		}
+		log.Printf("mcp/resource: reading %s", req.Params.URI)
+
+		var callReq mcp.CallToolRequest
+		callReq.Params.Arguments = map[string]interface{}{param: id}
+		result, err := handler(ctx, callReq)
+		if err != nil {
+			return nil, err
+		}
+		text := resultText(result)
+		if result.IsError {
+			return nil, errors.New(text)
+		}
+		return []mcp.ResourceContents{mcp.TextResourceContents{
+			URI:      req.Params.URI,
+			MIMEType: mimeJSON,
+			Text:     text,
+		}}, nil
+	}
+}
+
+// resultText joins the text content of a tool result.
+func resultText(result *mcp.CallToolResult) string {
+	var text string
+	for _, c := range result.Content {
+		if tc, ok := c.(mcp.TextContent); ok {
+			text += tc.Text
+		}
+	}
+	return text
+}
@@ -0,0 +1,95 @@
+package service
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/go-harden/llm-security-toolbox/sectool/protocol"
+)
+
+func TestMCP_Resources(t *testing.T) {
+	t.Parallel()
+
+	_, mcpClient, mockMCP, _, _ := setupMCPServerWithMock(t)
+
+	mockMCP.AddProxyEntry(
+		"GET /resource HTTP/1.1\r\nHost: example.com\r\n\r\n",
+		"HTTP/1.1 200 OK\r\n\r\nbody",
+		"",
+	)
+	mockMCP.SetSendResponse(
+		"HttpRequestResponse{httpRequest=GET /resource HTTP/1.1, httpResponse=HTTP/1.1 200 OK\r\n\r\nreplayed}",
+	)
+
+	readText := func(t *testing.T, uri string) (string, error) {
+		t.Helper()
+
+		var req mcp.ReadResourceRequest
+		req.Params.URI = uri
+		result, err := mcpClient.ReadResource(t.Context(), req)
+		if err != nil {
+			return "", err
+		}
+		require.Len(t, result.Contents, 1)
+		tc, ok := result.Contents[0].(mcp.TextResourceContents)
+		require.True(t, ok)
+		assert.Equal(t, uri, tc.URI)
+		return tc.Text, nil
+	}
+
+	t.Run("list", func(t *testing.T) {
+		resources, err := mcpClient.ListResources(t.Context(), mcp.ListResourcesRequest{})
+		require.NoError(t, err)
+		var uris []string
+		for _, r := range resources.Resources {
+			uris = append(uris, r.URI)
+		}
+		assert.ElementsMatch(t, []string{
+			"sectool://guide/explore",
+			"sectool://guide/test-report",
+			"sectool://guide/api",
+			"sectool://guide/llm-app",
+			"sectool://guide/mobile-backend",
+		}, uris)
+
+		templates, err := mcpClient.ListResourceTemplates(t.Context(), mcp.ListResourceTemplatesRequest{})
+		require.NoError(t, err)
+		assert.Len(t, templates.ResourceTemplates, 2)
+	})
+
+	t.Run("guide", func(t *testing.T) {
+		text, err := readText(t, "sectool://guide/explore")
+		require.NoError(t, err)
+		assert.Equal(t, workflowExploreContent, text)
+	})
+
+	t.Run("flow", func(t *testing.T) {
+		text, err := readText(t, "sectool://flow/last")
+		require.NoError(t, err)
+		var resp protocol.ProxyGetResponse
+		require.NoError(t, json.Unmarshal([]byte(text), &resp))
+		assert.Contains(t, resp.URL, "/resource")
+	})
+
+	t.Run("replay", func(t *testing.T) {
+		sendResp := CallMCPToolJSONOK[protocol.ReplaySendResponse](t, mcpClient, "replay_send", map[string]interface{}{
+			"flow_id": "last",
+		})
+
+		text, err := readText(t, "sectool://replay/"+sendResp.ReplayID)
+		require.NoError(t, err)
+		var resp protocol.ReplayGetResponse
+		require.NoError(t, json.Unmarshal([]byte(text), &resp))
+		assert.Equal(t, sendResp.ReplayID, resp.ReplayID)
+	})
+
+	t.Run("not_found", func(t *testing.T) {
+		_, err := readText(t, "sectool://replay/missing")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "replay not found")
+	})
+}
@@ -0,0 +1,145 @@
+package service
+
+import (
+	"context"
+	"log"
+	"net/url"
+	"slices"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/go-harden/llm-security-toolbox/sectool/protocol"
+	"github.com/go-harden/llm-security-toolbox/sectool/saml"
+)
+
+func (m *mcpServer) samlDecodeTool() mcp.Tool {
+	return mcp.NewTool("saml_decode",
+		mcp.WithDescription(`Decode SAML messages (SAMLRequest/SAMLResponse) without verifying them and suggest attacks.
+
+Set one of: value (the parameter value; URL-encoded or not, base64, DEFLATE-compressed for the redirect binding); text (a request, body or HTML form to search for SAMLRequest/SAMLResponse); or flow_id (searches the proxy flow's request and response).
+Returns each message's type, issuer, NameID, audiences, validity window, attributes, which parts are signed, hints (XSW, comment injection, replay) and the XML.
+Edit the XML and re-encode with saml_encode. No traffic is sent.`),
+		mcp.WithString("value", mcp.Description("SAMLRequest or SAMLResponse value, or raw XML")),
+		mcp.WithString("text", mcp.Description("Request, body or HTML containing SAMLRequest/SAMLResponse")),
+		mcp.WithString("flow_id", mcp.Description("Proxy flow to extract SAML messages from, or "+recentRefUsage)),
+		annotateReadOnly,
+	)
+}
+
+func (m *mcpServer) samlEncodeTool() mcp.Tool {
+	return mcp.NewTool("saml_encode",
+		mcp.WithDescription(`Encode SAML XML as a SAMLRequest/SAMLResponse value: base64 for the POST binding, or DEFLATE then base64 with deflate for the redirect binding.
+
+Returns encoded and url_encoded (for a query string or form body); pass it to replay_send set_query or set_form. No signing is done.`),
+		mcp.WithString("xml", mcp.Required(), mcp.Description("SAML message XML, e.g. edited saml_decode output")),
+		mcp.WithBoolean("deflate", mcp.Description("Compress for the HTTP-Redirect binding (default: false, POST binding)")),
+		annotateReadOnly,
+	)
+}
+
+func (m *mcpServer) handleSAMLDecode(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	value, text, flowID := req.GetString("value", ""), req.GetString("text", ""), req.GetString("flow_id", "")
+	var raw []string
+	switch {
+	case value != "":
+		raw = []string{value}
+	case text != "":
+		raw = saml.Find(text)
+	case flowID != "":
+		flowID, err := m.service.resolveFlowRef(ctx, flowID)
+		if err != nil {
+			return errorResultFromErr("", err), nil
+		}
+		entry, ok := m.service.flowStore.Lookup(flowID)
+		if !ok {
+			return errorResult("flow_id not found: run proxy_poll to see available flows"), nil
+		}
+		proxyEntries, err := m.service.httpBackend.GetProxyHistory(ctx, 1, entry.Offset)
+		if err != nil {
+			return errorResultFromErr("failed to fetch flow: ", err), nil
+		} else if len(proxyEntries) == 0 {
+			return errorResult("flow not found in proxy history"), nil
+		}
+		raw = saml.Find(proxyEntries[0].Request + "\n" + proxyEntries[0].Response)
+	default:
+		return errorResult("one of value, text or flow_id is required"), nil
+	}
+
+	resp := protocol.SAMLDecodeResponse{Messages: make([]protocol.SAMLMessage, 0, len(raw))}
+	for _, s := range raw {
+		msg, err := saml.Decode(s)
+		if err != nil {
+			if value != "" {
+				return errorResult(err.Error()), nil
+			}
+			continue // parameters that are not SAML messages
+		}
+		resp.Messages = append(resp.Messages, decodeSAML(msg, time.Now()))
+	}
+	if len(resp.Messages) == 0 {
+		return errorResult("no SAML message found"), nil
+	}
+	log.Printf("mcp/saml_decode: decoded %d message(s)", len(resp.Messages))
+	return jsonResult(resp)
+}
+
+// decodeSAML describes msg and lists attacks worth trying against the service provider.
+func decodeSAML(msg *saml.Message, now time.Time) protocol.SAMLMessage {
+	d := protocol.SAMLMessage{
+		Binding:            msg.Binding,
+		Type:               msg.Type,
+		ID:                 msg.ID,
+		IssueInstant:       msg.IssueInstant,
+		Destination:        msg.Destination,
+		InResponseTo:       msg.InResponseTo,
+		ACSURL:             msg.ACSURL,
+		Issuer:             msg.Issuer,
+		Status:             msg.Status,
+		NameID:             msg.NameID,
+		Audiences:          msg.Audiences,
+		NotBefore:          msg.NotBefore,
+		NotOnOrAfter:       msg.NotOnOrAfter,
+		Attributes:         msg.Attributes,
+		Signed:             msg.Signed,
+		EncryptedAssertion: msg.EncryptedAssertion,
+		XML:                msg.XML,
+	}
+
+	switch {
+	case len(msg.Signed) == 0 && msg.Type == "Response" && !msg.EncryptedAssertion:
+		d.Hints = append(d.Hints, "unsigned response: edit NameID or attributes and re-encode with saml_encode; the SP may not require a signature")
+	case len(msg.Signed) == 0:
+		d.Hints = append(d.Hints, "unsigned message: edit it and re-encode with saml_encode")
+	case !slices.Contains(msg.Signed, saml.SignedAssertion) && msg.Type == "Response":
+		d.Hints = append(d.Hints, "only the response is signed: try signature wrapping (XSW) with an injected unsigned assertion, or removing the Signature element")
+	default:
+		d.Hints = append(d.Hints, "signed: try signature wrapping (XSW), removing the Signature element, and comment injection in NameID (admin@corp.test<!---->.evil.test)")
+	}
+	if msg.EncryptedAssertion {
+		d.Hints = append(d.Hints, "assertion is encrypted: NameID and attributes are not visible without the SP key")
+	}
+	if msg.ACSURL != "" {
+		d.Hints = append(d.Hints, "AuthnRequest carries AssertionConsumerServiceURL: test whether the IdP sends the response to a URL you control")
+	}
+	if end, err := time.Parse(time.RFC3339, msg.NotOnOrAfter); err == nil && now.After(end) {
+		d.Hints = append(d.Hints, "conditions expired: replay it to test whether the SP enforces NotOnOrAfter and one-time use")
+	}
+	return d
+}
+
+func (m *mcpServer) handleSAMLEncode(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	xml := req.GetString("xml", "")
+	if xml == "" {
+		return errorResult("xml is required"), nil
+	}
+	if _, err := saml.Parse([]byte(xml), ""); err != nil {
+		return errorResult(err.Error()), nil
+	}
+
+	encoded, err := saml.Encode(xml, req.GetBool("deflate", false))
+	if err != nil {
+		return errorResultFromErr("failed to encode: ", err), nil
+	}
+	return jsonResult(protocol.SAMLEncodeResponse{Encoded: encoded, URLEncoded: url.QueryEscape(encoded)})
+}
@@ -0,0 +1,102 @@
+package service
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/go-harden/llm-security-toolbox/sectool/protocol"
+	"github.com/go-harden/llm-security-toolbox/sectool/saml"
+)
+
+const testSAMLResponse = `<samlp:Response xmlns:samlp="urn:oasis:names:tc:SAML:2.0:protocol" xmlns:saml="urn:oasis:names:tc:SAML:2.0:assertion" ID="_r1" InResponseTo="_a1" Destination="https://sp.test/acs">` +
+	`<saml:Issuer>https://idp.test</saml:Issuer>` +
+	`<samlp:Status><samlp:StatusCode Value="urn:oasis:names:tc:SAML:2.0:status:Success"/></samlp:Status>` +
+	`<saml:Assertion ID="_as1"><saml:Issuer>https://idp.test</saml:Issuer>` +
+	`<ds:Signature xmlns:ds="http://www.w3.org/2000/09/xmldsig#"/>` +
+	`<saml:Subject><saml:NameID>alice@corp.test</saml:NameID></saml:Subject>` +
+	`<saml:Conditions NotBefore="2020-01-01T00:00:00Z" NotOnOrAfter="2020-01-01T00:05:00Z">` +
+	`<saml:AudienceRestriction><saml:Audience>https://sp.test</saml:Audience></saml:AudienceRestriction></saml:Conditions>` +
+	`<saml:AttributeStatement><saml:Attribute Name="role"><saml:AttributeValue>user</saml:AttributeValue></saml:Attribute></saml:AttributeStatement>` +
+	`</saml:Assertion></samlp:Response>`
+
+func TestMCP_SAMLDecode(t *testing.T) {
+	t.Parallel()
+
+	_, mcpClient, mockMCP, _, _ := setupMCPServerWithMock(t)
+
+	post, err := saml.Encode(testSAMLResponse, false)
+	require.NoError(t, err)
+
+	t.Run("value", func(t *testing.T) {
+		resp := CallMCPToolJSONOK[protocol.SAMLDecodeResponse](t, mcpClient, "saml_decode", map[string]interface{}{
+			"value": url.QueryEscape(post),
+		})
+		require.Len(t, resp.Messages, 1)
+		msg := resp.Messages[0]
+		assert.Equal(t, saml.BindingPOST, msg.Binding)
+		assert.Equal(t, "Response", msg.Type)
+		assert.Equal(t, "https://idp.test", msg.Issuer)
+		assert.Equal(t, "alice@corp.test", msg.NameID)
+		assert.Equal(t, []string{"https://sp.test"}, msg.Audiences)
+		assert.Equal(t, map[string][]string{"role": {"user"}}, msg.Attributes)
+		assert.Equal(t, []string{saml.SignedAssertion}, msg.Signed)
+		assert.Len(t, msg.Hints, 2) // signed, expired
+		assert.Equal(t, testSAMLResponse, msg.XML)
+	})
+
+	t.Run("flow", func(t *testing.T) {
+		request := `<samlp:AuthnRequest xmlns:samlp="urn:oasis:names:tc:SAML:2.0:protocol" ID="_a1" AssertionConsumerServiceURL="https://sp.test/acs"/>`
+		redirect, err := saml.Encode(request, true)
+		require.NoError(t, err)
+
+		mockMCP.AddProxyEntry(
+			"GET /sso?SAMLRequest="+url.QueryEscape(redirect)+"&RelayState=x HTTP/1.1\r\nHost: idp.test\r\n\r\n",
+			"HTTP/1.1 200 OK\r\n\r\n<form><input type=\"hidden\" name=\"SAMLResponse\" value=\""+post+"\"/></form>",
+			"",
+		)
+		resp := CallMCPToolJSONOK[protocol.SAMLDecodeResponse](t, mcpClient, "saml_decode", map[string]interface{}{
+			"flow_id": "last",
+		})
+		require.Len(t, resp.Messages, 2)
+		assert.Equal(t, "AuthnRequest", resp.Messages[0].Type)
+		assert.Equal(t, saml.BindingRedirect, resp.Messages[0].Binding)
+		assert.Equal(t, "https://sp.test/acs", resp.Messages[0].ACSURL)
+		assert.Len(t, resp.Messages[0].Hints, 2) // unsigned, ACS URL
+		assert.Equal(t, "Response", resp.Messages[1].Type)
+	})
+
+	t.Run("validation", func(t *testing.T) {
+		result := CallMCPTool(t, mcpClient, "saml_decode", map[string]interface{}{})
+		assert.True(t, result.IsError)
+		result = CallMCPTool(t, mcpClient, "saml_decode", map[string]interface{}{"text": "no saml here"})
+		assert.True(t, result.IsError)
+		assert.Contains(t, ExtractMCPText(t, result), "no SAML message found")
+		result = CallMCPTool(t, mcpClient, "saml_decode", map[string]interface{}{"value": "!!!"})
+		assert.True(t, result.IsError)
+		assert.Contains(t, ExtractMCPText(t, result), "invalid base64")
+	})
+}
+
+func TestMCP_SAMLEncode(t *testing.T) {
+	t.Parallel()
+
+	_, mcpClient, _, _, _ := setupMCPServerWithMock(t)
+
+	for _, deflate := range []bool{false, true} {
+		resp := CallMCPToolJSONOK[protocol.SAMLEncodeResponse](t, mcpClient, "saml_encode", map[string]interface{}{
+			"xml":     testSAMLResponse,
+			"deflate": deflate,
+		})
+		assert.Equal(t, url.QueryEscape(resp.Encoded), resp.URLEncoded)
+		msg, err := saml.Decode(resp.URLEncoded)
+		require.NoError(t, err)
+		assert.Equal(t, "alice@corp.test", msg.NameID)
+	}
+
+	result := CallMCPTool(t, mcpClient, "saml_encode", map[string]interface{}{"xml": "<unclosed"})
+	assert.True(t, result.IsError)
+	assert.Contains(t, ExtractMCPText(t, result), "invalid SAML XML")
+}
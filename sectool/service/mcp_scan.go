@@ -0,0 +1,238 @@
+package service
+
+import (
+	"context"
+	"html"
+	"log"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/go-harden/llm-security-toolbox/sectool/protocol"
+)
+
+const (
+	// scanIssuesPageSize is the page size when reading project issues from the backend.
+	scanIssuesPageSize = 100
+
+	// scanIssuesMax caps the project issues read for one scan_issues call.
+	scanIssuesMax = 5000
+)
+
+var (
+	htmlBreakRe = regexp.MustCompile(`(?i)<\s*(br|/p|/li|/ul|/div|/h\d)\s*/?>`)
+	htmlTagRe   = regexp.MustCompile(`<[^>]*>`)
+	blankRunRe  = regexp.MustCompile(`\n\s*\n+`)
+)
+
+func (m *mcpServer) scanStartTool() mcp.Tool {
+	return mcp.NewTool("scan_start",
+		mcp.WithDescription(`Start a Burp Scanner crawl and audit of URLs or of a flow's URL (Burp Suite Professional with its REST API enabled, see config burp_rest_url).
+
+The scan stays within the given URL prefixes and runs in Burp; it sends many requests, so scan only targets you are authorized to test actively.
+Track it with scan_status and read findings with scan_issues scan_id=<id>.
+configurations names Burp scan configurations, e.g. "Audit checks - light active", "Audit checks - passive", "Crawl strategy - fastest", "Crawl limit - 10 minutes".`),
+		mcp.WithString("flow_id", mcp.Description("Scan the URL of this flow, or "+recentRefUsage+" proxy entry (exclusive with urls)")),
+		mcp.WithArray("urls", mcp.Items(map[string]interface{}{"type": "string"}), mcp.Description("URLs to scan (exclusive with flow_id)")),
+		mcp.WithArray("configurations", mcp.Items(map[string]interface{}{"type": "string"}), mcp.Description("Named Burp scan configurations (default: Burp's default crawl and audit)")),
+		annotateSendsTraffic,
+	)
+}
+
+func (m *mcpServer) scanStatusTool() mcp.Tool {
+	return mcp.NewTool("scan_status",
+		mcp.WithDescription("Get the progress of a scan started with scan_start: status, percent done, requests sent and issue counts by severity."),
+		mcp.WithString("scan_id", mcp.Required(), mcp.Description("Scan ID from scan_start")),
+		annotateReadOnly,
+	)
+}
+
+func (m *mcpServer) scanIssuesTool() mcp.Tool {
+	return mcp.NewTool("scan_issues",
+		mcp.WithDescription(`List Burp Scanner issues, most severe first.
+
+With scan_id: issues found by that scan. Without: every issue Burp's passive and active scanners reported for the project, including passive findings on proxied traffic.
+Details and remediation are omitted unless details=true; filter first, then fetch details for the issues you verify.`),
+		mcp.WithString("scan_id", mcp.Description("Only issues from this scan_start scan")),
+		mcp.WithString("severity", mcp.Description("Minimum severity: high, medium, low or info (default: info)")),
+		mcp.WithString("url", mcp.Description("Only issues whose URL contains this text")),
+		mcp.WithBoolean("details", mcp.Description("Include issue detail and remediation as text")),
+		mcp.WithNumber("limit", mcp.Description("Maximum issues to return (default: 50)")),
+		mcp.WithNumber("offset", mcp.Description("Skip first N matching issues")),
+		annotateReadOnly,
+	)
+}
+
+// scanner returns the HTTP backend's scanner, or an error result when it has none.
+func (m *mcpServer) scanner() (Scanner, *mcp.CallToolResult) {
+	scanner, ok := m.service.httpBackend.(Scanner)
+	if !ok {
+		return nil, errorResult("the HTTP backend has no scanner: scans need Burp Suite Professional")
+	}
+	return scanner, nil
+}
+
+func (m *mcpServer) handleScanStart(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if err := m.requireWorkflow(); err != nil {
+		return err, nil
+	}
+	scanner, errResult := m.scanner()
+	if errResult != nil {
+		return errResult, nil
+	}
+
+	flowID, urls := req.GetString("flow_id", ""), req.GetStringSlice("urls", nil)
+	if (flowID == "") == (len(urls) == 0) {
+		return errorResult("exactly one of flow_id or urls is required"), nil
+	}
+	if flowID != "" {
+		rawRequest, baseTarget, _, err := m.service.loadBaseRequest(ctx, flowID, "")
+		if err != nil {
+			return errorResultFromErr("", err), nil
+		}
+		host, port, usesHTTPS := parseTarget(rawRequest, baseTarget)
+		_, _, path := extractRequestMeta(string(rawRequest))
+		urls = []string{Target{Hostname: host, Port: port, UsesHTTPS: usesHTTPS}.origin() + path}
+	}
+
+	scope := m.service.projectScope()
+	for _, raw := range urls {
+		u, err := url.Parse(raw)
+		if err != nil || (u.Scheme != schemeHTTP && u.Scheme != schemeHTTPS) || u.Host == "" {
+			return errorResult("invalid URL " + raw + ": expected http(s)://host/path"), nil
+		}
+		if err := m.service.guard.CheckTarget(ctx, scope, u.Host, u.RequestURI()); err != nil {
+			return errorResult(err.Error()), nil
+		}
+	}
+
+	scanID, err := scanner.StartScan(ctx, urls, req.GetStringSlice("configurations", nil))
+	if err != nil {
+		return errorResultFromErr("failed to start scan: ", err), nil
+	}
+	log.Printf("mcp/scan_start: scan %s of %d URLs", scanID, len(urls))
+	return jsonResult(protocol.ScanStartResponse{ScanID: scanID, URLs: urls})
+}
+
+func (m *mcpServer) handleScanStatus(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if err := m.requireWorkflow(); err != nil {
+		return err, nil
+	}
+	scanner, errResult := m.scanner()
+	if errResult != nil {
+		return errResult, nil
+	}
+
+	scanID := req.GetString("scan_id", "")
+	if scanID == "" {
+		return errorResult("scan_id is required"), nil
+	}
+	state, err := scanner.GetScan(ctx, scanID)
+	if err != nil {
+		return errorResultFromErr("failed to get scan: ", err), nil
+	}
+
+	resp := protocol.ScanStatusResponse{
+		ScanID:     state.ID,
+		Status:     state.Status,
+		Done:       slices.Contains([]string{"succeeded", "failed", "cancelled"}, state.Status),
+		Progress:   state.Progress,
+		Caption:    state.Caption,
+		Requests:   state.Requests,
+		Elapsed:    (time.Duration(state.ElapsedSeconds) * time.Second).String(),
+		IssueCount: len(state.Issues),
+	}
+	if len(state.Issues) > 0 {
+		resp.Severities = make(map[string]int)
+		for _, issue := range state.Issues {
+			resp.Severities[issue.Severity]++
+		}
+	}
+	return jsonResult(resp)
+}
+
+func (m *mcpServer) handleScanIssues(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if err := m.requireWorkflow(); err != nil {
+		return err, nil
+	}
+	scanner, errResult := m.scanner()
+	if errResult != nil {
+		return errResult, nil
+	}
+
+	minSeverity := strings.ToLower(req.GetString("severity", "info"))
+	maxRank := slices.Index(scanSeverities, minSeverity)
+	if maxRank < 0 || minSeverity == "false_positive" {
+		return errorResult("invalid severity: use high, medium, low or info"), nil
+	}
+	urlFilter := req.GetString("url", "")
+	details := req.GetBool("details", false)
+	limit, offset := req.GetInt("limit", 50), req.GetInt("offset", 0)
+
+	var issues []ScanIssue
+	if scanID := req.GetString("scan_id", ""); scanID != "" {
+		state, err := scanner.GetScan(ctx, scanID)
+		if err != nil {
+			return errorResultFromErr("failed to get scan: ", err), nil
+		}
+		issues = state.Issues
+	} else {
+		for len(issues) < scanIssuesMax {
+			page, err := scanner.ListIssues(ctx, scanIssuesPageSize, len(issues))
+			if err != nil {
+				return errorResultFromErr("failed to list scanner issues: ", err), nil
+			}
+			issues = append(issues, page...)
+			if len(page) < scanIssuesPageSize {
+				break
+			}
+		}
+	}
+
+	var matched []ScanIssue
+	for _, issue := range issues {
+		rank := slices.Index(scanSeverities, issue.Severity)
+		if rank < 0 || rank > maxRank || (urlFilter != "" && !strings.Contains(issue.URL, urlFilter)) {
+			continue
+		}
+		matched = append(matched, issue)
+	}
+	slices.SortStableFunc(matched, func(a, b ScanIssue) int {
+		return slices.Index(scanSeverities, a.Severity) - slices.Index(scanSeverities, b.Severity)
+	})
+
+	resp := protocol.ScanIssuesResponse{Issues: []protocol.ScanIssue{}, Total: len(matched)}
+	if offset < len(matched) {
+		page := matched[offset:]
+		if limit > 0 && len(page) > limit {
+			page = page[:limit]
+			resp.NextOffset = offset + limit
+		}
+		for _, issue := range page {
+			out := protocol.ScanIssue{
+				Name:       issue.Name,
+				Severity:   issue.Severity,
+				Confidence: issue.Confidence,
+				URL:        issue.URL,
+			}
+			if details {
+				out.Detail, out.Remediation = htmlToText(issue.Detail), htmlToText(issue.Remediation)
+			}
+			resp.Issues = append(resp.Issues, out)
+		}
+	}
+
+	log.Printf("mcp/scan_issues: %d of %d issues match", len(matched), len(issues))
+	return jsonResult(resp)
+}
+
+// htmlToText converts the HTML of scanner issue descriptions to plain text.
+func htmlToText(s string) string {
+	s = htmlBreakRe.ReplaceAllString(s, "\n")
+	s = html.UnescapeString(htmlTagRe.ReplaceAllString(s, ""))
+	return strings.TrimSpace(blankRunRe.ReplaceAllString(s, "\n\n"))
+}
@@ -0,0 +1,118 @@
+package service
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/go-harden/llm-security-toolbox/sectool/protocol"
+)
+
+func TestHTMLToText(t *testing.T) {
+	t.Parallel()
+
+	got := htmlToText("<p>The <b>q</b> parameter is vulnerable.</p><p>Payload: &lt;script&gt;</p><ul><li>one</li><li>two</li></ul>")
+	assert.Equal(t, "The q parameter is vulnerable.\nPayload: <script>\none\ntwo", got)
+}
+
+func TestMCP_Scan(t *testing.T) {
+	t.Parallel()
+
+	srv, mcpClient, mockMCP, _, _ := setupMCPServerWithMock(t)
+
+	var started map[string]interface{}
+	rest := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/key/v0.1/scan":
+			body, _ := io.ReadAll(r.Body)
+			_ = json.Unmarshal(body, &started)
+			w.Header().Set("Location", "7")
+			w.WriteHeader(http.StatusCreated)
+		case r.Method == http.MethodGet && r.URL.Path == "/key/v0.1/scan/7":
+			_, _ = io.WriteString(w, `{"task_id":"7","scan_status":"auditing",
+				"scan_metrics":{"crawl_and_audit_progress":40,"crawl_and_audit_caption":"Auditing","crawl_requests_made":10,"audit_requests_made":90,"total_elapsed_time":75},
+				"issue_events":[
+					{"id":"1","type":"issue_found","issue":{"name":"Cross-site scripting (reflected)","serial_number":"123","origin":"https://example.com","path":"/search","severity":"high","confidence":"firm","description":"<p>The <b>q</b> value is echoed.</p>"}},
+					{"id":"2","type":"issue_found","issue":{"name":"Cookie without HttpOnly flag set","serial_number":"124","origin":"https://example.com","path":"/","severity":"low","confidence":"certain"}}
+				]}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(rest.Close)
+	srv.httpBackend.(*BurpBackend).SetRESTURL(rest.URL + "/key")
+
+	mockMCP.AddProxyEntry("GET /search?q=a HTTP/1.1\r\nHost: example.com\r\n\r\n", "HTTP/1.1 200 OK\r\n\r\na", "")
+	mockMCP.AddScannerIssue(`{"name":"Strict transport security not enforced","severity":"LOW","confidence":"CERTAIN","baseUrl":"https://example.com/"}`)
+	mockMCP.AddScannerIssue(`{"name":"SQL injection","severity":"HIGH","confidence":"FIRM","baseUrl":"https://example.com/items","detail":"<p>Boolean-based.</p>"}`)
+	mockMCP.AddScannerIssue(`{"name":"Email addresses disclosed","severity":"INFORMATION","confidence":"CERTAIN","baseUrl":"https://other.example.com/about"}`)
+
+	t.Run("start_from_flow", func(t *testing.T) {
+		resp := CallMCPToolJSONOK[protocol.ScanStartResponse](t, mcpClient, "scan_start", map[string]interface{}{
+			"flow_id":        "last",
+			"configurations": []interface{}{"Audit checks - light active"},
+		})
+		assert.Equal(t, "7", resp.ScanID)
+		assert.Equal(t, []string{"https://example.com/search?q=a"}, resp.URLs)
+		assert.Equal(t, []interface{}{"https://example.com/search?q=a"}, started["urls"])
+		assert.Equal(t, []interface{}{map[string]interface{}{"name": "Audit checks - light active", "type": "NamedConfiguration"}}, started["scan_configurations"])
+	})
+
+	t.Run("status", func(t *testing.T) {
+		resp := CallMCPToolJSONOK[protocol.ScanStatusResponse](t, mcpClient, "scan_status", map[string]interface{}{"scan_id": "7"})
+		assert.Equal(t, "auditing", resp.Status)
+		assert.False(t, resp.Done)
+		assert.Equal(t, 40, resp.Progress)
+		assert.Equal(t, 100, resp.Requests)
+		assert.Equal(t, "1m15s", resp.Elapsed)
+		assert.Equal(t, map[string]int{"high": 1, "low": 1}, resp.Severities)
+
+		result := CallMCPTool(t, mcpClient, "scan_status", map[string]interface{}{"scan_id": "99"})
+		assert.True(t, result.IsError)
+		assert.Contains(t, ExtractMCPText(t, result), "404")
+	})
+
+	t.Run("scan_issues", func(t *testing.T) {
+		resp := CallMCPToolJSONOK[protocol.ScanIssuesResponse](t, mcpClient, "scan_issues", map[string]interface{}{
+			"scan_id": "7",
+			"details": true,
+		})
+		require.Len(t, resp.Issues, 2)
+		assert.Equal(t, "Cross-site scripting (reflected)", resp.Issues[0].Name)
+		assert.Equal(t, "https://example.com/search", resp.Issues[0].URL)
+		assert.Equal(t, "The q value is echoed.", resp.Issues[0].Detail)
+	})
+
+	t.Run("project_issues", func(t *testing.T) {
+		resp := CallMCPToolJSONOK[protocol.ScanIssuesResponse](t, mcpClient, "scan_issues", nil)
+		require.Len(t, resp.Issues, 3)
+		assert.Equal(t, "SQL injection", resp.Issues[0].Name)
+		assert.Equal(t, "high", resp.Issues[0].Severity)
+		assert.Equal(t, "info", resp.Issues[2].Severity)
+		assert.Empty(t, resp.Issues[0].Detail)
+
+		resp = CallMCPToolJSONOK[protocol.ScanIssuesResponse](t, mcpClient, "scan_issues", map[string]interface{}{
+			"severity": "low",
+			"url":      "https://example.com",
+			"limit":    1,
+		})
+		assert.Equal(t, 2, resp.Total)
+		require.Len(t, resp.Issues, 1)
+		assert.Equal(t, "SQL injection", resp.Issues[0].Name)
+		assert.Equal(t, 1, resp.NextOffset)
+	})
+
+	t.Run("validation", func(t *testing.T) {
+		result := CallMCPTool(t, mcpClient, "scan_start", map[string]interface{}{"urls": []interface{}{"ftp://example.com"}})
+		assert.True(t, result.IsError)
+		assert.Contains(t, ExtractMCPText(t, result), "invalid URL")
+
+		result = CallMCPTool(t, mcpClient, "scan_issues", map[string]interface{}{"severity": "critical"})
+		assert.True(t, result.IsError)
+	})
+}
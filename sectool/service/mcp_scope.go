@@ -0,0 +1,86 @@
+package service
+
+import (
+	"context"
+	"log"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/go-harden/llm-security-toolbox/sectool/config"
+	"github.com/go-harden/llm-security-toolbox/sectool/protocol"
+)
+
+func (m *mcpServer) scopeGetTool() mcp.Tool {
+	return mcp.NewTool("scope_get",
+		mcp.WithDescription(`Get the project scope (.sectool/scope.json).
+
+When a scope is defined, replay_send, request_send and request_craft refuse out-of-scope targets (including redirect hops), and proxy_poll returns only in-scope flows by default.
+target_allowlist, when present, comes from the user's config: every request must also match it, and no tool can change it.
+Returns: defined, targets, include, exclude, target_allowlist. Patterns are "host" or "host/path" globs.`),
+		annotateReadOnly,
+	)
+}
+
+func (m *mcpServer) scopeSetTool() mcp.Tool {
+	return mcp.NewTool("scope_set",
+		mcp.WithDescription(`Replace the project scope, saved to .sectool/scope.json and applied immediately.
+
+Only change scope as the user directs; never widen it to reach a host you were refused. Scope cannot widen the target_allowlist config.
+Each target URL adds an include pattern for its host and path prefix. Patterns are "host" or "host/path" globs (e.g., '*.example.com', 'api.example.com/v2/*'); exclude wins over include.`),
+		mcp.WithArray("targets", mcp.Items(map[string]interface{}{"type": "string"}), mcp.Description("Engagement base URLs (e.g., 'https://app.example.com')")),
+		mcp.WithArray("include", mcp.Items(map[string]interface{}{"type": "string"}), mcp.Description("Extra include patterns")),
+		mcp.WithArray("exclude", mcp.Items(map[string]interface{}{"type": "string"}), mcp.Description("Exclude patterns")),
+		mcp.WithBoolean("clear", mcp.Description("Remove the scope instead (all targets allowed)")),
+		annotateLocalChange,
+	)
+}
+
+func (m *mcpServer) handleScopeGet(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if err := m.requireWorkflow(); err != nil {
+		return err, nil
+	}
+
+	return jsonResult(m.scopeResponse(m.service.projectScope()))
+}
+
+func (m *mcpServer) handleScopeSet(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if err := m.requireWorkflow(); err != nil {
+		return err, nil
+	}
+
+	if req.GetBool("clear", false) {
+		if err := m.service.setProjectScope(nil); err != nil {
+			return errorResultFromErr("", err), nil
+		}
+		log.Printf("mcp/scope_set: scope cleared")
+		return jsonResult(m.scopeResponse(nil))
+	}
+
+	scope, err := config.NewScope(req.GetStringSlice("targets", nil), req.GetStringSlice("include", nil), req.GetStringSlice("exclude", nil))
+	if err != nil {
+		return errorResult(err.Error()), nil
+	} else if len(scope.Include) == 0 {
+		return errorResult("scope requires at least one target or include pattern (use clear=true to remove it)"), nil
+	}
+	if err := m.service.setProjectScope(scope); err != nil {
+		return errorResultFromErr("", err), nil
+	}
+	log.Printf("mcp/scope_set: include=%v exclude=%v", scope.Include, scope.Exclude)
+	return jsonResult(m.scopeResponse(scope))
+}
+
+func (m *mcpServer) scopeResponse(scope *config.Scope) protocol.ScopeResponse {
+	var resp protocol.ScopeResponse
+	if scope != nil {
+		resp = protocol.ScopeResponse{
+			Defined: true,
+			Targets: scope.Targets,
+			Include: scope.Include,
+			Exclude: scope.Exclude,
+		}
+	}
+	if g := m.service.guard; g != nil {
+		resp.TargetAllowlist = g.allowlist
+	}
+	return resp
+}
@@ -0,0 +1,110 @@
+package service
+
+import (
+	"context"
+	"log"
+	"slices"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/go-harden/llm-security-toolbox/sectool/protocol"
+	"github.com/go-harden/llm-security-toolbox/sectool/report"
+)
+
+const defaultProxyFindingsLimit = 50
+
+func (m *mcpServer) proxyFindingsTool() mcp.Tool {
+	var names []string
+	for _, d := range secretDetectors {
+		names = append(names, d.Name)
+	}
+	return mcp.NewTool("proxy_findings",
+		mcp.WithDescription(`Passively scan proxy history for leaked secrets and PII, most severe first.
+
+Detectors: `+strings.Join(names, ", ")+`.
+Response headers and decoded bodies are scanned, plus request URLs (tokens in query strings); request headers and bodies are not, since they carry your own credentials.
+Each call scans only flows captured since the previous call; each distinct value is reported once with the first flow containing it and the number of flows it appears in.
+With record (default true), detections above info severity are added as draft findings with the flow as evidence (see finding_list); emails are listed only.
+Values are redacted unless reveal is set.`),
+		mcp.WithArray("detectors", mcp.Items(map[string]interface{}{"type": "string"}), mcp.Description("Only these detectors")),
+		mcp.WithString("severity", mcp.Description("Minimum severity: info, low, medium, high or critical (default: info)")),
+		mcp.WithString("host", mcp.Description("Only flows whose host matches this glob")),
+		mcp.WithBoolean("record", mcp.Description("Add draft findings for new detections above info (default true)")),
+		mcp.WithBoolean("reveal", mcp.Description("Return full values instead of redacted ones")),
+		mcp.WithNumber("limit", mcp.Description("Maximum detections to return (default 50)")),
+		annotateLocalChange,
+	)
+}
+
+func (m *mcpServer) handleProxyFindings(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if err := m.requireWorkflow(); err != nil {
+		return err, nil
+	}
+
+	minSeverity := strings.ToLower(req.GetString("severity", protocol.SeverityInfo))
+	if !validSeverities[minSeverity] {
+		return errorResult("invalid severity: " + minSeverity + " (use info, low, medium, high, critical)"), nil
+	}
+	detectors := req.GetStringSlice("detectors", nil)
+	for _, name := range detectors {
+		if !slices.ContainsFunc(secretDetectors, func(d secretDetector) bool { return d.Name == name }) {
+			return errorResult("unknown detector: " + name), nil
+		}
+	}
+	hostGlob := req.GetString("host", "")
+	limit := req.GetInt("limit", defaultProxyFindingsLimit)
+	if limit <= 0 {
+		limit = defaultProxyFindingsLimit
+	}
+
+	entries, err := m.service.fetchAllProxyEntries(ctx)
+	if err != nil {
+		return errorResultFromErr("failed to fetch proxy history: ", err), nil
+	}
+	resp := protocol.ProxyFindingsResponse{
+		Scanned:    m.service.secrets.scan(entries, m.service.registerFlow),
+		Detections: []protocol.SecretDetection{},
+	}
+	if req.GetBool("record", true) {
+		if resp.Recorded, err = m.service.secrets.record(m.service.findings); err != nil {
+			return errorResultFromErr("failed to record findings: ", err), nil
+		}
+	}
+
+	found := m.service.secrets.list()
+	slices.SortStableFunc(found, func(a, b secretDetection) int {
+		return report.SeverityRank(a.Detector.Severity) - report.SeverityRank(b.Detector.Severity)
+	})
+	reveal := req.GetBool("reveal", false)
+	for _, d := range found {
+		if report.SeverityRank(d.Detector.Severity) > report.SeverityRank(minSeverity) ||
+			(len(detectors) > 0 && !slices.Contains(detectors, d.Detector.Name)) ||
+			!matchesGlob(d.Entry.host, hostGlob) {
+			continue
+		}
+		resp.Total++
+		if len(resp.Detections) >= limit {
+			continue
+		}
+		value := d.Value
+		if !reveal {
+			value = redactSecret(value)
+		}
+		resp.Detections = append(resp.Detections, protocol.SecretDetection{
+			Detector:  d.Detector.Name,
+			Severity:  d.Detector.Severity,
+			Value:     value,
+			Location:  d.Location,
+			FlowID:    d.FlowID,
+			Method:    d.Entry.method,
+			Host:      d.Entry.host,
+			Path:      d.Entry.path,
+			Count:     d.Count,
+			FindingID: d.FindingID,
+		})
+	}
+	log.Printf("mcp/proxy_findings: scanned %d flows, %d detections, %d findings recorded", resp.Scanned, resp.Total, resp.Recorded)
+
+	return jsonResult(resp)
+}
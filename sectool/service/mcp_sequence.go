@@ -0,0 +1,265 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/go-harden/llm-security-toolbox/sectool/protocol"
+)
+
+func (m *mcpServer) sequenceSetTool() mcp.Tool {
+	return mcp.NewTool("sequence_set",
+		mcp.WithDescription(`Define or delete a sequence: an ordered list of requests run end-to-end by sequence_run, for multi-step flows such as checkout or password reset.
+
+Each step is {"name": "...", "tool": "replay_send"|"request_send"|"request_craft", "args": {...}, "extract": {"var": "kind:expr"}, "expect": "2xx"}.
+tool defaults to replay_send; args are that tool's arguments, so steps start from a captured flow_id or replay_id, or a crafted url/raw request, with the usual edits.
+String args may hold {{var}} placeholders, filled from sequence_run vars and from values earlier steps extract (kinds: json path, header, cookie, regex with a capture group, field for HTML input/meta by name). Values are inserted as-is.
+expect fails the step when the status does not match (codes or ranges, e.g. '200,302', '2xx').
+Example (password reset):
+[{"name":"request","args":{"flow_id":"f7k2x","set_json":{"email":"{{email}}"}},"extract":{"token":"regex:token=([a-f0-9]+)"},"expect":"200"},
+ {"name":"reset","args":{"flow_id":"a1b2c","set_json":{"token":"{{token}}","password":"{{password}}"}},"expect":"2xx"}]
+Sequences are ephemeral and cleared on service restart.`),
+		mcp.WithString("name", mcp.Required(), mcp.Description("Sequence name (e.g., 'checkout')")),
+		mcp.WithArray("steps", mcp.Items(map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"name":    map[string]interface{}{"type": "string"},
+				"tool":    map[string]interface{}{"type": "string", "enum": sequenceStepTools},
+				"args":    map[string]interface{}{"type": "object"},
+				"extract": map[string]interface{}{"type": "object"},
+				"expect":  map[string]interface{}{"type": "string"},
+			},
+			"required": []string{"args"},
+		}), mcp.Description("Ordered steps (required unless delete)")),
+		mcp.WithBoolean("delete", mcp.Description("Delete the sequence instead")),
+		annotateLocalChange,
+	)
+}
+
+func (m *mcpServer) sequenceListTool() mcp.Tool {
+	return mcp.NewTool("sequence_list",
+		mcp.WithDescription("List sequences with their steps and the input variables sequence_run needs."),
+		annotateReadOnly,
+	)
+}
+
+func (m *mcpServer) sequenceRunTool() mcp.Tool {
+	return mcp.NewTool("sequence_run",
+		mcp.WithDescription(`Run a sequence's steps in order, stopping at the first step that fails to send, misses its expect status, or cannot extract a value.
+
+Each step is stored as a replay (replay_get for the full response). Placeholders not set by vars or an earlier extract are left to the step's environment and fail the step if it has none.
+Returns per-step status, replay_id and extracted values, plus the final variables.`),
+		mcp.WithString("name", mcp.Required(), mcp.Description("Sequence name")),
+		mcp.WithObject("vars", mcp.Description(`Input variables: {"email": "victim@example.com"}`)),
+		mcp.WithNumber("rate", mcp.Description(rateParamDescription)),
+		annotateSendsTraffic,
+	)
+}
+
+type sequenceStepDef struct {
+	Name    string                 `json:"name"`
+	Tool    string                 `json:"tool"`
+	Args    map[string]interface{} `json:"args"`
+	Extract map[string]string      `json:"extract"`
+	Expect  string                 `json:"expect"`
+}
+
+func (m *mcpServer) handleSequenceSet(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if err := m.requireWorkflow(); err != nil {
+		return err, nil
+	}
+
+	name := strings.TrimSpace(req.GetString("name", ""))
+	if name == "" {
+		return errorResult("name is required"), nil
+	}
+	if req.GetBool("delete", false) {
+		if !m.service.sequences.Delete(name) {
+			return errorResult("sequence " + name + " not found"), nil
+		}
+		log.Printf("mcp/sequence_set: deleted sequence %s", name)
+		return jsonResult(sequenceListResponse(m.service.sequences.List()))
+	}
+
+	var defs []sequenceStepDef
+	if raw, err := json.Marshal(req.GetArguments()["steps"]); err != nil {
+		return errorResult("invalid steps: " + err.Error()), nil
+	} else if err := json.Unmarshal(raw, &defs); err != nil {
+		return errorResult("invalid steps: " + err.Error()), nil
+	}
+	if len(defs) == 0 {
+		return errorResult("steps is required"), nil
+	} else if len(defs) > maxSequenceSteps {
+		return errorResult(fmt.Sprintf("too many steps: %d (max %d)", len(defs), maxSequenceSteps)), nil
+	}
+
+	q := &sequence{Name: name, Steps: make([]sequenceStep, len(defs))}
+	for i, def := range defs {
+		step, err := newSequenceStep(def.Name, def.Tool, def.Args, def.Extract, def.Expect)
+		if err != nil {
+			return errorResult(fmt.Sprintf("%s: %v", step.Label(i), err)), nil
+		}
+		q.Steps[i] = step
+	}
+	m.service.sequences.Set(q)
+
+	log.Printf("mcp/sequence_set: sequence %s has %d steps", name, len(q.Steps))
+	return jsonResult(sequenceListResponse(m.service.sequences.List()))
+}
+
+func (m *mcpServer) handleSequenceList(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if err := m.requireWorkflow(); err != nil {
+		return err, nil
+	}
+	return jsonResult(sequenceListResponse(m.service.sequences.List()))
+}
+
+func (m *mcpServer) handleSequenceRun(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if err := m.requireWorkflow(); err != nil {
+		return err, nil
+	}
+	ctx, err := applyRunRate(ctx, req)
+	if err != nil {
+		return errorResult(err.Error()), nil
+	}
+
+	q, err := m.service.sequences.Get(req.GetString("name", ""))
+	if err != nil {
+		return errorResultFromErr("", err), nil
+	}
+	vars := formFieldMap(req.GetArguments()["vars"])
+	if vars == nil {
+		vars = make(map[string]string)
+	}
+
+	log.Printf("mcp/sequence_run: running %s (%d steps)", q.Name, len(q.Steps))
+
+	resp := protocol.SequenceRunResponse{Name: q.Name, Steps: make([]protocol.SequenceStepResult, 0, len(q.Steps))}
+	for i := range q.Steps {
+		result := m.runSequenceStep(ctx, i, &q.Steps[i], vars)
+		resp.Steps = append(resp.Steps, result)
+		if result.Error != "" {
+			resp.Skipped = len(q.Steps) - i - 1
+			log.Printf("mcp/sequence_run: %s %s failed, skipping %d", q.Name, q.Steps[i].Label(i), resp.Skipped)
+			break
+		}
+	}
+	resp.Completed = len(resp.Steps) == len(q.Steps) && resp.Steps[len(resp.Steps)-1].Error == ""
+	if len(vars) > 0 {
+		resp.Vars = vars
+	}
+	return jsonResult(resp)
+}
+
+// runSequenceStep expands placeholders in the step's args, sends it, checks the
+// status and stores the extracted values in vars.
+func (m *mcpServer) runSequenceStep(ctx context.Context, i int, step *sequenceStep, vars map[string]string) protocol.SequenceStepResult {
+	result := protocol.SequenceStepResult{Step: i + 1, Name: step.Name}
+
+	args, _ := expandSequenceValue(step.Args, vars).(map[string]interface{})
+	if undefined := placeholderNames(args); len(undefined) > 0 {
+		envName, _ := args["env"].(string)
+		_, envVars, err := m.service.envs.Resolve(envName)
+		if err != nil {
+			result.Error = err.Error()
+			return result
+		}
+		undefined = slices.DeleteFunc(undefined, func(name string) bool {
+			_, ok := envVars[name]
+			return ok
+		})
+		if len(undefined) > 0 {
+			result.Error = "undefined variables: " + strings.Join(undefined, ", ") + " (set them in vars or extract them in an earlier step)"
+			return result
+		}
+	}
+
+	var handler func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error)
+	switch step.Tool {
+	case "request_send":
+		handler = m.handleRequestSend
+	case "request_craft":
+		handler = m.handleRequestCraft
+	default:
+		handler = m.handleReplaySend
+	}
+	toolResult, err := handler(ctx, mcp.CallToolRequest{
+		Params: mcp.CallToolParams{Name: step.Tool, Arguments: args},
+	})
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	text := resultText(toolResult)
+	if toolResult.IsError {
+		result.Error = text
+		return result
+	}
+	var sent protocol.ReplaySendResponse
+	if err := json.Unmarshal([]byte(text), &sent); err != nil {
+		result.Error = "unexpected " + step.Tool + " output: " + err.Error()
+		return result
+	}
+	result.ReplayID, result.Status, result.RespSize, result.Duration = sent.ReplayID, sent.Status, sent.RespSize, sent.Duration
+	entry, ok := m.service.requestStore.Get(sent.ReplayID)
+	if !ok {
+		result.Error = "replay " + sent.ReplayID + " not found"
+		return result
+	}
+	result.Method, result.URL = replayURL(entry)
+
+	if step.expectFilter != nil && !step.expectFilter.Matches(sent.Status) {
+		result.Error = fmt.Sprintf("status %d does not match expect %s", sent.Status, step.Expect)
+		return result
+	}
+	what := step.Label(i) + " response"
+	for _, name := range slices.Sorted(maps.Keys(step.Extract)) {
+		value, err := step.Extract[name].ExtractFrom(what, entry.Headers, entry.Body)
+		if err != nil {
+			result.Error = fmt.Sprintf("extract %s: %v (status %d)", name, err, sent.Status)
+			return result
+		}
+		vars[name] = value
+		if result.Extracted == nil {
+			result.Extracted = make(map[string]string, len(step.Extract))
+		}
+		result.Extracted[name] = value
+	}
+	return result
+}
+
+func sequenceListResponse(sequences []*sequence) protocol.SequenceListResponse {
+	resp := protocol.SequenceListResponse{Sequences: make([]protocol.SequenceInfo, 0, len(sequences))}
+	for _, q := range sequences {
+		info := protocol.SequenceInfo{Name: q.Name, Inputs: q.Inputs(), Steps: make([]protocol.SequenceStepInfo, len(q.Steps))}
+		for i, st := range q.Steps {
+			step := protocol.SequenceStepInfo{Name: st.Name, Tool: st.Tool, Source: sequenceStepSource(st.Args), Expect: st.Expect}
+			for name, e := range st.Extract {
+				if step.Extract == nil {
+					step.Extract = make(map[string]string, len(st.Extract))
+				}
+				step.Extract[name] = e.String()
+			}
+			info.Steps[i] = step
+		}
+		resp.Sequences = append(resp.Sequences, info)
+	}
+	return resp
+}
+
+// sequenceStepSource describes where a step's request comes from.
+func sequenceStepSource(args map[string]interface{}) string {
+	for _, key := range []string{"flow_id", "replay_id", "url"} {
+		if v, _ := args[key].(string); v != "" {
+			return v
+		}
+	}
+	return "raw request"
+}
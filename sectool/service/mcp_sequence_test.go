@@ -0,0 +1,139 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/go-harden/llm-security-toolbox/sectool/protocol"
+)
+
+func TestMCP_Sequence(t *testing.T) {
+	t.Parallel()
+
+	srv, mcpClient, mockMCP, _, _ := setupMCPServerWithMock(t)
+	mockMCP.AddProxyEntry(
+		"POST /api/reset HTTP/1.1\r\nHost: app.example.com\r\nContent-Type: application/json\r\nContent-Length: 2\r\n\r\n{}",
+		"HTTP/1.1 200 OK\r\n\r\n",
+		"",
+	)
+
+	list := CallMCPToolJSONOK[protocol.SequenceListResponse](t, mcpClient, "sequence_set", map[string]interface{}{
+		"name": "reset",
+		"steps": []interface{}{
+			map[string]interface{}{
+				"name":    "request",
+				"args":    map[string]interface{}{"flow_id": "last", "set_json": map[string]interface{}{"email": "{{email}}"}},
+				"extract": map[string]interface{}{"token": "json:data.token"},
+				"expect":  "200",
+			},
+			map[string]interface{}{
+				"tool": "request_send",
+				"args": map[string]interface{}{
+					"url":     "https://app.example.com/api/reset/confirm?token={{token}}",
+					"method":  "POST",
+					"headers": map[string]interface{}{"X-Reset-Token": "{{ token }}"},
+				},
+				"expect": "2xx",
+			},
+		},
+	})
+	require.Len(t, list.Sequences, 1)
+	info := list.Sequences[0]
+	assert.Equal(t, []string{"email"}, info.Inputs)
+	require.Len(t, info.Steps, 2)
+	assert.Equal(t, protocol.SequenceStepInfo{Name: "request", Tool: "replay_send", Source: "last", Extract: map[string]string{"token": "json:data.token"}, Expect: "200"}, info.Steps[0])
+	assert.Equal(t, "https://app.example.com/api/reset/confirm?token={{token}}", info.Steps[1].Source)
+
+	t.Run("run", func(t *testing.T) {
+		mockMCP.SetSendResponse("HttpRequestResponse{httpRequest=POST /api/reset HTTP/1.1, httpResponse=HTTP/1.1 200 OK\r\nContent-Type: application/json\r\n\r\n{\"data\":{\"token\":\"t0k3n\"}}}")
+		mockMCP.SetSendResponse("HttpRequestResponse{httpRequest=POST /api/reset/confirm HTTP/1.1, httpResponse=HTTP/1.1 204 No Content\r\n\r\n}")
+
+		resp := CallMCPToolJSONOK[protocol.SequenceRunResponse](t, mcpClient, "sequence_run", map[string]interface{}{
+			"name": "reset",
+			"vars": map[string]interface{}{"email": "victim@example.com"},
+		})
+		assert.True(t, resp.Completed)
+		assert.Zero(t, resp.Skipped)
+		require.Len(t, resp.Steps, 2)
+		assert.Equal(t, 200, resp.Steps[0].Status)
+		assert.Equal(t, map[string]string{"token": "t0k3n"}, resp.Steps[0].Extracted)
+		assert.Equal(t, 204, resp.Steps[1].Status)
+		assert.Equal(t, "POST", resp.Steps[1].Method)
+		assert.Equal(t, map[string]string{"email": "victim@example.com", "token": "t0k3n"}, resp.Vars)
+
+		first, ok := srv.requestStore.Get(resp.Steps[0].ReplayID)
+		require.True(t, ok)
+		assert.Contains(t, string(first.Request), `"email":"victim@example.com"`)
+		second, ok := srv.requestStore.Get(resp.Steps[1].ReplayID)
+		require.True(t, ok)
+		assert.Contains(t, string(second.Request), "/api/reset/confirm?token=t0k3n HTTP/1.1\r\n")
+		assert.Contains(t, string(second.Request), "X-Reset-Token: t0k3n\r\n")
+	})
+
+	t.Run("stops_on_failure", func(t *testing.T) {
+		mockMCP.SetSendResponse("HttpRequestResponse{httpRequest=POST /api/reset HTTP/1.1, httpResponse=HTTP/1.1 200 OK\r\nContent-Type: application/json\r\n\r\n{\"error\":\"unknown email\"}}")
+
+		resp := CallMCPToolJSONOK[protocol.SequenceRunResponse](t, mcpClient, "sequence_run", map[string]interface{}{
+			"name": "reset",
+			"vars": map[string]interface{}{"email": "nobody@example.com"},
+		})
+		assert.False(t, resp.Completed)
+		assert.Equal(t, 1, resp.Skipped)
+		require.Len(t, resp.Steps, 1)
+		assert.Contains(t, resp.Steps[0].Error, "extract token: JSON path data.token not found in step 1 (request) response")
+		assert.NotEmpty(t, resp.Steps[0].ReplayID)
+	})
+
+	t.Run("expect", func(t *testing.T) {
+		mockMCP.SetSendResponse("HttpRequestResponse{httpRequest=POST /api/reset HTTP/1.1, httpResponse=HTTP/1.1 429 Too Many Requests\r\n\r\n}")
+
+		resp := CallMCPToolJSONOK[protocol.SequenceRunResponse](t, mcpClient, "sequence_run", map[string]interface{}{
+			"name": "reset",
+			"vars": map[string]interface{}{"email": "victim@example.com"},
+		})
+		require.Len(t, resp.Steps, 1)
+		assert.Equal(t, "status 429 does not match expect 200", resp.Steps[0].Error)
+	})
+
+	t.Run("undefined_input", func(t *testing.T) {
+		resp := CallMCPToolJSONOK[protocol.SequenceRunResponse](t, mcpClient, "sequence_run", map[string]interface{}{"name": "reset"})
+		require.Len(t, resp.Steps, 1)
+		assert.Equal(t, "undefined variables: email (set them in vars or extract them in an earlier step)", resp.Steps[0].Error)
+		assert.Empty(t, resp.Steps[0].ReplayID)
+	})
+
+	t.Run("validation", func(t *testing.T) {
+		tests := []struct {
+			name   string
+			args   map[string]interface{}
+			errMsg string
+		}{
+			{"no_steps", map[string]interface{}{"name": "x"}, "steps is required"},
+			{"bad_tool", map[string]interface{}{"name": "x", "steps": []interface{}{
+				map[string]interface{}{"tool": "proxy_poll", "args": map[string]interface{}{}},
+			}}, "step 1: tool must be one of replay_send, request_send, request_craft"},
+			{"no_source", map[string]interface{}{"name": "x", "steps": []interface{}{
+				map[string]interface{}{"name": "login", "args": map[string]interface{}{"path": "/"}},
+			}}, "step 1 (login): replay_send args need exactly one of flow_id or replay_id"},
+			{"bad_extract", map[string]interface{}{"name": "x", "steps": []interface{}{
+				map[string]interface{}{"args": map[string]interface{}{"url": "https://a.test/"}, "tool": "request_send", "extract": map[string]interface{}{"t": "xpath://a"}},
+			}}, `extract t: unknown extraction "xpath"`},
+			{"bad_expect", map[string]interface{}{"name": "x", "steps": []interface{}{
+				map[string]interface{}{"args": map[string]interface{}{"flow_id": "last"}, "expect": "ok"},
+			}}, `invalid expect "ok"`},
+		}
+		for _, tc := range tests {
+			t.Run(tc.name, func(t *testing.T) {
+				result := CallMCPTool(t, mcpClient, "sequence_set", tc.args)
+				assert.True(t, result.IsError)
+				assert.Contains(t, ExtractMCPText(t, result), tc.errMsg)
+			})
+		}
+
+		result := CallMCPTool(t, mcpClient, "sequence_run", map[string]interface{}{"name": "checkout"})
+		assert.True(t, result.IsError)
+		assert.Contains(t, ExtractMCPText(t, result), `unknown sequence "checkout" (available: reset)`)
+	})
+}
@@ -8,6 +8,8 @@ import (
 	"log"
 	"net"
 	"net/http"
+	"strings"
+	"sync"
 	"sync/atomic"
 
 	"github.com/mark3labs/mcp-go/mcp"
@@ -19,7 +21,7 @@ import (
 // mcpServer wraps the MCP server and its dependencies.
 type mcpServer struct {
 	server           *server.MCPServer
-	sseServer        *server.SSEServer
+	sseServer        *resumableSSE
 	streamableServer *server.StreamableHTTPServer
 	httpServer       *http.Server
 	listener         net.Listener
@@ -30,34 +32,61 @@ type mcpServer struct {
 	// "none"        - no workflow, all tools available immediately
 	// "explore"     - explore instructions in server description, all tools
 	// "test-report" - test-report instructions, no crawl tools
+	// "api", "llm-app", "mobile-backend" - engagement-specific instructions, no crawl tools
 	workflowMode        string
 	workflowInitialized atomic.Bool
+	workflowTask        atomic.Value // string task chosen via the workflow tool
+
+	// guides are workflow task -> instructions, built-in plus custom guide files
+	guides map[string]string
+
+	// gatedTools are capability -> tools listed only while the HTTP backend supports them
+	gatedMu    sync.Mutex
+	gatedTools map[string][]server.ServerTool
 }
 
 // newMCPServer creates a new MCP server instance.
 func newMCPServer(svc *Server, workflowMode string) *mcpServer {
+	hooks := &server.Hooks{}
+	hooks.AddOnUnregisterSession(func(_ context.Context, session server.ClientSession) {
+		svc.clients.Remove("session:" + session.SessionID())
+	})
 	opts := []server.ServerOption{
-		server.WithToolCapabilities(false),
+		server.WithToolCapabilities(true), // tools/list_changed when backend capabilities change
 		server.WithLogging(),
+		server.WithHooks(hooks),
+	}
+
+	guides := loadGuides(guideDirs(svc.configPath, svc.projectDir)...)
+	vars := svc.guideVars()
+	for task, content := range guides {
+		rendered, missing := renderGuide(content, vars)
+		if len(missing) > 0 {
+			log.Printf("guides: %s has unset variables %v (set with `sectool config set guide_vars.<name> <value>`)", task, missing)
+		}
+		guides[task] = rendered
 	}
 
 	// Add instructions based on workflow mode
 	switch workflowMode {
-	case WorkflowModeExplore:
-		opts = append(opts, server.WithInstructions(workflowExploreContent))
-	case WorkflowModeTestReport:
-		opts = append(opts, server.WithInstructions(workflowTestReportContent))
+	case WorkflowModeExplore, WorkflowModeTestReport, WorkflowModeAPI, WorkflowModeLLMApp, WorkflowModeMobile:
+		opts = append(opts, server.WithInstructions(guides[workflowMode]))
 	}
 
 	mcpSrv := server.NewMCPServer("sectool", config.Version, opts...)
+	mcpSrv.EnableSampling() // reflection_check asks the client model to adjudicate ambiguous evidence
 
 	m := &mcpServer{
 		server:       mcpSrv,
 		service:      svc,
 		workflowMode: workflowMode,
+		guides:       guides,
 	}
 
 	m.registerTools()
+	if reporter, ok := svc.httpBackend.(CapabilityReporter); ok {
+		reporter.OnCapabilitiesChanged(m.syncGatedTools)
+	}
 
 	return m
 }
@@ -71,20 +100,23 @@ func (m *mcpServer) Start(port int) error {
 	}
 	m.listener = listener
 
-	// SSE server for legacy clients
-	m.sseServer = server.NewSSEServer(m.server,
-		server.WithBaseURL("http://"+addr),
-	)
+	// SSE server for legacy clients, resumable across dropped connections
+	m.sseServer = newResumableSSE(m.server)
 
-	// Streamable HTTP server for modern clients
+	// Streamable HTTP server for modern clients. Sessions are stateful so per-client
+	// state can be keyed by Mcp-Session-Id. The CLI connects here, so only here may a
+	// request carry the out of scope confirmation.
 	m.streamableServer = server.NewStreamableHTTPServer(m.server,
-		server.WithStateLess(true),
+		server.WithHTTPContextFunc(func(ctx context.Context, r *http.Request) context.Context {
+			return m.service.guard.withConfirmation(withClientName(ctx, r), r)
+		}),
 	)
 
 	mux := http.NewServeMux()
 	mux.Handle("/mcp", m.streamableServer)
 	mux.Handle("/sse", m.sseServer)
 	mux.Handle("/sse/", m.sseServer)
+	mux.Handle(sseMessagePath, m.sseServer)
 
 	m.httpServer = &http.Server{Handler: mux}
 
@@ -107,13 +139,13 @@ func (m *mcpServer) Addr() string {
 // Close stops the MCP server.
 func (m *mcpServer) Close(ctx context.Context) error {
 	var errs []error
-	if m.httpServer != nil {
-		if err := m.httpServer.Shutdown(ctx); err != nil {
+	if m.sseServer != nil { // end open streams first so the HTTP shutdown doesn't wait on them
+		if err := m.sseServer.Shutdown(ctx); err != nil {
 			errs = append(errs, err)
 		}
 	}
-	if m.sseServer != nil {
-		if err := m.sseServer.Shutdown(ctx); err != nil {
+	if m.httpServer != nil {
+		if err := m.httpServer.Shutdown(ctx); err != nil {
 			errs = append(errs, err)
 		}
 	}
@@ -132,65 +164,197 @@ func (m *mcpServer) registerTools() {
 		m.addProxyTools()
 		m.addReplayTools()
 		m.addOastTools()
+		m.addScanTools()
 		m.addEncodeTools()
 		m.addCrawlTools()
-	case WorkflowModeTestReport:
+	case WorkflowModeTestReport, WorkflowModeAPI, WorkflowModeLLMApp, WorkflowModeMobile:
 		m.addProxyTools()
 		m.addReplayTools()
 		m.addOastTools()
+		m.addScanTools()
 		m.addEncodeTools()
 		// crawl tools excluded
 	default: // Empty (default) workflowMode: require workflow tool call first, all tools registered
-		m.server.AddTool(m.workflowTool(), m.handleWorkflow)
+		m.addTool(m.workflowTool(), m.handleWorkflow)
 		m.addProxyTools()
 		m.addReplayTools()
 		m.addOastTools()
+		m.addScanTools()
 		m.addEncodeTools()
 		m.addCrawlTools()
 	}
+	m.addTool(m.checklistGetTool(), m.handleChecklistGet)
+	m.addTool(m.checklistMarkTool(), m.handleChecklistMark)
+	m.addTool(m.noteAddTool(), m.handleNoteAdd)
+	m.addTool(m.noteListTool(), m.handleNoteList)
+	m.addTool(m.noteSearchTool(), m.handleNoteSearch)
+	m.addTool(m.findingAddTool(), m.handleFindingAdd)
+	m.addTool(m.findingListTool(), m.handleFindingList)
+	m.addTool(m.findingGetTool(), m.handleFindingGet)
+	m.addTool(m.exportTool(), m.handleExport)
+	m.addTool(m.statusTool(), m.handleStatus)
+	m.addTool(m.scopeGetTool(), m.handleScopeGet)
+	m.addTool(m.scopeSetTool(), m.handleScopeSet)
+	m.addTool(m.envSetTool(), m.handleEnvSet)
+	m.addTool(m.envListTool(), m.handleEnvList)
+	m.addTool(m.sessionSetTool(), m.handleSessionSet)
+	m.addTool(m.sessionListTool(), m.handleSessionList)
+	m.addTool(m.csrfSetTool(), m.handleCSRFSet)
+	m.addTool(m.cookieJarSetTool(), m.handleCookieJarSet)
+	m.addTool(m.cookieJarListTool(), m.handleCookieJarList)
+	m.addTool(m.sequenceSetTool(), m.handleSequenceSet)
+	m.addTool(m.sequenceListTool(), m.handleSequenceList)
+	m.addTool(m.sequenceRunTool(), m.handleSequenceRun)
+	m.addTool(m.identitySetTool(), m.handleIdentitySet)
+	m.addTool(m.identityListTool(), m.handleIdentityList)
+	m.addTool(m.authzMatrixTool(), m.handleAuthzMatrix)
+	m.addTool(m.batchTool(), m.handleBatch)
+	// output_get chunks on its own, so it skips max_output_bytes shaping
+	m.server.AddTool(m.outputGetTool(), m.handleOutputGet)
+	m.addResources()
+	m.addPrompts()
 }
 
 func (m *mcpServer) addProxyTools() {
-	m.server.AddTool(m.proxyPollTool(), m.handleProxyPoll)
-	m.server.AddTool(m.proxyGetTool(), m.handleProxyGet)
-	m.server.AddTool(m.proxyRuleListTool(), m.handleProxyRuleList)
-	m.server.AddTool(m.proxyRuleAddTool(), m.handleProxyRuleAdd)
-	m.server.AddTool(m.proxyRuleUpdateTool(), m.handleProxyRuleUpdate)
-	m.server.AddTool(m.proxyRuleDeleteTool(), m.handleProxyRuleDelete)
+	m.addTool(m.proxyPollTool(), m.handleProxyPoll)
+	m.addTool(m.proxyGetTool(), m.handleProxyGet)
+	m.addTool(m.proxyImportTool(), m.handleProxyImport)
+	m.addTool(m.proxyFindingsTool(), m.handleProxyFindings)
+	m.addTool(m.analyzeHeadersTool(), m.handleAnalyzeHeaders)
+	m.addTool(m.csrfAnalyzeTool(), m.handleCSRFAnalyze)
+	m.addTool(m.wsListTool(), m.handleWSList)
+	m.addTool(m.flowTagTool(), m.handleFlowTag)
+	m.addTool(m.flowListTool(), m.handleFlowList)
+	m.addGatedTools(CapabilityRules,
+		m.serverTool(m.proxyRuleListTool(), m.handleProxyRuleList),
+		m.serverTool(m.proxyRuleAddTool(), m.handleProxyRuleAdd),
+		m.serverTool(m.proxyRuleUpdateTool(), m.handleProxyRuleUpdate),
+		m.serverTool(m.proxyRuleToggleTool(), m.handleProxyRuleToggle),
+		m.serverTool(m.proxyRuleMoveTool(), m.handleProxyRuleMove),
+		m.serverTool(m.proxyRuleTestTool(), m.handleProxyRuleTest),
+		m.serverTool(m.proxyRuleDeleteTool(), m.handleProxyRuleDelete),
+	)
+}
+
+// addGatedTools registers tools that are listed only while the HTTP backend reports capability.
+func (m *mcpServer) addGatedTools(capability string, tools ...server.ServerTool) {
+	m.gatedMu.Lock()
+	defer m.gatedMu.Unlock()
+
+	if m.gatedTools == nil {
+		m.gatedTools = make(map[string][]server.ServerTool)
+	}
+	m.gatedTools[capability] = append(m.gatedTools[capability], tools...)
+	if hasCapability(m.service.httpBackend, capability) {
+		m.server.AddTools(tools...)
+	}
+}
+
+// syncGatedTools adds or removes gated tools to match current backend capabilities.
+// The MCP server notifies clients with tools/list_changed.
+func (m *mcpServer) syncGatedTools() {
+	m.gatedMu.Lock()
+	defer m.gatedMu.Unlock()
+
+	for capability, tools := range m.gatedTools {
+		available := hasCapability(m.service.httpBackend, capability)
+		if registered := m.server.GetTool(tools[0].Tool.Name) != nil; available == registered {
+			continue
+		}
+		log.Printf("mcp: backend capability %s available=%v, updating tools", capability, available)
+		if available {
+			m.server.AddTools(tools...)
+		} else {
+			names := make([]string, len(tools))
+			for i, t := range tools {
+				names[i] = t.Tool.Name
+			}
+			m.server.DeleteTools(names...)
+		}
+	}
+}
+
+func (m *mcpServer) addScanTools() {
+	m.addGatedTools(CapabilityScanner,
+		m.serverTool(m.scanStartTool(), m.handleScanStart),
+		m.serverTool(m.scanStatusTool(), m.handleScanStatus),
+		m.serverTool(m.scanIssuesTool(), m.handleScanIssues),
+	)
 }
 
 func (m *mcpServer) addReplayTools() {
-	m.server.AddTool(m.replaySendTool(), m.handleReplaySend)
-	m.server.AddTool(m.replayGetTool(), m.handleReplayGet)
-	m.server.AddTool(m.requestSendTool(), m.handleRequestSend)
+	m.addTool(m.replaySendTool(), m.handleReplaySend)
+	m.addTool(m.replayGetTool(), m.handleReplayGet)
+	m.addTool(m.replayHistoryTool(), m.handleReplayHistory)
+	m.addTool(m.requestSendTool(), m.handleRequestSend)
+	m.addTool(m.requestCraftTool(), m.handleRequestCraft)
+	m.addTool(m.specImportTool(), m.handleSpecImport)
+	m.addTool(m.graphqlIntrospectTool(), m.handleGraphQLIntrospect)
+	m.addTool(m.grpcSchemaTool(), m.handleGRPCSchema)
+	m.addTool(m.grpcListTool(), m.handleGRPCList)
+	m.addTool(m.grpcGetTool(), m.handleGRPCGet)
+	m.addTool(m.grpcSendTool(), m.handleGRPCSend)
+	m.addTool(m.http2SendTool(), m.handleHTTP2Send)
+	m.addTool(m.rawSendTool(), m.handleRawSend)
+	m.addTool(m.tlsProbeTool(), m.handleTLSProbe)
+	m.addTool(m.replayDiffTool(), m.handleReplayDiff)
+	m.addTool(m.responseExtractTool(), m.handleResponseExtract)
+	m.addTool(m.wsSendTool(), m.handleWSSend)
+	m.addTool(m.reflectionCheckTool(), m.handleReflectionCheck)
+	m.addTool(m.paramDiscoverTool(), m.handleParamDiscover)
+	m.addTool(m.ssrfTestTool(), m.handleSSRFTest)
 }
 
 func (m *mcpServer) addOastTools() {
-	m.server.AddTool(m.oastCreateTool(), m.handleOastCreate)
-	m.server.AddTool(m.oastPollTool(), m.handleOastPoll)
-	m.server.AddTool(m.oastGetTool(), m.handleOastGet)
-	m.server.AddTool(m.oastListTool(), m.handleOastList)
-	m.server.AddTool(m.oastDeleteTool(), m.handleOastDelete)
+	m.addTool(m.oastCreateTool(), m.handleOastCreate)
+	m.addTool(m.oastPollTool(), m.handleOastPoll)
+	m.addTool(m.oastGetTool(), m.handleOastGet)
+	m.addTool(m.oastListTool(), m.handleOastList)
+	m.addTool(m.oastExpectTool(), m.handleOastExpect)
+	m.addTool(m.oastDeleteTool(), m.handleOastDelete)
 }
 
 func (m *mcpServer) addEncodeTools() {
-	m.server.AddTool(m.encodeURLTool(), m.handleEncodeURL)
-	m.server.AddTool(m.encodeBase64Tool(), m.handleEncodeBase64)
-	m.server.AddTool(m.encodeHTMLTool(), m.handleEncodeHTML)
+	m.addTool(m.encodeURLTool(), m.handleEncodeURL)
+	m.addTool(m.encodeBase64Tool(), m.handleEncodeBase64)
+	m.addTool(m.encodeHTMLTool(), m.handleEncodeHTML)
+	m.addTool(m.encodeTool(), m.handleEncode)
+	m.addTool(m.hashTool(), m.handleHash)
+	m.addTool(m.hmacTool(), m.handleHMAC)
+	m.addTool(m.payloadTransformTool(), m.handlePayloadTransform)
+	m.addTool(m.mutateTool(), m.handleMutate)
+	m.addTool(m.payloadsListTool(), m.handlePayloadsList)
+	m.addTool(m.payloadsGetTool(), m.handlePayloadsGet)
+	m.addTool(m.jwtCrackTool(), m.handleJWTCrack)
+	m.addTool(m.jwtDecodeTool(), m.handleJWTDecode)
+	m.addTool(m.jwtForgeTool(), m.handleJWTForge)
+	m.addTool(m.samlDecodeTool(), m.handleSAMLDecode)
+	m.addTool(m.samlEncodeTool(), m.handleSAMLEncode)
 }
 
 func (m *mcpServer) addCrawlTools() {
-	m.server.AddTool(m.crawlCreateTool(), m.handleCrawlCreate)
-	m.server.AddTool(m.crawlSeedTool(), m.handleCrawlSeed)
-	m.server.AddTool(m.crawlStatusTool(), m.handleCrawlStatus)
-	m.server.AddTool(m.crawlPollTool(), m.handleCrawlPoll)
-	m.server.AddTool(m.crawlSessionsTool(), m.handleCrawlSessions)
-	m.server.AddTool(m.crawlStopTool(), m.handleCrawlStop)
-	m.server.AddTool(m.crawlGetTool(), m.handleCrawlGet)
+	m.addTool(m.crawlCreateTool(), m.handleCrawlCreate)
+	m.addTool(m.crawlSeedTool(), m.handleCrawlSeed)
+	m.addTool(m.crawlStatusTool(), m.handleCrawlStatus)
+	m.addTool(m.crawlPollTool(), m.handleCrawlPoll)
+	m.addTool(m.crawlResultsTool(), m.handleCrawlResults)
+	m.addTool(m.crawlSessionsTool(), m.handleCrawlSessions)
+	m.addTool(m.crawlStopTool(), m.handleCrawlStop)
+	m.addTool(m.crawlGetTool(), m.handleCrawlGet)
+	m.addTool(m.dirbustTool(), m.handleDirbust)
+	m.addTool(m.vhostDiscoverTool(), m.handleVhostDiscover)
+	m.addTool(m.subdomainEnumTool(), m.handleSubdomainEnum)
+	m.addTool(m.dnsQueryTool(), m.handleDNSQuery)
+	m.addTool(m.portScanTool(), m.handlePortScan)
 }
 
 const workflowNotInitializedError = "call workflow first with the relevant task, use 'explore' if there is no better fit"
 
+// client returns the per-client state of the caller.
+func (m *mcpServer) client(ctx context.Context) *clientState {
+	return m.service.clients.Get(clientKey(ctx))
+}
+
 // requireWorkflow returns an error result if workflow is required but not initialized, nil otherwise.
 // Only enforced when workflowMode is empty (default behavior).
 func (m *mcpServer) requireWorkflow() *mcp.CallToolResult {
@@ -201,35 +365,45 @@ func (m *mcpServer) requireWorkflow() *mcp.CallToolResult {
 }
 
 func (m *mcpServer) workflowTool() mcp.Tool {
-	return mcp.NewTool("workflow",
-		mcp.WithDescription(`Initialize sectool workflow - MUST be called before using other tools.
+	desc := `Initialize sectool workflow - MUST be called before using other tools.
 
 Select the task that best matches your objective:
 - test-report: Validating a specific vulnerability report
 - explore: Security testing and vulnerability discovery (default if unsure)
+- api: Testing APIs/back-ends directly, without a browser front-end
+- llm-app: Prompt injection and model-boundary testing of LLM-backed features
+- mobile-backend: Testing the back-end of a mobile app proxied from a device`
+	builtin := builtinGuides()
+	for _, task := range guideTasks(m.guides) {
+		if _, ok := builtin[task]; !ok {
+			desc += "\n- " + task + ": " + guideTitle(m.guides[task], "Custom workflow")
+		}
+	}
+	desc += `
 
-Returns necessary instructions on tool use and user interaction  strategies.`),
-		mcp.WithString("task", mcp.Required(), mcp.Description("Workflow type: 'test-report' for validating vulnerability reports, 'explore' for security testing/discovery")),
+Returns necessary instructions on tool use and user interaction  strategies.`
+
+	return mcp.NewTool("workflow",
+		mcp.WithDescription(desc),
+		mcp.WithString("task", mcp.Required(), mcp.Description("Workflow type: 'test-report' for validating vulnerability reports, 'explore' for security testing/discovery, or another listed task")),
+		annotateLocalChange,
 	)
 }
 
 func (m *mcpServer) handleWorkflow(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	task := req.GetString("task", WorkflowModeExplore)
 
-	var content string
-	switch task {
-	case WorkflowModeExplore:
-		content = workflowExploreContent
-	case WorkflowModeTestReport:
-		content = workflowTestReportContent
-	case WorkflowModeCLI:
+	if task == WorkflowModeCLI {
 		m.workflowInitialized.Store(true)
 		return mcp.NewToolResultText("Tools enabled for CLI usage"), nil
-	default:
-		return errorResult("invalid task: use 'explore' or 'test-report'"), nil
+	}
+	content, ok := m.guides[task]
+	if !ok {
+		return errorResult("invalid task: use one of " + strings.Join(guideTasks(m.guides), ", ")), nil
 	}
 
 	m.workflowInitialized.Store(true)
+	m.workflowTask.Store(task)
 	log.Printf("mcp/workflow: initialized with task=%s", task)
 
 	return mcp.NewToolResultText(content), nil
@@ -288,6 +462,136 @@ Collaborate with the user to validate a reported security vulnerability.
 6. Discuss results and additional testing permutations that should be considered
 `
 
+var workflowAPIContent = `# API Security Testing Workflow
+
+Collaborate with the user to test an API or back-end service directly, without relying on a browser front-end.
+
+## Collaboration Model
+
+**Your role:** Map endpoints and parameters from captured traffic, craft requests from scratch, test authorization and input handling, monitor OAST interactions.
+
+**User's role:** Provide API documentation or client traffic, credentials/tokens for each role, application context, answer questions to help.
+
+**Key principle:** Work collaboratively - don't assume, ask when uncertain about scope, rate limits, or which roles and tenants are in play.
+
+## Common Workflow
+
+1. User provides base URL, docs, and credentials - confirm scope and any destructive endpoints to avoid
+2. Capture baseline traffic (user's client, scripts, or docs) and review with proxy_poll / proxy_get
+3. Build an endpoint inventory: methods, parameters, object IDs, auth mechanism
+4. Test authorization first: replay_send each request with another role's token or another tenant's IDs (IDOR, BOLA, function-level access)
+5. Test input handling: type confusion, mass assignment (set_json extra fields), injection, SSRF via URL parameters with oast_create
+6. request_send for endpoints not yet seen in traffic; batch for multi-step flows
+7. Report findings with the replay_id evidence, discuss next steps
+
+## Tool Emphasis
+
+- replay_send with set_json/remove_json and add_headers for auth swaps
+- request_send to craft requests for undocumented or guessed endpoints
+- proxy_rule_add to inject or swap headers across the user's client traffic
+- Crawl tools are not available; APIs rarely expose navigable links
+`
+
+var workflowLLMAppContent = `# LLM Application Testing Workflow
+
+Collaborate with the user to test an LLM-backed feature for prompt injection and model-boundary weaknesses.
+
+## Collaboration Model
+
+**Your role:** Identify where user-controlled or third-party content reaches the model, craft injection payloads, check whether the model's output crosses trust boundaries (tool calls, rendered HTML, data access), monitor OAST interactions.
+
+**User's role:** Drive the chat or feature in the browser, share the intended behavior and guardrails, provide accounts for cross-user checks, answer questions to help.
+
+**Key principle:** Work collaboratively - model output is nondeterministic, so repeat promising tests and agree with the user on what counts as a successful bypass.
+
+## Common Workflow
+
+1. User describes the feature, its tools/integrations, and what the model must never do
+2. Capture a normal exchange with proxy_poll and locate the prompt, history, and any retrieved context in the request
+3. Direct injection: replay_send with modified user messages (instruction override, role confusion, encoding tricks)
+4. Indirect injection: plant payloads in content the model later reads (profile fields, documents, URLs) and trigger retrieval
+5. Impact: data exfiltration via rendered links/images (use oast_create domains), unauthorized tool actions, system prompt or other users' data disclosure
+6. Check rendering of model output for XSS with reflection_check
+7. Repeat successful payloads several times and report the success rate with replay_id evidence
+
+## Tool Emphasis
+
+- replay_send with set_json to edit message arrays and parameters
+- oast_create / oast_poll to detect out-of-band fetches triggered by model output
+- Streaming responses may need replay_get to see the complete body
+- Crawl tools are not available
+`
+
+var workflowMobileContent = `# Mobile Back-end Testing Workflow
+
+Collaborate with the user to test the back-end APIs used by a mobile application.
+
+## Collaboration Model
+
+**Your role:** Analyze the app's API traffic, identify trust the back-end places in the client, replay and modify requests, monitor OAST interactions.
+
+**User's role:** Configure the device to use the proxy (and trust its CA), operate the app, handle certificate pinning if needed, provide accounts, answer questions to help.
+
+**Key principle:** Work collaboratively - don't assume, ask when uncertain about scope, device setup, or app behavior.
+
+## Common Workflow
+
+1. Confirm the device's traffic reaches the proxy with proxy_poll; if nothing arrives, help the user troubleshoot proxy settings or pinning
+2. User exercises the app's features; map the API hosts, auth tokens, and client-supplied identifiers
+3. Test checks that only the client enforces: premium/feature flags, price or quantity fields, device or platform headers
+4. Test authorization with other accounts' IDs and tokens; look for legacy or versioned endpoints (/v1/ vs /v2/)
+5. Test token handling: refresh, revocation after logout, reuse across devices
+6. Report findings with replay_id evidence, discuss next steps
+
+## Tool Emphasis
+
+- proxy_poll filtered by host to separate app traffic from device background noise
+- replay_send with add_headers/set_json to tamper with client-enforced values
+- proxy_rule_add to modify the app's live traffic when its UI must be used
+- Crawl tools are not available; mobile APIs are not link-driven
+`
+
+// Tool annotation presets. mcp.NewTool defaults to a destructive, open-world tool, so every
+// tool sets one of these to let clients apply their own confirmation policy.
+var (
+	// annotateReadOnly marks tools that only read local state.
+	annotateReadOnly = mcp.WithToolAnnotation(mcp.ToolAnnotation{
+		ReadOnlyHint:    mcp.ToBoolPtr(true),
+		DestructiveHint: mcp.ToBoolPtr(false),
+		IdempotentHint:  mcp.ToBoolPtr(true),
+		OpenWorldHint:   mcp.ToBoolPtr(false),
+	})
+	// annotateLocalChange marks tools that add or stop sectool state without removing anything.
+	annotateLocalChange = mcp.WithToolAnnotation(mcp.ToolAnnotation{
+		ReadOnlyHint:    mcp.ToBoolPtr(false),
+		DestructiveHint: mcp.ToBoolPtr(false),
+		IdempotentHint:  mcp.ToBoolPtr(false),
+		OpenWorldHint:   mcp.ToBoolPtr(false),
+	})
+	// annotateDestructive marks tools that modify or delete proxy rules and sessions.
+	annotateDestructive = mcp.WithToolAnnotation(mcp.ToolAnnotation{
+		ReadOnlyHint:    mcp.ToBoolPtr(false),
+		DestructiveHint: mcp.ToBoolPtr(true),
+		IdempotentHint:  mcp.ToBoolPtr(false),
+		OpenWorldHint:   mcp.ToBoolPtr(false),
+	})
+	// annotateSendsTraffic marks tools that send requests to target hosts.
+	annotateSendsTraffic = mcp.WithToolAnnotation(mcp.ToolAnnotation{
+		ReadOnlyHint:    mcp.ToBoolPtr(false),
+		DestructiveHint: mcp.ToBoolPtr(true),
+		IdempotentHint:  mcp.ToBoolPtr(false),
+		OpenWorldHint:   mcp.ToBoolPtr(true),
+	})
+	// annotateExternalLookup marks tools that query outside services (DNS, CT logs) without
+	// sending requests to target hosts.
+	annotateExternalLookup = mcp.WithToolAnnotation(mcp.ToolAnnotation{
+		ReadOnlyHint:    mcp.ToBoolPtr(true),
+		DestructiveHint: mcp.ToBoolPtr(false),
+		IdempotentHint:  mcp.ToBoolPtr(true),
+		OpenWorldHint:   mcp.ToBoolPtr(true),
+	})
+)
+
 func jsonResult(data interface{}) (*mcp.CallToolResult, error) {
 	b, err := json.MarshalIndent(data, "", "  ")
 	if err != nil {
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 	"testing"
 	"time"
 
@@ -28,6 +29,7 @@ func setupMCPServerWithMock(t *testing.T) (*Server, *mcpclient.Client, *TestMCPS
 		BurpMCPURL:   mockMCP.URL(),
 		MCPPort:      0, // Let OS pick a port
 		WorkflowMode: WorkflowModeNone,
+		ProjectDir:   t.TempDir(),
 	}, nil, mockOast, mockCrawler)
 	require.NoError(t, err)
 
@@ -80,28 +82,104 @@ func TestMCP_ListTools(t *testing.T) {
 	expectedTools := []string{
 		"proxy_poll",
 		"proxy_get",
+		"proxy_import",
+		"proxy_findings",
+		"analyze_headers",
+		"csrf_analyze",
+		"ws_list",
+		"flow_tag",
+		"flow_list",
 		"proxy_rule_list",
 		"proxy_rule_add",
 		"proxy_rule_update",
+		"proxy_rule_toggle",
+		"proxy_rule_move",
+		"proxy_rule_test",
 		"proxy_rule_delete",
 		"replay_send",
 		"replay_get",
+		"replay_history",
 		"request_send",
+		"request_craft",
+		"spec_import",
+		"graphql_introspect",
+		"grpc_schema",
+		"grpc_list",
+		"grpc_get",
+		"grpc_send",
+		"http2_send",
+		"raw_send",
+		"tls_probe",
+		"replay_diff",
+		"response_extract",
+		"ws_send",
+		"reflection_check",
+		"param_discover",
+		"ssrf_test",
 		"oast_create",
 		"oast_poll",
 		"oast_get",
 		"oast_list",
+		"oast_expect",
 		"oast_delete",
+		"scan_start",
+		"scan_status",
+		"scan_issues",
 		"encode_url",
 		"encode_base64",
 		"encode_html",
+		"encode",
+		"hash",
+		"hmac",
+		"payload_transform",
+		"mutate",
+		"payloads_list",
+		"payloads_get",
+		"jwt_crack",
+		"jwt_decode",
+		"jwt_forge",
+		"saml_decode",
+		"saml_encode",
 		"crawl_create",
 		"crawl_seed",
 		"crawl_status",
 		"crawl_poll",
+		"crawl_results",
 		"crawl_get",
 		"crawl_sessions",
 		"crawl_stop",
+		"dirbust",
+		"vhost_discover",
+		"subdomain_enum",
+		"dns_query",
+		"port_scan",
+		"output_get",
+		"checklist_get",
+		"checklist_mark",
+		"note_add",
+		"note_list",
+		"note_search",
+		"finding_add",
+		"finding_list",
+		"finding_get",
+		"export",
+		"status",
+		"scope_get",
+		"scope_set",
+		"env_set",
+		"env_list",
+		"session_set",
+		"session_list",
+		"csrf_set",
+		"cookie_jar_set",
+		"cookie_jar_list",
+		"sequence_set",
+		"sequence_list",
+		"sequence_run",
+		"identity_set",
+		"identity_list",
+		"authz_matrix",
+		"batch",
 	}
 
 	toolNames := make([]string, len(result.Tools))
@@ -114,6 +192,110 @@ func TestMCP_ListTools(t *testing.T) {
 	}
 }
 
+func TestMCP_GatedRuleTools(t *testing.T) {
+	t.Parallel()
+
+	srv, mcpClient, mockMCP, _, _ := setupMCPServerWithMock(t)
+
+	hasRuleTools := func() bool {
+		ctx, cancel := context.WithTimeout(t.Context(), 10*time.Second)
+		defer cancel()
+		result, err := mcpClient.ListTools(ctx, mcp.ListToolsRequest{})
+		require.NoError(t, err)
+		for _, tool := range result.Tools {
+			if tool.Name == "proxy_rule_add" {
+				return true
+			}
+		}
+		return false
+	}
+	require.True(t, hasRuleTools())
+
+	mockMCP.SetConfigEditingDisabled(true)
+	result := CallMCPTool(t, mcpClient, "proxy_rule_add", map[string]interface{}{
+		"type":  RuleTypeRequestHeader,
+		"match": "a",
+	})
+	assert.True(t, result.IsError)
+	assert.Eventually(t, func() bool { return !hasRuleTools() }, 5*time.Second, 20*time.Millisecond)
+
+	srv.httpBackend.(*BurpBackend).setRulesDisabled(false)
+	assert.Eventually(t, hasRuleTools, 5*time.Second, 20*time.Millisecond)
+}
+
+func TestMCP_GatedScanTools(t *testing.T) {
+	t.Parallel()
+
+	_, mcpClient, _, _, _ := setupMCPServerWithMock(t)
+	ctx, cancel := context.WithTimeout(t.Context(), 10*time.Second)
+	defer cancel()
+	result, err := mcpClient.ListTools(ctx, mcp.ListToolsRequest{})
+	require.NoError(t, err)
+	var names []string
+	for _, tool := range result.Tools {
+		names = append(names, tool.Name)
+	}
+	assert.Contains(t, names, "scan_start")
+
+	// The built-in proxy has no scanner, so the scan tools are not listed.
+	backend, err := NewGoProxyBackend(0, t.TempDir())
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = backend.Close() })
+	m := newMCPServer(&Server{httpBackend: backend}, WorkflowModeNone)
+	for _, name := range []string{"scan_start", "scan_status", "scan_issues"} {
+		assert.Nil(t, m.server.GetTool(name), name)
+	}
+	assert.NotNil(t, m.server.GetTool("proxy_rule_list"))
+}
+
+func TestMCP_ToolAnnotations(t *testing.T) {
+	t.Parallel()
+
+	_, mcpClient, _, _, _ := setupMCPServerWithMock(t)
+
+	ctx, cancel := context.WithTimeout(t.Context(), 10*time.Second)
+	t.Cleanup(cancel)
+
+	result, err := mcpClient.ListTools(ctx, mcp.ListToolsRequest{})
+	require.NoError(t, err)
+
+	tools := make(map[string]mcp.ToolAnnotation, len(result.Tools))
+	for _, tool := range result.Tools {
+		tools[tool.Name] = tool.Annotations
+	}
+
+	tests := []struct {
+		tool        string
+		readOnly    bool
+		destructive bool
+		openWorld   bool
+	}{
+		{tool: "proxy_poll", readOnly: true},
+		{tool: "replay_get", readOnly: true},
+		{tool: "csrf_analyze", readOnly: true},
+		{tool: "oast_create"},
+		{tool: "proxy_rule_add", destructive: true},
+		{tool: "oast_delete", destructive: true},
+		{tool: "replay_send", destructive: true, openWorld: true},
+		{tool: "crawl_create", destructive: true, openWorld: true},
+		{tool: "subdomain_enum", readOnly: true, openWorld: true},
+		{tool: "dns_query", readOnly: true, openWorld: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.tool, func(t *testing.T) {
+			ann, ok := tools[tc.tool]
+			require.True(t, ok)
+			require.NotNil(t, ann.ReadOnlyHint)
+			require.NotNil(t, ann.DestructiveHint)
+			require.NotNil(t, ann.OpenWorldHint)
+			assert.Equal(t, tc.readOnly, *ann.ReadOnlyHint)
+			assert.Equal(t, tc.destructive, *ann.DestructiveHint)
+			assert.Equal(t, tc.openWorld, *ann.OpenWorldHint)
+		})
+	}
+}
+
 const sinceLast = "last"
 
 type mockOastBackend struct {
@@ -150,7 +332,7 @@ func (b *mockOastBackend) CreateSession(ctx context.Context, label string) (*Oas
 	return info, nil
 }
 
-func (b *mockOastBackend) PollSession(ctx context.Context, idOrDomain string, since string, eventType string, wait time.Duration, limit int) (*OastPollResultInfo, error) {
+func (b *mockOastBackend) PollSession(ctx context.Context, idOrDomain string, since string, eventType string, subdomainContains string, wait time.Duration, limit int) (*OastPollResultInfo, error) {
 	id, err := b.resolveID(idOrDomain)
 	if err != nil {
 		return nil, err
@@ -176,6 +358,9 @@ func (b *mockOastBackend) PollSession(ctx context.Context, idOrDomain string, si
 		if eventType != "" && ev.Type != eventType {
 			continue
 		}
+		if !strings.Contains(strings.ToLower(ev.Subdomain), strings.ToLower(subdomainContains)) {
+			continue
+		}
 		filtered = append(filtered, ev)
 	}
 
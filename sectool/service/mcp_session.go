@@ -0,0 +1,156 @@
+package service
+
+import (
+	"context"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/go-harden/llm-security-toolbox/sectool/protocol"
+)
+
+// sessionDefaultRefreshOn are the replay statuses that trigger a new login by default.
+const sessionDefaultRefreshOn = "401,403"
+
+func (m *mcpServer) sessionSetTool() mcp.Tool {
+	return mcp.NewTool("session_set",
+		mcp.WithDescription(`Define a login session that keeps replays authenticated.
+
+The login recipe is a captured or crafted login request (flow_id, or replay_id from request_send/request_craft) plus one extraction rule for the token in its response.
+The login is sent immediately to validate the rule. replay_send with session=<name> then sets the inject header with the current token, and when a response status matches refresh_on (default 401,403) logs in again and resends once with the fresh token.
+Sessions are ephemeral and cleared on service restart.`),
+		mcp.WithString("name", mcp.Required(), mcp.Description("Session name (e.g., 'admin')")),
+		mcp.WithString("flow_id", mcp.Description("Login request flow_id, or "+recentRefUsage+" proxy entry (exclusive with replay_id)")),
+		mcp.WithString("replay_id", mcp.Description("Login request replay_id or label (exclusive with flow_id)")),
+		mcp.WithString("target", mcp.Description("Override the login destination (scheme+host[:port])")),
+		mcp.WithString("extract_json", mcp.Description("JSON path of the token in the login response body (e.g., 'access_token', 'data.token')")),
+		mcp.WithString("extract_header", mcp.Description("Response header holding the token (e.g., 'X-Auth-Token')")),
+		mcp.WithString("extract_cookie", mcp.Description("Cookie set by the login response (e.g., 'JSESSIONID')")),
+		mcp.WithString("extract_regex", mcp.Description("Regex over the login response headers and body; the first capture group is the token")),
+		mcp.WithString("inject", mcp.Description("Header set on replays, with {{token}} for the token (default: 'Cookie: <name>={{token}}' for extract_cookie, else 'Authorization: Bearer {{token}}'); Cookie values merge with existing cookies")),
+		mcp.WithString("refresh_on", mcp.Description("Replay statuses that trigger a new login (default: '401,403'; ranges like '4xx' allowed)")),
+		mcp.WithBoolean("delete", mcp.Description("Delete the session instead")),
+		annotateSendsTraffic,
+	)
+}
+
+func (m *mcpServer) sessionListTool() mcp.Tool {
+	return mcp.NewTool("session_list",
+		mcp.WithDescription("List login sessions with their current token, login count and last login error."),
+		annotateReadOnly,
+	)
+}
+
+func (m *mcpServer) handleSessionSet(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if err := m.requireWorkflow(); err != nil {
+		return err, nil
+	}
+
+	name := strings.TrimSpace(req.GetString("name", ""))
+	if name == "" {
+		return errorResult("name is required"), nil
+	}
+	if req.GetBool("delete", false) {
+		if !m.service.sessions.Delete(name) {
+			return errorResult("session " + name + " not found"), nil
+		}
+		log.Printf("mcp/session_set: deleted session %s", name)
+		return jsonResult(sessionListResponse(m.service.sessions.List()))
+	}
+
+	flowID, replayID := req.GetString("flow_id", ""), req.GetString("replay_id", "")
+	if (flowID == "") == (replayID == "") {
+		return errorResult("exactly one of flow_id or replay_id is required for the login request"), nil
+	}
+
+	var kind, expr string
+	for _, k := range []string{extractJSON, extractHeader, extractCookie, extractRegex} {
+		if v := req.GetString("extract_"+k, ""); v != "" {
+			if kind != "" {
+				return errorResult("set only one of extract_json, extract_header, extract_cookie or extract_regex"), nil
+			}
+			kind, expr = k, v
+		}
+	}
+	if kind == "" {
+		return errorResult("one of extract_json, extract_header, extract_cookie or extract_regex is required"), nil
+	}
+	extractor, err := newTokenExtractor(kind, expr)
+	if err != nil {
+		return errorResultFromErr("", err), nil
+	}
+
+	inject := req.GetString("inject", "")
+	if inject == "" {
+		inject = "Authorization: Bearer " + sessionTokenVar
+		if kind == extractCookie {
+			inject = "Cookie: " + expr + "=" + sessionTokenVar
+		}
+	} else if headerName, _, ok := strings.Cut(inject, ":"); !ok || strings.TrimSpace(headerName) == "" || !strings.Contains(inject, sessionTokenVar) {
+		return errorResult("inject must be a 'Name: value' header containing " + sessionTokenVar), nil
+	}
+
+	refreshOn := req.GetString("refresh_on", sessionDefaultRefreshOn)
+	filter := parseStatusFilter(refreshOn)
+	if filter.Empty() {
+		return errorResult("invalid refresh_on: expected status codes or ranges such as '401,403' or '4xx'"), nil
+	}
+
+	rawRequest, baseTarget, base, err := m.service.loadBaseRequest(ctx, flowID, replayID)
+	if err != nil {
+		return errorResultFromErr("", err), nil
+	}
+	targetOverride := req.GetString("target", baseTarget)
+	host, port, usesHTTPS := parseTarget(rawRequest, targetOverride)
+
+	sess := &loginSession{
+		Name:          name,
+		Base:          base,
+		Request:       rawRequest,
+		Target:        Target{Hostname: host, Port: port, UsesHTTPS: usesHTTPS},
+		Extractor:     extractor,
+		Inject:        inject,
+		RefreshOn:     refreshOn,
+		refreshFilter: filter,
+	}
+	if _, err := sess.Token(ctx, m.service); err != nil {
+		return errorResultFromErr("", err), nil
+	}
+	m.service.sessions.Set(sess)
+
+	log.Printf("mcp/session_set: session %s logs in via %s (%s)", name, base, extractor)
+	return jsonResult(sessionListResponse(m.service.sessions.List()))
+}
+
+func (m *mcpServer) handleSessionList(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if err := m.requireWorkflow(); err != nil {
+		return err, nil
+	}
+	return jsonResult(sessionListResponse(m.service.sessions.List()))
+}
+
+func sessionListResponse(sessions []*loginSession) protocol.SessionListResponse {
+	resp := protocol.SessionListResponse{Sessions: make([]protocol.SessionInfo, 0, len(sessions))}
+	for _, sess := range sessions {
+		state := sess.State()
+		_, _, path := extractRequestMeta(string(sess.Request))
+		info := protocol.SessionInfo{
+			Name:      sess.Name,
+			Login:     sess.Base,
+			URL:       sess.Target.origin() + path,
+			Extract:   sess.Extractor.String(),
+			Inject:    sess.Inject,
+			RefreshOn: sess.RefreshOn,
+			Token:     state.Token,
+			Logins:    state.Refreshes,
+			LastError: state.LastErr,
+		}
+		if !state.RefreshedAt.IsZero() {
+			info.LoggedInAt = state.RefreshedAt.UTC().Format(time.RFC3339)
+		}
+		resp.Sessions = append(resp.Sessions, info)
+	}
+	return resp
+}
@@ -0,0 +1,153 @@
+package service
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/go-harden/llm-security-toolbox/sectool/protocol"
+)
+
+func TestTokenExtractor(t *testing.T) {
+	t.Parallel()
+
+	headers := []byte("HTTP/1.1 200 OK\r\nX-Auth-Token: hdr-tok\r\nSet-Cookie: theme=dark\r\nSet-Cookie: sid=cookie-tok; Path=/; HttpOnly\r\n\r\n")
+	body := []byte(`{"data":{"token":"json-tok","ttl":3600},"csrf":"<meta name=csrf content=x>"}`)
+
+	tests := []struct {
+		kind, expr, want string
+	}{
+		{kind: extractJSON, expr: "data.token", want: "json-tok"},
+		{kind: extractJSON, expr: "data.ttl", want: "3600"},
+		{kind: extractHeader, expr: "x-auth-token", want: "hdr-tok"},
+		{kind: extractCookie, expr: "sid", want: "cookie-tok"},
+		{kind: extractRegex, expr: `"token":"([^"]+)"`, want: "json-tok"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.kind+"_"+tc.expr, func(t *testing.T) {
+			e, err := newTokenExtractor(tc.kind, tc.expr)
+			require.NoError(t, err)
+			got, err := e.Extract(headers, body)
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+
+	e, err := newTokenExtractor(extractCookie, "missing")
+	require.NoError(t, err)
+	_, err = e.Extract(headers, body)
+	assert.ErrorContains(t, err, "cookie missing")
+
+	_, err = newTokenExtractor(extractRegex, "token=.*")
+	assert.ErrorContains(t, err, "capture group")
+}
+
+func TestMergeCookies(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "theme=dark; sid=new; lang=en", mergeCookies([]string{"theme=dark; sid=old", "lang=en"}, "sid=new"))
+	assert.Equal(t, "sid=new", mergeCookies(nil, "sid=new"))
+}
+
+func TestMCP_Session(t *testing.T) {
+	t.Parallel()
+
+	srv, mcpClient, mockMCP, _, _ := setupMCPServerWithMock(t)
+	loginResponse := func(token string) string {
+		return "HttpRequestResponse{httpRequest=POST /login HTTP/1.1, httpResponse=HTTP/1.1 200 OK\r\nContent-Type: application/json\r\n\r\n{\"access_token\":\"" + token + "\"}}"
+	}
+
+	mockMCP.AddProxyEntry(
+		"POST /login HTTP/1.1\r\nHost: example.com\r\nContent-Type: application/json\r\nContent-Length: 31\r\n\r\n{\"user\":\"alice\",\"pass\":\"s3cret\"}",
+		"HTTP/1.1 200 OK\r\n\r\n{\"access_token\":\"captured\"}",
+		"",
+	)
+	mockMCP.SetSendResponse(loginResponse("tok1"))
+	resp := CallMCPToolJSONOK[protocol.SessionListResponse](t, mcpClient, "session_set", map[string]interface{}{
+		"name":         "alice",
+		"flow_id":      "last",
+		"extract_json": "access_token",
+	})
+	require.Len(t, resp.Sessions, 1)
+	assert.Equal(t, "tok1", resp.Sessions[0].Token)
+	assert.Equal(t, "Authorization: Bearer {{token}}", resp.Sessions[0].Inject)
+	assert.Equal(t, "https://example.com/login", resp.Sessions[0].URL)
+	assert.Equal(t, 1, resp.Sessions[0].Logins)
+
+	mockMCP.AddProxyEntry(
+		"GET /api/me HTTP/1.1\r\nHost: example.com\r\nAuthorization: Bearer captured\r\n\r\n",
+		"HTTP/1.1 200 OK\r\n\r\nme",
+		"",
+	)
+
+	t.Run("injects_token", func(t *testing.T) {
+		mockMCP.SetSendResponse("HttpRequestResponse{httpRequest=GET / HTTP/1.1, httpResponse=HTTP/1.1 200 OK\r\n\r\nme}")
+		sent := CallMCPToolJSONOK[protocol.ReplaySendResponse](t, mcpClient, "replay_send", map[string]interface{}{
+			"flow_id": "last",
+			"session": "alice",
+		})
+		assert.Equal(t, http.StatusOK, sent.Status)
+		assert.False(t, sent.SessionRefreshed)
+		entry, ok := srv.requestStore.Get(sent.ReplayID)
+		require.True(t, ok)
+		assert.Contains(t, string(entry.Request), "Authorization: Bearer tok1\r\n")
+		assert.NotContains(t, string(entry.Request), "captured")
+	})
+
+	t.Run("refreshes_on_401", func(t *testing.T) {
+		mockMCP.SetSendResponse("HttpRequestResponse{httpRequest=GET / HTTP/1.1, httpResponse=HTTP/1.1 401 Unauthorized\r\n\r\nexpired}")
+		mockMCP.SetSendResponse(loginResponse("tok2"))
+		mockMCP.SetSendResponse("HttpRequestResponse{httpRequest=GET / HTTP/1.1, httpResponse=HTTP/1.1 200 OK\r\n\r\nme}")
+		sent := CallMCPToolJSONOK[protocol.ReplaySendResponse](t, mcpClient, "replay_send", map[string]interface{}{
+			"flow_id": "last",
+			"session": "alice",
+		})
+		assert.Equal(t, http.StatusOK, sent.Status)
+		assert.True(t, sent.SessionRefreshed)
+		entry, ok := srv.requestStore.Get(sent.ReplayID)
+		require.True(t, ok)
+		assert.Contains(t, string(entry.Request), "Authorization: Bearer tok2\r\n")
+
+		list := CallMCPToolJSONOK[protocol.SessionListResponse](t, mcpClient, "session_list", nil)
+		require.Len(t, list.Sessions, 1)
+		assert.Equal(t, "tok2", list.Sessions[0].Token)
+		assert.Equal(t, 2, list.Sessions[0].Logins)
+	})
+
+	t.Run("failed_refresh", func(t *testing.T) {
+		mockMCP.SetSendResponse("HttpRequestResponse{httpRequest=GET / HTTP/1.1, httpResponse=HTTP/1.1 403 Forbidden\r\n\r\nno}")
+		mockMCP.SetSendResponse("HttpRequestResponse{httpRequest=POST /login HTTP/1.1, httpResponse=HTTP/1.1 429 Too Many Requests\r\n\r\nslow down}")
+		result := CallMCPTool(t, mcpClient, "replay_send", map[string]interface{}{
+			"flow_id": "last",
+			"session": "alice",
+		})
+		assert.True(t, result.IsError)
+		assert.Contains(t, ExtractMCPText(t, result), "status 403")
+		assert.Contains(t, ExtractMCPText(t, result), "login response body is not JSON (status 429)")
+	})
+
+	t.Run("validation", func(t *testing.T) {
+		result := CallMCPTool(t, mcpClient, "replay_send", map[string]interface{}{"flow_id": "last", "session": "bob"})
+		assert.True(t, result.IsError)
+		assert.Contains(t, ExtractMCPText(t, result), "unknown session")
+
+		result = CallMCPTool(t, mcpClient, "session_set", map[string]interface{}{"name": "x", "flow_id": "last"})
+		assert.True(t, result.IsError)
+		assert.Contains(t, ExtractMCPText(t, result), "extract_json")
+
+		result = CallMCPTool(t, mcpClient, "session_set", map[string]interface{}{
+			"name": "x", "flow_id": "last", "extract_header": "X-Token", "inject": "X-Token: static",
+		})
+		assert.True(t, result.IsError)
+		assert.Contains(t, ExtractMCPText(t, result), "{{token}}")
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		resp := CallMCPToolJSONOK[protocol.SessionListResponse](t, mcpClient, "session_set", map[string]interface{}{
+			"name": "alice", "delete": true,
+		})
+		assert.Empty(t, resp.Sessions)
+	})
+}
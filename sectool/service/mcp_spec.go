@@ -0,0 +1,202 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/go-harden/llm-security-toolbox/sectool/config"
+	"github.com/go-harden/llm-security-toolbox/sectool/protocol"
+	"github.com/go-harden/llm-security-toolbox/sectool/service/ids"
+	"github.com/go-harden/llm-security-toolbox/sectool/service/store"
+)
+
+// maxSpecBytes caps the size of an imported OpenAPI document.
+const maxSpecBytes = 20 << 20
+
+func (m *mcpServer) specImportTool() mcp.Tool {
+	return mcp.NewTool("spec_import",
+		mcp.WithDescription(`Import an OpenAPI 2 (Swagger) or 3 document and turn each operation into a request template.
+
+Templates fill path, query, header and cookie parameters and the request body with example values (spec examples, defaults, enums, or type-based placeholders).
+Pass an operation's flow_id (or its operationId) to replay_send as flow_id to send it with edits; templates are not sent by this tool.
+Security schemes are not applied: add auth with replay_send add_headers or auth_profile.
+Set exactly one of source (file path or http(s) URL) or content (the document text). Templates are ephemeral and cleared on service restart.`),
+		mcp.WithString("source", mcp.Description("Path to a JSON/YAML spec file, or an http(s) URL to fetch it from")),
+		mcp.WithString("content", mcp.Description("Spec document text (JSON or YAML), instead of source")),
+		mcp.WithString("base_url", mcp.Description("Server URL to target, overriding the spec servers/host (e.g., 'https://staging.example.com/api')")),
+		annotateLocalChange,
+	)
+}
+
+func (m *mcpServer) handleSpecImport(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if err := m.requireWorkflow(); err != nil {
+		return err, nil
+	}
+
+	source, content := req.GetString("source", ""), req.GetString("content", "")
+	if (source == "") == (content == "") {
+		return errorResult("exactly one of source or content is required"), nil
+	}
+
+	data := []byte(content)
+	var sourceURL string
+	if source != "" {
+		var err error
+		if strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://") {
+			sourceURL = source
+			data, err = m.service.fetchSpec(ctx, source)
+		} else if err = m.service.guard.CheckReadable(source); err == nil {
+			data, err = readSpecFile(source)
+		}
+		if err != nil {
+			return errorResultFromErr("failed to load spec: ", err), nil
+		}
+	}
+
+	spec, err := parseOpenAPISpec(data, req.GetString("base_url", ""), sourceURL)
+	if err != nil {
+		return errorResultFromErr("failed to parse spec: ", err), nil
+	}
+
+	resp := protocol.SpecImportResponse{
+		Title:       spec.Title,
+		Version:     spec.Version,
+		SpecVersion: spec.SpecVersion,
+		BaseURL:     spec.BaseURL,
+		Operations:  make([]protocol.SpecOperation, 0, len(spec.Operations)),
+	}
+	for _, op := range spec.Operations {
+		id := ids.Generate(ids.DefaultLength)
+		m.service.specStore.Store(id, &store.RequestEntry{
+			Label:   op.OperationID,
+			Request: op.Request,
+			Target:  op.Target.origin(),
+		})
+		resp.Operations = append(resp.Operations, protocol.SpecOperation{
+			FlowID:      id,
+			Method:      op.Method,
+			Path:        op.Path,
+			OperationID: op.OperationID,
+			Summary:     op.Summary,
+			Params:      op.Params,
+			ContentType: op.ContentType,
+		})
+	}
+
+	log.Printf("mcp/spec_import: imported %d operations from %q (base %s)", len(resp.Operations), spec.Title, spec.BaseURL)
+	return jsonResult(resp)
+}
+
+// lookupSpecOperation finds an imported spec template by flow_id or operationId.
+func (s *Server) lookupSpecOperation(ref string) (*store.RequestEntry, bool) {
+	if entry, ok := s.specStore.Get(ref); ok {
+		return entry, true
+	} else if id, ok := s.specStore.LookupLabel(ref); ok {
+		return s.specStore.Get(id)
+	}
+	return nil, false
+}
+
+// fetchSpec downloads a spec document. The URL and every redirect hop must pass the
+// target guard and project scope, and each request waits on the rate limiter and
+// connects through the host overrides on ctx.
+func (s *Server) fetchSpec(ctx context.Context, specURL string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, specURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", config.UserAgent())
+	req.Header.Set("Accept", "application/json, application/yaml, */*")
+
+	scope := s.projectScope()
+	if err := s.guard.CheckTarget(ctx, scope, req.URL.Host, req.URL.RequestURI()); err != nil {
+		return nil, err
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = dialContext
+	defer transport.CloseIdleConnections()
+	client := &http.Client{
+		Transport: &rateLimitedTransport{limiter: s.rateLimiter, next: transport},
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= 10 {
+				return errors.New("stopped after 10 redirects")
+			} else if err := s.guard.CheckTarget(req.Context(), scope, req.URL.Host, req.URL.RequestURI()); err != nil {
+				return fmt.Errorf("redirect not followed: %w", err)
+			}
+			return nil
+		},
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetch %s: status %d", specURL, resp.StatusCode)
+	}
+	return readLimitedSpec(resp.Body)
+}
+
+// rateLimitedTransport waits on the rate limiter for each request's host and holds
+// the request's concurrency slots until its response body is closed.
+type rateLimitedTransport struct {
+	limiter *rateLimiter
+	next    http.RoundTripper
+}
+
+func (t *rateLimitedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	release, err := t.limiter.Wait(req.Context(), req.URL.Hostname())
+	if err != nil {
+		return nil, err
+	}
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		release()
+		return nil, err
+	}
+	resp.Body = &releaseOnClose{ReadCloser: resp.Body, release: sync.OnceFunc(release)}
+	return resp, nil
+}
+
+type releaseOnClose struct {
+	io.ReadCloser
+	release func()
+}
+
+func (b *releaseOnClose) Close() error {
+	defer b.release()
+	return b.ReadCloser.Close()
+}
+
+func readSpecFile(path string) ([]byte, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+	return readLimitedSpec(f)
+}
+
+func readLimitedSpec(r io.Reader) ([]byte, error) {
+	data, err := io.ReadAll(io.LimitReader(r, maxSpecBytes+1))
+	if err != nil {
+		return nil, err
+	} else if len(data) > maxSpecBytes {
+		return nil, errors.New("spec exceeds 20 MB")
+	}
+	return data, nil
+}
@@ -0,0 +1,128 @@
+package service
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/go-harden/llm-security-toolbox/sectool/protocol"
+)
+
+func TestMCP_SpecImport(t *testing.T) {
+	t.Parallel()
+
+	srv, mcpClient, mockMCP, _, _ := setupMCPServerWithMock(t)
+	mockMCP.SetSendResponse("HttpRequestResponse{httpRequest=GET / HTTP/1.1, httpResponse=HTTP/1.1 200 OK\r\n\r\nok}")
+
+	t.Run("content", func(t *testing.T) {
+		resp := CallMCPToolJSONOK[protocol.SpecImportResponse](t, mcpClient, "spec_import", map[string]interface{}{
+			"content": testOpenAPI3Spec,
+		})
+		assert.Equal(t, "Pets", resp.Title)
+		assert.Equal(t, "https://staging.example.com/api", resp.BaseURL)
+		require.Len(t, resp.Operations, 3)
+		assert.Equal(t, "/pets/{petId}", resp.Operations[2].Path)
+		assert.NotEmpty(t, resp.Operations[2].FlowID)
+	})
+
+	t.Run("replay_send", func(t *testing.T) {
+		resp := CallMCPToolJSONOK[protocol.SpecImportResponse](t, mcpClient, "spec_import", map[string]interface{}{
+			"content":  testOpenAPI3Spec,
+			"base_url": "http://localhost:9000",
+		})
+
+		sent := CallMCPToolJSONOK[protocol.ReplaySendResponse](t, mcpClient, "replay_send", map[string]interface{}{
+			"flow_id":   resp.Operations[0].FlowID,
+			"set_json":  map[string]interface{}{"name": "injected"},
+			"set_query": []interface{}{"debug=1"},
+		})
+		entry, ok := srv.requestStore.Get(sent.ReplayID)
+		require.True(t, ok)
+		assert.Equal(t, "http://localhost:9000", entry.Target)
+		assert.True(t, strings.HasPrefix(string(entry.Request), "POST /pets?debug=1 HTTP/1.1\r\n"), string(entry.Request))
+		assert.Contains(t, string(entry.Request), `"name":"injected"`)
+
+		byOperationID := CallMCPToolJSONOK[protocol.ReplaySendResponse](t, mcpClient, "replay_send", map[string]interface{}{
+			"flow_id": "getPet",
+		})
+		entry, ok = srv.requestStore.Get(byOperationID.ReplayID)
+		require.True(t, ok)
+		assert.Contains(t, string(entry.Request), "GET /pets/42")
+	})
+
+	t.Run("file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "swagger.json")
+		require.NoError(t, os.WriteFile(path, []byte(testSwagger2Spec), 0o600))
+
+		resp := CallMCPToolJSONOK[protocol.SpecImportResponse](t, mcpClient, "spec_import", map[string]interface{}{
+			"source": path,
+		})
+		assert.Equal(t, "2.0", resp.SpecVersion)
+		assert.Len(t, resp.Operations, 3)
+	})
+
+	t.Run("url", func(t *testing.T) {
+		specSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte("openapi: 3.0.0\nservers: [{url: /v2}]\npaths:\n  /health:\n    get: {}\n"))
+		}))
+		t.Cleanup(specSrv.Close)
+
+		resp := CallMCPToolJSONOK[protocol.SpecImportResponse](t, mcpClient, "spec_import", map[string]interface{}{
+			"source": specSrv.URL + "/openapi.yaml",
+		})
+		assert.Equal(t, specSrv.URL+"/v2", resp.BaseURL)
+		require.Len(t, resp.Operations, 1)
+	})
+
+	t.Run("validation", func(t *testing.T) {
+		result := CallMCPTool(t, mcpClient, "spec_import", nil)
+		assert.True(t, result.IsError)
+		assert.Contains(t, ExtractMCPText(t, result), "exactly one of source or content")
+
+		result = CallMCPTool(t, mcpClient, "spec_import", map[string]interface{}{"content": "openapi: [unclosed"})
+		assert.True(t, result.IsError)
+		assert.Contains(t, ExtractMCPText(t, result), "failed to parse spec")
+	})
+}
+
+func TestMCP_SpecImportRedirectScope(t *testing.T) {
+	t.Parallel()
+
+	_, mcpClient, _, _, _ := setupMCPServerWithMock(t)
+
+	var fetched bool
+	outside := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetched = true
+		_, _ = w.Write([]byte("openapi: 3.0.0\npaths: {}\n"))
+	}))
+	t.Cleanup(outside.Close)
+	specSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, outside.URL+"/openapi.yaml", http.StatusFound)
+	}))
+	t.Cleanup(specSrv.Close)
+
+	CallMCPToolJSONOK[protocol.ScopeResponse](t, mcpClient, "scope_set", map[string]interface{}{
+		"include": []string{"127.0.0.1"},
+		"exclude": []string{strings.TrimPrefix(outside.URL, "http://")},
+	})
+
+	result := CallMCPTool(t, mcpClient, "spec_import", map[string]interface{}{
+		"source": specSrv.URL + "/openapi.yaml",
+	})
+	require.True(t, result.IsError)
+	assert.Contains(t, ExtractMCPText(t, result), "redirect not followed: out of scope")
+	assert.False(t, fetched)
+
+	result = CallMCPTool(t, mcpClient, "spec_import", map[string]interface{}{
+		"source": outside.URL + "/openapi.yaml",
+	})
+	require.True(t, result.IsError)
+	assert.Contains(t, ExtractMCPText(t, result), "out of scope")
+	assert.False(t, fetched)
+}
@@ -0,0 +1,345 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/go-harden/llm-security-toolbox/sectool/service/ids"
+)
+
+const (
+	sseKeepAliveInterval = 15 * time.Second
+	sseReplayBuffer      = 256             // events kept per session for Last-Event-ID replay
+	sseDetachedTTL       = 5 * time.Minute // how long a session survives without a stream
+	sseMessagePath       = "/message"
+)
+
+// resumableSSE serves the legacy SSE transport. Unlike the mcp-go SSE server, a session
+// outlives its stream: every event carries an id ("<session>:<seq>"), events produced
+// while the client is away are buffered, and reconnecting with Last-Event-ID replays
+// them, so tool results finishing during a dropped connection are not lost. Streams
+// also carry keepalive comments so idle proxies and clients don't time out.
+type resumableSSE struct {
+	server    *server.MCPServer
+	keepAlive time.Duration
+
+	mu       sync.Mutex
+	sessions map[string]*sseSession
+	done     chan struct{}
+	closed   bool
+}
+
+func newResumableSSE(srv *server.MCPServer) *resumableSSE {
+	s := &resumableSSE{
+		server:    srv,
+		keepAlive: sseKeepAliveInterval,
+		sessions:  make(map[string]*sseSession),
+		done:      make(chan struct{}),
+	}
+	go s.expireDetached()
+	return s
+}
+
+type sseEvent struct {
+	seq  uint64
+	name string
+	data string
+}
+
+// sseSession implements server.ClientSession for one logical client across reconnects.
+type sseSession struct {
+	id            string
+	notifications chan mcp.JSONRPCNotification
+	done          chan struct{}
+	initialized   atomic.Bool
+	logLevel      atomic.Value // mcp.LoggingLevel
+	clientInfo    atomic.Value // mcp.Implementation
+	clientCaps    atomic.Value // mcp.ClientCapabilities
+
+	mu         sync.Mutex
+	seq        uint64
+	events     []sseEvent    // newest last, capped at sseReplayBuffer
+	wake       chan struct{} // closed and replaced whenever an event is added
+	stream     uint64        // generation of the attached stream, 0 when detached
+	detachedAt time.Time
+}
+
+func (s *sseSession) SessionID() string { return s.id }
+
+func (s *sseSession) NotificationChannel() chan<- mcp.JSONRPCNotification { return s.notifications }
+
+func (s *sseSession) Initialize() { s.initialized.Store(true) }
+
+func (s *sseSession) Initialized() bool { return s.initialized.Load() }
+
+func (s *sseSession) SetLogLevel(level mcp.LoggingLevel) { s.logLevel.Store(level) }
+
+func (s *sseSession) GetLogLevel() mcp.LoggingLevel {
+	if level, ok := s.logLevel.Load().(mcp.LoggingLevel); ok {
+		return level
+	}
+	return mcp.LoggingLevelError
+}
+
+func (s *sseSession) GetClientInfo() mcp.Implementation {
+	info, _ := s.clientInfo.Load().(mcp.Implementation)
+	return info
+}
+
+func (s *sseSession) SetClientInfo(info mcp.Implementation) { s.clientInfo.Store(info) }
+
+func (s *sseSession) GetClientCapabilities() mcp.ClientCapabilities {
+	caps, _ := s.clientCaps.Load().(mcp.ClientCapabilities)
+	return caps
+}
+
+func (s *sseSession) SetClientCapabilities(caps mcp.ClientCapabilities) { s.clientCaps.Store(caps) }
+
+// push appends an event to the replay buffer and wakes the attached stream.
+func (s *sseSession) push(name, data string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.seq++
+	s.events = append(s.events, sseEvent{seq: s.seq, name: name, data: data})
+	if len(s.events) > sseReplayBuffer {
+		s.events = s.events[len(s.events)-sseReplayBuffer:]
+	}
+	close(s.wake)
+	s.wake = make(chan struct{})
+}
+
+// since returns buffered events after seq and a channel closed on the next push.
+func (s *sseSession) since(seq uint64) ([]sseEvent, <-chan struct{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var out []sseEvent
+	for _, e := range s.events {
+		if e.seq > seq {
+			out = append(out, e)
+		}
+	}
+	return out, s.wake
+}
+
+// forwardNotifications queues server notifications as SSE events until the session ends.
+func (s *sseSession) forwardNotifications() {
+	for {
+		select {
+		case n := <-s.notifications:
+			if b, err := json.Marshal(n); err == nil {
+				s.push("message", string(b))
+			}
+		case <-s.done:
+			return
+		}
+	}
+}
+
+func (s *resumableSSE) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch {
+	case r.URL.Path == sseMessagePath && r.Method == http.MethodPost:
+		s.handleMessage(w, r)
+	case r.Method == http.MethodGet:
+		s.handleStream(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// parseLastEventID splits a "<session>:<seq>" event id.
+func parseLastEventID(id string) (string, uint64, bool) {
+	sessionID, seqStr, ok := strings.Cut(id, ":")
+	if !ok || sessionID == "" {
+		return "", 0, false
+	}
+	seq, err := strconv.ParseUint(seqStr, 10, 64)
+	return sessionID, seq, err == nil
+}
+
+func (s *resumableSSE) handleStream(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "Streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	var session *sseSession
+	var lastSeq uint64
+	if sessionID, seq, ok := parseLastEventID(r.Header.Get("Last-Event-ID")); ok {
+		s.mu.Lock()
+		session = s.sessions[sessionID]
+		s.mu.Unlock()
+		lastSeq = seq
+	}
+	if session == nil {
+		var err error
+		if session, err = s.newSession(r.Context()); err != nil {
+			http.Error(w, "Session registration failed: "+err.Error(), http.StatusServiceUnavailable)
+			return
+		}
+		lastSeq = 0
+		session.push("endpoint", sseMessagePath+"?sessionId="+session.id)
+	} else {
+		log.Printf("mcp/sse: session %s resumed after event %d", session.id, lastSeq)
+	}
+
+	session.mu.Lock()
+	session.stream++
+	stream := session.stream
+	session.mu.Unlock()
+	defer func() {
+		session.mu.Lock()
+		if session.stream == stream {
+			session.stream = 0
+			session.detachedAt = time.Now()
+		}
+		session.mu.Unlock()
+	}()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	ticker := time.NewTicker(s.keepAlive)
+	defer ticker.Stop()
+	for {
+		events, wake := session.since(lastSeq)
+		for _, e := range events {
+			_, _ = fmt.Fprintf(w, "id: %s:%d\nevent: %s\ndata: %s\n\n", session.id, e.seq, e.name, e.data)
+			lastSeq = e.seq
+		}
+		if len(events) > 0 {
+			flusher.Flush()
+		}
+
+		select {
+		case <-wake:
+		case <-ticker.C:
+			_, _ = fmt.Fprint(w, ": keepalive\n\n")
+			flusher.Flush()
+		case <-r.Context().Done():
+			return
+		case <-session.done:
+			return
+		case <-s.done:
+			return
+		}
+
+		session.mu.Lock()
+		replaced := session.stream != stream
+		session.mu.Unlock()
+		if replaced {
+			return // a newer stream for this session took over
+		}
+	}
+}
+
+func (s *resumableSSE) newSession(ctx context.Context) (*sseSession, error) {
+	session := &sseSession{
+		id:            ids.Generate(ids.DefaultLength),
+		notifications: make(chan mcp.JSONRPCNotification, 100),
+		done:          make(chan struct{}),
+		wake:          make(chan struct{}),
+		detachedAt:    time.Now(),
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return nil, fmt.Errorf("server shutting down")
+	} else if err := s.server.RegisterSession(ctx, session); err != nil {
+		return nil, err
+	}
+	s.sessions[session.id] = session
+	go session.forwardNotifications()
+	return session, nil
+}
+
+func (s *resumableSSE) handleMessage(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	session := s.sessions[r.URL.Query().Get("sessionId")]
+	s.mu.Unlock()
+	if session == nil {
+		http.Error(w, "Invalid session ID", http.StatusBadRequest)
+		return
+	}
+
+	var raw json.RawMessage
+	if err := json.NewDecoder(r.Body).Decode(&raw); err != nil {
+		http.Error(w, "Parse error", http.StatusBadRequest)
+		return
+	}
+	w.WriteHeader(http.StatusAccepted)
+
+	// Results are delivered over the stream, so handling must outlive this POST
+	ctx := withClientName(s.server.WithContext(context.WithoutCancel(r.Context()), session), r)
+	go func() {
+		if resp := s.server.HandleMessage(ctx, raw); resp != nil {
+			if b, err := json.Marshal(resp); err == nil {
+				session.push("message", string(b))
+			}
+		}
+	}()
+}
+
+// expireDetached drops sessions whose stream has been gone longer than sseDetachedTTL.
+func (s *resumableSSE) expireDetached() {
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+		case <-s.done:
+			return
+		}
+
+		s.mu.Lock()
+		for id, session := range s.sessions {
+			session.mu.Lock()
+			expired := session.stream == 0 && time.Since(session.detachedAt) > sseDetachedTTL
+			session.mu.Unlock()
+			if expired {
+				s.closeSession(id, session)
+			}
+		}
+		s.mu.Unlock()
+	}
+}
+
+// closeSession ends a session. Caller holds s.mu.
+func (s *resumableSSE) closeSession(id string, session *sseSession) {
+	close(session.done)
+	delete(s.sessions, id)
+	s.server.UnregisterSession(context.Background(), id)
+}
+
+// Shutdown ends all sessions and their streams.
+func (s *resumableSSE) Shutdown(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return nil
+	}
+	s.closed = true
+	close(s.done)
+	for id, session := range s.sessions {
+		s.closeSession(id, session)
+	}
+	return ctx.Err()
+}
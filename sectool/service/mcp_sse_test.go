@@ -0,0 +1,177 @@
+package service
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type sseFrame struct {
+	id, event, data string
+	comment         bool
+}
+
+// readSSEFrame reads one blank-line terminated SSE frame.
+func readSSEFrame(t *testing.T, r *bufio.Reader) sseFrame {
+	t.Helper()
+
+	var f sseFrame
+	for {
+		line, err := r.ReadString('\n')
+		require.NoError(t, err)
+		line = strings.TrimRight(line, "\n")
+		switch {
+		case line == "":
+			return f
+		case strings.HasPrefix(line, ":"):
+			f.comment = true
+		case strings.HasPrefix(line, "id: "):
+			f.id = strings.TrimPrefix(line, "id: ")
+		case strings.HasPrefix(line, "event: "):
+			f.event = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: "):
+			f.data = strings.TrimPrefix(line, "data: ")
+		}
+	}
+}
+
+func openSSEStream(t *testing.T, ctx context.Context, url, lastEventID string) (*http.Response, *bufio.Reader) {
+	t.Helper()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url+"/sse", nil)
+	require.NoError(t, err)
+	if lastEventID != "" {
+		req.Header.Set("Last-Event-ID", lastEventID)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	return resp, bufio.NewReader(resp.Body)
+}
+
+func postSSEMessage(t *testing.T, url, endpoint, body string) {
+	t.Helper()
+
+	resp, err := http.Post(url+endpoint, "application/json", strings.NewReader(body))
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	require.Equal(t, http.StatusAccepted, resp.StatusCode)
+}
+
+func TestParseLastEventID(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		input   string
+		session string
+		seq     uint64
+		ok      bool
+	}{
+		{name: "valid", input: "abc:12", session: "abc", seq: 12, ok: true},
+		{name: "empty", input: ""},
+		{name: "no_separator", input: "abc"},
+		{name: "missing_session", input: ":3"},
+		{name: "bad_seq", input: "abc:x"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			session, seq, ok := parseLastEventID(tc.input)
+			assert.Equal(t, tc.ok, ok)
+			if tc.ok {
+				assert.Equal(t, tc.session, session)
+				assert.Equal(t, tc.seq, seq)
+			}
+		})
+	}
+}
+
+func TestResumableSSE(t *testing.T) {
+	t.Parallel()
+
+	newServer := func(t *testing.T, keepAlive time.Duration) (*resumableSSE, *httptest.Server) {
+		t.Helper()
+
+		sse := newResumableSSE(server.NewMCPServer("test", "1.0.0"))
+		sse.keepAlive = keepAlive
+		mux := http.NewServeMux()
+		mux.Handle("/sse", sse)
+		mux.Handle(sseMessagePath, sse)
+		ts := httptest.NewServer(mux)
+		t.Cleanup(func() {
+			_ = sse.Shutdown(context.Background())
+			ts.Close()
+		})
+		return sse, ts
+	}
+
+	t.Run("resume_replays_missed_results", func(t *testing.T) {
+		_, ts := newServer(t, time.Minute)
+
+		ctx, cancel := context.WithCancel(t.Context())
+		resp, r := openSSEStream(t, ctx, ts.URL, "")
+		endpoint := readSSEFrame(t, r)
+		require.Equal(t, "endpoint", endpoint.event)
+		assert.True(t, strings.HasPrefix(endpoint.data, sseMessagePath+"?sessionId="))
+
+		postSSEMessage(t, ts.URL, endpoint.data, `{"jsonrpc":"2.0","id":1,"method":"ping"}`)
+		first := readSSEFrame(t, r)
+		assert.Equal(t, "message", first.event)
+		assert.Contains(t, first.data, `"id":1`)
+
+		// Drop the stream, then finish a request while detached
+		cancel()
+		_ = resp.Body.Close()
+		postSSEMessage(t, ts.URL, endpoint.data, `{"jsonrpc":"2.0","id":2,"method":"ping"}`)
+
+		resp, r = openSSEStream(t, t.Context(), ts.URL, first.id)
+		defer func() { _ = resp.Body.Close() }()
+		replayed := readSSEFrame(t, r)
+		assert.Equal(t, "message", replayed.event)
+		assert.Contains(t, replayed.data, `"id":2`)
+
+		firstSession, firstSeq, _ := parseLastEventID(first.id)
+		session, seq, ok := parseLastEventID(replayed.id)
+		require.True(t, ok)
+		assert.Equal(t, firstSession, session)
+		assert.Equal(t, firstSeq+1, seq)
+	})
+
+	t.Run("unknown_last_event_id_starts_new_session", func(t *testing.T) {
+		_, ts := newServer(t, time.Minute)
+
+		resp, r := openSSEStream(t, t.Context(), ts.URL, "missing:5")
+		defer func() { _ = resp.Body.Close() }()
+		frame := readSSEFrame(t, r)
+		assert.Equal(t, "endpoint", frame.event)
+		assert.NotContains(t, frame.id, "missing")
+	})
+
+	t.Run("keepalive", func(t *testing.T) {
+		_, ts := newServer(t, 20*time.Millisecond)
+
+		resp, r := openSSEStream(t, t.Context(), ts.URL, "")
+		defer func() { _ = resp.Body.Close() }()
+		assert.Equal(t, "endpoint", readSSEFrame(t, r).event)
+		assert.True(t, readSSEFrame(t, r).comment)
+	})
+
+	t.Run("unknown_session_rejected", func(t *testing.T) {
+		_, ts := newServer(t, time.Minute)
+
+		resp, err := http.Post(ts.URL+sseMessagePath+"?sessionId=nope", "application/json",
+			strings.NewReader(`{"jsonrpc":"2.0","id":1,"method":"ping"}`))
+		require.NoError(t, err)
+		_ = resp.Body.Close()
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+}
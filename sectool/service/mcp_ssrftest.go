@@ -0,0 +1,299 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/go-harden/llm-security-toolbox/sectool/protocol"
+	"github.com/go-harden/llm-security-toolbox/sectool/service/ids"
+	"github.com/go-harden/llm-security-toolbox/sectool/service/store"
+)
+
+const defaultSSRFOastWait = 5 * time.Second
+
+func (m *mcpServer) ssrfTestTool() mcp.Tool {
+	return mcp.NewTool("ssrf_test",
+		mcp.WithDescription(`Test a URL-taking parameter or header for SSRF and open redirect by sending canonical payloads and classifying each response.
+
+Categories: loopback (127.0.0.1 in decimal, hex, octal, IPv6 and short forms), internal (RFC 1918, kubernetes), metadata (AWS, GCP, Azure, Alibaba endpoints), scheme (file, gopher, dict), oast (URLs and a bare host on the OAST domain), redirect (//host, /\host).
+The unmodified request and a control URL that never resolves are sent first. Verdicts:
+- confirmed: an OAST interaction for the payload's subdomain, a Location/Refresh redirect to the payload host (open redirect), or target content such as ami-id or root:x:0:0 absent from the baseline and control
+- differs: status, body or timing differs from the control; confirm manually
+- none, or error when the request failed
+Without oast_id a new OAST session is created and returned. OAST hits are collected for wait after the last request; late hits show in oast_poll.
+Requests that got a confirmed or differs response are stored as replays.`),
+		mcp.WithString("flow_id", mcp.Description("Flow to test, or "+recentRefUsage+" proxy entry (exclusive with replay_id)")),
+		mcp.WithString("replay_id", mcp.Description("Replay to use as the request (exclusive with flow_id)")),
+		mcp.WithString("param", mcp.Description("Parameter to set to each payload (exclusive with header)")),
+		mcp.WithString("header", mcp.Description("Header to set to each payload, e.g. 'Referer' (exclusive with param)")),
+		mcp.WithString("location", mcp.Description("Where param goes: query or body (default: query if already there, else the form, multipart or JSON body)")),
+		mcp.WithArray("categories", mcp.Items(map[string]interface{}{"type": "string"}), mcp.Description("Built-in categories to send (default: all)")),
+		mcp.WithArray("payloads", mcp.Items(map[string]interface{}{"type": "string"}), mcp.Description("Custom payloads instead of the built-in ones; {oast} is replaced with a labelled OAST host")),
+		mcp.WithString("oast_id", mcp.Description("OAST session for oast and redirect payloads (default: create one)")),
+		mcp.WithString("wait", mcp.Description("How long to collect OAST interactions after the last request (default '5s')")),
+		mcp.WithString("timeout", mcp.Description("Per-request timeout (e.g., '30s')")),
+		mcp.WithNumber("rate", mcp.Description(rateParamDescription)),
+		annotateSendsTraffic,
+	)
+}
+
+func (m *mcpServer) handleSSRFTest(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if err := m.requireWorkflow(); err != nil {
+		return err, nil
+	}
+	ctx, err := applyRunRate(ctx, req)
+	if err != nil {
+		return errorResult(err.Error()), nil
+	}
+
+	flowID, replayID := req.GetString("flow_id", ""), req.GetString("replay_id", "")
+	if (flowID == "") == (replayID == "") {
+		return errorResult("exactly one of flow_id or replay_id is required"), nil
+	}
+	param, header := strings.TrimSpace(req.GetString("param", "")), strings.TrimSpace(req.GetString("header", ""))
+	if (param == "") == (header == "") {
+		return errorResult("exactly one of param or header is required"), nil
+	} else if strings.ContainsAny(header, ": \r\n") {
+		return errorResult("header must be a bare header name (e.g., 'Referer')"), nil
+	}
+	location := req.GetString("location", "")
+	switch {
+	case header != "":
+		if location != "" {
+			return errorResult("location applies to param only"), nil
+		}
+		location = ssrfInHeader
+	case location != "" && location != ssrfInQuery && location != ssrfInBody:
+		return errorResult("location must be query or body"), nil
+	}
+	categories := req.GetStringSlice("categories", nil)
+	for _, c := range categories {
+		if !slices.Contains(ssrfCategories, c) {
+			return errorResult("unknown category " + c + ": use " + strings.Join(ssrfCategories, ", ")), nil
+		}
+	}
+	custom := req.GetStringSlice("payloads", nil)
+	if len(custom) > 0 && len(categories) > 0 {
+		return errorResult("categories select built-in payloads and cannot be combined with payloads"), nil
+	}
+	var timeout time.Duration
+	if s := req.GetString("timeout", ""); s != "" {
+		var err error
+		if timeout, err = time.ParseDuration(s); err != nil {
+			return errorResult("invalid timeout duration: " + err.Error()), nil
+		}
+	}
+	wait := defaultSSRFOastWait
+	if s := req.GetString("wait", ""); s != "" {
+		var err error
+		if wait, err = time.ParseDuration(s); err != nil {
+			return errorResult("invalid wait duration: " + err.Error()), nil
+		}
+	}
+
+	rawRequest, baseTarget, base, err := m.service.loadBaseRequest(ctx, flowID, replayID)
+	if err != nil {
+		return errorResultFromErr("", err), nil
+	}
+	if location == "" {
+		location = ssrfLocation(rawRequest, param)
+	} else if location == ssrfInBody && ssrfLocation(rawRequest, "") != ssrfInBody {
+		return errorResult("request has no form, multipart or JSON body for location body"), nil
+	}
+	host, port, usesHTTPS := parseTarget(rawRequest, baseTarget)
+	target := Target{Hostname: host, Port: port, UsesHTTPS: usesHTTPS}
+
+	var payloads []ssrfPayload
+	needsOast := len(custom) == 0 && (len(categories) == 0 || slices.Contains(categories, ssrfOast) || slices.Contains(categories, ssrfRedirect))
+	for _, value := range custom {
+		needsOast = needsOast || strings.Contains(value, ssrfOastPlaceholder)
+	}
+	var oastID, oastDomain string
+	if needsOast {
+		if oastID, oastDomain, err = m.ssrfOastSession(ctx, req.GetString("oast_id", "")); err != nil {
+			return errorResultFromErr("", err), nil
+		}
+	}
+	if len(custom) > 0 {
+		payloads = customSSRFPayloads(custom, oastDomain)
+	} else {
+		payloads = ssrfPayloads(categories, oastDomain)
+	}
+	name := param
+	if name == "" {
+		name = header
+	}
+
+	send := func(value string) *ssrfProbe {
+		request := injectSSRF(rawRequest, location, name, value)
+		probe := &ssrfProbe{request: request}
+		probe.result, probe.err = m.service.sendRequest(ctx, "sectool-ssrf-test", SendRequestInput{
+			RawRequest: request,
+			Target:     target,
+			Timeout:    timeout,
+		}, nil)
+		if probe.err == nil {
+			probe.status, _ = parseResponseStatus(probe.result.Headers)
+		}
+		return probe
+	}
+
+	log.Printf("mcp/ssrf_test: sending %d payloads in %s %s of %s", len(payloads), location, name, base)
+	start := time.Now()
+	baseline := &ssrfProbe{request: rawRequest}
+	if baseline.result, err = m.service.sendRequest(ctx, "sectool-ssrf-test", SendRequestInput{
+		RawRequest: rawRequest,
+		Target:     target,
+		Timeout:    timeout,
+	}, nil); err != nil {
+		return errorResultFromErr("baseline request failed: ", err), nil
+	}
+	baseline.status, _ = parseResponseStatus(baseline.result.Headers)
+	control := send(ssrfControl)
+	if control.err != nil {
+		return errorResultFromErr("control request failed: ", control.err), nil
+	}
+
+	probes := make([]*ssrfProbe, 0, len(payloads))
+	for _, p := range payloads {
+		if ctx.Err() != nil {
+			return errorResultFromErr("", ctx.Err()), nil
+		}
+		probe := send(p.value)
+		probe.payload = p
+		classifySSRF(probe, baseline, control)
+		probes = append(probes, probe)
+	}
+
+	if oastID != "" {
+		events, err := m.ssrfOastEvents(ctx, oastID, start, wait, probes)
+		if err != nil {
+			return errorResultFromErr("failed to poll OAST session: ", err), nil
+		}
+		applySSRFOast(probes, events)
+	}
+
+	resp := protocol.SSRFTestResponse{
+		Base:     base,
+		Param:    param,
+		Header:   header,
+		Location: location,
+		OastID:   oastID,
+		Domain:   oastDomain,
+		Baseline: ssrfSummary(baseline),
+		Control:  ssrfSummary(control),
+		Requests: len(probes) + 2,
+		Results:  make([]protocol.SSRFResult, 0, len(probes)),
+	}
+	for _, probe := range probes {
+		result := protocol.SSRFResult{
+			Payload:  probe.payload.value,
+			Category: probe.payload.category,
+			Verdict:  probe.verdict,
+			Evidence: probe.evidence,
+		}
+		if probe.err == nil {
+			result.Status = probe.status
+			result.Size = len(probe.result.Body)
+			result.Duration = probe.result.Duration.Round(time.Millisecond).String()
+		}
+		if probe.err == nil && (probe.verdict == ssrfConfirmed || probe.verdict == ssrfDiffers) {
+			result.ReplayID = ids.Generate(ids.DefaultLength)
+			m.service.requestStore.Store(result.ReplayID, &store.RequestEntry{
+				Base:     base,
+				Request:  probe.request,
+				Target:   target.origin(),
+				Headers:  probe.result.Headers,
+				Body:     probe.result.Body,
+				Duration: probe.result.Duration,
+			})
+			m.service.correlateOast(ctx, result.ReplayID, probe.request)
+		}
+		switch probe.verdict {
+		case ssrfConfirmed:
+			resp.Confirmed++
+		case ssrfDiffers:
+			resp.Differs++
+		}
+		resp.Results = append(resp.Results, result)
+	}
+
+	log.Printf("mcp/ssrf_test: %s done, %d confirmed, %d differ", base, resp.Confirmed, resp.Differs)
+	return jsonResult(resp)
+}
+
+// ssrfOastSession resolves the OAST session by ID, domain or label, or creates
+// one when idOrLabel is empty, and returns its ID and domain.
+func (m *mcpServer) ssrfOastSession(ctx context.Context, idOrLabel string) (string, string, error) {
+	if idOrLabel == "" {
+		sess, err := m.service.oastBackend.CreateSession(ctx, "")
+		if err != nil {
+			return "", "", fmt.Errorf("failed to create OAST session: %w", err)
+		}
+		if m.service.oastNotify != nil {
+			m.service.oastNotify.Watch(*sess)
+		}
+		return sess.ID, sess.Domain, nil
+	}
+	sessions, err := m.service.oastBackend.ListSessions(ctx)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to list OAST sessions: %w", err)
+	}
+	for _, sess := range sessions {
+		if sess.ID == idOrLabel || sess.Domain == idOrLabel || (sess.Label != "" && sess.Label == idOrLabel) {
+			return sess.ID, sess.Domain, nil
+		}
+	}
+	return "", "", fmt.Errorf("OAST session %s not found", idOrLabel)
+}
+
+// ssrfOastEvents collects interactions since start until wait has passed after
+// the last request or every labelled probe has a hit.
+func (m *mcpServer) ssrfOastEvents(ctx context.Context, oastID string, start time.Time, wait time.Duration, probes []*ssrfProbe) ([]OastEventInfo, error) {
+	pending := make(map[string]bool)
+	for _, probe := range probes {
+		if probe.payload.label != "" {
+			pending[probe.payload.label] = true
+		}
+	}
+	if len(pending) == 0 {
+		return nil, nil
+	}
+
+	deadline := time.Now().Add(wait)
+	since := start.Format(time.RFC3339)
+	var events []OastEventInfo
+	for {
+		remaining := max(time.Until(deadline), 0)
+		result, err := m.service.oastBackend.PollSession(ctx, oastID, since, "", "", remaining, 0)
+		if err != nil {
+			return nil, err
+		} else if len(result.Events) == 0 {
+			return events, nil // the wait elapsed
+		}
+		events = append(events, result.Events...)
+		since = result.Events[len(result.Events)-1].ID
+		for _, event := range result.Events {
+			for _, part := range strings.Split(strings.ToLower(event.Subdomain), ".") {
+				delete(pending, part)
+			}
+		}
+		if len(pending) == 0 || remaining == 0 {
+			return events, nil
+		}
+	}
+}
+
+func ssrfSummary(probe *ssrfProbe) protocol.SSRFResponseSummary {
+	return protocol.SSRFResponseSummary{
+		Status:   probe.status,
+		Size:     len(probe.result.Body),
+		Duration: probe.result.Duration.Round(time.Millisecond).String(),
+	}
+}
@@ -0,0 +1,104 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/go-harden/llm-security-toolbox/sectool/protocol"
+)
+
+func TestMCP_SSRFTest(t *testing.T) {
+	t.Parallel()
+
+	srv, mcpClient, mockMCP, mockOast, _ := setupMCPServerWithMock(t)
+	const page = "HttpRequestResponse{httpRequest=GET /preview HTTP/1.1, httpResponse=HTTP/1.1 200 OK\r\nContent-Type: text/html\r\n\r\n<html>Preview unavailable</html>}"
+	mockMCP.AddProxyEntry(
+		"GET /preview?url=https%3A%2F%2Fexample.com%2F HTTP/1.1\r\nHost: app.example.com\r\n\r\n",
+		"HTTP/1.1 200 OK\r\nContent-Type: text/html\r\n\r\n<html>Example Domain</html>",
+		"",
+	)
+
+	t.Run("metadata", func(t *testing.T) {
+		mockMCP.SetSendResponse(page) // baseline
+		mockMCP.SetSendResponse(page) // control
+		mockMCP.SetSendResponse("HttpRequestResponse{httpRequest=GET /preview HTTP/1.1, httpResponse=HTTP/1.1 200 OK\r\nContent-Type: text/plain\r\n\r\nami-id\nhostname\n}")
+		for range 6 {
+			mockMCP.SetSendResponse(page)
+		}
+
+		resp := CallMCPToolJSONOK[protocol.SSRFTestResponse](t, mcpClient, "ssrf_test", map[string]interface{}{
+			"flow_id":    "last",
+			"param":      "url",
+			"categories": []interface{}{"metadata"},
+		})
+		assert.Equal(t, "query", resp.Location)
+		assert.Empty(t, resp.OastID)
+		assert.Equal(t, 9, resp.Requests)
+		assert.Equal(t, 1, resp.Confirmed)
+		assert.Zero(t, resp.Differs)
+		require.Len(t, resp.Results, 7)
+		r := resp.Results[0]
+		assert.Equal(t, "http://169.254.169.254/latest/meta-data/", r.Payload)
+		assert.Equal(t, "confirmed", r.Verdict)
+		assert.Equal(t, []string{`response contains "ami-id"`}, r.Evidence)
+		entry, ok := srv.requestStore.Get(r.ReplayID)
+		require.True(t, ok)
+		assert.Contains(t, string(entry.Request), "GET /preview?url=http%3A%2F%2F169.254.169.254%2Flatest%2Fmeta-data%2F HTTP/1.1\r\n")
+		for _, r := range resp.Results[1:] {
+			assert.Equal(t, "none", r.Verdict, r.Payload)
+			assert.Empty(t, r.ReplayID)
+		}
+	})
+
+	t.Run("oast_and_redirect", func(t *testing.T) {
+		sess := CallMCPToolJSONOK[protocol.OastCreateResponse](t, mcpClient, "oast_create", nil)
+		mockOast.events[sess.OastID] = []OastEventInfo{{
+			ID:        "ev1",
+			Time:      time.Now(),
+			Type:      "http",
+			SourceIP:  "203.0.113.7",
+			Subdomain: "ssrf02." + sess.Domain,
+		}}
+		for range 5 {
+			mockMCP.SetSendResponse(page) // baseline, control, oast
+		}
+		mockMCP.SetSendResponse("HttpRequestResponse{httpRequest=GET /preview HTTP/1.1, httpResponse=HTTP/1.1 302 Found\r\nLocation: //ssrf04." + sess.Domain + "/\r\n\r\n}")
+
+		resp := CallMCPToolJSONOK[protocol.SSRFTestResponse](t, mcpClient, "ssrf_test", map[string]interface{}{
+			"flow_id":    "last",
+			"param":      "url",
+			"categories": []interface{}{"oast", "redirect"},
+			"oast_id":    sess.OastID,
+			"wait":       "0s",
+		})
+		assert.Equal(t, sess.OastID, resp.OastID)
+		require.Len(t, resp.Results, 6)
+		assert.Equal(t, 2, resp.Confirmed)
+		assert.Equal(t, "https://ssrf02."+sess.Domain+"/", resp.Results[1].Payload)
+		assert.Equal(t, []string{"oast http interaction from 203.0.113.7"}, resp.Results[1].Evidence)
+		assert.Equal(t, "redirect", resp.Results[3].Category)
+		assert.Equal(t, "confirmed", resp.Results[3].Verdict)
+		assert.Equal(t, []string{"redirects to payload host: //ssrf04." + sess.Domain + "/"}, resp.Results[3].Evidence)
+	})
+
+	t.Run("validation", func(t *testing.T) {
+		result := CallMCPTool(t, mcpClient, "ssrf_test", map[string]interface{}{"flow_id": "last"})
+		assert.True(t, result.IsError)
+		assert.Contains(t, ExtractMCPText(t, result), "exactly one of param or header")
+
+		result = CallMCPTool(t, mcpClient, "ssrf_test", map[string]interface{}{"flow_id": "last", "param": "url", "categories": []interface{}{"cloud"}})
+		assert.True(t, result.IsError)
+		assert.Contains(t, ExtractMCPText(t, result), "unknown category cloud")
+
+		result = CallMCPTool(t, mcpClient, "ssrf_test", map[string]interface{}{"flow_id": "last", "param": "url", "location": "body"})
+		assert.True(t, result.IsError)
+		assert.Contains(t, ExtractMCPText(t, result), "no form, multipart or JSON body")
+
+		result = CallMCPTool(t, mcpClient, "ssrf_test", map[string]interface{}{"flow_id": "last", "param": "url", "oast_id": "missing"})
+		assert.True(t, result.IsError)
+		assert.Contains(t, ExtractMCPText(t, result), "OAST session missing not found")
+	})
+}
@@ -0,0 +1,124 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/go-harden/llm-security-toolbox/sectool/config"
+	"github.com/go-harden/llm-security-toolbox/sectool/protocol"
+)
+
+// statusHealthTimeout bounds each backend health check.
+const statusHealthTimeout = 5 * time.Second
+
+// knownCapabilities are reported for the HTTP backend in status.
+var knownCapabilities = []string{CapabilityRules, CapabilityScanner}
+
+func (m *mcpServer) statusTool() mcp.Tool {
+	return mcp.NewTool("status",
+		mcp.WithDescription(`Report service health: version, uptime, each backend (HTTP proxy, OAST, crawler) with health and capabilities, store statistics, and the limits in effect (project scope, max_output_bytes, rate_limit).
+
+Check this when tools fail unexpectedly (e.g. Burp disconnected) to decide whether to retry, wait, or switch approach.`),
+		annotateReadOnly,
+	)
+}
+
+func (m *mcpServer) handleStatus(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if err := m.requireWorkflow(); err != nil {
+		return err, nil
+	}
+
+	svc := m.service
+	resp := protocol.StatusResponse{
+		Version:      config.Version,
+		Uptime:       time.Since(svc.startedAt).Round(time.Second).String(),
+		WorkflowMode: m.workflowMode,
+		Backends: []protocol.BackendStatus{
+			backendStatus(ctx, "http", svc.httpBackend, svc.httpBackendDetail()),
+			backendStatus(ctx, "oast", svc.oastBackend, ""),
+			backendStatus(ctx, "crawler", svc.crawlerBackend, ""),
+		},
+		Metrics:        svc.healthMetrics(),
+		Scope:          m.scopeResponse(svc.projectScope()),
+		MaxOutputBytes: svc.maxOutputBytes(),
+		RateLimit:      svc.rateLimiter.status(),
+	}
+
+	resp.Backends[0].Capabilities = make(map[string]bool, len(knownCapabilities))
+	for _, capability := range knownCapabilities {
+		resp.Backends[0].Capabilities[capability] = hasCapability(svc.httpBackend, capability)
+	}
+
+	if sessions, err := svc.oastBackend.ListSessions(ctx); err == nil {
+		resp.Metrics["oast_sessions"] = strconv.Itoa(len(sessions))
+	}
+	if sessions, err := svc.crawlerBackend.ListSessions(ctx, 0); err == nil {
+		resp.Metrics["crawl_sessions"] = strconv.Itoa(len(sessions))
+	}
+
+	return jsonResult(resp)
+}
+
+// backendStatus describes backend, running its health check if it has one.
+func backendStatus(ctx context.Context, role string, backend any, detail string) protocol.BackendStatus {
+	status := protocol.BackendStatus{
+		Role:    role,
+		Name:    backendName(backend),
+		Detail:  detail,
+		Healthy: true,
+	}
+	if checker, ok := backend.(HealthChecker); ok {
+		checkCtx, cancel := context.WithTimeout(ctx, statusHealthTimeout)
+		defer cancel()
+		if err := checker.CheckHealth(checkCtx); err != nil {
+			log.Printf("mcp/status: %s backend unhealthy: %v", role, err)
+			status.Healthy = false
+			status.Error = err.Error()
+		}
+	}
+	return status
+}
+
+func backendName(backend any) string {
+	switch backend.(type) {
+	case *BurpBackend:
+		return "burp"
+	case *GoProxyBackend:
+		return "builtin"
+	case *InteractshBackend:
+		return "interactsh"
+	case *CollyBackend:
+		return "colly"
+	default:
+		return fmt.Sprintf("%T", backend)
+	}
+}
+
+// httpBackendDetail returns where the HTTP backend is reachable.
+func (s *Server) httpBackendDetail() string {
+	switch b := s.httpBackend.(type) {
+	case *BurpBackend:
+		return b.client.URL()
+	case *GoProxyBackend:
+		return "proxy listening on " + b.Addr()
+	default:
+		return ""
+	}
+}
+
+// healthMetrics evaluates all registered health metrics.
+func (s *Server) healthMetrics() map[string]string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	metrics := make(map[string]string, len(s.metricProvider)+2)
+	for key, provider := range s.metricProvider {
+		metrics[key] = provider()
+	}
+	return metrics
+}
@@ -0,0 +1,60 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/go-harden/llm-security-toolbox/sectool/config"
+	"github.com/go-harden/llm-security-toolbox/sectool/protocol"
+)
+
+func TestMCP_Status(t *testing.T) {
+	t.Parallel()
+
+	srv, mcpClient, mockMCP, _, _ := setupMCPServerWithMock(t)
+
+	resp := CallMCPToolJSONOK[protocol.StatusResponse](t, mcpClient, "status", nil)
+	assert.NotEmpty(t, resp.Version)
+	assert.Equal(t, WorkflowModeNone, resp.WorkflowMode)
+	assert.Equal(t, "0", resp.Metrics["flows"])
+	assert.Equal(t, "0", resp.Metrics["oast_sessions"])
+
+	require.Len(t, resp.Backends, 3)
+	http := resp.Backends[0]
+	assert.Equal(t, "http", http.Role)
+	assert.Equal(t, "burp", http.Name)
+	assert.Equal(t, mockMCP.URL(), http.Detail)
+	assert.True(t, http.Healthy)
+	assert.Equal(t, map[string]bool{CapabilityRules: true, CapabilityScanner: true}, http.Capabilities)
+
+	assert.False(t, resp.Scope.Defined)
+	assert.Equal(t, config.DefaultMaxOutputBytes, resp.MaxOutputBytes)
+	assert.Nil(t, resp.RateLimit)
+
+	t.Run("limits", func(t *testing.T) {
+		CallMCPToolJSONOK[protocol.ScopeResponse](t, mcpClient, "scope_set", map[string]interface{}{
+			"targets": []string{"https://app.example.com"},
+			"exclude": []string{"app.example.com/logout"},
+		})
+		srv.cfg.MaxOutputBytes = 8000
+		srv.rateLimiter = newRateLimiter(rateLimits{HostRPS: 2, Concurrent: 4, Jitter: 250 * time.Millisecond})
+
+		resp := CallMCPToolJSONOK[protocol.StatusResponse](t, mcpClient, "status", nil)
+		assert.True(t, resp.Scope.Defined)
+		assert.Equal(t, []string{"https://app.example.com"}, resp.Scope.Targets)
+		assert.Equal(t, []string{"app.example.com/logout"}, resp.Scope.Exclude)
+		assert.Equal(t, 8000, resp.MaxOutputBytes)
+		assert.Equal(t, &protocol.RateLimitStatus{HostRequestsPerSecond: 2, MaxConcurrent: 4, JitterMS: 250}, resp.RateLimit)
+	})
+
+	t.Run("burp_down", func(t *testing.T) {
+		require.NoError(t, srv.httpBackend.(*BurpBackend).client.Close())
+
+		resp := CallMCPToolJSONOK[protocol.StatusResponse](t, mcpClient, "status", nil)
+		assert.False(t, resp.Backends[0].Healthy)
+		assert.NotEmpty(t, resp.Backends[0].Error)
+	})
+}
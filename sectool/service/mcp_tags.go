@@ -0,0 +1,119 @@
+package service
+
+import (
+	"context"
+	"log"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/go-harden/llm-security-toolbox/sectool/protocol"
+)
+
+func (m *mcpServer) flowTagTool() mcp.Tool {
+	return mcp.NewTool("flow_tag",
+		mcp.WithDescription(`Tag a proxy flow or replay to organize interesting requests (e.g., 'auth', 'idor-candidate').
+
+Set exactly one of flow_id or replay_id. tags are added, then remove is applied; with neither, the current tags are returned. List tagged items with flow_list. Tags live with the flow IDs and are cleared on service restart; record lasting context with note_add.`),
+		mcp.WithString("flow_id", mcp.Description("Proxy flow ID, or "+recentRefUsage)),
+		mcp.WithString("replay_id", mcp.Description("Replay ID or label, or "+recentRefUsage)),
+		mcp.WithArray("tags", mcp.Items(map[string]interface{}{"type": "string"}), mcp.Description("Tags to add")),
+		mcp.WithArray("remove", mcp.Items(map[string]interface{}{"type": "string"}), mcp.Description("Tags to remove")),
+		annotateLocalChange,
+	)
+}
+
+func (m *mcpServer) flowListTool() mcp.Tool {
+	return mcp.NewTool("flow_list",
+		mcp.WithDescription(`List tagged flows and replays in the order first tagged, with method, URL and status.
+
+Use the returned id as flow_id (kind "flow") or replay_id (kind "replay") in proxy_get, replay_get or replay_send.`),
+		mcp.WithString("tag", mcp.Description("Only items with this tag (default: all tagged items)")),
+		annotateReadOnly,
+	)
+}
+
+func (m *mcpServer) handleFlowTag(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if err := m.requireWorkflow(); err != nil {
+		return err, nil
+	}
+
+	flowID := req.GetString("flow_id", "")
+	replayID := req.GetString("replay_id", "")
+	if (flowID == "") == (replayID == "") {
+		return errorResult("set exactly one of flow_id or replay_id"), nil
+	}
+	_, _, id, err := m.service.loadBaseRequest(ctx, flowID, replayID)
+	if err != nil {
+		return errorResultFromErr("", err), nil
+	}
+
+	tags := m.service.flowStore.Tag(id, trimValues(req.GetStringSlice("tags", nil)), trimValues(req.GetStringSlice("remove", nil)))
+	log.Printf("mcp/flow_tag: %s tags=%v", id, tags)
+	return jsonResult(protocol.FlowTagResponse{ID: id, Tags: tags})
+}
+
+func (m *mcpServer) handleFlowList(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if err := m.requireWorkflow(); err != nil {
+		return err, nil
+	}
+
+	tag := strings.TrimSpace(req.GetString("tag", ""))
+	ids := m.service.flowStore.Tagged(tag)
+	items := make([]protocol.TaggedFlow, 0, len(ids))
+	for _, id := range ids {
+		items = append(items, m.service.taggedFlow(ctx, id))
+	}
+
+	log.Printf("mcp/flow_list: tag=%q returning %d items", tag, len(items))
+	return jsonResult(protocol.FlowListResponse{Items: items})
+}
+
+// taggedFlow summarizes a tagged replay or flow. Method and URL are left empty when
+// the request is no longer available.
+func (s *Server) taggedFlow(ctx context.Context, id string) protocol.TaggedFlow {
+	item := protocol.TaggedFlow{ID: id, Kind: "flow", Tags: s.flowStore.Tags(id)}
+	if entry, ok := s.requestStore.Get(id); ok {
+		item.Kind = "replay"
+		item.Label = entry.Label
+		item.Method, item.URL = replayURL(entry)
+		item.Status, _ = parseResponseStatus(entry.Headers)
+		return item
+	}
+
+	var rawReq, target string
+	if entry, ok := s.flowStore.Lookup(id); ok {
+		proxyEntries, err := s.httpBackend.GetProxyHistory(ctx, 1, entry.Offset)
+		if err != nil || len(proxyEntries) == 0 {
+			return item
+		}
+		rawReq = proxyEntries[0].Request
+		respHeaders, _ := splitHeadersBody([]byte(proxyEntries[0].Response))
+		item.Status, _ = parseResponseStatus(respHeaders)
+	} else if req, reqTarget, _, err := s.loadBaseRequest(ctx, id, ""); err == nil {
+		rawReq, target = string(req), reqTarget
+	} else {
+		return item
+	}
+
+	method, host, path := extractRequestMeta(rawReq)
+	item.Method = method
+	if target != "" {
+		item.URL = target + path
+	} else {
+		scheme, _, _ := inferSchemeAndPort(host)
+		item.URL = scheme + "://" + host + path
+	}
+	return item
+}
+
+// trimValues trims values and drops empty ones.
+func trimValues(values []string) []string {
+	trimmed := make([]string, 0, len(values))
+	for _, v := range values {
+		if v = strings.TrimSpace(v); v != "" {
+			trimmed = append(trimmed, v)
+		}
+	}
+	return trimmed
+}
@@ -0,0 +1,91 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/go-harden/llm-security-toolbox/sectool/protocol"
+	"github.com/go-harden/llm-security-toolbox/sectool/service/store"
+)
+
+func TestMCP_FlowTags(t *testing.T) {
+	t.Parallel()
+
+	srv, mcpClient, mockMCP, _, _ := setupMCPServerWithMock(t)
+
+	mockMCP.AddProxyEntry(
+		"POST /login HTTP/1.1\r\nHost: app.test\r\n\r\nuser=a",
+		"HTTP/1.1 302 Found\r\nLocation: /\r\n\r\n",
+		"",
+	)
+	flows := CallMCPToolJSONOK[protocol.ProxyPollResponse](t, mcpClient, "proxy_poll", map[string]interface{}{
+		"output_mode": "flows",
+		"method":      "POST",
+	})
+	require.Len(t, flows.Flows, 1)
+	flowID := flows.Flows[0].FlowID
+
+	srv.requestStore.Store("rp1", &store.RequestEntry{
+		Label:   "orders",
+		Request: []byte("GET /api/orders/7 HTTP/1.1\r\nHost: app.test\r\n\r\n"),
+		Target:  "https://app.test",
+		Headers: []byte("HTTP/1.1 200 OK\r\n\r\n"),
+	})
+
+	tagged := CallMCPToolJSONOK[protocol.FlowTagResponse](t, mcpClient, "flow_tag", map[string]interface{}{
+		"flow_id": flowID,
+		"tags":    []string{"auth", " login "},
+	})
+	assert.Equal(t, flowID, tagged.ID)
+	assert.Equal(t, []string{"auth", "login"}, tagged.Tags)
+
+	tagged = CallMCPToolJSONOK[protocol.FlowTagResponse](t, mcpClient, "flow_tag", map[string]interface{}{
+		"replay_id": "orders",
+		"tags":      []string{"idor", "auth"},
+	})
+	assert.Equal(t, "rp1", tagged.ID)
+
+	t.Run("list_all", func(t *testing.T) {
+		resp := CallMCPToolJSONOK[protocol.FlowListResponse](t, mcpClient, "flow_list", nil)
+		require.Len(t, resp.Items, 2)
+
+		assert.Equal(t, protocol.TaggedFlow{
+			ID: flowID, Kind: "flow", Tags: []string{"auth", "login"},
+			Method: "POST", URL: "https://app.test/login", Status: 302,
+		}, resp.Items[0])
+		assert.Equal(t, protocol.TaggedFlow{
+			ID: "rp1", Kind: "replay", Tags: []string{"auth", "idor"},
+			Method: "GET", URL: "https://app.test/api/orders/7", Status: 200, Label: "orders",
+		}, resp.Items[1])
+	})
+
+	t.Run("list_by_tag", func(t *testing.T) {
+		resp := CallMCPToolJSONOK[protocol.FlowListResponse](t, mcpClient, "flow_list", map[string]interface{}{"tag": "idor"})
+		require.Len(t, resp.Items, 1)
+		assert.Equal(t, "rp1", resp.Items[0].ID)
+
+		history := CallMCPToolJSONOK[protocol.ReplayHistoryResponse](t, mcpClient, "replay_history", nil)
+		require.Len(t, history.Replays, 1)
+		assert.Equal(t, []string{"auth", "idor"}, history.Replays[0].Tags)
+	})
+
+	t.Run("remove", func(t *testing.T) {
+		resp := CallMCPToolJSONOK[protocol.FlowTagResponse](t, mcpClient, "flow_tag", map[string]interface{}{
+			"flow_id": flowID,
+			"remove":  []string{"login"},
+		})
+		assert.Equal(t, []string{"auth"}, resp.Tags)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		assert.True(t, CallMCPTool(t, mcpClient, "flow_tag", map[string]interface{}{"tags": []string{"x"}}).IsError)
+		assert.True(t, CallMCPTool(t, mcpClient, "flow_tag", map[string]interface{}{
+			"flow_id": flowID, "replay_id": "rp1",
+		}).IsError)
+		assert.True(t, CallMCPTool(t, mcpClient, "flow_tag", map[string]interface{}{
+			"flow_id": "nope", "tags": []string{"x"},
+		}).IsError)
+	})
+}
@@ -70,9 +70,12 @@ type TestMCPServer struct {
 
 	mu               sync.Mutex
 	proxyHistory     []testProxyEntry
+	wsHistory        []testWSEntry
 	sendResponses    []string // Stack of responses for send_http1_request
 	matchReplaceHTTP []testMatchReplaceRule
 	matchReplaceWS   []testMatchReplaceRule
+	configEditingOff bool
+	scannerIssues    []string // JSON objects returned by get_scanner_issues
 }
 
 type testMatchReplaceRule struct {
@@ -91,6 +94,11 @@ type testProxyEntry struct {
 	Notes    string `json:"notes"`
 }
 
+type testWSEntry struct {
+	Direction string `json:"direction"`
+	Payload   string `json:"payload"`
+}
+
 // NewTestMCPServer creates a mock MCP server for testing.
 func NewTestMCPServer(t *testing.T) *TestMCPServer {
 	t.Helper()
@@ -171,6 +179,34 @@ func NewTestMCPServer(t *testing.T) *TestMCPServer {
 		},
 	)
 
+	mcpServer.AddTool(
+		mcp.NewTool("get_proxy_websocket_history",
+			mcp.WithDescription("Get proxy WebSocket history"),
+			mcp.WithNumber("count", mcp.Description("Number of entries to return")),
+			mcp.WithNumber("offset", mcp.Description("Offset to start from")),
+		),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			ts.mu.Lock()
+			defer ts.mu.Unlock()
+
+			args := req.Params.Arguments.(map[string]any)
+			count := int(args["count"].(float64))
+			offset := int(args["offset"].(float64))
+
+			if offset >= len(ts.wsHistory) {
+				return mcp.NewToolResultText("Reached end of items"), nil
+			}
+
+			var sb strings.Builder
+			for _, entry := range ts.wsHistory[offset:min(offset+count, len(ts.wsHistory))] {
+				line, _ := json.Marshal(entry)
+				sb.Write(line)
+				sb.WriteByte('\n')
+			}
+			return mcp.NewToolResultText(sb.String()), nil
+		},
+	)
+
 	mcpServer.AddTool(
 		mcp.NewTool("send_http1_request",
 			mcp.WithDescription("Send HTTP/1.1 request"),
@@ -249,6 +285,10 @@ func NewTestMCPServer(t *testing.T) *TestMCPServer {
 			ts.mu.Lock()
 			defer ts.mu.Unlock()
 
+			if ts.configEditingOff {
+				return mcp.NewToolResultError("User has disabled configuration editing. They can enable it in the MCP tab in Burp."), nil
+			}
+
 			args := req.Params.Arguments.(map[string]any)
 			jsonStr := args["json"].(string)
 
@@ -272,6 +312,27 @@ func NewTestMCPServer(t *testing.T) *TestMCPServer {
 		},
 	)
 
+	mcpServer.AddTool(
+		mcp.NewTool("get_scanner_issues",
+			mcp.WithDescription("Get scanner issues"),
+			mcp.WithNumber("count", mcp.Description("Number of issues to return")),
+			mcp.WithNumber("offset", mcp.Description("Offset to start from")),
+		),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			ts.mu.Lock()
+			defer ts.mu.Unlock()
+
+			args := req.Params.Arguments.(map[string]any)
+			count := int(args["count"].(float64))
+			offset := int(args["offset"].(float64))
+			if offset >= len(ts.scannerIssues) {
+				return mcp.NewToolResultText("Reached end of items"), nil
+			}
+			// Burp separates issues with a blank line
+			return mcp.NewToolResultText(strings.Join(ts.scannerIssues[offset:min(offset+count, len(ts.scannerIssues))], "\n\n")), nil
+		},
+	)
+
 	httpServer := mcpserver.NewTestServer(mcpServer)
 
 	ts.HTTPServer = httpServer
@@ -285,6 +346,13 @@ func (t *TestMCPServer) URL() string {
 	return t.HTTPServer.URL + "/sse"
 }
 
+// SetConfigEditingDisabled makes set_project_options fail like Burp with 'Edit config' off.
+func (t *TestMCPServer) SetConfigEditingDisabled(disabled bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.configEditingOff = disabled
+}
+
 // AddProxyEntry adds an entry to the mock proxy history.
 func (t *TestMCPServer) AddProxyEntry(request, response, notes string) {
 	t.mu.Lock()
@@ -303,6 +371,14 @@ func (t *TestMCPServer) AddProxyEntries(entries ...testProxyEntry) {
 	t.proxyHistory = append(t.proxyHistory, entries...)
 }
 
+// AddWebSocketMessage adds a message to the mock WebSocket history, with Burp's
+// CLIENT_TO_SERVER / SERVER_TO_CLIENT direction names.
+func (t *TestMCPServer) AddWebSocketMessage(direction, payload string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.wsHistory = append(t.wsHistory, testWSEntry{Direction: direction, Payload: payload})
+}
+
 // SetSendResponse sets the response for the next send_http1_request call.
 func (t *TestMCPServer) SetSendResponse(response string) {
 	t.mu.Lock()
@@ -310,6 +386,13 @@ func (t *TestMCPServer) SetSendResponse(response string) {
 	t.sendResponses = append(t.sendResponses, response)
 }
 
+// AddScannerIssue adds an issue, as Burp's JSON, to the mock scanner issues.
+func (t *TestMCPServer) AddScannerIssue(issueJSON string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.scannerIssues = append(t.scannerIssues, issueJSON)
+}
+
 // ClearProxyHistory clears all proxy history entries.
 func (t *TestMCPServer) ClearProxyHistory() {
 	t.mu.Lock()
@@ -0,0 +1,104 @@
+package service
+
+import (
+	"context"
+	"crypto/tls"
+	"log"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/go-harden/llm-security-toolbox/sectool/protocol"
+)
+
+func (m *mcpServer) tlsProbeTool() mcp.Tool {
+	return mcp.NewTool("tls_probe",
+		mcp.WithDescription(`Connect to a TLS service and report its configuration: protocol versions, accepted cipher suites, ALPN, the certificate chain with expiry, trust and key details, and weak configurations as issues.
+
+One handshake is made per version (TLS 1.0-1.3) and, with ciphers (default true), per cipher suite for TLS 1.0-1.2, each on a new connection; TLS 1.3 reports the negotiated suite. SSL 2.0/3.0 cannot be tested.
+Connections are made directly from sectool, not through the HTTP backend. Project scope applies to host:port.
+Issues have severity high, medium, low or info; add confirmed ones with finding_add.`),
+		mcp.WithString("host", mcp.Required(), mcp.Description("Hostname or IP address")),
+		mcp.WithNumber("port", mcp.Description("TCP port (default 443)")),
+		mcp.WithString("sni", mcp.Description("TLS server name and name to verify (default: host)")),
+		mcp.WithBoolean("ciphers", mcp.Description("Enumerate accepted cipher suites (default true; about 25 handshakes per version)")),
+		mcp.WithString("timeout", mcp.Description("Limit for the whole probe (e.g., '60s', default 2m)")),
+		annotateSendsTraffic,
+	)
+}
+
+func (m *mcpServer) handleTLSProbe(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if err := m.requireWorkflow(); err != nil {
+		return err, nil
+	}
+
+	in := tlsProbeInput{
+		Host:    req.GetString("host", ""),
+		Port:    req.GetInt("port", 443),
+		SNI:     req.GetString("sni", ""),
+		Ciphers: req.GetBool("ciphers", true),
+	}
+	if in.Host == "" {
+		return errorResult("host is required"), nil
+	} else if in.Port < 1 || in.Port > 65535 {
+		return errorResult("port must be between 1 and 65535"), nil
+	}
+	timeout := defaultTLSProbeTimeout
+	if s := req.GetString("timeout", ""); s != "" {
+		d, err := time.ParseDuration(s)
+		if err != nil || d <= 0 {
+			return errorResult("invalid timeout duration: " + s), nil
+		}
+		timeout = d
+	}
+
+	addr := net.JoinHostPort(in.Host, strconv.Itoa(in.Port))
+	if err := m.service.guard.CheckTarget(ctx, m.service.projectScope(), addr, "/"); err != nil {
+		return errorResult(err.Error()), nil
+	}
+
+	log.Printf("mcp/tls_probe: probing %s (ciphers=%v)", addr, in.Ciphers)
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	result, err := probeTLS(ctx, in)
+	if err != nil {
+		return errorResultFromErr("tls probe failed: ", err), nil
+	}
+
+	now := time.Now()
+	serverName := in.SNI
+	if serverName == "" {
+		serverName = in.Host
+	}
+	trustErr := verifyTLSChain(result.chain, serverName, now)
+	resp := protocol.TLSProbeResponse{
+		Target:       addr,
+		SNI:          in.SNI,
+		ALPN:         result.alpn,
+		Certificates: make([]protocol.TLSCertificate, 0, len(result.chain)),
+		Trusted:      trustErr == nil,
+		Issues:       tlsIssues(result, trustErr, now),
+		Untested:     tlsUntested,
+	}
+	if trustErr != nil {
+		resp.TrustError = trustErr.Error()
+	}
+	for _, v := range result.versions {
+		version := protocol.TLSVersion{Version: tls.VersionName(v.version), Supported: v.supported}
+		for _, id := range v.ciphers {
+			version.Ciphers = append(version.Ciphers, protocol.TLSCipher{Name: tls.CipherSuiteName(id), Insecure: tlsSuiteInsecure(id)})
+		}
+		resp.Versions = append(resp.Versions, version)
+	}
+	for _, cert := range result.chain {
+		resp.Certificates = append(resp.Certificates, tlsCertificateSummary(cert, now))
+	}
+	if resp.Issues == nil {
+		resp.Issues = []protocol.TLSIssue{}
+	}
+
+	log.Printf("mcp/tls_probe: %s done, %d issues", addr, len(resp.Issues))
+	return jsonResult(resp)
+}
@@ -0,0 +1,59 @@
+package service
+
+import (
+	"crypto/tls"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/go-harden/llm-security-toolbox/sectool/protocol"
+)
+
+func TestMCP_TLSProbe(t *testing.T) {
+	t.Parallel()
+
+	_, mcpClient, _, _, _ := setupMCPServerWithMock(t)
+
+	t.Run("probe", func(t *testing.T) {
+		host, port := newTLS12Server(t, tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256)
+		resp := CallMCPToolJSONOK[protocol.TLSProbeResponse](t, mcpClient, "tls_probe", map[string]interface{}{
+			"host":    host,
+			"port":    port,
+			"ciphers": false,
+		})
+		require.Len(t, resp.Versions, 4)
+		assert.Equal(t, "TLS 1.2", resp.Versions[2].Version)
+		assert.True(t, resp.Versions[2].Supported)
+		assert.Equal(t, []protocol.TLSCipher{{Name: "TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256"}}, resp.Versions[2].Ciphers)
+		assert.False(t, resp.Versions[3].Supported)
+		require.NotEmpty(t, resp.Certificates)
+		assert.False(t, resp.Trusted)
+		assert.NotEmpty(t, resp.TrustError)
+		assert.NotEmpty(t, resp.Untested)
+	})
+
+	t.Run("validation", func(t *testing.T) {
+		result := CallMCPTool(t, mcpClient, "tls_probe", map[string]interface{}{})
+		assert.True(t, result.IsError)
+		assert.Contains(t, ExtractMCPText(t, result), "host is required")
+		result = CallMCPTool(t, mcpClient, "tls_probe", map[string]interface{}{"host": "example.com", "port": 70000})
+		assert.True(t, result.IsError)
+		assert.Contains(t, ExtractMCPText(t, result), "port must be")
+		result = CallMCPTool(t, mcpClient, "tls_probe", map[string]interface{}{"host": "example.com", "timeout": "soon"})
+		assert.True(t, result.IsError)
+		assert.Contains(t, ExtractMCPText(t, result), "invalid timeout")
+	})
+
+	t.Run("out_of_scope", func(t *testing.T) {
+		CallMCPToolJSONOK[protocol.ScopeResponse](t, mcpClient, "scope_set", map[string]interface{}{
+			"targets": []string{"https://app.example.com"},
+		})
+		t.Cleanup(func() {
+			CallMCPToolJSONOK[protocol.ScopeResponse](t, mcpClient, "scope_set", map[string]interface{}{"clear": true})
+		})
+		result := CallMCPTool(t, mcpClient, "tls_probe", map[string]interface{}{"host": "evil.com"})
+		assert.True(t, result.IsError)
+		assert.Contains(t, ExtractMCPText(t, result), "out of scope")
+	})
+}
@@ -0,0 +1,359 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http/httputil"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/go-harden/llm-security-toolbox/sectool/protocol"
+)
+
+const (
+	maxWSPayloadPreview = 2000 // runes of each message payload shown
+	defaultWSListLimit  = 100
+	defaultWSWait       = 2 * time.Second
+	defaultWSMaxReplies = 50
+	wsHandshakeTimeout  = 30 * time.Second
+)
+
+func (m *mcpServer) wsListTool() mcp.Tool {
+	return mcp.NewTool("ws_list",
+		mcp.WithDescription(`List captured WebSocket messages from proxy history, oldest first.
+
+Filters: host (glob, built-in proxy only since Burp does not report the connection URL), direction (to-server or to-client), contains (payload text).
+Payloads are previews; binary payloads show as "<BINARY:N Bytes>". Resend a message in full with ws_send message_id.
+The built-in proxy captures ws:// connections only; wss:// traffic needs the Burp backend.
+Pagination: when more messages match than limit (default 100), pass next_cursor back as cursor with the same filters.`),
+		mcp.WithString("host", mcp.Description("Filter by connection host (glob pattern)")),
+		mcp.WithString("direction", mcp.Description("Filter by direction: 'to-server' or 'to-client'")),
+		mcp.WithString("contains", mcp.Description("Filter by text in the payload")),
+		mcp.WithString("since", mcp.Description("Messages after this message_id")),
+		mcp.WithNumber("limit", mcp.Description("Max messages to return (default 100)")),
+		mcp.WithString("cursor", mcp.Description("next_cursor from a previous page")),
+		annotateReadOnly,
+	)
+}
+
+func (m *mcpServer) wsSendTool() mcp.Tool {
+	return mcp.NewTool("ws_send",
+		mcp.WithDescription(`Open a WebSocket connection, send messages, and collect the replies.
+
+Connection (exactly one): url (ws:// or wss://), or flow_id of a captured upgrade request to reuse its path, cookies and auth headers. add_headers/remove_headers edit the handshake.
+Messages: messages are sent as text frames in order; message_id resends a captured message (from ws_list) with its original opcode. Both may be combined; message_id goes first.
+Replies are collected until wait (default 2s) passes without a new message, max_replies (default 50) arrive, or the server closes.
+The connection is made directly from sectool, not through the HTTP backend, so it does not appear in proxy history. Project scope applies to the handshake.`),
+		mcp.WithString("url", mcp.Description("WebSocket URL (ws:// or wss://)")),
+		mcp.WithString("flow_id", mcp.Description("Proxy flow of the upgrade request, or "+recentRefUsage)),
+		mcp.WithString("target", mcp.Description("With flow_id: destination override (e.g., 'ws://host:8080'); default from the Host header, TLS unless port 80")),
+		mcp.WithArray("messages", mcp.Items(map[string]interface{}{"type": "string"}), mcp.Description("Text messages to send in order")),
+		mcp.WithString("message_id", mcp.Description("Captured message from ws_list to resend")),
+		mcp.WithArray("add_headers", mcp.Items(map[string]interface{}{"type": "string"}), mcp.Description("Handshake headers to add or replace ('Name: Value')")),
+		mcp.WithArray("remove_headers", mcp.Items(map[string]interface{}{"type": "string"}), mcp.Description("Handshake header names to remove")),
+		mcp.WithString("wait", mcp.Description("Idle time to wait for replies after sending (e.g., '2s')")),
+		mcp.WithNumber("max_replies", mcp.Description("Stop after this many replies (default 50)")),
+		mcp.WithString("timeout", mcp.Description("Handshake timeout (e.g., '30s')")),
+		annotateSendsTraffic,
+	)
+}
+
+func (m *mcpServer) handleWSList(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if err := m.requireWorkflow(); err != nil {
+		return err, nil
+	}
+
+	direction := req.GetString("direction", "")
+	if direction != "" && direction != WSDirectionToServer && direction != WSDirectionToClient {
+		return errorResult("direction must be 'to-server' or 'to-client'"), nil
+	}
+	host := req.GetString("host", "")
+	contains := req.GetString("contains", "")
+	limit := req.GetInt("limit", defaultWSListLimit)
+	if limit <= 0 {
+		limit = defaultWSListLimit
+	}
+
+	var after int64 = -1
+	if cursor := req.GetString("cursor", ""); cursor != "" {
+		pos, err := decodeCursor("ws_list", cursor)
+		if err != nil {
+			return errorResult(err.Error()), nil
+		}
+		n, err := strconv.ParseUint(pos, 10, 32)
+		if err != nil {
+			return errorResult("invalid cursor for ws_list"), nil
+		}
+		after = int64(n)
+	} else if since := req.GetString("since", ""); since != "" {
+		entry, ok := m.service.wsStore.Lookup(since)
+		if !ok {
+			return errorResult("message_id not found: run ws_list to see available messages"), nil
+		}
+		after = int64(entry.Offset)
+	}
+
+	source, ok := m.service.httpBackend.(WebSocketHistorySource)
+	if !ok {
+		return errorResult("the HTTP backend does not capture WebSocket history"), nil
+	}
+
+	log.Printf("mcp/ws_list: host=%q direction=%q contains=%q", host, direction, contains)
+
+	resp := protocol.WSListResponse{Messages: []protocol.WSMessage{}}
+	var offset uint32
+	if after >= 0 {
+		offset = uint32(after) + 1
+	}
+	for {
+		batch, err := source.GetWebSocketHistory(ctx, fetchBatchSize, offset)
+		if err != nil {
+			return errorResultFromErr("failed to fetch WebSocket history: ", err), nil
+		}
+		for i, msg := range batch {
+			msgOffset := offset + uint32(i)
+			if direction != "" && msg.Direction != direction {
+				continue
+			} else if contains != "" && !bytes.Contains(msg.Payload, []byte(contains)) {
+				continue
+			} else if host != "" && !matchesGlob(wsMessageHost(msg.URL), host) {
+				continue
+			}
+			if len(resp.Messages) == limit {
+				last := resp.Messages[len(resp.Messages)-1].MessageID
+				entry, _ := m.service.wsStore.Lookup(last)
+				resp.NextCursor = encodeCursor("ws_list", strconv.FormatUint(uint64(entry.Offset), 10))
+				return jsonResult(resp)
+			}
+			wm := wsMessageResponse(msg)
+			wm.MessageID = m.service.wsStore.Register(msgOffset, "")
+			resp.Messages = append(resp.Messages, wm)
+		}
+		offset += uint32(len(batch))
+		if len(batch) < fetchBatchSize {
+			break
+		}
+	}
+	log.Printf("mcp/ws_list: returning %d messages", len(resp.Messages))
+	return jsonResult(resp)
+}
+
+func (m *mcpServer) handleWSSend(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if err := m.requireWorkflow(); err != nil {
+		return err, nil
+	}
+
+	wsURL := req.GetString("url", "")
+	flowID := req.GetString("flow_id", "")
+	if (wsURL == "") == (flowID == "") {
+		return errorResult("exactly one of url or flow_id is required"), nil
+	}
+
+	type outgoing struct {
+		opcode  byte
+		payload []byte
+	}
+	var sends []outgoing
+	if messageID := req.GetString("message_id", ""); messageID != "" {
+		msg, err := m.service.lookupWSMessage(ctx, messageID)
+		if err != nil {
+			return errorResultFromErr("", err), nil
+		}
+		opcode := byte(1)
+		if msg.Opcode == "binary" {
+			opcode = 2
+		}
+		sends = append(sends, outgoing{opcode: opcode, payload: msg.Payload})
+	}
+	for _, text := range req.GetStringSlice("messages", nil) {
+		sends = append(sends, outgoing{opcode: 1, payload: []byte(text)})
+	}
+	if len(sends) == 0 {
+		return errorResult("messages or message_id is required"), nil
+	}
+
+	wait := defaultWSWait
+	if s := req.GetString("wait", ""); s != "" {
+		d, err := time.ParseDuration(s)
+		if err != nil || d <= 0 {
+			return errorResult("invalid wait duration: " + s), nil
+		}
+		wait = d
+	}
+	timeout := wsHandshakeTimeout
+	if s := req.GetString("timeout", ""); s != "" {
+		d, err := time.ParseDuration(s)
+		if err != nil || d <= 0 {
+			return errorResult("invalid timeout duration: " + s), nil
+		}
+		timeout = d
+	}
+	maxReplies := req.GetInt("max_replies", defaultWSMaxReplies)
+	if maxReplies <= 0 {
+		maxReplies = defaultWSMaxReplies
+	}
+
+	var rawRequest []byte
+	var target Target
+	if wsURL != "" {
+		u, err := url.Parse(wsURL)
+		if err != nil || u.Host == "" || (u.Scheme != "ws" && u.Scheme != "wss") {
+			return errorResult("invalid url: must be ws://host/path or wss://host/path"), nil
+		}
+		httpURL := *u
+		httpURL.Scheme = schemeHTTP
+		if u.Scheme == "wss" {
+			httpURL.Scheme = schemeHTTPS
+		}
+		rawRequest = buildRawRequest("GET", &httpURL, nil, nil)
+		target = targetFromURL(&httpURL)
+	} else {
+		resolved, err := m.service.resolveFlowRef(ctx, flowID)
+		if err != nil {
+			return errorResultFromErr("", err), nil
+		}
+		entry, ok := m.service.flowStore.Lookup(resolved)
+		if !ok {
+			return errorResult("flow_id not found: run proxy_poll to see available flows"), nil
+		}
+		proxyEntries, err := m.service.httpBackend.GetProxyHistory(ctx, 1, entry.Offset)
+		if err != nil {
+			return errorResultFromErr("failed to fetch flow: ", err), nil
+		} else if len(proxyEntries) == 0 {
+			return errorResult("flow not found in proxy history"), nil
+		}
+		headers, _ := splitHeadersBody([]byte(proxyEntries[0].Request))
+		rawRequest = modifyRequestLine(headers, &PathQueryOpts{Method: "GET"})
+		targetOverride := req.GetString("target", "")
+		if rest, ok := strings.CutPrefix(targetOverride, "wss://"); ok {
+			targetOverride = schemeHTTPS + "://" + rest
+		} else if rest, ok := strings.CutPrefix(targetOverride, "ws://"); ok {
+			targetOverride = schemeHTTP + "://" + rest
+		}
+		host, port, usesHTTPS := parseTarget(rawRequest, targetOverride)
+		target = Target{Hostname: host, Port: port, UsesHTTPS: usesHTTPS}
+	}
+
+	key := newWSKey()
+	rawRequest = applyHeaderModifications(rawRequest, &ReplaySendRequest{
+		AddHeaders:    req.GetStringSlice("add_headers", nil),
+		RemoveHeaders: req.GetStringSlice("remove_headers", nil),
+	})
+	rawRequest = removeHeader(rawRequest, "Content-Length")
+	rawRequest = removeHeader(rawRequest, "Sec-WebSocket-Extensions") // no compression
+	rawRequest = setHeader(rawRequest, "Upgrade", "websocket")
+	rawRequest = setHeader(rawRequest, "Connection", "Upgrade")
+	rawRequest = setHeader(rawRequest, "Sec-WebSocket-Version", "13")
+	rawRequest = setHeader(rawRequest, "Sec-WebSocket-Key", key)
+
+	if err := m.service.guard.CheckRequest(ctx, m.service.projectScope(), SendRequestInput{RawRequest: rawRequest, Target: target}); err != nil {
+		return errorResultFromErr("", err), nil
+	}
+
+	release, err := m.service.rateLimiter.Wait(ctx, target.Hostname)
+	if err != nil {
+		return errorResultFromErr("", err), nil
+	}
+	defer release()
+
+	connURL := wsConnURL(target, extractRequestPath(rawRequest))
+	log.Printf("mcp/ws_send: connecting to %s (%d messages)", connURL, len(sends))
+
+	start := time.Now()
+	dialCtx, cancel := context.WithTimeout(ctx, timeout)
+	client, handshake, err := dialWSClient(dialCtx, target, rawRequest, key)
+	cancel()
+	if err != nil {
+		return errorResultFromErr("websocket handshake failed: ", err), nil
+	}
+
+	resp := protocol.WSSendResponse{
+		URL:      connURL,
+		Status:   handshake.StatusCode,
+		Messages: []protocol.WSMessage{},
+	}
+	if client == nil {
+		dump, _ := httputil.DumpResponse(handshake, false)
+		resp.RespHeaders = string(dump)
+		resp.Duration = time.Since(start).Round(time.Millisecond).String()
+		return jsonResult(resp)
+	}
+	defer client.close()
+
+	for _, s := range sends {
+		if err := client.send(s.opcode, s.payload); err != nil {
+			return errorResultFromErr(fmt.Sprintf("send message %d: ", resp.Sent+1), err), nil
+		}
+		resp.Sent++
+	}
+
+	replies, closed, err := client.receive(wait, maxReplies)
+	if err != nil && len(replies) == 0 {
+		return errorResultFromErr("receive: ", err), nil
+	}
+	for _, msg := range replies {
+		resp.Messages = append(resp.Messages, wsMessageResponse(msg))
+	}
+	resp.Closed = closed
+	resp.Duration = time.Since(start).Round(time.Millisecond).String()
+
+	log.Printf("mcp/ws_send: sent %d, received %d (closed=%v)", resp.Sent, len(resp.Messages), closed)
+	return jsonResult(resp)
+}
+
+// lookupWSMessage fetches a captured WebSocket message by message_id.
+func (s *Server) lookupWSMessage(ctx context.Context, messageID string) (*WebSocketMessage, error) {
+	entry, ok := s.wsStore.Lookup(messageID)
+	if !ok {
+		return nil, errors.New("message_id not found: run ws_list to see available messages")
+	}
+	source, ok := s.httpBackend.(WebSocketHistorySource)
+	if !ok {
+		return nil, errors.New("the HTTP backend does not capture WebSocket history")
+	}
+	msgs, err := source.GetWebSocketHistory(ctx, 1, entry.Offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch WebSocket message: %w", err)
+	} else if len(msgs) == 0 {
+		return nil, errors.New("message not found in WebSocket history")
+	}
+	return &msgs[0], nil
+}
+
+func wsMessageResponse(msg WebSocketMessage) protocol.WSMessage {
+	wm := protocol.WSMessage{
+		URL:       msg.URL,
+		Direction: msg.Direction,
+		Opcode:    msg.Opcode,
+		Size:      len(msg.Payload),
+		Payload:   previewBody(msg.Payload, maxWSPayloadPreview),
+	}
+	if !msg.Time.IsZero() {
+		wm.Time = msg.Time.UTC().Format(time.RFC3339)
+	}
+	return wm
+}
+
+// wsMessageHost returns the host of a ws(s):// URL, or "" when unknown.
+func wsMessageHost(rawURL string) string {
+	if u, err := url.Parse(rawURL); err == nil {
+		return u.Hostname()
+	}
+	return ""
+}
+
+// wsConnURL formats the ws(s):// URL of a connection to target.
+func wsConnURL(target Target, path string) string {
+	origin := target.origin()
+	if rest, ok := strings.CutPrefix(origin, schemeHTTPS+"://"); ok {
+		return "wss://" + rest + path
+	}
+	return "ws://" + strings.TrimPrefix(origin, schemeHTTP+"://") + path
+}
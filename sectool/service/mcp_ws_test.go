@@ -0,0 +1,190 @@
+package service
+
+import (
+	"bufio"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/go-harden/llm-security-toolbox/sectool/protocol"
+)
+
+// newWSEchoServer starts a WebSocket server echoing each message prefixed with "echo:".
+// A message "bye" makes the server close the connection.
+func newWSEchoServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !isWebSocketUpgrade(r) {
+			http.Error(w, "upgrade required", http.StatusUpgradeRequired)
+			return
+		}
+		conn, buf, err := w.(http.Hijacker).Hijack()
+		if err != nil {
+			return
+		}
+		defer func() { _ = conn.Close() }()
+
+		_, _ = conn.Write([]byte("HTTP/1.1 101 Switching Protocols\r\nUpgrade: websocket\r\nConnection: Upgrade\r\n" +
+			"Sec-WebSocket-Accept: " + wsAccept(r.Header.Get("Sec-WebSocket-Key")) + "\r\n\r\n"))
+		r2 := bufio.NewReader(buf)
+		for {
+			frame, err := readWSFrame(r2)
+			if err != nil || frame.opcode == 8 {
+				return
+			}
+			if string(frame.payload) == "bye" {
+				_, _ = conn.Write(encodeWSFrame(&wsFrame{fin: true, opcode: 8}))
+				return
+			}
+			reply := &wsFrame{fin: true, opcode: frame.opcode, payload: append([]byte("echo:"), frame.payload...)}
+			if _, err := conn.Write(encodeWSFrame(reply)); err != nil {
+				return
+			}
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestMCP_WSList(t *testing.T) {
+	t.Parallel()
+
+	_, mcpClient, mockMCP, _, _ := setupMCPServerWithMock(t)
+	mockMCP.AddWebSocketMessage("CLIENT_TO_SERVER", `{"op":"subscribe","channel":"orders"}`)
+	mockMCP.AddWebSocketMessage("SERVER_TO_CLIENT", `{"op":"ack"}`)
+	mockMCP.AddWebSocketMessage("CLIENT_TO_SERVER", `{"op":"get","id":42}`)
+
+	t.Run("all", func(t *testing.T) {
+		resp := CallMCPToolJSONOK[protocol.WSListResponse](t, mcpClient, "ws_list", nil)
+		require.Len(t, resp.Messages, 3)
+		assert.Equal(t, WSDirectionToServer, resp.Messages[0].Direction)
+		assert.Equal(t, WSDirectionToClient, resp.Messages[1].Direction)
+		assert.Equal(t, len(`{"op":"ack"}`), resp.Messages[1].Size)
+		assert.NotEmpty(t, resp.Messages[0].MessageID)
+		assert.Empty(t, resp.NextCursor)
+	})
+
+	t.Run("filters", func(t *testing.T) {
+		resp := CallMCPToolJSONOK[protocol.WSListResponse](t, mcpClient, "ws_list", map[string]interface{}{
+			"direction": "to-server",
+			"contains":  `"id":42`,
+		})
+		require.Len(t, resp.Messages, 1)
+		assert.Equal(t, `{"op":"get","id":42}`, resp.Messages[0].Payload)
+	})
+
+	t.Run("cursor", func(t *testing.T) {
+		page := CallMCPToolJSONOK[protocol.WSListResponse](t, mcpClient, "ws_list", map[string]interface{}{"limit": 2})
+		require.Len(t, page.Messages, 2)
+		require.NotEmpty(t, page.NextCursor)
+
+		next := CallMCPToolJSONOK[protocol.WSListResponse](t, mcpClient, "ws_list", map[string]interface{}{"cursor": page.NextCursor})
+		require.Len(t, next.Messages, 1)
+		assert.Contains(t, next.Messages[0].Payload, `"id":42`)
+
+		since := CallMCPToolJSONOK[protocol.WSListResponse](t, mcpClient, "ws_list", map[string]interface{}{"since": page.Messages[0].MessageID})
+		assert.Len(t, since.Messages, 2)
+	})
+
+	t.Run("invalid_direction", func(t *testing.T) {
+		result := CallMCPTool(t, mcpClient, "ws_list", map[string]interface{}{"direction": "up"})
+		assert.True(t, result.IsError)
+	})
+}
+
+func TestMCP_WSSend(t *testing.T) {
+	t.Parallel()
+
+	srv, mcpClient, mockMCP, _, _ := setupMCPServerWithMock(t)
+	echo := newWSEchoServer(t)
+	wsURL := "ws" + strings.TrimPrefix(echo.URL, "http") + "/socket"
+
+	t.Run("messages", func(t *testing.T) {
+		resp := CallMCPToolJSONOK[protocol.WSSendResponse](t, mcpClient, "ws_send", map[string]interface{}{
+			"url":      wsURL,
+			"messages": []interface{}{"one", "two"},
+			"wait":     "300ms",
+		})
+		assert.Equal(t, http.StatusSwitchingProtocols, resp.Status)
+		assert.Equal(t, wsURL, resp.URL)
+		assert.Equal(t, 2, resp.Sent)
+		require.Len(t, resp.Messages, 2)
+		assert.Equal(t, "echo:one", resp.Messages[0].Payload)
+		assert.Equal(t, "echo:two", resp.Messages[1].Payload)
+		assert.Equal(t, WSDirectionToClient, resp.Messages[0].Direction)
+		assert.False(t, resp.Closed)
+	})
+
+	t.Run("server_close", func(t *testing.T) {
+		resp := CallMCPToolJSONOK[protocol.WSSendResponse](t, mcpClient, "ws_send", map[string]interface{}{
+			"url":      wsURL,
+			"messages": []interface{}{"hi", "bye"},
+		})
+		require.Len(t, resp.Messages, 1)
+		assert.True(t, resp.Closed)
+	})
+
+	t.Run("message_id", func(t *testing.T) {
+		mockMCP.AddWebSocketMessage("CLIENT_TO_SERVER", "captured")
+		list := CallMCPToolJSONOK[protocol.WSListResponse](t, mcpClient, "ws_list", map[string]interface{}{"contains": "captured"})
+		require.Len(t, list.Messages, 1)
+
+		resp := CallMCPToolJSONOK[protocol.WSSendResponse](t, mcpClient, "ws_send", map[string]interface{}{
+			"url":        wsURL,
+			"message_id": list.Messages[0].MessageID,
+			"wait":       "300ms",
+		})
+		require.Len(t, resp.Messages, 1)
+		assert.Equal(t, "echo:captured", resp.Messages[0].Payload)
+	})
+
+	t.Run("flow_id", func(t *testing.T) {
+		host := strings.TrimPrefix(echo.URL, "http://")
+		mockMCP.AddProxyEntry(
+			"GET /socket HTTP/1.1\r\nHost: "+host+"\r\nCookie: session=abc\r\nUpgrade: websocket\r\nConnection: Upgrade\r\n"+
+				"Sec-WebSocket-Key: old\r\nSec-WebSocket-Version: 13\r\n\r\n",
+			"HTTP/1.1 101 Switching Protocols\r\nUpgrade: websocket\r\n\r\n", "")
+		flowID, err := srv.resolveFlowRef(t.Context(), "last")
+		require.NoError(t, err)
+
+		resp := CallMCPToolJSONOK[protocol.WSSendResponse](t, mcpClient, "ws_send", map[string]interface{}{
+			"flow_id":  flowID,
+			"target":   "ws://" + host,
+			"messages": []interface{}{"from-flow"},
+			"wait":     "300ms",
+		})
+		assert.Equal(t, "ws://"+host+"/socket", resp.URL)
+		require.Len(t, resp.Messages, 1)
+		assert.Equal(t, "echo:from-flow", resp.Messages[0].Payload)
+	})
+
+	t.Run("not_upgraded", func(t *testing.T) {
+		plain := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, "forbidden", http.StatusForbidden)
+		}))
+		t.Cleanup(plain.Close)
+
+		resp := CallMCPToolJSONOK[protocol.WSSendResponse](t, mcpClient, "ws_send", map[string]interface{}{
+			"url":      "ws" + strings.TrimPrefix(plain.URL, "http"),
+			"messages": []interface{}{"x"},
+		})
+		assert.Equal(t, http.StatusForbidden, resp.Status)
+		assert.Zero(t, resp.Sent)
+		assert.Contains(t, resp.RespHeaders, "403")
+	})
+
+	t.Run("validation", func(t *testing.T) {
+		result := CallMCPTool(t, mcpClient, "ws_send", map[string]interface{}{"url": wsURL})
+		assert.True(t, result.IsError)
+		assert.Contains(t, ExtractMCPText(t, result), "messages or message_id is required")
+
+		result = CallMCPTool(t, mcpClient, "ws_send", map[string]interface{}{"url": "http://example.com", "messages": []interface{}{"x"}})
+		assert.True(t, result.IsError)
+		assert.Contains(t, ExtractMCPText(t, result), "invalid url")
+	})
+}
@@ -0,0 +1,112 @@
+package service
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-harden/llm-security-toolbox/sectool/config"
+	"github.com/go-harden/llm-security-toolbox/sectool/protocol"
+	"github.com/go-harden/llm-security-toolbox/sectool/service/ids"
+)
+
+// notesFileName is the project notes file within config.ProjectDirName, one JSON note per line.
+const notesFileName = "notes.jsonl"
+
+// notesStore persists notes in .sectool/notes.jsonl. Notes are only appended, so the
+// file stays readable by other services and tools. Thread-safe.
+type notesStore struct {
+	mu   sync.Mutex
+	path string
+}
+
+func newNotesStore(projectDir string) *notesStore {
+	return &notesStore{path: filepath.Join(projectDir, config.ProjectDirName, notesFileName)}
+}
+
+// Add appends a note about refs (flow, replay, OAST or finding IDs) and returns it.
+func (n *notesStore) Add(text string, tags, refs []string) (protocol.Note, error) {
+	note := protocol.Note{
+		NoteID:    ids.Generate(ids.DefaultLength),
+		Text:      text,
+		Tags:      tags,
+		Refs:      refs,
+		CreatedAt: time.Now().UTC().Format(time.RFC3339),
+	}
+	line, err := json.Marshal(note)
+	if err != nil {
+		return protocol.Note{}, err
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(n.path), 0755); err != nil {
+		return protocol.Note{}, fmt.Errorf("create notes directory: %w", err)
+	}
+	f, err := os.OpenFile(n.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return protocol.Note{}, err
+	}
+	defer func() { _ = f.Close() }()
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		return protocol.Note{}, err
+	}
+	return note, nil
+}
+
+// List returns notes matching filter, newest first.
+func (n *notesStore) List(filter func(protocol.Note) bool) ([]protocol.Note, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	f, err := os.Open(n.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+
+	var notes []protocol.Note
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 4*1024*1024)
+	for scanner.Scan() {
+		var note protocol.Note
+		if err := json.Unmarshal(scanner.Bytes(), &note); err != nil {
+			continue // skip lines damaged by hand edits
+		}
+		if filter == nil || filter(note) {
+			notes = append(notes, note)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	slices.Reverse(notes)
+	return notes, nil
+}
+
+// noteMatches reports whether note has tag and ref (if set) and contains every query
+// term (case-insensitive) in its text, tags or refs.
+func noteMatches(note protocol.Note, tag, ref, query string) bool {
+	if tag != "" && !slices.Contains(note.Tags, tag) {
+		return false
+	} else if ref != "" && !slices.Contains(note.Refs, ref) {
+		return false
+	}
+	haystack := strings.ToLower(note.Text + " " + strings.Join(note.Tags, " ") + " " + strings.Join(note.Refs, " "))
+	for _, term := range strings.Fields(strings.ToLower(query)) {
+		if !strings.Contains(haystack, term) {
+			return false
+		}
+	}
+	return true
+}
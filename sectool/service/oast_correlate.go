@@ -0,0 +1,129 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// oastCorrelator remembers which replays carried hostnames of which OAST session, so
+// interactions can be traced back to the requests that triggered them. Thread-safe.
+type oastCorrelator struct {
+	mu   sync.Mutex
+	uses map[string][]oastUse // by session ID, oldest first
+}
+
+// oastUse is an OAST hostname (subdomain tag plus session domain) seen in a replay.
+type oastUse struct {
+	host     string // lowercased
+	replayID string
+}
+
+func newOastCorrelator() *oastCorrelator {
+	return &oastCorrelator{uses: make(map[string][]oastUse)}
+}
+
+// Record notes the hostnames under each session domain that request contains.
+func (c *oastCorrelator) Record(replayID string, request []byte, sessions []OastSessionInfo) {
+	lower := bytes.ToLower(request)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, sess := range sessions {
+		for _, host := range oastHosts(lower, strings.ToLower(sess.Domain)) {
+			use := oastUse{host: host, replayID: replayID}
+			if !slices.Contains(c.uses[sess.ID], use) {
+				c.uses[sess.ID] = append(c.uses[sess.ID], use)
+			}
+		}
+	}
+}
+
+// Lookup returns the replays, oldest first, that sent a hostname matching an interaction
+// subdomain on sessionID. Backends report either the full hostname or the part before
+// the server domain ("tag.abc123" for "tag.abc123.oast.fun"), so a hostname matches when
+// it equals subdomain or continues it with further labels.
+func (c *oastCorrelator) Lookup(sessionID, subdomain string) []string {
+	subdomain = strings.ToLower(subdomain)
+	if subdomain == "" {
+		return nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var replayIDs []string
+	for _, use := range c.uses[sessionID] {
+		if (use.host == subdomain || strings.HasPrefix(use.host, subdomain+".")) && !slices.Contains(replayIDs, use.replayID) {
+			replayIDs = append(replayIDs, use.replayID)
+		}
+	}
+	return replayIDs
+}
+
+// Forget drops the recorded uses of sessionID.
+func (c *oastCorrelator) Forget(sessionID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.uses, sessionID)
+}
+
+// oastHosts returns the distinct hostnames in data (lowercased) that end with domain,
+// including domain itself.
+func oastHosts(data []byte, domain string) []string {
+	if domain == "" {
+		return nil
+	}
+	var hosts []string
+	for from := 0; ; {
+		i := bytes.Index(data[from:], []byte(domain))
+		if i < 0 {
+			return hosts
+		}
+		start, end := from+i, from+i+len(domain)
+		from = start + 1
+		if end < len(data) && isHostnameByte(data[end]) && data[end] != '.' {
+			continue // domain ends inside a longer label, e.g. "abc.oast.funny"
+		}
+		for start > 0 && isHostnameByte(data[start-1]) {
+			start--
+		}
+		host := strings.TrimLeft(string(data[start:end]), ".-")
+		if (host == domain || strings.HasSuffix(host, "."+domain)) && !slices.Contains(hosts, host) {
+			hosts = append(hosts, host)
+		}
+	}
+}
+
+func isHostnameByte(b byte) bool {
+	return b >= 'a' && b <= 'z' || b >= '0' && b <= '9' || b == '-' || b == '.'
+}
+
+// findOastSession returns the session whose ID, domain or label is idOrDomain.
+func (s *Server) findOastSession(ctx context.Context, idOrDomain string) (OastSessionInfo, bool, error) {
+	sessions, err := s.oastBackend.ListSessions(ctx)
+	if err != nil {
+		return OastSessionInfo{}, false, err
+	}
+	idx := slices.IndexFunc(sessions, func(sess OastSessionInfo) bool {
+		return sess.ID == idOrDomain || sess.Domain == idOrDomain || (sess.Label != "" && sess.Label == idOrDomain)
+	})
+	if idx < 0 {
+		return OastSessionInfo{}, false, nil
+	}
+	return sessions[idx], true, nil
+}
+
+// correlateOast records the OAST hostnames in a sent request for oast_poll and
+// oast_get correlated_with.
+func (s *Server) correlateOast(ctx context.Context, replayID string, request []byte) {
+	sessions, err := s.oastBackend.ListSessions(ctx)
+	if err != nil {
+		log.Printf("oast: skipping correlation for %s: %v", replayID, err)
+		return
+	}
+	s.oastUses.Record(replayID, request, sessions)
+}
@@ -0,0 +1,64 @@
+package service
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOastHosts(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		data string
+		want []string
+	}{
+		{
+			name: "tagged_and_bare",
+			data: "GET /?u=http://ssrf1.abc.oast.fun/x&v=abc.oast.fun HTTP/1.1\r\nReferer: https://Hdr.ABC.oast.fun\r\n\r\n",
+			want: []string{"ssrf1.abc.oast.fun", "abc.oast.fun", "hdr.abc.oast.fun"},
+		},
+		{
+			name: "duplicates",
+			data: "a.abc.oast.fun a.abc.oast.fun",
+			want: []string{"a.abc.oast.fun"},
+		},
+		{
+			name: "longer_label",
+			data: "abc.oast.funny xabc.oast.fun",
+		},
+		{
+			name: "absent",
+			data: "GET / HTTP/1.1\r\nHost: example.com\r\n\r\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, oastHosts(bytes.ToLower([]byte(tt.data)), "abc.oast.fun"))
+		})
+	}
+}
+
+func TestOastCorrelator(t *testing.T) {
+	t.Parallel()
+
+	c := newOastCorrelator()
+	sessions := []OastSessionInfo{{ID: "s1", Domain: "abc.oast.fun"}, {ID: "s2", Domain: "def.oast.fun"}}
+	c.Record("r1", []byte("GET /?u=http://ssrf1.abc.oast.fun HTTP/1.1\r\n\r\n"), sessions)
+	c.Record("r2", []byte("GET /?u=http://xss.ssrf1.abc.oast.fun HTTP/1.1\r\nX: def.oast.fun\r\n\r\n"), sessions)
+	c.Record("r1", []byte("GET /?u=http://ssrf1.abc.oast.fun HTTP/1.1\r\n\r\n"), sessions)
+
+	// Interactsh reports the part before the server domain; other backends the full hostname
+	assert.Equal(t, []string{"r1"}, c.Lookup("s1", "ssrf1.abc"))
+	assert.Equal(t, []string{"r1"}, c.Lookup("s1", "SSRF1.abc.oast.fun"))
+	assert.Equal(t, []string{"r2"}, c.Lookup("s1", "xss.ssrf1.abc"))
+	assert.Empty(t, c.Lookup("s1", "abc"))
+	assert.Equal(t, []string{"r2"}, c.Lookup("s2", "def"))
+	assert.Empty(t, c.Lookup("s1", ""))
+
+	c.Forget("s1")
+	assert.Empty(t, c.Lookup("s1", "ssrf1.abc"))
+}
@@ -0,0 +1,93 @@
+package service
+
+import (
+	"bufio"
+	"net/mail"
+	"strings"
+
+	"github.com/go-harden/interactsh-lite/oobclient"
+)
+
+// ldapDetailKeys maps fields of the interactsh LDAP request dump ("Key=Value" lines)
+// to event details. FilterString is the readable form of Filter, so it wins when both
+// are present.
+var ldapDetailKeys = map[string]string{
+	"type":                 "ldap_operation",
+	"name":                 "ldap_bind_dn",
+	"authenticationchoice": "ldap_auth_method",
+	"basedn":               "ldap_base_dn",
+	"baseobject":           "ldap_base_dn",
+	"filter":               "ldap_filter",
+	"filterstring":         "ldap_filter",
+	"attributes":           "ldap_attributes",
+}
+
+// interactionDetails builds event details from an interaction: the raw exchange plus
+// protocol-specific fields parsed from it, so callers need not read raw_request to see
+// who sent a mail or which DN a JNDI lookup asked for.
+func interactionDetails(interaction *oobclient.Interaction) map[string]interface{} {
+	details := make(map[string]interface{}, 8)
+	if interaction.RawRequest != "" {
+		details["raw_request"] = interaction.RawRequest
+	}
+	if interaction.RawResponse != "" {
+		details["raw_response"] = interaction.RawResponse
+	}
+	if interaction.QType != "" {
+		details["query_type"] = interaction.QType
+	}
+	if interaction.SMTPFrom != "" {
+		details["smtp_from"] = interaction.SMTPFrom
+	}
+
+	switch strings.ToLower(interaction.Protocol) {
+	case "smtp":
+		addSMTPDetails(details, interaction.RawRequest)
+	case "ldap":
+		addLDAPDetails(details, interaction.RawRequest)
+	}
+	return details
+}
+
+// addSMTPDetails adds the message headers of an SMTP DATA payload. Payloads that do not
+// parse as a message add nothing.
+func addSMTPDetails(details map[string]interface{}, raw string) {
+	msg, err := mail.ReadMessage(strings.NewReader(raw))
+	if err != nil {
+		return
+	}
+	for header, key := range map[string]string{
+		"From":       "smtp_header_from",
+		"To":         "smtp_to",
+		"Subject":    "smtp_subject",
+		"Message-Id": "smtp_message_id",
+	} {
+		if v := msg.Header.Get(header); v != "" {
+			details[key] = v
+		}
+	}
+}
+
+// addLDAPDetails adds the operation, bind DN, base DN and filter of an LDAP request.
+// The operation is lowercased (bind, search, ...).
+func addLDAPDetails(details map[string]interface{}, raw string) {
+	fields := make(map[string]string)
+	scanner := bufio.NewScanner(strings.NewReader(raw))
+	for scanner.Scan() {
+		name, value, ok := strings.Cut(scanner.Text(), "=")
+		if !ok || strings.TrimSpace(value) == "" {
+			continue
+		}
+		if key, known := ldapDetailKeys[strings.ToLower(strings.TrimSpace(name))]; known {
+			if _, set := fields[key]; !set || strings.EqualFold(name, "filterstring") {
+				fields[key] = strings.TrimSpace(value)
+			}
+		}
+	}
+	for key, value := range fields {
+		if key == "ldap_operation" {
+			value = strings.ToLower(value)
+		}
+		details[key] = value
+	}
+}
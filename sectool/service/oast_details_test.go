@@ -0,0 +1,72 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/go-harden/interactsh-lite/oobclient"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInteractionDetails(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		interaction oobclient.Interaction
+		want        map[string]interface{}
+	}{
+		{
+			name:        "dns",
+			interaction: oobclient.Interaction{Protocol: "dns", QType: "A", RawRequest: ";; QUESTION"},
+			want:        map[string]interface{}{"raw_request": ";; QUESTION", "query_type": "A"},
+		},
+		{
+			name: "smtp",
+			interaction: oobclient.Interaction{
+				Protocol: "smtp",
+				SMTPFrom: "bounce@app.test",
+				RawRequest: "From: App <noreply@app.test>\r\nTo: ssrf1@abc.oast.fun\r\n" +
+					"Subject: Reset your password\r\nMessage-ID: <1@app.test>\r\n\r\nhttps://app.test/reset?t=1\r\n",
+			},
+			want: map[string]interface{}{
+				"raw_request": "From: App <noreply@app.test>\r\nTo: ssrf1@abc.oast.fun\r\n" +
+					"Subject: Reset your password\r\nMessage-ID: <1@app.test>\r\n\r\nhttps://app.test/reset?t=1\r\n",
+				"smtp_from":        "bounce@app.test",
+				"smtp_header_from": "App <noreply@app.test>",
+				"smtp_to":          "ssrf1@abc.oast.fun",
+				"smtp_subject":     "Reset your password",
+				"smtp_message_id":  "<1@app.test>",
+			},
+		},
+		{
+			name:        "smtp_unparseable",
+			interaction: oobclient.Interaction{Protocol: "smtp", SMTPFrom: "a@b.test", RawRequest: "not a message"},
+			want:        map[string]interface{}{"raw_request": "not a message", "smtp_from": "a@b.test"},
+		},
+		{
+			name:        "ldap_bind",
+			interaction: oobclient.Interaction{Protocol: "ldap", RawRequest: "Type=Bind\nVersion=3\nName=cn=admin,dc=app\nAuthenticationChoice=simple\n"},
+			want: map[string]interface{}{
+				"raw_request":      "Type=Bind\nVersion=3\nName=cn=admin,dc=app\nAuthenticationChoice=simple\n",
+				"ldap_operation":   "bind",
+				"ldap_bind_dn":     "cn=admin,dc=app",
+				"ldap_auth_method": "simple",
+			},
+		},
+		{
+			name:        "ldap_jndi_search",
+			interaction: oobclient.Interaction{Protocol: "LDAP", RawRequest: "Type=Search\nBaseDn=log4j-a1\nFilter=[7]\nFilterString=(objectClass=*)\nAttributes=\n"},
+			want: map[string]interface{}{
+				"raw_request":    "Type=Search\nBaseDn=log4j-a1\nFilter=[7]\nFilterString=(objectClass=*)\nAttributes=\n",
+				"ldap_operation": "search",
+				"ldap_base_dn":   "log4j-a1",
+				"ldap_filter":    "(objectClass=*)",
+			},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, interactionDetails(&tc.interaction))
+		})
+	}
+}
@@ -0,0 +1,182 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"path"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-harden/llm-security-toolbox/sectool/protocol"
+	"github.com/go-harden/llm-security-toolbox/sectool/service/ids"
+)
+
+const (
+	oastWatchWait        = 30 * time.Second // long-poll duration per watch iteration
+	oastWatchMinInterval = time.Second      // lower bound between polls for backends that return early
+)
+
+// oastWatcher watches OAST sessions that have expectations registered and records a
+// draft finding for the first interaction matching each expectation. One goroutine
+// runs per watched session until the session is deleted or the watcher is closed.
+type oastWatcher struct {
+	backend     OastBackend
+	findings    *findingsStore
+	uses        *oastCorrelator
+	minInterval time.Duration
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	mu           sync.Mutex
+	expectations map[string][]*protocol.OastExpectation // by session ID
+}
+
+func newOastWatcher(backend OastBackend, findings *findingsStore, uses *oastCorrelator) *oastWatcher {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &oastWatcher{
+		backend:      backend,
+		findings:     findings,
+		uses:         uses,
+		minInterval:  oastWatchMinInterval,
+		ctx:          ctx,
+		cancel:       cancel,
+		expectations: make(map[string][]*protocol.OastExpectation),
+	}
+}
+
+// Expect registers exp on sessionID, starting a watch for the session if none runs.
+// Interactions that arrived before registration are checked too.
+func (w *oastWatcher) Expect(sessionID string, exp protocol.OastExpectation) protocol.OastExpectation {
+	exp.ExpectationID = ids.Generate(ids.DefaultLength)
+	exp.OastID = sessionID
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	_, watching := w.expectations[sessionID]
+	w.expectations[sessionID] = append(w.expectations[sessionID], &exp)
+	if !watching {
+		w.wg.Add(1)
+		go w.watch(sessionID)
+	}
+	return exp
+}
+
+// Expectations returns the expectations registered on sessionID.
+func (w *oastWatcher) Expectations(sessionID string) []protocol.OastExpectation {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	exps := make([]protocol.OastExpectation, len(w.expectations[sessionID]))
+	for i, exp := range w.expectations[sessionID] {
+		exps[i] = *exp
+	}
+	return exps
+}
+
+// Close stops all watches and waits for them to exit.
+func (w *oastWatcher) Close() {
+	w.cancel()
+	w.wg.Wait()
+}
+
+func (w *oastWatcher) watch(sessionID string) {
+	defer w.wg.Done()
+
+	err := watchOastSession(w.ctx, w.backend, sessionID, w.minInterval, func(events []OastEventInfo) {
+		w.match(sessionID, events)
+	})
+	if err != nil {
+		log.Printf("oast/watch: stopped watching session %s: %v", sessionID, err)
+		w.mu.Lock()
+		delete(w.expectations, sessionID)
+		w.mu.Unlock()
+	}
+}
+
+// watchOastSession long-polls sessionID and calls handle with each batch of new events
+// until ctx is done (returning nil) or polling fails.
+func watchOastSession(ctx context.Context, backend OastBackend, sessionID string, minInterval time.Duration, handle func([]OastEventInfo)) error {
+	var since string
+	for {
+		start := time.Now()
+		result, err := backend.PollSession(ctx, sessionID, since, "", "", oastWatchWait, 0)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+		if len(result.Events) > 0 {
+			since = result.Events[len(result.Events)-1].ID
+			handle(result.Events)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(minInterval - time.Since(start)):
+		}
+	}
+}
+
+// match confirms pending expectations on sessionID with the first matching event.
+func (w *oastWatcher) match(sessionID string, events []OastEventInfo) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for _, exp := range w.expectations[sessionID] {
+		if exp.FindingID != "" {
+			continue
+		}
+		for _, ev := range events {
+			if !subdomainMatches(exp.Subdomain, ev.Subdomain) {
+				continue
+			}
+			replayIDs := w.uses.Lookup(sessionID, ev.Subdomain)
+			if exp.ReplayID != "" && !slices.Contains(replayIDs, exp.ReplayID) {
+				replayIDs = append([]string{exp.ReplayID}, replayIDs...)
+			}
+			finding, err := w.findings.Add(protocol.Finding{
+				Title:    exp.Title,
+				Severity: exp.Severity,
+				Description: fmt.Sprintf("Out-of-band %s interaction on %s from %s confirmed OAST expectation %s.",
+					ev.Type, ev.Subdomain, ev.SourceIP, exp.ExpectationID),
+				ReplayIDs: replayIDs,
+				OastEvents: []protocol.FindingOastEvent{{
+					OastID:    sessionID,
+					EventID:   ev.ID,
+					Type:      ev.Type,
+					SourceIP:  ev.SourceIP,
+					Subdomain: ev.Subdomain,
+					Time:      ev.Time.UTC().Format(time.RFC3339),
+				}},
+			})
+			if err != nil {
+				log.Printf("oast/watch: failed to record finding for expectation %s: %v", exp.ExpectationID, err)
+				break
+			}
+			exp.FindingID = finding.FindingID
+			exp.ConfirmedAt = finding.CreatedAt
+			log.Printf("oast/watch: event %s confirmed expectation %s, created finding %s", ev.ID, exp.ExpectationID, finding.FindingID)
+			break
+		}
+	}
+}
+
+// subdomainMatches reports whether subdomain matches pattern, a case-insensitive glob.
+// A pattern without wildcards matches the subdomain itself and anything under it, so
+// "ssrf-step3" matches "ssrf-step3.abc123".
+func subdomainMatches(pattern, subdomain string) bool {
+	pattern, subdomain = strings.ToLower(pattern), strings.ToLower(subdomain)
+	if !strings.ContainsAny(pattern, "*?[") {
+		return subdomain == pattern || strings.HasPrefix(subdomain, pattern+".")
+	}
+	ok, _ := path.Match(pattern, subdomain)
+	return ok
+}
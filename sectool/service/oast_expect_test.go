@@ -0,0 +1,28 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSubdomainMatches(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		pattern   string
+		subdomain string
+		want      bool
+	}{
+		{"ssrf-step3", "ssrf-step3.abc123", true},
+		{"ssrf-step3", "SSRF-Step3", true},
+		{"ssrf-step3", "ssrf-step30.abc123", false},
+		{"ssrf-step3.*", "ssrf-step3.abc123", true},
+		{"ssrf-*", "ssrf-step1.abc123", true},
+		{"ssrf-*", "xxe-step1.abc123", false},
+		{"*.xxe.*", "a.xxe.abc123", true},
+	}
+	for _, tc := range tests {
+		assert.Equal(t, tc.want, subdomainMatches(tc.pattern, tc.subdomain), "%s vs %s", tc.pattern, tc.subdomain)
+	}
+}
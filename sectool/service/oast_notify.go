@@ -0,0 +1,157 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/go-harden/llm-security-toolbox/sectool/protocol"
+)
+
+const oastNotifyTimeout = 10 * time.Second
+
+// oastNotifier pushes each OAST interaction to a webhook and/or appends it to a JSON
+// lines file as it arrives, so callers need not long-poll. One goroutine runs per
+// session until the session is deleted or the notifier is closed.
+type oastNotifier struct {
+	backend     OastBackend
+	uses        *oastCorrelator
+	url         string
+	file        string
+	client      *http.Client
+	minInterval time.Duration
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	mu       sync.Mutex // guards watching and serializes file appends
+	watching map[string]bool
+}
+
+// newOastNotifier returns a notifier delivering to url and file; either may be empty.
+func newOastNotifier(backend OastBackend, uses *oastCorrelator, url, file string) *oastNotifier {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &oastNotifier{
+		backend:     backend,
+		uses:        uses,
+		url:         url,
+		file:        file,
+		client:      &http.Client{Timeout: oastNotifyTimeout},
+		minInterval: oastWatchMinInterval,
+		ctx:         ctx,
+		cancel:      cancel,
+		watching:    make(map[string]bool),
+	}
+}
+
+// Watch starts delivering interactions of sess, if not already.
+func (n *oastNotifier) Watch(sess OastSessionInfo) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if n.watching[sess.ID] {
+		return
+	}
+	n.watching[sess.ID] = true
+	n.wg.Add(1)
+	go n.watch(sess)
+}
+
+// Close stops all watches and waits for them to exit.
+func (n *oastNotifier) Close() {
+	n.cancel()
+	n.wg.Wait()
+}
+
+func (n *oastNotifier) watch(sess OastSessionInfo) {
+	defer n.wg.Done()
+
+	err := watchOastSession(n.ctx, n.backend, sess.ID, n.minInterval, func(events []OastEventInfo) {
+		for _, ev := range events {
+			n.deliver(protocol.OastNotification{
+				OastID: sess.ID,
+				Domain: sess.Domain,
+				Label:  sess.Label,
+				OastEvent: protocol.OastEvent{
+					EventID:        ev.ID,
+					Time:           ev.Time.UTC().Format(time.RFC3339),
+					Type:           ev.Type,
+					SourceIP:       ev.SourceIP,
+					Subdomain:      ev.Subdomain,
+					Details:        ev.Details,
+					CorrelatedWith: n.uses.Lookup(sess.ID, ev.Subdomain),
+				},
+			})
+		}
+	})
+	if err != nil {
+		log.Printf("oast/notify: stopped notifying for session %s: %v", sess.ID, err)
+	}
+
+	n.mu.Lock()
+	delete(n.watching, sess.ID)
+	n.mu.Unlock()
+}
+
+// deliver sends notification to each configured destination, logging failures.
+func (n *oastNotifier) deliver(notification protocol.OastNotification) {
+	data, err := json.Marshal(notification)
+	if err != nil {
+		log.Printf("oast/notify: failed to encode event %s: %v", notification.EventID, err)
+		return
+	}
+	if n.url != "" {
+		if err := n.post(data); err != nil {
+			log.Printf("oast/notify: webhook delivery of event %s failed: %v", notification.EventID, err)
+		}
+	}
+	if n.file != "" {
+		if err := n.appendLine(data); err != nil {
+			log.Printf("oast/notify: writing event %s to %s failed: %v", notification.EventID, n.file, err)
+		}
+	}
+}
+
+func (n *oastNotifier) post(data []byte) error {
+	req, err := http.NewRequestWithContext(n.ctx, http.MethodPost, n.url, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return err
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned %s", resp.Status)
+	}
+	return nil
+}
+
+func (n *oastNotifier) appendLine(data []byte) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(n.file), 0700); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(n.file, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,73 @@
+package service
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/go-harden/llm-security-toolbox/sectool/protocol"
+)
+
+func TestOastNotifier(t *testing.T) {
+	t.Parallel()
+
+	posted := make(chan protocol.OastNotification, 4)
+	hook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		body, _ := io.ReadAll(r.Body)
+		var n protocol.OastNotification
+		assert.NoError(t, json.Unmarshal(body, &n))
+		posted <- n
+	}))
+	t.Cleanup(hook.Close)
+
+	backend := newMockOastBackend()
+	sess, err := backend.CreateSession(t.Context(), "blind-ssrf")
+	require.NoError(t, err)
+	// Events are added before watching so the background watch only reads the mock
+	backend.events[sess.ID] = []OastEventInfo{
+		{ID: "e1", Time: time.Now(), Type: "dns", SourceIP: "1.2.3.4", Subdomain: "ssrf1." + sess.Domain},
+		{ID: "e2", Time: time.Now(), Type: "http", SourceIP: "5.6.7.8", Subdomain: "xxe." + sess.Domain},
+	}
+	uses := newOastCorrelator()
+	uses.Record("rp1", []byte("GET /?u=http://ssrf1."+sess.Domain+"/ HTTP/1.1\r\n\r\n"), []OastSessionInfo{*sess})
+
+	file := filepath.Join(t.TempDir(), "notify", "oast.jsonl")
+	n := newOastNotifier(backend, uses, hook.URL, file)
+	n.minInterval = 10 * time.Millisecond
+	n.Watch(*sess)
+	n.Watch(*sess) // already watching
+
+	for _, want := range []string{"e1", "e2"} {
+		select {
+		case got := <-posted:
+			assert.Equal(t, want, got.EventID)
+			assert.Equal(t, sess.ID, got.OastID)
+			assert.Equal(t, "blind-ssrf", got.Label)
+		case <-time.After(5 * time.Second):
+			t.Fatalf("no webhook delivery for %s", want)
+		}
+	}
+	n.Close()
+
+	data, err := os.ReadFile(file)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	require.Len(t, lines, 2)
+	var first protocol.OastNotification
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &first))
+	assert.Equal(t, "e1", first.EventID)
+	assert.Equal(t, sess.Domain, first.Domain)
+	assert.Equal(t, []string{"rp1"}, first.CorrelatedWith)
+	assert.Empty(t, posted, "events are delivered once")
+}
@@ -0,0 +1,488 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"mime/multipart"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// specMaxSchemaDepth bounds example generation for nested and recursive schemas.
+const specMaxSchemaDepth = 6
+
+// specMethods lists the OpenAPI path item keys that are operations, in output order.
+var specMethods = []string{"get", "post", "put", "patch", "delete", "head", "options", "trace"}
+
+// openAPISpec is an imported OpenAPI document with its operations materialized.
+type openAPISpec struct {
+	Title       string
+	Version     string // info.version
+	SpecVersion string // openapi or swagger field
+	BaseURL     string
+	Operations  []specOperation
+}
+
+// specOperation is one OpenAPI operation as a raw request template with example values.
+type specOperation struct {
+	Method      string
+	Path        string // path template from the spec, e.g. /users/{id}
+	OperationID string
+	Summary     string
+	Params      []string // path, query, header, cookie and top-level body field names
+	ContentType string
+	Request     []byte
+	Target      Target
+}
+
+// specParam is a parameter merged from the path item and operation.
+type specParam struct {
+	name, in string
+	value    interface{}
+}
+
+// parseOpenAPISpec parses an OpenAPI 2 (Swagger) or 3 document in JSON or YAML.
+// baseURL overrides the spec servers; sourceURL resolves relative server URLs.
+func parseOpenAPISpec(data []byte, baseURL, sourceURL string) (*openAPISpec, error) {
+	var doc map[string]interface{}
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '{' {
+		if err := json.Unmarshal(trimmed, &doc); err != nil {
+			return nil, fmt.Errorf("invalid JSON: %w", err)
+		}
+	} else if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("invalid YAML: %w", err)
+	}
+
+	spec := &openAPISpec{}
+	var v2 bool
+	if v, ok := doc["openapi"].(string); ok && strings.HasPrefix(v, "3") {
+		spec.SpecVersion = v
+	} else if v := fmt.Sprint(doc["swagger"]); strings.HasPrefix(v, "2") {
+		spec.SpecVersion, v2 = v, true
+	} else {
+		return nil, errors.New("not an OpenAPI 2 or 3 document: missing openapi or swagger version")
+	}
+	if info, ok := doc["info"].(map[string]interface{}); ok {
+		spec.Title, _ = info["title"].(string)
+		spec.Version = fmt.Sprint(info["version"])
+	}
+
+	if baseURL == "" {
+		if v2 {
+			baseURL = swaggerBaseURL(doc)
+		} else {
+			baseURL = openAPIServerURL(doc)
+		}
+	}
+	base, err := url.Parse(baseURL)
+	if err == nil && sourceURL != "" && !base.IsAbs() {
+		if src, serr := url.Parse(sourceURL); serr == nil {
+			base = src.ResolveReference(base)
+		}
+	}
+	if err != nil || base.Host == "" || (base.Scheme != schemeHTTP && base.Scheme != schemeHTTPS) {
+		return nil, errors.New("spec has no absolute http(s) server URL: set base_url")
+	}
+	spec.BaseURL = strings.TrimSuffix(base.String(), "/")
+
+	paths, _ := doc["paths"].(map[string]interface{})
+	for _, path := range sortedKeys(paths) {
+		item, ok := resolveSpecRef(doc, paths[path]).(map[string]interface{})
+		if !ok {
+			continue
+		}
+		for _, method := range specMethods {
+			op, ok := item[method].(map[string]interface{})
+			if !ok {
+				continue
+			}
+			spec.Operations = append(spec.Operations, buildSpecOperation(doc, v2, base, path, method, item, op))
+		}
+	}
+	return spec, nil
+}
+
+// openAPIServerURL returns the first OpenAPI 3 server URL with variables set to their defaults.
+func openAPIServerURL(doc map[string]interface{}) string {
+	servers, _ := doc["servers"].([]interface{})
+	if len(servers) == 0 {
+		return ""
+	}
+	server, _ := servers[0].(map[string]interface{})
+	serverURL, _ := server["url"].(string)
+	vars, _ := server["variables"].(map[string]interface{})
+	for name, v := range vars {
+		if variable, ok := v.(map[string]interface{}); ok {
+			serverURL = strings.ReplaceAll(serverURL, "{"+name+"}", fmt.Sprint(variable["default"]))
+		}
+	}
+	return serverURL
+}
+
+// swaggerBaseURL builds the Swagger 2 base URL from schemes, host and basePath, preferring https.
+func swaggerBaseURL(doc map[string]interface{}) string {
+	host, _ := doc["host"].(string)
+	basePath, _ := doc["basePath"].(string)
+	if host == "" {
+		return basePath
+	}
+	scheme := schemeHTTPS
+	if schemes, ok := doc["schemes"].([]interface{}); ok && len(schemes) > 0 &&
+		!slices.Contains(schemes, interface{}(schemeHTTPS)) {
+		scheme = fmt.Sprint(schemes[0])
+	}
+	return scheme + "://" + host + basePath
+}
+
+func buildSpecOperation(doc map[string]interface{}, v2 bool, base *url.URL, path, method string, item, op map[string]interface{}) specOperation {
+	result := specOperation{Method: strings.ToUpper(method), Path: path}
+	result.OperationID, _ = op["operationId"].(string)
+	result.Summary, _ = op["summary"].(string)
+
+	// Operation parameters override path item parameters with the same name and location
+	var params []specParam
+	var formParams []specParam
+	var bodySchema interface{}
+	for _, list := range []interface{}{item["parameters"], op["parameters"]} {
+		entries, _ := list.([]interface{})
+		for _, entry := range entries {
+			p, ok := resolveSpecRef(doc, entry).(map[string]interface{})
+			if !ok {
+				continue
+			}
+			name, _ := p["name"].(string)
+			in, _ := p["in"].(string)
+			switch in {
+			case "body":
+				bodySchema = p["schema"]
+				continue
+			case "formData":
+				formParams = append(formParams, specParam{name: name, in: in, value: specParamExample(doc, p)})
+				continue
+			}
+			sp := specParam{name: name, in: in, value: specParamExample(doc, p)}
+			if idx := slices.IndexFunc(params, func(e specParam) bool { return e.name == name && e.in == in }); idx >= 0 {
+				params[idx] = sp
+			} else {
+				params = append(params, sp)
+			}
+		}
+	}
+
+	headers := make(map[string]string)
+	query := url.Values{}
+	var cookies []string
+	reqPath := path
+	for _, p := range params {
+		value := specParamString(p.value)
+		switch p.in {
+		case "path":
+			reqPath = strings.ReplaceAll(reqPath, "{"+p.name+"}", url.PathEscape(value))
+		case "query":
+			query.Set(p.name, value)
+		case "header":
+			headers[p.name] = value
+		case "cookie":
+			cookies = append(cookies, p.name+"="+value)
+		}
+		result.Params = append(result.Params, p.name)
+	}
+	if len(cookies) > 0 {
+		headers["Cookie"] = strings.Join(cookies, "; ")
+	}
+
+	var body []byte
+	var fields []string
+	if v2 {
+		consumes := specStrings(op["consumes"])
+		if len(consumes) == 0 {
+			consumes = specStrings(doc["consumes"])
+		}
+		if bodySchema != nil {
+			result.ContentType = "application/json"
+			if len(consumes) > 0 {
+				result.ContentType = consumes[0]
+			}
+			body, fields = encodeSpecBody(result.ContentType, specExample(doc, bodySchema, 0))
+		} else if len(formParams) > 0 {
+			result.ContentType = "application/x-www-form-urlencoded"
+			if slices.Contains(consumes, "multipart/form-data") {
+				result.ContentType = "multipart/form-data"
+			}
+			form := make(map[string]interface{}, len(formParams))
+			for _, p := range formParams {
+				form[p.name] = p.value
+			}
+			body, fields = encodeSpecBody(result.ContentType, form)
+		}
+	} else if reqBody, ok := resolveSpecRef(doc, op["requestBody"]).(map[string]interface{}); ok {
+		content, _ := reqBody["content"].(map[string]interface{})
+		if mediaType := pickSpecMediaType(content); mediaType != "" {
+			media, _ := content[mediaType].(map[string]interface{})
+			result.ContentType = mediaType
+			body, fields = encodeSpecBody(mediaType, specMediaExample(doc, media))
+		}
+	}
+	if result.ContentType != "" {
+		if strings.HasPrefix(result.ContentType, "multipart/") {
+			headers["Content-Type"] = "multipart/form-data; boundary=" + specMultipartBoundary
+		} else {
+			headers["Content-Type"] = result.ContentType
+		}
+	}
+	result.Params = append(result.Params, fields...)
+
+	reqURL := *base
+	reqURL.Path = strings.TrimSuffix(base.Path, "/") + reqPath
+	reqURL.RawPath = ""
+	reqURL.RawQuery = query.Encode()
+	result.Request = buildRawRequest(result.Method, &reqURL, headers, body)
+	result.Target = targetFromURL(&reqURL)
+	return result
+}
+
+// specMultipartBoundary is fixed so templates are stable across imports.
+const specMultipartBoundary = "sectoolSpecBoundary"
+
+// pickSpecMediaType prefers JSON, then form encodings, then the first media type by name.
+func pickSpecMediaType(content map[string]interface{}) string {
+	types := sortedKeys(content)
+	for _, match := range []string{"application/json", "json", "application/x-www-form-urlencoded", "multipart/form-data"} {
+		for _, t := range types {
+			if strings.Contains(t, match) {
+				return t
+			}
+		}
+	}
+	if len(types) > 0 {
+		return types[0]
+	}
+	return ""
+}
+
+// specMediaExample returns the example for an OpenAPI 3 media type object.
+func specMediaExample(doc map[string]interface{}, media map[string]interface{}) interface{} {
+	if ex, ok := media["example"]; ok {
+		return ex
+	}
+	if examples, ok := media["examples"].(map[string]interface{}); ok {
+		for _, name := range sortedKeys(examples) {
+			if ex, ok := resolveSpecRef(doc, examples[name]).(map[string]interface{}); ok {
+				if v, ok := ex["value"]; ok {
+					return v
+				}
+			}
+		}
+	}
+	return specExample(doc, media["schema"], 0)
+}
+
+// encodeSpecBody serializes an example body for the content type and returns its top-level field names.
+func encodeSpecBody(contentType string, value interface{}) ([]byte, []string) {
+	obj, _ := value.(map[string]interface{})
+	fields := sortedKeys(obj)
+	switch {
+	case strings.Contains(contentType, "json"):
+		if value == nil {
+			return nil, nil
+		}
+		b, err := json.Marshal(value)
+		if err != nil {
+			return nil, nil
+		}
+		return b, fields
+	case strings.HasPrefix(contentType, "application/x-www-form-urlencoded"):
+		form := url.Values{}
+		for _, name := range fields {
+			form.Set(name, specParamString(obj[name]))
+		}
+		return []byte(form.Encode()), fields
+	case strings.HasPrefix(contentType, "multipart/"):
+		var buf bytes.Buffer
+		w := multipart.NewWriter(&buf)
+		_ = w.SetBoundary(specMultipartBoundary)
+		for _, name := range fields {
+			_ = w.WriteField(name, specParamString(obj[name]))
+		}
+		_ = w.Close()
+		return buf.Bytes(), fields
+	}
+	if s, ok := value.(string); ok {
+		return []byte(s), nil
+	}
+	return nil, nil
+}
+
+// specParamExample returns an example value for a parameter. Swagger 2 non-body
+// parameters carry their schema fields inline.
+func specParamExample(doc map[string]interface{}, p map[string]interface{}) interface{} {
+	if ex, ok := p["example"]; ok {
+		return ex
+	}
+	if ex, ok := p["x-example"]; ok {
+		return ex
+	}
+	if examples, ok := p["examples"].(map[string]interface{}); ok {
+		for _, name := range sortedKeys(examples) {
+			if ex, ok := resolveSpecRef(doc, examples[name]).(map[string]interface{}); ok {
+				if v, ok := ex["value"]; ok {
+					return v
+				}
+			}
+		}
+	}
+	if schema, ok := p["schema"]; ok {
+		return specExample(doc, schema, 0)
+	}
+	return specExample(doc, p, 0)
+}
+
+// specExample generates an example value for a schema from its example, default,
+// enum, or type and format.
+func specExample(doc map[string]interface{}, raw interface{}, depth int) interface{} {
+	schema, ok := resolveSpecRef(doc, raw).(map[string]interface{})
+	if !ok || depth > specMaxSchemaDepth {
+		return nil
+	}
+	for _, key := range []string{"example", "x-example", "default"} {
+		if v, ok := schema[key]; ok {
+			return v
+		}
+	}
+	if enum, ok := schema["enum"].([]interface{}); ok && len(enum) > 0 {
+		return enum[0]
+	}
+	if allOf, ok := schema["allOf"].([]interface{}); ok {
+		merged := make(map[string]interface{})
+		for _, sub := range allOf {
+			if obj, ok := specExample(doc, sub, depth+1).(map[string]interface{}); ok {
+				for k, v := range obj {
+					merged[k] = v
+				}
+			}
+		}
+		return merged
+	}
+	for _, key := range []string{"oneOf", "anyOf"} {
+		if subs, ok := schema[key].([]interface{}); ok && len(subs) > 0 {
+			return specExample(doc, subs[0], depth+1)
+		}
+	}
+
+	typ, _ := schema["type"].(string)
+	if types, ok := schema["type"].([]interface{}); ok && len(types) > 0 {
+		typ = fmt.Sprint(types[0]) // OpenAPI 3.1 type arrays
+	}
+	props, hasProps := schema["properties"].(map[string]interface{})
+	switch {
+	case typ == "object" || hasProps:
+		obj := make(map[string]interface{}, len(props))
+		for name, prop := range props {
+			obj[name] = specExample(doc, prop, depth+1)
+		}
+		return obj
+	case typ == "array":
+		if item := specExample(doc, schema["items"], depth+1); item != nil {
+			return []interface{}{item}
+		}
+		return []interface{}{}
+	case typ == "integer":
+		return 1
+	case typ == "number":
+		return 1.5
+	case typ == "boolean":
+		return true
+	}
+
+	format, _ := schema["format"].(string)
+	switch format {
+	case "date-time":
+		return "2024-01-01T00:00:00Z"
+	case "date":
+		return "2024-01-01"
+	case "uuid":
+		return "00000000-0000-0000-0000-000000000001"
+	case "email":
+		return "user@example.com"
+	case "uri", "url":
+		return "https://example.com"
+	case "byte":
+		return "dGVzdA=="
+	case "ipv4":
+		return "127.0.0.1"
+	}
+	return "test"
+}
+
+// specParamString formats an example value for a path, query, header or form field.
+func specParamString(v interface{}) string {
+	switch val := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return val
+	case float64:
+		return strconv.FormatFloat(val, 'f', -1, 64)
+	case []interface{}:
+		parts := make([]string, 0, len(val))
+		for _, item := range val {
+			parts = append(parts, specParamString(item))
+		}
+		return strings.Join(parts, ",")
+	case map[string]interface{}:
+		b, _ := json.Marshal(val)
+		return string(b)
+	}
+	return fmt.Sprint(v)
+}
+
+// resolveSpecRef follows a local JSON pointer $ref ("#/components/schemas/User").
+// Non-local or broken references resolve to nil.
+func resolveSpecRef(doc map[string]interface{}, v interface{}) interface{} {
+	for range specMaxSchemaDepth {
+		obj, ok := v.(map[string]interface{})
+		if !ok {
+			return v
+		}
+		ref, ok := obj["$ref"].(string)
+		if !ok {
+			return v
+		}
+		pointer, ok := strings.CutPrefix(ref, "#/")
+		if !ok {
+			return nil
+		}
+		var cur interface{} = doc
+		for _, part := range strings.Split(pointer, "/") {
+			part = strings.NewReplacer("~1", "/", "~0", "~").Replace(part)
+			m, ok := cur.(map[string]interface{})
+			if !ok {
+				return nil
+			}
+			cur = m[part]
+		}
+		v = cur
+	}
+	return nil
+}
+
+// specStrings converts a YAML/JSON list to strings.
+func specStrings(v interface{}) []string {
+	list, _ := v.([]interface{})
+	result := make([]string, 0, len(list))
+	for _, item := range list {
+		result = append(result, fmt.Sprint(item))
+	}
+	return result
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	return slices.Sorted(maps.Keys(m))
+}
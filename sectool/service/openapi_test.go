@@ -0,0 +1,193 @@
+package service
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testOpenAPI3Spec = `
+openapi: 3.0.3
+info:
+  title: Pets
+  version: "1.2"
+servers:
+  - url: https://{env}.example.com/api
+    variables:
+      env:
+        default: staging
+paths:
+  /pets/{petId}:
+    parameters:
+      - name: petId
+        in: path
+        required: true
+        schema: {type: integer, example: 42}
+    get:
+      operationId: getPet
+      summary: Get a pet
+      parameters:
+        - name: fields
+          in: query
+          schema: {type: array, items: {type: string, enum: [name, tag]}}
+        - name: X-Trace
+          in: header
+          schema: {type: string, format: uuid}
+        - name: session
+          in: cookie
+          schema: {type: string}
+  /pets:
+    post:
+      operationId: createPet
+      requestBody:
+        content:
+          application/json:
+            schema:
+              $ref: '#/components/schemas/Pet'
+    put:
+      requestBody:
+        content:
+          application/x-www-form-urlencoded:
+            schema:
+              type: object
+              properties:
+                name: {type: string, default: rex}
+                age: {type: integer}
+components:
+  schemas:
+    Pet:
+      type: object
+      properties:
+        name: {type: string}
+        born: {type: string, format: date}
+        owner:
+          $ref: '#/components/schemas/Owner'
+    Owner:
+      type: object
+      properties:
+        email: {type: string, format: email}
+        pets:
+          type: array
+          items:
+            $ref: '#/components/schemas/Pet'
+`
+
+const testSwagger2Spec = `{
+  "swagger": "2.0",
+  "info": {"title": "Legacy", "version": "1"},
+  "host": "legacy.example.com:8443",
+  "basePath": "/v1",
+  "schemes": ["http", "https"],
+  "consumes": ["application/json"],
+  "paths": {
+    "/users/{id}": {
+      "delete": {
+        "parameters": [{"name": "id", "in": "path", "type": "string", "x-example": "u-1"}]
+      }
+    },
+    "/login": {
+      "post": {
+        "consumes": ["application/x-www-form-urlencoded"],
+        "parameters": [
+          {"name": "user", "in": "formData", "type": "string"},
+          {"name": "remember", "in": "formData", "type": "boolean"}
+        ]
+      }
+    },
+    "/users": {
+      "post": {
+        "parameters": [{"name": "body", "in": "body", "schema": {"type": "object", "properties": {"id": {"type": "integer"}}}}]
+      }
+    }
+  }
+}`
+
+func TestParseOpenAPISpec_V3(t *testing.T) {
+	t.Parallel()
+
+	spec, err := parseOpenAPISpec([]byte(testOpenAPI3Spec), "", "")
+	require.NoError(t, err)
+	assert.Equal(t, "Pets", spec.Title)
+	assert.Equal(t, "1.2", spec.Version)
+	assert.Equal(t, "3.0.3", spec.SpecVersion)
+	assert.Equal(t, "https://staging.example.com/api", spec.BaseURL)
+	require.Len(t, spec.Operations, 3)
+
+	// Sorted by path, then method order
+	create, update, get := spec.Operations[0], spec.Operations[1], spec.Operations[2]
+
+	assert.Equal(t, "GET", get.Method)
+	assert.Equal(t, "getPet", get.OperationID)
+	assert.Equal(t, []string{"petId", "fields", "X-Trace", "session"}, get.Params)
+	assert.Equal(t, Target{Hostname: "staging.example.com", Port: 443, UsesHTTPS: true}, get.Target)
+	raw := string(get.Request)
+	assert.True(t, strings.HasPrefix(raw, "GET /api/pets/42?fields=name HTTP/1.1\r\n"), raw)
+	assert.Contains(t, raw, "Host: staging.example.com\r\n")
+	assert.Contains(t, raw, "X-Trace: 00000000-0000-0000-0000-000000000001\r\n")
+	assert.Contains(t, raw, "Cookie: session=test\r\n")
+
+	assert.Equal(t, "POST", create.Method)
+	assert.Equal(t, "application/json", create.ContentType)
+	assert.Equal(t, []string{"born", "name", "owner"}, create.Params)
+	_, body := splitHeadersBody(create.Request)
+	assert.Contains(t, string(body), `"born":"2024-01-01"`)
+	assert.Contains(t, string(body), `"email":"user@example.com"`)
+
+	assert.Equal(t, "PUT", update.Method)
+	_, body = splitHeadersBody(update.Request)
+	assert.Equal(t, "age=1&name=rex", string(body))
+	assert.Contains(t, string(update.Request), "Content-Type: application/x-www-form-urlencoded\r\n")
+}
+
+func TestParseOpenAPISpec_V2(t *testing.T) {
+	t.Parallel()
+
+	spec, err := parseOpenAPISpec([]byte(testSwagger2Spec), "", "")
+	require.NoError(t, err)
+	assert.Equal(t, "https://legacy.example.com:8443/v1", spec.BaseURL)
+	require.Len(t, spec.Operations, 3)
+
+	login, users, del := spec.Operations[0], spec.Operations[1], spec.Operations[2]
+
+	_, body := splitHeadersBody(login.Request)
+	assert.Equal(t, "remember=true&user=test", string(body))
+	assert.Equal(t, []string{"remember", "user"}, login.Params)
+
+	assert.Equal(t, "application/json", users.ContentType)
+	_, body = splitHeadersBody(users.Request)
+	assert.JSONEq(t, `{"id":1}`, string(body))
+
+	assert.True(t, strings.HasPrefix(string(del.Request), "DELETE /v1/users/u-1 HTTP/1.1\r\n"))
+	assert.Equal(t, 8443, del.Target.Port)
+}
+
+func TestParseOpenAPISpec_BaseURL(t *testing.T) {
+	t.Parallel()
+
+	const relative = "openapi: 3.1.0\nservers: [{url: /api}]\npaths:\n  /ping:\n    get: {}\n"
+
+	t.Run("source_relative", func(t *testing.T) {
+		spec, err := parseOpenAPISpec([]byte(relative), "", "http://app.example.com/docs/openapi.yaml")
+		require.NoError(t, err)
+		assert.Equal(t, "http://app.example.com/api", spec.BaseURL)
+		assert.True(t, strings.HasPrefix(string(spec.Operations[0].Request), "GET /api/ping HTTP/1.1\r\n"))
+	})
+
+	t.Run("override", func(t *testing.T) {
+		spec, err := parseOpenAPISpec([]byte(relative), "http://localhost:8080", "")
+		require.NoError(t, err)
+		assert.Equal(t, Target{Hostname: "localhost", Port: 8080}, spec.Operations[0].Target)
+	})
+
+	t.Run("missing", func(t *testing.T) {
+		_, err := parseOpenAPISpec([]byte(relative), "", "")
+		assert.ErrorContains(t, err, "set base_url")
+	})
+
+	t.Run("not_openapi", func(t *testing.T) {
+		_, err := parseOpenAPISpec([]byte(`{"name": "x"}`), "", "")
+		assert.ErrorContains(t, err, "not an OpenAPI")
+	})
+}
@@ -0,0 +1,288 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	// minOutputBudget is the smallest max_output_bytes honored; smaller values are raised to it.
+	minOutputBudget = 512
+	// minElidedString is the shortest a string field is shrunk to when shaping JSON.
+	minElidedString = 64
+	// maxShapeSteps bounds the shrink loop for pathological documents.
+	maxShapeSteps = 64
+)
+
+// interestingHeaders are kept from the elided middle of a string when they appear as header lines.
+var interestingHeaders = []string{
+	"set-cookie", "cookie", "authorization", "www-authenticate", "location", "content-type",
+	"content-security-policy", "access-control-allow-origin", "access-control-allow-credentials",
+	"strict-transport-security", "x-frame-options", "server",
+}
+
+// shapeOutput fits a tool result into budget bytes. JSON objects stay valid: the largest
+// strings are elided in the middle (keeping the status line and interesting headers)
+// and the longest arrays are cut short, with output_truncated/output_id/output_hint fields
+// prepended. Anything else has its middle elided with a trailing note. hint optionally
+// names a narrower follow-up call.
+func shapeOutput(text string, budget int, outputID, hint string) string {
+	if len(text) <= budget {
+		return text
+	}
+
+	outputHint := "call output_get with output_id=" + outputID + " for the rest"
+	if hint != "" {
+		outputHint += ", or " + hint
+	}
+	if root, err := decodeOrderedJSON(text); err == nil {
+		if obj, ok := root.(*jsonObject); ok {
+			obj.keys = append([]string{"output_truncated", "original_bytes", "output_id", "output_hint"}, obj.keys...)
+			obj.vals = append([]any{true, len(text), outputID, outputHint}, obj.vals...)
+			if shaped, ok := shrinkJSON(obj, budget); ok {
+				return shaped
+			}
+		}
+	}
+
+	note := fmt.Sprintf("\n[truncated: %d bytes total; %s]", len(text), outputHint)
+	return elideMiddle(text, max(budget-len(note), minElidedString)) + note
+}
+
+// shrinkJSON repeatedly shrinks the largest string or array until the document fits.
+func shrinkJSON(root any, budget int) (string, bool) {
+	for range maxShapeSteps {
+		b, err := json.MarshalIndent(root, "", "  ")
+		if err != nil {
+			return "", false
+		} else if len(b) <= budget {
+			return string(b), true
+		}
+		excess := len(b) - budget
+
+		switch n := largestShrinkable(root).(type) {
+		case *jsonString:
+			n.keep = max(minElidedString, min(n.keep, len(n.full))-excess)
+		case *jsonArray:
+			var saved int
+			for len(n.items) > 1 && saved < excess {
+				last, _ := json.Marshal(n.items[len(n.items)-1])
+				saved += len(last) + 4 // separator and indentation
+				n.items = n.items[:len(n.items)-1]
+				n.dropped++
+			}
+		default:
+			return "", false
+		}
+	}
+	return "", false
+}
+
+// largestShrinkable returns the string or array node with the largest encoding that can still shrink.
+func largestShrinkable(root any) any {
+	var best any
+	var bestSize int
+	var walk func(v any)
+	walk = func(v any) {
+		var size int
+		switch n := v.(type) {
+		case *jsonObject:
+			for _, c := range n.vals {
+				walk(c)
+			}
+			return
+		case *jsonArray:
+			for _, c := range n.items {
+				walk(c)
+			}
+			if len(n.items) <= 1 {
+				return
+			}
+			b, _ := json.Marshal(n)
+			size = len(b)
+		case *jsonString:
+			if min(n.keep, len(n.full)) <= minElidedString {
+				return
+			}
+			size = len(n.String())
+		default:
+			return
+		}
+		if size > bestSize {
+			best, bestSize = v, size
+		}
+	}
+	walk(root)
+	return best
+}
+
+// elideMiddle keeps roughly keep bytes of s: the head (including the first line), the
+// tail, and any interesting header lines from the removed middle.
+func elideMiddle(s string, keep int) string {
+	if len(s) <= keep {
+		return s
+	}
+
+	headLen := keep * 3 / 5
+	if nl := strings.IndexByte(s, '\n'); nl > headLen && nl < keep {
+		headLen = nl + 1 // keep the status or request line whole
+	}
+	for headLen > 0 && !utf8.RuneStart(s[headLen]) {
+		headLen--
+	}
+	tailStart := len(s) - (keep - headLen)
+	for tailStart < len(s) && !utf8.RuneStart(s[tailStart]) {
+		tailStart++
+	}
+	if tailStart < headLen {
+		tailStart = headLen
+	}
+	middle := s[headLen:tailStart]
+
+	var sb strings.Builder
+	sb.WriteString(s[:headLen])
+	_, _ = fmt.Fprintf(&sb, "\n…[%d bytes elided]…\n", len(middle))
+	var kept int
+	for _, line := range strings.Split(middle, "\n") {
+		line = strings.TrimRight(line, "\r")
+		if kept+len(line) <= keep/4 && isInterestingHeader(line) {
+			sb.WriteString(line)
+			sb.WriteString("\n")
+			kept += len(line)
+		}
+	}
+	sb.WriteString(s[tailStart:])
+	return sb.String()
+}
+
+func isInterestingHeader(line string) bool {
+	name, _, ok := strings.Cut(line, ":")
+	if !ok {
+		return false
+	}
+	name = strings.ToLower(strings.TrimSpace(name))
+	for _, h := range interestingHeaders {
+		if name == h {
+			return true
+		}
+	}
+	return false
+}
+
+// Ordered JSON tree used for shaping so object keys keep their original order.
+
+type jsonObject struct {
+	keys []string
+	vals []any
+}
+
+func (o *jsonObject) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, k := range o.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		kb, _ := json.Marshal(k)
+		buf.Write(kb)
+		buf.WriteByte(':')
+		vb, err := json.Marshal(o.vals[i])
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(vb)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+type jsonArray struct {
+	items   []any
+	dropped int
+}
+
+func (a *jsonArray) MarshalJSON() ([]byte, error) {
+	items := a.items
+	if a.dropped > 0 {
+		items = append(items[:len(items):len(items)], fmt.Sprintf("…[%d more items elided]", a.dropped))
+	}
+	if items == nil {
+		items = []any{}
+	}
+	return json.Marshal(items)
+}
+
+type jsonString struct {
+	full string
+	keep int
+}
+
+func (s *jsonString) String() string {
+	return elideMiddle(s.full, s.keep)
+}
+
+func (s *jsonString) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.String())
+}
+
+// decodeOrderedJSON parses text into jsonObject/jsonArray/jsonString nodes.
+func decodeOrderedJSON(text string) (any, error) {
+	dec := json.NewDecoder(strings.NewReader(text))
+	dec.UseNumber()
+	v, err := decodeOrderedValue(dec)
+	if err != nil {
+		return nil, err
+	} else if _, err := dec.Token(); !errors.Is(err, io.EOF) {
+		return nil, errors.New("trailing data after JSON value")
+	}
+	return v, nil
+}
+
+func decodeOrderedValue(dec *json.Decoder) (any, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	switch t := tok.(type) {
+	case json.Delim:
+		switch t {
+		case '{':
+			obj := &jsonObject{}
+			for dec.More() {
+				kt, err := dec.Token()
+				if err != nil {
+					return nil, err
+				}
+				v, err := decodeOrderedValue(dec)
+				if err != nil {
+					return nil, err
+				}
+				obj.keys = append(obj.keys, kt.(string))
+				obj.vals = append(obj.vals, v)
+			}
+			_, err := dec.Token()
+			return obj, err
+		case '[':
+			arr := &jsonArray{}
+			for dec.More() {
+				v, err := decodeOrderedValue(dec)
+				if err != nil {
+					return nil, err
+				}
+				arr.items = append(arr.items, v)
+			}
+			_, err := dec.Token()
+			return arr, err
+		}
+		return nil, fmt.Errorf("unexpected delimiter %v", t)
+	case string:
+		return &jsonString{full: t, keep: len(t)}, nil
+	default: // json.Number, bool, nil
+		return t, nil
+	}
+}
@@ -0,0 +1,60 @@
+package service
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestShapeOutput(t *testing.T) {
+	t.Parallel()
+
+	t.Run("under_budget", func(t *testing.T) {
+		assert.Equal(t, `{"a":1}`, shapeOutput(`{"a":1}`, 512, "out1", ""))
+	})
+
+	t.Run("json_elides_largest_string", func(t *testing.T) {
+		headers := "HTTP/1.1 200 OK\r\nContent-Type: text/html\r\nSet-Cookie: sid=1\r\n"
+		body := strings.Repeat("x", 5000) + "END"
+		in, err := json.MarshalIndent(map[string]any{"status": 200, "response_headers": headers, "response_body": body}, "", "  ")
+		require.NoError(t, err)
+
+		out := shapeOutput(string(in), 1024, "out1", "")
+		assert.LessOrEqual(t, len(out), 1024)
+
+		var got map[string]any
+		require.NoError(t, json.Unmarshal([]byte(out), &got))
+		assert.Equal(t, true, got["output_truncated"])
+		assert.Equal(t, "out1", got["output_id"])
+		assert.Equal(t, headers, got["response_headers"])
+		assert.Contains(t, got["response_body"], "bytes elided")
+		assert.True(t, strings.HasSuffix(got["response_body"].(string), "END"))
+		assert.Less(t, strings.Index(out, "output_truncated"), strings.Index(out, "response_body"))
+	})
+
+	t.Run("json_drops_array_items", func(t *testing.T) {
+		flows := make([]map[string]any, 200)
+		for i := range flows {
+			flows[i] = map[string]any{"flow_id": "abc", "path": "/api/items"}
+		}
+		in, err := json.MarshalIndent(map[string]any{"flows": flows}, "", "  ")
+		require.NoError(t, err)
+
+		out := shapeOutput(string(in), 2048, "out2", "page with limit and cursor")
+		assert.LessOrEqual(t, len(out), 2048)
+		assert.Contains(t, out, "more items elided")
+		assert.Contains(t, out, `"output_hint": "call output_get with output_id=out2 for the rest, or page with limit and cursor"`)
+		assert.True(t, json.Valid([]byte(out)))
+	})
+
+	t.Run("text_keeps_interesting_headers", func(t *testing.T) {
+		in := "HTTP/1.1 302 Found\n" + strings.Repeat("X-Pad: aaaaaaaaaa\n", 200) + "Location: /login\n" + strings.Repeat("X-Pad: bbbbbbbbbb\n", 200)
+		out := shapeOutput(in, 1024, "out3", "")
+		assert.True(t, strings.HasPrefix(out, "HTTP/1.1 302 Found\n"))
+		assert.Contains(t, out, "Location: /login")
+		assert.Contains(t, out, "output_id=out3")
+	})
+}
@@ -0,0 +1,294 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/go-harden/llm-security-toolbox/sectool/service/ids"
+)
+
+// Locations param_discover adds parameters to.
+const (
+	paramLocationQuery = "query"
+	paramLocationForm  = "form"
+	paramLocationJSON  = "json"
+)
+
+const (
+	defaultParamChunkSize   = 30
+	defaultParamMaxRequests = 100
+	// paramSimilarityMargin is how far below the baseline-to-baseline similarity a body
+	// must fall to count as changed.
+	paramSimilarityMargin = 0.02
+)
+
+// paramVolatileHeaders change between identical requests and are ignored when
+// comparing response headers.
+var paramVolatileHeaders = []string{"Date", "Content-Length", "Age", "Expires", "Last-Modified", "Etag", "Set-Cookie", "X-Request-Id", "X-Runtime"}
+
+// paramProbe is one request of a discovery run and its response.
+type paramProbe struct {
+	request []byte
+	result  *SendRequestResult
+	status  int
+}
+
+// paramCandidate is a parameter that changed the response, with the probe that
+// showed it.
+type paramCandidate struct {
+	name     string
+	evidence []string
+	probe    *paramProbe
+}
+
+// paramDiscoverer finds parameter names that change a response, arjun style: names
+// are sent in chunks with unique values, and chunks whose response differs from the
+// baseline are bisected until single names remain. Reflected values identify their
+// name directly.
+type paramDiscoverer struct {
+	send        func(ctx context.Context, request []byte) (*paramProbe, error)
+	base        []byte
+	location    string
+	values      map[string]string
+	baseline    *paramProbe
+	stable      float64 // body similarity between two baseline responses
+	maxRequests int
+
+	requests   int
+	stopped    bool
+	echoed     bool // every value of a chunk was reflected, so reflection is not evidence
+	candidates []paramCandidate
+}
+
+// paramLocation returns where parameters are added to raw by default: the body when it
+// is a form or JSON, otherwise the query string.
+func paramLocation(raw []byte) string {
+	headers, body := splitHeadersBody(raw)
+	if len(bytes.TrimSpace(body)) == 0 {
+		return paramLocationQuery
+	}
+	var contentType string
+	if values := parseHeadersToMap(string(headers))["Content-Type"]; len(values) > 0 {
+		contentType = strings.ToLower(values[0])
+	}
+	switch {
+	case strings.Contains(contentType, "json"):
+		return paramLocationJSON
+	case strings.Contains(contentType, "x-www-form-urlencoded"):
+		return paramLocationForm
+	}
+	return paramLocationQuery
+}
+
+// existingParams returns the parameter names already present at location in raw.
+func existingParams(raw []byte, location string) map[string]bool {
+	existing := make(map[string]bool)
+	headers, body := splitHeadersBody(raw)
+	var values url.Values
+	switch location {
+	case paramLocationQuery:
+		firstLine, _, _ := bytes.Cut(headers, []byte("\r\n"))
+		_, _, query, _ := parseRequestLine(string(firstLine))
+		values, _ = url.ParseQuery(query)
+	case paramLocationForm:
+		values, _ = url.ParseQuery(string(body))
+	case paramLocationJSON:
+		var obj map[string]interface{}
+		if err := json.Unmarshal(body, &obj); err == nil {
+			for k := range obj {
+				existing[k] = true
+			}
+		}
+	}
+	for k := range values {
+		existing[k] = true
+	}
+	return existing
+}
+
+// newParamDiscoverer prepares unique values for names, skipping those already in base.
+func newParamDiscoverer(base []byte, location string, names []string, maxRequests int,
+	send func(ctx context.Context, request []byte) (*paramProbe, error)) (*paramDiscoverer, []string) {
+	existing := existingParams(base, location)
+	prefix := "sx" + strings.ToLower(ids.Generate(5))
+	d := &paramDiscoverer{
+		send:        send,
+		base:        base,
+		location:    location,
+		values:      make(map[string]string),
+		maxRequests: maxRequests,
+	}
+	var tested []string
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if name == "" || existing[name] || d.values[name] != "" {
+			continue
+		}
+		d.values[name] = fmt.Sprintf("%s%04d", prefix, len(tested))
+		tested = append(tested, name)
+	}
+	return d, tested
+}
+
+// withParams returns the base request with names added at the discoverer's location.
+func (d *paramDiscoverer) withParams(names []string) ([]byte, error) {
+	if d.location == paramLocationJSON {
+		headers, body := splitHeadersBody(d.base)
+		set := make(map[string]interface{}, len(names))
+		for _, name := range names {
+			set[name] = d.values[name]
+		}
+		modified, err := modifyJSONBodyMap(body, set, nil)
+		if err != nil {
+			return nil, err
+		}
+		return append(updateContentLength(slices.Clone(headers), len(modified)), modified...), nil
+	}
+
+	pairs := make([]string, len(names))
+	for i, name := range names {
+		pairs[i] = url.QueryEscape(name) + "=" + d.values[name]
+	}
+	extra := strings.Join(pairs, "&")
+
+	headers, body := splitHeadersBody(d.base)
+	if d.location == paramLocationForm {
+		if len(body) > 0 {
+			extra = "&" + extra
+		}
+		body = append(slices.Clone(body), extra...)
+		return append(updateContentLength(slices.Clone(headers), len(body)), body...), nil
+	}
+
+	firstLine, rest, _ := bytes.Cut(headers, []byte("\r\n"))
+	method, path, query, version := parseRequestLine(string(firstLine))
+	if query != "" {
+		extra = query + "&" + extra
+	}
+	line := buildRequestLine(method, path, extra, version)
+	return append(append([]byte(line+"\r\n"), rest...), body...), nil
+}
+
+// run sends the baseline twice and then searches names in chunks of chunkSize.
+func (d *paramDiscoverer) run(ctx context.Context, names []string, chunkSize int) error {
+	first, err := d.send(ctx, d.base)
+	if err != nil {
+		return fmt.Errorf("baseline request failed: %w", err)
+	}
+	second, err := d.send(ctx, d.base)
+	if err != nil {
+		return fmt.Errorf("baseline request failed: %w", err)
+	}
+	d.requests += 2
+	if first.status != second.status {
+		return fmt.Errorf("unmodified request returned status %d then %d: responses are too unstable to compare", first.status, second.status)
+	}
+	d.baseline = second
+	d.stable = diffBodies(string(first.result.Headers), first.result.Body,
+		string(second.result.Headers), second.result.Body, diffOptions{noise: true}).Similarity
+
+	for chunk := range slices.Chunk(names, chunkSize) {
+		if err := d.search(ctx, chunk); err != nil {
+			return err
+		} else if d.stopped {
+			break
+		}
+	}
+	return nil
+}
+
+// search sends names together and bisects them while the response differs.
+func (d *paramDiscoverer) search(ctx context.Context, names []string) error {
+	if d.requests >= d.maxRequests {
+		d.stopped = true
+		return nil
+	}
+	request, err := d.withParams(names)
+	if err != nil {
+		return err
+	}
+	probe, err := d.send(ctx, request)
+	if err != nil {
+		return err
+	}
+	d.requests++
+
+	evidence := d.compare(probe)
+	var reflected, rest []string
+	for _, name := range names {
+		if !d.echoed && bytes.Contains(probe.result.Body, []byte(d.values[name])) &&
+			!bytes.Contains(d.baseline.result.Body, []byte(d.values[name])) {
+			reflected = append(reflected, name)
+		} else {
+			rest = append(rest, name)
+		}
+	}
+	if len(names) > 1 && len(reflected) == len(names) {
+		// The whole request is echoed back; judge this and later chunks on the rest
+		d.echoed = true
+		reflected, rest = nil, names
+	}
+	for _, name := range reflected {
+		d.candidates = append(d.candidates, paramCandidate{name: name, evidence: []string{"value reflected in response"}, probe: probe})
+	}
+
+	switch {
+	case len(evidence) == 0 || len(rest) == 0:
+		return nil
+	case len(rest) < len(names):
+		// Reflections alone change the body; check whether the others matter too
+		return d.search(ctx, rest)
+	case len(names) == 1:
+		d.candidates = append(d.candidates, paramCandidate{name: names[0], evidence: evidence, probe: probe})
+		return nil
+	}
+	half := len(names) / 2
+	if err := d.search(ctx, names[:half]); err != nil {
+		return err
+	}
+	return d.search(ctx, names[half:])
+}
+
+// compare describes how probe's response differs from the baseline, or returns nil.
+func (d *paramDiscoverer) compare(probe *paramProbe) []string {
+	base := d.baseline
+	var evidence []string
+	if probe.status != base.status {
+		evidence = append(evidence, fmt.Sprintf("status %d -> %d", base.status, probe.status))
+	}
+
+	similarity := diffBodies(string(base.result.Headers), base.result.Body,
+		string(probe.result.Headers), probe.result.Body, diffOptions{noise: true}).Similarity
+	if similarity < d.stable-paramSimilarityMargin {
+		evidence = append(evidence, fmt.Sprintf("body similarity %.2f (baseline %.2f), size %d -> %d",
+			similarity, d.stable, len(base.result.Body), len(probe.result.Body)))
+	}
+
+	baseHeaders := parseHeadersToMap(string(base.result.Headers))
+	probeHeaders := parseHeadersToMap(string(probe.result.Headers))
+	var added, removed []string
+	for name := range probeHeaders {
+		if _, ok := baseHeaders[name]; !ok && !slices.Contains(paramVolatileHeaders, name) {
+			added = append(added, name)
+		}
+	}
+	for name := range baseHeaders {
+		if _, ok := probeHeaders[name]; !ok && !slices.Contains(paramVolatileHeaders, name) {
+			removed = append(removed, name)
+		}
+	}
+	if len(added) > 0 {
+		slices.Sort(added)
+		evidence = append(evidence, "headers added: "+strings.Join(added, ", "))
+	}
+	if len(removed) > 0 {
+		slices.Sort(removed)
+		evidence = append(evidence, "headers removed: "+strings.Join(removed, ", "))
+	}
+	return evidence
+}
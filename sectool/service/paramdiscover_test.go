@@ -0,0 +1,106 @@
+package service
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testParamApp answers like an app with a debug switch, a reflected search term and
+// an admin field that adds a header, and ignores everything else.
+func testParamApp(t *testing.T) func(ctx context.Context, request []byte) (*paramProbe, error) {
+	return func(ctx context.Context, request []byte) (*paramProbe, error) {
+		firstLine, _, _ := strings.Cut(string(request), "\r\n")
+		_, _, query, _ := parseRequestLine(firstLine)
+		values, err := url.ParseQuery(query)
+		require.NoError(t, err)
+
+		status, headers, body := 200, "HTTP/1.1 200 OK\r\nContent-Type: text/html\r\n", "<html><body>"+strings.Repeat("<p>static content</p>", 20)
+		if values.Has("debug") {
+			status, headers = 500, "HTTP/1.1 500 Internal Server Error\r\nContent-Type: text/html\r\n"
+		}
+		if values.Has("admin") {
+			headers += "X-Admin: 1\r\n"
+		}
+		if q := values.Get("q"); q != "" {
+			body += "<p>Results for " + q + "</p>"
+		}
+		body += "</body></html>"
+		return &paramProbe{
+			request: request,
+			result:  &SendRequestResult{Headers: []byte(headers + "\r\n"), Body: []byte(body)},
+			status:  status,
+		}, nil
+	}
+}
+
+func TestParamDiscoverer(t *testing.T) {
+	t.Parallel()
+
+	base := []byte("GET /search?page=1 HTTP/1.1\r\nHost: example.com\r\n\r\n")
+	names := []string{"page", "a", "b", "q", "c", "debug", "d", "e", "admin", "f", "g", "h"}
+
+	t.Run("finds_parameters", func(t *testing.T) {
+		d, tested := newParamDiscoverer(base, paramLocationQuery, names, 100, testParamApp(t))
+		assert.NotContains(t, tested, "page")
+		assert.Len(t, tested, len(names)-1)
+		require.NoError(t, d.run(t.Context(), tested, 4))
+
+		found := make(map[string][]string)
+		for _, c := range d.candidates {
+			found[c.name] = c.evidence
+		}
+		assert.Equal(t, []string{"value reflected in response"}, found["q"])
+		assert.Equal(t, []string{"status 200 -> 500"}, found["debug"])
+		assert.Equal(t, []string{"headers added: X-Admin"}, found["admin"])
+		assert.Len(t, found, 3)
+		assert.False(t, d.stopped)
+	})
+
+	t.Run("max_requests", func(t *testing.T) {
+		d, tested := newParamDiscoverer(base, paramLocationQuery, names, 3, testParamApp(t))
+		require.NoError(t, d.run(t.Context(), tested, 4))
+		assert.True(t, d.stopped)
+		assert.Equal(t, 3, d.requests)
+	})
+}
+
+func TestParamDiscovererWithParams(t *testing.T) {
+	t.Parallel()
+
+	send := func(ctx context.Context, request []byte) (*paramProbe, error) { return nil, nil }
+
+	t.Run("query", func(t *testing.T) {
+		base := []byte("GET /x?a=1 HTTP/1.1\r\nHost: example.com\r\n\r\n")
+		d, _ := newParamDiscoverer(base, paramLocation(base), []string{"b"}, 10, send)
+		req, err := d.withParams([]string{"b"})
+		require.NoError(t, err)
+		assert.True(t, strings.HasPrefix(string(req), "GET /x?a=1&b="+d.values["b"]+" HTTP/1.1\r\n"))
+	})
+
+	t.Run("form", func(t *testing.T) {
+		base := []byte("POST /x HTTP/1.1\r\nContent-Type: application/x-www-form-urlencoded\r\nContent-Length: 3\r\n\r\na=1")
+		assert.Equal(t, paramLocationForm, paramLocation(base))
+		d, tested := newParamDiscoverer(base, paramLocationForm, []string{"a", "b"}, 10, send)
+		assert.Equal(t, []string{"b"}, tested)
+		req, err := d.withParams(tested)
+		require.NoError(t, err)
+		body := "a=1&b=" + d.values["b"]
+		assert.True(t, strings.HasSuffix(string(req), "\r\n\r\n"+body))
+		assert.Contains(t, string(req), "Content-Length: 17\r\n")
+	})
+
+	t.Run("json", func(t *testing.T) {
+		base := []byte("POST /x HTTP/1.1\r\nContent-Type: application/json\r\nContent-Length: 8\r\n\r\n{\"a\":1}")
+		assert.Equal(t, paramLocationJSON, paramLocation(base))
+		d, tested := newParamDiscoverer(base, paramLocationJSON, []string{"a", "role"}, 10, send)
+		assert.Equal(t, []string{"role"}, tested)
+		req, err := d.withParams(tested)
+		require.NoError(t, err)
+		assert.Contains(t, string(req), `"role":"`+d.values["role"]+`"`)
+	})
+}
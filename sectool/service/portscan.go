@@ -0,0 +1,352 @@
+package service
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	defaultPortScanThreads = 10
+	maxPortScanThreads     = 50
+	defaultPortScanRate    = 20 // connections per second
+	maxPortScanRate        = 200
+	maxPortScanTargets     = 5000 // host and port pairs per run
+	defaultPortTimeout     = 3 * time.Second
+	defaultBannerWait      = time.Second
+	maxBannerBytes         = 512
+)
+
+// defaultScanPorts are common TCP service ports checked when no list is given.
+var defaultScanPorts = []int{
+	21, 22, 23, 25, 53, 80, 81, 110, 111, 135, 139, 143, 389, 443, 445, 465, 587, 636,
+	873, 993, 995, 1080, 1433, 1521, 2049, 2375, 2376, 3000, 3306, 3389, 5000, 5432,
+	5601, 5672, 5900, 5984, 6379, 6443, 7001, 8000, 8008, 8080, 8081, 8443, 8888, 9000,
+	9090, 9200, 9300, 9443, 10250, 11211, 15672, 27017,
+}
+
+// wellKnownPorts names the service usually found on a port, used when the banner
+// does not identify it.
+var wellKnownPorts = map[int]string{
+	21: "ftp", 22: "ssh", 23: "telnet", 25: "smtp", 53: "dns", 80: "http", 110: "pop3",
+	111: "rpcbind", 135: "msrpc", 139: "netbios", 143: "imap", 389: "ldap", 443: "https",
+	445: "smb", 465: "smtps", 587: "smtp", 636: "ldaps", 873: "rsync", 993: "imaps",
+	995: "pop3s", 1080: "socks", 1433: "mssql", 1521: "oracle", 2049: "nfs", 2375: "docker",
+	2376: "docker-tls", 3306: "mysql", 3389: "rdp", 5432: "postgresql", 5601: "kibana",
+	5672: "amqp", 5900: "vnc", 5984: "couchdb", 6379: "redis", 6443: "kubernetes",
+	7001: "weblogic", 8080: "http-alt", 8443: "https-alt", 9200: "elasticsearch",
+	9300: "elasticsearch", 10250: "kubelet", 11211: "memcached", 15672: "rabbitmq",
+	27017: "mongodb",
+}
+
+// bannerServices identifies a service from the start of its banner.
+var bannerServices = []struct{ prefix, service string }{
+	{"SSH-", "ssh"},
+	{"HTTP/", "http"},
+	{"+OK", "pop3"},
+	{"* OK", "imap"},
+	{"-ERR", "redis"},
+	{"RFB ", "vnc"},
+	{"AMQP", "amqp"},
+}
+
+// parsePortSpec parses a comma-separated list of ports and ranges such as
+// "22,80,8000-8100". Duplicates are dropped and the order is kept.
+func parsePortSpec(spec string) ([]int, error) {
+	var ports []int
+	for _, part := range parseCommaSeparated(spec) {
+		lo, hi, isRange := strings.Cut(part, "-")
+		start, err := strconv.Atoi(strings.TrimSpace(lo))
+		if err != nil {
+			return nil, fmt.Errorf("invalid port %q", part)
+		}
+		end := start
+		if isRange {
+			if end, err = strconv.Atoi(strings.TrimSpace(hi)); err != nil || end < start {
+				return nil, fmt.Errorf("invalid port range %q", part)
+			}
+		}
+		if start < 1 || end > 65535 {
+			return nil, fmt.Errorf("port out of range in %q", part)
+		} else if end-start >= maxPortScanTargets {
+			return nil, fmt.Errorf("port range %q is larger than %d ports", part, maxPortScanTargets)
+		}
+		for port := start; port <= end; port++ {
+			if !slices.Contains(ports, port) {
+				ports = append(ports, port)
+			}
+		}
+	}
+	if len(ports) == 0 {
+		return nil, errors.New("no ports given")
+	}
+	return ports, nil
+}
+
+// portResult is the outcome of checking one host and port.
+type portResult struct {
+	host    string
+	port    int
+	state   string // open, closed or filtered
+	service string
+	banner  string
+	tls     bool
+}
+
+// portScanner connects to host and port pairs with a fixed number of workers and a
+// global connection rate, within the service's rate_limit config. Open ports are sent a banner probe: the server is given
+// bannerWait to speak first (SSH, SMTP, FTP), then sent an HTTP HEAD request, and
+// when neither answers, a TLS handshake is tried on a new connection.
+type portScanner struct {
+	dial       func(ctx context.Context, network, addr string) (net.Conn, error)
+	threads    int
+	rate       int          // connections per second
+	limiter    *rateLimiter // rate_limit config, applied to each connection on top of rate
+	timeout    time.Duration
+	bannerWait time.Duration
+
+	mu          sync.Mutex
+	connections int
+}
+
+// portTarget is a host and port to check.
+type portTarget struct {
+	host string
+	port int
+}
+
+func (s *portScanner) run(ctx context.Context, targets []portTarget) []portResult {
+	jobs := make(chan portTarget)
+	results := make(chan portResult)
+	ticker := time.NewTicker(time.Second / time.Duration(s.rate))
+	defer ticker.Stop()
+	// pace gates every connection, including banner follow-ups, on the shared rate and
+	// the limiter; the returned func frees the limiter's slots once the connection closes
+	pace := func(host string) (func(), bool) {
+		select {
+		case <-ctx.Done():
+			return nil, false
+		case <-ticker.C:
+		}
+		release, err := s.limiter.Wait(ctx, host)
+		if err != nil {
+			return nil, false
+		}
+		s.mu.Lock()
+		s.connections++
+		s.mu.Unlock()
+		return release, true
+	}
+
+	var wg sync.WaitGroup
+	for range s.threads {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range jobs {
+				release, ok := pace(j.host)
+				if !ok {
+					continue
+				}
+				results <- s.check(ctx, j.host, j.port, release, pace)
+			}
+		}()
+	}
+	go func() {
+		defer close(jobs)
+		for _, t := range targets {
+			select {
+			case <-ctx.Done():
+				return
+			case jobs <- t:
+			}
+		}
+	}()
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	var all []portResult
+	for r := range results {
+		all = append(all, r)
+	}
+	slices.SortFunc(all, comparePortResults)
+	return all
+}
+
+// check connects to one port and, when open, identifies the service. release frees
+// the pace slots of the first connection.
+func (s *portScanner) check(ctx context.Context, host string, port int, release func(), pace func(string) (func(), bool)) portResult {
+	r := portResult{host: host, port: port}
+	addr := net.JoinHostPort(host, strconv.Itoa(port))
+	dialCtx, cancel := context.WithTimeout(ctx, s.timeout)
+	conn, err := s.dial(dialCtx, "tcp", addr)
+	cancel()
+	if err != nil {
+		release()
+		if errors.Is(err, syscall.ECONNREFUSED) {
+			r.state = "closed"
+		} else {
+			r.state = "filtered"
+		}
+		return r
+	}
+	r.state = "open"
+
+	banner := readBanner(conn, s.bannerWait)
+	if len(banner) == 0 {
+		_ = conn.SetWriteDeadline(time.Now().Add(s.bannerWait))
+		if _, err := conn.Write([]byte("HEAD / HTTP/1.0\r\nHost: " + host + "\r\n\r\n")); err == nil {
+			banner = readBanner(conn, s.bannerWait)
+		}
+	}
+	_ = conn.Close()
+	release()
+
+	if len(banner) > 0 && !tlsOnlyReply(banner) {
+		r.banner = bannerText(banner)
+		r.service = bannerService(r.banner, port)
+		return r
+	}
+	if release, ok := pace(host); ok {
+		if version, subject, ok := s.tlsHandshake(ctx, host, addr); ok {
+			r.tls = true
+			r.banner = version
+			if subject != "" {
+				r.banner += ", " + subject
+			}
+		}
+		release()
+	}
+	r.service = wellKnownPorts[port]
+	if r.tls && (r.service == "" || r.service == "http" || r.service == "http-alt") {
+		r.service = "tls"
+	}
+	return r
+}
+
+func (s *portScanner) tlsHandshake(ctx context.Context, host, addr string) (string, string, bool) {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+	cfg := &tls.Config{InsecureSkipVerify: true}
+	if net.ParseIP(host) == nil {
+		cfg.ServerName = host
+	}
+	raw, err := s.dial(ctx, "tcp", addr)
+	if err != nil {
+		return "", "", false
+	}
+	conn := tls.Client(raw, cfg)
+	defer func() { _ = conn.Close() }()
+	if err := conn.HandshakeContext(ctx); err != nil {
+		return "", "", false
+	}
+	state := conn.ConnectionState()
+	var subject string
+	if len(state.PeerCertificates) > 0 {
+		subject = "CN=" + state.PeerCertificates[0].Subject.CommonName
+	}
+	return tls.VersionName(state.Version), subject, true
+}
+
+// tlsOnlyReply reports whether the reply to the HTTP probe shows the port expects TLS:
+// a TLS alert record, or an HTTP error about a plain request sent to an HTTPS port (Go,
+// nginx).
+func tlsOnlyReply(banner []byte) bool {
+	if banner[0] == 0x15 {
+		return true
+	}
+	lower := bytes.ToLower(banner)
+	return bytes.HasPrefix(lower, []byte("http/")) && bytes.Contains(lower, []byte("https"))
+}
+
+// readBanner reads what the peer sends within wait, up to maxBannerBytes.
+func readBanner(conn net.Conn, wait time.Duration) []byte {
+	_ = conn.SetReadDeadline(time.Now().Add(wait))
+	buf := make([]byte, maxBannerBytes)
+	var n int
+	for n < len(buf) {
+		m, err := conn.Read(buf[n:])
+		n += m
+		if err != nil {
+			break
+		}
+	}
+	return buf[:n]
+}
+
+// bannerText returns the first line of a text banner, or a binary banner quoted with
+// non-printable bytes escaped.
+func bannerText(banner []byte) string {
+	if utf8.Valid(banner) && !bytes.ContainsFunc(banner, func(r rune) bool { return r < 0x20 && r != '\t' && r != '\r' && r != '\n' }) {
+		line, _, _ := strings.Cut(strings.TrimLeft(string(banner), "\r\n"), "\n")
+		return strings.TrimSpace(line)
+	}
+	return strings.Trim(strconv.QuoteToASCII(string(banner)), `"`)
+}
+
+// bannerService identifies the service from its banner, falling back to the port's
+// usual service.
+func bannerService(banner string, port int) string {
+	for _, b := range bannerServices {
+		if strings.HasPrefix(banner, b.prefix) {
+			return b.service
+		}
+	}
+	if strings.HasPrefix(banner, "220") {
+		if strings.Contains(strings.ToUpper(banner), "FTP") {
+			return "ftp"
+		}
+		return "smtp"
+	}
+	return wellKnownPorts[port]
+}
+
+// portScanStore collects open ports across port_scan runs so crawl_results lists
+// them with the discovered endpoints.
+type portScanStore struct {
+	mu      sync.Mutex
+	entries []portResult
+}
+
+// add records open ports, replacing earlier results for the same host and port.
+func (s *portScanStore) add(results []portResult) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, r := range results {
+		if r.state != "open" {
+			continue
+		}
+		i := slices.IndexFunc(s.entries, func(e portResult) bool { return e.host == r.host && e.port == r.port })
+		if i >= 0 {
+			s.entries[i] = r
+		} else {
+			s.entries = append(s.entries, r)
+		}
+	}
+}
+
+// list returns the open ports sorted by host and port.
+func (s *portScanStore) list() []portResult {
+	s.mu.Lock()
+	entries := slices.Clone(s.entries)
+	s.mu.Unlock()
+	slices.SortFunc(entries, comparePortResults)
+	return entries
+}
+
+func comparePortResults(a, b portResult) int {
+	return cmp.Or(cmp.Compare(a.host, b.host), cmp.Compare(a.port, b.port))
+}
@@ -0,0 +1,143 @@
+package service
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePortSpec(t *testing.T) {
+	t.Parallel()
+
+	ports, err := parsePortSpec("22, 80,8000-8003,80")
+	require.NoError(t, err)
+	assert.Equal(t, []int{22, 80, 8000, 8001, 8002, 8003}, ports)
+
+	for _, spec := range []string{"", "http", "0", "70000", "90-80", "1-65535"} {
+		_, err := parsePortSpec(spec)
+		assert.Error(t, err, spec)
+	}
+}
+
+func TestBannerService(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "ssh", bannerService("SSH-2.0-OpenSSH_9.6", 2222))
+	assert.Equal(t, "ftp", bannerService("220 ProFTPD Server ready", 21))
+	assert.Equal(t, "smtp", bannerService("220 mail.example.com ESMTP Postfix", 2525))
+	assert.Equal(t, "redis", bannerService("-ERR unknown command 'HEAD'", 7000))
+	assert.Equal(t, "mysql", bannerService("J\\x00\\x00\\x00\\n8.0.36", 3306))
+	assert.Empty(t, bannerService("hello", 4000))
+
+	assert.Equal(t, `J\x00\x00\x00\n8.0.36`, bannerText([]byte("J\x00\x00\x00\n8.0.36")))
+	assert.Equal(t, "SSH-2.0-OpenSSH_9.6", bannerText([]byte("SSH-2.0-OpenSSH_9.6\r\n")))
+}
+
+func TestPortScanner(t *testing.T) {
+	t.Parallel()
+
+	sshPort := listenTestPort(t, func(conn net.Conn) {
+		_, _ = conn.Write([]byte("SSH-2.0-OpenSSH_9.6\r\n"))
+		_, _ = io.Copy(io.Discard, conn)
+	})
+	httpPort := listenTestPort(t, func(conn net.Conn) {
+		if _, err := http.ReadRequest(bufio.NewReader(conn)); err == nil {
+			_, _ = conn.Write([]byte("HTTP/1.0 200 OK\r\nServer: test\r\n\r\n"))
+		}
+	})
+	tlsSrv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	t.Cleanup(tlsSrv.Close)
+	u, err := url.Parse(tlsSrv.URL)
+	require.NoError(t, err)
+	tlsPort, err := strconv.Atoi(u.Port())
+	require.NoError(t, err)
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	closedPort := l.Addr().(*net.TCPAddr).Port
+	require.NoError(t, l.Close())
+
+	s := &portScanner{
+		dial:       (&net.Dialer{}).DialContext,
+		threads:    4,
+		rate:       200,
+		timeout:    time.Second,
+		bannerWait: 200 * time.Millisecond,
+	}
+	var targets []portTarget
+	for _, port := range []int{sshPort, httpPort, tlsPort, closedPort} {
+		targets = append(targets, portTarget{host: "127.0.0.1", port: port})
+	}
+	results := s.run(t.Context(), targets)
+	require.Len(t, results, 4)
+	byPort := make(map[int]portResult)
+	for _, r := range results {
+		byPort[r.port] = r
+	}
+
+	assert.Equal(t, portResult{host: "127.0.0.1", port: sshPort, state: "open", service: "ssh", banner: "SSH-2.0-OpenSSH_9.6"}, byPort[sshPort])
+	assert.Equal(t, "http", byPort[httpPort].service)
+	assert.Equal(t, "HTTP/1.0 200 OK", byPort[httpPort].banner)
+	assert.True(t, byPort[tlsPort].tls)
+	assert.Equal(t, "tls", byPort[tlsPort].service)
+	assert.Contains(t, byPort[tlsPort].banner, "TLS 1.3")
+	assert.Equal(t, "closed", byPort[closedPort].state)
+	assert.Equal(t, 5, s.connections) // one each, plus the TLS handshake
+}
+
+func TestPortScanner_RateLimit(t *testing.T) {
+	t.Parallel()
+
+	var targets []portTarget
+	for range 4 {
+		l, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		targets = append(targets, portTarget{host: "127.0.0.1", port: l.Addr().(*net.TCPAddr).Port})
+		require.NoError(t, l.Close())
+	}
+	s := &portScanner{
+		dial:       (&net.Dialer{}).DialContext,
+		threads:    4,
+		rate:       200,
+		limiter:    newRateLimiter(rateLimits{HostRPS: 20, HostConcurrent: 1}),
+		timeout:    time.Second,
+		bannerWait: 200 * time.Millisecond,
+	}
+	start := time.Now()
+	results := s.run(t.Context(), targets)
+	require.Len(t, results, 4)
+	// the configured 20/s per host wins over the scan's 200/s
+	assert.GreaterOrEqual(t, time.Since(start), 140*time.Millisecond)
+}
+
+func TestPortScanStore(t *testing.T) {
+	t.Parallel()
+
+	var s portScanStore
+	s.add([]portResult{
+		{host: "b.example.com", port: 22, state: "open", service: "ssh"},
+		{host: "a.example.com", port: 443, state: "open"},
+		{host: "a.example.com", port: 80, state: "closed"},
+	})
+	s.add([]portResult{{host: "a.example.com", port: 443, state: "open", service: "https", tls: true}})
+
+	entries := s.list()
+	require.Len(t, entries, 2)
+	assert.Equal(t, portResult{host: "a.example.com", port: 443, state: "open", service: "https", tls: true}, entries[0])
+	assert.Equal(t, "b.example.com", entries[1].host)
+}
+
+// listenTestPort serves handle on each connection to a local port and returns the port.
+func listenTestPort(t *testing.T, handle func(net.Conn)) int {
+	t.Helper()
+	_, port := newRawTestServer(t, handle)
+	return port
+}
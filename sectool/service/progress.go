@@ -0,0 +1,60 @@
+package service
+
+import (
+	"context"
+	"log"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// progressReporter sends notifications/progress for a tool call. Calls are no-ops
+// unless the client supplied a progressToken, so handlers can report unconditionally.
+type progressReporter struct {
+	notify   func(params map[string]any) error
+	progress float64
+	failed   bool
+}
+
+// newProgressReporter returns a reporter bound to the calling client, or nil when
+// the request carries no progress token.
+func newProgressReporter(ctx context.Context, req mcp.CallToolRequest) *progressReporter {
+	if req.Params.Meta == nil || req.Params.Meta.ProgressToken == nil {
+		return nil
+	}
+	srv := server.ServerFromContext(ctx)
+	if srv == nil {
+		return nil
+	}
+	token := req.Params.Meta.ProgressToken
+	return &progressReporter{
+		notify: func(params map[string]any) error {
+			params["progressToken"] = token
+			return srv.SendNotificationToClient(ctx, "notifications/progress", params)
+		},
+	}
+}
+
+// Enabled reports whether notifications will be delivered.
+func (p *progressReporter) Enabled() bool {
+	return p != nil && !p.failed
+}
+
+// Report sends progress out of total (0 if unknown). Progress never moves backwards.
+func (p *progressReporter) Report(progress, total float64, message string) {
+	if p == nil || p.failed {
+		return
+	}
+	p.progress = max(p.progress, progress)
+	params := map[string]any{"progress": p.progress}
+	if total > 0 {
+		params["total"] = total
+	}
+	if message != "" {
+		params["message"] = message
+	}
+	if err := p.notify(params); err != nil {
+		p.failed = true // client can't receive notifications, stop trying
+		log.Printf("mcp: progress notification failed: %v", err)
+	}
+}
@@ -0,0 +1,68 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPollOastWithProgress(t *testing.T) {
+	t.Parallel()
+
+	newReporter := func(sent *[]map[string]any) *progressReporter {
+		return &progressReporter{notify: func(params map[string]any) error {
+			*sent = append(*sent, params)
+			return nil
+		}}
+	}
+
+	t.Run("reports_between_chunks", func(t *testing.T) {
+		backend := newMockOastBackend()
+		sess, err := backend.CreateSession(t.Context(), "")
+		require.NoError(t, err)
+
+		var sent []map[string]any
+		result, err := pollOastWithProgress(t.Context(), backend, newReporter(&sent), 5*time.Second,
+			sess.ID, "", "", "", 20*time.Second, 0)
+		require.NoError(t, err)
+		assert.Empty(t, result.Events)
+
+		require.Len(t, sent, 3)
+		assert.InDelta(t, 5.0, sent[0]["progress"], 0.001)
+		assert.InDelta(t, 15.0, sent[2]["progress"], 0.001)
+		assert.InDelta(t, 20.0, sent[2]["total"], 0.001)
+	})
+
+	t.Run("returns_on_first_events", func(t *testing.T) {
+		backend := newMockOastBackend()
+		sess, err := backend.CreateSession(t.Context(), "")
+		require.NoError(t, err)
+		backend.events[sess.ID] = []OastEventInfo{{ID: "e1", Type: "dns"}}
+
+		var sent []map[string]any
+		result, err := pollOastWithProgress(t.Context(), backend, newReporter(&sent), 5*time.Second,
+			sess.ID, "", "", "", 20*time.Second, 0)
+		require.NoError(t, err)
+		assert.Len(t, result.Events, 1)
+		assert.Empty(t, sent)
+	})
+
+	t.Run("stops_after_failed_notification", func(t *testing.T) {
+		backend := newMockOastBackend()
+		sess, err := backend.CreateSession(t.Context(), "")
+		require.NoError(t, err)
+
+		var calls int
+		p := &progressReporter{notify: func(map[string]any) error {
+			calls++
+			return assert.AnError
+		}}
+		_, err = pollOastWithProgress(t.Context(), backend, p, 5*time.Second,
+			sess.ID, "", "", "", 20*time.Second, 0)
+		require.NoError(t, err)
+		assert.Equal(t, 1, calls)
+		assert.False(t, p.Enabled())
+	})
+}
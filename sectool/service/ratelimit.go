@@ -0,0 +1,207 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"golang.org/x/time/rate"
+
+	"github.com/go-harden/llm-security-toolbox/sectool/config"
+	"github.com/go-harden/llm-security-toolbox/sectool/protocol"
+)
+
+// rateLimits are politeness limits on outbound requests. Zero values are unlimited.
+type rateLimits struct {
+	RPS            float64       // requests per second across all hosts
+	HostRPS        float64       // requests per second to each host
+	Concurrent     int           // requests in flight across all hosts
+	HostConcurrent int           // requests in flight to each host
+	Jitter         time.Duration // random extra delay before each request, up to this
+}
+
+func rateLimitsFromConfig(c config.RateLimitConfig) rateLimits {
+	return rateLimits{
+		RPS:            c.RequestsPerSecond,
+		HostRPS:        c.HostRequestsPerSecond,
+		Concurrent:     c.MaxConcurrent,
+		HostConcurrent: c.HostMaxConcurrent,
+		Jitter:         time.Duration(c.JitterMS) * time.Millisecond,
+	}
+}
+
+// rateLimiter enforces rateLimits on the requests sent by replays, discovery and
+// brute-force tools and the crawler. A nil limiter allows everything. Thread-safe.
+type rateLimiter struct {
+	limits rateLimits
+	all    *rate.Limiter // nil when RPS is unlimited
+	allSem chan struct{} // nil when Concurrent is unlimited
+
+	mu    sync.Mutex
+	hosts map[string]*hostRateLimit
+}
+
+// hostRateLimit is the per-host state of a rateLimiter.
+type hostRateLimit struct {
+	rate *rate.Limiter
+	sem  chan struct{}
+}
+
+func newRateLimiter(limits rateLimits) *rateLimiter {
+	l := &rateLimiter{limits: limits, hosts: make(map[string]*hostRateLimit)}
+	if limits.RPS > 0 {
+		l.all = rate.NewLimiter(rate.Limit(limits.RPS), 1)
+	}
+	if limits.Concurrent > 0 {
+		l.allSem = make(chan struct{}, limits.Concurrent)
+	}
+	return l
+}
+
+func (l *rateLimiter) host(name string) *hostRateLimit {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	name = strings.ToLower(name)
+	h, ok := l.hosts[name]
+	if !ok {
+		h = &hostRateLimit{}
+		if l.limits.HostRPS > 0 {
+			h.rate = rate.NewLimiter(rate.Limit(l.limits.HostRPS), 1)
+		}
+		if l.limits.HostConcurrent > 0 {
+			h.sem = make(chan struct{}, l.limits.HostConcurrent)
+		}
+		l.hosts[name] = h
+	}
+	return h
+}
+
+// status reports the limits for the status tool, or nil when nothing is limited.
+func (l *rateLimiter) status() *protocol.RateLimitStatus {
+	if l == nil || l.limits == (rateLimits{}) {
+		return nil
+	}
+	return &protocol.RateLimitStatus{
+		RequestsPerSecond:     l.limits.RPS,
+		HostRequestsPerSecond: l.limits.HostRPS,
+		MaxConcurrent:         l.limits.Concurrent,
+		HostMaxConcurrent:     l.limits.HostConcurrent,
+		JitterMS:              int(l.limits.Jitter / time.Millisecond),
+	}
+}
+
+// Restricts reports whether Wait can delay a request made under ctx, so the hops of
+// a redirect chain must wait one by one.
+func (l *rateLimiter) Restricts(ctx context.Context) bool {
+	if _, ok := ctx.Value(runRateKey{}).(*runRate); ok {
+		return true
+	}
+	return l != nil && l.limits != rateLimits{}
+}
+
+// Wait blocks until a request to host may start, then returns a function that
+// frees the request's concurrency slots once it is done. Slots are taken before
+// rate tokens, host first, so a request queued for a slot does not spend tokens.
+// A rate set on ctx by withRunRate replaces the per-host rate.
+func (l *rateLimiter) Wait(ctx context.Context, host string) (func(), error) {
+	if l == nil {
+		return func() {}, nil
+	}
+	var h hostRateLimit
+	if l.limits.HostRPS > 0 || l.limits.HostConcurrent > 0 {
+		h = *l.host(host)
+	}
+	if run, ok := ctx.Value(runRateKey{}).(*runRate); ok {
+		h.rate = run.host(host)
+	}
+
+	var held []chan struct{}
+	release := func() {
+		for _, sem := range held {
+			<-sem
+		}
+	}
+	fail := func(err error) (func(), error) {
+		release()
+		return nil, fmt.Errorf("rate limit wait for %s: %w", host, err)
+	}
+	for _, sem := range []chan struct{}{h.sem, l.allSem} {
+		if sem == nil {
+			continue
+		}
+		select {
+		case sem <- struct{}{}:
+			held = append(held, sem)
+		case <-ctx.Done():
+			return fail(ctx.Err())
+		}
+	}
+	for _, lim := range []*rate.Limiter{h.rate, l.all} {
+		if lim == nil {
+			continue
+		}
+		if err := lim.Wait(ctx); err != nil {
+			return fail(err)
+		}
+	}
+	if l.limits.Jitter > 0 {
+		timer := time.NewTimer(rand.N(l.limits.Jitter))
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			return fail(ctx.Err())
+		}
+	}
+	return release, nil
+}
+
+// runRate is a per-host rate for the sends of one tool run, in place of the
+// configured one; 0 is unlimited.
+type runRate struct {
+	rps   float64
+	mu    sync.Mutex
+	hosts map[string]*rate.Limiter
+}
+
+func (r *runRate) host(name string) *rate.Limiter {
+	if r.rps <= 0 {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	name = strings.ToLower(name)
+	lim, ok := r.hosts[name]
+	if !ok {
+		lim = rate.NewLimiter(rate.Limit(r.rps), 1)
+		r.hosts[name] = lim
+	}
+	return lim
+}
+
+type runRateKey struct{}
+
+// withRunRate makes the sends made under ctx use rps as the per-host rate.
+func withRunRate(ctx context.Context, rps float64) context.Context {
+	return context.WithValue(ctx, runRateKey{}, &runRate{rps: rps, hosts: make(map[string]*rate.Limiter)})
+}
+
+// rateParamDescription documents the per-run override of rate_limit.host_requests_per_second.
+const rateParamDescription = "Requests per second to each host for this run, in place of the rate_limit.host_requests_per_second config (0: no per-host rate; other rate_limit settings still apply)"
+
+// applyRunRate returns ctx with the per-host rate of this run when req sets rate.
+func applyRunRate(ctx context.Context, req mcp.CallToolRequest) (context.Context, error) {
+	if _, ok := req.GetArguments()["rate"]; !ok {
+		return ctx, nil
+	}
+	rps, err := req.RequireFloat("rate")
+	if err != nil || rps < 0 {
+		return ctx, errors.New("rate must be a non-negative number of requests per second")
+	}
+	return withRunRate(ctx, rps), nil
+}
@@ -0,0 +1,95 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/go-harden/llm-security-toolbox/sectool/config"
+)
+
+func TestRateLimiter(t *testing.T) {
+	t.Parallel()
+
+	t.Run("nil_allows_all", func(t *testing.T) {
+		var l *rateLimiter
+		release, err := l.Wait(t.Context(), "example.com")
+		require.NoError(t, err)
+		release()
+	})
+
+	t.Run("host_concurrency", func(t *testing.T) {
+		l := newRateLimiter(rateLimits{HostConcurrent: 1})
+		release, err := l.Wait(t.Context(), "example.com")
+		require.NoError(t, err)
+
+		// Other hosts are not blocked.
+		other, err := l.Wait(t.Context(), "other.com")
+		require.NoError(t, err)
+		other()
+
+		ctx, cancel := context.WithTimeout(t.Context(), 20*time.Millisecond)
+		defer cancel()
+		_, err = l.Wait(ctx, "EXAMPLE.com")
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+
+		release()
+		again, err := l.Wait(t.Context(), "example.com")
+		require.NoError(t, err)
+		again()
+	})
+
+	t.Run("global_concurrency", func(t *testing.T) {
+		l := newRateLimiter(rateLimits{Concurrent: 1, HostConcurrent: 1})
+		release, err := l.Wait(t.Context(), "a.com")
+		require.NoError(t, err)
+
+		ctx, cancel := context.WithTimeout(t.Context(), 20*time.Millisecond)
+		defer cancel()
+		_, err = l.Wait(ctx, "b.com")
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		release()
+
+		// The failed wait gave back the host slot it took.
+		b, err := l.Wait(t.Context(), "b.com")
+		require.NoError(t, err)
+		b()
+	})
+
+	t.Run("host_rate", func(t *testing.T) {
+		l := newRateLimiter(rateLimits{HostRPS: 20})
+		start := time.Now()
+		for range 3 {
+			release, err := l.Wait(t.Context(), "example.com")
+			require.NoError(t, err)
+			release()
+		}
+		assert.GreaterOrEqual(t, time.Since(start), 90*time.Millisecond)
+
+		start = time.Now()
+		release, err := l.Wait(t.Context(), "other.com")
+		require.NoError(t, err)
+		release()
+		assert.Less(t, time.Since(start), 40*time.Millisecond)
+	})
+
+	t.Run("run_rate_replaces_host_rate", func(t *testing.T) {
+		l := newRateLimiter(rateLimits{HostRPS: 1})
+		ctx := withRunRate(t.Context(), 0)
+		start := time.Now()
+		for range 3 {
+			release, err := l.Wait(ctx, "example.com")
+			require.NoError(t, err)
+			release()
+		}
+		assert.Less(t, time.Since(start), 500*time.Millisecond)
+	})
+
+	t.Run("from_config", func(t *testing.T) {
+		limits := rateLimitsFromConfig(config.RateLimitConfig{HostRequestsPerSecond: 2.5, MaxConcurrent: 4, JitterMS: 150})
+		assert.Equal(t, rateLimits{HostRPS: 2.5, Concurrent: 4, Jitter: 150 * time.Millisecond}, limits)
+	})
+}
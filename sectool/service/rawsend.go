@@ -0,0 +1,111 @@
+package service
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strconv"
+	"time"
+)
+
+// rawSendInput describes a raw_send exchange.
+type rawSendInput struct {
+	Host      string
+	Port      int
+	TLS       bool
+	SNI       string   // TLS server name, default Host
+	ALPN      []string // offered TLS protocols
+	Data      []byte
+	HalfClose bool          // close the write side after sending
+	Wait      time.Duration // idle time that ends reading
+	MaxBytes  int
+}
+
+// rawSendResult is the data read back from a raw_send connection.
+type rawSendResult struct {
+	Data      []byte
+	Closed    bool // the peer closed the connection
+	Truncated bool // MaxBytes was reached
+	ALPN      string
+	Duration  time.Duration
+}
+
+// sendRaw writes in.Data to a TCP or TLS connection unchanged and reads until the
+// peer closes, in.Wait passes without data, or in.MaxBytes arrive. ctx bounds the
+// whole exchange.
+func sendRaw(ctx context.Context, in rawSendInput) (*rawSendResult, error) {
+	start := time.Now()
+	addr := net.JoinHostPort(in.Host, strconv.Itoa(in.Port))
+	conn, err := dialContext(ctx, "tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("dial %s: %w", addr, err)
+	}
+	defer func() { _ = conn.Close() }()
+	// Unblock reads and writes when ctx ends
+	stop := context.AfterFunc(ctx, func() { _ = conn.SetDeadline(time.Now()) })
+	defer stop()
+
+	result := &rawSendResult{}
+	rw := io.ReadWriter(conn)
+	if in.TLS {
+		serverName := in.SNI
+		if serverName == "" {
+			serverName = in.Host
+		}
+		tlsConn := tls.Client(conn, &tls.Config{
+			ServerName:         serverName,
+			InsecureSkipVerify: true,
+			NextProtos:         in.ALPN,
+		})
+		if err := tlsConn.HandshakeContext(ctx); err != nil {
+			return nil, wrapConnErr(ctx, "TLS handshake with "+addr, err)
+		}
+		result.ALPN = tlsConn.ConnectionState().NegotiatedProtocol
+		rw = tlsConn
+		defer func() { _ = tlsConn.Close() }()
+	}
+
+	if _, err := rw.Write(in.Data); err != nil {
+		return nil, wrapConnErr(ctx, "write", err)
+	}
+	if in.HalfClose {
+		if tlsConn, ok := rw.(*tls.Conn); ok {
+			_ = tlsConn.CloseWrite()
+		} else if tcpConn, ok := conn.(*net.TCPConn); ok {
+			_ = tcpConn.CloseWrite()
+		}
+	}
+
+	buf := make([]byte, 32*1024)
+	for len(result.Data) < in.MaxBytes {
+		if ctx.Err() != nil {
+			break
+		}
+		_ = conn.SetReadDeadline(time.Now().Add(in.Wait))
+		n, err := rw.Read(buf[:min(len(buf), in.MaxBytes-len(result.Data))])
+		result.Data = append(result.Data, buf[:n]...)
+		if errors.Is(err, os.ErrDeadlineExceeded) {
+			break
+		} else if errors.Is(err, io.EOF) || errors.Is(err, net.ErrClosed) {
+			result.Closed = true
+			break
+		} else if err != nil {
+			if ctx.Err() != nil {
+				break
+			}
+			// A reset after data was read still answers the probe
+			if len(result.Data) > 0 {
+				result.Closed = true
+				break
+			}
+			return nil, fmt.Errorf("read: %w", err)
+		}
+	}
+	result.Truncated = len(result.Data) >= in.MaxBytes
+	result.Duration = time.Since(start)
+	return result, nil
+}
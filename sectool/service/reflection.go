@@ -0,0 +1,154 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"unicode"
+
+	"github.com/go-harden/llm-security-toolbox/sectool/protocol"
+)
+
+// Reflection verdicts, ordered from weakest to strongest evidence.
+const (
+	reflectionNone      = "none"
+	reflectionUnlikely  = "unlikely"
+	reflectionAmbiguous = "ambiguous"
+	reflectionLikely    = "likely"
+)
+
+const (
+	minCanaryLength    = 4
+	reflectionSnippet  = 120 // bytes of context kept on each side of a reflection
+	maxReflectionsKept = 20
+)
+
+// splitProbe returns the leading alphanumeric canary of probe and the special
+// characters that follow it. The canary locates reflections even when the
+// specials are encoded or stripped.
+func splitProbe(probe string) (canary, specials string, err error) {
+	end := strings.IndexFunc(probe, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	if end < 0 {
+		end = len(probe)
+	}
+	if end < minCanaryLength {
+		return "", "", errors.New("probe must start with at least 4 alphanumeric characters (e.g. 'sect0x<\"'>')")
+	}
+	return probe[:end], probe[end:], nil
+}
+
+// findReflections locates each reflection of probe in body and classifies how much of
+// it survived in its HTML context.
+func findReflections(body, contentType, probe string) ([]protocol.Reflection, error) {
+	canary, specials, err := splitProbe(probe)
+	if err != nil {
+		return nil, err
+	}
+	isHTML := contentType == "" || strings.Contains(contentType, "html") || strings.Contains(contentType, "xml")
+
+	var reflections []protocol.Reflection
+	for pos := 0; len(reflections) < maxReflectionsKept; {
+		idx := strings.Index(body[pos:], canary)
+		if idx < 0 {
+			break
+		}
+		at := pos + idx
+		pos = at + len(canary)
+
+		raw := survivingSpecials(body[pos:], specials)
+		htmlCtx := "non_html"
+		if isHTML {
+			htmlCtx = htmlContextAt(body, at)
+		}
+		reflections = append(reflections, protocol.Reflection{
+			Offset:   at,
+			Context:  htmlCtx,
+			RawChars: raw,
+			Verdict:  judgeReflection(htmlCtx, raw),
+			Snippet:  body[max(0, at-reflectionSnippet):min(len(body), pos+len(specials)+reflectionSnippet)],
+		})
+	}
+	return reflections, nil
+}
+
+// survivingSpecials returns which probe specials appear unencoded after the canary.
+// HTML entities, backslash escapes and percent-encoding are skipped as encoded; any
+// other change means the rest of the probe was stripped or rewritten.
+func survivingSpecials(after, specials string) string {
+	var raw strings.Builder
+	var i int
+	for j := 0; j < len(specials) && i < len(after); j++ {
+		switch c := specials[j]; {
+		case after[i] == c:
+			raw.WriteByte(c)
+			i++
+		case after[i] == '&':
+			end := strings.IndexByte(after[i:], ';')
+			if end < 0 || end > 10 {
+				return raw.String()
+			}
+			i += end + 1
+		case after[i] == '\\' && strings.HasPrefix(after[i+1:], "u"):
+			i += 6 // \u003c
+		case after[i] == '\\':
+			i += 2
+		case after[i] == '%':
+			i += 3
+		default:
+			return raw.String()
+		}
+	}
+	return raw.String()
+}
+
+// htmlContextAt classifies the HTML context of offset: script, comment, attribute, or html.
+func htmlContextAt(body string, offset int) string {
+	before := strings.ToLower(body[:offset])
+	if open := strings.LastIndex(before, "<script"); open >= 0 && !strings.Contains(before[open:], "</script") {
+		return "script"
+	} else if open := strings.LastIndex(before, "<!--"); open >= 0 && !strings.Contains(before[open:], "-->") {
+		return "comment"
+	} else if strings.LastIndexByte(before, '<') > strings.LastIndexByte(before, '>') {
+		return "attribute"
+	}
+	return "html"
+}
+
+// judgeReflection decides the clear-cut cases and leaves the rest ambiguous.
+func judgeReflection(where, raw string) string {
+	hasAny := func(chars string) bool { return strings.ContainsAny(raw, chars) }
+	switch where {
+	case "html":
+		if hasAny("<") {
+			return reflectionLikely
+		}
+		return reflectionUnlikely
+	case "attribute":
+		if hasAny(`"`) && hasAny("'") || hasAny("<") && hasAny(">") {
+			return reflectionLikely
+		} else if hasAny(`"'`) {
+			return reflectionAmbiguous // exploitable only if it matches the attribute's quote
+		}
+		return reflectionUnlikely
+	case "script", "comment":
+		if raw == "" {
+			return reflectionUnlikely
+		}
+		return reflectionAmbiguous // depends on the surrounding string or comment syntax
+	default: // non_html responses are not rendered as markup
+		return reflectionUnlikely
+	}
+}
+
+// overallVerdict returns the strongest verdict among reflections.
+func overallVerdict(reflections []protocol.Reflection) string {
+	rank := map[string]int{reflectionNone: 0, reflectionUnlikely: 1, reflectionAmbiguous: 2, reflectionLikely: 3}
+	verdict := reflectionNone
+	for _, r := range reflections {
+		if rank[r.Verdict] > rank[verdict] {
+			verdict = r.Verdict
+		}
+	}
+	return verdict
+}
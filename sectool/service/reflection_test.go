@@ -0,0 +1,109 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindReflections(t *testing.T) {
+	t.Parallel()
+
+	const probe = `sect0x<"'>`
+
+	tests := []struct {
+		name        string
+		body        string
+		contentType string
+		context     string
+		raw         string
+		verdict     string
+	}{
+		{
+			name:    "html_raw",
+			body:    `<p>Hello sect0x<"'></p>`,
+			context: "html",
+			raw:     `<"'>`,
+			verdict: reflectionLikely,
+		},
+		{
+			name:    "html_encoded",
+			body:    `<p>Hello sect0x&lt;&quot;&#39;&gt;</p>`,
+			context: "html",
+			verdict: reflectionUnlikely,
+		},
+		{
+			name:    "attribute_one_quote",
+			body:    `<input value="sect0x<&quot;'&gt;">`,
+			context: "attribute",
+			raw:     "<'",
+			verdict: reflectionAmbiguous,
+		},
+		{
+			name:    "attribute_quotes_survive",
+			body:    `<input value='sect0x&lt;"'&gt;'>`,
+			context: "attribute",
+			raw:     `"'`,
+			verdict: reflectionLikely,
+		},
+		{
+			name:    "script_string",
+			body:    `<script>var q = "sect0x<\"'>";</script>`,
+			context: "script",
+			raw:     "<'>",
+			verdict: reflectionAmbiguous,
+		},
+		{
+			name:    "comment",
+			body:    `<!-- debug: sect0x<"'> -->`,
+			context: "comment",
+			raw:     `<"'>`,
+			verdict: reflectionAmbiguous,
+		},
+		{
+			name:        "json_body",
+			body:        `{"q":"sect0x\u003c\"'\u003e"}`,
+			contentType: "application/json",
+			context:     "non_html",
+			raw:         "'",
+			verdict:     reflectionUnlikely,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			refs, err := findReflections(tc.body, tc.contentType, probe)
+			require.NoError(t, err)
+			require.Len(t, refs, 1)
+			assert.Equal(t, tc.context, refs[0].Context)
+			assert.Equal(t, tc.raw, refs[0].RawChars)
+			assert.Equal(t, tc.verdict, refs[0].Verdict)
+		})
+	}
+
+	t.Run("short_canary", func(t *testing.T) {
+		_, err := findReflections("body", "", `ab<`)
+		assert.Error(t, err)
+	})
+
+	t.Run("no_reflection", func(t *testing.T) {
+		refs, err := findReflections("<p>nothing</p>", "text/html", probe)
+		require.NoError(t, err)
+		assert.Empty(t, refs)
+		assert.Equal(t, reflectionNone, overallVerdict(refs))
+	})
+}
+
+func TestParseAdjudication(t *testing.T) {
+	t.Parallel()
+
+	adj, err := parseAdjudication("VERDICT: likely\nThe quote closes the JS string.", "m1")
+	require.NoError(t, err)
+	assert.Equal(t, reflectionLikely, adj.Verdict)
+	assert.Equal(t, "The quote closes the JS string.", adj.Reason)
+	assert.Equal(t, "m1", adj.Model)
+
+	_, err = parseAdjudication("maybe?", "")
+	assert.Error(t, err)
+}
@@ -0,0 +1,64 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// recentRefUsage documents the shortcuts accepted in place of flow_id/replay_id.
+const recentRefUsage = "'last' or 'last-N' (N back from newest)"
+
+// parseRecentRef parses "last" or "last-N" into N (0 for "last").
+func parseRecentRef(ref string) (int, bool) {
+	if ref == "last" {
+		return 0, true
+	}
+	rest, ok := strings.CutPrefix(ref, "last-")
+	if !ok {
+		return 0, false
+	}
+	n, err := strconv.Atoi(rest)
+	if err != nil || n < 0 {
+		return 0, false
+	}
+	return n, true
+}
+
+// resolveFlowRef expands "last"/"last-N" to the proxy flow_id of the newest
+// (or Nth newest) history entry, registering it if needed. Other refs are returned unchanged.
+func (s *Server) resolveFlowRef(ctx context.Context, ref string) (string, error) {
+	n, ok := parseRecentRef(ref)
+	if !ok {
+		return ref, nil
+	}
+
+	entries, err := s.fetchAllProxyEntries(ctx)
+	if err != nil {
+		return "", fmt.Errorf("resolve %q: %w", ref, err)
+	} else if n >= len(entries) {
+		return "", fmt.Errorf("%q is out of range: proxy history has %d entries", ref, len(entries))
+	}
+	return s.registerFlow(entries[len(entries)-1-n]), nil
+}
+
+// resolveReplayRef expands "last"/"last-N" and replay labels to a replay_id.
+// Unknown refs are returned unchanged so the caller reports not-found.
+func (s *Server) resolveReplayRef(ref string) (string, error) {
+	if _, ok := s.requestStore.Get(ref); ok {
+		return ref, nil
+	}
+	if n, ok := parseRecentRef(ref); ok {
+		id, found := s.requestStore.Recent(n)
+		if !found {
+			return "", errors.New("no replay at " + ref + ": replay results are ephemeral and cleared on service restart")
+		}
+		return id, nil
+	}
+	if id, ok := s.requestStore.LookupLabel(ref); ok {
+		return id, nil
+	}
+	return ref, nil
+}
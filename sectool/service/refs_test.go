@@ -0,0 +1,33 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseRecentRef(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		ref    string
+		wantN  int
+		wantOK bool
+	}{
+		{ref: "last", wantN: 0, wantOK: true},
+		{ref: "last-1", wantN: 1, wantOK: true},
+		{ref: "last-12", wantN: 12, wantOK: true},
+		{ref: "last-", wantOK: false},
+		{ref: "last--1", wantOK: false},
+		{ref: "last-x", wantOK: false},
+		{ref: "lastly", wantOK: false},
+		{ref: "f7k2x", wantOK: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.ref, func(t *testing.T) {
+			n, ok := parseRecentRef(tt.ref)
+			assert.Equal(t, tt.wantOK, ok)
+			assert.Equal(t, tt.wantN, n)
+		})
+	}
+}
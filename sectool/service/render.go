@@ -0,0 +1,120 @@
+package service
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/net/html"
+
+	"github.com/go-harden/llm-security-toolbox/sectool/bundle"
+)
+
+// Body render modes for replay_get.
+const (
+	renderAuto = "auto" // chosen from Content-Type and content
+	renderJSON = "json" // pretty-printed
+	renderText = "text" // visible text of an HTML document
+	renderHex  = "hex"  // hex dump
+	renderRaw  = "raw"  // decoded body as is
+)
+
+// renderHexMaxBytes caps the bytes shown in a hex dump.
+const renderHexMaxBytes = 8192
+
+var validRenderModes = map[string]bool{renderAuto: true, renderJSON: true, renderText: true, renderHex: true, renderRaw: true}
+
+// renderBody decodes a response body's transfer and content codings and formats it
+// for reading, returning the text and the mode applied (auto resolves to another mode).
+func renderBody(headers, body []byte, mode string) (string, string, error) {
+	body, _ = bundle.DecodeBody(string(headers), body)
+	if mode == renderAuto {
+		mode = autoRenderMode(headers, body)
+	}
+
+	switch mode {
+	case renderJSON:
+		var buf bytes.Buffer
+		if err := json.Indent(&buf, bytes.TrimSpace(body), "", "  "); err != nil {
+			return "", mode, errors.New("response body is not JSON")
+		}
+		return buf.String(), mode, nil
+	case renderText:
+		doc, err := goquery.NewDocumentFromReader(bytes.NewReader(body))
+		if err != nil {
+			return "", mode, fmt.Errorf("parse HTML: %w", err)
+		}
+		doc.Find("script, style, noscript, template").Remove()
+		var sb strings.Builder
+		writeHTMLText(&sb, doc.Get(0))
+		var lines []string
+		for _, line := range strings.Split(sb.String(), "\n") {
+			if line = strings.Join(strings.Fields(line), " "); line != "" {
+				lines = append(lines, line)
+			}
+		}
+		return strings.Join(lines, "\n"), mode, nil
+	case renderHex:
+		if len(body) > renderHexMaxBytes {
+			return hex.Dump(body[:renderHexMaxBytes]) + fmt.Sprintf("… %d more bytes\n", len(body)-renderHexMaxBytes), mode, nil
+		}
+		return hex.Dump(body), mode, nil
+	default:
+		if !utf8.Valid(body) {
+			return "", mode, errors.New("response body is binary; use render=hex")
+		}
+		return string(body), renderRaw, nil
+	}
+}
+
+// htmlBlockElements start a new line in rendered HTML text.
+var htmlBlockElements = map[string]bool{
+	"address": true, "article": true, "aside": true, "blockquote": true, "br": true, "dd": true, "div": true,
+	"dl": true, "dt": true, "fieldset": true, "figcaption": true, "footer": true, "form": true, "h1": true,
+	"h2": true, "h3": true, "h4": true, "h5": true, "h6": true, "header": true, "hr": true, "li": true,
+	"main": true, "nav": true, "ol": true, "option": true, "p": true, "pre": true, "section": true,
+	"table": true, "td": true, "th": true, "title": true, "tr": true, "ul": true,
+}
+
+// writeHTMLText writes the text under n, with block elements on their own lines.
+func writeHTMLText(sb *strings.Builder, n *html.Node) {
+	switch n.Type {
+	case html.TextNode:
+		sb.WriteString(n.Data)
+		return
+	case html.ElementNode:
+		if htmlBlockElements[n.Data] {
+			sb.WriteByte('\n')
+			defer sb.WriteByte('\n')
+		}
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		writeHTMLText(sb, c)
+	}
+}
+
+// autoRenderMode picks a render mode from the Content-Type, falling back to sniffing.
+func autoRenderMode(headers, body []byte) string {
+	contentType := strings.ToLower(http.Header(parseHeadersToMap(string(headers))).Get("Content-Type"))
+	if contentType == "" {
+		contentType = strings.ToLower(http.DetectContentType(body))
+	}
+	switch {
+	case !utf8.Valid(body):
+		return renderHex
+	case strings.Contains(contentType, "json") && json.Valid(body):
+		return renderJSON
+	case strings.Contains(contentType, "html"):
+		return renderText
+	case json.Valid(body) && bytes.ContainsAny(bytes.TrimSpace(body)[:1], "{["):
+		return renderJSON
+	default:
+		return renderRaw
+	}
+}
@@ -0,0 +1,61 @@
+package service
+
+import (
+	"bytes"
+	"compress/gzip"
+	"strings"
+	"testing"
+
+	"github.com/andybalholm/brotli"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderBody(t *testing.T) {
+	t.Parallel()
+
+	var gz bytes.Buffer
+	gw := gzip.NewWriter(&gz)
+	_, _ = gw.Write([]byte(`{"a":[1,2]}`))
+	require.NoError(t, gw.Close())
+	var br bytes.Buffer
+	bw := brotli.NewWriter(&br)
+	_, _ = bw.Write([]byte("<html><head><style>p{}</style><script>x()</script></head><body><h1>Title</h1>\n  <p>Hello   <b>world</b></p></body></html>"))
+	require.NoError(t, bw.Close())
+
+	tests := []struct {
+		name     string
+		headers  string
+		body     []byte
+		mode     string
+		want     string
+		wantMode string
+		wantErr  bool
+	}{
+		{"auto_json_gzip", "HTTP/1.1 200 OK\r\nContent-Type: application/json\r\nContent-Encoding: gzip\r\n\r\n", gz.Bytes(), renderAuto, "{\n  \"a\": [\n    1,\n    2\n  ]\n}", renderJSON, false},
+		{"auto_html_brotli", "HTTP/1.1 200 OK\r\nContent-Type: text/html\r\nContent-Encoding: br\r\n\r\n", br.Bytes(), renderAuto, "Title\nHello world", renderText, false},
+		{"auto_sniffed_json", "HTTP/1.1 200 OK\r\nContent-Type: text/plain\r\n\r\n", []byte(` [true]`), renderAuto, "[\n  true\n]", renderJSON, false},
+		{"auto_text", "HTTP/1.1 200 OK\r\n\r\n", []byte("plain"), renderAuto, "plain", renderRaw, false},
+		{"auto_binary", "HTTP/1.1 200 OK\r\n\r\n", []byte{0xff, 0x00}, renderAuto, "00000000  ff 00                                             |..|\n", renderHex, false},
+		{"json_invalid", "HTTP/1.1 200 OK\r\n\r\n", []byte("<x>"), renderJSON, "", "", true},
+		{"raw_binary", "HTTP/1.1 200 OK\r\n\r\n", []byte{0xff}, renderRaw, "", "", true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, mode, err := renderBody([]byte(tc.headers), tc.body, tc.mode)
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+			assert.Equal(t, tc.wantMode, mode)
+		})
+	}
+
+	t.Run("hex_capped", func(t *testing.T) {
+		got, _, err := renderBody([]byte("HTTP/1.1 200 OK\r\n\r\n"), bytes.Repeat([]byte{0}, renderHexMaxBytes+10), renderHex)
+		require.NoError(t, err)
+		assert.True(t, strings.HasSuffix(got, "… 10 more bytes\n"))
+	})
+}
@@ -0,0 +1,151 @@
+package service
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/go-harden/llm-security-toolbox/sectool/protocol"
+)
+
+const (
+	rulePreviewFlows    = 100 // most recent flows scanned by the add-time preview
+	rulePreviewExamples = 3
+	rulePreviewMaxText  = 120
+)
+
+// javaOnlyRegex finds constructs that Java's regex engine (used by Burp) accepts but
+// Go's RE2 engine (used by the built-in proxy and previews) rejects.
+var javaOnlyRegex = []struct {
+	re   *regexp.Regexp
+	name string
+}{
+	{regexp.MustCompile(`\(\?<?[=!]`), "lookaround"},
+	{regexp.MustCompile(`\(\?>`), "atomic group"},
+	{regexp.MustCompile(`\\[1-9]`), "backreference"},
+	{regexp.MustCompile(`[*+?}]\+`), "possessive quantifier"},
+	{regexp.MustCompile(`\\[ZGRhHXk]`), "Java escape"},
+	{regexp.MustCompile(`\\[pP]\{(?:Is|In|java|Alpha|Alnum|Digit|Lower|Upper|Punct|Space|Blank|Cntrl|XDigit|Print|Graph|ASCII)`), "Java character class"},
+	{regexp.MustCompile(`\(\?[a-zA-Z]*[xdcu]`), "Java inline flag"},
+}
+
+var (
+	goNamedGroupRe  = regexp.MustCompile(`\(\?P<`)
+	posixClassRe    = regexp.MustCompile(`\[\[:\^?[a-z]+:\]`)
+	goUngreedyRe    = regexp.MustCompile(`\(\?[a-zA-Z]*U`)
+	goNumberedRefRe = regexp.MustCompile(`\$[0-9]+[A-Za-z_]`)
+	javaBadRefRe    = regexp.MustCompile(`\$(?:[^0-9{]|\{[^A-Za-z]|$)`)
+)
+
+// validateRuleRegex checks a regex rule's pattern and replacement for the engine that
+// will apply it (Java for Burp, Go otherwise). It returns warnings for patterns that
+// are accepted but behave differently than they look, and an error for patterns the
+// engine would reject. Burp rejects bad patterns silently, so catching them here
+// matters.
+func validateRuleRegex(match, replace string, java bool) (warnings []string, err error) {
+	_, goErr := regexp.Compile(match)
+	var javaOnly []string
+	for _, c := range javaOnlyRegex {
+		if c.re.MatchString(match) {
+			javaOnly = append(javaOnly, c.name)
+		}
+	}
+
+	if !java {
+		if goErr != nil {
+			if len(javaOnly) > 0 {
+				return nil, fmt.Errorf("invalid regex pattern: the built-in proxy uses Go regex (RE2), which does not support %s: %w",
+					strings.Join(javaOnly, ", "), goErr)
+			}
+			return nil, fmt.Errorf("invalid regex pattern: %w", goErr)
+		}
+		if goNumberedRefRe.MatchString(replace) {
+			warnings = append(warnings, "Go reads $1x as a group named \"1x\"; write ${1}x to follow a group reference with text")
+		}
+		return warnings, nil
+	}
+
+	if goNamedGroupRe.MatchString(match) {
+		return nil, errors.New("invalid regex pattern: (?P<name>...) is Go syntax; Burp uses Java regex, write (?<name>...)")
+	} else if goErr != nil && len(javaOnly) == 0 {
+		return nil, fmt.Errorf("invalid regex pattern: %w", goErr)
+	} else if javaBadRefRe.MatchString(replace) {
+		return nil, errors.New(`invalid replacement: Java treats $ as a group reference ($1 or ${name}); escape a literal dollar as \$`)
+	}
+
+	if goErr != nil {
+		warnings = append(warnings, "pattern uses Java-only syntax ("+strings.Join(javaOnly, ", ")+"); Burp accepts it but the preview and proxy_rule_test cannot evaluate it")
+	}
+	if posixClassRe.MatchString(match) {
+		warnings = append(warnings, "[[:class:]] is Go/POSIX syntax; Java reads it as a plain character set, use \\p{Alpha} style classes instead")
+	}
+	if goUngreedyRe.MatchString(match) {
+		warnings = append(warnings, "(?U) means ungreedy in Go but Unicode character classes in Java")
+	}
+	return warnings, nil
+}
+
+// previewRule runs rule over the recent flows it would apply to and reports how many it
+// would modify, with a few example matches. Returns nil for rules that can't be
+// previewed from HTTP history (WebSocket, session_token, header-add, Java-only regex).
+func (s *Server) previewRule(entries []flowEntry, rule storedRule) *protocol.RulePreview {
+	if isWSType(rule.Type) || rule.Type == RuleTypeSessionToken || rule.Match == "" {
+		return nil
+	}
+	if rule.IsRegex && rule.compiled == nil {
+		compiled, err := regexp.Compile(rule.Match)
+		if err != nil {
+			return nil
+		}
+		rule.compiled = compiled
+	}
+
+	if len(entries) > rulePreviewFlows {
+		entries = entries[len(entries)-rulePreviewFlows:]
+	}
+	preview := &protocol.RulePreview{Scanned: len(entries)}
+	for i := len(entries) - 1; i >= 0; i-- { // newest first
+		msg := entries[i].request
+		if strings.HasPrefix(rule.Type, "response_") {
+			msg = entries[i].response
+		}
+		headers, body := splitHeadersBody([]byte(msg))
+		section := body
+		if strings.HasSuffix(rule.Type, "_header") {
+			section = headers
+		}
+
+		match, result, ok := firstRuleMatch(section, rule)
+		if !ok {
+			continue
+		}
+		preview.Matched++
+		if len(preview.Examples) < rulePreviewExamples {
+			preview.Examples = append(preview.Examples, protocol.RulePreviewMatch{
+				FlowID: s.registerFlow(entries[i]),
+				Match:  truncateString(match, rulePreviewMaxText),
+				Result: truncateString(result, rulePreviewMaxText),
+			})
+		}
+	}
+	return preview
+}
+
+// firstRuleMatch returns the first text rule matches in data and what it becomes.
+func firstRuleMatch(data []byte, rule storedRule) (match, result string, ok bool) {
+	if !rule.IsRegex {
+		if !bytes.Contains(data, []byte(rule.Match)) {
+			return "", "", false
+		}
+		return rule.Match, rule.Replace, true
+	}
+
+	loc := rule.compiled.FindSubmatchIndex(data)
+	if loc == nil {
+		return "", "", false
+	}
+	expanded := rule.compiled.Expand(nil, []byte(rule.Replace), data, loc)
+	return string(data[loc[0]:loc[1]]), string(expanded), true
+}
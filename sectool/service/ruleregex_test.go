@@ -0,0 +1,67 @@
+package service
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateRuleRegex(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		match        string
+		replace      string
+		java         bool
+		wantErr      string
+		wantWarnings int
+	}{
+		{name: "go_valid", match: `token=(\w+)`, replace: "token=${1}x"},
+		{name: "go_invalid", match: `(`, wantErr: "invalid regex pattern"},
+		{name: "go_lookahead", match: `foo(?=bar)`, wantErr: "does not support lookaround"},
+		{name: "go_ambiguous_ref", match: `(\w+)`, replace: "$1x", wantWarnings: 1},
+		{name: "java_valid", match: `token=(\w+)`, replace: "token=$1", java: true},
+		{name: "java_lookahead", match: `foo(?=bar)`, java: true, wantWarnings: 1},
+		{name: "java_go_named_group", match: `(?P<id>\d+)`, java: true, wantErr: "(?<name>...)"},
+		{name: "java_invalid", match: `[a-`, java: true, wantErr: "invalid regex pattern"},
+		{name: "java_literal_dollar", match: `price`, replace: "$5", java: true},
+		{name: "java_bad_ref", match: `price`, replace: "cost $", java: true, wantErr: "invalid replacement"},
+		{name: "java_posix_class", match: `[[:alpha:]]+`, java: true, wantWarnings: 1},
+		{name: "java_ungreedy_flag", match: `(?U)a+`, java: true, wantWarnings: 1},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			warnings, err := validateRuleRegex(tc.match, tc.replace, tc.java)
+			if tc.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Len(t, warnings, tc.wantWarnings)
+		})
+	}
+}
+
+func TestFirstRuleMatch(t *testing.T) {
+	t.Parallel()
+
+	data := []byte("id=12&id=34")
+
+	match, result, ok := firstRuleMatch(data, storedRule{Match: "id=34", Replace: "id=1"})
+	require.True(t, ok)
+	assert.Equal(t, "id=34", match)
+	assert.Equal(t, "id=1", result)
+
+	rule := storedRule{Match: `id=(\d+)`, Replace: "uid=$1", IsRegex: true, compiled: regexp.MustCompile(`id=(\d+)`)}
+	match, result, ok = firstRuleMatch(data, rule)
+	require.True(t, ok)
+	assert.Equal(t, "id=12", match)
+	assert.Equal(t, "uid=12", result)
+
+	_, _, ok = firstRuleMatch(data, storedRule{Match: "missing"})
+	assert.False(t, ok)
+}
@@ -0,0 +1,117 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/go-harden/llm-security-toolbox/sectool/config"
+)
+
+// projectScope returns the project scope, or nil if none is defined.
+func (s *Server) projectScope() *config.Scope {
+	s.scopeMu.RLock()
+	defer s.scopeMu.RUnlock()
+	return s.scope
+}
+
+// setProjectScope saves scope to the project's scope file and applies it, or removes
+// the file when scope is nil.
+func (s *Server) setProjectScope(scope *config.Scope) error {
+	s.scopeMu.Lock()
+	defer s.scopeMu.Unlock()
+
+	path := config.ProjectScopePath(s.projectDir)
+	if scope == nil {
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("remove scope: %w", err)
+		}
+	} else if err := scope.Save(path); err != nil {
+		return fmt.Errorf("save scope: %w", err)
+	}
+	s.scope = scope
+	return nil
+}
+
+// checkRequestScope returns an error when scope is defined and req targets a host
+// or path outside it.
+func checkRequestScope(scope *config.Scope, req SendRequestInput) error {
+	if scope == nil {
+		return nil
+	}
+	host, path := requestScopeTarget(req)
+	if !inScope(scope, host, path) {
+		return fmt.Errorf("out of scope: %s%s is outside the project scope (see scope_get)", req.Target.Hostname, pathWithoutQuery(path))
+	}
+	return nil
+}
+
+// requestScopeTarget returns the host (with port when set) and path scope patterns
+// are matched against for req.
+func requestScopeTarget(req SendRequestInput) (host, path string) {
+	host = req.Target.Hostname
+	if req.Target.Port != 0 {
+		host = net.JoinHostPort(host, strconv.Itoa(req.Target.Port))
+	}
+	path = extractRequestPath(req.RawRequest)
+	if u, err := url.Parse(path); err == nil && u.IsAbs() {
+		path = u.RequestURI() // absolute-form request target
+	}
+	return host, path
+}
+
+// inScope reports whether a request to host and path falls within scope. host may
+// include a port; patterns match with or without it.
+func inScope(scope *config.Scope, host, path string) bool {
+	matchesAny := func(patterns []string) bool {
+		for _, pattern := range patterns {
+			if matchesScopePattern(pattern, host, path) {
+				return true
+			}
+		}
+		return false
+	}
+	return (len(scope.Include) == 0 || matchesAny(scope.Include)) && !matchesAny(scope.Exclude)
+}
+
+// matchesScopePattern matches a "host" or "host/path" glob. Hosts match without
+// case or a trailing dot, and paths after percent-decoding and cleaning, so
+// variants the server treats alike cannot slip past an exclude.
+func matchesScopePattern(pattern, host, path string) bool {
+	hostPattern, pathPattern, hasPath := strings.Cut(pattern, "/")
+	hostPattern = strings.TrimSuffix(strings.ToLower(hostPattern), ".")
+	host = strings.ToLower(host)
+	hostname := host
+	if h, port, err := net.SplitHostPort(host); err == nil {
+		hostname = strings.TrimSuffix(h, ".")
+		host = net.JoinHostPort(hostname, port)
+	} else {
+		hostname = strings.TrimSuffix(host, ".")
+		host = hostname
+	}
+	if !matchesGlob(host, hostPattern) && !matchesGlob(hostname, hostPattern) {
+		return false
+	} else if !hasPath {
+		return true
+	}
+	return matchesGlob(normalizeScopePath(path), "/"+pathPattern)
+}
+
+// normalizeScopePath drops the query of a request path, percent-decodes it and
+// resolves "." and ".." segments and repeated slashes, keeping a trailing slash.
+func normalizeScopePath(p string) string {
+	p = pathWithoutQuery(p)
+	if decoded, err := url.PathUnescape(p); err == nil {
+		p = decoded
+	}
+	cleaned := path.Clean("/" + p)
+	if strings.HasSuffix(p, "/") && cleaned != "/" {
+		cleaned += "/"
+	}
+	return cleaned
+}
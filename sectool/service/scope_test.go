@@ -0,0 +1,190 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/go-harden/llm-security-toolbox/sectool/config"
+	"github.com/go-harden/llm-security-toolbox/sectool/protocol"
+)
+
+func TestInScope(t *testing.T) {
+	t.Parallel()
+
+	scope := &config.Scope{
+		Include: []string{"*.example.com", "api.other.com/v2/*"},
+		Exclude: []string{"admin.example.com"},
+	}
+	cases := []struct {
+		host, path string
+		want       bool
+	}{
+		{"app.example.com", "/", true},
+		{"app.example.com:8443", "/login", true},
+		{"admin.example.com", "/", false},
+		{"api.other.com", "/v2/users?id=1", true},
+		{"api.other.com", "/v1/users", false},
+		{"evil.com", "/", false},
+	}
+	for _, tc := range cases {
+		assert.Equal(t, tc.want, inScope(scope, tc.host, tc.path), "%s%s", tc.host, tc.path)
+	}
+
+	assert.True(t, inScope(&config.Scope{Exclude: []string{"admin.example.com"}}, "any.com", "/"))
+}
+
+func TestInScopeExcludeVariants(t *testing.T) {
+	t.Parallel()
+
+	scope := &config.Scope{
+		Include: []string{"*.example.com"},
+		Exclude: []string{"prod.example.com", "app.example.com/admin*"},
+	}
+	cases := []struct {
+		name, host, path string
+		want             bool
+	}{
+		{"upper_host", "PROD.example.com", "/", false},
+		{"trailing_dot", "prod.example.com.", "/", false},
+		{"trailing_dot_port", "Prod.Example.com.:443", "/", false},
+		{"dot_dot", "app.example.com", "/x/../admin", false},
+		{"double_slash", "app.example.com", "//admin", false},
+		{"encoded_dot_dot", "app.example.com", "/x/%2e%2e/admin", false},
+		{"encoded_slash", "app.example.com", "/x/..%2Fadmin/users", false},
+		{"dot_segment", "app.example.com", "/./admin?x=1", false},
+		{"upper_include", "APP.EXAMPLE.COM", "/home", true},
+		{"not_admin", "app.example.com", "/administrator/../home", true},
+		{"trailing_slash_kept", "app.example.com", "/home/", true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, inScope(scope, tc.host, tc.path))
+		})
+	}
+
+	// checkRequestScope applies the same matching to requests
+	req := SendRequestInput{
+		RawRequest: []byte("GET //admin/../admin HTTP/1.1\r\nHost: app.example.com\r\n\r\n"),
+		Target:     Target{Hostname: "APP.example.com", Port: 443, UsesHTTPS: true},
+	}
+	require.Error(t, checkRequestScope(scope, req))
+}
+
+func TestNormalizeScopePath(t *testing.T) {
+	t.Parallel()
+
+	for in, want := range map[string]string{
+		"":                "/",
+		"/":               "/",
+		"/a/b/":           "/a/b/",
+		"/a//b?c=/d/../e": "/a/b",
+		"/a/./b/../c":     "/a/c",
+		"/%61dmin":        "/admin",
+		"/../../etc":      "/etc",
+		"/bad%zzescape":   "/bad%zzescape",
+	} {
+		assert.Equal(t, want, normalizeScopePath(in), in)
+	}
+}
+
+func TestMCP_ProxyPollInScope(t *testing.T) {
+	t.Parallel()
+
+	srv, mcpClient, mockMCP, _, _ := setupMCPServerWithMock(t)
+	mockMCP.AddProxyEntry("GET /a HTTP/1.1\r\nHost: app.example.com\r\n\r\n", "HTTP/1.1 200 OK\r\n\r\n", "")
+	mockMCP.AddProxyEntry("GET /b HTTP/1.1\r\nHost: tracker.com\r\n\r\n", "HTTP/1.1 200 OK\r\n\r\n", "")
+
+	result := CallMCPTool(t, mcpClient, "proxy_poll", map[string]interface{}{"in_scope": true})
+	require.True(t, result.IsError)
+	assert.Contains(t, ExtractMCPText(t, result), "no project scope")
+
+	srv.scope = &config.Scope{Include: []string{"*.example.com"}}
+	resp := CallMCPToolJSONOK[protocol.ProxyPollResponse](t, mcpClient, "proxy_poll", map[string]interface{}{
+		"output_mode": "flows",
+		"in_scope":    true,
+	})
+	require.Len(t, resp.Flows, 1)
+	assert.Equal(t, "app.example.com", resp.Flows[0].Host)
+}
+
+func TestMCP_ScopeSetAndEnforce(t *testing.T) {
+	t.Parallel()
+
+	srv, mcpClient, mockMCP, _, _ := setupMCPServerWithMock(t)
+	mockMCP.SetSendResponse(
+		"HttpRequestResponse{httpRequest=GET / HTTP/1.1, httpResponse=HTTP/1.1 200 OK\r\n\r\nok}",
+	)
+
+	empty := CallMCPToolJSONOK[protocol.ScopeResponse](t, mcpClient, "scope_get", map[string]interface{}{})
+	assert.False(t, empty.Defined)
+
+	set := CallMCPToolJSONOK[protocol.ScopeResponse](t, mcpClient, "scope_set", map[string]interface{}{
+		"targets": []string{"https://app.example.com"},
+		"exclude": []string{"app.example.com/admin*"},
+	})
+	assert.True(t, set.Defined)
+	assert.Equal(t, []string{"app.example.com"}, set.Include)
+
+	saved, err := config.LoadScope(config.ProjectScopePath(srv.projectDir))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"app.example.com/admin*"}, saved.Exclude)
+
+	t.Run("in_scope_send", func(t *testing.T) {
+		CallMCPToolJSONOK[protocol.ReplaySendResponse](t, mcpClient, "request_send", map[string]interface{}{
+			"url": "https://app.example.com/home",
+		})
+	})
+
+	t.Run("out_of_scope_host", func(t *testing.T) {
+		result := CallMCPTool(t, mcpClient, "request_send", map[string]interface{}{
+			"url": "https://evil.com/",
+		})
+		require.True(t, result.IsError)
+		assert.Contains(t, ExtractMCPText(t, result), "out of scope")
+	})
+
+	t.Run("excluded_path", func(t *testing.T) {
+		result := CallMCPTool(t, mcpClient, "request_craft", map[string]interface{}{
+			"raw": "GET /admin/users HTTP/1.1\r\nHost: app.example.com\r\n\r\n",
+		})
+		require.True(t, result.IsError)
+		assert.Contains(t, ExtractMCPText(t, result), "out of scope")
+	})
+
+	t.Run("invalid_pattern", func(t *testing.T) {
+		result := CallMCPTool(t, mcpClient, "scope_set", map[string]interface{}{
+			"include": []string{"https://x.com"},
+		})
+		assert.True(t, result.IsError)
+	})
+
+	cleared := CallMCPToolJSONOK[protocol.ScopeResponse](t, mcpClient, "scope_set", map[string]interface{}{"clear": true})
+	assert.False(t, cleared.Defined)
+	CallMCPToolJSONOK[protocol.ReplaySendResponse](t, mcpClient, "request_send", map[string]interface{}{
+		"url": "https://evil.com/",
+	})
+}
+
+func TestMCP_ProxyPollScopeDefault(t *testing.T) {
+	t.Parallel()
+
+	srv, mcpClient, mockMCP, _, _ := setupMCPServerWithMock(t)
+	mockMCP.AddProxyEntry("GET /a HTTP/1.1\r\nHost: app.example.com\r\n\r\n", "HTTP/1.1 200 OK\r\n\r\n", "")
+	mockMCP.AddProxyEntry("GET /b HTTP/1.1\r\nHost: tracker.com\r\n\r\n", "HTTP/1.1 200 OK\r\n\r\n", "")
+	require.NoError(t, srv.setProjectScope(&config.Scope{Include: []string{"*.example.com"}}))
+
+	resp := CallMCPToolJSONOK[protocol.ProxyPollResponse](t, mcpClient, "proxy_poll", map[string]interface{}{
+		"output_mode": "flows",
+		"limit":       10,
+	})
+	assert.Len(t, resp.Flows, 1)
+
+	resp = CallMCPToolJSONOK[protocol.ProxyPollResponse](t, mcpClient, "proxy_poll", map[string]interface{}{
+		"output_mode": "flows",
+		"limit":       10,
+		"in_scope":    false,
+	})
+	assert.Len(t, resp.Flows, 2)
+}
@@ -0,0 +1,284 @@
+package service
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/go-harden/llm-security-toolbox/sectool/bundle"
+	"github.com/go-harden/llm-security-toolbox/sectool/protocol"
+)
+
+// Places in a flow that secret detectors scan. Request headers and bodies are not
+// scanned: they hold the tester's own credentials.
+const (
+	secretInRequestURL     = "request_url"
+	secretInResponseHeader = "response_header"
+	secretInResponseBody   = "response_body"
+)
+
+// secretDetector flags one kind of secret or PII. When re has a capture group, group 1
+// is the value; valid, when set, filters out matches that only look right.
+type secretDetector struct {
+	Name     string
+	Label    string // finding title prefix
+	Severity string
+	re       *regexp.Regexp
+	valid    func(string) bool
+}
+
+var secretDetectors = []secretDetector{
+	{
+		Name: "private_key", Label: "Private key", Severity: protocol.SeverityCritical,
+		re: regexp.MustCompile(`-----BEGIN (?:RSA |EC |DSA |OPENSSH |ENCRYPTED |PGP )?PRIVATE KEY(?: BLOCK)?-----`),
+	},
+	{
+		Name: "aws_access_key_id", Label: "AWS access key ID", Severity: protocol.SeverityHigh,
+		re: regexp.MustCompile(`\b((?:AKIA|ASIA)[0-9A-Z]{16})\b`),
+	},
+	{
+		Name: "aws_secret_access_key", Label: "AWS secret access key", Severity: protocol.SeverityCritical,
+		re: regexp.MustCompile(`(?i)aws_?secret_?access_?key["']?\s*[:=]\s*["']?([A-Za-z0-9/+]{40})\b`),
+	},
+	{
+		Name: "google_api_key", Label: "Google API key", Severity: protocol.SeverityMedium,
+		re: regexp.MustCompile(`\b(AIza[0-9A-Za-z_-]{35})`),
+	},
+	{
+		Name: "github_token", Label: "GitHub token", Severity: protocol.SeverityHigh,
+		re: regexp.MustCompile(`\b((?:ghp|gho|ghu|ghs|ghr)_[A-Za-z0-9]{36}|github_pat_[A-Za-z0-9_]{82})\b`),
+	},
+	{
+		Name: "slack_token", Label: "Slack token", Severity: protocol.SeverityHigh,
+		re: regexp.MustCompile(`\b(xox[aboprs]-[A-Za-z0-9-]{10,})`),
+	},
+	{
+		Name: "stripe_secret_key", Label: "Stripe secret key", Severity: protocol.SeverityHigh,
+		re: regexp.MustCompile(`\b((?:sk|rk)_live_[A-Za-z0-9]{24,})\b`),
+	},
+	{
+		Name: "generic_api_key", Label: "API key or secret", Severity: protocol.SeverityMedium,
+		re: regexp.MustCompile(`(?i)["']?(?:api_?key|apikey|client_?secret|secret_?key|access_?token|private_?token)["']?\s*[:=]\s*["']([A-Za-z0-9_\-./+=]{16,})["']`),
+	},
+	{
+		Name: "jwt", Label: "JSON Web Token", Severity: protocol.SeverityLow,
+		re: regexp.MustCompile(`\b(eyJ[A-Za-z0-9_-]{8,}\.eyJ[A-Za-z0-9_-]{8,}\.[A-Za-z0-9_-]*)`),
+	},
+	{
+		Name: "credit_card", Label: "Payment card number", Severity: protocol.SeverityMedium,
+		re:    regexp.MustCompile(`\b(\d(?:[ -]?\d){12,18})\b`),
+		valid: validCardNumber,
+	},
+	{
+		Name: "email", Label: "Email address", Severity: protocol.SeverityInfo,
+		re:    regexp.MustCompile(`\b([A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,})\b`),
+		valid: validEmail,
+	},
+}
+
+// secretMatch is one detector hit in a piece of text.
+type secretMatch struct {
+	Detector *secretDetector
+	Value    string
+}
+
+// detectSecrets runs every detector over text, returning each distinct value once.
+func detectSecrets(text string) []secretMatch {
+	var matches []secretMatch
+	seen := make(map[string]bool)
+	for i := range secretDetectors {
+		d := &secretDetectors[i]
+		for _, m := range d.re.FindAllStringSubmatch(text, -1) {
+			value := m[0]
+			if len(m) > 1 {
+				value = m[1]
+			}
+			if seen[d.Name+"\x00"+value] || (d.valid != nil && !d.valid(value)) {
+				continue
+			}
+			seen[d.Name+"\x00"+value] = true
+			matches = append(matches, secretMatch{Detector: d, Value: value})
+		}
+	}
+	return matches
+}
+
+// validCardNumber accepts 13-19 digit numbers with a card network prefix that pass
+// the Luhn check.
+func validCardNumber(s string) bool {
+	digits := strings.NewReplacer(" ", "", "-", "").Replace(s)
+	if len(digits) < 13 || len(digits) > 19 {
+		return false
+	}
+	switch {
+	case digits[0] == '4',
+		digits[:2] >= "51" && digits[:2] <= "55",
+		digits[:4] >= "2221" && digits[:4] <= "2720",
+		digits[:2] == "34", digits[:2] == "37",
+		digits[:4] == "6011", digits[:2] == "65", digits[:2] == "35":
+	default:
+		return false
+	}
+	var sum int
+	for i := range len(digits) {
+		n := int(digits[len(digits)-1-i] - '0')
+		if i%2 == 1 {
+			if n *= 2; n > 9 {
+				n -= 9
+			}
+		}
+		sum += n
+	}
+	return sum%10 == 0
+}
+
+// validEmail drops asset names such as logo@2x.png that look like addresses.
+func validEmail(s string) bool {
+	tld := strings.ToLower(s[strings.LastIndexByte(s, '.')+1:])
+	switch tld {
+	case "png", "jpg", "jpeg", "gif", "svg", "webp", "css", "js":
+		return false
+	}
+	return true
+}
+
+// redactSecret keeps enough of a value to recognize it.
+func redactSecret(s string) string {
+	if len(s) <= 12 {
+		return s[:min(2, len(s))] + "****"
+	}
+	return s[:4] + "****" + s[len(s)-4:]
+}
+
+// secretDetection is a distinct detector value seen in proxy history.
+type secretDetection struct {
+	Detector  *secretDetector
+	Value     string
+	Location  string
+	Entry     flowEntry // first flow containing the value
+	FlowID    string
+	Count     int    // flows containing the value
+	FindingID string // draft finding, once recorded
+
+	lastOffset uint32
+}
+
+// secretScanner passively scans proxy history for secrets and PII, keeping each distinct
+// detector value once. Scanning is incremental by history offset. Thread-safe.
+type secretScanner struct {
+	mu         sync.Mutex
+	nextOffset uint32 // first offset not yet scanned
+	detections []*secretDetection
+	byKey      map[string]*secretDetection
+}
+
+func newSecretScanner() *secretScanner {
+	return &secretScanner{byKey: make(map[string]*secretDetection)}
+}
+
+// scan analyzes entries not seen before and returns how many it scanned. register
+// assigns flow IDs to entries with detections. A history shorter than what was already
+// scanned means it was cleared, so detections start over.
+func (sc *secretScanner) scan(entries []flowEntry, register func(flowEntry) string) int {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+
+	if uint32(len(entries)) < sc.nextOffset {
+		sc.nextOffset = 0
+		sc.detections = nil
+		clear(sc.byKey)
+	}
+	var scanned int
+	for _, entry := range entries {
+		if entry.offset < sc.nextOffset {
+			continue
+		}
+		scanned++
+		var flowID string
+		for _, part := range secretScanParts(entry) {
+			for _, m := range detectSecrets(part.text) {
+				key := m.Detector.Name + "\x00" + m.Value
+				d, ok := sc.byKey[key]
+				if !ok {
+					if flowID == "" {
+						flowID = register(entry)
+					}
+					d = &secretDetection{Detector: m.Detector, Value: m.Value, Location: part.location, Entry: entry, FlowID: flowID}
+					sc.byKey[key] = d
+					sc.detections = append(sc.detections, d)
+				} else if d.Count > 0 && d.lastOffset == entry.offset {
+					continue // already counted for this flow
+				}
+				d.Count++
+				d.lastOffset = entry.offset
+			}
+		}
+	}
+	if n := uint32(len(entries)); n > sc.nextOffset {
+		sc.nextOffset = n
+	}
+	return scanned
+}
+
+// list returns a copy of the detections in first-seen order.
+func (sc *secretScanner) list() []secretDetection {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+
+	out := make([]secretDetection, len(sc.detections))
+	for i, d := range sc.detections {
+		out[i] = *d
+	}
+	return out
+}
+
+// record adds a draft finding for each detection above info severity that has none,
+// returning how many were added.
+func (sc *secretScanner) record(findings *findingsStore) (int, error) {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+
+	var added int
+	for _, d := range sc.detections {
+		if d.FindingID != "" || d.Detector.Severity == protocol.SeverityInfo {
+			continue
+		}
+		evidence := newFindingEvidence(d.FlowID, "flow", []byte(d.Entry.request), []byte(d.Entry.response), "")
+		finding, err := findings.Add(protocol.Finding{
+			Title:    fmt.Sprintf("%s exposed in %s", d.Detector.Label, strings.ReplaceAll(d.Location, "_", " ")),
+			Severity: d.Detector.Severity,
+			Status:   protocol.FindingStatusDraft,
+			Description: fmt.Sprintf("Passive analysis detected %s `%s` in the %s of %s %s (seen in %d flows). Verify that it is live and not a placeholder.",
+				d.Detector.Name, redactSecret(d.Value), strings.ReplaceAll(d.Location, "_", " "), d.Entry.method, evidence.URL, d.Count),
+			FlowIDs:  []string{d.FlowID},
+			Evidence: []protocol.FindingEvidence{evidence},
+		})
+		if err != nil {
+			return added, err
+		}
+		d.FindingID = finding.FindingID
+		added++
+	}
+	return added, nil
+}
+
+type secretScanPart struct {
+	location string
+	text     string
+}
+
+// secretScanParts splits a flow into the texts scanned for secrets, with the response
+// body decoded.
+func secretScanParts(entry flowEntry) []secretScanPart {
+	parts := []secretScanPart{{location: secretInRequestURL, text: entry.path}}
+	headers, body := splitHeadersBody([]byte(entry.response))
+	if len(headers) == 0 {
+		return parts
+	}
+	decoded, _ := bundle.DecodeBody(string(headers), body)
+	return append(parts,
+		secretScanPart{location: secretInResponseHeader, text: string(headers)},
+		secretScanPart{location: secretInResponseBody, text: string(decoded)},
+	)
+}
@@ -0,0 +1,230 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+)
+
+const maxSequenceSteps = 50
+
+// sequenceStepTools are the tools a sequence step may call; each stores its
+// response as a replay.
+var sequenceStepTools = []string{"replay_send", "request_send", "request_craft"}
+
+// sequenceVarRe matches the names templateVarRe accepts as placeholders.
+var sequenceVarRe = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_.-]*$`)
+
+// sequenceStep is one request of a sequence: a send tool call whose string args
+// may hold {{var}} placeholders, the status its response must have, and the
+// values extracted from the response for later steps.
+type sequenceStep struct {
+	Name    string
+	Tool    string
+	Args    map[string]interface{}
+	Extract map[string]tokenExtractor // variable name to extraction rule
+	Expect  string                    // status codes or ranges, empty for any
+
+	expectFilter *StatusCodeFilter
+}
+
+// Label names the step in errors: its name, or its 1-based position.
+func (st *sequenceStep) Label(i int) string {
+	if st.Name != "" {
+		return fmt.Sprintf("step %d (%s)", i+1, st.Name)
+	}
+	return fmt.Sprintf("step %d", i+1)
+}
+
+// sequence is an ordered list of steps run by sequence_run, with values
+// extracted from each response available to the steps after it.
+type sequence struct {
+	Name  string
+	Steps []sequenceStep
+}
+
+// Inputs returns the placeholders no earlier step extracts, in order of use;
+// sequence_run vars or the step's environment must set them.
+func (q *sequence) Inputs() []string {
+	var inputs []string
+	extracted := make(map[string]bool)
+	for _, st := range q.Steps {
+		for _, name := range placeholderNames(st.Args) {
+			if !extracted[name] && !slices.Contains(inputs, name) {
+				inputs = append(inputs, name)
+			}
+		}
+		for name := range st.Extract {
+			extracted[name] = true
+		}
+	}
+	return inputs
+}
+
+// newSequenceStep validates a step definition. extract maps variable names to
+// "kind:expr" rules, with kinds json, header, cookie, regex and field.
+func newSequenceStep(name, tool string, args map[string]interface{}, extract map[string]string, expect string) (sequenceStep, error) {
+	st := sequenceStep{Name: name, Tool: tool, Args: args, Expect: expect}
+	if st.Tool == "" {
+		st.Tool = "replay_send"
+	} else if !slices.Contains(sequenceStepTools, st.Tool) {
+		return st, fmt.Errorf("tool must be one of %s, got %q", strings.Join(sequenceStepTools, ", "), tool)
+	}
+	if st.Args == nil {
+		st.Args = make(map[string]interface{})
+	}
+
+	has := func(key string) bool {
+		v, _ := st.Args[key].(string)
+		return v != ""
+	}
+	switch st.Tool {
+	case "replay_send":
+		if has("flow_id") == has("replay_id") {
+			return st, errors.New("replay_send args need exactly one of flow_id or replay_id")
+		}
+	case "request_send":
+		if !has("url") {
+			return st, errors.New("request_send args need url")
+		}
+	case "request_craft":
+		if has("raw") == has("url") {
+			return st, errors.New("request_craft args need exactly one of raw or url")
+		}
+	}
+
+	if len(extract) > 0 {
+		st.Extract = make(map[string]tokenExtractor, len(extract))
+	}
+	for varName, rule := range extract {
+		if !sequenceVarRe.MatchString(varName) {
+			return st, fmt.Errorf("invalid variable name %q: use letters, digits, _, . and -", varName)
+		}
+		kind, expr, ok := strings.Cut(rule, ":")
+		if !ok || expr == "" {
+			return st, fmt.Errorf("extract %s: expected kind:expr (e.g., 'json:data.token'), got %q", varName, rule)
+		}
+		e, err := newTokenExtractor(kind, expr)
+		if err != nil {
+			return st, fmt.Errorf("extract %s: %w", varName, err)
+		}
+		st.Extract[varName] = e
+	}
+
+	if expect != "" {
+		if st.expectFilter = parseStatusFilter(expect); st.expectFilter.Empty() {
+			return st, fmt.Errorf("invalid expect %q: expected status codes or ranges such as '200,302' or '2xx'", expect)
+		}
+	}
+	return st, nil
+}
+
+// placeholderNames returns the {{name}} placeholders in the strings of v, in order of first use.
+func placeholderNames(v interface{}) []string {
+	var names []string
+	var walk func(v interface{})
+	walk = func(v interface{}) {
+		switch val := v.(type) {
+		case string:
+			for _, m := range templateVarRe.FindAllStringSubmatch(val, -1) {
+				if !slices.Contains(names, m[1]) {
+					names = append(names, m[1])
+				}
+			}
+		case []interface{}:
+			for _, item := range val {
+				walk(item)
+			}
+		case map[string]interface{}:
+			for _, k := range slices.Sorted(maps.Keys(val)) {
+				walk(val[k])
+			}
+		}
+	}
+	walk(v)
+	return names
+}
+
+// expandSequenceValue returns a copy of v with placeholders in its strings
+// replaced from vars. Placeholders vars does not define are kept.
+func expandSequenceValue(v interface{}, vars map[string]string) interface{} {
+	switch val := v.(type) {
+	case string:
+		return templateVarRe.ReplaceAllStringFunc(val, func(match string) string {
+			if value, ok := vars[templateVarRe.FindStringSubmatch(match)[1]]; ok {
+				return value
+			}
+			return match
+		})
+	case []interface{}:
+		out := make([]interface{}, len(val))
+		for i, item := range val {
+			out[i] = expandSequenceValue(item, vars)
+		}
+		return out
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(val))
+		for k, item := range val {
+			out[k] = expandSequenceValue(item, vars)
+		}
+		return out
+	default:
+		return v
+	}
+}
+
+// sequenceStore holds sequences for the service lifetime. Thread-safe.
+type sequenceStore struct {
+	mu        sync.Mutex
+	sequences map[string]*sequence
+}
+
+func newSequenceStore() *sequenceStore {
+	return &sequenceStore{sequences: make(map[string]*sequence)}
+}
+
+// Get returns the named sequence.
+func (s *sequenceStore) Get(name string) (*sequence, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	q, ok := s.sequences[name]
+	if !ok {
+		if len(s.sequences) == 0 {
+			return nil, fmt.Errorf("unknown sequence %q: create one with sequence_set", name)
+		}
+		return nil, fmt.Errorf("unknown sequence %q (available: %s)", name, strings.Join(slices.Sorted(maps.Keys(s.sequences)), ", "))
+	}
+	return q, nil
+}
+
+// Set adds or replaces a sequence.
+func (s *sequenceStore) Set(q *sequence) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sequences[q.Name] = q
+}
+
+// Delete removes the named sequence, reporting whether it existed.
+func (s *sequenceStore) Delete(name string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, ok := s.sequences[name]
+	delete(s.sequences, name)
+	return ok
+}
+
+// List returns all sequences sorted by name.
+func (s *sequenceStore) List() []*sequence {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	names := slices.Sorted(maps.Keys(s.sequences))
+	out := make([]*sequence, len(names))
+	for i, name := range names {
+		out[i] = s.sequences[name]
+	}
+	return out
+}
@@ -0,0 +1,43 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSequenceInputs(t *testing.T) {
+	t.Parallel()
+
+	login, err := newSequenceStep("login", "", map[string]interface{}{
+		"flow_id":  "f1",
+		"set_form": map[string]interface{}{"user": "{{user}}", "pass": "{{pass}}"},
+	}, map[string]string{"sid": "cookie:SESSIONID"}, "")
+	require.NoError(t, err)
+	order, err := newSequenceStep("", "request_craft", map[string]interface{}{
+		"raw": "POST /orders HTTP/1.1\r\nHost: {{host}}\r\nCookie: SESSIONID={{sid}}\r\n\r\nqty={{qty}}",
+	}, nil, "2xx")
+	require.NoError(t, err)
+
+	q := &sequence{Name: "checkout", Steps: []sequenceStep{login, order}}
+	assert.Equal(t, []string{"pass", "user", "host", "qty"}, q.Inputs())
+}
+
+func TestExpandSequenceValue(t *testing.T) {
+	t.Parallel()
+
+	args := map[string]interface{}{
+		"path":        "/users/{{id}}",
+		"add_headers": []interface{}{"X-Token: {{token}}"},
+		"set_json":    map[string]interface{}{"n": 1.0, "note": "{{7*7}} {{missing}}"},
+	}
+	got := expandSequenceValue(args, map[string]string{"id": "42", "token": "abc"})
+	assert.Equal(t, map[string]interface{}{
+		"path":        "/users/42",
+		"add_headers": []interface{}{"X-Token: abc"},
+		"set_json":    map[string]interface{}{"n": 1.0, "note": "{{7*7}} {{missing}}"},
+	}, got)
+	assert.Equal(t, "/users/{{id}}", args["path"], "args are not modified")
+	assert.Equal(t, []string{"missing"}, placeholderNames(got))
+}
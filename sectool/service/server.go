@@ -2,32 +2,46 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
+	"net"
 	"os"
 	"os/signal"
 	"path/filepath"
 	"strconv"
+	"strings"
 	"sync"
-	"sync/atomic"
 	"syscall"
 	"time"
 
 	"github.com/go-harden/llm-security-toolbox/sectool/config"
+	"github.com/go-harden/llm-security-toolbox/sectool/grpc"
 	"github.com/go-harden/llm-security-toolbox/sectool/service/store"
+	"github.com/go-harden/llm-security-toolbox/sectool/update"
 )
 
-const shutdownTimeout = 10 * time.Second
+const (
+	shutdownTimeout    = 10 * time.Second
+	updateCheckTimeout = 10 * time.Second
+)
 
 // Server is the sectool MCP server.
 type Server struct {
 	cfg             *config.Config
 	configPath      string // resolved config file path (respects --config flag)
+	projectDir      string // directory holding the project's .sectool/ (respects --project-dir)
+	scopeMu         sync.RWMutex
+	scope           *config.Scope // project scope from .sectool/scope.json, nil if undefined; guarded by scopeMu
 	flagBurpMCPURL  string
 	flagConfigPath  string
-	flagMCPPort     int  // CLI override, 0 means use config
-	flagProxyPort   int  // CLI override for built-in proxy, 0 means use config
-	flagRequireBurp bool // --burp flag: require Burp MCP
+	flagProjectDir  string
+	flagProfile     string
+	flagMCPPort     int    // CLI override, 0 means use config
+	flagProxyPort   int    // CLI override for built-in proxy, 0 means use config
+	flagProxyListen string // CLI built-in proxy listen address, "" means 127.0.0.1 and proxyPort
+	flagBuiltin     bool   // use the built-in proxy without trying Burp
+	flagRequireBurp bool   // --burp flag: require Burp MCP
 
 	// MCP server settings
 	mcpPort           int
@@ -52,13 +66,65 @@ type Server struct {
 	// Flow ID mapping (ephemeral)
 	flowStore      *store.FlowStore
 	crawlFlowStore *store.CrawlFlowStore
+	wsStore        *store.FlowStore // message_id -> WebSocket history offset
 
 	// Request/response results store (ephemeral)
 	requestStore *store.RequestStore
 
-	// proxyLastOffset tracks the highest offset seen across all proxy list queries.
-	// Enables "since=last" to show only new traffic since the last query.
-	proxyLastOffset atomic.Uint32
+	// Paths found by dirbust and open ports found by port_scan, listed by crawl_results (ephemeral)
+	dirbusts  *dirbustStore
+	portScans *portScanStore
+
+	// Request templates from spec_import, usable as replay_send flow_id (ephemeral)
+	specStore *store.RequestStore
+
+	// GraphQL schemas by endpoint from graphql_introspect (ephemeral)
+	graphql *graphQLCache
+
+	// Protobuf files loaded by grpc_schema (ephemeral)
+	grpcSchema *grpc.Schema
+
+	// Secrets and PII detected passively in proxy history by proxy_findings (ephemeral)
+	secrets *secretScanner
+
+	// Full text of tool results shaped by max_output_bytes (ephemeral)
+	outputStore *store.OutputStore
+
+	// Per-client state such as "since=last" markers, so concurrent clients don't interfere
+	clients *clientRegistry
+
+	// Project methodology checklist, notes and findings (persisted in .sectool/)
+	checklist  *checklistStore
+	notes      *notesStore
+	findings   *findingsStore
+	envs       *envStore
+	identities *identityStore
+
+	// Digest auth challenges by user and origin, reused across sends (ephemeral)
+	digest *digestSessions
+
+	// Login recipes whose tokens are injected into replays and refreshed on 401/403 (ephemeral)
+	sessions *sessionStore
+
+	// Token page recipes that replays use to refresh anti-CSRF tokens, by host (ephemeral)
+	csrfRules *csrfStore
+
+	// Multi-step request flows with values extracted between steps (ephemeral)
+	sequences *sequenceStore
+
+	// Politeness limits on outbound requests, from cfg.RateLimit
+	rateLimiter *rateLimiter
+
+	// Addresses dialed in place of hostnames, from cfg.Hosts
+	hosts hostOverrides
+
+	// User signed-off targets (cfg.TargetAllowlist) and the CLI's out of scope confirmation
+	guard *targetGuard
+
+	// Background matching of OAST interactions to expectations, and the replays that sent OAST hostnames
+	oastWatch  *oastWatcher
+	oastUses   *oastCorrelator
+	oastNotify *oastNotifier // nil unless oast.notify_url or oast.notify_file is set
 
 	// Shutdown coordination
 	shutdownCh chan struct{}
@@ -71,16 +137,35 @@ func NewServer(flags MCPServerFlags, hb HttpBackend, ob OastBackend, cb CrawlerB
 	s := &Server{
 		flagBurpMCPURL:  flags.BurpMCPURL,
 		flagConfigPath:  flags.ConfigPath,
+		flagProjectDir:  flags.ProjectDir,
+		flagProfile:     flags.Profile,
 		flagMCPPort:     flags.MCPPort,
 		flagProxyPort:   flags.ProxyPort,
+		flagProxyListen: flags.ProxyListen,
+		flagBuiltin:     flags.BuiltinProxy,
 		flagRequireBurp: flags.RequireBurp,
 		mcpWorkflowMode: flags.WorkflowMode,
 		metricProvider:  make(map[string]HealthMetricProvider),
 		started:         make(chan struct{}),
 		shutdownCh:      make(chan struct{}),
 		flowStore:       store.NewFlowStore(),
+		wsStore:         store.NewFlowStore(),
 		crawlFlowStore:  store.NewCrawlFlowStore(),
 		requestStore:    store.NewRequestStore(),
+		oastUses:        newOastCorrelator(),
+		specStore:       store.NewRequestStore(),
+		dirbusts:        &dirbustStore{},
+		portScans:       &portScanStore{},
+		graphql:         newGraphQLCache(),
+		grpcSchema:      grpc.NewSchema(),
+		secrets:         newSecretScanner(),
+		outputStore:     store.NewOutputStore(maxRetainedOutputs),
+		clients:         newClientRegistry(),
+		digest:          newDigestSessions(),
+		sessions:        newSessionStore(),
+		csrfRules:       newCSRFStore(),
+		rateLimiter:     newRateLimiter(rateLimits{}),
+		sequences:       newSequenceStore(),
 		httpBackend:     hb,
 		oastBackend:     ob,
 		crawlerBackend:  cb,
@@ -90,6 +175,7 @@ func NewServer(flags MCPServerFlags, hb HttpBackend, ob OastBackend, cb CrawlerB
 	s.RegisterHealthMetric("flows", func() string { return strconv.Itoa(s.flowStore.Count()) })
 	s.RegisterHealthMetric("crawl_flows", func() string { return strconv.Itoa(s.crawlFlowStore.Count()) })
 	s.RegisterHealthMetric("requests", func() string { return strconv.Itoa(s.requestStore.Count()) })
+	s.RegisterHealthMetric("clients", func() string { return strconv.Itoa(s.clients.Count()) })
 
 	return s, nil
 }
@@ -113,6 +199,11 @@ func (s *Server) Run(ctx context.Context) error {
 	if err := s.loadOrCreateConfig(); err != nil {
 		return fmt.Errorf("failed to load config: %w", err)
 	}
+	s.checklist = newChecklistStore(s.projectDir)
+	s.notes = newNotesStore(s.projectDir)
+	s.findings = newFindingsStore(s.projectDir)
+	s.envs = newEnvStore(s.projectDir)
+	s.identities = newIdentityStore(s.projectDir)
 
 	// Setup signal handling
 	sigCh := make(chan os.Signal, 1)
@@ -127,12 +218,19 @@ func (s *Server) Run(ctx context.Context) error {
 
 	// Setup OAST backend
 	if s.oastBackend == nil {
-		s.oastBackend = NewInteractshBackend()
+		s.oastBackend = NewInteractshBackend(s.cfg.OAST)
+	}
+	s.oastWatch = newOastWatcher(s.oastBackend, s.findings, s.oastUses)
+	if notifyURL, notifyFile := s.cfg.OAST.NotifyURL, s.cfg.OAST.NotifyFile; notifyURL != "" || notifyFile != "" {
+		if notifyFile != "" && !filepath.IsAbs(notifyFile) {
+			notifyFile = filepath.Join(s.projectDir, notifyFile)
+		}
+		s.oastNotify = newOastNotifier(s.oastBackend, s.oastUses, notifyURL, notifyFile)
 	}
 
 	// Setup Crawler backend
 	if s.crawlerBackend == nil {
-		s.crawlerBackend = NewCollyBackend(s.cfg.Crawler, s.crawlFlowStore, s.flowStore, s.httpBackend)
+		s.crawlerBackend = NewCollyBackend(s.cfg.Crawler, s.crawlFlowStore, s.flowStore, s.httpBackend, s.rateLimiter, s.guard, s.hosts)
 	}
 
 	// Start MCP server
@@ -145,6 +243,11 @@ func (s *Server) Run(ctx context.Context) error {
 	log.Printf("MCP server listening on http://%s/mcp", s.mcpServer.Addr())
 	s.printMCPConfig()
 
+	// Release builds only; dev builds and tests skip the network call
+	if config.RevNum != "dev" && s.updateCheckEnabled() {
+		go s.checkForUpdate(ctx)
+	}
+
 	select {
 	case <-ctx.Done():
 		log.Printf("context cancelled, initiating shutdown")
@@ -171,6 +274,14 @@ func (s *Server) shutdown() error {
 		}
 	}
 
+	// Stop OAST watches before their backend closes
+	if s.oastWatch != nil {
+		s.oastWatch.Close()
+	}
+	if s.oastNotify != nil {
+		s.oastNotify.Close()
+	}
+
 	// Wait for any ongoing operations
 	s.wg.Wait()
 
@@ -202,6 +313,29 @@ func (s *Server) RegisterHealthMetric(key string, provider HealthMetricProvider)
 	s.metricProvider[key] = provider
 }
 
+// updateCheckEnabled reports whether config update_check (default true) allows the
+// startup release check.
+func (s *Server) updateCheckEnabled() bool {
+	return s.cfg == nil || s.cfg.UpdateCheck == nil || *s.cfg.UpdateCheck
+}
+
+// checkForUpdate logs a note and exposes an "update_available" health metric when a newer release exists.
+func (s *Server) checkForUpdate(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(ctx, updateCheckTimeout)
+	defer cancel()
+
+	rel, err := update.Latest(ctx)
+	if err != nil {
+		log.Printf("update check skipped: %v", err)
+		return
+	} else if !update.IsNewer(rel.Version(), config.Version) {
+		return
+	}
+
+	log.Printf("new version available: sectool %s (current %s); run `sectool update` to install", rel.TagName, config.Version)
+	s.RegisterHealthMetric("update_available", func() string { return rel.TagName })
+}
+
 // RequestShutdown initiates server shutdown.
 func (s *Server) RequestShutdown() {
 	select {
@@ -224,6 +358,11 @@ func (s *Server) loadOrCreateConfig() error {
 	cfg, err := config.LoadOrCreatePath(s.configPath)
 	if err != nil {
 		return err
+	} else if err := cfg.ApplyProfile(s.flagProfile); err != nil {
+		return err
+	}
+	if s.flagProfile != "" {
+		log.Printf("using config profile %q", s.flagProfile)
 	}
 
 	// Apply CLI flag overrides (non-zero values override config)
@@ -241,19 +380,55 @@ func (s *Server) loadOrCreateConfig() error {
 	}
 
 	s.cfg = cfg
+	s.rateLimiter = newRateLimiter(rateLimitsFromConfig(cfg.RateLimit))
+	if s.hosts = newHostOverrides(cfg.Hosts); len(s.hosts) > 0 {
+		log.Printf("host overrides: %v", cfg.Hosts)
+	}
+	if s.guard, err = newTargetGuard(cfg.TargetAllowlist, config.ConfirmKeyPath(s.configPath)); err != nil {
+		return err
+	} else if len(cfg.TargetAllowlist) > 0 {
+		log.Printf("target allowlist: %s", strings.Join(cfg.TargetAllowlist, ", "))
+	}
+	return s.loadScope()
+}
+
+// loadScope resolves the project directory and reads its scope, if defined.
+func (s *Server) loadScope() error {
+	s.projectDir = s.flagProjectDir
+	if s.projectDir == "" {
+		wd, err := os.Getwd()
+		if err != nil {
+			return err
+		}
+		s.projectDir = wd
+	}
+	if s.cfg != nil && s.cfg.Scope != nil {
+		log.Printf("using config scope: include=%v exclude=%v", s.cfg.Scope.Include, s.cfg.Scope.Exclude)
+		s.scope = s.cfg.Scope
+		return nil
+	}
+	scope, err := config.LoadScope(config.ProjectScopePath(s.projectDir))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("load project scope: %w", err)
+	}
+	log.Printf("using project scope: include=%v exclude=%v", scope.Include, scope.Exclude)
+	s.scope = scope
 	return nil
 }
 
 // setupHttpBackend sets up the HTTP backend based on flags and config.
 // Priority:
-// 1. If --proxy-port is specified, use built-in proxy (skip Burp)
+// 1. If --proxy-port or --proxy-listen is specified, or 'proxy start' runs the
+// service, use built-in proxy (skip Burp)
 // 2. If --burp flag is set, require Burp (error if unavailable)
 // 3. If config burp_required is true, require Burp
 // 4. Otherwise, try Burp first, fall back to built-in proxy
 func (s *Server) setupHttpBackend(ctx context.Context) error {
-	// Case 1: --proxy-port specified, use built-in proxy directly
-	if s.flagProxyPort != 0 {
-		log.Printf("--proxy-port specified, using built-in proxy")
+	// Case 1: built-in proxy requested, use it directly
+	if s.flagProxyPort != 0 || s.flagProxyListen != "" || s.flagBuiltin {
+		log.Printf("built-in proxy requested, skipping Burp")
 		return s.startBuiltinProxy()
 	}
 
@@ -284,11 +459,17 @@ func (s *Server) setupHttpBackend(ctx context.Context) error {
 // connectBurpMCP establishes the connection to Burp MCP.
 func (s *Server) connectBurpMCP(ctx context.Context) error {
 	burpURL := s.flagBurpMCPURL
+	if burpURL == "" {
+		burpURL = s.cfg.BurpMCPURL
+	}
 	if burpURL == "" {
 		burpURL = config.DefaultBurpMCPURL
 	}
 
 	burpBackend := NewBurpBackend(burpURL)
+	if s.cfg.BurpRESTURL != "" {
+		burpBackend.SetRESTURL(s.cfg.BurpRESTURL)
+	}
 	if err := burpBackend.Connect(ctx); err != nil {
 		return err
 	}
@@ -300,7 +481,11 @@ func (s *Server) connectBurpMCP(ctx context.Context) error {
 func (s *Server) startBuiltinProxy() error {
 	configDir := filepath.Dir(s.configPath)
 
-	backend, err := NewGoProxyBackend(s.proxyPort, configDir)
+	addr := s.flagProxyListen
+	if addr == "" {
+		addr = net.JoinHostPort("127.0.0.1", strconv.Itoa(s.proxyPort))
+	}
+	backend, err := NewGoProxyBackendAddr(addr, configDir)
 	if err != nil {
 		return fmt.Errorf("start built-in proxy: %w", err)
 	}
@@ -335,6 +520,8 @@ func (s *Server) printMCPConfig() {
 	_, _ = fmt.Fprintln(os.Stderr, "Codex (~/.codex/config.toml):")
 	_, _ = fmt.Fprintln(os.Stderr, "  [mcp_servers.sectool]")
 	_, _ = fmt.Fprintf(os.Stderr, "  url = \"%s\"\n", mcpURL)
+	_, _ = fmt.Fprintln(os.Stderr, "")
+	_, _ = fmt.Fprintln(os.Stderr, "Or register automatically: sectool config agent <claude|codex>")
 	_, _ = fmt.Fprintln(os.Stderr, "================================================================================")
 	_, _ = fmt.Fprintln(os.Stderr, "")
 }
@@ -347,4 +534,7 @@ func (s *Server) printBuiltinProxyConfig(backend *GoProxyBackend) {
 	_, _ = fmt.Fprintln(os.Stderr, "Built-in Proxy Configuration:")
 	_, _ = fmt.Fprintf(os.Stderr, "Proxy Address: %s\n", backend.Addr())
 	_, _ = fmt.Fprintf(os.Stderr, "CA Certificate: %s\n", caCertPath)
+	if host, _, _ := net.SplitHostPort(backend.Addr()); !net.ParseIP(host).IsLoopback() {
+		_, _ = fmt.Fprintln(os.Stderr, "WARNING: the proxy accepts connections from other hosts; anyone who can reach it can send traffic through it")
+	}
 }
@@ -0,0 +1,290 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"maps"
+	"net/http"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Token extraction sources for a login session or CSRF rule.
+const (
+	extractJSON   = "json"
+	extractHeader = "header"
+	extractCookie = "cookie"
+	extractRegex  = "regex"
+	extractField  = "field" // HTML input value or meta content, by name
+)
+
+// sessionTokenVar is the placeholder for the token in a session's inject header.
+const sessionTokenVar = "{{token}}"
+
+// tokenExtractor pulls a session token out of a login response, or an anti-CSRF
+// token out of the page that issues it.
+type tokenExtractor struct {
+	Kind string
+	Expr string // JSON path, header name, cookie name, regex or field name
+	re   *regexp.Regexp
+}
+
+func newTokenExtractor(kind, expr string) (tokenExtractor, error) {
+	e := tokenExtractor{Kind: kind, Expr: expr}
+	switch kind {
+	case extractJSON:
+		if _, err := parseJSONPath(expr); err != nil {
+			return e, fmt.Errorf("invalid JSON path %q: %w", expr, err)
+		}
+	case extractRegex:
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return e, fmt.Errorf("invalid regex: %w", err)
+		} else if re.NumSubexp() < 1 {
+			return e, errors.New("regex needs a capture group around the token")
+		}
+		e.re = re
+	case extractHeader, extractCookie, extractField:
+	default:
+		return e, fmt.Errorf("unknown extraction %q", kind)
+	}
+	return e, nil
+}
+
+func (e tokenExtractor) String() string {
+	return e.Kind + ":" + e.Expr
+}
+
+// Extract returns the token in a login response, or an error naming what was missing.
+func (e tokenExtractor) Extract(headers, body []byte) (string, error) {
+	return e.ExtractFrom("login response", headers, body)
+}
+
+// ExtractFrom returns the token in a response, which errors call what.
+func (e tokenExtractor) ExtractFrom(what string, headers, body []byte) (string, error) {
+	switch e.Kind {
+	case extractJSON:
+		var data interface{}
+		if err := json.Unmarshal(body, &data); err != nil {
+			return "", fmt.Errorf("%s body is not JSON", what)
+		}
+		segments, _ := parseJSONPath(e.Expr)
+		v, err := getValueAtPath(data, segments)
+		if err != nil {
+			return "", fmt.Errorf("JSON path %s not found in %s", e.Expr, what)
+		}
+		if s, ok := v.(string); ok {
+			return s, nil
+		}
+		b, _ := json.Marshal(v)
+		return string(b), nil
+	case extractHeader:
+		if values := parseHeadersToMap(string(headers))[http.CanonicalHeaderKey(e.Expr)]; len(values) > 0 {
+			return values[0], nil
+		}
+		return "", fmt.Errorf("header %s not in %s", e.Expr, what)
+	case extractCookie:
+		resp := http.Response{Header: http.Header(parseHeadersToMap(string(headers)))}
+		for _, c := range resp.Cookies() {
+			if c.Name == e.Expr {
+				return c.Value, nil
+			}
+		}
+		return "", fmt.Errorf("cookie %s not set by %s", e.Expr, what)
+	case extractField:
+		if value, ok := htmlFieldValue(body, e.Expr); ok {
+			return value, nil
+		}
+		return "", fmt.Errorf("no input or meta tag named %s in %s", e.Expr, what)
+	default:
+		m := e.re.FindSubmatch(append(append(slices.Clone(headers), "\r\n"...), body...))
+		if m == nil {
+			return "", fmt.Errorf("regex %s did not match %s", e.Expr, what)
+		}
+		return string(m[1]), nil
+	}
+}
+
+// loginSession re-authenticates by resending a captured login request and
+// extracting a token from the response, which is injected into replays.
+type loginSession struct {
+	Name      string
+	Base      string // flow_id or replay_id the login request came from
+	Request   []byte
+	Target    Target
+	Extractor tokenExtractor
+	Inject    string // header line containing sessionTokenVar
+	RefreshOn string // status codes/ranges that trigger a new login, e.g. "401,403"
+
+	refreshFilter *StatusCodeFilter
+
+	mu          sync.Mutex // held across a login so concurrent refreshes coalesce
+	token       string
+	refreshedAt time.Time
+	refreshes   int
+	lastErr     string
+}
+
+// sessionState is a consistent snapshot of a session's token status.
+type sessionState struct {
+	Token       string
+	RefreshedAt time.Time
+	Refreshes   int
+	LastErr     string
+}
+
+// State returns the current token status.
+func (l *loginSession) State() sessionState {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return sessionState{Token: l.token, RefreshedAt: l.refreshedAt, Refreshes: l.refreshes, LastErr: l.lastErr}
+}
+
+// ShouldRefresh reports whether a replay response status means the token expired.
+func (l *loginSession) ShouldRefresh(status int) bool {
+	return l.refreshFilter.Matches(status)
+}
+
+// Token returns the current token, logging in first if there is none.
+func (l *loginSession) Token(ctx context.Context, s *Server) (string, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.token != "" {
+		return l.token, nil
+	}
+	return l.login(ctx, s)
+}
+
+// Refresh logs in again unless another caller already replaced stale.
+func (l *loginSession) Refresh(ctx context.Context, s *Server, stale string) (string, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.token != stale && l.token != "" {
+		return l.token, nil
+	}
+	return l.login(ctx, s)
+}
+
+func (l *loginSession) login(ctx context.Context, s *Server) (string, error) {
+	result, err := s.sendRequest(ctx, "sectool-login-"+l.Name, SendRequestInput{
+		RawRequest: l.Request,
+		Target:     l.Target,
+		Timeout:    30 * time.Second,
+	}, nil)
+	if err == nil {
+		code, _ := parseResponseStatus(result.Headers)
+		var token string
+		if token, err = l.Extractor.Extract(result.Headers, result.Body); err == nil && token == "" {
+			err = fmt.Errorf("login response (status %d) has an empty token", code)
+		} else if err != nil {
+			err = fmt.Errorf("%w (status %d)", err, code)
+		}
+		if err == nil {
+			l.token, l.lastErr = token, ""
+			l.refreshedAt = time.Now()
+			l.refreshes++
+			log.Printf("session: %s logged in (login #%d, status %d)", l.Name, l.refreshes, code)
+			return token, nil
+		}
+	}
+	l.lastErr = err.Error()
+	return "", fmt.Errorf("session %s login failed: %w", l.Name, err)
+}
+
+// Apply sets the session header, with token substituted, on a raw request.
+func (l *loginSession) Apply(raw []byte, token string) []byte {
+	headers, body := splitHeadersBody(raw)
+	name, value, _ := strings.Cut(strings.ReplaceAll(l.Inject, sessionTokenVar, token), ":")
+	name, value = strings.TrimSpace(name), strings.TrimSpace(value)
+	if strings.EqualFold(name, "Cookie") {
+		value = mergeCookies(parseHeadersToMap(string(headers))["Cookie"], value)
+		headers = removeHeader(headers, name) // merged from every Cookie line into one
+	}
+	return append(setHeader(headers, name, value), body...)
+}
+
+// mergeCookies sets the name=value pairs of update in the existing Cookie header
+// values, keeping other cookies.
+func mergeCookies(existing []string, update string) string {
+	var names []string
+	values := make(map[string]string)
+	add := func(header string) {
+		for _, pair := range strings.Split(header, ";") {
+			name, value, _ := strings.Cut(strings.TrimSpace(pair), "=")
+			if name == "" {
+				continue
+			} else if _, ok := values[name]; !ok {
+				names = append(names, name)
+			}
+			values[name] = value
+		}
+	}
+	for _, header := range existing {
+		add(header)
+	}
+	add(update)
+
+	pairs := make([]string, len(names))
+	for i, name := range names {
+		pairs[i] = name + "=" + values[name]
+	}
+	return strings.Join(pairs, "; ")
+}
+
+// sessionStore holds login sessions for the service lifetime. Thread-safe.
+type sessionStore struct {
+	mu       sync.Mutex
+	sessions map[string]*loginSession
+}
+
+func newSessionStore() *sessionStore {
+	return &sessionStore{sessions: make(map[string]*loginSession)}
+}
+
+// Get returns the named session.
+func (s *sessionStore) Get(name string) (*loginSession, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sess, ok := s.sessions[name]
+	if !ok {
+		if len(s.sessions) == 0 {
+			return nil, fmt.Errorf("unknown session %q: create one with session_set", name)
+		}
+		return nil, fmt.Errorf("unknown session %q (available: %s)", name, strings.Join(slices.Sorted(maps.Keys(s.sessions)), ", "))
+	}
+	return sess, nil
+}
+
+// Set adds or replaces a session.
+func (s *sessionStore) Set(sess *loginSession) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sessions[sess.Name] = sess
+}
+
+// Delete removes the named session, reporting whether it existed.
+func (s *sessionStore) Delete(name string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, ok := s.sessions[name]
+	delete(s.sessions, name)
+	return ok
+}
+
+// List returns all sessions sorted by name.
+func (s *sessionStore) List() []*loginSession {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	names := slices.Sorted(maps.Keys(s.sessions))
+	out := make([]*loginSession, len(names))
+	for i, name := range names {
+		out[i] = s.sessions[name]
+	}
+	return out
+}
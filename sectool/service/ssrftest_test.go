@@ -0,0 +1,104 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSSRFPayloads(t *testing.T) {
+	t.Parallel()
+
+	all := ssrfPayloads(nil, "abc.oast.example")
+	assert.Len(t, all, len(ssrfStaticPayloads)+6)
+	assert.Equal(t, ssrfPayload{category: ssrfRedirect, value: `/\ssrf05.abc.oast.example/`, label: "ssrf05"}, all[len(all)-2])
+	assert.Equal(t, "ssrf05.abc.oast.example", all[len(all)-2].host())
+	assert.Equal(t, "ssrf03.abc.oast.example", all[len(ssrfStaticPayloads)+2].host())
+
+	for _, p := range ssrfPayloads([]string{ssrfMetadata, ssrfOast}, "") {
+		assert.Equal(t, ssrfMetadata, p.category)
+	}
+
+	custom := customSSRFPayloads([]string{"http://{oast}/x", "http://127.0.0.1:8080/"}, "d.example")
+	assert.Equal(t, []ssrfPayload{
+		{category: ssrfCustom, value: "http://ssrfc01.d.example/x", label: "ssrfc01"},
+		{category: ssrfCustom, value: "http://127.0.0.1:8080/"},
+	}, custom)
+}
+
+func TestInjectSSRF(t *testing.T) {
+	t.Parallel()
+
+	get := []byte("GET /fetch?url=http%3A%2F%2Fa.example%2F&x=1 HTTP/1.1\r\nHost: app\r\n\r\n")
+	assert.Equal(t, ssrfInQuery, ssrfLocation(get, "url"))
+	assert.Equal(t, "GET /fetch?url=http%3A%2F%2F127.0.0.1%2F&x=1 HTTP/1.1\r\nHost: app\r\n\r\n",
+		string(injectSSRF(get, ssrfInQuery, "url", "http://127.0.0.1/")))
+	assert.Equal(t, "GET /fetch?url=http%3A%2F%2Fa.example%2F&x=1 HTTP/1.1\r\nHost: app\r\nReferer: http://127.0.0.1/\r\n\r\n",
+		string(injectSSRF(get, ssrfInHeader, "Referer", "http://127.0.0.1/")))
+
+	post := []byte("POST /hook HTTP/1.1\r\nHost: app\r\nContent-Type: application/json\r\nContent-Length: 26\r\n\r\n{\"callback\":\"http://a/\"}")
+	assert.Equal(t, ssrfInBody, ssrfLocation(post, "callback"))
+	assert.Contains(t, string(injectSSRF(post, ssrfInBody, "callback", "file:///etc/passwd")), `{"callback":"file:///etc/passwd"}`)
+	assert.Equal(t, ssrfInQuery, ssrfLocation([]byte("POST /hook HTTP/1.1\r\nHost: app\r\nContent-Type: text/plain\r\n\r\nurl"), "url"))
+}
+
+func TestClassifySSRF(t *testing.T) {
+	t.Parallel()
+
+	response := func(headers, body string, d time.Duration) *SendRequestResult {
+		return &SendRequestResult{Headers: []byte(headers + "\r\n\r\n"), Body: []byte(body), Duration: d}
+	}
+	page := "<html><body>Preview unavailable for this URL. Try again later.</body></html>"
+	baseline := &ssrfProbe{status: 200, result: response("HTTP/1.1 200 OK", page, 100*time.Millisecond)}
+	control := &ssrfProbe{status: 200, result: response("HTTP/1.1 200 OK", page, 100*time.Millisecond)}
+	probe := func(p ssrfPayload, status int, result *SendRequestResult, err error) *ssrfProbe {
+		pr := &ssrfProbe{payload: p, status: status, result: result, err: err}
+		classifySSRF(pr, baseline, control)
+		return pr
+	}
+
+	aws := ssrfPayload{category: ssrfMetadata, value: "http://169.254.169.254/latest/meta-data/", markers: awsMarkers}
+	got := probe(aws, 200, response("HTTP/1.1 200 OK", "ami-id\ninstance-id\nhostname", 80*time.Millisecond), nil)
+	assert.Equal(t, ssrfConfirmed, got.verdict)
+	assert.Equal(t, []string{`response contains "ami-id"`, `response contains "instance-id"`}, got.evidence)
+
+	redirect := ssrfPayload{category: ssrfRedirect, value: "//ssrf04.d.example/", label: "ssrf04"}
+	got = probe(redirect, 302, response("HTTP/1.1 302 Found\r\nLocation: //ssrf04.d.example/", "", 0), nil)
+	assert.Equal(t, ssrfConfirmed, got.verdict)
+	assert.Equal(t, []string{"redirects to payload host: //ssrf04.d.example/"}, got.evidence)
+
+	got = probe(ssrfStaticPayloads[0], 500, response("HTTP/1.1 500 Internal Server Error", "upstream refused", 3*time.Second), nil)
+	assert.Equal(t, ssrfDiffers, got.verdict)
+	require.Len(t, got.evidence, 3)
+	assert.Equal(t, "status 500 (control 200)", got.evidence[0])
+	assert.Equal(t, "took 3s (control 100ms)", got.evidence[2])
+
+	got = probe(ssrfStaticPayloads[1], 200, response("HTTP/1.1 200 OK", page, 90*time.Millisecond), nil)
+	assert.Equal(t, ssrfNone, got.verdict)
+	assert.Empty(t, got.evidence)
+
+	got = probe(ssrfStaticPayloads[2], 0, nil, errors.New("timeout"))
+	assert.Equal(t, ssrfError, got.verdict)
+
+	oast := &ssrfProbe{payload: ssrfPayload{category: ssrfOast, value: "http://ssrf01.d.example/", label: "ssrf01"}, verdict: ssrfNone}
+	other := &ssrfProbe{payload: ssrfPayload{category: ssrfOast, value: "http://ssrf02.d.example/", label: "ssrf02"}, verdict: ssrfNone}
+	applySSRFOast([]*ssrfProbe{oast, other}, []OastEventInfo{
+		{Type: "dns", SourceIP: "198.51.100.1", Subdomain: "ssrf01.d.example"},
+		{Type: "http", SourceIP: "198.51.100.1", Subdomain: "ssrf01.d.example"},
+		{Type: "dns", SourceIP: "198.51.100.1", Subdomain: "ssrf012.d.example"},
+	})
+	assert.Equal(t, ssrfConfirmed, oast.verdict)
+	assert.Equal(t, []string{"oast dns interaction from 198.51.100.1", "oast http interaction from 198.51.100.1"}, oast.evidence)
+	assert.Equal(t, ssrfNone, other.verdict)
+}
+
+func TestRedirectLocation(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "/login", redirectLocation([]byte("HTTP/1.1 302 Found\r\nLocation: /login\r\n\r\n")))
+	assert.Empty(t, redirectLocation([]byte("HTTP/1.1 200 OK\r\nLocation: /login\r\n\r\n")))
+	assert.Equal(t, "https://Evil.example/", redirectLocation([]byte("HTTP/1.1 200 OK\r\nRefresh: 0; URL='https://Evil.example/'\r\n\r\n")))
+}
@@ -21,6 +21,8 @@ type FlowStore struct {
 	byID     map[string]*FlowEntry // flow_id -> entry
 	byHash   map[string][]string   // hash -> []flow_id (collision handling)
 	byOffset map[uint32]string     // offset -> flow_id (for updates)
+	tags     map[string][]string   // flow_id or replay_id -> sorted tags
+	tagOrder []string              // tagged IDs, in the order first tagged
 }
 
 // NewFlowStore creates a new empty FlowStore.
@@ -29,6 +31,7 @@ func NewFlowStore() *FlowStore {
 		byID:     make(map[string]*FlowEntry),
 		byHash:   make(map[string][]string),
 		byOffset: make(map[uint32]string),
+		tags:     make(map[string][]string),
 	}
 }
 
@@ -118,6 +121,8 @@ func (s *FlowStore) Clear() {
 	s.byID = make(map[string]*FlowEntry)
 	s.byHash = make(map[string][]string)
 	s.byOffset = make(map[uint32]string)
+	s.tags = make(map[string][]string)
+	s.tagOrder = nil
 }
 
 func (s *FlowStore) Count() int {
@@ -134,3 +139,54 @@ func (s *FlowStore) AllFlowIDs() []string {
 
 	return bulk.MapKeysSlice(s.byID)
 }
+
+// Tag adds and then removes tags on a flow or replay ID, returning its tags sorted.
+// An ID left with no tags is untracked.
+func (s *FlowStore) Tag(id string, add, remove []string) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	existing, tracked := s.tags[id]
+	tags := slices.Clone(existing)
+	for _, tag := range add {
+		if !slices.Contains(tags, tag) {
+			tags = append(tags, tag)
+		}
+	}
+	tags = slices.DeleteFunc(tags, func(tag string) bool { return slices.Contains(remove, tag) })
+	slices.Sort(tags)
+
+	switch {
+	case len(tags) > 0:
+		if !tracked {
+			s.tagOrder = append(s.tagOrder, id)
+		}
+		s.tags[id] = tags
+	case tracked:
+		delete(s.tags, id)
+		s.tagOrder = slices.DeleteFunc(s.tagOrder, func(t string) bool { return t == id })
+	}
+	return slices.Clone(tags)
+}
+
+// Tags returns the tags on a flow or replay ID.
+func (s *FlowStore) Tags(id string) []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return slices.Clone(s.tags[id])
+}
+
+// Tagged returns the IDs carrying tag (any tag when empty), in the order first tagged.
+func (s *FlowStore) Tagged(tag string) []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	ids := make([]string, 0, len(s.tagOrder))
+	for _, id := range s.tagOrder {
+		if tag == "" || slices.Contains(s.tags[id], tag) {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
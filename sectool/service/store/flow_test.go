@@ -205,3 +205,40 @@ func TestStoreConcurrency(t *testing.T) {
 	// Verify final state
 	assert.Equal(t, 100, store.Count())
 }
+
+func TestFlowStoreTags(t *testing.T) {
+	t.Parallel()
+
+	t.Run("add_and_remove", func(t *testing.T) {
+		store := NewFlowStore()
+
+		assert.Equal(t, []string{"auth", "idor"}, store.Tag("f1", []string{"idor", "auth", "auth"}, nil))
+		assert.Equal(t, []string{"idor"}, store.Tag("f1", nil, []string{"auth"}))
+		assert.Equal(t, []string{"idor"}, store.Tags("f1"))
+		assert.Nil(t, store.Tags("missing"))
+	})
+
+	t.Run("tagged_in_order", func(t *testing.T) {
+		store := NewFlowStore()
+
+		store.Tag("b", []string{"auth"}, nil)
+		store.Tag("a", []string{"auth", "xss"}, nil)
+		store.Tag("c", []string{"xss"}, nil)
+
+		assert.Equal(t, []string{"b", "a", "c"}, store.Tagged(""))
+		assert.Equal(t, []string{"b", "a"}, store.Tagged("auth"))
+		assert.Empty(t, store.Tagged("none"))
+	})
+
+	t.Run("untagged_id_dropped", func(t *testing.T) {
+		store := NewFlowStore()
+
+		store.Tag("a", []string{"auth"}, nil)
+		assert.Empty(t, store.Tag("a", nil, []string{"auth"}))
+		assert.Empty(t, store.Tagged(""))
+
+		store.Clear()
+		store.Tag("b", []string{"x"}, nil)
+		assert.Equal(t, []string{"b"}, store.Tagged(""))
+	})
+}
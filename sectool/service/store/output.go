@@ -0,0 +1,55 @@
+package store
+
+import (
+	"slices"
+	"sync"
+)
+
+// OutputStore keeps the full text of recently truncated tool results so they
+// can be fetched in chunks. Only the newest capacity entries are retained. Thread-safe.
+type OutputStore struct {
+	mu       sync.Mutex
+	capacity int
+	order    []string
+	entries  map[string]string
+}
+
+// NewOutputStore creates an OutputStore retaining at most capacity outputs.
+func NewOutputStore(capacity int) *OutputStore {
+	return &OutputStore{
+		capacity: max(capacity, 1),
+		entries:  make(map[string]string),
+	}
+}
+
+// Store saves the output, evicting the oldest entry when full.
+func (s *OutputStore) Store(id, output string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.entries[id]; !ok {
+		s.order = append(s.order, id)
+	}
+	s.entries[id] = output
+	for len(s.order) > s.capacity {
+		delete(s.entries, s.order[0])
+		s.order = slices.Delete(s.order, 0, 1)
+	}
+}
+
+// Get retrieves an output by ID.
+func (s *OutputStore) Get(id string) (string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	out, ok := s.entries[id]
+	return out, ok
+}
+
+// Count returns the number of retained outputs.
+func (s *OutputStore) Count() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return len(s.entries)
+}
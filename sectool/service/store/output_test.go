@@ -0,0 +1,25 @@
+package store
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOutputStore(t *testing.T) {
+	t.Parallel()
+
+	s := NewOutputStore(2)
+	s.Store("a", "one")
+	s.Store("b", "two")
+	s.Store("a", "uno") // update keeps position
+	s.Store("c", "three")
+
+	_, ok := s.Get("a")
+	assert.False(t, ok)
+	out, ok := s.Get("c")
+	require.True(t, ok)
+	assert.Equal(t, "three", out)
+	assert.Equal(t, 2, s.Count())
+}
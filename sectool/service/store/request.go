@@ -1,12 +1,18 @@
 package store
 
 import (
+	"slices"
+	"strings"
 	"sync"
 	"time"
 )
 
 // RequestEntry stores a request/response pair with metadata.
 type RequestEntry struct {
+	Label     string // optional user label for lookup
+	Base      string // flow or replay ID the request was derived from, if any
+	Request   []byte // raw request as sent
+	Target    string // scheme://host:port the request was sent to
 	Headers   []byte
 	Body      []byte
 	Duration  time.Duration
@@ -47,6 +53,54 @@ func (s *RequestStore) Get(id string) (*RequestEntry, bool) {
 	return e, ok
 }
 
+// Recent returns the ID of the nth most recent entry (0 is the newest).
+func (s *RequestStore) Recent(n int) (string, bool) {
+	ids := s.RecentIDs(0)
+	if n < 0 || n >= len(ids) {
+		return "", false
+	}
+	return ids[n], true
+}
+
+// LookupLabel returns the ID of the newest entry with the given label.
+func (s *RequestStore) LookupLabel(label string) (string, bool) {
+	if label == "" {
+		return "", false
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var bestID string
+	var best time.Time
+	for id, e := range s.entries {
+		if e.Label == label && (bestID == "" || e.CreatedAt.After(best)) {
+			bestID, best = id, e.CreatedAt
+		}
+	}
+	return bestID, bestID != ""
+}
+
+// RecentIDs returns entry IDs newest first, capped at limit (0 for all).
+func (s *RequestStore) RecentIDs(limit int) []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	ids := make([]string, 0, len(s.entries))
+	for id := range s.entries {
+		ids = append(ids, id)
+	}
+	slices.SortFunc(ids, func(a, b string) int {
+		if c := s.entries[b].CreatedAt.Compare(s.entries[a].CreatedAt); c != 0 {
+			return c
+		}
+		return strings.Compare(a, b)
+	})
+	if limit > 0 && len(ids) > limit {
+		ids = ids[:limit]
+	}
+	return ids
+}
+
 // Delete removes an entry by ID.
 func (s *RequestStore) Delete(id string) {
 	s.mu.Lock()
@@ -101,3 +101,60 @@ func TestRequestStoreClear(t *testing.T) {
 	_, ok := store.Get("one")
 	assert.False(t, ok)
 }
+
+func TestRequestStoreRecent(t *testing.T) {
+	t.Parallel()
+
+	store := NewRequestStore()
+	store.Store("old", &RequestEntry{CreatedAt: time.Unix(100, 0)})
+	store.Store("new", &RequestEntry{CreatedAt: time.Unix(300, 0)})
+	store.Store("mid", &RequestEntry{CreatedAt: time.Unix(200, 0)})
+
+	tests := []struct {
+		n      int
+		wantID string
+		wantOK bool
+	}{
+		{n: 0, wantID: "new", wantOK: true},
+		{n: 1, wantID: "mid", wantOK: true},
+		{n: 2, wantID: "old", wantOK: true},
+		{n: 3},
+		{n: -1},
+	}
+	for _, tt := range tests {
+		id, ok := store.Recent(tt.n)
+		assert.Equal(t, tt.wantOK, ok, "n=%d", tt.n)
+		assert.Equal(t, tt.wantID, id, "n=%d", tt.n)
+	}
+}
+
+func TestRequestStoreLookupLabel(t *testing.T) {
+	t.Parallel()
+
+	store := NewRequestStore()
+	store.Store("a", &RequestEntry{Label: "baseline", CreatedAt: time.Unix(100, 0)})
+	store.Store("b", &RequestEntry{Label: "baseline", CreatedAt: time.Unix(200, 0)})
+	store.Store("c", &RequestEntry{CreatedAt: time.Unix(300, 0)})
+
+	id, ok := store.LookupLabel("baseline")
+	require.True(t, ok)
+	assert.Equal(t, "b", id)
+
+	_, ok = store.LookupLabel("missing")
+	assert.False(t, ok)
+	_, ok = store.LookupLabel("")
+	assert.False(t, ok)
+}
+
+func TestRequestStoreRecentIDs(t *testing.T) {
+	t.Parallel()
+
+	store := NewRequestStore()
+	store.Store("old", &RequestEntry{CreatedAt: time.Unix(100, 0)})
+	store.Store("new", &RequestEntry{CreatedAt: time.Unix(300, 0)})
+	store.Store("mid", &RequestEntry{CreatedAt: time.Unix(200, 0)})
+
+	assert.Equal(t, []string{"new", "mid", "old"}, store.RecentIDs(0))
+	assert.Equal(t, []string{"new", "mid"}, store.RecentIDs(2))
+	assert.Equal(t, []string{"new", "mid", "old"}, store.RecentIDs(10))
+}
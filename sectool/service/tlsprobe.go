@@ -0,0 +1,267 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-harden/llm-security-toolbox/sectool/protocol"
+)
+
+const (
+	defaultTLSProbeTimeout = 2 * time.Minute
+	tlsHandshakeTimeout    = 5 * time.Second
+	tlsExpiryWarningDays   = 30
+	// tlsUntested names protocols crypto/tls cannot offer, so the probe cannot detect them.
+	tlsUntested = "SSL 2.0, SSL 3.0 (not implemented by the probe's TLS stack)"
+)
+
+// tlsProbeVersions are the protocol versions tried, oldest first.
+var tlsProbeVersions = []uint16{tls.VersionTLS10, tls.VersionTLS11, tls.VersionTLS12, tls.VersionTLS13}
+
+// tlsProbeInput describes a tls_probe run.
+type tlsProbeInput struct {
+	Host    string
+	Port    int
+	SNI     string // default Host, omitted for IP addresses
+	Ciphers bool   // enumerate accepted cipher suites for TLS 1.0-1.2
+}
+
+// tlsVersionResult is the outcome of probing one protocol version.
+type tlsVersionResult struct {
+	version   uint16
+	supported bool
+	ciphers   []uint16
+}
+
+// tlsProbeResult is what probeTLS learned about a server.
+type tlsProbeResult struct {
+	versions []tlsVersionResult
+	alpn     []string
+	chain    []*x509.Certificate
+}
+
+// probeTLS handshakes with the server once per protocol version and, when requested,
+// once per cipher suite, each on a new connection with certificate checks disabled.
+// The chain is taken from the newest version the server supports.
+func probeTLS(ctx context.Context, in tlsProbeInput) (*tlsProbeResult, error) {
+	addr := net.JoinHostPort(in.Host, strconv.Itoa(in.Port))
+	serverName := in.SNI
+	if serverName == "" && net.ParseIP(in.Host) == nil {
+		serverName = in.Host
+	}
+	handshake := func(cfg *tls.Config) (*tls.ConnectionState, error) {
+		cfg.ServerName = serverName
+		cfg.InsecureSkipVerify = true
+		ctx, cancel := context.WithTimeout(ctx, tlsHandshakeTimeout)
+		defer cancel()
+		d := tls.Dialer{Config: cfg}
+		conn, err := d.DialContext(ctx, "tcp", dialAddr(ctx, addr))
+		if err != nil {
+			return nil, err
+		}
+		defer func() { _ = conn.Close() }()
+		state := conn.(*tls.Conn).ConnectionState()
+		return &state, nil
+	}
+
+	// A plain TCP dial first separates an unreachable port from a failed handshake
+	conn, err := dialContext(ctx, "tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("dial %s: %w", addr, err)
+	}
+	_ = conn.Close()
+
+	result := &tlsProbeResult{}
+	var newest *tls.ConnectionState
+	var lastErr error
+	for _, version := range tlsProbeVersions {
+		state, err := handshake(&tls.Config{MinVersion: version, MaxVersion: version})
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		v := tlsVersionResult{version: version, supported: err == nil}
+		if err != nil {
+			lastErr = err
+		} else {
+			newest = state
+			if version == tls.VersionTLS13 || !in.Ciphers {
+				v.ciphers = []uint16{state.CipherSuite}
+			} else {
+				for _, suite := range tlsSuitesFor(version) {
+					if _, err := handshake(&tls.Config{MinVersion: version, MaxVersion: version, CipherSuites: []uint16{suite}}); err == nil {
+						v.ciphers = append(v.ciphers, suite)
+					} else if ctx.Err() != nil {
+						return nil, ctx.Err()
+					}
+				}
+			}
+		}
+		result.versions = append(result.versions, v)
+	}
+	if newest == nil {
+		return nil, fmt.Errorf("no TLS version negotiated with %s: %w", addr, lastErr)
+	}
+	result.chain = newest.PeerCertificates
+
+	for _, proto := range []string{"h2", "http/1.1"} {
+		if state, err := handshake(&tls.Config{NextProtos: []string{proto}}); err == nil && state.NegotiatedProtocol == proto {
+			result.alpn = append(result.alpn, proto)
+		}
+	}
+	return result, nil
+}
+
+// tlsSuitesFor returns the cipher suites crypto/tls can offer with version.
+func tlsSuitesFor(version uint16) []uint16 {
+	var ids []uint16
+	for _, suite := range append(tls.CipherSuites(), tls.InsecureCipherSuites()...) {
+		if slices.Contains(suite.SupportedVersions, version) {
+			ids = append(ids, suite.ID)
+		}
+	}
+	return ids
+}
+
+// tlsSuiteInsecure reports whether crypto/tls classifies the suite as insecure.
+func tlsSuiteInsecure(id uint16) bool {
+	return slices.ContainsFunc(tls.InsecureCipherSuites(), func(s *tls.CipherSuite) bool { return s.ID == id })
+}
+
+// verifyTLSChain verifies chain against the system roots for serverName.
+func verifyTLSChain(chain []*x509.Certificate, serverName string, now time.Time) error {
+	if len(chain) == 0 {
+		return errors.New("no certificate presented")
+	}
+	intermediates := x509.NewCertPool()
+	for _, cert := range chain[1:] {
+		intermediates.AddCert(cert)
+	}
+	_, err := chain[0].Verify(x509.VerifyOptions{DNSName: serverName, Intermediates: intermediates, CurrentTime: now})
+	return err
+}
+
+// tlsCertificateSummary converts a certificate for the tls_probe response.
+func tlsCertificateSummary(cert *x509.Certificate, now time.Time) protocol.TLSCertificate {
+	summary := protocol.TLSCertificate{
+		Subject:            cert.Subject.String(),
+		Issuer:             cert.Issuer.String(),
+		SerialNumber:       cert.SerialNumber.Text(16),
+		NotBefore:          cert.NotBefore.UTC().Format(time.RFC3339),
+		NotAfter:           cert.NotAfter.UTC().Format(time.RFC3339),
+		DaysLeft:           int(cert.NotAfter.Sub(now).Hours() / 24),
+		DNSNames:           cert.DNSNames,
+		Key:                tlsKeyDescription(cert),
+		SignatureAlgorithm: cert.SignatureAlgorithm.String(),
+		SelfSigned:         tlsSelfSigned(cert),
+	}
+	for _, ip := range cert.IPAddresses {
+		summary.IPAddresses = append(summary.IPAddresses, ip.String())
+	}
+	return summary
+}
+
+// tlsSelfSigned reports whether cert is signed by its own key. CheckSignatureFrom is
+// not used since it also requires the CA flag, which self-signed leaf certificates lack.
+func tlsSelfSigned(cert *x509.Certificate) bool {
+	return bytes.Equal(cert.RawSubject, cert.RawIssuer) &&
+		cert.CheckSignature(cert.SignatureAlgorithm, cert.RawTBSCertificate, cert.Signature) == nil
+}
+
+// tlsKeyDescription returns the key algorithm and size, e.g. "RSA 2048".
+func tlsKeyDescription(cert *x509.Certificate) string {
+	switch key := cert.PublicKey.(type) {
+	case *rsa.PublicKey:
+		return "RSA " + strconv.Itoa(key.N.BitLen())
+	case *ecdsa.PublicKey:
+		return "ECDSA " + key.Curve.Params().Name
+	case ed25519.PublicKey:
+		return "Ed25519"
+	}
+	return cert.PublicKeyAlgorithm.String()
+}
+
+// tlsIssues lists weak protocol, cipher and certificate configurations. trustErr is the
+// result of verifyTLSChain.
+func tlsIssues(result *tlsProbeResult, trustErr error, now time.Time) []protocol.TLSIssue {
+	var issues []protocol.TLSIssue
+	add := func(check, severity, detail string) {
+		issues = append(issues, protocol.TLSIssue{Check: check, Severity: severity, Detail: detail})
+	}
+
+	supported := make(map[uint16]bool)
+	var insecure, noForwardSecrecy []string
+	for _, v := range result.versions {
+		supported[v.version] = v.supported
+		for _, id := range v.ciphers {
+			name := tls.CipherSuiteName(id)
+			if tlsSuiteInsecure(id) && !slices.Contains(insecure, name) {
+				insecure = append(insecure, name)
+			}
+			if strings.HasPrefix(name, "TLS_RSA_") && !slices.Contains(noForwardSecrecy, name) {
+				noForwardSecrecy = append(noForwardSecrecy, name)
+			}
+		}
+	}
+	if supported[tls.VersionTLS10] {
+		add("tls1.0_enabled", "medium", "TLS 1.0 is deprecated (RFC 8996)")
+	}
+	if supported[tls.VersionTLS11] {
+		add("tls1.1_enabled", "medium", "TLS 1.1 is deprecated (RFC 8996)")
+	}
+	if !supported[tls.VersionTLS12] && !supported[tls.VersionTLS13] {
+		add("no_modern_tls", "high", "neither TLS 1.2 nor TLS 1.3 is supported")
+	} else if !supported[tls.VersionTLS13] {
+		add("tls1.3_unsupported", "info", "")
+	}
+	if len(insecure) > 0 {
+		add("insecure_ciphers", "medium", strings.Join(insecure, ", "))
+	}
+	if len(noForwardSecrecy) > 0 {
+		add("no_forward_secrecy", "low", "RSA key exchange: "+strings.Join(noForwardSecrecy, ", "))
+	}
+
+	if len(result.chain) == 0 {
+		return issues
+	}
+	leaf := result.chain[0]
+	switch {
+	case now.After(leaf.NotAfter):
+		add("certificate_expired", "high", "expired "+leaf.NotAfter.UTC().Format(time.DateOnly))
+	case now.Before(leaf.NotBefore):
+		add("certificate_not_yet_valid", "high", "valid from "+leaf.NotBefore.UTC().Format(time.DateOnly))
+	case leaf.NotAfter.Sub(now) < tlsExpiryWarningDays*24*time.Hour:
+		add("certificate_expiring", "low", "expires "+leaf.NotAfter.UTC().Format(time.DateOnly))
+	}
+	var hostErr x509.HostnameError
+	var authErr x509.UnknownAuthorityError
+	switch {
+	case errors.As(trustErr, &hostErr):
+		add("hostname_mismatch", "high", hostErr.Error())
+	case errors.As(trustErr, &authErr):
+		add("untrusted_certificate", "medium", authErr.Error())
+	}
+	for _, cert := range result.chain {
+		if key, ok := cert.PublicKey.(*rsa.PublicKey); ok && key.N.BitLen() < 2048 {
+			add("weak_key", "medium", fmt.Sprintf("RSA %d bits: %s", key.N.BitLen(), cert.Subject))
+		}
+		switch cert.SignatureAlgorithm {
+		case x509.SHA1WithRSA, x509.ECDSAWithSHA1, x509.MD5WithRSA, x509.MD2WithRSA:
+			if cert.CheckSignatureFrom(cert) != nil { // a root's self-signature is not relied on
+				add("weak_signature", "medium", cert.SignatureAlgorithm.String()+": "+cert.Subject.String())
+			}
+		}
+	}
+	return issues
+}
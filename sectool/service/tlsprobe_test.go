@@ -0,0 +1,170 @@
+package service
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/go-harden/llm-security-toolbox/sectool/protocol"
+)
+
+// newTLS12Server starts a TLS 1.2 only server accepting the given cipher suites and
+// returns its host and port.
+func newTLS12Server(t *testing.T, suites ...uint16) (string, int) {
+	t.Helper()
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	srv.TLS = &tls.Config{MinVersion: tls.VersionTLS12, MaxVersion: tls.VersionTLS12, CipherSuites: suites, NextProtos: []string{"h2", "http/1.1"}}
+	srv.StartTLS()
+	t.Cleanup(srv.Close)
+
+	u, err := url.Parse(srv.URL)
+	require.NoError(t, err)
+	port, err := strconv.Atoi(u.Port())
+	require.NoError(t, err)
+	return u.Hostname(), port
+}
+
+func TestProbeTLS(t *testing.T) {
+	t.Parallel()
+
+	host, port := newTLS12Server(t, tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256, tls.TLS_ECDHE_RSA_WITH_AES_128_CBC_SHA256)
+
+	t.Run("ciphers", func(t *testing.T) {
+		result, err := probeTLS(t.Context(), tlsProbeInput{Host: host, Port: port, Ciphers: true})
+		require.NoError(t, err)
+		require.Len(t, result.versions, 4)
+		for _, v := range result.versions {
+			assert.Equal(t, v.version == tls.VersionTLS12, v.supported, tls.VersionName(v.version))
+		}
+		assert.ElementsMatch(t, []uint16{tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256, tls.TLS_ECDHE_RSA_WITH_AES_128_CBC_SHA256}, result.versions[2].ciphers)
+		require.NotEmpty(t, result.chain)
+		assert.Equal(t, []string{"h2", "http/1.1"}, result.alpn)
+
+		issues := tlsIssues(result, verifyTLSChain(result.chain, host, time.Now()), time.Now())
+		checks := make(map[string]string)
+		for _, issue := range issues {
+			checks[issue.Check] = issue.Detail
+		}
+		assert.Equal(t, "TLS_ECDHE_RSA_WITH_AES_128_CBC_SHA256", checks["insecure_ciphers"])
+		assert.Contains(t, checks, "tls1.3_unsupported")
+		assert.Contains(t, checks, "untrusted_certificate")
+		assert.NotContains(t, checks, "tls1.0_enabled")
+	})
+
+	t.Run("negotiated_only", func(t *testing.T) {
+		result, err := probeTLS(t.Context(), tlsProbeInput{Host: host, Port: port})
+		require.NoError(t, err)
+		assert.Len(t, result.versions[2].ciphers, 1)
+	})
+
+	t.Run("closed_port", func(t *testing.T) {
+		l, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		closedPort := l.Addr().(*net.TCPAddr).Port
+		require.NoError(t, l.Close())
+		_, err = probeTLS(t.Context(), tlsProbeInput{Host: "127.0.0.1", Port: closedPort})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "dial")
+	})
+}
+
+func TestTLSIssues(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	newCert := func(t *testing.T, key any, notAfter time.Time, sigAlg x509.SignatureAlgorithm) *x509.Certificate {
+		t.Helper()
+		tmpl := &x509.Certificate{
+			SerialNumber:       big.NewInt(1),
+			Subject:            pkix.Name{CommonName: "example.com"},
+			NotBefore:          now.AddDate(-1, 0, 0),
+			NotAfter:           notAfter,
+			DNSNames:           []string{"example.com"},
+			SignatureAlgorithm: sigAlg,
+		}
+		var pub any
+		switch k := key.(type) {
+		case *rsa.PrivateKey:
+			pub = &k.PublicKey
+		case *ecdsa.PrivateKey:
+			pub = &k.PublicKey
+		}
+		der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, pub, key)
+		require.NoError(t, err)
+		cert, err := x509.ParseCertificate(der)
+		require.NoError(t, err)
+		return cert
+	}
+	checks := func(issues []protocol.TLSIssue) map[string]string {
+		m := make(map[string]string)
+		for _, issue := range issues {
+			m[issue.Check] = issue.Severity
+		}
+		return m
+	}
+
+	t.Run("protocols", func(t *testing.T) {
+		result := &tlsProbeResult{versions: []tlsVersionResult{
+			{version: tls.VersionTLS10, supported: true, ciphers: []uint16{tls.TLS_RSA_WITH_AES_128_CBC_SHA, tls.TLS_RSA_WITH_RC4_128_SHA}},
+			{version: tls.VersionTLS11, supported: true},
+			{version: tls.VersionTLS12},
+			{version: tls.VersionTLS13},
+		}}
+		got := checks(tlsIssues(result, nil, now))
+		assert.Equal(t, map[string]string{
+			"tls1.0_enabled":     "medium",
+			"tls1.1_enabled":     "medium",
+			"no_modern_tls":      "high",
+			"insecure_ciphers":   "medium",
+			"no_forward_secrecy": "low",
+		}, got)
+	})
+
+	t.Run("certificate", func(t *testing.T) {
+		rsaKey, err := rsa.GenerateKey(rand.Reader, 1024)
+		require.NoError(t, err)
+		expired := newCert(t, rsaKey, now.AddDate(0, 0, -3), x509.SHA256WithRSA)
+		result := &tlsProbeResult{
+			versions: []tlsVersionResult{{version: tls.VersionTLS13, supported: true}},
+			chain:    []*x509.Certificate{expired},
+		}
+		got := checks(tlsIssues(result, x509.HostnameError{Certificate: expired, Host: "other.com"}, now))
+		assert.Equal(t, map[string]string{
+			"certificate_expired": "high",
+			"hostname_mismatch":   "high",
+			"weak_key":            "medium",
+		}, got)
+
+		summary := tlsCertificateSummary(expired, now)
+		assert.Equal(t, "RSA 1024", summary.Key)
+		assert.Equal(t, -3, summary.DaysLeft)
+		assert.True(t, summary.SelfSigned)
+	})
+
+	t.Run("expiring", func(t *testing.T) {
+		ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		require.NoError(t, err)
+		cert := newCert(t, ecKey, now.AddDate(0, 0, 10), x509.ECDSAWithSHA256)
+		result := &tlsProbeResult{
+			versions: []tlsVersionResult{{version: tls.VersionTLS13, supported: true}},
+			chain:    []*x509.Certificate{cert},
+		}
+		assert.Equal(t, map[string]string{"certificate_expiring": "low"}, checks(tlsIssues(result, nil, now)))
+		assert.Equal(t, "ECDSA P-256", tlsCertificateSummary(cert, now).Key)
+	})
+}
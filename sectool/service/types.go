@@ -6,6 +6,7 @@ import (
 	"net"
 	"os"
 
+	"github.com/go-harden/llm-security-toolbox/sectool/config"
 	"github.com/go-harden/llm-security-toolbox/sectool/protocol"
 )
 
@@ -45,13 +46,16 @@ type ProxyListRequest struct {
 	ExcludePath  string `json:"exclude_path,omitempty"`
 	Limit        int    `json:"limit,omitempty"`
 	Offset       int    `json:"offset,omitempty"`
+
+	// Scope limits results to in-scope flows when set (in_scope=true)
+	Scope *config.Scope `json:"-"`
 }
 
 // HasFilters returns true if any filter is set.
 func (r *ProxyListRequest) HasFilters() bool {
 	return r.Host != "" || r.Path != "" || r.Method != "" || r.Status != "" ||
 		r.Contains != "" || r.ContainsBody != "" || r.Since != "" ||
-		r.ExcludeHost != "" || r.ExcludePath != "" || r.Limit > 0
+		r.ExcludeHost != "" || r.ExcludePath != "" || r.Limit > 0 || r.Scope != nil
 }
 
 // =============================================================================
@@ -0,0 +1,162 @@
+package service
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/tls"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+)
+
+// wsAcceptGUID is appended to Sec-WebSocket-Key to derive Sec-WebSocket-Accept (RFC 6455 §1.3).
+const wsAcceptGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// wsClient is a client WebSocket connection opened by ws_send.
+type wsClient struct {
+	conn net.Conn
+	r    *bufio.Reader
+}
+
+// newWSKey returns a random Sec-WebSocket-Key.
+func newWSKey() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	return base64.StdEncoding.EncodeToString(b[:])
+}
+
+// wsAccept returns the Sec-WebSocket-Accept value expected for key.
+func wsAccept(key string) string {
+	sum := sha1.Sum([]byte(key + wsAcceptGUID))
+	return base64.StdEncoding.EncodeToString(sum[:])
+}
+
+// dialWSClient sends the raw opening handshake to target. The handshake response is
+// returned with a nil client when the server does not switch protocols.
+func dialWSClient(ctx context.Context, target Target, rawRequest []byte, key string) (*wsClient, *http.Response, error) {
+	addr := net.JoinHostPort(target.Hostname, strconv.Itoa(target.Port))
+	dialer := &net.Dialer{}
+	var conn net.Conn
+	var err error
+	if target.UsesHTTPS {
+		tlsDialer := &tls.Dialer{
+			NetDialer: dialer,
+			Config: &tls.Config{
+				InsecureSkipVerify: true,
+				ServerName:         target.Hostname,
+			},
+		}
+		conn, err = tlsDialer.DialContext(ctx, "tcp", dialAddr(ctx, addr))
+	} else {
+		conn, err = dialer.DialContext(ctx, "tcp", dialAddr(ctx, addr))
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("dial: %w", err)
+	}
+
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+	if _, err := conn.Write(rawRequest); err != nil {
+		_ = conn.Close()
+		return nil, nil, fmt.Errorf("write handshake: %w", err)
+	}
+
+	r := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(r, nil)
+	if err != nil {
+		_ = conn.Close()
+		return nil, nil, fmt.Errorf("read handshake response: %w", err)
+	}
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		_ = conn.Close()
+		return nil, resp, nil
+	}
+	if got := resp.Header.Get("Sec-WebSocket-Accept"); got != wsAccept(key) {
+		_ = conn.Close()
+		return nil, resp, fmt.Errorf("invalid Sec-WebSocket-Accept %q", got)
+	}
+
+	_ = conn.SetDeadline(time.Time{})
+	return &wsClient{conn: conn, r: r}, resp, nil
+}
+
+// send writes a single masked frame, as required for clients (RFC 6455 §5.1).
+func (c *wsClient) send(opcode byte, payload []byte) error {
+	frame := &wsFrame{fin: true, opcode: opcode, masked: true, payload: payload}
+	if _, err := io.ReadFull(rand.Reader, frame.mask[:]); err != nil {
+		return err
+	}
+	_, err := c.conn.Write(encodeWSFrame(frame))
+	return err
+}
+
+// receive collects data messages until wait passes without a new message, max
+// messages arrive, or the server closes the connection. Pings are answered.
+func (c *wsClient) receive(wait time.Duration, maxMessages int) (msgs []WebSocketMessage, closed bool, err error) {
+	var message []byte
+	var messageOp byte
+	for len(msgs) < maxMessages {
+		_ = c.conn.SetReadDeadline(time.Now().Add(wait))
+		frame, err := readWSFrame(c.r)
+		if errors.Is(err, os.ErrDeadlineExceeded) {
+			return msgs, false, nil
+		} else if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, net.ErrClosed) {
+			return msgs, true, nil
+		} else if err != nil {
+			return msgs, false, err
+		}
+
+		switch frame.opcode {
+		case 8: // close
+			_ = c.send(8, frame.payload)
+			return msgs, true, nil
+		case 9: // ping
+			if err := c.send(10, frame.payload); err != nil {
+				return msgs, false, err
+			}
+			continue
+		case 10: // pong
+			continue
+		case 1, 2:
+			message, messageOp = nil, frame.opcode
+		case 0:
+			if messageOp == 0 {
+				continue // continuation without a start frame
+			}
+		default:
+			continue // reserved opcode
+		}
+		message = append(message, frame.payload...)
+		if !frame.fin {
+			continue
+		}
+
+		opName := "text"
+		if messageOp == 2 {
+			opName = "binary"
+		}
+		msgs = append(msgs, WebSocketMessage{
+			Direction: WSDirectionToClient,
+			Opcode:    opName,
+			Payload:   message,
+			Time:      time.Now(),
+		})
+		message, messageOp = nil, 0
+	}
+	return msgs, false, nil
+}
+
+// close sends a normal-closure frame and closes the connection.
+func (c *wsClient) close() {
+	_ = c.send(8, []byte{0x03, 0xe8}) // 1000
+	_ = c.conn.Close()
+}
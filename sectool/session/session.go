@@ -0,0 +1,172 @@
+package session
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/go-harden/llm-security-toolbox/sectool/cliutil"
+	"github.com/go-harden/llm-security-toolbox/sectool/mcpclient"
+	"github.com/go-harden/llm-security-toolbox/sectool/protocol"
+)
+
+var sessionColumns = []string{"name", "url", "extract", "inject", "logins", "logged_in_at"}
+
+var csrfColumns = []string{"host", "url", "extract", "param", "header", "fetched_at"}
+
+var jarColumns = []string{"host", "enabled", "cookie", "value", "path", "expires"}
+
+func list(mcpURL string, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	client, err := mcpclient.Connect(ctx, mcpURL)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = client.Close() }()
+
+	resp, err := client.SessionList(ctx)
+	if err != nil {
+		return fmt.Errorf("session list failed: %w", err)
+	}
+	printSessions(resp)
+	return nil
+}
+
+func set(mcpURL string, timeout time.Duration, opts mcpclient.SessionSetOpts) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	client, err := mcpclient.Connect(ctx, mcpURL)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = client.Close() }()
+
+	resp, err := client.SessionSet(ctx, opts)
+	if err != nil {
+		return fmt.Errorf("session set failed: %w", err)
+	}
+	printSessions(resp)
+	if !opts.Delete {
+		cliutil.Hintf("\nTo send with it: `sectool replay send --flow <flow_id> --session %s`\n", opts.Name)
+	}
+	return nil
+}
+
+func printSessions(resp *protocol.SessionListResponse) {
+	if len(resp.Sessions) == 0 {
+		fmt.Println("No sessions defined.")
+		cliutil.Hintf("\nTo create one: `sectool session set admin --flow <login_flow_id> --json access_token`\n")
+		return
+	}
+
+	t := cliutil.NewTable(os.Stdout, cliutil.FormatMarkdown, sessionColumns, sessionColumns)
+	t.Header()
+	for _, s := range resp.Sessions {
+		t.Row(s.Name, s.URL, s.Extract, s.Inject, strconv.Itoa(s.Logins), s.LoggedInAt)
+	}
+	t.Flush()
+	for _, s := range resp.Sessions {
+		if s.LastError != "" {
+			fmt.Printf("\n%s: last login failed: %s\n", s.Name, s.LastError)
+		}
+	}
+}
+
+func csrf(mcpURL string, timeout time.Duration, opts mcpclient.CSRFSetOpts) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	client, err := mcpclient.Connect(ctx, mcpURL)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = client.Close() }()
+
+	resp, err := client.CSRFSet(ctx, opts)
+	if err != nil {
+		return fmt.Errorf("session csrf failed: %w", err)
+	}
+	if len(resp.Rules) == 0 {
+		fmt.Println("No CSRF rules defined.")
+		cliutil.Hintf("\nTo find token sources: `sectool proxy csrf`\n")
+		return nil
+	}
+
+	t := cliutil.NewTable(os.Stdout, cliutil.FormatMarkdown, csrfColumns, csrfColumns)
+	t.Header()
+	for _, r := range resp.Rules {
+		t.Row(r.Host, r.URL, r.Extract, r.Param, r.Header, r.FetchedAt)
+	}
+	t.Flush()
+	for _, r := range resp.Rules {
+		if r.LastError != "" {
+			fmt.Printf("\n%s: last fetch failed: %s\n", r.Host, r.LastError)
+		}
+	}
+	if !opts.Delete {
+		cliutil.Hintf("\nTo send with a fresh token: `sectool replay send --flow <flow_id> --refresh-csrf`\n")
+	}
+	return nil
+}
+
+func jarList(mcpURL string, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	client, err := mcpclient.Connect(ctx, mcpURL)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = client.Close() }()
+
+	resp, err := client.CookieJarList(ctx)
+	if err != nil {
+		return fmt.Errorf("session jar failed: %w", err)
+	}
+	printJars(resp)
+	return nil
+}
+
+func jar(mcpURL string, timeout time.Duration, opts mcpclient.CookieJarSetOpts) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	client, err := mcpclient.Connect(ctx, mcpURL)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = client.Close() }()
+
+	resp, err := client.CookieJarSet(ctx, opts)
+	if err != nil {
+		return fmt.Errorf("session jar failed: %w", err)
+	}
+	printJars(resp)
+	return nil
+}
+
+func printJars(resp *protocol.CookieJarListResponse) {
+	if len(resp.Jars) == 0 {
+		fmt.Println("No cookie jars defined.")
+		cliutil.Hintf("\nTo create one: `sectool session jar <host>`\n")
+		return
+	}
+
+	t := cliutil.NewTable(os.Stdout, cliutil.FormatMarkdown, jarColumns, jarColumns)
+	t.Header()
+	for _, j := range resp.Jars {
+		enabled := strconv.FormatBool(j.Enabled)
+		if len(j.Cookies) == 0 {
+			t.Row(j.Host, enabled, "", "", "", "")
+		}
+		for _, c := range j.Cookies {
+			t.Row(j.Host, enabled, c.Name, c.Value, c.Path, c.Expires)
+		}
+	}
+	t.Flush()
+}
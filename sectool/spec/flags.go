@@ -0,0 +1,85 @@
+package spec
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/pflag"
+
+	"github.com/go-harden/llm-security-toolbox/sectool/cli"
+)
+
+var specSubcommands = []string{"import", "help"}
+
+func Parse(args []string, mcpURL string) error {
+	if len(args) < 1 {
+		printUsage()
+		return errors.New("subcommand required")
+	}
+
+	switch args[0] {
+	case "import":
+		return parseImport(args[1:], mcpURL)
+	case "help", "--help", "-h":
+		printUsage()
+		return nil
+	default:
+		return cli.UnknownSubcommandError("spec", args[0], specSubcommands)
+	}
+}
+
+func printUsage() {
+	_, _ = fmt.Fprint(os.Stderr, `Usage: sectool spec <command> [options]
+
+Import API specifications as request templates.
+
+---
+
+spec import <file|url> [options]
+
+  Parse an OpenAPI 2 (Swagger) or 3 document (JSON or YAML) and create a
+  request template with example values for each operation. Send a template
+  with 'sectool replay send --flow <flow_id>' or by its operationId.
+
+  Options:
+    --base-url <url>     server URL to target instead of the spec servers/host
+
+  Output: Markdown table with flow_id, method, path, operation_id, params
+
+  Examples:
+    sectool spec import openapi.yaml --base-url https://staging.example.com
+    sectool spec import https://example.com/swagger.json
+`)
+}
+
+func parseImport(args []string, mcpURL string) error {
+	fs := pflag.NewFlagSet("spec import", pflag.ContinueOnError)
+	fs.SetInterspersed(true)
+	var timeout time.Duration
+	var baseURL string
+
+	fs.DurationVar(&timeout, "timeout", time.Minute, "client-side timeout")
+	fs.StringVar(&baseURL, "base-url", "", "server URL to target (overrides the spec servers/host)")
+
+	fs.Usage = func() {
+		_, _ = fmt.Fprint(os.Stderr, `Usage: sectool spec import <file|url> [options]
+
+Import an OpenAPI 2/3 document as request templates.
+
+Options:
+`)
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return errors.New("exactly one spec file or URL is required")
+	}
+
+	return importSpec(mcpURL, timeout, fs.Arg(0), baseURL)
+}
@@ -0,0 +1,63 @@
+package spec
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/go-harden/llm-security-toolbox/sectool/cliutil"
+	"github.com/go-harden/llm-security-toolbox/sectool/mcpclient"
+)
+
+func importSpec(mcpURL string, timeout time.Duration, source, baseURL string) error {
+	opts := mcpclient.SpecImportOpts{BaseURL: baseURL}
+	if strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://") {
+		opts.Source = source
+	} else {
+		// Read locally so relative paths work regardless of the service working directory
+		data, err := os.ReadFile(source)
+		if err != nil {
+			return fmt.Errorf("read spec: %w", err)
+		}
+		opts.Content = string(data)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	client, err := mcpclient.Connect(ctx, mcpURL)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = client.Close() }()
+
+	resp, err := client.SpecImport(ctx, opts)
+	if err != nil {
+		return fmt.Errorf("spec import failed: %w", err)
+	}
+
+	defer cliutil.StartPager()()
+
+	title := resp.Title
+	if title == "" {
+		title = "Untitled spec"
+	}
+	fmt.Printf("## %s (OpenAPI %s)\n\nBase URL: `%s`\n\n", cliutil.EscapeMarkdown(title), resp.SpecVersion, resp.BaseURL)
+	if len(resp.Operations) == 0 {
+		fmt.Println("No operations found.")
+		return nil
+	}
+
+	fmt.Println("| flow_id | method | path | operation_id | params |")
+	fmt.Println("|---------|--------|------|--------------|--------|")
+	for _, op := range resp.Operations {
+		fmt.Printf("| %s | %s | %s | %s | %s |\n",
+			op.FlowID, op.Method, cliutil.EscapeMarkdown(op.Path), cliutil.EscapeMarkdown(op.OperationID),
+			cliutil.EscapeMarkdown(strings.Join(op.Params, ", ")))
+	}
+	fmt.Printf("\n*%d operation(s) imported*\n", len(resp.Operations))
+	cliutil.Hintf("\nTo send one: `sectool replay send --flow %s`\n", resp.Operations[0].FlowID)
+	return nil
+}
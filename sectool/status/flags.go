@@ -0,0 +1,35 @@
+package status
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/pflag"
+)
+
+func Parse(args []string, mcpURL string) error {
+	fs := pflag.NewFlagSet("status", pflag.ContinueOnError)
+	fs.SetInterspersed(true)
+	var timeout time.Duration
+
+	fs.DurationVar(&timeout, "timeout", 10*time.Second, "client-side timeout")
+
+	fs.Usage = func() {
+		_, _ = fmt.Fprint(os.Stderr, `Usage: sectool status [options]
+
+Summarize the project in the current directory: workflow mode, scope,
+saved requests, custom guides, and (when 'sectool mcp' is running) flows,
+replays, OAST and crawl sessions, backend health, and last activity.
+
+Options:
+`)
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	return status(mcpURL, timeout)
+}
@@ -0,0 +1,201 @@
+package status
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/go-harden/llm-security-toolbox/sectool/bundle"
+	"github.com/go-harden/llm-security-toolbox/sectool/cliutil"
+	"github.com/go-harden/llm-security-toolbox/sectool/config"
+	"github.com/go-harden/llm-security-toolbox/sectool/mcpclient"
+	"github.com/go-harden/llm-security-toolbox/sectool/protocol"
+)
+
+func status(mcpURL string, timeout time.Duration) error {
+	wd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("## Project")
+	fmt.Println()
+	fmt.Printf("Directory: `%s`\n", wd)
+	if err := printScope(wd); err != nil {
+		return err
+	}
+	lastSaved := printSavedRequests(wd)
+	printGuides(wd)
+	fmt.Println()
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	client, err := mcpclient.Connect(ctx, mcpURL)
+	if err != nil {
+		fmt.Println("## Service")
+		fmt.Println()
+		fmt.Println("Not running.")
+		if !lastSaved.IsZero() {
+			fmt.Printf("\nLast activity: %s (saved request)\n", lastSaved.Format(time.RFC3339))
+		}
+		cliutil.Hintf("\nStart it with `sectool mcp` in this directory.\n")
+		return nil
+	}
+	defer func() { _ = client.Close() }()
+
+	return printService(ctx, client, lastSaved)
+}
+
+func printScope(wd string) error {
+	scope, err := config.LoadScope(config.ProjectScopePath(wd))
+	if errors.Is(err, os.ErrNotExist) {
+		fmt.Println("Scope: not defined")
+		cliutil.Hintf("  Define it with `sectool config scope --target <url>`\n")
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	if len(scope.Targets) > 0 {
+		fmt.Printf("Targets: %s\n", codeList(scope.Targets))
+	}
+	fmt.Printf("Scope: %s\n", codeList(scope.Include))
+	if len(scope.Exclude) > 0 {
+		fmt.Printf("Excluded: %s\n", codeList(scope.Exclude))
+	}
+	return nil
+}
+
+// printSavedRequests reports exported bundles and returns when the newest was saved.
+func printSavedRequests(wd string) time.Time {
+	entries, err := os.ReadDir(filepath.Join(wd, bundle.DefaultDir))
+	if err != nil {
+		fmt.Println("Saved requests: 0")
+		return time.Time{}
+	}
+
+	var count int
+	var newest time.Time
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		count++
+		if info, err := entry.Info(); err == nil && info.ModTime().After(newest) {
+			newest = info.ModTime()
+		}
+	}
+	fmt.Printf("Saved requests: %d (./%s)\n", count, bundle.DefaultDir)
+	return newest
+}
+
+func printGuides(wd string) {
+	matches, _ := filepath.Glob(filepath.Join(wd, config.ProjectDirName, "guides", "*.md"))
+	if len(matches) == 0 {
+		return
+	}
+	tasks := make([]string, len(matches))
+	for i, m := range matches {
+		tasks[i] = strings.TrimSuffix(filepath.Base(m), ".md")
+	}
+	fmt.Printf("Project guides: %s\n", codeList(tasks))
+}
+
+func printService(ctx context.Context, client *mcpclient.Client, lastSaved time.Time) error {
+	resp, err := client.Status(ctx)
+	if err != nil {
+		return fmt.Errorf("status failed: %w", err)
+	}
+
+	fmt.Println("## Service")
+	fmt.Println()
+	fmt.Printf("Version: %s, up %s\n", resp.Version, resp.Uptime)
+	workflowMode := resp.WorkflowMode
+	if workflowMode == "" {
+		workflowMode = "selected by agent"
+	}
+	fmt.Printf("Workflow mode: `%s`\n", workflowMode)
+	for _, b := range resp.Backends {
+		health := "healthy"
+		if !b.Healthy {
+			health = "unhealthy: " + b.Error
+		}
+		fmt.Printf("Backend %s: %s (%s)\n", b.Role, b.Name, health)
+	}
+	if resp.MaxOutputBytes > 0 {
+		fmt.Printf("Max output: %d bytes per agent tool result\n", resp.MaxOutputBytes)
+	}
+	if resp.RateLimit != nil {
+		fmt.Printf("Rate limit: %s\n", rateLimitSummary(resp.RateLimit))
+	}
+	fmt.Println()
+
+	fmt.Println("| item | count |")
+	fmt.Println("|------|-------|")
+	for _, m := range []struct{ key, label string }{
+		{"flows", "proxy flows seen"},
+		{"requests", "replays"},
+		{"oast_sessions", "OAST sessions"},
+		{"crawl_sessions", "crawl sessions"},
+		{"crawl_flows", "crawled flows"},
+	} {
+		if v, ok := resp.Metrics[m.key]; ok {
+			fmt.Printf("| %s | %s |\n", m.label, v)
+		}
+	}
+	fmt.Println()
+
+	last, lastWhat := lastSaved, "saved request"
+	if history, err := client.ReplayHistory(ctx, 1); err == nil && len(history.Replays) > 0 {
+		r := history.Replays[0]
+		if created, err := time.Parse(time.RFC3339, r.CreatedAt); err == nil && created.After(last) {
+			last, lastWhat = created, fmt.Sprintf("replay `%s` %s %s", r.ReplayID, r.Method, r.URL)
+		}
+	}
+	if oast, err := client.OastList(ctx, 1); err == nil && len(oast.Sessions) > 0 {
+		s := oast.Sessions[0]
+		if created, err := time.Parse(time.RFC3339, s.CreatedAt); err == nil && created.After(last) {
+			last, lastWhat = created, fmt.Sprintf("OAST session `%s` created", s.OastID)
+		}
+	}
+	if !last.IsZero() {
+		fmt.Printf("Last activity: %s (%s)\n", last.Local().Format(time.RFC3339), lastWhat)
+	}
+	if v := resp.Metrics["update_available"]; v != "" {
+		cliutil.Hintf("\nUpdate available: %s (`sectool update`)\n", v)
+	}
+	return nil
+}
+
+func rateLimitSummary(rl *protocol.RateLimitStatus) string {
+	var parts []string
+	if rl.RequestsPerSecond > 0 {
+		parts = append(parts, fmt.Sprintf("%g req/s", rl.RequestsPerSecond))
+	}
+	if rl.HostRequestsPerSecond > 0 {
+		parts = append(parts, fmt.Sprintf("%g req/s per host", rl.HostRequestsPerSecond))
+	}
+	if rl.MaxConcurrent > 0 {
+		parts = append(parts, fmt.Sprintf("%d concurrent", rl.MaxConcurrent))
+	}
+	if rl.HostMaxConcurrent > 0 {
+		parts = append(parts, fmt.Sprintf("%d concurrent per host", rl.HostMaxConcurrent))
+	}
+	if rl.JitterMS > 0 {
+		parts = append(parts, fmt.Sprintf("up to %dms jitter", rl.JitterMS))
+	}
+	return strings.Join(parts, ", ")
+}
+
+func codeList(items []string) string {
+	quoted := make([]string, len(items))
+	for i, item := range items {
+		quoted[i] = "`" + cliutil.EscapeMarkdown(item) + "`"
+	}
+	return strings.Join(quoted, ", ")
+}
@@ -0,0 +1,58 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/pflag"
+)
+
+func Parse(args []string, mcpURL string) error {
+	fs := pflag.NewFlagSet("ui", pflag.ContinueOnError)
+	fs.SetInterspersed(true)
+	var opts options
+
+	fs.DurationVar(&opts.timeout, "timeout", 30*time.Second, "client-side timeout per request")
+	fs.DurationVar(&opts.interval, "interval", 2*time.Second, "proxy history poll interval")
+	fs.StringVar(&opts.host, "host", "", "only show flows for hosts matching pattern (glob: *, ?)")
+	fs.StringVar(&opts.excludeHost, "exclude-host", "", "hide flows for hosts matching pattern")
+
+	fs.Usage = func() {
+		_, _ = fmt.Fprint(os.Stderr, `Usage: sectool ui [options]
+
+Interactive terminal UI for live proxy history.
+
+Shows the same flows the agent sees via proxy_poll, refreshed continuously.
+Select a flow to view the full request and response, or replay it with a
+single keystroke.
+
+Keys (list):
+  up/down, k/j       move selection
+  pgup/pgdown        move by page
+  g/G                jump to first/last flow
+  /                  filter flows (matches method, host, path, status)
+  enter              open flow detail
+  r                  replay selected flow (replay_send)
+  f                  toggle follow mode (auto-select newest flow)
+  q, ctrl+c          quit
+
+Keys (detail):
+  up/down, k/j       scroll
+  r                  replay flow and show result
+  esc, backspace     back to list
+
+Options:
+`)
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if opts.interval < 250*time.Millisecond {
+		opts.interval = 250 * time.Millisecond
+	}
+
+	return run(mcpURL, opts)
+}
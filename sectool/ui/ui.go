@@ -0,0 +1,501 @@
+package ui
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/go-harden/llm-security-toolbox/sectool/mcpclient"
+	"github.com/go-harden/llm-security-toolbox/sectool/protocol"
+)
+
+// pageSize is the number of flows requested per proxy_poll call.
+const pageSize = 500
+
+// maxBodyDisplay caps decoded body bytes rendered in the detail pane.
+const maxBodyDisplay = 64 * 1024
+
+type options struct {
+	timeout     time.Duration
+	interval    time.Duration
+	host        string
+	excludeHost string
+}
+
+type viewMode int
+
+const (
+	viewList viewMode = iota
+	viewDetail
+)
+
+type (
+	tickMsg  struct{}
+	flowsMsg struct {
+		flows []protocol.FlowEntry
+		err   error
+	}
+	detailMsg struct {
+		flow *protocol.ProxyGetResponse
+		err  error
+	}
+	replayMsg struct {
+		flowID string
+		resp   *protocol.ReplaySendResponse
+		err    error
+	}
+)
+
+// model is the bubbletea model for the proxy history UI.
+type model struct {
+	client *mcpclient.Client
+	opts   options
+
+	flows    []protocol.FlowEntry
+	seen     map[string]bool
+	lastFlow string
+	fetching bool
+
+	mode      viewMode
+	cursor    int // index into visible flows
+	top       int // first visible row in list view
+	follow    bool
+	filter    string
+	filtering bool
+
+	detail       *protocol.ProxyGetResponse
+	detailLines  []string
+	detailScroll int
+	replay       *protocol.ReplaySendResponse
+
+	status        string
+	width, height int
+}
+
+func run(mcpURL string, opts options) error {
+	ctx, cancel := context.WithTimeout(context.Background(), opts.timeout)
+	client, err := mcpclient.Connect(ctx, mcpURL)
+	cancel()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = client.Close() }()
+
+	m := model{
+		client: client,
+		opts:   opts,
+		seen:   make(map[string]bool),
+		follow: true,
+		status: "loading proxy history...",
+	}
+	_, err = tea.NewProgram(m, tea.WithAltScreen()).Run()
+	return err
+}
+
+func (m model) Init() tea.Cmd {
+	return m.fetchFlows()
+}
+
+func (m model) fetchFlows() tea.Cmd {
+	client, opts, since := m.client, m.opts, m.lastFlow
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), opts.timeout)
+		defer cancel()
+
+		var flows []protocol.FlowEntry
+		for offset := 0; ; offset += pageSize {
+			resp, err := client.ProxyPoll(ctx, mcpclient.ProxyPollOpts{
+				OutputMode:  "flows",
+				Host:        opts.host,
+				ExcludeHost: opts.excludeHost,
+				Since:       since,
+				Limit:       pageSize,
+				Offset:      offset,
+			})
+			if err != nil {
+				return flowsMsg{err: err}
+			}
+			flows = append(flows, resp.Flows...)
+			if len(resp.Flows) < pageSize {
+				break
+			}
+		}
+		return flowsMsg{flows: flows}
+	}
+}
+
+func (m model) fetchDetail(flowID string) tea.Cmd {
+	client, timeout := m.client, m.opts.timeout
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		resp, err := client.ProxyGet(ctx, flowID)
+		return detailMsg{flow: resp, err: err}
+	}
+}
+
+func (m model) sendReplay(flowID string) tea.Cmd {
+	client, timeout := m.client, m.opts.timeout
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		resp, err := client.ReplaySend(ctx, mcpclient.ReplaySendOpts{FlowID: flowID})
+		return replayMsg{flowID: flowID, resp: resp, err: err}
+	}
+}
+
+func (m model) scheduleTick() tea.Cmd {
+	return tea.Tick(m.opts.interval, func(time.Time) tea.Msg { return tickMsg{} })
+}
+
+func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+		m.clampList()
+		return m, nil
+
+	case tickMsg:
+		if m.fetching {
+			return m, m.scheduleTick()
+		}
+		m.fetching = true
+		return m, m.fetchFlows()
+
+	case flowsMsg:
+		m.fetching = false
+		if msg.err != nil {
+			m.status = "poll failed: " + msg.err.Error()
+			return m, m.scheduleTick()
+		}
+		m.appendFlows(msg.flows)
+		if m.status == "loading proxy history..." {
+			m.status = ""
+		}
+		return m, m.scheduleTick()
+
+	case detailMsg:
+		if msg.err != nil {
+			m.status = "get failed: " + msg.err.Error()
+			return m, nil
+		}
+		m.detail = msg.flow
+		m.replay = nil
+		m.detailScroll = 0
+		m.detailLines = renderDetail(m.detail, nil)
+		m.mode = viewDetail
+		return m, nil
+
+	case replayMsg:
+		if msg.err != nil {
+			m.status = "replay failed: " + msg.err.Error()
+			return m, nil
+		}
+		m.status = fmt.Sprintf("replay %s: %d %s (%d bytes, %s)",
+			msg.resp.ReplayID, msg.resp.Status, msg.resp.StatusLine, msg.resp.RespSize, msg.resp.Duration)
+		if m.mode == viewDetail && m.detail != nil && m.detail.FlowID == msg.flowID {
+			m.replay = msg.resp
+			m.detailLines = renderDetail(m.detail, m.replay)
+		}
+		return m, nil
+
+	case tea.KeyMsg:
+		if m.filtering {
+			return m.updateFilter(msg)
+		} else if m.mode == viewDetail {
+			return m.updateDetail(msg)
+		}
+		return m.updateList(msg)
+	}
+	return m, nil
+}
+
+func (m model) updateFilter(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEnter:
+		m.filtering = false
+	case tea.KeyEsc:
+		m.filtering = false
+		m.filter = ""
+	case tea.KeyBackspace:
+		if m.filter != "" {
+			_, size := utf8.DecodeLastRuneInString(m.filter)
+			m.filter = m.filter[:len(m.filter)-size]
+		}
+	case tea.KeyCtrlC:
+		return m, tea.Quit
+	case tea.KeyRunes, tea.KeySpace:
+		m.filter += string(msg.Runes)
+	}
+	m.cursor = 0
+	m.top = 0
+	m.clampList()
+	return m, nil
+}
+
+func (m model) updateList(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	visible := m.visibleFlows()
+	switch msg.String() {
+	case "q", "ctrl+c":
+		return m, tea.Quit
+	case "up", "k":
+		m.cursor--
+		m.follow = false
+	case "down", "j":
+		m.cursor++
+	case "pgup":
+		m.cursor -= m.listRows()
+		m.follow = false
+	case "pgdown":
+		m.cursor += m.listRows()
+	case "g", "home":
+		m.cursor = 0
+		m.follow = false
+	case "G", "end":
+		m.cursor = len(visible) - 1
+	case "f":
+		m.follow = !m.follow
+		if m.follow {
+			m.cursor = len(visible) - 1
+		}
+	case "/":
+		m.filtering = true
+	case "enter":
+		if f, ok := m.selected(); ok {
+			m.status = "loading " + f.FlowID + "..."
+			return m, m.fetchDetail(f.FlowID)
+		}
+	case "r":
+		if f, ok := m.selected(); ok {
+			m.status = "replaying " + f.FlowID + "..."
+			return m, m.sendReplay(f.FlowID)
+		}
+	}
+	m.clampList()
+	return m, nil
+}
+
+func (m model) updateDetail(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	rows := m.height - 2
+	switch msg.String() {
+	case "q", "ctrl+c":
+		return m, tea.Quit
+	case "esc", "backspace":
+		m.mode = viewList
+		m.detail = nil
+		m.replay = nil
+		return m, nil
+	case "up", "k":
+		m.detailScroll--
+	case "down", "j":
+		m.detailScroll++
+	case "pgup":
+		m.detailScroll -= rows
+	case "pgdown", " ":
+		m.detailScroll += rows
+	case "g", "home":
+		m.detailScroll = 0
+	case "G", "end":
+		m.detailScroll = len(m.detailLines) - rows
+	case "r":
+		if m.detail != nil {
+			m.status = "replaying " + m.detail.FlowID + "..."
+			return m, m.sendReplay(m.detail.FlowID)
+		}
+	}
+	m.detailScroll = max(0, min(m.detailScroll, len(m.detailLines)-rows))
+	return m, nil
+}
+
+// appendFlows adds newly polled flows, skipping ones already displayed.
+func (m *model) appendFlows(flows []protocol.FlowEntry) {
+	for _, f := range flows {
+		if m.seen[f.FlowID] {
+			continue
+		}
+		m.seen[f.FlowID] = true
+		m.flows = append(m.flows, f)
+		m.lastFlow = f.FlowID
+	}
+	if m.follow {
+		m.cursor = len(m.visibleFlows()) - 1
+	}
+	m.clampList()
+}
+
+func (m *model) visibleFlows() []protocol.FlowEntry {
+	if m.filter == "" {
+		return m.flows
+	}
+	var result []protocol.FlowEntry
+	for _, f := range m.flows {
+		if matchesFilter(f, m.filter) {
+			result = append(result, f)
+		}
+	}
+	return result
+}
+
+func (m *model) selected() (protocol.FlowEntry, bool) {
+	visible := m.visibleFlows()
+	if m.cursor < 0 || m.cursor >= len(visible) {
+		return protocol.FlowEntry{}, false
+	}
+	return visible[m.cursor], true
+}
+
+// listRows returns the number of flow rows that fit on screen.
+func (m *model) listRows() int {
+	return max(1, m.height-4) // title, column header, status, help
+}
+
+func (m *model) clampList() {
+	count := len(m.visibleFlows())
+	m.cursor = max(0, min(m.cursor, count-1))
+	rows := m.listRows()
+	if m.cursor < m.top {
+		m.top = m.cursor
+	} else if m.cursor >= m.top+rows {
+		m.top = m.cursor - rows + 1
+	}
+	m.top = max(0, min(m.top, count-rows))
+}
+
+// matchesFilter reports whether a flow matches all space-separated terms (case-insensitive).
+func matchesFilter(f protocol.FlowEntry, filter string) bool {
+	haystack := strings.ToLower(f.Method + " " + f.Host + " " + f.Path + " " + strconv.Itoa(f.Status))
+	for _, term := range strings.Fields(strings.ToLower(filter)) {
+		if !strings.Contains(haystack, term) {
+			return false
+		}
+	}
+	return true
+}
+
+func (m model) View() string {
+	if m.width == 0 {
+		return "loading..."
+	}
+	if m.mode == viewDetail {
+		return m.viewDetail()
+	}
+	return m.viewList()
+}
+
+func (m model) viewList() string {
+	visible := m.visibleFlows()
+	var b strings.Builder
+
+	title := fmt.Sprintf("sectool ui - %d flows", len(m.flows))
+	if m.filter != "" {
+		title += fmt.Sprintf(" (%d shown, filter: %s)", len(visible), m.filter)
+	}
+	if m.follow {
+		title += " [follow]"
+	}
+	b.WriteString(fitLine(title, m.width) + "\n")
+	b.WriteString(fitLine(fmt.Sprintf("%-8s %-7s %-6s %-8s %-28s %s", "flow_id", "method", "status", "size", "host", "path"), m.width) + "\n")
+
+	rows := m.listRows()
+	for i := m.top; i < m.top+rows; i++ {
+		if i >= len(visible) {
+			b.WriteString("\n")
+			continue
+		}
+		line := fitLine(formatFlowLine(visible[i]), m.width)
+		if i == m.cursor {
+			line = "\x1b[7m" + padRight(line, m.width) + "\x1b[0m"
+		}
+		b.WriteString(line + "\n")
+	}
+
+	b.WriteString(fitLine(m.status, m.width) + "\n")
+	if m.filtering {
+		b.WriteString(fitLine("filter: "+m.filter+"_  (enter: apply, esc: clear)", m.width))
+	} else {
+		b.WriteString(fitLine("enter: detail  r: replay  /: filter  f: follow  q: quit", m.width))
+	}
+	return b.String()
+}
+
+func (m model) viewDetail() string {
+	var b strings.Builder
+	rows := m.height - 2
+	end := min(len(m.detailLines), m.detailScroll+rows)
+	for i := m.detailScroll; i < m.detailScroll+rows; i++ {
+		if i < end {
+			b.WriteString(fitLine(m.detailLines[i], m.width))
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString(fitLine(m.status, m.width) + "\n")
+	b.WriteString(fitLine("esc: back  r: replay  up/down: scroll  q: quit", m.width))
+	return b.String()
+}
+
+func formatFlowLine(f protocol.FlowEntry) string {
+	return fmt.Sprintf("%-8s %-7s %-6d %-8d %-28s %s", f.FlowID, f.Method, f.Status, f.ResponseLength, f.Host, f.Path)
+}
+
+// renderDetail builds the scrollable text for a flow and optional replay result.
+func renderDetail(flow *protocol.ProxyGetResponse, replay *protocol.ReplaySendResponse) []string {
+	var b strings.Builder
+	_, _ = fmt.Fprintf(&b, "Flow %s  %s %s\n\n", flow.FlowID, flow.Method, flow.URL)
+	b.WriteString("--- Request ---\n")
+	b.WriteString(strings.TrimRight(flow.ReqHeaders, "\r\n") + "\n\n")
+	b.WriteString(decodeBody(flow.ReqBody) + "\n")
+	_, _ = fmt.Fprintf(&b, "--- Response (%d bytes) ---\n", flow.RespSize)
+	b.WriteString(strings.TrimRight(flow.RespHeaders, "\r\n") + "\n\n")
+	b.WriteString(decodeBody(flow.RespBody) + "\n")
+	if replay != nil {
+		_, _ = fmt.Fprintf(&b, "--- Replay %s (%s) ---\n", replay.ReplayID, replay.Duration)
+		b.WriteString(strings.TrimRight(replay.RespHeaders, "\r\n") + "\n\n")
+		b.WriteString(replay.RespPreview + "\n")
+	}
+	return strings.Split(strings.ReplaceAll(b.String(), "\r", ""), "\n")
+}
+
+// decodeBody decodes a base64 body from proxy_get, substituting a placeholder for binary content.
+func decodeBody(encoded string) string {
+	if encoded == "" {
+		return ""
+	}
+	body, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return "(failed to decode body: " + err.Error() + ")"
+	} else if !utf8.Valid(body) {
+		return "<BINARY:" + strconv.Itoa(len(body)) + " Bytes>"
+	} else if len(body) > maxBodyDisplay {
+		return string(body[:maxBodyDisplay]) + "\n... (truncated, " + strconv.Itoa(len(body)) + " bytes total)"
+	}
+	return string(body)
+}
+
+// fitLine truncates s to width runes, replacing tabs and control characters.
+func fitLine(s string, width int) string {
+	s = strings.Map(func(r rune) rune {
+		if r == '\t' {
+			return ' '
+		} else if r < 0x20 || r == 0x7f {
+			return -1
+		}
+		return r
+	}, s)
+	if width <= 0 || utf8.RuneCountInString(s) <= width {
+		return s
+	}
+	return string([]rune(s)[:width])
+}
+
+func padRight(s string, width int) string {
+	if n := utf8.RuneCountInString(s); n < width {
+		return s + strings.Repeat(" ", width-n)
+	}
+	return s
+}
@@ -0,0 +1,124 @@
+package ui
+
+import (
+	"encoding/base64"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/go-harden/llm-security-toolbox/sectool/protocol"
+)
+
+func TestMatchesFilter(t *testing.T) {
+	t.Parallel()
+
+	flow := protocol.FlowEntry{Method: "POST", Host: "api.example.com", Path: "/v1/login", Status: 401}
+
+	tests := []struct {
+		name   string
+		filter string
+		want   bool
+	}{
+		{"empty_filter", "", true},
+		{"method_match", "post", true},
+		{"host_and_path", "example /login", true},
+		{"status_match", "401", true},
+		{"one_term_misses", "post /logout", false},
+		{"no_match", "delete", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, matchesFilter(flow, tt.filter))
+		})
+	}
+}
+
+func TestDecodeBody(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		encoded string
+		want    string
+	}{
+		{"empty", "", ""},
+		{"text", base64.StdEncoding.EncodeToString([]byte("hello")), "hello"},
+		{"binary", base64.StdEncoding.EncodeToString([]byte{0xff, 0xfe, 0x00}), "<BINARY:3 Bytes>"},
+		{"invalid_base64", "!!!", "(failed to decode body: illegal base64 data at input byte 0)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, decodeBody(tt.encoded))
+		})
+	}
+}
+
+func TestFitLine(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "abc", fitLine("abcdef", 3))
+	assert.Equal(t, "a b", fitLine("a\tb", 10))
+	assert.Equal(t, "ab", fitLine("a\x1bb", 10))
+	assert.Equal(t, "héll", fitLine("héllo", 4))
+}
+
+func TestModelUpdate(t *testing.T) {
+	t.Parallel()
+
+	newModel := func() model {
+		return model{
+			opts:   options{interval: 1},
+			seen:   make(map[string]bool),
+			follow: true,
+			width:  80,
+			height: 10,
+		}
+	}
+	flows := []protocol.FlowEntry{
+		{FlowID: "a1", Method: "GET", Host: "example.com", Path: "/"},
+		{FlowID: "b2", Method: "POST", Host: "example.com", Path: "/login"},
+	}
+
+	t.Run("appends_and_dedupes", func(t *testing.T) {
+		updated, _ := newModel().Update(flowsMsg{flows: flows})
+		updated, _ = updated.Update(flowsMsg{flows: flows})
+		m := updated.(model)
+
+		require.Len(t, m.flows, 2)
+		assert.Equal(t, "b2", m.lastFlow)
+		assert.Equal(t, 1, m.cursor)
+	})
+
+	t.Run("follow_disabled_keeps_cursor", func(t *testing.T) {
+		m := newModel()
+		m.follow = false
+		updated, _ := m.Update(flowsMsg{flows: flows})
+
+		assert.Equal(t, 0, updated.(model).cursor)
+	})
+
+	t.Run("filter_narrows_selection", func(t *testing.T) {
+		updated, _ := newModel().Update(flowsMsg{flows: flows})
+		updated, _ = updated.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("/")})
+		updated, _ = updated.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("login")})
+		updated, _ = updated.Update(tea.KeyMsg{Type: tea.KeyEnter})
+		m := updated.(model)
+
+		assert.False(t, m.filtering)
+		f, ok := m.selected()
+		require.True(t, ok)
+		assert.Equal(t, "b2", f.FlowID)
+	})
+
+	t.Run("cursor_clamped", func(t *testing.T) {
+		updated, _ := newModel().Update(flowsMsg{flows: flows})
+		updated, _ = updated.Update(tea.KeyMsg{Type: tea.KeyDown})
+		updated, _ = updated.Update(tea.KeyMsg{Type: tea.KeyDown})
+
+		assert.Equal(t, 1, updated.(model).cursor)
+	})
+}
@@ -0,0 +1,81 @@
+package update
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"runtime"
+	"time"
+
+	"github.com/spf13/pflag"
+
+	"github.com/go-harden/llm-security-toolbox/sectool/cliutil"
+	"github.com/go-harden/llm-security-toolbox/sectool/config"
+)
+
+func Parse(args []string) error {
+	fs := pflag.NewFlagSet("update", pflag.ContinueOnError)
+	fs.SetInterspersed(true)
+	var timeout time.Duration
+	var check, force bool
+
+	fs.DurationVar(&timeout, "timeout", 2*time.Minute, "timeout for release check and download")
+	fs.BoolVar(&check, "check", false, "only report whether a newer version is available")
+	fs.BoolVar(&force, "force", false, "reinstall even if already on the latest version")
+
+	fs.Usage = func() {
+		_, _ = fmt.Fprint(os.Stderr, `Usage: sectool update [options]
+
+Check GitHub for the latest sectool release and replace the running binary.
+The downloaded binary is verified against the release checksums.txt (SHA-256)
+before it is installed; releases without checksums are refused.
+
+Restart 'sectool mcp' after updating.
+
+Options:
+`)
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	return run(timeout, check, force)
+}
+
+func run(timeout time.Duration, check, force bool) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	rel, err := Latest(ctx)
+	if err != nil {
+		return err
+	}
+
+	newer := IsNewer(rel.Version(), config.Version)
+	if !newer && (check || !force) {
+		fmt.Printf("sectool %s is up to date (latest release: %s)\n", config.Version, rel.TagName)
+		return nil
+	} else if check {
+		fmt.Printf("New version available: %s (current: %s)\n", rel.TagName, config.Version)
+		if rel.HTMLURL != "" {
+			fmt.Printf("Release notes: %s\n", rel.HTMLURL)
+		}
+		cliutil.Hintf("Run `sectool update` to install.\n")
+		return nil
+	}
+
+	data, err := Download(ctx, rel, runtime.GOOS, runtime.GOARCH)
+	if err != nil {
+		return err
+	}
+	path, err := Install(data)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Updated %s to %s (checksum verified)\n", path, rel.TagName)
+	cliutil.Hintf("Restart `sectool mcp` to use the new version.\n")
+	return nil
+}
@@ -0,0 +1,221 @@
+package update
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/go-harden/llm-security-toolbox/sectool/config"
+)
+
+const (
+	checksumsAsset  = "checksums.txt"
+	maxBinaryBytes  = 200 << 20
+	maxMetaBytes    = 1 << 20
+	defaultReleases = "https://api.github.com/repos/go-harden/llm-security-toolbox/releases/latest"
+)
+
+// latestReleaseURL is a var so tests can point it at a local server.
+var latestReleaseURL = defaultReleases
+
+// Release is the subset of the GitHub release API used for updates.
+type Release struct {
+	TagName string  `json:"tag_name"`
+	HTMLURL string  `json:"html_url"`
+	Assets  []Asset `json:"assets"`
+}
+
+type Asset struct {
+	Name string `json:"name"`
+	URL  string `json:"browser_download_url"`
+}
+
+// Version returns the release tag without a leading "v".
+func (r *Release) Version() string {
+	return strings.TrimPrefix(r.TagName, "v")
+}
+
+// Latest fetches the latest published release.
+func Latest(ctx context.Context) (*Release, error) {
+	body, err := fetch(ctx, latestReleaseURL, maxMetaBytes)
+	if err != nil {
+		return nil, fmt.Errorf("check latest release: %w", err)
+	}
+
+	var rel Release
+	if err := json.Unmarshal(body, &rel); err != nil {
+		return nil, fmt.Errorf("parse release: %w", err)
+	} else if rel.TagName == "" {
+		return nil, errors.New("release has no tag")
+	}
+	return &rel, nil
+}
+
+// IsNewer reports whether version latest is greater than current (dotted numeric, optional "v").
+func IsNewer(latest, current string) bool {
+	l := parseVersion(latest)
+	c := parseVersion(current)
+	for i := 0; i < max(len(l), len(c)); i++ {
+		var lv, cv int
+		if i < len(l) {
+			lv = l[i]
+		}
+		if i < len(c) {
+			cv = c[i]
+		}
+		if lv != cv {
+			return lv > cv
+		}
+	}
+	return false
+}
+
+func parseVersion(v string) []int {
+	v = strings.TrimPrefix(strings.TrimSpace(v), "v")
+	if i := strings.IndexAny(v, "-+"); i >= 0 {
+		v = v[:i]
+	}
+	var parts []int
+	for _, p := range strings.Split(v, ".") {
+		n, _ := strconv.Atoi(p)
+		parts = append(parts, n)
+	}
+	return parts
+}
+
+// AssetName returns the release binary name for a platform (matches `make build-cross`).
+func AssetName(goos, goarch string) string {
+	name := "sectool-" + goos + "-" + goarch
+	if goos == "windows" {
+		name += ".exe"
+	}
+	return name
+}
+
+// Download fetches the platform binary and verifies it against the release checksums.
+func Download(ctx context.Context, rel *Release, goos, goarch string) ([]byte, error) {
+	name := AssetName(goos, goarch)
+	binURL := rel.assetURL(name)
+	if binURL == "" {
+		return nil, fmt.Errorf("release %s has no binary for %s/%s", rel.TagName, goos, goarch)
+	}
+	sumsURL := rel.assetURL(checksumsAsset)
+	if sumsURL == "" {
+		return nil, fmt.Errorf("release %s has no %s; refusing unverified update", rel.TagName, checksumsAsset)
+	}
+
+	sums, err := fetch(ctx, sumsURL, maxMetaBytes)
+	if err != nil {
+		return nil, fmt.Errorf("download checksums: %w", err)
+	}
+	want, ok := parseChecksums(sums)[name]
+	if !ok {
+		return nil, fmt.Errorf("%s does not list %s", checksumsAsset, name)
+	}
+
+	data, err := fetch(ctx, binURL, maxBinaryBytes)
+	if err != nil {
+		return nil, fmt.Errorf("download binary: %w", err)
+	}
+	sum := sha256.Sum256(data)
+	if got := hex.EncodeToString(sum[:]); !strings.EqualFold(got, want) {
+		return nil, fmt.Errorf("checksum mismatch for %s: got %s, want %s", name, got, want)
+	}
+	return data, nil
+}
+
+// Install replaces the running executable with data.
+func Install(data []byte) (string, error) {
+	exe, err := os.Executable()
+	if err != nil {
+		return "", fmt.Errorf("locate executable: %w", err)
+	}
+	if resolved, err := filepath.EvalSymlinks(exe); err == nil {
+		exe = resolved
+	}
+	return exe, installTo(exe, data)
+}
+
+// installTo writes data beside path then renames over it so a failure never leaves a partial binary.
+func installTo(path string, data []byte) error {
+	mode := os.FileMode(0755)
+	if info, err := os.Stat(path); err == nil {
+		mode = info.Mode().Perm()
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".sectool-update-*")
+	if err != nil {
+		return fmt.Errorf("create temp file: %w", err)
+	}
+	tmpPath := tmp.Name()
+	defer func() { _ = os.Remove(tmpPath) }()
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("write binary: %w", err)
+	} else if err := tmp.Close(); err != nil {
+		return fmt.Errorf("write binary: %w", err)
+	} else if err := os.Chmod(tmpPath, mode); err != nil {
+		return fmt.Errorf("chmod binary: %w", err)
+	} else if err := os.Rename(tmpPath, path); err != nil {
+		return fmt.Errorf("replace binary: %w", err)
+	}
+	return nil
+}
+
+func (r *Release) assetURL(name string) string {
+	for _, a := range r.Assets {
+		if a.Name == name {
+			return a.URL
+		}
+	}
+	return ""
+}
+
+// parseChecksums parses `sha256sum` output into name -> hex digest.
+func parseChecksums(data []byte) map[string]string {
+	sums := make(map[string]string)
+	for _, line := range bytes.Split(data, []byte("\n")) {
+		fields := strings.Fields(string(line))
+		if len(fields) != 2 {
+			continue
+		}
+		sums[strings.TrimPrefix(fields[1], "*")] = fields[0]
+	}
+	return sums
+}
+
+func fetch(ctx context.Context, url string, limit int64) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", config.UserAgent())
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GET %s: %s", url, resp.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, limit+1))
+	if err != nil {
+		return nil, err
+	} else if int64(len(data)) > limit {
+		return nil, fmt.Errorf("GET %s: response exceeds %d bytes", url, limit)
+	}
+	return data, nil
+}
@@ -0,0 +1,132 @@
+package update
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsNewer(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		latest  string
+		current string
+		want    bool
+	}{
+		{"patch_bump", "v0.0.2", "0.0.1", true},
+		{"same", "v0.0.1", "0.0.1", false},
+		{"older", "0.0.9", "0.1.0", false},
+		{"minor_beats_patch", "0.2.0", "0.1.9", true},
+		{"double_digit", "0.0.10", "0.0.9", true},
+		{"extra_component", "1.0.0.1", "1.0.0", true},
+		{"prerelease_suffix_ignored", "v0.0.1-rc1", "0.0.1", false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tc.want, IsNewer(tc.latest, tc.current))
+		})
+	}
+}
+
+func TestParseChecksums(t *testing.T) {
+	t.Parallel()
+
+	sums := parseChecksums([]byte("abc123  sectool-linux-amd64\ndef456 *sectool-darwin-arm64\n\nbad line here\n"))
+	assert.Equal(t, map[string]string{
+		"sectool-linux-amd64":  "abc123",
+		"sectool-darwin-arm64": "def456",
+	}, sums)
+}
+
+func TestDownload(t *testing.T) {
+	t.Parallel()
+
+	binary := []byte("new sectool binary")
+	sum := sha256.Sum256(binary)
+	goodSums := hex.EncodeToString(sum[:]) + "  sectool-linux-amd64\n"
+
+	newRelease := func(t *testing.T, sums string) *Release {
+		t.Helper()
+		mux := http.NewServeMux()
+		mux.HandleFunc("/bin", func(w http.ResponseWriter, r *http.Request) { _, _ = w.Write(binary) })
+		mux.HandleFunc("/sums", func(w http.ResponseWriter, r *http.Request) { _, _ = w.Write([]byte(sums)) })
+		srv := httptest.NewServer(mux)
+		t.Cleanup(srv.Close)
+		return &Release{
+			TagName: "v9.9.9",
+			Assets: []Asset{
+				{Name: "sectool-linux-amd64", URL: srv.URL + "/bin"},
+				{Name: checksumsAsset, URL: srv.URL + "/sums"},
+			},
+		}
+	}
+
+	t.Run("verified", func(t *testing.T) {
+		data, err := Download(t.Context(), newRelease(t, goodSums), "linux", "amd64")
+		require.NoError(t, err)
+		assert.Equal(t, binary, data)
+	})
+
+	t.Run("checksum_mismatch", func(t *testing.T) {
+		_, err := Download(t.Context(), newRelease(t, "0000  sectool-linux-amd64\n"), "linux", "amd64")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "checksum mismatch")
+	})
+
+	t.Run("missing_platform", func(t *testing.T) {
+		_, err := Download(t.Context(), newRelease(t, goodSums), "plan9", "386")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "no binary")
+	})
+
+	t.Run("missing_checksums", func(t *testing.T) {
+		rel := newRelease(t, goodSums)
+		rel.Assets = rel.Assets[:1]
+		_, err := Download(t.Context(), rel, "linux", "amd64")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "refusing unverified update")
+	})
+}
+
+func TestLatest(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(Release{TagName: "v1.2.3", HTMLURL: "https://example.com/r"})
+	}))
+	t.Cleanup(srv.Close)
+
+	orig := latestReleaseURL
+	latestReleaseURL = srv.URL
+	t.Cleanup(func() { latestReleaseURL = orig })
+
+	rel, err := Latest(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, "1.2.3", rel.Version())
+}
+
+func TestInstallTo(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "sectool")
+	require.NoError(t, os.WriteFile(path, []byte("old"), 0700))
+
+	require.NoError(t, installTo(path, []byte("new")))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "new", string(data))
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0700), info.Mode().Perm())
+}
@@ -0,0 +1,178 @@
+package ws
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/pflag"
+
+	"github.com/go-harden/llm-security-toolbox/sectool/cli"
+	"github.com/go-harden/llm-security-toolbox/sectool/cliutil"
+	"github.com/go-harden/llm-security-toolbox/sectool/mcpclient"
+)
+
+var wsSubcommands = []string{"list", "send", "help"}
+
+func Parse(args []string, mcpURL string) error {
+	if len(args) < 1 {
+		printUsage()
+		return errors.New("subcommand required")
+	}
+
+	switch args[0] {
+	case "list":
+		return parseList(args[1:], mcpURL)
+	case "send":
+		return parseSend(args[1:], mcpURL)
+	case "help", "--help", "-h":
+		printUsage()
+		return nil
+	default:
+		return cli.UnknownSubcommandError("ws", args[0], wsSubcommands)
+	}
+}
+
+func printUsage() {
+	_, _ = fmt.Fprint(os.Stderr, `Usage: sectool ws <command> [options]
+
+Inspect and replay WebSocket traffic.
+
+---
+
+ws list [options]
+
+  List WebSocket messages captured by the proxy (oldest first).
+
+  Options:
+    --host <pattern>     filter by host (glob: *, ?); built-in proxy only
+    --direction <dir>    to-server or to-client
+    --contains <str>     filter by payload substring
+    --since <id>         messages after message_id, or 'last'
+    --limit <n>          max messages per page (default 100)
+    --cursor <c>         continue from a previous page
+
+  Output: Markdown table with message_id, direction, opcode, size, payload
+
+---
+
+ws send [options]
+
+  Open a WebSocket connection, send messages, and collect the replies.
+  The connection is made directly, not through the proxy.
+
+  Options:
+    --url <ws-url>           ws:// or wss:// URL to connect to
+    --flow <flow_id>         reuse the handshake (cookies, auth) of a proxy flow
+    --target <ws-url>        override scheme/host/port when using --flow
+    -m, --message <str>      message to send (repeatable, sent in order)
+    --message-id <id>        resend a captured message from 'ws list'
+    -H, --header <h>         add or replace handshake header (repeatable)
+    --remove-header <name>   remove handshake header (repeatable)
+    --wait <dur>             idle time to wait for replies (default 2s)
+    --max-replies <n>        stop after n replies (default 50)
+    --request-timeout <dur>  overall connection timeout (default 30s)
+
+  Output: handshake status, then a Markdown table of received messages
+
+  Examples:
+    sectool ws send --url wss://example.com/socket -m '{"op":"ping"}'
+    sectool ws send --flow f7k2x --message-id m3 --wait 5s
+`)
+}
+
+var messageColumns = []string{"message_id", "direction", "opcode", "size", "payload"}
+
+func parseList(args []string, mcpURL string) error {
+	fs := pflag.NewFlagSet("ws list", pflag.ContinueOnError)
+	fs.SetInterspersed(true)
+	var timeout time.Duration
+	var opts mcpclient.WSListOpts
+	var format, columns string
+
+	fs.DurationVar(&timeout, "timeout", 30*time.Second, "client-side timeout")
+	fs.StringVar(&opts.Host, "host", "", "filter by host (glob pattern)")
+	fs.StringVar(&opts.Direction, "direction", "", "filter by direction: to-server, to-client")
+	fs.StringVar(&opts.Contains, "contains", "", "filter by payload substring")
+	fs.StringVar(&opts.Since, "since", "", "messages after message_id, or 'last'")
+	fs.IntVar(&opts.Limit, "limit", 0, "maximum number of messages per page")
+	fs.StringVar(&opts.Cursor, "cursor", "", "continue from a previous page")
+	fs.StringVar(&format, "format", "markdown", "output format: markdown, plain, csv, tsv")
+	fs.StringVar(&columns, "columns", "", "comma-separated columns to show (message_id,direction,opcode,size,payload)")
+
+	fs.Usage = func() {
+		_, _ = fmt.Fprint(os.Stderr, `Usage: sectool ws list [options]
+
+List WebSocket messages captured by the proxy.
+
+Options:
+`)
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	outFormat, err := cliutil.ParseFormat(format)
+	if err != nil {
+		return err
+	}
+	cols := messageColumns
+	if columns != "" {
+		if cols, err = cliutil.SelectColumns(columns, messageColumns); err != nil {
+			return err
+		}
+	}
+
+	return list(mcpURL, timeout, opts, outFormat, cols)
+}
+
+func parseSend(args []string, mcpURL string) error {
+	fs := pflag.NewFlagSet("ws send", pflag.ContinueOnError)
+	fs.SetInterspersed(true)
+	var timeout, wait, requestTimeout time.Duration
+	var opts mcpclient.WSSendOpts
+
+	fs.DurationVar(&timeout, "timeout", time.Minute, "client-side timeout")
+	fs.StringVar(&opts.URL, "url", "", "ws:// or wss:// URL to connect to")
+	fs.StringVar(&opts.FlowID, "flow", "", "flow_id whose handshake request is reused")
+	fs.StringVar(&opts.Target, "target", "", "override ws:// or wss:// scheme/host/port (with --flow)")
+	fs.StringArrayVarP(&opts.Messages, "message", "m", nil, "message to send (repeatable)")
+	fs.StringVar(&opts.MessageID, "message-id", "", "message_id from ws list to resend")
+	fs.StringArrayVarP(&opts.AddHeaders, "header", "H", nil, "add or replace handshake header (repeatable)")
+	fs.StringArrayVar(&opts.RemoveHeaders, "remove-header", nil, "remove handshake header by name (repeatable)")
+	fs.DurationVar(&wait, "wait", 0, "idle time to wait for replies (default 2s)")
+	fs.IntVar(&opts.MaxReplies, "max-replies", 0, "stop after this many replies (default 50)")
+	fs.DurationVar(&requestTimeout, "request-timeout", 0, "overall connection timeout (default 30s)")
+
+	fs.Usage = func() {
+		_, _ = fmt.Fprint(os.Stderr, `Usage: sectool ws send (--url <ws-url> | --flow <flow_id>) [options]
+
+Open a WebSocket connection, send messages, and collect the replies.
+
+Options:
+`)
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if opts.URL == "" && opts.FlowID == "" {
+		fs.Usage()
+		return errors.New("one of --url or --flow is required")
+	}
+	if len(opts.Messages) == 0 && opts.MessageID == "" {
+		return errors.New("at least one --message or --message-id is required")
+	}
+	if wait > 0 {
+		opts.Wait = wait.String()
+	}
+	if requestTimeout > 0 {
+		opts.Timeout = requestTimeout.String()
+	}
+
+	return send(mcpURL, timeout, opts)
+}
@@ -0,0 +1,89 @@
+package ws
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/go-harden/llm-security-toolbox/sectool/cliutil"
+	"github.com/go-harden/llm-security-toolbox/sectool/mcpclient"
+	"github.com/go-harden/llm-security-toolbox/sectool/protocol"
+)
+
+func list(mcpURL string, timeout time.Duration, opts mcpclient.WSListOpts, format cliutil.Format, columns []string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	client, err := mcpclient.Connect(ctx, mcpURL)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = client.Close() }()
+
+	resp, err := client.WSList(ctx, opts)
+	if err != nil {
+		return fmt.Errorf("ws list failed: %w", err)
+	}
+
+	defer cliutil.StartPager()()
+
+	if len(resp.Messages) == 0 && (format == cliutil.FormatMarkdown || format == "") {
+		fmt.Println("No WebSocket messages found.")
+		return nil
+	}
+
+	printMessages(resp.Messages, format, columns)
+	if format != cliutil.FormatMarkdown {
+		return nil
+	}
+	fmt.Printf("\n*%d message(s)*\n", len(resp.Messages))
+	if resp.NextCursor != "" {
+		cliutil.Hintf("\nMore messages: `sectool ws list --cursor %s`\n", resp.NextCursor)
+	} else if len(resp.Messages) > 0 {
+		last := resp.Messages[len(resp.Messages)-1]
+		cliutil.Hintf("\nTo resend a message: `sectool ws send --url <ws-url> --message-id %s`\n", last.MessageID)
+	}
+	return nil
+}
+
+func send(mcpURL string, timeout time.Duration, opts mcpclient.WSSendOpts) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	client, err := mcpclient.Connect(ctx, mcpURL)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = client.Close() }()
+
+	resp, err := client.WSSend(ctx, opts)
+	if err != nil {
+		return fmt.Errorf("ws send failed: %w", err)
+	}
+
+	fmt.Printf("Connected to `%s` (status %d, %s)\n", resp.URL, resp.Status, resp.Duration)
+	if resp.Sent == 0 && resp.RespHeaders != "" {
+		fmt.Printf("\nHandshake was not upgraded:\n```\n%s```\n", resp.RespHeaders)
+		return nil
+	}
+	fmt.Printf("Sent %d message(s), received %d\n", resp.Sent, len(resp.Messages))
+	if resp.Closed {
+		fmt.Println("Server closed the connection.")
+	}
+	if len(resp.Messages) > 0 {
+		fmt.Println()
+		printMessages(resp.Messages, cliutil.FormatMarkdown, []string{"direction", "opcode", "size", "payload"})
+	}
+	return nil
+}
+
+func printMessages(msgs []protocol.WSMessage, format cliutil.Format, columns []string) {
+	t := cliutil.NewTable(os.Stdout, format, messageColumns, columns)
+	t.Header()
+	for _, m := range msgs {
+		t.Row(m.MessageID, m.Direction, m.Opcode, strconv.Itoa(m.Size), m.Payload)
+	}
+	t.Flush()
+}